@@ -0,0 +1,388 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package bench provides reproducible benchmarks of the sign, verify, encrypt, decrypt, wrap and unwrap operations
+// of a Crypto, with the keys of a KeyManager, and compares their results with a baseline to gate performance
+// regressions:
+//
+//	results, err := bench.Run()
+//	// write the baseline...
+//	err = results.WriteJSON(f)
+//	// ...or compare with it.
+//	baseline, err := bench.ReadResults(f)
+//	err = bench.Compare(baseline, results, 0.1).Err()
+//
+// The benchmarks run the same operations on the same inputs, derived from a fixed seed, for each run: their results
+// only depend on the KeyManager and Crypto implementations and on the host recorded in the Results. The keys are
+// created once per benchmark, before the benchmark timer starts. The benchmarks of the package tests run the same
+// cases with go test -bench, for benchstat comparisons.
+package bench
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+	"github.com/trustbloc/kms-go/util/entropy"
+)
+
+const (
+	defaultMessageSize = 1024
+	cekSize            = 32
+)
+
+// seed is the seed of the benchmark inputs.
+const seed = "kms-go bench"
+
+// Operation is a benchmarked Crypto operation.
+type Operation string
+
+// The benchmarked operations.
+const (
+	// Sign signs the message with Crypto.Sign.
+	Sign Operation = "sign"
+	// Verify verifies the signature of the message with Crypto.Verify and the public key handle of the key.
+	Verify Operation = "verify"
+	// Encrypt encrypts the message with Crypto.Encrypt.
+	Encrypt Operation = "encrypt"
+	// Decrypt decrypts the encrypted message with Crypto.Decrypt.
+	Decrypt Operation = "decrypt"
+	// Wrap wraps a CEK with Crypto.WrapKey and the public key of the key, or with KEKWrapper.WrapKeyWithKEK for the
+	// AES-KW keys.
+	Wrap Operation = "wrap"
+	// Unwrap unwraps the wrapped CEK with Crypto.UnwrapKey.
+	Unwrap Operation = "unwrap"
+)
+
+// Case is a benchmark: an operation with a key of a key type.
+type Case struct {
+	Op      Operation
+	KeyType kmsapi.KeyType
+}
+
+// Name returns the name of the benchmark, its operation and key type (eg: sign/ED25519).
+func (c Case) Name() string {
+	return string(c.Op) + "/" + string(c.KeyType)
+}
+
+//nolint:gochecknoglobals
+var (
+	// signatureKeyTypes don't include the secp256k1 key types: their keys sign with Tink prefixed signatures, which
+	// can't be verified with the exported public keys.
+	signatureKeyTypes = []kmsapi.KeyType{
+		kmsapi.ED25519Type,
+		kmsapi.ECDSAP256TypeIEEEP1363, kmsapi.ECDSAP384TypeIEEEP1363, kmsapi.ECDSAP521TypeIEEEP1363,
+		kmsapi.ECDSAP256TypeDER, kmsapi.RSARS256Type, kmsapi.RSAPS256Type,
+	}
+	encryptionKeyTypes = []kmsapi.KeyType{
+		kmsapi.AES128GCMType, kmsapi.AES256GCMType, kmsapi.ChaCha20Poly1305Type, kmsapi.XChaCha20Poly1305Type,
+	}
+	wrappingKeyTypes = []kmsapi.KeyType{
+		kmsapi.NISTP256ECDHKWType, kmsapi.NISTP384ECDHKWType, kmsapi.NISTP521ECDHKWType, kmsapi.X25519ECDHKWType,
+		kmsapi.RSAOAEP256Type, kmsapi.AES256KWType,
+	}
+)
+
+// Cases returns the default benchmarks: the sign and verify operations of the signature key types, the encrypt and
+// decrypt operations of the AEAD key types and the wrap and unwrap operations of the key wrapping key types.
+func Cases() []Case {
+	var cases []Case
+
+	for _, ops := range []struct {
+		ops [2]Operation
+		kts []kmsapi.KeyType
+	}{
+		{ops: [2]Operation{Sign, Verify}, kts: signatureKeyTypes},
+		{ops: [2]Operation{Encrypt, Decrypt}, kts: encryptionKeyTypes},
+		{ops: [2]Operation{Wrap, Unwrap}, kts: wrappingKeyTypes},
+	} {
+		for _, op := range ops.ops {
+			for _, kt := range ops.kts {
+				cases = append(cases, Case{Op: op, KeyType: kt})
+			}
+		}
+	}
+
+	return cases
+}
+
+type options struct {
+	km          kmsapi.KeyManager
+	crypto      cryptoapi.Crypto
+	cases       []Case
+	messageSize int
+}
+
+// Opt is an option of the benchmarks.
+type Opt func(*options)
+
+// WithKeyManager sets the KeyManager creating the keys of the benchmarks, an in-memory LocalKMS (see
+// testutil/mockkms) by default.
+func WithKeyManager(km kmsapi.KeyManager) Opt {
+	return func(o *options) {
+		o.km = km
+	}
+}
+
+// WithCrypto sets the Crypto running the benchmarked operations, a tinkcrypto Crypto by default.
+func WithCrypto(c cryptoapi.Crypto) Opt {
+	return func(o *options) {
+		o.crypto = c
+	}
+}
+
+// WithCases sets the benchmarks that Run runs, the ones of Cases by default.
+func WithCases(cases ...Case) Opt {
+	return func(o *options) {
+		o.cases = cases
+	}
+}
+
+// WithMessageSize sets the size of the signed and encrypted messages, 1024 bytes by default.
+func WithMessageSize(size int) Opt {
+	return func(o *options) {
+		o.messageSize = size
+	}
+}
+
+func newOptions(opts []Opt) (*options, error) {
+	o := &options{messageSize: defaultMessageSize}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.km == nil {
+		km, err := mockkms.New()
+		if err != nil {
+			return nil, err
+		}
+
+		o.km = km
+	}
+
+	if o.crypto == nil {
+		c, err := tinkcrypto.New()
+		if err != nil {
+			return nil, err
+		}
+
+		o.crypto = c
+	}
+
+	if o.cases == nil {
+		o.cases = Cases()
+	}
+
+	return o, nil
+}
+
+// Prepare creates the key of the benchmark c and returns its operation, to be called in a benchmark loop.
+func Prepare(c Case, opts ...Opt) (func() error, error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("prepare %s: %w", c.Name(), err)
+	}
+
+	op, err := o.prepare(c)
+	if err != nil {
+		return nil, fmt.Errorf("prepare %s: %w", c.Name(), err)
+	}
+
+	return op, nil
+}
+
+func (o *options) prepare(c Case) (func() error, error) {
+	keyID, kh, err := o.km.Create(c.KeyType)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c.Op {
+	case Sign, Verify:
+		return o.prepareSignature(c.Op, keyID, kh)
+	case Encrypt, Decrypt:
+		return o.prepareEncryption(c.Op, kh)
+	case Wrap, Unwrap:
+		return o.prepareWrapping(c, keyID, kh)
+	default:
+		return nil, fmt.Errorf("unknown operation '%s'", c.Op)
+	}
+}
+
+// input returns size bytes of the deterministic input of label.
+func input(label string, size int) ([]byte, error) {
+	b := make([]byte, size)
+
+	if _, err := io.ReadFull(entropy.NewDeterministic([]byte(seed), label), b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (o *options) prepareSignature(op Operation, keyID string, kh interface{}) (func() error, error) {
+	msg, err := input("message", o.messageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if op == Sign {
+		return func() error {
+			_, e := o.crypto.Sign(msg, kh)
+
+			return e
+		}, nil
+	}
+
+	sig, err := o.crypto.Sign(msg, kh)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, kt, err := o.km.ExportPubKeyBytes(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKH, err := o.km.PubKeyBytesToHandle(pubKey, kt)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		return o.crypto.Verify(sig, msg, pubKH)
+	}, nil
+}
+
+func (o *options) prepareEncryption(op Operation, kh interface{}) (func() error, error) {
+	msg, err := input("message", o.messageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if op == Encrypt {
+		return func() error {
+			_, _, e := o.crypto.Encrypt(msg, nil, kh)
+
+			return e
+		}, nil
+	}
+
+	ct, nonce, err := o.crypto.Encrypt(msg, nil, kh)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		_, e := o.crypto.Decrypt(ct, nil, nonce, kh)
+
+		return e
+	}, nil
+}
+
+func (o *options) prepareWrapping(c Case, keyID string, kh interface{}) (func() error, error) {
+	cek, err := input("cek", cekSize)
+	if err != nil {
+		return nil, err
+	}
+
+	wrap, err := o.wrapFunc(c.KeyType, keyID, kh, cek)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Op == Wrap {
+		return func() error {
+			_, e := wrap()
+
+			return e
+		}, nil
+	}
+
+	wk, err := wrap()
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		_, e := o.crypto.UnwrapKey(wk, kh)
+
+		return e
+	}, nil
+}
+
+// wrapFunc returns the function wrapping cek with the key of keyID: with the KEK kh for the AES-KW key types,
+// otherwise with the recipient public key of keyID.
+func (o *options) wrapFunc(kt kmsapi.KeyType, keyID string, kh interface{},
+	cek []byte) (func() (*cryptoapi.RecipientWrappedKey, error), error) {
+	switch kt { //nolint:exhaustive // the other key types wrap with their public key.
+	case kmsapi.AES128KWType, kmsapi.AES192KWType, kmsapi.AES256KWType:
+		w, ok := o.crypto.(cryptoapi.KEKWrapper)
+		if !ok {
+			return nil, errors.New("crypto doesn't wrap keys with key encryption keys")
+		}
+
+		return func() (*cryptoapi.RecipientWrappedKey, error) {
+			return w.WrapKeyWithKEK(cek, kh)
+		}, nil
+	}
+
+	recPubKey, err := o.recipientPublicKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() (*cryptoapi.RecipientWrappedKey, error) {
+		return o.crypto.WrapKey(cek, nil, nil, recPubKey)
+	}, nil
+}
+
+// recipientPublicKey returns the public key of keyID: the exported ECDH-KW public keys are marshalled
+// cryptoapi.PublicKeys, the RSA ones are DER public keys.
+func (o *options) recipientPublicKey(keyID string) (*cryptoapi.PublicKey, error) {
+	pubKey, kt, err := o.km.ExportPubKeyBytes(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if kt != kmsapi.RSAOAEP256Type {
+		recPubKey := &cryptoapi.PublicKey{}
+
+		if err = json.Unmarshal(pubKey, recPubKey); err != nil {
+			return nil, err
+		}
+
+		recPubKey.KID = keyID
+
+		return recPubKey, nil
+	}
+
+	key, err := x509.ParsePKIXPublicKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	rsaPubKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not an RSA key")
+	}
+
+	return &cryptoapi.PublicKey{
+		KID:  keyID,
+		Type: "RSA",
+		N:    rsaPubKey.N.Bytes(),
+		E:    big.NewInt(int64(rsaPubKey.E)).Bytes(),
+	}, nil
+}
@@ -0,0 +1,185 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bench_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/bench"
+	mockcryptoapi "github.com/trustbloc/kms-go/mock/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockcrypto"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func BenchmarkCrypto(b *testing.B) {
+	for _, c := range bench.Cases() {
+		op, err := bench.Prepare(c)
+		require.NoError(b, err)
+
+		b.Run(c.Name(), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				if e := op(); e != nil {
+					b.Fatal(e)
+				}
+			}
+		})
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	km, err := mockkms.New()
+	require.NoError(t, err)
+
+	for _, c := range bench.Cases() {
+		t.Run(c.Name(), func(t *testing.T) {
+			op, e := bench.Prepare(c, bench.WithKeyManager(km), bench.WithMessageSize(32))
+			require.NoError(t, e)
+			require.NoError(t, op())
+		})
+	}
+
+	t.Run("unknown operation", func(t *testing.T) {
+		_, e := bench.Prepare(bench.Case{Op: "derive", KeyType: kmsapi.AES256GCMType})
+		require.EqualError(t, e, "prepare derive/AES256GCM: unknown operation 'derive'")
+	})
+
+	t.Run("key creation error", func(t *testing.T) {
+		errCreate := errors.New("create error")
+
+		km.FailOn(mockkms.MethodCreate, errCreate)
+		defer km.Reset()
+
+		_, e := bench.Prepare(bench.Case{Op: bench.Sign, KeyType: kmsapi.ED25519Type}, bench.WithKeyManager(km))
+		require.ErrorIs(t, e, errCreate)
+	})
+
+	t.Run("crypto without key encryption keys", func(t *testing.T) {
+		_, e := bench.Prepare(bench.Case{Op: bench.Wrap, KeyType: kmsapi.AES256KWType},
+			bench.WithCrypto(&mockcryptoapi.Crypto{}))
+		require.EqualError(t, e, "prepare wrap/AES256KW: crypto doesn't wrap keys with key encryption keys")
+	})
+}
+
+func TestRun(t *testing.T) {
+	c := bench.Case{Op: bench.Encrypt, KeyType: kmsapi.AES128GCMType}
+
+	results, err := bench.Run(bench.WithCases(c), bench.WithMessageSize(64))
+	require.NoError(t, err)
+	require.NotEmpty(t, results.GoVersion)
+	require.NotZero(t, results.CPUs)
+	require.Len(t, results.Benchmarks, 1)
+	require.Equal(t, "encrypt/AES128GCM", results.Benchmarks[0].Name)
+	require.Positive(t, results.Benchmarks[0].N)
+	require.Positive(t, results.Benchmarks[0].NsPerOp)
+
+	var buf bytes.Buffer
+
+	require.NoError(t, results.WriteJSON(&buf))
+
+	read, err := bench.ReadResults(&buf)
+	require.NoError(t, err)
+	require.Equal(t, results, read)
+
+	_, err = bench.ReadResults(strings.NewReader("{"))
+	require.ErrorContains(t, err, "read results: ")
+
+	errEncrypt := errors.New("encrypt error")
+
+	cr, err := mockcrypto.New()
+	require.NoError(t, err)
+
+	cr.FailOn(mockcrypto.MethodEncrypt, errEncrypt)
+
+	_, err = bench.Run(bench.WithCases(c), bench.WithCrypto(cr))
+	require.ErrorIs(t, err, errEncrypt)
+	require.ErrorContains(t, err, "run benchmarks: encrypt/AES128GCM: ")
+}
+
+func TestCompare(t *testing.T) {
+	baseline := &bench.Results{Benchmarks: []bench.Result{
+		{Name: "sign/ED25519", NsPerOp: 1000},
+		{Name: "verify/ED25519", NsPerOp: 2000},
+		{Name: "encrypt/AES256GCM", NsPerOp: 500},
+		{Name: "decrypt/AES256GCM", NsPerOp: 0},
+	}}
+	current := &bench.Results{Benchmarks: []bench.Result{
+		{Name: "sign/ED25519", NsPerOp: 1050},
+		{Name: "verify/ED25519", NsPerOp: 2500},
+		{Name: "decrypt/AES256GCM", NsPerOp: 600},
+		{Name: "wrap/X25519ECDHKW", NsPerOp: 10000},
+	}}
+
+	c := bench.Compare(baseline, current, 0.1)
+	require.Equal(t, []bench.Delta{
+		{Name: "sign/ED25519", Baseline: 1000, Current: 1050, Change: 0.05},
+		{Name: "verify/ED25519", Baseline: 2000, Current: 2500, Change: 0.25, Regressed: true},
+		{Name: "decrypt/AES256GCM", Baseline: 0, Current: 600},
+	}, c.Deltas)
+	require.Equal(t, []string{"encrypt/AES256GCM"}, c.Missing)
+	require.Len(t, c.Regressed(), 1)
+	require.ErrorIs(t, c.Err(), bench.ErrRegression)
+	require.EqualError(t, c.Err(), "performance regression over 10.0%: verify/ED25519 (+25.0%)")
+
+	require.NoError(t, bench.Compare(baseline, current, 0.3).Err())
+}
+
+// TestRegressionGate runs the benchmarks and writes their results to the BENCH_OUTPUT file, and compares them with
+// the baseline results of the BENCH_BASELINE file, failing if a benchmark regressed by more than BENCH_THRESHOLD
+// (0.1 by default). See the bench-baseline and bench-check make targets.
+func TestRegressionGate(t *testing.T) {
+	output, baselineFile := os.Getenv("BENCH_OUTPUT"), os.Getenv("BENCH_BASELINE")
+	if output == "" && baselineFile == "" {
+		t.Skip("BENCH_OUTPUT and BENCH_BASELINE are not set")
+	}
+
+	results, err := bench.Run()
+	require.NoError(t, err)
+
+	if output != "" {
+		var buf bytes.Buffer
+
+		require.NoError(t, results.WriteJSON(&buf))
+		require.NoError(t, os.WriteFile(output, buf.Bytes(), 0o600))
+	}
+
+	if baselineFile == "" {
+		return
+	}
+
+	threshold := 0.1
+
+	if s := os.Getenv("BENCH_THRESHOLD"); s != "" {
+		threshold, err = strconv.ParseFloat(s, 64)
+		require.NoError(t, err)
+	}
+
+	f, err := os.Open(baselineFile) //nolint:gosec // baseline file set by the caller.
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, f.Close()) }()
+
+	baseline, err := bench.ReadResults(f)
+	require.NoError(t, err)
+
+	c := bench.Compare(baseline, results, threshold)
+
+	for _, d := range c.Deltas {
+		t.Logf("%-40s %12d ns/op %12d ns/op %+7.1f%%", d.Name, d.Baseline, d.Current, d.Change*100)
+	}
+
+	require.NoError(t, c.Err())
+}
@@ -0,0 +1,205 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bench
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// ErrRegression is wrapped by the error of the Comparisons with regressed benchmarks.
+var ErrRegression = errors.New("performance regression")
+
+// Result is the result of a benchmark.
+type Result struct {
+	Name        string `json:"name"`
+	N           int    `json:"n"`
+	NsPerOp     int64  `json:"nsPerOp"`
+	AllocsPerOp int64  `json:"allocsPerOp"`
+	BytesPerOp  int64  `json:"bytesPerOp"`
+}
+
+// Results are the results of the benchmarks of a Run, with the host they ran on.
+type Results struct {
+	GoVersion  string   `json:"goVersion"`
+	GOOS       string   `json:"goos"`
+	GOARCH     string   `json:"goarch"`
+	CPUs       int      `json:"cpus"`
+	Benchmarks []Result `json:"benchmarks"`
+}
+
+// Run runs the benchmarks, for the duration set by the -test.benchtime flag (1s by default) each.
+func Run(opts ...Opt) (*Results, error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("run benchmarks: %w", err)
+	}
+
+	results := &Results{
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		CPUs:      runtime.NumCPU(),
+	}
+
+	for _, c := range o.cases {
+		r, e := o.run(c)
+		if e != nil {
+			return nil, fmt.Errorf("run benchmarks: %s: %w", c.Name(), e)
+		}
+
+		results.Benchmarks = append(results.Benchmarks, *r)
+	}
+
+	return results, nil
+}
+
+func (o *options) run(c Case) (*Result, error) {
+	op, err := o.prepare(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var opErr error
+
+	r := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N && opErr == nil; i++ {
+			opErr = op()
+		}
+	})
+
+	if opErr != nil {
+		return nil, opErr
+	}
+
+	return &Result{
+		Name:        c.Name(),
+		N:           r.N,
+		NsPerOp:     r.NsPerOp(),
+		AllocsPerOp: r.AllocsPerOp(),
+		BytesPerOp:  r.AllocedBytesPerOp(),
+	}, nil
+}
+
+// ReadResults reads the JSON Results of r, written by Results.WriteJSON.
+func ReadResults(r io.Reader) (*Results, error) {
+	results := &Results{}
+
+	if err := json.NewDecoder(r).Decode(results); err != nil {
+		return nil, fmt.Errorf("read results: %w", err)
+	}
+
+	return results, nil
+}
+
+// WriteJSON writes the indented JSON of r to w.
+func (r *Results) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(r); err != nil {
+		return fmt.Errorf("write results: %w", err)
+	}
+
+	return nil
+}
+
+// Delta is the change of the time per operation of a benchmark.
+type Delta struct {
+	Name string
+	// Baseline and Current are the times per operation of the baseline and current results, in nanoseconds.
+	Baseline int64
+	Current  int64
+	// Change is the relative change of the time per operation, eg: 0.1 if the current result is 10% slower.
+	Change float64
+	// Regressed is true if Change is over the threshold of the Comparison.
+	Regressed bool
+}
+
+// Comparison is the comparison of the current results of benchmarks with their baseline results.
+type Comparison struct {
+	Threshold float64
+	// Deltas are the changes of the benchmarks with baseline and current results, in the order of the current ones.
+	Deltas []Delta
+	// Missing are the names of the baseline benchmarks without current result.
+	Missing []string
+}
+
+// Compare compares the current results with the baseline ones. A benchmark regressed if its time per operation
+// increased by more than threshold (eg: 0.1 for 10%). The benchmarks without baseline result are ignored.
+func Compare(baseline, current *Results, threshold float64) *Comparison {
+	baselines := map[string]Result{}
+
+	for _, r := range baseline.Benchmarks {
+		baselines[r.Name] = r
+	}
+
+	c := &Comparison{Threshold: threshold}
+
+	for _, r := range current.Benchmarks {
+		b, ok := baselines[r.Name]
+		if !ok {
+			continue
+		}
+
+		delete(baselines, r.Name)
+
+		d := Delta{Name: r.Name, Baseline: b.NsPerOp, Current: r.NsPerOp}
+
+		if b.NsPerOp > 0 {
+			d.Change = float64(r.NsPerOp-b.NsPerOp) / float64(b.NsPerOp)
+			d.Regressed = d.Change > threshold
+		}
+
+		c.Deltas = append(c.Deltas, d)
+	}
+
+	for _, r := range baseline.Benchmarks {
+		if _, ok := baselines[r.Name]; ok {
+			c.Missing = append(c.Missing, r.Name)
+		}
+	}
+
+	return c
+}
+
+// Regressed returns the deltas of the regressed benchmarks.
+func (c *Comparison) Regressed() []Delta {
+	var regressed []Delta
+
+	for _, d := range c.Deltas {
+		if d.Regressed {
+			regressed = append(regressed, d)
+		}
+	}
+
+	return regressed
+}
+
+// Err returns an error wrapping ErrRegression, listing the regressed benchmarks, or nil if none regressed.
+func (c *Comparison) Err() error {
+	regressed := c.Regressed()
+	if len(regressed) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(regressed))
+
+	for i, d := range regressed {
+		names[i] = fmt.Sprintf("%s (%+.1f%%)", d.Name, d.Change*100) //nolint:gomnd // percentage.
+	}
+
+	return fmt.Errorf("%w over %.1f%%: %s", ErrRegression, c.Threshold*100, //nolint:gomnd // percentage.
+		strings.Join(names, ", "))
+}
@@ -0,0 +1,85 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package bind provides gomobile compatible wrappers of the local KMS and crypto for iOS and Android wallets:
+//
+//	gomobile bind -target=ios github.com/trustbloc/kms-go/bind
+//	gomobile bind -target=android github.com/trustbloc/kms-go/bind
+//
+// The API only uses the types gomobile can bind: strings, byte slices, integers, booleans, errors, pointers to the
+// package structs and the KeyStore interface, implemented by the app. Public keys are exchanged as JWK JSON, JWE
+// messages as their JSON serialization, and lists (recipients, BBS+ messages) are built with the Recipients and
+// Messages types instead of slices of slices.
+package bind
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/kms/localkms"
+	"github.com/trustbloc/kms-go/secretlock/local"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/spi/secretlock"
+)
+
+const primaryKeyURI = "local-lock://bind/master/key/"
+
+// KMS creates and uses keys stored in a KeyStore. Private keys never leave the KMS, they are referenced by their key
+// ID.
+type KMS struct {
+	kms    *localkms.LocalKMS
+	crypto *tinkcrypto.Crypto
+}
+
+// NewKMS creates a KMS storing its keys in store, encrypted with masterKey: a 32 bytes AES key, raw or base64URL
+// encoded (see GenerateMasterKey). The master key should be kept in the platform secure storage (Keychain, Android
+// Keystore).
+//
+// An empty masterKey stores the keys unencrypted, which is only suitable when store is itself encrypted.
+func NewKMS(store KeyStore, masterKey []byte) (*KMS, error) {
+	if store == nil {
+		return nil, fmt.Errorf("new kms: key store is nil")
+	}
+
+	var (
+		lock secretlock.Service = &noop.NoLock{}
+		err  error
+	)
+
+	if len(masterKey) > 0 {
+		lock, err = local.NewService(bytes.NewReader(masterKey), nil)
+		if err != nil {
+			return nil, fmt.Errorf("new kms: master key: %w", err)
+		}
+	}
+
+	km, err := localkms.New(primaryKeyURI, &kmsProvider{store: &keyStore{store: store}, lock: lock})
+	if err != nil {
+		return nil, fmt.Errorf("new kms: %w", err)
+	}
+
+	cr, err := tinkcrypto.New()
+	if err != nil {
+		return nil, fmt.Errorf("new kms: %w", err)
+	}
+
+	return &KMS{kms: km, crypto: cr}, nil
+}
+
+type kmsProvider struct {
+	store kmsapi.Store
+	lock  secretlock.Service
+}
+
+func (p *kmsProvider) StorageProvider() kmsapi.Store {
+	return p.store
+}
+
+func (p *kmsProvider) SecretLock() secretlock.Service {
+	return p.lock
+}
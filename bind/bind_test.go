@@ -0,0 +1,209 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bind
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newKMS(t *testing.T) *KMS {
+	t.Helper()
+
+	masterKey, err := GenerateMasterKey()
+	require.NoError(t, err)
+
+	k, err := NewKMS(NewMemKeyStore(), masterKey)
+	require.NoError(t, err)
+
+	return k
+}
+
+func TestNewKMS(t *testing.T) {
+	store := NewMemKeyStore()
+
+	k, err := NewKMS(store, nil)
+	require.NoError(t, err)
+
+	key, err := k.CreateKey("ED25519")
+	require.NoError(t, err)
+
+	stored, err := store.Get(key.KID)
+	require.NoError(t, err)
+	require.NotEmpty(t, stored)
+
+	require.NoError(t, store.Delete(key.KID))
+
+	_, err = k.Sign(key.KID, []byte("msg"))
+	require.ErrorContains(t, err, "key not found")
+
+	_, err = NewKMS(nil, nil)
+	require.EqualError(t, err, "new kms: key store is nil")
+
+	_, err = NewKMS(store, []byte("bad"))
+	require.ErrorContains(t, err, "new kms: master key")
+
+	_, err = NewKMS(&failingStore{}, nil)
+	require.NoError(t, err)
+}
+
+func TestKMS_SignVerify(t *testing.T) {
+	k := newKMS(t)
+	other := newKMS(t)
+	msg := []byte("msg")
+
+	for _, kt := range []string{"ED25519", "ECDSAP256IEEEP1363", "ECDSAP384DER"} {
+		t.Run(kt, func(t *testing.T) {
+			key, err := k.CreateKey(kt)
+			require.NoError(t, err)
+			require.NotEmpty(t, key.KID)
+			require.Contains(t, string(key.JWK), key.KID)
+
+			pub, err := k.PublicKey(key.KID)
+			require.NoError(t, err)
+			require.JSONEq(t, string(key.JWK), string(pub))
+
+			sig, err := k.Sign(key.KID, msg)
+			require.NoError(t, err)
+
+			require.NoError(t, k.Verify(key.KID, sig, msg))
+			require.Error(t, k.Verify(key.KID, sig, []byte("other")))
+
+			if kt != "ECDSAP384DER" {
+				// the JWK doesn't tell the DER and IEEE P1363 signature formats apart, IEEE P1363 is assumed.
+				require.NoError(t, other.VerifyWithJWK(sig, msg, key.JWK))
+				require.Error(t, other.VerifyWithJWK(sig, []byte("other"), key.JWK))
+			}
+		})
+	}
+
+	t.Run("BBS+", func(t *testing.T) {
+		key, err := k.CreateKey("BLS12381G2")
+		require.NoError(t, err)
+
+		messages := NewMessages()
+		messages.Add([]byte("message1"))
+		messages.Add([]byte("message2"))
+		require.Equal(t, 2, messages.Len())
+
+		sig, err := k.SignMulti(key.KID, messages)
+		require.NoError(t, err)
+
+		require.NoError(t, other.VerifyMultiWithJWK(sig, messages, key.JWK))
+
+		messages.Add([]byte("message3"))
+		require.Error(t, other.VerifyMultiWithJWK(sig, messages, key.JWK))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := k.CreateKey("unknown")
+		require.ErrorContains(t, err, "create key")
+
+		_, err = k.PublicKey("unknown")
+		require.ErrorContains(t, err, "public key")
+
+		_, err = k.Sign("unknown", msg)
+		require.ErrorContains(t, err, "sign")
+
+		require.ErrorContains(t, k.Verify("unknown", nil, msg), "verify")
+		require.ErrorContains(t, k.VerifyWithJWK(nil, msg, []byte("{}")), "verify: parse jwk")
+
+		_, err = k.SignMulti("unknown", NewMessages())
+		require.ErrorContains(t, err, "sign multi")
+
+		require.ErrorContains(t, k.VerifyMultiWithJWK(nil, nil, []byte("{")), "verify multi: parse jwk")
+	})
+}
+
+func TestKMS_PackUnpack(t *testing.T) {
+	alice := newKMS(t)
+	bob := newKMS(t)
+	msg := []byte("secret message")
+
+	for _, kt := range []string{"X25519ECDHKW", "NISTP256ECDHKW"} {
+		t.Run(kt, func(t *testing.T) {
+			aliceKey, err := alice.CreateKey(kt)
+			require.NoError(t, err)
+
+			bobKey, err := bob.CreateKey(kt)
+			require.NoError(t, err)
+
+			recipients := NewRecipients()
+			require.NoError(t, recipients.AddJWK(bobKey.JWK))
+			recipients.AddKID(aliceKey.KID)
+			require.Equal(t, 2, recipients.Len())
+
+			packed, err := alice.Pack(msg, recipients)
+			require.NoError(t, err)
+
+			for _, k := range []*KMS{alice, bob} {
+				pt, e := k.Unpack(packed)
+				require.NoError(t, e)
+				require.Equal(t, msg, pt)
+			}
+
+			packed, err = alice.PackFrom(msg, aliceKey.KID, recipients)
+			require.NoError(t, err)
+
+			pt, err := alice.Unpack(packed)
+			require.NoError(t, err)
+			require.Equal(t, msg, pt)
+
+			_, err = bob.Unpack(packed)
+			require.Error(t, err)
+
+			pt, err = bob.UnpackFrom(packed, aliceKey.JWK)
+			require.NoError(t, err)
+			require.Equal(t, msg, pt)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		recipients := NewRecipients()
+
+		_, err := alice.Pack(msg, recipients)
+		require.EqualError(t, err, "pack: no recipients")
+
+		_, err = alice.Pack(msg, nil)
+		require.EqualError(t, err, "pack: no recipients")
+
+		_, err = alice.PackFrom(msg, "", recipients)
+		require.EqualError(t, err, "pack: sender kid is empty")
+
+		require.ErrorContains(t, recipients.AddJWK([]byte("{")), "add recipient: parse jwk")
+
+		sigKey, err := alice.CreateKey("ED25519")
+		require.NoError(t, err)
+
+		recipients.AddKID(sigKey.KID)
+
+		_, err = alice.Pack(msg, recipients)
+		require.ErrorContains(t, err, "is not supported for JWE recipients")
+
+		_, err = alice.Unpack("bad")
+		require.ErrorContains(t, err, "unpack")
+
+		_, err = alice.UnpackFrom("bad", []byte("{"))
+		require.ErrorContains(t, err, "unpack: parse sender jwk")
+	})
+}
+
+type failingStore struct{}
+
+func (f *failingStore) Put(string, []byte) error {
+	return errors.New("put failed")
+}
+
+func (f *failingStore) Get(string) ([]byte, error) {
+	return nil, errors.New("get failed")
+}
+
+func (f *failingStore) Delete(string) error {
+	return nil
+}
@@ -0,0 +1,206 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bind
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+// Key is an asymmetric KMS key, with its public key as JWK JSON.
+type Key struct {
+	KID string
+	JWK []byte
+}
+
+// CreateKey creates a key of keyType, one of the kms.KeyType values (eg: "ED25519", "ECDSAP256IEEEP1363",
+// "BLS12381G2", "X25519ECDHKW", "NISTP256ECDHKW").
+func (k *KMS) CreateKey(keyType string) (*Key, error) {
+	kid, pubKey, err := k.kms.CreateAndExportPubKeyBytes(kmsapi.KeyType(keyType))
+	if err != nil {
+		return nil, fmt.Errorf("create key: %w", err)
+	}
+
+	jwkBytes, err := pubKeyJWK(kid, pubKey, kmsapi.KeyType(keyType))
+	if err != nil {
+		return nil, fmt.Errorf("create key: %w", err)
+	}
+
+	return &Key{KID: kid, JWK: jwkBytes}, nil
+}
+
+// PublicKey returns the public key of the key kid as JWK JSON.
+func (k *KMS) PublicKey(kid string) ([]byte, error) {
+	pubKey, kt, err := k.kms.ExportPubKeyBytes(kid)
+	if err != nil {
+		return nil, fmt.Errorf("public key: %w", err)
+	}
+
+	jwkBytes, err := pubKeyJWK(kid, pubKey, kt)
+	if err != nil {
+		return nil, fmt.Errorf("public key: %w", err)
+	}
+
+	return jwkBytes, nil
+}
+
+// Sign signs msg with the key kid.
+func (k *KMS) Sign(kid string, msg []byte) ([]byte, error) {
+	kh, err := k.kms.Get(kid)
+	if err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+
+	sig, err := k.crypto.Sign(msg, kh)
+	if err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+
+	return sig, nil
+}
+
+// Verify verifies the signature sig of msg with the public key of the key kid.
+func (k *KMS) Verify(kid string, sig, msg []byte) error {
+	kh, err := k.publicKeyHandle(kid)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+
+	if err = k.crypto.Verify(sig, msg, kh); err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyWithJWK verifies the signature sig of msg with the public key jwkBytes (JWK JSON), eg: the key of another
+// wallet.
+func (k *KMS) VerifyWithJWK(sig, msg, jwkBytes []byte) error {
+	kh, err := k.jwkHandle(jwkBytes)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+
+	if err = k.crypto.Verify(sig, msg, kh); err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+
+	return nil
+}
+
+// SignMulti creates a BBS+ signature of messages with the BLS12381G2 key kid.
+func (k *KMS) SignMulti(kid string, messages *Messages) ([]byte, error) {
+	kh, err := k.kms.Get(kid)
+	if err != nil {
+		return nil, fmt.Errorf("sign multi: %w", err)
+	}
+
+	sig, err := k.crypto.SignMulti(messages.list(), kh)
+	if err != nil {
+		return nil, fmt.Errorf("sign multi: %w", err)
+	}
+
+	return sig, nil
+}
+
+// VerifyMultiWithJWK verifies the BBS+ signature sig of messages with the BLS12381G2 public key jwkBytes (JWK JSON).
+func (k *KMS) VerifyMultiWithJWK(sig []byte, messages *Messages, jwkBytes []byte) error {
+	kh, err := k.jwkHandle(jwkBytes)
+	if err != nil {
+		return fmt.Errorf("verify multi: %w", err)
+	}
+
+	if err = k.crypto.VerifyMulti(messages.list(), sig, kh); err != nil {
+		return fmt.Errorf("verify multi: %w", err)
+	}
+
+	return nil
+}
+
+func (k *KMS) publicKeyHandle(kid string) (interface{}, error) {
+	pubKey, kt, err := k.kms.ExportPubKeyBytes(kid)
+	if err != nil {
+		return nil, err
+	}
+
+	return k.kms.PubKeyBytesToHandle(pubKey, kt)
+}
+
+func (k *KMS) jwkHandle(jwkBytes []byte) (interface{}, error) {
+	pub := &jwk.JWK{}
+
+	if err := pub.UnmarshalJSON(jwkBytes); err != nil {
+		return nil, fmt.Errorf("parse jwk: %w", err)
+	}
+
+	pubKey, err := pub.PublicKeyBytes()
+	if err != nil {
+		return nil, fmt.Errorf("parse jwk: %w", err)
+	}
+
+	kt, err := pub.KeyType()
+	if err != nil {
+		return nil, fmt.Errorf("parse jwk: %w", err)
+	}
+
+	return k.kms.PubKeyBytesToHandle(pubKey, kt)
+}
+
+func pubKeyJWK(kid string, pubKey []byte, kt kmsapi.KeyType) ([]byte, error) {
+	// the KMS exports the X25519 and X448 ECDH keys as marshalled crypto.PublicKey, holding the raw key.
+	if kt == kmsapi.X25519ECDHKWType || kt == kmsapi.X448ECDHKWType {
+		ecdhKey := &cryptoapi.PublicKey{}
+
+		if err := json.Unmarshal(pubKey, ecdhKey); err != nil {
+			return nil, fmt.Errorf("unmarshal ecdh key: %w", err)
+		}
+
+		pubKey = ecdhKey.X
+	}
+
+	pub, err := jwksupport.PubKeyBytesToJWK(pubKey, kt)
+	if err != nil {
+		return nil, err
+	}
+
+	pub.KeyID = kid
+
+	return pub.MarshalJSON()
+}
+
+// Messages is a list of messages signed with a single BBS+ signature.
+type Messages struct {
+	messages [][]byte
+}
+
+// NewMessages creates an empty Messages list.
+func NewMessages() *Messages {
+	return &Messages{}
+}
+
+// Add appends msg to the list.
+func (m *Messages) Add(msg []byte) {
+	m.messages = append(m.messages, append([]byte(nil), msg...))
+}
+
+// Len returns the number of messages.
+func (m *Messages) Len() int {
+	return len(m.messages)
+}
+
+func (m *Messages) list() [][]byte {
+	if m == nil {
+		return nil
+	}
+
+	return m.messages
+}
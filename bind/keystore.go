@@ -0,0 +1,108 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bind
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"sync"
+
+	"github.com/trustbloc/kms-go/kms"
+)
+
+const masterKeySize = 32
+
+// KeyStore is the storage of the KMS keys, implemented by the app (eg: with a database or the platform key-value
+// storage). gomobile generates a native interface for it.
+type KeyStore interface {
+	// Put stores key under keysetID.
+	Put(keysetID string, key []byte) error
+	// Get returns the key stored under keysetID, or nil without error if there is none.
+	Get(keysetID string) ([]byte, error)
+	// Delete deletes the key stored under keysetID, it must not fail if there is none.
+	Delete(keysetID string) error
+}
+
+// keyStore adapts a KeyStore to a kms.Store, as native implementations can't wrap kms.ErrKeyNotFound.
+type keyStore struct {
+	store KeyStore
+}
+
+func (s *keyStore) Put(keysetID string, key []byte) error {
+	return s.store.Put(keysetID, key)
+}
+
+func (s *keyStore) Get(keysetID string) ([]byte, error) {
+	key, err := s.store.Get(keysetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(key) == 0 {
+		return nil, fmt.Errorf("%w: keysetID '%s'", kms.ErrKeyNotFound, keysetID)
+	}
+
+	return key, nil
+}
+
+func (s *keyStore) Delete(keysetID string) error {
+	return s.store.Delete(keysetID)
+}
+
+// MemKeyStore is an in-memory KeyStore, for ephemeral keys and tests.
+type MemKeyStore struct {
+	mu   sync.RWMutex
+	keys map[string][]byte
+}
+
+// NewMemKeyStore creates an empty MemKeyStore.
+func NewMemKeyStore() *MemKeyStore {
+	return &MemKeyStore{keys: map[string][]byte{}}
+}
+
+// Put stores key under keysetID.
+func (m *MemKeyStore) Put(keysetID string, key []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.keys[keysetID] = append([]byte(nil), key...)
+
+	return nil
+}
+
+// Get returns the key stored under keysetID, or nil if there is none.
+func (m *MemKeyStore) Get(keysetID string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.keys[keysetID], nil
+}
+
+// Delete deletes the key stored under keysetID.
+func (m *MemKeyStore) Delete(keysetID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.keys, keysetID)
+
+	return nil
+}
+
+// GenerateMasterKey returns a new random base64URL encoded master key for NewKMS.
+func GenerateMasterKey() ([]byte, error) {
+	key := make([]byte, masterKeySize)
+
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generate master key: %w", err)
+	}
+
+	encoded := make([]byte, base64.URLEncoding.EncodedLen(masterKeySize))
+	base64.URLEncoding.Encode(encoded, key)
+
+	return encoded, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bind
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/kms-go/doc/jose/jwe"
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+)
+
+// Recipients is a list of JWE recipients, identified by the key IDs of ECDH keys of the KMS or by their public JWK.
+type Recipients struct {
+	recipients []jwe.Recipient
+}
+
+// NewRecipients creates an empty Recipients list.
+func NewRecipients() *Recipients {
+	return &Recipients{}
+}
+
+// AddKID adds the ECDH key kid of the KMS as a recipient.
+func (r *Recipients) AddKID(kid string) {
+	r.recipients = append(r.recipients, jwe.Recipient{KeyID: kid})
+}
+
+// AddJWK adds the ECDH public key jwkBytes (JWK JSON, with a "kid") as a recipient.
+func (r *Recipients) AddJWK(jwkBytes []byte) error {
+	pub := &jwk.JWK{}
+
+	if err := pub.UnmarshalJSON(jwkBytes); err != nil {
+		return fmt.Errorf("add recipient: parse jwk: %w", err)
+	}
+
+	if pub.KeyID == "" {
+		return errors.New("add recipient: missing jwk kid")
+	}
+
+	r.recipients = append(r.recipients, jwe.Recipient{JWK: pub})
+
+	return nil
+}
+
+// Len returns the number of recipients.
+func (r *Recipients) Len() int {
+	return len(r.recipients)
+}
+
+// Pack encrypts plaintext for recipients (Anoncrypt) and returns the JWE JSON serialization.
+func (k *KMS) Pack(plaintext []byte, recipients *Recipients) (string, error) {
+	return k.pack(plaintext, recipients)
+}
+
+// PackFrom encrypts plaintext for recipients, authenticated with the ECDH key senderKID of the KMS (Authcrypt), and
+// returns the JWE JSON serialization. Recipients resolve the "skid" header to the sender public key, see UnpackFrom.
+func (k *KMS) PackFrom(plaintext []byte, senderKID string, recipients *Recipients) (string, error) {
+	if senderKID == "" {
+		return "", errors.New("pack: sender kid is empty")
+	}
+
+	return k.pack(plaintext, recipients, jwe.WithSender(senderKID))
+}
+
+func (k *KMS) pack(plaintext []byte, recipients *Recipients, opts ...jwe.EncrypterOpt) (string, error) {
+	if recipients == nil || len(recipients.recipients) == 0 {
+		return "", errors.New("pack: no recipients")
+	}
+
+	serialized, err := jwe.NewEncrypter(k.kms, k.crypto, opts...).EncryptJSON(plaintext, nil, recipients.recipients...)
+	if err != nil {
+		return "", fmt.Errorf("pack: %w", err)
+	}
+
+	return serialized, nil
+}
+
+// Unpack decrypts a JWE addressed to an ECDH key of the KMS and returns its plaintext. The sender of Authcrypt
+// messages must be a key of the KMS, use UnpackFrom for other senders.
+func (k *KMS) Unpack(serialized string) ([]byte, error) {
+	pt, err := jwe.NewDecrypter(k.kms, k.crypto).Decrypt(serialized)
+	if err != nil {
+		return nil, fmt.Errorf("unpack: %w", err)
+	}
+
+	return pt, nil
+}
+
+// UnpackFrom decrypts an Authcrypt JWE sent with the ECDH public key senderJWK (JWK JSON, its "kid" matching the JWE
+// "skid" header) and returns its plaintext.
+func (k *KMS) UnpackFrom(serialized string, senderJWK []byte) ([]byte, error) {
+	sender := &jwk.JWK{}
+
+	if err := sender.UnmarshalJSON(senderJWK); err != nil {
+		return nil, fmt.Errorf("unpack: parse sender jwk: %w", err)
+	}
+
+	pt, err := jwe.NewDecrypter(k.kms, k.crypto, jwe.WithSenderJWKs(sender)).Decrypt(serialized)
+	if err != nil {
+		return nil, fmt.Errorf("unpack: %w", err)
+	}
+
+	return pt, nil
+}
@@ -0,0 +1,289 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/trustbloc/kms-go/secretlock/local/ceremony"
+)
+
+const (
+	shareFileSuffix = ".share.json"
+	secretFilePerm  = 0o600
+	dirPerm         = 0o700
+)
+
+type operatorResult struct {
+	Operator    string `json:"operator"`
+	Fingerprint string `json:"contributionFingerprint"`
+	ShareFile   string `json:"shareFile"`
+}
+
+func ceremonyCmd(fs *flag.FlagSet) func(*keystore, io.Reader, io.Writer) (any, error) {
+	threshold := fs.Int("threshold", 0, "number of operators required to recover the master key")
+	operators := fs.Int("operators", 0, "number of operators, each contributing entropy and holding a share")
+	out := fs.String("out", "", "directory of the sealed share files")
+	iterations := fs.Int("kdf-iterations", ceremony.DefaultKDFIterations, "PBKDF2 iterations of the share sealing keys")
+
+	return func(_ *keystore, stdin io.Reader, stderr io.Writer) (any, error) {
+		if *out == "" || *operators < *threshold {
+			return nil, errors.New("missing --out, or less --operators than --threshold")
+		}
+
+		if err := os.MkdirAll(*out, dirPerm); err != nil {
+			return nil, err
+		}
+
+		c, err := ceremony.New(*threshold, ceremony.WithKDFIterations(*iterations))
+		if err != nil {
+			return nil, err
+		}
+
+		p := &prompter{in: bufio.NewReader(stdin), out: stderr}
+
+		p.printf("Key ceremony %s: %d operators, %d of them required to recover the master key.\n", c.ID(),
+			*operators, *threshold)
+
+		results, err := contribute(c, p, *operators)
+		if err != nil {
+			return nil, err
+		}
+
+		mk, err := c.Generate()
+		if err != nil {
+			return nil, err
+		}
+
+		p.printf("\nMaster key fingerprint: %s\nRecord it in the ceremony minutes.\n\n", mk.Fingerprint())
+
+		for i := range results {
+			if results[i].ShareFile, err = sealShare(mk, p, results[i].Operator, *out); err != nil {
+				return nil, err
+			}
+		}
+
+		return map[string]any{
+			"ceremonyID":           c.ID(),
+			"threshold":            *threshold,
+			"masterKeyFingerprint": mk.Fingerprint(),
+			"operators":            results,
+		}, nil
+	}
+}
+
+// contribute prompts the operators for their name and their entropy, and reads back the fingerprint of their
+// contribution.
+func contribute(c *ceremony.Ceremony, p *prompter, operators int) ([]operatorResult, error) {
+	results := make([]operatorResult, 0, operators)
+
+	for len(results) < operators {
+		name, err := p.prompt(fmt.Sprintf("\nOperator %d name: ", len(results)+1))
+		if err != nil {
+			return nil, err
+		}
+
+		entropy, err := p.prompt(fmt.Sprintf("Operator %s, type at least %d random characters: ", name,
+			ceremony.MinEntropySize))
+		if err != nil {
+			return nil, err
+		}
+
+		fingerprint, err := c.Contribute(name, []byte(entropy))
+		if err != nil {
+			p.printf("%s, try again.\n", err)
+
+			continue
+		}
+
+		p.printf("Contribution fingerprint of %s: %s\n", name, fingerprint)
+
+		results = append(results, operatorResult{Operator: name, Fingerprint: fingerprint})
+	}
+
+	return results, nil
+}
+
+// sealShare prompts operator for the passphrase of their share, and writes the sealed share in the directory dir.
+func sealShare(mk *ceremony.MasterKey, p *prompter, operator, dir string) (string, error) {
+	for {
+		passphrase, err := p.prompt(fmt.Sprintf("Operator %s, share passphrase: ", operator))
+		if err != nil {
+			return "", err
+		}
+
+		confirmed, err := p.prompt("Confirm the passphrase: ")
+		if err != nil {
+			return "", err
+		}
+
+		if passphrase == "" || passphrase != confirmed {
+			p.printf("The passphrases are empty or don't match, try again.\n")
+
+			continue
+		}
+
+		sealed, err := mk.SealShare(operator, passphrase)
+		if err != nil {
+			return "", err
+		}
+
+		path := filepath.Join(dir, fmt.Sprintf("%03d-%s%s", sealed.Index, fileName(operator), shareFileSuffix))
+
+		if err = writeJSONFile(path, sealed); err != nil {
+			return "", err
+		}
+
+		p.printf("Share of %s written to %s.\n\n", operator, path)
+
+		return path, nil
+	}
+}
+
+func recoverCmd(fs *flag.FlagSet) func(*keystore, io.Reader, io.Writer) (any, error) {
+	shares := &filesFlag{}
+	fs.Var(shares, "share", "sealed share file of an operator, repeated for each operator")
+	out := fs.String("out", "", "master key file to write, for --master-key-file")
+
+	return func(_ *keystore, stdin io.Reader, stderr io.Writer) (any, error) {
+		if *out == "" || len(*shares) == 0 {
+			return nil, errors.New("missing --out or --share")
+		}
+
+		p := &prompter{in: bufio.NewReader(stdin), out: stderr}
+		unsealed := make([]*ceremony.Share, 0, len(*shares))
+
+		for _, path := range *shares {
+			share, err := unsealShare(p, path)
+			if err != nil {
+				return nil, err
+			}
+
+			unsealed = append(unsealed, share)
+		}
+
+		r, err := ceremony.Recover(unsealed...)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = writeSecretFile(*out, r); err != nil {
+			return nil, err
+		}
+
+		p.printf("Master key fingerprint: %s\n", unsealed[0].MasterKeyFingerprint)
+
+		return map[string]any{
+			"ceremonyID":           unsealed[0].CeremonyID,
+			"masterKeyFingerprint": unsealed[0].MasterKeyFingerprint,
+			"masterKeyFile":        *out,
+		}, nil
+	}
+}
+
+// unsealShare reads the sealed share file path, and prompts its operator for its passphrase.
+func unsealShare(p *prompter, path string) (*ceremony.Share, error) {
+	v, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := &ceremony.SealedShare{}
+
+	if err = json.Unmarshal(v, sealed); err != nil {
+		return nil, fmt.Errorf("invalid share file '%s': %w", path, err)
+	}
+
+	passphrase, err := p.prompt(fmt.Sprintf("Operator %s, passphrase of share %d of ceremony %s: ",
+		sealed.Operator, sealed.Index, sealed.CeremonyID))
+	if err != nil {
+		return nil, err
+	}
+
+	return sealed.Unseal(passphrase)
+}
+
+// prompter prompts the operators on out, and reads their answers from in.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func (p *prompter) printf(format string, a ...any) {
+	_, _ = fmt.Fprintf(p.out, format, a...) //nolint:errcheck // the answers are read from in
+}
+
+func (p *prompter) prompt(label string) (string, error) {
+	p.printf("%s", label)
+
+	line, err := p.in.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		p.printf("\n")
+
+		return "", fmt.Errorf("read answer: %w", err)
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// fileName returns name with the characters other than letters, digits, '.', '-' and '_' replaced by '_'.
+func fileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+func writeJSONFile(path string, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeSecretFile(path, strings.NewReader(string(b)+"\n"))
+}
+
+// writeSecretFile writes the content of r to the new file path, readable by its owner only.
+func writeSecretFile(path string, r io.Reader) error {
+	f, err := os.OpenFile(filepath.Clean(path), os.O_WRONLY|os.O_CREATE|os.O_EXCL, secretFilePerm)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, r)
+
+	if e := f.Close(); err == nil {
+		err = e
+	}
+
+	return err
+}
+
+// filesFlag is a repeated file flag.
+type filesFlag []string
+
+func (f *filesFlag) String() string {
+	return strings.Join(*f, ", ")
+}
+
+func (f *filesFlag) Set(v string) error {
+	*f = append(*f, v)
+
+	return nil
+}
@@ -0,0 +1,419 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/google/tink/go/keyset"
+
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+	"github.com/trustbloc/kms-go/doc/util/pubkeyfmt"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+// command registers its flags in a flag set, and returns the function executing it once the flags are parsed. The
+// interactive commands prompt on stderr, noKeystore is set for the commands executed without a keystore.
+type command struct {
+	usage      string
+	flags      func(fs *flag.FlagSet) func(ks *keystore, stdin io.Reader, stderr io.Writer) (any, error)
+	noKeystore bool
+}
+
+//nolint:gochecknoglobals
+var commands = map[string]command{
+	"create":  {usage: "create a key", flags: createCmd},
+	"list":    {usage: "list the keys of a local keystore", flags: listCmd},
+	"rotate":  {usage: "rotate a key of a local keystore", flags: rotateCmd},
+	"delete":  {usage: "delete a key of a local keystore", flags: deleteCmd},
+	"export":  {usage: "export the public key of a key", flags: exportCmd},
+	"sign":    {usage: "sign a message", flags: signCmd},
+	"verify":  {usage: "verify a signature", flags: verifyCmd},
+	"encrypt": {usage: "encrypt a message", flags: encryptCmd},
+	"decrypt": {usage: "decrypt a ciphertext", flags: decryptCmd},
+	"wrap":    {usage: "wrap a content encryption key for a recipient public key", flags: wrapCmd},
+	"unwrap":  {usage: "unwrap a wrapped content encryption key", flags: unwrapCmd},
+	"ceremony": {
+		usage: "generate a master key in an interactive key ceremony", flags: ceremonyCmd, noKeystore: true,
+	},
+	"recover": {usage: "recover a master key from the shares of a key ceremony", flags: recoverCmd, noKeystore: true},
+}
+
+// keyInfo is the result of the key lifecycle commands.
+type keyInfo struct {
+	KeyID         string         `json:"keyID"`
+	KeyType       kmsapi.KeyType `json:"keyType,omitempty"`
+	PreviousKeyID string         `json:"previousKeyID,omitempty"`
+}
+
+func createCmd(fs *flag.FlagSet) func(*keystore, io.Reader, io.Writer) (any, error) {
+	kt := fs.String("type", "", "key type, eg: ED25519, ECDSAP256DER, AES256GCM or NISTP256ECDHKW")
+	keyID := fs.String("id", "", "key ID (default generated)")
+
+	return func(ks *keystore, _ io.Reader, _ io.Writer) (any, error) {
+		if *kt == "" {
+			return nil, errors.New("missing --type")
+		}
+
+		var opts []kmsapi.KeyOpts
+
+		if *keyID != "" {
+			opts = append(opts, kmsapi.WithCreateKeyID(*keyID))
+		}
+
+		id, _, err := ks.km.Create(kmsapi.KeyType(*kt), opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return &keyInfo{KeyID: id, KeyType: kmsapi.KeyType(*kt)}, nil
+	}
+}
+
+func listCmd(*flag.FlagSet) func(*keystore, io.Reader, io.Writer) (any, error) {
+	return func(ks *keystore, _ io.Reader, _ io.Writer) (any, error) {
+		lister, ok := ks.store.(kmsapi.StoreLister)
+		if !ok {
+			return nil, errors.New("not supported by webkms keystores")
+		}
+
+		ids, err := lister.KeyIDs()
+		if err != nil {
+			return nil, err
+		}
+
+		sort.Strings(ids)
+
+		keys := make([]keyInfo, 0, len(ids))
+
+		for _, id := range ids {
+			// the secret keys have no public key, and so no exported key type.
+			_, kt, e := ks.km.ExportPubKeyBytes(id)
+			if e != nil {
+				kt = ""
+			}
+
+			keys = append(keys, keyInfo{KeyID: id, KeyType: kt})
+		}
+
+		return map[string][]keyInfo{"keys": keys}, nil
+	}
+}
+
+func rotateCmd(fs *flag.FlagSet) func(*keystore, io.Reader, io.Writer) (any, error) {
+	keyID := fs.String("id", "", "ID of the key to rotate")
+	kt := fs.String("type", "", "key type of the new key")
+
+	return func(ks *keystore, _ io.Reader, _ io.Writer) (any, error) {
+		if *keyID == "" || *kt == "" {
+			return nil, errors.New("missing --id or --type")
+		}
+
+		id, _, err := ks.km.Rotate(kmsapi.KeyType(*kt), *keyID)
+		if err != nil {
+			return nil, err
+		}
+
+		return &keyInfo{KeyID: id, KeyType: kmsapi.KeyType(*kt), PreviousKeyID: *keyID}, nil
+	}
+}
+
+func deleteCmd(fs *flag.FlagSet) func(*keystore, io.Reader, io.Writer) (any, error) {
+	keyID := fs.String("id", "", "ID of the key to delete")
+
+	return func(ks *keystore, _ io.Reader, _ io.Writer) (any, error) {
+		if *keyID == "" {
+			return nil, errors.New("missing --id")
+		}
+
+		deleter, ok := ks.km.(interface{ Delete(keyID string) error })
+		if !ok || ks.store == nil {
+			return nil, errors.New("not supported by webkms keystores")
+		}
+
+		if err := deleter.Delete(*keyID); err != nil {
+			return nil, err
+		}
+
+		return &keyInfo{KeyID: *keyID}, nil
+	}
+}
+
+func exportCmd(fs *flag.FlagSet) func(*keystore, io.Reader, io.Writer) (any, error) {
+	keyID := fs.String("id", "", "key ID")
+	format := fs.String("format", string(kmsapi.PubKeyFormatJWK), "public key format: jwk, spki-der, spki-pem, raw "+
+		"or multibase")
+
+	return func(ks *keystore, _ io.Reader, _ io.Writer) (any, error) {
+		if *keyID == "" {
+			return nil, errors.New("missing --id")
+		}
+
+		pub, err := exportPubKey(ks.km, *keyID, kmsapi.PubKeyFormat(*format))
+		if err != nil {
+			return nil, err
+		}
+
+		var encoded any
+
+		switch kmsapi.PubKeyFormat(*format) { //nolint:exhaustive // the other formats are binary
+		case kmsapi.PubKeyFormatJWK:
+			encoded = json.RawMessage(pub)
+		case kmsapi.PubKeyFormatSPKIPEM, kmsapi.PubKeyFormatMultibase:
+			encoded = string(pub)
+		default:
+			encoded = pub
+		}
+
+		return map[string]any{"keyID": *keyID, "format": *format, "publicKey": encoded}, nil
+	}
+}
+
+func exportPubKey(km kmsapi.KeyManager, keyID string, format kmsapi.PubKeyFormat) ([]byte, error) {
+	if exporter, ok := km.(kmsapi.PubKeyExporter); ok {
+		return exporter.ExportPubKey(keyID, format)
+	}
+
+	pub, kt, err := km.ExportPubKeyBytes(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pubkeyfmt.Encode(keyID, pub, kt, format)
+}
+
+func signCmd(fs *flag.FlagSet) func(*keystore, io.Reader, io.Writer) (any, error) {
+	keyID := fs.String("id", "", "ID of the signing key")
+	in := fs.String("in", "-", "message file, - for stdin")
+
+	return func(ks *keystore, stdin io.Reader, _ io.Writer) (any, error) {
+		kh, msg, err := keyAndInput(ks, *keyID, *in, stdin)
+		if err != nil {
+			return nil, err
+		}
+
+		sig, err := ks.crypto.Sign(msg, kh)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"keyID": *keyID, "signature": sig}, nil
+	}
+}
+
+func verifyCmd(fs *flag.FlagSet) func(*keystore, io.Reader, io.Writer) (any, error) {
+	keyID := fs.String("id", "", "ID of the signing key")
+	in := fs.String("in", "-", "message file, - for stdin")
+	sig := &bytesFlag{}
+	fs.Var(sig, "signature", "base64 signature")
+
+	return func(ks *keystore, stdin io.Reader, _ io.Writer) (any, error) {
+		kh, msg, err := keyAndInput(ks, *keyID, *in, stdin)
+		if err != nil {
+			return nil, err
+		}
+
+		// the local keysets are verified with their public keyset, the webkms keys with their key URL.
+		if h, ok := kh.(*keyset.Handle); ok {
+			if kh, err = h.Public(); err != nil {
+				return nil, err
+			}
+		}
+
+		if err = ks.crypto.Verify(*sig, msg, kh); err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"keyID": *keyID, "valid": true}, nil
+	}
+}
+
+func encryptCmd(fs *flag.FlagSet) func(*keystore, io.Reader, io.Writer) (any, error) {
+	keyID := fs.String("id", "", "ID of the encryption key")
+	in := fs.String("in", "-", "plaintext file, - for stdin")
+	aad := &bytesFlag{}
+	fs.Var(aad, "aad", "base64 additional authenticated data")
+
+	return func(ks *keystore, stdin io.Reader, _ io.Writer) (any, error) {
+		kh, msg, err := keyAndInput(ks, *keyID, *in, stdin)
+		if err != nil {
+			return nil, err
+		}
+
+		ciphertext, nonce, err := ks.crypto.Encrypt(msg, *aad, kh)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"keyID": *keyID, "ciphertext": ciphertext, "nonce": nonce}, nil
+	}
+}
+
+func decryptCmd(fs *flag.FlagSet) func(*keystore, io.Reader, io.Writer) (any, error) {
+	keyID := fs.String("id", "", "ID of the encryption key")
+	ciphertext, nonce, aad := &bytesFlag{}, &bytesFlag{}, &bytesFlag{}
+	fs.Var(ciphertext, "ciphertext", "base64 ciphertext")
+	fs.Var(nonce, "nonce", "base64 nonce")
+	fs.Var(aad, "aad", "base64 additional authenticated data")
+
+	return func(ks *keystore, _ io.Reader, _ io.Writer) (any, error) {
+		kh, err := getKey(ks, *keyID)
+		if err != nil {
+			return nil, err
+		}
+
+		plaintext, err := ks.crypto.Decrypt(*ciphertext, *aad, *nonce, kh)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"keyID": *keyID, "plaintext": plaintext}, nil
+	}
+}
+
+func wrapCmd(fs *flag.FlagSet) func(*keystore, io.Reader, io.Writer) (any, error) {
+	keyID := fs.String("id", "", "ID of the recipient key")
+	recipient := fs.String("recipient", "", "recipient public JWK file, eg: exported by a remote party, instead of --id")
+	cek, apu, apv := &bytesFlag{}, &bytesFlag{}, &bytesFlag{}
+	fs.Var(cek, "cek", "base64 content encryption key")
+	fs.Var(apu, "apu", "base64 agreement PartyUInfo")
+	fs.Var(apv, "apv", "base64 agreement PartyVInfo")
+
+	return func(ks *keystore, stdin io.Reader, _ io.Writer) (any, error) {
+		recPubKey, err := recipientKey(ks, *keyID, *recipient, stdin)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(*cek) == 0 {
+			return nil, errors.New("missing --cek")
+		}
+
+		return ks.crypto.WrapKey(*cek, *apu, *apv, recPubKey)
+	}
+}
+
+// recipientKey returns the public key of the key keyID, or the public JWK of the file path if keyID is not set.
+func recipientKey(ks *keystore, keyID, path string, stdin io.Reader) (*cryptoapi.PublicKey, error) {
+	switch {
+	case keyID != "" && path != "":
+		return nil, errors.New("--id and --recipient are exclusive")
+	case keyID != "":
+		// the ECDH public keys are exported as JSON crypto.PublicKey.
+		pub, _, err := ks.km.ExportPubKeyBytes(keyID)
+		if err != nil {
+			return nil, err
+		}
+
+		recPubKey := &cryptoapi.PublicKey{}
+
+		if err = json.Unmarshal(pub, recPubKey); err != nil {
+			return nil, fmt.Errorf("invalid recipient public key: %w", err)
+		}
+
+		return recPubKey, nil
+	case path != "":
+		v, err := readInput(path, stdin)
+		if err != nil {
+			return nil, err
+		}
+
+		j := &jwk.JWK{}
+
+		if err = j.UnmarshalJSON(v); err != nil {
+			return nil, fmt.Errorf("invalid recipient public key: %w", err)
+		}
+
+		return jwksupport.PublicKeyFromJWK(j)
+	default:
+		return nil, errors.New("missing --id or --recipient")
+	}
+}
+
+func unwrapCmd(fs *flag.FlagSet) func(*keystore, io.Reader, io.Writer) (any, error) {
+	keyID := fs.String("id", "", "ID of the recipient key")
+	in := fs.String("in", "-", "wrapped key file, the JSON output of the wrap command, - for stdin")
+
+	return func(ks *keystore, stdin io.Reader, _ io.Writer) (any, error) {
+		kh, v, err := keyAndInput(ks, *keyID, *in, stdin)
+		if err != nil {
+			return nil, err
+		}
+
+		wk := &cryptoapi.RecipientWrappedKey{}
+
+		if err = json.Unmarshal(v, wk); err != nil {
+			return nil, fmt.Errorf("invalid wrapped key: %w", err)
+		}
+
+		cek, err := ks.crypto.UnwrapKey(wk, kh)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"keyID": *keyID, "cek": cek}, nil
+	}
+}
+
+func getKey(ks *keystore, keyID string) (any, error) {
+	if keyID == "" {
+		return nil, errors.New("missing --id")
+	}
+
+	return ks.km.Get(keyID)
+}
+
+func keyAndInput(ks *keystore, keyID, path string, stdin io.Reader) (any, []byte, error) {
+	kh, err := getKey(ks, keyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v, err := readInput(path, stdin)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return kh, v, nil
+}
+
+// readInput reads the file path, or stdin if path is -.
+func readInput(path string, stdin io.Reader) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(stdin)
+	}
+
+	return os.ReadFile(filepath.Clean(path))
+}
+
+// bytesFlag is a base64 encoded flag, as the binary values of the JSON results.
+type bytesFlag []byte
+
+func (b *bytesFlag) String() string {
+	return base64.StdEncoding.EncodeToString(*b)
+}
+
+func (b *bytesFlag) Set(v string) error {
+	d, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return err
+	}
+
+	*b = d
+
+	return nil
+}
@@ -0,0 +1,193 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	webcrypto "github.com/trustbloc/kms-go/crypto/webkms"
+	"github.com/trustbloc/kms-go/kms/localkms"
+	"github.com/trustbloc/kms-go/kms/store/file"
+	"github.com/trustbloc/kms-go/kms/webkms"
+	"github.com/trustbloc/kms-go/secretlock/local"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/spi/secretlock"
+)
+
+const (
+	keysDir            = "keys"
+	masterKeyFile      = "master.key"
+	masterKeySize      = 32
+	masterKeyURI       = "local-lock://kmscli/master/key/"
+	defaultHTTPTimeout = 30 * time.Second
+)
+
+// keystore is the KeyManager and Crypto of a local keystore directory or of a webkms keystore. store is nil for the
+// webkms keystores.
+type keystore struct {
+	km     kmsapi.KeyManager
+	crypto cryptoapi.Crypto
+	store  kmsapi.Store
+}
+
+// globalFlags are the flags set before the command.
+type globalFlags struct {
+	keystoreDir   string
+	masterKeyFile string
+	webkmsURL     string
+	headers       headerFlags
+	timeout       time.Duration
+}
+
+// headerFlags are the "Name: value" HTTP headers of the webkms requests, eg: an Authorization header.
+type headerFlags []string
+
+func (h *headerFlags) String() string {
+	return strings.Join(*h, ", ")
+}
+
+func (h *headerFlags) Set(v string) error {
+	if name, _, ok := strings.Cut(v, ":"); !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("invalid header '%s', expected 'Name: value'", v)
+	}
+
+	*h = append(*h, v)
+
+	return nil
+}
+
+func openKeystore(g *globalFlags) (*keystore, error) {
+	switch {
+	case g.keystoreDir != "" && g.webkmsURL != "":
+		return nil, errors.New("--keystore-dir and --webkms-url are exclusive")
+	case g.keystoreDir != "":
+		return openLocalKeystore(g)
+	case g.webkmsURL != "":
+		return openWebKeystore(g), nil
+	default:
+		return nil, errors.New("a keystore must be set with --keystore-dir or --webkms-url")
+	}
+}
+
+func openLocalKeystore(g *globalFlags) (*keystore, error) {
+	store, err := file.New(filepath.Join(g.keystoreDir, keysDir))
+	if err != nil {
+		return nil, fmt.Errorf("open keystore: %w", err)
+	}
+
+	lock, err := masterKeyLock(g)
+	if err != nil {
+		return nil, fmt.Errorf("open keystore: %w", err)
+	}
+
+	km, err := localkms.New(masterKeyURI, &kmsProvider{store: store, lock: lock})
+	if err != nil {
+		return nil, fmt.Errorf("open keystore: %w", err)
+	}
+
+	c, err := tinkcrypto.New()
+	if err != nil {
+		return nil, fmt.Errorf("open keystore: %w", err)
+	}
+
+	return &keystore{km: km, crypto: c, store: store}, nil
+}
+
+// masterKeyLock returns the secret lock of the master key file, created with a random master key in the keystore
+// directory if no master key file is set.
+func masterKeyLock(g *globalFlags) (secretlock.Service, error) {
+	path := g.masterKeyFile
+
+	if path == "" {
+		path = filepath.Join(g.keystoreDir, masterKeyFile)
+
+		if err := createMasterKey(path); err != nil {
+			return nil, err
+		}
+	}
+
+	r, err := local.MasterKeyFromPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("read master key: %w", err)
+	}
+
+	return local.NewService(r, nil)
+}
+
+func createMasterKey(path string) error {
+	key := make([]byte, masterKeySize)
+
+	if _, err := rand.Read(key); err != nil {
+		return fmt.Errorf("create master key: %w", err)
+	}
+
+	f, err := os.OpenFile(filepath.Clean(path), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("create master key: %w", err)
+	}
+
+	_, err = f.WriteString(base64.URLEncoding.EncodeToString(key))
+
+	if e := f.Close(); err == nil {
+		err = e
+	}
+
+	if err != nil {
+		return fmt.Errorf("create master key: %w", err)
+	}
+
+	return nil
+}
+
+func openWebKeystore(g *globalFlags) *keystore {
+	client := &http.Client{Timeout: g.timeout}
+	headers := g.headers
+
+	opt := webkms.WithHeaders(func(*http.Request) (*http.Header, error) {
+		h := http.Header{}
+
+		for _, v := range headers {
+			name, value, _ := strings.Cut(v, ":")
+			h.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+
+		return &h, nil
+	})
+
+	return &keystore{
+		km:     webkms.New(g.webkmsURL, client, opt),
+		crypto: webcrypto.New(g.webkmsURL, client, opt),
+	}
+}
+
+type kmsProvider struct {
+	store kmsapi.Store
+	lock  secretlock.Service
+}
+
+func (p *kmsProvider) StorageProvider() kmsapi.Store {
+	return p.store
+}
+
+func (p *kmsProvider) SecretLock() secretlock.Service {
+	return p.lock
+}
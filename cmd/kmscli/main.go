@@ -0,0 +1,156 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Command kmscli manages the keys of a local keystore directory or of a webkms keystore, and executes crypto
+// operations with them, for the manual operations of the KMS operators:
+//
+//	kmscli --keystore-dir ./keys create --type ED25519
+//	kmscli --webkms-url https://kms.example.com/v1/keystores/default sign --id <key ID> --in release.tar.gz
+//
+// The commands are create, list, rotate, delete, export, sign, verify, encrypt, decrypt, wrap and unwrap, and
+// ceremony and recover, generating the master key of a local keystore in a key ceremony and recovering it from the
+// shares of the operators (see secretlock/local/ceremony). Run "kmscli <command> -h" for their flags. The results are
+// written to stdout as JSON objects, binary values being base64 encoded, and the errors to stderr as a
+// {"error": "..."} JSON object, ending the output, with the exit status 1.
+//
+// A local keystore directory holds the keysets in its keys subdirectory, encrypted with the master key of its
+// master.key file, created with a random key on first use unless --master-key-file is set, eg: to the master key file
+// written by the recover command. The list, rotate and delete commands are only supported by the local keystores.
+//
+// The ceremony and recover commands prompt the operators on stderr and read their answers from stdin, one per line.
+// They don't disable the terminal echo of the passphrases: they are meant to be run on the offline machine of the
+// ceremony, with the terminal cleared before the next operator enters their passphrase.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command of args, and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	g := &globalFlags{}
+
+	// the flag errors are reported as JSON errors, only the -h usage is written to stderr.
+	flagOut := &bytes.Buffer{}
+
+	fs := flag.NewFlagSet("kmscli", flag.ContinueOnError)
+	fs.SetOutput(flagOut)
+	fs.StringVar(&g.keystoreDir, "keystore-dir", "", "local keystore directory")
+	fs.StringVar(&g.masterKeyFile, "master-key-file", "", "master key file of the local keystore "+
+		"(default <keystore-dir>/master.key)")
+	fs.StringVar(&g.webkmsURL, "webkms-url", "", "webkms keystore URL, eg: https://kms.example.com/v1/keystores/<ID>")
+	fs.Var(&g.headers, "header", "'Name: value' HTTP header of the webkms requests, can be repeated")
+	fs.DurationVar(&g.timeout, "timeout", defaultHTTPTimeout, "timeout of the webkms requests")
+	fs.Usage = func() {
+		fmt.Fprintf(flagOut, "Usage: kmscli [flags] <command> [command flags]\n\nCommands:\n%s\nFlags:\n", usage())
+		fs.PrintDefaults()
+	}
+
+	err := fs.Parse(args)
+	if err == nil && fs.NArg() == 0 {
+		err = errors.New("missing command, run kmscli -h for the commands")
+	}
+
+	var out any
+
+	if err == nil {
+		out, err = execute(g, fs.Arg(0), fs.Args()[1:], stdin, stderr, flagOut)
+	}
+
+	if errors.Is(err, flag.ErrHelp) {
+		_, _ = io.Copy(stderr, flagOut) //nolint:errcheck // nowhere to report it
+
+		return 0
+	}
+
+	if err == nil {
+		err = writeJSON(stdout, out)
+	}
+
+	if err != nil {
+		// the error is written on its own line, after the prompts of the interactive commands.
+		_ = json.NewEncoder(stderr).Encode(map[string]string{"error": err.Error()}) //nolint:errcheck // nowhere to report it
+
+		return 1
+	}
+
+	return 0
+}
+
+func execute(g *globalFlags, name string, args []string, stdin io.Reader, stderr, flagOut io.Writer) (any, error) {
+	cmd, ok := commands[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown command '%s'", name)
+	}
+
+	fs := flag.NewFlagSet("kmscli "+name, flag.ContinueOnError)
+	fs.SetOutput(flagOut)
+
+	exec := cmd.flags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("%s: unexpected arguments %s", name, strings.Join(fs.Args(), " "))
+	}
+
+	var (
+		ks  *keystore
+		err error
+	)
+
+	if !cmd.noKeystore {
+		if ks, err = openKeystore(g); err != nil {
+			return nil, err
+		}
+	}
+
+	out, err := exec(ks, stdin, stderr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return out, nil
+}
+
+func usage() string {
+	names := make([]string, 0, len(commands))
+
+	for name := range commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	b := &strings.Builder{}
+
+	for _, name := range names {
+		fmt.Fprintf(b, "  %-9s %s\n", name, commands[name].usage)
+	}
+
+	return b.String()
+}
+
+func writeJSON(w io.Writer, v any) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+
+	return e.Encode(v)
+}
@@ -0,0 +1,366 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	mockstorage "github.com/trustbloc/kms-go/internal/mock/storage"
+	"github.com/trustbloc/kms-go/kms"
+	"github.com/trustbloc/kms-go/kms/localkms"
+	"github.com/trustbloc/kms-go/kms/server"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+)
+
+type result struct {
+	status int
+	out    map[string]any
+	err    string
+}
+
+func runCLI(t *testing.T, stdin string, args ...string) *result {
+	t.Helper()
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	r := &result{status: run(args, strings.NewReader(stdin), stdout, stderr)}
+
+	if stdout.Len() > 0 {
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &r.out))
+	}
+
+	if r.status != 0 {
+		e := map[string]string{}
+
+		// the error follows the prompts of the interactive commands.
+		i := strings.LastIndex(stderr.String(), `{"error":`)
+
+		if i >= 0 && json.Unmarshal(stderr.Bytes()[i:], &e) == nil {
+			r.err = e["error"]
+		}
+	}
+
+	return r
+}
+
+func mustRun(t *testing.T, stdin string, args ...string) map[string]any {
+	t.Helper()
+
+	r := runCLI(t, stdin, args...)
+	require.Equal(t, 0, r.status, r.err)
+
+	return r.out
+}
+
+func TestLocalKeystore(t *testing.T) {
+	dir := t.TempDir()
+	ks := []string{"--keystore-dir", dir}
+
+	cmd := func(args ...string) []string {
+		return append(append([]string{}, ks...), args...)
+	}
+
+	out := mustRun(t, "", cmd("create", "--type", "ED25519", "--id", "signing")...)
+	require.Equal(t, map[string]any{"keyID": "signing", "keyType": "ED25519"}, out)
+
+	t.Run("sign and verify", func(t *testing.T) {
+		sig := mustRun(t, "release", cmd("sign", "--id", "signing")...)["signature"].(string)
+
+		out := mustRun(t, "release", cmd("verify", "--id", "signing", "--signature", sig)...)
+		require.Equal(t, true, out["valid"])
+
+		r := runCLI(t, "tampered", cmd("verify", "--id", "signing", "--signature", sig)...)
+		require.Equal(t, 1, r.status)
+		require.Contains(t, r.err, "verify:")
+
+		msg := filepath.Join(t.TempDir(), "msg")
+		require.NoError(t, os.WriteFile(msg, []byte("release"), 0o600))
+
+		out = mustRun(t, "", cmd("verify", "--id", "signing", "--in", msg, "--signature", sig)...)
+		require.Equal(t, true, out["valid"])
+
+		pemKey := mustRun(t, "", cmd("export", "--id", "signing", "--format", "spki-pem")...)["publicKey"].(string)
+		block, _ := pem.Decode([]byte(pemKey))
+		require.NotNil(t, block)
+
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		require.NoError(t, err)
+
+		rawSig, err := base64.StdEncoding.DecodeString(sig)
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(pub.(ed25519.PublicKey), []byte("release"), rawSig))
+	})
+
+	t.Run("export", func(t *testing.T) {
+		out := mustRun(t, "", cmd("export", "--id", "signing")...)
+		require.Equal(t, "jwk", out["format"])
+		require.Equal(t, "OKP", out["publicKey"].(map[string]any)["kty"])
+
+		out = mustRun(t, "", cmd("export", "--id", "signing", "--format", "raw")...)
+
+		raw, err := base64.StdEncoding.DecodeString(out["publicKey"].(string))
+		require.NoError(t, err)
+		require.Len(t, raw, ed25519.PublicKeySize)
+
+		require.Contains(t, runCLI(t, "", cmd("export", "--id", "signing", "--format", "ssh")...).err, "export:")
+	})
+
+	t.Run("encrypt and decrypt", func(t *testing.T) {
+		keyID := mustRun(t, "", cmd("create", "--type", "AES256GCM")...)["keyID"].(string)
+		aad := base64.StdEncoding.EncodeToString([]byte("context"))
+
+		out := mustRun(t, "secret", cmd("encrypt", "--id", keyID, "--aad", aad)...)
+
+		out = mustRun(t, "", cmd("decrypt", "--id", keyID, "--aad", aad,
+			"--ciphertext", out["ciphertext"].(string), "--nonce", out["nonce"].(string))...)
+		require.Equal(t, base64.StdEncoding.EncodeToString([]byte("secret")), out["plaintext"])
+	})
+
+	t.Run("wrap and unwrap", func(t *testing.T) {
+		keyID := mustRun(t, "", cmd("create", "--type", "NISTP256ECDHKW")...)["keyID"].(string)
+		cek := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+
+		wk := mustRun(t, "", cmd("wrap", "--id", keyID, "--cek", cek)...)
+		require.Equal(t, keyID, wk["kid"])
+
+		wkJSON, err := json.Marshal(wk)
+		require.NoError(t, err)
+
+		out := mustRun(t, string(wkJSON), cmd("unwrap", "--id", keyID)...)
+		require.Equal(t, cek, out["cek"])
+
+		recipient := filepath.Join(t.TempDir(), "recipient.json")
+		pub, err := json.Marshal(mustRun(t, "", cmd("export", "--id", keyID)...)["publicKey"])
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(recipient, pub, 0o600))
+
+		wk = mustRun(t, "", cmd("wrap", "--recipient", recipient, "--cek", cek)...)
+		wkJSON, err = json.Marshal(wk)
+		require.NoError(t, err)
+
+		out = mustRun(t, string(wkJSON), cmd("unwrap", "--id", keyID)...)
+		require.Equal(t, cek, out["cek"])
+	})
+
+	t.Run("rotate, list and delete", func(t *testing.T) {
+		out := mustRun(t, "", cmd("rotate", "--id", "signing", "--type", "ECDSAP256DER")...)
+		require.Equal(t, "signing", out["previousKeyID"])
+		require.Equal(t, "ECDSAP256DER", out["keyType"])
+
+		rotated := out["keyID"].(string)
+
+		// the rotated key replaces the key, reopening the keystore must use the same master key.
+		keys := mustRun(t, "", cmd("list")...)["keys"].([]any)
+		require.Len(t, keys, 3)
+		require.Contains(t, keys, map[string]any{"keyID": rotated, "keyType": "ECDSAP256DER"})
+		require.NotContains(t, keys, map[string]any{"keyID": "signing", "keyType": "ED25519"})
+
+		mustRun(t, "", cmd("delete", "--id", rotated)...)
+
+		keys = mustRun(t, "", cmd("list")...)["keys"].([]any)
+		require.Len(t, keys, 2)
+		require.NotContains(t, keys, map[string]any{"keyID": rotated, "keyType": "ECDSAP256DER"})
+	})
+}
+
+func TestWebKMS(t *testing.T) {
+	store, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+	require.NoError(t, err)
+
+	km, err := localkms.New("local-lock://test/master/key/", &kmsProvider{store: store, lock: &noop.NoLock{}})
+	require.NoError(t, err)
+
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(server.New(km, c, server.WithMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			next.ServeHTTP(w, req)
+		})
+	})))
+	t.Cleanup(srv.Close)
+
+	ks := []string{"--webkms-url", srv.URL + "/v1/keystores/default", "--header", "Authorization: Bearer token"}
+
+	cmd := func(args ...string) []string {
+		return append(append([]string{}, ks...), args...)
+	}
+
+	keyID := mustRun(t, "", cmd("create", "--type", "ECDSAP256DER")...)["keyID"].(string)
+
+	sig := mustRun(t, "release", cmd("sign", "--id", keyID)...)["signature"].(string)
+	require.Equal(t, true, mustRun(t, "release", cmd("verify", "--id", keyID, "--signature", sig)...)["valid"])
+	require.Equal(t, 1, runCLI(t, "tampered", cmd("verify", "--id", keyID, "--signature", sig)...).status)
+
+	out := mustRun(t, "", cmd("export", "--id", keyID)...)
+	require.Equal(t, "EC", out["publicKey"].(map[string]any)["kty"])
+
+	require.Equal(t, "list: not supported by webkms keystores", runCLI(t, "", cmd("list")...).err)
+	require.Equal(t, "delete: not supported by webkms keystores", runCLI(t, "", cmd("delete", "--id", keyID)...).err)
+
+	r := runCLI(t, "", "--webkms-url", srv.URL+"/v1/keystores/default", "create", "--type", "ED25519")
+	require.Equal(t, 1, r.status)
+	require.Contains(t, r.err, "create:")
+}
+
+func TestErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, tc := range []struct {
+		args []string
+		err  string
+	}{
+		{args: []string{"create"}, err: "a keystore must be set with --keystore-dir or --webkms-url"},
+		{args: []string{"--keystore-dir", dir, "--webkms-url", "http://localhost", "list"},
+			err: "--keystore-dir and --webkms-url are exclusive"},
+		{args: []string{"--keystore-dir", dir, "generate"}, err: "unknown command 'generate'"},
+		{args: []string{"--keystore-dir", dir, "create"}, err: "create: missing --type"},
+		{args: []string{"--keystore-dir", dir, "create", "--type", "P256"}, err: "create:"},
+		{args: []string{"--keystore-dir", dir, "create", "--type", "ED25519", "extra"},
+			err: "create: unexpected arguments extra"},
+		{args: []string{"--keystore-dir", dir, "sign"}, err: "sign: missing --id"},
+		{args: []string{"--keystore-dir", dir, "sign", "--id", "missing"}, err: "sign:"},
+		{args: []string{"--keystore-dir", dir, "sign", "--id", "missing", "--nope"}, err: "sign: flag provided"},
+		{args: []string{"--nope"}, err: "flag provided but not defined: -nope"},
+		{args: []string{}, err: "missing command"},
+		{args: []string{"--keystore-dir", dir, "verify", "--signature", "!"}, err: "verify: invalid value"},
+		{args: []string{"--keystore-dir", dir, "rotate", "--id", "k"}, err: "rotate: missing --id or --type"},
+		{args: []string{"--keystore-dir", dir, "delete"}, err: "delete: missing --id"},
+		{args: []string{"--keystore-dir", dir, "export"}, err: "export: missing --id"},
+		{args: []string{"--keystore-dir", dir, "decrypt"}, err: "decrypt: missing --id"},
+		{args: []string{"--keystore-dir", dir, "wrap"}, err: "wrap: missing --id or --recipient"},
+		{args: []string{"--keystore-dir", dir, "wrap", "--id", "k", "--recipient", "r"},
+			err: "wrap: --id and --recipient are exclusive"},
+		{args: []string{"--keystore-dir", dir, "wrap", "--recipient", "-"}, err: "wrap: invalid recipient public key"},
+		{args: []string{"--keystore-dir", dir, "unwrap", "--id", "k"}, err: "unwrap:"},
+		{args: []string{"--header", "token", "list"}, err: ""},
+		{args: []string{"--keystore-dir", dir, "--master-key-file", filepath.Join(dir, "missing"), "list"},
+			err: "open keystore: read master key:"},
+		{args: []string{"--keystore-dir", filepath.Join(dir, "master.key"), "list"}, err: "open keystore:"},
+	} {
+		r := runCLI(t, "", tc.args...)
+		require.Equal(t, 1, r.status, tc.args)
+		require.Contains(t, r.err, tc.err, tc.args)
+	}
+
+	r := runCLI(t, "", "--keystore-dir", dir, "create", "--type", "ED25519")
+	require.Equal(t, 0, r.status)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "recipient.json"), []byte("{"), 0o600))
+
+	r = runCLI(t, "", "--keystore-dir", dir, "wrap", "--recipient", filepath.Join(dir, "recipient.json"),
+		"--cek", "AA==")
+	require.Contains(t, r.err, "wrap: invalid recipient public key")
+
+	r = runCLI(t, "", "--keystore-dir", dir, "wrap", "--recipient", filepath.Join(dir, "recipient.json"))
+	require.Contains(t, r.err, "wrap: invalid recipient public key")
+
+	stderr := &bytes.Buffer{}
+	require.Equal(t, 0, run([]string{"-h"}, nil, &bytes.Buffer{}, stderr))
+	require.Contains(t, stderr.String(), "unwrap    unwrap a wrapped content encryption key")
+
+	stderr.Reset()
+	require.Equal(t, 0, run([]string{"--keystore-dir", dir, "sign", "-h"}, nil, &bytes.Buffer{}, stderr))
+	require.Contains(t, stderr.String(), "-in string")
+}
+
+func TestCeremony(t *testing.T) {
+	dir := t.TempDir()
+	shares := filepath.Join(dir, "shares")
+
+	answers := strings.Join([]string{
+		"alice", "short", // too short, alice is prompted again
+		"alice", strings.Repeat("a", 16),
+		"bob", strings.Repeat("b", 16),
+		"carol", strings.Repeat("c", 16),
+		"alice passphrase", "typo", // confirmation mismatch, alice is prompted again
+		"alice passphrase", "alice passphrase",
+		"bob passphrase", "bob passphrase",
+		"carol passphrase", "carol passphrase",
+	}, "\n")
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	status := run([]string{"ceremony", "--threshold", "2", "--operators", "3", "--out", shares, "--kdf-iterations",
+		"1000"}, strings.NewReader(answers), stdout, stderr)
+	require.Equal(t, 0, status, stderr.String())
+	require.Contains(t, stderr.String(), "entropy of operator 'alice' must be at least 16 bytes, try again.")
+	require.Contains(t, stderr.String(), "The passphrases are empty or don't match, try again.")
+
+	out := map[string]any{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &out))
+
+	fingerprint := out["masterKeyFingerprint"].(string)
+	require.Contains(t, stderr.String(), "Master key fingerprint: "+fingerprint)
+
+	operators := out["operators"].([]any)
+	require.Len(t, operators, 3)
+
+	bob := operators[1].(map[string]any)
+	require.Equal(t, "bob", bob["operator"])
+	require.Equal(t, filepath.Join(shares, "002-bob.share.json"), bob["shareFile"])
+	require.Contains(t, stderr.String(), "Contribution fingerprint of bob: "+bob["contributionFingerprint"].(string))
+
+	masterKey := filepath.Join(dir, "master.key")
+
+	r := runCLI(t, "carol passphrase\nbob passphrase\n", "recover", "--out", masterKey,
+		"--share", filepath.Join(shares, "003-carol.share.json"), "--share", bob["shareFile"].(string))
+	require.Equal(t, 0, r.status, r.err)
+	require.Equal(t, fingerprint, r.out["masterKeyFingerprint"])
+	require.Equal(t, out["ceremonyID"], r.out["ceremonyID"])
+
+	ks := []string{"--keystore-dir", filepath.Join(dir, "keystore"), "--master-key-file", masterKey}
+
+	keyID := mustRun(t, "", append(ks, "create", "--type", "ED25519")...)["keyID"].(string)
+	mustRun(t, "", append(ks, "export", "--id", keyID)...)
+
+	for _, tc := range []struct {
+		stdin string
+		args  []string
+		err   string
+	}{
+		{args: []string{"ceremony", "--threshold", "3", "--operators", "2", "--out", shares},
+			err: "ceremony: missing --out, or less --operators than --threshold"},
+		{args: []string{"ceremony", "--threshold", "1", "--operators", "2", "--out", shares},
+			err: "ceremony: new ceremony: threshold must be between 2 and 255"},
+		{stdin: "alice\n", args: []string{"ceremony", "--threshold", "2", "--operators", "2", "--out", shares},
+			err: "ceremony: read answer: EOF"},
+		{args: []string{"recover", "--out", masterKey}, err: "recover: missing --out or --share"},
+		{stdin: "wrong\n", args: []string{"recover", "--out", masterKey, "--share", bob["shareFile"].(string)},
+			err: "recover: unseal share of operator 'bob': wrong passphrase or corrupted share"},
+		{stdin: "bob passphrase\n", args: []string{"recover", "--out", masterKey, "--share", bob["shareFile"].(string)},
+			err: "recover: recover master key: 1 shares for a threshold of 2"},
+		{stdin: "carol passphrase\nbob passphrase\n", args: []string{"recover", "--out", masterKey,
+			"--share", filepath.Join(shares, "003-carol.share.json"), "--share", bob["shareFile"].(string)},
+			err: "recover: open " + masterKey + ": file exists"},
+		{args: []string{"recover", "--out", masterKey, "--share", masterKey}, err: "recover: invalid share file"},
+	} {
+		r = runCLI(t, tc.stdin, tc.args...)
+		require.Equal(t, 1, r.status, tc.args)
+		require.Contains(t, r.err, tc.err, tc.args)
+	}
+}
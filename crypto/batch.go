@@ -0,0 +1,85 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package crypto
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/trustbloc/kms-go/spi/crypto"
+)
+
+type signer interface {
+	Sign(msg []byte, kh interface{}) ([]byte, error)
+}
+
+// SignBatch signs messages with kh using c and returns the signatures in the order of messages. It calls
+// c.SignBatch if c is a crypto.BatchSigner, c.Sign for every message otherwise.
+func SignBatch(c signer, messages [][]byte, kh interface{}, opts ...crypto.SignBatchOpts) ([][]byte, error) {
+	if bs, ok := c.(crypto.BatchSigner); ok {
+		return bs.SignBatch(messages, kh, opts...)
+	}
+
+	batchOpts := crypto.NewSignBatchOpt()
+
+	for _, opt := range opts {
+		opt(batchOpts)
+	}
+
+	return SignEach(messages, batchOpts.Parallelism(), func(msg []byte) ([]byte, error) {
+		return c.Sign(msg, kh)
+	})
+}
+
+// SignEach calls sign for every message of messages, with at most parallelism concurrent calls, and returns the
+// signatures in the order of messages. It fails with the error of the first failing message.
+func SignEach(messages [][]byte, parallelism int, sign func(msg []byte) ([]byte, error)) ([][]byte, error) {
+	sigs := make([][]byte, len(messages))
+	errs := make([]error, len(messages))
+
+	if parallelism <= 1 {
+		for i, msg := range messages {
+			sig, err := sign(msg)
+			if err != nil {
+				return nil, fmt.Errorf("sign message %d: %w", i, err)
+			}
+
+			sigs[i] = sig
+		}
+
+		return sigs, nil
+	}
+
+	var wg sync.WaitGroup
+
+	sem := make(chan struct{}, parallelism)
+
+	for i := range messages {
+		wg.Add(1)
+
+		sem <- struct{}{}
+
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			sigs[i], errs[i] = sign(messages[i])
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("sign message %d: %w", i, err)
+		}
+	}
+
+	return sigs, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package crypto
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/spi/crypto"
+)
+
+type reverseSigner struct {
+	active, maxActive int32
+	failOn            string
+}
+
+func (s *reverseSigner) Sign(msg []byte, _ interface{}) ([]byte, error) {
+	n := atomic.AddInt32(&s.active, 1)
+	defer atomic.AddInt32(&s.active, -1)
+
+	for {
+		m := atomic.LoadInt32(&s.maxActive)
+		if n <= m || atomic.CompareAndSwapInt32(&s.maxActive, m, n) {
+			break
+		}
+	}
+
+	if string(msg) == s.failOn {
+		return nil, errors.New("sign failure")
+	}
+
+	sig := make([]byte, len(msg))
+	for i := range msg {
+		sig[i] = msg[len(msg)-1-i]
+	}
+
+	return sig, nil
+}
+
+type batchSigner struct {
+	reverseSigner
+}
+
+func (s *batchSigner) SignBatch(_ [][]byte, _ interface{}, _ ...crypto.SignBatchOpts) ([][]byte, error) {
+	return [][]byte{[]byte("batch")}, nil
+}
+
+func TestSignBatch(t *testing.T) {
+	msgs := [][]byte{[]byte("ab"), []byte("cd"), []byte("ef"), []byte("gh"), []byte("ij")}
+
+	t.Run("fallback to Sign keeps the order of messages", func(t *testing.T) {
+		for _, parallelism := range []int{0, 1, 3, 10} {
+			s := &reverseSigner{}
+
+			sigs, err := SignBatch(s, msgs, nil, crypto.WithParallelism(parallelism))
+			require.NoError(t, err)
+			require.Equal(t, [][]byte{[]byte("ba"), []byte("dc"), []byte("fe"), []byte("hg"), []byte("ji")}, sigs)
+
+			limit := int32(parallelism)
+			if limit < 1 {
+				limit = 1
+			}
+
+			require.LessOrEqual(t, s.maxActive, limit)
+		}
+	})
+
+	t.Run("uses BatchSigner", func(t *testing.T) {
+		sigs, err := SignBatch(&batchSigner{}, msgs, nil)
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{[]byte("batch")}, sigs)
+	})
+
+	t.Run("fails with the first failing message", func(t *testing.T) {
+		for _, parallelism := range []int{1, 4} {
+			sigs, err := SignBatch(&reverseSigner{failOn: "ef"}, msgs, nil, crypto.WithParallelism(parallelism))
+			require.EqualError(t, err, "sign message 2: sign failure")
+			require.Nil(t, sigs)
+		}
+	})
+}
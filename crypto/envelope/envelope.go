@@ -0,0 +1,214 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package envelope encrypts objects with envelope encryption: each object is encrypted with a fresh data encryption
+// key (DEK), and the DEK is wrapped with a key encryption key (KEK) held by a KMS (an AES128KW, AES192KW or AES256KW
+// key). Only the wrapped DEK is sent to the Crypto implementation, large payloads are encrypted locally.
+//
+// EncryptObject returns a self-describing JSON envelope carrying the KEK key ID, the key wrapping and content
+// encryption algorithms, the wrapped DEK, the nonce and the ciphertext. The envelope header is authenticated with the
+// payload, so the fields of an envelope can't be swapped with the ones of another envelope.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/trustbloc/kms-go/internal/memguard"
+	"github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/util/cryptoutil"
+)
+
+// Version is the envelope format version.
+const Version = 1
+
+// Content encryption algorithms.
+const (
+	EncA256GCM = "A256GCM"
+	EncXC20P   = "XC20P"
+)
+
+const dekSize = 32
+
+// Envelope is an encrypted object.
+type Envelope struct {
+	Version int `json:"v"`
+	// KID is the key ID of the KEK.
+	KID string `json:"kid"`
+	// Alg is the key wrapping algorithm of the DEK (eg: A256KW).
+	Alg string `json:"alg"`
+	// Enc is the content encryption algorithm of the payload.
+	Enc          string `json:"enc"`
+	EncryptedDEK []byte `json:"encrypted_dek"`
+	Nonce        []byte `json:"nonce"`
+	Ciphertext   []byte `json:"ciphertext"`
+}
+
+// Parse parses a serialized envelope, eg: to find the KEK of an object without decrypting it.
+func Parse(serialized []byte) (*Envelope, error) {
+	env := &Envelope{}
+
+	if err := json.Unmarshal(serialized, env); err != nil {
+		return nil, fmt.Errorf("parse envelope: %w", err)
+	}
+
+	if env.Version != Version {
+		return nil, fmt.Errorf("parse envelope: unsupported version %d", env.Version)
+	}
+
+	return env, nil
+}
+
+// Service encrypts and decrypts objects with KEKs managed by a KeyManager, the DEKs are wrapped by a Crypto
+// implementing crypto.KEKWrapper.
+type Service struct {
+	km     kms.KeyManager
+	crypto crypto.Crypto
+	enc    string
+}
+
+// Opt is a Service option.
+type Opt func(s *Service)
+
+// WithContentEncryption sets the content encryption algorithm of EncryptObject, EncA256GCM (default) or EncXC20P.
+func WithContentEncryption(enc string) Opt {
+	return func(s *Service) {
+		s.enc = enc
+	}
+}
+
+// New creates a new envelope encryption Service.
+func New(km kms.KeyManager, c crypto.Crypto, opts ...Opt) *Service {
+	s := &Service{km: km, crypto: c, enc: EncA256GCM}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// EncryptObject encrypts plaintext and aad with a fresh DEK, wraps the DEK with the KEK kekID and returns the
+// serialized envelope. aad is not part of the envelope, the same aad must be given to DecryptObject.
+func (s *Service) EncryptObject(kekID string, plaintext, aad []byte) ([]byte, error) {
+	kw, ok := s.crypto.(crypto.KEKWrapper)
+	if !ok {
+		return nil, errors.New("encrypt object: crypto doesn't support key wrapping with a KEK")
+	}
+
+	kek, err := s.km.Get(kekID)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt object: get KEK: %w", err)
+	}
+
+	dek := make([]byte, dekSize)
+	defer memguard.Wipe(dek)
+
+	if _, err = io.ReadFull(rand.Reader, dek); err != nil {
+		return nil, fmt.Errorf("encrypt object: generate DEK: %w", err)
+	}
+
+	wrapped, err := kw.WrapKeyWithKEK(dek, kek)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt object: wrap DEK: %w", err)
+	}
+
+	env := &Envelope{Version: Version, KID: kekID, Alg: wrapped.Alg, Enc: s.enc, EncryptedDEK: wrapped.EncryptedCEK}
+
+	aead, err := newAEAD(env.Enc, dek)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt object: %w", err)
+	}
+
+	env.Nonce = make([]byte, aead.NonceSize())
+
+	if _, err = io.ReadFull(rand.Reader, env.Nonce); err != nil {
+		return nil, fmt.Errorf("encrypt object: generate nonce: %w", err)
+	}
+
+	env.Ciphertext = aead.Seal(nil, env.Nonce, plaintext, env.aad(aad))
+
+	serialized, err := json.Marshal(env)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt object: %w", err)
+	}
+
+	return serialized, nil
+}
+
+// DecryptObject unwraps the DEK of the serialized envelope with its KEK and decrypts the payload with aad.
+func (s *Service) DecryptObject(serialized, aad []byte) ([]byte, error) {
+	env, err := Parse(serialized)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt object: %w", err)
+	}
+
+	kek, err := s.km.Get(env.KID)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt object: get KEK: %w", err)
+	}
+
+	dek, err := s.crypto.UnwrapKey(&crypto.RecipientWrappedKey{EncryptedCEK: env.EncryptedDEK, Alg: env.Alg}, kek)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt object: unwrap DEK: %w", err)
+	}
+
+	defer memguard.Wipe(dek)
+
+	aead, err := newAEAD(env.Enc, dek)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt object: %w", err)
+	}
+
+	if len(env.Nonce) != aead.NonceSize() {
+		return nil, errors.New("decrypt object: invalid nonce size")
+	}
+
+	plaintext, err := aead.Open(nil, env.Nonce, env.Ciphertext, env.aad(aad))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt object: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// aad returns the additional authenticated data of the payload: the envelope header followed by the caller aad.
+func (e *Envelope) aad(aad []byte) []byte {
+	var b []byte
+
+	for _, field := range [][]byte{
+		[]byte(strconv.Itoa(e.Version)), []byte(e.KID), []byte(e.Alg), []byte(e.Enc), e.EncryptedDEK, aad,
+	} {
+		b = append(b, cryptoutil.LengthPrefix(field)...)
+	}
+
+	return b
+}
+
+func newAEAD(enc string, dek []byte) (cipher.AEAD, error) {
+	switch enc {
+	case EncA256GCM:
+		block, err := aes.NewCipher(dek)
+		if err != nil {
+			return nil, err
+		}
+
+		return cipher.NewGCM(block)
+	case EncXC20P:
+		return chacha20poly1305.NewX(dek)
+	default:
+		return nil, fmt.Errorf("unsupported content encryption algorithm '%s'", enc)
+	}
+}
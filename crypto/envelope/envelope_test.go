@@ -0,0 +1,148 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package envelope_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/envelope"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func TestService(t *testing.T) {
+	km := mockkms.NewForTest(t)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	kekID, _, err := km.Create(kmsapi.AES256KWType)
+	require.NoError(t, err)
+
+	plaintext := []byte("a large object")
+	aad := []byte("object ID")
+
+	for _, enc := range []string{envelope.EncA256GCM, envelope.EncXC20P} {
+		t.Run(enc, func(t *testing.T) {
+			s := envelope.New(km, cr, envelope.WithContentEncryption(enc))
+
+			serialized, err := s.EncryptObject(kekID, plaintext, aad)
+			require.NoError(t, err)
+
+			env, err := envelope.Parse(serialized)
+			require.NoError(t, err)
+			require.Equal(t, envelope.Version, env.Version)
+			require.Equal(t, kekID, env.KID)
+			require.Equal(t, tinkcrypto.A256KWAlg, env.Alg)
+			require.Equal(t, enc, env.Enc)
+
+			other, err := s.EncryptObject(kekID, plaintext, aad)
+			require.NoError(t, err)
+
+			otherEnv, err := envelope.Parse(other)
+			require.NoError(t, err)
+			require.NotEqual(t, env.EncryptedDEK, otherEnv.EncryptedDEK)
+
+			// any KeyManager holding the KEK decrypts the envelope.
+			decrypted, err := envelope.New(km, cr).DecryptObject(serialized, aad)
+			require.NoError(t, err)
+			require.Equal(t, plaintext, decrypted)
+
+			_, err = s.DecryptObject(serialized, []byte("other object ID"))
+			require.Error(t, err)
+
+			// the DEK of another object doesn't decrypt the payload.
+			env.EncryptedDEK = otherEnv.EncryptedDEK
+			_, err = s.DecryptObject(marshal(t, env), aad)
+			require.Error(t, err)
+		})
+	}
+
+	t.Run("tampered envelope", func(t *testing.T) {
+		s := envelope.New(km, cr)
+
+		serialized, err := s.EncryptObject(kekID, plaintext, nil)
+		require.NoError(t, err)
+
+		env, err := envelope.Parse(serialized)
+		require.NoError(t, err)
+
+		env.Ciphertext[0] ^= 1
+		_, err = s.DecryptObject(marshal(t, env), nil)
+		require.Error(t, err)
+		env.Ciphertext[0] ^= 1
+
+		env.Nonce = env.Nonce[1:]
+		_, err = s.DecryptObject(marshal(t, env), nil)
+		require.EqualError(t, err, "decrypt object: invalid nonce size")
+
+		env.Enc = envelope.EncXC20P
+		_, err = s.DecryptObject(marshal(t, env), nil)
+		require.Error(t, err)
+
+		env.Enc = "A128CBC-HS256"
+		_, err = s.DecryptObject(marshal(t, env), nil)
+		require.EqualError(t, err, "decrypt object: unsupported content encryption algorithm 'A128CBC-HS256'")
+
+		env.Version = 2
+		_, err = s.DecryptObject(marshal(t, env), nil)
+		require.EqualError(t, err, "decrypt object: parse envelope: unsupported version 2")
+
+		_, err = s.DecryptObject([]byte("{"), nil)
+		require.ErrorContains(t, err, "decrypt object: parse envelope")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := envelope.New(km, cr).EncryptObject("unknown", plaintext, nil)
+		require.ErrorContains(t, err, "encrypt object: get KEK")
+
+		_, err = envelope.New(km, cr, envelope.WithContentEncryption("A128CBC-HS256")).EncryptObject(kekID, plaintext, nil)
+		require.EqualError(t, err, "encrypt object: unsupported content encryption algorithm 'A128CBC-HS256'")
+
+		aeadID, _, err := km.Create(kmsapi.AES256GCMType)
+		require.NoError(t, err)
+
+		_, err = envelope.New(km, cr).EncryptObject(aeadID, plaintext, nil)
+		require.ErrorContains(t, err, "encrypt object: wrap DEK")
+
+		_, err = envelope.New(km, &noKEKWrapper{cr}).EncryptObject(kekID, plaintext, nil)
+		require.EqualError(t, err, "encrypt object: crypto doesn't support key wrapping with a KEK")
+
+		serialized, err := envelope.New(km, cr).EncryptObject(kekID, plaintext, nil)
+		require.NoError(t, err)
+
+		env, err := envelope.Parse(serialized)
+		require.NoError(t, err)
+
+		env.KID = "unknown"
+		_, err = envelope.New(km, cr).DecryptObject(marshal(t, env), nil)
+		require.ErrorContains(t, err, "decrypt object: get KEK")
+
+		env.KID = aeadID
+		_, err = envelope.New(km, cr).DecryptObject(marshal(t, env), nil)
+		require.ErrorContains(t, err, "decrypt object: unwrap DEK")
+	})
+}
+
+// noKEKWrapper hides the KEKWrapper implementation of a Crypto.
+type noKEKWrapper struct {
+	cryptoapi.Crypto
+}
+
+func marshal(t *testing.T, env *envelope.Envelope) []byte {
+	t.Helper()
+
+	b, err := json.Marshal(env)
+	require.NoError(t, err)
+
+	return b
+}
@@ -0,0 +1,351 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package fssig provides a forward-secure (key evolving) signature scheme.
+//
+// The lifetime of a key is split in a fixed number of periods. Each period has its own Ed25519 key pair derived from a
+// one-way seed chain: moving to the next period with UpdatePeriod() replaces the seed with its hash, so once updated,
+// the private keys of past periods can't be recovered, even if the current private key leaks. The public key stays
+// the same for all periods: it is the root of a Merkle tree built over the period public keys. Signatures are stamped
+// with the period they were created in and carry the period public key with its Merkle authentication path.
+//
+// This is meant for signing append-only data such as audit logs where entries signed before a key compromise must
+// remain trustworthy.
+package fssig
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+const (
+	// MaxPeriods is the maximum number of periods supported by a key.
+	MaxPeriods = 1 << 20
+
+	// PublicKeySize is the size in bytes of a marshalled public key.
+	PublicKeySize = periodSize + sha256.Size
+
+	seedSize   = 32
+	periodSize = 4
+
+	leafPrefix byte = 0x00
+	nodePrefix byte = 0x01
+)
+
+var (
+	keySeedInfo    = []byte("fssig key seed")
+	updateSeedInfo = []byte("fssig update seed")
+	signInfo       = []byte("fssig signature")
+)
+
+var (
+	// ErrKeyExpired is returned by UpdatePeriod and Sign when all periods of a private key have been used.
+	ErrKeyExpired = errors.New("fssig: private key has no periods left")
+	// ErrInvalidSignature is returned when a signature fails verification.
+	ErrInvalidSignature = errors.New("fssig: invalid signature")
+)
+
+// PublicKey is a forward-secure public key. It is valid for all periods of its private key.
+type PublicKey struct {
+	root    [sha256.Size]byte
+	periods uint32
+}
+
+// PrivateKey is a forward-secure private key. It is stateful: UpdatePeriod() must be called at the end of every period
+// and the updated key persisted (see MarshalBinary) in place of the previous one.
+type PrivateKey struct {
+	period uint32
+	seed   []byte
+	// leaves are the hashes of all the period public keys, they are public and needed to build authentication paths.
+	leaves [][]byte
+}
+
+// GenerateKey creates a new forward-secure key pair valid for the given number of periods using entropy from rand.
+func GenerateKey(periods uint32, rand io.Reader) (*PrivateKey, error) {
+	if periods == 0 || periods > MaxPeriods {
+		return nil, fmt.Errorf("fssig: periods must be between 1 and %d", MaxPeriods)
+	}
+
+	seed := make([]byte, seedSize)
+
+	if _, err := io.ReadFull(rand, seed); err != nil {
+		return nil, fmt.Errorf("fssig: failed to read seed: %w", err)
+	}
+
+	leaves := make([][]byte, periods)
+	s := append([]byte{}, seed...)
+
+	for i := uint32(0); i < periods; i++ {
+		leaves[i] = leafHash(i, periodKey(s).Public().(ed25519.PublicKey))
+
+		next := nextSeed(s)
+		zeroize(s)
+		s = next
+	}
+
+	zeroize(s)
+
+	return &PrivateKey{seed: seed, leaves: leaves}, nil
+}
+
+// Period returns the current period of the private key.
+func (k *PrivateKey) Period() uint32 {
+	return k.period
+}
+
+// Periods returns the total number of periods of the key.
+func (k *PrivateKey) Periods() uint32 {
+	return uint32(len(k.leaves))
+}
+
+// Public returns the public key of k.
+func (k *PrivateKey) Public() *PublicKey {
+	return &PublicKey{
+		root:    merkleRoot(k.leaves),
+		periods: k.Periods(),
+	}
+}
+
+// UpdatePeriod moves k to the next period. The key material of the current period is erased and can't be recovered.
+// Once the last period has been used, the seed is erased and ErrKeyExpired is returned.
+func (k *PrivateKey) UpdatePeriod() error {
+	if k.seed == nil {
+		return ErrKeyExpired
+	}
+
+	if k.period+1 >= k.Periods() {
+		zeroize(k.seed)
+		k.seed = nil
+
+		return ErrKeyExpired
+	}
+
+	next := nextSeed(k.seed)
+	zeroize(k.seed)
+
+	k.seed = next
+	k.period++
+
+	return nil
+}
+
+// Sign signs msg with the key of the current period. The signature is stamped with the current period.
+func (k *PrivateKey) Sign(msg []byte) ([]byte, error) {
+	if k.seed == nil {
+		return nil, ErrKeyExpired
+	}
+
+	priv := periodKey(k.seed)
+	defer zeroize(priv)
+
+	path := authPath(k.leaves, k.period)
+
+	sig := make([]byte, 0, periodSize+ed25519.PublicKeySize+ed25519.SignatureSize+len(path)*sha256.Size)
+	sig = binary.BigEndian.AppendUint32(sig, k.period)
+	sig = append(sig, priv.Public().(ed25519.PublicKey)...)
+	sig = append(sig, ed25519.Sign(priv, signedMessage(k.period, msg))...)
+
+	for _, node := range path {
+		sig = append(sig, node...)
+	}
+
+	return sig, nil
+}
+
+// Verify checks sig is a valid signature of msg for pub. It returns the period in which the signature was created.
+func (pub *PublicKey) Verify(msg, sig []byte) (uint32, error) {
+	depth := treeDepth(pub.periods)
+
+	if len(sig) != periodSize+ed25519.PublicKeySize+ed25519.SignatureSize+depth*sha256.Size {
+		return 0, ErrInvalidSignature
+	}
+
+	period := binary.BigEndian.Uint32(sig)
+	if period >= pub.periods {
+		return 0, ErrInvalidSignature
+	}
+
+	rest := sig[periodSize:]
+	periodPub := ed25519.PublicKey(rest[:ed25519.PublicKeySize])
+	rest = rest[ed25519.PublicKeySize:]
+
+	if !ed25519.Verify(periodPub, signedMessage(period, msg), rest[:ed25519.SignatureSize]) {
+		return 0, ErrInvalidSignature
+	}
+
+	rest = rest[ed25519.SignatureSize:]
+	node := leafHash(period, periodPub)
+
+	for i, idx := 0, period; i < depth; i, idx = i+1, idx>>1 {
+		sibling := rest[i*sha256.Size : (i+1)*sha256.Size]
+
+		if idx&1 == 0 {
+			node = nodeHash(node, sibling)
+		} else {
+			node = nodeHash(sibling, node)
+		}
+	}
+
+	if subtle.ConstantTimeCompare(node, pub.root[:]) != 1 {
+		return 0, ErrInvalidSignature
+	}
+
+	return period, nil
+}
+
+// Periods returns the number of periods the public key is valid for.
+func (pub *PublicKey) Periods() uint32 {
+	return pub.periods
+}
+
+// Bytes returns the marshalled public key: the period count (big endian uint32) followed by the Merkle tree root.
+func (pub *PublicKey) Bytes() []byte {
+	b := binary.BigEndian.AppendUint32(make([]byte, 0, PublicKeySize), pub.periods)
+
+	return append(b, pub.root[:]...)
+}
+
+// Equal reports whether pub and other are the same public key.
+func (pub *PublicKey) Equal(other *PublicKey) bool {
+	return other != nil && pub.periods == other.periods && pub.root == other.root
+}
+
+// ParsePublicKey parses a public key marshalled with PublicKey.Bytes().
+func ParsePublicKey(b []byte) (*PublicKey, error) {
+	if len(b) != PublicKeySize {
+		return nil, fmt.Errorf("fssig: invalid public key size %d", len(b))
+	}
+
+	pub := &PublicKey{periods: binary.BigEndian.Uint32(b)}
+	if pub.periods == 0 || pub.periods > MaxPeriods {
+		return nil, errors.New("fssig: invalid public key periods")
+	}
+
+	copy(pub.root[:], b[periodSize:])
+
+	return pub, nil
+}
+
+// SignaturePeriod returns the period a signature is stamped with, without verifying it.
+func SignaturePeriod(sig []byte) (uint32, error) {
+	if len(sig) < periodSize {
+		return 0, ErrInvalidSignature
+	}
+
+	return binary.BigEndian.Uint32(sig), nil
+}
+
+func periodKey(seed []byte) ed25519.PrivateKey {
+	keySeed := deriveSeed(keySeedInfo, seed)
+	defer zeroize(keySeed)
+
+	return ed25519.NewKeyFromSeed(keySeed)
+}
+
+func nextSeed(seed []byte) []byte {
+	return deriveSeed(updateSeedInfo, seed)
+}
+
+func deriveSeed(info, seed []byte) []byte {
+	h := sha256.New()
+	h.Write(info)
+	h.Write(seed)
+
+	return h.Sum(nil)
+}
+
+func signedMessage(period uint32, msg []byte) []byte {
+	m := make([]byte, 0, len(signInfo)+periodSize+len(msg))
+	m = append(m, signInfo...)
+	m = binary.BigEndian.AppendUint32(m, period)
+
+	return append(m, msg...)
+}
+
+func leafHash(period uint32, pub ed25519.PublicKey) []byte {
+	h := sha256.New()
+	h.Write([]byte{leafPrefix})
+	_ = binary.Write(h, binary.BigEndian, period) //nolint:errcheck // hash writes never fail
+	h.Write(pub)
+
+	return h.Sum(nil)
+}
+
+func nodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+
+	return h.Sum(nil)
+}
+
+// treeDepth returns the depth of a Merkle tree with n leaves, leaves are padded to the next power of 2.
+func treeDepth(n uint32) int {
+	if n <= 1 {
+		return 0
+	}
+
+	return bits.Len32(n - 1)
+}
+
+// levels returns all the levels of the Merkle tree built over leaves. Missing leaves are all zero hashes.
+func levels(leaves [][]byte) [][][]byte {
+	depth := treeDepth(uint32(len(leaves)))
+	lvl := make([][]byte, 1<<depth)
+	copy(lvl, leaves)
+
+	for i := len(leaves); i < len(lvl); i++ {
+		lvl[i] = make([]byte, sha256.Size)
+	}
+
+	tree := [][][]byte{lvl}
+
+	for len(lvl) > 1 {
+		next := make([][]byte, len(lvl)/2)
+
+		for i := range next {
+			next[i] = nodeHash(lvl[2*i], lvl[2*i+1])
+		}
+
+		tree = append(tree, next)
+		lvl = next
+	}
+
+	return tree
+}
+
+func merkleRoot(leaves [][]byte) [sha256.Size]byte {
+	var root [sha256.Size]byte
+
+	tree := levels(leaves)
+	copy(root[:], tree[len(tree)-1][0])
+
+	return root
+}
+
+func authPath(leaves [][]byte, period uint32) [][]byte {
+	tree := levels(leaves)
+	path := make([][]byte, 0, len(tree)-1)
+
+	for i, idx := 0, period; i < len(tree)-1; i, idx = i+1, idx>>1 {
+		path = append(path, tree[i][idx^1])
+	}
+
+	return path
+}
+
+func zeroize(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fssig
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerify(t *testing.T) {
+	for _, periods := range []uint32{1, 2, 5, 8, 33} {
+		priv, err := GenerateKey(periods, rand.Reader)
+		require.NoError(t, err)
+		require.Equal(t, periods, priv.Periods())
+
+		pub := priv.Public()
+		msg := []byte("audit log entry")
+
+		var sigs [][]byte
+
+		for p := uint32(0); p < periods; p++ {
+			require.Equal(t, p, priv.Period())
+			require.True(t, pub.Equal(priv.Public()), "public key must not change with periods")
+
+			sig, err := priv.Sign(msg)
+			require.NoError(t, err)
+
+			sigPeriod, err := SignaturePeriod(sig)
+			require.NoError(t, err)
+			require.Equal(t, p, sigPeriod)
+
+			sigs = append(sigs, sig)
+
+			if p+1 < periods {
+				require.NoError(t, priv.UpdatePeriod())
+			}
+		}
+
+		// signatures from all past periods still verify.
+		for p, sig := range sigs {
+			period, err := pub.Verify(msg, sig)
+			require.NoError(t, err)
+			require.Equal(t, uint32(p), period)
+
+			_, err = pub.Verify([]byte("tampered"), sig)
+			require.ErrorIs(t, err, ErrInvalidSignature)
+		}
+
+		require.ErrorIs(t, priv.UpdatePeriod(), ErrKeyExpired)
+
+		_, err = priv.Sign(msg)
+		require.ErrorIs(t, err, ErrKeyExpired)
+	}
+}
+
+func TestForwardSecurity(t *testing.T) {
+	priv, err := GenerateKey(4, rand.Reader)
+	require.NoError(t, err)
+
+	pub := priv.Public()
+	oldSeed := append([]byte{}, priv.seed...)
+
+	require.NoError(t, priv.UpdatePeriod())
+	require.NotEqual(t, oldSeed, priv.seed)
+
+	// a stolen key from period 1 can't sign for period 0: forging the period stamp breaks the Merkle path.
+	sig, err := priv.Sign([]byte("backdated entry"))
+	require.NoError(t, err)
+
+	sig[3] = 0
+
+	_, err = pub.Verify([]byte("backdated entry"), sig)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestInvalidSignatures(t *testing.T) {
+	priv, err := GenerateKey(4, rand.Reader)
+	require.NoError(t, err)
+
+	other, err := GenerateKey(4, rand.Reader)
+	require.NoError(t, err)
+
+	msg := []byte("msg")
+
+	sig, err := priv.Sign(msg)
+	require.NoError(t, err)
+
+	_, err = other.Public().Verify(msg, sig)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = priv.Public().Verify(msg, sig[:len(sig)-1])
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	badPeriod := append([]byte{}, sig...)
+	badPeriod[0] = 0xff
+
+	_, err = priv.Public().Verify(msg, badPeriod)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	badPath := append([]byte{}, sig...)
+	badPath[len(badPath)-1] ^= 1
+
+	_, err = priv.Public().Verify(msg, badPath)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = SignaturePeriod(nil)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestGenerateKeyErrors(t *testing.T) {
+	_, err := GenerateKey(0, rand.Reader)
+	require.EqualError(t, err, "fssig: periods must be between 1 and 1048576")
+
+	_, err = GenerateKey(MaxPeriods+1, rand.Reader)
+	require.Error(t, err)
+
+	_, err = GenerateKey(2, &failingReader{})
+	require.EqualError(t, err, "fssig: failed to read seed: read failed")
+}
+
+func TestMarshal(t *testing.T) {
+	priv, err := GenerateKey(6, rand.Reader)
+	require.NoError(t, err)
+
+	require.NoError(t, priv.UpdatePeriod())
+	require.NoError(t, priv.UpdatePeriod())
+
+	b, err := priv.MarshalBinary()
+	require.NoError(t, err)
+
+	restored := &PrivateKey{}
+	require.NoError(t, restored.UnmarshalBinary(b))
+	require.Equal(t, priv, restored)
+
+	sig, err := restored.Sign([]byte("msg"))
+	require.NoError(t, err)
+
+	pub, err := ParsePublicKey(priv.Public().Bytes())
+	require.NoError(t, err)
+	require.True(t, pub.Equal(priv.Public()))
+	require.Equal(t, uint32(6), pub.Periods())
+
+	period, err := pub.Verify([]byte("msg"), sig)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), period)
+
+	t.Run("failures", func(t *testing.T) {
+		require.Error(t, (&PrivateKey{}).UnmarshalBinary(nil))
+		require.Error(t, (&PrivateKey{}).UnmarshalBinary(b[:len(b)-1]))
+
+		badPeriod := bytes.Clone(b)
+		badPeriod[4] = 6
+
+		require.EqualError(t, (&PrivateKey{}).UnmarshalBinary(badPeriod), "fssig: invalid private key periods")
+
+		_, err = ParsePublicKey([]byte("short"))
+		require.EqualError(t, err, "fssig: invalid public key size 5")
+
+		_, err = ParsePublicKey(make([]byte, PublicKeySize))
+		require.EqualError(t, err, "fssig: invalid public key periods")
+
+		expired, err := GenerateKey(1, rand.Reader)
+		require.NoError(t, err)
+		require.ErrorIs(t, expired.UpdatePeriod(), ErrKeyExpired)
+
+		_, err = expired.MarshalBinary()
+		require.ErrorIs(t, err, ErrKeyExpired)
+	})
+}
+
+type failingReader struct{}
+
+func (r *failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
@@ -0,0 +1,69 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fssig
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	privateKeyVersion byte = 1
+	privateKeyHeader       = 1 + 2*periodSize + seedSize
+)
+
+// MarshalBinary serializes the private key state (current period, seed and period public key hashes). The result
+// must be stored securely and replaced every time the period is updated, keeping an older copy defeats forward
+// security.
+func (k *PrivateKey) MarshalBinary() ([]byte, error) {
+	if k.seed == nil {
+		return nil, ErrKeyExpired
+	}
+
+	b := make([]byte, 0, privateKeyHeader+len(k.leaves)*sha256.Size)
+	b = append(b, privateKeyVersion)
+	b = binary.BigEndian.AppendUint32(b, k.period)
+	b = binary.BigEndian.AppendUint32(b, k.Periods())
+	b = append(b, k.seed...)
+
+	for _, leaf := range k.leaves {
+		b = append(b, leaf...)
+	}
+
+	return b, nil
+}
+
+// UnmarshalBinary restores a private key serialized with MarshalBinary.
+func (k *PrivateKey) UnmarshalBinary(b []byte) error {
+	if len(b) < privateKeyHeader || b[0] != privateKeyVersion {
+		return errors.New("fssig: invalid private key encoding")
+	}
+
+	period := binary.BigEndian.Uint32(b[1:])
+	periods := binary.BigEndian.Uint32(b[1+periodSize:])
+
+	if periods == 0 || periods > MaxPeriods || period >= periods {
+		return errors.New("fssig: invalid private key periods")
+	}
+
+	if len(b) != privateKeyHeader+int(periods)*sha256.Size {
+		return fmt.Errorf("fssig: invalid private key size %d", len(b))
+	}
+
+	k.period = period
+	k.seed = append([]byte{}, b[1+2*periodSize:privateKeyHeader]...)
+	k.leaves = make([][]byte, periods)
+
+	for i := range k.leaves {
+		off := privateKeyHeader + i*sha256.Size
+		k.leaves[i] = append([]byte{}, b[off:off+sha256.Size]...)
+	}
+
+	return nil
+}
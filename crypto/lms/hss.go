@@ -0,0 +1,232 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package lms implements the Leighton-Micali stateful hash-based signature schemes LMS and HSS (RFC 8554).
+//
+// Hash-based signatures only rely on the security of SHA-256 and are considered safe against quantum computers, but
+// they are stateful: every one-time key (tree leaf) must be used at most once, reusing one allows forgeries. The
+// PrivateKey type only exposes signing through Signer, which persists the updated key state to a kms.Store before
+// releasing any signature.
+package lms
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// MaxLevels is the maximum number of HSS levels (RFC 8554 section 6).
+	MaxLevels = 8
+)
+
+var (
+	// ErrInvalidSignature is returned when a signature fails verification.
+	ErrInvalidSignature = errors.New("lms: invalid signature")
+	// ErrKeyExhausted is returned when all the one-time keys of a private key have been used.
+	ErrKeyExhausted = errors.New("lms: private key exhausted")
+)
+
+// Params are the LMS and LM-OTS parameter sets of an HSS level.
+type Params struct {
+	LMS   LMSType
+	LMOTS LMOTSType
+}
+
+// PrivateKey is an HSS private key (a single level HSS key is an LMS key with an HSS framing).
+type PrivateKey struct {
+	levels []*lmsKey
+	// signedPubs[i] is the signature of levels[i+1]'s public key by levels[i] followed by that public key.
+	signedPubs [][]byte
+}
+
+// GenerateKey creates a new HSS private key with one level per params entry (top level first), using entropy from
+// rand. The key can sign the product of 2^h of all levels messages.
+func GenerateKey(params []Params, rand io.Reader) (*PrivateKey, error) {
+	if len(params) == 0 || len(params) > MaxLevels {
+		return nil, fmt.Errorf("lms: levels must be between 1 and %d", MaxLevels)
+	}
+
+	top, err := newLMSKey(params[0].LMS, params[0].LMOTS, rand)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &PrivateKey{levels: make([]*lmsKey, len(params)), signedPubs: make([][]byte, len(params)-1)}
+	k.levels[0] = top
+
+	for i := 1; i < len(params); i++ {
+		if err = k.newChild(i, params[i], rand); err != nil {
+			return nil, err
+		}
+	}
+
+	return k, nil
+}
+
+// newChild creates a new key at level i, signed by the key at level i-1 whose state is advanced.
+func (k *PrivateKey) newChild(i int, params Params, rand io.Reader) error {
+	child, err := newLMSKey(params.LMS, params.LMOTS, rand)
+	if err != nil {
+		return err
+	}
+
+	parent := k.levels[i-1]
+	pub := child.publicKey()
+
+	sig, err := parent.signAt(parent.q, pub, rand)
+	if err != nil {
+		return err
+	}
+
+	parent.q++
+
+	k.levels[i] = child
+	k.signedPubs[i-1] = append(sig, pub...)
+
+	return nil
+}
+
+// Public returns the HSS public key: u32str(L) || the top level LMS public key.
+func (k *PrivateKey) Public() []byte {
+	return append(u32str(uint32(len(k.levels))), k.levels[0].publicKey()...)
+}
+
+// Remaining returns the number of signatures the key can still produce.
+func (k *PrivateKey) Remaining() uint64 {
+	remaining := uint64(0)
+	capacity := uint64(1)
+
+	for i := len(k.levels) - 1; i >= 0; i-- {
+		l := k.levels[i]
+		left := uint64(l.leaves() - min(l.q, l.leaves()))
+
+		if i == len(k.levels)-1 {
+			remaining = left
+		} else {
+			// every remaining leaf of this level signs a new full subtree.
+			remaining += left * capacity
+		}
+
+		capacity *= uint64(l.leaves())
+	}
+
+	return remaining
+}
+
+// reserve advances the key state past the next one-time key and returns a copy of the state to sign with. The
+// returned key must only be used to sign once, after the advanced state k has been persisted.
+func (k *PrivateKey) reserve(rand io.Reader) (*PrivateKey, error) {
+	bottom := len(k.levels) - 1
+	if k.levels[bottom].exhausted() {
+		return nil, ErrKeyExhausted
+	}
+
+	reserved := &PrivateKey{
+		levels:     append([]*lmsKey{}, k.levels...),
+		signedPubs: append([][]byte{}, k.signedPubs...),
+	}
+
+	// levels are shallow copied: take a private copy of the bottom key state for the reserved key.
+	b := *k.levels[bottom]
+	reserved.levels[bottom] = &b
+
+	k.levels[bottom] = &lmsKey{}
+	*k.levels[bottom] = b
+	k.levels[bottom].q++
+
+	if k.levels[bottom].exhausted() {
+		if err := k.refill(rand); err != nil {
+			return nil, err
+		}
+	}
+
+	return reserved, nil
+}
+
+// refill replaces the exhausted lower levels with new keys signed by the deepest level that has leaves left. If no
+// level has leaves left, the key is left exhausted.
+func (k *PrivateKey) refill(rand io.Reader) error {
+	d := len(k.levels) - 2
+	for d >= 0 && k.levels[d].exhausted() {
+		d--
+	}
+
+	if d < 0 {
+		return nil
+	}
+
+	// copy the signing parent before it is advanced, the reserved key still references the previous value.
+	parent := *k.levels[d]
+	k.levels[d] = &parent
+
+	for i := d + 1; i < len(k.levels); i++ {
+		params := Params{LMS: k.levels[i].typ, LMOTS: k.levels[i].otsTyp}
+
+		if err := k.newChild(i, params, rand); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sign creates the HSS signature of msg with the current state of k, without updating it (RFC 8554 section 6.2).
+func (k *PrivateKey) sign(msg []byte, rand io.Reader) ([]byte, error) {
+	bottom := k.levels[len(k.levels)-1]
+
+	sig, err := bottom.signAt(bottom.q, msg, rand)
+	if err != nil {
+		return nil, err
+	}
+
+	out := u32str(uint32(len(k.levels) - 1))
+
+	for _, sp := range k.signedPubs {
+		out = append(out, sp...)
+	}
+
+	return append(out, sig...), nil
+}
+
+// Verify checks sig is a valid HSS signature of msg for the HSS public key pub (RFC 8554 section 6.3).
+func Verify(pub, msg, sig []byte) error {
+	if len(pub) != 4+lmsPublicKeySize || len(sig) < 4 {
+		return ErrInvalidSignature
+	}
+
+	levels := binary.BigEndian.Uint32(pub)
+	nspk := binary.BigEndian.Uint32(sig)
+
+	if levels == 0 || levels > MaxLevels || nspk+1 != levels {
+		return ErrInvalidSignature
+	}
+
+	key := pub[4:]
+	rest := sig[4:]
+
+	for i := uint32(0); i < nspk; i++ {
+		size, err := lmsSignatureSize(rest)
+		if err != nil || len(rest) < size+lmsPublicKeySize {
+			return ErrInvalidSignature
+		}
+
+		childPub := rest[size : size+lmsPublicKeySize]
+
+		if err = lmsVerify(key, childPub, rest[:size]); err != nil {
+			return ErrInvalidSignature
+		}
+
+		key, rest = childPub, rest[size+lmsPublicKeySize:]
+	}
+
+	if err := lmsVerify(key, msg, rest); err != nil {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lms
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// LMOTSType is an LM-OTS (one-time signature) parameter set (RFC 8554 section 4.1).
+type LMOTSType uint32
+
+// LM-OTS parameter sets, all use SHA-256 with n = 32.
+const (
+	LMOTSSHA256N32W1 LMOTSType = 1
+	LMOTSSHA256N32W2 LMOTSType = 2
+	LMOTSSHA256N32W4 LMOTSType = 3
+	LMOTSSHA256N32W8 LMOTSType = 4
+)
+
+const (
+	n      = sha256.Size
+	idSize = 16
+
+	dPBLC uint16 = 0x8080
+	dMESG uint16 = 0x8181
+	dLEAF uint16 = 0x8282
+	dINTR uint16 = 0x8383
+
+	// privElemMarker is used in the pseudorandom private key generation of RFC 8554 Appendix A.
+	privElemMarker = 0xff
+)
+
+type lmotsParams struct {
+	w  int
+	p  int
+	ls int
+}
+
+var lmotsParamSets = map[LMOTSType]lmotsParams{ //nolint:gochecknoglobals
+	LMOTSSHA256N32W1: {w: 1, p: 265, ls: 7},
+	LMOTSSHA256N32W2: {w: 2, p: 133, ls: 6},
+	LMOTSSHA256N32W4: {w: 4, p: 67, ls: 4},
+	LMOTSSHA256N32W8: {w: 8, p: 34, ls: 0},
+}
+
+func (t LMOTSType) params() (lmotsParams, error) {
+	p, ok := lmotsParamSets[t]
+	if !ok {
+		return lmotsParams{}, fmt.Errorf("lms: unsupported LM-OTS type %d", t)
+	}
+
+	return p, nil
+}
+
+// sigSize returns the LM-OTS signature size: type || C || y[0] .. y[p-1].
+func (p lmotsParams) sigSize() int {
+	return 4 + n + p.p*n
+}
+
+// coef returns the i-th w bits of s (RFC 8554 section 3.1.3).
+func coef(s []byte, i, w int) int {
+	return int(s[i*w/8]>>(8-(w*(i%(8/w))+w))) & (1<<w - 1)
+}
+
+// checksum is the LM-OTS checksum of q (RFC 8554 section 4.4).
+func checksum(q []byte, p lmotsParams) []byte {
+	sum := 0
+	maxCoef := 1<<p.w - 1
+
+	for i := 0; i < n*8/p.w; i++ {
+		sum += maxCoef - coef(q, i, p.w)
+	}
+
+	return binary.BigEndian.AppendUint16(nil, uint16(sum<<p.ls))
+}
+
+func hashOf(parts ...[]byte) []byte {
+	h := sha256.New()
+
+	for _, part := range parts {
+		h.Write(part)
+	}
+
+	return h.Sum(nil)
+}
+
+func u32str(v uint32) []byte {
+	return binary.BigEndian.AppendUint32(nil, v)
+}
+
+func u16str(v uint16) []byte {
+	return binary.BigEndian.AppendUint16(nil, v)
+}
+
+// otsPrivElem derives the i-th element of the LM-OTS private key q from seed (RFC 8554 Appendix A).
+func otsPrivElem(id []byte, q uint32, i int, seed []byte) []byte {
+	return hashOf(id, u32str(q), u16str(uint16(i)), []byte{privElemMarker}, seed)
+}
+
+// chain iterates the LM-OTS hash chain i of key q from step start (included) to end (excluded).
+func chain(id []byte, q uint32, i, start, end int, tmp []byte) []byte {
+	qStr, iStr := u32str(q), u16str(uint16(i))
+
+	for j := start; j < end; j++ {
+		tmp = hashOf(id, qStr, iStr, []byte{byte(j)}, tmp)
+	}
+
+	return tmp
+}
+
+// otsPublicKey computes the LM-OTS public key K of key q (RFC 8554 section 4.3).
+func otsPublicKey(typ LMOTSType, id []byte, q uint32, seed []byte) ([]byte, error) {
+	p, err := typ.params()
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	h.Write(id)
+	h.Write(u32str(q))
+	h.Write(u16str(dPBLC))
+
+	for i := 0; i < p.p; i++ {
+		h.Write(chain(id, q, i, 0, 1<<p.w-1, otsPrivElem(id, q, i, seed)))
+	}
+
+	return h.Sum(nil), nil
+}
+
+// otsSign signs msg with LM-OTS key q (RFC 8554 section 4.5).
+func otsSign(typ LMOTSType, id []byte, q uint32, seed, msg []byte, rand io.Reader) ([]byte, error) {
+	p, err := typ.params()
+	if err != nil {
+		return nil, err
+	}
+
+	c := make([]byte, n)
+
+	if _, err = io.ReadFull(rand, c); err != nil {
+		return nil, fmt.Errorf("lms: failed to read randomizer: %w", err)
+	}
+
+	qHash := hashOf(id, u32str(q), u16str(dMESG), c, msg)
+	qHash = append(qHash, checksum(qHash, p)...)
+
+	sig := make([]byte, 0, p.sigSize())
+	sig = append(sig, u32str(uint32(typ))...)
+	sig = append(sig, c...)
+
+	for i := 0; i < p.p; i++ {
+		sig = append(sig, chain(id, q, i, 0, coef(qHash, i, p.w), otsPrivElem(id, q, i, seed))...)
+	}
+
+	return sig, nil
+}
+
+// otsCandidateKey computes the LM-OTS public key candidate of sig for msg (RFC 8554 section 4.6).
+func otsCandidateKey(wantType LMOTSType, id []byte, q uint32, msg, sig []byte) ([]byte, error) {
+	if len(sig) < 4 || LMOTSType(binary.BigEndian.Uint32(sig)) != wantType {
+		return nil, errors.New("lms: LM-OTS signature type mismatch")
+	}
+
+	p, err := wantType.params()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sig) != p.sigSize() {
+		return nil, errors.New("lms: invalid LM-OTS signature size")
+	}
+
+	c := sig[4 : 4+n]
+	y := sig[4+n:]
+
+	qHash := hashOf(id, u32str(q), u16str(dMESG), c, msg)
+	qHash = append(qHash, checksum(qHash, p)...)
+
+	h := sha256.New()
+	h.Write(id)
+	h.Write(u32str(q))
+	h.Write(u16str(dPBLC))
+
+	for i := 0; i < p.p; i++ {
+		h.Write(chain(id, q, i, coef(qHash, i, p.w), 1<<p.w-1, y[i*n:(i+1)*n]))
+	}
+
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,240 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lms
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// LMSType is an LMS (Merkle tree) parameter set (RFC 8554 section 5.1).
+type LMSType uint32
+
+// LMS parameter sets, all use SHA-256 with m = 32. A tree of height h can sign 2^h messages.
+const (
+	LMSSHA256M32H5  LMSType = 5
+	LMSSHA256M32H10 LMSType = 6
+	LMSSHA256M32H15 LMSType = 7
+	LMSSHA256M32H20 LMSType = 8
+	LMSSHA256M32H25 LMSType = 9
+)
+
+// lmsPublicKeySize is the size of an LMS public key: type || otstype || I || T[1].
+const lmsPublicKeySize = 4 + 4 + idSize + n
+
+var lmsHeights = map[LMSType]int{ //nolint:gochecknoglobals
+	LMSSHA256M32H5:  5,
+	LMSSHA256M32H10: 10,
+	LMSSHA256M32H15: 15,
+	LMSSHA256M32H20: 20,
+	LMSSHA256M32H25: 25,
+}
+
+func (t LMSType) height() (int, error) {
+	h, ok := lmsHeights[t]
+	if !ok {
+		return 0, fmt.Errorf("lms: unsupported LMS type %d", t)
+	}
+
+	return h, nil
+}
+
+// lmsKey is a single LMS private key with its Merkle tree (kept in memory only).
+type lmsKey struct {
+	typ    LMSType
+	otsTyp LMOTSType
+	id     []byte
+	seed   []byte
+	q      uint32
+	h      int
+	// tree holds the Merkle tree nodes T[1] .. T[2^(h+1)-1] (T[0] is unused).
+	tree [][]byte
+}
+
+func newLMSKey(typ LMSType, otsTyp LMOTSType, rand io.Reader) (*lmsKey, error) {
+	id := make([]byte, idSize)
+	seed := make([]byte, n)
+
+	if _, err := io.ReadFull(rand, id); err != nil {
+		return nil, fmt.Errorf("lms: failed to read key identifier: %w", err)
+	}
+
+	if _, err := io.ReadFull(rand, seed); err != nil {
+		return nil, fmt.Errorf("lms: failed to read seed: %w", err)
+	}
+
+	k := &lmsKey{typ: typ, otsTyp: otsTyp, id: id, seed: seed}
+
+	if err := k.buildTree(); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// buildTree computes the Merkle tree of the key (RFC 8554 section 5.3).
+func (k *lmsKey) buildTree() error {
+	h, err := k.typ.height()
+	if err != nil {
+		return err
+	}
+
+	if _, err = k.otsTyp.params(); err != nil {
+		return err
+	}
+
+	leaves := uint32(1) << h
+	k.h = h
+	k.tree = make([][]byte, 2*leaves)
+
+	for q := uint32(0); q < leaves; q++ {
+		otsPub, e := otsPublicKey(k.otsTyp, k.id, q, k.seed)
+		if e != nil {
+			return e
+		}
+
+		r := leaves + q
+		k.tree[r] = hashOf(k.id, u32str(r), u16str(dLEAF), otsPub)
+	}
+
+	for r := leaves - 1; r >= 1; r-- {
+		k.tree[r] = hashOf(k.id, u32str(r), u16str(dINTR), k.tree[2*r], k.tree[2*r+1])
+	}
+
+	return nil
+}
+
+func (k *lmsKey) leaves() uint32 {
+	return uint32(1) << k.h
+}
+
+func (k *lmsKey) exhausted() bool {
+	return k.q >= k.leaves()
+}
+
+func (k *lmsKey) publicKey() []byte {
+	pub := make([]byte, 0, lmsPublicKeySize)
+	pub = append(pub, u32str(uint32(k.typ))...)
+	pub = append(pub, u32str(uint32(k.otsTyp))...)
+	pub = append(pub, k.id...)
+
+	return append(pub, k.tree[1]...)
+}
+
+// signAt signs msg with the leaf q (RFC 8554 section 5.4.1). It doesn't update the key state.
+func (k *lmsKey) signAt(q uint32, msg []byte, rand io.Reader) ([]byte, error) {
+	if q >= k.leaves() {
+		return nil, errors.New("lms: LMS key exhausted")
+	}
+
+	otsSig, err := otsSign(k.otsTyp, k.id, q, k.seed, msg, rand)
+	if err != nil {
+		return nil, err
+	}
+
+	sig := make([]byte, 0, 4+len(otsSig)+4+k.h*n)
+	sig = append(sig, u32str(q)...)
+	sig = append(sig, otsSig...)
+	sig = append(sig, u32str(uint32(k.typ))...)
+
+	node := k.leaves() + q
+
+	for i := 0; i < k.h; i++ {
+		sig = append(sig, k.tree[(node>>i)^1]...)
+	}
+
+	return sig, nil
+}
+
+// lmsSignatureSize returns the size of the LMS signature at the start of sig, read from its type fields.
+func lmsSignatureSize(sig []byte) (int, error) {
+	if len(sig) < 8 { //nolint:gomnd // q || otstype
+		return 0, errors.New("lms: invalid LMS signature size")
+	}
+
+	p, err := LMOTSType(binary.BigEndian.Uint32(sig[4:])).params()
+	if err != nil {
+		return 0, err
+	}
+
+	typOffset := 4 + p.sigSize()
+	if len(sig) < typOffset+4 {
+		return 0, errors.New("lms: invalid LMS signature size")
+	}
+
+	h, err := LMSType(binary.BigEndian.Uint32(sig[typOffset:])).height()
+	if err != nil {
+		return 0, err
+	}
+
+	return typOffset + 4 + h*n, nil
+}
+
+// lmsVerify verifies the LMS signature sig of msg for the LMS public key pub (RFC 8554 section 5.4.2).
+func lmsVerify(pub, msg, sig []byte) error {
+	if len(pub) != lmsPublicKeySize {
+		return errors.New("lms: invalid LMS public key size")
+	}
+
+	typ := LMSType(binary.BigEndian.Uint32(pub))
+	otsTyp := LMOTSType(binary.BigEndian.Uint32(pub[4:]))
+	id := pub[8 : 8+idSize]
+	root := pub[8+idSize:]
+
+	h, err := typ.height()
+	if err != nil {
+		return err
+	}
+
+	size, err := lmsSignatureSize(sig)
+	if err != nil {
+		return err
+	}
+
+	if size != len(sig) {
+		return errors.New("lms: invalid LMS signature size")
+	}
+
+	q := binary.BigEndian.Uint32(sig)
+	otsSigEnd := len(sig) - 4 - h*n
+
+	if LMSType(binary.BigEndian.Uint32(sig[otsSigEnd:])) != typ {
+		return errors.New("lms: LMS signature type mismatch")
+	}
+
+	if q >= uint32(1)<<h {
+		return errors.New("lms: invalid LMS signature leaf")
+	}
+
+	kc, err := otsCandidateKey(otsTyp, id, q, msg, sig[4:otsSigEnd])
+	if err != nil {
+		return err
+	}
+
+	path := sig[otsSigEnd+4:]
+	node := uint32(1)<<h + q
+	tmp := hashOf(id, u32str(node), u16str(dLEAF), kc)
+
+	for i := 0; node > 1; i, node = i+1, node/2 {
+		sibling := path[i*n : (i+1)*n]
+
+		if node&1 == 1 {
+			tmp = hashOf(id, u32str(node/2), u16str(dINTR), sibling, tmp)
+		} else {
+			tmp = hashOf(id, u32str(node/2), u16str(dINTR), tmp, sibling)
+		}
+	}
+
+	if subtle.ConstantTimeCompare(tmp, root) != 1 {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lms
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoef(t *testing.T) {
+	// examples from RFC 8554 section 3.1.3.
+	s := []byte{0x12, 0x34}
+
+	require.Equal(t, 0, coef(s, 7, 1))
+	require.Equal(t, 1, coef(s, 0, 4))
+	require.Equal(t, 4, coef(s, 3, 4))
+	require.Equal(t, 0x12, coef(s, 0, 8))
+}
+
+func TestSignVerify(t *testing.T) {
+	msg := []byte("firmware image v1.2.3")
+
+	for _, ots := range []LMOTSType{LMOTSSHA256N32W1, LMOTSSHA256N32W2, LMOTSSHA256N32W4, LMOTSSHA256N32W8} {
+		priv, err := GenerateKey([]Params{{LMS: LMSSHA256M32H5, LMOTS: ots}}, rand.Reader)
+		require.NoError(t, err)
+		require.Equal(t, uint64(32), priv.Remaining())
+
+		reserved, err := priv.reserve(rand.Reader)
+		require.NoError(t, err)
+
+		sig, err := reserved.sign(msg, rand.Reader)
+		require.NoError(t, err)
+		require.Equal(t, uint64(31), priv.Remaining())
+
+		require.NoError(t, Verify(priv.Public(), msg, sig))
+		require.ErrorIs(t, Verify(priv.Public(), []byte("tampered"), sig), ErrInvalidSignature)
+
+		tampered := append([]byte{}, sig...)
+		tampered[len(tampered)-1] ^= 1
+		require.ErrorIs(t, Verify(priv.Public(), msg, tampered), ErrInvalidSignature)
+
+		require.ErrorIs(t, Verify(priv.Public(), msg, sig[:len(sig)-1]), ErrInvalidSignature)
+	}
+}
+
+func TestHSSRefill(t *testing.T) {
+	params := []Params{
+		{LMS: LMSSHA256M32H5, LMOTS: LMOTSSHA256N32W4},
+		{LMS: LMSSHA256M32H5, LMOTS: LMOTSSHA256N32W4},
+	}
+
+	priv, err := GenerateKey(params, rand.Reader)
+	require.NoError(t, err)
+	// the top level signed the first bottom level key.
+	require.Equal(t, uint64(31*32+32), priv.Remaining())
+
+	pub := priv.Public()
+	seen := map[string]bool{}
+
+	// cross the first bottom level key boundary.
+	for i := 0; i < 40; i++ {
+		reserved, err := priv.reserve(rand.Reader)
+		require.NoError(t, err)
+
+		msg := []byte{byte(i)}
+		sig, err := reserved.sign(msg, rand.Reader)
+		require.NoError(t, err)
+		require.NoError(t, Verify(pub, msg, sig))
+		require.Equal(t, pub, priv.Public())
+
+		// (bottom key identifier, leaf) pairs are never reused.
+		bottomSig := sig[len(sig)-(4+4+n+67*n+4+5*n):]
+		leaf := string(reserved.levels[1].id) + string(bottomSig[:4])
+		require.False(t, seen[leaf])
+		seen[leaf] = true
+	}
+
+	require.Equal(t, uint64(31*32+32-40), priv.Remaining())
+}
+
+func TestExhausted(t *testing.T) {
+	priv, err := GenerateKey([]Params{{LMS: LMSSHA256M32H5, LMOTS: LMOTSSHA256N32W8}}, rand.Reader)
+	require.NoError(t, err)
+
+	for i := 0; i < 32; i++ {
+		_, err = priv.reserve(rand.Reader)
+		require.NoError(t, err)
+	}
+
+	require.Zero(t, priv.Remaining())
+
+	_, err = priv.reserve(rand.Reader)
+	require.ErrorIs(t, err, ErrKeyExhausted)
+}
+
+func TestGenerateKeyErrors(t *testing.T) {
+	_, err := GenerateKey(nil, rand.Reader)
+	require.Error(t, err)
+
+	_, err = GenerateKey([]Params{{LMS: 42, LMOTS: LMOTSSHA256N32W8}}, rand.Reader)
+	require.EqualError(t, err, "lms: unsupported LMS type 42")
+
+	_, err = GenerateKey([]Params{{LMS: LMSSHA256M32H5, LMOTS: 42}}, rand.Reader)
+	require.EqualError(t, err, "lms: unsupported LM-OTS type 42")
+}
+
+func TestVerifyLevelMismatch(t *testing.T) {
+	one, err := GenerateKey([]Params{{LMS: LMSSHA256M32H5, LMOTS: LMOTSSHA256N32W8}}, rand.Reader)
+	require.NoError(t, err)
+
+	two, err := GenerateKey([]Params{
+		{LMS: LMSSHA256M32H5, LMOTS: LMOTSSHA256N32W8},
+		{LMS: LMSSHA256M32H5, LMOTS: LMOTSSHA256N32W8},
+	}, rand.Reader)
+	require.NoError(t, err)
+
+	reserved, err := two.reserve(rand.Reader)
+	require.NoError(t, err)
+
+	sig, err := reserved.sign([]byte("msg"), rand.Reader)
+	require.NoError(t, err)
+
+	require.NoError(t, Verify(two.Public(), []byte("msg"), sig))
+	require.ErrorIs(t, Verify(one.Public(), []byte("msg"), sig), ErrInvalidSignature)
+}
+
+func TestMarshalBinary(t *testing.T) {
+	priv, err := GenerateKey([]Params{
+		{LMS: LMSSHA256M32H5, LMOTS: LMOTSSHA256N32W8},
+		{LMS: LMSSHA256M32H5, LMOTS: LMOTSSHA256N32W4},
+	}, rand.Reader)
+	require.NoError(t, err)
+
+	_, err = priv.reserve(rand.Reader)
+	require.NoError(t, err)
+
+	b, err := priv.MarshalBinary()
+	require.NoError(t, err)
+
+	restored := &PrivateKey{}
+	require.NoError(t, restored.UnmarshalBinary(b))
+	require.Equal(t, priv.Public(), restored.Public())
+	require.Equal(t, priv.Remaining(), restored.Remaining())
+
+	reserved, err := restored.reserve(rand.Reader)
+	require.NoError(t, err)
+
+	sig, err := reserved.sign([]byte("msg"), rand.Reader)
+	require.NoError(t, err)
+	require.NoError(t, Verify(priv.Public(), []byte("msg"), sig))
+
+	require.Error(t, restored.UnmarshalBinary(b[:len(b)-1]))
+	require.Error(t, restored.UnmarshalBinary(nil))
+
+	b[0] = 2
+	require.Error(t, restored.UnmarshalBinary(b))
+}
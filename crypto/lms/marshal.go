@@ -0,0 +1,121 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	privateKeyVersion byte = 1
+	// levelStateSize is the serialized size of a level: type || otstype || I || SEED || q.
+	levelStateSize = 4 + 4 + idSize + n + 4
+)
+
+// MarshalBinary serializes the private key state (per level parameters, seeds and next leaf indexes, and the signed
+// lower level public keys). Merkle trees are not serialized, they are recomputed by UnmarshalBinary. The result must be
+// stored securely and replaced every time a signature is created, restoring an older copy reuses one-time keys.
+func (k *PrivateKey) MarshalBinary() ([]byte, error) {
+	if len(k.levels) == 0 {
+		return nil, errors.New("lms: empty private key")
+	}
+
+	b := []byte{privateKeyVersion, byte(len(k.levels))}
+
+	for _, l := range k.levels {
+		b = binary.BigEndian.AppendUint32(b, uint32(l.typ))
+		b = binary.BigEndian.AppendUint32(b, uint32(l.otsTyp))
+		b = append(b, l.id...)
+		b = append(b, l.seed...)
+		b = binary.BigEndian.AppendUint32(b, l.q)
+	}
+
+	for _, sp := range k.signedPubs {
+		b = binary.BigEndian.AppendUint32(b, uint32(len(sp)))
+		b = append(b, sp...)
+	}
+
+	return b, nil
+}
+
+// UnmarshalBinary restores a private key serialized with MarshalBinary. It recomputes the Merkle tree of every level,
+// which is slow for tall trees.
+func (k *PrivateKey) UnmarshalBinary(b []byte) error {
+	if len(b) < 2 || b[0] != privateKeyVersion || b[1] == 0 || b[1] > MaxLevels {
+		return errors.New("lms: invalid private key encoding")
+	}
+
+	count := int(b[1])
+	b = b[2:]
+
+	if len(b) < count*levelStateSize {
+		return errors.New("lms: invalid private key size")
+	}
+
+	levels := make([]*lmsKey, count)
+
+	for i := range levels {
+		l := &lmsKey{
+			typ:    LMSType(binary.BigEndian.Uint32(b)),
+			otsTyp: LMOTSType(binary.BigEndian.Uint32(b[4:])),
+			id:     append([]byte{}, b[8:8+idSize]...),
+			seed:   append([]byte{}, b[8+idSize:8+idSize+n]...),
+			q:      binary.BigEndian.Uint32(b[8+idSize+n:]),
+		}
+
+		if err := l.buildTree(); err != nil {
+			return err
+		}
+
+		if l.q > l.leaves() {
+			return fmt.Errorf("lms: invalid leaf index %d at level %d", l.q, i)
+		}
+
+		levels[i] = l
+		b = b[levelStateSize:]
+	}
+
+	signedPubs, err := unmarshalSignedPubs(levels, b)
+	if err != nil {
+		return err
+	}
+
+	k.levels = levels
+	k.signedPubs = signedPubs
+
+	return nil
+}
+
+// unmarshalSignedPubs reads the signed public keys of the lower levels and checks they match the restored levels.
+func unmarshalSignedPubs(levels []*lmsKey, b []byte) ([][]byte, error) {
+	signedPubs := make([][]byte, len(levels)-1)
+
+	for i := range signedPubs {
+		if len(b) < 4 || len(b)-4 < int(binary.BigEndian.Uint32(b)) {
+			return nil, errors.New("lms: invalid private key size")
+		}
+
+		size := int(binary.BigEndian.Uint32(b))
+		sp := append([]byte{}, b[4:4+size]...)
+
+		if len(sp) < lmsPublicKeySize || !bytes.Equal(sp[len(sp)-lmsPublicKeySize:], levels[i+1].publicKey()) {
+			return nil, fmt.Errorf("lms: signed public key mismatch at level %d", i+1)
+		}
+
+		signedPubs[i] = sp
+		b = b[4+size:]
+	}
+
+	if len(b) != 0 {
+		return nil, errors.New("lms: invalid private key size")
+	}
+
+	return signedPubs, nil
+}
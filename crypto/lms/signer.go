@@ -0,0 +1,269 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lms
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	kmsapi "github.com/trustbloc/kms-go/kms"
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/spi/secretlock"
+)
+
+// Opt is a Signer option.
+type Opt func(o *signerOpts)
+
+type signerOpts struct {
+	rand       io.Reader
+	secretLock secretlock.Service
+	keyURI     string
+}
+
+// WithRandom sets the entropy source used for key generation and signature randomizers (default crypto/rand).
+func WithRandom(r io.Reader) Opt {
+	return func(o *signerOpts) {
+		o.rand = r
+	}
+}
+
+// WithSecretLock encrypts the key state stored in the kms.Store with the secret lock master key keyURI.
+func WithSecretLock(sl secretlock.Service, keyURI string) Opt {
+	return func(o *signerOpts) {
+		o.secretLock = sl
+		o.keyURI = keyURI
+	}
+}
+
+// Signer signs with an HSS private key whose state is kept in a kms.Store. Every call to Sign persists the advanced key
+// state before the signature is returned, so a failed or interrupted write can only skip one-time keys, never reuse
+// one. Only one Signer may be open per store and key ID in a process, a key must never be used from different
+// processes or restored from a backup.
+type Signer struct {
+	mu     sync.Mutex
+	store  kms.Store
+	keyID  string
+	key    *PrivateKey
+	opts   *signerOpts
+	closed bool
+	guard  interface{}
+}
+
+//nolint:gochecknoglobals
+var (
+	activeMu   sync.Mutex
+	activeKeys = map[interface{}]struct{}{}
+)
+
+type activeKey struct {
+	store kms.Store
+	keyID string
+}
+
+// CreateSigner generates a new HSS private key with the given params, stores it under keyID and returns its Signer.
+// It fails if store already holds keyID.
+func CreateSigner(store kms.Store, keyID string, params []Params, opts ...Opt) (*Signer, error) {
+	s, err := newSigner(store, keyID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = store.Get(keyID); err == nil {
+		s.release()
+
+		return nil, fmt.Errorf("lms: key '%s' already exists", keyID)
+	} else if !errors.Is(err, kmsapi.ErrKeyNotFound) {
+		s.release()
+
+		return nil, fmt.Errorf("lms: get key '%s': %w", keyID, err)
+	}
+
+	s.key, err = GenerateKey(params, s.opts.rand)
+	if err == nil {
+		err = s.persist(s.key)
+	}
+
+	if err != nil {
+		s.release()
+
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// OpenSigner loads the HSS private key stored under keyID and returns its Signer.
+func OpenSigner(store kms.Store, keyID string, opts ...Opt) (*Signer, error) {
+	s, err := newSigner(store, keyID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s.key, err = s.load()
+	if err != nil {
+		s.release()
+
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func newSigner(store kms.Store, keyID string, opts []Opt) (*Signer, error) {
+	if keyID == "" {
+		return nil, errors.New("lms: empty key ID")
+	}
+
+	o := &signerOpts{rand: rand.Reader}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	s := &Signer{store: store, keyID: keyID, opts: o, guard: keyID}
+
+	// key the guard by store too when possible, so the same key ID may be used in unrelated stores.
+	if reflect.TypeOf(store).Comparable() {
+		s.guard = activeKey{store: store, keyID: keyID}
+	}
+
+	activeMu.Lock()
+	defer activeMu.Unlock()
+
+	if _, ok := activeKeys[s.guard]; ok {
+		return nil, fmt.Errorf("lms: key '%s' is already in use", keyID)
+	}
+
+	activeKeys[s.guard] = struct{}{}
+
+	return s, nil
+}
+
+func (s *Signer) release() {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+
+	delete(activeKeys, s.guard)
+}
+
+// Sign signs msg with the next one-time key. It fails with ErrKeyExhausted when all one-time keys are used.
+func (s *Signer) Sign(msg []byte) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, errors.New("lms: signer is closed")
+	}
+
+	// reserve on a copy of the state so that a failure leaves s.key on the last persisted state.
+	next := &PrivateKey{
+		levels:     append([]*lmsKey{}, s.key.levels...),
+		signedPubs: append([][]byte{}, s.key.signedPubs...),
+	}
+
+	reserved, err := next.reserve(s.opts.rand)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.persist(next); err != nil {
+		return nil, err
+	}
+
+	s.key = next
+
+	return reserved.sign(msg, s.opts.rand)
+}
+
+// Public returns the HSS public key.
+func (s *Signer) Public() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.key.Public()
+}
+
+// Remaining returns the number of signatures the key can still produce.
+func (s *Signer) Remaining() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.key.Remaining()
+}
+
+// Close releases the key so that it can be opened again by OpenSigner.
+func (s *Signer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		s.release()
+	}
+
+	return nil
+}
+
+func (s *Signer) persist(k *PrivateKey) error {
+	b, err := k.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	if s.opts.secretLock != nil {
+		resp, e := s.opts.secretLock.Encrypt(s.opts.keyURI, &secretlock.EncryptRequest{
+			Plaintext:                   base64.URLEncoding.EncodeToString(b),
+			AdditionalAuthenticatedData: base64.URLEncoding.EncodeToString([]byte(s.keyID)),
+		})
+		if e != nil {
+			return fmt.Errorf("lms: encrypt key state: %w", e)
+		}
+
+		b = []byte(resp.Ciphertext)
+	}
+
+	if err = s.store.Put(s.keyID, b); err != nil {
+		return fmt.Errorf("lms: store key state: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Signer) load() (*PrivateKey, error) {
+	b, err := s.store.Get(s.keyID)
+	if err != nil {
+		return nil, fmt.Errorf("lms: get key '%s': %w", s.keyID, err)
+	}
+
+	if s.opts.secretLock != nil {
+		resp, e := s.opts.secretLock.Decrypt(s.opts.keyURI, &secretlock.DecryptRequest{
+			Ciphertext:                  string(b),
+			AdditionalAuthenticatedData: base64.URLEncoding.EncodeToString([]byte(s.keyID)),
+		})
+		if e != nil {
+			return nil, fmt.Errorf("lms: decrypt key state: %w", e)
+		}
+
+		b, err = base64.URLEncoding.DecodeString(resp.Plaintext)
+		if err != nil {
+			return nil, fmt.Errorf("lms: decode key state: %w", err)
+		}
+	}
+
+	k := &PrivateKey{}
+
+	if err = k.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lms
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/trustbloc/kms-go/kms"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+)
+
+type inMemoryKMSStore struct {
+	keys   map[string][]byte
+	errPut error
+}
+
+func newInMemoryKMSStore() *inMemoryKMSStore {
+	return &inMemoryKMSStore{keys: make(map[string][]byte)}
+}
+
+func (i *inMemoryKMSStore) Put(keysetID string, key []byte) error {
+	if i.errPut != nil {
+		return i.errPut
+	}
+
+	i.keys[keysetID] = key
+
+	return nil
+}
+
+func (i *inMemoryKMSStore) Get(keysetID string) ([]byte, error) {
+	key, found := i.keys[keysetID]
+	if !found {
+		return nil, kmsapi.ErrKeyNotFound
+	}
+
+	return key, nil
+}
+
+func (i *inMemoryKMSStore) Delete(keysetID string) error {
+	delete(i.keys, keysetID)
+
+	return nil
+}
+
+var testParams = []Params{{LMS: LMSSHA256M32H5, LMOTS: LMOTSSHA256N32W4}} //nolint:gochecknoglobals
+
+func TestSigner(t *testing.T) {
+	store := newInMemoryKMSStore()
+
+	s, err := CreateSigner(store, "fw-signing", testParams, WithSecretLock(&noop.NoLock{}, ""))
+	require.NoError(t, err)
+
+	pub := s.Public()
+
+	sig, err := s.Sign([]byte("image 1"))
+	require.NoError(t, err)
+	require.NoError(t, Verify(pub, []byte("image 1"), sig))
+	require.Equal(t, uint64(31), s.Remaining())
+
+	t.Run("key can't be opened twice", func(t *testing.T) {
+		_, err = OpenSigner(store, "fw-signing")
+		require.EqualError(t, err, "lms: key 'fw-signing' is already in use")
+
+		_, err = CreateSigner(store, "fw-signing", testParams)
+		require.EqualError(t, err, "lms: key 'fw-signing' is already in use")
+	})
+
+	t.Run("state is persisted before signing", func(t *testing.T) {
+		store.errPut = errors.New("disk full")
+
+		_, err = s.Sign([]byte("image 2"))
+		require.EqualError(t, err, "lms: store key state: disk full")
+		require.Equal(t, uint64(31), s.Remaining())
+
+		store.errPut = nil
+	})
+
+	require.NoError(t, s.Close())
+
+	_, err = s.Sign([]byte("image 2"))
+	require.EqualError(t, err, "lms: signer is closed")
+
+	t.Run("reopened key continues from the stored state", func(t *testing.T) {
+		reopened, err := OpenSigner(store, "fw-signing", WithSecretLock(&noop.NoLock{}, ""))
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, reopened.Close())
+		}()
+
+		require.Equal(t, pub, reopened.Public())
+		require.Equal(t, uint64(31), reopened.Remaining())
+
+		sig2, err := reopened.Sign([]byte("image 2"))
+		require.NoError(t, err)
+		require.NoError(t, Verify(pub, []byte("image 2"), sig2))
+
+		// the first leaf was not reused.
+		require.NotEqual(t, sig[4:8], sig2[4:8])
+	})
+
+	t.Run("exhausted key", func(t *testing.T) {
+		ex, err := OpenSigner(store, "fw-signing", WithSecretLock(&noop.NoLock{}, ""))
+		require.NoError(t, err)
+
+		for ex.Remaining() > 0 {
+			_, err = ex.Sign([]byte("image"))
+			require.NoError(t, err)
+		}
+
+		_, err = ex.Sign([]byte("image"))
+		require.ErrorIs(t, err, ErrKeyExhausted)
+		require.NoError(t, ex.Close())
+	})
+}
+
+func TestSignerErrors(t *testing.T) {
+	store := newInMemoryKMSStore()
+
+	_, err := CreateSigner(store, "", testParams)
+	require.EqualError(t, err, "lms: empty key ID")
+
+	_, err = OpenSigner(store, "missing")
+	require.ErrorIs(t, err, kmsapi.ErrKeyNotFound)
+
+	s, err := CreateSigner(store, "key", testParams)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	_, err = CreateSigner(store, "key", testParams)
+	require.EqualError(t, err, "lms: key 'key' already exists")
+
+	_, err = CreateSigner(store, "bad", []Params{{LMS: 1, LMOTS: LMOTSSHA256N32W4}})
+	require.Error(t, err)
+
+	// a failed open releases the key.
+	store.keys["corrupt"] = []byte{1}
+
+	_, err = OpenSigner(store, "corrupt")
+	require.Error(t, err)
+
+	_, err = OpenSigner(store, "corrupt")
+	require.EqualError(t, err, "lms: invalid private key encoding")
+}
@@ -0,0 +1,205 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package metrics provides an opt-in metrics decorator for crypto.Crypto implementations, reporting the operations to
+// a kms/metrics Recorder (eg: the kms/metrics Collector shared with the KeyManager decorator).
+//
+// Crypto operations get key handles, not key types: the key_type label of Tink keyset handles is the type of their
+// primary key (eg: 'EcdsaPrivateKey'), the curve of the recipient key for WrapKey and empty for other key handles (eg:
+// webkms key URLs).
+package metrics
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/tink/go/keyset"
+
+	cryptopkg "github.com/trustbloc/kms-go/crypto"
+	kmsmetrics "github.com/trustbloc/kms-go/kms/metrics"
+	"github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+// Crypto is a crypto.Crypto decorator reporting the operations of the wrapped Crypto to a Recorder.
+type Crypto struct {
+	c crypto.Crypto
+	r kmsmetrics.Recorder
+}
+
+// NewCrypto creates a Crypto reporting the operations of c to r.
+func NewCrypto(c crypto.Crypto, r kmsmetrics.Recorder) *Crypto {
+	return &Crypto{c: c, r: r}
+}
+
+func (m *Crypto) observe(op kmsapi.Operation, kh interface{}, start time.Time, err error) {
+	m.r.Observe(op, keyTypeLabel(kh), time.Since(start), err)
+}
+
+// keyTypeLabel returns the type of the primary key of Tink keyset handles, the empty string for other handles.
+func keyTypeLabel(kh interface{}) string {
+	h, ok := kh.(*keyset.Handle)
+	if !ok {
+		return ""
+	}
+
+	info := h.KeysetInfo()
+
+	for _, k := range info.GetKeyInfo() {
+		if k.GetKeyId() == info.GetPrimaryKeyId() {
+			typeURL := k.GetTypeUrl()
+
+			return typeURL[strings.LastIndex(typeURL, ".")+1:]
+		}
+	}
+
+	return ""
+}
+
+// Encrypt msg and aad with kh.
+func (m *Crypto) Encrypt(msg, aad []byte, kh interface{}) ([]byte, []byte, error) {
+	start := time.Now()
+	ct, nonce, err := m.c.Encrypt(msg, aad, kh)
+
+	m.observe(kmsapi.OperationEncrypt, kh, start, err)
+
+	return ct, nonce, err
+}
+
+// Decrypt cipher with aad and nonce using kh.
+func (m *Crypto) Decrypt(cipher, aad, nonce []byte, kh interface{}) ([]byte, error) {
+	start := time.Now()
+	pt, err := m.c.Decrypt(cipher, aad, nonce, kh)
+
+	m.observe(kmsapi.OperationDecrypt, kh, start, err)
+
+	return pt, err
+}
+
+// Sign msg with kh.
+func (m *Crypto) Sign(msg []byte, kh interface{}) ([]byte, error) {
+	start := time.Now()
+	sig, err := m.c.Sign(msg, kh)
+
+	m.observe(kmsapi.OperationSign, kh, start, err)
+
+	return sig, err
+}
+
+// SignBatch signs messages with kh, using the batch signing of the wrapped Crypto if it is a crypto.BatchSigner. The
+// batch is reported as a single OperationSign.
+func (m *Crypto) SignBatch(messages [][]byte, kh interface{}, opts ...crypto.SignBatchOpts) ([][]byte, error) {
+	start := time.Now()
+	sigs, err := cryptopkg.SignBatch(m.c, messages, kh, opts...)
+
+	m.observe(kmsapi.OperationSign, kh, start, err)
+
+	return sigs, err
+}
+
+// Verify signature of msg with kh.
+func (m *Crypto) Verify(signature, msg []byte, kh interface{}) error {
+	start := time.Now()
+	err := m.c.Verify(signature, msg, kh)
+
+	m.observe(kmsapi.OperationVerify, kh, start, err)
+
+	return err
+}
+
+// ComputeMAC computes the MAC of data with kh.
+func (m *Crypto) ComputeMAC(data []byte, kh interface{}) ([]byte, error) {
+	start := time.Now()
+	mac, err := m.c.ComputeMAC(data, kh)
+
+	m.observe(kmsapi.OperationComputeMAC, kh, start, err)
+
+	return mac, err
+}
+
+// VerifyMAC verifies mac of data with kh.
+func (m *Crypto) VerifyMAC(mac, data []byte, kh interface{}) error {
+	start := time.Now()
+	err := m.c.VerifyMAC(mac, data, kh)
+
+	m.observe(kmsapi.OperationVerifyMAC, kh, start, err)
+
+	return err
+}
+
+// WrapKey wraps cek for recPubKey.
+func (m *Crypto) WrapKey(cek, apu, apv []byte, recPubKey *crypto.PublicKey,
+	opts ...crypto.WrapKeyOpts) (*crypto.RecipientWrappedKey, error) {
+	start := time.Now()
+	wk, err := m.c.WrapKey(cek, apu, apv, recPubKey, opts...)
+
+	var curve string
+
+	if recPubKey != nil {
+		curve = recPubKey.Curve
+	}
+
+	m.r.Observe(kmsapi.OperationWrapKey, curve, time.Since(start), err)
+
+	return wk, err
+}
+
+// UnwrapKey unwraps the key of recWK with kh.
+func (m *Crypto) UnwrapKey(recWK *crypto.RecipientWrappedKey, kh interface{},
+	opts ...crypto.WrapKeyOpts) ([]byte, error) {
+	start := time.Now()
+	cek, err := m.c.UnwrapKey(recWK, kh, opts...)
+
+	m.observe(kmsapi.OperationUnwrapKey, kh, start, err)
+
+	return cek, err
+}
+
+// SignMulti signs messages with the BBS+ key kh.
+func (m *Crypto) SignMulti(messages [][]byte, kh interface{}) ([]byte, error) {
+	start := time.Now()
+	sig, err := m.c.SignMulti(messages, kh)
+
+	m.observe(kmsapi.OperationSignMulti, kh, start, err)
+
+	return sig, err
+}
+
+// VerifyMulti verifies the BBS+ signature of messages with kh.
+func (m *Crypto) VerifyMulti(messages [][]byte, signature []byte, kh interface{}) error {
+	start := time.Now()
+	err := m.c.VerifyMulti(messages, signature, kh)
+
+	m.observe(kmsapi.OperationVerifyMulti, kh, start, err)
+
+	return err
+}
+
+// VerifyProof verifies the BBS+ proof of revealedMessages with kh.
+func (m *Crypto) VerifyProof(revealedMessages [][]byte, proof, nonce []byte, kh interface{}) error {
+	start := time.Now()
+	err := m.c.VerifyProof(revealedMessages, proof, nonce, kh)
+
+	m.observe(kmsapi.OperationVerifyProof, kh, start, err)
+
+	return err
+}
+
+// DeriveProof derives a BBS+ proof revealing the messages at revealedIndexes with kh.
+func (m *Crypto) DeriveProof(messages [][]byte, bbsSignature, nonce []byte, revealedIndexes []int,
+	kh interface{}) ([]byte, error) {
+	start := time.Now()
+	proof, err := m.c.DeriveProof(messages, bbsSignature, nonce, revealedIndexes, kh)
+
+	m.observe(kmsapi.OperationDeriveProof, kh, start, err)
+
+	return proof, err
+}
+
+var (
+	_ crypto.Crypto      = &Crypto{}
+	_ crypto.BatchSigner = &Crypto{}
+)
@@ -0,0 +1,82 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/metrics"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	kmsmetrics "github.com/trustbloc/kms-go/kms/metrics"
+)
+
+func TestCrypto(t *testing.T) {
+	tc, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	reg := prometheus.NewPedanticRegistry()
+
+	collector, err := kmsmetrics.NewCollector(reg, "crypto")
+	require.NoError(t, err)
+
+	c := metrics.NewCrypto(tc, collector)
+
+	sigKH, err := keyset.NewHandle(signature.ED25519KeyTemplate())
+	require.NoError(t, err)
+
+	sig, err := c.Sign([]byte("msg"), sigKH)
+	require.NoError(t, err)
+
+	sigs, err := c.SignBatch([][]byte{[]byte("msg"), []byte("msg")}, sigKH)
+	require.NoError(t, err)
+	require.Len(t, sigs, 2)
+
+	pubKH, err := sigKH.Public()
+	require.NoError(t, err)
+
+	require.NoError(t, c.Verify(sig, []byte("msg"), pubKH))
+	require.Error(t, c.Verify([]byte("bad"), []byte("msg"), pubKH))
+
+	aeadKH, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+	require.NoError(t, err)
+
+	ct, nonce, err := c.Encrypt([]byte("msg"), nil, aeadKH)
+	require.NoError(t, err)
+
+	_, err = c.Decrypt(ct, nil, nonce, aeadKH)
+	require.NoError(t, err)
+
+	_, err = c.Sign([]byte("msg"), "https://kms.example.com/keys/1")
+	require.Error(t, err)
+
+	expected := `# HELP crypto_operations_total Number of operations.
+# TYPE crypto_operations_total counter
+crypto_operations_total{key_type="",operation="sign"} 1
+crypto_operations_total{key_type="AesGcmKey",operation="decrypt"} 1
+crypto_operations_total{key_type="AesGcmKey",operation="encrypt"} 1
+crypto_operations_total{key_type="Ed25519PrivateKey",operation="sign"} 2
+crypto_operations_total{key_type="Ed25519PublicKey",operation="verify"} 2
+# HELP crypto_operation_errors_total Number of failed operations.
+# TYPE crypto_operation_errors_total counter
+crypto_operation_errors_total{key_type="",operation="sign"} 1
+crypto_operation_errors_total{key_type="AesGcmKey",operation="decrypt"} 0
+crypto_operation_errors_total{key_type="AesGcmKey",operation="encrypt"} 0
+crypto_operation_errors_total{key_type="Ed25519PrivateKey",operation="sign"} 0
+crypto_operation_errors_total{key_type="Ed25519PublicKey",operation="verify"} 1
+`
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "crypto_operations_total",
+		"crypto_operation_errors_total"))
+}
@@ -0,0 +1,212 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package noise
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/trustbloc/kms-go/internal/memguard"
+)
+
+const (
+	hashLen = sha256.Size
+	tagLen  = chacha20poly1305.Overhead
+)
+
+var errNonceExhausted = errors.New("noise: nonce exhausted")
+
+// CipherState encrypts and decrypts the messages of one direction of a Noise session with ChaChaPoly. The transport
+// CipherStates of a completed Handshake are returned by Handshake.Transport. A CipherState is not safe for concurrent
+// use.
+type CipherState struct {
+	k      [chacha20poly1305.KeySize]byte
+	hasKey bool
+	n      uint64
+}
+
+func (c *CipherState) initializeKey(k []byte) {
+	copy(c.k[:], k)
+	c.hasKey = true
+	c.n = 0
+}
+
+func (c *CipherState) nonce() []byte {
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	binary.LittleEndian.PutUint64(nonce[4:], c.n)
+
+	return nonce
+}
+
+// Encrypt encrypts plaintext with the associated data ad and the next nonce. Plaintexts are returned as is by a
+// CipherState without a key (before the first DH of a handshake).
+func (c *CipherState) Encrypt(ad, plaintext []byte) ([]byte, error) {
+	if !c.hasKey {
+		return append([]byte(nil), plaintext...), nil
+	}
+
+	// 2^64-1 is reserved by the spec (see Rekey).
+	if c.n == math.MaxUint64 {
+		return nil, errNonceExhausted
+	}
+
+	aead, err := chacha20poly1305.New(c.k[:])
+	if err != nil {
+		return nil, err
+	}
+
+	ct := aead.Seal(nil, c.nonce(), plaintext, ad)
+	c.n++
+
+	return ct, nil
+}
+
+// Decrypt decrypts ciphertext with the associated data ad and the next nonce. The nonce is only incremented when
+// ciphertext is authentic.
+func (c *CipherState) Decrypt(ad, ciphertext []byte) ([]byte, error) {
+	if !c.hasKey {
+		return append([]byte(nil), ciphertext...), nil
+	}
+
+	if c.n == math.MaxUint64 {
+		return nil, errNonceExhausted
+	}
+
+	aead, err := chacha20poly1305.New(c.k[:])
+	if err != nil {
+		return nil, err
+	}
+
+	pt, err := aead.Open(nil, c.nonce(), ciphertext, ad)
+	if err != nil {
+		return nil, errors.New("noise: message authentication failed")
+	}
+
+	c.n++
+
+	return pt, nil
+}
+
+// Rekey replaces the key of c with a key derived from it, as per the Noise specification (section 11.3). Both parties
+// must rekey after the same message.
+func (c *CipherState) Rekey() {
+	if !c.hasKey {
+		return
+	}
+
+	aead, err := chacha20poly1305.New(c.k[:])
+	if err != nil {
+		return
+	}
+
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	binary.LittleEndian.PutUint64(nonce[4:], math.MaxUint64)
+
+	k := aead.Seal(nil, nonce, make([]byte, chacha20poly1305.KeySize), nil)
+	copy(c.k[:], k)
+
+	memguard.Wipe(k)
+}
+
+// destroy wipes the key of c.
+func (c *CipherState) destroy() {
+	memguard.Wipe(c.k[:])
+	c.hasKey = false
+}
+
+// symmetricState is the Noise SymmetricState: the chaining key ck, the handshake hash h and the CipherState of the
+// handshake messages.
+type symmetricState struct {
+	cs CipherState
+	ck [hashLen]byte
+	h  [hashLen]byte
+}
+
+func (s *symmetricState) initialize(protocolName string) {
+	if len(protocolName) <= hashLen {
+		copy(s.h[:], protocolName)
+	} else {
+		s.h = sha256.Sum256([]byte(protocolName))
+	}
+
+	s.ck = s.h
+}
+
+func (s *symmetricState) mixKey(ikm []byte) {
+	ck, k := hkdf(s.ck[:], ikm)
+
+	copy(s.ck[:], ck)
+	s.cs.initializeKey(k)
+
+	memguard.Wipe(ck, k)
+}
+
+func (s *symmetricState) mixHash(data []byte) {
+	h := sha256.New()
+	h.Write(s.h[:])
+	h.Write(data)
+	h.Sum(s.h[:0])
+}
+
+func (s *symmetricState) encryptAndHash(plaintext []byte) ([]byte, error) {
+	ct, err := s.cs.Encrypt(s.h[:], plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mixHash(ct)
+
+	return ct, nil
+}
+
+func (s *symmetricState) decryptAndHash(ciphertext []byte) ([]byte, error) {
+	pt, err := s.cs.Decrypt(s.h[:], ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mixHash(ciphertext)
+
+	return pt, nil
+}
+
+// split returns the transport CipherStates: the initiator to responder one, then the responder to initiator one.
+func (s *symmetricState) split() (*CipherState, *CipherState) {
+	k1, k2 := hkdf(s.ck[:], nil)
+
+	c1, c2 := &CipherState{}, &CipherState{}
+	c1.initializeKey(k1)
+	c2.initializeKey(k2)
+
+	memguard.Wipe(k1, k2, s.ck[:])
+	s.cs.destroy()
+
+	return c1, c2
+}
+
+// hkdf is the two outputs Noise HKDF function of HMAC-SHA256.
+func hkdf(ck, ikm []byte) ([]byte, []byte) {
+	tempKey := hmacSHA256(ck, ikm)
+	defer memguard.Wipe(tempKey)
+
+	out1 := hmacSHA256(tempKey, []byte{1})
+	out2 := hmacSHA256(tempKey, append(append([]byte(nil), out1...), 2)) //nolint:gomnd // second output
+
+	return out1, out2
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+
+	return mac.Sum(nil)
+}
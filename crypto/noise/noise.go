@@ -0,0 +1,406 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package noise implements the XX and IK handshakes of the Noise Protocol Framework
+// (https://noiseprotocol.org/noise.html) with the 25519 DH functions, ChaChaPoly and SHA256:
+// Noise_XX_25519_ChaChaPoly_SHA256 and Noise_IK_25519_ChaChaPoly_SHA256.
+//
+// The local static key is an X25519 ECDH key of a KMS (eg: a kms.X25519ECDHKWType key of localkms), the DH operations
+// with it are executed by the KeyRef.ComputeDH of a kms/keyref Manager, whose Policy must allow kms.OperationComputeDH
+// for the key, so the static private key never leaves the KMS. Ephemeral keys are
+// generated in memory for each handshake and wiped once it completes.
+package noise
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/curve25519"
+
+	"github.com/trustbloc/kms-go/internal/memguard"
+	"github.com/trustbloc/kms-go/kms/keyref"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+)
+
+// Pattern is a Noise handshake pattern.
+type Pattern string
+
+const (
+	// XX is the Noise XX pattern: both parties transmit their static keys during the handshake.
+	XX = Pattern("XX")
+	// IK is the Noise IK pattern: the initiator knows the responder static key before the handshake (see
+	// WithRemoteStatic) and transmits its own static key in the first message.
+	IK = Pattern("IK")
+)
+
+// MaxMessageSize is the maximum size of a Noise message.
+const MaxMessageSize = 65535
+
+const dhLen = curve25519.PointSize
+
+type token int
+
+const (
+	tokenE token = iota
+	tokenS
+	tokenEE
+	tokenES
+	tokenSE
+	tokenSS
+)
+
+//nolint:gochecknoglobals
+var patterns = map[Pattern][][]token{
+	XX: {{tokenE}, {tokenE, tokenEE, tokenS, tokenES}, {tokenS, tokenSE}},
+	IK: {{tokenE, tokenES, tokenS, tokenSS}, {tokenE, tokenEE, tokenSE}},
+}
+
+// Opt is a NewHandshake option.
+type Opt func(o *opts)
+
+type opts struct {
+	prologue     []byte
+	remoteStatic []byte
+	random       io.Reader
+}
+
+// WithPrologue sets the prologue both parties must agree on, it is authenticated by the handshake.
+func WithPrologue(prologue []byte) Opt {
+	return func(o *opts) {
+		o.prologue = prologue
+	}
+}
+
+// WithRemoteStatic sets the X25519 static public key of the responder, required by IK initiators.
+func WithRemoteStatic(pub []byte) Opt {
+	return func(o *opts) {
+		o.remoteStatic = pub
+	}
+}
+
+// WithRandomness sets the source of the ephemeral keys, crypto/rand.Reader by default.
+func WithRandomness(r io.Reader) Opt {
+	return func(o *opts) {
+		o.random = r
+	}
+}
+
+// Handshake is the state of one party of a Noise handshake. Handshake messages are written and read in turns, starting
+// with a WriteMessage call of the initiator, until Complete returns true. A Handshake is not safe for concurrent use.
+type Handshake struct {
+	ss        symmetricState
+	static    *keyref.KeyRef
+	s         []byte
+	e         *[dhLen]byte
+	ePub      []byte
+	rs        []byte
+	re        []byte
+	initiator bool
+	messages  [][]token
+	random    io.Reader
+	next      int
+	send      *CipherState
+	recv      *CipherState
+	err       error
+}
+
+// NewHandshake creates the Handshake of the initiator or responder of a pattern handshake. static is the reference of
+// the local static X25519 key.
+func NewHandshake(pattern Pattern, initiator bool, static *keyref.KeyRef, opt ...Opt) (*Handshake, error) {
+	messages, ok := patterns[pattern]
+	if !ok {
+		return nil, fmt.Errorf("noise: unsupported pattern '%s'", pattern)
+	}
+
+	if static == nil {
+		return nil, errors.New("noise: static key is required")
+	}
+
+	staticPub, err := static.ExportJWK()
+	if err != nil {
+		return nil, fmt.Errorf("noise: export static public key: %w", err)
+	}
+
+	s, ok := staticPub.Key.([]byte)
+	if staticPub.Crv != "X25519" || !ok || len(s) != dhLen {
+		return nil, errors.New("noise: invalid static public key")
+	}
+
+	o := &opts{random: rand.Reader}
+
+	for _, f := range opt {
+		f(o)
+	}
+
+	hs := &Handshake{
+		static:    static,
+		s:         append([]byte(nil), s...),
+		initiator: initiator,
+		messages:  messages,
+		random:    o.random,
+	}
+
+	hs.ss.initialize("Noise_" + string(pattern) + "_25519_ChaChaPoly_SHA256")
+	hs.ss.mixHash(o.prologue)
+
+	if pattern == IK {
+		// pre-message pattern "<- s".
+		if !initiator {
+			hs.ss.mixHash(hs.s)
+
+			return hs, nil
+		}
+
+		if len(o.remoteStatic) != dhLen {
+			return nil, errors.New("noise: IK initiator requires the responder static public key")
+		}
+
+		hs.rs = append([]byte(nil), o.remoteStatic...)
+		hs.ss.mixHash(hs.rs)
+	}
+
+	return hs, nil
+}
+
+// WriteMessage writes the next handshake message, with payload. The payload of the first message of a handshake is not
+// encrypted, nor is the payload of the first XX response sent before the responder static key.
+func (h *Handshake) WriteMessage(payload []byte) ([]byte, error) {
+	if err := h.checkTurn(true); err != nil {
+		return nil, err
+	}
+
+	var msg []byte
+
+	for _, tok := range h.messages[h.next] {
+		switch tok {
+		case tokenE:
+			if err := h.generateEphemeral(); err != nil {
+				return nil, h.fail(err)
+			}
+
+			msg = append(msg, h.ePub...)
+			h.ss.mixHash(h.ePub)
+		case tokenS:
+			ct, err := h.ss.encryptAndHash(h.s)
+			if err != nil {
+				return nil, h.fail(err)
+			}
+
+			msg = append(msg, ct...)
+		default:
+			if err := h.mixDH(tok); err != nil {
+				return nil, h.fail(err)
+			}
+		}
+	}
+
+	ct, err := h.ss.encryptAndHash(payload)
+	if err != nil {
+		return nil, h.fail(err)
+	}
+
+	msg = append(msg, ct...)
+
+	if len(msg) > MaxMessageSize {
+		return nil, h.fail(errors.New("noise: message too large"))
+	}
+
+	h.advance()
+
+	return msg, nil
+}
+
+// ReadMessage reads the next handshake message and returns its payload. A Handshake can't be used anymore once
+// ReadMessage failed.
+func (h *Handshake) ReadMessage(msg []byte) ([]byte, error) {
+	if err := h.checkTurn(false); err != nil {
+		return nil, err
+	}
+
+	if len(msg) > MaxMessageSize {
+		return nil, h.fail(errors.New("noise: message too large"))
+	}
+
+	for _, tok := range h.messages[h.next] {
+		switch tok {
+		case tokenE:
+			if len(msg) < dhLen {
+				return nil, h.fail(errors.New("noise: message too short"))
+			}
+
+			h.re = append([]byte(nil), msg[:dhLen]...)
+			h.ss.mixHash(h.re)
+			msg = msg[dhLen:]
+		case tokenS:
+			n := dhLen
+			if h.ss.cs.hasKey {
+				n += tagLen
+			}
+
+			if len(msg) < n {
+				return nil, h.fail(errors.New("noise: message too short"))
+			}
+
+			rs, err := h.ss.decryptAndHash(msg[:n])
+			if err != nil {
+				return nil, h.fail(err)
+			}
+
+			h.rs = rs
+			msg = msg[n:]
+		default:
+			if err := h.mixDH(tok); err != nil {
+				return nil, h.fail(err)
+			}
+		}
+	}
+
+	payload, err := h.ss.decryptAndHash(msg)
+	if err != nil {
+		return nil, h.fail(err)
+	}
+
+	h.advance()
+
+	return payload, nil
+}
+
+// Complete returns true once all the handshake messages were written and read.
+func (h *Handshake) Complete() bool {
+	return h.send != nil
+}
+
+// RemoteStatic returns the static public key of the remote party, nil until it is received.
+func (h *Handshake) RemoteStatic() []byte {
+	return h.rs
+}
+
+// HandshakeHash returns the hash of the handshake, identifying the session for channel binding once the handshake is
+// complete.
+func (h *Handshake) HandshakeHash() []byte {
+	return append([]byte(nil), h.ss.h[:]...)
+}
+
+// Transport returns the transport CipherStates of a complete handshake: send encrypts the messages sent to the remote
+// party and recv decrypts the messages received from it.
+func (h *Handshake) Transport() (*CipherState, *CipherState, error) {
+	if !h.Complete() {
+		return nil, nil, errors.New("noise: handshake is not complete")
+	}
+
+	return h.send, h.recv, nil
+}
+
+func (h *Handshake) checkTurn(write bool) error {
+	if h.err != nil {
+		return h.err
+	}
+
+	if h.Complete() {
+		return errors.New("noise: handshake is complete")
+	}
+
+	// the initiator writes the even messages, the responder the odd ones.
+	if (h.next%2 == 0) != (h.initiator == write) {
+		return errors.New("noise: out of turn handshake message")
+	}
+
+	return nil
+}
+
+func (h *Handshake) fail(err error) error {
+	h.err = fmt.Errorf("noise: handshake failed: %w", err)
+	h.wipe()
+
+	return h.err
+}
+
+func (h *Handshake) advance() {
+	h.next++
+
+	if h.next < len(h.messages) {
+		return
+	}
+
+	c1, c2 := h.ss.split()
+
+	h.send, h.recv = c1, c2
+	if !h.initiator {
+		h.send, h.recv = c2, c1
+	}
+
+	h.wipe()
+}
+
+func (h *Handshake) wipe() {
+	if h.e != nil {
+		memguard.Wipe(h.e[:])
+		h.e = nil
+	}
+
+	memguard.Wipe(h.ss.ck[:])
+	h.ss.cs.destroy()
+}
+
+func (h *Handshake) generateEphemeral() error {
+	var e [dhLen]byte
+
+	if _, err := io.ReadFull(h.random, e[:]); err != nil {
+		return err
+	}
+
+	pub, err := curve25519.X25519(e[:], curve25519.Basepoint)
+	if err != nil {
+		return err
+	}
+
+	h.e, h.ePub = &e, pub
+
+	return nil
+}
+
+// mixDH mixes the DH output of a ee, es, se or ss token into the chaining key.
+func (h *Handshake) mixDH(tok token) error {
+	var (
+		z   []byte
+		err error
+	)
+
+	// es is DH(e, rs) for the initiator and DH(s, re) for the responder, se the opposite.
+	localStatic := tok == tokenSS || (tok == tokenES) != h.initiator
+	remoteStatic := tok == tokenSS || (tok == tokenSE) != h.initiator
+
+	if tok == tokenEE {
+		localStatic, remoteStatic = false, false
+	}
+
+	remote := h.re
+	if remoteStatic {
+		remote = h.rs
+	}
+
+	if len(remote) != dhLen {
+		return errors.New("missing remote key")
+	}
+
+	if localStatic {
+		z, err = h.static.ComputeDH(&cryptoapi.PublicKey{Type: "OKP", Curve: "X25519", X: remote})
+	} else {
+		z, err = curve25519.X25519(h.e[:], remote)
+	}
+
+	if err != nil {
+		return fmt.Errorf("DH: %w", err)
+	}
+
+	defer memguard.Wipe(z)
+
+	h.ss.mixKey(z)
+
+	return nil
+}
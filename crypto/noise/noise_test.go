@@ -0,0 +1,278 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package noise
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/kms/keyref"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+type staticKey struct {
+	ref *keyref.KeyRef
+	pub []byte
+}
+
+// newStaticKey creates an X25519 key in a mock KMS, referenced by a keyref Manager allowing computeDH for it.
+func newStaticKey(t *testing.T) *staticKey {
+	t.Helper()
+
+	km, err := mockkms.New()
+	require.NoError(t, err)
+
+	keyID, _, err := km.Create(kmsapi.X25519ECDHKWType)
+	require.NoError(t, err)
+
+	c, err := tinkcrypto.New(tinkcrypto.WithComputeDH())
+	require.NoError(t, err)
+
+	ref, err := keyref.New(km, c, keyref.WithPolicy(keyref.AllowComputeDH(keyID))).Get(keyID)
+	require.NoError(t, err)
+
+	jwk, err := ref.ExportJWK()
+	require.NoError(t, err)
+
+	return &staticKey{ref: ref, pub: jwk.Key.([]byte)}
+}
+
+func newHandshakes(t *testing.T, pattern Pattern, opt ...Opt) (*Handshake, *Handshake, *staticKey, *staticKey) {
+	t.Helper()
+
+	iKey, rKey := newStaticKey(t), newStaticKey(t)
+
+	initiatorOpts := opt
+	if pattern == IK {
+		initiatorOpts = append(initiatorOpts, WithRemoteStatic(rKey.pub))
+	}
+
+	initiator, err := NewHandshake(pattern, true, iKey.ref, initiatorOpts...)
+	require.NoError(t, err)
+
+	responder, err := NewHandshake(pattern, false, rKey.ref, opt...)
+	require.NoError(t, err)
+
+	return initiator, responder, iKey, rKey
+}
+
+// run runs a handshake, the writer of each message alternating between initiator and responder.
+func run(t *testing.T, initiator, responder *Handshake) {
+	t.Helper()
+
+	writer, reader := initiator, responder
+
+	for i := 0; !initiator.Complete(); i++ {
+		payload := []byte{byte(i)}
+
+		msg, err := writer.WriteMessage(payload)
+		require.NoError(t, err)
+
+		got, err := reader.ReadMessage(msg)
+		require.NoError(t, err)
+		require.Equal(t, payload, got)
+
+		writer, reader = reader, writer
+	}
+
+	require.True(t, responder.Complete())
+}
+
+func TestHandshake(t *testing.T) {
+	for _, pattern := range []Pattern{XX, IK} {
+		t.Run(string(pattern), func(t *testing.T) {
+			initiator, responder, iKey, rKey := newHandshakes(t, pattern, WithPrologue([]byte("prologue")))
+
+			run(t, initiator, responder)
+
+			require.Equal(t, rKey.pub, initiator.RemoteStatic())
+			require.Equal(t, iKey.pub, responder.RemoteStatic())
+			require.Equal(t, initiator.HandshakeHash(), responder.HandshakeHash())
+
+			iSend, iRecv, err := initiator.Transport()
+			require.NoError(t, err)
+
+			rSend, rRecv, err := responder.Transport()
+			require.NoError(t, err)
+
+			for i := 0; i < 3; i++ {
+				ct, err := iSend.Encrypt(nil, []byte("ping"))
+				require.NoError(t, err)
+
+				pt, err := rRecv.Decrypt(nil, ct)
+				require.NoError(t, err)
+				require.Equal(t, []byte("ping"), pt)
+
+				ct, err = rSend.Encrypt([]byte("ad"), []byte("pong"))
+				require.NoError(t, err)
+
+				pt, err = iRecv.Decrypt([]byte("ad"), ct)
+				require.NoError(t, err)
+				require.Equal(t, []byte("pong"), pt)
+			}
+
+			iSend.Rekey()
+			rRecv.Rekey()
+
+			ct, err := iSend.Encrypt(nil, []byte("rekeyed"))
+			require.NoError(t, err)
+
+			pt, err := rRecv.Decrypt(nil, ct)
+			require.NoError(t, err)
+			require.Equal(t, []byte("rekeyed"), pt)
+
+			_, err = initiator.WriteMessage(nil)
+			require.EqualError(t, err, "noise: handshake is complete")
+		})
+	}
+}
+
+func TestHandshakeFailures(t *testing.T) {
+	t.Run("prologue mismatch", func(t *testing.T) {
+		iKey, rKey := newStaticKey(t), newStaticKey(t)
+
+		initiator, err := NewHandshake(XX, true, iKey.ref, WithPrologue([]byte("a")))
+		require.NoError(t, err)
+
+		responder, err := NewHandshake(XX, false, rKey.ref, WithPrologue([]byte("b")))
+		require.NoError(t, err)
+
+		msg, err := initiator.WriteMessage(nil)
+		require.NoError(t, err)
+
+		_, err = responder.ReadMessage(msg)
+		require.NoError(t, err)
+
+		msg, err = responder.WriteMessage(nil)
+		require.NoError(t, err)
+
+		_, err = initiator.ReadMessage(msg)
+		require.EqualError(t, err, "noise: handshake failed: noise: message authentication failed")
+
+		// a failed handshake can't be used anymore.
+		_, err = initiator.WriteMessage(nil)
+		require.Error(t, err)
+	})
+
+	t.Run("tampered message", func(t *testing.T) {
+		initiator, responder, _, _ := newHandshakes(t, IK)
+
+		msg, err := initiator.WriteMessage([]byte("payload"))
+		require.NoError(t, err)
+
+		msg[len(msg)-1] ^= 1
+
+		_, err = responder.ReadMessage(msg)
+		require.ErrorContains(t, err, "message authentication failed")
+	})
+
+	t.Run("IK initiator with the wrong responder key", func(t *testing.T) {
+		iKey, rKey, other := newStaticKey(t), newStaticKey(t), newStaticKey(t)
+
+		initiator, err := NewHandshake(IK, true, iKey.ref, WithRemoteStatic(other.pub))
+		require.NoError(t, err)
+
+		responder, err := NewHandshake(IK, false, rKey.ref)
+		require.NoError(t, err)
+
+		msg, err := initiator.WriteMessage(nil)
+		require.NoError(t, err)
+
+		_, err = responder.ReadMessage(msg)
+		require.ErrorContains(t, err, "message authentication failed")
+	})
+
+	t.Run("out of turn and short messages", func(t *testing.T) {
+		initiator, responder, _, _ := newHandshakes(t, XX)
+
+		_, err := responder.WriteMessage(nil)
+		require.EqualError(t, err, "noise: out of turn handshake message")
+
+		_, err = initiator.ReadMessage(nil)
+		require.EqualError(t, err, "noise: out of turn handshake message")
+
+		_, _, err = initiator.Transport()
+		require.EqualError(t, err, "noise: handshake is not complete")
+
+		_, err = responder.ReadMessage([]byte("short"))
+		require.EqualError(t, err, "noise: handshake failed: noise: message too short")
+
+		_, err = responder.ReadMessage(make([]byte, MaxMessageSize+1))
+		require.Error(t, err)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		key := newStaticKey(t)
+
+		_, err := NewHandshake("NN", true, key.ref)
+		require.EqualError(t, err, "noise: unsupported pattern 'NN'")
+
+		_, err = NewHandshake(XX, true, nil)
+		require.EqualError(t, err, "noise: static key is required")
+
+		km, err := mockkms.New()
+		require.NoError(t, err)
+
+		keyID, _, err := km.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		c, err := tinkcrypto.New(tinkcrypto.WithComputeDH())
+		require.NoError(t, err)
+
+		edRef, err := keyref.New(km, c).Get(keyID)
+		require.NoError(t, err)
+
+		_, err = NewHandshake(XX, true, edRef)
+		require.EqualError(t, err, "noise: invalid static public key")
+
+		_, err = NewHandshake(IK, true, key.ref)
+		require.EqualError(t, err, "noise: IK initiator requires the responder static public key")
+	})
+
+	t.Run("computeDH not allowed for the static key", func(t *testing.T) {
+		rKey := newStaticKey(t)
+
+		km, err := mockkms.New()
+		require.NoError(t, err)
+
+		keyID, _, err := km.Create(kmsapi.X25519ECDHKWType)
+		require.NoError(t, err)
+
+		c, err := tinkcrypto.New(tinkcrypto.WithComputeDH())
+		require.NoError(t, err)
+
+		ref, err := keyref.New(km, c).Get(keyID)
+		require.NoError(t, err)
+
+		initiator, err := NewHandshake(IK, true, ref, WithRemoteStatic(rKey.pub))
+		require.NoError(t, err)
+
+		_, err = initiator.WriteMessage(nil)
+		require.ErrorIs(t, err, keyref.ErrComputeDHDisabled)
+	})
+}
+
+func TestCipherState(t *testing.T) {
+	c := &CipherState{}
+
+	ct, err := c.Encrypt(nil, []byte("plaintext"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("plaintext"), ct)
+
+	c.initializeKey(make([]byte, 32))
+	c.n = math.MaxUint64
+
+	_, err = c.Encrypt(nil, []byte("plaintext"))
+	require.ErrorIs(t, err, errNonceExhausted)
+
+	_, err = c.Decrypt(nil, ct)
+	require.ErrorIs(t, err, errNonceExhausted)
+}
@@ -0,0 +1,234 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package nonce manages the nonces of the AEAD keys encrypting with caller chosen nonces (see
+// crypto.DetachedNonceAEAD), where reusing a nonce with the same key is catastrophic (eg: AES-GCM).
+//
+// Nonces follow the deterministic construction of NIST SP 800-38D section 8.2.1: a fixed field, the instance ID,
+// followed by an invocation field, a counter incremented for every nonce of the key. The counters are persisted in a
+// storage provider by blocks: a block of counters is reserved in the store before its first nonce is returned, so that
+// a restarted Manager never returns a nonce of a previous run, the unused counters of the reserved block are skipped.
+// Instances of a multi-instance deployment sharing the keys and the store must each have a distinct instance ID.
+package nonce
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/spi/storage"
+)
+
+const (
+	// StoreName is the name of the store of the nonce counters.
+	StoreName = "noncecounters"
+
+	// InstanceIDSize is the size of the instance ID, the fixed field of the nonces.
+	InstanceIDSize = 4
+
+	defaultNonceSize   = 12
+	defaultReservation = 1024
+	counterSize        = 8
+)
+
+// ErrCounterExhausted is returned when all the nonces of a key were used, the key must be rotated.
+var ErrCounterExhausted = errors.New("nonce counter exhausted")
+
+type counter struct {
+	next     uint64
+	reserved uint64
+}
+
+// Manager returns unique nonces per key. It is safe for concurrent use.
+type Manager struct {
+	crypto      cryptoapi.DetachedNonceAEAD
+	store       storage.Store
+	instanceID  [InstanceIDSize]byte
+	nonceSize   int
+	reservation uint64
+
+	mu       sync.Mutex
+	counters map[string]*counter
+}
+
+type opts struct {
+	instanceID  []byte
+	nonceSize   int
+	reservation uint64
+}
+
+// Opt is a Manager option.
+type Opt func(*opts)
+
+// WithInstanceID sets the instance ID of the Manager, the fixed field of its nonces. It must be distinct for every
+// instance sharing the keys, it is 4 zero bytes by default for single-instance deployments.
+func WithInstanceID(id [InstanceIDSize]byte) Opt {
+	return func(o *opts) {
+		o.instanceID = id[:]
+	}
+}
+
+// WithNonceSize sets the size of the nonces, the 12 bytes nonces of AES-GCM and ChaCha20Poly1305 by default (eg: 24
+// for XChaCha20Poly1305).
+func WithNonceSize(size int) Opt {
+	return func(o *opts) {
+		o.nonceSize = size
+	}
+}
+
+// WithReservation sets the number of counters reserved in the store at once, 1024 by default. Larger reservations
+// write the store less often but skip more nonces when the Manager restarts.
+func WithReservation(n uint64) Opt {
+	return func(o *opts) {
+		o.reservation = n
+	}
+}
+
+// NewManager creates a Manager encrypting with c and persisting its counters in the StoreName store of p.
+func NewManager(c cryptoapi.DetachedNonceAEAD, p storage.Provider, opt ...Opt) (*Manager, error) {
+	o := &opts{
+		instanceID:  make([]byte, InstanceIDSize),
+		nonceSize:   defaultNonceSize,
+		reservation: defaultReservation,
+	}
+
+	for _, fn := range opt {
+		fn(o)
+	}
+
+	if o.nonceSize < InstanceIDSize+counterSize {
+		return nil, fmt.Errorf("nonce: nonce size %d is smaller than %d", o.nonceSize, InstanceIDSize+counterSize)
+	}
+
+	if o.reservation == 0 {
+		return nil, errors.New("nonce: reservation must be positive")
+	}
+
+	store, err := p.OpenStore(StoreName)
+	if err != nil {
+		return nil, fmt.Errorf("nonce: open store: %w", err)
+	}
+
+	m := &Manager{
+		crypto:      c,
+		store:       store,
+		nonceSize:   o.nonceSize,
+		reservation: o.reservation,
+		counters:    map[string]*counter{},
+	}
+
+	copy(m.instanceID[:], o.instanceID)
+
+	return m, nil
+}
+
+// Next returns the next nonce of the key kid. The nonce is never returned again for kid by a Manager with the same
+// instance ID and store.
+func (m *Manager) Next(kid string) ([]byte, error) {
+	if kid == "" {
+		return nil, errors.New("nonce: key ID is empty")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.counter(kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.next == c.reserved {
+		if err = m.reserve(kid, c); err != nil {
+			return nil, err
+		}
+	}
+
+	nonce := make([]byte, m.nonceSize)
+	copy(nonce, m.instanceID[:])
+	binary.BigEndian.PutUint64(nonce[m.nonceSize-counterSize:], c.next)
+
+	c.next++
+
+	return nonce, nil
+}
+
+// Encrypt encrypts msg with aad using the primary key of kh, the key identified by kid, and the next nonce of kid.
+// returns:
+//
+//	the ciphertext followed by the tag, without nonce
+//	the nonce, to be carried with the ciphertext for decryption with DecryptDetached
+//	error in case of errors
+func (m *Manager) Encrypt(msg, aad []byte, kh interface{}, kid string) ([]byte, []byte, error) {
+	nonce, err := m.Next(kid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ct, err := m.crypto.EncryptWithNonce(msg, aad, nonce, kh)
+	if err != nil {
+		return nil, nil, fmt.Errorf("nonce: encrypt: %w", err)
+	}
+
+	return ct, nonce, nil
+}
+
+func (m *Manager) storeKey(kid string) string {
+	return kid + "/" + hex.EncodeToString(m.instanceID[:])
+}
+
+// counter returns the counter of kid, loading its reserved counters from the store on first use.
+func (m *Manager) counter(kid string) (*counter, error) {
+	if c, ok := m.counters[kid]; ok {
+		return c, nil
+	}
+
+	c := &counter{}
+
+	v, err := m.store.Get(m.storeKey(kid))
+
+	switch {
+	case errors.Is(err, storage.ErrDataNotFound):
+	case err != nil:
+		return nil, fmt.Errorf("nonce: get counter of '%s': %w", kid, err)
+	case len(v) != counterSize:
+		return nil, fmt.Errorf("nonce: invalid counter of '%s'", kid)
+	default:
+		// the counters reserved by a previous run may have been used.
+		c.next = binary.BigEndian.Uint64(v)
+		c.reserved = c.next
+	}
+
+	m.counters[kid] = c
+
+	return c, nil
+}
+
+// reserve persists the reservation of the next block of counters of kid.
+func (m *Manager) reserve(kid string, c *counter) error {
+	if c.next == math.MaxUint64 {
+		return fmt.Errorf("nonce: key '%s': %w", kid, ErrCounterExhausted)
+	}
+
+	reserved := c.next + m.reservation
+	if reserved < c.next {
+		reserved = math.MaxUint64
+	}
+
+	v := make([]byte, counterSize)
+	binary.BigEndian.PutUint64(v, reserved)
+
+	if err := m.store.Put(m.storeKey(kid), v); err != nil {
+		return fmt.Errorf("nonce: reserve counters of '%s': %w", kid, err)
+	}
+
+	c.reserved = reserved
+
+	return nil
+}
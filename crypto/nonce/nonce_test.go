@@ -0,0 +1,190 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package nonce
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	mockstorage "github.com/trustbloc/kms-go/internal/mock/storage"
+)
+
+func TestManager(t *testing.T) {
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	kh, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+	require.NoError(t, err)
+
+	t.Run("encrypts with unique nonces", func(t *testing.T) {
+		m, err := NewManager(c, mockstorage.NewMockStoreProvider(), WithReservation(3))
+		require.NoError(t, err)
+
+		seen := map[string]struct{}{}
+
+		for i := 0; i < 10; i++ {
+			ct, nonce, err := m.Encrypt([]byte("message"), []byte("aad"), kh, "key1")
+			require.NoError(t, err)
+			require.Len(t, nonce, defaultNonceSize)
+			require.Equal(t, uint64(i), binary.BigEndian.Uint64(nonce[InstanceIDSize:]))
+
+			_, ok := seen[string(nonce)]
+			require.False(t, ok)
+
+			seen[string(nonce)] = struct{}{}
+
+			pt, err := c.DecryptDetached(ct, []byte("aad"), nonce, kh)
+			require.NoError(t, err)
+			require.Equal(t, []byte("message"), pt)
+		}
+
+		nonce, err := m.Next("key2")
+		require.NoError(t, err)
+		require.Equal(t, make([]byte, defaultNonceSize), nonce)
+	})
+
+	t.Run("restart skips the reserved counters", func(t *testing.T) {
+		p := mockstorage.NewMockStoreProvider()
+
+		m, err := NewManager(c, p, WithReservation(100))
+		require.NoError(t, err)
+
+		for i := 0; i < 5; i++ {
+			_, err = m.Next("key1")
+			require.NoError(t, err)
+		}
+
+		m, err = NewManager(c, p, WithReservation(100))
+		require.NoError(t, err)
+
+		nonce, err := m.Next("key1")
+		require.NoError(t, err)
+		require.Equal(t, uint64(100), binary.BigEndian.Uint64(nonce[InstanceIDSize:]))
+	})
+
+	t.Run("instances sharing the store", func(t *testing.T) {
+		p := mockstorage.NewMockStoreProvider()
+
+		m1, err := NewManager(c, p, WithInstanceID([InstanceIDSize]byte{0, 0, 0, 1}))
+		require.NoError(t, err)
+
+		m2, err := NewManager(c, p, WithInstanceID([InstanceIDSize]byte{0, 0, 0, 2}))
+		require.NoError(t, err)
+
+		var (
+			wg     sync.WaitGroup
+			mu     sync.Mutex
+			nonces = map[string]struct{}{}
+		)
+
+		for _, m := range []*Manager{m1, m2, m1, m2} {
+			wg.Add(1)
+
+			go func(m *Manager) {
+				defer wg.Done()
+
+				for i := 0; i < 500; i++ {
+					nonce, e := m.Next("key1")
+					require.NoError(t, e)
+
+					mu.Lock()
+					nonces[string(nonce)] = struct{}{}
+					mu.Unlock()
+				}
+			}(m)
+		}
+
+		wg.Wait()
+		require.Len(t, nonces, 2000)
+	})
+
+	t.Run("XChaCha20Poly1305 nonces", func(t *testing.T) {
+		xkh, err := keyset.NewHandle(aead.XChaCha20Poly1305KeyTemplate())
+		require.NoError(t, err)
+
+		m, err := NewManager(c, mockstorage.NewMockStoreProvider(), WithNonceSize(24))
+		require.NoError(t, err)
+
+		ct, nonce, err := m.Encrypt([]byte("message"), nil, xkh, "xkey")
+		require.NoError(t, err)
+		require.Len(t, nonce, 24)
+
+		pt, err := c.DecryptDetached(ct, nil, nonce, xkh)
+		require.NoError(t, err)
+		require.Equal(t, []byte("message"), pt)
+	})
+
+	t.Run("counter exhausted", func(t *testing.T) {
+		p := mockstorage.NewMockStoreProvider()
+
+		v := make([]byte, counterSize)
+		binary.BigEndian.PutUint64(v, math.MaxUint64-2)
+		require.NoError(t, p.Store.Put("key1/00000000", v))
+
+		m, err := NewManager(c, p)
+		require.NoError(t, err)
+
+		_, err = m.Next("key1")
+		require.NoError(t, err)
+
+		_, err = m.Next("key1")
+		require.NoError(t, err)
+
+		_, err = m.Next("key1")
+		require.ErrorIs(t, err, ErrCounterExhausted)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewManager(c, mockstorage.NewMockStoreProvider(), WithNonceSize(8))
+		require.EqualError(t, err, "nonce: nonce size 8 is smaller than 12")
+
+		_, err = NewManager(c, mockstorage.NewMockStoreProvider(), WithReservation(0))
+		require.EqualError(t, err, "nonce: reservation must be positive")
+
+		p := mockstorage.NewMockStoreProvider()
+		p.ErrOpenStoreHandle = errors.New("open failed")
+
+		_, err = NewManager(c, p)
+		require.EqualError(t, err, "nonce: open store: open failed")
+
+		p = mockstorage.NewMockStoreProvider()
+
+		m, err := NewManager(c, p)
+		require.NoError(t, err)
+
+		_, err = m.Next("")
+		require.EqualError(t, err, "nonce: key ID is empty")
+
+		p.Store.ErrGet = errors.New("get failed")
+
+		_, err = m.Next("key1")
+		require.EqualError(t, err, "nonce: get counter of 'key1': get failed")
+
+		p.Store.ErrGet = nil
+		p.Store.ErrPut = errors.New("put failed")
+
+		_, err = m.Next("key1")
+		require.EqualError(t, err, "nonce: reserve counters of 'key1': put failed")
+
+		p.Store.ErrPut = nil
+		require.NoError(t, p.Store.Put("key2/00000000", []byte{1}))
+
+		_, err = m.Next("key2")
+		require.EqualError(t, err, "nonce: invalid counter of 'key2'")
+
+		_, _, err = m.Encrypt([]byte("message"), nil, "not a key handle", "key1")
+		require.ErrorContains(t, err, "nonce: encrypt")
+	})
+}
@@ -0,0 +1,246 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package signer adapts the ECDSA, RSA and Ed25519 keys of a KMS to crypto.Signer, so the keys can sign TLS handshakes
+// with a tls.Certificate, or X.509 certificates and certificate requests with the x509 package.
+//
+// A crypto.Signer signs digests while the Crypto services sign messages: the Signer signs with the private key of
+// the local keyset of the KMS key. The private key is held by the Signer and never returned, the keys of a remote
+// KMS are not supported.
+package signer
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
+	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+	rsapb "github.com/google/tink/go/proto/rsa_ssa_pkcs1_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/rsapss"
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+const (
+	ecdsaSignerTypeURL       = "type.googleapis.com/google.crypto.tink.EcdsaPrivateKey"
+	rsaSSAPKCS1SignerTypeURL = "type.googleapis.com/google.crypto.tink.RsaSsaPkcs1PrivateKey"
+	ed25519SignerTypeURL     = "type.googleapis.com/google.crypto.tink.Ed25519PrivateKey"
+)
+
+// Signer is a crypto.Signer of a KMS key.
+type Signer struct {
+	keyID string
+	key   crypto.Signer
+}
+
+var _ crypto.Signer = (*Signer)(nil)
+
+// New creates a new Signer of the key keyID of km: an ECDSA P-256, P-384 or P-521 key, an RSA key (RSARS256Type or
+// RSAPS256Type) or an Ed25519 key.
+func New(km kms.KeyManager, keyID string) (*Signer, error) {
+	kh, err := km.Get(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("signer: get key: %w", err)
+	}
+
+	key, err := privateKey(kh)
+	if err != nil {
+		return nil, fmt.Errorf("signer: %w", err)
+	}
+
+	return &Signer{keyID: keyID, key: key}, nil
+}
+
+// KeyID returns the KMS key ID of the Signer.
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
+
+// Public returns the public key of the Signer: an *ecdsa.PublicKey, an *rsa.PublicKey or an ed25519.PublicKey.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.key.Public()
+}
+
+// Sign signs digest, computed with opts.HashFunc(). It returns an ASN.1 DER signature for ECDSA keys, a PKCS #1 v1.5
+// signature for RSA keys, or a PSS signature if opts is an *rsa.PSSOptions. Ed25519 keys sign the message itself,
+// with the hash function 0.
+func (s *Signer) Sign(random io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if h := opts.HashFunc(); h != 0 && (!h.Available() || len(digest) != h.Size()) {
+		return nil, fmt.Errorf("signer: sign: invalid digest of hash function %s", h)
+	}
+
+	sig, err := s.key.Sign(random, digest, opts)
+	if err != nil {
+		return nil, fmt.Errorf("signer: sign: %w", err)
+	}
+
+	return sig, nil
+}
+
+// TLSCertificate returns a tls.Certificate with the Signer as private key, for the tls.Config of the clients or the
+// servers. certChain is the DER certificate chain, the first certificate must be the certificate of the key.
+func (s *Signer) TLSCertificate(certChain ...[]byte) (tls.Certificate, error) {
+	if len(certChain) == 0 {
+		return tls.Certificate{}, errors.New("signer: tls certificate: missing certificate")
+	}
+
+	leaf, err := x509.ParseCertificate(certChain[0])
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("signer: tls certificate: parse certificate: %w", err)
+	}
+
+	pub, ok := leaf.PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !pub.Equal(s.Public()) {
+		return tls.Certificate{}, errors.New("signer: tls certificate: certificate public key does not match the key")
+	}
+
+	return tls.Certificate{
+		Certificate: certChain,
+		PrivateKey:  s,
+		Leaf:        leaf,
+	}, nil
+}
+
+// privateKey returns the private key of the primary key of the keyset kh.
+func privateKey(kh interface{}) (crypto.Signer, error) {
+	handle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errors.New("key is not a keyset handle")
+	}
+
+	ks := insecurecleartextkeyset.KeysetMaterial(handle)
+
+	for _, k := range ks.Key {
+		if k.KeyId != ks.PrimaryKeyId || k.Status != tinkpb.KeyStatusType_ENABLED {
+			continue
+		}
+
+		switch k.KeyData.TypeUrl {
+		case ecdsaSignerTypeURL:
+			return ecdsaKey(k.KeyData.Value)
+		case rsaSSAPKCS1SignerTypeURL, rsapss.SignerTypeURL:
+			return rsaKey(k.KeyData.Value)
+		case ed25519SignerTypeURL:
+			return ed25519Key(k.KeyData.Value)
+		default:
+			return nil, fmt.Errorf("key type '%s' is not supported", k.KeyData.TypeUrl)
+		}
+	}
+
+	return nil, errors.New("primary key not found")
+}
+
+func ecdsaKey(serialized []byte) (*ecdsa.PrivateKey, error) {
+	key := &ecdsapb.EcdsaPrivateKey{}
+
+	if proto.Unmarshal(serialized, key) != nil || key.PublicKey.GetParams() == nil {
+		return nil, errors.New("key is not an ECDSA private key")
+	}
+
+	var (
+		crv   elliptic.Curve
+		ecdhC ecdh.Curve
+	)
+
+	switch key.PublicKey.Params.Curve { //nolint:exhaustive
+	case commonpb.EllipticCurveType_NIST_P256:
+		crv, ecdhC = elliptic.P256(), ecdh.P256()
+	case commonpb.EllipticCurveType_NIST_P384:
+		crv, ecdhC = elliptic.P384(), ecdh.P384()
+	case commonpb.EllipticCurveType_NIST_P521:
+		crv, ecdhC = elliptic.P521(), ecdh.P521()
+	default:
+		return nil, fmt.Errorf("ECDSA curve '%s' is not supported", key.PublicKey.Params.Curve)
+	}
+
+	size := (crv.Params().BitSize + 7) / 8 //nolint:gomnd // bits to bytes
+
+	d := new(big.Int).SetBytes(key.KeyValue)
+	if d.BitLen() > crv.Params().BitSize {
+		return nil, errors.New("invalid ECDSA private key")
+	}
+
+	// the public key is computed from the private key, it must match the public key of the keyset.
+	priv, err := ecdhC.NewPrivateKey(d.FillBytes(make([]byte, size)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ECDSA private key: %w", err)
+	}
+
+	point := priv.PublicKey().Bytes()
+	x, y := new(big.Int).SetBytes(point[1:1+size]), new(big.Int).SetBytes(point[1+size:])
+
+	if x.Cmp(new(big.Int).SetBytes(key.PublicKey.X)) != 0 || y.Cmp(new(big.Int).SetBytes(key.PublicKey.Y)) != 0 {
+		return nil, errors.New("invalid ECDSA private key: public key mismatch")
+	}
+
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{Curve: crv, X: x, Y: y},
+		D:         d,
+	}, nil
+}
+
+func rsaKey(serialized []byte) (*rsa.PrivateKey, error) {
+	key := &rsapb.RsaSsaPkcs1PrivateKey{}
+
+	if proto.Unmarshal(serialized, key) != nil || key.PublicKey == nil {
+		return nil, errors.New("key is not an RSA private key")
+	}
+
+	e := new(big.Int).SetBytes(key.PublicKey.E)
+	if !e.IsInt64() || e.Int64() > math.MaxInt32 {
+		return nil, errors.New("invalid RSA public exponent")
+	}
+
+	priv := &rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{
+			N: new(big.Int).SetBytes(key.PublicKey.N),
+			E: int(e.Int64()),
+		},
+		D:      new(big.Int).SetBytes(key.D),
+		Primes: []*big.Int{new(big.Int).SetBytes(key.P), new(big.Int).SetBytes(key.Q)},
+	}
+
+	if err := priv.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid RSA private key: %w", err)
+	}
+
+	priv.Precompute()
+
+	return priv, nil
+}
+
+func ed25519Key(serialized []byte) (ed25519.PrivateKey, error) {
+	key := &ed25519pb.Ed25519PrivateKey{}
+
+	if proto.Unmarshal(serialized, key) != nil || len(key.KeyValue) != ed25519.SeedSize {
+		return nil, errors.New("key is not an Ed25519 private key")
+	}
+
+	priv := ed25519.NewKeyFromSeed(key.KeyValue)
+
+	if key.PublicKey != nil && !bytes.Equal(priv[ed25519.SeedSize:], key.PublicKey.KeyValue) {
+		return nil, errors.New("invalid Ed25519 private key: public key mismatch")
+	}
+
+	return priv, nil
+}
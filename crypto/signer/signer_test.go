@@ -0,0 +1,206 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package signer_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/signer"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func TestSign(t *testing.T) {
+	km := mockkms.NewForTest(t)
+	digest := sha256.Sum256([]byte("message"))
+
+	for _, kt := range []kmsapi.KeyType{
+		kmsapi.ECDSAP256TypeDER, kmsapi.ECDSAP384TypeIEEEP1363, kmsapi.ECDSAP521TypeDER,
+		kmsapi.RSARS256Type, kmsapi.RSAPS256Type, kmsapi.ED25519Type,
+	} {
+		t.Run(string(kt), func(t *testing.T) {
+			keyID, _, err := km.CreateAndExportPubKeyBytes(kt)
+			require.NoError(t, err)
+
+			s, err := signer.New(km, keyID)
+			require.NoError(t, err)
+			require.Equal(t, keyID, s.KeyID())
+
+			switch pub := s.Public().(type) {
+			case *ecdsa.PublicKey:
+				sig, err := s.Sign(rand.Reader, digest[:], crypto.SHA256)
+				require.NoError(t, err)
+				require.True(t, ecdsa.VerifyASN1(pub, digest[:], sig))
+			case *rsa.PublicKey:
+				sig, err := s.Sign(rand.Reader, digest[:], crypto.SHA256)
+				require.NoError(t, err)
+				require.NoError(t, rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig))
+
+				pssOpts := &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA256}
+
+				sig, err = s.Sign(rand.Reader, digest[:], pssOpts)
+				require.NoError(t, err)
+				require.NoError(t, rsa.VerifyPSS(pub, crypto.SHA256, digest[:], sig, pssOpts))
+			case ed25519.PublicKey:
+				sig, err := s.Sign(rand.Reader, []byte("message"), crypto.Hash(0))
+				require.NoError(t, err)
+				require.True(t, ed25519.Verify(pub, []byte("message"), sig))
+			default:
+				require.Failf(t, "unexpected public key", "%T", pub)
+			}
+
+			// the certificates signed by the Signer are verified with the public key of the KMS key.
+			cert := selfSignedCertificate(t, s)
+			require.NoError(t, cert.CheckSignatureFrom(cert))
+		})
+	}
+}
+
+func TestSignErrors(t *testing.T) {
+	km := mockkms.NewForTest(t)
+
+	keyID, _, err := km.CreateAndExportPubKeyBytes(kmsapi.ECDSAP256TypeDER)
+	require.NoError(t, err)
+
+	s, err := signer.New(km, keyID)
+	require.NoError(t, err)
+
+	_, err = s.Sign(rand.Reader, []byte("not a digest"), crypto.SHA256)
+	require.EqualError(t, err, "signer: sign: invalid digest of hash function SHA-256")
+
+	digest := sha512.Sum384([]byte("message"))
+
+	_, err = s.Sign(rand.Reader, digest[:], crypto.SHA256)
+	require.Error(t, err)
+
+	_, err = signer.New(km, "missing")
+	require.ErrorContains(t, err, "signer: get key:")
+
+	for _, kt := range []kmsapi.KeyType{kmsapi.AES256GCMType, kmsapi.ECDSASecp256k1TypeIEEEP1363} {
+		keyID, _, err = km.Create(kt)
+		require.NoError(t, err)
+
+		_, err = signer.New(km, keyID)
+		require.ErrorContains(t, err, "is not supported")
+	}
+}
+
+func TestTLSCertificate(t *testing.T) {
+	km := mockkms.NewForTest(t)
+
+	serverSigner := newSigner(t, km, kmsapi.ECDSAP256TypeDER)
+	clientSigner := newSigner(t, km, kmsapi.RSAPS256Type)
+
+	serverCert := selfSignedCertificate(t, serverSigner)
+	clientCert := selfSignedCertificate(t, clientSigner)
+
+	serverTLSCert, err := serverSigner.TLSCertificate(serverCert.Raw)
+	require.NoError(t, err)
+	require.Equal(t, serverCert, serverTLSCert.Leaf)
+
+	clientTLSCert, err := clientSigner.TLSCertificate(clientCert.Raw)
+	require.NoError(t, err)
+
+	serverPool, clientPool := x509.NewCertPool(), x509.NewCertPool()
+	serverPool.AddCert(clientCert)
+	clientPool.AddCert(serverCert)
+
+	for _, version := range []uint16{tls.VersionTLS12, tls.VersionTLS13} {
+		clientConn, serverConn := net.Pipe()
+
+		server := tls.Server(serverConn, &tls.Config{
+			Certificates: []tls.Certificate{serverTLSCert},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    serverPool,
+			MinVersion:   version,
+			MaxVersion:   version,
+		})
+
+		client := tls.Client(clientConn, &tls.Config{
+			Certificates: []tls.Certificate{clientTLSCert},
+			RootCAs:      clientPool,
+			ServerName:   "localhost",
+			MinVersion:   version,
+			MaxVersion:   version,
+		})
+
+		errs := make(chan error, 1)
+
+		go func() {
+			errs <- server.Handshake()
+		}()
+
+		require.NoError(t, client.Handshake())
+		require.NoError(t, <-errs)
+
+		require.Equal(t, version, client.ConnectionState().Version)
+		require.Equal(t, clientCert, server.ConnectionState().PeerCertificates[0])
+
+		require.NoError(t, clientConn.Close())
+		require.NoError(t, serverConn.Close())
+	}
+
+	_, err = serverSigner.TLSCertificate()
+	require.EqualError(t, err, "signer: tls certificate: missing certificate")
+
+	_, err = serverSigner.TLSCertificate([]byte("not a certificate"))
+	require.ErrorContains(t, err, "signer: tls certificate: parse certificate:")
+
+	_, err = serverSigner.TLSCertificate(clientCert.Raw)
+	require.EqualError(t, err, "signer: tls certificate: certificate public key does not match the key")
+}
+
+func newSigner(t *testing.T, km kmsapi.KeyManager, kt kmsapi.KeyType) *signer.Signer {
+	t.Helper()
+
+	keyID, _, err := km.CreateAndExportPubKeyBytes(kt)
+	require.NoError(t, err)
+
+	s, err := signer.New(km, keyID)
+	require.NoError(t, err)
+
+	return s
+}
+
+func selfSignedCertificate(t *testing.T, s *signer.Signer) *x509.Certificate {
+	t.Helper()
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, s.Public(), s)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert
+}
@@ -0,0 +1,98 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/keyset"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aeskw"
+)
+
+// AES key wrap (RFC 3394) algorithms, set by the size of the key encryption key.
+const (
+	A128KWAlg = "A128KW"
+	A192KWAlg = "A192KW"
+	A256KWAlg = "A256KW"
+)
+
+//nolint:gochecknoglobals
+var aesKWAlgs = map[int]string{
+	16: A128KWAlg, //nolint:gomnd
+	24: A192KWAlg, //nolint:gomnd
+	32: A256KWAlg, //nolint:gomnd
+}
+
+var _ cryptoapi.KEKWrapper = (*Crypto)(nil)
+
+// WrapKeyWithKEK wraps key with the AES-KW key encryption key kek (an AES128KW, AES192KW or AES256KW key handle),
+// without ECDH key agreement. key can be any key material of a size multiple of 8 bytes, 16 bytes or more.
+// The result is unwrapped by UnwrapKey with the same kek.
+// returns:
+//
+//	RecipientWrappedKey with the wrapped key and the A128KW, A192KW or A256KW alg matching the kek size
+//	error in case of errors
+func (t *Crypto) WrapKeyWithKEK(key []byte, kek interface{}) (*cryptoapi.RecipientWrappedKey, error) {
+	w, err := newAESKeyWrapper(kek)
+	if err != nil {
+		return nil, fmt.Errorf("wrapKeyWithKEK: %w", err)
+	}
+
+	wk, err := w.Wrap(key)
+	if err != nil {
+		return nil, fmt.Errorf("wrapKeyWithKEK: %w", err)
+	}
+
+	recWK := &cryptoapi.RecipientWrappedKey{EncryptedCEK: wk, Alg: aesKWAlgs[w.KeySize()]}
+
+	if err = t.checkProfileWrappedKey(recWK); err != nil {
+		return nil, fmt.Errorf("wrapKeyWithKEK: %w", err)
+	}
+
+	return recWK, nil
+}
+
+// unwrapAESKW unwraps encKey with the AES-KW key encryption key kek of the alg size.
+func unwrapAESKW(alg string, encKey []byte, kek interface{}) ([]byte, error) {
+	w, err := newAESKeyWrapper(kek)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapKey: %w", err)
+	}
+
+	if aesKWAlgs[w.KeySize()] != alg {
+		return nil, fmt.Errorf("unwrapKey: %s key encryption key can't unwrap %s keys", aesKWAlgs[w.KeySize()], alg)
+	}
+
+	key, err := w.Unwrap(encKey)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapKey: %w", err)
+	}
+
+	return key, nil
+}
+
+func newAESKeyWrapper(kek interface{}) (aeskw.KeyWrapper, error) {
+	kh, ok := kek.(*keyset.Handle)
+	if !ok {
+		return nil, errBadKeyHandleFormat
+	}
+
+	w, err := aeskw.New(kh)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok = aesKWAlgs[w.KeySize()]; !ok {
+		return nil, errors.New("invalid AES-KW key size")
+	}
+
+	return w, nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+//nolint:gochecknoglobals
+var (
+	signOps = []kms.Operation{kms.OperationSign, kms.OperationVerify}
+	aeadOps = []kms.Operation{kms.OperationEncrypt, kms.OperationDecrypt, kms.OperationDeriveKey}
+	macOps  = []kms.Operation{
+		kms.OperationComputeMAC, kms.OperationVerifyMAC, kms.OperationComputePRF, kms.OperationDeriveKey,
+	}
+	kwOps    = []kms.Operation{kms.OperationWrapKey, kms.OperationUnwrapKey}
+	ecdhOps  = []kms.Operation{kms.OperationWrapKey, kms.OperationUnwrapKey, kms.OperationComputeDH}
+	aesKWOps = []kms.Operation{kms.OperationWrapKey, kms.OperationUnwrapKey, kms.OperationDeriveKey}
+	bbsOps   = []kms.Operation{
+		kms.OperationSignMulti, kms.OperationVerifyMulti, kms.OperationDeriveProof, kms.OperationVerifyProof,
+	}
+
+	nistPKWAlgs  = []string{ECDHESA256KWAlg, ECDH1PUA128KWAlg, ECDH1PUA192KWAlg, ECDH1PUA256KWAlg, ECDHESAlg}
+	x25519KWAlgs = []string{ECDHESXC20PKWAlg, ECDH1PUXC20PKWAlg, ECDHESAlg}
+	x448KWAlgs   = []string{ECDHESA256KWAlg, ECDHESXC20PKWAlg, ECDHESAlg}
+
+	capabilities = kms.Capabilities{
+		KeyTypes: []kms.KeyCapability{
+			{KeyType: kms.AES128GCMType, Algorithms: []string{"A128GCM"}, Operations: aeadOps},
+			{KeyType: kms.AES256GCMType, Algorithms: []string{"A256GCM"}, Operations: aeadOps},
+			{KeyType: kms.AES256GCMNoPrefixType, Algorithms: []string{"A256GCM"}, Operations: aeadOps},
+			{KeyType: kms.ChaCha20Poly1305Type, Algorithms: []string{"C20P"}, Operations: aeadOps},
+			{KeyType: kms.XChaCha20Poly1305Type, Algorithms: []string{"XC20P"}, Operations: aeadOps},
+			{KeyType: kms.AESGCMSIV256Type, Algorithms: []string{"AES-256-GCM-SIV"}, Operations: aeadOps},
+			{KeyType: kms.HMACSHA256Tag256Type, Algorithms: []string{"HS256"}, Operations: macOps},
+			{KeyType: kms.HMACSHA384Tag384Type, Algorithms: []string{"HS384"}, Operations: macOps},
+			{KeyType: kms.HMACSHA512Tag512Type, Algorithms: []string{"HS512"}, Operations: macOps},
+			{KeyType: kms.ECDSAP256TypeDER, Algorithms: []string{"ES256"}, Operations: signOps},
+			{KeyType: kms.ECDSAP384TypeDER, Algorithms: []string{"ES384"}, Operations: signOps},
+			{KeyType: kms.ECDSAP521TypeDER, Algorithms: []string{"ES512"}, Operations: signOps},
+			{KeyType: kms.ECDSAP256TypeIEEEP1363, Algorithms: []string{"ES256"}, Operations: signOps},
+			{KeyType: kms.ECDSAP384TypeIEEEP1363, Algorithms: []string{"ES384"}, Operations: signOps},
+			{KeyType: kms.ECDSAP521TypeIEEEP1363, Algorithms: []string{"ES512"}, Operations: signOps},
+			{KeyType: kms.ECDSASecp256k1TypeIEEEP1363, Algorithms: []string{"ES256K"}, Operations: signOps},
+			{KeyType: kms.BIP340Secp256k1Type, Algorithms: []string{"BIP340"}, Operations: signOps},
+			{KeyType: kms.ED25519Type, Algorithms: []string{"EdDSA"}, Operations: signOps},
+			{KeyType: kms.RSARS256Type, Algorithms: []string{"RS256"}, Operations: signOps},
+			{KeyType: kms.RSAPS256Type, Algorithms: []string{"PS256"}, Operations: signOps},
+			{KeyType: kms.RSAOAEP256Type, Algorithms: []string{RSAOAEP256Alg}, Operations: kwOps},
+			{KeyType: kms.AES128KWType, Algorithms: []string{A128KWAlg}, Operations: aesKWOps},
+			{KeyType: kms.AES192KWType, Algorithms: []string{A192KWAlg}, Operations: aesKWOps},
+			{KeyType: kms.AES256KWType, Algorithms: []string{A256KWAlg}, Operations: aesKWOps},
+			{KeyType: kms.NISTP256ECDHKWType, Algorithms: nistPKWAlgs, Operations: ecdhOps},
+			{KeyType: kms.NISTP384ECDHKWType, Algorithms: nistPKWAlgs, Operations: ecdhOps},
+			{KeyType: kms.NISTP521ECDHKWType, Algorithms: nistPKWAlgs, Operations: ecdhOps},
+			{KeyType: kms.X25519ECDHKWType, Algorithms: x25519KWAlgs, Operations: ecdhOps},
+			{KeyType: kms.X448ECDHKWType, Algorithms: x448KWAlgs, Operations: ecdhOps},
+			{KeyType: kms.BLS12381G2Type, Algorithms: []string{"BBS+"}, Operations: bbsOps},
+		},
+		ContentEncryption: []string{
+			"A256GCM", "A192GCM", "A128GCM", "XC20P", "A128CBC-HS256", "A192CBC-HS384", "A256CBC-HS384",
+			"A256CBC-HS512",
+		},
+	}
+)
+
+// Capabilities returns the key types, algorithms and crypto operations supported by Crypto, and the JWE content
+// encryption algorithms of its composite (ECDH) primitives. kms.OperationComputeDH is only listed WithComputeDH.
+func (t *Crypto) Capabilities() (*kms.Capabilities, error) {
+	// return a copy so callers can't alter the advertised capabilities.
+	caps := kms.MergeCapabilities(&capabilities)
+
+	if t.dhEnabled {
+		return caps, nil
+	}
+
+	for i := range caps.KeyTypes {
+		ops := caps.KeyTypes[i].Operations[:0]
+
+		for _, op := range caps.KeyTypes[i].Operations {
+			if op != kms.OperationComputeDH {
+				ops = append(ops, op)
+			}
+		}
+
+		caps.KeyTypes[i].Operations = ops
+	}
+
+	return caps, nil
+}
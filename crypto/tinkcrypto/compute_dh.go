@@ -0,0 +1,125 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/circl/dh/x448"
+	hybrid "github.com/google/tink/go/hybrid/subtle"
+	"github.com/google/tink/go/keyset"
+	"golang.org/x/crypto/curve25519"
+
+	"github.com/trustbloc/kms-go/internal/memguard"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/kms/audit"
+)
+
+var _ cryptoapi.DHComputer = (*Crypto)(nil)
+
+// ComputeDH computes the raw shared secret of the NIST P curve, X25519 or X448 ECDH-KW private key in kh and pubKey:
+// the X coordinate of the shared point for NIST P curves (as per SEC 1, section 3.3.1) or the X25519/X448 function
+// output (RFC 7748). X25519 and X448 public keys of a small order are rejected. It fails with
+// cryptoapi.ErrComputeDHDisabled unless Crypto is created WithComputeDH.
+func (t *Crypto) ComputeDH(kh interface{}, pubKey *cryptoapi.PublicKey) ([]byte, error) {
+	start := time.Now()
+	z, err := t.computeDH(kh, pubKey)
+
+	audit.Log(t.auditLogger, kmsapi.OperationComputeDH, pubKeyKID(pubKey), nil, start, err)
+
+	return z, err
+}
+
+func (t *Crypto) computeDH(kh interface{}, pubKey *cryptoapi.PublicKey) ([]byte, error) {
+	if !t.dhEnabled {
+		return nil, fmt.Errorf("computeDH: %w (see WithComputeDH)", cryptoapi.ErrComputeDHDisabled)
+	}
+
+	keyHandle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errBadKeyHandleFormat
+	}
+
+	if pubKey == nil {
+		return nil, errors.New("computeDH: public key is nil")
+	}
+
+	if err := t.checkHandle(keyHandle); err != nil {
+		return nil, err
+	}
+
+	privKey, err := extractPrivKey(keyHandle)
+	if err != nil {
+		return nil, fmt.Errorf("computeDH: %w", err)
+	}
+
+	switch priv := privKey.(type) {
+	case *hybrid.ECPrivateKey:
+		return computeECDH(priv, pubKey)
+	case x448PrivKey:
+		defer memguard.Wipe(priv)
+
+		return computeX448(priv, pubKey)
+	case []byte:
+		defer memguard.Wipe(priv)
+
+		if !strings.EqualFold(pubKey.Curve, "X25519") || len(pubKey.X) != curve25519.PointSize {
+			return nil, errors.New("computeDH: public key is not an X25519 key")
+		}
+
+		z, err := curve25519.X25519(priv, pubKey.X)
+		if err != nil {
+			return nil, fmt.Errorf("computeDH: %w", err)
+		}
+
+		return z, nil
+	default:
+		return nil, fmt.Errorf("computeDH: unsupported private key type %T: %w", privKey, kmsapi.ErrInvalidKeyType)
+	}
+}
+
+func computeECDH(priv *hybrid.ECPrivateKey, pubKey *cryptoapi.PublicKey) ([]byte, error) {
+	curve, err := hybrid.GetCurve(pubKey.Curve)
+	if err != nil || curve != priv.PublicKey.Curve {
+		return nil, fmt.Errorf("computeDH: public key curve '%s' doesn't match the private key", pubKey.Curve)
+	}
+
+	point := &hybrid.ECPoint{X: new(big.Int).SetBytes(pubKey.X), Y: new(big.Int).SetBytes(pubKey.Y)}
+
+	// ComputeSharedSecret checks the point is on the curve.
+	z, err := hybrid.ComputeSharedSecret(point, priv)
+	if err != nil {
+		return nil, fmt.Errorf("computeDH: %w", err)
+	}
+
+	return z, nil
+}
+
+func computeX448(priv x448PrivKey, pubKey *cryptoapi.PublicKey) ([]byte, error) {
+	if !strings.EqualFold(pubKey.Curve, x448Crv) || len(pubKey.X) != x448.Size {
+		return nil, errors.New("computeDH: public key is not an X448 key")
+	}
+
+	var sk, pk, z x448.Key
+
+	defer memguard.Wipe(sk[:])
+
+	copy(sk[:], priv)
+	copy(pk[:], pubKey.X)
+
+	if !x448.Shared(&z, &sk, &pk) {
+		return nil, errors.New("computeDH: X448 public key of a small order")
+	}
+
+	return z[:], nil
+}
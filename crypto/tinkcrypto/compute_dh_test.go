@@ -0,0 +1,119 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/stretchr/testify/require"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/composite/ecdh"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/composite/keyio"
+)
+
+func TestCrypto_ComputeDH(t *testing.T) {
+	c, err := New(WithComputeDH())
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name string
+		kt   *tinkpb.KeyTemplate
+		size int
+	}{
+		{name: "P-256", kt: ecdh.NISTP256ECDHKWKeyTemplate(), size: 32},
+		{name: "P-521", kt: ecdh.NISTP521ECDHKWKeyTemplate(), size: 66},
+		{name: "X25519", kt: ecdh.X25519ECDHKWKeyTemplate(), size: 32},
+		{name: "X448", kt: ecdh.X448ECDHKWKeyTemplate(), size: 56},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			alice, err := keyset.NewHandle(tt.kt)
+			require.NoError(t, err)
+
+			bob, err := keyset.NewHandle(tt.kt)
+			require.NoError(t, err)
+
+			alicePub, err := keyio.ExtractPrimaryPublicKey(alice)
+			require.NoError(t, err)
+
+			bobPub, err := keyio.ExtractPrimaryPublicKey(bob)
+			require.NoError(t, err)
+
+			z1, err := c.ComputeDH(alice, bobPub)
+			require.NoError(t, err)
+			require.Len(t, z1, tt.size)
+
+			z2, err := c.ComputeDH(bob, alicePub)
+			require.NoError(t, err)
+			require.Equal(t, z1, z2)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		x25519KH, err := keyset.NewHandle(ecdh.X25519ECDHKWKeyTemplate())
+		require.NoError(t, err)
+
+		p256KH, err := keyset.NewHandle(ecdh.NISTP256ECDHKWKeyTemplate())
+		require.NoError(t, err)
+
+		p384KH, err := keyset.NewHandle(ecdh.NISTP384ECDHKWKeyTemplate())
+		require.NoError(t, err)
+
+		p384Pub, err := keyio.ExtractPrimaryPublicKey(p384KH)
+		require.NoError(t, err)
+
+		_, err = c.ComputeDH("kh", p384Pub)
+		require.ErrorIs(t, err, errBadKeyHandleFormat)
+
+		_, err = c.ComputeDH(p256KH, nil)
+		require.EqualError(t, err, "computeDH: public key is nil")
+
+		_, err = c.ComputeDH(p256KH, p384Pub)
+		require.EqualError(t, err, "computeDH: public key curve 'NIST_P384' doesn't match the private key")
+
+		_, err = c.ComputeDH(x25519KH, p384Pub)
+		require.EqualError(t, err, "computeDH: public key is not an X25519 key")
+
+		lowOrder := &cryptoapi.PublicKey{Type: "OKP", Curve: "X25519", X: make([]byte, 32)}
+
+		_, err = c.ComputeDH(x25519KH, lowOrder)
+		require.ErrorContains(t, err, "low order point")
+
+		pubKH, err := p256KH.Public()
+		require.NoError(t, err)
+
+		_, err = c.ComputeDH(pubKH, p384Pub)
+		require.ErrorContains(t, err, "computeDH: extractPrivKey")
+
+		caps, err := c.Capabilities()
+		require.NoError(t, err)
+		require.True(t, caps.Supports(kmsapi.X25519ECDHKWType, kmsapi.OperationComputeDH))
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		d, err := New()
+		require.NoError(t, err)
+
+		kh, err := keyset.NewHandle(ecdh.X25519ECDHKWKeyTemplate())
+		require.NoError(t, err)
+
+		pub, err := keyio.ExtractPrimaryPublicKey(kh)
+		require.NoError(t, err)
+
+		_, err = d.ComputeDH(kh, pub)
+		require.ErrorIs(t, err, cryptoapi.ErrComputeDHDisabled)
+
+		caps, err := d.Capabilities()
+		require.NoError(t, err)
+		require.False(t, caps.Supports(kmsapi.X25519ECDHKWType, kmsapi.OperationComputeDH))
+		require.True(t, caps.Supports(kmsapi.X25519ECDHKWType, kmsapi.OperationWrapKey))
+	})
+}
@@ -13,6 +13,9 @@ package tinkcrypto
 import (
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"time"
 
 	"github.com/google/tink/go/aead"
 	aeadsubtle "github.com/google/tink/go/aead/subtle"
@@ -22,7 +25,12 @@ import (
 	"github.com/google/tink/go/signature"
 	"golang.org/x/crypto/chacha20poly1305"
 
+	cryptopkg "github.com/trustbloc/kms-go/crypto"
+	"github.com/trustbloc/kms-go/internal/logutil"
+	"github.com/trustbloc/kms-go/kms/audit"
 	"github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/util/profiles"
 
 	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aead/subtle"
 	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/bbs"
@@ -46,7 +54,7 @@ const (
 	x25519ECDHKWPrivateKeyTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.X25519EcdhKwPrivateKey"
 )
 
-var errBadKeyHandleFormat = errors.New("bad key handle format")
+var errBadKeyHandleFormat = fmt.Errorf("bad key handle format: %w", kmsapi.ErrInvalidKeyType)
 
 // Package tinkcrypto includes the default implementation of pkg/crypto. It uses Tink for executing crypto primitives
 // and will be built as a framework option. It represents the main crypto service in the framework. `kh interface{}`
@@ -55,22 +63,108 @@ var errBadKeyHandleFormat = errors.New("bad key handle format")
 
 // Crypto is the default Crypto SPI implementation using Tink.
 type Crypto struct {
-	ecKW  keyWrapper
-	okpKW keyWrapper
+	ecKW        keyWrapper
+	okpKW       keyWrapper
+	legacy      LegacyFlags
+	auditLogger kmsapi.AuditLogger
+	randomness  io.Reader
+	profile     *profiles.Profile
+	logger      *slog.Logger
+	dhEnabled   bool
+}
+
+// LegacyFlags are compatibility flags allowing Crypto to unwrap keys wrapped by older aries-framework-go versions.
+type LegacyFlags uint
+
+const (
+	// LegacyECDH1PUNoTagKDF unwraps ECDH-1PU keys derived without the cctag in the KDF SuppPubInfo, as described in
+	// https://datatracker.ietf.org/doc/html/draft-madden-jose-ecdh-1pu-03 and used by aries-framework-go before the
+	// draft-04 KDF update. It is only tried when unwrapping with the current KDF fails.
+	LegacyECDH1PUNoTagKDF LegacyFlags = 1 << iota
+)
+
+// Opt is a Crypto option.
+type Opt func(*Crypto)
+
+// WithLegacyCompat enables the legacy compatibility flags on Crypto, flags can be combined (eg: flagA | flagB).
+func WithLegacyCompat(flags LegacyFlags) Opt {
+	return func(c *Crypto) {
+		c.legacy |= flags
+	}
+}
+
+// WithAuditLogger sets the audit logger recording the Crypto operations. Tink key handles don't carry key IDs, the
+// records identify the key only for key wrapping operations (the recipient KID).
+func WithAuditLogger(l kmsapi.AuditLogger) Opt {
+	return func(c *Crypto) {
+		c.auditLogger = l
+	}
+}
+
+// WithRandomness sets the randomness source of the ephemeral keys and nonces of key wrapping, eg: a deterministic
+// entropy.NewDeterministic reader to reproduce test vectors. r must be safe for concurrent use if Crypto is.
+func WithRandomness(r io.Reader) Opt {
+	return func(c *Crypto) {
+		c.randomness = r
+	}
+}
+
+// WithLogger sets the logger of the Crypto debug and warn events, eg: the keys unwrapped with a legacy compatibility
+// fallback. The key material is never logged. Without logger the events are discarded.
+func WithLogger(l *slog.Logger) Opt {
+	return func(c *Crypto) {
+		c.logger = l
+	}
+}
+
+// WithComputeDH enables ComputeDH, disabled by default: the raw shared secrets of the ECDH keys bypass the key
+// derivation of WrapKey and UnwrapKey, they must only be computed for protocols running their own key schedule (eg:
+// Noise). Key ID based callers should also restrict it to the keys of these protocols (see kms/keyref AllowComputeDH).
+func WithComputeDH() Opt {
+	return func(c *Crypto) {
+		c.dhEnabled = true
+	}
+}
+
+// log returns the logger of t, a discarding logger if it has none.
+func (t *Crypto) log() *slog.Logger {
+	return logutil.Logger(t.logger)
 }
 
 // New creates a new Crypto instance.
-func New() (*Crypto, error) {
-	return &Crypto{ecKW: &ecKWSupport{}, okpKW: &okpKWSupport{}}, nil
+func New(opts ...Opt) (*Crypto, error) {
+	c := &Crypto{}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.ecKW = &ecKWSupport{random: c.randomness}
+	c.okpKW = &okpKWSupport{random: c.randomness}
+
+	return c, nil
 }
 
 // Encrypt will encrypt msg using the implementation's corresponding encryption key and primitive in kh of a public key.
 func (t *Crypto) Encrypt(msg, aad []byte, kh interface{}) ([]byte, []byte, error) {
+	start := time.Now()
+	ct, nonce, err := t.encrypt(msg, aad, kh)
+
+	audit.Log(t.auditLogger, kmsapi.OperationEncrypt, "", nil, start, err)
+
+	return ct, nonce, err
+}
+
+func (t *Crypto) encrypt(msg, aad []byte, kh interface{}) ([]byte, []byte, error) {
 	keyHandle, ok := kh.(*keyset.Handle)
 	if !ok {
 		return nil, nil, errBadKeyHandleFormat
 	}
 
+	if err := t.checkHandle(keyHandle); err != nil {
+		return nil, nil, err
+	}
+
 	ps, err := keyHandle.Primitives()
 	if err != nil {
 		return nil, nil, fmt.Errorf("get primitives: %w", err)
@@ -103,6 +197,8 @@ func nonceSize(ps *primitiveset.PrimitiveSet) int {
 		ivSize = chacha20poly1305.NonceSizeX
 	case *aeadsubtle.AESGCM:
 		ivSize = aeadsubtle.AESGCMIVSize
+	case *aeadsubtle.AESGCMSIV:
+		ivSize = aeadsubtle.AESGCMSIVNonceSize
 	case *aeadsubtle.EncryptThenAuthenticate:
 		// AESCBC+HMACSHA Tink keys use Tink's EncryptThenAuthenticate AEAD primitive as per the CBC hmac key manager's
 		// Primitive() call.
@@ -117,11 +213,24 @@ func nonceSize(ps *primitiveset.PrimitiveSet) int {
 // Decrypt will decrypt cipher using the implementation's corresponding encryption key referenced by kh of
 // a private key.
 func (t *Crypto) Decrypt(cipher, aad, nonce []byte, kh interface{}) ([]byte, error) {
+	start := time.Now()
+	pt, err := t.decrypt(cipher, aad, nonce, kh)
+
+	audit.Log(t.auditLogger, kmsapi.OperationDecrypt, "", nil, start, err)
+
+	return pt, err
+}
+
+func (t *Crypto) decrypt(cipher, aad, nonce []byte, kh interface{}) ([]byte, error) {
 	keyHandle, ok := kh.(*keyset.Handle)
 	if !ok {
 		return nil, errBadKeyHandleFormat
 	}
 
+	if err := t.checkHandle(keyHandle); err != nil {
+		return nil, err
+	}
+
 	ps, err := keyHandle.Primitives()
 	if err != nil {
 		return nil, fmt.Errorf("get primitives: %w", err)
@@ -146,16 +255,29 @@ func (t *Crypto) Decrypt(cipher, aad, nonce []byte, kh interface{}) ([]byte, err
 		}
 	}
 
-	return nil, fmt.Errorf("decrypt cipher: decryption failed")
+	return nil, fmt.Errorf("decrypt cipher: decryption failed: %w", crypto.ErrAuthTagMismatch)
 }
 
 // Sign will sign msg using the implementation's corresponding signing key referenced by kh of a private key.
 func (t *Crypto) Sign(msg []byte, kh interface{}) ([]byte, error) {
+	start := time.Now()
+	sig, err := t.sign(msg, kh)
+
+	audit.Log(t.auditLogger, kmsapi.OperationSign, "", nil, start, err)
+
+	return sig, err
+}
+
+func (t *Crypto) sign(msg []byte, kh interface{}) ([]byte, error) {
 	keyHandle, ok := kh.(*keyset.Handle)
 	if !ok {
 		return nil, errBadKeyHandleFormat
 	}
 
+	if err := t.checkSigningHandle(keyHandle); err != nil {
+		return nil, err
+	}
+
 	signer, err := signature.NewSigner(keyHandle)
 	if err != nil {
 		return nil, fmt.Errorf("create new signer: %w", err)
@@ -169,14 +291,62 @@ func (t *Crypto) Sign(msg []byte, kh interface{}) ([]byte, error) {
 	return s, nil
 }
 
+// SignBatch signs every message of messages with the signing key of kh, creating the signer primitive once. The
+// signatures are returned in the order of messages, messages are signed concurrently with the WithParallelism option.
+func (t *Crypto) SignBatch(messages [][]byte, kh interface{}, opts ...crypto.SignBatchOpts) ([][]byte, error) {
+	start := time.Now()
+	sigs, err := t.signBatch(messages, kh, opts...)
+
+	audit.Log(t.auditLogger, kmsapi.OperationSign, "", nil, start, err)
+
+	return sigs, err
+}
+
+func (t *Crypto) signBatch(messages [][]byte, kh interface{}, opts ...crypto.SignBatchOpts) ([][]byte, error) {
+	keyHandle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errBadKeyHandleFormat
+	}
+
+	if err := t.checkSigningHandle(keyHandle); err != nil {
+		return nil, err
+	}
+
+	signer, err := signature.NewSigner(keyHandle)
+	if err != nil {
+		return nil, fmt.Errorf("create new signer: %w", err)
+	}
+
+	batchOpts := crypto.NewSignBatchOpt()
+
+	for _, opt := range opts {
+		opt(batchOpts)
+	}
+
+	return cryptopkg.SignEach(messages, batchOpts.Parallelism(), signer.Sign)
+}
+
 // Verify will verify sig signature of msg using the implementation's corresponding signing key referenced by kh of
 // a public key.
 func (t *Crypto) Verify(sig, msg []byte, kh interface{}) error {
+	start := time.Now()
+	err := t.verify(sig, msg, kh)
+
+	audit.Log(t.auditLogger, kmsapi.OperationVerify, "", nil, start, err)
+
+	return err
+}
+
+func (t *Crypto) verify(sig, msg []byte, kh interface{}) error {
 	keyHandle, ok := kh.(*keyset.Handle)
 	if !ok {
 		return errBadKeyHandleFormat
 	}
 
+	if err := t.checkSigningHandle(keyHandle); err != nil {
+		return err
+	}
+
 	verifier, err := signature.NewVerifier(keyHandle)
 	if err != nil {
 		return fmt.Errorf("create new verifier: %w", err)
@@ -193,11 +363,24 @@ func (t *Crypto) Verify(sig, msg []byte, kh interface{}) error {
 // ComputeMAC computes message authentication code (MAC) for code data
 // using a matching MAC primitive in kh key handle.
 func (t *Crypto) ComputeMAC(data []byte, kh interface{}) ([]byte, error) {
+	start := time.Now()
+	macBytes, err := t.computeMAC(data, kh)
+
+	audit.Log(t.auditLogger, kmsapi.OperationComputeMAC, "", nil, start, err)
+
+	return macBytes, err
+}
+
+func (t *Crypto) computeMAC(data []byte, kh interface{}) ([]byte, error) {
 	keyHandle, ok := kh.(*keyset.Handle)
 	if !ok {
 		return nil, errBadKeyHandleFormat
 	}
 
+	if err := t.checkHandle(keyHandle); err != nil {
+		return nil, err
+	}
+
 	macPrimitive, err := mac.New(keyHandle)
 	if err != nil {
 		return nil, err
@@ -209,17 +392,34 @@ func (t *Crypto) ComputeMAC(data []byte, kh interface{}) ([]byte, error) {
 // VerifyMAC determines if mac is a correct authentication code (MAC) for data
 // using a matching MAC primitive in kh key handle and returns nil if so, otherwise it returns an error.
 func (t *Crypto) VerifyMAC(macBytes, data []byte, kh interface{}) error {
+	start := time.Now()
+	err := t.verifyMAC(macBytes, data, kh)
+
+	audit.Log(t.auditLogger, kmsapi.OperationVerifyMAC, "", nil, start, err)
+
+	return err
+}
+
+func (t *Crypto) verifyMAC(macBytes, data []byte, kh interface{}) error {
 	keyHandle, ok := kh.(*keyset.Handle)
 	if !ok {
 		return errBadKeyHandleFormat
 	}
 
+	if err := t.checkHandle(keyHandle); err != nil {
+		return err
+	}
+
 	macPrimitive, err := mac.New(keyHandle)
 	if err != nil {
 		return err
 	}
 
-	return macPrimitive.VerifyMAC(macBytes, data)
+	if err = macPrimitive.VerifyMAC(macBytes, data); err != nil {
+		return fmt.Errorf("verify MAC: %w: %w", crypto.ErrAuthTagMismatch, err)
+	}
+
+	return nil
 }
 
 // WrapKey will do ECDH (ES or 1PU) key wrapping of cek using apu, apv and recipient public key 'recPubKey'.
@@ -239,19 +439,51 @@ func (t *Crypto) VerifyMAC(macBytes, data []byte, kh interface{}) error {
 //     `Curve25519`+`Concat KDF` as per https://tools.ietf.org/html/rfc7748#section-6.1
 //     (for recPubKey with X25519 curve).
 //
+// recPubKey of type RSA wraps cek with `RSA-OAEP-256` instead, apu, apv and the options don't apply.
+//
 // returns the resulting key wrapping info as *composite.RecipientWrappedKey or error in case of wrapping failure.
 func (t *Crypto) WrapKey(cek, apu, apv []byte, recPubKey *crypto.PublicKey,
+	wrapKeyOpts ...crypto.WrapKeyOpts) (*crypto.RecipientWrappedKey, error) {
+	start := time.Now()
+	wk, err := t.wrapKey(cek, apu, apv, recPubKey, wrapKeyOpts...)
+
+	if err == nil {
+		if err = t.checkProfileWrappedKey(wk); err != nil {
+			wk, err = nil, fmt.Errorf("wrapKey: %w", err)
+		}
+	}
+
+	var kid string
+
+	if recPubKey != nil {
+		kid = recPubKey.KID
+	}
+
+	audit.Log(t.auditLogger, kmsapi.OperationWrapKey, kid, nil, start, err)
+
+	return wk, err
+}
+
+func (t *Crypto) wrapKey(cek, apu, apv []byte, recPubKey *crypto.PublicKey,
 	wrapKeyOpts ...crypto.WrapKeyOpts) (*crypto.RecipientWrappedKey, error) {
 	if recPubKey == nil {
 		return nil, errors.New("wrapKey: recipient public key is required")
 	}
 
+	if recPubKey.Type == rsaKeyType {
+		return wrapRSAOAEP(cek, recPubKey)
+	}
+
 	pOpts := crypto.NewOpt()
 
 	for _, opt := range wrapKeyOpts {
 		opt(pOpts)
 	}
 
+	if err := checkFIPSWrap(recPubKey.Curve, pOpts.SenderKey() != nil, pOpts.UseXC20PKW()); err != nil {
+		return nil, fmt.Errorf("wrapKey: %w", err)
+	}
+
 	wk, err := t.deriveKEKAndWrap(cek, apu, apv, pOpts.Tag(), pOpts.SenderKey(), recPubKey, pOpts.EPK(),
 		pOpts.UseXC20PKW())
 	if err != nil {
@@ -276,7 +508,13 @@ func (t *Crypto) WrapKey(cek, apu, apv []byte, recPubKey *crypto.PublicKey,
 //   - `ECDH-1PU+XC20PKW` alg (XChacha20Poly1305, authcrypt using crypto.WithXC20PKW() with cek size=32).
 //   - KDF (based on recWk.EPK.KeyType): `Concat KDF` as per https://tools.ietf.org/html/rfc7518#section-4.6 (for type
 //     value as EC) or `Curve25519`+`Concat KDF` as per https://tools.ietf.org/html/rfc7748#section-6.1 (for type value
-//     as OKP, ie X25519 key).
+//     as OKP, ie X25519 key) or `Curve448`+`Concat KDF` as per https://tools.ietf.org/html/rfc7748#section-6.2 (for
+//     X448 OKP keys, ECDH-ES only).
+//   - `RSA-OAEP-256` alg with an RSA-OAEP recipientKH (no options).
+//   - `A128KW`, `A192KW` or `A256KW` algs (keys wrapped by WrapKeyWithKEK) with an AES-KW key encryption key as
+//     recipientKH (no options).
+//   - `ECDH-ES` alg (direct key agreement keys built by DeriveDirectKey, no options): the CEK is derived for
+//     recWK.Enc instead of being unwrapped.
 //
 // returns the resulting unwrapping key or error in case of unwrapping failure.
 //
@@ -296,11 +534,44 @@ func (t *Crypto) WrapKey(cek, apu, apv []byte, recPubKey *crypto.PublicKey,
 //
 // 4- recipientKH must contain the private key since unwrapping is usually done on the recipient side.
 func (t *Crypto) UnwrapKey(recWK *crypto.RecipientWrappedKey, recipientKH interface{},
+	wrapKeyOpts ...crypto.WrapKeyOpts) ([]byte, error) {
+	start := time.Now()
+	cek, err := t.unwrapKey(recWK, recipientKH, wrapKeyOpts...)
+
+	var kid string
+
+	if recWK != nil {
+		kid = recWK.KID
+	}
+
+	audit.Log(t.auditLogger, kmsapi.OperationUnwrapKey, kid, nil, start, err)
+
+	return cek, err
+}
+
+func (t *Crypto) unwrapKey(recWK *crypto.RecipientWrappedKey, recipientKH interface{},
 	wrapKeyOpts ...crypto.WrapKeyOpts) ([]byte, error) {
 	if recWK == nil {
 		return nil, fmt.Errorf("unwrapKey: RecipientWrappedKey is empty")
 	}
 
+	if err := checkFIPSUnwrap(recWK, recipientKH); err != nil {
+		return nil, fmt.Errorf("unwrapKey: %w", err)
+	}
+
+	if err := t.checkProfileUnwrap(recWK, recipientKH); err != nil {
+		return nil, fmt.Errorf("unwrapKey: %w", err)
+	}
+
+	switch recWK.Alg {
+	case RSAOAEP256Alg:
+		return unwrapRSAOAEP(recWK.EncryptedCEK, recipientKH)
+	case A128KWAlg, A192KWAlg, A256KWAlg:
+		return unwrapAESKW(recWK.Alg, recWK.EncryptedCEK, recipientKH)
+	case ECDHESAlg:
+		return t.deriveDirectKeyForUnwrap(recWK, recipientKH)
+	}
+
 	pOpts := crypto.NewOpt()
 
 	for _, opt := range wrapKeyOpts {
@@ -327,6 +598,10 @@ func (t *Crypto) SignMulti(messages [][]byte, signerKH interface{}) ([]byte, err
 		return nil, errBadKeyHandleFormat
 	}
 
+	if err := t.checkHandle(keyHandle); err != nil {
+		return nil, err
+	}
+
 	signer, err := bbs.NewSigner(keyHandle)
 	if err != nil {
 		return nil, fmt.Errorf("create new BBS+ signer: %w", err)
@@ -350,6 +625,10 @@ func (t *Crypto) VerifyMulti(messages [][]byte, bbsSignature []byte, signerPubKH
 		return errBadKeyHandleFormat
 	}
 
+	if err := t.checkHandle(keyHandle); err != nil {
+		return err
+	}
+
 	verifier, err := bbs.NewVerifier(keyHandle)
 	if err != nil {
 		return fmt.Errorf("create new BBS+ verifier: %w", err)
@@ -374,6 +653,10 @@ func (t *Crypto) VerifyProof(revealedMessages [][]byte, proof, nonce []byte, sig
 		return errBadKeyHandleFormat
 	}
 
+	if err := t.checkHandle(keyHandle); err != nil {
+		return err
+	}
+
 	verifier, err := bbs.NewVerifier(keyHandle)
 	if err != nil {
 		return fmt.Errorf("create new BBS+ verifier: %w", err)
@@ -400,6 +683,10 @@ func (t *Crypto) DeriveProof(messages [][]byte, bbsSignature, nonce []byte, reve
 		return nil, errBadKeyHandleFormat
 	}
 
+	if err := t.checkHandle(keyHandle); err != nil {
+		return nil, err
+	}
+
 	verifier, err := bbs.NewVerifier(keyHandle)
 	if err != nil {
 		return nil, fmt.Errorf("create new BBS+ verifier: %w", err)
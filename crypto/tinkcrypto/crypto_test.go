@@ -25,6 +25,7 @@ import (
 	chacha "golang.org/x/crypto/chacha20poly1305"
 
 	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/spi/kms"
 
 	"github.com/trustbloc/kms-go/crypto"
 	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aead"
@@ -111,7 +112,7 @@ func TestCrypto_EncryptDecrypt(t *testing.T) {
 
 			// decrypt with bad cipher - should fail
 			plainText, err = c.Decrypt([]byte("bad cipher"), aad, nonce, kh)
-			require.Error(t, err)
+			require.ErrorIs(t, err, cryptoapi.ErrAuthTagMismatch)
 			require.Empty(t, plainText)
 		})
 	}
@@ -275,6 +276,34 @@ func TestCrypto_SignVerify(t *testing.T) {
 	})
 }
 
+func TestCrypto_SignBatch(t *testing.T) {
+	kh, err := keyset.NewHandle(signature.ECDSAP256KeyWithoutPrefixTemplate())
+	require.NoError(t, err)
+
+	pubKH, err := kh.Public()
+	require.NoError(t, err)
+
+	msgs := [][]byte{[]byte("msg 1"), []byte("msg 2"), []byte("msg 3"), []byte("msg 4"), []byte("msg 5")}
+	c := Crypto{}
+
+	for _, parallelism := range []int{0, 1, 2, 8} {
+		sigs, err := c.SignBatch(msgs, kh, cryptoapi.WithParallelism(parallelism))
+		require.NoError(t, err)
+		require.Len(t, sigs, len(msgs))
+
+		for i, sig := range sigs {
+			require.NoError(t, c.Verify(sig, msgs[i], pubKH))
+		}
+	}
+
+	_, err = c.SignBatch(msgs, nil)
+	require.Equal(t, errBadKeyHandleFormat, err)
+
+	_, err = c.SignBatch(msgs, pubKH)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "create new signer")
+}
+
 func TestCrypto_ComputeMAC(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		kh, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
@@ -330,11 +359,15 @@ func TestCrypto_VerifyMAC(t *testing.T) {
 
 		err = c.VerifyMAC(macBytes, msg, kh)
 		require.NoError(t, err)
+
+		err = c.VerifyMAC(macBytes, []byte("other message"), kh)
+		require.ErrorIs(t, err, cryptoapi.ErrAuthTagMismatch)
 	})
 	t.Run("bad key handle format", func(t *testing.T) {
 		c := Crypto{}
 		err := c.VerifyMAC(nil, nil, nil)
 		require.Equal(t, errBadKeyHandleFormat, err)
+		require.ErrorIs(t, err, kms.ErrInvalidKeyType)
 	})
 	t.Run("fail - wrong key type", func(t *testing.T) {
 		kh, err := keyset.NewHandle(signature.ECDSAP256KeyTemplate())
@@ -382,7 +415,7 @@ func TestCrypto_ECDHES_Wrap_Unwrap_Key(t *testing.T) {
 	require.EqualError(t, err, "unwrapKey: RecipientWrappedKey is empty")
 
 	_, err = c.UnwrapKey(wrappedKey, nil)
-	require.EqualError(t, err, "unwrapKey: deriveKEKAndUnwrap: bad key handle format")
+	require.EqualError(t, err, "unwrapKey: deriveKEKAndUnwrap: bad key handle format: invalid key type")
 
 	// test UnwrapKey with ECDHES key but different curve
 	ecdh384Key, err := keyset.NewHandle(ecdh.NISTP384ECDHKWKeyTemplate())
@@ -645,7 +678,7 @@ func TestCrypto_ECDH1PU_Wrap_Unwrap_Key(t *testing.T) {
 	_, err = c.WrapKey(cek, apu, apv, recipientKey, cryptoapi.WithSender("badKey"))
 	require.EqualError(t, err, "wrapKey: deriveKEKAndWrap: error ECDH-1PU kek derivation: derive1PUKEK: EC key"+
 		" derivation error derive1PUWithECKey: failed to retrieve sender key: ksToPrivateECDSAKey: bad key handle "+
-		"format")
+		"format: invalid key type")
 
 	// now test WrapKey with good key
 	wrappedKey, err := c.WrapKey(cek, apu, apv, recipientKey, cryptoapi.WithSender(senderKH))
@@ -831,3 +864,26 @@ func TestBBSCrypto_SignVerify_DeriveProofVerifyProof(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestCrypto_Capabilities(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+
+	caps, err := c.Capabilities()
+	require.NoError(t, err)
+
+	require.True(t, caps.Supports(kms.ECDSAP256TypeIEEEP1363, kms.OperationSign))
+	require.True(t, caps.Supports(kms.X25519ECDHKWType, kms.OperationUnwrapKey))
+	require.True(t, caps.Supports(kms.BLS12381G2Type, kms.OperationDeriveProof))
+	require.False(t, caps.Supports(kms.ED25519Type, kms.OperationEncrypt))
+	require.ElementsMatch(t, []kms.KeyType{kms.HMACSHA256Tag256Type, kms.HMACSHA384Tag384Type, kms.HMACSHA512Tag512Type},
+		caps.KeyTypesFor(kms.OperationComputeMAC))
+	require.Equal(t, []string{"EdDSA"}, caps.KeyType(kms.ED25519Type).Algorithms)
+
+	// callers get a copy of the advertised capabilities.
+	caps.KeyType(kms.ED25519Type).Algorithms[0] = "changed"
+
+	caps, err = c.Capabilities()
+	require.NoError(t, err)
+	require.Equal(t, []string{"EdDSA"}, caps.KeyType(kms.ED25519Type).Algorithms)
+}
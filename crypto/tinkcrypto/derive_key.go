@@ -0,0 +1,136 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	gcmpb "github.com/google/tink/go/proto/aes_gcm_go_proto"
+	chachapb "github.com/google/tink/go/proto/chacha20_poly1305_go_proto"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	hmacpb "github.com/google/tink/go/proto/hmac_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	xchachapb "github.com/google/tink/go/proto/xchacha20_poly1305_go_proto"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/trustbloc/kms-go/internal/memguard"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aeskw"
+	"github.com/trustbloc/kms-go/kms/audit"
+)
+
+const hkdfMaxBlocks = 255
+
+var _ cryptoapi.KeyDeriver = (*Crypto)(nil)
+
+// DeriveKey derives length bytes of key material from the primary key of kh with HKDF (RFC 5869), using salt and
+// info. The key of kh is an HMAC, AES-GCM, ChaCha20-Poly1305, XChaCha20-Poly1305 or AES-KW key: HMAC keys are
+// derived with their hash function, the other keys with SHA-256. length can't be larger than 255 times the hash
+// size. The derived key material is usually imported in the KMS, as a new key (eg: localkms imports raw AEAD keys).
+func (t *Crypto) DeriveKey(kh interface{}, salt, info []byte, length int) ([]byte, error) {
+	start := time.Now()
+	key, err := t.deriveKey(kh, salt, info, length)
+
+	audit.Log(t.auditLogger, kmsapi.OperationDeriveKey, "", nil, start, err)
+
+	return key, err
+}
+
+func (t *Crypto) deriveKey(kh interface{}, salt, info []byte, length int) ([]byte, error) {
+	keyHandle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errBadKeyHandleFormat
+	}
+
+	if err := t.checkHandle(keyHandle); err != nil {
+		return nil, err
+	}
+
+	ikm, h, err := primarySymmetricKey(keyHandle)
+	if err != nil {
+		return nil, fmt.Errorf("deriveKey: %w", err)
+	}
+
+	defer memguard.Wipe(ikm)
+
+	if length <= 0 || length > hkdfMaxBlocks*h().Size() {
+		return nil, errors.New("deriveKey: invalid key length")
+	}
+
+	key := make([]byte, length)
+
+	if _, err = io.ReadFull(hkdf.New(h, ikm, salt, info), key); err != nil {
+		return nil, fmt.Errorf("deriveKey: %w", err)
+	}
+
+	return key, nil
+}
+
+// primarySymmetricKey returns a copy of the raw key value of the primary key of kh with the HKDF hash function of
+// the key.
+func primarySymmetricKey(kh *keyset.Handle) ([]byte, func() hash.Hash, error) {
+	ks := insecurecleartextkeyset.KeysetMaterial(kh)
+
+	for _, k := range ks.Key {
+		if k.KeyId != ks.PrimaryKeyId || k.Status != tinkpb.KeyStatusType_ENABLED {
+			continue
+		}
+
+		return symmetricKeyValue(k.KeyData)
+	}
+
+	return nil, nil, errors.New("no primary key found")
+}
+
+func symmetricKeyValue(kd *tinkpb.KeyData) ([]byte, func() hash.Hash, error) {
+	switch kd.TypeUrl {
+	case hmacKeyTypeURL:
+		key := &hmacpb.HmacKey{}
+		if err := proto.Unmarshal(kd.Value, key); err != nil {
+			return nil, nil, err
+		}
+
+		switch key.GetParams().GetHash() { //nolint:exhaustive
+		case commonpb.HashType_SHA256:
+			return key.KeyValue, sha256.New, nil
+		case commonpb.HashType_SHA384:
+			return key.KeyValue, sha512.New384, nil
+		case commonpb.HashType_SHA512:
+			return key.KeyValue, sha512.New, nil
+		default:
+			return nil, nil, errors.New("invalid HMAC key")
+		}
+	case aesGCMKeyTypeURL, aeskw.TypeURL:
+		key := &gcmpb.AesGcmKey{}
+		err := proto.Unmarshal(kd.Value, key)
+
+		return key.KeyValue, sha256.New, err
+	case chaCha20Poly1305KeyTypeURL:
+		key := &chachapb.ChaCha20Poly1305Key{}
+		err := proto.Unmarshal(kd.Value, key)
+
+		return key.KeyValue, sha256.New, err
+	case xChaCha20Poly1305KeyTypeURL:
+		key := &xchachapb.XChaCha20Poly1305Key{}
+		err := proto.Unmarshal(kd.Value, key)
+
+		return key.KeyValue, sha256.New, err
+	default:
+		return nil, nil, fmt.Errorf("key type '%s' is not supported", kd.TypeUrl)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"io"
+	"testing"
+
+	tinkaead "github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/signature"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aeskw"
+)
+
+func TestCrypto_DeriveKey(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+
+	salt, info := []byte("salt"), []byte("info")
+
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+		hash     func() hash.Hash
+	}{
+		{name: "AES-128-GCM", template: tinkaead.AES128GCMKeyTemplate(), hash: sha256.New},
+		{name: "AES-256-GCM", template: tinkaead.AES256GCMKeyTemplate(), hash: sha256.New},
+		{name: "ChaCha20-Poly1305", template: tinkaead.ChaCha20Poly1305KeyTemplate(), hash: sha256.New},
+		{name: "XChaCha20-Poly1305", template: tinkaead.XChaCha20Poly1305KeyTemplate(), hash: sha256.New},
+		{name: "AES-256-KW", template: aeskw.A256KWKeyTemplate(), hash: sha256.New},
+		{name: "HMAC-SHA256", template: mac.HMACSHA256Tag256KeyTemplate(), hash: sha256.New},
+		{name: "HMAC-SHA512", template: mac.HMACSHA512Tag512KeyTemplate(), hash: sha512.New},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			kh, err := keyset.NewHandle(tc.template)
+			require.NoError(t, err)
+
+			ikm, _, err := primarySymmetricKey(kh)
+			require.NoError(t, err)
+
+			expected := make([]byte, 48)
+			_, err = io.ReadFull(hkdf.New(tc.hash, ikm, salt, info), expected)
+			require.NoError(t, err)
+
+			key, err := c.DeriveKey(kh, salt, info, len(expected))
+			require.NoError(t, err)
+			require.Equal(t, expected, key)
+
+			other, err := c.DeriveKey(kh, salt, []byte("other info"), len(expected))
+			require.NoError(t, err)
+			require.NotEqual(t, key, other)
+
+			_, err = c.DeriveKey(kh, salt, info, 0)
+			require.EqualError(t, err, "deriveKey: invalid key length")
+
+			_, err = c.DeriveKey(kh, salt, info, 255*tc.hash().Size()+1)
+			require.EqualError(t, err, "deriveKey: invalid key length")
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := c.DeriveKey("bad", salt, info, 32)
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+
+		kh, err := keyset.NewHandle(signature.ED25519KeyTemplate())
+		require.NoError(t, err)
+
+		_, err = c.DeriveKey(kh, salt, info, 32)
+		require.EqualError(t, err, "deriveKey: key type 'type.googleapis.com/google.crypto.tink.Ed25519PrivateKey'"+
+			" is not supported")
+	})
+
+	caps, err := c.Capabilities()
+	require.NoError(t, err)
+	require.True(t, caps.Supports(kms.AES256GCMType, kms.OperationDeriveKey))
+	require.True(t, caps.Supports(kms.AES256KWType, kms.OperationDeriveKey))
+	require.False(t, caps.Supports(kms.ED25519Type, kms.OperationDeriveKey))
+}
@@ -0,0 +1,181 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	gcmpb "github.com/google/tink/go/proto/aes_gcm_go_proto"
+	gcmsivpb "github.com/google/tink/go/proto/aes_gcm_siv_go_proto"
+	chachapb "github.com/google/tink/go/proto/chacha20_poly1305_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	xchachapb "github.com/google/tink/go/proto/xchacha20_poly1305_go_proto"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aead/subtle"
+)
+
+const (
+	aesGCMKeyTypeURL            = "type.googleapis.com/google.crypto.tink.AesGcmKey"
+	aesGCMSIVKeyTypeURL         = "type.googleapis.com/google.crypto.tink.AesGcmSivKey"
+	chaCha20Poly1305KeyTypeURL  = "type.googleapis.com/google.crypto.tink.ChaCha20Poly1305Key"
+	xChaCha20Poly1305KeyTypeURL = "type.googleapis.com/google.crypto.tink.XChaCha20Poly1305Key"
+)
+
+var _ cryptoapi.DetachedNonceAEAD = (*Crypto)(nil)
+
+// EncryptWithNonce encrypts msg with aad and the caller chosen nonce using the primary key of kh, an AES-GCM,
+// AES-GCM-SIV, ChaCha20Poly1305 or XChaCha20Poly1305 key. The nonce is checked with the cryptoapi.WithNoncePolicy option policy,
+// if set, with the Tink key ID of the primary key as kid.
+// The returned ciphertext has the same format as the one returned by Encrypt: the raw ciphertext and tag without key
+// prefix or nonce, so it can be decrypted with DecryptDetached or Decrypt.
+func (t *Crypto) EncryptWithNonce(msg, aad, nonce []byte, kh interface{},
+	opts ...cryptoapi.NonceOpts) ([]byte, error) {
+	keyHandle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errBadKeyHandleFormat
+	}
+
+	pOpts := cryptoapi.NewNonceOpt()
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	ks := insecurecleartextkeyset.KeysetMaterial(keyHandle)
+
+	for _, k := range ks.Key {
+		if k.KeyId != ks.PrimaryKeyId || k.Status != tinkpb.KeyStatusType_ENABLED {
+			continue
+		}
+
+		a, err := detachedAEAD(k.KeyData)
+		if err != nil {
+			return nil, fmt.Errorf("encryptWithNonce: %w", err)
+		}
+
+		if len(nonce) == a.NonceSize() && pOpts.Policy() != nil {
+			if err = pOpts.Policy().CheckNonce(strconv.FormatUint(uint64(k.KeyId), 10), nonce); err != nil {
+				return nil, fmt.Errorf("encryptWithNonce: %w", err)
+			}
+		}
+
+		ct, err := a.EncryptWithNonce(msg, aad, nonce)
+		if err != nil {
+			return nil, fmt.Errorf("encryptWithNonce: %w", err)
+		}
+
+		return ct, nil
+	}
+
+	return nil, errors.New("encryptWithNonce: no primary key found")
+}
+
+// DecryptDetached decrypts cipher with aad and nonce using the enabled AES-GCM, AES-GCM-SIV, ChaCha20Poly1305 or
+// XChaCha20Poly1305 keys of kh.
+func (t *Crypto) DecryptDetached(cipher, aad, nonce []byte, kh interface{}) ([]byte, error) {
+	keyHandle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errBadKeyHandleFormat
+	}
+
+	ks := insecurecleartextkeyset.KeysetMaterial(keyHandle)
+
+	for _, k := range ks.Key {
+		if k.Status != tinkpb.KeyStatusType_ENABLED {
+			continue
+		}
+
+		a, err := detachedAEAD(k.KeyData)
+		if err != nil {
+			t.log().Debug("tinkcrypto: key skipped by the detached nonce decryption", "keyID", k.KeyId, "error", err)
+
+			continue
+		}
+
+		pt, err := a.DecryptDetached(cipher, aad, nonce)
+		if err == nil {
+			return pt, nil
+		}
+	}
+
+	return nil, fmt.Errorf("decryptDetached: decryption failed: %w", cryptoapi.ErrAuthTagMismatch)
+}
+
+func detachedAEAD(kd *tinkpb.KeyData) (*subtle.DetachedAEAD, error) {
+	switch kd.TypeUrl {
+	case aesGCMKeyTypeURL:
+		key := &gcmpb.AesGcmKey{}
+
+		if err := proto.Unmarshal(kd.Value, key); err != nil {
+			return nil, err
+		}
+
+		return subtle.NewAESGCMDetached(key.KeyValue)
+	case aesGCMSIVKeyTypeURL:
+		key := &gcmsivpb.AesGcmSivKey{}
+
+		if err := proto.Unmarshal(kd.Value, key); err != nil {
+			return nil, err
+		}
+
+		return subtle.NewAESGCMSIVDetached(key.KeyValue)
+	case chaCha20Poly1305KeyTypeURL:
+		key := &chachapb.ChaCha20Poly1305Key{}
+
+		if err := proto.Unmarshal(kd.Value, key); err != nil {
+			return nil, err
+		}
+
+		return subtle.NewChaCha20Poly1305Detached(key.KeyValue)
+	case xChaCha20Poly1305KeyTypeURL:
+		key := &xchachapb.XChaCha20Poly1305Key{}
+
+		if err := proto.Unmarshal(kd.Value, key); err != nil {
+			return nil, err
+		}
+
+		return subtle.NewXChaCha20Poly1305Detached(key.KeyValue)
+	default:
+		return nil, fmt.Errorf("key type '%s' does not support detached nonces", kd.TypeUrl)
+	}
+}
+
+// UniqueNoncePolicy is a cryptoapi.NoncePolicy rejecting nonces already used with the same key. It remembers the used
+// nonces in memory for its lifetime, it is safe for concurrent use.
+type UniqueNoncePolicy struct {
+	mu   sync.Mutex
+	used map[string]struct{}
+}
+
+// NewUniqueNoncePolicy creates a new UniqueNoncePolicy.
+func NewUniqueNoncePolicy() *UniqueNoncePolicy {
+	return &UniqueNoncePolicy{used: map[string]struct{}{}}
+}
+
+// CheckNonce returns an error if nonce was already used with kid, it records it otherwise.
+func (p *UniqueNoncePolicy) CheckNonce(kid string, nonce []byte) error {
+	id := kid + "/" + string(nonce)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.used[id]; ok {
+		return errors.New("nonce already used")
+	}
+
+	p.used[id] = struct{}{}
+
+	return nil
+}
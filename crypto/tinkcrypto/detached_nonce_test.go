@@ -0,0 +1,111 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+
+	kmsaead "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aead"
+)
+
+func TestCrypto_EncryptWithNonce(t *testing.T) {
+	c := Crypto{}
+	msg, aad := []byte(testMessage), []byte("aad")
+
+	tests := []struct {
+		name      string
+		template  *tinkpb.KeyTemplate
+		nonceSize uint32
+	}{
+		{name: "AES128GCM", template: aead.AES128GCMKeyTemplate(), nonceSize: 12},
+		{name: "AES256GCM", template: aead.AES256GCMKeyTemplate(), nonceSize: 12},
+		{name: "ChaCha20Poly1305", template: aead.ChaCha20Poly1305KeyTemplate(), nonceSize: 12},
+		{name: "XChaCha20Poly1305", template: aead.XChaCha20Poly1305KeyTemplate(), nonceSize: 24},
+		{name: "AES256GCMSIV", template: kmsaead.AES256GCMSIVKeyTemplate(), nonceSize: 12},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			kh, err := keyset.NewHandle(tc.template)
+			require.NoError(t, err)
+
+			nonce := random.GetRandomBytes(tc.nonceSize)
+
+			ct, err := c.EncryptWithNonce(msg, aad, nonce, kh)
+			require.NoError(t, err)
+
+			pt, err := c.DecryptDetached(ct, aad, nonce, kh)
+			require.NoError(t, err)
+			require.Equal(t, msg, pt)
+
+			// same format as Encrypt/Decrypt.
+			pt, err = c.Decrypt(ct, aad, nonce, kh)
+			require.NoError(t, err)
+			require.Equal(t, msg, pt)
+
+			ct, nonce, err = c.Encrypt(msg, aad, kh)
+			require.NoError(t, err)
+
+			pt, err = c.DecryptDetached(ct, aad, nonce, kh)
+			require.NoError(t, err)
+			require.Equal(t, msg, pt)
+
+			_, err = c.EncryptWithNonce(msg, aad, nonce[1:], kh)
+			require.ErrorContains(t, err, "invalid nonce size")
+
+			_, err = c.DecryptDetached(ct, []byte("other aad"), nonce, kh)
+			require.EqualError(t, err, "decryptDetached: decryption failed: authentication tag mismatch")
+		})
+	}
+
+	t.Run("unique nonce policy", func(t *testing.T) {
+		kh, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+		require.NoError(t, err)
+
+		otherKH, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+		require.NoError(t, err)
+
+		policy := NewUniqueNoncePolicy()
+		nonce := random.GetRandomBytes(12)
+
+		_, err = c.EncryptWithNonce(msg, aad, nonce, kh, cryptoapi.WithNoncePolicy(policy))
+		require.NoError(t, err)
+
+		_, err = c.EncryptWithNonce(msg, aad, nonce, kh, cryptoapi.WithNoncePolicy(policy))
+		require.EqualError(t, err, "encryptWithNonce: nonce already used")
+
+		// the nonce can be used with another key.
+		_, err = c.EncryptWithNonce(msg, aad, nonce, otherKH, cryptoapi.WithNoncePolicy(policy))
+		require.NoError(t, err)
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		_, err := c.EncryptWithNonce(msg, aad, nil, nil)
+		require.Equal(t, errBadKeyHandleFormat, err)
+
+		_, err = c.DecryptDetached(nil, aad, nil, nil)
+		require.Equal(t, errBadKeyHandleFormat, err)
+
+		kh, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+		require.NoError(t, err)
+
+		_, err = c.EncryptWithNonce(msg, aad, random.GetRandomBytes(12), kh)
+		require.ErrorContains(t, err, "does not support detached nonces")
+
+		_, err = c.DecryptDetached([]byte("ct"), aad, random.GetRandomBytes(12), kh)
+		require.EqualError(t, err, "decryptDetached: decryption failed: authentication tag mismatch")
+	})
+}
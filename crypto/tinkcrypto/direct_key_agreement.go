@@ -0,0 +1,88 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/keyset"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+)
+
+// ECDHESAlg is the ECDH-ES direct key agreement algorithm (no key wrapping) as per
+// https://tools.ietf.org/html/rfc7518#section-4.6
+const ECDHESAlg = "ECDH-ES"
+
+// direct key agreement content encryption algorithms. They all use 256 bit keys, the size derived by ECDH-ES.
+//
+//nolint:gochecknoglobals
+var directKeyAgreementEncs = map[string]bool{
+	"A256GCM": true,
+	"XC20P":   true,
+}
+
+var _ cryptoapi.DirectKeyAgreement = (*Crypto)(nil)
+
+// DeriveDirectKey does ECDH-ES direct key agreement with the recipient public key recPubKey: the key derived with an
+// ephemeral key and Concat KDF (using enc as AlgorithmID) is the CEK itself, for enc `A256GCM` or `XC20P` content
+// encryption. recPubKey can be a NIST P curve, X25519 or X448 key.
+// The recipient derives the same CEK with UnwrapKey and the returned RecipientWrappedKey.
+// returns:
+//
+//	the derived CEK
+//	RecipientWrappedKey with the ECDH-ES alg, enc, EPK, APU and APV and no EncryptedCEK
+//	error in case of errors
+func (t *Crypto) DeriveDirectKey(enc string, apu, apv []byte,
+	recPubKey *cryptoapi.PublicKey) ([]byte, *cryptoapi.RecipientWrappedKey, error) {
+	if recPubKey == nil {
+		return nil, nil, errors.New("deriveDirectKey: recipient public key is required")
+	}
+
+	if !directKeyAgreementEncs[enc] {
+		return nil, nil, fmt.Errorf("deriveDirectKey: unsupported content encryption alg '%s'", enc)
+	}
+
+	cek, epk, apu, err := t.deriveESKEK(enc, apu, apv, recPubKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("deriveDirectKey: %w", err)
+	}
+
+	return cek, &cryptoapi.RecipientWrappedKey{
+		KID: recPubKey.KID,
+		EPK: *epk,
+		Alg: ECDHESAlg,
+		Enc: enc,
+		APU: apu,
+		APV: apv,
+	}, nil
+}
+
+// deriveDirectKeyForUnwrap derives the ECDH-ES direct key agreement CEK of recWK with the recipient private key recKH.
+func (t *Crypto) deriveDirectKeyForUnwrap(recWK *cryptoapi.RecipientWrappedKey, recKH interface{}) ([]byte, error) {
+	if !directKeyAgreementEncs[recWK.Enc] {
+		return nil, fmt.Errorf("unwrapKey: unsupported ECDH-ES content encryption alg '%s'", recWK.Enc)
+	}
+
+	recPrivKH, ok := recKH.(*keyset.Handle)
+	if !ok {
+		return nil, fmt.Errorf("unwrapKey: %w", errBadKeyHandleFormat)
+	}
+
+	recipientPrivateKey, err := extractPrivKey(recPrivKH)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapKey: %w", err)
+	}
+
+	cek, err := t.deriveESKEKForUnwrap(recWK.Enc, recWK.APU, recWK.APV, &recWK.EPK, recipientPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapKey: %w", err)
+	}
+
+	return cek, nil
+}
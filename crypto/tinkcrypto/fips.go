@@ -0,0 +1,78 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"github.com/google/tink/go/keyset"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/util/fips"
+)
+
+// checkFIPSHandle returns fips.ErrNotApproved if the module is built in FIPS mode and kh holds a key of an algorithm
+// that is not approved.
+func checkFIPSHandle(kh *keyset.Handle) error {
+	if !fips.Enabled() {
+		return nil
+	}
+
+	for _, ki := range kh.KeysetInfo().GetKeyInfo() {
+		if err := fips.CheckTypeURL(ki.GetTypeUrl()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkFIPSWrap returns fips.ErrNotApproved if the module is built in FIPS mode and the key wrapping uses a curve or an
+// algorithm that is not approved. RSA recipient keys have no curve.
+func checkFIPSWrap(curve string, withSender, useXC20PKW bool) error {
+	if !fips.Enabled() {
+		return nil
+	}
+
+	if useXC20PKW {
+		alg := ECDHESXC20PKWAlg
+
+		if withSender {
+			alg = ECDH1PUXC20PKWAlg
+		}
+
+		return fips.CheckAlgorithm(alg)
+	}
+
+	if curve == "" {
+		return nil
+	}
+
+	return fips.CheckCurve(curve)
+}
+
+// checkFIPSUnwrap returns fips.ErrNotApproved if the module is built in FIPS mode and the key unwrapping of recWK with
+// recipientKH uses a curve, an algorithm or a key that is not approved.
+func checkFIPSUnwrap(recWK *cryptoapi.RecipientWrappedKey, recipientKH interface{}) error {
+	if !fips.Enabled() {
+		return nil
+	}
+
+	if err := fips.CheckAlgorithm(recWK.Alg); err != nil {
+		return err
+	}
+
+	if recWK.EPK.Curve != "" {
+		if err := fips.CheckCurve(recWK.EPK.Curve); err != nil {
+			return err
+		}
+	}
+
+	if kh, ok := recipientKH.(*keyset.Handle); ok {
+		return checkFIPSHandle(kh)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+//go:build fips
+
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"testing"
+
+	tinkaead "github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/util/fips"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/composite/ecdh"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/composite/keyio"
+)
+
+func TestCrypto_FIPS(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+
+	t.Run("approved keys", func(t *testing.T) {
+		kh, err := keyset.NewHandle(tinkaead.AES256GCMKeyTemplate())
+		require.NoError(t, err)
+
+		_, _, err = c.Encrypt([]byte(testMessage), nil, kh)
+		require.NoError(t, err)
+
+		kh, err = keyset.NewHandle(signature.ECDSAP256KeyTemplate())
+		require.NoError(t, err)
+
+		_, err = c.Sign([]byte(testMessage), kh)
+		require.NoError(t, err)
+	})
+
+	t.Run("not approved keys", func(t *testing.T) {
+		kh, err := keyset.NewHandle(tinkaead.XChaCha20Poly1305KeyTemplate())
+		require.NoError(t, err)
+
+		_, _, err = c.Encrypt([]byte(testMessage), nil, kh)
+		require.ErrorIs(t, err, fips.ErrNotApproved)
+
+		kh, err = keyset.NewHandle(signature.ED25519KeyTemplate())
+		require.NoError(t, err)
+
+		_, err = c.Sign([]byte(testMessage), kh)
+		require.ErrorIs(t, err, fips.ErrNotApproved)
+
+		err = c.VerifyWithPublicKey(nil, []byte(testMessage), &cryptoapi.PublicKey{Type: "OKP", Curve: "Ed25519"})
+		require.ErrorIs(t, err, fips.ErrNotApproved)
+	})
+
+	t.Run("key wrapping", func(t *testing.T) {
+		cek := random.GetRandomBytes(32)
+
+		recKH, err := keyset.NewHandle(ecdh.NISTP256ECDHKWKeyTemplate())
+		require.NoError(t, err)
+
+		recPubKey, err := keyio.ExtractPrimaryPublicKey(recKH)
+		require.NoError(t, err)
+
+		wk, err := c.WrapKey(cek, nil, nil, recPubKey)
+		require.NoError(t, err)
+
+		_, err = c.UnwrapKey(wk, recKH)
+		require.NoError(t, err)
+
+		_, err = c.WrapKey(cek, nil, nil, recPubKey, cryptoapi.WithXC20PKW())
+		require.ErrorIs(t, err, fips.ErrNotApproved)
+
+		x25519KH, err := keyset.NewHandle(ecdh.X25519ECDHKWKeyTemplate())
+		require.NoError(t, err)
+
+		x25519PubKey, err := keyio.ExtractPrimaryPublicKey(x25519KH)
+		require.NoError(t, err)
+
+		_, err = c.WrapKey(cek, nil, nil, x25519PubKey)
+		require.ErrorIs(t, err, fips.ErrNotApproved)
+
+		wk.Alg = ECDHESXC20PKWAlg
+
+		_, err = c.UnwrapKey(wk, recKH)
+		require.ErrorIs(t, err, fips.ErrNotApproved)
+	})
+}
@@ -0,0 +1,135 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/kms-go/spi/crypto"
+)
+
+// kwBlockSize is the AES key wrap (RFC 3394) block size, nested keys are padded to a multiple of this size.
+const kwBlockSize = 8
+
+// RewrapKey wraps an already wrapped key recWK for recipient public key 'recPubKey' using ECDH-ES key wrapping. This
+// allows multi-hop (nested) wrapping where each layer is meant for the next hop in a routing path (eg: wrap the key
+// for the final recipient first, then rewrap the result for each mediator in reverse routing order).
+// 'opts' only supports the WithXC20PKW() option since sender authentication (ECDH-1PU) is reserved for the innermost
+// layer (the one wrapping the CEK).
+// returns:
+//
+//	RecipientWrappedKey with Nested set to true, containing the wrapped recWK value
+//	error in case of errors
+func (t *Crypto) RewrapKey(recWK *crypto.RecipientWrappedKey, apu, apv []byte, recPubKey *crypto.PublicKey,
+	opts ...crypto.WrapKeyOpts) (*crypto.RecipientWrappedKey, error) {
+	if recWK == nil {
+		return nil, errors.New("rewrapKey: RecipientWrappedKey is empty")
+	}
+
+	pOpts := crypto.NewOpt()
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	if pOpts.SenderKey() != nil {
+		return nil, errors.New("rewrapKey: sender key option is not supported for nested key wrapping")
+	}
+
+	layer, err := json.Marshal(recWK)
+	if err != nil {
+		return nil, fmt.Errorf("rewrapKey: failed to marshal RecipientWrappedKey: %w", err)
+	}
+
+	// AES key wrapping requires the wrapped value size to be a multiple of 8, pad with JSON whitespace which is
+	// ignored when the layer is unmarshalled.
+	if rem := len(layer) % kwBlockSize; rem != 0 {
+		layer = append(layer, bytes.Repeat([]byte(" "), kwBlockSize-rem)...)
+	}
+
+	wk, err := t.WrapKey(layer, apu, apv, recPubKey, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("rewrapKey: %w", err)
+	}
+
+	wk.Nested = true
+
+	return wk, nil
+}
+
+// UnwrapChain peels the layers of a (possibly nested) wrapped key recWK using the successive private key handles in
+// recipientKHs, one key handle per layer starting with the outermost layer. The number of key handles must match the
+// number of layers.
+// 'opts' are applied to the innermost layer only, they allow ECDH-1PU unwrapping (using crypto.WithSender() and
+// crypto.WithTag() options) of the CEK. Outer layers are always unwrapped with ECDH-ES.
+// returns:
+//
+//	unwrapped CEK in raw bytes
+//	error in case of errors
+func (t *Crypto) UnwrapChain(recWK *crypto.RecipientWrappedKey, recipientKHs []interface{},
+	opts ...crypto.WrapKeyOpts) ([]byte, error) {
+	if recWK == nil {
+		return nil, errors.New("unwrapChain: RecipientWrappedKey is empty")
+	}
+
+	current := recWK
+
+	for i, kh := range recipientKHs {
+		if !current.Nested {
+			if i != len(recipientKHs)-1 {
+				return nil, fmt.Errorf("unwrapChain: %d key handles provided for %d wrapping layers",
+					len(recipientKHs), i+1)
+			}
+
+			cek, err := t.UnwrapKey(current, kh, opts...)
+			if err != nil {
+				return nil, fmt.Errorf("unwrapChain: layer %d: %w", i, err)
+			}
+
+			return cek, nil
+		}
+
+		next, err := t.PeelLayer(current, kh)
+		if err != nil {
+			return nil, fmt.Errorf("unwrapChain: layer %d: %w", i, err)
+		}
+
+		current = next
+	}
+
+	return nil, fmt.Errorf("unwrapChain: not enough key handles (%d) to unwrap all layers", len(recipientKHs))
+}
+
+// PeelLayer unwraps the outermost layer of the nested wrapped key recWK using recipient private key handle kh and
+// returns the inner wrapped key. It is meant for intermediaries (eg: mediators) forwarding the inner wrapped key to
+// the next hop.
+// returns:
+//
+//	the inner RecipientWrappedKey
+//	error in case of errors or if recWK is not nested
+func (t *Crypto) PeelLayer(recWK *crypto.RecipientWrappedKey, kh interface{}) (*crypto.RecipientWrappedKey, error) {
+	if recWK == nil || !recWK.Nested {
+		return nil, errors.New("peelLayer: RecipientWrappedKey is not nested")
+	}
+
+	layer, err := t.UnwrapKey(recWK, kh)
+	if err != nil {
+		return nil, fmt.Errorf("peelLayer: %w", err)
+	}
+
+	next := &crypto.RecipientWrappedKey{}
+
+	err = json.Unmarshal(layer, next)
+	if err != nil {
+		return nil, fmt.Errorf("peelLayer: failed to unmarshal nested wrapped key: %w", err)
+	}
+
+	return next, nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+
+	"github.com/trustbloc/kms-go/crypto"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/composite/ecdh"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/composite/keyio"
+)
+
+func newECDHKeyPair(t *testing.T, useX25519 bool) (*keyset.Handle, *cryptoapi.PublicKey) {
+	t.Helper()
+
+	tmpl := ecdh.NISTP256ECDHKWKeyTemplate()
+	if useX25519 {
+		tmpl = ecdh.X25519ECDHKWKeyTemplate()
+	}
+
+	kh, err := keyset.NewHandle(tmpl)
+	require.NoError(t, err)
+
+	pub, err := keyio.ExtractPrimaryPublicKey(kh)
+	require.NoError(t, err)
+
+	return kh, pub
+}
+
+func TestCrypto_RewrapKey_UnwrapChain(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+
+	apu := random.GetRandomBytes(uint32(10))
+	apv := random.GetRandomBytes(uint32(10))
+
+	t.Run("success - recipient and two mediators", func(t *testing.T) {
+		recKH, recPub := newECDHKeyPair(t, false)
+		med1KH, med1Pub := newECDHKeyPair(t, true)
+		med2KH, med2Pub := newECDHKeyPair(t, false)
+
+		cek := random.GetRandomBytes(uint32(crypto.DefKeySize))
+
+		wk, err := c.WrapKey(cek, apu, apv, recPub)
+		require.NoError(t, err)
+		require.False(t, wk.Nested)
+
+		// route: sender -> med1 -> med2 -> recipient, so wrap for med2 first, then med1.
+		wk2, err := c.RewrapKey(wk, apu, apv, med2Pub)
+		require.NoError(t, err)
+		require.True(t, wk2.Nested)
+
+		wk1, err := c.RewrapKey(wk2, apu, apv, med1Pub, cryptoapi.WithXC20PKW())
+		require.NoError(t, err)
+		require.True(t, wk1.Nested)
+		require.Equal(t, ECDHESXC20PKWAlg, wk1.Alg)
+
+		uCEK, err := c.UnwrapChain(wk1, []interface{}{med1KH, med2KH, recKH})
+		require.NoError(t, err)
+		require.Equal(t, cek, uCEK)
+
+		// each mediator peels its own layer and forwards the inner key.
+		fwd, err := c.PeelLayer(wk1, med1KH)
+		require.NoError(t, err)
+		require.Equal(t, wk2, fwd)
+
+		fwd, err = c.PeelLayer(fwd, med2KH)
+		require.NoError(t, err)
+		require.Equal(t, wk, fwd)
+
+		_, err = c.PeelLayer(fwd, recKH)
+		require.EqualError(t, err, "peelLayer: RecipientWrappedKey is not nested")
+
+		uCEK, err = c.UnwrapKey(fwd, recKH)
+		require.NoError(t, err)
+		require.Equal(t, cek, uCEK)
+	})
+
+	t.Run("success - authcrypt innermost layer", func(t *testing.T) {
+		recKH, recPub := newECDHKeyPair(t, false)
+		medKH, medPub := newECDHKeyPair(t, false)
+		senderKH, _ := newECDHKeyPair(t, false)
+
+		senderPubKH, err := senderKH.Public()
+		require.NoError(t, err)
+
+		cek := random.GetRandomBytes(uint32(crypto.DefKeySize * 2))
+
+		wk, err := c.WrapKey(cek, apu, apv, recPub, cryptoapi.WithSender(senderKH))
+		require.NoError(t, err)
+
+		nested, err := c.RewrapKey(wk, apu, apv, medPub)
+		require.NoError(t, err)
+
+		uCEK, err := c.UnwrapChain(nested, []interface{}{medKH, recKH}, cryptoapi.WithSender(senderPubKH))
+		require.NoError(t, err)
+		require.Equal(t, cek, uCEK)
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		recKH, recPub := newECDHKeyPair(t, false)
+		medKH, medPub := newECDHKeyPair(t, false)
+		senderKH, _ := newECDHKeyPair(t, false)
+
+		wk, err := c.WrapKey(random.GetRandomBytes(uint32(crypto.DefKeySize)), apu, apv, recPub)
+		require.NoError(t, err)
+
+		_, err = c.RewrapKey(nil, apu, apv, medPub)
+		require.EqualError(t, err, "rewrapKey: RecipientWrappedKey is empty")
+
+		_, err = c.RewrapKey(wk, apu, apv, medPub, cryptoapi.WithSender(senderKH))
+		require.EqualError(t, err, "rewrapKey: sender key option is not supported for nested key wrapping")
+
+		_, err = c.RewrapKey(wk, apu, apv, nil)
+		require.EqualError(t, err, "rewrapKey: wrapKey: recipient public key is required")
+
+		nested, err := c.RewrapKey(wk, apu, apv, medPub)
+		require.NoError(t, err)
+
+		_, err = c.UnwrapChain(nil, []interface{}{medKH})
+		require.EqualError(t, err, "unwrapChain: RecipientWrappedKey is empty")
+
+		_, err = c.UnwrapChain(nested, []interface{}{medKH})
+		require.EqualError(t, err, "unwrapChain: not enough key handles (1) to unwrap all layers")
+
+		_, err = c.UnwrapChain(wk, []interface{}{medKH, recKH})
+		require.EqualError(t, err, "unwrapChain: 2 key handles provided for 1 wrapping layers")
+
+		_, err = c.UnwrapChain(nested, []interface{}{recKH, recKH})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unwrapChain: layer 0: peelLayer: unwrapKey:")
+
+		_, err = c.UnwrapChain(nested, []interface{}{medKH, medKH})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unwrapChain: layer 1: unwrapKey:")
+	})
+}
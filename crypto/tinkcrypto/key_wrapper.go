@@ -20,7 +20,9 @@ import (
 	"golang.org/x/crypto/chacha20poly1305"
 	"golang.org/x/crypto/curve25519"
 
+	"github.com/trustbloc/kms-go/internal/memguard"
 	"github.com/trustbloc/kms-go/util/cryptoutil"
+	"github.com/trustbloc/kms-go/util/entropy"
 
 	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
 
@@ -48,12 +50,20 @@ func (t *Crypto) deriveKEKAndWrap(cek, apu, apv, tag []byte, senderKH interface{
 			return nil, fmt.Errorf("deriveKEKAndWrap: error ECDH-1PU kek derivation: %w", err)
 		}
 	} else { // ecdhes
-		wrappingAlg, kek, epk, apu, err = t.deriveESKEK(apu, apv, recPubKey, useXC20PKW)
+		wrappingAlg = ECDHESA256KWAlg
+
+		if useXC20PKW {
+			wrappingAlg = ECDHESXC20PKWAlg
+		}
+
+		kek, epk, apu, err = t.deriveESKEK(wrappingAlg, apu, apv, recPubKey)
 		if err != nil {
 			return nil, fmt.Errorf("deriveKEKAndWrap: error ECDH-ES kek derivation: %w", err)
 		}
 	}
 
+	defer memguard.Wipe(kek)
+
 	return t.wrapRaw(kek, cek, apu, apv, wrappingAlg, recPubKey.KID, epk, useXC20PKW)
 }
 
@@ -117,11 +127,19 @@ func (t *Crypto) deriveKEKAndUnwrap(alg string, encCEK, apu, apv, tag []byte, ep
 		if err != nil {
 			return nil, fmt.Errorf("deriveKEKAndUnwrap: error ECDH-1PU kek derivation: %w", err)
 		}
+
+		defer memguard.Wipe(kek)
+
+		if t.legacy&LegacyECDH1PUNoTagKDF != 0 {
+			return t.unwrap1PUWithLegacyFallback(alg, kek, encCEK, apu, apv, tag, epk, senderKH, recipientPrivateKey)
+		}
 	case ECDHESA256KWAlg, ECDHESXC20PKWAlg:
 		kek, err = t.deriveESKEKForUnwrap(alg, apu, apv, epk, recipientPrivateKey)
 		if err != nil {
 			return nil, fmt.Errorf("deriveKEKAndUnwrap: error ECDH-ES kek derivation: %w", err)
 		}
+
+		defer memguard.Wipe(kek)
 	default:
 		return nil, fmt.Errorf("deriveKEKAndUnwrap: unsupported JWE KW Alg '%s'", alg)
 	}
@@ -129,6 +147,36 @@ func (t *Crypto) deriveKEKAndUnwrap(alg string, encCEK, apu, apv, tag []byte, ep
 	return t.unwrapRaw(alg, kek, encCEK)
 }
 
+// unwrap1PUWithLegacyFallback unwraps encCEK with kek and, if it fails, with the legacy ECDH-1PU kek derived without
+// the tag. The key unwrap integrity check rejects keks that don't match the one used to wrap encCEK.
+func (t *Crypto) unwrap1PUWithLegacyFallback(alg string, kek, encCEK, apu, apv, tag []byte, epk *cryptoapi.PublicKey,
+	senderKH, recipientPrivateKey interface{}) ([]byte, error) {
+	cek, err := t.unwrapRaw(alg, kek, encCEK)
+	if err == nil {
+		return cek, nil
+	}
+
+	t.log().Debug("tinkcrypto: ECDH-1PU key unwrap failed, trying the legacy KDF", "alg", alg)
+
+	legacy := &Crypto{ecKW: &ecKWSupport{noTagKDF: true}, okpKW: &okpKWSupport{noTagKDF: true}}
+
+	legacyKEK, e := legacy.derive1PUKEKForUnwrap(alg, apu, apv, tag, epk, senderKH, recipientPrivateKey)
+	if e != nil {
+		return nil, err
+	}
+
+	defer memguard.Wipe(legacyKEK)
+
+	cek, e = t.unwrapRaw(alg, legacyKEK, encCEK)
+	if e != nil {
+		return nil, err
+	}
+
+	t.log().Warn("tinkcrypto: ECDH-1PU key unwrapped with the legacy KDF: the sender should be upgraded", "alg", alg)
+
+	return cek, nil
+}
+
 func (t *Crypto) unwrapRaw(alg string, kek, encCEK []byte) ([]byte, error) {
 	var wk []byte
 
@@ -195,6 +243,10 @@ func (t *Crypto) derive1PUKEK(cekSize int, apu, apv, tag []byte, senderKH interf
 			return "", nil, nil, nil, fmt.Errorf("derive1PUKEK: EC key derivation error %w", err)
 		}
 	case ecdhpb.KeyType_OKP.String():
+		if recPubKey.Curve == x448Crv {
+			return "", nil, nil, nil, errors.New("derive1PUKEK: ECDH-1PU is not supported with X448 keys")
+		}
+
 		wrappingAlg, kek, epk, apu, err = t.derive1PUWithOKPKey(wrappingAlg, apu, apv, tag, senderKH, recPubKey, epkPrv)
 		if err != nil {
 			return "", nil, nil, nil, fmt.Errorf("derive1PUKEK: OKP key derivation error %w", err)
@@ -225,6 +277,10 @@ func (t *Crypto) derive1PUKEKForUnwrap(alg string, apu, apv, tag []byte, epk *cr
 			return nil, fmt.Errorf("derive1PUKEKForUnwrap: EC key derivation error %w", err)
 		}
 	case ecdhpb.KeyType_OKP.String():
+		if epk.Curve == x448Crv {
+			return nil, errors.New("derive1PUKEKForUnwrap: ECDH-1PU is not supported with X448 keys")
+		}
+
 		kek, err = t.derive1PUWithOKPKeyForUnwrap(alg, apu, apv, tag, epk, senderKH, recipientPrivateKey)
 		if err != nil {
 			return nil, fmt.Errorf("derive1PUKEKForUnwrap: OKP key derivation error %w", err)
@@ -236,31 +292,32 @@ func (t *Crypto) derive1PUKEKForUnwrap(alg string, apu, apv, tag []byte, epk *cr
 	return kek, nil
 }
 
-func (t *Crypto) deriveESKEK(apu, apv []byte, recPubKey *cryptoapi.PublicKey,
-	useXC20PKW bool) (string, []byte, *cryptoapi.PublicKey, []byte, error) {
+// deriveESKEK derives an ECDH-ES key for recPubKey, alg is the Concat KDF AlgorithmID: the key wrapping alg or, for
+// direct key agreement, the content encryption alg.
+func (t *Crypto) deriveESKEK(alg string, apu, apv []byte,
+	recPubKey *cryptoapi.PublicKey) ([]byte, *cryptoapi.PublicKey, []byte, error) {
 	var (
-		kek         []byte
-		epk         *cryptoapi.PublicKey
-		wrappingAlg string
-		err         error
+		kek []byte
+		epk *cryptoapi.PublicKey
+		err error
 	)
 
 	switch recPubKey.Type {
 	case ecdhpb.KeyType_EC.String():
-		wrappingAlg, kek, epk, apu, err = t.deriveESWithECKey(apu, apv, recPubKey, useXC20PKW)
+		kek, epk, apu, err = t.deriveESWithECKey(alg, apu, apv, recPubKey)
 		if err != nil {
-			return "", nil, nil, nil, fmt.Errorf("deriveESKEK: error %w", err)
+			return nil, nil, nil, fmt.Errorf("deriveESKEK: error %w", err)
 		}
 	case ecdhpb.KeyType_OKP.String():
-		wrappingAlg, kek, epk, apu, err = t.deriveESWithOKPKey(apu, apv, recPubKey, useXC20PKW)
+		kek, epk, apu, err = t.deriveESWithOKPKey(alg, apu, apv, recPubKey)
 		if err != nil {
-			return "", nil, nil, nil, fmt.Errorf("deriveESKEK: error %w", err)
+			return nil, nil, nil, fmt.Errorf("deriveESKEK: error %w", err)
 		}
 	default:
-		return "", nil, nil, nil, errors.New("deriveESKEK: invalid recipient key type for ECDH-ES")
+		return nil, nil, nil, errors.New("deriveESKEK: invalid recipient key type for ECDH-ES")
 	}
 
-	return wrappingAlg, kek, epk, apu, nil
+	return kek, epk, apu, nil
 }
 
 func (t *Crypto) deriveESKEKForUnwrap(alg string, apu, apv []byte, epk *cryptoapi.PublicKey,
@@ -413,17 +470,11 @@ func (t *Crypto) deriveESWithECKeyForUnwrap(alg string, apu, apv []byte, epk *cr
 	return josecipher.DeriveECDHES(alg, apu, apv, recPrivKey, epkPubKey, defKeySize), nil
 }
 
-func (t *Crypto) deriveESWithECKey(apu, apv []byte, recPubKey *cryptoapi.PublicKey,
-	useXC20PKW bool) (string, []byte, *cryptoapi.PublicKey, []byte, error) {
-	wrappingAlg := ECDHESA256KWAlg
-
-	if useXC20PKW {
-		wrappingAlg = ECDHESXC20PKWAlg
-	}
-
+func (t *Crypto) deriveESWithECKey(wrappingAlg string, apu, apv []byte,
+	recPubKey *cryptoapi.PublicKey) ([]byte, *cryptoapi.PublicKey, []byte, error) {
 	recECPubKey, ephemeralPrivKey, err := t.convertRecKeyAndGenOrGetEPKEC(recPubKey, nil)
 	if err != nil {
-		return "", nil, nil, nil, fmt.Errorf("deriveESWithECKey: failed to generate ephemeral key: %w", err)
+		return nil, nil, nil, fmt.Errorf("deriveESWithECKey: failed to generate ephemeral key: %w", err)
 	}
 
 	ephemeralXBytes := ephemeralPrivKey.PublicKey.X.Bytes()
@@ -441,7 +492,7 @@ func (t *Crypto) deriveESWithECKey(apu, apv []byte, recPubKey *cryptoapi.PublicK
 		Type:  recPubKey.Type,
 	}
 
-	return wrappingAlg, kek, epk, apu, nil
+	return kek, epk, apu, nil
 }
 
 func (t *Crypto) derive1PUWithOKPKey(wrappingAlg string, apu, apv, tag []byte, senderKH interface{},
@@ -496,17 +547,15 @@ func (t *Crypto) derive1PUWithOKPKeyForUnwrap(alg string, apu, apv, tag []byte,
 	return kek, nil
 }
 
-func (t *Crypto) deriveESWithOKPKey(apu, apv []byte, recPubKey *cryptoapi.PublicKey,
-	useXC20PKW bool) (string, []byte, *cryptoapi.PublicKey, []byte, error) {
-	wrappingAlg := ECDHESA256KWAlg
-
-	if useXC20PKW {
-		wrappingAlg = ECDHESXC20PKWAlg
+func (t *Crypto) deriveESWithOKPKey(wrappingAlg string, apu, apv []byte,
+	recPubKey *cryptoapi.PublicKey) ([]byte, *cryptoapi.PublicKey, []byte, error) {
+	if recPubKey.Curve == x448Crv {
+		return deriveESWithX448Key(wrappingAlg, apu, apv, recPubKey, entropy.Reader(t.randomness))
 	}
 
 	ephemeralPubKey, ephemeralPrivKey, err := t.generateOrGetEphemeralOKPKey(nil)
 	if err != nil {
-		return "", nil, nil, nil, fmt.Errorf("deriveESWithOKPKey: failed to generate ephemeral key: %w", err)
+		return nil, nil, nil, fmt.Errorf("deriveESWithOKPKey: failed to generate ephemeral key: %w", err)
 	}
 
 	ephemeralPrivChacha := new([chacha20poly1305.KeySize]byte)
@@ -521,11 +570,18 @@ func (t *Crypto) deriveESWithOKPKey(apu, apv []byte, recPubKey *cryptoapi.Public
 	}
 
 	z, err := cryptoutil.DeriveECDHX25519(ephemeralPrivChacha, recPubKeyChacha)
+	memguard.Wipe(ephemeralPrivKey, ephemeralPrivChacha[:])
+
 	if err != nil {
-		return "", nil, nil, nil, fmt.Errorf("deriveESWithOKPKey: failed to derive 25519 kek: %w", err)
+		return nil, nil, nil, fmt.Errorf("deriveESWithOKPKey: failed to derive 25519 kek: %w", err)
 	}
 
-	kek := kdf(wrappingAlg, z, apu, apv, chacha20poly1305.KeySize)
+	kek, err := kdf(wrappingAlg, z, apu, apv, chacha20poly1305.KeySize)
+	memguard.Wipe(z)
+
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("deriveESWithOKPKey: %w", err)
+	}
 
 	epk := &cryptoapi.PublicKey{
 		X:     ephemeralPubKey,
@@ -533,11 +589,15 @@ func (t *Crypto) deriveESWithOKPKey(apu, apv []byte, recPubKey *cryptoapi.Public
 		Type:  recPubKey.Type,
 	}
 
-	return wrappingAlg, kek, epk, apu, nil
+	return kek, epk, apu, nil
 }
 
 func (t *Crypto) deriveESWithOKPKeyForUnwrap(alg string, apu, apv []byte, epk *cryptoapi.PublicKey,
 	recipientPrivateKey interface{}) ([]byte, error) {
+	if epk.Curve == x448Crv {
+		return deriveESWithX448KeyForUnwrap(alg, apu, apv, epk, recipientPrivateKey)
+	}
+
 	recPrivOKPKey, ok := recipientPrivateKey.([]byte)
 	if !ok {
 		return nil, errors.New("deriveESWithOKPKeyForUnwrap: recipient key is not an OKP key")
@@ -550,11 +610,20 @@ func (t *Crypto) deriveESWithOKPKeyForUnwrap(alg string, apu, apv []byte, epk *c
 	copy(epkChacha[:], epk.X)
 
 	z, err := cryptoutil.DeriveECDHX25519(recPrivKeyChacha, epkChacha)
+	memguard.Wipe(recPrivKeyChacha[:])
+
 	if err != nil {
 		return nil, fmt.Errorf("deriveESWithOKPKeyForUnwrap: %w", err)
 	}
 
-	return kdf(alg, z, apu, apv, chacha20poly1305.KeySize), nil
+	defer memguard.Wipe(z)
+
+	kek, err := kdf(alg, z, apu, apv, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("deriveESWithOKPKeyForUnwrap: %w", err)
+	}
+
+	return kek, nil
 }
 
 // convertRecKeyAndGenOrGetEPKEC converts recPubKey into *ecdsa.PublicKey and generates an ephemeral EC private key
@@ -7,16 +7,23 @@ SPDX-License-Identifier: Apache-2.0
 package tinkcrypto
 
 import (
+	"bytes"
+	gocrypto "crypto"
 	"crypto/aes"
 	"crypto/cipher"
+	stdecdh "crypto/ecdh"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"log/slog"
+	"math/big"
 	"testing"
 
+	josecipher "github.com/go-jose/go-jose/v3/cipher"
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/aead"
 	"github.com/google/tink/go/keyset"
@@ -29,6 +36,8 @@ import (
 	"golang.org/x/crypto/curve25519"
 
 	"github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/util/cryptoutil"
+	"github.com/trustbloc/kms-go/util/entropy"
 
 	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/composite/ecdh"
 	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/composite/keyio"
@@ -137,7 +146,7 @@ func TestWrapKey_Failure(t *testing.T) {
 	_, err = c.WrapKey(cek, apu, apv, badRecipientKey, crypto.WithSender(recipientKey))
 	require.EqualError(t, err, "wrapKey: deriveKEKAndWrap: error ECDH-1PU kek derivation: derive1PUKEK: OKP key"+
 		" derivation error derive1PUWithOKPKey: failed to retrieve sender key: ksToPrivateX25519Key: bad key handle "+
-		"format")
+		"format: invalid key type")
 
 	aesCipher, err := aes.NewCipher(random.GetRandomBytes(uint32(defKeySize)))
 	require.NoError(t, err)
@@ -322,7 +331,7 @@ func Test_deriveKEKAndUnwrap_Failure(t *testing.T) {
 	require.NoError(t, err)
 
 	_, err = c.deriveKEKAndUnwrap(ECDH1PUA256KWAlg, nil, nil, nil, nil, nil, nil, nil)
-	require.EqualError(t, err, "deriveKEKAndUnwrap: bad key handle format")
+	require.EqualError(t, err, "deriveKEKAndUnwrap: bad key handle format: invalid key type")
 
 	_, err = c.deriveKEKAndUnwrap(ECDH1PUA256KWAlg, nil, nil, nil, nil, nil, nil, recKH)
 	require.EqualError(t, err, "deriveKEKAndUnwrap: error ECDH-1PU kek derivation: derive1PUKEKForUnwrap: sender's"+
@@ -478,3 +487,196 @@ func TestX25519ECDHVector(t *testing.T) {
 	require.EqualValues(t, sharedSecretVector, sharedSecretFromAlice)
 	require.EqualValues(t, sharedSecretVector, sharedSecretFromBob)
 }
+
+func TestWrapKey_WithRandomness(t *testing.T) {
+	cek := random.GetRandomBytes(uint32(defKeySize))
+	apu, apv := []byte("alice"), []byte("bob")
+
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+		opts     []crypto.WrapKeyOpts
+	}{
+		{name: "NIST P-256", template: ecdh.NISTP256ECDHKWKeyTemplate()},
+		{name: "X25519 XC20P KW", template: ecdh.X25519ECDHKWKeyTemplate(), opts: []crypto.WrapKeyOpts{crypto.WithXC20PKW()}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			recKH, err := keyset.NewHandle(tc.template)
+			require.NoError(t, err)
+
+			recPubKey, err := keyio.ExtractPrimaryPublicKey(recKH)
+			require.NoError(t, err)
+
+			wrapped := make([]*crypto.RecipientWrappedKey, 3)
+
+			for i, seed := range []string{"seed", "seed", "other seed"} {
+				c, err := New(WithRandomness(entropy.NewDeterministic([]byte(seed), "tinkcrypto test")))
+				require.NoError(t, err)
+
+				wrapped[i], err = c.WrapKey(cek, apu, apv, recPubKey, tc.opts...)
+				require.NoError(t, err)
+
+				unwrapped, err := c.UnwrapKey(wrapped[i], recKH)
+				require.NoError(t, err)
+				require.Equal(t, cek, unwrapped)
+			}
+
+			require.Equal(t, wrapped[0].EPK, wrapped[1].EPK)
+			require.Equal(t, wrapped[0].EncryptedCEK, wrapped[1].EncryptedCEK)
+			require.NotEqual(t, wrapped[0].EPK, wrapped[2].EPK)
+		})
+	}
+}
+
+func TestUnwrapKey_LegacyECDH1PUNoTagKDF(t *testing.T) {
+	cek := random.GetRandomBytes(uint32(defKeySize * 2))
+	apu, apv, tag := []byte("alice"), []byte("bob"), random.GetRandomBytes(16)
+
+	tests := []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+	}{
+		{"NIST P-256", ecdh.NISTP256ECDHKWKeyTemplate()},
+		{"X25519", ecdh.X25519ECDHKWKeyTemplate()},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			recKH, err := keyset.NewHandle(tc.template)
+			require.NoError(t, err)
+
+			recPubKey, err := keyio.ExtractPrimaryPublicKey(recKH)
+			require.NoError(t, err)
+
+			// legacy wrapped key, as produced by aries-framework-go with the draft-03 ECDH-1PU KDF.
+			wrappedKey, senderPubKey := legacyECDH1PUWrappedKey(t, cek, apu, apv, recPubKey)
+
+			c, err := New()
+			require.NoError(t, err)
+
+			_, err = c.UnwrapKey(wrappedKey, recKH, crypto.WithSender(senderPubKey), crypto.WithTag(tag))
+			require.ErrorContains(t, err, "failed to AES unwrap key")
+			require.ErrorIs(t, err, crypto.ErrAuthTagMismatch)
+
+			logs := &bytes.Buffer{}
+
+			legacyCrypto, err := New(WithLegacyCompat(LegacyECDH1PUNoTagKDF),
+				WithLogger(slog.New(slog.NewTextHandler(logs, nil))))
+			require.NoError(t, err)
+
+			unwrapped, err := legacyCrypto.UnwrapKey(wrappedKey, recKH, crypto.WithSender(senderPubKey),
+				crypto.WithTag(tag))
+			require.NoError(t, err)
+			require.Equal(t, cek, unwrapped)
+			require.Contains(t, logs.String(), `level=WARN msg="tinkcrypto: ECDH-1PU key unwrapped with the legacy KDF:`)
+
+			// keys wrapped with the current KDF are still unwrapped by the legacy compatible Crypto.
+			senderKH, err := keyset.NewHandle(tc.template)
+			require.NoError(t, err)
+
+			senderPubKH, err := senderKH.Public()
+			require.NoError(t, err)
+
+			wrappedKey, err = c.WrapKey(cek, apu, apv, recPubKey, crypto.WithSender(senderKH), crypto.WithTag(tag))
+			require.NoError(t, err)
+
+			unwrapped, err = legacyCrypto.UnwrapKey(wrappedKey, recKH, crypto.WithSender(senderPubKH),
+				crypto.WithTag(tag))
+			require.NoError(t, err)
+			require.Equal(t, cek, unwrapped)
+
+			// a wrong sender fails with both KDFs.
+			_, err = legacyCrypto.UnwrapKey(wrappedKey, recKH, crypto.WithSender(senderPubKey), crypto.WithTag(tag))
+			require.ErrorContains(t, err, "failed to AES unwrap key")
+		})
+	}
+}
+
+// legacyECDH1PUWrappedKey wraps cek for recPubKey with ECDH-1PU+A256KW using the draft-madden-jose-ecdh-1pu-03 KDF
+// (no tag in SuppPubInfo), independently of the Crypto KDF. It returns the wrapped key and the sender public key.
+func legacyECDH1PUWrappedKey(t *testing.T, cek, apu, apv []byte,
+	recPubKey *crypto.PublicKey) (*crypto.RecipientWrappedKey, *crypto.PublicKey) {
+	t.Helper()
+
+	var (
+		ze, zs            []byte
+		epk, senderPubKey *crypto.PublicKey
+	)
+
+	if recPubKey.Type == ecdhpb.KeyType_OKP.String() {
+		recPub, err := stdecdh.X25519().NewPublicKey(recPubKey.X)
+		require.NoError(t, err)
+
+		ze, epk = legacyX25519Agreement(t, recPub)
+		zs, senderPubKey = legacyX25519Agreement(t, recPub)
+	} else {
+		recPub, err := (&ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(recPubKey.X),
+			Y:     new(big.Int).SetBytes(recPubKey.Y),
+		}).ECDH()
+		require.NoError(t, err)
+
+		ze, epk = legacyP256Agreement(t, recPub)
+		zs, senderPubKey = legacyP256Agreement(t, recPub)
+	}
+
+	supPubInfo := make([]byte, 4)
+	binary.BigEndian.PutUint32(supPubInfo, 256)
+
+	kek := make([]byte, 32)
+	_, err := josecipher.NewConcatKDF(gocrypto.SHA256, append(ze, zs...),
+		cryptoutil.LengthPrefix([]byte(ECDH1PUA256KWAlg)), cryptoutil.LengthPrefix(apu), cryptoutil.LengthPrefix(apv),
+		supPubInfo, []byte{}).Read(kek)
+	require.NoError(t, err)
+
+	block, err := aes.NewCipher(kek)
+	require.NoError(t, err)
+
+	encCEK, err := josecipher.KeyWrap(block, cek)
+	require.NoError(t, err)
+
+	return &crypto.RecipientWrappedKey{
+		EncryptedCEK: encCEK,
+		EPK:          *epk,
+		Alg:          ECDH1PUA256KWAlg,
+		APU:          apu,
+		APV:          apv,
+	}, senderPubKey
+}
+
+func legacyP256Agreement(t *testing.T, recPub *stdecdh.PublicKey) ([]byte, *crypto.PublicKey) {
+	t.Helper()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ecdhPriv, err := priv.ECDH()
+	require.NoError(t, err)
+
+	z, err := ecdhPriv.ECDH(recPub)
+	require.NoError(t, err)
+
+	return z, &crypto.PublicKey{
+		X:     priv.X.Bytes(),
+		Y:     priv.Y.Bytes(),
+		Curve: elliptic.P256().Params().Name,
+		Type:  ecdhpb.KeyType_EC.String(),
+	}
+}
+
+func legacyX25519Agreement(t *testing.T, recPub *stdecdh.PublicKey) ([]byte, *crypto.PublicKey) {
+	t.Helper()
+
+	priv, err := stdecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	z, err := priv.ECDH(recPub)
+	require.NoError(t, err)
+
+	return z, &crypto.PublicKey{
+		X:     priv.PublicKey().Bytes(),
+		Curve: "X25519",
+		Type:  ecdhpb.KeyType_OKP.String(),
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/prf/subtle"
+	hmacpb "github.com/google/tink/go/proto/hmac_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+
+	"github.com/trustbloc/kms-go/util/kdfdomain"
+)
+
+const hmacKeyTypeURL = "type.googleapis.com/google.crypto.tink.HmacKey"
+
+// ComputePRF computes outputLength bytes of HMAC pseudorandom function output of data with the primary HMAC key of kh.
+// Unlike ComputeMAC, the output has no key prefix and doesn't depend on the key output prefix type, it is the plain
+// (truncated) HMAC of data, so it can be used for deterministic keyed hashing, eg: to compute blinded indexes.
+// outputLength can't be larger than the HMAC hash function size.
+func (t *Crypto) ComputePRF(data []byte, kh interface{}, outputLength int) ([]byte, error) {
+	keyHandle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errBadKeyHandleFormat
+	}
+
+	if outputLength <= 0 {
+		return nil, errors.New("computePRF: invalid output length")
+	}
+
+	key, err := primaryHMACKey(keyHandle)
+	if err != nil {
+		return nil, fmt.Errorf("computePRF: %w", err)
+	}
+
+	prf, err := subtle.NewHMACPRF(key.Params.Hash.String(), key.KeyValue)
+	if err != nil {
+		return nil, fmt.Errorf("computePRF: %w", err)
+	}
+
+	out, err := prf.ComputePRF(data, uint32(outputLength))
+	if err != nil {
+		return nil, fmt.Errorf("computePRF: %w", err)
+	}
+
+	return out, nil
+}
+
+// ComputeDomainPRF computes the PRF output of data with kh, like ComputePRF, for the key derivation domain d: the PRF
+// input is the domain info bytes followed by data, so the same key and data give unrelated outputs in other domains.
+func (t *Crypto) ComputeDomainPRF(d *kdfdomain.Domain, data []byte, kh interface{}, outputLength int) ([]byte, error) {
+	if d == nil {
+		return nil, errors.New("computeDomainPRF: key derivation domain is required")
+	}
+
+	return t.ComputePRF(d.Info(data), kh, outputLength)
+}
+
+func primaryHMACKey(kh *keyset.Handle) (*hmacpb.HmacKey, error) {
+	ks := insecurecleartextkeyset.KeysetMaterial(kh)
+
+	for _, k := range ks.Key {
+		if k.KeyId != ks.PrimaryKeyId || k.Status != tinkpb.KeyStatusType_ENABLED {
+			continue
+		}
+
+		if k.KeyData.TypeUrl != hmacKeyTypeURL {
+			return nil, errors.New("key is not an HMAC key")
+		}
+
+		key := &hmacpb.HmacKey{}
+
+		if err := proto.Unmarshal(k.KeyData.Value, key); err != nil {
+			return nil, err
+		}
+
+		if key.Params == nil {
+			return nil, errors.New("invalid HMAC key")
+		}
+
+		return key, nil
+	}
+
+	return nil, errors.New("no primary key found")
+}
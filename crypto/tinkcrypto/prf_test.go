@@ -0,0 +1,99 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	hmacpb "github.com/google/tink/go/proto/hmac_go_proto"
+	"github.com/google/tink/go/signature"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/util/kdfdomain"
+)
+
+func TestCrypto_ComputePRF(t *testing.T) {
+	c := Crypto{}
+	msg := []byte(testMessage)
+
+	t.Run("success", func(t *testing.T) {
+		kh, err := keyset.NewHandle(mac.HMACSHA512Tag512KeyTemplate())
+		require.NoError(t, err)
+
+		key := &hmacpb.HmacKey{}
+		require.NoError(t, proto.Unmarshal(insecurecleartextkeyset.KeysetMaterial(kh).Key[0].KeyData.Value, key))
+
+		h := hmac.New(sha512.New, key.KeyValue)
+		h.Write(msg)
+		expected := h.Sum(nil)
+
+		out, err := c.ComputePRF(msg, kh, 64)
+		require.NoError(t, err)
+		require.Equal(t, expected, out)
+
+		// PRF outputs are deterministic and can be truncated.
+		out, err = c.ComputePRF(msg, kh, 16)
+		require.NoError(t, err)
+		require.Equal(t, expected[:16], out)
+
+		// MACs of TINK prefixed keys are not plain HMACs.
+		macBytes, err := c.ComputeMAC(msg, kh)
+		require.NoError(t, err)
+		require.NotEqual(t, expected, macBytes)
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		_, err := c.ComputePRF(msg, nil, 32)
+		require.Equal(t, errBadKeyHandleFormat, err)
+
+		kh, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+		require.NoError(t, err)
+
+		_, err = c.ComputePRF(msg, kh, 0)
+		require.EqualError(t, err, "computePRF: invalid output length")
+
+		_, err = c.ComputePRF(msg, kh, 33)
+		require.ErrorContains(t, err, "computePRF")
+
+		kh, err = keyset.NewHandle(signature.ECDSAP256KeyTemplate())
+		require.NoError(t, err)
+
+		_, err = c.ComputePRF(msg, kh, 32)
+		require.EqualError(t, err, "computePRF: key is not an HMAC key")
+	})
+}
+
+func TestCrypto_ComputeDomainPRF(t *testing.T) {
+	c := Crypto{}
+	msg := []byte(testMessage)
+
+	kh, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	require.NoError(t, err)
+
+	domainA := kdfdomain.MustRegister("tinkcrypto/test-prf-a", 1, nil)
+	domainB := kdfdomain.MustRegister("tinkcrypto/test-prf-b", 1, nil)
+
+	outA, err := c.ComputeDomainPRF(domainA, msg, kh, 32)
+	require.NoError(t, err)
+
+	expected, err := c.ComputePRF(domainA.Info(msg), kh, 32)
+	require.NoError(t, err)
+	require.Equal(t, expected, outA)
+
+	outB, err := c.ComputeDomainPRF(domainB, msg, kh, 32)
+	require.NoError(t, err)
+	require.NotEqual(t, outA, outB)
+
+	_, err = c.ComputeDomainPRF(nil, msg, kh, 32)
+	require.EqualError(t, err, "computeDomainPRF: key derivation domain is required")
+}
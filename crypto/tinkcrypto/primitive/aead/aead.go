@@ -21,4 +21,8 @@ func init() {
 	if err := registry.RegisterKeyManager(newAESCBCHMACAEADKeyManager()); err != nil {
 		panic(fmt.Sprintf("aead.init() failed: %v", err))
 	}
+
+	if err := registry.RegisterKeyManager(newAESGCMAEADKeyManager()); err != nil {
+		panic(fmt.Sprintf("aead.init() failed: %v", err))
+	}
 }
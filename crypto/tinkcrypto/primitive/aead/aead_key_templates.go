@@ -8,6 +8,8 @@ package aead
 
 import (
 	"github.com/golang/protobuf/proto"
+	gcmpb "github.com/google/tink/go/proto/aes_gcm_go_proto"
+	gcmsivpb "github.com/google/tink/go/proto/aes_gcm_siv_go_proto"
 	commonpb "github.com/google/tink/go/proto/common_go_proto"
 	hmacpb "github.com/google/tink/go/proto/hmac_go_proto"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
@@ -88,3 +90,44 @@ func createAESCBCHMACAEADKeyTemplate(aesKeySize, hmacKeySize, tagSize uint32,
 		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
 	}
 }
+
+// AES192GCMKeyTemplate is a KeyTemplate that generates an AES-GCM key with the following parameters:
+//   - Key size: 24 bytes
+//   - IV size: 12 bytes
+//   - Tag size: 16 bytes
+//
+// It is used for the JWE A192GCM content encryption, Tink's AES-GCM key manager only supports 16 and 32 bytes keys.
+func AES192GCMKeyTemplate() *tinkpb.KeyTemplate {
+	serializedFormat, err := proto.Marshal(&gcmpb.AesGcmKeyFormat{KeySize: subtle.AES192Size})
+	if err != nil {
+		panic("failed to marshal AES-GCM key format proto")
+	}
+
+	return &tinkpb.KeyTemplate{
+		Value:            serializedFormat,
+		TypeUrl:          aesGCMAEADTypeURL,
+		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+	}
+}
+
+// aesGCMSIVTypeURL is the type URL of Tink's AES-GCM-SIV keys.
+const aesGCMSIVTypeURL = "type.googleapis.com/google.crypto.tink.AesGcmSivKey"
+
+// AES256GCMSIVKeyTemplate is a KeyTemplate that generates a nonce misuse-resistant AES-GCM-SIV (RFC 8452) key with
+// the following parameters:
+//   - Key size: 32 bytes
+//   - Output prefix type: TINK
+//
+// Its keys are run by Tink's AES-GCM-SIV key manager.
+func AES256GCMSIVKeyTemplate() *tinkpb.KeyTemplate {
+	serializedFormat, err := proto.Marshal(&gcmsivpb.AesGcmSivKeyFormat{KeySize: subtle.AES256Size})
+	if err != nil {
+		panic("failed to marshal AES-GCM-SIV key format proto")
+	}
+
+	return &tinkpb.KeyTemplate{
+		Value:            serializedFormat,
+		TypeUrl:          aesGCMSIVTypeURL,
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+	}
+}
@@ -35,6 +35,12 @@ func TestKeyTemplates(t *testing.T) {
 		}, {
 			name:     "AEAD_AES_256_CBC_HMAC_SHA_512",
 			template: aead.AES256CBCHMACSHA512KeyTemplate(),
+		}, {
+			name:     "AEAD_AES_192_GCM",
+			template: aead.AES192GCMKeyTemplate(),
+		}, {
+			name:     "AEAD_AES_256_GCM_SIV",
+			template: aead.AES256GCMSIVKeyTemplate(),
 		},
 	}
 
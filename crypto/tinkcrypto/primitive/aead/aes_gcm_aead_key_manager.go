@@ -0,0 +1,114 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aead
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/keyset"
+	gcmpb "github.com/google/tink/go/proto/aes_gcm_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/subtle/random"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aead/subtle"
+)
+
+const (
+	aesGCMAEADKeyVersion = 0
+	aesGCMAEADTypeURL    = "type.hyperledger.org/hyperledger.aries.crypto.tink.AesGcmKey"
+)
+
+// common errors.
+var (
+	errInvalidAESGCMAEADKey       = fmt.Errorf("aes_gcm_aead_key_manager: invalid key")
+	errInvalidAESGCMAEADKeyFormat = fmt.Errorf("aes_gcm_aead_key_manager: invalid key format")
+)
+
+// aesGCMAEADKeyManager is an implementation of KeyManager interface for AesGcmKey keys of 16, 24 or 32 bytes. Tink's
+// AES-GCM key manager does not support the 24 bytes keys of the JWE A192GCM content encryption.
+// It generates new AesGcmKey keys and produces new instances of AESGCM subtle.
+type aesGCMAEADKeyManager struct{}
+
+// newAESGCMAEADKeyManager creates a new aesGCMAEADKeyManager.
+func newAESGCMAEADKeyManager() *aesGCMAEADKeyManager {
+	return new(aesGCMAEADKeyManager)
+}
+
+// Primitive creates an AEAD for the given serialized AesGcmKey proto.
+func (km *aesGCMAEADKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidAESGCMAEADKey
+	}
+
+	key := new(gcmpb.AesGcmKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidAESGCMAEADKey
+	}
+
+	if err := keyset.ValidateKeyVersion(key.Version, aesGCMAEADKeyVersion); err != nil {
+		return nil, fmt.Errorf("aes_gcm_aead_key_manager: %w", err)
+	}
+
+	aead, err := subtle.NewAESGCM(key.KeyValue)
+	if err != nil {
+		return nil, fmt.Errorf("aes_gcm_aead_key_manager: cannot create new primitive: %w", err)
+	}
+
+	return aead, nil
+}
+
+// NewKey creates a new key according to the given serialized AesGcmKeyFormat.
+func (km *aesGCMAEADKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidAESGCMAEADKeyFormat
+	}
+
+	keyFormat := new(gcmpb.AesGcmKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidAESGCMAEADKeyFormat
+	}
+
+	if err := subtle.ValidateAESKeySize(keyFormat.KeySize); err != nil {
+		return nil, fmt.Errorf("aes_gcm_aead_key_manager: invalid key format: %w", err)
+	}
+
+	return &gcmpb.AesGcmKey{
+		Version:  aesGCMAEADKeyVersion,
+		KeyValue: random.GetRandomBytes(keyFormat.KeySize),
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given serialized AesGcmKeyFormat.
+// It should be used solely by the key management API.
+func (km *aesGCMAEADKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tinkpb.KeyData{
+		TypeUrl:         km.TypeURL(),
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *aesGCMAEADKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == aesGCMAEADTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *aesGCMAEADKeyManager) TypeURL() string {
+	return aesGCMAEADTypeURL
+}
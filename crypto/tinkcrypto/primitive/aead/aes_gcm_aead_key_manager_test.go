@@ -0,0 +1,96 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aead_test
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"testing"
+
+	"github.com/google/tink/go/core/registry"
+	gcmpb "github.com/google/tink/go/proto/aes_gcm_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/tink"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aead"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aead/subtle"
+)
+
+// AESGCMAEADTypeURL is the type URL of the AES-GCM keys supporting 24 bytes keys.
+const AESGCMAEADTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.AesGcmKey"
+
+func TestAESGCMAEADKeyManager(t *testing.T) {
+	km, err := registry.GetKeyManager(AESGCMAEADTypeURL)
+	require.NoError(t, err)
+	require.True(t, km.DoesSupport(AESGCMAEADTypeURL))
+	require.False(t, km.DoesSupport("type.googleapis.com/google.crypto.tink.AesGcmKey"))
+
+	kd, err := km.NewKeyData(aead.AES192GCMKeyTemplate().Value)
+	require.NoError(t, err)
+	require.Equal(t, AESGCMAEADTypeURL, kd.TypeUrl)
+	require.Equal(t, tinkpb.KeyData_SYMMETRIC, kd.KeyMaterialType)
+
+	key := new(gcmpb.AesGcmKey)
+	require.NoError(t, proto.Unmarshal(kd.Value, key))
+	require.Len(t, key.KeyValue, subtle.AES192Size)
+
+	p, err := km.Primitive(kd.Value)
+	require.NoError(t, err)
+
+	a, ok := p.(tink.AEAD)
+	require.True(t, ok)
+
+	ct, err := a.Encrypt([]byte("plaintext"), []byte("aad"))
+	require.NoError(t, err)
+
+	// the ciphertext is the IV followed by a standard AES-192-GCM ciphertext and tag.
+	block, err := aes.NewCipher(key.KeyValue)
+	require.NoError(t, err)
+
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+
+	pt, err := gcm.Open(nil, ct[:subtle.AESGCMIVSize], ct[subtle.AESGCMIVSize:], []byte("aad"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("plaintext"), pt)
+
+	t.Run("errors", func(t *testing.T) {
+		_, err = km.Primitive(nil)
+		require.EqualError(t, err, "aes_gcm_aead_key_manager: invalid key")
+
+		_, err = km.Primitive([]byte("bad key"))
+		require.EqualError(t, err, "aes_gcm_aead_key_manager: invalid key")
+
+		sk, err := proto.Marshal(&gcmpb.AesGcmKey{Version: 1, KeyValue: key.KeyValue})
+		require.NoError(t, err)
+
+		_, err = km.Primitive(sk)
+		require.ErrorContains(t, err, "aes_gcm_aead_key_manager: ")
+
+		sk, err = proto.Marshal(&gcmpb.AesGcmKey{KeyValue: make([]byte, 20)})
+		require.NoError(t, err)
+
+		_, err = km.Primitive(sk)
+		require.EqualError(t, err, "aes_gcm_aead_key_manager: cannot create new primitive: aes_gcm: "+
+			"invalid AES key size; want 16, 24 or 32, got 20")
+
+		_, err = km.NewKey(nil)
+		require.EqualError(t, err, "aes_gcm_aead_key_manager: invalid key format")
+
+		_, err = km.NewKey([]byte("bad format"))
+		require.EqualError(t, err, "aes_gcm_aead_key_manager: invalid key format")
+
+		skf, err := proto.Marshal(&gcmpb.AesGcmKeyFormat{KeySize: 20})
+		require.NoError(t, err)
+
+		_, err = km.NewKeyData(skf)
+		require.EqualError(t, err, "aes_gcm_aead_key_manager: invalid key format: "+
+			"invalid AES key size; want 16, 24 or 32, got 20")
+	})
+}
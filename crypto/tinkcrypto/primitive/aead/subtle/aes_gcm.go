@@ -0,0 +1,74 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/subtle/random"
+)
+
+const (
+	// AESGCMIVSize is the IV size of AESGCM.
+	AESGCMIVSize = 12
+	// AESGCMTagSize is the tag size of AESGCM.
+	AESGCMTagSize = 16
+)
+
+// AESGCM is an AES-GCM AEAD with 12 bytes IVs and 16 bytes tags. Unlike Tink's AES-GCM primitive, it accepts 24 bytes
+// AES-192 keys, as used by the JWE A192GCM content encryption.
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCM returns an AESGCM with key, 16, 24 or 32 bytes.
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	if err := ValidateAESKeySize(uint32(len(key))); err != nil {
+		return nil, fmt.Errorf("aes_gcm: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes_gcm: %w", err)
+	}
+
+	a, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("aes_gcm: %w", err)
+	}
+
+	return &AESGCM{aead: a}, nil
+}
+
+// Encrypt encrypts plaintext with additionalData and a random IV. The returned ciphertext is the IV followed by the
+// encrypted plaintext and the tag, as Tink's AES-GCM primitive.
+func (a *AESGCM) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	if len(plaintext) > maxInt-AESGCMIVSize-AESGCMTagSize {
+		return nil, errors.New("aes_gcm: plaintext too long")
+	}
+
+	iv := random.GetRandomBytes(AESGCMIVSize)
+
+	return a.aead.Seal(iv, iv, plaintext, additionalData), nil
+}
+
+// Decrypt decrypts ciphertext, the IV followed by the encrypted plaintext and the tag, with additionalData.
+func (a *AESGCM) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < AESGCMIVSize+AESGCMTagSize {
+		return nil, errors.New("aes_gcm: ciphertext too short")
+	}
+
+	pt, err := a.aead.Open(nil, ciphertext[:AESGCMIVSize], ciphertext[AESGCMIVSize:], additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("aes_gcm: %w", err)
+	}
+
+	return pt, nil
+}
@@ -0,0 +1,249 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	tinksubtle "github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/subtle/random"
+)
+
+const (
+	// AESGCMSIVNonceSize is the nonce size of AES-GCM-SIV (RFC 8452).
+	AESGCMSIVNonceSize = 12
+	// AESGCMSIVTagSize is the tag size of AES-GCM-SIV (RFC 8452).
+	AESGCMSIVTagSize = 16
+
+	aesGCMSIVBlockSize = 16
+	// aesGCMSIVMaxSize is the maximum size of the plaintext and additional data, 2^36 bytes (RFC 8452 section 6).
+	aesGCMSIVMaxSize = 1 << 36
+)
+
+// AESGCMSIV is the nonce misuse-resistant AES-GCM-SIV AEAD of RFC 8452: encrypting with a repeated nonce only reveals
+// whether the same plaintext and additional data were encrypted, instead of breaking the confidentiality and
+// integrity of the messages as with AES-GCM. It implements cipher.AEAD with 12 bytes nonces and 16 bytes tags.
+type AESGCMSIV struct {
+	block cipher.Block
+	key   []byte
+}
+
+var _ cipher.AEAD = (*AESGCMSIV)(nil)
+
+// NewAESGCMSIV returns an AESGCMSIV with the key-generating key key, 16 bytes for AES-128-GCM-SIV or 32 bytes for
+// AES-256-GCM-SIV.
+func NewAESGCMSIV(key []byte) (*AESGCMSIV, error) {
+	if len(key) != AES128Size && len(key) != AES256Size {
+		return nil, fmt.Errorf("aes_gcm_siv: invalid AES key size; want 16 or 32, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes_gcm_siv: %w", err)
+	}
+
+	return &AESGCMSIV{block: block, key: key}, nil
+}
+
+// NewAESGCMSIVDetached returns an AES-GCM-SIV DetachedAEAD with 12 bytes nonces. The key must be 16 or 32 bytes.
+func NewAESGCMSIVDetached(key []byte) (*DetachedAEAD, error) {
+	a, err := NewAESGCMSIV(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDetachedAEAD(a), nil
+}
+
+// NonceSize returns the size of the nonces, 12 bytes.
+func (a *AESGCMSIV) NonceSize() int {
+	return AESGCMSIVNonceSize
+}
+
+// Overhead returns the size of the tag appended to ciphertexts, 16 bytes.
+func (a *AESGCMSIV) Overhead() int {
+	return AESGCMSIVTagSize
+}
+
+// Encrypt encrypts plaintext with additionalData and a random nonce. The returned ciphertext is the nonce followed by
+// the encrypted plaintext and the tag, as Tink's AES-GCM-SIV primitive.
+func (a *AESGCMSIV) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	if uint64(len(plaintext)) > aesGCMSIVMaxSize || uint64(len(additionalData)) > aesGCMSIVMaxSize {
+		return nil, errors.New("aes_gcm_siv: plaintext or additional data too long")
+	}
+
+	nonce := random.GetRandomBytes(AESGCMSIVNonceSize)
+
+	return a.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// Decrypt decrypts ciphertext, the nonce followed by the encrypted plaintext and the tag, with additionalData.
+func (a *AESGCMSIV) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < AESGCMSIVNonceSize+AESGCMSIVTagSize {
+		return nil, errors.New("aes_gcm_siv: ciphertext too short")
+	}
+
+	return a.Open(nil, ciphertext[:AESGCMSIVNonceSize], ciphertext[AESGCMSIVNonceSize:], additionalData)
+}
+
+// Seal encrypts and authenticates plaintext with additionalData and nonce and appends the result to dst. It panics if
+// nonce is not 12 bytes or plaintext or additionalData exceed 2^36 bytes.
+func (a *AESGCMSIV) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != AESGCMSIVNonceSize {
+		panic("aes_gcm_siv: incorrect nonce length given to AES-GCM-SIV")
+	}
+
+	if uint64(len(plaintext)) > aesGCMSIVMaxSize || uint64(len(additionalData)) > aesGCMSIVMaxSize {
+		panic("aes_gcm_siv: message too large for AES-GCM-SIV")
+	}
+
+	authKey, encBlock := a.deriveKeys(nonce)
+	tag := computeTag(authKey, encBlock, nonce, plaintext, additionalData)
+
+	ret, out := sliceForAppend(dst, len(plaintext)+AESGCMSIVTagSize)
+	ctr(encBlock, tag, out[:len(plaintext)], plaintext)
+	copy(out[len(plaintext):], tag[:])
+
+	return ret
+}
+
+// Open decrypts and authenticates ciphertext, the encrypted plaintext followed by the tag, with additionalData and
+// nonce and appends the plaintext to dst.
+func (a *AESGCMSIV) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != AESGCMSIVNonceSize {
+		return nil, errors.New("aes_gcm_siv: invalid nonce size")
+	}
+
+	if len(ciphertext) < AESGCMSIVTagSize || uint64(len(ciphertext)) > aesGCMSIVMaxSize+AESGCMSIVTagSize ||
+		uint64(len(additionalData)) > aesGCMSIVMaxSize {
+		return nil, errors.New("aes_gcm_siv: message authentication failure")
+	}
+
+	var tag [AESGCMSIVTagSize]byte
+
+	copy(tag[:], ciphertext[len(ciphertext)-AESGCMSIVTagSize:])
+	ciphertext = ciphertext[:len(ciphertext)-AESGCMSIVTagSize]
+
+	authKey, encBlock := a.deriveKeys(nonce)
+
+	ret, out := sliceForAppend(dst, len(ciphertext))
+	ctr(encBlock, tag, out, ciphertext)
+
+	expected := computeTag(authKey, encBlock, nonce, out, additionalData)
+
+	if subtle.ConstantTimeCompare(expected[:], tag[:]) != 1 {
+		for i := range out {
+			out[i] = 0
+		}
+
+		return nil, errors.New("aes_gcm_siv: message authentication failure")
+	}
+
+	return ret, nil
+}
+
+// deriveKeys derives the message authentication key and the message encryption key of nonce (RFC 8452 section 4).
+func (a *AESGCMSIV) deriveKeys(nonce []byte) ([]byte, cipher.Block) {
+	var in, out [aesGCMSIVBlockSize]byte
+
+	copy(in[4:], nonce)
+
+	const halfBlock = aesGCMSIVBlockSize / 2
+
+	derived := make([]byte, aesGCMSIVBlockSize+len(a.key))
+
+	for i := 0; i < len(derived)/halfBlock; i++ {
+		binary.LittleEndian.PutUint32(in[:4], uint32(i))
+		a.block.Encrypt(out[:], in[:])
+		copy(derived[i*halfBlock:], out[:halfBlock])
+	}
+
+	encBlock, err := aes.NewCipher(derived[aesGCMSIVBlockSize:])
+	if err != nil {
+		// the derived key has the size of the key-generating key, validated by NewAESGCMSIV.
+		panic(err)
+	}
+
+	return derived[:aesGCMSIVBlockSize], encBlock
+}
+
+// computeTag returns the tag of plaintext and additionalData (RFC 8452 section 4).
+func computeTag(authKey []byte, encBlock cipher.Block, nonce, plaintext,
+	additionalData []byte) [AESGCMSIVTagSize]byte {
+	var lengths [aesGCMSIVBlockSize]byte
+
+	binary.LittleEndian.PutUint64(lengths[:8], uint64(len(additionalData))*8) //nolint:gomnd // bits
+	binary.LittleEndian.PutUint64(lengths[8:], uint64(len(plaintext))*8)      //nolint:gomnd // bits
+
+	p, err := tinksubtle.NewPolyval(authKey)
+	if err != nil {
+		// authKey is always a 16 bytes block.
+		panic(err)
+	}
+
+	p.Update(additionalData)
+	p.Update(plaintext)
+	p.Update(lengths[:])
+
+	s := p.Finish()
+
+	for i, b := range nonce {
+		s[i] ^= b
+	}
+
+	s[aesGCMSIVBlockSize-1] &= 0x7f
+
+	var tag [AESGCMSIVTagSize]byte
+
+	encBlock.Encrypt(tag[:], s[:])
+
+	return tag
+}
+
+// ctr xors in with the AES-GCM-SIV key stream of tag into out: the counter block is the tag with its most significant
+// bit set, its first 32 bits being a little-endian counter (RFC 8452 section 4).
+func ctr(encBlock cipher.Block, tag [AESGCMSIVTagSize]byte, out, in []byte) {
+	var block, stream [aesGCMSIVBlockSize]byte
+
+	copy(block[:], tag[:])
+	block[aesGCMSIVBlockSize-1] |= 0x80
+
+	counter := binary.LittleEndian.Uint32(block[:4])
+
+	for i := 0; i < len(in); i += aesGCMSIVBlockSize {
+		binary.LittleEndian.PutUint32(block[:4], counter)
+		encBlock.Encrypt(stream[:], block[:])
+
+		counter++
+
+		end := i + aesGCMSIVBlockSize
+		if end > len(in) {
+			end = len(in)
+		}
+
+		for j := i; j < end; j++ {
+			out[j] = in[j] ^ stream[j-i]
+		}
+	}
+}
+
+// sliceForAppend extends in by n bytes, returning the whole slice and the appended bytes.
+func sliceForAppend(in []byte, n int) ([]byte, []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		return in[:total], in[len(in):total]
+	}
+
+	ret := make([]byte, len(in)+n)
+	copy(ret, in)
+
+	return ret, ret[len(in):]
+}
@@ -0,0 +1,135 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	tinksubtle "github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aead/subtle"
+)
+
+func TestAESGCMSIV_RFC8452Vectors(t *testing.T) {
+	// RFC 8452 appendix C.1 and C.2.
+	tests := []struct {
+		key, nonce, plaintext, aad, result string
+	}{
+		{
+			key:    "01000000000000000000000000000000",
+			nonce:  "030000000000000000000000",
+			result: "dc20e2d83f25705bb49e439eca56de25",
+		},
+		{
+			key:    "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce:  "030000000000000000000000",
+			result: "07f5f4169bbf55a8400cd47ea6fd400f",
+		},
+	}
+
+	for _, tc := range tests {
+		a, err := subtle.NewAESGCMSIV(mustHex(t, tc.key))
+		require.NoError(t, err)
+
+		nonce := mustHex(t, tc.nonce)
+		ct := a.Seal(nil, nonce, mustHex(t, tc.plaintext), mustHex(t, tc.aad))
+		require.Equal(t, tc.result, hex.EncodeToString(ct))
+
+		pt, err := a.Open(nil, nonce, ct, mustHex(t, tc.aad))
+		require.NoError(t, err)
+		require.True(t, bytes.Equal(mustHex(t, tc.plaintext), pt))
+	}
+}
+
+func TestAESGCMSIV(t *testing.T) {
+	for _, keySize := range []uint32{16, 32} {
+		key := random.GetRandomBytes(keySize)
+
+		a, err := subtle.NewAESGCMSIV(key)
+		require.NoError(t, err)
+		require.Equal(t, subtle.AESGCMSIVNonceSize, a.NonceSize())
+		require.Equal(t, subtle.AESGCMSIVTagSize, a.Overhead())
+
+		tink, err := tinksubtle.NewAESGCMSIV(key)
+		require.NoError(t, err)
+
+		for _, size := range []int{0, 1, 15, 16, 17, 100, 1024} {
+			plaintext, aad := random.GetRandomBytes(uint32(size)), random.GetRandomBytes(uint32(size/2))
+
+			ct, err := a.Encrypt(plaintext, aad)
+			require.NoError(t, err)
+			require.Len(t, ct, subtle.AESGCMSIVNonceSize+size+subtle.AESGCMSIVTagSize)
+
+			// interoperates with Tink's AES-GCM-SIV primitive.
+			pt, err := tink.Decrypt(ct, aad)
+			require.NoError(t, err)
+			require.True(t, bytes.Equal(plaintext, pt))
+
+			ct, err = tink.Encrypt(plaintext, aad)
+			require.NoError(t, err)
+
+			pt, err = a.Decrypt(ct, aad)
+			require.NoError(t, err)
+			require.True(t, bytes.Equal(plaintext, pt))
+
+			ct[len(ct)-1] ^= 1
+
+			_, err = a.Decrypt(ct, aad)
+			require.EqualError(t, err, "aes_gcm_siv: message authentication failure")
+		}
+
+		// deterministic for a given nonce: a repeated nonce only reveals repeated messages.
+		nonce := random.GetRandomBytes(subtle.AESGCMSIVNonceSize)
+		require.Equal(t, a.Seal(nil, nonce, []byte("msg"), nil), a.Seal(nil, nonce, []byte("msg"), nil))
+		require.NotEqual(t, a.Seal(nil, nonce, []byte("msg"), nil), a.Seal(nil, nonce, []byte("msh"), nil))
+
+		d, err := subtle.NewAESGCMSIVDetached(key)
+		require.NoError(t, err)
+
+		ct, err := d.EncryptWithNonce([]byte("msg"), []byte("aad"), nonce)
+		require.NoError(t, err)
+
+		pt, err := d.DecryptDetached(ct, []byte("aad"), nonce)
+		require.NoError(t, err)
+		require.Equal(t, []byte("msg"), pt)
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := subtle.NewAESGCMSIV(make([]byte, 24))
+		require.EqualError(t, err, "aes_gcm_siv: invalid AES key size; want 16 or 32, got 24")
+
+		_, err = subtle.NewAESGCMSIVDetached(nil)
+		require.Error(t, err)
+
+		a, err := subtle.NewAESGCMSIV(make([]byte, 16))
+		require.NoError(t, err)
+
+		_, err = a.Decrypt(make([]byte, 27), nil)
+		require.EqualError(t, err, "aes_gcm_siv: ciphertext too short")
+
+		_, err = a.Open(nil, make([]byte, 8), make([]byte, 16), nil)
+		require.EqualError(t, err, "aes_gcm_siv: invalid nonce size")
+
+		_, err = a.Open(nil, make([]byte, 12), make([]byte, 15), nil)
+		require.EqualError(t, err, "aes_gcm_siv: message authentication failure")
+
+		require.Panics(t, func() { a.Seal(nil, make([]byte, 8), nil, nil) })
+	})
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+
+	return b
+}
@@ -0,0 +1,60 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle_test
+
+import (
+	"testing"
+
+	tinksubtle "github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aead/subtle"
+)
+
+func TestAESGCM(t *testing.T) {
+	for _, keySize := range []uint32{subtle.AES128Size, subtle.AES192Size, subtle.AES256Size} {
+		key := random.GetRandomBytes(keySize)
+
+		a, err := subtle.NewAESGCM(key)
+		require.NoError(t, err)
+
+		ct, err := a.Encrypt([]byte("plaintext"), []byte("aad"))
+		require.NoError(t, err)
+		require.Len(t, ct, subtle.AESGCMIVSize+len("plaintext")+subtle.AESGCMTagSize)
+
+		pt, err := a.Decrypt(ct, []byte("aad"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("plaintext"), pt)
+
+		_, err = a.Decrypt(ct, []byte("other aad"))
+		require.EqualError(t, err, "aes_gcm: cipher: message authentication failed")
+
+		if keySize == subtle.AES192Size {
+			continue
+		}
+
+		// interoperates with Tink's AES-GCM primitive for the key sizes it supports.
+		tink, err := tinksubtle.NewAESGCM(key)
+		require.NoError(t, err)
+
+		pt, err = tink.Decrypt(ct, []byte("aad"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("plaintext"), pt)
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := subtle.NewAESGCM(make([]byte, 20))
+		require.EqualError(t, err, "aes_gcm: invalid AES key size; want 16, 24 or 32, got 20")
+
+		a, err := subtle.NewAESGCM(make([]byte, subtle.AES192Size))
+		require.NoError(t, err)
+
+		_, err = a.Decrypt(make([]byte, 27), nil)
+		require.EqualError(t, err, "aes_gcm: ciphertext too short")
+	})
+}
@@ -0,0 +1,107 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// DetachedAEAD is an AEAD primitive taking the nonce as an argument instead of generating it and prefixing it to the
+// ciphertext, for protocols carrying the IV in headers (eg: JWE or COSE). Ciphertexts are the encrypted plaintext
+// followed by the tag. The caller is responsible of never reusing a nonce with the same key.
+type DetachedAEAD struct {
+	aead cipher.AEAD
+}
+
+// NewDetachedAEAD returns a DetachedAEAD using the cipher.AEAD a.
+func NewDetachedAEAD(a cipher.AEAD) *DetachedAEAD {
+	return &DetachedAEAD{aead: a}
+}
+
+// NewAESGCMDetached returns an AES-GCM DetachedAEAD with 12 bytes nonces. The key must be 16, 24 or 32 bytes.
+func NewAESGCMDetached(key []byte) (*DetachedAEAD, error) {
+	if err := ValidateAESKeySize(uint32(len(key))); err != nil {
+		return nil, fmt.Errorf("aes_gcm_detached: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes_gcm_detached: %w", err)
+	}
+
+	a, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("aes_gcm_detached: %w", err)
+	}
+
+	return NewDetachedAEAD(a), nil
+}
+
+// NewChaCha20Poly1305Detached returns a ChaCha20Poly1305 DetachedAEAD with 12 bytes nonces.
+func NewChaCha20Poly1305Detached(key []byte) (*DetachedAEAD, error) {
+	a, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, fmt.Errorf("chacha20poly1305_detached: %w", err)
+	}
+
+	return NewDetachedAEAD(a), nil
+}
+
+// NewXChaCha20Poly1305Detached returns a XChaCha20Poly1305 DetachedAEAD with 24 bytes nonces.
+func NewXChaCha20Poly1305Detached(key []byte) (*DetachedAEAD, error) {
+	a, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, fmt.Errorf("xchacha20poly1305_detached: %w", err)
+	}
+
+	return NewDetachedAEAD(a), nil
+}
+
+// NonceSize returns the size of the nonces accepted by d.
+func (d *DetachedAEAD) NonceSize() int {
+	return d.aead.NonceSize()
+}
+
+// EncryptWithNonce encrypts plaintext with additionalData and nonce, which must be NonceSize() long.
+func (d *DetachedAEAD) EncryptWithNonce(plaintext, additionalData, nonce []byte) ([]byte, error) {
+	if err := checkNonceSize(nonce, d.aead.NonceSize()); err != nil {
+		return nil, err
+	}
+
+	if len(plaintext) > maxInt-d.aead.Overhead() {
+		return nil, errors.New("detached_aead: plaintext too long")
+	}
+
+	return d.aead.Seal(nil, nonce, plaintext, additionalData), nil
+}
+
+// DecryptDetached decrypts ciphertext with additionalData and the nonce it was encrypted with.
+func (d *DetachedAEAD) DecryptDetached(ciphertext, additionalData, nonce []byte) ([]byte, error) {
+	if err := checkNonceSize(nonce, d.aead.NonceSize()); err != nil {
+		return nil, err
+	}
+
+	plaintext, err := d.aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("detached_aead: failed to decrypt: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+func checkNonceSize(nonce []byte, size int) error {
+	if len(nonce) != size {
+		return fmt.Errorf("detached_aead: invalid nonce size; want %d, got %d", size, len(nonce))
+	}
+
+	return nil
+}
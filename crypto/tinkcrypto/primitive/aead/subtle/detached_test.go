@@ -0,0 +1,71 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle_test
+
+import (
+	"testing"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aead/subtle"
+)
+
+func TestDetachedAEAD(t *testing.T) {
+	tests := []struct {
+		name      string
+		newAEAD   func(key []byte) (*subtle.DetachedAEAD, error)
+		keySize   uint32
+		nonceSize int
+	}{
+		{name: "AES-128-GCM", newAEAD: subtle.NewAESGCMDetached, keySize: 16, nonceSize: 12},
+		{name: "AES-256-GCM", newAEAD: subtle.NewAESGCMDetached, keySize: 32, nonceSize: 12},
+		{name: "ChaCha20Poly1305", newAEAD: subtle.NewChaCha20Poly1305Detached, keySize: 32, nonceSize: 12},
+		{name: "XChaCha20Poly1305", newAEAD: subtle.NewXChaCha20Poly1305Detached, keySize: 32, nonceSize: 24},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := tc.newAEAD(random.GetRandomBytes(tc.keySize))
+			require.NoError(t, err)
+			require.Equal(t, tc.nonceSize, a.NonceSize())
+
+			plaintext, aad := []byte("plaintext"), []byte("aad")
+			nonce := random.GetRandomBytes(uint32(tc.nonceSize))
+
+			ct, err := a.EncryptWithNonce(plaintext, aad, nonce)
+			require.NoError(t, err)
+
+			pt, err := a.DecryptDetached(ct, aad, nonce)
+			require.NoError(t, err)
+			require.Equal(t, plaintext, pt)
+
+			_, err = a.DecryptDetached(ct, []byte("other aad"), nonce)
+			require.ErrorContains(t, err, "detached_aead: failed to decrypt")
+
+			_, err = a.DecryptDetached(ct, aad, random.GetRandomBytes(uint32(tc.nonceSize)))
+			require.ErrorContains(t, err, "detached_aead: failed to decrypt")
+
+			_, err = a.EncryptWithNonce(plaintext, aad, nonce[1:])
+			require.ErrorContains(t, err, "invalid nonce size")
+
+			_, err = a.DecryptDetached(ct, aad, append(nonce, 0))
+			require.ErrorContains(t, err, "invalid nonce size")
+		})
+	}
+
+	t.Run("invalid keys", func(t *testing.T) {
+		_, err := subtle.NewAESGCMDetached(make([]byte, 20))
+		require.ErrorContains(t, err, "invalid AES key size")
+
+		_, err = subtle.NewChaCha20Poly1305Detached(make([]byte, 16))
+		require.Error(t, err)
+
+		_, err = subtle.NewXChaCha20Poly1305Detached(make([]byte, 16))
+		require.Error(t, err)
+	})
+}
@@ -82,6 +82,37 @@ func (a *AESCBCHMAC) Decrypt(ciphertext, additionalData []byte) ([]byte, error)
 	return plaintext, nil
 }
 
+// EncryptWithNonce encrypts plaintext with the AESCBCIVSize bytes IV nonce. Unlike Encrypt, the IV is not prefixed to
+// the resulting ciphertext, it is carried separately (eg: in the JWE IV header).
+func (a *AESCBCHMAC) EncryptWithNonce(plaintext, additionalData, nonce []byte) ([]byte, error) {
+	cbcHMAC, err := josecipher.NewCBCHMAC(a.Key, aes.NewCipher)
+	if err != nil {
+		return nil, fmt.Errorf("aes_cbc_hmac: %w", err)
+	}
+
+	ct, err := NewDetachedAEAD(cbcHMAC).EncryptWithNonce(plaintext, additionalData, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("aes_cbc_hmac: %w", err)
+	}
+
+	return ct, nil
+}
+
+// DecryptDetached decrypts ciphertext, created by EncryptWithNonce, with its IV nonce.
+func (a *AESCBCHMAC) DecryptDetached(ciphertext, additionalData, nonce []byte) ([]byte, error) {
+	cbcHMAC, err := josecipher.NewCBCHMAC(a.Key, aes.NewCipher)
+	if err != nil {
+		return nil, fmt.Errorf("aes_cbc_hmac: %w", err)
+	}
+
+	pt, err := NewDetachedAEAD(cbcHMAC).DecryptDetached(ciphertext, additionalData, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("aes_cbc_hmac: %w", err)
+	}
+
+	return pt, nil
+}
+
 // newIV creates a new IV for encryption.
 func (a *AESCBCHMAC) newIV() []byte {
 	return random.GetRandomBytes(uint32(AESCBCIVSize))
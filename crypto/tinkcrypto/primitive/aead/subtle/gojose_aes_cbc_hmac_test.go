@@ -223,6 +223,20 @@ func TestIETFTestVector(t *testing.T) {
 			if !bytes.Equal(out[len(out)-tagSize:], tc.expectedAuthtag) {
 				t.Error("Auth tag did not match, got", out[len(out)-tagSize:], "wanted", tc.expectedAuthtag)
 			}
+
+			detached, err := subtle.NewAESCBCHMAC(tc.key)
+			require.NoError(t, err)
+
+			detachedCT, err := detached.EncryptWithNonce(plaintext, aad, tc.nonce)
+			require.NoError(t, err)
+			require.EqualValues(t, ct[len(nonce):], detachedCT)
+
+			out2, err := detached.DecryptDetached(detachedCT, aad, tc.nonce)
+			require.NoError(t, err)
+			require.EqualValues(t, plaintext, out2)
+
+			_, err = detached.EncryptWithNonce(plaintext, aad, tc.nonce[1:])
+			require.EqualError(t, err, "aes_cbc_hmac: detached_aead: invalid nonce size; want 16, got 15")
 		})
 	}
 }
@@ -312,3 +326,49 @@ func RunRoundtrip(t *testing.T, key []byte) {
 			"(auth tag mismatch)")
 	})
 }
+
+// FuzzAESCBCHMACDecrypt checks that Decrypt rejects malformed ciphertexts without panicking and that it decrypts the
+// ciphertexts of Encrypt, for the three AES-CBC-HMAC key sizes.
+func FuzzAESCBCHMACDecrypt(f *testing.F) {
+	key := make([]byte, 64)
+	for i := range key {
+		key[i] = byte(i)
+	}
+
+	for _, keySize := range []int{32, 48, 64} {
+		aead, err := subtle.NewAESCBCHMAC(key[:keySize])
+		require.NoError(f, err)
+
+		ct, err := aead.Encrypt([]byte("plaintext"), []byte("aad"))
+		require.NoError(f, err)
+
+		f.Add(uint8(keySize), []byte("plaintext"), []byte("aad"), ct)
+		f.Add(uint8(keySize), []byte{}, []byte{}, ct[:len(ct)-1])
+	}
+
+	f.Add(uint8(32), []byte{}, []byte{}, []byte{})
+	f.Add(uint8(48), []byte("x"), []byte{}, make([]byte, 48))
+
+	f.Fuzz(func(t *testing.T, keySize uint8, plaintext, aad, ciphertext []byte) {
+		aead, err := subtle.NewAESCBCHMAC(key[:32+16*(int(keySize)%3)])
+		require.NoError(t, err)
+
+		if pt, e := aead.Decrypt(ciphertext, aad); e == nil {
+			ct, e := aead.Encrypt(pt, aad)
+			require.NoError(t, e)
+			require.NotEmpty(t, ct)
+		}
+
+		ct, err := aead.Encrypt(plaintext, aad)
+		require.NoError(t, err)
+
+		pt, err := aead.Decrypt(ct, aad)
+		require.NoError(t, err)
+		require.True(t, bytes.Equal(plaintext, pt))
+
+		ct[len(ct)-1] ^= 1
+
+		_, err = aead.Decrypt(ct, aad)
+		require.Error(t, err)
+	})
+}
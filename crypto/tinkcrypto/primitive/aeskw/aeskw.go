@@ -0,0 +1,66 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package aeskw provides a Tink key manager and factory of AES key wrap (RFC 3394, the A128KW, A192KW and A256KW JWE
+// algorithms) KeyWrapper primitives. They wrap arbitrary key material (a multiple of 8 bytes, 16 bytes or more) with a
+// symmetric key encryption key (KEK), without an ECDH key agreement.
+//
+// To wrap keys using Tink you can use the AES-KW key templates.
+package aeskw
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/core/registry"
+	gcmpb "github.com/google/tink/go/proto/aes_gcm_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// TypeURL is the type URL of AES-KW keys.
+const TypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.AesKwKey"
+
+// KeyWrapper wraps and unwraps key material with a KEK.
+type KeyWrapper interface {
+	// Wrap wraps key, its size must be a multiple of 8 bytes and 16 bytes or more.
+	Wrap(key []byte) ([]byte, error)
+	// Unwrap unwraps a wrapped key and checks its integrity.
+	Unwrap(wrapped []byte) ([]byte, error)
+	// KeySize returns the KEK size in bytes (16, 24 or 32), which sets the JWE algorithm (A128KW, A192KW or A256KW).
+	KeySize() int
+}
+
+// nolint:gochecknoinits
+func init() {
+	if err := registry.RegisterKeyManager(new(keyManager)); err != nil {
+		panic(fmt.Sprintf("aeskw.init() failed: %v", err))
+	}
+}
+
+// A128KWKeyTemplate is a KeyTemplate that generates a new 16 bytes AES-KW KEK. Wrapped keys are RAW (no Tink prefix).
+func A128KWKeyTemplate() *tinkpb.KeyTemplate {
+	return createKeyTemplate(keySize128)
+}
+
+// A192KWKeyTemplate is a KeyTemplate that generates a new 24 bytes AES-KW KEK. Wrapped keys are RAW (no Tink prefix).
+func A192KWKeyTemplate() *tinkpb.KeyTemplate {
+	return createKeyTemplate(keySize192)
+}
+
+// A256KWKeyTemplate is a KeyTemplate that generates a new 32 bytes AES-KW KEK. Wrapped keys are RAW (no Tink prefix).
+func A256KWKeyTemplate() *tinkpb.KeyTemplate {
+	return createKeyTemplate(keySize256)
+}
+
+func createKeyTemplate(keySize uint32) *tinkpb.KeyTemplate {
+	serializedFormat, _ := proto.Marshal(&gcmpb.AesGcmKeyFormat{KeySize: keySize}) //nolint:errcheck
+
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          TypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+	}
+}
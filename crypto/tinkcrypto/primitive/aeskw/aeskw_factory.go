@@ -0,0 +1,63 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aeskw
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+// New returns the KeyWrapper of the primary key of kh. Unwrapping tries all the enabled keys of kh, so keys wrapped
+// before a KEK rotation can still be unwrapped.
+func New(kh *keyset.Handle) (KeyWrapper, error) {
+	ps, err := kh.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("aeskw_factory: cannot obtain primitive set: %w", err)
+	}
+
+	primary, ok := ps.Primary.Primitive.(KeyWrapper)
+	if !ok || ps.Primary.PrefixType != tinkpb.OutputPrefixType_RAW {
+		return nil, errors.New("aeskw_factory: not a RAW AES-KW KeyWrapper primitive")
+	}
+
+	w := &wrappedKeyWrapper{KeyWrapper: primary}
+
+	for _, entries := range ps.Entries {
+		for _, e := range entries {
+			if p, ok := e.Primitive.(KeyWrapper); ok && e.PrefixType == tinkpb.OutputPrefixType_RAW {
+				w.all = append(w.all, p)
+			}
+		}
+	}
+
+	return w, nil
+}
+
+// wrappedKeyWrapper wraps with the primary key and unwraps with any key of a keyset.
+type wrappedKeyWrapper struct {
+	KeyWrapper
+	all []KeyWrapper
+}
+
+// Unwrap unwraps wrapped with the first key of the keyset that checks its integrity.
+func (w *wrappedKeyWrapper) Unwrap(wrapped []byte) ([]byte, error) {
+	key, err := w.KeyWrapper.Unwrap(wrapped)
+	if err == nil {
+		return key, nil
+	}
+
+	for _, p := range w.all {
+		if k, e := p.Unwrap(wrapped); e == nil {
+			return k, nil
+		}
+	}
+
+	return nil, err
+}
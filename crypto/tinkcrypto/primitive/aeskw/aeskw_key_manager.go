@@ -0,0 +1,157 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aeskw
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	josecipher "github.com/go-jose/go-jose/v3/cipher"
+	"github.com/google/tink/go/core/registry"
+	gcmpb "github.com/google/tink/go/proto/aes_gcm_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/subtle/random"
+	"google.golang.org/protobuf/proto"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+)
+
+const (
+	keyVersion = 0
+	keySize128 = 16
+	keySize192 = 24
+	keySize256 = 32
+)
+
+var errInvalidKey = errors.New("aeskw_key_manager: invalid key")
+
+// keyManager generates AES-KW keys and creates their KeyWrapper primitives. Keys reuse the Tink AES-GCM key protos.
+type keyManager struct{}
+
+var _ registry.KeyManager = (*keyManager)(nil)
+
+// Primitive creates the KeyWrapper primitive of the serialized AES-KW key.
+func (km *keyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	key := new(gcmpb.AesGcmKey)
+
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidKey
+	}
+
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	return newKeyWrapper(key.KeyValue)
+}
+
+// NewKey creates a new AES-KW key of the given serialized AesGcmKeyFormat.
+func (km *keyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	format := new(gcmpb.AesGcmKeyFormat)
+
+	if err := proto.Unmarshal(serializedKeyFormat, format); err != nil {
+		return nil, fmt.Errorf("aeskw_key_manager: invalid key format: %w", err)
+	}
+
+	if err := validateKeySize(format.KeySize); err != nil {
+		return nil, err
+	}
+
+	return &gcmpb.AesGcmKey{Version: keyVersion, KeyValue: random.GetRandomBytes(format.KeySize)}, nil
+}
+
+// NewKeyData creates a new KeyData of the given serialized AesGcmKeyFormat.
+func (km *keyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, fmt.Errorf("aeskw_key_manager: %w", err)
+	}
+
+	return &tinkpb.KeyData{
+		TypeUrl:         TypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *keyManager) DoesSupport(typeURL string) bool {
+	return typeURL == TypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *keyManager) TypeURL() string {
+	return TypeURL
+}
+
+func validateKey(key *gcmpb.AesGcmKey) error {
+	if key.Version != keyVersion {
+		return errInvalidKey
+	}
+
+	return validateKeySize(uint32(len(key.KeyValue)))
+}
+
+func validateKeySize(size uint32) error {
+	switch size {
+	case keySize128, keySize192, keySize256:
+		return nil
+	default:
+		return fmt.Errorf("aeskw_key_manager: invalid key size %d", size)
+	}
+}
+
+// kw is the AES key wrap KeyWrapper primitive.
+type kw struct {
+	block cipher.Block
+	size  int
+}
+
+func newKeyWrapper(kek []byte) (*kw, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("aeskw: %w", err)
+	}
+
+	return &kw{block: block, size: len(kek)}, nil
+}
+
+// Wrap wraps key.
+func (k *kw) Wrap(key []byte) ([]byte, error) {
+	wrapped, err := josecipher.KeyWrap(k.block, key)
+	if err != nil {
+		return nil, fmt.Errorf("aeskw: %w", err)
+	}
+
+	return wrapped, nil
+}
+
+// Unwrap unwraps a wrapped key, integrity check failures wrap cryptoapi.ErrAuthTagMismatch.
+func (k *kw) Unwrap(wrapped []byte) ([]byte, error) {
+	key, err := josecipher.KeyUnwrap(k.block, wrapped)
+	if err != nil && len(wrapped)%8 == 0 {
+		return nil, fmt.Errorf("aeskw: %w: %w", cryptoapi.ErrAuthTagMismatch, err)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("aeskw: %w", err)
+	}
+
+	return key, nil
+}
+
+// KeySize returns the KEK size.
+func (k *kw) KeySize() int {
+	return k.size
+}
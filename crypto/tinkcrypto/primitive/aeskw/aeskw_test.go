@@ -0,0 +1,183 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aeskw
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	gcmpb "github.com/google/tink/go/proto/aes_gcm_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+)
+
+func rawKeyHandle(t *testing.T, kek []byte) *keyset.Handle {
+	t.Helper()
+
+	value, err := proto.Marshal(&gcmpb.AesGcmKey{KeyValue: kek})
+	require.NoError(t, err)
+
+	kh, err := insecurecleartextkeyset.Read(&keyset.MemReaderWriter{Keyset: &tinkpb.Keyset{
+		PrimaryKeyId: 1,
+		Key: []*tinkpb.Keyset_Key{{
+			KeyData:          &tinkpb.KeyData{TypeUrl: TypeURL, Value: value, KeyMaterialType: tinkpb.KeyData_SYMMETRIC},
+			Status:           tinkpb.KeyStatusType_ENABLED,
+			KeyId:            1,
+			OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+		}},
+	}})
+	require.NoError(t, err)
+
+	return kh
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+
+	return b
+}
+
+// RFC 3394 section 4 test vectors.
+func TestKeyWrapVectors(t *testing.T) {
+	keyData := "00112233445566778899AABBCCDDEEFF"
+	tests := []struct {
+		kek, key, wrapped string
+		size              int
+	}{
+		{
+			"000102030405060708090A0B0C0D0E0F", keyData,
+			"1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5", 16,
+		},
+		{
+			"000102030405060708090A0B0C0D0E0F1011121314151617", keyData,
+			"96778B25AE6CA435F92B5B97C050AED2468AB8A17AD84E5D", 24,
+		},
+		{
+			"000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F", keyData + "0001020304050607",
+			"A8F9BC1612C68B3FF6E6F4FBE30E71E4769C8B80A32CB8958CD5D17D6B254DA1", 32,
+		},
+	}
+
+	for _, tc := range tests {
+		w, err := New(rawKeyHandle(t, decodeHex(t, tc.kek)))
+		require.NoError(t, err)
+		require.Equal(t, tc.size, w.KeySize())
+
+		wrapped, err := w.Wrap(decodeHex(t, tc.key))
+		require.NoError(t, err)
+		require.Equal(t, decodeHex(t, tc.wrapped), wrapped)
+
+		key, err := w.Unwrap(wrapped)
+		require.NoError(t, err)
+		require.Equal(t, decodeHex(t, tc.key), key)
+
+		wrapped[0] ^= 1
+
+		_, err = w.Unwrap(wrapped)
+		require.ErrorContains(t, err, "aeskw")
+		require.ErrorIs(t, err, cryptoapi.ErrAuthTagMismatch)
+
+		_, err = w.Unwrap(wrapped[1:])
+		require.NotErrorIs(t, err, cryptoapi.ErrAuthTagMismatch)
+	}
+}
+
+func TestKeyTemplates(t *testing.T) {
+	for size, template := range map[int]*tinkpb.KeyTemplate{
+		16: A128KWKeyTemplate(),
+		24: A192KWKeyTemplate(),
+		32: A256KWKeyTemplate(),
+	} {
+		kh, err := keyset.NewHandle(template)
+		require.NoError(t, err)
+
+		w, err := New(kh)
+		require.NoError(t, err)
+		require.Equal(t, size, w.KeySize())
+
+		_, err = w.Wrap([]byte("short"))
+		require.ErrorContains(t, err, "aeskw")
+	}
+}
+
+func TestRotatedKeyset(t *testing.T) {
+	m := keyset.NewManager()
+
+	oldID, err := m.Add(A256KWKeyTemplate())
+	require.NoError(t, err)
+	require.NoError(t, m.SetPrimary(oldID))
+
+	oldKH, err := m.Handle()
+	require.NoError(t, err)
+
+	oldW, err := New(oldKH)
+	require.NoError(t, err)
+
+	key := decodeHex(t, "00112233445566778899AABBCCDDEEFF")
+
+	wrapped, err := oldW.Wrap(key)
+	require.NoError(t, err)
+
+	id, err := m.Add(A256KWKeyTemplate())
+	require.NoError(t, err)
+	require.NoError(t, m.SetPrimary(id))
+
+	kh, err := m.Handle()
+	require.NoError(t, err)
+
+	w, err := New(kh)
+	require.NoError(t, err)
+
+	newWrapped, err := w.Wrap(key)
+	require.NoError(t, err)
+	require.NotEqual(t, wrapped, newWrapped)
+
+	// keys wrapped with the previous primary key can still be unwrapped.
+	unwrapped, err := w.Unwrap(wrapped)
+	require.NoError(t, err)
+	require.Equal(t, key, unwrapped)
+}
+
+func TestKeyManagerErrors(t *testing.T) {
+	km := new(keyManager)
+
+	require.True(t, km.DoesSupport(TypeURL))
+	require.Equal(t, TypeURL, km.TypeURL())
+
+	_, err := km.Primitive([]byte("invalid"))
+	require.ErrorIs(t, err, errInvalidKey)
+
+	_, err = km.NewKey([]byte("invalid"))
+	require.ErrorContains(t, err, "invalid key format")
+
+	format, err := proto.Marshal(&gcmpb.AesGcmKeyFormat{KeySize: 20})
+	require.NoError(t, err)
+
+	_, err = km.NewKeyData(format)
+	require.ErrorContains(t, err, "invalid key size 20")
+
+	value, err := proto.Marshal(&gcmpb.AesGcmKey{Version: 1, KeyValue: make([]byte, 16)})
+	require.NoError(t, err)
+
+	_, err = km.Primitive(value)
+	require.ErrorIs(t, err, errInvalidKey)
+
+	kh, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	require.NoError(t, err)
+
+	_, err = New(kh)
+	require.ErrorContains(t, err, "not a RAW AES-KW KeyWrapper primitive")
+}
@@ -0,0 +1,250 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+
+	ml "github.com/IBM/mathlib"
+	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+)
+
+// A blind signature is a signature of messages some of which are hidden from the signer, eg: a holder binding
+// secret. The holder commits to the hidden messages with NewBlindCommitment: the commitment
+// C = h0^s' * h[i]^m[i]... of the hidden messages m[i] and a random blinding factor s', with a Schnorr proof of
+// knowledge of its opening bound to a signer nonce. The signer verifies the proof and signs the commitment along with
+// the known messages with BlindSign: A = (g1 * C * h0^s'' * h[j]^m[j]...)^(1/(x+e)). The holder unblinds the
+// signature (A, e, s'') with UnblindSignature into the regular signature (A, e, s' + s'') of all the messages.
+
+var errInvalidBlindCommitment = errors.New("invalid blind commitment")
+
+// NewBlindCommitment returns the commitment to the hidden messages, by index among the messagesCount messages to
+// sign, for a blind signature of the signer's public key pubKeyBytes, and the blinding factor of the commitment. nonce
+// is the signer nonce of the signature request. The blinding factor must be kept secret, it unblinds the signature.
+func NewBlindCommitment(pubKeyBytes []byte, messagesCount int, hidden map[int][]byte,
+	nonce []byte) ([]byte, []byte, error) {
+	indexes, err := blindIndexes(messagesCount, hidden)
+	if err != nil {
+		return nil, nil, fmt.Errorf("new blind commitment: %w", err)
+	}
+
+	gens, err := generatorsFor(pubKeyBytes, messagesCount, runtime.GOMAXPROCS(0))
+	if err != nil {
+		return nil, nil, fmt.Errorf("new blind commitment: %w", err)
+	}
+
+	messages := make([][]byte, len(indexes))
+
+	for j, i := range indexes {
+		messages[j] = hidden[i]
+	}
+
+	// the secrets of the proof are the blinding factor and the hidden messages.
+	secrets := append([]*ml.Zr{curve.NewRandomZr(rand.Reader)}, parseMessages(messages, 1)...)
+	bases := gens.blindBases(indexes)
+	randoms := make([]*ml.Zr, len(secrets))
+
+	for i := range randoms {
+		randoms[i] = curve.NewRandomZr(rand.Reader)
+	}
+
+	c := multiExp(bases, secrets)
+	t := multiExp(bases, randoms)
+	challenge := blindChallenge(c, t, messagesCount, indexes, nonce)
+
+	commitment := append(c.Compressed(), t.Compressed()...)
+
+	for i := range secrets {
+		response := randoms[i].Minus(challenge.Mul(secrets[i]))
+		response.Mod(curve.GroupOrder)
+
+		commitment = append(commitment, response.Bytes()...)
+	}
+
+	return commitment, secrets[0].Bytes(), nil
+}
+
+// BlindSign signs the messagesCount messages of the blind commitment of the hidden messages (see NewBlindCommitment)
+// and of the known messages, by index. The messages that are not known are the hidden ones. It fails if the proof of
+// knowledge of the commitment is not valid for nonce.
+// returns:
+//
+//	blind signature in []byte, to unblind with UnblindSignature
+//	error in case of errors
+func (s *BLS12381G2Signer) BlindSign(commitment []byte, messagesCount int, known map[int][]byte,
+	nonce []byte) ([]byte, error) {
+	privKey, err := bbs12381g2pub.UnmarshalPrivateKey(s.privateKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("blind sign: %w", err)
+	}
+
+	pubKeyBytes, err := privKey.PublicKey().Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("blind sign: %w", err)
+	}
+
+	gens, err := generatorsFor(pubKeyBytes, messagesCount, runtime.GOMAXPROCS(0))
+	if err != nil {
+		return nil, fmt.Errorf("blind sign: %w", err)
+	}
+
+	c, err := verifyBlindCommitment(gens, commitment, messagesCount, known, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("blind sign: %w", err)
+	}
+
+	knownIndexes := make([]int, 0, len(known))
+	knownMessages := make([][]byte, 0, len(known))
+
+	for i := 0; i < messagesCount; i++ {
+		if m, ok := known[i]; ok {
+			knownIndexes = append(knownIndexes, i)
+			knownMessages = append(knownMessages, m)
+		}
+	}
+
+	e, sPrime := curve.NewRandomZr(rand.Reader), curve.NewRandomZr(rand.Reader)
+
+	b := gens.commitMessages(sPrime, parseMessages(knownMessages, 1), knownIndexes, 1)
+	b.Add(c)
+
+	exp := privKey.FR.Plus(e)
+	exp.Mod(curve.GroupOrder)
+	exp.InvModP(curve.GroupOrder)
+
+	sig := &bbs12381g2pub.Signature{A: b.Mul(exp), E: e, S: sPrime}
+
+	return sig.ToBytes()
+}
+
+// UnblindSignature returns the signature of all the messages of the blind signature blindSignature of BlindSign,
+// unblinded with the blinding factor of the commitment of NewBlindCommitment.
+func UnblindSignature(blindSignature, blinding []byte) ([]byte, error) {
+	if len(blinding) != curve.ScalarByteSize {
+		return nil, errors.New("unblind signature: invalid blinding factor")
+	}
+
+	sig, err := bbs12381g2pub.ParseSignature(blindSignature)
+	if err != nil {
+		return nil, fmt.Errorf("unblind signature: %w", err)
+	}
+
+	sig.S = sig.S.Plus(curve.NewZrFromBytes(blinding))
+	sig.S.Mod(curve.GroupOrder)
+
+	return sig.ToBytes()
+}
+
+// verifyBlindCommitment verifies the proof of knowledge of commitment and returns the commitment point.
+func verifyBlindCommitment(gens *generators, commitment []byte, messagesCount int, known map[int][]byte,
+	nonce []byte) (*ml.G1, error) {
+	var indexes []int
+
+	for i := 0; i < messagesCount; i++ {
+		if _, ok := known[i]; !ok {
+			indexes = append(indexes, i)
+		}
+	}
+
+	if len(indexes) == 0 || len(known) != messagesCount-len(indexes) {
+		return nil, errors.New("invalid known messages indexes")
+	}
+
+	g1Size := curve.CompressedG1ByteSize
+
+	if len(commitment) != 2*g1Size+(len(indexes)+1)*curve.ScalarByteSize {
+		return nil, errInvalidBlindCommitment
+	}
+
+	c, err := curve.NewG1FromCompressed(commitment[:g1Size])
+	if err != nil {
+		return nil, errInvalidBlindCommitment
+	}
+
+	t, err := curve.NewG1FromCompressed(commitment[g1Size : 2*g1Size])
+	if err != nil {
+		return nil, errInvalidBlindCommitment
+	}
+
+	challenge := blindChallenge(c, t, messagesCount, indexes, nonce)
+
+	responses := make([]*ml.Zr, len(indexes)+1)
+
+	for i := range responses {
+		offset := 2*g1Size + i*curve.ScalarByteSize
+		responses[i] = curve.NewZrFromBytes(commitment[offset : offset+curve.ScalarByteSize])
+	}
+
+	// t = h0^r' * h[i]^r[i]... = C^challenge * h0^z' * h[i]^z[i]...
+	expected := multiExp(gens.blindBases(indexes), responses)
+	expected.Add(c.Mul(challenge))
+
+	if !expected.Equals(t) {
+		return nil, errors.New("invalid blind commitment proof")
+	}
+
+	return c, nil
+}
+
+// blindIndexes returns the sorted indexes of the hidden messages of a blind commitment.
+func blindIndexes(messagesCount int, hidden map[int][]byte) ([]int, error) {
+	if len(hidden) == 0 {
+		return nil, errors.New("no hidden messages")
+	}
+
+	indexes := make([]int, 0, len(hidden))
+
+	for i := range hidden {
+		if i < 0 || i >= messagesCount {
+			return nil, fmt.Errorf("invalid hidden message index %d", i)
+		}
+
+		indexes = append(indexes, i)
+	}
+
+	sort.Ints(indexes)
+
+	return indexes, nil
+}
+
+// blindBases returns h0 and the h generators of indexes.
+func (g *generators) blindBases(indexes []int) []*ml.G1 {
+	bases := []*ml.G1{g.h0}
+
+	for _, i := range indexes {
+		bases = append(bases, g.h[i])
+	}
+
+	return bases
+}
+
+func blindChallenge(c, t *ml.G1, messagesCount int, indexes []int, nonce []byte) *ml.Zr {
+	data := append(c.Bytes(), t.Bytes()...)
+	data = append(data, uint32Bytes(messagesCount)...)
+
+	for _, i := range indexes {
+		data = append(data, uint32Bytes(i)...)
+	}
+
+	data = append(data, nonce...)
+
+	return curve.HashToZr(data)
+}
+
+func multiExp(bases []*ml.G1, scalars []*ml.Zr) *ml.G1 {
+	res := bases[0].Mul(scalars[0])
+
+	for i := 1; i < len(bases); i++ {
+		res.Add(bases[i].Mul(scalars[i]))
+	}
+
+	return res
+}
@@ -0,0 +1,102 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBBSG2_BlindSign(t *testing.T) {
+	pubKey, privKey, err := generateKeyPairRandom()
+	require.NoError(t, err)
+
+	privKeyBytes, err := privKey.Marshal()
+	require.NoError(t, err)
+
+	pubKeyBytes, err := pubKey.Marshal()
+	require.NoError(t, err)
+
+	blsSigner := NewBLS12381G2Signer(privKeyBytes)
+	blsVerifier := NewBLS12381G2Verifier(pubKeyBytes)
+
+	messages := [][]byte{[]byte("link secret"), []byte("name"), []byte("age"), []byte("holder salt")}
+	hidden := map[int][]byte{0: messages[0], 3: messages[3]}
+	known := map[int][]byte{1: messages[1], 2: messages[2]}
+	nonce := []byte("issuer nonce")
+
+	commitment, blinding, err := NewBlindCommitment(pubKeyBytes, len(messages), hidden, nonce)
+	require.NoError(t, err)
+
+	blindSig, err := blsSigner.BlindSign(commitment, len(messages), known, nonce)
+	require.NoError(t, err)
+
+	// the blind signature is not a signature of the messages.
+	require.Error(t, blsVerifier.Verify(messages, blindSig))
+
+	sig, err := UnblindSignature(blindSig, blinding)
+	require.NoError(t, err)
+	require.Len(t, sig, 112)
+	require.NoError(t, blsVerifier.Verify(messages, sig))
+
+	// the unblinded signature is a regular signature: the holder derives proofs hiding the link secret.
+	proofNonce := []byte("verifier nonce")
+
+	proof, err := blsVerifier.DeriveProof(messages, sig, proofNonce, []int{1})
+	require.NoError(t, err)
+	require.NoError(t, blsVerifier.VerifyProof([][]byte{messages[1]}, proof, proofNonce))
+
+	t.Run("invalid commitment proofs", func(t *testing.T) {
+		_, err = blsSigner.BlindSign(commitment, len(messages), known, []byte("other nonce"))
+		require.EqualError(t, err, "blind sign: invalid blind commitment proof")
+
+		// the hidden messages of the commitment must be the messages that are not known.
+		_, err = blsSigner.BlindSign(commitment, len(messages), map[int][]byte{1: messages[1]}, nonce)
+		require.EqualError(t, err, "blind sign: invalid blind commitment")
+
+		_, err = blsSigner.BlindSign(commitment, len(messages), map[int][]byte{1: messages[1], 3: messages[3]},
+			nonce)
+		require.EqualError(t, err, "blind sign: invalid blind commitment proof")
+
+		tampered := append([]byte{}, commitment...)
+		tampered[len(tampered)-1] ^= 1
+
+		_, err = blsSigner.BlindSign(tampered, len(messages), known, nonce)
+		require.EqualError(t, err, "blind sign: invalid blind commitment proof")
+
+		_, err = blsSigner.BlindSign(make([]byte, len(commitment)), len(messages), known, nonce)
+		require.EqualError(t, err, "blind sign: invalid blind commitment")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, _, err = NewBlindCommitment(pubKeyBytes, len(messages), nil, nonce)
+		require.EqualError(t, err, "new blind commitment: no hidden messages")
+
+		_, _, err = NewBlindCommitment(pubKeyBytes, len(messages), map[int][]byte{4: messages[0]}, nonce)
+		require.EqualError(t, err, "new blind commitment: invalid hidden message index 4")
+
+		_, _, err = NewBlindCommitment([]byte("invalid"), len(messages), hidden, nonce)
+		require.ErrorContains(t, err, "new blind commitment: parse public key")
+
+		_, err = blsSigner.BlindSign(commitment, len(messages), map[int][]byte{0: nil, 1: nil, 2: nil, 3: nil},
+			nonce)
+		require.EqualError(t, err, "blind sign: invalid known messages indexes")
+
+		_, err = blsSigner.BlindSign(commitment, len(messages), map[int][]byte{1: nil, 2: nil, 7: nil}, nonce)
+		require.EqualError(t, err, "blind sign: invalid known messages indexes")
+
+		_, err = NewBLS12381G2Signer([]byte("invalid")).BlindSign(commitment, len(messages), known, nonce)
+		require.ErrorContains(t, err, "blind sign: ")
+
+		_, err = UnblindSignature(blindSig, []byte("invalid"))
+		require.EqualError(t, err, "unblind signature: invalid blinding factor")
+
+		_, err = UnblindSignature([]byte("invalid"), blinding)
+		require.ErrorContains(t, err, "unblind signature: ")
+	})
+}
@@ -0,0 +1,322 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	ml "github.com/IBM/mathlib"
+	"github.com/bluele/gcache"
+	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+)
+
+// The generators of a BBS+ public key are derived by hashing the key to G1 once per message, which costs more than
+// the pairings of a verification. They are cached per public key and messages count, along with fixed-base
+// multiplication tables of the generators, so repeated operations with the keys of the same issuer skip the hashing
+// and use table lookups and additions instead of scalar multiplications.
+//
+// The pairing engine of the mathlib backends doesn't expose the Miller loop lines of a G2 point, so the Miller loop
+// of the issuer key w can't be precomputed and the pairings are the same as the bbs12381g2pub ones.
+
+const (
+	// precomputedKeysCacheSize is the number of (public key, messages count) generators kept in the cache. A table
+	// holds about 1000 G1 points, there is one table per message.
+	precomputedKeysCacheSize = 16
+	tableWindowBits          = 4
+	tableWindowSize          = 1 << tableWindowBits
+)
+
+// nolint:gochecknoglobals
+var (
+	curve            = ml.Curves[ml.BLS12_381_BBS]
+	g1Domain         = []byte("BLS12381G1_XMD:BLAKE2B_SSWU_RO_BBS+_SIGNATURES:1_0_0")
+	precomputedCache = gcache.New(precomputedKeysCacheSize).LRU().Build()
+
+	g1TableOnce sync.Once
+	g1Table     *fixedBaseTable
+)
+
+// generators are the generators of the BBS+ public key w for a messages count, with their multiplication tables.
+type generators struct {
+	h0 *ml.G1
+	h  []*ml.G1
+	w  *ml.G2
+
+	h0Table *fixedBaseTable
+	hTables []*fixedBaseTable
+}
+
+// generatorsFor returns the generators of the messagesCount messages of the public key pubKeyBytes, from the cache
+// if they were already derived.
+func generatorsFor(pubKeyBytes []byte, messagesCount, workers int) (*generators, error) {
+	cacheKey := fmt.Sprintf("%x/%d", pubKeyBytes, messagesCount)
+
+	if v, err := precomputedCache.Get(cacheKey); err == nil {
+		if gens, ok := v.(*generators); ok {
+			return gens, nil
+		}
+	}
+
+	pubKey, err := bbs12381g2pub.UnmarshalPublicKey(pubKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+
+	gens := newGenerators(pubKey, messagesCount, workers)
+
+	_ = precomputedCache.Set(cacheKey, gens) //nolint:errcheck // a cache miss only costs a new derivation
+
+	return gens, nil
+}
+
+// newGenerators derives the h0 and h generators of the messagesCount messages of pubKey, as
+// bbs12381g2pub.PublicKey.ToPublicKeyWithGenerators, and their multiplication tables.
+func newGenerators(pubKey *bbs12381g2pub.PublicKey, messagesCount, workers int) *generators {
+	offset := curve.G2ByteSize + 1
+
+	data := pubKey.PointG2.Bytes()
+	data = append(data, 0, 0, 0, 0, 0, 0)
+	data = append(data, uint32Bytes(messagesCount)...)
+
+	gens := &generators{
+		h:       make([]*ml.G1, messagesCount),
+		w:       pubKey.PointG2,
+		hTables: make([]*fixedBaseTable, messagesCount),
+	}
+
+	parallelFor(messagesCount+1, workers, func(i int) {
+		if i == messagesCount {
+			gens.h0 = curve.HashToG1WithDomain(data, g1Domain)
+			gens.h0Table = newFixedBaseTable(gens.h0)
+
+			return
+		}
+
+		dataCopy := make([]byte, len(data))
+		copy(dataCopy, data)
+		copy(dataCopy[offset:], uint32Bytes(i+1))
+
+		gens.h[i] = curve.HashToG1WithDomain(dataCopy, g1Domain)
+		gens.hTables[i] = newFixedBaseTable(gens.h[i])
+	})
+
+	return gens
+}
+
+// commitMessages computes g1 * h0^s * h[i]^messages[i]... for the indexes of messages, all the messages if
+// indexes is nil.
+func (g *generators) commitMessages(s *ml.Zr, messages []*ml.Zr, indexes []int, workers int) *ml.G1 {
+	g1TableOnce.Do(func() {
+		g1Table = newFixedBaseTable(curve.GenG1)
+	})
+
+	terms := []term{{base: curve.GenG1, table: g1Table, scalar: curve.NewZrFromInt(1)}}
+
+	if s != nil {
+		terms = append(terms, g.h0Term(s))
+	}
+
+	if indexes == nil {
+		for i := range messages {
+			terms = append(terms, g.hTerm(i, messages[i]))
+		}
+	}
+
+	for j, i := range indexes {
+		terms = append(terms, g.hTerm(i, messages[j]))
+	}
+
+	return sumOfTerms(terms, workers)
+}
+
+func (g *generators) h0Term(s *ml.Zr) term {
+	return term{base: g.h0, table: g.h0Table, scalar: s}
+}
+
+func (g *generators) hTerm(i int, s *ml.Zr) term {
+	return term{base: g.h[i], table: g.hTables[i], scalar: s}
+}
+
+// fixedBaseTable holds the multiples d * 16^j * P of a point P for every digit d of the scalars in base 16.
+type fixedBaseTable struct {
+	windows [][tableWindowSize - 1]*ml.G1
+}
+
+func newFixedBaseTable(p *ml.G1) *fixedBaseTable {
+	t := &fixedBaseTable{windows: make([][tableWindowSize - 1]*ml.G1, 2*curve.ScalarByteSize)}
+
+	base := p.Copy()
+
+	for j := range t.windows {
+		t.windows[j][0] = base.Copy()
+
+		for d := 1; d < tableWindowSize-1; d++ {
+			t.windows[j][d] = t.windows[j][d-1].Copy()
+			t.windows[j][d].Add(base)
+		}
+
+		base = t.windows[j][tableWindowSize-2].Copy()
+		base.Add(t.windows[j][0])
+	}
+
+	return t
+}
+
+// mul returns s * P.
+func (t *fixedBaseTable) mul(s *ml.Zr) *ml.G1 {
+	p := t.windows[0][0]
+
+	b := s.Bytes()
+	if len(b) > curve.ScalarByteSize {
+		return p.Mul(s)
+	}
+
+	var res *ml.G1
+
+	for k := 0; k < len(b); k++ {
+		v := b[len(b)-1-k]
+
+		for w, d := range []byte{v & (tableWindowSize - 1), v >> tableWindowBits} {
+			if d == 0 {
+				continue
+			}
+
+			if res == nil {
+				res = t.windows[2*k+w][d-1].Copy()
+			} else {
+				res.Add(t.windows[2*k+w][d-1])
+			}
+		}
+	}
+
+	if res == nil {
+		res = p.Copy()
+		res.Sub(p)
+	}
+
+	return res
+}
+
+// term is a term scalar * base of a multi-scalar multiplication, computed with table if base is a generator.
+type term struct {
+	base   *ml.G1
+	table  *fixedBaseTable
+	scalar *ml.Zr
+}
+
+func (t term) eval() *ml.G1 {
+	if t.table != nil {
+		return t.table.mul(t.scalar)
+	}
+
+	return t.base.Mul(t.scalar)
+}
+
+// sumOfTerms computes the multi-scalar multiplication of terms, splitting it in at most workers chunks computed
+// concurrently.
+func sumOfTerms(terms []term, workers int) *ml.G1 {
+	if workers > len(terms) {
+		workers = len(terms)
+	}
+
+	if workers <= 1 {
+		return sumOfChunk(terms)
+	}
+
+	chunkSize := (len(terms) + workers - 1) / workers
+	sums := make([]*ml.G1, (len(terms)+chunkSize-1)/chunkSize)
+
+	var wg sync.WaitGroup
+
+	for c := range sums {
+		wg.Add(1)
+
+		go func(c int) {
+			defer wg.Done()
+
+			start, end := c*chunkSize, (c+1)*chunkSize
+			if end > len(terms) {
+				end = len(terms)
+			}
+
+			sums[c] = sumOfChunk(terms[start:end])
+		}(c)
+	}
+
+	wg.Wait()
+
+	res := sums[0]
+
+	for _, sum := range sums[1:] {
+		res.Add(sum)
+	}
+
+	return res
+}
+
+func sumOfChunk(terms []term) *ml.G1 {
+	res := terms[0].eval()
+
+	for _, t := range terms[1:] {
+		res.Add(t.eval())
+	}
+
+	return res
+}
+
+// parallelFor calls f for every index in [0, n) using at most workers goroutines.
+func parallelFor(n, workers int, f func(i int)) {
+	if workers <= 1 || n <= 1 {
+		for i := 0; i < n; i++ {
+			f(i)
+		}
+
+		return
+	}
+
+	var wg sync.WaitGroup
+
+	sem := make(chan struct{}, workers)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+
+		sem <- struct{}{}
+
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			f(i)
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// parseMessages hashes messages to scalars, as bbs12381g2pub.ParseSignatureMessage.
+func parseMessages(messages [][]byte, workers int) []*ml.Zr {
+	messagesFr := make([]*ml.Zr, len(messages))
+
+	parallelFor(len(messages), workers, func(i int) {
+		messagesFr[i] = bbs12381g2pub.ParseSignatureMessage(messages[i]).FR
+	})
+
+	return messagesFr
+}
+
+func uint32Bytes(v int) []byte {
+	b := make([]byte, uint32Size)
+
+	binary.BigEndian.PutUint32(b, uint32(v))
+
+	return b
+}
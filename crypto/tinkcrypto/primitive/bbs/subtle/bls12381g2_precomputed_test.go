@@ -0,0 +1,141 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle
+
+import (
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	ml "github.com/IBM/mathlib"
+	"github.com/stretchr/testify/require"
+	bbs "github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+)
+
+func TestFixedBaseTable(t *testing.T) {
+	p := curve.HashToG1WithDomain([]byte("point"), g1Domain)
+	table := newFixedBaseTable(p)
+
+	neg := curve.NewRandomZr(rand.Reader)
+	neg.Neg()
+
+	for _, s := range []*ml.Zr{
+		curve.NewZrFromInt(0),
+		curve.NewZrFromInt(1),
+		curve.NewZrFromInt(15),
+		curve.NewZrFromInt(16),
+		curve.NewZrFromInt(255),
+		curve.NewRandomZr(rand.Reader),
+		curve.NewRandomZr(rand.Reader).Plus(curve.GroupOrder),
+		neg,
+	} {
+		require.Equal(t, p.Mul(s).Bytes(), table.mul(s).Bytes(), s.String())
+	}
+
+	require.True(t, table.mul(curve.NewZrFromInt(0)).IsInfinity())
+}
+
+func TestVerifier_Compatibility(t *testing.T) {
+	messages, sig, pubKeyBytes := newSignedMessages(t, 12)
+	nonce := []byte("nonce")
+	revealed := []int{1, 4, 11}
+
+	revealedMessages := [][]byte{messages[1], messages[4], messages[11]}
+
+	verifier := NewBLS12381G2Verifier(pubKeyBytes)
+
+	// twice, with the generators derived and then cached
+	for i := 0; i < 2; i++ {
+		require.NoError(t, verifier.Verify(messages, sig))
+		require.EqualError(t, verifier.Verify(messages[1:], sig), "invalid BLS12-381 signature")
+
+		proof, err := bbs.New().DeriveProof(messages, sig, nonce, pubKeyBytes, revealed)
+		require.NoError(t, err)
+
+		require.NoError(t, verifier.VerifyProof(revealedMessages, proof, nonce))
+		require.EqualError(t, verifier.VerifyProof(revealedMessages, proof, []byte("other")), "bad signature")
+		require.EqualError(t, verifier.VerifyProof(messages[:3], proof, nonce), "bad signature")
+
+		proof, err = verifier.DeriveProof(messages, sig, nonce, revealed)
+		require.NoError(t, err)
+
+		require.NoError(t, verifier.VerifyProof(revealedMessages, proof, nonce))
+		require.NoError(t, verifier.VerifyProof(revealedMessages, proof, nonce))
+		require.NoError(t, bbs.New().VerifyProof(revealedMessages, proof, nonce, pubKeyBytes))
+	}
+
+	_, err := generatorsFor(pubKeyBytes, len(messages), 1)
+	require.NoError(t, err)
+	require.True(t, precomputedCache.Has(fmt.Sprintf("%x/%d", pubKeyBytes, len(messages))))
+
+	t.Run("errors", func(t *testing.T) {
+		require.ErrorContains(t, NewBLS12381G2Verifier([]byte("bad")).Verify(messages, sig), "parse public key")
+		require.ErrorContains(t, verifier.Verify(messages, []byte("bad")), "parse signature")
+
+		proof, err := verifier.DeriveProof(messages, sig, nonce, revealed)
+		require.NoError(t, err)
+
+		require.EqualError(t, verifier.VerifyProof(revealedMessages[:2], proof, nonce),
+			"payload revealed bigger from messages")
+
+		for _, size := range []int{0, 1, 4, 20, 100, 200, len(proof) - 1} {
+			require.Error(t, verifier.VerifyProof(revealedMessages, proof[:size], nonce), size)
+		}
+
+		tampered := append([]byte(nil), proof...)
+		tampered[len(tampered)-1] ^= 1
+		require.Error(t, verifier.VerifyProof(revealedMessages, tampered, nonce))
+
+		tampered = append([]byte(nil), proof...)
+		tampered[2] |= 0x80
+		require.ErrorContains(t, verifier.VerifyProof(revealedMessages, tampered, nonce), "invalid revealed index")
+	})
+}
+
+func BenchmarkVerify(b *testing.B) {
+	for _, count := range []int{10, 50} {
+		messages, sig, pubKeyBytes := newSignedMessages(b, count)
+		verifier := NewBLS12381G2Verifier(pubKeyBytes)
+
+		b.Run(fmt.Sprintf("messages=%d/bbs12381g2pub", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				require.NoError(b, bbs.New().Verify(messages, sig, pubKeyBytes))
+			}
+		})
+
+		b.Run(fmt.Sprintf("messages=%d/precomputed", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				require.NoError(b, verifier.Verify(messages, sig))
+			}
+		})
+	}
+}
+
+func BenchmarkVerifyProof(b *testing.B) {
+	for _, count := range []int{10, 50} {
+		messages, sig, pubKeyBytes := newSignedMessages(b, count)
+		nonce := []byte("nonce")
+		verifier := NewBLS12381G2Verifier(pubKeyBytes)
+
+		proof, err := verifier.DeriveProof(messages, sig, nonce, []int{0})
+		require.NoError(b, err)
+
+		b.Run(fmt.Sprintf("messages=%d/bbs12381g2pub", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				// bbs12381g2pub.VerifyProof reverses the revealed messages bit vector of proof in place
+				require.NoError(b, bbs.New().VerifyProof(messages[:1], append([]byte(nil), proof...), nonce,
+					pubKeyBytes))
+			}
+		})
+
+		b.Run(fmt.Sprintf("messages=%d/precomputed", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				require.NoError(b, verifier.VerifyProof(messages[:1], proof, nonce))
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	ml "github.com/IBM/mathlib"
+	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+)
+
+// The signature and proof verifications below accept the same signatures and proofs as the bbs12381g2pub.BBSG2Pub
+// ones, with the cached generators and their multiplication tables.
+
+const frCompressedSize = 32
+
+// errBadSignature is the proof verification failure error, as returned by bbs12381g2pub.
+var errBadSignature = errors.New("bad signature")
+
+// verifySignatureBytes verifies the BBS+ signature sigBytes of messages with the public key pubKeyBytes.
+func verifySignatureBytes(messages [][]byte, sigBytes, pubKeyBytes []byte, workers int) error {
+	signature, err := bbs12381g2pub.ParseSignature(sigBytes)
+	if err != nil {
+		return fmt.Errorf("parse signature: %w", err)
+	}
+
+	gens, err := generatorsFor(pubKeyBytes, len(messages), workers)
+	if err != nil {
+		return err
+	}
+
+	b := gens.commitMessages(signature.S, parseMessages(messages, workers), nil, workers)
+
+	if !verifySignature(signature, b, gens) {
+		return errors.New("invalid BLS12-381 signature")
+	}
+
+	return nil
+}
+
+// signatureProof is a parsed BBS+ signature proof.
+type signatureProof struct {
+	messagesCount int
+	revealed      []int
+
+	aPrime, aBar, d *ml.G1
+
+	commitment1, commitment2 *ml.G1
+	responses1, responses2   []*ml.Zr
+}
+
+// verifyProof verifies the BBS+ signature proof of the revealed messages with the public key pubKeyBytes.
+func verifyProof(messages [][]byte, proofBytes, nonce, pubKeyBytes []byte, workers int) error {
+	proof, err := parseSignatureProof(proofBytes)
+	if err != nil {
+		return fmt.Errorf("parse signature proof: %w", err)
+	}
+
+	gens, err := generatorsFor(pubKeyBytes, proof.messagesCount, workers)
+	if err != nil {
+		return err
+	}
+
+	if len(proof.revealed) > len(messages) {
+		return fmt.Errorf("payload revealed bigger from messages")
+	}
+
+	revealedMessages := parseMessages(messages[:len(proof.revealed)], workers)
+
+	hidden := hiddenMessages(gens, nil, proof.revealed)
+
+	challenge := proof.challenge(gens, hidden, nonce)
+
+	aBar := proof.aBar.Copy()
+	aBar.Neg()
+
+	if !curve.FExp(curve.Pairing2(gens.w, proof.aPrime, curve.GenG2, aBar)).IsUnity() {
+		return errBadSignature
+	}
+
+	aBarD := proof.aBar.Copy()
+	aBarD.Sub(proof.d)
+
+	if !verifyProofG1([]term{{base: proof.aPrime}, gens.h0Term(nil), {base: aBarD}},
+		proof.responses1, proof.commitment1, challenge, workers) {
+		return errBadSignature
+	}
+
+	pr := gens.commitMessages(nil, revealedMessages, proof.revealed, workers)
+	pr.Neg()
+
+	if !verifyProofG1(append(append([]term{{base: proof.d}, gens.h0Term(nil)}, hidden...), term{base: pr}),
+		proof.responses2, proof.commitment2, challenge, workers) {
+		return errBadSignature
+	}
+
+	return nil
+}
+
+// challenge computes the challenge of the proof, as bbs12381g2pub.PoKOfSignatureProof.GetBytesForChallenge.
+func (p *signatureProof) challenge(gens *generators, hidden []term, nonce []byte) *ml.Zr {
+	challengeBytes := p.aBar.Bytes()
+	challengeBytes = append(challengeBytes, p.aPrime.Bytes()...)
+	challengeBytes = append(challengeBytes, gens.h0.Bytes()...)
+	challengeBytes = append(challengeBytes, p.commitment1.Bytes()...)
+	challengeBytes = append(challengeBytes, p.d.Bytes()...)
+	challengeBytes = append(challengeBytes, gens.h0.Bytes()...)
+
+	for _, t := range hidden {
+		challengeBytes = append(challengeBytes, t.base.Bytes()...)
+	}
+
+	challengeBytes = append(challengeBytes, p.commitment2.Bytes()...)
+	challengeBytes = append(challengeBytes, bbs12381g2pub.ParseProofNonce(nonce).ToBytes()...)
+
+	return bbs12381g2pub.ParseSignatureMessage(challengeBytes).FR
+}
+
+// verifyProofG1 checks that the responses for the terms bases, followed by challenge for the last term base, sum up
+// to commitment.
+func verifyProofG1(terms []term, responses []*ml.Zr, commitment *ml.G1, challenge *ml.Zr, workers int) bool {
+	if len(responses) != len(terms)-1 {
+		return false
+	}
+
+	for i := range responses {
+		terms[i].scalar = responses[i]
+	}
+
+	terms[len(terms)-1].scalar = challenge
+
+	contribution := sumOfTerms(terms, workers)
+	contribution.Sub(commitment)
+
+	return contribution.IsInfinity()
+}
+
+// parseSignatureProof parses a proof serialized by bbs12381g2pub.BBSG2Pub.DeriveProof or deriveProof.
+func parseSignatureProof(b []byte) (*signatureProof, error) {
+	if len(b) < 2 { //nolint:gomnd
+		return nil, errors.New("invalid size of PoK payload")
+	}
+
+	proof := &signatureProof{messagesCount: int(binary.BigEndian.Uint16(b))}
+
+	offset := 2 + proof.messagesCount/byteBits + 1 //nolint:gomnd
+	if len(b) < offset {
+		return nil, errors.New("invalid size of PoK payload")
+	}
+
+	bitvector := append([]byte(nil), b[2:offset]...)
+	reverseBytes(bitvector)
+
+	for i := 0; i < len(bitvector)*byteBits; i++ {
+		if bitvector[i/byteBits]&(1<<(i%byteBits)) != 0 {
+			if i >= proof.messagesCount {
+				return nil, errors.New("invalid revealed index in PoK payload")
+			}
+
+			proof.revealed = append(proof.revealed, i)
+		}
+	}
+
+	points, offset, err := parseG1Points(b, offset, 3) //nolint:gomnd
+	if err != nil {
+		return nil, err
+	}
+
+	proof.aPrime, proof.aBar, proof.d = points[0], points[1], points[2]
+
+	if len(b) < offset+uint32Size {
+		return nil, errors.New("invalid size of signature proof")
+	}
+
+	proof1Len := int(binary.BigEndian.Uint32(b[offset:]))
+	offset += uint32Size
+
+	if proof1Len < 0 || len(b)-offset < proof1Len {
+		return nil, errors.New("invalid size of signature proof")
+	}
+
+	proof.commitment1, proof.responses1, err = parseProofG1(b[offset : offset+proof1Len])
+	if err != nil {
+		return nil, err
+	}
+
+	proof.commitment2, proof.responses2, err = parseProofG1(b[offset+proof1Len:])
+	if err != nil {
+		return nil, err
+	}
+
+	return proof, nil
+}
+
+func parseG1Points(b []byte, offset, count int) ([]*ml.G1, int, error) {
+	if len(b) < offset+count*curve.CompressedG1ByteSize {
+		return nil, 0, errors.New("invalid size of signature proof")
+	}
+
+	points := make([]*ml.G1, count)
+
+	for i := range points {
+		p, err := curve.NewG1FromCompressed(b[offset : offset+curve.CompressedG1ByteSize])
+		if err != nil {
+			return nil, 0, fmt.Errorf("parse G1 point: %w", err)
+		}
+
+		points[i] = p
+		offset += curve.CompressedG1ByteSize
+	}
+
+	return points, offset, nil
+}
+
+func parseProofG1(b []byte) (*ml.G1, []*ml.Zr, error) {
+	points, offset, err := parseG1Points(b, 0, 1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse G1 proof: %w", err)
+	}
+
+	if len(b) < offset+uint32Size {
+		return nil, nil, errors.New("parse G1 proof: invalid size of G1 signature proof")
+	}
+
+	count := int(binary.BigEndian.Uint32(b[offset:]))
+	offset += uint32Size
+
+	if count < 0 || (len(b)-offset)/frCompressedSize < count {
+		return nil, nil, errors.New("parse G1 proof: invalid size of G1 signature proof")
+	}
+
+	responses := make([]*ml.Zr, count)
+
+	for i := range responses {
+		responses[i] = curve.NewZrFromBytes(b[offset : offset+frCompressedSize])
+		offset += frCompressedSize
+	}
+
+	return points[0], responses, nil
+}
@@ -0,0 +1,242 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	ml "github.com/IBM/mathlib"
+	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+)
+
+// The prover below produces the same proofs as bbs12381g2pub.BBSG2Pub.DeriveProof (they are verified by
+// bbs12381g2pub.BBSG2Pub.VerifyProof), with the generators derivation and the multi-scalar multiplications split
+// across goroutines. The commitment to the signature messages is also computed once instead of twice.
+
+const (
+	uint32Size = 4
+	byteBits   = 8
+)
+
+// deriveProof derives a proof of the BBS+ signature sigBytes of messages disclosing the messages at revealedIndexes,
+// using at most workers goroutines.
+func deriveProof(messages [][]byte, sigBytes, nonce, pubKeyBytes []byte, revealedIndexes []int,
+	workers int) ([]byte, error) {
+	revealed, err := sortedRevealedIndexes(revealedIndexes, len(messages))
+	if err != nil {
+		return nil, err
+	}
+
+	gens, err := generatorsFor(pubKeyBytes, len(messages), workers)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := bbs12381g2pub.ParseSignature(sigBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse signature: %w", err)
+	}
+
+	messagesFr := parseMessages(messages, workers)
+
+	b := gens.commitMessages(signature.S, messagesFr, nil, workers)
+
+	if !verifySignature(signature, b, gens) {
+		return nil, errors.New("init proof of knowledge signature: verify input signature: " +
+			"invalid BLS12-381 signature")
+	}
+
+	return proveKnowledge(signature, b, gens, messagesFr, revealed, nonce, workers), nil
+}
+
+// proveKnowledge creates the proof of knowledge of signature disclosing the messages at the revealed indexes, b is
+// the commitment to the messages.
+func proveKnowledge(signature *bbs12381g2pub.Signature, b *ml.G1, gens *generators, messages []*ml.Zr,
+	revealed []int, nonce []byte, workers int) []byte {
+	r1, r2 := curve.NewRandomZr(rand.Reader), curve.NewRandomZr(rand.Reader)
+
+	aPrime := signature.A.Mul(r1)
+
+	aBar := b.Mul(r1)
+	aBar.Sub(aPrime.Mul(signature.E))
+
+	r2D := r2.Copy()
+	r2D.Neg()
+
+	d := sumOfTerms([]term{{base: b, scalar: r1}, gens.h0Term(r2D)}, 1)
+
+	r3 := r1.Copy()
+	r3.InvModP(curve.GroupOrder)
+
+	sPrime := r2.Mul(r3)
+	sPrime.Neg()
+	sPrime = sPrime.Plus(signature.S)
+
+	e := signature.E.Copy()
+	e.Neg()
+
+	vc1 := newCommitment([]term{{base: aPrime, scalar: e}, gens.h0Term(r2)}, workers)
+
+	r3D := r3.Copy()
+	r3D.Neg()
+
+	vc2 := newCommitment(append([]term{{base: d, scalar: r3D}, gens.h0Term(sPrime)},
+		hiddenMessages(gens, messages, revealed)...), workers)
+
+	challengeBytes := aBar.Bytes()
+	challengeBytes = append(challengeBytes, vc1.bytes()...)
+	challengeBytes = append(challengeBytes, vc2.bytes()...)
+	challengeBytes = append(challengeBytes, bbs12381g2pub.ParseProofNonce(nonce).ToBytes()...)
+
+	challenge := bbs12381g2pub.ParseSignatureMessage(challengeBytes).FR
+
+	proof := pokPayload(len(messages), revealed)
+	proof = append(proof, aPrime.Compressed()...)
+	proof = append(proof, aBar.Compressed()...)
+	proof = append(proof, d.Compressed()...)
+
+	proof1 := vc1.proof(challenge)
+	proof = append(proof, uint32Bytes(len(proof1))...)
+	proof = append(proof, proof1...)
+
+	return append(proof, vc2.proof(challenge)...)
+}
+
+// hiddenMessages returns the terms of the generators and copies of the messages that are not revealed, without
+// scalars if messages is nil.
+func hiddenMessages(gens *generators, messages []*ml.Zr, revealed []int) []term {
+	isRevealed := make(map[int]bool, len(revealed))
+	for _, i := range revealed {
+		isRevealed[i] = true
+	}
+
+	terms := make([]term, 0, len(gens.h)-len(revealed))
+
+	for i := range gens.h {
+		if isRevealed[i] {
+			continue
+		}
+
+		var s *ml.Zr
+
+		if messages != nil {
+			s = messages[i].Copy()
+		}
+
+		terms = append(terms, gens.hTerm(i, s))
+	}
+
+	return terms
+}
+
+func sortedRevealedIndexes(revealedIndexes []int, messagesCount int) ([]int, error) {
+	if len(revealedIndexes) == 0 {
+		return nil, errors.New("no message to reveal")
+	}
+
+	revealed := append([]int(nil), revealedIndexes...)
+	sort.Ints(revealed)
+
+	unique := revealed[:1]
+
+	for _, i := range revealed[1:] {
+		if i != unique[len(unique)-1] {
+			unique = append(unique, i)
+		}
+	}
+
+	revealed = unique
+
+	if revealed[0] < 0 || revealed[len(revealed)-1] >= messagesCount {
+		return nil, fmt.Errorf("invalid revealed index: indexes must be in [0, %d)", messagesCount)
+	}
+
+	return revealed, nil
+}
+
+func verifySignature(signature *bbs12381g2pub.Signature, b *ml.G1, gens *generators) bool {
+	q1 := curve.GenG2.Mul(signature.E)
+	q1.Add(gens.w)
+
+	p2 := b.Copy()
+	p2.Neg()
+
+	return curve.FExp(curve.Pairing2(q1, signature.A, curve.GenG2, p2)).IsUnity()
+}
+
+// commitment is a Schnorr commitment to the secrets of terms, with random blinding factors.
+type commitment struct {
+	terms           []term
+	blindingFactors []*ml.Zr
+	commitment      *ml.G1
+}
+
+func newCommitment(terms []term, workers int) *commitment {
+	blindingFactors := make([]*ml.Zr, len(terms))
+	blinded := make([]term, len(terms))
+
+	for i, t := range terms {
+		blindingFactors[i] = curve.NewRandomZr(rand.Reader)
+		blinded[i] = term{base: t.base, table: t.table, scalar: blindingFactors[i]}
+	}
+
+	return &commitment{
+		terms:           terms,
+		blindingFactors: blindingFactors,
+		commitment:      sumOfTerms(blinded, workers),
+	}
+}
+
+// bytes returns the challenge contribution of the commitment.
+func (c *commitment) bytes() []byte {
+	var b []byte
+
+	for _, t := range c.terms {
+		b = append(b, t.base.Bytes()...)
+	}
+
+	return append(b, c.commitment.Bytes()...)
+}
+
+// proof returns the serialized proof of knowledge of the secrets for challenge.
+func (c *commitment) proof(challenge *ml.Zr) []byte {
+	b := c.commitment.Compressed()
+	b = append(b, uint32Bytes(len(c.terms))...)
+
+	for i, t := range c.terms {
+		b = append(b, c.blindingFactors[i].Minus(challenge.Mul(t.scalar)).Bytes()...)
+	}
+
+	return b
+}
+
+// pokPayload returns the proof header: the messages count and the bit vector of the revealed messages.
+func pokPayload(messagesCount int, revealed []int) []byte {
+	b := make([]byte, 2+messagesCount/byteBits+1) //nolint:gomnd
+
+	binary.BigEndian.PutUint16(b, uint16(messagesCount))
+
+	bitvector := b[2:]
+
+	for _, r := range revealed {
+		bitvector[r/byteBits] |= 1 << (r % byteBits)
+	}
+
+	reverseBytes(bitvector)
+
+	return b
+}
+
+func reverseBytes(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	bbs "github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+)
+
+func newSignedMessages(t testing.TB, count int) ([][]byte, []byte, []byte) {
+	t.Helper()
+
+	pubKey, privKey, err := generateKeyPairRandom()
+	require.NoError(t, err)
+
+	privKeyBytes, err := privKey.Marshal()
+	require.NoError(t, err)
+
+	pubKeyBytes, err := pubKey.Marshal()
+	require.NoError(t, err)
+
+	messages := make([][]byte, count)
+	for i := range messages {
+		messages[i] = []byte(fmt.Sprintf("message%d", i))
+	}
+
+	sig, err := bbs.New().Sign(messages, privKeyBytes)
+	require.NoError(t, err)
+
+	return messages, sig, pubKeyBytes
+}
+
+func TestDeriveProof(t *testing.T) {
+	messages, sig, pubKeyBytes := newSignedMessages(t, 20)
+	nonce := []byte("nonce")
+
+	for _, tc := range []struct {
+		name     string
+		revealed []int
+	}{
+		{name: "one revealed", revealed: []int{7}},
+		{name: "unsorted with duplicates", revealed: []int{19, 0, 5, 5, 12}},
+		{name: "all revealed", revealed: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var revealedMessages [][]byte
+
+			for i, msg := range messages {
+				for _, r := range tc.revealed {
+					if r == i {
+						revealedMessages = append(revealedMessages, msg)
+
+						break
+					}
+				}
+			}
+
+			for _, workers := range []int{1, 3, 64} {
+				revealed := append([]int(nil), tc.revealed...)
+
+				proof, err := deriveProof(messages, sig, nonce, pubKeyBytes, revealed, workers)
+				require.NoError(t, err)
+				require.Equal(t, tc.revealed, revealed)
+
+				require.NoError(t, bbs.New().VerifyProof(revealedMessages, proof, nonce, pubKeyBytes))
+				require.Error(t, bbs.New().VerifyProof(revealedMessages, proof, []byte("other"), pubKeyBytes))
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := deriveProof(messages, sig, nonce, pubKeyBytes, nil, 2)
+		require.EqualError(t, err, "no message to reveal")
+
+		_, err = deriveProof(messages, sig, nonce, pubKeyBytes, []int{20}, 2)
+		require.EqualError(t, err, "invalid revealed index: indexes must be in [0, 20)")
+
+		_, err = deriveProof(messages, sig, nonce, pubKeyBytes, []int{-1}, 2)
+		require.Error(t, err)
+
+		_, err = deriveProof(messages, sig, nonce, []byte("bad"), []int{0}, 2)
+		require.ErrorContains(t, err, "parse public key")
+
+		_, err = deriveProof(messages, []byte("bad"), nonce, pubKeyBytes, []int{0}, 2)
+		require.ErrorContains(t, err, "parse signature")
+
+		_, err = deriveProof(messages[1:], sig, nonce, pubKeyBytes, []int{0}, 2)
+		require.ErrorContains(t, err, "invalid BLS12-381 signature")
+	})
+}
+
+func BenchmarkDeriveProof(b *testing.B) {
+	for _, count := range []int{10, 50, 200} {
+		messages, sig, pubKeyBytes := newSignedMessages(b, count)
+		nonce := []byte("nonce")
+		revealed := []int{0, count / 2}
+
+		b.Run(fmt.Sprintf("messages=%d/bbs12381g2pub", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, err := bbs.New().DeriveProof(messages, sig, nonce, pubKeyBytes, revealed)
+				require.NoError(b, err)
+			}
+		})
+
+		for _, workers := range []int{1, runtime.GOMAXPROCS(0)} {
+			b.Run(fmt.Sprintf("messages=%d/workers=%d", count, workers), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					_, err := deriveProof(messages, sig, nonce, pubKeyBytes, revealed, workers)
+					require.NoError(b, err)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,261 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package bip340 provides BIP-340 Schnorr signatures over secp256k1, see
+// https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki, and the Tink key managers of their Signer and
+// Verifier primitives.
+//
+// BIP-340 public keys are the 32 bytes x coordinates of the points with an even Y, signatures are 64 bytes and sign
+// messages of any size, unhashed. The keys reuse the secp256k1 key protos, with their own type URLs: a key is either
+// an ECDSA or a BIP-340 key, it can't be used for both.
+//
+// As for the secp256k1 ECDSA primitive, the scalar multiplications are not constant time.
+//
+// To sign data using Tink you can use the BIP-340 key template.
+package bip340
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/tink"
+	"google.golang.org/protobuf/proto"
+
+	secp256k1pb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/secp256k1_go_proto"
+)
+
+const (
+	// SignerTypeURL is the type URL of BIP-340 private keys.
+	SignerTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.BIP340Secp256k1PrivateKey"
+	// VerifierTypeURL is the type URL of BIP-340 public keys.
+	VerifierTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.BIP340Secp256k1PublicKey"
+
+	keyVersion = 0
+)
+
+var (
+	errInvalidSignerKey   = errors.New("bip340_signer_key_manager: invalid key")
+	errInvalidVerifierKey = errors.New("bip340_verifier_key_manager: invalid key")
+	errNotImplemented     = errors.New("bip340_verifier_key_manager: not implemented")
+)
+
+// nolint:gochecknoinits
+func init() {
+	if err := registry.RegisterKeyManager(new(signerKeyManager)); err != nil {
+		panic(fmt.Sprintf("bip340.init() failed: %v", err))
+	}
+
+	if err := registry.RegisterKeyManager(new(verifierKeyManager)); err != nil {
+		panic(fmt.Sprintf("bip340.init() failed: %v", err))
+	}
+}
+
+// KeyTemplate is a KeyTemplate that generates a new BIP-340 secp256k1 private key. Signatures are RAW (no Tink
+// prefix).
+func KeyTemplate() *tinkpb.KeyTemplate {
+	format := &secp256k1pb.Secp256K1KeyFormat{Params: Params()}
+	serializedFormat, _ := proto.Marshal(format) //nolint:errcheck
+
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          SignerTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+	}
+}
+
+// Params returns the secp256k1 key params of BIP-340 keys: the tagged hashes of BIP-340 are SHA-256 hashes, the
+// signature encoding doesn't apply.
+func Params() *secp256k1pb.Secp256K1Params {
+	return &secp256k1pb.Secp256K1Params{
+		HashType: commonpb.HashType_SHA256,
+		Curve:    secp256k1pb.BitcoinCurveType_SECP256K1,
+	}
+}
+
+// NewPrivateKey returns the private key proto of the BIP-340 private key keyValue.
+func NewPrivateKey(keyValue []byte) (*secp256k1pb.Secp256K1PrivateKey, error) {
+	pubKey, err := PublicKey(keyValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return &secp256k1pb.Secp256K1PrivateKey{
+		Version:   keyVersion,
+		PublicKey: &secp256k1pb.Secp256K1PublicKey{Version: keyVersion, Params: Params(), X: pubKey},
+		KeyValue:  keyValue,
+	}, nil
+}
+
+type signerKeyManager struct{}
+
+// Primitive creates a BIP-340 Signer for the given serialized Secp256K1PrivateKey proto.
+func (km *signerKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	key := new(secp256k1pb.Secp256K1PrivateKey)
+
+	if err := proto.Unmarshal(serializedKey, key); err != nil || len(serializedKey) == 0 {
+		return nil, errInvalidSignerKey
+	}
+
+	if keyset.ValidateKeyVersion(key.Version, keyVersion) != nil || validateParams(key.PublicKey.GetParams()) != nil ||
+		len(key.KeyValue) != PrivateKeySize {
+		return nil, errInvalidSignerKey
+	}
+
+	return &signer{keyValue: key.KeyValue}, nil
+}
+
+// NewKey creates a new BIP-340 Secp256K1PrivateKey of the given serialized Secp256K1KeyFormat.
+func (km *signerKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	format := new(secp256k1pb.Secp256K1KeyFormat)
+
+	if err := proto.Unmarshal(serializedKeyFormat, format); err != nil {
+		return nil, fmt.Errorf("bip340_signer_key_manager: invalid key format: %w", err)
+	}
+
+	if err := validateParams(format.Params); err != nil {
+		return nil, fmt.Errorf("bip340_signer_key_manager: invalid key format: %w", err)
+	}
+
+	privKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("bip340_signer_key_manager: cannot generate key: %w", err)
+	}
+
+	keyValue := privKey.Key.Bytes()
+
+	key, err := NewPrivateKey(keyValue[:])
+	if err != nil {
+		return nil, fmt.Errorf("bip340_signer_key_manager: %w", err)
+	}
+
+	return key, nil
+}
+
+// NewKeyData creates a new KeyData of the given serialized Secp256K1KeyFormat.
+func (km *signerKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, fmt.Errorf("bip340_signer_key_manager: %w", err)
+	}
+
+	return &tinkpb.KeyData{
+		TypeUrl:         SignerTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}, nil
+}
+
+// PublicKeyData extracts the public key data from the private key.
+func (km *signerKeyManager) PublicKeyData(serializedPrivKey []byte) (*tinkpb.KeyData, error) {
+	privKey := new(secp256k1pb.Secp256K1PrivateKey)
+
+	if err := proto.Unmarshal(serializedPrivKey, privKey); err != nil || privKey.PublicKey == nil {
+		return nil, errInvalidSignerKey
+	}
+
+	serializedPubKey, err := proto.Marshal(privKey.PublicKey)
+	if err != nil {
+		return nil, errInvalidSignerKey
+	}
+
+	return &tinkpb.KeyData{
+		TypeUrl:         VerifierTypeURL,
+		Value:           serializedPubKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *signerKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == SignerTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *signerKeyManager) TypeURL() string {
+	return SignerTypeURL
+}
+
+type verifierKeyManager struct{}
+
+// Primitive creates a BIP-340 Verifier for the given serialized Secp256K1PublicKey proto.
+func (km *verifierKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	key := new(secp256k1pb.Secp256K1PublicKey)
+
+	if err := proto.Unmarshal(serializedKey, key); err != nil || len(serializedKey) == 0 {
+		return nil, errInvalidVerifierKey
+	}
+
+	if keyset.ValidateKeyVersion(key.Version, keyVersion) != nil || validateParams(key.Params) != nil {
+		return nil, errInvalidVerifierKey
+	}
+
+	if _, err := liftX(key.X); err != nil {
+		return nil, errInvalidVerifierKey
+	}
+
+	return &verifier{pubKey: key.X}, nil
+}
+
+// NewKey is not implemented.
+func (km *verifierKeyManager) NewKey([]byte) (proto.Message, error) {
+	return nil, errNotImplemented
+}
+
+// NewKeyData is not implemented.
+func (km *verifierKeyManager) NewKeyData([]byte) (*tinkpb.KeyData, error) {
+	return nil, errNotImplemented
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *verifierKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == VerifierTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *verifierKeyManager) TypeURL() string {
+	return VerifierTypeURL
+}
+
+func validateParams(params *secp256k1pb.Secp256K1Params) error {
+	if params.GetCurve() != secp256k1pb.BitcoinCurveType_SECP256K1 || params.GetHashType() != commonpb.HashType_SHA256 {
+		return errors.New("BIP-340 keys must be secp256k1 keys with SHA-256 hashes")
+	}
+
+	return nil
+}
+
+type signer struct {
+	keyValue []byte
+}
+
+// Sign signs data.
+func (s *signer) Sign(data []byte) ([]byte, error) {
+	return Sign(s.keyValue, data)
+}
+
+type verifier struct {
+	pubKey []byte
+}
+
+// Verify verifies the signature of data.
+func (v *verifier) Verify(signature, data []byte) error {
+	return Verify(v.pubKey, data, signature)
+}
+
+var (
+	_ tink.Signer   = (*signer)(nil)
+	_ tink.Verifier = (*verifier)(nil)
+)
@@ -0,0 +1,233 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bip340
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/signature"
+	"github.com/stretchr/testify/require"
+
+	secp256k1pb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/secp256k1_go_proto"
+)
+
+// BIP-340 test vectors, https://github.com/bitcoin/bips/blob/master/bip-0340/test-vectors.csv.
+//
+//nolint:lll
+var signVectors = []struct {
+	sk, pk, aux, msg, sig string
+}{
+	{
+		sk:  "0000000000000000000000000000000000000000000000000000000000000003",
+		pk:  "F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+		aux: "0000000000000000000000000000000000000000000000000000000000000000",
+		msg: "0000000000000000000000000000000000000000000000000000000000000000",
+		sig: "E907831F80848D1069A5371B402410364BDF1C5F8307B0084C55F1CE2DCA821525F66A4A85EA8B71E482A74F382D2CE5EBEEE8FDB2172F477DF4900D310536C0",
+	},
+	{
+		sk:  "B7E151628AED2A6ABF7158809CF4F3C762E7160F38B4DA56A784D9045190CFEF",
+		pk:  "DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		aux: "0000000000000000000000000000000000000000000000000000000000000001",
+		msg: "243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		sig: "6896BD60EEAE296DB48A229FF71DFE071BDE413E6D43F917DC8DCF8C78DE33418906D11AC976ABCCB20B091292BFF4EA897EFCB639EA871CFA95F6DE339E4B0A",
+	},
+}
+
+func TestSign(t *testing.T) {
+	for _, v := range signVectors {
+		pk, err := PublicKey(decode(t, v.sk))
+		require.NoError(t, err)
+		require.Equal(t, decode(t, v.pk), pk)
+
+		sig, err := sign(decode(t, v.sk), decode(t, v.msg), bytes.NewReader(decode(t, v.aux)))
+		require.NoError(t, err)
+		require.Equal(t, decode(t, v.sig), sig)
+
+		require.NoError(t, Verify(pk, decode(t, v.msg), sig))
+	}
+
+	t.Run("random auxiliary data", func(t *testing.T) {
+		sk := decode(t, signVectors[1].sk)
+		msg := []byte("messages of any size are signed")
+
+		sig1, err := Sign(sk, msg)
+		require.NoError(t, err)
+
+		sig2, err := Sign(sk, msg)
+		require.NoError(t, err)
+		require.NotEqual(t, sig1, sig2)
+
+		pk, err := PublicKey(sk)
+		require.NoError(t, err)
+		require.NoError(t, Verify(pk, msg, sig1))
+		require.NoError(t, Verify(pk, msg, sig2))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := Sign(make([]byte, PrivateKeySize), nil)
+		require.EqualError(t, err, "bip340: sign: invalid private key")
+
+		_, err = Sign(bytes.Repeat([]byte{0xff}, PrivateKeySize), nil)
+		require.EqualError(t, err, "bip340: sign: invalid private key")
+
+		_, err = PublicKey([]byte("short"))
+		require.EqualError(t, err, "bip340: public key: invalid private key")
+
+		_, err = sign(decode(t, signVectors[0].sk), nil, bytes.NewReader(nil))
+		require.ErrorContains(t, err, "bip340: sign: auxiliary randomness")
+	})
+}
+
+func TestVerify(t *testing.T) {
+	v := signVectors[1]
+	pk, msg, sig := decode(t, v.pk), decode(t, v.msg), decode(t, v.sig)
+
+	for _, i := range []int{0, 31, 32, 63} {
+		tampered := append([]byte{}, sig...)
+		tampered[i] ^= 1
+
+		require.ErrorIs(t, Verify(pk, msg, tampered), ErrInvalidSignature)
+	}
+
+	require.ErrorIs(t, Verify(pk, msg[1:], sig), ErrInvalidSignature)
+	require.ErrorIs(t, Verify(pk, msg, sig[1:]), ErrInvalidSignature)
+	require.ErrorIs(t, Verify(decode(t, signVectors[0].pk), msg, sig), ErrInvalidSignature)
+
+	// s must be reduced and r must be a field element.
+	require.ErrorIs(t, Verify(pk, msg, append(sig[:32:32], bytes.Repeat([]byte{0xff}, 32)...)), ErrInvalidSignature)
+	require.ErrorIs(t, Verify(pk, msg, append(bytes.Repeat([]byte{0xff}, 32), sig[32:]...)), ErrInvalidSignature)
+
+	// test vector 5: the public key is not on the curve.
+	err := Verify(decode(t, "EEFDEA4CDB677750A420FEE807EACF21EB9898AE79B9768766E4FAA04A2D4A34"), msg, sig)
+	require.EqualError(t, err, "bip340: verify: invalid public key")
+}
+
+func TestBatchVerify(t *testing.T) {
+	var pubKeys, msgs, sigs [][]byte
+
+	for i := 0; i < 8; i++ {
+		sk := bytes.Repeat([]byte{byte(i + 1)}, PrivateKeySize)
+		msg := []byte{byte(i)}
+
+		pk, err := PublicKey(sk)
+		require.NoError(t, err)
+
+		sig, err := Sign(sk, msg)
+		require.NoError(t, err)
+
+		pubKeys, msgs, sigs = append(pubKeys, pk), append(msgs, msg), append(sigs, sig)
+	}
+
+	require.NoError(t, BatchVerify(pubKeys, msgs, sigs))
+	require.NoError(t, BatchVerify(pubKeys[:1], msgs[:1], sigs[:1]))
+	require.NoError(t, BatchVerify(nil, nil, nil))
+
+	// any invalid signature invalidates the batch.
+	swapped := append([][]byte{}, sigs...)
+	swapped[2], swapped[3] = sigs[3], sigs[2]
+	require.ErrorIs(t, BatchVerify(pubKeys, msgs, swapped), ErrInvalidSignature)
+
+	tampered := append([][]byte{}, sigs...)
+	tampered[7] = append([]byte{}, sigs[7]...)
+	tampered[7][63] ^= 1
+	require.ErrorIs(t, BatchVerify(pubKeys, msgs, tampered), ErrInvalidSignature)
+
+	tampered[7] = sigs[7][1:]
+	require.ErrorIs(t, BatchVerify(pubKeys, msgs, tampered), ErrInvalidSignature)
+
+	err := BatchVerify(pubKeys, msgs[1:], sigs)
+	require.EqualError(t, err, "bip340: batch verify: mismatched number of public keys, messages and signatures")
+
+	invalidKeys := append([][]byte{}, pubKeys...)
+	invalidKeys[1] = make([]byte, PublicKeySize)
+	require.EqualError(t, BatchVerify(invalidKeys, msgs, sigs), "bip340: batch verify: public key 1: invalid public key")
+}
+
+func TestKeyManagers(t *testing.T) {
+	kh, err := keyset.NewHandle(KeyTemplate())
+	require.NoError(t, err)
+
+	s, err := signature.NewSigner(kh)
+	require.NoError(t, err)
+
+	msg := []byte("message")
+
+	sig, err := s.Sign(msg)
+	require.NoError(t, err)
+	require.Len(t, sig, SignatureSize)
+
+	pubKH, err := kh.Public()
+	require.NoError(t, err)
+	require.Equal(t, VerifierTypeURL, pubKH.KeysetInfo().KeyInfo[0].TypeUrl)
+
+	v, err := signature.NewVerifier(pubKH)
+	require.NoError(t, err)
+	require.NoError(t, v.Verify(sig, msg))
+	require.Error(t, v.Verify(sig, []byte("other")))
+
+	t.Run("private key proto", func(t *testing.T) {
+		key, err := NewPrivateKey(decode(t, signVectors[1].sk))
+		require.NoError(t, err)
+		require.Equal(t, decode(t, signVectors[1].pk), key.PublicKey.X)
+
+		serializedKey, err := proto.Marshal(key)
+		require.NoError(t, err)
+
+		p, err := new(signerKeyManager).Primitive(serializedKey)
+		require.NoError(t, err)
+
+		sig, err := p.(*signer).Sign(msg)
+		require.NoError(t, err)
+		require.NoError(t, Verify(key.PublicKey.X, msg, sig))
+
+		_, err = NewPrivateKey(nil)
+		require.EqualError(t, err, "bip340: public key: invalid private key")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		ecdsaParams := &secp256k1pb.Secp256K1Params{
+			HashType: 1,
+			Curve:    secp256k1pb.BitcoinCurveType_INVALID_BITCOIN_CURVE,
+		}
+
+		format, err := proto.Marshal(&secp256k1pb.Secp256K1KeyFormat{Params: ecdsaParams})
+		require.NoError(t, err)
+
+		_, err = keyset.NewHandle(&tinkpb.KeyTemplate{
+			TypeUrl:          SignerTypeURL,
+			Value:            format,
+			OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+		})
+		require.Error(t, err)
+
+		_, err = new(signerKeyManager).Primitive(nil)
+		require.EqualError(t, err, "bip340_signer_key_manager: invalid key")
+
+		pubKey, err := proto.Marshal(&secp256k1pb.Secp256K1PublicKey{Params: Params(), X: make([]byte, PublicKeySize)})
+		require.NoError(t, err)
+
+		_, err = new(verifierKeyManager).Primitive(pubKey)
+		require.EqualError(t, err, "bip340_verifier_key_manager: invalid key")
+
+		_, err = new(verifierKeyManager).NewKey(nil)
+		require.EqualError(t, err, "bip340_verifier_key_manager: not implemented")
+	})
+}
+
+func decode(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+
+	return b
+}
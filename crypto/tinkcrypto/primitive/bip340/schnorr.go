@@ -0,0 +1,340 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bip340
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+
+	"github.com/trustbloc/kms-go/internal/memguard"
+)
+
+const (
+	// PublicKeySize is the size in bytes of an x-only BIP-340 public key.
+	PublicKeySize = 32
+	// SignatureSize is the size in bytes of a BIP-340 signature.
+	SignatureSize = 64
+	// PrivateKeySize is the size in bytes of a BIP-340 private key.
+	PrivateKeySize = 32
+
+	auxTag       = "BIP0340/aux"
+	nonceTag     = "BIP0340/nonce"
+	challengeTag = "BIP0340/challenge"
+)
+
+// ErrInvalidSignature is returned when a signature is not valid.
+var ErrInvalidSignature = errors.New("bip340: invalid signature")
+
+var (
+	errInvalidPrivateKey = errors.New("invalid private key")
+	errInvalidPublicKey  = errors.New("invalid public key")
+)
+
+// PublicKey returns the x-only public key of the private key privKey.
+func PublicKey(privKey []byte) ([]byte, error) {
+	d, p, err := keyPair(privKey)
+	if err != nil {
+		return nil, fmt.Errorf("bip340: public key: %w", err)
+	}
+
+	d.Zero()
+
+	return xBytes(p), nil
+}
+
+// Sign returns the BIP-340 signature of msg with the private key privKey, with auxiliary randomness from
+// crypto/rand.
+func Sign(privKey, msg []byte) ([]byte, error) {
+	return sign(privKey, msg, rand.Reader)
+}
+
+func sign(privKey, msg []byte, random io.Reader) ([]byte, error) {
+	d, p, err := keyPair(privKey)
+	if err != nil {
+		return nil, fmt.Errorf("bip340: sign: %w", err)
+	}
+
+	defer d.Zero()
+
+	aux := make([]byte, 32) //nolint:gomnd // BIP-340 auxiliary randomness size.
+
+	if _, err = io.ReadFull(random, aux); err != nil {
+		return nil, fmt.Errorf("bip340: sign: auxiliary randomness: %w", err)
+	}
+
+	pk := xBytes(p)
+
+	// t = bytes(d) xor hash_aux(a), k' = int(hash_nonce(t || bytes(P) || m)) mod n
+	t := d.Bytes()
+	defer memguard.Wipe(t[:])
+
+	auxHash := taggedHash(auxTag, aux)
+
+	for i := range t {
+		t[i] ^= auxHash[i]
+	}
+
+	nonce := taggedHash(nonceTag, t[:], pk, msg)
+	defer memguard.Wipe(nonce[:])
+
+	var k btcec.ModNScalar
+
+	k.SetBytes(&nonce)
+	defer k.Zero()
+
+	if k.IsZero() {
+		return nil, errors.New("bip340: sign: zero nonce")
+	}
+
+	r := new(btcec.JacobianPoint)
+	btcec.ScalarBaseMultNonConst(&k, r)
+	r.ToAffine()
+
+	if r.Y.IsOdd() {
+		k.Negate()
+	}
+
+	rx := xBytes(r)
+	e := challenge(rx, pk, msg)
+
+	// s = (k + e*d) mod n
+	s := new(btcec.ModNScalar).Mul2(e, &d).Add(&k)
+	sBytes := s.Bytes()
+
+	sig := append(rx, sBytes[:]...)
+
+	// the signature is verified to protect the key against faults of the computation.
+	if err = Verify(pk, msg, sig); err != nil {
+		return nil, fmt.Errorf("bip340: sign: %w", err)
+	}
+
+	return sig, nil
+}
+
+// Verify verifies the BIP-340 signature sig of msg with the x-only public key pubKey. It returns ErrInvalidSignature
+// if the signature is not valid.
+func Verify(pubKey, msg, sig []byte) error {
+	p, err := liftX(pubKey)
+	if err != nil {
+		return fmt.Errorf("bip340: verify: %w", err)
+	}
+
+	r, s, err := parseSignature(sig)
+	if err != nil {
+		return err
+	}
+
+	// R = s*G - e*P
+	e := challenge(sig[:32], pubKey, msg)
+	e.Negate()
+
+	var sG, eP, rPoint btcec.JacobianPoint
+
+	btcec.ScalarBaseMultNonConst(s, &sG)
+	btcec.ScalarMultNonConst(e, p, &eP)
+	btcec.AddNonConst(&sG, &eP, &rPoint)
+
+	if isInfinity(&rPoint) {
+		return ErrInvalidSignature
+	}
+
+	rPoint.ToAffine()
+
+	if rPoint.Y.IsOdd() || !rPoint.X.Equals(r) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// BatchVerify verifies the BIP-340 signatures sigs[i] of msgs[i] with the x-only public keys pubKeys[i] at once,
+// faster than one at a time. It returns ErrInvalidSignature if any of the signatures is not valid, without telling
+// which one: the signatures must be verified one at a time with Verify to find it.
+func BatchVerify(pubKeys, msgs, sigs [][]byte) error {
+	if len(pubKeys) != len(msgs) || len(pubKeys) != len(sigs) {
+		return errors.New("bip340: batch verify: mismatched number of public keys, messages and signatures")
+	}
+
+	// the signatures are valid if (s1 + a2*s2 + ... + au*su)*G = R1 + a2*R2 + ... + au*Ru + e1*P1 + ... + au*eu*Pu,
+	// with the random weights a2, ..., au.
+	var sum btcec.ModNScalar
+
+	acc := new(btcec.JacobianPoint)
+
+	for i := range sigs {
+		p, err := liftX(pubKeys[i])
+		if err != nil {
+			return fmt.Errorf("bip340: batch verify: public key %d: %w", i, err)
+		}
+
+		_, s, err := parseSignature(sigs[i])
+		if err != nil {
+			return err
+		}
+
+		r, err := liftX(sigs[i][:32])
+		if err != nil {
+			return ErrInvalidSignature
+		}
+
+		a, err := batchWeight(i)
+		if err != nil {
+			return fmt.Errorf("bip340: batch verify: %w", err)
+		}
+
+		sum.Add(s.Mul(a))
+
+		// acc -= a*R + a*e*P
+		e := challenge(sigs[i][:32], pubKeys[i], msgs[i])
+		e.Mul(a).Negate()
+		a.Negate()
+
+		var aR, eP, sumPoints btcec.JacobianPoint
+
+		btcec.ScalarMultNonConst(a, r, &aR)
+		btcec.ScalarMultNonConst(e, p, &eP)
+		btcec.AddNonConst(&aR, &eP, &sumPoints)
+		acc = add(acc, &sumPoints)
+	}
+
+	var sG btcec.JacobianPoint
+
+	btcec.ScalarBaseMultNonConst(&sum, &sG)
+	acc = add(acc, &sG)
+
+	if !isInfinity(acc) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// batchWeight returns the weight of the signature i of a batch, 1 for the first one and a random non zero scalar for
+// the others.
+func batchWeight(i int) (*btcec.ModNScalar, error) {
+	a := new(btcec.ModNScalar)
+
+	if i == 0 {
+		return a.SetInt(1), nil
+	}
+
+	var b [32]byte
+
+	for a.IsZero() {
+		if _, err := rand.Read(b[:]); err != nil {
+			return nil, err
+		}
+
+		a.SetBytes(&b)
+	}
+
+	return a, nil
+}
+
+// keyPair returns the secret scalar of privKey, negated if needed for its public point P to have an even Y, and P.
+func keyPair(privKey []byte) (btcec.ModNScalar, *btcec.JacobianPoint, error) {
+	var d btcec.ModNScalar
+
+	if len(privKey) != PrivateKeySize || d.SetByteSlice(privKey) || d.IsZero() {
+		return d, nil, errInvalidPrivateKey
+	}
+
+	p := new(btcec.JacobianPoint)
+	btcec.ScalarBaseMultNonConst(&d, p)
+	p.ToAffine()
+
+	if p.Y.IsOdd() {
+		d.Negate()
+	}
+
+	return d, p, nil
+}
+
+// liftX returns the point of x coordinate x with an even Y.
+func liftX(x []byte) (*btcec.JacobianPoint, error) {
+	var fx, fy btcec.FieldVal
+
+	if len(x) != PublicKeySize || fx.SetByteSlice(x) {
+		return nil, errInvalidPublicKey
+	}
+
+	if !btcec.DecompressY(&fx, false, &fy) {
+		return nil, errInvalidPublicKey
+	}
+
+	p := btcec.MakeJacobianPoint(&fx, fy.Normalize(), new(btcec.FieldVal).SetInt(1))
+
+	return &p, nil
+}
+
+// parseSignature returns r and s of sig, it fails if r isn't a field element or s isn't reduced.
+func parseSignature(sig []byte) (*btcec.FieldVal, *btcec.ModNScalar, error) {
+	if len(sig) != SignatureSize {
+		return nil, nil, ErrInvalidSignature
+	}
+
+	r, s := new(btcec.FieldVal), new(btcec.ModNScalar)
+
+	if r.SetByteSlice(sig[:32]) || s.SetByteSlice(sig[32:]) {
+		return nil, nil, ErrInvalidSignature
+	}
+
+	return r, s, nil
+}
+
+// challenge returns e = int(hash_challenge(bytes(R) || bytes(P) || m)) mod n.
+func challenge(rx, pk, msg []byte) *btcec.ModNScalar {
+	h := taggedHash(challengeTag, rx, pk, msg)
+
+	e := new(btcec.ModNScalar)
+	e.SetBytes(&h)
+
+	return e
+}
+
+// taggedHash is the BIP-340 tagged hash SHA256(SHA256(tag) || SHA256(tag) || x).
+func taggedHash(tag string, x ...[]byte) [32]byte {
+	tagHash := sha256.Sum256([]byte(tag))
+
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+
+	for _, b := range x {
+		h.Write(b)
+	}
+
+	var out [32]byte
+
+	copy(out[:], h.Sum(nil))
+
+	return out
+}
+
+// xBytes returns the 32 bytes x coordinate of the affine point p.
+func xBytes(p *btcec.JacobianPoint) []byte {
+	b := p.X.Bytes()
+
+	return b[:]
+}
+
+// add returns p1 + p2.
+func add(p1, p2 *btcec.JacobianPoint) *btcec.JacobianPoint {
+	r := new(btcec.JacobianPoint)
+	btcec.AddNonConst(p1, p2, r)
+
+	return r
+}
+
+func isInfinity(p *btcec.JacobianPoint) bool {
+	return (p.X.IsZero() && p.Y.IsZero()) || p.Z.IsZero()
+}
@@ -123,4 +123,14 @@ func init() {
 	if err != nil {
 		panic(fmt.Sprintf("ecdh.init() failed: %v", err))
 	}
+
+	err = registry.RegisterKeyManager(newX448ECDHKWPrivateKeyManager())
+	if err != nil {
+		panic(fmt.Sprintf("ecdh.init() failed: %v", err))
+	}
+
+	err = registry.RegisterKeyManager(newX448ECDHKWPublicKeyManager())
+	if err != nil {
+		panic(fmt.Sprintf("ecdh.init() failed: %v", err))
+	}
 }
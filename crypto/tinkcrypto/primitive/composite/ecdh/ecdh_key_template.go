@@ -33,6 +33,10 @@ const (
 	AES256CBCHMACSHA384
 	// AES256CBCHMACSHA512 AEAD.
 	AES256CBCHMACSHA512
+	// AES128GCM AEAD.
+	AES128GCM
+	// AES192GCM AEAD.
+	AES192GCM
 )
 
 // EncryptionAlgLabel maps AEADAlg to its label.
@@ -43,6 +47,8 @@ var EncryptionAlgLabel = map[AEADAlg]string{ //nolint:gochecknoglobals
 	AES192CBCHMACSHA384: "AES192CBCHMACSHA384",
 	AES256CBCHMACSHA384: "AES256CBCHMACSHA384",
 	AES256CBCHMACSHA512: "AES256CBCHMACSHA512",
+	AES128GCM:           "AES128GCM",
+	AES192GCM:           "AES192GCM",
 }
 
 // NISTP256ECDHKWKeyTemplate is a KeyTemplate that generates a key that accepts a CEK for JWE content
@@ -97,6 +103,23 @@ func X25519ECDHKWKeyTemplate() *tinkpb.KeyTemplate {
 	return createKeyTemplate(false, XC20P, commonpb.EllipticCurveType_CURVE25519, nil)
 }
 
+// X448ECDHKWKeyTemplate is a KeyTemplate that generates a key that accepts a CEK for JWE content
+// encryption. CEK wrapping is done outside of this Tink key (in the tinkcrypto service).
+// Keys from this template represent a valid recipient public/private key pairs and can be stored in the KMS.The
+// recipient key represented in this key template uses the following key wrapping curve:
+//   - Curve448
+//
+// Keys created with this template are mainly used for key wrapping of a cek. They are independent of the AEAD content
+// encryption algorithm.
+func X448ECDHKWKeyTemplate() *tinkpb.KeyTemplate {
+	// xc20p is set to pass key generation in the key manager, it's irrelevant to the key or its intended use.
+	// Tink has no Curve448 curve type, the curve is set by the key type URL.
+	t := createKeyTemplate(false, XC20P, commonpb.EllipticCurveType_UNKNOWN_CURVE, nil)
+	t.TypeUrl = x448ECDHKWPrivateKeyTypeURL
+
+	return t
+}
+
 // KeyTemplateForECDHPrimitiveWithCEK is similar to NISTP256ECDHKWKeyTemplate but adding the cek to execute the
 // CompositeEncrypt primitive for encrypting a message targeted to one ore more recipients. KW is not executed by this
 // template, so it is ignored and set to NIST P Curved key by default.
@@ -104,10 +127,13 @@ func X25519ECDHKWKeyTemplate() *tinkpb.KeyTemplate {
 // The key created from this template has no recipient key info linked to it. It is exclusively used for primitive
 // execution using content encryption. Available content encryption algorithms:
 //   - AES256GCM, XChacaha20Poly1305, AES128CBC+HMAC256, AES192CBC+HMAC384, AES256CBC+HMAC384, AES256CBC+HMAC512
+//   - AES128GCM, AES192GCM
 //
 // It works with both key wrapping modes (executed outside of the key primitive created by this template):
 // NIST P kw or XC20P kw
 // cek should be of size:
+// - 16 bytes for AES128GCM.
+// - 24 bytes for AES192GCM.
 // - 32 bytes for AES256GCM, XChacaha20Poly1305, AES128CBC+HMAC256.
 // - 48 bytes for AES192CBC+HMAC384.
 // - 56 bytes for AES256CBC+HMAC384.
@@ -160,6 +186,10 @@ func getTypeParams(nistpKW bool, encAlg AEADAlg, cek []byte) (string, ecdhpb.Key
 	switch encAlg {
 	case AES256GCM:
 		keyTemplate = aead.AES256GCMKeyTemplate()
+	case AES128GCM:
+		keyTemplate = aead.AES128GCMKeyTemplate()
+	case AES192GCM:
+		keyTemplate = cbcaead.AES192GCMKeyTemplate()
 	case AES128CBCHMACSHA256, AES192CBCHMACSHA384, AES256CBCHMACSHA384, AES256CBCHMACSHA512:
 		switch len(cek) {
 		case subtle.AES128Size * twoKeys:
@@ -163,6 +163,28 @@ func TestECDHESKeyTemplateSuccess(t *testing.T) {
 			tmplFunc: X25519ECDHKWKeyTemplate,
 			encAlg:   AES256CBCHMACSHA512,
 		},
+		{
+			tcName:   "create ECDH NIST P-256 KW with AES128-GCM key templates test",
+			tmplFunc: NISTP256ECDHKWKeyTemplate,
+			nistpKW:  true,
+			encAlg:   AES128GCM,
+		},
+		{
+			tcName:   "create ECDH NIST P-384 KW with AES192-GCM key templates test",
+			tmplFunc: NISTP384ECDHKWKeyTemplate,
+			nistpKW:  true,
+			encAlg:   AES192GCM,
+		},
+		{
+			tcName:   "creat ECDH X25519 KW with AES128-GCM key templates test",
+			tmplFunc: X25519ECDHKWKeyTemplate,
+			encAlg:   AES128GCM,
+		},
+		{
+			tcName:   "creat ECDH X25519 KW with AES192-GCM key templates test",
+			tmplFunc: X25519ECDHKWKeyTemplate,
+			encAlg:   AES192GCM,
+		},
 	}
 
 	for _, tt := range flagTests {
@@ -223,6 +245,10 @@ func TestECDHESKeyTemplateSuccess(t *testing.T) {
 
 func createCEK(cbcAlg AEADAlg) []byte {
 	switch cbcAlg {
+	case AES128GCM:
+		return random.GetRandomBytes(uint32(subtle.AES128Size)) // cek: 16 bytes.
+	case AES192GCM:
+		return random.GetRandomBytes(uint32(subtle.AES192Size)) // cek: 24 bytes.
 	case AES128CBCHMACSHA256:
 		return random.GetRandomBytes(uint32(subtle.AES128Size * 2)) // cek: 32 bytes.
 	case AES192CBCHMACSHA384:
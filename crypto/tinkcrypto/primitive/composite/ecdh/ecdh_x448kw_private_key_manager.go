@@ -0,0 +1,202 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ecdh
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cloudflare/circl/dh/x448"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/composite"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/composite/ecdh/subtle"
+	ecdhpb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
+)
+
+const (
+	x448ECDHKWPrivateKeyVersion = 0
+	x448ECDHKWPrivateKeyTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.X448EcdhKwPrivateKey"
+)
+
+// common errors.
+var (
+	errInvalidx448ECDHKWPrivateKey       = errors.New("x448kw_ecdh_private_key_manager: invalid key")
+	errInvalidx448ECDHKWPrivateKeyFormat = errors.New("x448kw_ecdh_private_key_manager: invalid key format")
+)
+
+// x448ECDHKWPrivateKeyManager is an implementation of PrivateKeyManager interface for X448 key wrapping.
+// It generates new ECDHPrivateKey (X448 KW) keys and produces new instances of ECDHAEADCompositeDecrypt subtle.
+// Tink has no Curve448 curve type: X448 keys are identified by their type URL and their curve type is not set.
+type x448ECDHKWPrivateKeyManager struct{}
+
+// Assert that x448ECDHKWPrivateKeyManager implements the PrivateKeyManager interface.
+var _ registry.PrivateKeyManager = (*x448ECDHKWPrivateKeyManager)(nil)
+
+// newX448ECDHKWPrivateKeyManager creates a new x448ECDHKWPrivateKeyManager.
+func newX448ECDHKWPrivateKeyManager() *x448ECDHKWPrivateKeyManager {
+	return new(x448ECDHKWPrivateKeyManager)
+}
+
+// Primitive creates an ECDHESPrivateKey subtle for the given serialized ECDHESPrivateKey proto.
+func (km *x448ECDHKWPrivateKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidx448ECDHKWPrivateKey
+	}
+
+	key := new(ecdhpb.EcdhAeadPrivateKey)
+
+	err := proto.Unmarshal(serializedKey, key)
+	if err != nil {
+		return nil, errInvalidx448ECDHKWPrivateKey
+	}
+
+	err = km.validateKey(key)
+	if err != nil {
+		return nil, errInvalidx448ECDHKWPrivateKey
+	}
+
+	rEnc, err := composite.NewRegisterCompositeAEADEncHelper(key.PublicKey.Params.EncParams.AeadEnc)
+	if err != nil {
+		return nil, fmt.Errorf("x448kw_ecdh_private_key_manager: NewRegisterCompositeAEADEncHelper "+
+			"failed: %w", err)
+	}
+
+	return subtle.NewECDHAEADCompositeDecrypt(rEnc, key.PublicKey.Params.EncParams.CEK), nil
+}
+
+// NewKey creates a new key according to the specification of ECDHESPrivateKey format.
+func (km *x448ECDHKWPrivateKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidx448ECDHKWPrivateKeyFormat
+	}
+
+	keyFormat := new(ecdhpb.EcdhAeadKeyFormat)
+
+	err := proto.Unmarshal(serializedKeyFormat, keyFormat)
+	if err != nil {
+		return nil, errInvalidx448ECDHKWPrivateKeyFormat
+	}
+
+	err = validateKeyX448Format(keyFormat.Params)
+	if err != nil {
+		return nil, errInvalidx448ECDHKWPrivateKeyFormat
+	}
+
+	var pub, pvt x448.Key
+
+	_, err = io.ReadFull(rand.Reader, pvt[:])
+	if err != nil {
+		return nil, fmt.Errorf("x448kw_ecdh_private_key_manager: GenerateECDHKeyPair failed: %w", err)
+	}
+
+	x448.KeyGen(&pub, &pvt)
+
+	return &ecdhpb.EcdhAeadPrivateKey{
+		Version:  x448ECDHKWPrivateKeyVersion,
+		KeyValue: pvt[:],
+		PublicKey: &ecdhpb.EcdhAeadPublicKey{
+			Version: x448ECDHKWPrivateKeyVersion,
+			Params:  keyFormat.Params,
+			X:       pub[:],
+		},
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to the specification of ECDHESPrivateKey Format.
+// It should be used solely by the key management API.
+func (km *x448ECDHKWPrivateKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, fmt.Errorf("x448kw_ecdh_private_key_manager: Proto.Marshal failed: %w", err)
+	}
+
+	return &tinkpb.KeyData{
+		TypeUrl:         x448ECDHKWPrivateKeyTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}, nil
+}
+
+// PublicKeyData returns the enclosed public key data of serializedPrivKey.
+func (km *x448ECDHKWPrivateKeyManager) PublicKeyData(serializedPrivKey []byte) (*tinkpb.KeyData, error) {
+	privKey := new(ecdhpb.EcdhAeadPrivateKey)
+
+	err := proto.Unmarshal(serializedPrivKey, privKey)
+	if err != nil {
+		return nil, errInvalidx448ECDHKWPrivateKey
+	}
+
+	serializedPubKey, err := proto.Marshal(privKey.PublicKey)
+	if err != nil {
+		return nil, errInvalidx448ECDHKWPrivateKey
+	}
+
+	return &tinkpb.KeyData{
+		TypeUrl:         x448ECDHKWPublicKeyTypeURL,
+		Value:           serializedPubKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *x448ECDHKWPrivateKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == x448ECDHKWPrivateKeyTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *x448ECDHKWPrivateKeyManager) TypeURL() string {
+	return x448ECDHKWPrivateKeyTypeURL
+}
+
+// validateKey validates the given ECDHPrivateKey.
+func (km *x448ECDHKWPrivateKeyManager) validateKey(key *ecdhpb.EcdhAeadPrivateKey) error {
+	err := keyset.ValidateKeyVersion(key.Version, x448ECDHKWPrivateKeyVersion)
+	if err != nil {
+		return fmt.Errorf("x448kw_ecdh_private_key_manager: invalid key: %w", err)
+	}
+
+	if len(key.KeyValue) != x448.Size {
+		return errors.New("x448kw_ecdh_private_key_manager: invalid key size")
+	}
+
+	return validateKeyX448Format(key.PublicKey.Params)
+}
+
+// validateKeyX448Format validates the given ECDHESKeyFormat of an X448 key.
+func validateKeyX448Format(params *ecdhpb.EcdhAeadParams) error {
+	km, err := registry.GetKeyManager(params.EncParams.AeadEnc.TypeUrl)
+	if err != nil {
+		return fmt.Errorf("x448kw_ecdh_private_key_manager: GetKeyManager error: %w", err)
+	}
+
+	_, err = km.NewKeyData(params.EncParams.AeadEnc.Value)
+	if err != nil {
+		return fmt.Errorf("x448kw_ecdh_private_key_manager: NewKeyData error: %w", err)
+	}
+
+	if params.KwParams.KeyType.String() != ecdhpb.KeyType_OKP.String() {
+		return fmt.Errorf("x448kw_ecdh_private_key_manager: invalid key type %v", params.KwParams.KeyType)
+	}
+
+	if params.KwParams.CurveType != commonpb.EllipticCurveType_UNKNOWN_CURVE {
+		return fmt.Errorf("x448kw_ecdh_private_key_manager: invalid curve %v", params.KwParams.CurveType)
+	}
+
+	return nil
+}
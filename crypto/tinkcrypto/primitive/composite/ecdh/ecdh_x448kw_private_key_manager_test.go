@@ -0,0 +1,144 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ecdh
+
+import (
+	"testing"
+
+	"github.com/cloudflare/circl/dh/x448"
+	"github.com/google/tink/go/aead"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	ecdhpb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
+)
+
+func TestECDHX448PrivateKeyManager_Primitive(t *testing.T) {
+	km := newX448ECDHKWPrivateKeyManager()
+
+	t.Run("Test private key manager Primitive() with empty serialized key", func(t *testing.T) {
+		p, err := km.Primitive([]byte(""))
+		require.EqualError(t, err, errInvalidx448ECDHKWPrivateKey.Error())
+		require.Empty(t, p)
+	})
+
+	t.Run("Test private key manager Primitive() with bad serialize key", func(t *testing.T) {
+		p, err := km.Primitive([]byte("bad.data"))
+		require.EqualError(t, err, errInvalidx448ECDHKWPrivateKey.Error())
+		require.Empty(t, p)
+	})
+
+	t.Run("success private key manager Primitive()", func(t *testing.T) {
+		sPrivKey := newSerializedX448Key(t, km, commonpb.EllipticCurveType_UNKNOWN_CURVE)
+
+		p, err := km.Primitive(sPrivKey)
+		require.NoError(t, err)
+		require.NotEmpty(t, p)
+	})
+
+	t.Run("Test private key manager Primitive() with bad key size", func(t *testing.T) {
+		key := newX448Key(t, km, commonpb.EllipticCurveType_UNKNOWN_CURVE)
+		key.KeyValue = key.KeyValue[1:]
+
+		sPrivKey, err := proto.Marshal(key)
+		require.NoError(t, err)
+
+		p, err := km.Primitive(sPrivKey)
+		require.EqualError(t, err, errInvalidx448ECDHKWPrivateKey.Error())
+		require.Empty(t, p)
+	})
+}
+
+func TestECDHX448PrivateKeyManager_DoesSupport(t *testing.T) {
+	km := newX448ECDHKWPrivateKeyManager()
+	require.False(t, km.DoesSupport("bad/url"))
+	require.True(t, km.DoesSupport(x448ECDHKWPrivateKeyTypeURL))
+	require.Equal(t, x448ECDHKWPrivateKeyTypeURL, km.TypeURL())
+}
+
+func TestECDHX448PrivateKeyManager_NewKey(t *testing.T) {
+	km := newX448ECDHKWPrivateKeyManager()
+
+	t.Run("Test private key manager NewKey() with nil key", func(t *testing.T) {
+		k, err := km.NewKey(nil)
+		require.EqualError(t, err, errInvalidx448ECDHKWPrivateKeyFormat.Error())
+		require.Empty(t, k)
+	})
+
+	t.Run("success private key manager NewKey(), NewKeyData() and PublicKeyData()", func(t *testing.T) {
+		key := newX448Key(t, km, commonpb.EllipticCurveType_UNKNOWN_CURVE)
+		require.Len(t, key.KeyValue, x448.Size)
+		require.Len(t, key.PublicKey.X, x448.Size)
+
+		kd, err := km.NewKeyData(x448KeyFormat(t, commonpb.EllipticCurveType_UNKNOWN_CURVE, ecdhpb.KeyType_OKP))
+		require.NoError(t, err)
+		require.Equal(t, x448ECDHKWPrivateKeyTypeURL, kd.TypeUrl)
+		require.Equal(t, tinkpb.KeyData_ASYMMETRIC_PRIVATE, kd.KeyMaterialType)
+
+		pubKD, err := km.PublicKeyData(kd.Value)
+		require.NoError(t, err)
+		require.Equal(t, x448ECDHKWPublicKeyTypeURL, pubKD.TypeUrl)
+		require.Equal(t, tinkpb.KeyData_ASYMMETRIC_PUBLIC, pubKD.KeyMaterialType)
+	})
+
+	t.Run("Test private key manager NewKey() with a Curve25519 curve", func(t *testing.T) {
+		p, err := km.NewKey(x448KeyFormat(t, commonpb.EllipticCurveType_CURVE25519, ecdhpb.KeyType_OKP))
+		require.EqualError(t, err, errInvalidx448ECDHKWPrivateKeyFormat.Error())
+		require.Empty(t, p)
+	})
+
+	t.Run("Test private key manager NewKey() with EC key type", func(t *testing.T) {
+		p, err := km.NewKey(x448KeyFormat(t, commonpb.EllipticCurveType_UNKNOWN_CURVE, ecdhpb.KeyType_EC))
+		require.EqualError(t, err, errInvalidx448ECDHKWPrivateKeyFormat.Error())
+		require.Empty(t, p)
+	})
+}
+
+func x448KeyFormat(t *testing.T, curveType commonpb.EllipticCurveType, keyType ecdhpb.KeyType) []byte {
+	t.Helper()
+
+	keyFormat, err := proto.Marshal(&ecdhpb.EcdhAeadKeyFormat{
+		Params: &ecdhpb.EcdhAeadParams{
+			KwParams: &ecdhpb.EcdhKwParams{
+				CurveType: curveType,
+				KeyType:   keyType,
+			},
+			EncParams: &ecdhpb.EcdhAeadEncParams{
+				AeadEnc: aead.XChaCha20Poly1305KeyTemplate(),
+			},
+			EcPointFormat: commonpb.EcPointFormat_COMPRESSED,
+		},
+	})
+	require.NoError(t, err)
+
+	return keyFormat
+}
+
+func newX448Key(t *testing.T, km *x448ECDHKWPrivateKeyManager,
+	curveType commonpb.EllipticCurveType) *ecdhpb.EcdhAeadPrivateKey {
+	t.Helper()
+
+	p, err := km.NewKey(x448KeyFormat(t, curveType, ecdhpb.KeyType_OKP))
+	require.NoError(t, err)
+
+	key, ok := p.(*ecdhpb.EcdhAeadPrivateKey)
+	require.True(t, ok)
+
+	return key
+}
+
+func newSerializedX448Key(t *testing.T, km *x448ECDHKWPrivateKeyManager,
+	curveType commonpb.EllipticCurveType) []byte {
+	t.Helper()
+
+	sKey, err := proto.Marshal(newX448Key(t, km, curveType))
+	require.NoError(t, err)
+
+	return sKey
+}
@@ -0,0 +1,103 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ecdh
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cloudflare/circl/dh/x448"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/composite"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/composite/ecdh/subtle"
+	ecdhpb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
+)
+
+const (
+	x448ECDHKWPublicKeyVersion = 0
+	x448ECDHKWPublicKeyTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.X448EcdhKwPublicKey"
+)
+
+// common errors.
+var errInvalidx448ECDHKWPublicKey = errors.New("x448kw_ecdh_public_key_manager: invalid key")
+
+// x448ECDHKWPublicKeyManager is an implementation of KeyManager interface for X448 key wrapping.
+// It produces new instances of ECDHAEADCompositeEncrypt subtle.
+type x448ECDHKWPublicKeyManager struct{}
+
+// Assert that x448ECDHKWPublicKeyManager implements the KeyManager interface.
+var _ registry.KeyManager = (*x448ECDHKWPublicKeyManager)(nil)
+
+// newX448ECDHKWPublicKeyManager creates a new x448ECDHKWPublicKeyManager.
+func newX448ECDHKWPublicKeyManager() *x448ECDHKWPublicKeyManager {
+	return new(x448ECDHKWPublicKeyManager)
+}
+
+// Primitive creates an ECDHESPublicKey subtle for the given serialized EcdhAeadPublicKey proto.
+func (km *x448ECDHKWPublicKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidx448ECDHKWPublicKey
+	}
+
+	ecdhPubKey := new(ecdhpb.EcdhAeadPublicKey)
+
+	err := proto.Unmarshal(serializedKey, ecdhPubKey)
+	if err != nil {
+		return nil, errInvalidx448ECDHKWPublicKey
+	}
+
+	err = km.validateKey(ecdhPubKey)
+	if err != nil {
+		return nil, errInvalidx448ECDHKWPublicKey
+	}
+
+	rEnc, err := composite.NewRegisterCompositeAEADEncHelper(ecdhPubKey.Params.EncParams.AeadEnc)
+	if err != nil {
+		return nil, fmt.Errorf("x448kw_ecdh_public_key_manager: NewRegisterCompositeAEADEncHelper "+
+			"failed: %w", err)
+	}
+
+	return subtle.NewECDHAEADCompositeEncrypt(rEnc, ecdhPubKey.Params.EncParams.CEK), nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *x448ECDHKWPublicKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == x448ECDHKWPublicKeyTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *x448ECDHKWPublicKeyManager) TypeURL() string {
+	return x448ECDHKWPublicKeyTypeURL
+}
+
+// NewKey is not implemented for public key manager.
+func (km *x448ECDHKWPublicKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	return nil, errors.New("x448kw_ecdh_public_key_manager: NewKey not implemented")
+}
+
+// NewKeyData is not implemented for public key manager.
+func (km *x448ECDHKWPublicKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	return nil, errors.New("x448kw_ecdh_public_key_manager: NewKeyData not implemented")
+}
+
+// validateKey validates the given EcdhAeadPublicKey.
+func (km *x448ECDHKWPublicKeyManager) validateKey(key *ecdhpb.EcdhAeadPublicKey) error {
+	err := keyset.ValidateKeyVersion(key.Version, x448ECDHKWPublicKeyVersion)
+	if err != nil {
+		return fmt.Errorf("x448kw_ecdh_public_key_manager: invalid key: %w", err)
+	}
+
+	if len(key.X) != x448.Size {
+		return errors.New("x448kw_ecdh_public_key_manager: invalid key size")
+	}
+
+	return validateKeyX448Format(key.Params)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ecdh
+
+import (
+	"testing"
+
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestECDHX448PublicKeyManager_Primitive(t *testing.T) {
+	km := newX448ECDHKWPublicKeyManager()
+
+	t.Run("Test public key manager Primitive() with empty serialized key", func(t *testing.T) {
+		p, err := km.Primitive([]byte(""))
+		require.EqualError(t, err, errInvalidx448ECDHKWPublicKey.Error())
+		require.Empty(t, p)
+	})
+
+	t.Run("Test public key manager Primitive() with bad serialize key", func(t *testing.T) {
+		p, err := km.Primitive([]byte("bad.data"))
+		require.EqualError(t, err, errInvalidx448ECDHKWPublicKey.Error())
+		require.Empty(t, p)
+	})
+
+	privKey := newX448Key(t, newX448ECDHKWPrivateKeyManager(), commonpb.EllipticCurveType_UNKNOWN_CURVE)
+
+	t.Run("success public key manager Primitive()", func(t *testing.T) {
+		sPubKey, err := proto.Marshal(privKey.PublicKey)
+		require.NoError(t, err)
+
+		p, err := km.Primitive(sPubKey)
+		require.NoError(t, err)
+		require.NotEmpty(t, p)
+	})
+
+	t.Run("Test public key manager Primitive() with bad key size", func(t *testing.T) {
+		privKey.PublicKey.X = privKey.PublicKey.X[1:]
+
+		sBadPubKey, err := proto.Marshal(privKey.PublicKey)
+		require.NoError(t, err)
+
+		p, err := km.Primitive(sBadPubKey)
+		require.EqualError(t, err, errInvalidx448ECDHKWPublicKey.Error())
+		require.Empty(t, p)
+	})
+}
+
+func TestECDHX448PublicKeyManager_DoesSupport(t *testing.T) {
+	km := newX448ECDHKWPublicKeyManager()
+	require.False(t, km.DoesSupport("bad/url"))
+	require.True(t, km.DoesSupport(x448ECDHKWPublicKeyTypeURL))
+	require.Equal(t, x448ECDHKWPublicKeyTypeURL, km.TypeURL())
+}
+
+func TestECDHX448PublicKeyManager_NewKeyAndNewKeyData(t *testing.T) {
+	km := newX448ECDHKWPublicKeyManager()
+
+	k, err := km.NewKey(nil)
+	require.EqualError(t, err, "x448kw_ecdh_public_key_manager: NewKey not implemented")
+	require.Empty(t, k)
+
+	kd, err := km.NewKeyData(nil)
+	require.EqualError(t, err, "x448kw_ecdh_public_key_manager: NewKeyData not implemented")
+	require.Empty(t, kd)
+}
@@ -20,6 +20,8 @@ import (
 	"github.com/google/tink/go/tink"
 	"github.com/stretchr/testify/require"
 
+	cbchmacaead "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aead"
+	subtlecbchmacaead "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aead/subtle"
 	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/composite"
 )
 
@@ -154,6 +156,49 @@ func TestEncryptDecryptWithSingleRecipient(t *testing.T) {
 	require.EqualValues(t, pt, dpt)
 }
 
+// FuzzECDHAEADCompositeDecrypt checks that Decrypt rejects malformed serialized ciphertexts without panicking, with
+// the content encryption helpers of the AES-GCM, XChaCha20Poly1305 and AES-CBC-HMAC content encryptions.
+func FuzzECDHAEADCompositeDecrypt(f *testing.F) {
+	contentEncryptions := []struct {
+		template *tinkpb.KeyTemplate
+		keySize  int
+	}{
+		{template: aead.AES256GCMKeyTemplate(), keySize: subtlecbchmacaead.AES256Size},
+		{template: aead.XChaCha20Poly1305KeyTemplate(), keySize: subtlecbchmacaead.AES256Size},
+		{template: cbchmacaead.AES256CBCHMACSHA512KeyTemplate(), keySize: subtlecbchmacaead.AES256Size * 2},
+	}
+
+	decrypters := make([]*ECDHAEADCompositeDecrypt, len(contentEncryptions))
+
+	for i, c := range contentEncryptions {
+		encHelper, err := composite.NewRegisterCompositeAEADEncHelper(c.template)
+		require.NoError(f, err)
+
+		cek := random.GetRandomBytes(uint32(c.keySize))
+
+		ct, err := NewECDHAEADCompositeEncrypt(encHelper, cek).Encrypt([]byte("plaintext"), []byte("aad"))
+		require.NoError(f, err)
+
+		decrypters[i] = NewECDHAEADCompositeDecrypt(encHelper, cek)
+
+		f.Add(uint8(i), ct, []byte("aad"))
+	}
+
+	f.Add(uint8(0), []byte(`{}`), []byte{})
+	f.Add(uint8(1), []byte(`{"ciphertext":"","iv":"","tag":""}`), []byte{})
+	f.Add(uint8(2), []byte(`{"ciphertext":"AA==","iv":"AAAAAAAAAAAAAAAAAAAAAA==","tag":"AA=="}`), []byte("aad"))
+	f.Add(uint8(0), []byte(`[`), []byte{})
+
+	f.Fuzz(func(t *testing.T, helper uint8, ciphertext, aad []byte) {
+		d := decrypters[int(helper)%len(decrypters)]
+
+		pt, err := d.Decrypt(ciphertext, aad)
+		if err != nil {
+			require.Nil(t, pt)
+		}
+	})
+}
+
 func getAEADPrimitive(t *testing.T, kt *tinkpb.KeyTemplate) tink.AEAD {
 	t.Helper()
 
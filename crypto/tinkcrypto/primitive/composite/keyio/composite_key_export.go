@@ -39,6 +39,12 @@ const (
 	x25519ECDHKWPublicKeyTypeURL  = "type.hyperledger.org/hyperledger.aries.crypto.tink.X25519EcdhKwPublicKey"
 	nistPECDHKWPrivateKeyTypeURL  = "type.hyperledger.org/hyperledger.aries.crypto.tink.NistPEcdhKwPrivateKey"
 	x25519ECDHKWPrivateKeyTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.X25519EcdhKwPrivateKey"
+	x448ECDHKWPublicKeyTypeURL    = "type.hyperledger.org/hyperledger.aries.crypto.tink.X448EcdhKwPublicKey"
+	x448ECDHKWPrivateKeyTypeURL   = "type.hyperledger.org/hyperledger.aries.crypto.tink.X448EcdhKwPrivateKey"
+
+	// x448Crv is the JWK curve name of X448 keys. Tink has no Curve448 curve type, X448 keys are identified by their
+	// type URL.
+	x448Crv = "X448"
 )
 
 //nolint:gochecknoglobals
@@ -53,6 +59,7 @@ var ecdhKeyTypes = map[string]kms.KeyType{
 // The keyset must have a keyURL value equal to either one of the public key URLs:
 //   - `nistPECDHKWPublicKeyTypeURL`
 //   - `x25519ECDHKWPublicKeyTypeURL`
+//   - `x448ECDHKWPublicKeyTypeURL`
 //
 // constants of ecdh package.
 // Note: This writer should be used only for ECDH public key exports. Other export of public keys should be
@@ -139,6 +146,18 @@ func protoToCompositeKey(keyData *tinkpb.KeyData) (*cryptoapi.PublicKey, kms.Key
 		if err != nil {
 			return nil, "", err
 		}
+	case x448ECDHKWPublicKeyTypeURL:
+		cKey, err = newECDHKey(keyData.Value)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return &cryptoapi.PublicKey{
+			KID:   cKey.kid(),
+			Type:  ecdhpb.KeyType_OKP.String(),
+			Curve: x448Crv,
+			X:     cKey.x(),
+		}, kms.X448ECDHKWType, nil
 	default:
 		return nil, "", fmt.Errorf("can't export key with keyURL:%s", keyData.TypeUrl)
 	}
@@ -287,7 +306,7 @@ func PublicKeyToKeysetHandle(pubKey *cryptoapi.PublicKey, aeadAlg ecdh.AEADAlg)
 		return nil, fmt.Errorf("publicKeyToKeysetHandle: failed to convert key type to proto: %w", err)
 	}
 
-	encT, keyURL, err := keyTemplateAndURL(cp, aeadAlg, true)
+	encT, keyURL, err := curveKeyTemplateAndURL(pubKey.Curve, cp, aeadAlg, true)
 	if err != nil {
 		return nil, fmt.Errorf("publicKeyToKeysetHandle: %w", err)
 	}
@@ -345,7 +364,7 @@ func PrivateKeyToKeysetHandle(privKey *cryptoapi.PrivateKey, aeadAlg ecdh.AEADAl
 		return nil, fmt.Errorf("privateKeyToKeysetHandle: failed to convert key type to proto: %w", err)
 	}
 
-	encT, keyURL, err := keyTemplateAndURL(cp, aeadAlg, false)
+	encT, keyURL, err := curveKeyTemplateAndURL(privKey.PublicKey.Curve, cp, aeadAlg, false)
 	if err != nil {
 		return nil, fmt.Errorf("privateKeyToKeysetHandle: %w", err)
 	}
@@ -402,13 +421,26 @@ var keyTemplateToPrivateKeyURL = map[commonpb.EllipticCurveType]string{ //nolint
 	commonpb.EllipticCurveType_CURVE25519: x25519ECDHKWPrivateKeyTypeURL,
 }
 
+// curveKeyTemplateAndURL is keyTemplateAndURL for the given curve name. X448 keys have no Tink curve type, their key
+// URL is selected from the curve name instead.
+func curveKeyTemplateAndURL(crv string, cp commonpb.EllipticCurveType, aeadAlg ecdh.AEADAlg,
+	isPublic bool) (*tinkpb.KeyTemplate, string, error) {
+	if crv != x448Crv {
+		return keyTemplateAndURL(cp, aeadAlg, isPublic)
+	}
+
+	encT, err := aeadKeyTemplate(aeadAlg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return encT, x448KeyURL(isPublic), nil
+}
+
 func keyTemplateAndURL(cp commonpb.EllipticCurveType, aeadAlg ecdh.AEADAlg,
 	isPublic bool) (*tinkpb.KeyTemplate, string, error) {
 	// set ecdh kw public keyTypeURL.
-	var (
-		encT   *tinkpb.KeyTemplate
-		keyURL string
-	)
+	var keyURL string
 
 	if isPublic {
 		keyURL = keyTemplateToPublicKeyURL[cp]
@@ -420,25 +452,36 @@ func keyTemplateAndURL(cp commonpb.EllipticCurveType, aeadAlg ecdh.AEADAlg,
 		return nil, "", fmt.Errorf("invalid key curve: '%v'", cp)
 	}
 
-	// set aeadAlg encryption primitive template.
+	encT, err := aeadKeyTemplate(aeadAlg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return encT, keyURL, nil
+}
+
+// aeadKeyTemplate returns the aeadAlg encryption primitive template.
+func aeadKeyTemplate(aeadAlg ecdh.AEADAlg) (*tinkpb.KeyTemplate, error) {
 	switch aeadAlg {
 	case ecdh.AES256GCM:
-		encT = tinkaead.AES256GCMKeyTemplate()
+		return tinkaead.AES256GCMKeyTemplate(), nil
+	case ecdh.AES128GCM:
+		return tinkaead.AES128GCMKeyTemplate(), nil
+	case ecdh.AES192GCM:
+		return aead.AES192GCMKeyTemplate(), nil
 	case ecdh.XC20P:
-		encT = tinkaead.XChaCha20Poly1305KeyTemplate()
+		return tinkaead.XChaCha20Poly1305KeyTemplate(), nil
 	case ecdh.AES128CBCHMACSHA256:
-		encT = aead.AES128CBCHMACSHA256KeyTemplate()
+		return aead.AES128CBCHMACSHA256KeyTemplate(), nil
 	case ecdh.AES192CBCHMACSHA384:
-		encT = aead.AES192CBCHMACSHA384KeyTemplate()
+		return aead.AES192CBCHMACSHA384KeyTemplate(), nil
 	case ecdh.AES256CBCHMACSHA384:
-		encT = aead.AES256CBCHMACSHA384KeyTemplate()
+		return aead.AES256CBCHMACSHA384KeyTemplate(), nil
 	case ecdh.AES256CBCHMACSHA512:
-		encT = aead.AES256CBCHMACSHA512KeyTemplate()
+		return aead.AES256CBCHMACSHA512KeyTemplate(), nil
 	default:
-		return nil, "", fmt.Errorf("invalid encryption algorithm: '%v'", ecdh.EncryptionAlgLabel[aeadAlg])
+		return nil, fmt.Errorf("invalid encryption algorithm: '%v'", ecdh.EncryptionAlgLabel[aeadAlg])
 	}
-
-	return encT, keyURL, nil
 }
 
 func getCurveProto(c string) (commonpb.EllipticCurveType, error) {
@@ -451,11 +494,22 @@ func getCurveProto(c string) (commonpb.EllipticCurveType, error) {
 		return commonpb.EllipticCurveType_NIST_P521, nil
 	case commonpb.EllipticCurveType_CURVE25519.String(), "X25519":
 		return commonpb.EllipticCurveType_CURVE25519, nil
+	case x448Crv:
+		// X448 keys don't set a curve type.
+		return commonpb.EllipticCurveType_UNKNOWN_CURVE, nil
 	default:
 		return commonpb.EllipticCurveType_UNKNOWN_CURVE, errors.New("unsupported curve")
 	}
 }
 
+func x448KeyURL(isPublic bool) string {
+	if isPublic {
+		return x448ECDHKWPublicKeyTypeURL
+	}
+
+	return x448ECDHKWPrivateKeyTypeURL
+}
+
 func getKeyType(k string) (ecdhpb.KeyType, error) {
 	switch k {
 	case ecdhpb.KeyType_EC.String():
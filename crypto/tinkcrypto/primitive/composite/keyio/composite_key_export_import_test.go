@@ -119,6 +119,35 @@ func TestPubKeyExport(t *testing.T) {
 	}
 }
 
+func TestX448PubKeyExport(t *testing.T) {
+	kh, err := keyset.NewHandle(ecdh.X448ECDHKWKeyTemplate())
+	require.NoError(t, err)
+
+	exportedKeyBytes := exportRawPublicKeyBytes(t, kh, false)
+
+	pubKey := new(cryptoapi.PublicKey)
+	err = json.Unmarshal(exportedKeyBytes, pubKey)
+	require.NoError(t, err)
+	require.Equal(t, x448Crv, pubKey.Curve)
+	require.Equal(t, ecdhpb.KeyType_OKP.String(), pubKey.Type)
+	require.Len(t, pubKey.X, 56)
+
+	pubKH, err := PublicKeyToKeysetHandle(pubKey, ecdh.XC20P)
+	require.NoError(t, err)
+	require.Equal(t, x448ECDHKWPublicKeyTypeURL, pubKH.KeysetInfo().KeyInfo[0].TypeUrl)
+
+	extractedPubKey, err := ExtractPrimaryPublicKey(pubKH)
+	require.NoError(t, err)
+	require.EqualValues(t, pubKey, extractedPubKey)
+
+	privKH, err := PrivateKeyToKeysetHandle(&cryptoapi.PrivateKey{
+		PublicKey: *pubKey,
+		D:         make([]byte, 56),
+	}, ecdh.AES256GCM)
+	require.NoError(t, err)
+	require.Equal(t, x448ECDHKWPrivateKeyTypeURL, privKH.KeysetInfo().KeyInfo[0].TypeUrl)
+}
+
 func testPrivateKeyAsKH(t *testing.T, pubKey *cryptoapi.PublicKey) {
 	var (
 		crv        elliptic.Curve
@@ -24,6 +24,7 @@ import (
 
 	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aead/subtle"
 	cbchmacpb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/aes_cbc_hmac_aead_go_proto"
+	"github.com/trustbloc/kms-go/internal/memguard"
 )
 
 const (
@@ -31,6 +32,8 @@ const (
 	AESCBCHMACAEADTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.AesCbcHmacAeadKey"
 	// AESGCMTypeURL for AESGCM content encryption URL identifier.
 	AESGCMTypeURL = "type.googleapis.com/google.crypto.tink.AesGcmKey"
+	// AESGCMAEADTypeURL for AESGCM content encryption with AES-192 keys (A192GCM) URL identifier.
+	AESGCMAEADTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.AesGcmKey"
 	// ChaCha20Poly1305TypeURL for Chacha20Poly1305 content encryption URL identifier.
 	ChaCha20Poly1305TypeURL = "type.googleapis.com/google.crypto.tink.ChaCha20Poly1305Key"
 	// XChaCha20Poly1305TypeURL for XChachaPoly1305 content encryption URL identifier.
@@ -76,7 +79,7 @@ func NewRegisterCompositeAEADEncHelper(k *tinkpb.KeyTemplate) (*RegisterComposit
 		if err != nil {
 			return nil, fmt.Errorf("compositeAEADEncHelper: failed to serialize cbcHMAC key format, error: %w", err)
 		}
-	case AESGCMTypeURL:
+	case AESGCMTypeURL, AESGCMAEADTypeURL:
 		gcmKeyFormat := new(gcmpb.AesGcmKeyFormat)
 
 		err = proto.Unmarshal(k.Value, gcmKeyFormat)
@@ -190,7 +193,10 @@ func (r *RegisterCompositeAEADEncHelper) GetAEAD(symmetricKeyValue []byte) (tink
 		return nil, err
 	}
 
+	// the key manager unmarshals its own copy of the key, the serialized one is not needed afterwards.
 	p, err := registry.Primitive(r.encKeyURL, sk)
+	memguard.Wipe(sk)
+
 	if err != nil {
 		return nil, err
 	}
@@ -215,7 +221,7 @@ func (r *RegisterCompositeAEADEncHelper) getSerializedKey(symmetricKeyValue []by
 		if err != nil {
 			return nil, fmt.Errorf("registerCompositeAEADEncHelper: failed to serialize key, error: %w", err)
 		}
-	case AESGCMTypeURL:
+	case AESGCMTypeURL, AESGCMAEADTypeURL:
 		sk, err = r.getSerializedAESGCMKey(symmetricKeyValue)
 		if err != nil {
 			return nil, fmt.Errorf("registerCompositeAEADEncHelper: failed to serialize key, error: %w", err)
@@ -0,0 +1,165 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rsakey
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/core/registry"
+	rsapb "github.com/google/tink/go/proto/rsa_ssa_pkcs1_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"google.golang.org/protobuf/proto"
+)
+
+var errNotSupported = errors.New("not supported")
+
+// PrivateKeyManager is a Tink private key manager of RSA keys. It generates new keys and creates its primitives from
+// the RSA private key with the NewPrimitive function.
+type PrivateKeyManager struct {
+	PrivateTypeURL string
+	PublicTypeURL  string
+	// Name is the key manager name used in errors.
+	Name         string
+	NewPrimitive func(key *rsa.PrivateKey, h crypto.Hash) interface{}
+}
+
+// Primitive creates the primitive of the serialized RSA private key proto.
+func (km *PrivateKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	key := new(rsapb.RsaSsaPkcs1PrivateKey)
+
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, fmt.Errorf("%s: %w", km.Name, errInvalidKey)
+	}
+
+	priv, h, err := PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", km.Name, err)
+	}
+
+	return km.NewPrimitive(priv, h), nil
+}
+
+// NewKey creates a new RSA private key proto of the given serialized RsaSsaPkcs1KeyFormat.
+func (km *PrivateKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	format := new(rsapb.RsaSsaPkcs1KeyFormat)
+
+	if err := proto.Unmarshal(serializedKeyFormat, format); err != nil {
+		return nil, fmt.Errorf("%s: invalid key format: %w", km.Name, err)
+	}
+
+	key, err := New(format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", km.Name, err)
+	}
+
+	return key, nil
+}
+
+// NewKeyData creates a new private KeyData of the given serialized RsaSsaPkcs1KeyFormat.
+func (km *PrivateKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", km.Name, err)
+	}
+
+	return &tinkpb.KeyData{
+		TypeUrl:         km.PrivateTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}, nil
+}
+
+// PublicKeyData extracts the public key data from the serialized private key.
+func (km *PrivateKeyManager) PublicKeyData(serializedPrivKey []byte) (*tinkpb.KeyData, error) {
+	key := new(rsapb.RsaSsaPkcs1PrivateKey)
+
+	if err := proto.Unmarshal(serializedPrivKey, key); err != nil {
+		return nil, fmt.Errorf("%s: %w", km.Name, errInvalidKey)
+	}
+
+	if _, _, err := PublicKey(key.PublicKey); err != nil {
+		return nil, fmt.Errorf("%s: %w", km.Name, err)
+	}
+
+	serializedPubKey, err := proto.Marshal(key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", km.Name, err)
+	}
+
+	return &tinkpb.KeyData{
+		TypeUrl:         km.PublicTypeURL,
+		Value:           serializedPubKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}, nil
+}
+
+// DoesSupport tells if typeURL is the private key type URL of the key manager.
+func (km *PrivateKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == km.PrivateTypeURL
+}
+
+// TypeURL returns the private key type URL of the key manager.
+func (km *PrivateKeyManager) TypeURL() string {
+	return km.PrivateTypeURL
+}
+
+// PublicKeyManager is a Tink key manager of RSA public keys. It doesn't support key generation.
+type PublicKeyManager struct {
+	PublicTypeURL string
+	// Name is the key manager name used in errors.
+	Name         string
+	NewPrimitive func(key *rsa.PublicKey, h crypto.Hash) interface{}
+}
+
+// Primitive creates the primitive of the serialized RSA public key proto.
+func (km *PublicKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	key := new(rsapb.RsaSsaPkcs1PublicKey)
+
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, fmt.Errorf("%s: %w", km.Name, errInvalidKey)
+	}
+
+	pub, h, err := PublicKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", km.Name, err)
+	}
+
+	return km.NewPrimitive(pub, h), nil
+}
+
+// NewKey is not supported.
+func (km *PublicKeyManager) NewKey([]byte) (proto.Message, error) {
+	return nil, fmt.Errorf("%s: %w", km.Name, errNotSupported)
+}
+
+// NewKeyData is not supported.
+func (km *PublicKeyManager) NewKeyData([]byte) (*tinkpb.KeyData, error) {
+	return nil, fmt.Errorf("%s: %w", km.Name, errNotSupported)
+}
+
+// DoesSupport tells if typeURL is the public key type URL of the key manager.
+func (km *PublicKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == km.PublicTypeURL
+}
+
+// TypeURL returns the public key type URL of the key manager.
+func (km *PublicKeyManager) TypeURL() string {
+	return km.PublicTypeURL
+}
+
+var (
+	_ registry.PrivateKeyManager = (*PrivateKeyManager)(nil)
+	_ registry.KeyManager        = (*PublicKeyManager)(nil)
+)
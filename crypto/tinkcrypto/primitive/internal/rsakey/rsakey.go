@@ -0,0 +1,140 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package rsakey converts the RSA key protos shared by the RSA-PSS and RSA-OAEP key managers. Both reuse Tink's
+// RSA-SSA-PKCS1 key protos since the key material is the same, the algorithm being set by the key type URL.
+package rsakey
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	rsapb "github.com/google/tink/go/proto/rsa_ssa_pkcs1_go_proto"
+)
+
+const (
+	// Version is the version of the RSA key protos.
+	Version = 0
+	// MinModulusSizeInBits is the smallest RSA modulus accepted.
+	MinModulusSizeInBits = 2048
+	publicExponent       = 65537
+)
+
+var errInvalidKey = errors.New("invalid RSA key")
+
+// Hash returns the hash function of h. Only SHA-2 hash functions are supported.
+func Hash(h commonpb.HashType) (crypto.Hash, error) {
+	switch h { //nolint:exhaustive
+	case commonpb.HashType_SHA256:
+		return crypto.SHA256, nil
+	case commonpb.HashType_SHA384:
+		return crypto.SHA384, nil
+	case commonpb.HashType_SHA512:
+		return crypto.SHA512, nil
+	default:
+		return 0, fmt.Errorf("unsupported RSA hash type: %s", h)
+	}
+}
+
+// New generates an RSA private key proto of the given format.
+func New(format *rsapb.RsaSsaPkcs1KeyFormat) (*rsapb.RsaSsaPkcs1PrivateKey, error) {
+	if _, err := Hash(format.GetParams().GetHashType()); err != nil {
+		return nil, err
+	}
+
+	if format.ModulusSizeInBits < MinModulusSizeInBits {
+		return nil, fmt.Errorf("RSA modulus size %d is smaller than %d bits", format.ModulusSizeInBits,
+			MinModulusSizeInBits)
+	}
+
+	if new(big.Int).SetBytes(format.PublicExponent).Int64() != publicExponent {
+		return nil, errors.New("RSA public exponent must be 65537")
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, int(format.ModulusSizeInBits))
+	if err != nil {
+		return nil, fmt.Errorf("generate RSA key: %w", err)
+	}
+
+	return FromPrivateKey(key, format.Params.HashType), nil
+}
+
+// FromPrivateKey returns the proto of key, used with the hash function h.
+func FromPrivateKey(key *rsa.PrivateKey, h commonpb.HashType) *rsapb.RsaSsaPkcs1PrivateKey {
+	key.Precompute()
+
+	return &rsapb.RsaSsaPkcs1PrivateKey{
+		Version:   Version,
+		PublicKey: FromPublicKey(&key.PublicKey, h),
+		D:         key.D.Bytes(),
+		P:         key.Primes[0].Bytes(),
+		Q:         key.Primes[1].Bytes(),
+		Dp:        key.Precomputed.Dp.Bytes(),
+		Dq:        key.Precomputed.Dq.Bytes(),
+		Crt:       key.Precomputed.Qinv.Bytes(),
+	}
+}
+
+// FromPublicKey returns the proto of key, used with the hash function h.
+func FromPublicKey(key *rsa.PublicKey, h commonpb.HashType) *rsapb.RsaSsaPkcs1PublicKey {
+	return &rsapb.RsaSsaPkcs1PublicKey{
+		Version: Version,
+		Params:  &rsapb.RsaSsaPkcs1Params{HashType: h},
+		N:       key.N.Bytes(),
+		E:       big.NewInt(int64(key.E)).Bytes(),
+	}
+}
+
+// PrivateKey returns the RSA private key of a proto and its hash function.
+func PrivateKey(key *rsapb.RsaSsaPkcs1PrivateKey) (*rsa.PrivateKey, crypto.Hash, error) {
+	if key.Version != Version || len(key.D) == 0 || len(key.P) == 0 || len(key.Q) == 0 {
+		return nil, 0, errInvalidKey
+	}
+
+	pub, h, err := PublicKey(key.PublicKey)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	priv := &rsa.PrivateKey{
+		PublicKey: *pub,
+		D:         new(big.Int).SetBytes(key.D),
+		Primes:    []*big.Int{new(big.Int).SetBytes(key.P), new(big.Int).SetBytes(key.Q)},
+	}
+
+	if err = priv.Validate(); err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", errInvalidKey, err)
+	}
+
+	priv.Precompute()
+
+	return priv, h, nil
+}
+
+// PublicKey returns the RSA public key of a proto and its hash function.
+func PublicKey(key *rsapb.RsaSsaPkcs1PublicKey) (*rsa.PublicKey, crypto.Hash, error) {
+	if key == nil || key.Version != Version || len(key.N) == 0 || len(key.E) == 0 {
+		return nil, 0, errInvalidKey
+	}
+
+	h, err := Hash(key.GetParams().GetHashType())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	pub := &rsa.PublicKey{N: new(big.Int).SetBytes(key.N), E: int(new(big.Int).SetBytes(key.E).Int64())}
+
+	if pub.N.BitLen() < MinModulusSizeInBits || pub.E != publicExponent {
+		return nil, 0, errInvalidKey
+	}
+
+	return pub, h, nil
+}
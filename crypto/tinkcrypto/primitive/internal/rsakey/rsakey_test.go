@@ -0,0 +1,76 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rsakey
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	rsapb "github.com/google/tink/go/proto/rsa_ssa_pkcs1_go_proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHash(t *testing.T) {
+	h, err := Hash(commonpb.HashType_SHA256)
+	require.NoError(t, err)
+	require.Equal(t, crypto.SHA256, h)
+
+	_, err = Hash(commonpb.HashType_SHA1)
+	require.ErrorContains(t, err, "unsupported RSA hash type")
+}
+
+func TestPrivateKeyRoundTrip(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, MinModulusSizeInBits)
+	require.NoError(t, err)
+
+	pb := FromPrivateKey(key, commonpb.HashType_SHA256)
+
+	priv, h, err := PrivateKey(pb)
+	require.NoError(t, err)
+	require.Equal(t, crypto.SHA256, h)
+	require.True(t, key.Equal(priv))
+
+	pub, _, err := PublicKey(FromPublicKey(&key.PublicKey, commonpb.HashType_SHA256))
+	require.NoError(t, err)
+	require.True(t, key.PublicKey.Equal(pub))
+}
+
+func TestKeyErrors(t *testing.T) {
+	params := &rsapb.RsaSsaPkcs1Params{HashType: commonpb.HashType_SHA256}
+
+	_, err := New(&rsapb.RsaSsaPkcs1KeyFormat{Params: params, ModulusSizeInBits: 1024, PublicExponent: []byte{1, 0, 1}})
+	require.ErrorContains(t, err, "smaller than 2048 bits")
+
+	_, err = New(&rsapb.RsaSsaPkcs1KeyFormat{Params: params, ModulusSizeInBits: 2048, PublicExponent: []byte{3}})
+	require.ErrorContains(t, err, "public exponent")
+
+	_, _, err = PublicKey(nil)
+	require.ErrorIs(t, err, errInvalidKey)
+
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	_, _, err = PublicKey(FromPublicKey(&small.PublicKey, commonpb.HashType_SHA256))
+	require.ErrorIs(t, err, errInvalidKey)
+
+	key, err := rsa.GenerateKey(rand.Reader, MinModulusSizeInBits)
+	require.NoError(t, err)
+
+	pb := FromPrivateKey(key, commonpb.HashType_SHA256)
+	pb.D = []byte{1}
+
+	_, _, err = PrivateKey(pb)
+	require.ErrorIs(t, err, errInvalidKey)
+
+	pb.D = nil
+
+	_, _, err = PrivateKey(pb)
+	require.ErrorIs(t, err, errInvalidKey)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package rsaoaep provides Tink key managers of RSA-OAEP
+// (RSAES-OAEP, https://www.rfc-editor.org/rfc/rfc8017#section-7.1) HybridEncrypt and HybridDecrypt primitives, used to
+// wrap content encryption keys as the RSA-OAEP-256 JWE algorithm does. The hybrid context info is the OAEP label.
+package rsaoaep
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+
+	"github.com/google/tink/go/core/registry"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	rsapb "github.com/google/tink/go/proto/rsa_ssa_pkcs1_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/tink"
+	"google.golang.org/protobuf/proto"
+
+	// register the SHA-2 hash functions.
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/internal/rsakey"
+)
+
+const (
+	// DecrypterTypeURL is the type URL of RSA-OAEP private keys.
+	DecrypterTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.RsaOaepPrivateKey"
+	// EncrypterTypeURL is the type URL of RSA-OAEP public keys.
+	EncrypterTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.RsaOaepPublicKey"
+
+	modulusSize2048 = 2048
+)
+
+// nolint:gochecknoinits
+func init() {
+	if err := registry.RegisterKeyManager(&rsakey.PrivateKeyManager{
+		PrivateTypeURL: DecrypterTypeURL,
+		PublicTypeURL:  EncrypterTypeURL,
+		Name:           "rsaoaep_decrypter_key_manager",
+		NewPrimitive: func(key *rsa.PrivateKey, h crypto.Hash) interface{} {
+			return &decrypter{key: key, hash: h}
+		},
+	}); err != nil {
+		panic(fmt.Sprintf("rsaoaep.init() failed: %v", err))
+	}
+
+	if err := registry.RegisterKeyManager(&rsakey.PublicKeyManager{
+		PublicTypeURL: EncrypterTypeURL,
+		Name:          "rsaoaep_encrypter_key_manager",
+		NewPrimitive: func(key *rsa.PublicKey, h crypto.Hash) interface{} {
+			return &encrypter{key: key, hash: h}
+		},
+	}); err != nil {
+		panic(fmt.Sprintf("rsaoaep.init() failed: %v", err))
+	}
+}
+
+// RSAOAEP256KeyTemplate is a KeyTemplate that generates a new RSA-OAEP private key with a 2048-bit modulus, the 65537
+// public exponent and SHA-256 as hash and MGF1 function. Ciphertexts are RAW (no Tink prefix).
+func RSAOAEP256KeyTemplate() *tinkpb.KeyTemplate {
+	format := &rsapb.RsaSsaPkcs1KeyFormat{
+		Params:            &rsapb.RsaSsaPkcs1Params{HashType: commonpb.HashType_SHA256},
+		ModulusSizeInBits: modulusSize2048,
+		PublicExponent:    []byte{0x01, 0x00, 0x01},
+	}
+	serializedFormat, _ := proto.Marshal(format) //nolint:errcheck
+
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          DecrypterTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+	}
+}
+
+// Encrypt encrypts plaintext for the RSA public key pub with OAEP and the hash function h.
+func Encrypt(pub *rsa.PublicKey, h crypto.Hash, plaintext, label []byte) ([]byte, error) {
+	ct, err := rsa.EncryptOAEP(h.New(), rand.Reader, pub, plaintext, label)
+	if err != nil {
+		return nil, fmt.Errorf("rsaoaep: encrypt: %w", err)
+	}
+
+	return ct, nil
+}
+
+type encrypter struct {
+	key  *rsa.PublicKey
+	hash crypto.Hash
+}
+
+// Encrypt encrypts plaintext with contextInfo as OAEP label.
+func (e *encrypter) Encrypt(plaintext, contextInfo []byte) ([]byte, error) {
+	return Encrypt(e.key, e.hash, plaintext, contextInfo)
+}
+
+type decrypter struct {
+	key  *rsa.PrivateKey
+	hash crypto.Hash
+}
+
+// Decrypt decrypts ciphertext with contextInfo as OAEP label.
+func (d *decrypter) Decrypt(ciphertext, contextInfo []byte) ([]byte, error) {
+	pt, err := rsa.DecryptOAEP(d.hash.New(), rand.Reader, d.key, ciphertext, contextInfo)
+	if err != nil {
+		return nil, fmt.Errorf("rsaoaep: decrypt: %w", err)
+	}
+
+	return pt, nil
+}
+
+var (
+	_ tink.HybridEncrypt = (*encrypter)(nil)
+	_ tink.HybridDecrypt = (*decrypter)(nil)
+)
@@ -0,0 +1,81 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rsaoaep
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"math/big"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/hybrid"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	rsapb "github.com/google/tink/go/proto/rsa_ssa_pkcs1_go_proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSAOAEP256EncryptDecrypt(t *testing.T) {
+	kh, err := keyset.NewHandle(RSAOAEP256KeyTemplate())
+	require.NoError(t, err)
+
+	pubKH, err := kh.Public()
+	require.NoError(t, err)
+	require.Equal(t, EncrypterTypeURL, pubKH.KeysetInfo().KeyInfo[0].TypeUrl)
+
+	enc, err := hybrid.NewHybridEncrypt(pubKH)
+	require.NoError(t, err)
+
+	dec, err := hybrid.NewHybridDecrypt(kh)
+	require.NoError(t, err)
+
+	cek := []byte("0123456789abcdef0123456789abcdef")
+	label := []byte("label")
+
+	ct, err := enc.Encrypt(cek, label)
+	require.NoError(t, err)
+
+	pt, err := dec.Decrypt(ct, label)
+	require.NoError(t, err)
+	require.Equal(t, cek, pt)
+
+	_, err = dec.Decrypt(ct, []byte("other label"))
+	require.Error(t, err)
+
+	// ciphertexts of the exported Encrypt function decrypt with the key handle.
+	ks := insecurecleartextkeyset.KeysetMaterial(kh)
+	key := new(rsapb.RsaSsaPkcs1PrivateKey)
+	require.NoError(t, proto.Unmarshal(ks.Key[0].KeyData.Value, key))
+
+	pub := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(key.PublicKey.N),
+		E: int(new(big.Int).SetBytes(key.PublicKey.E).Int64()),
+	}
+
+	ct, err = Encrypt(pub, crypto.SHA256, cek, nil)
+	require.NoError(t, err)
+
+	pt, err = dec.Decrypt(ct, nil)
+	require.NoError(t, err)
+	require.Equal(t, cek, pt)
+
+	_, err = Encrypt(pub, crypto.SHA256, make([]byte, 256), nil)
+	require.ErrorContains(t, err, "rsaoaep: encrypt")
+}
+
+func TestRSAOAEP256KeyManagerErrors(t *testing.T) {
+	kh, err := keyset.NewHandle(RSAOAEP256KeyTemplate())
+	require.NoError(t, err)
+
+	pubKH, err := kh.Public()
+	require.NoError(t, err)
+
+	// a public key can't decrypt.
+	_, err = hybrid.NewHybridDecrypt(pubKH)
+	require.Error(t, err)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package rsapss provides Tink key managers of RSA-PSS (RSASSA-PSS, https://www.rfc-editor.org/rfc/rfc8017#section-8.1)
+// Signer and Verifier primitives, with a salt as long as the digest as required by the PS* JWS algorithms.
+//
+// To sign data using Tink you can use the RSA-PSS key templates.
+package rsapss
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+
+	"github.com/google/tink/go/core/registry"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	rsapb "github.com/google/tink/go/proto/rsa_ssa_pkcs1_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/tink"
+	"google.golang.org/protobuf/proto"
+
+	// register the SHA-2 hash functions.
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/internal/rsakey"
+)
+
+const (
+	// SignerTypeURL is the type URL of RSA-PSS private keys.
+	SignerTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.RsaSsaPssPrivateKey"
+	// VerifierTypeURL is the type URL of RSA-PSS public keys.
+	VerifierTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.RsaSsaPssPublicKey"
+
+	modulusSize2048 = 2048
+)
+
+// nolint:gochecknoinits
+func init() {
+	if err := registry.RegisterKeyManager(&rsakey.PrivateKeyManager{
+		PrivateTypeURL: SignerTypeURL,
+		PublicTypeURL:  VerifierTypeURL,
+		Name:           "rsapss_signer_key_manager",
+		NewPrimitive: func(key *rsa.PrivateKey, h crypto.Hash) interface{} {
+			return &signer{key: key, hash: h}
+		},
+	}); err != nil {
+		panic(fmt.Sprintf("rsapss.init() failed: %v", err))
+	}
+
+	if err := registry.RegisterKeyManager(&rsakey.PublicKeyManager{
+		PublicTypeURL: VerifierTypeURL,
+		Name:          "rsapss_verifier_key_manager",
+		NewPrimitive: func(key *rsa.PublicKey, h crypto.Hash) interface{} {
+			return &verifier{key: key, hash: h}
+		},
+	}); err != nil {
+		panic(fmt.Sprintf("rsapss.init() failed: %v", err))
+	}
+}
+
+// PS256KeyTemplate is a KeyTemplate that generates a new RSA-PSS private key with a 2048-bit modulus, the 65537
+// public exponent and SHA-256 as hash and MGF1 function. Signatures are RAW (no Tink prefix).
+func PS256KeyTemplate() *tinkpb.KeyTemplate {
+	format := &rsapb.RsaSsaPkcs1KeyFormat{
+		Params:            &rsapb.RsaSsaPkcs1Params{HashType: commonpb.HashType_SHA256},
+		ModulusSizeInBits: modulusSize2048,
+		PublicExponent:    []byte{0x01, 0x00, 0x01},
+	}
+	serializedFormat, _ := proto.Marshal(format) //nolint:errcheck
+
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          SignerTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+	}
+}
+
+type signer struct {
+	key  *rsa.PrivateKey
+	hash crypto.Hash
+}
+
+// Sign signs the digest of data.
+func (s *signer) Sign(data []byte) ([]byte, error) {
+	h := s.hash.New()
+	h.Write(data)
+
+	sig, err := rsa.SignPSS(rand.Reader, s.key, s.hash, h.Sum(nil),
+		&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	if err != nil {
+		return nil, fmt.Errorf("rsapss: sign: %w", err)
+	}
+
+	return sig, nil
+}
+
+type verifier struct {
+	key  *rsa.PublicKey
+	hash crypto.Hash
+}
+
+// Verify verifies the signature of data.
+func (v *verifier) Verify(signature, data []byte) error {
+	h := v.hash.New()
+	h.Write(data)
+
+	if err := rsa.VerifyPSS(v.key, v.hash, h.Sum(nil), signature,
+		&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}); err != nil {
+		return fmt.Errorf("rsapss: verify: %w", err)
+	}
+
+	return nil
+}
+
+var (
+	_ tink.Signer   = (*signer)(nil)
+	_ tink.Verifier = (*verifier)(nil)
+)
@@ -0,0 +1,98 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rsapss
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	rsapb "github.com/google/tink/go/proto/rsa_ssa_pkcs1_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/signature"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPS256SignVerify(t *testing.T) {
+	kh, err := keyset.NewHandle(PS256KeyTemplate())
+	require.NoError(t, err)
+
+	signer, err := signature.NewSigner(kh)
+	require.NoError(t, err)
+
+	msg := []byte("message")
+
+	sig, err := signer.Sign(msg)
+	require.NoError(t, err)
+	require.Len(t, sig, 256)
+
+	pubKH, err := kh.Public()
+	require.NoError(t, err)
+	require.Equal(t, VerifierTypeURL, pubKH.KeysetInfo().KeyInfo[0].TypeUrl)
+
+	verifier, err := signature.NewVerifier(pubKH)
+	require.NoError(t, err)
+
+	require.NoError(t, verifier.Verify(sig, msg))
+	require.Error(t, verifier.Verify(sig, []byte("other")))
+
+	// signatures are RSASSA-PSS with SHA-256 and a salt as long as the digest.
+	ks := insecurecleartextkeyset.KeysetMaterial(kh)
+	key := new(rsapb.RsaSsaPkcs1PrivateKey)
+	require.NoError(t, proto.Unmarshal(ks.Key[0].KeyData.Value, key))
+
+	pub := &rsa.PublicKey{N: bigInt(key.PublicKey.N), E: int(bigInt(key.PublicKey.E).Int64())}
+	digest := sha256.Sum256(msg)
+
+	require.NoError(t, rsa.VerifyPSS(pub, crypto.SHA256, digest[:], sig, &rsa.PSSOptions{SaltLength: 32}))
+}
+
+func TestPS256KeyManagerErrors(t *testing.T) {
+	format := &rsapb.RsaSsaPkcs1KeyFormat{
+		Params:            &rsapb.RsaSsaPkcs1Params{HashType: 3},
+		ModulusSizeInBits: 1024,
+		PublicExponent:    []byte{0x01, 0x00, 0x01},
+	}
+
+	for _, f := range []*rsapb.RsaSsaPkcs1KeyFormat{
+		format,
+		{Params: format.Params, ModulusSizeInBits: 2048, PublicExponent: []byte{0x03}},
+		{Params: &rsapb.RsaSsaPkcs1Params{HashType: 1}, ModulusSizeInBits: 2048, PublicExponent: format.PublicExponent},
+	} {
+		serialized, err := proto.Marshal(f)
+		require.NoError(t, err)
+
+		_, err = keyset.NewHandle(&tinkpb.KeyTemplate{
+			TypeUrl:          SignerTypeURL,
+			Value:            serialized,
+			OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+		})
+		require.Error(t, err)
+	}
+
+	kh, err := keyset.NewHandle(PS256KeyTemplate())
+	require.NoError(t, err)
+
+	pubKH, err := kh.Public()
+	require.NoError(t, err)
+
+	// a public key can't sign.
+	_, err = signature.NewSigner(pubKH)
+	require.Error(t, err)
+
+	_, err = keyset.NewHandle(&tinkpb.KeyTemplate{TypeUrl: VerifierTypeURL, OutputPrefixType: tinkpb.OutputPrefixType_RAW})
+	require.ErrorContains(t, err, "not supported")
+}
+
+func bigInt(b []byte) *big.Int {
+	return new(big.Int).SetBytes(b)
+}
@@ -0,0 +1,235 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"fmt"
+	"strings"
+
+	hybrid "github.com/google/tink/go/hybrid/subtle"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
+	rsapb "github.com/google/tink/go/proto/rsa_ssa_pkcs1_go_proto"
+	"google.golang.org/protobuf/proto"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/util/profiles"
+)
+
+const (
+	tinkTypeURL  = "type.googleapis.com/google.crypto.tink."
+	ariesTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink."
+)
+
+//nolint:gochecknoglobals
+var (
+	// signatureAlgorithms are the JWS algorithms of the signing keys by Tink key type URL, except the ECDSA and RSA
+	// PKCS#1 keys whose algorithm depends on their parameters.
+	signatureAlgorithms = map[string]string{
+		tinkTypeURL + "Ed25519PrivateKey":    "EdDSA",
+		tinkTypeURL + "Ed25519PublicKey":     "EdDSA",
+		tinkTypeURL + "secp256k1PrivateKey":  "ES256K",
+		tinkTypeURL + "secp256k1PublicKey":   "ES256K",
+		ariesTypeURL + "RsaSsaPssPrivateKey": "PS256",
+		ariesTypeURL + "RsaSsaPssPublicKey":  "PS256",
+	}
+
+	ecdsaCurveAlgorithms = map[commonpb.EllipticCurveType]string{
+		commonpb.EllipticCurveType_NIST_P256: "ES256",
+		commonpb.EllipticCurveType_NIST_P384: "ES384",
+		commonpb.EllipticCurveType_NIST_P521: "ES512",
+	}
+
+	ecdsaCurveNameAlgorithms = map[string]string{"P-256": "ES256", "P-384": "ES384", "P-521": "ES512"}
+
+	rsaHashAlgorithms = map[commonpb.HashType]string{
+		commonpb.HashType_SHA256: "RS256",
+		commonpb.HashType_SHA384: "RS384",
+		commonpb.HashType_SHA512: "RS512",
+	}
+)
+
+// WithProfile restricts Crypto to the crypto suite profile p: using keys of other key types, wrapping or unwrapping
+// keys with other algorithms or curves, and signing or verifying with other signature algorithms fail with
+// profiles.ErrNotAllowed. Keys wrapped with an algorithm outside of p are discarded. BBS+ and BIP340 keys have no JWS
+// algorithm, they are only restricted by their key type.
+func WithProfile(p *profiles.Profile) Opt {
+	return func(c *Crypto) {
+		c.profile = p
+	}
+}
+
+// checkHandle returns fips.ErrNotApproved or profiles.ErrNotAllowed if kh holds a key of an algorithm that is not
+// approved in FIPS mode or not allowed by the profile of t.
+func (t *Crypto) checkHandle(kh *keyset.Handle) error {
+	if err := checkFIPSHandle(kh); err != nil {
+		return err
+	}
+
+	if t.profile == nil {
+		return nil
+	}
+
+	for _, ki := range kh.KeysetInfo().GetKeyInfo() {
+		if err := t.profile.CheckTypeURL(ki.GetTypeUrl()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkSigningHandle returns the errors of checkHandle, or profiles.ErrNotAllowed if the JWS algorithm of the
+// primary key of kh is not allowed by the profile of t.
+func (t *Crypto) checkSigningHandle(kh *keyset.Handle) error {
+	if err := t.checkHandle(kh); err != nil {
+		return err
+	}
+
+	if t.profile == nil {
+		return nil
+	}
+
+	alg, err := signatureAlgorithm(kh)
+	if err != nil || alg == "" {
+		return err
+	}
+
+	return t.profile.CheckSignatureAlgorithm(alg)
+}
+
+// checkProfileWrappedKey returns profiles.ErrNotAllowed if the algorithm or the ephemeral key curve of wk are not
+// allowed by the profile of t.
+func (t *Crypto) checkProfileWrappedKey(wk *cryptoapi.RecipientWrappedKey) error {
+	if t.profile == nil {
+		return nil
+	}
+
+	if err := t.profile.CheckKeyWrapAlgorithm(wk.Alg); err != nil {
+		return err
+	}
+
+	if wk.EPK.Curve != "" {
+		return t.profile.CheckCurve(wk.EPK.Curve)
+	}
+
+	return nil
+}
+
+// checkProfileUnwrap returns profiles.ErrNotAllowed if the key unwrapping of recWK with recipientKH uses an algorithm,
+// a curve or a key that is not allowed by the profile of t.
+func (t *Crypto) checkProfileUnwrap(recWK *cryptoapi.RecipientWrappedKey, recipientKH interface{}) error {
+	if t.profile == nil {
+		return nil
+	}
+
+	if err := t.checkProfileWrappedKey(recWK); err != nil {
+		return err
+	}
+
+	if kh, ok := recipientKH.(*keyset.Handle); ok {
+		return t.checkHandle(kh)
+	}
+
+	return nil
+}
+
+// checkProfilePublicKey returns profiles.ErrNotAllowed if the JWS algorithm of the Ed25519 or NIST P curves ECDSA
+// pubKey is not allowed by the profile of t.
+func (t *Crypto) checkProfilePublicKey(pubKey *cryptoapi.PublicKey) error {
+	if t.profile == nil {
+		return nil
+	}
+
+	if pubKey.Type == okpKeyType && strings.EqualFold(pubKey.Curve, ed25519Crv) {
+		return t.profile.CheckSignatureAlgorithm("EdDSA")
+	}
+
+	if pubKey.Type != ecKeyType || strings.EqualFold(pubKey.Curve, bls12381G2Crv) {
+		return nil
+	}
+
+	// unsupported curves are rejected by the verification.
+	if curve, err := hybrid.GetCurve(pubKey.Curve); err == nil {
+		return t.profile.CheckSignatureAlgorithm(ecdsaCurveNameAlgorithms[curve.Params().Name])
+	}
+
+	return nil
+}
+
+// signatureAlgorithm returns the JWS algorithm of the primary key of the signing or verification key handle kh, or
+// an empty algorithm for the keys without JWS algorithm.
+func signatureAlgorithm(kh *keyset.Handle) (string, error) {
+	ks := insecurecleartextkeyset.KeysetMaterial(kh)
+
+	for _, k := range ks.GetKey() {
+		if k.GetKeyId() != ks.GetPrimaryKeyId() {
+			continue
+		}
+
+		switch typeURL := k.GetKeyData().GetTypeUrl(); typeURL {
+		case tinkTypeURL + "EcdsaPrivateKey", tinkTypeURL + "EcdsaPublicKey":
+			return ecdsaAlgorithm(typeURL, k.GetKeyData().GetValue())
+		case tinkTypeURL + "RsaSsaPkcs1PrivateKey", tinkTypeURL + "RsaSsaPkcs1PublicKey":
+			return rsaAlgorithm(typeURL, k.GetKeyData().GetValue())
+		default:
+			return signatureAlgorithms[typeURL], nil
+		}
+	}
+
+	return "", nil
+}
+
+func ecdsaAlgorithm(typeURL string, value []byte) (string, error) {
+	var params *ecdsapb.EcdsaParams
+
+	if strings.HasSuffix(typeURL, "PrivateKey") {
+		key := &ecdsapb.EcdsaPrivateKey{}
+
+		if err := proto.Unmarshal(value, key); err != nil {
+			return "", fmt.Errorf("invalid ECDSA key: %w", err)
+		}
+
+		params = key.GetPublicKey().GetParams()
+	} else {
+		key := &ecdsapb.EcdsaPublicKey{}
+
+		if err := proto.Unmarshal(value, key); err != nil {
+			return "", fmt.Errorf("invalid ECDSA key: %w", err)
+		}
+
+		params = key.GetParams()
+	}
+
+	return ecdsaCurveAlgorithms[params.GetCurve()], nil
+}
+
+func rsaAlgorithm(typeURL string, value []byte) (string, error) {
+	var params *rsapb.RsaSsaPkcs1Params
+
+	if strings.HasSuffix(typeURL, "PrivateKey") {
+		key := &rsapb.RsaSsaPkcs1PrivateKey{}
+
+		if err := proto.Unmarshal(value, key); err != nil {
+			return "", fmt.Errorf("invalid RSA key: %w", err)
+		}
+
+		params = key.GetPublicKey().GetParams()
+	} else {
+		key := &rsapb.RsaSsaPkcs1PublicKey{}
+
+		if err := proto.Unmarshal(value, key); err != nil {
+			return "", fmt.Errorf("invalid RSA key: %w", err)
+		}
+
+		params = key.GetParams()
+	}
+
+	return rsaHashAlgorithms[params.GetHashType()], nil
+}
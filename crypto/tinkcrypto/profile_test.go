@@ -0,0 +1,140 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aeskw"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/composite/ecdh"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/composite/keyio"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/util/profiles"
+)
+
+func TestCrypto_WithProfile(t *testing.T) {
+	didcomm, err := profiles.Get(profiles.DIDCommV2)
+	require.NoError(t, err)
+
+	c, err := New(WithProfile(didcomm))
+	require.NoError(t, err)
+
+	unrestricted, err := New()
+	require.NoError(t, err)
+
+	msg := []byte(testMessage)
+
+	t.Run("sign and verify", func(t *testing.T) {
+		for _, test := range []struct {
+			template *tinkpb.KeyTemplate
+			err      error
+		}{
+			{template: signature.ED25519KeyTemplate()},
+			{template: signature.ECDSAP256KeyTemplate()},
+			{template: signature.ECDSAP384KeyTemplate(), err: profiles.ErrNotAllowed},
+			{template: signature.RSA_SSA_PKCS1_3072_SHA256_F4_Key_Template(), err: profiles.ErrNotAllowed},
+		} {
+			kh, e := keyset.NewHandle(test.template)
+			require.NoError(t, e)
+
+			_, e = c.Sign(msg, kh)
+			require.ErrorIs(t, e, test.err)
+
+			sig, e := unrestricted.Sign(msg, kh)
+			require.NoError(t, e)
+
+			pubKH, e := kh.Public()
+			require.NoError(t, e)
+			require.ErrorIs(t, c.Verify(sig, msg, pubKH), test.err)
+		}
+	})
+
+	t.Run("verify with public key", func(t *testing.T) {
+		pub, priv, e := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, e)
+
+		pubKey := &cryptoapi.PublicKey{Type: okpKeyType, Curve: ed25519Crv, X: pub}
+		require.NoError(t, c.VerifyWithPublicKey(ed25519.Sign(priv, msg), msg, pubKey))
+
+		fips, e := profiles.Get(profiles.FIPS)
+		require.NoError(t, e)
+
+		fipsCrypto, e := New(WithProfile(fips))
+		require.NoError(t, e)
+
+		e = fipsCrypto.VerifyWithPublicKey(ed25519.Sign(priv, msg), msg, pubKey)
+		require.ErrorIs(t, e, profiles.ErrNotAllowed)
+
+		e = c.VerifyWithPublicKey(nil, msg, &cryptoapi.PublicKey{Type: ecKeyType, Curve: "P-384"})
+		require.EqualError(t, e, "verify with public key: profiles: not allowed by the profile: profile "+
+			"'didcomm-v2': signature algorithm 'ES384'")
+	})
+
+	t.Run("symmetric keys", func(t *testing.T) {
+		kh, e := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+		require.NoError(t, e)
+
+		_, _, e = c.Encrypt(msg, nil, kh)
+		require.ErrorIs(t, e, profiles.ErrNotAllowed)
+	})
+
+	t.Run("wrap and unwrap", func(t *testing.T) {
+		cek := random.GetRandomBytes(32)
+
+		for _, test := range []struct {
+			template *tinkpb.KeyTemplate
+			opts     []cryptoapi.WrapKeyOpts
+			err      error
+		}{
+			{template: ecdh.X25519ECDHKWKeyTemplate(), opts: []cryptoapi.WrapKeyOpts{cryptoapi.WithXC20PKW()}},
+			{template: ecdh.NISTP256ECDHKWKeyTemplate()},
+			{template: ecdh.X448ECDHKWKeyTemplate(), err: profiles.ErrNotAllowed},
+		} {
+			kh, e := keyset.NewHandle(test.template)
+			require.NoError(t, e)
+
+			recPubKey, e := keyio.ExtractPrimaryPublicKey(kh)
+			require.NoError(t, e)
+
+			wk, e := c.WrapKey(cek, nil, nil, recPubKey, test.opts...)
+			require.ErrorIs(t, e, test.err)
+
+			if test.err != nil {
+				require.Nil(t, wk)
+
+				continue
+			}
+
+			unwrapped, e := c.UnwrapKey(wk, kh, test.opts...)
+			require.NoError(t, e)
+			require.Equal(t, cek, unwrapped)
+
+			wk.Alg = A256KWAlg
+
+			_, e = c.UnwrapKey(wk, kh)
+			require.ErrorIs(t, e, profiles.ErrNotAllowed)
+			require.ErrorContains(t, e, "key wrapping algorithm 'A256KW'")
+		}
+
+		// AES-KW key encryption keys are not in the profile.
+		kek, e := keyset.NewHandle(aeskw.A256KWKeyTemplate())
+		require.NoError(t, e)
+
+		_, e = c.WrapKeyWithKEK(cek, kek)
+		require.ErrorIs(t, e, profiles.ErrNotAllowed)
+		require.ErrorContains(t, e, "wrapKeyWithKEK: ")
+	})
+}
@@ -0,0 +1,72 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/google/tink/go/hybrid"
+	"github.com/google/tink/go/keyset"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/rsaoaep"
+)
+
+const (
+	// RSAOAEP256Alg is the RSA-OAEP (SHA-256 and MGF1 with SHA-256) key wrapping algorithm.
+	RSAOAEP256Alg = "RSA-OAEP-256"
+
+	rsaKeyType = "RSA"
+)
+
+// wrapRSAOAEP wraps cek for an RSA recipient public key. It needs no ephemeral key, apu or apv.
+func wrapRSAOAEP(cek []byte, recPubKey *cryptoapi.PublicKey) (*cryptoapi.RecipientWrappedKey, error) {
+	if len(recPubKey.N) == 0 || len(recPubKey.E) == 0 {
+		return nil, errors.New("wrapRSAOAEP: invalid RSA public key")
+	}
+
+	pub := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(recPubKey.N),
+		E: int(new(big.Int).SetBytes(recPubKey.E).Int64()),
+	}
+
+	wk, err := rsaoaep.Encrypt(pub, crypto.SHA256, cek, nil)
+	if err != nil {
+		return nil, fmt.Errorf("wrapRSAOAEP: %w", err)
+	}
+
+	return &cryptoapi.RecipientWrappedKey{
+		KID:          recPubKey.KID,
+		EncryptedCEK: wk,
+		Alg:          RSAOAEP256Alg,
+	}, nil
+}
+
+// unwrapRSAOAEP unwraps encCEK with the RSA-OAEP private key of recKH.
+func unwrapRSAOAEP(encCEK []byte, recKH interface{}) ([]byte, error) {
+	kh, ok := recKH.(*keyset.Handle)
+	if !ok {
+		return nil, fmt.Errorf("unwrapRSAOAEP: %w", errBadKeyHandleFormat)
+	}
+
+	d, err := hybrid.NewHybridDecrypt(kh)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapRSAOAEP: %w", err)
+	}
+
+	cek, err := d.Decrypt(encCEK, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapRSAOAEP: %w", err)
+	}
+
+	return cek, nil
+}
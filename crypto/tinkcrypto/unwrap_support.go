@@ -76,6 +76,15 @@ func extractPrivKey(kh *keyset.Handle) (interface{}, error) {
 		}
 
 		return pbKey.KeyValue, nil
+	case x448ECDHKWPrivateKeyTypeURL:
+		pbKey := new(ecdhpb.EcdhAeadPrivateKey)
+
+		err = proto.Unmarshal(primaryKey.KeyData.Value, pbKey)
+		if err != nil {
+			return nil, errors.New("extractPrivKey: invalid key in keyset")
+		}
+
+		return x448PrivKey(pbKey.KeyValue), nil
 	}
 
 	return nil, fmt.Errorf("extractPrivKey: can't extract unsupported private key '%s'", primaryKey.KeyData.TypeUrl)
@@ -0,0 +1,179 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	hybrid "github.com/google/tink/go/hybrid/subtle"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/util/fips"
+
+	bbssubtle "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/bbs/subtle"
+	"github.com/trustbloc/kms-go/kms/audit"
+)
+
+const (
+	ecKeyType     = "EC"
+	okpKeyType    = "OKP"
+	ed25519Crv    = "Ed25519"
+	bls12381G2Crv = "BLS12381_G2"
+)
+
+var _ cryptoapi.PublicKeyVerifier = (*Crypto)(nil)
+
+var errInvalidSignature = errors.New("invalid signature")
+
+// VerifyWithPublicKey verifies signature of msg with the raw public key pubKey, without importing it in the KMS:
+//   - an EC key on the NIST P-256, P-384 or P-521 curve verifies an ECDSA signature, in the IEEE P1363 or DER format,
+//     of msg hashed with SHA-256, SHA-384 or SHA-512 (the hash functions of the ECDSA key types of localkms).
+//   - an OKP Ed25519 key verifies an Ed25519 signature.
+//   - an EC BLS12381_G2 key, whose X is the BBS+ public key bytes, verifies the BBS+ signature of the single message
+//     msg. Signatures of several messages are verified with VerifyMultiWithPublicKey.
+func (t *Crypto) VerifyWithPublicKey(sig, msg []byte, pubKey *cryptoapi.PublicKey) error {
+	start := time.Now()
+	err := t.verifyWithPublicKey(sig, msg, pubKey)
+
+	audit.Log(t.auditLogger, kmsapi.OperationVerify, pubKeyKID(pubKey), nil, start, err)
+
+	return err
+}
+
+// VerifyMultiWithPublicKey verifies the BBS+ signature of messages with the raw EC BLS12381_G2 public key pubKey,
+// without importing it in the KMS.
+func (t *Crypto) VerifyMultiWithPublicKey(messages [][]byte, sig []byte, pubKey *cryptoapi.PublicKey) error {
+	start := time.Now()
+	err := verifyBBSWithPublicKey(messages, sig, pubKey)
+
+	audit.Log(t.auditLogger, kmsapi.OperationVerify, pubKeyKID(pubKey), nil, start, err)
+
+	return err
+}
+
+func (t *Crypto) verifyWithPublicKey(sig, msg []byte, pubKey *cryptoapi.PublicKey) error {
+	if pubKey == nil {
+		return errors.New("verify with public key: public key is nil")
+	}
+
+	if err := t.checkProfilePublicKey(pubKey); err != nil {
+		return fmt.Errorf("verify with public key: %w", err)
+	}
+
+	switch {
+	case pubKey.Type == okpKeyType && strings.EqualFold(pubKey.Curve, ed25519Crv):
+		if err := fips.CheckKeyType(kmsapi.ED25519Type); err != nil {
+			return err
+		}
+
+		if len(pubKey.X) != ed25519.PublicKeySize {
+			return errors.New("verify with public key: invalid Ed25519 public key")
+		}
+
+		if !ed25519.Verify(pubKey.X, msg, sig) {
+			return fmt.Errorf("verify with public key: %w", errInvalidSignature)
+		}
+
+		return nil
+	case pubKey.Type == ecKeyType && strings.EqualFold(pubKey.Curve, bls12381G2Crv):
+		return verifyBBSWithPublicKey([][]byte{msg}, sig, pubKey)
+	case pubKey.Type == ecKeyType:
+		return verifyECDSAWithPublicKey(sig, msg, pubKey)
+	default:
+		return fmt.Errorf("verify with public key: unsupported key type '%s' and curve '%s'", pubKey.Type,
+			pubKey.Curve)
+	}
+}
+
+func verifyECDSAWithPublicKey(sig, msg []byte, pubKey *cryptoapi.PublicKey) error {
+	if err := fips.CheckCurve(pubKey.Curve); err != nil {
+		return err
+	}
+
+	curve, err := hybrid.GetCurve(pubKey.Curve)
+	if err != nil {
+		return fmt.Errorf("verify with public key: %w", err)
+	}
+
+	hash, err := ecdsaHash(curve)
+	if err != nil {
+		return fmt.Errorf("verify with public key: %w", err)
+	}
+
+	pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(pubKey.X), Y: new(big.Int).SetBytes(pubKey.Y)}
+
+	if !curve.IsOnCurve(pub.X, pub.Y) {
+		return errors.New("verify with public key: invalid EC public key")
+	}
+
+	h := hash.New()
+	h.Write(msg)
+	digest := h.Sum(nil)
+
+	var valid bool
+
+	size := (curve.Params().BitSize + 7) / 8 //nolint:gomnd // bits to bytes
+
+	if len(sig) == 2*size {
+		valid = ecdsa.Verify(pub, digest, new(big.Int).SetBytes(sig[:size]), new(big.Int).SetBytes(sig[size:]))
+	} else {
+		valid = ecdsa.VerifyASN1(pub, digest, sig)
+	}
+
+	if !valid {
+		return fmt.Errorf("verify with public key: %w", errInvalidSignature)
+	}
+
+	return nil
+}
+
+// ecdsaHash returns the hash function of the ECDSA signatures on curve.
+func ecdsaHash(curve elliptic.Curve) (crypto.Hash, error) {
+	switch curve {
+	case elliptic.P256():
+		return crypto.SHA256, nil
+	case elliptic.P384():
+		return crypto.SHA384, nil
+	case elliptic.P521():
+		return crypto.SHA512, nil
+	default:
+		return 0, fmt.Errorf("unsupported EC curve '%s'", curve.Params().Name)
+	}
+}
+
+func verifyBBSWithPublicKey(messages [][]byte, sig []byte, pubKey *cryptoapi.PublicKey) error {
+	if pubKey == nil || pubKey.Type != ecKeyType || !strings.EqualFold(pubKey.Curve, bls12381G2Crv) {
+		return errors.New("BBS+ verify with public key: public key is not a BLS12381_G2 key")
+	}
+
+	if err := fips.CheckKeyType(kmsapi.BLS12381G2Type); err != nil {
+		return err
+	}
+
+	if err := bbssubtle.NewBLS12381G2Verifier(pubKey.X).Verify(messages, sig); err != nil {
+		return fmt.Errorf("BBS+ verify with public key: %w", err)
+	}
+
+	return nil
+}
+
+func pubKeyKID(pubKey *cryptoapi.PublicKey) string {
+	if pubKey == nil {
+		return ""
+	}
+
+	return pubKey.KID
+}
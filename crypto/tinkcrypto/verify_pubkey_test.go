@@ -0,0 +1,127 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+
+	bbssubtle "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/bbs/subtle"
+)
+
+func TestCrypto_VerifyWithPublicKey(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+
+	msg := []byte("message")
+
+	t.Run("ECDSA", func(t *testing.T) {
+		for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+			priv, err := ecdsa.GenerateKey(curve, rand.Reader)
+			require.NoError(t, err)
+
+			var digest []byte
+
+			switch curve {
+			case elliptic.P256():
+				d := sha256.Sum256(msg)
+				digest = d[:]
+			case elliptic.P384():
+				d := sha512.Sum384(msg)
+				digest = d[:]
+			default:
+				d := sha512.Sum512(msg)
+				digest = d[:]
+			}
+
+			pubKey := &cryptoapi.PublicKey{
+				Type:  "EC",
+				Curve: curve.Params().Name,
+				X:     priv.X.Bytes(),
+				Y:     priv.Y.Bytes(),
+			}
+
+			der, err := ecdsa.SignASN1(rand.Reader, priv, digest)
+			require.NoError(t, err)
+			require.NoError(t, c.VerifyWithPublicKey(der, msg, pubKey))
+
+			r, s, err := ecdsa.Sign(rand.Reader, priv, digest)
+			require.NoError(t, err)
+
+			size := (curve.Params().BitSize + 7) / 8
+			p1363 := make([]byte, 2*size)
+			r.FillBytes(p1363[:size])
+			s.FillBytes(p1363[size:])
+
+			require.NoError(t, c.VerifyWithPublicKey(p1363, msg, pubKey))
+			require.ErrorIs(t, c.VerifyWithPublicKey(p1363, []byte("other"), pubKey), errInvalidSignature)
+		}
+	})
+
+	t.Run("Ed25519", func(t *testing.T) {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		pubKey := &cryptoapi.PublicKey{Type: "OKP", Curve: "Ed25519", X: pub}
+		sig := ed25519.Sign(priv, msg)
+
+		require.NoError(t, c.VerifyWithPublicKey(sig, msg, pubKey))
+		require.ErrorIs(t, c.VerifyWithPublicKey(sig, []byte("other"), pubKey), errInvalidSignature)
+	})
+
+	t.Run("BBS+", func(t *testing.T) {
+		pub, priv, err := bbs12381g2pub.GenerateKeyPair(sha256.New, nil)
+		require.NoError(t, err)
+
+		pubBytes, err := pub.Marshal()
+		require.NoError(t, err)
+
+		privBytes, err := priv.Marshal()
+		require.NoError(t, err)
+
+		pubKey := &cryptoapi.PublicKey{Type: "EC", Curve: "BLS12381_G2", X: pubBytes}
+		messages := [][]byte{[]byte("first"), []byte("second")}
+
+		sig, err := bbssubtle.NewBLS12381G2Signer(privBytes).Sign(messages)
+		require.NoError(t, err)
+		require.NoError(t, c.VerifyMultiWithPublicKey(messages, sig, pubKey))
+		require.Error(t, c.VerifyMultiWithPublicKey(messages[:1], sig, pubKey))
+
+		sig, err = bbssubtle.NewBLS12381G2Signer(privBytes).Sign([][]byte{msg})
+		require.NoError(t, err)
+		require.NoError(t, c.VerifyWithPublicKey(sig, msg, pubKey))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		require.EqualError(t, c.VerifyWithPublicKey(nil, msg, nil), "verify with public key: public key is nil")
+
+		err := c.VerifyWithPublicKey(nil, msg, &cryptoapi.PublicKey{Type: "OKP", Curve: "X25519"})
+		require.EqualError(t, err, "verify with public key: unsupported key type 'OKP' and curve 'X25519'")
+
+		err = c.VerifyWithPublicKey(nil, msg, &cryptoapi.PublicKey{Type: "OKP", Curve: "Ed25519", X: []byte{1}})
+		require.EqualError(t, err, "verify with public key: invalid Ed25519 public key")
+
+		err = c.VerifyWithPublicKey(nil, msg, &cryptoapi.PublicKey{Type: "EC", Curve: "P-224"})
+		require.EqualError(t, err, "verify with public key: unsupported EC curve 'P-224'")
+
+		err = c.VerifyWithPublicKey(nil, msg, &cryptoapi.PublicKey{Type: "EC", Curve: "P-256", X: []byte{1}, Y: []byte{2}})
+		require.EqualError(t, err, "verify with public key: invalid EC public key")
+
+		err = c.VerifyMultiWithPublicKey(nil, nil, &cryptoapi.PublicKey{Type: "EC", Curve: "P-256"})
+		require.EqualError(t, err, "BBS+ verify with public key: public key is not a BLS12381_G2 key")
+	})
+}
@@ -7,17 +7,30 @@ SPDX-License-Identifier: Apache-2.0
 package tinkcrypto_test
 
 import (
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"math/big"
 	"testing"
 
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/subtle/random"
 	"github.com/stretchr/testify/require"
 
 	mockstorage "github.com/trustbloc/kms-go/internal/mock/storage"
 
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
 	kmsapi "github.com/trustbloc/kms-go/spi/kms"
 
 	"github.com/trustbloc/kms-go/spi/secretlock"
 
 	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
 	"github.com/trustbloc/kms-go/doc/util/jwkkid"
 	"github.com/trustbloc/kms-go/kms"
 	"github.com/trustbloc/kms-go/kms/localkms"
@@ -96,3 +109,407 @@ func TestSignVerifyKeyTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestRSAKeyTypes(t *testing.T) {
+	kmsStore, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+	require.NoError(t, err)
+
+	kmsStorage, err := localkms.New("local-lock://test/master/key/", &kmsProvider{
+		store:             kmsStore,
+		secretLockService: &noop.NoLock{},
+	})
+	require.NoError(t, err)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	data := []byte("abcdefg 1234567 1234567 1234567 1234567 1234567 AaAaAaAaAaAaAaAaAaAaAaAaAaAaAaAaAaAaAa")
+
+	for _, kt := range []kmsapi.KeyType{kmsapi.RSARS256Type, kmsapi.RSAPS256Type} {
+		t.Run(string(kt), func(t *testing.T) {
+			kid, pkb, err := kmsStorage.CreateAndExportPubKeyBytes(kt)
+			require.NoError(t, err)
+
+			kh, err := kmsStorage.Get(kid)
+			require.NoError(t, err)
+
+			pkJWK, err := jwkkid.BuildJWK(pkb, kt)
+			require.NoError(t, err)
+			require.Equal(t, "RSA", pkJWK.Kty)
+
+			jkBytes, err := pkJWK.PublicKeyBytes()
+			require.NoError(t, err)
+
+			kh2, err := kmsStorage.PubKeyBytesToHandle(jkBytes, kt)
+			require.NoError(t, err)
+
+			sig, err := cr.Sign(data, kh)
+			require.NoError(t, err)
+
+			require.NoError(t, cr.Verify(sig, data, kh2))
+			require.Error(t, cr.Verify(sig, []byte("other data"), kh2))
+		})
+	}
+
+	t.Run("RSA-OAEP-256 wrap and unwrap", func(t *testing.T) {
+		kid, pkb, err := kmsStorage.CreateAndExportPubKeyBytes(kmsapi.RSAOAEP256Type)
+		require.NoError(t, err)
+
+		kh, err := kmsStorage.Get(kid)
+		require.NoError(t, err)
+
+		pkJWK, err := jwkkid.BuildJWK(pkb, kmsapi.RSAOAEP256Type)
+		require.NoError(t, err)
+
+		rsaPub, ok := pkJWK.Key.(*rsa.PublicKey)
+		require.True(t, ok)
+
+		recPubKey := &cryptoapi.PublicKey{
+			KID:  kid,
+			Type: "RSA",
+			N:    rsaPub.N.Bytes(),
+			E:    big.NewInt(int64(rsaPub.E)).Bytes(),
+		}
+
+		cek := random.GetRandomBytes(32)
+
+		wk, err := cr.WrapKey(cek, nil, nil, recPubKey)
+		require.NoError(t, err)
+		require.Equal(t, tinkcrypto.RSAOAEP256Alg, wk.Alg)
+		require.Equal(t, kid, wk.KID)
+
+		unwrapped, err := cr.UnwrapKey(wk, kh)
+		require.NoError(t, err)
+		require.Equal(t, cek, unwrapped)
+
+		_, err = cr.WrapKey(cek, nil, nil, &cryptoapi.PublicKey{Type: "RSA"})
+		require.ErrorContains(t, err, "invalid RSA public key")
+
+		_, err = cr.UnwrapKey(wk, "not a key handle")
+		require.Error(t, err)
+	})
+}
+
+func TestHMACKeyTypes(t *testing.T) {
+	kmsStore, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+	require.NoError(t, err)
+
+	kmsStorage, err := localkms.New("local-lock://test/master/key/", &kmsProvider{
+		store:             kmsStore,
+		secretLockService: &noop.NoLock{},
+	})
+	require.NoError(t, err)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	data := []byte("alice@example.com")
+
+	testCases := []struct {
+		keyType kmsapi.KeyType
+		hash    func() hash.Hash
+	}{
+		{kmsapi.HMACSHA256Tag256Type, sha256.New},
+		{kmsapi.HMACSHA384Tag384Type, sha512.New384},
+		{kmsapi.HMACSHA512Tag512Type, sha512.New},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.keyType), func(t *testing.T) {
+			_, kh, err := kmsStorage.Create(tc.keyType)
+			require.NoError(t, err)
+
+			macBytes, err := cr.ComputeMAC(data, kh)
+			require.NoError(t, err)
+			require.NoError(t, cr.VerifyMAC(macBytes, data, kh))
+
+			// raw imported keys compute plain HMACs.
+			rawKey := random.GetRandomBytes(32)
+
+			_, importedKH, err := kmsStorage.ImportPrivateKey(rawKey, tc.keyType)
+			require.NoError(t, err)
+
+			h := hmac.New(tc.hash, rawKey)
+			h.Write(data)
+			expected := h.Sum(nil)
+
+			macBytes, err = cr.ComputeMAC(data, importedKH)
+			require.NoError(t, err)
+			require.Equal(t, expected, macBytes)
+			require.NoError(t, cr.VerifyMAC(expected, data, importedKH))
+
+			prf, err := cr.ComputePRF(data, importedKH, 32)
+			require.NoError(t, err)
+			require.Equal(t, expected[:32], prf)
+		})
+	}
+}
+
+func TestAESKeyWrap(t *testing.T) {
+	kmsStore, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+	require.NoError(t, err)
+
+	kmsStorage, err := localkms.New("local-lock://test/master/key/", &kmsProvider{
+		store:             kmsStore,
+		secretLockService: &noop.NoLock{},
+	})
+	require.NoError(t, err)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	dek := random.GetRandomBytes(32)
+
+	for kt, alg := range map[kmsapi.KeyType]string{
+		kmsapi.AES128KWType: tinkcrypto.A128KWAlg,
+		kmsapi.AES192KWType: tinkcrypto.A192KWAlg,
+		kmsapi.AES256KWType: tinkcrypto.A256KWAlg,
+	} {
+		t.Run(string(kt), func(t *testing.T) {
+			_, kek, err := kmsStorage.Create(kt)
+			require.NoError(t, err)
+
+			wk, err := cr.WrapKeyWithKEK(dek, kek)
+			require.NoError(t, err)
+			require.Equal(t, alg, wk.Alg)
+			require.Len(t, wk.EncryptedCEK, len(dek)+8)
+
+			unwrapped, err := cr.UnwrapKey(wk, kek)
+			require.NoError(t, err)
+			require.Equal(t, dek, unwrapped)
+		})
+	}
+
+	t.Run("imported KEK (RFC 3394 vector)", func(t *testing.T) {
+		kek, err := hex.DecodeString("000102030405060708090A0B0C0D0E0F")
+		require.NoError(t, err)
+
+		_, kh, err := kmsStorage.ImportPrivateKey(kek, kmsapi.AES128KWType)
+		require.NoError(t, err)
+
+		key, err := hex.DecodeString("00112233445566778899AABBCCDDEEFF")
+		require.NoError(t, err)
+
+		wk, err := cr.WrapKeyWithKEK(key, kh)
+		require.NoError(t, err)
+		require.Equal(t, "1fa68b0a8112b447aef34bd8fb5a7b829d3e862371d2cfe5", hex.EncodeToString(wk.EncryptedCEK))
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		_, kek, err := kmsStorage.Create(kmsapi.AES256KWType)
+		require.NoError(t, err)
+
+		_, otherKEK, err := kmsStorage.Create(kmsapi.AES128KWType)
+		require.NoError(t, err)
+
+		_, err = cr.WrapKeyWithKEK(dek, "bad key handle")
+		require.ErrorContains(t, err, "wrapKeyWithKEK")
+
+		_, hmacKH, err := kmsStorage.Create(kmsapi.HMACSHA256Tag256Type)
+		require.NoError(t, err)
+
+		_, err = cr.WrapKeyWithKEK(dek, hmacKH)
+		require.ErrorContains(t, err, "not a RAW AES-KW KeyWrapper primitive")
+
+		_, err = cr.WrapKeyWithKEK([]byte("not a multiple of 8"), kek)
+		require.ErrorContains(t, err, "wrapKeyWithKEK")
+
+		wk, err := cr.WrapKeyWithKEK(dek, kek)
+		require.NoError(t, err)
+
+		_, err = cr.UnwrapKey(wk, otherKEK)
+		require.ErrorContains(t, err, "A128KW key encryption key can't unwrap A256KW keys")
+
+		_, otherKEK, err = kmsStorage.Create(kmsapi.AES256KWType)
+		require.NoError(t, err)
+
+		_, err = cr.UnwrapKey(wk, otherKEK)
+		require.ErrorContains(t, err, "unwrapKey")
+
+		_, err = cr.UnwrapKey(wk, nil)
+		require.ErrorContains(t, err, "unwrapKey")
+	})
+}
+
+func TestX448KeyWrap(t *testing.T) {
+	kmsStore, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+	require.NoError(t, err)
+
+	kmsStorage, err := localkms.New("local-lock://test/master/key/", &kmsProvider{
+		store:             kmsStore,
+		secretLockService: &noop.NoLock{},
+	})
+	require.NoError(t, err)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	kid, recKH, err := kmsStorage.Create(kmsapi.X448ECDHKWType)
+	require.NoError(t, err)
+
+	pubKeyBytes, kt, err := kmsStorage.ExportPubKeyBytes(kid)
+	require.NoError(t, err)
+	require.Equal(t, kmsapi.X448ECDHKWType, kt)
+
+	recPubKey := &cryptoapi.PublicKey{}
+	require.NoError(t, json.Unmarshal(pubKeyBytes, recPubKey))
+	require.Equal(t, "X448", recPubKey.Curve)
+	require.Equal(t, "OKP", recPubKey.Type)
+	require.Len(t, recPubKey.X, 56)
+
+	expectedKID, err := jwkkid.CreateKID(pubKeyBytes, kmsapi.X448ECDHKWType)
+	require.NoError(t, err)
+	require.Equal(t, expectedKID, kid)
+
+	cek := random.GetRandomBytes(32)
+	apu := []byte("Alice")
+	apv := []byte("Bob")
+
+	for alg, opts := range map[string][]cryptoapi.WrapKeyOpts{
+		tinkcrypto.ECDHESA256KWAlg:  nil,
+		tinkcrypto.ECDHESXC20PKWAlg: {cryptoapi.WithXC20PKW()},
+	} {
+		t.Run(alg, func(t *testing.T) {
+			wk, err := cr.WrapKey(cek, apu, apv, recPubKey, opts...)
+			require.NoError(t, err)
+			require.Equal(t, alg, wk.Alg)
+			require.Equal(t, "X448", wk.EPK.Curve)
+			require.Len(t, wk.EPK.X, 56)
+
+			unwrapped, err := cr.UnwrapKey(wk, recKH)
+			require.NoError(t, err)
+			require.Equal(t, cek, unwrapped)
+		})
+	}
+
+	t.Run("JWK round trip", func(t *testing.T) {
+		j, err := jwksupport.JWKFromX448Key(recPubKey.X)
+		require.NoError(t, err)
+
+		jwkBytes, err := j.MarshalJSON()
+		require.NoError(t, err)
+		require.Contains(t, string(jwkBytes), `"crv":"X448"`)
+
+		parsed := &jwk.JWK{}
+		require.NoError(t, parsed.UnmarshalJSON(jwkBytes))
+
+		parsedKT, err := parsed.KeyType()
+		require.NoError(t, err)
+		require.Equal(t, kmsapi.X448ECDHKWType, parsedKT)
+
+		x, err := parsed.PublicKeyBytes()
+		require.NoError(t, err)
+		require.Equal(t, recPubKey.X, x)
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		_, senderKH, err := kmsStorage.Create(kmsapi.X448ECDHKWType)
+		require.NoError(t, err)
+
+		_, err = cr.WrapKey(cek, apu, apv, recPubKey, cryptoapi.WithSender(senderKH))
+		require.ErrorContains(t, err, "ECDH-1PU is not supported with X448 keys")
+
+		_, x25519KH, err := kmsStorage.Create(kmsapi.X25519ECDHKWType)
+		require.NoError(t, err)
+
+		wk, err := cr.WrapKey(cek, apu, apv, recPubKey)
+		require.NoError(t, err)
+
+		_, err = cr.UnwrapKey(wk, x25519KH)
+		require.ErrorContains(t, err, "recipient key is not an X448 key")
+
+		badRecPubKey := *recPubKey
+		badRecPubKey.X = badRecPubKey.X[1:]
+
+		_, err = cr.WrapKey(cek, apu, apv, &badRecPubKey)
+		require.ErrorContains(t, err, "invalid recipient key")
+	})
+}
+
+func TestECDHESDirectKeyAgreement(t *testing.T) {
+	kmsStore, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+	require.NoError(t, err)
+
+	kmsStorage, err := localkms.New("local-lock://test/master/key/", &kmsProvider{
+		store:             kmsStore,
+		secretLockService: &noop.NoLock{},
+	})
+	require.NoError(t, err)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	apu := []byte("Alice")
+	apv := []byte("Bob")
+
+	for _, kt := range []kmsapi.KeyType{
+		kmsapi.NISTP256ECDHKWType, kmsapi.NISTP384ECDHKWType, kmsapi.NISTP521ECDHKWType,
+		kmsapi.X25519ECDHKWType, kmsapi.X448ECDHKWType,
+	} {
+		t.Run(string(kt), func(t *testing.T) {
+			kid, recKH, err := kmsStorage.Create(kt)
+			require.NoError(t, err)
+
+			pubKeyBytes, _, err := kmsStorage.ExportPubKeyBytes(kid)
+			require.NoError(t, err)
+
+			recPubKey := &cryptoapi.PublicKey{}
+			require.NoError(t, json.Unmarshal(pubKeyBytes, recPubKey))
+
+			for _, enc := range []string{"A256GCM", "XC20P"} {
+				cek, rwk, err := cr.DeriveDirectKey(enc, apu, apv, recPubKey)
+				require.NoError(t, err)
+				require.Len(t, cek, 32)
+				require.Equal(t, tinkcrypto.ECDHESAlg, rwk.Alg)
+				require.Equal(t, enc, rwk.Enc)
+				require.Empty(t, rwk.EncryptedCEK)
+				require.NotEmpty(t, rwk.EPK.X)
+
+				derived, err := cr.UnwrapKey(rwk, recKH)
+				require.NoError(t, err)
+				require.Equal(t, cek, derived)
+
+				// a new ephemeral key gives a new CEK.
+				cek2, _, err := cr.DeriveDirectKey(enc, apu, apv, recPubKey)
+				require.NoError(t, err)
+				require.NotEqual(t, cek, cek2)
+			}
+
+			// the CEK is bound to enc.
+			cek, rwk, err := cr.DeriveDirectKey("A256GCM", apu, apv, recPubKey)
+			require.NoError(t, err)
+
+			rwk.Enc = "XC20P"
+
+			derived, err := cr.UnwrapKey(rwk, recKH)
+			require.NoError(t, err)
+			require.NotEqual(t, cek, derived)
+		})
+	}
+
+	t.Run("failures", func(t *testing.T) {
+		_, _, err := cr.DeriveDirectKey("A256GCM", apu, apv, nil)
+		require.EqualError(t, err, "deriveDirectKey: recipient public key is required")
+
+		_, recKH, err := kmsStorage.Create(kmsapi.NISTP256ECDHKWType)
+		require.NoError(t, err)
+
+		pubKH, err := recKH.(*keyset.Handle).Public()
+		require.NoError(t, err)
+
+		_, _, err = cr.DeriveDirectKey("A128CBC-HS256", apu, apv, &cryptoapi.PublicKey{})
+		require.EqualError(t, err, "deriveDirectKey: unsupported content encryption alg 'A128CBC-HS256'")
+
+		_, _, err = cr.DeriveDirectKey("A256GCM", apu, apv, &cryptoapi.PublicKey{Type: "bad"})
+		require.ErrorContains(t, err, "invalid recipient key type for ECDH-ES")
+
+		_, err = cr.UnwrapKey(&cryptoapi.RecipientWrappedKey{Alg: tinkcrypto.ECDHESAlg, Enc: "A128GCM"}, recKH)
+		require.EqualError(t, err, "unwrapKey: unsupported ECDH-ES content encryption alg 'A128GCM'")
+
+		_, err = cr.UnwrapKey(&cryptoapi.RecipientWrappedKey{Alg: tinkcrypto.ECDHESAlg, Enc: "A256GCM"}, "bad")
+		require.ErrorContains(t, err, "bad key handle format")
+
+		_, err = cr.UnwrapKey(&cryptoapi.RecipientWrappedKey{Alg: tinkcrypto.ECDHESAlg, Enc: "A256GCM"}, pubKH)
+		require.ErrorContains(t, err, "unwrapKey")
+	})
+}
@@ -17,12 +17,17 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 
 	josecipher "github.com/go-jose/go-jose/v3/cipher"
 	hybrid "github.com/google/tink/go/hybrid/subtle"
 	"golang.org/x/crypto/chacha20poly1305"
 
+	"github.com/trustbloc/kms-go/internal/memguard"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
 	"github.com/trustbloc/kms-go/util/cryptoutil"
+	"github.com/trustbloc/kms-go/util/entropy"
+	"github.com/trustbloc/kms-go/util/kdfdomain"
 
 	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aead/subtle"
 )
@@ -39,13 +44,23 @@ type keyWrapper interface {
 		keySize int) ([]byte, error)
 }
 
-type ecKWSupport struct{}
+type ecKWSupport struct {
+	// noTagKDF derives ECDH-1PU keks without the tag in the KDF SuppPubInfo (draft-madden-jose-ecdh-1pu-03).
+	noTagKDF bool
+	// random is the randomness source of the ephemeral keys, the system one if nil.
+	random io.Reader
+}
 
 func (w *ecKWSupport) getCurve(curve string) (elliptic.Curve, error) {
 	return hybrid.GetCurve(curve)
 }
 
 func (w *ecKWSupport) generateKey(curve elliptic.Curve) (interface{}, error) {
+	if w.random != nil {
+		// ecdsa.GenerateKey doesn't generate the same key from the same randomness.
+		return entropy.ECDSAKey(curve, w.random)
+	}
+
 	return ecdsa.GenerateKey(curve, rand.Reader)
 }
 
@@ -68,7 +83,13 @@ func (w *ecKWSupport) unwrap(block interface{}, encryptedKey []byte) ([]byte, er
 		return nil, errors.New("unwrap support: EC wrap with invalid cipher block type")
 	}
 
-	return josecipher.KeyUnwrap(blockCipher, encryptedKey)
+	cek, err := josecipher.KeyUnwrap(blockCipher, encryptedKey)
+	if err != nil && len(encryptedKey)%8 == 0 {
+		// the input is made of 8 bytes blocks: the integrity check failed.
+		return nil, fmt.Errorf("%w: %w", cryptoapi.ErrAuthTagMismatch, err)
+	}
+
+	return cek, err
 }
 
 func (w *ecKWSupport) deriveSender1Pu(alg string, apu, apv, tag []byte, ephemeralPriv, senderPrivKey interface{},
@@ -95,7 +116,12 @@ func (w *ecKWSupport) deriveSender1Pu(alg string, apu, apv, tag []byte, ephemera
 	ze := deriveECDH(ephemeralPrivEC, recPubKeyEC, keySize)
 	zs := deriveECDH(senderPrivKeyEC, recPubKeyEC, keySize)
 
-	return derive1Pu(alg, ze, zs, apu, apv, tag, keySize), nil
+	kek, err := derive1Pu(alg, ze, zs, apu, apv, tag, keySize, !w.noTagKDF)
+	if err != nil {
+		return nil, fmt.Errorf("deriveSender1Pu: %w", err)
+	}
+
+	return kek, nil
 }
 
 func (w *ecKWSupport) deriveRecipient1Pu(alg string, apu, apv, tag []byte, ephemeralPub, senderPubKey interface{},
@@ -123,11 +149,33 @@ func (w *ecKWSupport) deriveRecipient1Pu(alg string, apu, apv, tag []byte, ephem
 	ze := deriveECDH(recPrivKeyEC, ephemeralPubEC, keySize)
 	zs := deriveECDH(recPrivKeyEC, senderPubKeyEC, keySize)
 
-	return derive1Pu(alg, ze, zs, apu, apv, tag, keySize), nil
+	kek, err := derive1Pu(alg, ze, zs, apu, apv, tag, keySize, !w.noTagKDF)
+	if err != nil {
+		return nil, fmt.Errorf("deriveRecipient1Pu: %w", err)
+	}
+
+	return kek, nil
 }
 
 const byteSize = 8
 
+// concatKDFDomains are the key derivation domains of the Concat KDF AlgorithmIDs: the JOSE key wrapping alg or, for
+// direct key agreement, the content encryption alg (https://www.rfc-editor.org/rfc/rfc7518#section-4.6.2).
+//
+//nolint:gochecknoglobals
+var concatKDFDomains = registerConcatKDFDomains(ECDHESA256KWAlg, ECDHESXC20PKWAlg, ECDH1PUA128KWAlg, ECDH1PUA192KWAlg,
+	ECDH1PUA256KWAlg, ECDH1PUXC20PKWAlg, "A256GCM", "XC20P")
+
+func registerConcatKDFDomains(algs ...string) map[string]*kdfdomain.Domain {
+	domains := make(map[string]*kdfdomain.Domain, len(algs))
+
+	for _, alg := range algs {
+		domains[alg] = kdfdomain.MustRegister("jose/concat-kdf/"+alg, 1, []byte(alg))
+	}
+
+	return domains
+}
+
 // deriveECDH does key derivation using ECDH only (without KDF).
 func deriveECDH(priv *ecdsa.PrivateKey, pub *ecdsa.PublicKey, size int) []byte {
 	if size > 1<<16 {
@@ -169,7 +217,12 @@ func dSize(curve elliptic.Curve) int {
 	return size
 }
 
-type okpKWSupport struct{}
+type okpKWSupport struct {
+	// noTagKDF derives ECDH-1PU keks without the tag in the KDF SuppPubInfo (draft-madden-jose-ecdh-1pu-03).
+	noTagKDF bool
+	// random is the randomness source of the ephemeral keys and nonces, the system one if nil.
+	random io.Reader
+}
 
 func (o *okpKWSupport) getCurve(curve string) (elliptic.Curve, error) {
 	return nil, errors.New("getCurve: not implemented for OKP KW support")
@@ -178,7 +231,7 @@ func (o *okpKWSupport) getCurve(curve string) (elliptic.Curve, error) {
 func (o *okpKWSupport) generateKey(_ elliptic.Curve) (interface{}, error) {
 	newKey := make([]byte, cryptoutil.Curve25519KeySize)
 
-	_, err := rand.Read(newKey)
+	_, err := io.ReadFull(entropy.Reader(o.random), newKey)
 	if err != nil {
 		return nil, fmt.Errorf("generateKey: failed to create X25519 random key: %w", err)
 	}
@@ -204,7 +257,7 @@ func (o *okpKWSupport) wrap(aead interface{}, cek []byte) ([]byte, error) {
 	nonceSize := aeadPrimitive.NonceSize()
 	nonce := make([]byte, nonceSize)
 
-	_, err := rand.Read(nonce)
+	_, err := io.ReadFull(entropy.Reader(o.random), nonce)
 	if err != nil {
 		return nil, fmt.Errorf("wrap support: failed to generate random nonce: %w", err)
 	}
@@ -228,7 +281,7 @@ func (o *okpKWSupport) unwrap(aead interface{}, encryptedKey []byte) ([]byte, er
 
 	cek, err := aeadPrimitive.Open(nil, nonce, encryptedKey[aeadPrimitive.NonceSize():], nil)
 	if err != nil {
-		return nil, fmt.Errorf("unwrap support: OKP failed to unwrap key: %w", err)
+		return nil, fmt.Errorf("unwrap support: OKP failed to unwrap key: %w: %w", cryptoapi.ErrAuthTagMismatch, err)
 	}
 
 	return cek, nil
@@ -244,6 +297,8 @@ func (o *okpKWSupport) deriveSender1Pu(kwAlg string, apu, apv, tag []byte, ephem
 	ephemeralPrivOKPChacha := new([chacha20poly1305.KeySize]byte)
 	copy(ephemeralPrivOKPChacha[:], ephemeralPrivOKP)
 
+	defer memguard.Wipe(ephemeralPrivOKPChacha[:])
+
 	senderPrivKeyOKP, ok := senderPrivKey.([]byte)
 	if !ok {
 		return nil, errors.New("deriveSender1Pu: sender key not OKP type")
@@ -252,6 +307,8 @@ func (o *okpKWSupport) deriveSender1Pu(kwAlg string, apu, apv, tag []byte, ephem
 	senderPrivKeyOKPChacha := new([chacha20poly1305.KeySize]byte)
 	copy(senderPrivKeyOKPChacha[:], senderPrivKeyOKP)
 
+	defer memguard.Wipe(senderPrivKeyOKPChacha[:])
+
 	recPubKeyOKP, ok := recPubKey.([]byte)
 	if !ok {
 		return nil, errors.New("deriveSender1Pu: recipient key not OKP type")
@@ -270,7 +327,12 @@ func (o *okpKWSupport) deriveSender1Pu(kwAlg string, apu, apv, tag []byte, ephem
 		return nil, fmt.Errorf("deriveSender1Pu: %w", err)
 	}
 
-	return derive1Pu(kwAlg, ze, zs, apu, apv, tag, chacha20poly1305.KeySize), nil
+	kek, err := derive1Pu(kwAlg, ze, zs, apu, apv, tag, chacha20poly1305.KeySize, !o.noTagKDF)
+	if err != nil {
+		return nil, fmt.Errorf("deriveSender1Pu: %w", err)
+	}
+
+	return kek, nil
 }
 
 func (o *okpKWSupport) deriveRecipient1Pu(kwAlg string, apu, apv, tag []byte, ephemeralPub, senderPubKey interface{},
@@ -299,6 +361,8 @@ func (o *okpKWSupport) deriveRecipient1Pu(kwAlg string, apu, apv, tag []byte, ep
 	recPrivKeyOKPChacha := new([chacha20poly1305.KeySize]byte)
 	copy(recPrivKeyOKPChacha[:], recPrivKeyOKP)
 
+	defer memguard.Wipe(recPrivKeyOKPChacha[:])
+
 	ze, err := cryptoutil.DeriveECDHX25519(recPrivKeyOKPChacha, ephemeralPubOKPChacha)
 	if err != nil {
 		return nil, fmt.Errorf("deriveRecipient1Pu: %w", err)
@@ -309,22 +373,37 @@ func (o *okpKWSupport) deriveRecipient1Pu(kwAlg string, apu, apv, tag []byte, ep
 		return nil, fmt.Errorf("deriveRecipient1Pu: %w", err)
 	}
 
-	return derive1Pu(kwAlg, ze, zs, apu, apv, tag, chacha20poly1305.KeySize), nil
+	kek, err := derive1Pu(kwAlg, ze, zs, apu, apv, tag, chacha20poly1305.KeySize, !o.noTagKDF)
+	if err != nil {
+		return nil, fmt.Errorf("deriveRecipient1Pu: %w", err)
+	}
+
+	return kek, nil
 }
 
-func derive1Pu(kwAlg string, ze, zs, apu, apv, tag []byte, keySize int) []byte {
+// derive1Pu derives the ECDH-1PU kek from the shared secrets ze and zs, which are wiped afterwards.
+func derive1Pu(kwAlg string, ze, zs, apu, apv, tag []byte, keySize int, useTag bool) ([]byte, error) {
 	z := append([]byte{}, ze...)
 	z = append(z, zs...)
 
-	return kdfWithTag(kwAlg, z, apu, apv, tag, keySize, true)
+	defer memguard.Wipe(ze, zs, z)
+
+	return kdfWithTag(kwAlg, z, apu, apv, tag, keySize, useTag)
 }
 
-func kdf(kwAlg string, z, apu, apv []byte, keySize int) []byte {
+func kdf(kwAlg string, z, apu, apv []byte, keySize int) ([]byte, error) {
 	return kdfWithTag(kwAlg, z, apu, apv, nil, keySize, false)
 }
 
-func kdfWithTag(kwAlg string, z, apu, apv, tag []byte, keySize int, useTag bool) []byte {
-	algID := cryptoutil.LengthPrefix([]byte(kwAlg))
+// kdfWithTag derives the kek with the Concat KDF. The returned error is set if kwAlg, the KDF AlgorithmID, is not a
+// registered key derivation domain.
+func kdfWithTag(kwAlg string, z, apu, apv, tag []byte, keySize int, useTag bool) ([]byte, error) {
+	domain, ok := concatKDFDomains[kwAlg]
+	if !ok {
+		return nil, fmt.Errorf("concat KDF: AlgorithmID '%s' is not a registered key derivation domain", kwAlg)
+	}
+
+	algID := cryptoutil.LengthPrefix(domain.Info())
 	ptyUInfo := cryptoutil.LengthPrefix(apu)
 	ptyVInfo := cryptoutil.LengthPrefix(apv)
 
@@ -358,5 +437,5 @@ func kdfWithTag(kwAlg string, z, apu, apv, tag []byte, keySize int, useTag bool)
 
 	_, _ = reader.Read(kek) // nolint:errcheck // ConcatKDF's Read() never returns an error
 
-	return kek
+	return kek, nil
 }
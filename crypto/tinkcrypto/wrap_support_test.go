@@ -19,6 +19,7 @@ import (
 	"golang.org/x/crypto/chacha20poly1305"
 	"golang.org/x/crypto/curve25519"
 
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
 	"github.com/trustbloc/kms-go/util/cryptoutil"
 )
 
@@ -100,7 +101,9 @@ func Test_okpKWSupportFailures(t *testing.T) {
 	require.EqualError(t, err, "unwrap support: OKP unwrap invalid key")
 
 	_, err = okpKW.unwrap(XC20PPrimitive, []byte("badEncryptedKeyLargerThankNonceSize"))
-	require.EqualError(t, err, "unwrap support: OKP failed to unwrap key: chacha20poly1305: message authentication failed")
+	require.EqualError(t, err, "unwrap support: OKP failed to unwrap key: authentication tag mismatch: "+
+		"chacha20poly1305: message authentication failed")
+	require.ErrorIs(t, err, cryptoapi.ErrAuthTagMismatch)
 
 	_, err = okpKW.deriveSender1Pu("", nil, nil, nil, "badEphemeralPrivKeyType", nil, nil, 0)
 	require.EqualError(t, err, "deriveSender1Pu: ephemeral key not OKP type")
@@ -361,3 +364,12 @@ func TestDeriveReferenceKey(t *testing.T) {
 		require.EqualValues(t, cek, charlieDecryptedCEK)
 	})
 }
+
+func TestConcatKDFDomains(t *testing.T) {
+	for alg, d := range concatKDFDomains {
+		require.Equal(t, []byte(alg), d.Info())
+	}
+
+	_, err := kdf("unknown", nil, nil, nil, 32)
+	require.EqualError(t, err, "concat KDF: AlgorithmID 'unknown' is not a registered key derivation domain")
+}
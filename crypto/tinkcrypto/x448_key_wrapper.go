@@ -0,0 +1,102 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cloudflare/circl/dh/x448"
+
+	"github.com/trustbloc/kms-go/internal/memguard"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+)
+
+const (
+	x448Crv = "X448"
+
+	x448ECDHKWPrivateKeyTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.X448EcdhKwPrivateKey"
+)
+
+// x448PrivKey is an X448 private key extracted from a keyset handle. It is a distinct type from X25519 private keys
+// ([]byte) so keys of both curves can't be mixed up.
+type x448PrivKey []byte
+
+// deriveESWithX448Key derives an ECDH-ES kek for an X448 recipient key, using an ephemeral X448 key and Concat KDF
+// as per https://tools.ietf.org/html/rfc7748#section-6.2.
+func deriveESWithX448Key(wrappingAlg string, apu, apv []byte, recPubKey *cryptoapi.PublicKey,
+	random io.Reader) ([]byte, *cryptoapi.PublicKey, []byte, error) {
+	var ephemeralPub, ephemeralPriv, recPub, z x448.Key
+
+	defer memguard.Wipe(ephemeralPriv[:], z[:])
+
+	if len(recPubKey.X) != x448.Size {
+		return nil, nil, nil, errors.New("deriveESWithX448Key: invalid recipient key")
+	}
+
+	copy(recPub[:], recPubKey.X)
+
+	if _, err := io.ReadFull(random, ephemeralPriv[:]); err != nil {
+		return nil, nil, nil, fmt.Errorf("deriveESWithX448Key: failed to generate ephemeral key: %w", err)
+	}
+
+	x448.KeyGen(&ephemeralPub, &ephemeralPriv)
+
+	if !x448.Shared(&z, &ephemeralPriv, &recPub) {
+		return nil, nil, nil, errors.New("deriveESWithX448Key: invalid recipient key")
+	}
+
+	if len(apu) == 0 {
+		apu = make([]byte, base64.RawURLEncoding.EncodedLen(len(ephemeralPub)))
+		base64.RawURLEncoding.Encode(apu, ephemeralPub[:])
+	}
+
+	kek, err := kdf(wrappingAlg, z[:], apu, apv, defKeySize)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("deriveESWithX448Key: %w", err)
+	}
+
+	epk := &cryptoapi.PublicKey{
+		X:     ephemeralPub[:],
+		Curve: x448Crv,
+		Type:  recPubKey.Type,
+	}
+
+	return kek, epk, apu, nil
+}
+
+func deriveESWithX448KeyForUnwrap(alg string, apu, apv []byte, epk *cryptoapi.PublicKey,
+	recipientPrivateKey interface{}) ([]byte, error) {
+	var recPriv, ephemeralPub, z x448.Key
+
+	defer memguard.Wipe(recPriv[:], z[:])
+
+	recPrivKey, ok := recipientPrivateKey.(x448PrivKey)
+	if !ok {
+		return nil, errors.New("deriveESWithX448KeyForUnwrap: recipient key is not an X448 key")
+	}
+
+	if len(epk.X) != x448.Size {
+		return nil, errors.New("deriveESWithX448KeyForUnwrap: invalid ephemeral key")
+	}
+
+	copy(recPriv[:], recPrivKey)
+	copy(ephemeralPub[:], epk.X)
+
+	if !x448.Shared(&z, &recPriv, &ephemeralPub) {
+		return nil, errors.New("deriveESWithX448KeyForUnwrap: invalid ephemeral key")
+	}
+
+	kek, err := kdf(alg, z[:], apu, apv, defKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("deriveESWithX448KeyForUnwrap: %w", err)
+	}
+
+	return kek, nil
+}
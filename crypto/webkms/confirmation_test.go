@@ -0,0 +1,207 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webkms
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+	"github.com/stretchr/testify/require"
+
+	webkmsimpl "github.com/trustbloc/kms-go/kms/webkms"
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+const confirmationPath = "/confirmations/1"
+
+// confirmingServer signs with kh once the pending signature is confirmed.
+type confirmingServer struct {
+	mu        sync.Mutex
+	kh        *keyset.Handle
+	msg       []byte
+	confirmed bool
+	rejected  bool
+	polls     int
+	inBody    bool
+}
+
+func (s *confirmingServer) confirm() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.confirmed = true
+}
+
+func (s *confirmingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, signURI):
+		req := &signReq{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		s.msg = req.Message
+
+		if s.inBody {
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"confirmation_uri":"` + confirmationPath + `"}`))
+
+			return
+		}
+
+		w.Header().Set("Location", confirmationPath)
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == http.MethodGet && r.URL.Path == confirmationPath:
+		s.polls++
+
+		switch {
+		case s.rejected:
+			w.WriteHeader(http.StatusForbidden)
+		case !s.confirmed:
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			signer, err := signature.NewSigner(s.kh)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+
+				return
+			}
+
+			sig, err := signer.Sign(s.msg)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(&signResp{Signature: sig})
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestSignWithConfirmation(t *testing.T) {
+	kh, err := keyset.NewHandle(signature.ECDSAP256KeyTemplate())
+	require.NoError(t, err)
+
+	pubKH, err := kh.Public()
+	require.NoError(t, err)
+
+	verifier, err := signature.NewVerifier(pubKH)
+	require.NoError(t, err)
+
+	msg := []byte("lorem ipsum")
+
+	newServer := func(t *testing.T) (*confirmingServer, string) {
+		s := &confirmingServer{kh: kh}
+		srv := httptest.NewServer(s)
+		t.Cleanup(srv.Close)
+
+		return s, srv.URL + "/v1/keystores/" + defaultKeyStoreID + "/keys/" + defaultKID
+	}
+
+	t.Run("polling", func(t *testing.T) {
+		s, keyURL := newServer(t)
+
+		go func() {
+			time.Sleep(30 * time.Millisecond)
+			s.confirm()
+		}()
+
+		rCrypto := New(keyURL, http.DefaultClient, webkmsimpl.WithConfirmationPolling(10*time.Millisecond, time.Minute))
+
+		sig, err := rCrypto.Sign(msg, keyURL)
+		require.NoError(t, err)
+		require.NoError(t, verifier.Verify(sig, msg))
+		require.Greater(t, s.polls, 1)
+	})
+
+	t.Run("confirmation URI in the response body", func(t *testing.T) {
+		s, keyURL := newServer(t)
+		s.inBody = true
+		s.confirmed = true
+
+		rCrypto := New(keyURL, http.DefaultClient, webkmsimpl.WithConfirmationPolling(10*time.Millisecond, 0))
+
+		sig, err := rCrypto.Sign(msg, keyURL)
+		require.NoError(t, err)
+		require.NoError(t, verifier.Verify(sig, msg))
+	})
+
+	t.Run("webhook callback", func(t *testing.T) {
+		s, keyURL := newServer(t)
+		webhook := make(chan string)
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			s.confirm()
+			webhook <- keyURL[:strings.Index(keyURL, "/v1/")] + confirmationPath
+		}()
+
+		var waited string
+
+		rCrypto := New(keyURL, http.DefaultClient, webkmsimpl.WithConfirmationWaiter(func(confirmationURL string) error {
+			waited = confirmationURL
+
+			require.Equal(t, confirmationURL, <-webhook)
+
+			return nil
+		}))
+
+		sig, err := rCrypto.Sign(msg, keyURL)
+		require.NoError(t, err)
+		require.NoError(t, verifier.Verify(sig, msg))
+		require.True(t, strings.HasSuffix(waited, confirmationPath))
+		require.Equal(t, 1, s.polls)
+	})
+
+	t.Run("confirmation not supported", func(t *testing.T) {
+		_, keyURL := newServer(t)
+
+		_, err := New(keyURL, http.DefaultClient).Sign(msg, keyURL)
+		require.ErrorIs(t, err, webkmsimpl.ErrConfirmationRequired)
+	})
+
+	t.Run("confirmation times out", func(t *testing.T) {
+		_, keyURL := newServer(t)
+
+		rCrypto := New(keyURL, http.DefaultClient,
+			webkmsimpl.WithConfirmationPolling(10*time.Millisecond, 50*time.Millisecond))
+
+		_, err := rCrypto.Sign(msg, keyURL)
+		require.ErrorIs(t, err, webkmsimpl.ErrConfirmationTimeout)
+	})
+
+	t.Run("signature rejected", func(t *testing.T) {
+		s, keyURL := newServer(t)
+		s.rejected = true
+
+		rCrypto := New(keyURL, http.DefaultClient, webkmsimpl.WithConfirmationPolling(10*time.Millisecond, 0))
+
+		_, err := rCrypto.Sign(msg, keyURL)
+		require.EqualError(t, err, "posting Sign returned http error: 403 Forbidden")
+
+		var remoteErr *kms.RemoteError
+
+		require.ErrorAs(t, err, &remoteErr)
+		require.Equal(t, http.StatusForbidden, remoteErr.StatusCode)
+		require.NotErrorIs(t, err, kms.ErrRemoteUnavailable)
+	})
+}
@@ -8,6 +8,7 @@ package webkms
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/json"
 	"errors"
@@ -24,8 +25,10 @@ import (
 	"github.com/google/tink/go/keyset"
 
 	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/spi/kms"
 
 	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/composite/keyio"
+	"github.com/trustbloc/kms-go/kms/audit"
 	webkmsimpl "github.com/trustbloc/kms-go/kms/webkms"
 )
 
@@ -168,6 +171,8 @@ const (
 	wrapURI       = "/wrap"
 	unwrapURI     = "/unwrap"
 
+	capabilitiesURI = "/capabilities"
+
 	// multi signatures/selective disclosure crypto (eg BBS+) endpoints.
 	signMultiURI   = "/signmulti"
 	verifyMultiURI = "/verifymulti"
@@ -192,11 +197,27 @@ func New(keystoreURL string, client HTTPClient, opts ...webkmsimpl.Opt) *RemoteC
 	}
 }
 
+// audit records the operation op on the key at keyURL with the audit logger set by webkms.WithAuditLogger.
+func (r *RemoteCrypto) audit(op kms.Operation, keyURL interface{}, start time.Time, err error) {
+	if r.opts.AuditLogger == nil {
+		return
+	}
+
+	u := fmt.Sprintf("%s", keyURL)
+
+	audit.Log(r.opts.AuditLogger, op, u[strings.LastIndex(u, "/")+1:], nil, start, err)
+}
+
 func (r *RemoteCrypto) postHTTPRequest(destination string, mReq []byte) (*http.Response, error) {
-	return r.doHTTPRequest(http.MethodPost, destination, mReq)
+	return r.doHTTPRequest(context.Background(), http.MethodPost, destination, mReq)
 }
 
-func (r *RemoteCrypto) doHTTPRequest(method, destination string, mReq []byte) (*http.Response, error) {
+func (r *RemoteCrypto) getHTTPRequest(ctx context.Context, destination string) (*http.Response, error) {
+	return r.doHTTPRequest(ctx, http.MethodGet, destination, nil)
+}
+
+func (r *RemoteCrypto) doHTTPRequest(ctx context.Context, method, destination string,
+	mReq []byte) (*http.Response, error) {
 	start := time.Now()
 
 	var body io.Reader
@@ -205,7 +226,7 @@ func (r *RemoteCrypto) doHTTPRequest(method, destination string, mReq []byte) (*
 		body = bytes.NewBuffer(mReq)
 	}
 
-	httpReq, err := http.NewRequest(method, destination, body)
+	httpReq, err := http.NewRequestWithContext(ctx, method, destination, body)
 	if err != nil {
 		return nil, fmt.Errorf("build request error: %w", err)
 	}
@@ -214,24 +235,49 @@ func (r *RemoteCrypto) doHTTPRequest(method, destination string, mReq []byte) (*
 		httpReq.Header.Set("Content-Type", webkmsimpl.ContentType)
 	}
 
-	if r.opts.HeadersFunc != nil {
-		httpHeaders, e := r.opts.HeadersFunc(httpReq)
-		if e != nil {
-			return nil, fmt.Errorf("add optional request headers error: %w", e)
-		}
-
-		if httpHeaders != nil {
-			httpReq.Header = httpHeaders.Clone()
-		}
+	if err = webkmsimpl.SetHeaders(r.opts, httpReq); err != nil {
+		return nil, err
 	}
 
-	resp, err := r.httpClient.Do(httpReq)
+	resp, err := webkmsimpl.DoTraced(r.opts, r.httpClient, httpReq)
 
 	debugLogger.Printf("  HTTP %s %s call duration: %s", method, destination, time.Since(start))
+	webkmsimpl.LogRequest(r.opts, httpReq, resp, err, start)
 
 	return resp, err
 }
 
+// Capabilities fetches the key types, algorithms, operations and limits supported by the remote keystore.
+func (r *RemoteCrypto) Capabilities() (*kms.Capabilities, error) {
+	destination := r.keystoreURL + capabilitiesURI
+
+	resp, err := r.getHTTPRequest(context.Background(), destination)
+	if err != nil {
+		return nil, fmt.Errorf("posting GET Capabilities failed [%s, %w]", destination, err)
+	}
+
+	// handle response
+	defer closeResponseBody(resp.Body, "Capabilities")
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpError("Capabilities", resp)
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read capabilities response failed [%s, %w]", destination, err)
+	}
+
+	caps := &kms.Capabilities{}
+
+	err = r.unmarshalFunc(respBody, caps)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal capabilities failed [%s, %w]", destination, err)
+	}
+
+	return caps, nil
+}
+
 // Encrypt will remotely encrypt msg and aad using a matching AEAD primitive in a remote key handle at keyURL of
 // a public key.
 // returns:
@@ -240,6 +286,15 @@ func (r *RemoteCrypto) doHTTPRequest(method, destination string, mReq []byte) (*
 //	nonce in []byte
 //	error in case of errors during encryption
 func (r *RemoteCrypto) Encrypt(msg, aad []byte, keyURL interface{}) ([]byte, []byte, error) {
+	start := time.Now()
+	ct, nonce, err := r.encrypt(msg, aad, keyURL)
+
+	r.audit(kms.OperationEncrypt, keyURL, start, err)
+
+	return ct, nonce, err
+}
+
+func (r *RemoteCrypto) encrypt(msg, aad []byte, keyURL interface{}) ([]byte, []byte, error) {
 	startEncrypt := time.Now()
 	destination := fmt.Sprintf("%s", keyURL) + encryptURI
 
@@ -262,7 +317,7 @@ func (r *RemoteCrypto) Encrypt(msg, aad []byte, keyURL interface{}) ([]byte, []b
 	defer closeResponseBody(resp.Body, "Encrypt")
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, nil, fmt.Errorf("posting Encrypt returned http error: %s", resp.Status)
+		return nil, nil, httpError("Encrypt", resp)
 	}
 
 	respBody, err := ioutil.ReadAll(resp.Body)
@@ -289,6 +344,15 @@ func (r *RemoteCrypto) Encrypt(msg, aad []byte, keyURL interface{}) ([]byte, []b
 //	plainText in []byte
 //	error in case of errors
 func (r *RemoteCrypto) Decrypt(cipher, aad, nonce []byte, keyURL interface{}) ([]byte, error) {
+	start := time.Now()
+	pt, err := r.decrypt(cipher, aad, nonce, keyURL)
+
+	r.audit(kms.OperationDecrypt, keyURL, start, err)
+
+	return pt, err
+}
+
+func (r *RemoteCrypto) decrypt(cipher, aad, nonce []byte, keyURL interface{}) ([]byte, error) {
 	startDecrypt := time.Now()
 	destination := fmt.Sprintf("%s", keyURL) + decryptURI
 
@@ -312,7 +376,7 @@ func (r *RemoteCrypto) Decrypt(cipher, aad, nonce []byte, keyURL interface{}) ([
 	defer closeResponseBody(resp.Body, "Decrypt")
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("posting Decrypt returned http error: %s", resp.Status)
+		return nil, httpError("Decrypt", resp)
 	}
 
 	respBody, err := ioutil.ReadAll(resp.Body)
@@ -333,11 +397,22 @@ func (r *RemoteCrypto) Decrypt(cipher, aad, nonce []byte, keyURL interface{}) ([
 }
 
 // Sign will remotely sign msg using a matching signature primitive in remote kh key handle at keyURL of a private key.
+// If the server requires a user confirmation of the signature (202 Accepted response with a confirmation URI), Sign
+// resumes once it is confirmed as set by the webkms.WithConfirmationPolling and webkms.WithConfirmationWaiter options.
 // returns:
 //
 //	signature in []byte
 //	error in case of errors
 func (r *RemoteCrypto) Sign(msg []byte, keyURL interface{}) ([]byte, error) {
+	start := time.Now()
+	sig, err := r.sign(msg, keyURL)
+
+	r.audit(kms.OperationSign, keyURL, start, err)
+
+	return sig, err
+}
+
+func (r *RemoteCrypto) sign(msg []byte, keyURL interface{}) ([]byte, error) {
 	startSign := time.Now()
 	destination := fmt.Sprintf("%s", keyURL) + signURI
 
@@ -355,11 +430,16 @@ func (r *RemoteCrypto) Sign(msg []byte, keyURL interface{}) ([]byte, error) {
 		return nil, fmt.Errorf("posting Sign message failed [%s, %w]", destination, err)
 	}
 
+	resp, err = webkmsimpl.AwaitConfirmation(context.Background(), r.opts, destination, resp, r.getHTTPRequest)
+	if err != nil {
+		return nil, fmt.Errorf("posting Sign message failed [%s, %w]", destination, err)
+	}
+
 	// handle response
 	defer closeResponseBody(resp.Body, "Sign")
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("posting Sign returned http error: %s", resp.Status)
+		return nil, httpError("Sign", resp)
 	}
 
 	respBody, err := ioutil.ReadAll(resp.Body)
@@ -385,6 +465,15 @@ func (r *RemoteCrypto) Sign(msg []byte, keyURL interface{}) ([]byte, error) {
 //
 //	error in case of errors or nil if signature verification was successful
 func (r *RemoteCrypto) Verify(signature, msg []byte, keyURL interface{}) error {
+	start := time.Now()
+	err := r.verify(signature, msg, keyURL)
+
+	r.audit(kms.OperationVerify, keyURL, start, err)
+
+	return err
+}
+
+func (r *RemoteCrypto) verify(signature, msg []byte, keyURL interface{}) error {
 	startVerify := time.Now()
 	destination := fmt.Sprintf("%s", keyURL) + verifyURI
 
@@ -407,7 +496,7 @@ func (r *RemoteCrypto) Verify(signature, msg []byte, keyURL interface{}) error {
 	defer closeResponseBody(resp.Body, "Verify")
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("posting Verify signature returned http error: %s", resp.Status)
+		return httpError("Verify signature", resp)
 	}
 
 	debugLogger.Printf("overall Verify duration: %s", time.Since(startVerify))
@@ -417,7 +506,16 @@ func (r *RemoteCrypto) Verify(signature, msg []byte, keyURL interface{}) error {
 
 // ComputeMAC remotely computes message authentication code (MAC) for code data with key at keyURL.
 // using a matching MAC primitive in kh key handle.
-func (r *RemoteCrypto) ComputeMAC(data []byte, keyURL interface{}) ([]byte, error) { // nolint:gocyclo
+func (r *RemoteCrypto) ComputeMAC(data []byte, keyURL interface{}) ([]byte, error) {
+	start := time.Now()
+	mac, err := r.computeMAC(data, keyURL)
+
+	r.audit(kms.OperationComputeMAC, keyURL, start, err)
+
+	return mac, err
+}
+
+func (r *RemoteCrypto) computeMAC(data []byte, keyURL interface{}) ([]byte, error) { // nolint:gocyclo
 	keyHash := string(sha256.New().Sum([]byte(fmt.Sprintf("%s_%s", keyURL, data))))
 
 	if r.opts.ComputeMACCache != nil {
@@ -452,7 +550,7 @@ func (r *RemoteCrypto) ComputeMAC(data []byte, keyURL interface{}) ([]byte, erro
 	defer closeResponseBody(resp.Body, "ComputeMAC")
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("posting ComputeMAC request returned http error: %s", resp.Status)
+		return nil, httpError("ComputeMAC request", resp)
 	}
 
 	respBody, err := ioutil.ReadAll(resp.Body)
@@ -481,6 +579,15 @@ func (r *RemoteCrypto) ComputeMAC(data []byte, keyURL interface{}) ([]byte, erro
 // VerifyMAC remotely determines if mac is a correct authentication code (MAC) for data using a key at KeyURL
 // using a matching MAC primitive in kh key handle and returns nil if so, otherwise it returns an error.
 func (r *RemoteCrypto) VerifyMAC(mac, data []byte, keyURL interface{}) error {
+	start := time.Now()
+	err := r.verifyMAC(mac, data, keyURL)
+
+	r.audit(kms.OperationVerifyMAC, keyURL, start, err)
+
+	return err
+}
+
+func (r *RemoteCrypto) verifyMAC(mac, data []byte, keyURL interface{}) error {
 	startVerifyMAC := time.Now()
 	destination := fmt.Sprintf("%s", keyURL) + verifyMACURI
 
@@ -503,7 +610,7 @@ func (r *RemoteCrypto) VerifyMAC(mac, data []byte, keyURL interface{}) error {
 	defer closeResponseBody(resp.Body, "VerifyMAC")
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("posting VerifyMAC request returned http error: %s", resp.Status)
+		return httpError("VerifyMAC request", resp)
 	}
 
 	debugLogger.Printf("overall VerifyMAC duration: %s", time.Since(startVerifyMAC))
@@ -518,7 +625,23 @@ func (r *RemoteCrypto) VerifyMAC(mac, data []byte, keyURL interface{}) error {
 //
 //	RecipientWrappedKey containing the wrapped cek value
 //	error in case of errors
-func (r *RemoteCrypto) WrapKey(cek, apu, apv []byte, recPubKey *cryptoapi.PublicKey, // nolint:funlen
+func (r *RemoteCrypto) WrapKey(cek, apu, apv []byte, recPubKey *cryptoapi.PublicKey,
+	opts ...cryptoapi.WrapKeyOpts) (*cryptoapi.RecipientWrappedKey, error) {
+	start := time.Now()
+	wk, err := r.wrapKey(cek, apu, apv, recPubKey, opts...)
+
+	var kid string
+
+	if recPubKey != nil {
+		kid = recPubKey.KID
+	}
+
+	audit.Log(r.opts.AuditLogger, kms.OperationWrapKey, kid, nil, start, err)
+
+	return wk, err
+}
+
+func (r *RemoteCrypto) wrapKey(cek, apu, apv []byte, recPubKey *cryptoapi.PublicKey, // nolint:funlen
 	opts ...cryptoapi.WrapKeyOpts) (*cryptoapi.RecipientWrappedKey, error) {
 	startWrapKey := time.Now()
 	destination := r.keystoreURL + wrapURI
@@ -567,7 +690,7 @@ func (r *RemoteCrypto) WrapKey(cek, apu, apv []byte, recPubKey *cryptoapi.Public
 	defer closeResponseBody(resp.Body, "WrapKey")
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("posting WrapKey returned http error: %s", resp.Status)
+		return nil, httpError("WrapKey", resp)
 	}
 
 	respBody, err := ioutil.ReadAll(resp.Body)
@@ -602,6 +725,16 @@ func (r *RemoteCrypto) buildWrappedKeyResponse(respBody []byte, dest string) (*c
 //	unwrapped key in raw bytes
 //	error in case of errors
 func (r *RemoteCrypto) UnwrapKey(recWK *cryptoapi.RecipientWrappedKey, keyURL interface{},
+	opts ...cryptoapi.WrapKeyOpts) ([]byte, error) {
+	start := time.Now()
+	cek, err := r.unwrapKey(recWK, keyURL, opts...)
+
+	r.audit(kms.OperationUnwrapKey, keyURL, start, err)
+
+	return cek, err
+}
+
+func (r *RemoteCrypto) unwrapKey(recWK *cryptoapi.RecipientWrappedKey, keyURL interface{},
 	opts ...cryptoapi.WrapKeyOpts) ([]byte, error) {
 	startUnwrapKey := time.Now()
 	destination := fmt.Sprintf("%s", keyURL) + unwrapURI
@@ -640,7 +773,7 @@ func (r *RemoteCrypto) UnwrapKey(recWK *cryptoapi.RecipientWrappedKey, keyURL in
 	defer closeResponseBody(resp.Body, "UnwrapKey")
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("posting UnwrapKey returned http error: %s", resp.Status)
+		return nil, httpError("UnwrapKey", resp)
 	}
 
 	respBody, err := ioutil.ReadAll(resp.Body)
@@ -703,7 +836,7 @@ func (r *RemoteCrypto) SignMulti(messages [][]byte, signerKeyURL interface{}) ([
 	defer closeResponseBody(resp.Body, "BBS+ Sign")
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("posting BBS+ sign returned http error: %s", resp.Status)
+		return nil, httpError("BBS+ sign", resp)
 	}
 
 	respBody, err := ioutil.ReadAll(resp.Body)
@@ -750,7 +883,7 @@ func (r *RemoteCrypto) VerifyMulti(messages [][]byte, signature []byte, signerKe
 	defer closeResponseBody(resp.Body, "BBS+ Verify")
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("posting BBS+ Verify signature returned http error: %s", resp.Status)
+		return httpError("BBS+ Verify signature", resp)
 	}
 
 	debugLogger.Printf("overall BBS+ Verify duration: %s", time.Since(startVerify))
@@ -787,7 +920,7 @@ func (r *RemoteCrypto) VerifyProof(revealedMessages [][]byte, proof, nonce []byt
 	defer closeResponseBody(resp.Body, "BBS+ Verify Proof")
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("posting BBS+ Verify proof returned http error: %s", resp.Status)
+		return httpError("BBS+ Verify proof", resp)
 	}
 
 	debugLogger.Printf("overall BBS+ Verify proof duration: %s", time.Since(startVerifyProof))
@@ -827,7 +960,7 @@ func (r *RemoteCrypto) DeriveProof(messages [][]byte, bbsSignature, nonce []byte
 	defer closeResponseBody(resp.Body, "BBS+ Derive Proof")
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("posting BBS+ Derive proof returned http error: %s", resp.Status)
+		return nil, httpError("BBS+ Derive proof", resp)
 	}
 
 	respBody, err := ioutil.ReadAll(resp.Body)
@@ -848,6 +981,14 @@ func (r *RemoteCrypto) DeriveProof(messages [][]byte, bbsSignature, nonce []byte
 }
 
 // closeResponseBody closes the response body.
+// httpError returns the *kms.RemoteError of the non 200 response resp of the action request.
+func httpError(action string, resp *http.Response) error {
+	return &kms.RemoteError{
+		StatusCode: resp.StatusCode,
+		Message:    fmt.Sprintf("posting %s returned http error: %s", action, resp.Status),
+	}
+}
+
 func closeResponseBody(respBody io.Closer, action string) {
 	err := respBody.Close()
 	if err != nil {
@@ -0,0 +1,164 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package age provides the age (https://age-encryption.org/v1) X25519 identities of X25519 keys held by a KMS, for
+// filippo.io/age. An X25519Identity is an age.Identity unwrapping the file keys with a kms.X25519ECDHKWType key: the
+// X25519 operations are executed by the KeyRef.ComputeDH of a kms/keyref Manager, whose Policy must allow
+// kms.OperationComputeDH for the key, so the private key is never exported.
+//
+// Files are encrypted with age.Encrypt to the age.X25519Recipient of the identity (or its age1 recipient string, eg:
+// with the age CLI), and decrypted with age.Decrypt and the identity.
+package age
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"filippo.io/age"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/trustbloc/kms-go/kms/keyref"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+)
+
+const (
+	x25519StanzaType = "X25519"
+	x25519Label      = "age-encryption.org/v1/X25519"
+	recipientHRP     = "age"
+	fileKeySize      = 16
+)
+
+// X25519Identity is an age X25519 identity of an X25519 key of a KMS.
+type X25519Identity struct {
+	key       *keyref.KeyRef
+	pub       []byte
+	recipient *age.X25519Recipient
+}
+
+var _ age.Identity = (*X25519Identity)(nil)
+
+// NewX25519Identity creates the X25519Identity of the kms.X25519ECDHKWType key keyID of m, executing the X25519
+// operations with KeyRef.ComputeDH.
+func NewX25519Identity(m *keyref.Manager, keyID string) (*X25519Identity, error) {
+	key, err := m.Get(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("age: new X25519 identity: get key: %w", err)
+	}
+
+	pubKey, err := key.ExportJWK()
+	if err != nil {
+		return nil, fmt.Errorf("age: new X25519 identity: export public key: %w", err)
+	}
+
+	if pubKey.Crv != x25519StanzaType {
+		return nil, fmt.Errorf("age: new X25519 identity: key '%s' is not an X25519 key", keyID)
+	}
+
+	pub, ok := pubKey.Key.([]byte)
+	if !ok || len(pub) != curve25519.PointSize {
+		return nil, errors.New("age: new X25519 identity: invalid X25519 public key")
+	}
+
+	s, err := bech32Encode(recipientHRP, pub)
+	if err != nil {
+		return nil, fmt.Errorf("age: new X25519 identity: %w", err)
+	}
+
+	recipient, err := age.ParseX25519Recipient(s)
+	if err != nil {
+		return nil, fmt.Errorf("age: new X25519 identity: %w", err)
+	}
+
+	return &X25519Identity{key: key, pub: pub, recipient: recipient}, nil
+}
+
+// Recipient returns the age.X25519Recipient of the identity.
+func (i *X25519Identity) Recipient() *age.X25519Recipient {
+	return i.recipient
+}
+
+// Unwrap unwraps the file key of the X25519 stanza of the identity, it returns age.ErrIncorrectIdentity if none of the
+// stanzas is for the identity.
+func (i *X25519Identity) Unwrap(stanzas []*age.Stanza) ([]byte, error) {
+	for _, s := range stanzas {
+		fileKey, err := i.unwrap(s)
+		if errors.Is(err, age.ErrIncorrectIdentity) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return fileKey, nil
+	}
+
+	return nil, age.ErrIncorrectIdentity
+}
+
+func (i *X25519Identity) unwrap(s *age.Stanza) ([]byte, error) {
+	if s.Type != x25519StanzaType {
+		return nil, age.ErrIncorrectIdentity
+	}
+
+	if len(s.Args) != 1 {
+		return nil, errors.New("age: invalid X25519 recipient block")
+	}
+
+	share, err := base64.RawStdEncoding.Strict().DecodeString(s.Args[0])
+	if err != nil || len(share) != curve25519.PointSize {
+		return nil, errors.New("age: invalid X25519 recipient block")
+	}
+
+	if len(s.Body) != fileKeySize+chacha20poly1305.Overhead {
+		return nil, errors.New("age: invalid X25519 recipient block: incorrect file key size")
+	}
+
+	shared, err := i.key.ComputeDH(&cryptoapi.PublicKey{Type: "OKP", Curve: "X25519", X: share})
+	if err != nil {
+		return nil, fmt.Errorf("age: unwrap: compute DH: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare(shared, make([]byte, len(shared))) == 1 {
+		return nil, errors.New("age: invalid X25519 recipient block")
+	}
+
+	fileKey, err := aeadOpen(wrappingKey(shared, share, i.pub), s.Body)
+	if err != nil {
+		// the stanza is for another recipient.
+		return nil, age.ErrIncorrectIdentity
+	}
+
+	return fileKey, nil
+}
+
+func wrappingKey(shared, share, pub []byte) []byte {
+	salt := make([]byte, 0, len(share)+len(pub))
+	salt = append(salt, share...)
+	salt = append(salt, pub...)
+
+	key := make([]byte, chacha20poly1305.KeySize)
+
+	_, _ = io.ReadFull(hkdf.New(sha256.New, shared, salt, []byte(x25519Label)), key) //nolint:errcheck // 32 bytes key
+
+	return key
+}
+
+// aeadOpen decrypts ciphertext with key and a zero nonce, the wrapping keys are used once.
+func aeadOpen(key, ciphertext []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Open(nil, make([]byte, chacha20poly1305.NonceSize), ciphertext, nil)
+}
@@ -0,0 +1,199 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package age_test
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"testing"
+
+	filippoage "filippo.io/age"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/doc/age"
+	"github.com/trustbloc/kms-go/kms/keyref"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	km := mockkms.NewForTest(t)
+	identity := newIdentity(t, km)
+
+	// the age1 recipient string, eg: of the age CLI -r flag.
+	recipient, err := filippoage.ParseX25519Recipient(identity.Recipient().String())
+	require.NoError(t, err)
+
+	for _, size := range []int{0, 1, 64*1024 + 1} {
+		plaintext := make([]byte, size)
+		_, err = rand.Read(plaintext)
+		require.NoError(t, err)
+
+		file := encrypt(t, plaintext, recipient)
+		require.Equal(t, plaintext, decrypt(t, file, identity))
+	}
+}
+
+func TestRecipientString(t *testing.T) {
+	km := mockkms.NewForTest(t)
+
+	// the key of the examples of the age specification.
+	key, err := ecdh.X25519().NewPrivateKey(bytes.Repeat([]byte{0x42}, 32))
+	require.NoError(t, err)
+
+	keyID, _, err := km.ImportPrivateKey(key, kmsapi.X25519ECDHKWType)
+	require.NoError(t, err)
+
+	identity, err := age.NewX25519Identity(newManager(t, km, keyID), keyID)
+	require.NoError(t, err)
+
+	require.Equal(t, "age1zvkyg2lqzraa2lnjvqej32nkuu0ues2s82hzrye869xeexvn73equnujwj", identity.Recipient().String())
+}
+
+func TestMultipleIdentities(t *testing.T) {
+	km := mockkms.NewForTest(t)
+	alice, bob, eve := newIdentity(t, km), newIdentity(t, km), newIdentity(t, km)
+
+	software, err := filippoage.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	file := encrypt(t, []byte("for alice, bob and carol"), alice.Recipient(), bob.Recipient(), software.Recipient())
+
+	require.Equal(t, []byte("for alice, bob and carol"), decrypt(t, file, bob))
+	require.Equal(t, []byte("for alice, bob and carol"), decrypt(t, file, eve, alice))
+	require.Equal(t, []byte("for alice, bob and carol"), decrypt(t, file, software))
+
+	var noMatch *filippoage.NoIdentityMatchError
+
+	_, err = filippoage.Decrypt(bytes.NewReader(file), eve)
+	require.ErrorAs(t, err, &noMatch)
+
+	// the KMS identity doesn't unwrap the stanzas of other recipients.
+	other, err := filippoage.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	_, err = filippoage.Decrypt(bytes.NewReader(encrypt(t, []byte("for dave"), other.Recipient())), alice)
+	require.ErrorAs(t, err, &noMatch)
+}
+
+func TestUnwrapErrors(t *testing.T) {
+	km := mockkms.NewForTest(t)
+	identity := newIdentity(t, km)
+
+	stanzas, err := identity.Recipient().Wrap(make([]byte, 16))
+	require.NoError(t, err)
+	require.Len(t, stanzas, 1)
+
+	fileKey, err := identity.Unwrap(stanzas)
+	require.NoError(t, err)
+	require.Equal(t, make([]byte, 16), fileKey)
+
+	valid := *stanzas[0]
+
+	lowOrder := base64.RawStdEncoding.EncodeToString(make([]byte, 32))
+
+	for name, tc := range map[string]struct {
+		args []string
+		body []byte
+		err  string
+	}{
+		"args":      {body: valid.Body, err: "invalid X25519 recipient block"},
+		"share":     {args: []string{"AA"}, body: valid.Body, err: "invalid X25519 recipient block"},
+		"body":      {args: valid.Args, body: valid.Body[1:], err: "incorrect file key size"},
+		"low order": {args: []string{lowOrder}, body: valid.Body, err: "low order point"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err = identity.Unwrap([]*filippoage.Stanza{{Type: "X25519", Args: tc.args, Body: tc.body}})
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+
+	_, err = identity.Unwrap([]*filippoage.Stanza{{Type: "scrypt", Args: []string{"salt", "10"}}})
+	require.ErrorIs(t, err, filippoage.ErrIncorrectIdentity)
+}
+
+func TestNewX25519IdentityErrors(t *testing.T) {
+	km := mockkms.NewForTest(t)
+
+	m := newManager(t, km)
+
+	_, err := age.NewX25519Identity(m, "missing")
+	require.ErrorContains(t, err, "age: new X25519 identity: get key:")
+
+	keyID, _, err := km.Create(kmsapi.NISTP256ECDHKWType)
+	require.NoError(t, err)
+
+	_, err = age.NewX25519Identity(m, keyID)
+	require.EqualError(t, err, "age: new X25519 identity: key '"+keyID+"' is not an X25519 key")
+
+	// the Manager Policy doesn't allow computeDH for the key.
+	keyID, _, err = km.Create(kmsapi.X25519ECDHKWType)
+	require.NoError(t, err)
+
+	identity, err := age.NewX25519Identity(m, keyID)
+	require.NoError(t, err)
+
+	_, err = filippoage.Decrypt(bytes.NewReader(encrypt(t, []byte("secret"), identity.Recipient())), identity)
+	require.ErrorIs(t, err, keyref.ErrComputeDHDisabled)
+}
+
+func encrypt(t *testing.T, plaintext []byte, recipients ...filippoage.Recipient) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+
+	w, err := filippoage.Encrypt(&b, recipients...)
+	require.NoError(t, err)
+
+	_, err = w.Write(plaintext)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return b.Bytes()
+}
+
+func decrypt(t *testing.T, file []byte, identities ...filippoage.Identity) []byte {
+	t.Helper()
+
+	r, err := filippoage.Decrypt(bytes.NewReader(file), identities...)
+	require.NoError(t, err)
+
+	plaintext, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	if plaintext == nil {
+		plaintext = []byte{}
+	}
+
+	return plaintext
+}
+
+func newIdentity(t *testing.T, km kmsapi.KeyManager) *age.X25519Identity {
+	t.Helper()
+
+	keyID, _, err := km.Create(kmsapi.X25519ECDHKWType)
+	require.NoError(t, err)
+
+	identity, err := age.NewX25519Identity(newManager(t, km, keyID), keyID)
+	require.NoError(t, err)
+
+	return identity
+}
+
+// newManager returns a keyref Manager of the keys of km allowing computeDH for the keys keyIDs.
+func newManager(t *testing.T, km kmsapi.KeyManager, keyIDs ...string) *keyref.Manager {
+	t.Helper()
+
+	c, err := tinkcrypto.New(tinkcrypto.WithComputeDH())
+	require.NoError(t, err)
+
+	return keyref.New(km, c, keyref.WithPolicy(keyref.AllowComputeDH(keyIDs...)))
+}
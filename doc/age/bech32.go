@@ -0,0 +1,115 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package age
+
+import (
+	"errors"
+	"strings"
+)
+
+// Bech32 (https://github.com/bitcoin/bips/blob/master/bip-0173.mediawiki) encoding without the 90 characters limit, as
+// the age keys use it: filippo.io/age parses the age1 recipient strings but doesn't create recipients from the public
+// keys.
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+//nolint:gochecknoglobals
+var bech32Generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+
+	for _, v := range values {
+		top := chk >> 25 //nolint:gomnd // BIP 173 checksum
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+
+		for i := range bech32Generator {
+			if (top>>i)&1 == 1 {
+				chk ^= bech32Generator[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	v := make([]byte, 0, 2*len(hrp)+1)
+
+	for i := 0; i < len(hrp); i++ {
+		v = append(v, hrp[i]>>5) //nolint:gomnd // BIP 173 HRP expansion
+	}
+
+	v = append(v, 0)
+
+	for i := 0; i < len(hrp); i++ {
+		v = append(v, hrp[i]&31) //nolint:gomnd // BIP 173 HRP expansion
+	}
+
+	return v
+}
+
+// convertBits regroups the frombits bits groups of data to tobits bits groups.
+func convertBits(data []byte, frombits, tobits uint, pad bool) ([]byte, error) {
+	var (
+		acc  uint32
+		bits uint
+		out  []byte
+	)
+
+	maxv := uint32(1)<<tobits - 1
+
+	for _, v := range data {
+		if uint32(v)>>frombits != 0 {
+			return nil, errors.New("invalid data range")
+		}
+
+		acc = acc<<frombits | uint32(v)
+		bits += frombits
+
+		for bits >= tobits {
+			bits -= tobits
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+
+	switch {
+	case pad && bits > 0:
+		out = append(out, byte(acc<<(tobits-bits)&maxv))
+	case !pad && (bits >= frombits || acc<<(tobits-bits)&maxv != 0):
+		return nil, errors.New("invalid padding")
+	}
+
+	return out, nil
+}
+
+// bech32Encode returns the lower case Bech32 string of hrp and data.
+func bech32Encode(hrp string, data []byte) (string, error) {
+	values, err := convertBits(data, 8, 5, true) //nolint:gomnd // bytes to 5 bits groups
+	if err != nil {
+		return "", err
+	}
+
+	hrp = strings.ToLower(hrp)
+
+	polymod := bech32Polymod(append(append(bech32HRPExpand(hrp), values...), 0, 0, 0, 0, 0, 0)) ^ 1
+
+	var b strings.Builder
+
+	b.WriteString(hrp)
+	b.WriteByte('1')
+
+	for _, v := range values {
+		b.WriteByte(bech32Charset[v])
+	}
+
+	for i := 0; i < 6; i++ {
+		b.WriteByte(bech32Charset[(polymod>>(5*(5-i)))&31]) //nolint:gomnd // 6 checksum characters of 5 bits
+	}
+
+	return b.String(), nil
+}
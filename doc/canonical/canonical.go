@@ -0,0 +1,75 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package canonical provides the canonicalization algorithms applied to documents before they are signed, so that
+// signers and verifiers compute signatures over the same bytes: JCS (https://www.rfc-editor.org/rfc/rfc8785) for
+// JSON documents and URDNA2015 (https://www.w3.org/TR/rdf-canon/) for JSON-LD documents. Other algorithms can be
+// added to the registry with Register.
+package canonical
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Names of the built-in canonicalization algorithms.
+const (
+	JCS       = "JCS"
+	URDNA2015 = "URDNA2015"
+)
+
+// Canonicalizer transforms a document into its canonical form.
+type Canonicalizer interface {
+	Canonicalize(doc []byte) ([]byte, error)
+}
+
+// CanonicalizerFunc is a function implementing Canonicalizer.
+type CanonicalizerFunc func(doc []byte) ([]byte, error)
+
+// Canonicalize calls f(doc).
+func (f CanonicalizerFunc) Canonicalize(doc []byte) ([]byte, error) {
+	return f(doc)
+}
+
+//nolint:gochecknoglobals
+var (
+	mu       sync.RWMutex
+	registry = map[string]Canonicalizer{
+		JCS:       NewJCS(),
+		URDNA2015: NewURDNA2015(),
+	}
+)
+
+// Register registers c under name, replacing any algorithm already registered with this name.
+func Register(name string, c Canonicalizer) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	registry[name] = c
+}
+
+// Lookup returns the canonicalization algorithm registered under name.
+func Lookup(name string) (Canonicalizer, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	c, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("canonicalization algorithm '%s' is not registered", name)
+	}
+
+	return c, nil
+}
+
+// Canonicalize transforms doc with the canonicalization algorithm registered under name.
+func Canonicalize(name string, doc []byte) ([]byte, error) {
+	c, err := Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Canonicalize(doc)
+}
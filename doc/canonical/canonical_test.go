@@ -0,0 +1,104 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package canonical
+
+import (
+	"testing"
+
+	"github.com/piprate/json-gold/ld"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJCS(t *testing.T) {
+	c, err := Lookup(JCS)
+	require.NoError(t, err)
+
+	t.Run("RFC 8785 examples", func(t *testing.T) {
+		res, err := c.Canonicalize([]byte(`{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`))
+		require.NoError(t, err)
+		require.Equal(t,
+			`{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],`+
+				`"string":"€$\u000f\nA'B\"\\\\\"/"}`, string(res))
+
+		res, err = c.Canonicalize([]byte(`{"\u20ac":"Euro Sign","\r":"Carriage Return","\ufb33":"Hebrew Letter",` +
+			`"1":"One","\ud83d\ude00":"Emoji","\u0080":"Control","\u00f6":"Latin Small Letter O With Diaeresis"}`))
+		require.NoError(t, err)
+		require.Equal(t, "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\","+
+			"\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\",\"\U0001F600\":\"Emoji\","+
+			"\"\ufb33\":\"Hebrew Letter\"}", string(res))
+	})
+
+	t.Run("numbers", func(t *testing.T) {
+		for in, out := range map[string]string{
+			"-0": "0", "1e21": "1e+21", "1e20": "100000000000000000000", "0.000001": "0.000001", "1e-7": "1e-7",
+			"-1.5e-300": "-1.5e-300", "9007199254740993": "9007199254740992",
+		} {
+			res, err := c.Canonicalize([]byte("[" + in + "]"))
+			require.NoError(t, err)
+			require.Equal(t, "["+out+"]", string(res), in)
+		}
+	})
+
+	t.Run("nested values", func(t *testing.T) {
+		res, err := c.Canonicalize([]byte(` { "b" : [ {}, [], {"d":1, "c":"\t\u0001"} ], "a" : { } } `))
+		require.NoError(t, err)
+		require.Equal(t, `{"a":{},"b":[{},[],{"c":"\t\u0001","d":1}]}`, string(res))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, doc := range []string{`{"a":1,"a":2}`, `[1e400]`, `{"a":1} {}`, `{"a":`, `[1,`, ``} {
+			_, err := c.Canonicalize([]byte(doc))
+			require.Error(t, err, doc)
+		}
+	})
+}
+
+func TestURDNA2015(t *testing.T) {
+	doc := []byte(`{
+  "@context": {"name": "http://schema.org/name", "knows": {"@id": "http://schema.org/knows", "@type": "@id"}},
+  "name": "Alice",
+  "knows": {"@id": "http://example.com/bob", "name": "Bob"}
+}`)
+
+	res, err := Canonicalize(URDNA2015, doc)
+	require.NoError(t, err)
+	require.Equal(t, `<http://example.com/bob> <http://schema.org/name> "Bob" .
+_:c14n0 <http://schema.org/knows> <http://example.com/bob> .
+_:c14n0 <http://schema.org/name> "Alice" .
+`, string(res))
+
+	c := NewURDNA2015(WithDocumentLoader(ld.NewCachingDocumentLoader(ld.NewDefaultDocumentLoader(nil))))
+
+	same, err := c.Canonicalize([]byte(`{"knows":{"name":"Bob","@id":"http://example.com/bob"},"name":"Alice",` +
+		`"@context":{"knows":{"@type":"@id","@id":"http://schema.org/knows"},"name":"http://schema.org/name"}}`))
+	require.NoError(t, err)
+	require.Equal(t, res, same)
+
+	_, err = c.Canonicalize([]byte(`{`))
+	require.ErrorContains(t, err, "unmarshal document")
+
+	_, err = c.Canonicalize([]byte(`{"@context": 1}`))
+	require.Error(t, err)
+}
+
+func TestRegistry(t *testing.T) {
+	_, err := Lookup("unknown")
+	require.ErrorContains(t, err, "not registered")
+
+	_, err = Canonicalize("unknown", nil)
+	require.Error(t, err)
+
+	Register("identity", CanonicalizerFunc(func(doc []byte) ([]byte, error) { return doc, nil }))
+
+	res, err := Canonicalize("identity", []byte("doc"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("doc"), res)
+}
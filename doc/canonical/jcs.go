@@ -0,0 +1,237 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package canonical
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+type jcs struct{}
+
+// NewJCS returns the JSON Canonicalization Scheme (RFC 8785): object members sorted by their UTF-16 encoded names,
+// no insignificant whitespace, minimal string escaping and numbers serialized as ECMAScript does. Documents with
+// duplicate member names are rejected.
+func NewJCS() Canonicalizer {
+	return jcs{}
+}
+
+func (jcs) Canonicalize(doc []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	v, err := parseValue(dec)
+	if err != nil {
+		return nil, fmt.Errorf("jcs: %w", err)
+	}
+
+	if _, err = dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("jcs: unexpected data after the JSON value")
+	}
+
+	var b bytes.Buffer
+
+	if err = writeValue(&b, v); err != nil {
+		return nil, fmt.Errorf("jcs: %w", err)
+	}
+
+	return b.Bytes(), nil
+}
+
+type member struct {
+	name  string
+	value interface{}
+}
+
+func parseValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		return parseObject(dec)
+	case json.Delim('['):
+		var arr []interface{}
+
+		for dec.More() {
+			v, e := parseValue(dec)
+			if e != nil {
+				return nil, e
+			}
+
+			arr = append(arr, v)
+		}
+
+		_, err = dec.Token()
+
+		return arr, err
+	default:
+		return tok, nil
+	}
+}
+
+func parseObject(dec *json.Decoder) ([]member, error) {
+	var (
+		members []member
+		names   = map[string]bool{}
+	)
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		name, _ := tok.(string) // the decoder only returns string tokens for member names.
+		if names[name] {
+			return nil, fmt.Errorf("duplicate member name '%s'", name)
+		}
+
+		names[name] = true
+
+		v, err := parseValue(dec)
+		if err != nil {
+			return nil, err
+		}
+
+		members = append(members, member{name: name, value: v})
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(members, func(i, j int) bool { return lessUTF16(members[i].name, members[j].name) })
+
+	return members, nil
+}
+
+func writeValue(b *bytes.Buffer, v interface{}) error {
+	switch t := v.(type) {
+	case []member:
+		b.WriteByte('{')
+
+		for i, m := range t {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+
+			writeString(b, m.name)
+			b.WriteByte(':')
+
+			if err := writeValue(b, m.value); err != nil {
+				return err
+			}
+		}
+
+		b.WriteByte('}')
+	case []interface{}:
+		b.WriteByte('[')
+
+		for i, e := range t {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+
+			if err := writeValue(b, e); err != nil {
+				return err
+			}
+		}
+
+		b.WriteByte(']')
+	case string:
+		writeString(b, t)
+	case json.Number:
+		n, err := formatNumber(t)
+		if err != nil {
+			return err
+		}
+
+		b.WriteString(n)
+	case bool:
+		b.WriteString(strconv.FormatBool(t))
+	default:
+		b.WriteString("null")
+	}
+
+	return nil
+}
+
+// formatNumber serializes a number as the ECMAScript Number.prototype.toString method does.
+func formatNumber(n json.Number) (string, error) {
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil || math.IsInf(f, 0) {
+		return "", fmt.Errorf("number %s is not an IEEE 754 double precision value", n)
+	}
+
+	if f == 0 {
+		return "0", nil
+	}
+
+	if abs := math.Abs(f); abs >= 1e-6 && abs < 1e21 {
+		return strconv.FormatFloat(f, 'f', -1, 64), nil
+	}
+
+	s := strconv.FormatFloat(f, 'e', -1, 64)
+
+	// ECMAScript exponents have no leading zeros: 1e-07 is 1e-7.
+	i := strings.IndexByte(s, 'e') + 2 //nolint:gomnd
+
+	return s[:i] + strings.TrimLeft(s[i:], "0"), nil
+}
+
+func writeString(b *bytes.Buffer, s string) {
+	b.WriteByte('"')
+
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 { //nolint:gomnd
+				fmt.Fprintf(b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+
+	b.WriteByte('"')
+}
+
+func lessUTF16(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+
+	return len(ua) < len(ub)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package canonical
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/piprate/json-gold/ld"
+)
+
+const nQuadsFormat = "application/n-quads"
+
+// URDNA2015Opt is a NewURDNA2015 option.
+type URDNA2015Opt func(c *urdna2015)
+
+// WithDocumentLoader sets the loader of the remote JSON-LD contexts. By default, contexts are fetched over HTTP for
+// every document, which should be avoided in production by using a caching or preloaded loader.
+func WithDocumentLoader(loader ld.DocumentLoader) URDNA2015Opt {
+	return func(c *urdna2015) {
+		c.loader = loader
+	}
+}
+
+type urdna2015 struct {
+	loader ld.DocumentLoader
+}
+
+// NewURDNA2015 returns the URDNA2015 RDF dataset canonicalization of JSON-LD documents, serialized as N-Quads.
+func NewURDNA2015(opts ...URDNA2015Opt) Canonicalizer {
+	c := &urdna2015{}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.loader == nil {
+		c.loader = ld.NewDefaultDocumentLoader(nil)
+	}
+
+	return c
+}
+
+func (c *urdna2015) Canonicalize(doc []byte) ([]byte, error) {
+	var input interface{}
+
+	if err := json.Unmarshal(doc, &input); err != nil {
+		return nil, fmt.Errorf("urdna2015: unmarshal document: %w", err)
+	}
+
+	options := ld.NewJsonLdOptions("")
+	options.ProcessingMode = ld.JsonLd_1_1
+	options.Algorithm = ld.AlgorithmURDNA2015
+	options.Format = nQuadsFormat
+	options.DocumentLoader = c.loader
+
+	res, err := ld.NewJsonLdProcessor().Normalize(input, options)
+	if err != nil {
+		return nil, fmt.Errorf("urdna2015: %w", err)
+	}
+
+	nQuads, ok := res.(string)
+	if !ok {
+		return nil, fmt.Errorf("urdna2015: unexpected result type %T", res)
+	}
+
+	return []byte(nQuads), nil
+}
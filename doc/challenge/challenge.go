@@ -0,0 +1,205 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package challenge manages the single-use nonces (challenges) that verifiers send to provers so that proofs of
+// possession and presentations can't be replayed: a Manager generates random challenges with a TTL, stores them and
+// consumes them when the proof is verified.
+package challenge
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTTL is the default validity of a challenge.
+	DefaultTTL = 5 * time.Minute
+	// DefaultSize is the default number of random bytes of a challenge.
+	DefaultSize = 32
+)
+
+// Consume errors.
+var (
+	ErrNotFound       = errors.New("challenge: unknown or already consumed challenge")
+	ErrExpired        = errors.New("challenge: challenge is expired")
+	ErrDomainMismatch = errors.New("challenge: domain does not match")
+)
+
+// Challenge is an issued challenge.
+type Challenge struct {
+	// Value is the base64url encoded random nonce sent to the prover.
+	Value string `json:"value"`
+	// Domain, if set, is the verifier (eg: the audience of a proof) the challenge was issued for.
+	Domain    string    `json:"domain,omitempty"`
+	IssuedAt  time.Time `json:"issuedAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Store stores the issued challenges until they are consumed.
+type Store interface {
+	// Put stores c.
+	Put(c *Challenge) error
+	// Consume atomically removes and returns the challenge with the given value, or returns an error wrapping
+	// ErrNotFound if there is none. A challenge can't be consumed twice, even concurrently.
+	Consume(value string) (*Challenge, error)
+}
+
+// Manager generates and consumes challenges.
+type Manager struct {
+	store        Store
+	ttl          time.Duration
+	size         int
+	now          func() time.Time
+	randomReader io.Reader
+}
+
+// Opt is a NewManager option.
+type Opt func(m *Manager)
+
+// WithStore sets the challenge store, an in-memory store by default. A shared store is needed when the challenges
+// are consumed by another instance than the one that generated them.
+func WithStore(s Store) Opt {
+	return func(m *Manager) {
+		m.store = s
+	}
+}
+
+// WithTTL sets the validity of the generated challenges, DefaultTTL by default.
+func WithTTL(ttl time.Duration) Opt {
+	return func(m *Manager) {
+		m.ttl = ttl
+	}
+}
+
+// WithSize sets the number of random bytes of the generated challenges, DefaultSize by default.
+func WithSize(size int) Opt {
+	return func(m *Manager) {
+		m.size = size
+	}
+}
+
+// WithClock sets the current time source, time.Now by default.
+func WithClock(now func() time.Time) Opt {
+	return func(m *Manager) {
+		m.now = now
+	}
+}
+
+// WithRandomReader sets the source of the challenges, crypto/rand by default.
+func WithRandomReader(r io.Reader) Opt {
+	return func(m *Manager) {
+		m.randomReader = r
+	}
+}
+
+// NewManager creates a challenge Manager.
+func NewManager(opts ...Opt) *Manager {
+	m := &Manager{ttl: DefaultTTL, size: DefaultSize, now: time.Now, randomReader: rand.Reader}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if m.store == nil {
+		m.store = NewMemStore()
+	}
+
+	return m
+}
+
+// Generate generates and stores a new challenge for domain, which may be empty if the challenge is not bound to a
+// verifier.
+func (m *Manager) Generate(domain string) (*Challenge, error) {
+	b := make([]byte, m.size)
+
+	if _, err := io.ReadFull(m.randomReader, b); err != nil {
+		return nil, fmt.Errorf("challenge: read random: %w", err)
+	}
+
+	now := m.now()
+
+	c := &Challenge{
+		Value:     base64.RawURLEncoding.EncodeToString(b),
+		Domain:    domain,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(m.ttl),
+	}
+
+	if err := m.store.Put(c); err != nil {
+		return nil, fmt.Errorf("challenge: store: %w", err)
+	}
+
+	return c, nil
+}
+
+// Consume consumes the challenge value received in a proof for domain. It fails if the challenge was not generated
+// by the manager, was already consumed, is expired or was generated for another domain. A challenge generated without
+// a domain is accepted for any domain.
+func (m *Manager) Consume(value, domain string) (*Challenge, error) {
+	c, err := m.store.Consume(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if !m.now().Before(c.ExpiresAt) {
+		return nil, ErrExpired
+	}
+
+	if c.Domain != "" && c.Domain != domain {
+		return nil, ErrDomainMismatch
+	}
+
+	return c, nil
+}
+
+// MemStore is an in-memory Store. Expired challenges are purged when new ones are stored.
+type MemStore struct {
+	mu         sync.Mutex
+	challenges map[string]*Challenge
+}
+
+// NewMemStore creates an in-memory Store.
+func NewMemStore() *MemStore {
+	return &MemStore{challenges: map[string]*Challenge{}}
+}
+
+// Put stores c.
+func (s *MemStore) Put(c *Challenge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	for v, e := range s.challenges {
+		if now.After(e.ExpiresAt) {
+			delete(s.challenges, v)
+		}
+	}
+
+	s.challenges[c.Value] = c
+
+	return nil
+}
+
+// Consume removes and returns the challenge with the given value.
+func (s *MemStore) Consume(value string) (*Challenge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.challenges[value]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	delete(s.challenges, value)
+
+	return c, nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package challenge
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/doc/jose/jws"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func TestManager(t *testing.T) {
+	now := time.Now()
+	m := NewManager(WithTTL(time.Minute), WithSize(16), WithClock(func() time.Time { return now }))
+
+	t.Run("single use", func(t *testing.T) {
+		c, err := m.Generate("https://verifier.example.com")
+		require.NoError(t, err)
+		require.Len(t, c.Value, 22)
+		require.Equal(t, now.Add(time.Minute), c.ExpiresAt)
+
+		consumed, err := m.Consume(c.Value, "https://verifier.example.com")
+		require.NoError(t, err)
+		require.Equal(t, c, consumed)
+
+		_, err = m.Consume(c.Value, "https://verifier.example.com")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("domain", func(t *testing.T) {
+		c, err := m.Generate("https://verifier.example.com")
+		require.NoError(t, err)
+
+		_, err = m.Consume(c.Value, "https://other.example.com")
+		require.ErrorIs(t, err, ErrDomainMismatch)
+
+		unbound, err := m.Generate("")
+		require.NoError(t, err)
+
+		_, err = m.Consume(unbound.Value, "https://other.example.com")
+		require.NoError(t, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		c, err := m.Generate("")
+		require.NoError(t, err)
+
+		late := NewManager(WithStore(m.store), WithClock(func() time.Time { return now.Add(time.Minute) }))
+
+		_, err = late.Consume(c.Value, "")
+		require.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("concurrent consumption", func(t *testing.T) {
+		c, err := m.Generate("")
+		require.NoError(t, err)
+
+		var (
+			wg       sync.WaitGroup
+			accepted int32
+		)
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				if _, e := m.Consume(c.Value, ""); e == nil {
+					atomic.AddInt32(&accepted, 1)
+				}
+			}()
+		}
+
+		wg.Wait()
+		require.EqualValues(t, 1, accepted)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewManager(WithRandomReader(failingReader{})).Generate("")
+		require.ErrorContains(t, err, "read random")
+
+		_, err = NewManager(WithStore(failingStore{})).Generate("")
+		require.ErrorContains(t, err, "store")
+	})
+}
+
+func TestMemStorePurge(t *testing.T) {
+	s := NewMemStore()
+
+	require.NoError(t, s.Put(&Challenge{Value: "expired", ExpiresAt: time.Now().Add(-time.Second)}))
+	require.NoError(t, s.Put(&Challenge{Value: "valid", ExpiresAt: time.Now().Add(time.Minute)}))
+
+	_, err := s.Consume("expired")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = s.Consume("valid")
+	require.NoError(t, err)
+}
+
+func TestVerifyProofJWT(t *testing.T) {
+	km := mockkms.NewForTest(t)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	svc := jws.New(km, cr)
+
+	keyID, _, err := km.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	m := NewManager()
+
+	proof := func(claims map[string]interface{}) string {
+		payload, e := json.Marshal(claims)
+		require.NoError(t, e)
+
+		signed, e := svc.SignJWS(keyID, nil, payload)
+		require.NoError(t, e)
+
+		compact, e := signed.Compact(false)
+		require.NoError(t, e)
+
+		return compact
+	}
+
+	const domain = "https://verifier.example.com"
+
+	c, err := m.Generate(domain)
+	require.NoError(t, err)
+
+	jwt := proof(map[string]interface{}{"nonce": c.Value, "aud": []string{"https://other.example.com", domain}})
+
+	_, err = m.VerifyProofJWT(svc, "", jwt, domain)
+	require.NoError(t, err)
+
+	_, err = m.VerifyProofJWT(svc, "", jwt, domain)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	c, err = m.Generate(domain)
+	require.NoError(t, err)
+
+	_, err = m.VerifyProofJWT(svc, "", proof(map[string]interface{}{"nonce": c.Value, "aud": "other"}), domain)
+	require.ErrorIs(t, err, ErrDomainMismatch)
+
+	_, err = m.VerifyProofJWT(svc, "", proof(map[string]interface{}{"nonce": c.Value, "aud": 1}), domain)
+	require.ErrorIs(t, err, ErrDomainMismatch)
+
+	_, err = m.VerifyProofJWT(svc, "", proof(map[string]interface{}{"nonce": c.Value, "aud": domain}), domain)
+	require.NoError(t, err)
+
+	_, err = m.VerifyProofJWT(svc, "", proof(map[string]interface{}{"aud": domain}), domain)
+	require.ErrorContains(t, err, "no nonce claim")
+
+	signed, err := svc.SignJWS(keyID, nil, []byte("not json"))
+	require.NoError(t, err)
+
+	compact, err := signed.Compact(false)
+	require.NoError(t, err)
+
+	_, err = m.VerifyProofJWT(svc, "", compact, domain)
+	require.ErrorContains(t, err, "unmarshal proof claims")
+
+	_, err = m.VerifyProofJWT(svc, "", "invalid", domain)
+	require.Error(t, err)
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("no entropy")
+}
+
+type failingStore struct{}
+
+func (failingStore) Put(*Challenge) error {
+	return errors.New("unavailable")
+}
+
+func (failingStore) Consume(string) (*Challenge, error) {
+	return nil, errors.New("unavailable")
+}
@@ -0,0 +1,74 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package challenge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/kms-go/doc/jose/jws"
+)
+
+// proofClaims are the claims of a proof JWT binding it to a challenge.
+type proofClaims struct {
+	Nonce    string          `json:"nonce"`
+	Audience json.RawMessage `json:"aud,omitempty"`
+}
+
+// VerifyProofJWT verifies a proof of possession JWT (eg: an OpenID4VCI key proof or an SD-JWT key binding JWT) signed
+// with the KMS key keyID, or the key of its "kid" header if keyID is empty, then consumes the challenge of its "nonce"
+// claim for the verifier domain, which must be one of the "aud" claim values.
+func (m *Manager) VerifyProofJWT(svc *jws.Service, keyID, jwt, domain string) (*jws.JWS, error) {
+	verified, err := svc.VerifyJWS(keyID, jwt)
+	if err != nil {
+		return nil, fmt.Errorf("challenge: %w", err)
+	}
+
+	claims := &proofClaims{}
+
+	if err = json.Unmarshal(verified.Payload, claims); err != nil {
+		return nil, fmt.Errorf("challenge: unmarshal proof claims: %w", err)
+	}
+
+	if claims.Nonce == "" {
+		return nil, errors.New("challenge: proof has no nonce claim")
+	}
+
+	if domain != "" && !hasAudience(claims.Audience, domain) {
+		return nil, ErrDomainMismatch
+	}
+
+	if _, err = m.Consume(claims.Nonce, domain); err != nil {
+		return nil, err
+	}
+
+	return verified, nil
+}
+
+// hasAudience tells if the "aud" claim, a string or an array of strings, contains aud.
+func hasAudience(raw json.RawMessage, aud string) bool {
+	var single string
+
+	if json.Unmarshal(raw, &single) == nil {
+		return single == aud
+	}
+
+	var multiple []string
+
+	if json.Unmarshal(raw, &multiple) != nil {
+		return false
+	}
+
+	for _, a := range multiple {
+		if a == aud {
+			return true
+		}
+	}
+
+	return false
+}
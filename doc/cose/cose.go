@@ -0,0 +1,229 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package cose creates and verifies CBOR Object Signing and Encryption structures
+// (https://www.rfc-editor.org/rfc/rfc9052) with keys held by a KMS: COSE_Sign1 with signing keys and COSE_Encrypt0
+// with AEAD keys. The COSE algorithm is resolved from the KMS key type so callers only need to provide a key ID.
+package cose
+
+import (
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+// COSE header labels (https://www.rfc-editor.org/rfc/rfc9052#section-3.1).
+const (
+	HeaderAlgorithm   int64 = 1
+	HeaderCritical    int64 = 2
+	HeaderContentType int64 = 3
+	HeaderKeyID       int64 = 4
+	HeaderIV          int64 = 5
+	HeaderPartialIV   int64 = 6
+)
+
+// COSE algorithms (https://www.iana.org/assignments/cose/cose.xhtml#algorithms).
+const (
+	AlgES256            int64 = -7
+	AlgES384            int64 = -35
+	AlgES512            int64 = -36
+	AlgES256K           int64 = -47
+	AlgEdDSA            int64 = -8
+	AlgPS256            int64 = -37
+	AlgRS256            int64 = -257
+	AlgA128GCM          int64 = 1
+	AlgA256GCM          int64 = 3
+	AlgChaCha20Poly1305 int64 = 24
+)
+
+// CBOR tags of the COSE structures.
+const (
+	TagSign1    = 18
+	TagEncrypt0 = 16
+)
+
+// Service creates and verifies COSE structures using keys managed by a KeyManager and crypto operations from a Crypto
+// service.
+type Service struct {
+	km     kms.KeyManager
+	crypto crypto.Crypto
+}
+
+// New creates a new COSE Service.
+func New(km kms.KeyManager, c crypto.Crypto) *Service {
+	return &Service{km: km, crypto: c}
+}
+
+// Headers are COSE header parameters, keyed by their integer label.
+type Headers map[int64]interface{}
+
+// Algorithm returns the value of the alg header.
+func (h Headers) Algorithm() (int64, bool) {
+	switch alg := h[HeaderAlgorithm].(type) {
+	case int64:
+		return alg, true
+	case uint64:
+		return int64(alg), true //nolint:gosec
+	case int:
+		return int64(alg), true
+	default:
+		return 0, false
+	}
+}
+
+// KeyID returns the value of the kid header.
+func (h Headers) KeyID() (string, bool) {
+	kid, ok := h[HeaderKeyID].([]byte)
+
+	return string(kid), ok
+}
+
+// Opt is a COSE Service option.
+type Opt func(o *opts)
+
+type opts struct {
+	protected       Headers
+	unprotected     Headers
+	externalAAD     []byte
+	detachedPayload []byte
+	detached        bool
+}
+
+// WithProtectedHeaders adds headers to the protected bucket of the created structure.
+func WithProtectedHeaders(h Headers) Opt {
+	return func(o *opts) {
+		o.protected = h
+	}
+}
+
+// WithUnprotectedHeaders adds headers to the unprotected bucket of the created structure.
+func WithUnprotectedHeaders(h Headers) Opt {
+	return func(o *opts) {
+		o.unprotected = h
+	}
+}
+
+// WithExternalAAD sets the externally supplied data authenticated with the structure.
+func WithExternalAAD(aad []byte) Opt {
+	return func(o *opts) {
+		o.externalAAD = aad
+	}
+}
+
+// WithDetachedPayload creates a COSE_Sign1 without its payload (set to nil), or verifies a detached COSE_Sign1 with
+// payload.
+func WithDetachedPayload(payload []byte) Opt {
+	return func(o *opts) {
+		o.detached = true
+		o.detachedPayload = payload
+	}
+}
+
+func newOpts(options []Opt) *opts {
+	o := &opts{}
+
+	for _, opt := range options {
+		opt(o)
+	}
+
+	return o
+}
+
+// SignAlgorithmForKeyType returns the COSE algorithm of signatures created with keys of type kt.
+func SignAlgorithmForKeyType(kt kms.KeyType) (int64, error) {
+	switch kt { //nolint:exhaustive
+	case kms.ECDSAP256TypeDER, kms.ECDSAP256TypeIEEEP1363:
+		return AlgES256, nil
+	case kms.ECDSAP384TypeDER, kms.ECDSAP384TypeIEEEP1363:
+		return AlgES384, nil
+	case kms.ECDSAP521TypeDER, kms.ECDSAP521TypeIEEEP1363:
+		return AlgES512, nil
+	case kms.ECDSASecp256k1TypeDER, kms.ECDSASecp256k1TypeIEEEP1363:
+		return AlgES256K, nil
+	case kms.ED25519Type:
+		return AlgEdDSA, nil
+	case kms.RSAPS256Type:
+		return AlgPS256, nil
+	case kms.RSARS256Type:
+		return AlgRS256, nil
+	default:
+		return 0, fmt.Errorf("key type '%s' is not supported for COSE signatures", kt)
+	}
+}
+
+// EncryptAlgorithmForKeyType returns the COSE algorithm of content encrypted with keys of type kt.
+func EncryptAlgorithmForKeyType(kt kms.KeyType) (int64, error) {
+	switch kt { //nolint:exhaustive
+	case kms.AES128GCMType:
+		return AlgA128GCM, nil
+	case kms.AES256GCMType, kms.AES256GCMNoPrefixType:
+		return AlgA256GCM, nil
+	case kms.ChaCha20Poly1305Type:
+		return AlgChaCha20Poly1305, nil
+	default:
+		return 0, fmt.Errorf("key type '%s' is not supported for COSE encryption", kt)
+	}
+}
+
+//nolint:gochecknoglobals
+var (
+	encMode, _ = cbor.CoreDetEncOptions().EncMode()
+	decMode, _ = cbor.DecOptions{IntDec: cbor.IntDecConvertSigned}.DecMode()
+)
+
+// encodeProtected encodes protected headers as a bstr wrapped map, an empty map is encoded as a zero length bstr.
+func encodeProtected(h Headers) ([]byte, error) {
+	if len(h) == 0 {
+		return []byte{}, nil
+	}
+
+	return encMode.Marshal(h)
+}
+
+func decodeProtected(b []byte) (Headers, error) {
+	h := Headers{}
+
+	if len(b) == 0 {
+		return h, nil
+	}
+
+	if err := decMode.Unmarshal(b, &h); err != nil {
+		return nil, fmt.Errorf("decode protected headers: %w", err)
+	}
+
+	return h, nil
+}
+
+func mergeHeaders(dst, src Headers) Headers {
+	if dst == nil {
+		dst = Headers{}
+	}
+
+	for k, v := range src {
+		dst[k] = v
+	}
+
+	return dst
+}
+
+// unwrapTag returns the content of data tagged with tag, or data itself when untagged.
+func unwrapTag(data []byte, tag uint64) ([]byte, error) {
+	var raw cbor.RawTag
+
+	if err := decMode.Unmarshal(data, &raw); err != nil {
+		// not a tag
+		return data, nil //nolint:nilerr
+	}
+
+	if raw.Number != tag {
+		return nil, fmt.Errorf("unexpected CBOR tag %d", raw.Number)
+	}
+
+	return raw.Content, nil
+}
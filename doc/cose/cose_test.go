@@ -0,0 +1,231 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cose
+
+import (
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func newService(t *testing.T) (*Service, kmsapi.KeyManager) {
+	t.Helper()
+
+	km := mockkms.NewForTest(t)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	return New(km, cr), km
+}
+
+func TestSign1Verify1(t *testing.T) {
+	svc, km := newService(t)
+
+	tests := []struct {
+		kt  kmsapi.KeyType
+		alg int64
+	}{
+		{kmsapi.ECDSAP256TypeDER, AlgES256},
+		{kmsapi.ECDSAP256TypeIEEEP1363, AlgES256},
+		{kmsapi.ECDSAP384TypeDER, AlgES384},
+		{kmsapi.ECDSAP521TypeIEEEP1363, AlgES512},
+		{kmsapi.ECDSASecp256k1TypeIEEEP1363, AlgES256K},
+		{kmsapi.ED25519Type, AlgEdDSA},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.kt), func(t *testing.T) {
+			keyID, _, err := km.Create(tc.kt)
+			require.NoError(t, err)
+
+			payload := []byte("mdoc payload")
+
+			data, err := svc.Sign1(keyID, payload, WithExternalAAD([]byte("aad")))
+			require.NoError(t, err)
+
+			var tag cbor.RawTag
+			require.NoError(t, cbor.Unmarshal(data, &tag))
+			require.EqualValues(t, TagSign1, tag.Number)
+
+			msg, err := svc.Verify1("", data, WithExternalAAD([]byte("aad")))
+			require.NoError(t, err)
+			require.Equal(t, payload, msg.Payload)
+
+			alg, ok := msg.Protected.Algorithm()
+			require.True(t, ok)
+			require.Equal(t, tc.alg, alg)
+
+			kid, ok := msg.Protected.KeyID()
+			require.True(t, ok)
+			require.Equal(t, keyID, kid)
+
+			// untagged
+			_, err = svc.Verify1(keyID, tag.Content, WithExternalAAD([]byte("aad")))
+			require.NoError(t, err)
+
+			_, err = svc.Verify1(keyID, data, WithExternalAAD([]byte("other aad")))
+			require.ErrorContains(t, err, "verify")
+		})
+	}
+}
+
+func TestSign1Options(t *testing.T) {
+	svc, km := newService(t)
+
+	keyID, _, err := km.Create(kmsapi.ECDSAP256TypeIEEEP1363)
+	require.NoError(t, err)
+
+	t.Run("detached payload", func(t *testing.T) {
+		data, err := svc.Sign1(keyID, []byte("payload"), WithDetachedPayload(nil))
+		require.NoError(t, err)
+
+		msg, err := svc.Verify1(keyID, data, WithDetachedPayload([]byte("payload")))
+		require.NoError(t, err)
+		require.Equal(t, []byte("payload"), msg.Payload)
+
+		_, err = svc.Verify1(keyID, data)
+		require.ErrorContains(t, err, "missing detached payload")
+
+		_, err = svc.Verify1(keyID, data, WithDetachedPayload([]byte("other")))
+		require.Error(t, err)
+	})
+
+	t.Run("headers", func(t *testing.T) {
+		data, err := svc.Sign1(keyID, []byte("payload"),
+			WithProtectedHeaders(Headers{HeaderContentType: "application/cbor"}),
+			WithUnprotectedHeaders(Headers{HeaderKeyID: []byte("external kid")}))
+		require.NoError(t, err)
+
+		msg, err := svc.Verify1(keyID, data)
+		require.NoError(t, err)
+		require.Equal(t, "application/cbor", msg.Protected[HeaderContentType])
+		require.Equal(t, []byte("external kid"), msg.Unprotected[HeaderKeyID])
+
+		_, ok := msg.Protected.KeyID()
+		require.False(t, ok)
+
+		_, err = svc.Sign1(keyID, []byte("payload"), WithProtectedHeaders(Headers{HeaderAlgorithm: AlgEdDSA}))
+		require.ErrorContains(t, err, "does not match key algorithm")
+	})
+}
+
+func TestSign1Errors(t *testing.T) {
+	svc, km := newService(t)
+
+	p256ID, _, err := km.Create(kmsapi.ECDSAP256TypeDER)
+	require.NoError(t, err)
+
+	edID, _, err := km.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	aesID, _, err := km.Create(kmsapi.AES256GCMType)
+	require.NoError(t, err)
+
+	_, err = svc.Sign1("unknown", []byte("payload"))
+	require.Error(t, err)
+
+	_, err = svc.Sign1(aesID, []byte("payload"))
+	require.Error(t, err)
+
+	data, err := svc.Sign1(p256ID, []byte("payload"))
+	require.NoError(t, err)
+
+	_, err = svc.Verify1(edID, data)
+	require.ErrorContains(t, err, "does not match key algorithm")
+
+	_, err = svc.Verify1(p256ID, []byte("not cbor"))
+	require.Error(t, err)
+
+	wrongTag, err := cbor.Marshal(cbor.Tag{Number: TagEncrypt0, Content: []interface{}{}})
+	require.NoError(t, err)
+
+	_, err = svc.Verify1(p256ID, wrongTag)
+	require.ErrorContains(t, err, "unexpected CBOR tag")
+
+	noKID, err := svc.Sign1(p256ID, []byte("payload"), WithUnprotectedHeaders(Headers{HeaderKeyID: []byte{}}))
+	require.NoError(t, err)
+
+	_, err = svc.Verify1("", noKID)
+	require.ErrorContains(t, err, "missing key ID")
+}
+
+func TestEncrypt0Decrypt0(t *testing.T) {
+	svc, km := newService(t)
+
+	tests := []struct {
+		kt  kmsapi.KeyType
+		alg int64
+	}{
+		{kmsapi.AES128GCMType, AlgA128GCM},
+		{kmsapi.AES256GCMType, AlgA256GCM},
+		{kmsapi.AES256GCMNoPrefixType, AlgA256GCM},
+		{kmsapi.ChaCha20Poly1305Type, AlgChaCha20Poly1305},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.kt), func(t *testing.T) {
+			keyID, _, err := km.Create(tc.kt)
+			require.NoError(t, err)
+
+			data, err := svc.Encrypt0(keyID, []byte("secret"), WithExternalAAD([]byte("aad")))
+			require.NoError(t, err)
+
+			msg, err := svc.Decrypt0("", data, WithExternalAAD([]byte("aad")))
+			require.NoError(t, err)
+			require.Equal(t, []byte("secret"), msg.Plaintext)
+
+			alg, ok := msg.Protected.Algorithm()
+			require.True(t, ok)
+			require.Equal(t, tc.alg, alg)
+			require.NotEmpty(t, msg.Unprotected[HeaderIV])
+
+			_, err = svc.Decrypt0(keyID, data, WithExternalAAD([]byte("other aad")))
+			require.ErrorContains(t, err, "decrypt")
+		})
+	}
+}
+
+func TestEncrypt0Errors(t *testing.T) {
+	svc, km := newService(t)
+
+	aes128ID, _, err := km.Create(kmsapi.AES128GCMType)
+	require.NoError(t, err)
+
+	aes256ID, _, err := km.Create(kmsapi.AES256GCMType)
+	require.NoError(t, err)
+
+	p256ID, _, err := km.Create(kmsapi.ECDSAP256TypeDER)
+	require.NoError(t, err)
+
+	_, err = svc.Encrypt0("unknown", []byte("secret"))
+	require.Error(t, err)
+
+	_, err = svc.Encrypt0(p256ID, []byte("secret"))
+	require.ErrorContains(t, err, "not supported for COSE encryption")
+
+	data, err := svc.Encrypt0(aes128ID, []byte("secret"))
+	require.NoError(t, err)
+
+	_, err = svc.Decrypt0(aes256ID, data)
+	require.ErrorContains(t, err, "does not match key algorithm")
+
+	_, err = svc.Decrypt0(aes128ID, []byte("not cbor"))
+	require.Error(t, err)
+
+	noIV, err := encMode.Marshal(&encrypt0{Protected: []byte{}, Unprotected: Headers{}, Ciphertext: []byte("ct")})
+	require.NoError(t, err)
+
+	_, err = svc.Decrypt0(aes128ID, noIV)
+	require.Error(t, err)
+}
@@ -0,0 +1,190 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cose
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	gcmpb "github.com/google/tink/go/proto/aes_gcm_go_proto"
+
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+const (
+	encrypt0Context = "Encrypt0"
+
+	aesGCMTypeURL           = "type.googleapis.com/google.crypto.tink.AesGcmKey"
+	chaCha20Poly1305TypeURL = "type.googleapis.com/google.crypto.tink.ChaCha20Poly1305Key"
+)
+
+// Encrypt0Message is a decrypted COSE_Encrypt0 structure.
+type Encrypt0Message struct {
+	Protected   Headers
+	Unprotected Headers
+	Plaintext   []byte
+}
+
+type encrypt0 struct {
+	_           struct{} `cbor:",toarray"`
+	Protected   []byte
+	Unprotected Headers
+	Ciphertext  []byte
+}
+
+// Encrypt0 encrypts plaintext with the AEAD key keyID and returns the tagged COSE_Encrypt0 structure. The alg
+// protected header is set from the key type and the IV unprotected header is set to the nonce used. kid is set to keyID
+// unless already present in the headers.
+func (s *Service) Encrypt0(keyID string, plaintext []byte, options ...Opt) ([]byte, error) {
+	o := newOpts(options)
+
+	kh, err := s.km.Get(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("cose encrypt0: get key: %w", err)
+	}
+
+	alg, err := encryptAlgorithm(kh)
+	if err != nil {
+		return nil, fmt.Errorf("cose encrypt0: %w", err)
+	}
+
+	protected, err := buildProtected(o, alg, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("cose encrypt0: %w", err)
+	}
+
+	aad, err := encStructure(protected, o.externalAAD)
+	if err != nil {
+		return nil, fmt.Errorf("cose encrypt0: %w", err)
+	}
+
+	ct, nonce, err := s.crypto.Encrypt(plaintext, aad, kh)
+	if err != nil {
+		return nil, fmt.Errorf("cose encrypt0: encrypt: %w", err)
+	}
+
+	unprotected := mergeHeaders(nil, o.unprotected)
+	unprotected[HeaderIV] = nonce
+
+	return encMode.Marshal(cbor.Tag{Number: TagEncrypt0, Content: &encrypt0{
+		Protected:   protected,
+		Unprotected: unprotected,
+		Ciphertext:  ct,
+	}})
+}
+
+// Decrypt0 parses and decrypts a tagged or untagged COSE_Encrypt0 structure. If keyID is empty, the kid header is used
+// to select the KMS key. The key algorithm must match the alg protected header.
+func (s *Service) Decrypt0(keyID string, data []byte, options ...Opt) (*Encrypt0Message, error) {
+	o := newOpts(options)
+
+	content, err := unwrapTag(data, TagEncrypt0)
+	if err != nil {
+		return nil, fmt.Errorf("cose decrypt0: %w", err)
+	}
+
+	raw := &encrypt0{}
+
+	if err = decMode.Unmarshal(content, raw); err != nil {
+		return nil, fmt.Errorf("cose decrypt0: decode COSE_Encrypt0: %w", err)
+	}
+
+	protected, err := decodeProtected(raw.Protected)
+	if err != nil {
+		return nil, fmt.Errorf("cose decrypt0: %w", err)
+	}
+
+	unprotected := mergeHeaders(nil, raw.Unprotected)
+
+	kid := resolveKeyID(keyID, protected, unprotected)
+	if kid == "" {
+		return nil, errors.New("cose decrypt0: missing key ID")
+	}
+
+	kh, err := s.km.Get(kid)
+	if err != nil {
+		return nil, fmt.Errorf("cose decrypt0: get key: %w", err)
+	}
+
+	alg, err := encryptAlgorithm(kh)
+	if err != nil {
+		return nil, fmt.Errorf("cose decrypt0: %w", err)
+	}
+
+	if hAlg, _ := protected.Algorithm(); hAlg != alg {
+		return nil, fmt.Errorf("cose decrypt0: alg header %d does not match key algorithm %d", hAlg, alg)
+	}
+
+	iv, ok := unprotected[HeaderIV].([]byte)
+	if !ok {
+		return nil, errors.New("cose decrypt0: missing IV header")
+	}
+
+	aad, err := encStructure(raw.Protected, o.externalAAD)
+	if err != nil {
+		return nil, fmt.Errorf("cose decrypt0: %w", err)
+	}
+
+	pt, err := s.crypto.Decrypt(raw.Ciphertext, aad, iv, kh)
+	if err != nil {
+		return nil, fmt.Errorf("cose decrypt0: decrypt: %w", err)
+	}
+
+	return &Encrypt0Message{Protected: protected, Unprotected: unprotected, Plaintext: pt}, nil
+}
+
+// encStructure returns the Enc_structure used as AEAD additional data
+// (https://www.rfc-editor.org/rfc/rfc9052#section-5.3).
+func encStructure(protected, externalAAD []byte) ([]byte, error) {
+	if externalAAD == nil {
+		externalAAD = []byte{}
+	}
+
+	return encMode.Marshal([]interface{}{encrypt0Context, protected, externalAAD})
+}
+
+// encryptAlgorithm returns the COSE algorithm of an AEAD key handle. Key handles are not bound to a KMS key type, so
+// for Tink keysets the type is read from the primary key (and its size for AES-GCM keys).
+func encryptAlgorithm(kh interface{}) (int64, error) {
+	ksh, ok := kh.(*keyset.Handle)
+	if !ok {
+		return 0, fmt.Errorf("unsupported key handle type %T", kh)
+	}
+
+	ks := insecurecleartextkeyset.KeysetMaterial(ksh)
+
+	for _, k := range ks.Key {
+		if k.KeyId != ks.PrimaryKeyId {
+			continue
+		}
+
+		switch k.KeyData.TypeUrl {
+		case chaCha20Poly1305TypeURL:
+			return EncryptAlgorithmForKeyType(kms.ChaCha20Poly1305Type)
+		case aesGCMTypeURL:
+			gcmKey := &gcmpb.AesGcmKey{}
+
+			if err := proto.Unmarshal(k.KeyData.Value, gcmKey); err != nil {
+				return 0, fmt.Errorf("unmarshal AES-GCM key: %w", err)
+			}
+
+			if len(gcmKey.KeyValue) == 16 { //nolint:gomnd
+				return EncryptAlgorithmForKeyType(kms.AES128GCMType)
+			}
+
+			return EncryptAlgorithmForKeyType(kms.AES256GCMType)
+		default:
+			return 0, fmt.Errorf("key type '%s' is not supported for COSE encryption", k.KeyData.TypeUrl)
+		}
+	}
+
+	return 0, errors.New("primary key not found")
+}
@@ -0,0 +1,254 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cose
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/google/tink/go/keyset"
+
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+const sign1Context = "Signature1"
+
+// Sign1Message is a verified COSE_Sign1 structure.
+type Sign1Message struct {
+	Protected   Headers
+	Unprotected Headers
+	Payload     []byte
+	Signature   []byte
+}
+
+type sign1 struct {
+	_           struct{} `cbor:",toarray"`
+	Protected   []byte
+	Unprotected Headers
+	Payload     []byte
+	Signature   []byte
+}
+
+// Sign1 signs payload with the key keyID and returns the tagged COSE_Sign1 structure. The alg protected header is set
+// from the key type and kid is set to keyID unless already present in the headers. Headers may not set an alg
+// different from the key's algorithm.
+func (s *Service) Sign1(keyID string, payload []byte, options ...Opt) ([]byte, error) {
+	o := newOpts(options)
+
+	kt, alg, err := s.signAlgorithm(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("cose sign1: %w", err)
+	}
+
+	protected, err := buildProtected(o, alg, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("cose sign1: %w", err)
+	}
+
+	msg := &sign1{Protected: protected, Unprotected: mergeHeaders(nil, o.unprotected), Payload: payload}
+
+	if o.detached {
+		msg.Payload = nil
+	}
+
+	toBeSigned, err := sigStructure(protected, o.externalAAD, payload)
+	if err != nil {
+		return nil, fmt.Errorf("cose sign1: %w", err)
+	}
+
+	kh, err := s.km.Get(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("cose sign1: get key: %w", err)
+	}
+
+	sig, err := s.crypto.Sign(toBeSigned, kh)
+	if err != nil {
+		return nil, fmt.Errorf("cose sign1: sign: %w", err)
+	}
+
+	msg.Signature, err = toCOSESignature(kt, sig)
+	if err != nil {
+		return nil, fmt.Errorf("cose sign1: %w", err)
+	}
+
+	return encMode.Marshal(cbor.Tag{Number: TagSign1, Content: msg})
+}
+
+// Verify1 parses and verifies a tagged or untagged COSE_Sign1 structure. If keyID is empty, the kid header is used to
+// select the KMS key. The key algorithm must match the alg protected header. Use WithDetachedPayload to verify a
+// COSE_Sign1 created without its payload.
+func (s *Service) Verify1(keyID string, data []byte, options ...Opt) (*Sign1Message, error) {
+	o := newOpts(options)
+
+	msg, payload, err := parseSign1(data, o)
+	if err != nil {
+		return nil, fmt.Errorf("cose verify1: %w", err)
+	}
+
+	kid := resolveKeyID(keyID, msg.Protected, msg.Unprotected)
+	if kid == "" {
+		return nil, errors.New("cose verify1: missing key ID")
+	}
+
+	kt, alg, err := s.signAlgorithm(kid)
+	if err != nil {
+		return nil, fmt.Errorf("cose verify1: %w", err)
+	}
+
+	if hAlg, _ := msg.Protected.Algorithm(); hAlg != alg {
+		return nil, fmt.Errorf("cose verify1: alg header %d does not match key algorithm %d", hAlg, alg)
+	}
+
+	toBeSigned, err := sigStructure(msg.raw.Protected, o.externalAAD, payload)
+	if err != nil {
+		return nil, fmt.Errorf("cose verify1: %w", err)
+	}
+
+	kh, err := s.publicKeyHandle(kid)
+	if err != nil {
+		return nil, fmt.Errorf("cose verify1: %w", err)
+	}
+
+	sig, err := fromCOSESignature(kt, msg.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("cose verify1: %w", err)
+	}
+
+	if err = s.crypto.Verify(sig, toBeSigned, kh); err != nil {
+		return nil, fmt.Errorf("cose verify1: verify: %w", err)
+	}
+
+	msg.Payload = payload
+
+	return &msg.Sign1Message, nil
+}
+
+type parsedSign1 struct {
+	Sign1Message
+	raw *sign1
+}
+
+func parseSign1(data []byte, o *opts) (*parsedSign1, []byte, error) {
+	content, err := unwrapTag(data, TagSign1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw := &sign1{}
+
+	if err = decMode.Unmarshal(content, raw); err != nil {
+		return nil, nil, fmt.Errorf("decode COSE_Sign1: %w", err)
+	}
+
+	protected, err := decodeProtected(raw.Protected)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payload := raw.Payload
+
+	if o.detached {
+		if raw.Payload != nil {
+			return nil, nil, errors.New("detached payload set for a COSE_Sign1 with payload")
+		}
+
+		payload = o.detachedPayload
+	} else if raw.Payload == nil {
+		return nil, nil, errors.New("missing detached payload")
+	}
+
+	return &parsedSign1{
+		Sign1Message: Sign1Message{
+			Protected:   protected,
+			Unprotected: mergeHeaders(nil, raw.Unprotected),
+			Payload:     raw.Payload,
+			Signature:   raw.Signature,
+		},
+		raw: raw,
+	}, payload, nil
+}
+
+// sigStructure returns the Sig_structure to sign (https://www.rfc-editor.org/rfc/rfc9052#section-4.4).
+func sigStructure(protected, externalAAD, payload []byte) ([]byte, error) {
+	if externalAAD == nil {
+		externalAAD = []byte{}
+	}
+
+	if payload == nil {
+		payload = []byte{}
+	}
+
+	return encMode.Marshal([]interface{}{sign1Context, protected, externalAAD, payload})
+}
+
+// buildProtected returns the encoded protected headers set with o, alg and kid (unless already set).
+func buildProtected(o *opts, alg int64, keyID string) ([]byte, error) {
+	protected := mergeHeaders(nil, o.protected)
+
+	if hAlg, ok := protected.Algorithm(); ok && hAlg != alg {
+		return nil, fmt.Errorf("alg header %d does not match key algorithm %d", hAlg, alg)
+	}
+
+	protected[HeaderAlgorithm] = alg
+
+	_, inUnprotected := o.unprotected[HeaderKeyID]
+	if _, ok := protected[HeaderKeyID]; !ok && !inUnprotected && keyID != "" {
+		protected[HeaderKeyID] = []byte(keyID)
+	}
+
+	return encodeProtected(protected)
+}
+
+func resolveKeyID(keyID string, protected, unprotected Headers) string {
+	if keyID != "" {
+		return keyID
+	}
+
+	if kid, ok := protected.KeyID(); ok {
+		return kid
+	}
+
+	kid, _ := unprotected.KeyID()
+
+	return kid
+}
+
+func (s *Service) signAlgorithm(keyID string) (kms.KeyType, int64, error) {
+	_, kt, err := s.km.ExportPubKeyBytes(keyID)
+	if err != nil {
+		return "", 0, fmt.Errorf("export public key: %w", err)
+	}
+
+	alg, err := SignAlgorithmForKeyType(kt)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return kt, alg, nil
+}
+
+// publicKeyHandle returns the key handle of keyID to verify signatures with. Tink keyset handles are converted to
+// public keyset handles as required by Tink verifiers.
+func (s *Service) publicKeyHandle(keyID string) (interface{}, error) {
+	kh, err := s.km.Get(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("get key: %w", err)
+	}
+
+	ksh, ok := kh.(*keyset.Handle)
+	if !ok {
+		return kh, nil
+	}
+
+	pubKH, err := ksh.Public()
+	if err != nil {
+		return nil, fmt.Errorf("get public key handle: %w", err)
+	}
+
+	return pubKH, nil
+}
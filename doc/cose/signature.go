@@ -0,0 +1,23 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cose
+
+import (
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/util/sigencoding"
+)
+
+// toCOSESignature converts a KMS signature to the COSE format: COSE requires ECDSA signatures in IEEE-P1363 format
+// (R || S), DER encoded signatures are converted.
+func toCOSESignature(kt kms.KeyType, sig []byte) ([]byte, error) {
+	return sigencoding.Convert(sig, kt, sigencoding.IEEEP1363)
+}
+
+// fromCOSESignature converts a COSE signature to the format expected for a KMS key of type kt.
+func fromCOSESignature(kt kms.KeyType, sig []byte) ([]byte, error) {
+	return sigencoding.ConvertToKeyType(sig, kt, sigencoding.IEEEP1363)
+}
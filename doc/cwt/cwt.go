@@ -0,0 +1,298 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package cwt creates and verifies CBOR Web Tokens (https://www.rfc-editor.org/rfc/rfc8392) signed as COSE_Sign1
+// structures with KMS keys (see doc/cose), eg: with ES256 (ECDSA P-256) or EdDSA (Ed25519) keys.
+package cwt
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/trustbloc/kms-go/doc/cose"
+)
+
+// CWT claim keys (https://www.rfc-editor.org/rfc/rfc8392#section-4).
+const (
+	ClaimIssuer     int64 = 1
+	ClaimSubject    int64 = 2
+	ClaimAudience   int64 = 3
+	ClaimExpiration int64 = 4
+	ClaimNotBefore  int64 = 5
+	ClaimIssuedAt   int64 = 6
+	ClaimCWTID      int64 = 7
+)
+
+// TagCWT is the CBOR tag of a CWT.
+const TagCWT = 61
+
+// Validation errors.
+var (
+	ErrExpired         = errors.New("cwt: token is expired")
+	ErrNotYetValid     = errors.New("cwt: token is not valid yet")
+	ErrInvalidAudience = errors.New("cwt: invalid audience")
+)
+
+// Claims are the CWT claims. Times are NumericDate values, in seconds since the Unix epoch, zero when not set.
+type Claims struct {
+	Issuer     string
+	Subject    string
+	Audience   string
+	Expiration int64
+	NotBefore  int64
+	IssuedAt   int64
+	CWTID      []byte
+	// Private are the other claims, keyed by their integer claim key.
+	Private map[int64]interface{}
+}
+
+//nolint:gochecknoglobals
+var (
+	encMode, _ = cbor.CoreDetEncOptions().EncMode()
+	decMode, _ = cbor.DecOptions{IntDec: cbor.IntDecConvertSigned}.DecMode()
+)
+
+// MarshalCBOR encodes the claims as a CWT claims set.
+func (c *Claims) MarshalCBOR() ([]byte, error) {
+	m := make(map[int64]interface{}, len(c.Private)+7) //nolint:gomnd
+
+	for k, v := range c.Private {
+		m[k] = v
+	}
+
+	setString(m, ClaimIssuer, c.Issuer)
+	setString(m, ClaimSubject, c.Subject)
+	setString(m, ClaimAudience, c.Audience)
+	setTime(m, ClaimExpiration, c.Expiration)
+	setTime(m, ClaimNotBefore, c.NotBefore)
+	setTime(m, ClaimIssuedAt, c.IssuedAt)
+
+	if len(c.CWTID) > 0 {
+		m[ClaimCWTID] = c.CWTID
+	}
+
+	return encMode.Marshal(m)
+}
+
+// UnmarshalCBOR decodes a CWT claims set.
+func (c *Claims) UnmarshalCBOR(data []byte) error {
+	m := map[int64]interface{}{}
+
+	if err := decMode.Unmarshal(data, &m); err != nil {
+		return err
+	}
+
+	var err error
+
+	*c = Claims{}
+
+	for k, v := range m {
+		switch k {
+		case ClaimIssuer:
+			c.Issuer, err = stringClaim(k, v)
+		case ClaimSubject:
+			c.Subject, err = stringClaim(k, v)
+		case ClaimAudience:
+			c.Audience, err = stringClaim(k, v)
+		case ClaimExpiration:
+			c.Expiration, err = timeClaim(k, v)
+		case ClaimNotBefore:
+			c.NotBefore, err = timeClaim(k, v)
+		case ClaimIssuedAt:
+			c.IssuedAt, err = timeClaim(k, v)
+		case ClaimCWTID:
+			var ok bool
+			if c.CWTID, ok = v.([]byte); !ok {
+				err = fmt.Errorf("claim %d is not a byte string", k)
+			}
+		default:
+			if c.Private == nil {
+				c.Private = map[int64]interface{}{}
+			}
+
+			c.Private[k] = v
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func setString(m map[int64]interface{}, k int64, v string) {
+	if v != "" {
+		m[k] = v
+	}
+}
+
+func setTime(m map[int64]interface{}, k, v int64) {
+	if v != 0 {
+		m[k] = v
+	}
+}
+
+func stringClaim(k int64, v interface{}) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("claim %d is not a text string", k)
+	}
+
+	return s, nil
+}
+
+func timeClaim(k int64, v interface{}) (int64, error) {
+	switch t := v.(type) {
+	case int64:
+		return t, nil
+	case float64:
+		return int64(t), nil
+	default:
+		return 0, fmt.Errorf("claim %d is not a NumericDate", k)
+	}
+}
+
+// Service signs and verifies CWTs with a COSE Service.
+type Service struct {
+	cose *cose.Service
+}
+
+// New creates a new CWT Service.
+func New(c *cose.Service) *Service {
+	return &Service{cose: c}
+}
+
+// SignOpt is a Sign option.
+type SignOpt func(o *signOpts)
+
+type signOpts struct {
+	tagged bool
+	cose   []cose.Opt
+}
+
+// WithCWTTag wraps the COSE_Sign1 structure in the CWT CBOR tag (61).
+func WithCWTTag() SignOpt {
+	return func(o *signOpts) {
+		o.tagged = true
+	}
+}
+
+// WithCOSEOptions sets options (eg: headers) of the COSE_Sign1 structure.
+func WithCOSEOptions(opts ...cose.Opt) SignOpt {
+	return func(o *signOpts) {
+		o.cose = append(o.cose, opts...)
+	}
+}
+
+// Sign signs claims with the KMS key keyID and returns the CWT.
+func (s *Service) Sign(keyID string, claims *Claims, options ...SignOpt) ([]byte, error) {
+	o := &signOpts{}
+
+	for _, opt := range options {
+		opt(o)
+	}
+
+	payload, err := claims.MarshalCBOR()
+	if err != nil {
+		return nil, fmt.Errorf("cwt: marshal claims: %w", err)
+	}
+
+	sign1, err := s.cose.Sign1(keyID, payload, o.cose...)
+	if err != nil {
+		return nil, fmt.Errorf("cwt: %w", err)
+	}
+
+	if !o.tagged {
+		return sign1, nil
+	}
+
+	return encMode.Marshal(cbor.RawTag{Number: TagCWT, Content: sign1})
+}
+
+// VerifyOpt is a Verify option.
+type VerifyOpt func(o *verifyOpts)
+
+type verifyOpts struct {
+	audience string
+	now      func() time.Time
+	leeway   time.Duration
+}
+
+// WithAudience requires the aud claim to be aud.
+func WithAudience(aud string) VerifyOpt {
+	return func(o *verifyOpts) {
+		o.audience = aud
+	}
+}
+
+// WithLeeway sets the clock skew tolerated when validating the exp and nbf claims.
+func WithLeeway(d time.Duration) VerifyOpt {
+	return func(o *verifyOpts) {
+		o.leeway = d
+	}
+}
+
+// WithClock sets the current time source used to validate the exp and nbf claims, time.Now by default.
+func WithClock(now func() time.Time) VerifyOpt {
+	return func(o *verifyOpts) {
+		o.now = now
+	}
+}
+
+// Verify verifies the COSE_Sign1 signature of a CWT (tagged or not) with the KMS key keyID, or the key of its kid
+// header if keyID is empty, then validates its exp, nbf and aud claims.
+func (s *Service) Verify(keyID string, data []byte, options ...VerifyOpt) (*Claims, error) {
+	o := &verifyOpts{now: time.Now}
+
+	for _, opt := range options {
+		opt(o)
+	}
+
+	var raw cbor.RawTag
+
+	if err := decMode.Unmarshal(data, &raw); err == nil && raw.Number == TagCWT {
+		data = raw.Content
+	}
+
+	msg, err := s.cose.Verify1(keyID, data)
+	if err != nil {
+		return nil, fmt.Errorf("cwt: %w", err)
+	}
+
+	claims := &Claims{}
+
+	if err = claims.UnmarshalCBOR(msg.Payload); err != nil {
+		return nil, fmt.Errorf("cwt: unmarshal claims: %w", err)
+	}
+
+	if err = validate(claims, o); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func validate(c *Claims, o *verifyOpts) error {
+	now := o.now()
+
+	if c.Expiration != 0 && !now.Before(time.Unix(c.Expiration, 0).Add(o.leeway)) {
+		return ErrExpired
+	}
+
+	if c.NotBefore != 0 && now.Add(o.leeway).Before(time.Unix(c.NotBefore, 0)) {
+		return ErrNotYetValid
+	}
+
+	if o.audience != "" && c.Audience != o.audience {
+		return ErrInvalidAudience
+	}
+
+	return nil
+}
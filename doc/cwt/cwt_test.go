@@ -0,0 +1,146 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/doc/cose"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func newService(t *testing.T) (*Service, kmsapi.KeyManager) {
+	t.Helper()
+
+	km := mockkms.NewForTest(t)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	return New(cose.New(km, cr)), km
+}
+
+func TestSignVerify(t *testing.T) {
+	svc, km := newService(t)
+
+	now := time.Unix(1700000000, 0)
+	clock := func() time.Time { return now }
+
+	claims := &Claims{
+		Issuer:     "coap://as.example.com",
+		Subject:    "erikw",
+		Audience:   "coap://light.example.com",
+		Expiration: now.Add(time.Hour).Unix(),
+		NotBefore:  now.Add(-time.Minute).Unix(),
+		IssuedAt:   now.Unix(),
+		CWTID:      []byte{0x0b, 0x71},
+		Private:    map[int64]interface{}{-70000: "private"},
+	}
+
+	for _, kt := range []kmsapi.KeyType{kmsapi.ECDSAP256TypeIEEEP1363, kmsapi.ED25519Type} {
+		t.Run(string(kt), func(t *testing.T) {
+			keyID, _, err := km.Create(kt)
+			require.NoError(t, err)
+
+			token, err := svc.Sign(keyID, claims)
+			require.NoError(t, err)
+
+			verified, err := svc.Verify("", token, WithClock(clock), WithAudience("coap://light.example.com"))
+			require.NoError(t, err)
+			require.Equal(t, claims, verified)
+
+			tagged, err := svc.Sign(keyID, claims, WithCWTTag(),
+				WithCOSEOptions(cose.WithProtectedHeaders(cose.Headers{cose.HeaderContentType: "application/cwt"})))
+			require.NoError(t, err)
+
+			var tag cbor.RawTag
+			require.NoError(t, cbor.Unmarshal(tagged, &tag))
+			require.EqualValues(t, TagCWT, tag.Number)
+
+			verified, err = svc.Verify(keyID, tagged, WithClock(clock))
+			require.NoError(t, err)
+			require.Equal(t, claims, verified)
+		})
+	}
+}
+
+func TestVerifyClaims(t *testing.T) {
+	svc, km := newService(t)
+
+	keyID, _, err := km.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+
+	token, err := svc.Sign(keyID, &Claims{
+		Audience:   "aud",
+		Expiration: now.Unix(),
+		NotBefore:  now.Add(-time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+
+	at := func(t time.Time) VerifyOpt {
+		return WithClock(func() time.Time { return t })
+	}
+
+	_, err = svc.Verify(keyID, token, at(now))
+	require.ErrorIs(t, err, ErrExpired)
+
+	_, err = svc.Verify(keyID, token, at(now), WithLeeway(time.Minute))
+	require.NoError(t, err)
+
+	_, err = svc.Verify(keyID, token, at(now.Add(-2*time.Hour)))
+	require.ErrorIs(t, err, ErrNotYetValid)
+
+	_, err = svc.Verify(keyID, token, at(now.Add(-time.Hour-time.Second)), WithLeeway(time.Minute))
+	require.NoError(t, err)
+
+	_, err = svc.Verify(keyID, token, at(now.Add(-time.Minute)), WithAudience("other"))
+	require.ErrorIs(t, err, ErrInvalidAudience)
+}
+
+func TestErrors(t *testing.T) {
+	svc, km := newService(t)
+
+	keyID, _, err := km.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	_, err = svc.Sign("unknown", &Claims{})
+	require.Error(t, err)
+
+	_, err = svc.Sign(keyID, &Claims{Private: map[int64]interface{}{100: make(chan int)}})
+	require.ErrorContains(t, err, "marshal claims")
+
+	_, err = svc.Verify(keyID, []byte("not a cwt"))
+	require.Error(t, err)
+
+	token, err := svc.Sign(keyID, &Claims{Private: map[int64]interface{}{ClaimIssuer: 1}})
+	require.NoError(t, err)
+
+	_, err = svc.Verify(keyID, token)
+	require.ErrorContains(t, err, "claim 1 is not a text string")
+
+	for _, c := range []map[int64]interface{}{{ClaimExpiration: "soon"}, {ClaimCWTID: "id"}} {
+		token, err = svc.Sign(keyID, &Claims{Private: c})
+		require.NoError(t, err)
+
+		_, err = svc.Verify(keyID, token)
+		require.ErrorContains(t, err, "unmarshal claims")
+	}
+
+	claims := &Claims{}
+	require.Error(t, claims.UnmarshalCBOR([]byte{0x01}))
+
+	require.NoError(t, claims.UnmarshalCBOR([]byte{0xa1, 0x04, 0xfb, 0x41, 0xd9, 0x54, 0xfc, 0x40, 0x00, 0x00, 0x00}))
+	require.EqualValues(t, 1700000000, claims.Expiration)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package packer packs and unpacks DIDComm v2 encrypted messages
+// (https://identity.foundation/didcomm-messaging/spec/#didcomm-encrypted-messages): anoncrypt messages are encrypted with ECDH-ES+A256KW key wrapping and authcrypt messages with ECDH-1PU+A256KW, the
+// content being encrypted with A256CBC-HS512. X25519 recipients are wrapped with the XC20PKW variants of these
+// algorithms, the only X25519 key wrapping of the crypto package.
+//
+// Recipients are given as public JWKs, their "kid" being the recipient key ID of the message. Unpacking looks the
+// recipient keys up in the Packer's KMS by these key IDs.
+package packer
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/trustbloc/kms-go/doc/jose"
+	"github.com/trustbloc/kms-go/doc/jose/jwe"
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+const (
+	// MediaTypeEncrypted is the "typ" header of DIDComm v2 encrypted messages.
+	MediaTypeEncrypted = "application/didcomm-encrypted+json"
+	// MediaTypePlaintext is the media type of DIDComm v2 plaintext messages, the default "cty" of packed messages.
+	MediaTypePlaintext = "application/didcomm-plain+json"
+	// MediaTypeSigned is the media type of DIDComm v2 signed messages, the "cty" of packed signed messages.
+	MediaTypeSigned = "application/didcomm-signed+json"
+)
+
+// Packer packs and unpacks DIDComm v2 encrypted messages with the keys of a KeyManager.
+type Packer struct {
+	km     kms.KeyManager
+	crypto crypto.Crypto
+	cty    string
+}
+
+// Opt is a Packer option.
+type Opt func(p *Packer)
+
+// WithContentType sets the "cty" header of packed messages, MediaTypePlaintext by default.
+func WithContentType(cty string) Opt {
+	return func(p *Packer) {
+		p.cty = cty
+	}
+}
+
+// New creates a Packer using the keys of km and the key wrapping of c.
+func New(km kms.KeyManager, c crypto.Crypto, opts ...Opt) *Packer {
+	p := &Packer{km: km, crypto: c, cty: MediaTypePlaintext}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Envelope is an unpacked DIDComm v2 encrypted message.
+type Envelope struct {
+	// Message is the decrypted message.
+	Message []byte
+	// ContentType is the "cty" header of the message, eg: MediaTypePlaintext.
+	ContentType string
+	// SenderKID is the "skid" of authcrypt messages, empty for anoncrypt messages.
+	SenderKID string
+}
+
+// PackAnoncrypt packs msg for recipients as an anoncrypt message in the JWE JSON serialization.
+func (p *Packer) PackAnoncrypt(msg []byte, recipients ...*jwk.JWK) ([]byte, error) {
+	return p.pack(msg, "", recipients)
+}
+
+// PackAuthcrypt packs msg for recipients as an authcrypt message in the JWE JSON serialization. senderKID is the ID of
+// the sender ECDH key in the Packer's KMS, it is set as the message "skid" so recipients must be able to resolve it to
+// the sender public key (see Unpack), eg: a DID URL or the KMS key ID.
+func (p *Packer) PackAuthcrypt(msg []byte, senderKID string, recipients ...*jwk.JWK) ([]byte, error) {
+	if senderKID == "" {
+		return nil, errors.New("pack authcrypt: sender key ID is empty")
+	}
+
+	return p.pack(msg, senderKID, recipients)
+}
+
+func (p *Packer) pack(msg []byte, senderKID string, recipients []*jwk.JWK) ([]byte, error) {
+	if len(recipients) == 0 {
+		return nil, errors.New("pack: no recipients")
+	}
+
+	recs := make([]jwe.Recipient, 0, len(recipients))
+	kids := make([]string, 0, len(recipients))
+
+	for i, r := range recipients {
+		if r == nil || r.KeyID == "" {
+			return nil, fmt.Errorf("pack: recipient %d: missing JWK or kid", i+1)
+		}
+
+		recs = append(recs, jwe.Recipient{JWK: r})
+		kids = append(kids, r.KeyID)
+	}
+
+	opts := []jwe.EncrypterOpt{
+		jwe.WithContentEncryption(jose.A256CBCHS512),
+		jwe.WithMediaType(MediaTypeEncrypted),
+		jwe.WithContentType(p.cty),
+	}
+
+	if senderKID != "" {
+		opts = append(opts, jwe.WithSender(senderKID))
+	} else {
+		opts = append(opts, jwe.WithAgreementPartyInfo(nil, recipientsAPV(kids)))
+	}
+
+	env, err := jwe.NewEncrypter(p.km, p.crypto, opts...).Encrypt(msg, nil, recs...)
+	if err != nil {
+		return nil, fmt.Errorf("pack: %w", err)
+	}
+
+	s, err := env.FullSerialize(json.Marshal)
+	if err != nil {
+		return nil, fmt.Errorf("pack: %w", err)
+	}
+
+	return []byte(s), nil
+}
+
+// Unpack decrypts the DIDComm v2 encrypted message envelope, in the JWE JSON or compact serialization, with a
+// recipient key of the Packer's KMS. The "skid" of authcrypt messages is resolved to the JWK with the same "kid" among
+// senders, then as a key ID of the Packer's KMS.
+func (p *Packer) Unpack(envelope []byte, senders ...*jwk.JWK) (*Envelope, error) {
+	env, err := jose.Deserialize(string(envelope))
+	if err != nil {
+		return nil, fmt.Errorf("unpack: %w", err)
+	}
+
+	if typ, ok := env.ProtectedHeaders.Type(); ok && typ != MediaTypeEncrypted {
+		return nil, fmt.Errorf("unpack: unsupported media type '%s'", typ)
+	}
+
+	skid, err := senderKID(env)
+	if err != nil {
+		return nil, fmt.Errorf("unpack: %w", err)
+	}
+
+	if enc, _ := env.ProtectedHeaders.Encryption(); skid != "" && enc != string(jose.A256CBCHS512) {
+		return nil, fmt.Errorf("unpack: unsupported authcrypt content encryption '%s'", enc)
+	}
+
+	msg, err := jwe.NewDecrypter(p.km, p.crypto, jwe.WithSenderJWKs(senders...)).DecryptJWE(env)
+	if err != nil {
+		return nil, fmt.Errorf("unpack: %w", err)
+	}
+
+	cty, _ := env.ProtectedHeaders.ContentType()
+
+	return &Envelope{Message: msg, ContentType: cty, SenderKID: skid}, nil
+}
+
+// senderKID returns the "skid" of env, or its "apu" for multi recipients authcrypt messages without "skid".
+func senderKID(env *jose.JSONWebEncryption) (string, error) {
+	if skid, ok := env.ProtectedHeaders.SenderKeyID(); ok {
+		return skid, nil
+	}
+
+	alg, _ := env.ProtectedHeaders.Algorithm()
+	if !strings.Contains(strings.ToUpper(alg), "1PU") {
+		return "", nil
+	}
+
+	apu, ok := env.ProtectedHeaders["apu"].(string)
+	if !ok {
+		return "", errors.New("authcrypt message without skid or apu")
+	}
+
+	skid, err := base64.RawURLEncoding.DecodeString(apu)
+	if err != nil {
+		return "", fmt.Errorf("decode apu: %w", err)
+	}
+
+	return string(skid), nil
+}
+
+// recipientsAPV returns the DIDComm v2 apv of recipients kids: the SHA-256 hash of their sorted list joined with ".".
+func recipientsAPV(kids []string) []byte {
+	sorted := append([]string(nil), kids...)
+	sort.Strings(sorted)
+
+	apv := sha256.Sum256([]byte(strings.Join(sorted, ".")))
+
+	return apv[:]
+}
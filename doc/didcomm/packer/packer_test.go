@@ -0,0 +1,160 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package packer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/doc/jose"
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func newPacker(t *testing.T) (*Packer, kmsapi.KeyManager) {
+	t.Helper()
+
+	km := mockkms.NewForTest(t)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	return New(km, cr), km
+}
+
+func createJWK(t *testing.T, km kmsapi.KeyManager, kt kmsapi.KeyType) *jwk.JWK {
+	t.Helper()
+
+	kid, pubBytes, err := km.CreateAndExportPubKeyBytes(kt)
+	require.NoError(t, err)
+
+	var j *jwk.JWK
+
+	if kt == kmsapi.X25519ECDHKWType {
+		pub := &cryptoapi.PublicKey{}
+		require.NoError(t, json.Unmarshal(pubBytes, pub))
+
+		j, err = jwksupport.JWKFromX25519Key(pub.X)
+	} else {
+		j, err = jwksupport.PubKeyBytesToJWK(pubBytes, kt)
+	}
+
+	require.NoError(t, err)
+
+	j.KeyID = kid
+
+	return j
+}
+
+func protectedHeaders(t *testing.T, envelope []byte) jose.Headers {
+	t.Helper()
+
+	env, err := jose.Deserialize(string(envelope))
+	require.NoError(t, err)
+
+	return env.ProtectedHeaders
+}
+
+func TestPacker(t *testing.T) {
+	msg := []byte(`{"id":"1234567890","type":"https://example.com/protocol/1.0/message","body":{}}`)
+
+	for _, tt := range []struct {
+		kt  kmsapi.KeyType
+		alg string
+	}{
+		{kt: kmsapi.NISTP256ECDHKWType, alg: "ECDH-1PU+A256KW"},
+		{kt: kmsapi.NISTP384ECDHKWType, alg: "ECDH-1PU+A256KW"},
+		{kt: kmsapi.X25519ECDHKWType, alg: "ECDH-1PU+XC20PKW"},
+	} {
+		t.Run(string(tt.kt), func(t *testing.T) {
+			sender, senderKMS := newPacker(t)
+			alice, aliceKMS := newPacker(t)
+			bob, bobKMS := newPacker(t)
+
+			senderJWK := createJWK(t, senderKMS, tt.kt)
+			recipients := []*jwk.JWK{createJWK(t, aliceKMS, tt.kt), createJWK(t, bobKMS, tt.kt)}
+
+			t.Run("anoncrypt", func(t *testing.T) {
+				envelope, err := sender.PackAnoncrypt(msg, recipients...)
+				require.NoError(t, err)
+
+				h := protectedHeaders(t, envelope)
+				typ, _ := h.Type()
+				require.Equal(t, MediaTypeEncrypted, typ)
+				enc, _ := h.Encryption()
+				require.Equal(t, string(jose.A256CBCHS512), enc)
+
+				for _, p := range []*Packer{alice, bob} {
+					env, err := p.Unpack(envelope)
+					require.NoError(t, err)
+					require.Equal(t, msg, env.Message)
+					require.Equal(t, MediaTypePlaintext, env.ContentType)
+					require.Empty(t, env.SenderKID)
+				}
+			})
+
+			t.Run("authcrypt", func(t *testing.T) {
+				envelope, err := sender.PackAuthcrypt(msg, senderJWK.KeyID, recipients...)
+				require.NoError(t, err)
+
+				for _, p := range []*Packer{alice, bob} {
+					env, err := p.Unpack(envelope, senderJWK)
+					require.NoError(t, err)
+					require.Equal(t, msg, env.Message)
+					require.Equal(t, senderJWK.KeyID, env.SenderKID)
+				}
+
+				envelope, err = sender.PackAuthcrypt(msg, senderJWK.KeyID, recipients[0])
+				require.NoError(t, err)
+
+				alg, _ := protectedHeaders(t, envelope).Algorithm()
+				require.Equal(t, tt.alg, alg)
+
+				_, err = alice.Unpack(envelope)
+				require.ErrorContains(t, err, "failed to add sender public key for skid")
+			})
+		})
+	}
+}
+
+func TestPackerErrors(t *testing.T) {
+	p, km := newPacker(t)
+	rec := createJWK(t, km, kmsapi.NISTP256ECDHKWType)
+
+	_, err := p.PackAnoncrypt([]byte("msg"))
+	require.EqualError(t, err, "pack: no recipients")
+
+	_, err = p.PackAnoncrypt([]byte("msg"), &jwk.JWK{})
+	require.EqualError(t, err, "pack: recipient 1: missing JWK or kid")
+
+	_, err = p.PackAuthcrypt([]byte("msg"), "", rec)
+	require.EqualError(t, err, "pack authcrypt: sender key ID is empty")
+
+	_, err = p.PackAuthcrypt([]byte("msg"), "missing", rec)
+	require.ErrorContains(t, err, "pack: jwe encrypt: get sender key")
+
+	_, err = p.Unpack([]byte("not a JWE"))
+	require.ErrorContains(t, err, "unpack: ")
+
+	envelope, err := New(p.km, p.crypto, WithContentType(MediaTypeSigned)).PackAnoncrypt([]byte("msg"), rec)
+	require.NoError(t, err)
+
+	env, err := p.Unpack(envelope)
+	require.NoError(t, err)
+	require.Equal(t, MediaTypeSigned, env.ContentType)
+
+	other, _ := newPacker(t)
+
+	_, err = other.Unpack(envelope)
+	require.ErrorContains(t, err, "unpack: jwe decrypt")
+}
@@ -0,0 +1,233 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpsig
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	componentContentDigest = "content-digest"
+	signatureParams        = "@signature-params"
+)
+
+// signatureBase returns the signature base of the components of req and of the serialized signature parameters, as
+// https://www.rfc-editor.org/rfc/rfc9421#section-2.5 creates it.
+func signatureBase(req *http.Request, components []string, sigParams string) ([]byte, error) {
+	var b bytes.Buffer
+
+	seen := make(map[string]bool, len(components))
+
+	for _, c := range components {
+		if seen[c] {
+			return nil, fmt.Errorf("duplicate component '%s'", c)
+		}
+
+		seen[c] = true
+
+		v, err := componentValue(req, c)
+		if err != nil {
+			return nil, err
+		}
+
+		b.WriteString(`"` + c + `": ` + v + "\n")
+	}
+
+	b.WriteString(`"` + signatureParams + `": ` + sigParams)
+
+	return b.Bytes(), nil
+}
+
+func componentValue(req *http.Request, component string) (string, error) {
+	switch component {
+	case "@method":
+		return req.Method, nil
+	case "@target-uri":
+		return scheme(req) + "://" + authority(req) + req.URL.RequestURI(), nil
+	case "@authority":
+		return authority(req), nil
+	case "@scheme":
+		return scheme(req), nil
+	case "@request-target":
+		return req.URL.RequestURI(), nil
+	case "@path":
+		if p := req.URL.EscapedPath(); p != "" {
+			return p, nil
+		}
+
+		return "/", nil
+	case "@query":
+		return "?" + req.URL.RawQuery, nil
+	}
+
+	if strings.HasPrefix(component, "@") || component != strings.ToLower(component) {
+		return "", fmt.Errorf("unsupported component '%s'", component)
+	}
+
+	return headerValue(req, component)
+}
+
+func headerValue(req *http.Request, name string) (string, error) {
+	values := req.Header.Values(name)
+
+	switch {
+	case len(values) > 0:
+	case name == "host":
+		return authority(req), nil
+	case name == "content-length" && req.ContentLength > 0:
+		return strconv.FormatInt(req.ContentLength, 10), nil
+	default:
+		return "", fmt.Errorf("missing header '%s'", name)
+	}
+
+	for i, v := range values {
+		values[i] = strings.TrimSpace(v)
+	}
+
+	return strings.Join(values, ", "), nil
+}
+
+// scheme returns the scheme of req: the scheme of its URL for the outgoing requests, https or http for the incoming
+// requests.
+func scheme(req *http.Request) string {
+	switch {
+	case req.URL.Scheme != "":
+		return strings.ToLower(req.URL.Scheme)
+	case req.TLS != nil:
+		return "https"
+	default:
+		return "http"
+	}
+}
+
+// authority returns the lower case host of req, without the default port of its scheme.
+func authority(req *http.Request) string {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	host = strings.ToLower(host)
+
+	if h, port, err := net.SplitHostPort(host); err == nil {
+		if (port == "443" && scheme(req) == "https") || (port == "80" && scheme(req) == "http") {
+			if strings.Contains(h, ":") {
+				return "[" + h + "]"
+			}
+
+			return h
+		}
+	}
+
+	return host
+}
+
+// body returns the body of req and resets it to be read again.
+func body(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody != nil {
+		r, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+
+		return io.ReadAll(r)
+	}
+
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = req.Body.Close() //nolint:errcheck
+
+	req.Body = io.NopCloser(bytes.NewReader(b))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(b)), nil
+	}
+
+	return b, nil
+}
+
+// setContentDigest sets the SHA-256 Content-Digest header of the body of req, unless already set.
+func setContentDigest(req *http.Request) error {
+	if req.Header.Get(HeaderContentDigest) != "" {
+		return nil
+	}
+
+	b, err := body(req)
+	if err != nil {
+		return fmt.Errorf("read body: %w", err)
+	}
+
+	digest := sha256.Sum256(b)
+
+	var sb strings.Builder
+
+	sb.WriteString("sha-256=")
+	writeBareItem(&sb, digest[:])
+
+	req.Header.Set(HeaderContentDigest, sb.String())
+
+	return nil
+}
+
+// verifyContentDigest verifies the SHA-256 and SHA-512 Content-Digest of the body of req, at least one is required.
+func verifyContentDigest(req *http.Request) error {
+	members, err := parseDictionary(strings.Join(req.Header.Values(HeaderContentDigest), ", "))
+	if err != nil {
+		return fmt.Errorf("parse content digest: %w", err)
+	}
+
+	b, err := body(req)
+	if err != nil {
+		return fmt.Errorf("read body: %w", err)
+	}
+
+	verified := false
+
+	for _, m := range members {
+		var digest []byte
+
+		switch m.key {
+		case "sha-256":
+			d := sha256.Sum256(b)
+			digest = d[:]
+		case "sha-512":
+			d := sha512.Sum512(b)
+			digest = d[:]
+		default:
+			continue
+		}
+
+		v, ok := m.item.value.([]byte)
+		if m.isList || !ok || subtle.ConstantTimeCompare(v, digest) != 1 {
+			return errors.New("content digest mismatch")
+		}
+
+		verified = true
+	}
+
+	if !verified {
+		return errors.New("missing SHA-256 or SHA-512 content digest")
+	}
+
+	return nil
+}
@@ -0,0 +1,266 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package httpsig creates and verifies HTTP message signatures (https://www.rfc-editor.org/rfc/rfc9421) of requests
+// with keys held by a KMS. The signature algorithm is resolved from the KMS key type and the keyid parameter of the
+// signatures is the KMS key ID.
+//
+// A Service signs outgoing requests, standalone or as the headers function of the webkms clients (AddHeaders), and
+// verifies incoming requests, eg: with the Handler middleware. The covered components are the derived components
+// @method, @target-uri, @authority, @scheme, @request-target, @path and @query and the header fields, without
+// component parameters. The Content-Digest header (https://www.rfc-editor.org/rfc/rfc9530) of the body is set when
+// covered and verified against the body.
+package httpsig
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/tink/go/core/cryptofmt"
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+
+	"github.com/trustbloc/kms-go/kms/webkms"
+	"github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+// HTTP signature algorithms (https://www.rfc-editor.org/rfc/rfc9421#section-6.2.2).
+const (
+	AlgRSAV15SHA256    = "rsa-v1_5-sha256"
+	AlgHMACSHA256      = "hmac-sha256"
+	AlgECDSAP256SHA256 = "ecdsa-p256-sha256"
+	AlgECDSAP384SHA384 = "ecdsa-p384-sha384"
+	AlgEd25519         = "ed25519"
+)
+
+// HTTP header fields of the signatures.
+const (
+	HeaderSignatureInput = "Signature-Input"
+	HeaderSignature      = "Signature"
+	HeaderContentDigest  = "Content-Digest"
+)
+
+const (
+	defaultLabel   = "sig1"
+	hmacKeyTypeURL = "type.googleapis.com/google.crypto.tink.HmacKey"
+)
+
+// Service signs and verifies HTTP requests using keys managed by a KeyManager and crypto operations from a Crypto
+// service.
+type Service struct {
+	km     kms.KeyManager
+	crypto crypto.Crypto
+}
+
+// New creates a new HTTP signatures Service.
+func New(km kms.KeyManager, c crypto.Crypto) *Service {
+	return &Service{km: km, crypto: c}
+}
+
+// Opt is an HTTP signatures Service option.
+type Opt func(o *opts)
+
+type opts struct {
+	label      string
+	components []string
+	required   []string
+	keyID      string
+	expires    time.Duration
+	maxAge     time.Duration
+	nonce      string
+	tag        string
+	now        func() time.Time
+}
+
+// WithLabel sets the label of the created signature, sig1 by default, or selects the verified signature, the first
+// signature of the request by default.
+func WithLabel(label string) Opt {
+	return func(o *opts) {
+		o.label = label
+	}
+}
+
+// WithComponents sets the components covered by the created signature, eg: "@method", "@path" or "content-type".
+// The default components are @method, @target-uri and, for requests with a body, content-digest.
+func WithComponents(components ...string) Opt {
+	return func(o *opts) {
+		o.components = components
+	}
+}
+
+// WithRequiredComponents sets the components a verified signature must cover.
+func WithRequiredComponents(components ...string) Opt {
+	return func(o *opts) {
+		o.required = components
+	}
+}
+
+// WithKeyID sets the KMS key ID a signature is verified with, the keyid parameter of the signature by default.
+func WithKeyID(keyID string) Opt {
+	return func(o *opts) {
+		o.keyID = keyID
+	}
+}
+
+// WithExpires sets the expires parameter of the created signature, d after its creation.
+func WithExpires(d time.Duration) Opt {
+	return func(o *opts) {
+		o.expires = d
+	}
+}
+
+// WithMaxAge rejects the verified signatures created more than d ago.
+func WithMaxAge(d time.Duration) Opt {
+	return func(o *opts) {
+		o.maxAge = d
+	}
+}
+
+// WithNonce sets the nonce parameter of the created signature.
+func WithNonce(nonce string) Opt {
+	return func(o *opts) {
+		o.nonce = nonce
+	}
+}
+
+// WithTag sets the tag parameter of the created signature, or the tag parameter a verified signature must have.
+func WithTag(tag string) Opt {
+	return func(o *opts) {
+		o.tag = tag
+	}
+}
+
+// WithClock sets the clock of the signature creation and expiration times, time.Now by default.
+func WithClock(now func() time.Time) Opt {
+	return func(o *opts) {
+		o.now = now
+	}
+}
+
+func newOpts(options []Opt) *opts {
+	o := &opts{now: time.Now}
+
+	for _, opt := range options {
+		opt(o)
+	}
+
+	return o
+}
+
+// AlgorithmForKeyType returns the HTTP signature algorithm of signatures created with keys of type kt.
+func AlgorithmForKeyType(kt kms.KeyType) (string, error) {
+	switch kt { //nolint:exhaustive
+	case kms.ECDSAP256TypeDER, kms.ECDSAP256TypeIEEEP1363:
+		return AlgECDSAP256SHA256, nil
+	case kms.ECDSAP384TypeDER, kms.ECDSAP384TypeIEEEP1363:
+		return AlgECDSAP384SHA384, nil
+	case kms.ED25519Type:
+		return AlgEd25519, nil
+	case kms.RSARS256Type:
+		return AlgRSAV15SHA256, nil
+	case kms.HMACSHA256Tag256Type:
+		return AlgHMACSHA256, nil
+	default:
+		return "", fmt.Errorf("key type '%s' is not supported for HTTP signatures", kt)
+	}
+}
+
+type signingKey struct {
+	kh  interface{}
+	kt  kms.KeyType
+	alg string
+	// macPrefix is the Tink output prefix of the MAC tags of an HMAC key, stripped from the HTTP signatures.
+	macPrefix []byte
+}
+
+// key returns the signing key keyID. HMAC keys have no public key: a key without public key is an HMAC-SHA256 key if
+// its primary key is an HMAC key.
+func (s *Service) key(keyID string) (*signingKey, error) {
+	kh, err := s.km.Get(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("get key: %w", err)
+	}
+
+	k := &signingKey{kh: kh}
+
+	_, k.kt, err = s.km.ExportPubKeyBytes(keyID)
+	if err != nil {
+		var isHMAC bool
+
+		if k.macPrefix, isHMAC = hmacKeyPrefix(kh); !isHMAC {
+			return nil, fmt.Errorf("export public key: %w", err)
+		}
+
+		k.kt = kms.HMACSHA256Tag256Type
+	}
+
+	k.alg, err = AlgorithmForKeyType(k.kt)
+	if err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// hmacKeyPrefix returns the output prefix of the primary key of kh and true if it is an HMAC key.
+func hmacKeyPrefix(kh interface{}) ([]byte, bool) {
+	handle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, false
+	}
+
+	info := handle.KeysetInfo()
+
+	for _, k := range info.KeyInfo {
+		if k.KeyId != info.PrimaryKeyId {
+			continue
+		}
+
+		if k.TypeUrl != hmacKeyTypeURL {
+			return nil, false
+		}
+
+		switch k.OutputPrefixType { //nolint:exhaustive
+		case tinkpb.OutputPrefixType_RAW:
+			return nil, true
+		case tinkpb.OutputPrefixType_TINK:
+			return binary.BigEndian.AppendUint32([]byte{cryptofmt.TinkStartByte}, k.KeyId), true
+		default:
+			return binary.BigEndian.AppendUint32([]byte{cryptofmt.LegacyStartByte}, k.KeyId), true
+		}
+	}
+
+	return nil, false
+}
+
+// AddHeaders returns a webkms headers function signing the requests of remoteKMS and remoteCrypto with the key keyID,
+// set with webkms.WithHeaders.
+func (s *Service) AddHeaders(keyID string, options ...Opt) webkms.AddHeaders {
+	return func(req *http.Request) (*http.Header, error) {
+		if err := s.Sign(req, keyID, options...); err != nil {
+			return nil, err
+		}
+
+		return &req.Header, nil
+	}
+}
+
+// Handler returns a middleware verifying the signature of the requests before calling next, it responds
+// 401 Unauthorized to the requests without a valid signature.
+func (s *Service) Handler(next http.Handler, options ...Opt) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, err := s.Verify(req, options...); err != nil {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+
+			return
+		}
+
+		next.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,323 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpsig
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func newService(t *testing.T) (*Service, kmsapi.KeyManager) {
+	t.Helper()
+
+	km := mockkms.NewForTest(t)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	return New(km, cr), km
+}
+
+// testRequest is the test request of RFC 9421 appendix B.2.
+func testRequest(t *testing.T) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, "https://example.com/foo?param=Value&Pet=dog",
+		strings.NewReader(`{"hello": "world"}`))
+	require.NoError(t, err)
+
+	req.Header.Set("Date", "Tue, 20 Apr 2021 02:07:55 GMT")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Digest", "sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyeal"+
+		"dVLvRwEmTHWXvJwew==:")
+	req.Header.Set("Content-Length", "18")
+
+	return req
+}
+
+func TestVerify_RFC9421Ed25519(t *testing.T) {
+	svc, km := newService(t)
+
+	// test-key-ed25519 of RFC 9421 appendix B.1.4.
+	der, err := base64.StdEncoding.DecodeString("MC4CAQAwBQYDK2VwBCIEIJ+DYvh6SEqVTm50DFtMDoQikTmiCqirVv9mWG9qfSnF")
+	require.NoError(t, err)
+
+	privKey, err := x509.ParsePKCS8PrivateKey(der)
+	require.NoError(t, err)
+
+	keyID, _, err := km.ImportPrivateKey(privKey.(ed25519.PrivateKey), kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	req := testRequest(t)
+	req.Header.Set(HeaderSignatureInput, `sig-b26=("date" "@method" "@path" "@authority" "content-type" `+
+		`"content-length");created=1618884473;keyid="test-key-ed25519"`)
+	req.Header.Set(HeaderSignature, "sig-b26=:wqcAqbmYJ2ji2glfAMaRy4gruYYnx2nEFN2HN6jrnDnQCK1u02Gb04v9EDgwUPiu4A0w6v"+
+		"uQv5lIp5WPpBKRCw==:")
+
+	sig, err := svc.Verify(req, WithKeyID(keyID))
+	require.NoError(t, err)
+	require.Equal(t, &Signature{
+		Label:      "sig-b26",
+		Components: []string{"date", "@method", "@path", "@authority", "content-type", "content-length"},
+		KeyID:      "test-key-ed25519",
+		Algorithm:  AlgEd25519,
+		Created:    time.Unix(1618884473, 0),
+	}, sig)
+
+	req.Header.Set("Date", "Tue, 20 Apr 2021 02:07:56 GMT")
+
+	_, err = svc.Verify(req, WithKeyID(keyID))
+	require.ErrorContains(t, err, "httpsig verify: verify:")
+}
+
+func TestSignVerify(t *testing.T) {
+	svc, km := newService(t)
+
+	for _, kt := range []kmsapi.KeyType{
+		kmsapi.ECDSAP256TypeDER, kmsapi.ECDSAP256TypeIEEEP1363, kmsapi.ECDSAP384TypeDER, kmsapi.ED25519Type,
+		kmsapi.RSARS256Type, kmsapi.HMACSHA256Tag256Type,
+	} {
+		t.Run(string(kt), func(t *testing.T) {
+			keyID, _, err := km.Create(kt)
+			require.NoError(t, err)
+
+			req := testRequest(t)
+			req.Header.Del("Content-Digest")
+
+			require.NoError(t, svc.Sign(req, keyID, WithNonce("n-1"), WithTag("app"), WithExpires(time.Minute)))
+			require.True(t, strings.HasPrefix(req.Header.Get(HeaderSignatureInput),
+				`sig1=("@method" "@target-uri" "content-digest");created=`))
+			require.Equal(t, "sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:", req.Header.Get("Content-Digest"))
+
+			// the body is still readable after signing.
+			b, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			require.Equal(t, `{"hello": "world"}`, string(b))
+
+			sig, err := svc.Verify(req, WithRequiredComponents("@method", "content-digest"), WithTag("app"),
+				WithMaxAge(time.Minute))
+			require.NoError(t, err)
+			require.Equal(t, keyID, sig.KeyID)
+			require.Equal(t, "n-1", sig.Nonce)
+			require.Equal(t, "app", sig.Tag)
+
+			alg, err := AlgorithmForKeyType(kt)
+			require.NoError(t, err)
+			require.Equal(t, alg, sig.Algorithm)
+
+			req.Body = io.NopCloser(strings.NewReader(`{"hello": "there"}`))
+			req.GetBody = nil
+
+			_, err = svc.Verify(req)
+			require.EqualError(t, err, "httpsig verify: content digest mismatch")
+		})
+	}
+}
+
+func TestSignComponents(t *testing.T) {
+	svc, km := newService(t)
+
+	keyID, _, err := km.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	req := testRequest(t)
+
+	require.NoError(t, svc.Sign(req, keyID, WithLabel("sig-a"),
+		WithComponents("@method", "@authority", "@scheme", "@request-target", "@path", "@query", "host", "date")))
+	require.NoError(t, svc.Sign(req, keyID, WithLabel("sig-b"), WithComponents("content-type", "content-length")))
+	require.Len(t, req.Header.Values(HeaderSignature), 2)
+
+	base, err := signatureBase(req, []string{"@method", "@authority", "@scheme", "@request-target", "@path",
+		"@query", "host", "date"}, serializeInnerList([]string{"@method"}, params{{key: "created", value: int64(1)}}))
+	require.NoError(t, err)
+	require.Equal(t, `"@method": POST
+"@authority": example.com
+"@scheme": https
+"@request-target": /foo?param=Value&Pet=dog
+"@path": /foo
+"@query": ?param=Value&Pet=dog
+"host": example.com
+"date": Tue, 20 Apr 2021 02:07:55 GMT
+"@signature-params": ("@method");created=1`, string(base))
+
+	for _, label := range []string{"sig-a", "sig-b"} {
+		sig, err := svc.Verify(req, WithLabel(label))
+		require.NoError(t, err)
+		require.Equal(t, label, sig.Label)
+	}
+
+	_, err = svc.Verify(req, WithLabel("sig-c"))
+	require.EqualError(t, err, "httpsig verify: missing signature")
+
+	_, err = svc.Verify(req, WithLabel("sig-b"), WithRequiredComponents("@method"))
+	require.EqualError(t, err, "httpsig verify: component '@method' is not covered")
+
+	_, err = svc.Verify(req, WithLabel("sig-b"), WithTag("app"))
+	require.EqualError(t, err, "httpsig verify: unexpected tag ''")
+
+	_, err = svc.Verify(req, WithLabel("sig-b"), WithMaxAge(time.Minute), WithClock(func() time.Time {
+		return time.Now().Add(time.Hour)
+	}))
+	require.EqualError(t, err, "httpsig verify: signature too old")
+
+	err = svc.Sign(req, keyID, WithComponents("@status"))
+	require.EqualError(t, err, "httpsig sign: unsupported component '@status'")
+
+	err = svc.Sign(req, keyID, WithComponents("X-Missing"))
+	require.EqualError(t, err, "httpsig sign: unsupported component 'X-Missing'")
+
+	err = svc.Sign(req, keyID, WithComponents("x-missing"))
+	require.EqualError(t, err, "httpsig sign: missing header 'x-missing'")
+
+	err = svc.Sign(req, keyID, WithComponents("date", "date"))
+	require.EqualError(t, err, "httpsig sign: duplicate component 'date'")
+}
+
+func TestVerifyErrors(t *testing.T) {
+	svc, km := newService(t)
+
+	keyID, _, err := km.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	otherKeyID, _, err := km.Create(kmsapi.ECDSAP256TypeIEEEP1363)
+	require.NoError(t, err)
+
+	now := time.Now()
+	req := testRequest(t)
+
+	require.NoError(t, svc.Sign(req, keyID, WithExpires(time.Minute), WithClock(func() time.Time { return now })))
+
+	_, err = svc.Verify(req, WithClock(func() time.Time { return now.Add(time.Minute) }))
+	require.EqualError(t, err, "httpsig verify: signature expired")
+
+	_, err = svc.Verify(req, WithKeyID(otherKeyID))
+	require.EqualError(t, err, "httpsig verify: alg parameter 'ed25519' does not match key algorithm "+
+		"'ecdsa-p256-sha256'")
+
+	_, err = svc.Verify(req, WithKeyID("unknown"))
+	require.ErrorContains(t, err, "httpsig verify: get key:")
+
+	for _, tc := range []struct {
+		input, sig, err string
+	}{
+		{`sig1=("@method");keyid="k"`, `sig2=:AAAA:`, "httpsig verify: missing signature value of 'sig1'"},
+		{`sig1=("@method")`, `sig1=:AAAA:`, "httpsig verify: missing key ID"},
+		{`sig1="@method"`, `sig1=:AAAA:`, "httpsig verify: signature input 'sig1': not an inner list"},
+		{`sig1=("@method";bs)`, `sig1=:AAAA:`, "httpsig verify: signature input 'sig1': unsupported component"},
+		{`sig1=("@method");created="x"`, `sig1=:AAAA:`,
+			"httpsig verify: signature input 'sig1': invalid parameter 'created'"},
+		{`sig1=("@method"`, `sig1=:AAAA:`, "httpsig verify: parse signature input: unterminated inner list"},
+		{`sig1=("@method")`, `sig1=:AAAA`, "httpsig verify: parse signature: unterminated byte sequence"},
+		{``, ``, "httpsig verify: missing signature"},
+	} {
+		r := testRequest(t)
+		r.Header.Set(HeaderSignatureInput, tc.input)
+		r.Header.Set(HeaderSignature, tc.sig)
+
+		_, err = svc.Verify(r)
+		require.EqualError(t, err, tc.err, tc.input)
+	}
+}
+
+func TestKeyErrors(t *testing.T) {
+	svc, km := newService(t)
+
+	keyID, _, err := km.Create(kmsapi.AES256GCMType)
+	require.NoError(t, err)
+
+	err = svc.Sign(testRequest(t), keyID)
+	require.ErrorContains(t, err, "httpsig sign: export public key:")
+
+	keyID, _, err = km.Create(kmsapi.HMACSHA512Tag512Type)
+	require.NoError(t, err)
+
+	err = svc.Sign(testRequest(t), keyID)
+	require.EqualError(t, err, "httpsig sign: key is not an HMAC-SHA256 key")
+
+	keyID, _, err = km.Create(kmsapi.ECDSASecp256k1TypeIEEEP1363)
+	require.NoError(t, err)
+
+	err = svc.Sign(testRequest(t), keyID)
+	require.EqualError(t, err, "httpsig sign: key type 'ECDSASecp256k1IEEEP1363' is not supported for HTTP signatures")
+}
+
+func TestAddHeadersAndHandler(t *testing.T) {
+	svc, km := newService(t)
+
+	keyID, _, err := km.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	handler := svc.Handler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		b, e := io.ReadAll(req.Body)
+		require.NoError(t, e)
+
+		_, e = w.Write(b)
+		require.NoError(t, e)
+	}), WithRequiredComponents("@method", "@target-uri", "content-digest"))
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/v1/keystores/1/keys", bytes.NewReader([]byte("{}")))
+	require.NoError(t, err)
+
+	h, err := svc.AddHeaders(keyID)(req)
+	require.NoError(t, err)
+
+	req.Header = h.Clone()
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "{}", string(b))
+	require.NoError(t, resp.Body.Close())
+
+	resp, err = http.Post(server.URL, "application/json", bytes.NewReader([]byte("{}"))) //nolint:noctx
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+
+	_, err = svc.AddHeaders("unknown")(req)
+	require.ErrorContains(t, err, "httpsig sign: get key:")
+}
+
+func TestParseDictionary(t *testing.T) {
+	members, err := parseDictionary(`a=?1, b=("x" y);p=-12, c;q="\"q\\", d=:AQI=:`)
+	require.NoError(t, err)
+	require.Equal(t, []member{
+		{key: "a", item: item{value: true}},
+		{key: "b", isList: true, list: []item{{value: "x"}, {value: token("y")}},
+			item: item{params: params{{key: "p", value: int64(-12)}}}},
+		{key: "c", item: item{value: true, params: params{{key: "q", value: `"q\`}}}},
+		{key: "d", item: item{value: []byte{1, 2}}},
+	}, members)
+
+	for _, s := range []string{"a=1,", "A=1", "a=1.5", "a=?2", `a="\x"`, `a="x`, "a=:!:", "a=(x", "a=(x)y", "a=%"} {
+		_, err = parseDictionary(s)
+		require.Error(t, err, s)
+	}
+
+	require.Equal(t, `("a" "b\"c");x;n=1;t=tok;b=:AQ==:;f=?0`, serializeInnerList([]string{"a", `b"c`},
+		params{{"x", true}, {"n", int64(1)}, {"t", token("tok")}, {"b", []byte{1}}, {"f", false}}))
+}
@@ -0,0 +1,128 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpsig
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/trustbloc/kms-go/util/sigencoding"
+)
+
+// Sign signs req with the key keyID and adds the signature to its Signature-Input and Signature headers. The
+// signature parameters are created, alg and keyid, and expires, nonce and tag when set.
+func (s *Service) Sign(req *http.Request, keyID string, options ...Opt) error {
+	o := newOpts(options)
+
+	label := o.label
+	if label == "" {
+		label = defaultLabel
+	}
+
+	k, err := s.key(keyID)
+	if err != nil {
+		return fmt.Errorf("httpsig sign: %w", err)
+	}
+
+	components := o.components
+	if components == nil {
+		components = []string{"@method", "@target-uri"}
+
+		if req.Body != nil && req.Body != http.NoBody {
+			components = append(components, componentContentDigest)
+		}
+	}
+
+	if contains(components, componentContentDigest) {
+		if err = setContentDigest(req); err != nil {
+			return fmt.Errorf("httpsig sign: %w", err)
+		}
+	}
+
+	created := o.now()
+	prms := params{{key: "created", value: created.Unix()}}
+
+	if o.expires > 0 {
+		prms = append(prms, param{key: "expires", value: created.Add(o.expires).Unix()})
+	}
+
+	if o.nonce != "" {
+		prms = append(prms, param{key: "nonce", value: o.nonce})
+	}
+
+	prms = append(prms, param{key: "alg", value: k.alg}, param{key: "keyid", value: keyID})
+
+	if o.tag != "" {
+		prms = append(prms, param{key: "tag", value: o.tag})
+	}
+
+	sigParams := serializeInnerList(components, prms)
+
+	base, err := signatureBase(req, components, sigParams)
+	if err != nil {
+		return fmt.Errorf("httpsig sign: %w", err)
+	}
+
+	sig, err := s.sign(base, k)
+	if err != nil {
+		return fmt.Errorf("httpsig sign: %w", err)
+	}
+
+	var b strings.Builder
+
+	b.WriteString(label + "=")
+	writeBareItem(&b, sig)
+
+	req.Header.Add(HeaderSignatureInput, label+"="+sigParams)
+	req.Header.Add(HeaderSignature, b.String())
+
+	return nil
+}
+
+func (s *Service) sign(base []byte, k *signingKey) ([]byte, error) {
+	switch k.alg {
+	case AlgHMACSHA256:
+		mac, err := s.crypto.ComputeMAC(base, k.kh)
+		if err != nil {
+			return nil, fmt.Errorf("compute MAC: %w", err)
+		}
+
+		if len(mac) != len(k.macPrefix)+sha256.Size {
+			return nil, errors.New("key is not an HMAC-SHA256 key")
+		}
+
+		return mac[len(k.macPrefix):], nil
+	case AlgECDSAP256SHA256, AlgECDSAP384SHA384:
+		sig, err := s.crypto.Sign(base, k.kh)
+		if err != nil {
+			return nil, fmt.Errorf("sign: %w", err)
+		}
+
+		// HTTP signatures require ECDSA signatures in IEEE-P1363 format (R || S).
+		return sigencoding.Convert(sig, k.kt, sigencoding.IEEEP1363)
+	default:
+		sig, err := s.crypto.Sign(base, k.kh)
+		if err != nil {
+			return nil, fmt.Errorf("sign: %w", err)
+		}
+
+		return sig, nil
+	}
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,391 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpsig
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The subset of the structured field values (https://www.rfc-editor.org/rfc/rfc8941) used by HTTP message
+// signatures: dictionaries of inner lists and byte sequences, with parameters. Decimals are not supported.
+
+// token is a structured field token, as opposed to a string.
+type token string
+
+type param struct {
+	key   string
+	value interface{}
+}
+
+type params []param
+
+type item struct {
+	value  interface{}
+	params params
+}
+
+// member is a dictionary member: an inner list if isList, an item otherwise.
+type member struct {
+	key    string
+	item   item
+	list   []item
+	isList bool
+}
+
+type sfParser struct {
+	s string
+	i int
+}
+
+func parseDictionary(s string) ([]member, error) {
+	p := &sfParser{s: s}
+	p.skipSP()
+
+	var members []member
+
+	for !p.eof() {
+		m, err := p.parseMember()
+		if err != nil {
+			return nil, err
+		}
+
+		members = append(members, m)
+
+		p.skipOWS()
+
+		if p.eof() {
+			break
+		}
+
+		if p.s[p.i] != ',' {
+			return nil, fmt.Errorf("unexpected character '%c' in dictionary", p.s[p.i])
+		}
+
+		p.i++
+		p.skipOWS()
+
+		if p.eof() {
+			return nil, errors.New("trailing comma in dictionary")
+		}
+	}
+
+	return members, nil
+}
+
+func (p *sfParser) parseMember() (member, error) {
+	key, err := p.parseKey()
+	if err != nil {
+		return member{}, err
+	}
+
+	m := member{key: key}
+
+	if p.eof() || p.s[p.i] != '=' {
+		m.item.value = true
+		m.item.params, err = p.parseParams()
+
+		return m, err
+	}
+
+	p.i++
+
+	if !p.eof() && p.s[p.i] == '(' {
+		m.isList = true
+		m.list, m.item.params, err = p.parseInnerList()
+
+		return m, err
+	}
+
+	m.item, err = p.parseItem()
+
+	return m, err
+}
+
+func (p *sfParser) parseInnerList() ([]item, params, error) {
+	p.i++
+
+	var items []item
+
+	for {
+		p.skipSP()
+
+		if p.eof() {
+			return nil, nil, errors.New("unterminated inner list")
+		}
+
+		if p.s[p.i] == ')' {
+			p.i++
+
+			prms, err := p.parseParams()
+
+			return items, prms, err
+		}
+
+		it, err := p.parseItem()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		items = append(items, it)
+
+		if !p.eof() && p.s[p.i] != ' ' && p.s[p.i] != ')' {
+			return nil, nil, errors.New("invalid inner list")
+		}
+	}
+}
+
+func (p *sfParser) parseItem() (item, error) {
+	v, err := p.parseBareItem()
+	if err != nil {
+		return item{}, err
+	}
+
+	prms, err := p.parseParams()
+
+	return item{value: v, params: prms}, err
+}
+
+func (p *sfParser) parseParams() (params, error) {
+	var prms params
+
+	for !p.eof() && p.s[p.i] == ';' {
+		p.i++
+		p.skipSP()
+
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+
+		var v interface{} = true
+
+		if !p.eof() && p.s[p.i] == '=' {
+			p.i++
+
+			if v, err = p.parseBareItem(); err != nil {
+				return nil, err
+			}
+		}
+
+		prms = append(prms, param{key: key, value: v})
+	}
+
+	return prms, nil
+}
+
+func (p *sfParser) parseBareItem() (interface{}, error) {
+	if p.eof() {
+		return nil, errors.New("missing item")
+	}
+
+	switch c := p.s[p.i]; {
+	case c == '-' || isDigit(c):
+		return p.parseInteger()
+	case c == '"':
+		return p.parseString()
+	case c == ':':
+		return p.parseByteSequence()
+	case c == '?':
+		return p.parseBoolean()
+	case isAlpha(c) || c == '*':
+		return p.parseToken(), nil
+	default:
+		return nil, fmt.Errorf("unexpected character '%c' in item", c)
+	}
+}
+
+func (p *sfParser) parseInteger() (int64, error) {
+	start := p.i
+
+	if p.s[p.i] == '-' {
+		p.i++
+	}
+
+	for !p.eof() && isDigit(p.s[p.i]) {
+		p.i++
+	}
+
+	if !p.eof() && p.s[p.i] == '.' {
+		return 0, errors.New("decimals are not supported")
+	}
+
+	n, err := strconv.ParseInt(p.s[start:p.i], 10, 64)
+	if err != nil || p.i-start > 15 { //nolint:gomnd // RFC 8941 integers have at most 15 digits
+		return 0, fmt.Errorf("invalid integer '%s'", p.s[start:p.i])
+	}
+
+	return n, nil
+}
+
+func (p *sfParser) parseString() (string, error) {
+	var b strings.Builder
+
+	for p.i++; !p.eof(); p.i++ {
+		switch c := p.s[p.i]; {
+		case c == '\\':
+			p.i++
+
+			if p.eof() || (p.s[p.i] != '"' && p.s[p.i] != '\\') {
+				return "", errors.New("invalid string escape")
+			}
+
+			b.WriteByte(p.s[p.i])
+		case c == '"':
+			p.i++
+
+			return b.String(), nil
+		case c < 0x20 || c > 0x7e:
+			return "", errors.New("invalid string character")
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", errors.New("unterminated string")
+}
+
+func (p *sfParser) parseByteSequence() ([]byte, error) {
+	end := strings.IndexByte(p.s[p.i+1:], ':')
+	if end < 0 {
+		return nil, errors.New("unterminated byte sequence")
+	}
+
+	b, err := base64.StdEncoding.DecodeString(p.s[p.i+1 : p.i+1+end])
+	if err != nil {
+		return nil, fmt.Errorf("invalid byte sequence: %w", err)
+	}
+
+	p.i += end + 2 //nolint:gomnd // the two colons
+
+	return b, nil
+}
+
+func (p *sfParser) parseBoolean() (bool, error) {
+	if p.i+1 >= len(p.s) || (p.s[p.i+1] != '0' && p.s[p.i+1] != '1') {
+		return false, errors.New("invalid boolean")
+	}
+
+	v := p.s[p.i+1] == '1'
+	p.i += 2
+
+	return v, nil
+}
+
+func (p *sfParser) parseToken() token {
+	start := p.i
+
+	for p.i++; !p.eof() && isTokenChar(p.s[p.i]); p.i++ {
+	}
+
+	return token(p.s[start:p.i])
+}
+
+func (p *sfParser) parseKey() (string, error) {
+	if p.eof() || !(isLCAlpha(p.s[p.i]) || p.s[p.i] == '*') {
+		return "", errors.New("invalid key")
+	}
+
+	start := p.i
+
+	for p.i++; !p.eof(); p.i++ {
+		c := p.s[p.i]
+
+		if !isLCAlpha(c) && !isDigit(c) && c != '_' && c != '-' && c != '.' && c != '*' {
+			break
+		}
+	}
+
+	return p.s[start:p.i], nil
+}
+
+func (p *sfParser) eof() bool {
+	return p.i >= len(p.s)
+}
+
+func (p *sfParser) skipSP() {
+	for !p.eof() && p.s[p.i] == ' ' {
+		p.i++
+	}
+}
+
+func (p *sfParser) skipOWS() {
+	for !p.eof() && (p.s[p.i] == ' ' || p.s[p.i] == '\t') {
+		p.i++
+	}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLCAlpha(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+func isAlpha(c byte) bool {
+	return isLCAlpha(c) || (c >= 'A' && c <= 'Z')
+}
+
+func isTokenChar(c byte) bool {
+	return isAlpha(c) || isDigit(c) || strings.IndexByte("!#$%&'*+-.^_`|~:/", c) >= 0
+}
+
+// serializeInnerList serializes an inner list of strings with its parameters.
+func serializeInnerList(values []string, prms params) string {
+	var b strings.Builder
+
+	b.WriteByte('(')
+
+	for i, v := range values {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+
+		writeBareItem(&b, v)
+	}
+
+	b.WriteByte(')')
+
+	for _, prm := range prms {
+		b.WriteByte(';')
+		b.WriteString(prm.key)
+
+		if prm.value != true {
+			b.WriteByte('=')
+			writeBareItem(&b, prm.value)
+		}
+	}
+
+	return b.String()
+}
+
+func writeBareItem(b *strings.Builder, v interface{}) {
+	switch v := v.(type) {
+	case string:
+		b.WriteByte('"')
+		b.WriteString(strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v))
+		b.WriteByte('"')
+	case int64:
+		b.WriteString(strconv.FormatInt(v, 10))
+	case []byte:
+		b.WriteByte(':')
+		b.WriteString(base64.StdEncoding.EncodeToString(v))
+		b.WriteByte(':')
+	case token:
+		b.WriteString(string(v))
+	case bool:
+		if v {
+			b.WriteString("?1")
+		} else {
+			b.WriteString("?0")
+		}
+	}
+}
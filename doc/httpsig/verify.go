@@ -0,0 +1,247 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpsig
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/tink/go/keyset"
+
+	"github.com/trustbloc/kms-go/util/sigencoding"
+)
+
+// Signature is a verified HTTP message signature.
+type Signature struct {
+	Label      string
+	Components []string
+	KeyID      string
+	Algorithm  string
+	// Created and Expires are zero if the signature has no created or expires parameter.
+	Created time.Time
+	Expires time.Time
+	Nonce   string
+	Tag     string
+}
+
+// Verify verifies the signature of req selected with WithLabel. The signature is verified with the key of its keyid
+// parameter unless WithKeyID is set, the key algorithm must match the alg parameter if any. An expired signature is
+// rejected.
+func (s *Service) Verify(req *http.Request, options ...Opt) (*Signature, error) {
+	o := newOpts(options)
+
+	sig, sigValue, prms, err := findSignature(req, o.label)
+	if err != nil {
+		return nil, fmt.Errorf("httpsig verify: %w", err)
+	}
+
+	if err = o.check(sig); err != nil {
+		return nil, fmt.Errorf("httpsig verify: %w", err)
+	}
+
+	keyID := o.keyID
+	if keyID == "" {
+		keyID = sig.KeyID
+	}
+
+	if keyID == "" {
+		return nil, errors.New("httpsig verify: missing key ID")
+	}
+
+	k, err := s.key(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("httpsig verify: %w", err)
+	}
+
+	if sig.Algorithm != "" && sig.Algorithm != k.alg {
+		return nil, fmt.Errorf("httpsig verify: alg parameter '%s' does not match key algorithm '%s'",
+			sig.Algorithm, k.alg)
+	}
+
+	if contains(sig.Components, componentContentDigest) {
+		if err = verifyContentDigest(req); err != nil {
+			return nil, fmt.Errorf("httpsig verify: %w", err)
+		}
+	}
+
+	base, err := signatureBase(req, sig.Components, serializeInnerList(sig.Components, prms))
+	if err != nil {
+		return nil, fmt.Errorf("httpsig verify: %w", err)
+	}
+
+	if err = s.verify(sigValue, base, k); err != nil {
+		return nil, fmt.Errorf("httpsig verify: %w", err)
+	}
+
+	sig.Algorithm = k.alg
+
+	return sig, nil
+}
+
+// check checks the covered components, the times and the tag of sig against the options.
+func (o *opts) check(sig *Signature) error {
+	for _, c := range o.required {
+		if !contains(sig.Components, c) {
+			return fmt.Errorf("component '%s' is not covered", c)
+		}
+	}
+
+	now := o.now()
+
+	if !sig.Expires.IsZero() && !now.Before(sig.Expires) {
+		return errors.New("signature expired")
+	}
+
+	if o.maxAge > 0 && (sig.Created.IsZero() || now.Sub(sig.Created) > o.maxAge) {
+		return errors.New("signature too old")
+	}
+
+	if o.tag != "" && sig.Tag != o.tag {
+		return fmt.Errorf("unexpected tag '%s'", sig.Tag)
+	}
+
+	return nil
+}
+
+// findSignature returns the signature of req labeled label, or its first signature if label is empty, with its
+// value and its parameters.
+func findSignature(req *http.Request, label string) (*Signature, []byte, params, error) {
+	inputs, err := parseDictionary(strings.Join(req.Header.Values(HeaderSignatureInput), ", "))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("parse signature input: %w", err)
+	}
+
+	values, err := parseDictionary(strings.Join(req.Header.Values(HeaderSignature), ", "))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("parse signature: %w", err)
+	}
+
+	var input *member
+
+	for i := range inputs {
+		if label == "" || inputs[i].key == label {
+			input = &inputs[i]
+
+			break
+		}
+	}
+
+	if input == nil {
+		return nil, nil, nil, errors.New("missing signature")
+	}
+
+	var sigValue []byte
+
+	for _, v := range values {
+		if b, ok := v.item.value.([]byte); ok && !v.isList && v.key == input.key {
+			sigValue = b
+		}
+	}
+
+	if sigValue == nil {
+		return nil, nil, nil, fmt.Errorf("missing signature value of '%s'", input.key)
+	}
+
+	sig, err := parseSignatureInput(input)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("signature input '%s': %w", input.key, err)
+	}
+
+	return sig, sigValue, input.item.params, nil
+}
+
+func parseSignatureInput(input *member) (*Signature, error) {
+	if !input.isList {
+		return nil, errors.New("not an inner list")
+	}
+
+	sig := &Signature{Label: input.key}
+
+	for _, it := range input.list {
+		c, ok := it.value.(string)
+		if !ok || len(it.params) > 0 {
+			return nil, errors.New("unsupported component")
+		}
+
+		sig.Components = append(sig.Components, c)
+	}
+
+	for _, prm := range input.item.params {
+		var ok bool
+
+		switch prm.key {
+		case "created":
+			sig.Created, ok = unixTime(prm.value)
+		case "expires":
+			sig.Expires, ok = unixTime(prm.value)
+		case "nonce":
+			sig.Nonce, ok = prm.value.(string)
+		case "alg":
+			sig.Algorithm, ok = prm.value.(string)
+		case "keyid":
+			sig.KeyID, ok = prm.value.(string)
+		case "tag":
+			sig.Tag, ok = prm.value.(string)
+		default:
+			ok = true
+		}
+
+		if !ok {
+			return nil, fmt.Errorf("invalid parameter '%s'", prm.key)
+		}
+	}
+
+	return sig, nil
+}
+
+func unixTime(v interface{}) (time.Time, bool) {
+	n, ok := v.(int64)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return time.Unix(n, 0), true
+}
+
+func (s *Service) verify(sig, base []byte, k *signingKey) error {
+	if k.alg == AlgHMACSHA256 {
+		if err := s.crypto.VerifyMAC(append(append([]byte{}, k.macPrefix...), sig...), base, k.kh); err != nil {
+			return fmt.Errorf("verify MAC: %w", err)
+		}
+
+		return nil
+	}
+
+	if k.alg == AlgECDSAP256SHA256 || k.alg == AlgECDSAP384SHA384 {
+		var err error
+
+		if sig, err = sigencoding.ConvertToKeyType(sig, k.kt, sigencoding.IEEEP1363); err != nil {
+			return err
+		}
+	}
+
+	kh := k.kh
+
+	// Tink verifiers require public keyset handles.
+	if handle, ok := kh.(*keyset.Handle); ok {
+		pubKH, err := handle.Public()
+		if err != nil {
+			return fmt.Errorf("get public key handle: %w", err)
+		}
+
+		kh = pubKH
+	}
+
+	if err := s.crypto.Verify(sig, base, kh); err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+
+	return nil
+}
@@ -90,6 +90,10 @@ const (
 	// A256GCMALG is the default content encryption algorithm value as per
 	// the JWA specification: https://tools.ietf.org/html/rfc7518#section-5.1
 	A256GCMALG = "A256GCM"
+	// A128GCMALG represents AES128GCM content encryption algorithm value.
+	A128GCMALG = "A128GCM"
+	// A192GCMALG represents AES192GCM content encryption algorithm value.
+	A192GCMALG = "A192GCM"
 	// XC20PALG represents XChacha20Poly1305 content encryption algorithm value.
 	XC20PALG = "XC20P"
 	// A128CBCHS256ALG represents AES_128_CBC_HMAC_SHA_256 encryption algorithm value.
@@ -104,6 +108,8 @@ const (
 
 var aeadAlg = map[EncAlg]ecdh.AEADAlg{ //nolint:gochecknoglobals
 	A256GCM:      ecdh.AES256GCM,
+	A128GCM:      ecdh.AES128GCM,
+	A192GCM:      ecdh.AES192GCM,
 	XC20P:        ecdh.XC20P,
 	A128CBCHS256: ecdh.AES128CBCHMACSHA256,
 	A192CBCHS384: ecdh.AES192CBCHMACSHA384,
@@ -22,6 +22,7 @@ import (
 	ecdhpb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
 	"github.com/trustbloc/kms-go/doc/jose/jwk"
 	resolver "github.com/trustbloc/kms-go/doc/jose/kidresolver"
+	"github.com/trustbloc/kms-go/util/profiles"
 
 	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
 	"github.com/trustbloc/kms-go/spi/kms"
@@ -38,16 +39,35 @@ type JWEDecrypt struct {
 	kidResolvers []resolver.KIDResolver
 	crypto       cryptoapi.Crypto
 	kms          kms.KeyManager
+	profile      *profiles.Profile
+}
+
+// JWEDecryptOpt is a NewJWEDecrypt option.
+type JWEDecryptOpt func(jd *JWEDecrypt)
+
+// WithDecryptProfile restricts the JWE algorithms to the alg and enc combinations allowed by p: decrypting a JWE with
+// another combination fails with profiles.ErrNotAllowed, before any key is unwrapped.
+func WithDecryptProfile(p *profiles.Profile) JWEDecryptOpt {
+	return func(jd *JWEDecrypt) {
+		jd.profile = p
+	}
 }
 
 // NewJWEDecrypt creates a new JWEDecrypt instance to parse and decrypt a JWE message for a given recipient
 // store is needed for Authcrypt only (to fetch sender's pre agreed upon public key), it is not needed for Anoncrypt.
-func NewJWEDecrypt(kidResolvers []resolver.KIDResolver, c cryptoapi.Crypto, k kms.KeyManager) *JWEDecrypt {
-	return &JWEDecrypt{
+func NewJWEDecrypt(kidResolvers []resolver.KIDResolver, c cryptoapi.Crypto, k kms.KeyManager,
+	opts ...JWEDecryptOpt) *JWEDecrypt {
+	jd := &JWEDecrypt{
 		kidResolvers: kidResolvers,
 		crypto:       c,
 		kms:          k,
 	}
+
+	for _, opt := range opts {
+		opt(jd)
+	}
+
+	return jd
 }
 
 func getECDHDecPrimitive(cek []byte, encAlg EncAlg, nistpKW bool) (api.CompositeDecrypt, error) {
@@ -95,6 +115,10 @@ func (jd *JWEDecrypt) Decrypt(jwe *JSONWebEncryption) ([]byte, error) {
 		return nil, fmt.Errorf("jwedecrypt: failed to build recipients WK: %w", err)
 	}
 
+	if err = jd.checkProfile(recWK, encAlg); err != nil {
+		return nil, fmt.Errorf("jwedecrypt: %w", err)
+	}
+
 	cek, err := jd.unwrapCEK(recWK, wkOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("jwedecrypt: %w", err)
@@ -113,6 +137,22 @@ func (jd *JWEDecrypt) Decrypt(jwe *JSONWebEncryption) ([]byte, error) {
 	return jd.decryptJWE(jwe, cek)
 }
 
+// checkProfile returns profiles.ErrNotAllowed if the key of a recipient of recWK is wrapped with an algorithm that
+// is not allowed with the content encryption algorithm encAlg by the profile of jd.
+func (jd *JWEDecrypt) checkProfile(recWK []*cryptoapi.RecipientWrappedKey, encAlg string) error {
+	if jd.profile == nil {
+		return nil
+	}
+
+	for _, rec := range recWK {
+		if err := jd.profile.CheckJWE(rec.Alg, encAlg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func fetchSKIDFromAPU(jwe *JSONWebEncryption) (string, bool) {
 	// for multi-recipients only: check apu in protectedHeaders if it's found for ECDH-1PU, if skid header is empty then
 	// use apu as skid instead.
@@ -260,7 +300,7 @@ func (jd *JWEDecrypt) validateAndExtractProtectedHeaders(jwe *JSONWebEncryption)
 	}
 
 	switch encAlg {
-	case string(A256GCM), string(XC20P), string(A128CBCHS256),
+	case string(A256GCM), string(A128GCM), string(A192GCM), string(XC20P), string(A128CBCHS256),
 		string(A192CBCHS384), string(A256CBCHS384), string(A256CBCHS512):
 	default:
 		return "", fmt.Errorf("encryption algorithm '%s' not supported", encAlg)
@@ -34,6 +34,7 @@ import (
 	ecdhpb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
 	"github.com/trustbloc/kms-go/doc/jose/jwk"
 	"github.com/trustbloc/kms-go/util/cryptoutil"
+	"github.com/trustbloc/kms-go/util/profiles"
 
 	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
 )
@@ -44,6 +45,10 @@ type EncAlg string
 const (
 	// A256GCM for AES256GCM content encryption.
 	A256GCM = EncAlg(A256GCMALG)
+	// A128GCM for AES128GCM content encryption.
+	A128GCM = EncAlg(A128GCMALG)
+	// A192GCM for AES192GCM content encryption.
+	A192GCM = EncAlg(A192GCMALG)
 	// XC20P for XChacha20Poly1305 content encryption.
 	XC20P = EncAlg(XC20PALG)
 	// A128CBCHS256 for A128CBC-HS256 (AES128-CBC+HMAC-SHA256) content encryption.
@@ -75,18 +80,42 @@ type JWEEncrypt struct {
 	encTyp         string
 	cty            string
 	crypto         cryptoapi.Crypto
+	apu            []byte
+	apv            []byte
+	profile        *profiles.Profile
+}
+
+// JWEEncryptOpt is a NewJWEEncrypt option.
+type JWEEncryptOpt func(je *JWEEncrypt)
+
+// WithAgreementPartyInfo sets the apu (Agreement PartyUInfo) and apv (Agreement PartyVInfo) values used in the key
+// derivation of Anoncrypt (ECDH-ES) recipients. Authcrypt (ECDH-1PU) always builds them from skid and the recipients
+// kids.
+func WithAgreementPartyInfo(apu, apv []byte) JWEEncryptOpt {
+	return func(je *JWEEncrypt) {
+		je.apu = apu
+		je.apv = apv
+	}
+}
+
+// WithEncryptProfile restricts the JWE algorithms to the alg and enc combinations allowed by p: encrypting for a
+// recipient key wrapped with another algorithm fails with profiles.ErrNotAllowed.
+func WithEncryptProfile(p *profiles.Profile) JWEEncryptOpt {
+	return func(je *JWEEncrypt) {
+		je.profile = p
+	}
 }
 
 // NewJWEEncrypt creates a new JWEEncrypt instance to build JWE with recipientsPubKeys
 // senderKID and senderKH are used for Authcrypt (to authenticate the sender), if not set JWEEncrypt assumes Anoncrypt.
 func NewJWEEncrypt(encAlg EncAlg, envelopMediaType, cty, senderKID string, senderKH *keyset.Handle,
-	recipientsPubKeys []*cryptoapi.PublicKey, crypto cryptoapi.Crypto) (*JWEEncrypt, error) {
+	recipientsPubKeys []*cryptoapi.PublicKey, crypto cryptoapi.Crypto, opts ...JWEEncryptOpt) (*JWEEncrypt, error) {
 	if len(recipientsPubKeys) == 0 {
 		return nil, fmt.Errorf("empty recipientsPubKeys list")
 	}
 
 	switch encAlg {
-	case A256GCM, XC20P, A128CBCHS256, A192CBCHS384, A256CBCHS384, A256CBCHS512:
+	case A256GCM, A128GCM, A192GCM, XC20P, A128CBCHS256, A192CBCHS384, A256CBCHS384, A256CBCHS512:
 	default:
 		return nil, fmt.Errorf("encryption algorithm '%s' not supported", encAlg)
 	}
@@ -102,7 +131,7 @@ func NewJWEEncrypt(encAlg EncAlg, envelopMediaType, cty, senderKID string, sende
 		}
 	}
 
-	return &JWEEncrypt{
+	je := &JWEEncrypt{
 		recipientsKeys: recipientsPubKeys,
 		skid:           senderKID,
 		senderKH:       senderKH,
@@ -110,7 +139,13 @@ func NewJWEEncrypt(encAlg EncAlg, envelopMediaType, cty, senderKID string, sende
 		encTyp:         envelopMediaType,
 		cty:            cty,
 		crypto:         crypto,
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(je)
+	}
+
+	return je, nil
 }
 
 func (je *JWEEncrypt) getECDHEncPrimitive(cek []byte) (api.CompositeEncrypt, error) {
@@ -173,7 +208,7 @@ func (je *JWEEncrypt) EncryptWithAuthData(plaintext, aad []byte) (*JSONWebEncryp
 
 func (je *JWEEncrypt) encrypt(protectedHeaders map[string]interface{}, encPrimitive api.CompositeEncrypt,
 	plaintext, authData, cek, aad []byte) (*JSONWebEncryption, error) {
-	recipients, singleRecipientHeaderADDs, err := je.wrapCEKForRecipients(cek, []byte{}, []byte{}, authData, json.Marshal)
+	recipients, singleRecipientHeaderADDs, err := je.wrapCEKForRecipients(cek, je.apu, je.apv, authData, json.Marshal)
 	if err != nil {
 		return nil, fmt.Errorf("jweencrypt: failed to wrap cek: %w", err)
 	}
@@ -323,6 +358,12 @@ func (je *JWEEncrypt) wrapKey(cek, apu, apv, aad []byte, wrapOpts []cryptoapi.Wr
 			return nil, nil, fmt.Errorf("wrapKey: %d failed: %w", i+1, err)
 		}
 
+		if je.profile != nil {
+			if err = je.profile.CheckJWE(kek.Alg, string(je.encAlg)); err != nil {
+				return nil, nil, fmt.Errorf("wrapKey: %d failed: %w", i+1, err)
+			}
+		}
+
 		je.encodeAPUAPV(kek)
 
 		recipientsWK = append(recipientsWK, kek)
@@ -570,6 +611,10 @@ func (je *JWEEncrypt) newCEK() []byte {
 	switch je.encAlg {
 	case A256GCM, XC20P:
 		return random.GetRandomBytes(uint32(defKeySize))
+	case A128GCM:
+		return random.GetRandomBytes(uint32(subtle.AES128Size)) // cek: 16 bytes.
+	case A192GCM:
+		return random.GetRandomBytes(uint32(subtle.AES192Size)) // cek: 24 bytes.
 	case A128CBCHS256:
 		return random.GetRandomBytes(uint32(subtle.AES128Size * twoKeys)) // cek: 32 bytes.
 	case A192CBCHS384:
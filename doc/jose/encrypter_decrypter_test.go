@@ -43,6 +43,7 @@ import (
 
 	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
 	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/util/profiles"
 )
 
 const (
@@ -935,6 +936,66 @@ func TestInteropWithLocalJoseEncryptAndGoJoseDecryptUsingCompactSerialization(t
 	require.EqualValues(t, pt, msg)
 }
 
+func TestInteropWithGoJoseAESGCMContentEncryption(t *testing.T) {
+	recECKeys, recKHs, recKIDs, _ := createRecipients(t, 1)
+	gjRecipients := convertToGoJoseRecipients(t, recECKeys, recKIDs)
+
+	c, k := createCryptoAndKMSServices(t, recKHs)
+
+	recPrivKey, err := ecdsa.GenerateKey(subtle.GetCurve("NIST_P256"), rand.Reader)
+	require.NoError(t, err)
+
+	recPubKey := &cryptoapi.PublicKey{
+		X:     recPrivKey.PublicKey.X.Bytes(),
+		Y:     recPrivKey.PublicKey.Y.Bytes(),
+		Curve: recPrivKey.PublicKey.Curve.Params().Name,
+		Type:  "EC",
+	}
+
+	for encAlg, gjEncAlg := range map[ariesjose.EncAlg]jose.ContentEncryption{
+		ariesjose.A128GCM: jose.A128GCM,
+		ariesjose.A192GCM: jose.A192GCM,
+	} {
+		t.Run(string(encAlg)+" go-jose encrypt and local decrypt", func(t *testing.T) {
+			gjEncrypter, err := jose.NewEncrypter(gjEncAlg, gjRecipients[0], nil)
+			require.NoError(t, err)
+
+			gjJWE, err := gjEncrypter.Encrypt([]byte("Test secret message"))
+			require.NoError(t, err)
+
+			gjSerializedJWE, err := gjJWE.CompactSerialize()
+			require.NoError(t, err)
+
+			localJWE, err := ariesjose.Deserialize(gjSerializedJWE)
+			require.NoError(t, err)
+
+			msg, err := ariesjose.NewJWEDecrypt(nil, c, k).Decrypt(localJWE)
+			require.NoError(t, err)
+			require.EqualValues(t, []byte("Test secret message"), msg)
+		})
+
+		t.Run(string(encAlg)+" local encrypt and go-jose decrypt", func(t *testing.T) {
+			jweEncrypter, err := ariesjose.NewJWEEncrypt(encAlg, EnvelopeEncodingType, DIDCommContentEncodingType,
+				"", nil, []*cryptoapi.PublicKey{recPubKey}, c)
+			require.NoError(t, err)
+
+			jwe, err := jweEncrypter.Encrypt([]byte("some msg"))
+			require.NoError(t, err)
+
+			serializedJWE, err := jwe.CompactSerialize(json.Marshal)
+			require.NoError(t, err)
+
+			gjParsedJWE, err := jose.ParseEncrypted(serializedJWE)
+			require.NoError(t, err)
+			require.Equal(t, string(gjEncAlg), gjParsedJWE.Header.ExtraHeaders[jose.HeaderKey("enc")])
+
+			msg, err := gjParsedJWE.Decrypt(recPrivKey)
+			require.NoError(t, err)
+			require.EqualValues(t, []byte("some msg"), msg)
+		})
+	}
+}
+
 func convertToGoJoseRecipients(t *testing.T, keys []*cryptoapi.PublicKey, kids []string) []jose.Recipient {
 	t.Helper()
 
@@ -1085,6 +1146,61 @@ func getPrintedX25519PubKey(t *testing.T, pubKeyType *cryptoapi.PublicKey) strin
 	return strings.Replace(jwkStr, "Ed25519", "X25519", 1)
 }
 
+func TestJWEWithProfile(t *testing.T) {
+	didcomm, err := profiles.Get(profiles.DIDCommV2)
+	require.NoError(t, err)
+
+	recipients, recKHs, _, _ := createRecipients(t, 2)
+	cryptoSvc, kmsSvc := createCryptoAndKMSServices(t, recKHs)
+
+	plaintext := []byte("secret message")
+
+	jweEncrypter, err := ariesjose.NewJWEEncrypt(ariesjose.A256GCM, EnvelopeEncodingType, "", "", nil,
+		recipients, cryptoSvc, ariesjose.WithEncryptProfile(didcomm))
+	require.NoError(t, err)
+
+	jwe := encryptAndDeserialize(t, jweEncrypter, plaintext)
+
+	msg, err := ariesjose.NewJWEDecrypt(nil, cryptoSvc, kmsSvc, ariesjose.WithDecryptProfile(didcomm)).Decrypt(jwe)
+	require.NoError(t, err)
+	require.Equal(t, plaintext, msg)
+
+	jweEncrypter, err = ariesjose.NewJWEEncrypt(ariesjose.A128GCM, EnvelopeEncodingType, "", "", nil,
+		recipients, cryptoSvc, ariesjose.WithEncryptProfile(didcomm))
+	require.NoError(t, err)
+
+	_, err = jweEncrypter.Encrypt(plaintext)
+	require.ErrorIs(t, err, profiles.ErrNotAllowed)
+
+	// the JWE is encrypted without profile.
+	jweEncrypter, err = ariesjose.NewJWEEncrypt(ariesjose.A128GCM, EnvelopeEncodingType, "", "", nil,
+		recipients, cryptoSvc)
+	require.NoError(t, err)
+
+	jwe = encryptAndDeserialize(t, jweEncrypter, plaintext)
+
+	_, err = ariesjose.NewJWEDecrypt(nil, cryptoSvc, kmsSvc, ariesjose.WithDecryptProfile(didcomm)).Decrypt(jwe)
+	require.ErrorIs(t, err, profiles.ErrNotAllowed)
+	require.EqualError(t, err, "jwedecrypt: profiles: not allowed by the profile: profile 'didcomm-v2': JWE "+
+		"algorithm 'ECDH-ES+A256KW' with encryption 'A128GCM'")
+}
+
+func encryptAndDeserialize(t *testing.T, jweEncrypter *ariesjose.JWEEncrypt,
+	plaintext []byte) *ariesjose.JSONWebEncryption {
+	t.Helper()
+
+	jwe, err := jweEncrypter.Encrypt(plaintext)
+	require.NoError(t, err)
+
+	serializedJWE, err := jwe.FullSerialize(json.Marshal)
+	require.NoError(t, err)
+
+	jwe, err = ariesjose.Deserialize(serializedJWE)
+	require.NoError(t, err)
+
+	return jwe
+}
+
 func TestFailNewJWEEncrypt(t *testing.T) {
 	c, err := tinkcrypto.New()
 	require.NoError(t, err)
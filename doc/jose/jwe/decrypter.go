@@ -0,0 +1,113 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwe
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/trustbloc/kms-go/doc/jose"
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	resolver "github.com/trustbloc/kms-go/doc/jose/kidresolver"
+	"github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+// Decrypter decrypts JWE messages addressed to keys of a KeyManager.
+type Decrypter struct {
+	dec *jose.JWEDecrypt
+}
+
+// DecrypterOpt is a Decrypter option.
+type DecrypterOpt func(o *decOpts)
+
+type decOpts struct {
+	resolvers []resolver.KIDResolver
+}
+
+// WithSenderResolvers adds resolvers for the Authcrypt "skid" header.
+func WithSenderResolvers(resolvers ...resolver.KIDResolver) DecrypterOpt {
+	return func(o *decOpts) {
+		o.resolvers = append(o.resolvers, resolvers...)
+	}
+}
+
+// WithSenderJWKs resolves the Authcrypt "skid" header to the JWK with the same "kid" among jwks.
+func WithSenderJWKs(jwks ...*jwk.JWK) DecrypterOpt {
+	return func(o *decOpts) {
+		o.resolvers = append(o.resolvers, jwkResolver(jwks))
+	}
+}
+
+// NewDecrypter creates a new JWE Decrypter. Recipient keys are looked up in km by their "kid". The "skid" of Authcrypt
+// messages is resolved with the configured resolvers, then as a key ID of km.
+func NewDecrypter(km kms.KeyManager, c crypto.Crypto, opts ...DecrypterOpt) *Decrypter {
+	o := &decOpts{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	resolvers := append(o.resolvers, &KMSResolver{KMS: km}) //nolint:gocritic
+
+	return &Decrypter{dec: jose.NewJWEDecrypt(resolvers, c, km)}
+}
+
+// Decrypt parses a JWE in compact or JSON serialization and returns its plaintext.
+func (d *Decrypter) Decrypt(serialized string) ([]byte, error) {
+	jwe, err := jose.Deserialize(serialized)
+	if err != nil {
+		return nil, fmt.Errorf("jwe decrypt: %w", err)
+	}
+
+	return d.DecryptJWE(jwe)
+}
+
+// DecryptJWE decrypts a deserialized JWE and returns its plaintext.
+func (d *Decrypter) DecryptJWE(jwe *jose.JSONWebEncryption) ([]byte, error) {
+	pt, err := d.dec.Decrypt(jwe)
+	if err != nil {
+		return nil, fmt.Errorf("jwe decrypt: %w", err)
+	}
+
+	return pt, nil
+}
+
+// KMSResolver resolves a "kid"/"skid" as the ID of an ECDH key in a KeyManager.
+type KMSResolver struct {
+	KMS kms.KeyManager
+}
+
+// Resolve exports the public key of the KMS key kid.
+func (r *KMSResolver) Resolve(kid string) (*crypto.PublicKey, error) {
+	b, _, err := r.KMS.ExportPubKeyBytes(kid)
+	if err != nil {
+		return nil, fmt.Errorf("kmsResolver: export public key: %w", err)
+	}
+
+	pub := &crypto.PublicKey{}
+
+	if err = json.Unmarshal(b, pub); err != nil {
+		return nil, fmt.Errorf("kmsResolver: unmarshal public key: %w", err)
+	}
+
+	pub.KID = kid
+
+	return pub, nil
+}
+
+type jwkResolver []*jwk.JWK
+
+func (r jwkResolver) Resolve(kid string) (*crypto.PublicKey, error) {
+	for _, j := range r {
+		if j.KeyID == kid {
+			return publicKeyFromJWK(j)
+		}
+	}
+
+	return nil, fmt.Errorf("jwkResolver: kid '%s' not found", kid)
+}
@@ -0,0 +1,217 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package jwe encrypts and decrypts JSON Web Encryption messages (https://tools.ietf.org/html/rfc7516) for recipients
+// identified by KMS key IDs or JWKs. Messages are encrypted with ECDH-ES (Anoncrypt) or, when a sender key is set,
+// ECDH-1PU (Authcrypt) key wrapping.
+package jwe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/keyset"
+
+	"github.com/trustbloc/kms-go/doc/jose"
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+	"github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+// Recipient is a JWE recipient, identified either by the ID of its ECDH key in the Encrypter's KMS or by its public
+// JWK. JWK recipients must have a "kid" set.
+type Recipient struct {
+	KeyID string
+	JWK   *jwk.JWK
+}
+
+// Encrypter creates JWE messages with keys from a KeyManager and key wrapping from a Crypto service.
+type Encrypter struct {
+	km     kms.KeyManager
+	crypto crypto.Crypto
+	opts   *encOpts
+}
+
+// EncrypterOpt is an Encrypter option.
+type EncrypterOpt func(o *encOpts)
+
+type encOpts struct {
+	encAlg    jose.EncAlg
+	mediaType string
+	cty       string
+	senderKID string
+	apu       []byte
+	apv       []byte
+}
+
+// WithContentEncryption sets the content encryption algorithm ("enc" header). The default is A256GCM for Anoncrypt
+// and A256CBC-HS512 for Authcrypt, which only supports the CBC-HMAC algorithms.
+func WithContentEncryption(enc jose.EncAlg) EncrypterOpt {
+	return func(o *encOpts) {
+		o.encAlg = enc
+	}
+}
+
+// WithMediaType sets the "typ" protected header.
+func WithMediaType(typ string) EncrypterOpt {
+	return func(o *encOpts) {
+		o.mediaType = typ
+	}
+}
+
+// WithContentType sets the "cty" protected header.
+func WithContentType(cty string) EncrypterOpt {
+	return func(o *encOpts) {
+		o.cty = cty
+	}
+}
+
+// WithSender enables Authcrypt (ECDH-1PU) with the ECDH key senderKID of the Encrypter's KMS. The "skid" header is set
+// to senderKID so recipients must be able to resolve it to the sender public key.
+func WithSender(senderKID string) EncrypterOpt {
+	return func(o *encOpts) {
+		o.senderKID = senderKID
+	}
+}
+
+// WithAgreementPartyInfo sets the Anoncrypt apu and apv values (https://tools.ietf.org/html/rfc7518#section-4.6.1).
+// Authcrypt derives them from the sender and recipients key IDs and ignores this option.
+func WithAgreementPartyInfo(apu, apv []byte) EncrypterOpt {
+	return func(o *encOpts) {
+		o.apu = apu
+		o.apv = apv
+	}
+}
+
+// NewEncrypter creates a new JWE Encrypter.
+func NewEncrypter(km kms.KeyManager, c crypto.Crypto, opts ...EncrypterOpt) *Encrypter {
+	o := &encOpts{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.encAlg == "" {
+		o.encAlg = jose.A256GCM
+
+		if o.senderKID != "" {
+			o.encAlg = jose.A256CBCHS512
+		}
+	}
+
+	return &Encrypter{km: km, crypto: c, opts: o}
+}
+
+// Encrypt encrypts plaintext and aad for recipients. All recipients must use keys of the same curve family (NIST P
+// curves or X25519).
+func (e *Encrypter) Encrypt(plaintext, aad []byte, recipients ...Recipient) (*jose.JSONWebEncryption, error) {
+	recKeys := make([]*crypto.PublicKey, 0, len(recipients))
+
+	for i, r := range recipients {
+		pub, err := e.recipientKey(r)
+		if err != nil {
+			return nil, fmt.Errorf("jwe encrypt: recipient %d: %w", i+1, err)
+		}
+
+		recKeys = append(recKeys, pub)
+	}
+
+	var senderKH *keyset.Handle
+
+	if e.opts.senderKID != "" {
+		kh, err := e.km.Get(e.opts.senderKID)
+		if err != nil {
+			return nil, fmt.Errorf("jwe encrypt: get sender key: %w", err)
+		}
+
+		var ok bool
+
+		senderKH, ok = kh.(*keyset.Handle)
+		if !ok {
+			return nil, errors.New("jwe encrypt: sender key is not a keyset handle")
+		}
+	}
+
+	enc, err := jose.NewJWEEncrypt(e.opts.encAlg, e.opts.mediaType, e.opts.cty, e.opts.senderKID, senderKH, recKeys,
+		e.crypto, jose.WithAgreementPartyInfo(e.opts.apu, e.opts.apv))
+	if err != nil {
+		return nil, fmt.Errorf("jwe encrypt: %w", err)
+	}
+
+	jwe, err := enc.EncryptWithAuthData(plaintext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("jwe encrypt: %w", err)
+	}
+
+	return jwe, nil
+}
+
+// EncryptJSON encrypts plaintext and aad for recipients and returns the JWE JSON serialization (flattened for a single
+// recipient without aad, general otherwise).
+func (e *Encrypter) EncryptJSON(plaintext, aad []byte, recipients ...Recipient) (string, error) {
+	jwe, err := e.Encrypt(plaintext, aad, recipients...)
+	if err != nil {
+		return "", err
+	}
+
+	return jwe.FullSerialize(json.Marshal)
+}
+
+// EncryptCompact encrypts plaintext for recipient and returns the JWE compact serialization.
+func (e *Encrypter) EncryptCompact(plaintext []byte, recipient Recipient) (string, error) {
+	jwe, err := e.Encrypt(plaintext, nil, recipient)
+	if err != nil {
+		return "", err
+	}
+
+	return jwe.CompactSerialize(json.Marshal)
+}
+
+func (e *Encrypter) recipientKey(r Recipient) (*crypto.PublicKey, error) {
+	if r.JWK != nil {
+		if r.JWK.KeyID == "" {
+			return nil, errors.New("missing JWK kid")
+		}
+
+		return publicKeyFromJWK(r.JWK)
+	}
+
+	if r.KeyID == "" {
+		return nil, errors.New("missing key ID or JWK")
+	}
+
+	b, kt, err := e.km.ExportPubKeyBytes(r.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("export public key: %w", err)
+	}
+
+	switch kt { //nolint:exhaustive
+	case kms.NISTP256ECDHKWType, kms.NISTP384ECDHKWType, kms.NISTP521ECDHKWType, kms.X25519ECDHKWType:
+	default:
+		return nil, fmt.Errorf("key type '%s' is not supported for JWE recipients", kt)
+	}
+
+	pub := &crypto.PublicKey{}
+
+	if err = json.Unmarshal(b, pub); err != nil {
+		return nil, fmt.Errorf("unmarshal public key: %w", err)
+	}
+
+	pub.KID = r.KeyID
+
+	return pub, nil
+}
+
+// publicKeyFromJWK converts j to a PublicKey, including the X25519 JWKs holding a raw []byte key.
+func publicKeyFromJWK(j *jwk.JWK) (*crypto.PublicKey, error) {
+	if x, ok := j.Key.([]byte); ok && j.Crv == "X25519" {
+		return &crypto.PublicKey{KID: j.KeyID, Curve: j.Crv, Type: j.Kty, X: x}, nil
+	}
+
+	return jwksupport.PublicKeyFromJWK(j)
+}
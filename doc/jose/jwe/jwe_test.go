@@ -0,0 +1,251 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwe
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/doc/jose"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func newKMS(t *testing.T) (kmsapi.KeyManager, cryptoapi.Crypto) {
+	t.Helper()
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	return mockkms.NewForTest(t), cr
+}
+
+func createKeys(t *testing.T, km kmsapi.KeyManager, kt kmsapi.KeyType, count int) []string {
+	t.Helper()
+
+	var kids []string
+
+	for i := 0; i < count; i++ {
+		kid, _, err := km.Create(kt)
+		require.NoError(t, err)
+
+		kids = append(kids, kid)
+	}
+
+	return kids
+}
+
+func TestAnoncrypt(t *testing.T) {
+	km, cr := newKMS(t)
+	plaintext := []byte("secret message")
+
+	for _, kt := range []kmsapi.KeyType{
+		kmsapi.NISTP256ECDHKWType, kmsapi.NISTP384ECDHKWType, kmsapi.NISTP521ECDHKWType, kmsapi.X25519ECDHKWType,
+	} {
+		t.Run(string(kt), func(t *testing.T) {
+			kids := createKeys(t, km, kt, 3)
+			enc := NewEncrypter(km, cr, WithMediaType("application/example+jwe"))
+			dec := NewDecrypter(km, cr)
+
+			var recipients []Recipient
+
+			for _, kid := range kids {
+				recipients = append(recipients, Recipient{KeyID: kid})
+			}
+
+			serialized, err := enc.EncryptJSON(plaintext, []byte("aad"), recipients...)
+			require.NoError(t, err)
+
+			raw := map[string]json.RawMessage{}
+			require.NoError(t, json.Unmarshal([]byte(serialized), &raw))
+			require.Contains(t, raw, "recipients")
+			require.Contains(t, raw, "aad")
+
+			// every recipient can decrypt.
+			for _, kid := range kids {
+				pt, e := NewDecrypter(&singleKeyKMS{KeyManager: km, kid: kid}, cr).Decrypt(serialized)
+				require.NoError(t, e)
+				require.Equal(t, plaintext, pt)
+			}
+
+			compact, err := enc.EncryptCompact(plaintext, recipients[0])
+			require.NoError(t, err)
+			require.Len(t, strings.Split(compact, "."), 5)
+
+			pt, err := dec.Decrypt(compact)
+			require.NoError(t, err)
+			require.Equal(t, plaintext, pt)
+		})
+	}
+}
+
+func TestAuthcrypt(t *testing.T) {
+	senderKMS, cr := newKMS(t)
+	recKMS, _ := newKMS(t)
+
+	senderKID := createKeys(t, senderKMS, kmsapi.NISTP256ECDHKWType, 1)[0]
+	recKIDs := createKeys(t, recKMS, kmsapi.NISTP256ECDHKWType, 2)
+
+	var recipients []Recipient
+
+	for _, kid := range recKIDs {
+		pubBytes, kt, err := recKMS.ExportPubKeyBytes(kid)
+		require.NoError(t, err)
+
+		j, err := jwksupport.PubKeyBytesToJWK(pubBytes, kt)
+		require.NoError(t, err)
+
+		j.KeyID = kid
+		recipients = append(recipients, Recipient{JWK: j})
+	}
+
+	enc := NewEncrypter(senderKMS, cr, WithSender(senderKID))
+
+	jwe, err := enc.Encrypt([]byte("authenticated"), nil, recipients...)
+	require.NoError(t, err)
+
+	skid, ok := jwe.ProtectedHeaders.SenderKeyID()
+	require.True(t, ok)
+	require.Equal(t, senderKID, skid)
+
+	encAlg, _ := jwe.ProtectedHeaders.Encryption()
+	require.Equal(t, string(jose.A256CBCHS512), encAlg)
+
+	serialized, err := jwe.FullSerialize(json.Marshal)
+	require.NoError(t, err)
+
+	t.Run("unknown sender", func(t *testing.T) {
+		_, err = NewDecrypter(recKMS, cr).Decrypt(serialized)
+		require.ErrorContains(t, err, "failed to add sender public key for skid")
+	})
+
+	t.Run("sender JWK", func(t *testing.T) {
+		pubBytes, kt, e := senderKMS.ExportPubKeyBytes(senderKID)
+		require.NoError(t, e)
+
+		senderJWK, e := jwksupport.PubKeyBytesToJWK(pubBytes, kt)
+		require.NoError(t, e)
+
+		senderJWK.KeyID = senderKID
+
+		pt, e := NewDecrypter(recKMS, cr, WithSenderJWKs(senderJWK)).Decrypt(serialized)
+		require.NoError(t, e)
+		require.Equal(t, []byte("authenticated"), pt)
+	})
+
+	t.Run("sender in the same KMS", func(t *testing.T) {
+		kid := createKeys(t, senderKMS, kmsapi.NISTP256ECDHKWType, 1)[0]
+
+		compact, e := enc.EncryptCompact([]byte("local"), Recipient{KeyID: kid})
+		require.NoError(t, e)
+
+		pt, e := NewDecrypter(senderKMS, cr).Decrypt(compact)
+		require.NoError(t, e)
+		require.Equal(t, []byte("local"), pt)
+	})
+}
+
+func TestX25519JWKRecipient(t *testing.T) {
+	km, cr := newKMS(t)
+	kid := createKeys(t, km, kmsapi.X25519ECDHKWType, 1)[0]
+
+	pubBytes, _, err := km.ExportPubKeyBytes(kid)
+	require.NoError(t, err)
+
+	pub := &cryptoapi.PublicKey{}
+	require.NoError(t, json.Unmarshal(pubBytes, pub))
+
+	j, err := jwksupport.JWKFromX25519Key(pub.X)
+	require.NoError(t, err)
+
+	j.KeyID = kid
+
+	compact, err := NewEncrypter(km, cr, WithContentEncryption(jose.XC20P)).EncryptCompact([]byte("hi"),
+		Recipient{JWK: j})
+	require.NoError(t, err)
+
+	pt, err := NewDecrypter(km, cr).Decrypt(compact)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hi"), pt)
+}
+
+func TestAgreementPartyInfo(t *testing.T) {
+	km, cr := newKMS(t)
+	kid := createKeys(t, km, kmsapi.NISTP256ECDHKWType, 1)[0]
+
+	enc := NewEncrypter(km, cr, WithAgreementPartyInfo([]byte("Alice"), []byte("Bob")))
+
+	jwe, err := enc.Encrypt([]byte("msg"), nil, Recipient{KeyID: kid})
+	require.NoError(t, err)
+
+	require.Equal(t, base64.RawURLEncoding.EncodeToString([]byte("Alice")), jwe.ProtectedHeaders["apu"])
+	require.Equal(t, base64.RawURLEncoding.EncodeToString([]byte("Bob")), jwe.ProtectedHeaders["apv"])
+
+	serialized, err := jwe.FullSerialize(json.Marshal)
+	require.NoError(t, err)
+
+	pt, err := NewDecrypter(km, cr).Decrypt(serialized)
+	require.NoError(t, err)
+	require.Equal(t, []byte("msg"), pt)
+
+	// apu/apv are bound to the key derivation.
+	parsed, err := jose.Deserialize(serialized)
+	require.NoError(t, err)
+
+	parsed.ProtectedHeaders["apv"] = base64.RawURLEncoding.EncodeToString([]byte("Eve"))
+
+	_, err = NewDecrypter(km, cr).DecryptJWE(parsed)
+	require.Error(t, err)
+}
+
+func TestEncryptErrors(t *testing.T) {
+	km, cr := newKMS(t)
+	sigKID := createKeys(t, km, kmsapi.ED25519Type, 1)[0]
+
+	enc := NewEncrypter(km, cr)
+
+	_, err := enc.Encrypt([]byte("msg"), nil)
+	require.EqualError(t, err, "jwe encrypt: empty recipientsPubKeys list")
+
+	_, err = enc.Encrypt([]byte("msg"), nil, Recipient{})
+	require.EqualError(t, err, "jwe encrypt: recipient 1: missing key ID or JWK")
+
+	_, err = enc.Encrypt([]byte("msg"), nil, Recipient{KeyID: sigKID})
+	require.EqualError(t, err, "jwe encrypt: recipient 1: key type 'ED25519' is not supported for JWE recipients")
+
+	_, err = enc.Encrypt([]byte("msg"), nil, Recipient{KeyID: "missing"})
+	require.ErrorContains(t, err, "export public key")
+
+	_, err = NewEncrypter(km, cr, WithSender("missing")).Encrypt([]byte("msg"), nil, Recipient{KeyID: sigKID})
+	require.Error(t, err)
+
+	_, err = NewDecrypter(km, cr).Decrypt("not a jwe")
+	require.Error(t, err)
+}
+
+// singleKeyKMS only exposes kid, to check a recipient decrypts with its own key.
+type singleKeyKMS struct {
+	kmsapi.KeyManager
+	kid string
+}
+
+func (s *singleKeyKMS) Get(keyID string) (interface{}, error) {
+	if keyID != s.kid {
+		return nil, kms.ErrKeyNotFound
+	}
+
+	return s.KeyManager.Get(keyID)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwe
+
+import (
+	"errors"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/doc/jose"
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+// ErrNoCommonSuite is returned by Negotiate when no key wrapping and content encryption suite is supported by both
+// parties under the policy.
+var ErrNoCommonSuite = errors.New("no mutually supported JWE suite")
+
+// Peer is what a peer advertises, typically read from the keyAgreement keys of its DID document or from a JWKS.
+type Peer struct {
+	// Keys are the peer key agreement public keys. The "alg" of a key, if set, restricts its key wrapping algorithm.
+	Keys []*jwk.JWK
+	// KeyWrapping are the key wrapping algorithms ("alg" values) accepted by the peer, empty if not advertised.
+	KeyWrapping []string
+	// ContentEncryption are the content encryption algorithms ("enc" values) accepted by the peer, empty if not
+	// advertised.
+	ContentEncryption []string
+}
+
+// Suite is the outcome of a negotiation.
+type Suite struct {
+	// KeyType is the ECDH key type of the selected peer key, an Authcrypt sender key must be of the same type.
+	KeyType           kms.KeyType
+	KeyWrapping       string
+	ContentEncryption jose.EncAlg
+	// Recipient is the selected peer key.
+	Recipient *jwk.JWK
+}
+
+// Policy sets the negotiation preferences. Preferences are ordered from the most to the least preferred, algorithms
+// missing from a preference list are never selected.
+type Policy struct {
+	KeyTypes          []kms.KeyType
+	ContentEncryption []jose.EncAlg
+	// Authcrypt selects ECDH-1PU key wrapping (sender authenticated) instead of ECDH-ES.
+	Authcrypt bool
+}
+
+// DefaultPolicy prefers the strongest algorithms first: NIST P-521 and P-384 curves, then P-256 and X25519, and
+// CBC-HMAC or AES-GCM content encryption with 256-bit keys. A192GCM and A128GCM are only selected for peers
+// advertising no stronger content encryption.
+func DefaultPolicy() *Policy {
+	return &Policy{
+		KeyTypes: []kms.KeyType{
+			kms.NISTP521ECDHKWType, kms.NISTP384ECDHKWType, kms.NISTP256ECDHKWType, kms.X25519ECDHKWType,
+		},
+		ContentEncryption: []jose.EncAlg{
+			jose.A256CBCHS512, jose.A256GCM, jose.XC20P, jose.A256CBCHS384, jose.A192CBCHS384, jose.A128CBCHS256,
+			jose.A192GCM, jose.A128GCM,
+		},
+	}
+}
+
+// Negotiate selects the most preferred suite supported by local (the capabilities of the KMS and Crypto used to
+// encrypt, see kms.MergeCapabilities) and by peer under policy. A nil policy is DefaultPolicy. Content encryption
+// preferences take precedence over key type preferences, peer keys are considered in their advertised order.
+func Negotiate(local *kms.Capabilities, peer *Peer, policy *Policy) (*Suite, error) {
+	if policy == nil {
+		policy = DefaultPolicy()
+	}
+
+	for _, enc := range policy.ContentEncryption {
+		if !acceptContentEncryption(local, peer, policy, enc) {
+			continue
+		}
+
+		for _, kt := range policy.KeyTypes {
+			alg := keyWrappingAlg(kt, enc, policy.Authcrypt)
+			if alg == "" || !acceptKeyWrapping(local, peer, kt, alg) {
+				continue
+			}
+
+			for _, k := range peer.Keys {
+				if ecdhKeyType(k) == kt && (k.Algorithm == "" || k.Algorithm == alg) {
+					return &Suite{KeyType: kt, KeyWrapping: alg, ContentEncryption: enc, Recipient: k}, nil
+				}
+			}
+		}
+	}
+
+	return nil, ErrNoCommonSuite
+}
+
+func acceptContentEncryption(local *kms.Capabilities, peer *Peer, policy *Policy, enc jose.EncAlg) bool {
+	// Authcrypt only supports the CBC-HMAC content encryption algorithms.
+	if policy.Authcrypt && !isCBCHMAC(enc) {
+		return false
+	}
+
+	return contains(local.ContentEncryption, string(enc)) &&
+		(len(peer.ContentEncryption) == 0 || contains(peer.ContentEncryption, string(enc)))
+}
+
+func isCBCHMAC(enc jose.EncAlg) bool {
+	switch enc { //nolint:exhaustive
+	case jose.A128CBCHS256, jose.A192CBCHS384, jose.A256CBCHS384, jose.A256CBCHS512:
+		return true
+	default:
+		return false
+	}
+}
+
+func acceptKeyWrapping(local *kms.Capabilities, peer *Peer, kt kms.KeyType, alg string) bool {
+	if !local.Supports(kt, kms.OperationWrapKey) || !contains(local.KeyType(kt).Algorithms, alg) {
+		return false
+	}
+
+	return len(peer.KeyWrapping) == 0 || contains(peer.KeyWrapping, alg)
+}
+
+// keyWrappingAlg returns the key wrapping algorithm used by the Encrypter for kt keys and enc, empty if not supported.
+// ECDH-1PU with NIST P curves wraps the CEK with an AES key of the size of the CBC-HMAC CEK halves.
+func keyWrappingAlg(kt kms.KeyType, enc jose.EncAlg, authcrypt bool) string {
+	switch {
+	case kt == kms.X25519ECDHKWType && authcrypt:
+		return tinkcrypto.ECDH1PUXC20PKWAlg
+	case kt == kms.X25519ECDHKWType:
+		return tinkcrypto.ECDHESXC20PKWAlg
+	case !authcrypt:
+		return tinkcrypto.ECDHESA256KWAlg
+	}
+
+	switch enc { //nolint:exhaustive
+	case jose.A128CBCHS256:
+		return tinkcrypto.ECDH1PUA128KWAlg
+	case jose.A192CBCHS384:
+		return tinkcrypto.ECDH1PUA192KWAlg
+	case jose.A256CBCHS512:
+		return tinkcrypto.ECDH1PUA256KWAlg
+	default:
+		return ""
+	}
+}
+
+// ecdhKeyType returns the ECDH key type of a key agreement JWK, or an empty key type if the JWK is not an ECDH key.
+func ecdhKeyType(k *jwk.JWK) kms.KeyType {
+	kt, err := k.KeyType()
+	if err != nil {
+		return ""
+	}
+
+	switch kt { //nolint:exhaustive
+	case kms.X25519ECDHKWType:
+		return kms.X25519ECDHKWType
+	case kms.ECDSAP256TypeIEEEP1363:
+		return kms.NISTP256ECDHKWType
+	case kms.ECDSAP384TypeIEEEP1363:
+		return kms.NISTP384ECDHKWType
+	case kms.ECDSAP521TypeIEEEP1363:
+		return kms.NISTP521ECDHKWType
+	default:
+		return ""
+	}
+}
+
+func contains(values []string, v string) bool {
+	for _, e := range values {
+		if e == v {
+			return true
+		}
+	}
+
+	return false
+}
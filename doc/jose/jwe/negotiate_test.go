@@ -0,0 +1,164 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwe
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/doc/jose"
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+func localCapabilities(t *testing.T, km kmsapi.KeyManager, cr cryptoapi.Crypto) *kmsapi.Capabilities {
+	t.Helper()
+
+	kmsCaps, err := km.(kmsapi.CapabilitiesProvider).Capabilities()
+	require.NoError(t, err)
+
+	cryptoCaps, err := cr.(kmsapi.CapabilitiesProvider).Capabilities()
+	require.NoError(t, err)
+
+	return kmsapi.MergeCapabilities(kmsCaps, cryptoCaps)
+}
+
+func peerJWK(t *testing.T, km kmsapi.KeyManager, kt kmsapi.KeyType) *jwk.JWK {
+	t.Helper()
+
+	kid := createKeys(t, km, kt, 1)[0]
+
+	pubBytes, _, err := km.ExportPubKeyBytes(kid)
+	require.NoError(t, err)
+
+	var j *jwk.JWK
+
+	if kt == kmsapi.X25519ECDHKWType {
+		pub := &cryptoapi.PublicKey{}
+		require.NoError(t, json.Unmarshal(pubBytes, pub))
+
+		j, err = jwksupport.JWKFromX25519Key(pub.X)
+	} else {
+		j, err = jwksupport.PubKeyBytesToJWK(pubBytes, kt)
+	}
+
+	require.NoError(t, err)
+
+	j.KeyID = kid
+
+	return j
+}
+
+func TestNegotiate(t *testing.T) {
+	km, cr := newKMS(t)
+	peerKMS, _ := newKMS(t)
+
+	local := localCapabilities(t, km, cr)
+
+	p256 := peerJWK(t, peerKMS, kmsapi.NISTP256ECDHKWType)
+	x25519 := peerJWK(t, peerKMS, kmsapi.X25519ECDHKWType)
+
+	t.Run("default policy", func(t *testing.T) {
+		suite, err := Negotiate(local, &Peer{Keys: []*jwk.JWK{x25519, p256}}, nil)
+		require.NoError(t, err)
+		require.Equal(t, kmsapi.NISTP256ECDHKWType, suite.KeyType)
+		require.Equal(t, tinkcrypto.ECDHESA256KWAlg, suite.KeyWrapping)
+		require.Equal(t, jose.A256CBCHS512, suite.ContentEncryption)
+		require.Equal(t, p256, suite.Recipient)
+
+		compact, err := NewEncrypter(km, cr, WithContentEncryption(suite.ContentEncryption)).EncryptCompact(
+			[]byte("negotiated"), Recipient{JWK: suite.Recipient})
+		require.NoError(t, err)
+
+		pt, err := NewDecrypter(peerKMS, cr).Decrypt(compact)
+		require.NoError(t, err)
+		require.Equal(t, []byte("negotiated"), pt)
+	})
+
+	t.Run("peer advertised algorithms", func(t *testing.T) {
+		suite, err := Negotiate(local, &Peer{
+			Keys:              []*jwk.JWK{p256, x25519},
+			KeyWrapping:       []string{tinkcrypto.ECDHESXC20PKWAlg},
+			ContentEncryption: []string{string(jose.XC20P), string(jose.A128CBCHS256)},
+		}, nil)
+		require.NoError(t, err)
+		require.Equal(t, kmsapi.X25519ECDHKWType, suite.KeyType)
+		require.Equal(t, jose.XC20P, suite.ContentEncryption)
+		require.Equal(t, x25519, suite.Recipient)
+	})
+
+	t.Run("peer advertising AES-GCM with smaller keys", func(t *testing.T) {
+		for _, enc := range []jose.EncAlg{jose.A128GCM, jose.A192GCM} {
+			suite, err := Negotiate(local, &Peer{
+				Keys:              []*jwk.JWK{p256},
+				ContentEncryption: []string{string(enc)},
+			}, nil)
+			require.NoError(t, err)
+			require.Equal(t, enc, suite.ContentEncryption)
+
+			compact, err := NewEncrypter(km, cr, WithContentEncryption(suite.ContentEncryption)).EncryptCompact(
+				[]byte("negotiated"), Recipient{JWK: suite.Recipient})
+			require.NoError(t, err)
+
+			pt, err := NewDecrypter(peerKMS, cr).Decrypt(compact)
+			require.NoError(t, err)
+			require.Equal(t, []byte("negotiated"), pt)
+		}
+	})
+
+	t.Run("authcrypt", func(t *testing.T) {
+		policy := DefaultPolicy()
+		policy.Authcrypt = true
+
+		suite, err := Negotiate(local, &Peer{
+			Keys:              []*jwk.JWK{p256},
+			ContentEncryption: []string{string(jose.A256GCM), string(jose.A128CBCHS256)},
+		}, policy)
+		require.NoError(t, err)
+		require.Equal(t, tinkcrypto.ECDH1PUA128KWAlg, suite.KeyWrapping)
+		require.Equal(t, jose.A128CBCHS256, suite.ContentEncryption)
+
+		_, err = Negotiate(local, &Peer{
+			Keys:              []*jwk.JWK{p256},
+			ContentEncryption: []string{string(jose.A256GCM)},
+		}, policy)
+		require.ErrorIs(t, err, ErrNoCommonSuite)
+	})
+
+	t.Run("policy preferences", func(t *testing.T) {
+		suite, err := Negotiate(local, &Peer{Keys: []*jwk.JWK{p256, x25519}}, &Policy{
+			KeyTypes:          []kmsapi.KeyType{kmsapi.X25519ECDHKWType, kmsapi.NISTP256ECDHKWType},
+			ContentEncryption: []jose.EncAlg{jose.A256GCM},
+		})
+		require.NoError(t, err)
+		require.Equal(t, x25519, suite.Recipient)
+		require.Equal(t, jose.A256GCM, suite.ContentEncryption)
+	})
+
+	t.Run("no common suite", func(t *testing.T) {
+		_, err := Negotiate(local, &Peer{Keys: []*jwk.JWK{p256}}, &Policy{
+			KeyTypes:          []kmsapi.KeyType{kmsapi.NISTP384ECDHKWType},
+			ContentEncryption: []jose.EncAlg{jose.A256GCM},
+		})
+		require.ErrorIs(t, err, ErrNoCommonSuite)
+
+		// the key alg restricts its key wrapping algorithm.
+		restricted := *p256
+		restricted.Algorithm = tinkcrypto.ECDH1PUA256KWAlg
+
+		_, err = Negotiate(local, &Peer{Keys: []*jwk.JWK{&restricted}}, nil)
+		require.ErrorIs(t, err, ErrNoCommonSuite)
+
+		_, err = Negotiate(&kmsapi.Capabilities{}, &Peer{Keys: []*jwk.JWK{p256}}, nil)
+		require.ErrorIs(t, err, ErrNoCommonSuite)
+	})
+}
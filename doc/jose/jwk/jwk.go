@@ -34,7 +34,9 @@ const (
 	bitsPerByte    = 8
 	ecKty          = "EC"
 	okpKty         = "OKP"
+	octKty         = "oct"
 	x25519Crv      = "X25519"
+	x448Crv        = "X448"
 	ed25519Crv     = "Ed25519"
 	bls12381G2Crv  = "BLS12381_G2"
 	bls12381G2Size = 96
@@ -71,6 +73,15 @@ func (j *JWK) PublicKeyBytes() ([]byte, error) { //nolint:gocyclo
 		return x25519Key, nil
 	}
 
+	if j.isX448() {
+		x448Key, ok := j.Key.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("invalid public key in kid '%s'", j.KeyID)
+		}
+
+		return x448Key, nil
+	}
+
 	if j.isSecp256k1() {
 		var ecPubKey *ecdsa.PublicKey
 
@@ -132,6 +143,13 @@ func (j *JWK) UnmarshalJSON(jwkBytes []byte) error {
 			return fmt.Errorf("unable to read X25519 JWE: %w", err)
 		}
 
+		*j = *jwk
+	} else if isX448(key.Kty, key.Crv) {
+		jwk, err := unmarshalX448(&key)
+		if err != nil {
+			return fmt.Errorf("unable to read X448 JWE: %w", err)
+		}
+
 		*j = *jwk
 	} else {
 		var joseJWK jose.JSONWebKey
@@ -160,6 +178,10 @@ func (j *JWK) MarshalJSON() ([]byte, error) {
 		return marshalX25519(j)
 	}
 
+	if j.isX448() {
+		return marshalX448(j)
+	}
+
 	if j.isBLS12381G2() {
 		return marshalBLS12381G2(j)
 	}
@@ -185,15 +207,35 @@ func (j *JWK) KeyType() (kms.KeyType, error) {
 	switch {
 	case isX25519(j.Kty, j.Crv):
 		return kms.X25519ECDHKWType, nil
+	case isX448(j.Kty, j.Crv):
+		return kms.X448ECDHKWType, nil
 	case isEd25519(j.Kty, j.Crv):
 		return kms.ED25519Type, nil
 	case isSecp256k1(j.Algorithm, j.Kty, j.Crv):
 		return kms.ECDSASecp256k1TypeIEEEP1363, nil
+	case j.Kty == octKty:
+		return octKeyType(j.Algorithm)
 	default:
 		return "", fmt.Errorf("no keytype recognized for jwk")
 	}
 }
 
+// octKeyType returns the AEAD key type of an oct JWK from its content encryption algorithm alg.
+func octKeyType(alg string) (kms.KeyType, error) {
+	switch alg {
+	case "C20P":
+		return kms.ChaCha20Poly1305Type, nil
+	case "XC20P":
+		return kms.XChaCha20Poly1305Type, nil
+	case "A128GCM":
+		return kms.AES128GCMType, nil
+	case "A256GCM":
+		return kms.AES256GCMType, nil
+	default:
+		return "", fmt.Errorf("no keytype recognized for oct jwk with alg '%s'", alg)
+	}
+}
+
 func ecdsaPubKeyType(pub *ecdsa.PublicKey) (kms.KeyType, error) {
 	switch pub.Curve {
 	case btcec.S256():
@@ -218,6 +260,15 @@ func (j *JWK) isX25519() bool {
 	}
 }
 
+func (j *JWK) isX448() bool {
+	switch j.Key.(type) {
+	case []byte:
+		return isX448(j.Kty, j.Crv)
+	default:
+		return false
+	}
+}
+
 func (j *JWK) isBLS12381G2() bool {
 	switch j.Key.(type) {
 	case *bbs12381g2pub.PublicKey, *bbs12381g2pub.PrivateKey:
@@ -246,6 +297,10 @@ func isX25519(kty, crv string) bool {
 	return strings.EqualFold(kty, okpKty) && strings.EqualFold(crv, x25519Crv)
 }
 
+func isX448(kty, crv string) bool {
+	return strings.EqualFold(kty, okpKty) && strings.EqualFold(crv, x448Crv)
+}
+
 func isEd25519(kty, crv string) bool {
 	return strings.EqualFold(kty, okpKty) && strings.EqualFold(crv, ed25519Crv)
 }
@@ -358,6 +413,49 @@ func marshalX25519(jwk *JWK) ([]byte, error) {
 	return json.Marshal(raw)
 }
 
+func unmarshalX448(jwk *jsonWebKey) (*JWK, error) {
+	if jwk.X == nil {
+		return nil, ErrInvalidKey
+	}
+
+	if len(jwk.X.data) != cryptoutil.Curve448KeySize {
+		return nil, ErrInvalidKey
+	}
+
+	return &JWK{
+		JSONWebKey: jose.JSONWebKey{
+			Key: jwk.X.data, KeyID: jwk.Kid, Algorithm: jwk.Alg, Use: jwk.Use,
+		},
+		Crv: jwk.Crv,
+		Kty: jwk.Kty,
+	}, nil
+}
+
+func marshalX448(jwk *JWK) ([]byte, error) {
+	var raw jsonWebKey
+
+	key, ok := jwk.Key.([]byte)
+	if !ok {
+		return nil, errors.New("marshalX448: invalid key")
+	}
+
+	if len(key) != cryptoutil.Curve448KeySize {
+		return nil, errors.New("marshalX448: invalid key")
+	}
+
+	raw = jsonWebKey{
+		Kty: okpKty,
+		Crv: x448Crv,
+		X:   newFixedSizeBuffer(key, cryptoutil.Curve448KeySize),
+	}
+
+	raw.Kid = jwk.KeyID
+	raw.Alg = jwk.Algorithm
+	raw.Use = jwk.Use
+
+	return json.Marshal(raw)
+}
+
 func unmarshalBLS12381G2(jwk *jsonWebKey) (*JWK, error) {
 	if jwk.X == nil {
 		return nil, ErrInvalidKey
@@ -8,6 +8,7 @@ package jwk
 
 import (
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/sha256"
@@ -483,6 +484,27 @@ func TestJWK_KeyType(t *testing.T) {
 		}
 	})
 
+	t.Run("oct keys", func(t *testing.T) {
+		for alg, keyType := range map[string]kms.KeyType{
+			"C20P": kms.ChaCha20Poly1305Type, "XC20P": kms.XChaCha20Poly1305Type,
+			"A128GCM": kms.AES128GCMType, "A256GCM": kms.AES256GCMType,
+		} {
+			j := JWK{}
+			e := j.UnmarshalJSON([]byte(`{"kty":"oct","alg":"` + alg + `","k":"GawgguFyGrWKav7AX4VKUg"}`))
+			require.NoError(t, e)
+
+			kt, e := j.KeyType()
+			require.NoError(t, e)
+			require.Equal(t, keyType, kt)
+		}
+
+		j := JWK{}
+		require.NoError(t, j.UnmarshalJSON([]byte(`{"kty":"oct","alg":"HS256","k":"GawgguFyGrWKav7AX4VKUg"}`)))
+
+		_, e := j.KeyType()
+		require.EqualError(t, e, "no keytype recognized for oct jwk with alg 'HS256'")
+	})
+
 	t.Run("test ed25519 with []byte key material", func(t *testing.T) {
 		jwkJSON := `{
 			"kty": "OKP",
@@ -542,3 +564,57 @@ func TestJWK_KeyType(t *testing.T) {
 		require.Equal(t, kms.KeyType(""), kt)
 	})
 }
+
+// FuzzJWK_UnmarshalJSON checks that UnmarshalJSON rejects malformed JWKs without panicking and that the JWKs it reads
+// are marshalled to JWKs it reads again.
+func FuzzJWK_UnmarshalJSON(f *testing.F) {
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(f, err)
+
+	secp256k1Key, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+	require.NoError(f, err)
+
+	edPubKey, edPrivKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(f, err)
+
+	for _, key := range []*JWK{
+		{JSONWebKey: jose.JSONWebKey{Key: p256Key, KeyID: "p256"}},
+		{JSONWebKey: jose.JSONWebKey{Key: &p256Key.PublicKey, Algorithm: "ES256"}},
+		{JSONWebKey: jose.JSONWebKey{Key: secp256k1Key, Algorithm: secp256k1Alg}, Kty: ecKty, Crv: secp256k1Crv},
+		{JSONWebKey: jose.JSONWebKey{Key: &secp256k1Key.PublicKey}, Kty: ecKty, Crv: secp256k1Crv},
+		{JSONWebKey: jose.JSONWebKey{Key: edPrivKey}},
+		{JSONWebKey: jose.JSONWebKey{Key: edPubKey}},
+		{JSONWebKey: jose.JSONWebKey{Key: []byte(edPubKey)}, Kty: okpKty, Crv: x25519Crv},
+		{JSONWebKey: jose.JSONWebKey{Key: []byte("0123456789abcdef")}},
+	} {
+		jwkBytes, e := key.MarshalJSON()
+		require.NoError(f, e)
+
+		f.Add(jwkBytes)
+	}
+
+	f.Add([]byte(`{"kty":"EC","crv":"P-256","x":"","y":""}`))
+	f.Add([]byte(`{"kty":"OKP","crv":"X448","x":"AA"}`))
+	f.Add([]byte(`{"kty":"EC","crv":"BLS12381_G2","x":"AA","d":"AA"}`))
+	f.Add([]byte(`}`))
+
+	f.Fuzz(func(t *testing.T, jwkBytes []byte) {
+		var key JWK
+
+		if key.UnmarshalJSON(jwkBytes) != nil {
+			return
+		}
+
+		_, _ = key.PublicKeyBytes()
+		_, _ = key.KeyType()
+
+		marshalled, e := key.MarshalJSON()
+		if e != nil {
+			return
+		}
+
+		var remarshalled JWK
+
+		require.NoError(t, remarshalled.UnmarshalJSON(marshalled), string(marshalled))
+	})
+}
@@ -32,6 +32,7 @@ const (
 	ecKty          = "EC"
 	okpKty         = "OKP"
 	x25519Crv      = "X25519"
+	x448Crv        = "X448"
 	bls12381G2Crv  = "BLS12381_G2"
 	bls12381G2Size = 96
 )
@@ -67,7 +68,7 @@ func PubKeyBytesToKey(bytes []byte, keyType kms.KeyType) (interface{}, error) {
 	switch keyType {
 	case kms.ED25519Type:
 		return ed25519.PublicKey(bytes), nil
-	case kms.X25519ECDHKWType:
+	case kms.X25519ECDHKWType, kms.X448ECDHKWType:
 		return bytes, nil
 	case kms.BLS12381G2Type:
 		return bbs12381g2pub.UnmarshalPublicKey(bytes)
@@ -100,7 +101,7 @@ func PubKeyBytesToKey(bytes []byte, keyType kms.KeyType) (interface{}, error) {
 		}
 
 		return ecKey, nil
-	case kms.RSARS256, kms.RSAPS256:
+	case kms.RSARS256, kms.RSAPS256, kms.RSAOAEP256:
 		pubKeyRsa, err := x509.ParsePKIXPublicKey(bytes)
 		if err != nil {
 			return nil, errors.New("rsa: invalid public key")
@@ -156,6 +157,30 @@ func JWKFromX25519Key(pubKey []byte) (*jwk.JWK, error) {
 	return key, nil
 }
 
+// JWKFromX448Key is similar to JWKFromX25519Key but is specific to X448 keys when using a public key as raw []byte.
+func JWKFromX448Key(pubKey []byte) (*jwk.JWK, error) {
+	key := &jwk.JWK{
+		JSONWebKey: jose.JSONWebKey{
+			Key: pubKey,
+		},
+		Crv: x448Crv,
+		Kty: okpKty,
+	}
+
+	// marshal/unmarshal to get all JWK's fields other than Key filled.
+	keyBytes, err := key.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("create JWK: %w", err)
+	}
+
+	err = key.UnmarshalJSON(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("create JWK: %w", err)
+	}
+
+	return key, nil
+}
+
 // PubKeyBytesToJWK converts marshalled bytes of keyType into JWK.
 func PubKeyBytesToJWK(bytes []byte, keyType kms.KeyType) (*jwk.JWK, error) {
 	switch keyType {
@@ -169,12 +194,14 @@ func PubKeyBytesToJWK(bytes []byte, keyType kms.KeyType) (*jwk.JWK, error) {
 		}, nil
 	case kms.X25519ECDHKWType:
 		return JWKFromX25519Key(bytes)
+	case kms.X448ECDHKWType:
+		return JWKFromX448Key(bytes)
 	case kms.BLS12381G2Type,
 		kms.ECDSASecp256k1TypeIEEEP1363, kms.ECDSASecp256k1TypeDER,
 		kms.ECDSAP256TypeIEEEP1363, kms.ECDSAP384TypeIEEEP1363, kms.ECDSAP521TypeIEEEP1363,
 		kms.ECDSAP256TypeDER, kms.ECDSAP384TypeDER, kms.ECDSAP521TypeDER,
 		kms.NISTP256ECDHKWType, kms.NISTP384ECDHKWType, kms.NISTP521ECDHKWType,
-		kms.RSARS256, kms.RSAPS256:
+		kms.RSARS256, kms.RSAPS256, kms.RSAOAEP256:
 		key, err := PubKeyBytesToKey(bytes, keyType)
 		if err != nil {
 			return nil, err
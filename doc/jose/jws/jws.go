@@ -0,0 +1,276 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package jws signs and verifies JSON Web Signatures (https://tools.ietf.org/html/rfc7515) with keys held by a KMS.
+// The JWS algorithm is resolved from the KMS key type so callers only need to provide a key ID.
+package jws
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/keyset"
+
+	"github.com/trustbloc/kms-go/doc/canonical"
+	"github.com/trustbloc/kms-go/doc/jose"
+	"github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+// JWS algorithms supported.
+const (
+	AlgES256  = "ES256"
+	AlgES384  = "ES384"
+	AlgES512  = "ES512"
+	AlgES256K = "ES256K"
+	AlgEdDSA  = "EdDSA"
+	AlgPS256  = "PS256"
+	AlgRS256  = "RS256"
+)
+
+// Service signs and verifies JWS using keys managed by a KeyManager and crypto operations from a Crypto service.
+type Service struct {
+	km     kms.KeyManager
+	crypto crypto.Crypto
+}
+
+// New creates a new JWS Service.
+func New(km kms.KeyManager, c crypto.Crypto) *Service {
+	return &Service{km: km, crypto: c}
+}
+
+// Opt is a SignJWS/VerifyJWS option.
+type Opt func(o *opts)
+
+type opts struct {
+	unprotected     jose.Headers
+	detachedPayload []byte
+	canonicalizer   canonical.Canonicalizer
+}
+
+// WithUnprotectedHeaders sets the unprotected headers of a JWS created by SignJWS. They are only part of the JSON
+// serialization.
+func WithUnprotectedHeaders(headers jose.Headers) Opt {
+	return func(o *opts) {
+		o.unprotected = headers
+	}
+}
+
+// WithDetachedPayload sets the payload to verify with VerifyJWS for a JWS serialized without its payload
+// (https://tools.ietf.org/html/rfc7515#appendix-F).
+func WithDetachedPayload(payload []byte) Opt {
+	return func(o *opts) {
+		o.detachedPayload = payload
+	}
+}
+
+// WithCanonicalizer canonicalizes the payload signed by SignJWS, and the detached payload verified by VerifyJWS, with
+// c (eg: canonical.NewJCS()).
+func WithCanonicalizer(c canonical.Canonicalizer) Opt {
+	return func(o *opts) {
+		o.canonicalizer = c
+	}
+}
+
+// AlgorithmForKeyType returns the JWS algorithm of signatures created with keys of type kt.
+func AlgorithmForKeyType(kt kms.KeyType) (string, error) {
+	switch kt { //nolint:exhaustive
+	case kms.ECDSAP256TypeDER, kms.ECDSAP256TypeIEEEP1363:
+		return AlgES256, nil
+	case kms.ECDSAP384TypeDER, kms.ECDSAP384TypeIEEEP1363:
+		return AlgES384, nil
+	case kms.ECDSAP521TypeDER, kms.ECDSAP521TypeIEEEP1363:
+		return AlgES512, nil
+	case kms.ECDSASecp256k1TypeDER, kms.ECDSASecp256k1TypeIEEEP1363:
+		return AlgES256K, nil
+	case kms.ED25519Type:
+		return AlgEdDSA, nil
+	case kms.RSAPS256Type:
+		return AlgPS256, nil
+	case kms.RSARS256Type:
+		return AlgRS256, nil
+	default:
+		return "", fmt.Errorf("key type '%s' is not supported for JWS", kt)
+	}
+}
+
+// SignJWS signs payload with the key keyID. The "alg" protected header is set from the key type and "kid" is set to
+// keyID unless already present in headers. Headers may not set an "alg" different from the key's algorithm.
+func (s *Service) SignJWS(keyID string, headers jose.Headers, payload []byte, options ...Opt) (*JWS, error) {
+	o := &opts{}
+
+	for _, opt := range options {
+		opt(o)
+	}
+
+	kt, alg, err := s.keyAlgorithm(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("signJWS: %w", err)
+	}
+
+	if o.canonicalizer != nil {
+		if payload, err = o.canonicalizer.Canonicalize(payload); err != nil {
+			return nil, fmt.Errorf("signJWS: canonicalize payload: %w", err)
+		}
+	}
+
+	protected := make(jose.Headers, len(headers))
+
+	for k, v := range headers {
+		protected[k] = v
+	}
+
+	if hAlg, ok := protected.Algorithm(); ok && hAlg != alg {
+		return nil, fmt.Errorf("signJWS: alg header '%s' does not match key algorithm '%s'", hAlg, alg)
+	}
+
+	protected[jose.HeaderAlgorithm] = alg
+
+	if _, ok := protected[jose.HeaderKeyID]; !ok {
+		protected[jose.HeaderKeyID] = keyID
+	}
+
+	kh, err := s.km.Get(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("signJWS: get key: %w", err)
+	}
+
+	sigInput, err := signingInput(protected, "", payload)
+	if err != nil {
+		return nil, fmt.Errorf("signJWS: %w", err)
+	}
+
+	sig, err := s.crypto.Sign(sigInput, kh)
+	if err != nil {
+		return nil, fmt.Errorf("signJWS: sign: %w", err)
+	}
+
+	sig, err = toJWSSignature(kt, sig)
+	if err != nil {
+		return nil, fmt.Errorf("signJWS: %w", err)
+	}
+
+	return &JWS{
+		ProtectedHeaders:   protected,
+		UnprotectedHeaders: o.unprotected,
+		Payload:            payload,
+		Signature:          sig,
+	}, nil
+}
+
+// VerifyJWS parses and verifies a JWS in compact or JSON (flattened or general) serialization. If keyID is empty, the
+// "kid" header is used to select the KMS key. With the general JSON serialization, the JWS is valid if one of its
+// signatures is valid for the key. The key algorithm must match the "alg" header.
+func (s *Service) VerifyJWS(keyID, serialized string, options ...Opt) (*JWS, error) {
+	o := &opts{}
+
+	for _, opt := range options {
+		opt(o)
+	}
+
+	if o.canonicalizer != nil && o.detachedPayload != nil {
+		payload, err := o.canonicalizer.Canonicalize(o.detachedPayload)
+		if err != nil {
+			return nil, fmt.Errorf("verifyJWS: canonicalize detached payload: %w", err)
+		}
+
+		o.detachedPayload = payload
+	}
+
+	candidates, err := parse(serialized, o.detachedPayload)
+	if err != nil {
+		return nil, fmt.Errorf("verifyJWS: %w", err)
+	}
+
+	var errs []error
+
+	for _, c := range candidates {
+		if err = s.verify(keyID, c); err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		return c.jws, nil
+	}
+
+	return nil, fmt.Errorf("verifyJWS: %w", errors.Join(errs...))
+}
+
+func (s *Service) verify(keyID string, c *parsedSignature) error {
+	kid := keyID
+	if kid == "" {
+		kid, _ = c.jws.ProtectedHeaders.KeyID()
+	}
+
+	if kid == "" {
+		kid, _ = c.jws.UnprotectedHeaders.KeyID()
+	}
+
+	if kid == "" {
+		return errors.New("missing key ID")
+	}
+
+	kt, alg, err := s.keyAlgorithm(kid)
+	if err != nil {
+		return err
+	}
+
+	if hAlg, _ := c.jws.ProtectedHeaders.Algorithm(); hAlg != alg {
+		return fmt.Errorf("alg header '%s' does not match key algorithm '%s'", hAlg, alg)
+	}
+
+	kh, err := s.publicKeyHandle(kid)
+	if err != nil {
+		return err
+	}
+
+	sig, err := fromJWSSignature(kt, c.jws.Signature)
+	if err != nil {
+		return err
+	}
+
+	if err = s.crypto.Verify(sig, c.signingInput, kh); err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) keyAlgorithm(keyID string) (kms.KeyType, string, error) {
+	_, kt, err := s.km.ExportPubKeyBytes(keyID)
+	if err != nil {
+		return "", "", fmt.Errorf("export public key: %w", err)
+	}
+
+	alg, err := AlgorithmForKeyType(kt)
+	if err != nil {
+		return "", "", err
+	}
+
+	return kt, alg, nil
+}
+
+// publicKeyHandle returns the key handle of keyID to verify signatures with. Tink keyset handles are converted to
+// public keyset handles as required by Tink verifiers.
+func (s *Service) publicKeyHandle(keyID string) (interface{}, error) {
+	kh, err := s.km.Get(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("get key: %w", err)
+	}
+
+	ksh, ok := kh.(*keyset.Handle)
+	if !ok {
+		return kh, nil
+	}
+
+	pubKH, err := ksh.Public()
+	if err != nil {
+		return nil, fmt.Errorf("get public key handle: %w", err)
+	}
+
+	return pubKH, nil
+}
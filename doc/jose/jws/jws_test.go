@@ -0,0 +1,285 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jws
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/doc/canonical"
+	"github.com/trustbloc/kms-go/doc/jose"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func newService(t *testing.T) (*Service, kmsapi.KeyManager) {
+	t.Helper()
+
+	km := mockkms.NewForTest(t)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	return New(km, cr), km
+}
+
+func TestSignVerifyJWS(t *testing.T) {
+	svc, km := newService(t)
+
+	testCases := []struct {
+		keyType kmsapi.KeyType
+		alg     string
+	}{
+		{kmsapi.ECDSAP256TypeIEEEP1363, AlgES256},
+		{kmsapi.ECDSAP384TypeIEEEP1363, AlgES384},
+		{kmsapi.ECDSAP521TypeIEEEP1363, AlgES512},
+		{kmsapi.ECDSAP256TypeDER, AlgES256},
+		{kmsapi.ECDSAP384TypeDER, AlgES384},
+		{kmsapi.ECDSAP521TypeDER, AlgES512},
+		{kmsapi.ECDSASecp256k1TypeIEEEP1363, AlgES256K},
+		{kmsapi.ED25519Type, AlgEdDSA},
+	}
+
+	payload := []byte(`{"iss":"issuer"}`)
+
+	for _, tc := range testCases {
+		t.Run(string(tc.keyType), func(t *testing.T) {
+			keyID, _, err := km.Create(tc.keyType)
+			require.NoError(t, err)
+
+			signed, err := svc.SignJWS(keyID, jose.Headers{jose.HeaderType: "JWT"}, payload)
+			require.NoError(t, err)
+
+			alg, _ := signed.ProtectedHeaders.Algorithm()
+			require.Equal(t, tc.alg, alg)
+
+			kid, _ := signed.ProtectedHeaders.KeyID()
+			require.Equal(t, keyID, kid)
+
+			compact, err := signed.Compact(false)
+			require.NoError(t, err)
+
+			parsed, err := svc.VerifyJWS("", compact)
+			require.NoError(t, err)
+			require.Equal(t, payload, parsed.Payload)
+			require.Equal(t, signed.Signature, parsed.Signature)
+
+			jsonJWS, err := signed.JSON(false)
+			require.NoError(t, err)
+
+			parsed, err = svc.VerifyJWS(keyID, string(jsonJWS))
+			require.NoError(t, err)
+			require.Equal(t, payload, parsed.Payload)
+
+			// round trip of a parsed JWS keeps its original encoding.
+			reserialized, err := parsed.Compact(false)
+			require.NoError(t, err)
+			require.Equal(t, compact, reserialized)
+
+			tampered := strings.Replace(compact, base64.RawURLEncoding.EncodeToString(payload),
+				base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"other"}`)), 1)
+
+			_, err = svc.VerifyJWS("", tampered)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestSignJWSOptions(t *testing.T) {
+	svc, km := newService(t)
+
+	keyID, _, err := km.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	payload := []byte("detached payload")
+
+	t.Run("detached payload and unprotected headers", func(t *testing.T) {
+		signed, err := svc.SignJWS(keyID, jose.Headers{jose.HeaderKeyID: "did:example:123#key-1"}, payload,
+			WithUnprotectedHeaders(jose.Headers{"custom": "value"}))
+		require.NoError(t, err)
+
+		compact, err := signed.Compact(true)
+		require.NoError(t, err)
+		require.Contains(t, compact, "..")
+
+		_, err = svc.VerifyJWS(keyID, compact, WithDetachedPayload(payload))
+		require.NoError(t, err)
+
+		_, err = svc.VerifyJWS(keyID, compact, WithDetachedPayload([]byte("other")))
+		require.Error(t, err)
+
+		jsonJWS, err := signed.JSON(true)
+		require.NoError(t, err)
+		require.NotContains(t, string(jsonJWS), `"payload"`)
+		require.Contains(t, string(jsonJWS), `"header":{"custom":"value"}`)
+
+		_, err = svc.VerifyJWS(keyID, string(jsonJWS))
+		require.EqualError(t, err, "verifyJWS: missing payload")
+
+		parsed, err := svc.VerifyJWS(keyID, string(jsonJWS), WithDetachedPayload(payload))
+		require.NoError(t, err)
+		require.Equal(t, jose.Headers{"custom": "value"}, parsed.UnprotectedHeaders)
+
+		// kid header is not a KMS key ID.
+		_, err = svc.VerifyJWS("", compact, WithDetachedPayload(payload))
+		require.Error(t, err)
+	})
+
+	t.Run("unencoded payload", func(t *testing.T) {
+		signed, err := svc.SignJWS(keyID, jose.Headers{jose.HeaderB64Payload: false, jose.HeaderCritical: []string{"b64"}},
+			payload)
+		require.NoError(t, err)
+
+		jsonJWS, err := signed.JSON(false)
+		require.NoError(t, err)
+		require.Contains(t, string(jsonJWS), `"payload":"detached payload"`)
+
+		parsed, err := svc.VerifyJWS("", string(jsonJWS))
+		require.NoError(t, err)
+		require.Equal(t, payload, parsed.Payload)
+	})
+
+	t.Run("general JSON serialization", func(t *testing.T) {
+		otherKeyID, _, err := km.Create(kmsapi.ECDSAP256TypeIEEEP1363)
+		require.NoError(t, err)
+
+		s1, err := svc.SignJWS(keyID, nil, payload)
+		require.NoError(t, err)
+
+		s2, err := svc.SignJWS(otherKeyID, nil, payload)
+		require.NoError(t, err)
+
+		general := map[string]interface{}{
+			"payload": base64.RawURLEncoding.EncodeToString(payload),
+			"signatures": []interface{}{
+				toGeneralSignature(t, s1),
+				toGeneralSignature(t, s2),
+			},
+		}
+
+		generalJSON, err := json.Marshal(general)
+		require.NoError(t, err)
+
+		parsed, err := svc.VerifyJWS(otherKeyID, string(generalJSON))
+		require.NoError(t, err)
+		require.Equal(t, s2.Signature, parsed.Signature)
+
+		parsed, err = svc.VerifyJWS("", string(generalJSON))
+		require.NoError(t, err)
+		require.Equal(t, s1.Signature, parsed.Signature)
+	})
+
+	t.Run("canonicalized payload", func(t *testing.T) {
+		jcs := WithCanonicalizer(canonical.NewJCS())
+
+		signed, err := svc.SignJWS(keyID, nil, []byte(`{"b": 2, "a": 1}`), jcs)
+		require.NoError(t, err)
+		require.Equal(t, []byte(`{"a":1,"b":2}`), signed.Payload)
+
+		compact, err := signed.Compact(true)
+		require.NoError(t, err)
+
+		_, err = svc.VerifyJWS(keyID, compact, WithDetachedPayload([]byte(`{ "a": 1, "b": 2 }`)), jcs)
+		require.NoError(t, err)
+
+		_, err = svc.VerifyJWS(keyID, compact, WithDetachedPayload([]byte(`{`)), jcs)
+		require.ErrorContains(t, err, "canonicalize detached payload")
+
+		_, err = svc.SignJWS(keyID, nil, []byte(`{`), jcs)
+		require.ErrorContains(t, err, "canonicalize payload")
+	})
+
+	t.Run("interoperates with jose.ParseJWS", func(t *testing.T) {
+		signed, err := svc.SignJWS(keyID, nil, payload)
+		require.NoError(t, err)
+
+		compact, err := signed.Compact(false)
+		require.NoError(t, err)
+
+		_, err = jose.ParseJWS(compact, jose.SignatureVerifierFunc(func(_ jose.Headers, _, sigInput, sig []byte) error {
+			_, tail, _ := strings.Cut(compact, ".")
+			require.Equal(t, compact[:len(compact)-len(tail)-1]+"."+base64.RawURLEncoding.EncodeToString(payload),
+				string(sigInput))
+			require.Equal(t, signed.Signature, sig)
+
+			return nil
+		}))
+		require.NoError(t, err)
+	})
+}
+
+func TestJWSErrors(t *testing.T) {
+	svc, km := newService(t)
+
+	edKeyID, _, err := km.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	p256KeyID, _, err := km.Create(kmsapi.ECDSAP256TypeIEEEP1363)
+	require.NoError(t, err)
+
+	aesKeyID, _, err := km.Create(kmsapi.AES256GCMType)
+	require.NoError(t, err)
+
+	_, err = svc.SignJWS("unknown", nil, []byte("payload"))
+	require.ErrorContains(t, err, "signJWS: export public key")
+
+	_, err = svc.SignJWS(aesKeyID, nil, []byte("payload"))
+	require.ErrorContains(t, err, "signJWS: export public key")
+
+	_, err = svc.SignJWS(edKeyID, jose.Headers{jose.HeaderAlgorithm: AlgES256}, []byte("payload"))
+	require.EqualError(t, err, "signJWS: alg header 'ES256' does not match key algorithm 'EdDSA'")
+
+	signed, err := svc.SignJWS(edKeyID, nil, []byte("payload"))
+	require.NoError(t, err)
+
+	compact, err := signed.Compact(false)
+	require.NoError(t, err)
+
+	_, err = svc.VerifyJWS(p256KeyID, compact)
+	require.ErrorContains(t, err, "alg header 'EdDSA' does not match key algorithm 'ES256'")
+
+	for _, invalid := range []string{
+		"a.b",
+		"!.b.c",
+		base64.RawURLEncoding.EncodeToString([]byte("{")) + ".b.c",
+		base64.RawURLEncoding.EncodeToString([]byte("{}")) + ".b.c",
+		strings.Split(compact, ".")[0] + ".!.c",
+		strings.Split(compact, ".")[0] + ".b.!",
+		"{",
+		`{"signature":"abc"}`,
+	} {
+		_, err = svc.VerifyJWS(edKeyID, invalid)
+		require.Error(t, err, invalid)
+	}
+
+	_, err = AlgorithmForKeyType(kmsapi.AES128GCMType)
+	require.EqualError(t, err, "key type 'AES128GCM' is not supported for JWS")
+
+	_, err = toJWSSignature(kmsapi.ECDSAP256TypeDER, []byte("not DER"))
+	require.Error(t, err)
+
+	_, err = fromJWSSignature(kmsapi.ECDSAP256TypeDER, []byte("short"))
+	require.EqualError(t, err, "invalid ECDSA signature size")
+}
+
+func toGeneralSignature(t *testing.T, j *JWS) map[string]interface{} {
+	t.Helper()
+
+	protected, err := j.encodedProtected()
+	require.NoError(t, err)
+
+	return map[string]interface{}{
+		"protected": protected,
+		"signature": base64.RawURLEncoding.EncodeToString(j.Signature),
+	}
+}
@@ -0,0 +1,232 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jws
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/trustbloc/kms-go/doc/jose"
+)
+
+const compactParts = 3
+
+// JWS is a signed JSON Web Signature.
+type JWS struct {
+	ProtectedHeaders   jose.Headers
+	UnprotectedHeaders jose.Headers
+	Payload            []byte
+	Signature          []byte
+
+	// protected is the original encoded protected header of a parsed JWS.
+	protected string
+}
+
+type flattenedJWS struct {
+	Payload   *string      `json:"payload,omitempty"`
+	Protected string       `json:"protected,omitempty"`
+	Header    jose.Headers `json:"header,omitempty"`
+	Signature string       `json:"signature"`
+}
+
+type generalJWS struct {
+	Payload    *string            `json:"payload,omitempty"`
+	Signatures []generalSignature `json:"signatures"`
+}
+
+type generalSignature struct {
+	Protected string       `json:"protected,omitempty"`
+	Header    jose.Headers `json:"header,omitempty"`
+	Signature string       `json:"signature"`
+}
+
+type parsedSignature struct {
+	jws          *JWS
+	signingInput []byte
+}
+
+// Compact returns the JWS Compact Serialization (https://tools.ietf.org/html/rfc7515#section-7.1). Unprotected headers
+// are not part of it. If detached is set, the payload is omitted (https://tools.ietf.org/html/rfc7515#appendix-F).
+func (j *JWS) Compact(detached bool) (string, error) {
+	protected, err := j.encodedProtected()
+	if err != nil {
+		return "", err
+	}
+
+	payload := ""
+	if !detached {
+		payload = j.encodedPayload()
+	}
+
+	return protected + "." + payload + "." + base64.RawURLEncoding.EncodeToString(j.Signature), nil
+}
+
+// JSON returns the flattened JWS JSON Serialization (https://tools.ietf.org/html/rfc7515#section-7.2.2). If detached
+// is set, the payload is omitted.
+func (j *JWS) JSON(detached bool) ([]byte, error) {
+	protected, err := j.encodedProtected()
+	if err != nil {
+		return nil, err
+	}
+
+	fj := &flattenedJWS{
+		Protected: protected,
+		Header:    j.UnprotectedHeaders,
+		Signature: base64.RawURLEncoding.EncodeToString(j.Signature),
+	}
+
+	if !detached {
+		payload := j.encodedPayload()
+		fj.Payload = &payload
+	}
+
+	return json.Marshal(fj)
+}
+
+func (j *JWS) encodedProtected() (string, error) {
+	if j.protected != "" {
+		return j.protected, nil
+	}
+
+	b, err := json.Marshal(j.ProtectedHeaders)
+	if err != nil {
+		return "", fmt.Errorf("marshal protected headers: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func (j *JWS) encodedPayload() string {
+	if !isB64(j.ProtectedHeaders) {
+		return string(j.Payload)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(j.Payload)
+}
+
+func isB64(headers jose.Headers) bool {
+	b64, ok := headers[jose.HeaderB64Payload].(bool)
+
+	return !ok || b64
+}
+
+func signingInput(headers jose.Headers, protected string, payload []byte) ([]byte, error) {
+	j := &JWS{ProtectedHeaders: headers, Payload: payload, protected: protected}
+
+	encodedProtected, err := j.encodedProtected()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(encodedProtected + "." + j.encodedPayload()), nil
+}
+
+// parse returns all the signatures of a serialized JWS with their signing input.
+func parse(serialized string, detachedPayload []byte) ([]*parsedSignature, error) {
+	serialized = strings.TrimSpace(serialized)
+
+	if !strings.HasPrefix(serialized, "{") {
+		parts := strings.Split(serialized, ".")
+		if len(parts) != compactParts {
+			return nil, errors.New("invalid JWS compact format")
+		}
+
+		payload := parts[1]
+
+		c, err := parseSignature(parts[0], nil, &payload, parts[2], detachedPayload)
+		if err != nil {
+			return nil, err
+		}
+
+		return []*parsedSignature{c}, nil
+	}
+
+	gj := &generalJWS{}
+
+	if err := json.Unmarshal([]byte(serialized), gj); err != nil {
+		return nil, fmt.Errorf("unmarshal JWS JSON: %w", err)
+	}
+
+	if gj.Signatures == nil {
+		fj := &flattenedJWS{}
+
+		if err := json.Unmarshal([]byte(serialized), fj); err != nil {
+			return nil, fmt.Errorf("unmarshal JWS JSON: %w", err)
+		}
+
+		gj.Signatures = []generalSignature{{Protected: fj.Protected, Header: fj.Header, Signature: fj.Signature}}
+	}
+
+	candidates := make([]*parsedSignature, 0, len(gj.Signatures))
+
+	for _, sig := range gj.Signatures {
+		c, err := parseSignature(sig.Protected, sig.Header, gj.Payload, sig.Signature, detachedPayload)
+		if err != nil {
+			return nil, err
+		}
+
+		candidates = append(candidates, c)
+	}
+
+	return candidates, nil
+}
+
+func parseSignature(protected string, unprotected jose.Headers, payload *string, signature string,
+	detachedPayload []byte) (*parsedSignature, error) {
+	headersBytes, err := base64.RawURLEncoding.DecodeString(protected)
+	if err != nil {
+		return nil, fmt.Errorf("decode protected headers: %w", err)
+	}
+
+	headers := jose.Headers{}
+
+	if err = json.Unmarshal(headersBytes, &headers); err != nil {
+		return nil, fmt.Errorf("unmarshal protected headers: %w", err)
+	}
+
+	if _, ok := headers.Algorithm(); !ok {
+		return nil, errors.New("missing alg protected header")
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return nil, fmt.Errorf("decode signature: %w", err)
+	}
+
+	var decodedPayload []byte
+
+	switch {
+	case detachedPayload != nil:
+		decodedPayload = detachedPayload
+	case payload == nil:
+		return nil, errors.New("missing payload")
+	case !isB64(headers):
+		decodedPayload = []byte(*payload)
+	default:
+		if decodedPayload, err = base64.RawURLEncoding.DecodeString(*payload); err != nil {
+			return nil, fmt.Errorf("decode payload: %w", err)
+		}
+	}
+
+	j := &JWS{
+		ProtectedHeaders:   headers,
+		UnprotectedHeaders: unprotected,
+		Payload:            decodedPayload,
+		Signature:          sig,
+		protected:          protected,
+	}
+
+	sigInput, err := signingInput(headers, protected, decodedPayload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsedSignature{jws: j, signingInput: sigInput}, nil
+}
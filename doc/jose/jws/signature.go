@@ -0,0 +1,23 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jws
+
+import (
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/util/sigencoding"
+)
+
+// toJWSSignature converts a KMS signature to the JWS format: JWS requires ECDSA signatures in IEEE-P1363 format
+// (R || S), DER encoded signatures are converted.
+func toJWSSignature(kt kms.KeyType, sig []byte) ([]byte, error) {
+	return sigencoding.Convert(sig, kt, sigencoding.IEEEP1363)
+}
+
+// fromJWSSignature converts a JWS signature to the format expected for a KMS key of type kt.
+func fromJWSSignature(kt kms.KeyType, sig []byte) ([]byte, error) {
+	return sigencoding.ConvertToKeyType(sig, kt, sigencoding.IEEEP1363)
+}
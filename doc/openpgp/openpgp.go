@@ -0,0 +1,369 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package openpgp uses the Ed25519 and RSA keys held by a KMS as OpenPGP v4 keys
+// (https://www.rfc-editor.org/rfc/rfc4880), eg: to sign release artifacts or git commits with localkms keys. The
+// keys sign with the crypto.Signer of the signer package, Ed25519 keys are EdDSA keys
+// (https://datatracker.ietf.org/doc/html/draft-ietf-openpgp-rfc4880bis-10#section-5.2.3), the signatures use SHA-256.
+//
+// A Key signs detached signatures of binary documents, the armored signatures are the .asc files of the release
+// artifacts and the gpgsig signatures of the git commits. An Entity binds the user IDs and the signing subkeys to a
+// primary key, its public key is serialized as the OpenPGP packets imported by gpg or by other OpenPGP
+// implementations to verify the signatures.
+package openpgp
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // OpenPGP v4 fingerprints are SHA-1 hashes
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/trustbloc/kms-go/crypto/signer"
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+// public key algorithms (https://www.rfc-editor.org/rfc/rfc4880#section-9.1).
+const (
+	pubKeyAlgoRSA   = 1
+	pubKeyAlgoEdDSA = 22
+)
+
+// signature types (https://www.rfc-editor.org/rfc/rfc4880#section-5.2.1).
+const (
+	sigTypeBinary            = 0x00
+	sigTypePositiveCert      = 0x13
+	sigTypeSubkeyBinding     = 0x18
+	sigTypePrimaryKeyBinding = 0x19
+)
+
+// key flags (https://www.rfc-editor.org/rfc/rfc4880#section-5.2.3.21).
+const (
+	keyFlagCertify = 0x01
+	keyFlagSign    = 0x02
+)
+
+const (
+	keyVersion      = 4
+	hashSHA256      = 8
+	hashSHA512      = 10
+	fingerprintSize = 20
+)
+
+// ed25519OID is the OID of the Ed25519 curve of the EdDSA keys, without its tag and length.
+//
+//nolint:gochecknoglobals
+var ed25519OID = []byte{0x2b, 0x06, 0x01, 0x04, 0x01, 0xda, 0x47, 0x0f, 0x01}
+
+// Service creates OpenPGP keys with keys managed by a KeyManager.
+type Service struct {
+	km kms.KeyManager
+}
+
+// New creates a new OpenPGP Service.
+func New(km kms.KeyManager) *Service {
+	return &Service{km: km}
+}
+
+// Opt is a signature option.
+type Opt func(o *opts)
+
+type opts struct {
+	now func() time.Time
+}
+
+// WithClock sets the clock of the creation time of the signatures, time.Now by default.
+func WithClock(now func() time.Time) Opt {
+	return func(o *opts) {
+		o.now = now
+	}
+}
+
+// Key is an OpenPGP key of a KMS key.
+type Key struct {
+	signer      *signer.Signer
+	algo        byte
+	created     time.Time
+	body        []byte
+	fingerprint []byte
+}
+
+// Key returns the OpenPGP key of the Ed25519 or RSA key keyID, created at created. The creation time is part of the
+// fingerprint of the key: the same creation time must be used to sign with the key and to export its public key.
+func (s *Service) Key(keyID string, created time.Time) (*Key, error) {
+	if created.Unix() < 0 || created.Unix() > math.MaxUint32 {
+		return nil, errors.New("openpgp key: creation time out of range")
+	}
+
+	sig, err := signer.New(s.km, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("openpgp key: %w", err)
+	}
+
+	k := &Key{signer: sig, created: time.Unix(created.Unix(), 0)}
+	k.body = binary.BigEndian.AppendUint32([]byte{keyVersion}, uint32(created.Unix()))
+
+	switch pub := sig.Public().(type) {
+	case ed25519.PublicKey:
+		k.algo = pubKeyAlgoEdDSA
+		k.body = append(k.body, k.algo, byte(len(ed25519OID)))
+		k.body = append(k.body, ed25519OID...)
+		// the EdDSA points are prefixed with 0x40.
+		k.body = append(k.body, mpi(append([]byte{0x40}, pub...))...)
+	case *rsa.PublicKey:
+		k.algo = pubKeyAlgoRSA
+		k.body = append(append(append(k.body, k.algo), mpi(pub.N.Bytes())...), mpi(big.NewInt(int64(pub.E)).Bytes())...)
+	default:
+		return nil, fmt.Errorf("openpgp key: key type %T is not supported", pub)
+	}
+
+	h := sha1.New() //nolint:gosec // OpenPGP v4 fingerprints are SHA-1 hashes
+	hashKey(h, k.body)
+	k.fingerprint = h.Sum(nil)
+
+	return k, nil
+}
+
+// Fingerprint returns the 20 bytes v4 fingerprint of the key.
+func (k *Key) Fingerprint() []byte {
+	return append([]byte(nil), k.fingerprint...)
+}
+
+// KeyID returns the key ID of the key, the low 64 bits of its fingerprint.
+func (k *Key) KeyID() uint64 {
+	return binary.BigEndian.Uint64(k.fingerprint[fingerprintSize-8:])
+}
+
+// CreationTime returns the creation time of the key.
+func (k *Key) CreationTime() time.Time {
+	return k.created
+}
+
+// DetachSign writes to w the detached signature of the binary document read from message.
+func (k *Key) DetachSign(w io.Writer, message io.Reader, options ...Opt) error {
+	sig, err := k.detachSign(message, options)
+	if err != nil {
+		return fmt.Errorf("openpgp detach sign: %w", err)
+	}
+
+	if err = writePacket(w, tagSignature, sig); err != nil {
+		return fmt.Errorf("openpgp detach sign: %w", err)
+	}
+
+	return nil
+}
+
+// ArmoredDetachSign writes to w the armored detached signature of the binary document read from message.
+func (k *Key) ArmoredDetachSign(w io.Writer, message io.Reader, options ...Opt) error {
+	sig, err := k.detachSign(message, options)
+	if err != nil {
+		return fmt.Errorf("openpgp detach sign: %w", err)
+	}
+
+	if err = armorPackets(w, armorSignature, packet{tagSignature, sig}); err != nil {
+		return fmt.Errorf("openpgp detach sign: %w", err)
+	}
+
+	return nil
+}
+
+func (k *Key) detachSign(message io.Reader, options []Opt) ([]byte, error) {
+	h := sha256.New()
+
+	if _, err := io.Copy(h, message); err != nil {
+		return nil, fmt.Errorf("read message: %w", err)
+	}
+
+	return k.sign(h, sigTypeBinary, signatureTime(options), nil)
+}
+
+// sign returns the body of the signature of the data hashed by h, with the hashed subpackets of the signature
+// creation time, the issuer fingerprint and hashed.
+func (k *Key) sign(h hash.Hash, sigType byte, now time.Time, hashed []byte) ([]byte, error) {
+	if now.Before(k.created) {
+		return nil, errors.New("signature creation time is before the key creation time")
+	}
+
+	subpackets := subpacket(subpacketCreationTime, binary.BigEndian.AppendUint32(nil, uint32(now.Unix())))
+	subpackets = append(subpackets, subpacket(subpacketIssuerFingerprint, append([]byte{keyVersion}, k.fingerprint...))...)
+	subpackets = append(subpackets, hashed...)
+
+	body := []byte{keyVersion, sigType, k.algo, hashSHA256}
+	body = binary.BigEndian.AppendUint16(body, uint16(len(subpackets)))
+	body = append(body, subpackets...)
+
+	h.Write(body)
+	h.Write(binary.BigEndian.AppendUint32([]byte{keyVersion, 0xff}, uint32(len(body))))
+
+	digest := h.Sum(nil)
+
+	var opts crypto.SignerOpts = crypto.SHA256
+
+	if k.algo == pubKeyAlgoEdDSA {
+		// EdDSA signs the digest as message.
+		opts = crypto.Hash(0)
+	}
+
+	sig, err := k.signer.Sign(rand.Reader, digest, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	unhashed := subpacket(subpacketIssuer, binary.BigEndian.AppendUint64(nil, k.KeyID()))
+
+	body = binary.BigEndian.AppendUint16(body, uint16(len(unhashed)))
+	body = append(body, unhashed...)
+	body = append(body, digest[:2]...)
+
+	if k.algo == pubKeyAlgoEdDSA {
+		return append(append(body, mpi(sig[:ed25519.SignatureSize/2])...), mpi(sig[ed25519.SignatureSize/2:])...), nil
+	}
+
+	return append(body, mpi(sig)...), nil
+}
+
+// Entity is an OpenPGP key: a primary key, its user IDs and its signing subkeys.
+type Entity struct {
+	PrimaryKey *Key
+	UserIDs    []string
+	Subkeys    []*Key
+}
+
+// Serialize writes to w the public key of e: the packets of the primary key, of the user IDs and of the subkeys,
+// with the certifications of the user IDs and the binding signatures of the subkeys signed by the keys.
+func (e *Entity) Serialize(w io.Writer, options ...Opt) error {
+	packets, err := e.packets(signatureTime(options))
+	if err != nil {
+		return fmt.Errorf("openpgp serialize: %w", err)
+	}
+
+	for _, p := range packets {
+		if err = writePacket(w, p.tag, p.body); err != nil {
+			return fmt.Errorf("openpgp serialize: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// SerializeArmored writes to w the armored public key of e.
+func (e *Entity) SerializeArmored(w io.Writer, options ...Opt) error {
+	packets, err := e.packets(signatureTime(options))
+	if err != nil {
+		return fmt.Errorf("openpgp serialize: %w", err)
+	}
+
+	if err = armorPackets(w, armorPublicKey, packets...); err != nil {
+		return fmt.Errorf("openpgp serialize: %w", err)
+	}
+
+	return nil
+}
+
+func (e *Entity) packets(now time.Time) ([]packet, error) {
+	if e.PrimaryKey == nil {
+		return nil, errors.New("missing primary key")
+	}
+
+	if len(e.UserIDs) == 0 {
+		return nil, errors.New("missing user ID")
+	}
+
+	primary := e.PrimaryKey
+	packets := []packet{{tagPublicKey, primary.body}}
+
+	for _, id := range e.UserIDs {
+		h := sha256.New()
+		hashKey(h, primary.body)
+		h.Write(binary.BigEndian.AppendUint32([]byte{0xb4}, uint32(len(id))))
+		h.Write([]byte(id))
+
+		sig, err := primary.sign(h, sigTypePositiveCert, now, append(
+			subpacket(subpacketKeyFlags, []byte{keyFlagCertify | keyFlagSign}),
+			subpacket(subpacketPreferredHash, []byte{hashSHA256, hashSHA512})...))
+		if err != nil {
+			return nil, fmt.Errorf("certify user ID '%s': %w", id, err)
+		}
+
+		packets = append(packets, packet{tagUserID, []byte(id)}, packet{tagSignature, sig})
+	}
+
+	for _, subkey := range e.Subkeys {
+		sig, err := bindSubkey(primary, subkey, now)
+		if err != nil {
+			return nil, fmt.Errorf("bind subkey %X: %w", subkey.fingerprint, err)
+		}
+
+		packets = append(packets, packet{tagPublicSubkey, subkey.body}, packet{tagSignature, sig})
+	}
+
+	return packets, nil
+}
+
+// bindSubkey returns the subkey binding signature of the signing subkey, with the embedded primary key binding
+// signature signed by the subkey (https://www.rfc-editor.org/rfc/rfc4880#section-11.1).
+func bindSubkey(primary, subkey *Key, now time.Time) ([]byte, error) {
+	h := sha256.New()
+	hashKey(h, primary.body)
+	hashKey(h, subkey.body)
+
+	backSig, err := subkey.sign(h, sigTypePrimaryKeyBinding, now, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	h.Reset()
+	hashKey(h, primary.body)
+	hashKey(h, subkey.body)
+
+	return primary.sign(h, sigTypeSubkeyBinding, now, append(
+		subpacket(subpacketKeyFlags, []byte{keyFlagSign}),
+		subpacket(subpacketEmbeddedSignature, backSig)...))
+}
+
+// hashKey hashes the body of a public key packet, as it's hashed by the fingerprints and the key signatures.
+func hashKey(h hash.Hash, body []byte) {
+	h.Write(binary.BigEndian.AppendUint16([]byte{0x99}, uint16(len(body))))
+	h.Write(body)
+}
+
+type packet struct {
+	tag  byte
+	body []byte
+}
+
+func armorPackets(w io.Writer, blockType string, packets ...packet) error {
+	var b bytes.Buffer
+
+	for _, p := range packets {
+		if err := writePacket(&b, p.tag, p.body); err != nil {
+			return err
+		}
+	}
+
+	return armor(w, blockType, b.Bytes())
+}
+
+// signatureTime returns the creation time of the signatures, in seconds.
+func signatureTime(options []Opt) time.Time {
+	o := &opts{now: time.Now}
+
+	for _, opt := range options {
+		opt(o)
+	}
+
+	return time.Unix(o.now().Unix(), 0)
+}
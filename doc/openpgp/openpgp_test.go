@@ -0,0 +1,196 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package openpgp_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	xopenpgp "golang.org/x/crypto/openpgp" //nolint:staticcheck // verifies the RSA signatures
+
+	"github.com/trustbloc/kms-go/doc/openpgp"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+const message = "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\nauthor Alice <alice@example.com>\n\nrelease\n"
+
+func TestRSASignatures(t *testing.T) {
+	km := mockkms.NewForTest(t)
+	svc := openpgp.New(km)
+	created := time.Now().Add(-time.Hour)
+
+	entity := &openpgp.Entity{
+		PrimaryKey: newKey(t, km, svc, kmsapi.RSARS256Type, created),
+		UserIDs:    []string{"Alice <alice@example.com>", "Alice Release Key"},
+		Subkeys:    []*openpgp.Key{newKey(t, km, svc, kmsapi.RSARS256Type, created)},
+	}
+
+	var pub bytes.Buffer
+
+	require.NoError(t, entity.SerializeArmored(&pub))
+	require.True(t, strings.HasPrefix(pub.String(), "-----BEGIN PGP PUBLIC KEY BLOCK-----\n\n"))
+
+	keyRing, err := xopenpgp.ReadArmoredKeyRing(&pub)
+	require.NoError(t, err)
+	require.Len(t, keyRing, 1)
+
+	e := keyRing[0]
+	require.Equal(t, entity.PrimaryKey.Fingerprint(), e.PrimaryKey.Fingerprint[:])
+	require.Equal(t, entity.PrimaryKey.KeyID(), e.PrimaryKey.KeyId)
+	require.Equal(t, entity.PrimaryKey.CreationTime(), e.PrimaryKey.CreationTime)
+	require.Contains(t, e.Identities, "Alice <alice@example.com>")
+	require.Contains(t, e.Identities, "Alice Release Key")
+	require.Len(t, e.Subkeys, 1)
+	require.Equal(t, entity.Subkeys[0].KeyID(), e.Subkeys[0].PublicKey.KeyId)
+
+	for _, key := range []*openpgp.Key{entity.PrimaryKey, entity.Subkeys[0]} {
+		var sig bytes.Buffer
+
+		require.NoError(t, key.ArmoredDetachSign(&sig, strings.NewReader(message)))
+		require.True(t, strings.HasPrefix(sig.String(), "-----BEGIN PGP SIGNATURE-----\n\n"))
+
+		signer, err := xopenpgp.CheckArmoredDetachedSignature(keyRing, strings.NewReader(message), &sig)
+		require.NoError(t, err)
+		require.Equal(t, e.PrimaryKey.Fingerprint, signer.PrimaryKey.Fingerprint)
+
+		sig.Reset()
+		require.NoError(t, key.DetachSign(&sig, strings.NewReader(message)))
+
+		_, err = xopenpgp.CheckDetachedSignature(keyRing, strings.NewReader(message+"modified"), &sig)
+		require.Error(t, err)
+	}
+
+	var binary bytes.Buffer
+
+	require.NoError(t, entity.Serialize(&binary))
+
+	keyRing, err = xopenpgp.ReadKeyRing(&binary)
+	require.NoError(t, err)
+	require.Equal(t, entity.PrimaryKey.Fingerprint(), keyRing[0].PrimaryKey.Fingerprint[:])
+}
+
+func TestGPG(t *testing.T) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg is not installed")
+	}
+
+	km := mockkms.NewForTest(t)
+	svc := openpgp.New(km)
+	created := time.Now().Add(-time.Hour)
+
+	for _, kt := range []kmsapi.KeyType{kmsapi.ED25519Type, kmsapi.RSARS256Type} {
+		t.Run(string(kt), func(t *testing.T) {
+			entity := &openpgp.Entity{
+				PrimaryKey: newKey(t, km, svc, kt, created),
+				UserIDs:    []string{"Alice <alice@example.com>"},
+				Subkeys:    []*openpgp.Key{newKey(t, km, svc, kmsapi.ED25519Type, created)},
+			}
+
+			dir := t.TempDir()
+
+			var pub bytes.Buffer
+
+			require.NoError(t, entity.SerializeArmored(&pub))
+			writeFile(t, dir, "key.asc", pub.Bytes())
+			writeFile(t, dir, "message", []byte(message))
+
+			gpg(t, dir, "--import", filepath.Join(dir, "key.asc"))
+
+			for _, key := range []*openpgp.Key{entity.PrimaryKey, entity.Subkeys[0]} {
+				var sig bytes.Buffer
+
+				require.NoError(t, key.ArmoredDetachSign(&sig, strings.NewReader(message)))
+				writeFile(t, dir, "message.asc", sig.Bytes())
+
+				status := gpg(t, dir, "--verify", filepath.Join(dir, "message.asc"), filepath.Join(dir, "message"))
+				require.Contains(t, status, "[GNUPG:] VALIDSIG "+strings.ToUpper(hex.EncodeToString(key.Fingerprint())))
+			}
+		})
+	}
+}
+
+func TestKeyErrors(t *testing.T) {
+	km := mockkms.NewForTest(t)
+	svc := openpgp.New(km)
+
+	_, err := svc.Key("missing", time.Now())
+	require.ErrorContains(t, err, "openpgp key: signer: get key:")
+
+	_, err = svc.Key("missing", time.Unix(-1, 0))
+	require.EqualError(t, err, "openpgp key: creation time out of range")
+
+	keyID, _, err := km.Create(kmsapi.ECDSAP256TypeIEEEP1363)
+	require.NoError(t, err)
+
+	_, err = svc.Key(keyID, time.Now())
+	require.EqualError(t, err, "openpgp key: key type *ecdsa.PublicKey is not supported")
+
+	created := time.Now()
+	key := newKey(t, km, svc, kmsapi.ED25519Type, created)
+
+	again := newKey(t, km, svc, kmsapi.ED25519Type, created)
+	require.NotEqual(t, key.Fingerprint(), again.Fingerprint())
+
+	before := openpgp.WithClock(func() time.Time {
+		return created.Add(-time.Hour)
+	})
+
+	err = key.ArmoredDetachSign(&bytes.Buffer{}, strings.NewReader(message), before)
+	require.EqualError(t, err,
+		"openpgp detach sign: signature creation time is before the key creation time")
+
+	err = (&openpgp.Entity{UserIDs: []string{"Alice"}}).Serialize(&bytes.Buffer{})
+	require.EqualError(t, err, "openpgp serialize: missing primary key")
+
+	err = (&openpgp.Entity{PrimaryKey: key}).SerializeArmored(&bytes.Buffer{})
+	require.EqualError(t, err, "openpgp serialize: missing user ID")
+
+	err = (&openpgp.Entity{PrimaryKey: key, UserIDs: []string{"Alice"}}).Serialize(&bytes.Buffer{}, before)
+	require.ErrorContains(t, err, "openpgp serialize: certify user ID 'Alice':")
+}
+
+func newKey(t *testing.T, km kmsapi.KeyManager, svc *openpgp.Service, kt kmsapi.KeyType,
+	created time.Time) *openpgp.Key {
+	t.Helper()
+
+	keyID, _, err := km.Create(kt)
+	require.NoError(t, err)
+
+	key, err := svc.Key(keyID, created)
+	require.NoError(t, err)
+
+	again, err := svc.Key(keyID, created)
+	require.NoError(t, err)
+	require.Equal(t, key.Fingerprint(), again.Fingerprint())
+
+	return key
+}
+
+func gpg(t *testing.T, home string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("gpg", append([]string{"--homedir", home, "--batch", "--status-fd", "1"}, args...)...)
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	return string(out)
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+}
@@ -0,0 +1,122 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package openpgp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// packet tags (https://www.rfc-editor.org/rfc/rfc4880#section-4.3).
+const (
+	tagSignature    = 2
+	tagPublicKey    = 6
+	tagUserID       = 13
+	tagPublicSubkey = 14
+)
+
+// signature subpacket types (https://www.rfc-editor.org/rfc/rfc4880#section-5.2.3.1).
+const (
+	subpacketCreationTime      = 2
+	subpacketIssuer            = 16
+	subpacketPreferredHash     = 21
+	subpacketKeyFlags          = 27
+	subpacketEmbeddedSignature = 32
+	subpacketIssuerFingerprint = 33
+)
+
+const (
+	armorSignature = "PGP SIGNATURE"
+	armorPublicKey = "PGP PUBLIC KEY BLOCK"
+	armorColumns   = 64
+	crc24Init      = 0xb704ce
+	crc24Poly      = 0x1864cfb
+)
+
+// writePacket writes a new format packet (https://www.rfc-editor.org/rfc/rfc4880#section-4.2.2).
+func writePacket(w io.Writer, tag byte, body []byte) error {
+	header := appendLength([]byte{0xc0 | tag}, len(body))
+
+	if _, err := w.Write(append(header, body...)); err != nil {
+		return fmt.Errorf("write packet: %w", err)
+	}
+
+	return nil
+}
+
+// appendLength appends the length n of a new format packet or of a subpacket to b.
+func appendLength(b []byte, n int) []byte {
+	switch {
+	case n < 192: //nolint:gomnd // one octet length
+		return append(b, byte(n))
+	case n < 8384: //nolint:gomnd // two octets length
+		n -= 192
+
+		return append(b, byte(n>>8)+192, byte(n)) //nolint:gomnd // two octets length
+	default:
+		return binary.BigEndian.AppendUint32(append(b, 0xff), uint32(n))
+	}
+}
+
+// mpi encodes b, a big endian integer, as a multiprecision integer.
+func mpi(b []byte) []byte {
+	v := new(big.Int).SetBytes(b)
+
+	return append(binary.BigEndian.AppendUint16(nil, uint16(v.BitLen())), v.Bytes()...)
+}
+
+// subpacket encodes a signature subpacket, its length includes its type.
+func subpacket(typ byte, data []byte) []byte {
+	return append(append(appendLength(nil, len(data)+1), typ), data...)
+}
+
+// armor writes data as an ASCII armored block (https://www.rfc-editor.org/rfc/rfc4880#section-6.2).
+func armor(w io.Writer, blockType string, data []byte) error {
+	var b bytes.Buffer
+
+	b.WriteString("-----BEGIN " + blockType + "-----\n\n")
+
+	for s := base64.StdEncoding.EncodeToString(data); s != ""; {
+		n := min(len(s), armorColumns)
+
+		b.WriteString(s[:n] + "\n")
+		s = s[n:]
+	}
+
+	crc := crc24(data)
+
+	b.WriteString("=" + base64.StdEncoding.EncodeToString([]byte{byte(crc >> 16), byte(crc >> 8), byte(crc)}) + "\n")
+	b.WriteString("-----END " + blockType + "-----\n")
+
+	if _, err := w.Write(b.Bytes()); err != nil {
+		return fmt.Errorf("write armor: %w", err)
+	}
+
+	return nil
+}
+
+func crc24(data []byte) uint32 {
+	crc := uint32(crc24Init)
+
+	for _, v := range data {
+		crc ^= uint32(v) << 16 //nolint:gomnd // CRC-24
+
+		for i := 0; i < 8; i++ {
+			crc <<= 1
+
+			if crc&0x1000000 != 0 {
+				crc ^= crc24Poly
+			}
+		}
+	}
+
+	return crc & 0xffffff
+}
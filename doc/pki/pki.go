@@ -0,0 +1,234 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package pki creates X.509 certificate requests (https://www.rfc-editor.org/rfc/rfc2986) and issues X.509
+// certificates (https://www.rfc-editor.org/rfc/rfc5280) with keys held by a KMS, eg: to run an internal CA on top of
+// localkms. The keys sign with the crypto.Signer of the signer package, the certificates and requests are DER
+// encoded.
+package pki
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/trustbloc/kms-go/crypto/signer"
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+const serialNumberBits = 128
+
+// SubjectAltNames are the subject alternative names of a certificate request.
+type SubjectAltNames struct {
+	DNSNames       []string
+	EmailAddresses []string
+	IPAddresses    []net.IP
+	URIs           []*url.URL
+}
+
+// Service creates certificate requests and certificates with keys managed by a KeyManager.
+type Service struct {
+	km kms.KeyManager
+}
+
+// New creates a new pki Service.
+func New(km kms.KeyManager) *Service {
+	return &Service{km: km}
+}
+
+// Opt is a SignCertificate option.
+type Opt func(o *opts)
+
+type opts struct {
+	parent *x509.Certificate
+	now    func() time.Time
+}
+
+// WithParent sets the certificate of the CA key, the issuer of the signed certificate. The certificate is
+// self-signed by default: the key of the request must then be the CA key.
+func WithParent(cert *x509.Certificate) Opt {
+	return func(o *opts) {
+		o.parent = cert
+	}
+}
+
+// WithClock sets the clock of the default NotBefore time of the certificates, time.Now by default.
+func WithClock(now func() time.Time) Opt {
+	return func(o *opts) {
+		o.now = now
+	}
+}
+
+// CreateCSR returns the DER certificate request of the subject and the subject alternative names sans signed with the
+// key keyID. RSAPS256Type keys sign with RSA-PSS.
+func (s *Service) CreateCSR(keyID string, subject pkix.Name, sans *SubjectAltNames) ([]byte, error) {
+	sig, alg, err := s.signer(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("pki create csr: %w", err)
+	}
+
+	template := &x509.CertificateRequest{
+		Subject:            subject,
+		SignatureAlgorithm: alg,
+	}
+
+	if sans != nil {
+		template.DNSNames = sans.DNSNames
+		template.EmailAddresses = sans.EmailAddresses
+		template.IPAddresses = sans.IPAddresses
+		template.URIs = sans.URIs
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, template, sig)
+	if err != nil {
+		return nil, fmt.Errorf("pki create csr: %w", err)
+	}
+
+	return csr, nil
+}
+
+// SignCertificate returns the DER certificate of the public key of the DER certificate request csr signed with the
+// CA key caKeyID. The signature of csr is verified. The certificate is created from template, at least NotAfter must
+// be set, with defaults:
+//   - the subject and the subject alternative names of csr if template has none, the other extensions of csr are
+//     ignored,
+//   - a random 128 bits serial number,
+//   - the current time as NotBefore,
+//   - the signature algorithm of the CA key type.
+func (s *Service) SignCertificate(caKeyID string, template *x509.Certificate, csr []byte, options ...Opt) ([]byte,
+	error) {
+	o := &opts{now: time.Now}
+
+	for _, opt := range options {
+		opt(o)
+	}
+
+	req, err := x509.ParseCertificateRequest(csr)
+	if err != nil {
+		return nil, fmt.Errorf("pki sign certificate: parse csr: %w", err)
+	}
+
+	if err = req.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("pki sign certificate: csr signature: %w", err)
+	}
+
+	sig, alg, err := s.signer(caKeyID)
+	if err != nil {
+		return nil, fmt.Errorf("pki sign certificate: %w", err)
+	}
+
+	if err = checkIssuer(o.parent, sig, req); err != nil {
+		return nil, fmt.Errorf("pki sign certificate: %w", err)
+	}
+
+	cert, err := certificateTemplate(template, req, alg, o.now)
+	if err != nil {
+		return nil, fmt.Errorf("pki sign certificate: %w", err)
+	}
+
+	parent := o.parent
+	if parent == nil {
+		parent = cert
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, cert, parent, req.PublicKey, sig)
+	if err != nil {
+		return nil, fmt.Errorf("pki sign certificate: %w", err)
+	}
+
+	return der, nil
+}
+
+// signer returns the crypto.Signer of the key keyID and its signature algorithm, or x509.UnknownSignatureAlgorithm
+// for the default algorithm of the key.
+func (s *Service) signer(keyID string) (*signer.Signer, x509.SignatureAlgorithm, error) {
+	_, kt, err := s.km.ExportPubKeyBytes(keyID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("export public key: %w", err)
+	}
+
+	sig, err := signer.New(s.km, keyID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if kt == kms.RSAPS256Type {
+		return sig, x509.SHA256WithRSAPSS, nil
+	}
+
+	return sig, x509.UnknownSignatureAlgorithm, nil
+}
+
+// checkIssuer checks the public key of parent is the CA key or, without parent, the key of req is the CA key of a
+// self-signed certificate.
+func checkIssuer(parent *x509.Certificate, sig *signer.Signer, req *x509.CertificateRequest) error {
+	if parent == nil {
+		if !publicKeyEqual(req.PublicKey, sig) {
+			return errors.New("the csr key must be the CA key of a self-signed certificate")
+		}
+
+		return nil
+	}
+
+	if !publicKeyEqual(parent.PublicKey, sig) {
+		return errors.New("parent certificate public key does not match the CA key")
+	}
+
+	return nil
+}
+
+func certificateTemplate(template *x509.Certificate, req *x509.CertificateRequest, alg x509.SignatureAlgorithm,
+	now func() time.Time) (*x509.Certificate, error) {
+	if template == nil || template.NotAfter.IsZero() {
+		return nil, errors.New("missing certificate NotAfter time")
+	}
+
+	cert := *template
+
+	if len(cert.Subject.ToRDNSequence()) == 0 {
+		cert.Subject = req.Subject
+	}
+
+	if len(cert.DNSNames)+len(cert.EmailAddresses)+len(cert.IPAddresses)+len(cert.URIs) == 0 {
+		cert.DNSNames = req.DNSNames
+		cert.EmailAddresses = req.EmailAddresses
+		cert.IPAddresses = req.IPAddresses
+		cert.URIs = req.URIs
+	}
+
+	if cert.SerialNumber == nil {
+		serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialNumberBits))
+		if err != nil {
+			return nil, fmt.Errorf("create serial number: %w", err)
+		}
+
+		// a zero serial number is not allowed.
+		cert.SerialNumber = serial.Add(serial, big.NewInt(1))
+	}
+
+	if cert.NotBefore.IsZero() {
+		cert.NotBefore = now()
+	}
+
+	if cert.SignatureAlgorithm == x509.UnknownSignatureAlgorithm {
+		cert.SignatureAlgorithm = alg
+	}
+
+	return &cert, nil
+}
+
+func publicKeyEqual(pub crypto.PublicKey, sig *signer.Signer) bool {
+	k, ok := pub.(interface{ Equal(x crypto.PublicKey) bool })
+
+	return ok && k.Equal(sig.Public())
+}
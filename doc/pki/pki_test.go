@@ -0,0 +1,201 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pki_test
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/doc/pki"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func TestIssueCertificates(t *testing.T) {
+	km := mockkms.NewForTest(t)
+	svc := pki.New(km)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	clock := func() time.Time { return now }
+
+	rootKeyID := createKey(t, km, kmsapi.ECDSAP384TypeDER)
+	intermediateKeyID := createKey(t, km, kmsapi.ED25519Type)
+	leafKeyID := createKey(t, km, kmsapi.RSAPS256Type)
+
+	// root CA.
+	csr, err := svc.CreateCSR(rootKeyID, pkix.Name{CommonName: "Root CA", Organization: []string{"Example"}}, nil)
+	require.NoError(t, err)
+
+	caTemplate := &x509.Certificate{
+		NotAfter:              now.Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := svc.SignCertificate(rootKeyID, caTemplate, csr, pki.WithClock(clock))
+	require.NoError(t, err)
+	require.Nil(t, caTemplate.SerialNumber)
+
+	root := parseCertificate(t, der)
+	require.Equal(t, "Root CA", root.Subject.CommonName)
+	require.Equal(t, root.Subject.String(), root.Issuer.String())
+	require.Equal(t, x509.ECDSAWithSHA384, root.SignatureAlgorithm)
+	require.Equal(t, now, root.NotBefore)
+	require.Equal(t, 1, root.SerialNumber.Sign())
+	require.NotEmpty(t, root.SubjectKeyId)
+	require.NoError(t, root.CheckSignatureFrom(root))
+
+	// intermediate CA.
+	csr, err = svc.CreateCSR(intermediateKeyID, pkix.Name{CommonName: "Intermediate CA"}, nil)
+	require.NoError(t, err)
+
+	der, err = svc.SignCertificate(rootKeyID, caTemplate, csr, pki.WithParent(root))
+	require.NoError(t, err)
+
+	intermediate := parseCertificate(t, der)
+	require.Equal(t, root.SubjectKeyId, intermediate.AuthorityKeyId)
+
+	// leaf.
+	u, err := url.Parse("spiffe://example.org/service")
+	require.NoError(t, err)
+
+	csr, err = svc.CreateCSR(leafKeyID, pkix.Name{CommonName: "service"}, &pki.SubjectAltNames{
+		DNSNames:       []string{"service.example.org"},
+		EmailAddresses: []string{"admin@example.org"},
+		IPAddresses:    []net.IP{net.IPv4(127, 0, 0, 1)},
+		URIs:           []*url.URL{u},
+	})
+	require.NoError(t, err)
+
+	req, err := x509.ParseCertificateRequest(csr)
+	require.NoError(t, err)
+	require.Equal(t, x509.SHA256WithRSAPSS, req.SignatureAlgorithm)
+
+	der, err = svc.SignCertificate(intermediateKeyID, &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		NotAfter:     now.Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, csr, pki.WithParent(intermediate))
+	require.NoError(t, err)
+
+	leaf := parseCertificate(t, der)
+	require.Equal(t, big.NewInt(42), leaf.SerialNumber)
+	require.Equal(t, "service", leaf.Subject.CommonName)
+	require.Equal(t, []string{"service.example.org"}, leaf.DNSNames)
+	require.Equal(t, []string{"admin@example.org"}, leaf.EmailAddresses)
+	require.True(t, leaf.IPAddresses[0].Equal(net.IPv4(127, 0, 0, 1)))
+	require.Equal(t, u.String(), leaf.URIs[0].String())
+	require.Equal(t, x509.PureEd25519, leaf.SignatureAlgorithm)
+
+	roots, intermediates := x509.NewCertPool(), x509.NewCertPool()
+	roots.AddCert(root)
+	intermediates.AddCert(intermediate)
+
+	chains, err := leaf.Verify(x509.VerifyOptions{
+		DNSName:       "service.example.org",
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now.Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.Len(t, chains, 1)
+	require.Len(t, chains[0], 3)
+}
+
+func TestSignCertificateTemplate(t *testing.T) {
+	km := mockkms.NewForTest(t)
+	svc := pki.New(km)
+
+	keyID := createKey(t, km, kmsapi.ECDSAP256TypeIEEEP1363)
+
+	csr, err := svc.CreateCSR(keyID, pkix.Name{CommonName: "csr"}, &pki.SubjectAltNames{DNSNames: []string{"csr"}})
+	require.NoError(t, err)
+
+	der, err := svc.SignCertificate(keyID, &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "template"},
+		DNSNames: []string{"template"},
+		NotAfter: time.Now().Add(time.Hour),
+	}, csr)
+	require.NoError(t, err)
+
+	cert := parseCertificate(t, der)
+	require.Equal(t, "template", cert.Subject.CommonName)
+	require.Equal(t, []string{"template"}, cert.DNSNames)
+	require.Equal(t, x509.ECDSAWithSHA256, cert.SignatureAlgorithm)
+}
+
+func TestErrors(t *testing.T) {
+	km := mockkms.NewForTest(t)
+	svc := pki.New(km)
+
+	caKeyID := createKey(t, km, kmsapi.ECDSAP256TypeDER)
+	keyID := createKey(t, km, kmsapi.ECDSAP256TypeDER)
+	template := &x509.Certificate{NotAfter: time.Now().Add(time.Hour)}
+
+	_, err := svc.CreateCSR("missing", pkix.Name{}, nil)
+	require.ErrorContains(t, err, "pki create csr: export public key:")
+
+	aesKeyID, _, err := km.Create(kmsapi.AES256GCMType)
+	require.NoError(t, err)
+
+	_, err = svc.CreateCSR(aesKeyID, pkix.Name{}, nil)
+	require.ErrorContains(t, err, "pki create csr: export public key:")
+
+	csr, err := svc.CreateCSR(keyID, pkix.Name{CommonName: "service"}, nil)
+	require.NoError(t, err)
+
+	_, err = svc.SignCertificate(caKeyID, template, []byte("not a csr"))
+	require.ErrorContains(t, err, "pki sign certificate: parse csr:")
+
+	tampered := append([]byte{}, csr...)
+	tampered[len(tampered)-1] ^= 1
+
+	_, err = svc.SignCertificate(caKeyID, template, tampered)
+	require.ErrorContains(t, err, "pki sign certificate: csr signature:")
+
+	_, err = svc.SignCertificate("missing", template, csr)
+	require.ErrorContains(t, err, "pki sign certificate: export public key:")
+
+	_, err = svc.SignCertificate(caKeyID, template, csr)
+	require.EqualError(t, err, "pki sign certificate: the csr key must be the CA key of a self-signed certificate")
+
+	_, err = svc.SignCertificate(keyID, nil, csr)
+	require.EqualError(t, err, "pki sign certificate: missing certificate NotAfter time")
+
+	der, err := svc.SignCertificate(keyID, template, csr)
+	require.NoError(t, err)
+
+	_, err = svc.SignCertificate(caKeyID, template, csr, pki.WithParent(parseCertificate(t, der)))
+	require.EqualError(t, err, "pki sign certificate: parent certificate public key does not match the CA key")
+}
+
+func createKey(t *testing.T, km kmsapi.KeyManager, kt kmsapi.KeyType) string {
+	t.Helper()
+
+	keyID, _, err := km.CreateAndExportPubKeyBytes(kt)
+	require.NoError(t, err)
+
+	return keyID
+}
+
+func parseCertificate(t *testing.T, der []byte) *x509.Certificate {
+	t.Helper()
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert
+}
@@ -0,0 +1,312 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package sdjwt issues Selective Disclosure JWTs signed with KMS keys
+// (https://datatracker.ietf.org/doc/draft-ietf-oauth-selective-disclosure-jwt/): selectively disclosable claims are
+// replaced in the issuer JWT by the digests of their salted disclosures, which are appended to the combined
+// serialization so the holder can choose which ones to present.
+package sdjwt
+
+import (
+	"crypto"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	// register the SHA-2 hash functions.
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+
+	"github.com/trustbloc/kms-go/doc/jose"
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/doc/jose/jws"
+)
+
+// SD-JWT claim names and serialization separator.
+const (
+	ClaimSD      = "_sd"
+	ClaimSDAlg   = "_sd_alg"
+	ClaimCnf     = "cnf"
+	Separator    = "~"
+	DefaultType  = "dc+sd-jwt"
+	saltSize     = 16
+	cnfJWKMember = "jwk"
+)
+
+// hashNames are the IANA "Named Information Hash Algorithm" names used in the _sd_alg claim.
+//
+//nolint:gochecknoglobals
+var hashNames = map[crypto.Hash]string{
+	crypto.SHA256: "sha-256",
+	crypto.SHA384: "sha-384",
+	crypto.SHA512: "sha-512",
+}
+
+// alwaysVisible are the claims that are never made selectively disclosable, as they are needed to process the SD-JWT.
+//
+//nolint:gochecknoglobals
+var alwaysVisible = map[string]bool{
+	"iss": true, "iat": true, "nbf": true, "exp": true, "cnf": true, "vct": true, "status": true,
+	ClaimSD: true, ClaimSDAlg: true,
+}
+
+// Disclosure is a salted claim disclosure.
+type Disclosure struct {
+	Salt  string
+	Name  string
+	Value interface{}
+	// Encoded is the base64url encoded disclosure as it appears in the combined serialization.
+	Encoded string
+	// Digest is the digest of the disclosure found in the issuer JWT _sd claim.
+	Digest string
+}
+
+// SDJWT is an issued SD-JWT.
+type SDJWT struct {
+	// JWT is the signed issuer JWT in compact serialization.
+	JWT         string
+	Disclosures []*Disclosure
+}
+
+// Serialize returns the combined format for issuance: the issuer JWT followed by all the disclosures, each one
+// terminated by "~".
+func (s *SDJWT) Serialize() string {
+	var b strings.Builder
+
+	b.WriteString(s.JWT)
+	b.WriteString(Separator)
+
+	for _, d := range s.Disclosures {
+		b.WriteString(d.Encoded)
+		b.WriteString(Separator)
+	}
+
+	return b.String()
+}
+
+// Issuer issues SD-JWTs signed with KMS keys.
+type Issuer struct {
+	jws *jws.Service
+}
+
+// NewIssuer creates an Issuer signing issuer JWTs with svc.
+func NewIssuer(svc *jws.Service) *Issuer {
+	return &Issuer{jws: svc}
+}
+
+// Opt is an Issue option.
+type Opt func(o *opts)
+
+type opts struct {
+	hash         crypto.Hash
+	typ          string
+	visible      map[string]bool
+	selective    map[string]bool
+	decoys       int
+	holderKey    *jwk.JWK
+	randomReader io.Reader
+}
+
+// WithHashAlgorithm sets the disclosure digest hash algorithm, SHA-256 (the default), SHA-384 or SHA-512.
+func WithHashAlgorithm(h crypto.Hash) Opt {
+	return func(o *opts) {
+		o.hash = h
+	}
+}
+
+// WithType sets the "typ" header of the issuer JWT, "dc+sd-jwt" by default.
+func WithType(typ string) Opt {
+	return func(o *opts) {
+		o.typ = typ
+	}
+}
+
+// WithSelectiveClaims only makes the given top level claims selectively disclosable. By default, all the top level
+// claims are, but the registered ones needed to process the SD-JWT (iss, iat, nbf, exp, cnf, vct and status).
+func WithSelectiveClaims(names ...string) Opt {
+	return func(o *opts) {
+		o.selective = map[string]bool{}
+
+		for _, n := range names {
+			o.selective[n] = true
+		}
+	}
+}
+
+// WithVisibleClaims keeps the given top level claims in plain text in the issuer JWT.
+func WithVisibleClaims(names ...string) Opt {
+	return func(o *opts) {
+		for _, n := range names {
+			o.visible[n] = true
+		}
+	}
+}
+
+// WithDecoyDigests adds count decoy digests to the _sd claim to hide the number of selectively disclosable claims.
+func WithDecoyDigests(count int) Opt {
+	return func(o *opts) {
+		o.decoys = count
+	}
+}
+
+// WithHolderKey binds the SD-JWT to the holder public key, set as the "cnf" claim for key binding.
+func WithHolderKey(key *jwk.JWK) Opt {
+	return func(o *opts) {
+		o.holderKey = key
+	}
+}
+
+// WithRandomReader sets the source of salts and decoy digests, crypto/rand by default.
+func WithRandomReader(r io.Reader) Opt {
+	return func(o *opts) {
+		o.randomReader = r
+	}
+}
+
+// Issue creates the issuer JWT from claims, with the top level claims made selectively disclosable, and signs it
+// with the KMS key keyID.
+func (i *Issuer) Issue(keyID string, claims map[string]interface{}, options ...Opt) (*SDJWT, error) {
+	o := &opts{hash: crypto.SHA256, typ: DefaultType, visible: map[string]bool{}, randomReader: rand.Reader}
+
+	for _, opt := range options {
+		opt(o)
+	}
+
+	hashName, ok := hashNames[o.hash]
+	if !ok || !o.hash.Available() {
+		return nil, fmt.Errorf("sdjwt: unsupported hash algorithm %s", o.hash)
+	}
+
+	payload, disclosures, err := buildPayload(claims, o)
+	if err != nil {
+		return nil, fmt.Errorf("sdjwt: %w", err)
+	}
+
+	payload[ClaimSDAlg] = hashName
+
+	if o.holderKey != nil {
+		payload[ClaimCnf] = map[string]interface{}{cnfJWKMember: o.holderKey}
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("sdjwt: marshal payload: %w", err)
+	}
+
+	signed, err := i.jws.SignJWS(keyID, jose.Headers{jose.HeaderType: o.typ}, payloadBytes)
+	if err != nil {
+		return nil, fmt.Errorf("sdjwt: %w", err)
+	}
+
+	compact, err := signed.Compact(false)
+	if err != nil {
+		return nil, fmt.Errorf("sdjwt: %w", err)
+	}
+
+	return &SDJWT{JWT: compact, Disclosures: disclosures}, nil
+}
+
+func buildPayload(claims map[string]interface{}, o *opts) (map[string]interface{}, []*Disclosure, error) {
+	if _, ok := claims[ClaimSD]; ok {
+		return nil, nil, errors.New("claims may not contain the _sd claim")
+	}
+
+	payload := make(map[string]interface{}, len(claims))
+
+	var (
+		disclosures []*Disclosure
+		digests     []string
+	)
+
+	for name, value := range claims {
+		if !isSelective(name, o) {
+			payload[name] = value
+
+			continue
+		}
+
+		d, err := newDisclosure(name, value, o)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		disclosures = append(disclosures, d)
+		digests = append(digests, d.Digest)
+	}
+
+	for n := 0; n < o.decoys; n++ {
+		decoy, err := randomString(o.randomReader)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		digests = append(digests, digest(o.hash, decoy))
+	}
+
+	// sorted digests don't leak the claims order.
+	sort.Strings(digests)
+
+	sort.Slice(disclosures, func(i, j int) bool { return disclosures[i].Name < disclosures[j].Name })
+
+	if len(digests) > 0 {
+		payload[ClaimSD] = digests
+	}
+
+	return payload, disclosures, nil
+}
+
+func isSelective(name string, o *opts) bool {
+	if alwaysVisible[name] || o.visible[name] {
+		return false
+	}
+
+	return o.selective == nil || o.selective[name]
+}
+
+func newDisclosure(name string, value interface{}, o *opts) (*Disclosure, error) {
+	salt, err := randomString(o.randomReader)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal([]interface{}{salt, name, value})
+	if err != nil {
+		return nil, fmt.Errorf("marshal disclosure '%s': %w", name, err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(raw)
+
+	return &Disclosure{
+		Salt:    salt,
+		Name:    name,
+		Value:   value,
+		Encoded: encoded,
+		Digest:  digest(o.hash, encoded),
+	}, nil
+}
+
+// digest returns the base64url encoded digest of an encoded disclosure.
+func digest(h crypto.Hash, encoded string) string {
+	hh := h.New()
+	hh.Write([]byte(encoded))
+
+	return base64.RawURLEncoding.EncodeToString(hh.Sum(nil))
+}
+
+func randomString(r io.Reader) (string, error) {
+	b := make([]byte, saltSize)
+
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", fmt.Errorf("read random: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
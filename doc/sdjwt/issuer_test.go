@@ -0,0 +1,174 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdjwt
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+	"github.com/trustbloc/kms-go/doc/jose/jws"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func newJWSService(t *testing.T) (*jws.Service, kmsapi.KeyManager) {
+	t.Helper()
+
+	km := mockkms.NewForTest(t)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	return jws.New(km, cr), km
+}
+
+func TestIssue(t *testing.T) {
+	svc, km := newJWSService(t)
+
+	keyID, _, err := km.Create(kmsapi.ECDSAP256TypeIEEEP1363)
+	require.NoError(t, err)
+
+	claims := map[string]interface{}{
+		"iss":         "https://issuer.example.com",
+		"exp":         1883000000,
+		"given_name":  "John",
+		"family_name": "Doe",
+		"address":     map[string]interface{}{"country": "DE"},
+	}
+
+	sdJWT, err := NewIssuer(svc).Issue(keyID, claims)
+	require.NoError(t, err)
+	require.Len(t, sdJWT.Disclosures, 3)
+
+	parts := strings.Split(sdJWT.Serialize(), Separator)
+	require.Len(t, parts, 5)
+	require.Equal(t, sdJWT.JWT, parts[0])
+	require.Empty(t, parts[4])
+
+	verified, err := svc.VerifyJWS("", parts[0])
+	require.NoError(t, err)
+
+	typ, _ := verified.ProtectedHeaders.Type()
+	require.Equal(t, DefaultType, typ)
+
+	payload := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(verified.Payload, &payload))
+
+	require.Equal(t, "https://issuer.example.com", payload["iss"])
+	require.Equal(t, "sha-256", payload[ClaimSDAlg])
+	require.NotContains(t, payload, "given_name")
+	require.NotContains(t, payload, "address")
+
+	digests := payload[ClaimSD].([]interface{})
+	require.Len(t, digests, 3)
+
+	disclosed := map[string]interface{}{}
+
+	for _, encoded := range parts[1:4] {
+		sum := sha256.Sum256([]byte(encoded))
+		require.Contains(t, digests, base64.RawURLEncoding.EncodeToString(sum[:]))
+
+		raw, err := base64.RawURLEncoding.DecodeString(encoded)
+		require.NoError(t, err)
+
+		var d []interface{}
+		require.NoError(t, json.Unmarshal(raw, &d))
+		require.Len(t, d, 3)
+
+		disclosed[d[1].(string)] = d[2]
+	}
+
+	require.Equal(t, "John", disclosed["given_name"])
+	require.Equal(t, "Doe", disclosed["family_name"])
+	require.Equal(t, map[string]interface{}{"country": "DE"}, disclosed["address"])
+}
+
+func TestIssueOptions(t *testing.T) {
+	svc, km := newJWSService(t)
+
+	keyID, _, err := km.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	holderKID, holderPub, err := km.CreateAndExportPubKeyBytes(kmsapi.ECDSAP256TypeIEEEP1363)
+	require.NoError(t, err)
+
+	holderJWK, err := jwksupport.PubKeyBytesToJWK(holderPub, kmsapi.ECDSAP256TypeIEEEP1363)
+	require.NoError(t, err)
+
+	holderJWK.KeyID = holderKID
+
+	claims := map[string]interface{}{"a": 1, "b": 2, "c": 3}
+
+	sdJWT, err := NewIssuer(svc).Issue(keyID, claims,
+		WithSelectiveClaims("a", "b"),
+		WithVisibleClaims("b"),
+		WithDecoyDigests(4),
+		WithHashAlgorithm(crypto.SHA512),
+		WithType("vc+sd-jwt"),
+		WithHolderKey(holderJWK))
+	require.NoError(t, err)
+	require.Len(t, sdJWT.Disclosures, 1)
+	require.Equal(t, "a", sdJWT.Disclosures[0].Name)
+
+	verified, err := svc.VerifyJWS("", sdJWT.JWT)
+	require.NoError(t, err)
+
+	typ, _ := verified.ProtectedHeaders.Type()
+	require.Equal(t, "vc+sd-jwt", typ)
+
+	payload := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(verified.Payload, &payload))
+
+	require.Equal(t, "sha-512", payload[ClaimSDAlg])
+	require.EqualValues(t, 2, payload["b"])
+	require.EqualValues(t, 3, payload["c"])
+	require.Len(t, payload[ClaimSD], 5)
+	require.Contains(t, payload[ClaimSD], sdJWT.Disclosures[0].Digest)
+
+	cnf := payload[ClaimCnf].(map[string]interface{})
+	require.Equal(t, holderKID, cnf["jwk"].(map[string]interface{})["kid"])
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("no entropy")
+}
+
+func TestIssueErrors(t *testing.T) {
+	svc, km := newJWSService(t)
+
+	keyID, _, err := km.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	issuer := NewIssuer(svc)
+
+	_, err = issuer.Issue(keyID, map[string]interface{}{ClaimSD: []string{}})
+	require.ErrorContains(t, err, "_sd claim")
+
+	_, err = issuer.Issue(keyID, map[string]interface{}{"a": 1}, WithHashAlgorithm(crypto.MD5))
+	require.ErrorContains(t, err, "unsupported hash algorithm")
+
+	_, err = issuer.Issue(keyID, map[string]interface{}{"a": 1}, WithRandomReader(failingReader{}))
+	require.ErrorContains(t, err, "no entropy")
+
+	_, err = issuer.Issue(keyID, map[string]interface{}{"a": make(chan int)})
+	require.Error(t, err)
+
+	_, err = issuer.Issue("unknown", map[string]interface{}{"a": 1})
+	require.Error(t, err)
+}
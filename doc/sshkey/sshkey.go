@@ -0,0 +1,158 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package sshkey uses the keys held by a KMS as SSH keys: as golang.org/x/crypto/ssh Signers of the SSH clients and
+// servers, or as the CA keys of OpenSSH certificates, eg: to operate an SSH CA with localkms. The ECDSA, RSA and
+// Ed25519 keys supported by the signer package are SSH keys, RSA keys sign with rsa-sha2-256 or rsa-sha2-512.
+//
+// The public keys are marshaled to the authorized_keys and known_hosts formats of OpenSSH with MarshalAuthorizedKey,
+// MarshalKnownHost and MarshalCertAuthority.
+package sshkey
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/trustbloc/kms-go/crypto/signer"
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+const certAuthorityMarker = "@cert-authority"
+
+// Service creates SSH signers and certificates with keys managed by a KeyManager.
+type Service struct {
+	km kms.KeyManager
+}
+
+// New creates a new SSH key Service.
+func New(km kms.KeyManager) *Service {
+	return &Service{km: km}
+}
+
+// Signer returns the SSH Signer of the key keyID, its PublicKey is the SSH public key of the key.
+func (s *Service) Signer(keyID string) (ssh.Signer, error) {
+	sig, err := s.signer(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("sshkey signer: %w", err)
+	}
+
+	return sig, nil
+}
+
+// SignSSHCertificate signs cert with the CA key caKeyID: it sets the nonce, the signature key and the signature of
+// cert. The key, the type and the ValidBefore time of cert must be set, a random serial number is set if cert has
+// none.
+func (s *Service) SignSSHCertificate(caKeyID string, cert *ssh.Certificate) error {
+	if err := checkCertificate(cert); err != nil {
+		return fmt.Errorf("sshkey sign certificate: %w", err)
+	}
+
+	sig, err := s.signer(caKeyID)
+	if err != nil {
+		return fmt.Errorf("sshkey sign certificate: %w", err)
+	}
+
+	if cert.Serial == 0 {
+		var serial [8]byte
+
+		if _, err = rand.Read(serial[:]); err != nil {
+			return fmt.Errorf("sshkey sign certificate: create serial number: %w", err)
+		}
+
+		cert.Serial = binary.BigEndian.Uint64(serial[:])
+	}
+
+	if err = cert.SignCert(rand.Reader, sig); err != nil {
+		return fmt.Errorf("sshkey sign certificate: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) signer(keyID string) (ssh.Signer, error) {
+	kmsSigner, err := signer.New(s.km, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := ssh.NewSignerFromSigner(kmsSigner)
+	if err != nil {
+		return nil, err
+	}
+
+	if sig.PublicKey().Type() != ssh.KeyAlgoRSA {
+		return sig, nil
+	}
+
+	algSigner, ok := sig.(ssh.AlgorithmSigner)
+	if !ok {
+		return nil, errors.New("RSA signer does not support the SHA-2 signature algorithms")
+	}
+
+	// ssh-rsa signatures use SHA-1.
+	multiAlgSigner, err := ssh.NewSignerWithAlgorithms(algSigner, []string{ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSASHA512})
+	if err != nil {
+		return nil, err
+	}
+
+	return &rsaSigner{MultiAlgorithmSigner: multiAlgSigner}, nil
+}
+
+// rsaSigner is an RSA signer signing with rsa-sha2-256 by default.
+type rsaSigner struct {
+	ssh.MultiAlgorithmSigner
+}
+
+func (s *rsaSigner) Sign(random io.Reader, data []byte) (*ssh.Signature, error) {
+	return s.SignWithAlgorithm(random, data, ssh.KeyAlgoRSASHA256)
+}
+
+func checkCertificate(cert *ssh.Certificate) error {
+	switch {
+	case cert == nil || cert.Key == nil:
+		return errors.New("missing certificate key")
+	case cert.CertType != ssh.UserCert && cert.CertType != ssh.HostCert:
+		return fmt.Errorf("invalid certificate type %d", cert.CertType)
+	case cert.ValidBefore == 0:
+		return errors.New("missing certificate ValidBefore time")
+	case cert.ValidAfter >= cert.ValidBefore:
+		return errors.New("certificate ValidAfter time is not before its ValidBefore time")
+	default:
+		return nil
+	}
+}
+
+// MarshalAuthorizedKey returns the authorized_keys line of pub, with comment if not empty. The line ends with a
+// newline.
+func MarshalAuthorizedKey(pub ssh.PublicKey, comment string) string {
+	line := strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(pub)), "\n")
+
+	if comment != "" {
+		line += " " + comment
+	}
+
+	return line + "\n"
+}
+
+// MarshalKnownHost returns the known_hosts line of the host key pub of the host addresses, eg: "example.com" or
+// "192.0.2.1:2222". The line ends with a newline.
+func MarshalKnownHost(addresses []string, pub ssh.PublicKey) string {
+	return knownhosts.Line(addresses, pub) + "\n"
+}
+
+// MarshalCertAuthority returns the @cert-authority known_hosts line of the CA key pub of the host certificates of the
+// host patterns, eg: "*.example.com". The line ends with a newline.
+func MarshalCertAuthority(patterns []string, pub ssh.PublicKey) string {
+	return certAuthorityMarker + " " + strings.Join(patterns, ",") + " " +
+		strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(pub)), "\n") + "\n"
+}
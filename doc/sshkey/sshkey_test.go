@@ -0,0 +1,241 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sshkey_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/trustbloc/kms-go/doc/sshkey"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func TestSigner(t *testing.T) {
+	km := mockkms.NewForTest(t)
+	svc := sshkey.New(km)
+	data := []byte("session data")
+
+	for kt, keyAlg := range map[kmsapi.KeyType]string{
+		kmsapi.ECDSAP256TypeDER:       ssh.KeyAlgoECDSA256,
+		kmsapi.ECDSAP384TypeIEEEP1363: ssh.KeyAlgoECDSA384,
+		kmsapi.ECDSAP521TypeDER:       ssh.KeyAlgoECDSA521,
+		kmsapi.RSARS256Type:           ssh.KeyAlgoRSA,
+		kmsapi.ED25519Type:            ssh.KeyAlgoED25519,
+	} {
+		t.Run(string(kt), func(t *testing.T) {
+			sig, err := svc.Signer(createKey(t, km, kt))
+			require.NoError(t, err)
+			require.Equal(t, keyAlg, sig.PublicKey().Type())
+
+			signature, err := sig.Sign(rand.Reader, data)
+			require.NoError(t, err)
+			require.NoError(t, sig.PublicKey().Verify(data, signature))
+
+			if keyAlg == ssh.KeyAlgoRSA {
+				require.Equal(t, ssh.KeyAlgoRSASHA256, signature.Format)
+			}
+
+			line := sshkey.MarshalAuthorizedKey(sig.PublicKey(), "user@example.com")
+
+			pub, comment, _, rest, err := ssh.ParseAuthorizedKey([]byte(line))
+			require.NoError(t, err)
+			require.Empty(t, rest)
+			require.Equal(t, "user@example.com", comment)
+			require.Equal(t, sig.PublicKey().Marshal(), pub.Marshal())
+		})
+	}
+
+	_, err := svc.Signer("missing")
+	require.ErrorContains(t, err, "sshkey signer:")
+}
+
+func TestCertificates(t *testing.T) {
+	km := mockkms.NewForTest(t)
+	svc := sshkey.New(km)
+
+	caKeyID := createKey(t, km, kmsapi.ED25519Type)
+
+	ca, err := svc.Signer(caKeyID)
+	require.NoError(t, err)
+
+	hostSigner, err := svc.Signer(createKey(t, km, kmsapi.ECDSAP256TypeDER))
+	require.NoError(t, err)
+
+	userSigner, err := svc.Signer(createKey(t, km, kmsapi.RSARS256Type))
+	require.NoError(t, err)
+
+	now := time.Now()
+
+	hostCert := &ssh.Certificate{
+		Key:             hostSigner.PublicKey(),
+		CertType:        ssh.HostCert,
+		KeyId:           "host",
+		ValidPrincipals: []string{"host.example.com"},
+		ValidAfter:      uint64(now.Add(-time.Minute).Unix()),
+		ValidBefore:     uint64(now.Add(time.Hour).Unix()),
+	}
+	require.NoError(t, svc.SignSSHCertificate(caKeyID, hostCert))
+	require.NotZero(t, hostCert.Serial)
+	require.Equal(t, ca.PublicKey().Marshal(), hostCert.SignatureKey.Marshal())
+
+	userCert := &ssh.Certificate{
+		Key:             userSigner.PublicKey(),
+		Serial:          42,
+		CertType:        ssh.UserCert,
+		KeyId:           "user",
+		ValidPrincipals: []string{"alice"},
+		ValidBefore:     ssh.CertTimeInfinity,
+		Permissions: ssh.Permissions{
+			Extensions: map[string]string{"permit-pty": ""},
+		},
+	}
+	require.NoError(t, svc.SignSSHCertificate(caKeyID, userCert))
+	require.Equal(t, uint64(42), userCert.Serial)
+
+	parsed, _, _, _, err := ssh.ParseAuthorizedKey([]byte(sshkey.MarshalAuthorizedKey(userCert, "")))
+	require.NoError(t, err)
+	require.Equal(t, userCert.Marshal(), parsed.Marshal())
+
+	knownHostsFile := filepath.Join(t.TempDir(), "known_hosts")
+	require.NoError(t, os.WriteFile(knownHostsFile, []byte(
+		sshkey.MarshalCertAuthority([]string{"*.example.com"}, ca.PublicKey())+
+			sshkey.MarshalKnownHost([]string{"plain.example.org:2222"}, hostSigner.PublicKey())), 0o600))
+
+	hostKeyCallback, err := knownhosts.New(knownHostsFile)
+	require.NoError(t, err)
+
+	// the plain host key line is matched by the host name and port.
+	require.NoError(t, hostKeyCallback("plain.example.org:2222", loopback, hostSigner.PublicKey()))
+	require.Error(t, hostKeyCallback("plain.example.org:22", loopback, hostSigner.PublicKey()))
+
+	hostCertSigner, err := ssh.NewCertSigner(hostCert, hostSigner)
+	require.NoError(t, err)
+
+	userCertSigner, err := ssh.NewCertSigner(userCert, userSigner)
+	require.NoError(t, err)
+
+	handshake(t, ca.PublicKey(), hostCertSigner, userCertSigner, hostKeyCallback)
+}
+
+func TestSignSSHCertificateErrors(t *testing.T) {
+	km := mockkms.NewForTest(t)
+	svc := sshkey.New(km)
+
+	caKeyID := createKey(t, km, kmsapi.ED25519Type)
+
+	key, err := svc.Signer(createKey(t, km, kmsapi.ED25519Type))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		cert *ssh.Certificate
+		err  string
+	}{
+		{err: "missing certificate key"},
+		{cert: &ssh.Certificate{}, err: "missing certificate key"},
+		{cert: &ssh.Certificate{Key: key.PublicKey(), CertType: 3}, err: "invalid certificate type 3"},
+		{cert: &ssh.Certificate{Key: key.PublicKey(), CertType: ssh.UserCert}, err: "missing certificate ValidBefore time"},
+		{
+			cert: &ssh.Certificate{Key: key.PublicKey(), CertType: ssh.UserCert, ValidAfter: 2, ValidBefore: 1},
+			err:  "certificate ValidAfter time is not before its ValidBefore time",
+		},
+	} {
+		require.EqualError(t, svc.SignSSHCertificate(caKeyID, tc.cert), "sshkey sign certificate: "+tc.err)
+	}
+
+	err = svc.SignSSHCertificate("missing", &ssh.Certificate{
+		Key: key.PublicKey(), CertType: ssh.UserCert, ValidBefore: ssh.CertTimeInfinity,
+	})
+	require.ErrorContains(t, err, "sshkey sign certificate:")
+}
+
+// handshake connects an SSH client authenticated with the user certificate to an SSH server authenticated with the
+// host certificate, both certificates are issued by ca.
+func handshake(t *testing.T, ca ssh.PublicKey, host, user ssh.Signer, hostKeyCallback ssh.HostKeyCallback) {
+	t.Helper()
+
+	isCA := func(auth ssh.PublicKey) bool {
+		return bytes.Equal(auth.Marshal(), ca.Marshal())
+	}
+
+	checker := &ssh.CertChecker{
+		IsUserAuthority: func(auth ssh.PublicKey) bool { return isCA(auth) },
+	}
+
+	serverConfig := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if _, ok := key.(*ssh.Certificate); !ok {
+				return nil, errors.New("certificate required")
+			}
+
+			return checker.Authenticate(conn, key)
+		},
+	}
+	serverConfig.AddHostKey(host)
+
+	clientConfig := &ssh.ClientConfig{
+		User:              "alice",
+		Auth:              []ssh.AuthMethod{ssh.PublicKeys(user)},
+		HostKeyCallback:   hostKeyCallback,
+		HostKeyAlgorithms: []string{ssh.CertAlgoECDSA256v01},
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, listener.Close())
+	}()
+
+	errs := make(chan error, 1)
+
+	go func() {
+		serverConn, err := listener.Accept()
+		if err != nil {
+			errs <- err
+
+			return
+		}
+
+		defer serverConn.Close() //nolint:errcheck
+
+		_, _, _, err = ssh.NewServerConn(serverConn, serverConfig)
+		errs <- err
+	}()
+
+	clientConn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+
+	conn, _, _, err := ssh.NewClientConn(clientConn, "host.example.com:22", clientConfig)
+	require.NoError(t, err)
+
+	defer conn.Close() //nolint:errcheck
+
+	require.NoError(t, <-errs)
+	require.Equal(t, "alice", conn.User())
+}
+
+var loopback = &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 22} //nolint:gochecknoglobals
+
+func createKey(t *testing.T, km kmsapi.KeyManager, kt kmsapi.KeyType) string {
+	t.Helper()
+
+	keyID, _, err := km.CreateAndExportPubKeyBytes(kt)
+	require.NoError(t, err)
+
+	return keyID
+}
@@ -37,6 +37,8 @@ const (
 	P384PubKeyMultiCodec = 0x1201
 	// P521PubKeyMultiCodec for NIST P-521 public key in multicodec table.
 	P521PubKeyMultiCodec = 0x1202
+	// Secp256k1PubKeyMultiCodec for secp256k1 public key in multicodec table.
+	Secp256k1PubKeyMultiCodec = 0xe7
 
 	// RSAPubKeyMultiCodec for RSA public key in multicodec table.
 	RSAPubKeyMultiCodec = 0x1205
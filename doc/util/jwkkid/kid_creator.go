@@ -16,6 +16,8 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+
 	"github.com/trustbloc/kms-go/util/cryptoutil"
 
 	"github.com/trustbloc/kms-go/spi/kms"
@@ -28,66 +30,102 @@ import (
 
 var errInvalidKeyType = errors.New("key type is not supported")
 
+const bip340XOnlyKeySize = 32
+
 // CreateKID creates a KID value based on the marshalled keyBytes of type kt. This function should be called for
-// asymmetric public keys only (ECDSA DER or IEEE-P1363, ED25519, X25519, BLS12381G2).
+// asymmetric public keys only (ECDSA DER or IEEE-P1363, BIP-340, ED25519, X25519, X448, BLS12381G2, RSA).
+// The KID is the RFC 7638 SHA-256 JWK thumbprint of the key, computed with the KID scheme set with WithScheme.
 // returns:
-//   - base64 raw (no padding) URL encoded KID
+//   - base64 raw (no padding) URL encoded KID with the default scheme
 //   - error in case of error
-//
+func CreateKID(keyBytes []byte, kt kms.KeyType, opts ...Opt) (string, error) {
+	o := &kidOpts{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	tp, err := thumbprint(keyBytes, kt)
+	if err != nil {
+		return "", err
+	}
+
+	kid, err := encodeKID(tp, o.scheme)
+	if err != nil {
+		return "", fmt.Errorf("createKID: %w", err)
+	}
+
+	return kid, nil
+}
+
 //nolint:gocyclo
-func CreateKID(keyBytes []byte, kt kms.KeyType) (string, error) {
+func thumbprint(keyBytes []byte, kt kms.KeyType) ([]byte, error) {
 	if len(keyBytes) == 0 {
-		return "", errors.New("createKID: empty key")
+		return nil, errors.New("createKID: empty key")
 	}
 
 	switch kt {
 	case kms.X25519ECDHKWType: // X25519 JWK is not supported by go jose, manually build it and build its resulting KID.
 		x25519KID, err := createX25519KID(keyBytes)
 		if err != nil {
-			return "", fmt.Errorf("createKID: %w", err)
+			return nil, fmt.Errorf("createKID: %w", err)
 		}
 
 		return x25519KID, nil
+	case kms.X448ECDHKWType: // X448 JWK is not supported by go jose either.
+		x448KID, err := createX448KID(keyBytes)
+		if err != nil {
+			return nil, fmt.Errorf("createKID: %w", err)
+		}
+
+		return x448KID, nil
 	case kms.BLS12381G2Type: // BBS+ as JWK thumbprint.
 		bbsKID, err := createBLS12381G2KID(keyBytes)
 		if err != nil {
-			return "", fmt.Errorf("createKID: %w", err)
+			return nil, fmt.Errorf("createKID: %w", err)
 		}
 
 		return bbsKID, nil
 	case kms.ECDSASecp256k1TypeDER, kms.ECDSASecp256k1TypeIEEEP1363:
 		secp256k1KID, err := secp256k1Thumbprint(keyBytes, kt)
 		if err != nil {
-			return "", fmt.Errorf("createKID: %w", err)
+			return nil, fmt.Errorf("createKID: %w", err)
 		}
 
 		return secp256k1KID, nil
+	case kms.BIP340Secp256k1Type:
+		bip340KID, err := bip340Thumbprint(keyBytes)
+		if err != nil {
+			return nil, fmt.Errorf("createKID: %w", err)
+		}
+
+		return bip340KID, nil
 	}
 
 	j, err := BuildJWK(keyBytes, kt)
 	if err != nil {
-		return "", fmt.Errorf("createKID: failed to build jwk: %w", err)
+		return nil, fmt.Errorf("createKID: failed to build jwk: %w", err)
 	}
 
 	tp, err := j.Thumbprint(crypto.SHA256)
 	if err != nil {
-		return "", fmt.Errorf("createKID: failed to get jwk Thumbprint: %w", err)
+		return nil, fmt.Errorf("createKID: failed to get jwk Thumbprint: %w", err)
 	}
 
-	return base64.RawURLEncoding.EncodeToString(tp), nil
+	return tp, nil
 }
 
-func secp256k1Thumbprint(keyBytes []byte, kt kms.KeyType) (string, error) {
+func secp256k1Thumbprint(keyBytes []byte, kt kms.KeyType) ([]byte, error) {
 	switch kt {
 	case kms.ECDSASecp256k1IEEEP1363:
 	case kms.ECDSASecp256k1DER:
 	default:
-		return "", fmt.Errorf("secp256k1Thumbprint: invalid key type: %s", kt)
+		return nil, fmt.Errorf("secp256k1Thumbprint: invalid key type: %s", kt)
 	}
 
 	k, err := jwksupport.PubKeyBytesToKey(keyBytes, kt)
 	if err != nil {
-		return "", fmt.Errorf("secp256k1Thumbprint: failed to build jwk: %w", err)
+		return nil, fmt.Errorf("secp256k1Thumbprint: failed to build jwk: %w", err)
 	}
 
 	var input string
@@ -96,16 +134,32 @@ func secp256k1Thumbprint(keyBytes []byte, kt kms.KeyType) (string, error) {
 	case *ecdsa.PublicKey:
 		input, err = secp256k1ThumbprintInput(key.Curve, key.X, key.Y)
 		if err != nil {
-			return "", fmt.Errorf("secp256k1Thumbprint: failed to get public key thumbprint input: %w", err)
+			return nil, fmt.Errorf("secp256k1Thumbprint: failed to get public key thumbprint input: %w", err)
 		}
 	default:
-		return "", fmt.Errorf("secp256k1Thumbprint: unknown key type '%T'", key)
+		return nil, fmt.Errorf("secp256k1Thumbprint: unknown key type '%T'", key)
 	}
 
-	h := crypto.SHA256.New()
-	_, _ = h.Write([]byte(input))
+	return sha256Sum(input), nil
+}
 
-	return base64.RawURLEncoding.EncodeToString(h.Sum(nil)), nil
+// bip340Thumbprint returns the thumbprint of the secp256k1 JWK of the point with an even Y of the x-only key keyBytes.
+func bip340Thumbprint(keyBytes []byte) ([]byte, error) {
+	if len(keyBytes) != bip340XOnlyKeySize {
+		return nil, errors.New("bip340Thumbprint: invalid x-only public key size")
+	}
+
+	pubKey, err := btcec.ParsePubKey(append([]byte{0x02}, keyBytes...))
+	if err != nil {
+		return nil, fmt.Errorf("bip340Thumbprint: %w", err)
+	}
+
+	input, err := secp256k1ThumbprintInput(btcec.S256(), pubKey.X(), pubKey.Y())
+	if err != nil {
+		return nil, fmt.Errorf("bip340Thumbprint: %w", err)
+	}
+
+	return sha256Sum(input), nil
 }
 
 func secp256k1ThumbprintInput(curve elliptic.Curve, x, y *big.Int) (string, error) {
@@ -174,7 +228,8 @@ func BuildJWK(keyBytes []byte, kt kms.KeyType) (*jwk.JWK, error) { //nolint: goc
 		kms.ECDSAP256TypeIEEEP1363, kms.ECDSAP384TypeIEEEP1363, kms.ECDSAP521TypeIEEEP1363,
 		kms.NISTP256ECDHKWType, kms.NISTP384ECDHKWType, kms.NISTP521ECDHKWType,
 		kms.ECDSASecp256k1DER, kms.ECDSASecp256k1IEEEP1363,
-		kms.ED25519Type, kms.X25519ECDHKWType, kms.BLS12381G2Type:
+		kms.ED25519Type, kms.X25519ECDHKWType, kms.X448ECDHKWType, kms.BLS12381G2Type,
+		kms.RSARS256Type, kms.RSAPS256Type, kms.RSAOAEP256Type:
 		return jwksupport.PubKeyBytesToJWK(keyBytes, kt)
 	default:
 		return nil, fmt.Errorf("buildJWK: %w: '%s'", errInvalidKeyType, kt)
@@ -192,20 +247,18 @@ func unmarshalECDHKey(keyBytes []byte) (*cryptoapi.PublicKey, error) {
 	return compositeKey, nil
 }
 
-func createX25519KID(marshalledKey []byte) (string, error) {
+func createX25519KID(marshalledKey []byte) ([]byte, error) {
 	compositeKey, err := unmarshalECDHKey(marshalledKey)
 	if err != nil {
-		return "", fmt.Errorf("createX25519KID: %w", err)
+		return nil, fmt.Errorf("createX25519KID: %w", err)
 	}
 
 	j, err := buildX25519JWK(compositeKey.X)
 	if err != nil {
-		return "", fmt.Errorf("createX25519KID: %w", err)
+		return nil, fmt.Errorf("createX25519KID: %w", err)
 	}
 
-	thumbprint := sha256Sum(j)
-
-	return base64.RawURLEncoding.EncodeToString(thumbprint), nil
+	return sha256Sum(j), nil
 }
 
 func buildX25519JWK(keyBytes []byte) (string, error) {
@@ -224,7 +277,24 @@ func buildX25519JWK(keyBytes []byte) (string, error) {
 	return j, nil
 }
 
-func createBLS12381G2KID(keyBytes []byte) (string, error) {
+func createX448KID(marshalledKey []byte) ([]byte, error) {
+	const x448ThumbprintTemplate = `{"crv":"X448","kty":"OKP","x":"%s"}`
+
+	compositeKey, err := unmarshalECDHKey(marshalledKey)
+	if err != nil {
+		return nil, fmt.Errorf("createX448KID: %w", err)
+	}
+
+	if len(compositeKey.X) != cryptoutil.Curve448KeySize {
+		return nil, errors.New("createX448KID: invalid ECDH X448 key")
+	}
+
+	j := fmt.Sprintf(x448ThumbprintTemplate, base64.RawURLEncoding.EncodeToString(compositeKey.X))
+
+	return sha256Sum(j), nil
+}
+
+func createBLS12381G2KID(keyBytes []byte) ([]byte, error) {
 	const (
 		bls12381g2ThumbprintTemplate = `{"crv":"Bls12381g2","kty":"OKP","x":"%s"}`
 		// Default BLS 12-381 public key length in G2 field.
@@ -234,7 +304,7 @@ func createBLS12381G2KID(keyBytes []byte) (string, error) {
 	lenKey := len(keyBytes)
 
 	if lenKey > bls12381G2PublicKeyLen {
-		return "", errors.New("invalid BBS+ key")
+		return nil, errors.New("invalid BBS+ key")
 	}
 
 	pad := make([]byte, bls12381G2PublicKeyLen-lenKey)
@@ -242,9 +312,7 @@ func createBLS12381G2KID(keyBytes []byte) (string, error) {
 
 	j := fmt.Sprintf(bls12381g2ThumbprintTemplate, base64.RawURLEncoding.EncodeToString(bbsRawKey))
 
-	thumbprint := sha256Sum(j)
-
-	return base64.RawURLEncoding.EncodeToString(thumbprint), nil
+	return sha256Sum(j), nil
 }
 
 func sha256Sum(j string) []byte {
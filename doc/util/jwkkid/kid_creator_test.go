@@ -99,6 +99,27 @@ func Test_CreateKID(t *testing.T) {
 		require.NoError(t, err)
 		require.NotEmpty(t, kid)
 	})
+
+	t.Run("BIP-340 secp256k1 KID", func(t *testing.T) {
+		secp256k1Key, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+		require.NoError(t, err)
+
+		xOnly := secp256k1Key.X.FillBytes(make([]byte, 32))
+
+		kid, err := CreateKID(xOnly, kms.BIP340Secp256k1Type)
+		require.NoError(t, err)
+
+		// the KID is the one of the secp256k1 key with an even Y.
+		evenY, err := btcec.ParsePubKey(append([]byte{0x02}, xOnly...))
+		require.NoError(t, err)
+
+		ecKID, err := CreateKID(elliptic.Marshal(btcec.S256(), evenY.X(), evenY.Y()), kms.ECDSASecp256k1IEEEP1363)
+		require.NoError(t, err)
+		require.Equal(t, ecKID, kid)
+
+		_, err = CreateKID(xOnly[1:], kms.BIP340Secp256k1Type)
+		require.EqualError(t, err, "createKID: bip340Thumbprint: invalid x-only public key size")
+	})
 }
 
 func TestCreateKID(t *testing.T) {
@@ -215,6 +236,23 @@ func TestCreateX25519KID_Failure(t *testing.T) {
 	require.EqualError(t, err, "createX25519KID: buildX25519JWK: invalid ECDH X25519 key")
 }
 
+func TestCreateX448KID_Failure(t *testing.T) {
+	key := &cryptoapi.PublicKey{
+		Curve: "X448",
+		X:     []byte(strings.Repeat("a", cryptoutil.Curve25519KeySize)), // X25519 sized key
+		Type:  ecdhpb.KeyType_OKP.String(),
+	}
+
+	mKey, err := json.Marshal(key)
+	require.NoError(t, err)
+
+	_, err = CreateKID(mKey, kms.X448ECDHKWType)
+	require.EqualError(t, err, "createKID: createX448KID: invalid ECDH X448 key")
+
+	_, err = CreateKID([]byte("{"), kms.X448ECDHKWType)
+	require.ErrorContains(t, err, "createX448KID")
+}
+
 func TestBuildJWKX25519(t *testing.T) {
 	x25519 := make([]byte, cryptoutil.Curve25519KeySize)
 	_, err := rand.Read(x25519)
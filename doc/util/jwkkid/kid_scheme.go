@@ -0,0 +1,64 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwkkid
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+const (
+	// MinKIDSize is the minimum size in bytes of truncated thumbprint KIDs.
+	MinKIDSize = 8
+
+	thumbprintSize = 32
+	// sha2-256 multihash code.
+	sha256MultihashCode = 0x12
+	// base58btc multibase prefix.
+	base58BTCPrefix = "z"
+)
+
+type kidOpts struct {
+	scheme kms.KIDScheme
+}
+
+// Opt is a CreateKID option.
+type Opt func(opts *kidOpts)
+
+// WithScheme sets the KID scheme of CreateKID: the thumbprint is truncated to scheme.Size bytes if set (at least
+// MinKIDSize) and encoded with scheme.Encoding, base64 raw URL encoding by default.
+func WithScheme(scheme kms.KIDScheme) Opt {
+	return func(opts *kidOpts) {
+		opts.scheme = scheme
+	}
+}
+
+func encodeKID(tp []byte, scheme kms.KIDScheme) (string, error) {
+	if scheme.Size != 0 {
+		if scheme.Size < MinKIDSize || scheme.Size > thumbprintSize {
+			return "", fmt.Errorf("invalid KID size %d, want %d to %d", scheme.Size, MinKIDSize, thumbprintSize)
+		}
+
+		tp = tp[:scheme.Size]
+	}
+
+	switch scheme.Encoding {
+	case "", kms.KIDEncodingBase64URL:
+		return base64.RawURLEncoding.EncodeToString(tp), nil
+	case kms.KIDEncodingMultibase:
+		// the multihash length of a truncated thumbprint is its truncated size.
+		mh := append([]byte{sha256MultihashCode, byte(len(tp))}, tp...)
+
+		return base58BTCPrefix + base58.Encode(mh), nil
+	default:
+		return "", fmt.Errorf("unsupported KID encoding '%s'", scheme.Encoding)
+	}
+}
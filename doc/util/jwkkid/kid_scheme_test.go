@@ -0,0 +1,72 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwkkid
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+func TestCreateKIDWithScheme(t *testing.T) {
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	defaultKID, err := CreateKID(pubKey, kms.ED25519Type)
+	require.NoError(t, err)
+
+	tp, err := base64.RawURLEncoding.DecodeString(defaultKID)
+	require.NoError(t, err)
+	require.Len(t, tp, thumbprintSize)
+
+	t.Run("base64url", func(t *testing.T) {
+		kid, err := CreateKID(pubKey, kms.ED25519Type, WithScheme(kms.KIDScheme{Encoding: kms.KIDEncodingBase64URL}))
+		require.NoError(t, err)
+		require.Equal(t, defaultKID, kid)
+	})
+
+	t.Run("truncated base64url", func(t *testing.T) {
+		kid, err := CreateKID(pubKey, kms.ED25519Type, WithScheme(kms.KIDScheme{Size: 16}))
+		require.NoError(t, err)
+		require.Equal(t, base64.RawURLEncoding.EncodeToString(tp[:16]), kid)
+	})
+
+	t.Run("multibase", func(t *testing.T) {
+		kid, err := CreateKID(pubKey, kms.ED25519Type, WithScheme(kms.KIDScheme{Encoding: kms.KIDEncodingMultibase}))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(kid, "zQm"))
+		require.Equal(t, append([]byte{sha256MultihashCode, thumbprintSize}, tp...), base58.Decode(kid[1:]))
+
+		kid, err = CreateKID(pubKey, kms.ED25519Type, WithScheme(kms.KIDScheme{
+			Encoding: kms.KIDEncodingMultibase,
+			Size:     MinKIDSize,
+		}))
+		require.NoError(t, err)
+		require.Equal(t, append([]byte{sha256MultihashCode, MinKIDSize}, tp[:MinKIDSize]...), base58.Decode(kid[1:]))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := CreateKID(pubKey, kms.ED25519Type, WithScheme(kms.KIDScheme{Size: 4}))
+		require.EqualError(t, err, "createKID: invalid KID size 4, want 8 to 32")
+
+		_, err = CreateKID(pubKey, kms.ED25519Type, WithScheme(kms.KIDScheme{Size: 33}))
+		require.EqualError(t, err, "createKID: invalid KID size 33, want 8 to 32")
+
+		_, err = CreateKID(pubKey, kms.ED25519Type, WithScheme(kms.KIDScheme{Encoding: "hex"}))
+		require.EqualError(t, err, "createKID: unsupported KID encoding 'hex'")
+
+		_, err = CreateKID(nil, kms.ED25519Type, WithScheme(kms.KIDScheme{Encoding: kms.KIDEncodingMultibase}))
+		require.EqualError(t, err, "createKID: empty key")
+	})
+}
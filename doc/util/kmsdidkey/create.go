@@ -0,0 +1,206 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kmsdidkey
+
+import (
+	"crypto/elliptic"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/doc/util/fingerprint"
+	"github.com/trustbloc/kms-go/doc/util/pubkeyfmt"
+	"github.com/trustbloc/kms-go/kms/localkms"
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+const (
+	didKeyPrefix = "did:key:"
+	didJWKPrefix = "did:jwk:"
+
+	// MultikeyType is the verification method type of did:key DIDs.
+	MultikeyType = "Multikey"
+	// JSONWebKey2020Type is the verification method type of did:jwk DIDs.
+	JSONWebKey2020Type = "JsonWebKey2020"
+)
+
+// VerificationMethod is the DID document verification method of a did:key or a did:jwk DID.
+type VerificationMethod struct {
+	ID                 string   `json:"id"`
+	Type               string   `json:"type"`
+	Controller         string   `json:"controller"`
+	PublicKeyMultibase string   `json:"publicKeyMultibase,omitempty"`
+	PublicKeyJwk       *jwk.JWK `json:"publicKeyJwk,omitempty"`
+}
+
+// CreateAndExportDIDKey creates a new key of type keyType in km and returns its key ID, its did:key DID and the
+// verification method of the DID document.
+func CreateAndExportDIDKey(km kms.KeyManager, keyType kms.KeyType,
+	opts ...kms.KeyOpts) (string, string, *VerificationMethod, error) {
+	kid, pubKeyBytes, err := km.CreateAndExportPubKeyBytes(keyType, opts...)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("createAndExportDIDKey: %w", err)
+	}
+
+	mb, err := pubkeyfmt.Encode(kid, pubKeyBytes, keyType, kms.PubKeyFormatMultibase)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("createAndExportDIDKey: %w", err)
+	}
+
+	did := didKeyPrefix + string(mb)
+
+	return kid, did, &VerificationMethod{
+		ID:                 did + "#" + string(mb),
+		Type:               MultikeyType,
+		Controller:         did,
+		PublicKeyMultibase: string(mb),
+	}, nil
+}
+
+// CreateAndExportDIDJWK creates a new key of type keyType in km and returns its key ID, its did:jwk DID and the
+// verification method of the DID document.
+func CreateAndExportDIDJWK(km kms.KeyManager, keyType kms.KeyType,
+	opts ...kms.KeyOpts) (string, string, *VerificationMethod, error) {
+	kid, pubKeyBytes, err := km.CreateAndExportPubKeyBytes(keyType, opts...)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("createAndExportDIDJWK: %w", err)
+	}
+
+	pubJWK, err := pubkeyfmt.ToJWK(pubKeyBytes, keyType)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("createAndExportDIDJWK: %w", err)
+	}
+
+	jwkBytes, err := pubJWK.MarshalJSON()
+	if err != nil {
+		return "", "", nil, fmt.Errorf("createAndExportDIDJWK: %w", err)
+	}
+
+	did := didJWKPrefix + base64.RawURLEncoding.EncodeToString(jwkBytes)
+
+	return kid, did, &VerificationMethod{
+		ID:           did + "#0",
+		Type:         JSONWebKey2020Type,
+		Controller:   did,
+		PublicKeyJwk: pubJWK,
+	}, nil
+}
+
+// VerificationKeyFromDIDKey resolves the did:key DID (or DID URL) didKey to a public key handle usable by
+// Crypto.Verify and returns it with its key type. NIST P and secp256k1 keys are resolved as IEEE P1363 keys.
+func VerificationKeyFromDIDKey(didKey string) (interface{}, kms.KeyType, error) {
+	pubKey, code, err := extractRawKey(strings.SplitN(didKey, "#", 2)[0])
+	if err != nil {
+		return nil, "", fmt.Errorf("verificationKeyFromDIDKey: %w", err)
+	}
+
+	var kt kms.KeyType
+
+	switch code {
+	case fingerprint.ED25519PubKeyMultiCodec:
+		kt = kms.ED25519Type
+	case fingerprint.BLS12381g2PubKeyMultiCodec:
+		kt = kms.BLS12381G2Type
+	case fingerprint.P256PubKeyMultiCodec:
+		kt = kms.ECDSAP256TypeIEEEP1363
+	case fingerprint.P384PubKeyMultiCodec:
+		kt = kms.ECDSAP384TypeIEEEP1363
+	case fingerprint.P521PubKeyMultiCodec:
+		kt = kms.ECDSAP521TypeIEEEP1363
+	case fingerprint.Secp256k1PubKeyMultiCodec:
+		kt = kms.ECDSASecp256k1TypeIEEEP1363
+	default:
+		return nil, "", fmt.Errorf("verificationKeyFromDIDKey: unsupported verification key multicodec code [0x%x]",
+			code)
+	}
+
+	kh, err := verificationHandle(pubKey, kt)
+	if err != nil {
+		return nil, "", fmt.Errorf("verificationKeyFromDIDKey: %w", err)
+	}
+
+	return kh, kt, nil
+}
+
+// VerificationKeyFromDIDJWK resolves the did:jwk DID (or DID URL) didJWK to a public key handle usable by
+// Crypto.Verify and returns it with its key type.
+func VerificationKeyFromDIDJWK(didJWK string) (interface{}, kms.KeyType, error) {
+	did := strings.SplitN(didJWK, "#", 2)[0]
+	if !strings.HasPrefix(did, didJWKPrefix) {
+		return nil, "", fmt.Errorf("verificationKeyFromDIDJWK: not a did:jwk DID: %s", didJWK)
+	}
+
+	jwkBytes, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(did, didJWKPrefix))
+	if err != nil {
+		return nil, "", fmt.Errorf("verificationKeyFromDIDJWK: %w", err)
+	}
+
+	pubJWK := &jwk.JWK{}
+
+	if err = pubJWK.UnmarshalJSON(jwkBytes); err != nil {
+		return nil, "", fmt.Errorf("verificationKeyFromDIDJWK: %w", err)
+	}
+
+	kt, err := pubJWK.KeyType()
+	if err != nil {
+		return nil, "", fmt.Errorf("verificationKeyFromDIDJWK: %w", err)
+	}
+
+	pubKey, err := pubJWK.PublicKeyBytes()
+	if err != nil {
+		return nil, "", fmt.Errorf("verificationKeyFromDIDJWK: %w", err)
+	}
+
+	kh, err := verificationHandle(pubKey, kt)
+	if err != nil {
+		return nil, "", fmt.Errorf("verificationKeyFromDIDJWK: %w", err)
+	}
+
+	return kh, kt, nil
+}
+
+// verificationHandle creates the public key handle of pubKey, EC keys can be compressed or uncompressed points.
+func verificationHandle(pubKey []byte, kt kms.KeyType) (interface{}, error) {
+	var curve elliptic.Curve
+
+	switch kt {
+	case kms.X25519ECDHKWType, kms.X448ECDHKWType:
+		return nil, fmt.Errorf("'%s' keys are not verification keys", kt)
+	case kms.ECDSAP256TypeIEEEP1363:
+		curve = elliptic.P256()
+	case kms.ECDSAP384TypeIEEEP1363:
+		curve = elliptic.P384()
+	case kms.ECDSAP521TypeIEEEP1363:
+		curve = elliptic.P521()
+	case kms.ECDSASecp256k1TypeIEEEP1363:
+		key, err := btcec.ParsePubKey(pubKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secp256k1 public key: %w", err)
+		}
+
+		pubKey = key.SerializeUncompressed()
+	}
+
+	if curve != nil && len(pubKey) > 0 && pubKey[0] != 4 {
+		x, y := elliptic.UnmarshalCompressed(curve, pubKey)
+		if x == nil {
+			return nil, fmt.Errorf("invalid compressed '%s' public key", kt)
+		}
+
+		pubKey = elliptic.Marshal(curve, x, y) //nolint:staticcheck // uncompressed points are read by the kms
+	}
+
+	kh, err := localkms.PublicKeyBytesToHandle(pubKey, kt)
+	if err != nil {
+		return nil, err
+	}
+
+	return kh, nil
+}
@@ -9,10 +9,12 @@ package kmsdidkey
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
 	mockstorage "github.com/trustbloc/kms-go/internal/mock/storage"
 
 	"github.com/trustbloc/kms-go/kms"
@@ -353,3 +355,99 @@ func TestGetBase58PubKeyFromDIDKey(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateAndExportDIDKey(t *testing.T) {
+	k := newKMS(t, mockstorage.NewMockStoreProvider())
+
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	msg := []byte("message")
+
+	for _, kt := range []kmsapi.KeyType{
+		kmsapi.ED25519Type,
+		kmsapi.ECDSAP256TypeIEEEP1363,
+		kmsapi.ECDSAP384TypeIEEEP1363,
+		kmsapi.ECDSAP521TypeIEEEP1363,
+	} {
+		t.Run(string(kt), func(t *testing.T) {
+			kid, did, vm, err := CreateAndExportDIDKey(k, kt)
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(did, "did:key:z"))
+			require.Equal(t, did+"#"+vm.PublicKeyMultibase, vm.ID)
+			require.Equal(t, did, vm.Controller)
+			require.Equal(t, MultikeyType, vm.Type)
+
+			kid2, didJWK, jwkVM, err := CreateAndExportDIDJWK(k, kt)
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(didJWK, "did:jwk:"))
+			require.Equal(t, didJWK+"#0", jwkVM.ID)
+			require.Equal(t, JSONWebKey2020Type, jwkVM.Type)
+			require.NotNil(t, jwkVM.PublicKeyJwk)
+
+			for _, tc := range []struct {
+				kid     string
+				resolve func() (interface{}, kmsapi.KeyType, error)
+			}{
+				{kid, func() (interface{}, kmsapi.KeyType, error) { return VerificationKeyFromDIDKey(vm.ID) }},
+				{kid2, func() (interface{}, kmsapi.KeyType, error) { return VerificationKeyFromDIDJWK(jwkVM.ID) }},
+			} {
+				kh, err := k.Get(tc.kid)
+				require.NoError(t, err)
+
+				sig, err := c.Sign(msg, kh)
+				require.NoError(t, err)
+
+				pubKH, resolvedKT, err := tc.resolve()
+				require.NoError(t, err)
+				require.Equal(t, kt, resolvedKT)
+				require.NoError(t, c.Verify(sig, msg, pubKH))
+			}
+		})
+	}
+
+	t.Run("secp256k1", func(t *testing.T) {
+		_, did, _, err := CreateAndExportDIDKey(k, kmsapi.ECDSASecp256k1TypeIEEEP1363)
+		require.NoError(t, err)
+
+		_, kt, err := VerificationKeyFromDIDKey(did)
+		require.NoError(t, err)
+		require.Equal(t, kmsapi.ECDSASecp256k1TypeIEEEP1363, kt)
+
+		_, didJWK, _, err := CreateAndExportDIDJWK(k, kmsapi.ECDSASecp256k1TypeIEEEP1363)
+		require.NoError(t, err)
+
+		_, kt, err = VerificationKeyFromDIDJWK(didJWK)
+		require.NoError(t, err)
+		require.Equal(t, kmsapi.ECDSASecp256k1TypeIEEEP1363, kt)
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		_, _, _, err := CreateAndExportDIDKey(k, "unknown")
+		require.ErrorContains(t, err, "createAndExportDIDKey: ")
+
+		_, _, _, err = CreateAndExportDIDJWK(k, "unknown")
+		require.ErrorContains(t, err, "createAndExportDIDJWK: ")
+
+		_, did, _, err := CreateAndExportDIDKey(k, kmsapi.X25519ECDHKWType)
+		require.NoError(t, err)
+
+		_, _, err = VerificationKeyFromDIDKey(did)
+		require.EqualError(t, err, "verificationKeyFromDIDKey: unsupported verification key multicodec code [0xec]")
+
+		_, didJWK, _, err := CreateAndExportDIDJWK(k, kmsapi.X25519ECDHKWType)
+		require.NoError(t, err)
+
+		_, _, err = VerificationKeyFromDIDJWK(didJWK)
+		require.EqualError(t, err, "verificationKeyFromDIDJWK: 'X25519ECDHKW' keys are not verification keys")
+
+		_, _, err = VerificationKeyFromDIDJWK(did)
+		require.ErrorContains(t, err, "not a did:jwk DID")
+
+		_, _, err = VerificationKeyFromDIDJWK("did:jwk:!")
+		require.Error(t, err)
+
+		_, _, err = VerificationKeyFromDIDKey("did:key:invalid")
+		require.Error(t, err)
+	})
+}
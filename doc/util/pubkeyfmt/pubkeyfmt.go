@@ -0,0 +1,199 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package pubkeyfmt encodes the marshalled public keys returned by KeyManager.ExportPubKeyBytes, whose format depends
+// on the key type, in the well known kms.PubKeyFormat formats (JWK, SPKI DER or PEM, raw and multibase). It is used by
+// the kms.PubKeyExporter implementations.
+package pubkeyfmt
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+	"github.com/trustbloc/kms-go/doc/util/fingerprint"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+// Encode encodes pubKeyBytes, a public key of type kt as returned by KeyManager.ExportPubKeyBytes, in format.
+// keyID, if set, is the kid of JWK keys.
+func Encode(keyID string, pubKeyBytes []byte, kt kms.KeyType, format kms.PubKeyFormat) ([]byte, error) {
+	if format == kms.PubKeyFormatJWK {
+		j, err := ToJWK(pubKeyBytes, kt)
+		if err != nil {
+			return nil, err
+		}
+
+		j.KeyID = keyID
+
+		return j.MarshalJSON()
+	}
+
+	key, err := parse(pubKeyBytes, kt)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case kms.PubKeyFormatSPKIDER:
+		return spki(key)
+	case kms.PubKeyFormatSPKIPEM:
+		der, e := spki(key)
+		if e != nil {
+			return nil, e
+		}
+
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+	case kms.PubKeyFormatRaw:
+		_, raw, e := codecAndRaw(key, false)
+
+		return raw, e
+	case kms.PubKeyFormatMultibase:
+		code, raw, e := codecAndRaw(key, true)
+		if e != nil {
+			return nil, e
+		}
+
+		return []byte(fingerprint.KeyFingerprint(code, raw)), nil
+	default:
+		return nil, fmt.Errorf("pubkeyfmt: unsupported public key format '%s'", format)
+	}
+}
+
+// ToJWK returns pubKeyBytes, a public key of type kt as returned by KeyManager.ExportPubKeyBytes, as a JWK.
+func ToJWK(pubKeyBytes []byte, kt kms.KeyType) (*jwk.JWK, error) {
+	if kt == kms.X25519ECDHKWType {
+		raw, err := x25519Bytes(pubKeyBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		pubKeyBytes = raw
+	}
+
+	j, err := jwksupport.PubKeyBytesToJWK(pubKeyBytes, kt)
+	if err != nil {
+		return nil, fmt.Errorf("pubkeyfmt: %w", err)
+	}
+
+	return j, nil
+}
+
+func parse(pubKeyBytes []byte, kt kms.KeyType) (interface{}, error) {
+	if kt == kms.X25519ECDHKWType {
+		raw, err := x25519Bytes(pubKeyBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := ecdh.X25519().NewPublicKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("pubkeyfmt: invalid X25519 public key: %w", err)
+		}
+
+		return key, nil
+	}
+
+	key, err := jwksupport.PubKeyBytesToKey(pubKeyBytes, kt)
+	if err != nil {
+		return nil, fmt.Errorf("pubkeyfmt: %w", err)
+	}
+
+	return key, nil
+}
+
+// x25519Bytes returns the raw X25519 public key of pubKeyBytes, exported either as raw bytes or as a marshalled
+// cryptoapi.PublicKey.
+func x25519Bytes(pubKeyBytes []byte) ([]byte, error) {
+	if len(pubKeyBytes) == 0 || pubKeyBytes[0] != '{' {
+		return pubKeyBytes, nil
+	}
+
+	pubKey := &cryptoapi.PublicKey{}
+
+	if err := json.Unmarshal(pubKeyBytes, pubKey); err != nil {
+		return nil, fmt.Errorf("pubkeyfmt: invalid X25519 public key: %w", err)
+	}
+
+	return pubKey.X, nil
+}
+
+func spki(key interface{}) ([]byte, error) {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if k.Curve == btcec.S256() {
+			return nil, fmt.Errorf("pubkeyfmt: secp256k1 keys have no SPKI encoding")
+		}
+	case *bbs12381g2pub.PublicKey:
+		return nil, fmt.Errorf("pubkeyfmt: BLS12-381 G2 keys have no SPKI encoding")
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("pubkeyfmt: %w", err)
+	}
+
+	return der, nil
+}
+
+// codecAndRaw returns the multicodec code and the raw bytes of key, EC points are compressed if compressed is set.
+func codecAndRaw(key interface{}, compressed bool) (uint64, []byte, error) {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		code, err := ecCodec(k.Curve)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if compressed {
+			return code, elliptic.MarshalCompressed(k.Curve, k.X, k.Y), nil
+		}
+
+		return code, elliptic.Marshal(k.Curve, k.X, k.Y), nil //nolint:staticcheck // also used for secp256k1 keys
+	case ed25519.PublicKey:
+		return fingerprint.ED25519PubKeyMultiCodec, k, nil
+	case *ecdh.PublicKey:
+		return fingerprint.X25519PubKeyMultiCodec, k.Bytes(), nil
+	case *bbs12381g2pub.PublicKey:
+		raw, err := k.Marshal()
+		if err != nil {
+			return 0, nil, fmt.Errorf("pubkeyfmt: %w", err)
+		}
+
+		return fingerprint.BLS12381g2PubKeyMultiCodec, raw, nil
+	case *rsa.PublicKey:
+		return fingerprint.RSAPubKeyMultiCodec, x509.MarshalPKCS1PublicKey(k), nil
+	default:
+		return 0, nil, fmt.Errorf("pubkeyfmt: unsupported public key type %T", key)
+	}
+}
+
+func ecCodec(curve elliptic.Curve) (uint64, error) {
+	switch curve {
+	case elliptic.P256():
+		return fingerprint.P256PubKeyMultiCodec, nil
+	case elliptic.P384():
+		return fingerprint.P384PubKeyMultiCodec, nil
+	case elliptic.P521():
+		return fingerprint.P521PubKeyMultiCodec, nil
+	case btcec.S256():
+		return fingerprint.Secp256k1PubKeyMultiCodec, nil
+	default:
+		return 0, fmt.Errorf("pubkeyfmt: unsupported EC curve")
+	}
+}
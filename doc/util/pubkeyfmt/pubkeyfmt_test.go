@@ -0,0 +1,119 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pubkeyfmt_test
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/doc/util/fingerprint"
+	"github.com/trustbloc/kms-go/doc/util/pubkeyfmt"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func TestExportPubKey(t *testing.T) {
+	k := mockkms.NewForTest(t)
+
+	var exporter kmsapi.PubKeyExporter = k
+
+	tests := []struct {
+		kt     kmsapi.KeyType
+		rawLen int
+		code   uint64
+		spki   bool
+	}{
+		{kmsapi.ED25519Type, 32, fingerprint.ED25519PubKeyMultiCodec, true},
+		{kmsapi.ECDSAP256TypeIEEEP1363, 65, fingerprint.P256PubKeyMultiCodec, true},
+		{kmsapi.ECDSAP384TypeDER, 97, fingerprint.P384PubKeyMultiCodec, true},
+		{kmsapi.ECDSASecp256k1TypeIEEEP1363, 65, fingerprint.Secp256k1PubKeyMultiCodec, false},
+		{kmsapi.X25519ECDHKWType, 32, fingerprint.X25519PubKeyMultiCodec, true},
+		{kmsapi.NISTP521ECDHKWType, 133, fingerprint.P521PubKeyMultiCodec, true},
+		{kmsapi.BLS12381G2Type, 96, fingerprint.BLS12381g2PubKeyMultiCodec, false},
+		{kmsapi.RSAPS256Type, 0, fingerprint.RSAPubKeyMultiCodec, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.kt), func(t *testing.T) {
+			kid, _, err := k.Create(tc.kt)
+			require.NoError(t, err)
+
+			jwkBytes, err := exporter.ExportPubKey(kid, kmsapi.PubKeyFormatJWK)
+			require.NoError(t, err)
+
+			pub := &jwk.JWK{}
+			require.NoError(t, pub.UnmarshalJSON(jwkBytes))
+			require.Equal(t, kid, pub.KeyID)
+
+			raw, err := exporter.ExportPubKey(kid, kmsapi.PubKeyFormatRaw)
+			require.NoError(t, err)
+
+			if tc.rawLen > 0 {
+				require.Len(t, raw, tc.rawLen)
+			}
+
+			mb, err := exporter.ExportPubKey(kid, kmsapi.PubKeyFormatMultibase)
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(string(mb), "z"))
+
+			_, code, err := fingerprint.PubKeyFromFingerprint(string(mb))
+			require.NoError(t, err)
+			require.Equal(t, tc.code, code)
+
+			der, err := exporter.ExportPubKey(kid, kmsapi.PubKeyFormatSPKIDER)
+			if !tc.spki {
+				require.ErrorContains(t, err, "have no SPKI encoding")
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			_, err = x509.ParsePKIXPublicKey(der)
+			require.NoError(t, err)
+
+			pemBytes, err := exporter.ExportPubKey(kid, kmsapi.PubKeyFormatSPKIPEM)
+			require.NoError(t, err)
+
+			block, _ := pem.Decode(pemBytes)
+			require.NotNil(t, block)
+			require.Equal(t, "PUBLIC KEY", block.Type)
+			require.Equal(t, der, block.Bytes)
+		})
+	}
+
+	t.Run("Ed25519 multibase matches the did:key fingerprint", func(t *testing.T) {
+		kid, pubKeyBytes, err := k.CreateAndExportPubKeyBytes(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		mb, err := exporter.ExportPubKey(kid, kmsapi.PubKeyFormatMultibase)
+		require.NoError(t, err)
+		require.Equal(t, fingerprint.KeyFingerprint(fingerprint.ED25519PubKeyMultiCodec, pubKeyBytes), string(mb))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		kid, _, err := k.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		_, err = exporter.ExportPubKey(kid, "ssh")
+		require.EqualError(t, err, "exportPubKey: pubkeyfmt: unsupported public key format 'ssh'")
+
+		_, err = exporter.ExportPubKey("unknown", kmsapi.PubKeyFormatJWK)
+		require.Error(t, err)
+
+		_, err = pubkeyfmt.Encode("", []byte("key"), "unknown", kmsapi.PubKeyFormatRaw)
+		require.ErrorContains(t, err, "pubkeyfmt: invalid key type")
+
+		_, err = pubkeyfmt.Encode("", []byte("{"), kmsapi.X25519ECDHKWType, kmsapi.PubKeyFormatRaw)
+		require.ErrorContains(t, err, "invalid X25519 public key")
+	})
+}
@@ -0,0 +1,44 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package logutil provides the default slog logger of the KMS and Crypto implementations: without a logger option,
+// their debug and warn events are discarded.
+package logutil
+
+import (
+	"context"
+	"log/slog"
+)
+
+//nolint:gochecknoglobals
+var discard = slog.New(discardHandler{})
+
+// Logger returns l, or a logger discarding all the records if l is nil.
+func Logger(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		return discard
+	}
+
+	return l
+}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool {
+	return false
+}
+
+func (discardHandler) Handle(context.Context, slog.Record) error {
+	return nil
+}
+
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler {
+	return h
+}
+
+func (h discardHandler) WithGroup(string) slog.Handler {
+	return h
+}
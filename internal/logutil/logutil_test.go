@@ -0,0 +1,32 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logutil_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/internal/logutil"
+)
+
+func TestLogger(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+
+	require.Same(t, l, logutil.Logger(l))
+
+	discard := logutil.Logger(nil)
+	require.NotNil(t, discard)
+	require.False(t, discard.Enabled(context.Background(), slog.LevelError))
+
+	discard = discard.With("key", "value").WithGroup("group")
+	require.False(t, discard.Enabled(context.Background(), slog.LevelError))
+	require.NoError(t, discard.Handler().Handle(context.Background(), slog.Record{}))
+}
@@ -0,0 +1,34 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package memguard provides helpers to compare key material in constant time and to wipe it from memory once it is
+// no longer needed.
+//
+// Go doesn't guarantee that a secret isn't copied by the runtime (stack growth, garbage collection) or the functions it
+// was passed to, Wipe only reduces the time the buffers owned by the caller hold the secret.
+package memguard
+
+import (
+	"crypto/subtle"
+	"runtime"
+)
+
+// Wipe overwrites the content of the buffers with zeros.
+func Wipe(buffers ...[]byte) {
+	for _, b := range buffers {
+		for i := range b {
+			b[i] = 0
+		}
+
+		// keep b reachable until it's overwritten so the writes are not optimized away.
+		runtime.KeepAlive(b)
+	}
+}
+
+// Equal reports whether a and b hold the same bytes, in a time that depends on their lengths only.
+func Equal(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
@@ -0,0 +1,36 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package memguard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWipe(t *testing.T) {
+	a := []byte("secret key")
+	b := []byte{1, 2, 3}
+
+	Wipe(a, nil, b[:2])
+
+	require.Equal(t, make([]byte, 10), a)
+	require.Equal(t, []byte{0, 0, 3}, b)
+
+	key := &[32]byte{1, 2, 3}
+
+	Wipe(key[:])
+
+	require.Equal(t, [32]byte{}, *key)
+}
+
+func TestEqual(t *testing.T) {
+	require.True(t, Equal([]byte("key"), []byte("key")))
+	require.True(t, Equal(nil, []byte{}))
+	require.False(t, Equal([]byte("key"), []byte("kez")))
+	require.False(t, Equal([]byte("key"), []byte("key1")))
+}
@@ -8,22 +8,23 @@ package kms
 
 import (
 	"errors"
-	"io"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
 )
 
 // ErrKeyNotFound is an error type that a KMS expects from the Store.Get method if no key stored under the given
-// key ID could be found.
-var ErrKeyNotFound = errors.New("key not found")
+// key ID could be found. It is the ErrKeyNotFound error of the spi/kms package.
+var ErrKeyNotFound = kmsapi.ErrKeyNotFound
+
+// ErrKeyExists is an error type that a KMS expects from the StoreCreator.Create method if a key is already stored
+// under the given key ID.
+var ErrKeyExists = errors.New("key already exists")
+
+// VersionConflictError is the error type that a KMS expects from the StoreVersioner.PutWithVersion method if the
+// version of the stored key isn't the expected one. It is the VersionConflictError of the spi/kms package.
+type VersionConflictError = kmsapi.VersionConflictError
 
 // CryptoBox is a libsodium crypto service used by legacy authcrypt packer.
 // TODO remove this service when legacy packer is retired from the framework.
-type CryptoBox interface {
-	// Easy seals a payload with a provided nonce
-	Easy(payload, nonce, theirPub []byte, myKID string) ([]byte, error)
-	// EashOpen unseals a cipherText sealed with Easy, where the nonce is provided
-	EasyOpen(cipherText, nonce, theirPub, myPub []byte) ([]byte, error)
-	// Seal seals a payload using the equivalent logic of libsodium box_seal
-	Seal(payload, theirEncPub []byte, randSource io.Reader) ([]byte, error)
-	// SealOpen decrypts a payload encrypted with Seal
-	SealOpen(cipherText, myPub []byte) ([]byte, error)
-}
+type CryptoBox = cryptoapi.CryptoBox
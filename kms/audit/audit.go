@@ -0,0 +1,168 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package audit provides the default kms.AuditLogger, writing the operations as hash chained JSON lines: each record
+// carries the SHA-256 hash of the previous line, so removed, reordered or modified records are detected by Verify. The
+// hash of the last line (returned by Verify) can be anchored elsewhere to protect the end of the trail as well.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+// Record is a JSON line written by JSONLogger.
+type Record struct {
+	Time    time.Time          `json:"time"`
+	Op      kmsapi.Operation   `json:"op"`
+	KeyID   string             `json:"keyID,omitempty"`
+	Success bool               `json:"success"`
+	Latency time.Duration      `json:"latencyNs"`
+	Caller  *kmsapi.CallerInfo `json:"caller,omitempty"`
+	// Prev is the hex encoded SHA-256 hash of the previous line, empty for the first record.
+	Prev string `json:"prev,omitempty"`
+}
+
+// JSONLogger is a kms.AuditLogger writing a hash chained JSON Record line per operation.
+type JSONLogger struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+	prev   string
+	err    error
+}
+
+// NewJSONLogger creates a JSONLogger writing to w, starting a new hash chain.
+func NewJSONLogger(w io.Writer) *JSONLogger {
+	return &JSONLogger{w: w}
+}
+
+// NewFileLogger creates a JSONLogger appending to the file at path, created if needed. The hash chain of the records
+// already in the file is verified and continued.
+func NewFileLogger(path string) (*JSONLogger, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600) //nolint:gosec // audit file path is trusted
+	if err != nil {
+		return nil, fmt.Errorf("audit: open file: %w", err)
+	}
+
+	prev, err := Verify(f)
+	if err != nil {
+		_ = f.Close() //nolint:errcheck
+
+		return nil, err
+	}
+
+	return &JSONLogger{w: f, closer: f, prev: prev}, nil
+}
+
+// OnOperation writes the Record of the operation.
+func (l *JSONLogger) OnOperation(op kmsapi.Operation, keyID string, success bool, latency time.Duration,
+	caller *kmsapi.CallerInfo) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	line, err := json.Marshal(&Record{
+		Time:    time.Now().UTC(),
+		Op:      op,
+		KeyID:   keyID,
+		Success: success,
+		Latency: latency,
+		Caller:  caller,
+		Prev:    l.prev,
+	})
+	if err == nil {
+		_, err = l.w.Write(append(line, '\n'))
+	}
+
+	if err != nil {
+		l.err = fmt.Errorf("audit: write record: %w", err)
+
+		return
+	}
+
+	l.prev = lineHash(line)
+}
+
+// Err returns the last error writing a record, if any.
+func (l *JSONLogger) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.err
+}
+
+// Close closes the file of loggers created with NewFileLogger.
+func (l *JSONLogger) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+
+	return l.closer.Close()
+}
+
+// Verify checks the hash chain of the JSON lines read from r and returns the hash of the last line.
+func Verify(r io.Reader) (string, error) {
+	var (
+		prev string
+		n    int
+	)
+
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		n++
+
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		rec := &Record{}
+
+		if err := json.Unmarshal(line, rec); err != nil {
+			return "", fmt.Errorf("audit: invalid record at line %d: %w", n, err)
+		}
+
+		if rec.Prev != prev {
+			return "", fmt.Errorf("audit: hash chain broken at line %d", n)
+		}
+
+		prev = lineHash(line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("audit: read records: %w", err)
+	}
+
+	return prev, nil
+}
+
+func lineHash(line []byte) string {
+	h := sha256.Sum256(line)
+
+	return hex.EncodeToString(h[:])
+}
+
+// Log records the operation op started at start and ending with err on l, it is a no-op if l is nil. It is called by
+// the KMS and Crypto implementations supporting audit loggers.
+func Log(l kmsapi.AuditLogger, op kmsapi.Operation, keyID string, caller *kmsapi.CallerInfo, start time.Time,
+	err error) {
+	if l == nil {
+		return
+	}
+
+	l.OnOperation(op, keyID, err == nil, time.Since(start), caller)
+}
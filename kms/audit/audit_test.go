@@ -0,0 +1,111 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+func TestJSONLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := NewJSONLogger(buf)
+	caller := &kmsapi.CallerInfo{Subject: "alice"}
+
+	l.OnOperation(kmsapi.OperationCreate, "kid1", true, time.Millisecond, caller)
+	Log(l, kmsapi.OperationSign, "kid1", nil, time.Now(), errors.New("sign failed"))
+	Log(nil, kmsapi.OperationSign, "kid1", nil, time.Now(), nil)
+	require.NoError(t, l.Err())
+	require.NoError(t, l.Close())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	first := &Record{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), first))
+	require.Equal(t, kmsapi.OperationCreate, first.Op)
+	require.Equal(t, "kid1", first.KeyID)
+	require.True(t, first.Success)
+	require.Equal(t, time.Millisecond, first.Latency)
+	require.Equal(t, caller, first.Caller)
+	require.Empty(t, first.Prev)
+
+	second := &Record{}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), second))
+	require.Equal(t, kmsapi.OperationSign, second.Op)
+	require.False(t, second.Success)
+	require.Equal(t, lineHash([]byte(lines[0])), second.Prev)
+
+	last, err := Verify(strings.NewReader(buf.String()))
+	require.NoError(t, err)
+	require.Equal(t, lineHash([]byte(lines[1])), last)
+
+	t.Run("tampered records are detected", func(t *testing.T) {
+		_, err = Verify(strings.NewReader(strings.Replace(buf.String(), `"success":true`, `"success":false`, 1)))
+		require.EqualError(t, err, "audit: hash chain broken at line 2")
+
+		_, err = Verify(strings.NewReader(lines[1] + "\n"))
+		require.EqualError(t, err, "audit: hash chain broken at line 1")
+
+		_, err = Verify(strings.NewReader("{\n"))
+		require.ErrorContains(t, err, "audit: invalid record at line 1")
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		f, err := os.CreateTemp(t.TempDir(), "audit")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		el := NewJSONLogger(f)
+		el.OnOperation(kmsapi.OperationCreate, "kid1", true, 0, nil)
+		require.ErrorContains(t, el.Err(), "audit: write record")
+	})
+}
+
+func TestNewFileLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	l, err := NewFileLogger(path)
+	require.NoError(t, err)
+
+	l.OnOperation(kmsapi.OperationCreate, "kid1", true, 0, nil)
+	require.NoError(t, l.Close())
+
+	l, err = NewFileLogger(path)
+	require.NoError(t, err)
+
+	l.OnOperation(kmsapi.OperationSign, "kid1", true, 0, nil)
+	require.NoError(t, l.Err())
+	require.NoError(t, l.Close())
+
+	f, err := os.Open(path) //nolint:gosec
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, f.Close()) }()
+
+	last, err := Verify(f)
+	require.NoError(t, err)
+	require.NotEmpty(t, last)
+
+	require.NoError(t, os.WriteFile(path, []byte("{\"prev\":\"bad\"}\n"), 0o600))
+
+	_, err = NewFileLogger(path)
+	require.EqualError(t, err, "audit: hash chain broken at line 1")
+
+	_, err = NewFileLogger(filepath.Join(path, "dir", "audit.log"))
+	require.ErrorContains(t, err, "audit: open file")
+}
@@ -0,0 +1,437 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpckms
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+
+	"github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	pb "github.com/trustbloc/kms-go/kms/grpckms/proto/grpckms_go_proto"
+)
+
+// Client is a remote KMS client using gRPC as transport. It is the gRPC alternative of webkms.RemoteKMS and
+// webkms.RemoteCrypto: keys are referenced by the key ID (a string) returned by Create.
+type Client struct {
+	client pb.KMSClient
+	opts   *opts
+}
+
+// NewClient creates a new remote KMS client using the conn gRPC connection.
+func NewClient(conn grpc.ClientConnInterface, opts ...Opt) *Client {
+	return &Client{
+		client: pb.NewKMSClient(conn),
+		opts:   newOpts(opts...),
+	}
+}
+
+func (c *Client) context() (context.Context, context.CancelFunc) {
+	if c.opts.timeout > 0 {
+		return context.WithTimeout(context.Background(), c.opts.timeout)
+	}
+
+	return context.WithCancel(context.Background())
+}
+
+// Create a new remote key of type kt.
+// Returns:
+//   - keyID of the new key
+//   - marshalled public key bytes (empty for symmetric keys)
+//   - error if failure
+func (c *Client) Create(kt kmsapi.KeyType) (string, []byte, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	resp, err := c.client.Create(ctx, &pb.CreateRequest{KeyType: string(kt)}, c.opts.callOptions...)
+	if err != nil {
+		return "", nil, fmt.Errorf("grpc Create failed: %w", err)
+	}
+
+	return resp.GetKeyId(), resp.GetPublicKey(), nil
+}
+
+// Sign will remotely sign msg using the private key referenced by keyID.
+func (c *Client) Sign(msg []byte, keyID interface{}) ([]byte, error) {
+	kid, err := keyIDString(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	resp, err := c.client.Sign(ctx, &pb.SignRequest{KeyId: kid, Message: msg}, c.opts.callOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("grpc Sign failed: %w", err)
+	}
+
+	return resp.GetSignature(), nil
+}
+
+// SignStream will remotely sign the content of r, sent in chunks, using the private key referenced by keyID.
+func (c *Client) SignStream(r io.Reader, keyID interface{}) ([]byte, error) {
+	kid, err := keyIDString(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	stream, err := c.client.SignStream(ctx, c.opts.callOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("grpc SignStream failed: %w", err)
+	}
+
+	err = readChunks(r, c.opts.chunkSize, func(chunk []byte, first bool) error {
+		req := &pb.SignRequest{Message: chunk}
+		if first {
+			req.KeyId = kid
+		}
+
+		return stream.Send(req)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("grpc SignStream send failed: %w", err)
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, fmt.Errorf("grpc SignStream failed: %w", err)
+	}
+
+	return resp.GetSignature(), nil
+}
+
+// Verify will remotely verify a signature for msg using the public key of keyID.
+func (c *Client) Verify(signature, msg []byte, keyID interface{}) error {
+	kid, err := keyIDString(keyID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	_, err = c.client.Verify(ctx, &pb.VerifyRequest{KeyId: kid, Signature: signature, Message: msg},
+		c.opts.callOptions...)
+	if err != nil {
+		return fmt.Errorf("grpc Verify failed: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyStream will remotely verify a signature over the content of r, sent in chunks, using the public key of keyID.
+func (c *Client) VerifyStream(signature []byte, r io.Reader, keyID interface{}) error {
+	kid, err := keyIDString(keyID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	stream, err := c.client.VerifyStream(ctx, c.opts.callOptions...)
+	if err != nil {
+		return fmt.Errorf("grpc VerifyStream failed: %w", err)
+	}
+
+	err = readChunks(r, c.opts.chunkSize, func(chunk []byte, first bool) error {
+		req := &pb.VerifyRequest{Message: chunk}
+		if first {
+			req.KeyId, req.Signature = kid, signature
+		}
+
+		return stream.Send(req)
+	})
+	if err != nil {
+		return fmt.Errorf("grpc VerifyStream send failed: %w", err)
+	}
+
+	_, err = stream.CloseAndRecv()
+	if err != nil {
+		return fmt.Errorf("grpc VerifyStream failed: %w", err)
+	}
+
+	return nil
+}
+
+// WrapKey will remotely wrap cek for recPubKey. 'opts' allows setting the sender key using the WithSender() option
+// where the sender key is a remote key ID (string). This option allows ECDH-1PU key wrapping (aka Authcrypt), its
+// absence uses ECDH-ES key wrapping (aka Anoncrypt).
+func (c *Client) WrapKey(cek, apu, apv []byte, recPubKey *crypto.PublicKey,
+	opts ...crypto.WrapKeyOpts) (*crypto.RecipientWrappedKey, error) {
+	pOpts := crypto.NewOpt()
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	req := &pb.WrapRequest{
+		Cek:             cek,
+		Apu:             apu,
+		Apv:             apv,
+		RecipientPubKey: toPBPublicKey(recPubKey),
+		Tag:             pOpts.Tag(),
+		UseXc20Pkw:      pOpts.UseXC20PKW(),
+	}
+
+	if pOpts.SenderKey() != nil {
+		senderKID, err := keyIDString(pOpts.SenderKey())
+		if err != nil {
+			return nil, fmt.Errorf("grpc WrapKey invalid sender key: %w", err)
+		}
+
+		req.SenderKeyId = senderKID
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	resp, err := c.client.Wrap(ctx, req, c.opts.callOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("grpc WrapKey failed: %w", err)
+	}
+
+	return fromPBWrappedKey(resp.GetWrappedKey()), nil
+}
+
+// UnwrapKey will remotely unwrap recWK using the private key of keyID. 'opts' allows setting the sender public key
+// (*crypto.PublicKey) using the WithSender() option for ECDH-1PU key unwrapping (aka Authcrypt).
+func (c *Client) UnwrapKey(recWK *crypto.RecipientWrappedKey, keyID interface{},
+	opts ...crypto.WrapKeyOpts) ([]byte, error) {
+	kid, err := keyIDString(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	pOpts := crypto.NewOpt()
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	req := &pb.UnwrapRequest{
+		KeyId:      kid,
+		WrappedKey: toPBWrappedKey(recWK),
+		Tag:        pOpts.Tag(),
+	}
+
+	if pOpts.SenderKey() != nil {
+		senderPubKey, ok := pOpts.SenderKey().(*crypto.PublicKey)
+		if !ok {
+			return nil, errors.New("grpc UnwrapKey invalid sender key type, should be *crypto.PublicKey")
+		}
+
+		req.SenderPubKey = toPBPublicKey(senderPubKey)
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	resp, err := c.client.Unwrap(ctx, req, c.opts.callOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("grpc UnwrapKey failed: %w", err)
+	}
+
+	return resp.GetKey(), nil
+}
+
+// Encrypt will remotely encrypt msg and aad using the AEAD key of keyID.
+// returns:
+//
+//	cipher text in []byte
+//	nonce in []byte
+//	error in case of errors during encryption
+func (c *Client) Encrypt(msg, aad []byte, keyID interface{}) ([]byte, []byte, error) {
+	kid, err := keyIDString(keyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	resp, err := c.client.Encrypt(ctx, &pb.EncryptRequest{KeyId: kid, Message: msg, Aad: aad}, c.opts.callOptions...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("grpc Encrypt failed: %w", err)
+	}
+
+	return resp.GetCiphertext(), resp.GetNonce(), nil
+}
+
+// Decrypt will remotely decrypt cipher with aad and nonce using the AEAD key of keyID.
+func (c *Client) Decrypt(cipher, aad, nonce []byte, keyID interface{}) ([]byte, error) {
+	kid, err := keyIDString(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	resp, err := c.client.Decrypt(ctx, &pb.DecryptRequest{KeyId: kid, Ciphertext: cipher, Aad: aad, Nonce: nonce},
+		c.opts.callOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("grpc Decrypt failed: %w", err)
+	}
+
+	return resp.GetPlaintext(), nil
+}
+
+// EncryptStream will remotely encrypt the content of r with aad using the AEAD key of keyID. The message is sent in
+// chunks and the resulting cipher text is written to w as it is received.
+// returns:
+//
+//	nonce in []byte
+//	error in case of errors during encryption
+func (c *Client) EncryptStream(r io.Reader, aad []byte, keyID interface{}, w io.Writer) ([]byte, error) {
+	kid, err := keyIDString(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	stream, err := c.client.EncryptStream(ctx, c.opts.callOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("grpc EncryptStream failed: %w", err)
+	}
+
+	err = readChunks(r, c.opts.chunkSize, func(chunk []byte, first bool) error {
+		req := &pb.EncryptRequest{Message: chunk}
+		if first {
+			req.KeyId, req.Aad = kid, aad
+		}
+
+		return stream.Send(req)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("grpc EncryptStream send failed: %w", err)
+	}
+
+	if err = stream.CloseSend(); err != nil {
+		return nil, fmt.Errorf("grpc EncryptStream close failed: %w", err)
+	}
+
+	var nonce []byte
+
+	for {
+		resp, e := stream.Recv()
+		if errors.Is(e, io.EOF) {
+			break
+		}
+
+		if e != nil {
+			return nil, fmt.Errorf("grpc EncryptStream failed: %w", e)
+		}
+
+		if len(resp.GetNonce()) > 0 {
+			nonce = resp.GetNonce()
+		}
+
+		if _, e = w.Write(resp.GetCiphertext()); e != nil {
+			return nil, fmt.Errorf("grpc EncryptStream write failed: %w", e)
+		}
+	}
+
+	return nonce, nil
+}
+
+// DecryptStream will remotely decrypt the content of r with aad and nonce using the AEAD key of keyID. The cipher
+// text is sent in chunks and the resulting plain text is written to w as it is received.
+func (c *Client) DecryptStream(r io.Reader, aad, nonce []byte, keyID interface{}, w io.Writer) error {
+	kid, err := keyIDString(keyID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	stream, err := c.client.DecryptStream(ctx, c.opts.callOptions...)
+	if err != nil {
+		return fmt.Errorf("grpc DecryptStream failed: %w", err)
+	}
+
+	err = readChunks(r, c.opts.chunkSize, func(chunk []byte, first bool) error {
+		req := &pb.DecryptRequest{Ciphertext: chunk}
+		if first {
+			req.KeyId, req.Aad, req.Nonce = kid, aad, nonce
+		}
+
+		return stream.Send(req)
+	})
+	if err != nil {
+		return fmt.Errorf("grpc DecryptStream send failed: %w", err)
+	}
+
+	if err = stream.CloseSend(); err != nil {
+		return fmt.Errorf("grpc DecryptStream close failed: %w", err)
+	}
+
+	for {
+		resp, e := stream.Recv()
+		if errors.Is(e, io.EOF) {
+			return nil
+		}
+
+		if e != nil {
+			return fmt.Errorf("grpc DecryptStream failed: %w", e)
+		}
+
+		if _, e = w.Write(resp.GetPlaintext()); e != nil {
+			return fmt.Errorf("grpc DecryptStream write failed: %w", e)
+		}
+	}
+}
+
+// readChunks reads r in chunkSize parts and calls send for each of them. send is called at least once, even if r is
+// empty, so that the first request carrying the call parameters is always sent.
+func readChunks(r io.Reader, chunkSize int, send func(chunk []byte, first bool) error) error {
+	buf := make([]byte, chunkSize)
+	first := true
+
+	for {
+		n, err := io.ReadFull(r, buf)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			if n > 0 || first {
+				return send(bytes.Clone(buf[:n]), first)
+			}
+
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err = send(bytes.Clone(buf[:n]), first); err != nil {
+			return err
+		}
+
+		first = false
+	}
+}
+
+func keyIDString(keyID interface{}) (string, error) {
+	kid, ok := keyID.(string)
+	if !ok || kid == "" {
+		return "", fmt.Errorf("invalid key ID %v, should be a non empty string", keyID)
+	}
+
+	return kid, nil
+}
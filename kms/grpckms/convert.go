@@ -0,0 +1,82 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpckms
+
+import (
+	"github.com/trustbloc/kms-go/spi/crypto"
+
+	pb "github.com/trustbloc/kms-go/kms/grpckms/proto/grpckms_go_proto"
+)
+
+func toPBPublicKey(pk *crypto.PublicKey) *pb.PublicKey {
+	if pk == nil {
+		return nil
+	}
+
+	return &pb.PublicKey{
+		Kid:   pk.KID,
+		X:     pk.X,
+		Y:     pk.Y,
+		N:     pk.N,
+		E:     pk.E,
+		Curve: pk.Curve,
+		Type:  pk.Type,
+	}
+}
+
+func fromPBPublicKey(pk *pb.PublicKey) *crypto.PublicKey {
+	if pk == nil {
+		return nil
+	}
+
+	return &crypto.PublicKey{
+		KID:   pk.GetKid(),
+		X:     pk.GetX(),
+		Y:     pk.GetY(),
+		N:     pk.GetN(),
+		E:     pk.GetE(),
+		Curve: pk.GetCurve(),
+		Type:  pk.GetType(),
+	}
+}
+
+func toPBWrappedKey(wk *crypto.RecipientWrappedKey) *pb.RecipientWrappedKey {
+	if wk == nil {
+		return nil
+	}
+
+	return &pb.RecipientWrappedKey{
+		Kid:          wk.KID,
+		EncryptedCek: wk.EncryptedCEK,
+		Epk:          toPBPublicKey(&wk.EPK),
+		Alg:          wk.Alg,
+		Apu:          wk.APU,
+		Apv:          wk.APV,
+		Nested:       wk.Nested,
+	}
+}
+
+func fromPBWrappedKey(wk *pb.RecipientWrappedKey) *crypto.RecipientWrappedKey {
+	if wk == nil {
+		return nil
+	}
+
+	rwk := &crypto.RecipientWrappedKey{
+		KID:          wk.GetKid(),
+		EncryptedCEK: wk.GetEncryptedCek(),
+		Alg:          wk.GetAlg(),
+		APU:          wk.GetApu(),
+		APV:          wk.GetApv(),
+		Nested:       wk.GetNested(),
+	}
+
+	if epk := fromPBPublicKey(wk.GetEpk()); epk != nil {
+		rwk.EPK = *epk
+	}
+
+	return rwk
+}
@@ -0,0 +1,319 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpckms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	pb "github.com/trustbloc/kms-go/kms/grpckms/proto/grpckms_go_proto"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func newTestClient(t *testing.T, opts ...Opt) *Client {
+	t.Helper()
+
+	km := mockkms.NewForTest(t)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	pb.RegisterKMSServer(srv, NewServer(km, cr, opts...))
+
+	go func() {
+		_ = srv.Serve(lis) //nolint:errcheck
+	}()
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, conn.Close())
+		srv.Stop()
+	})
+
+	return NewClient(conn, opts...)
+}
+
+func exportECDHPubKey(t *testing.T, pubKeyBytes []byte, kid string) *cryptoapi.PublicKey {
+	t.Helper()
+
+	pubKey := &cryptoapi.PublicKey{}
+	require.NoError(t, json.Unmarshal(pubKeyBytes, pubKey))
+
+	pubKey.KID = kid
+
+	return pubKey
+}
+
+func TestClient_SignVerify(t *testing.T) {
+	c := newTestClient(t, WithChunkSize(16))
+
+	keyID, pubKey, err := c.Create(kmsapi.ECDSAP256TypeIEEEP1363)
+	require.NoError(t, err)
+	require.NotEmpty(t, keyID)
+	require.NotEmpty(t, pubKey)
+
+	msg := random.GetRandomBytes(100)
+
+	t.Run("unary", func(t *testing.T) {
+		sig, err := c.Sign(msg, keyID)
+		require.NoError(t, err)
+
+		require.NoError(t, c.Verify(sig, msg, keyID))
+
+		err = c.Verify(sig, []byte("other message"), keyID)
+		require.Error(t, err)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		sig, err := c.SignStream(bytes.NewReader(msg), keyID)
+		require.NoError(t, err)
+
+		require.NoError(t, c.Verify(sig, msg, keyID))
+		require.NoError(t, c.VerifyStream(sig, bytes.NewReader(msg), keyID))
+
+		err = c.VerifyStream(sig, bytes.NewReader(msg[1:]), keyID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "grpc VerifyStream failed")
+	})
+
+	t.Run("key not found", func(t *testing.T) {
+		_, err := c.Sign(msg, "unknown")
+		require.Error(t, err)
+		require.Equal(t, codes.NotFound, status.Code(err))
+
+		_, err = c.SignStream(bytes.NewReader(msg), "unknown")
+		require.Error(t, err)
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("invalid key ID", func(t *testing.T) {
+		_, err := c.Sign(msg, 1)
+		require.EqualError(t, err, "invalid key ID 1, should be a non empty string")
+
+		err = c.Verify(msg, msg, "")
+		require.EqualError(t, err, "invalid key ID , should be a non empty string")
+	})
+}
+
+func TestClient_EncryptDecrypt(t *testing.T) {
+	c := newTestClient(t, WithChunkSize(10))
+
+	keyID, pubKey, err := c.Create(kmsapi.AES256GCMType)
+	require.NoError(t, err)
+	require.Empty(t, pubKey)
+
+	msg := random.GetRandomBytes(95)
+	aad := []byte("aad")
+
+	t.Run("unary", func(t *testing.T) {
+		ct, nonce, err := c.Encrypt(msg, aad, keyID)
+		require.NoError(t, err)
+
+		pt, err := c.Decrypt(ct, aad, nonce, keyID)
+		require.NoError(t, err)
+		require.Equal(t, msg, pt)
+
+		_, err = c.Decrypt(ct, []byte("bad aad"), nonce, keyID)
+		require.Error(t, err)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		ct := &bytes.Buffer{}
+
+		nonce, err := c.EncryptStream(bytes.NewReader(msg), aad, keyID, ct)
+		require.NoError(t, err)
+		require.NotEmpty(t, nonce)
+
+		pt, err := c.Decrypt(ct.Bytes(), aad, nonce, keyID)
+		require.NoError(t, err)
+		require.Equal(t, msg, pt)
+
+		pt2 := &bytes.Buffer{}
+
+		require.NoError(t, c.DecryptStream(bytes.NewReader(ct.Bytes()), aad, nonce, keyID, pt2))
+		require.Equal(t, msg, pt2.Bytes())
+	})
+
+	t.Run("stream empty message", func(t *testing.T) {
+		ct := &bytes.Buffer{}
+
+		nonce, err := c.EncryptStream(bytes.NewReader(nil), nil, keyID, ct)
+		require.NoError(t, err)
+
+		pt := &bytes.Buffer{}
+
+		require.NoError(t, c.DecryptStream(bytes.NewReader(ct.Bytes()), nil, nonce, keyID, pt))
+		require.Empty(t, pt.Bytes())
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		_, err := c.EncryptStream(bytes.NewReader(msg), aad, "unknown", &bytes.Buffer{})
+		require.Error(t, err)
+		require.Equal(t, codes.NotFound, status.Code(err))
+
+		err = c.DecryptStream(bytes.NewReader(msg), aad, nil, keyID, &bytes.Buffer{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "grpc DecryptStream failed")
+
+		_, _, err = c.Create("")
+		require.Error(t, err)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_MaxMessageSize(t *testing.T) {
+	c := newTestClient(t, WithChunkSize(16), WithMaxMessageSize(64))
+
+	sigKeyID, _, err := c.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	aeadKeyID, _, err := c.Create(kmsapi.AES256GCMType)
+	require.NoError(t, err)
+
+	msg := random.GetRandomBytes(64)
+
+	sig, err := c.SignStream(bytes.NewReader(msg), sigKeyID)
+	require.NoError(t, err)
+	require.NoError(t, c.VerifyStream(sig, bytes.NewReader(msg), sigKeyID))
+
+	ct := &bytes.Buffer{}
+
+	nonce, err := c.EncryptStream(bytes.NewReader(msg), nil, aeadKeyID, ct)
+	require.NoError(t, err)
+
+	tooLarge := random.GetRandomBytes(65)
+
+	_, err = c.SignStream(bytes.NewReader(tooLarge), sigKeyID)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	err = c.VerifyStream(sig, bytes.NewReader(tooLarge), sigKeyID)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	_, err = c.EncryptStream(bytes.NewReader(tooLarge), nil, aeadKeyID, &bytes.Buffer{})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// the ciphertext of the 64 bytes message has a tag.
+	err = c.DecryptStream(bytes.NewReader(ct.Bytes()), nil, nonce, aeadKeyID, &bytes.Buffer{})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.ErrorContains(t, err, "decrypt: message exceeds the maximum size of 64 bytes")
+}
+
+func TestClient_CreateSymmetric(t *testing.T) {
+	c := newTestClient(t)
+
+	for _, kt := range kmsapi.SymmetricKeyTypes() {
+		t.Run(string(kt), func(t *testing.T) {
+			keyID, pubKey, err := c.Create(kt)
+			require.NoError(t, err)
+			require.NotEmpty(t, keyID)
+			require.Empty(t, pubKey)
+		})
+	}
+}
+
+func TestClient_WrapUnwrap(t *testing.T) {
+	c := newTestClient(t)
+
+	recKID, recPubKeyBytes, err := c.Create(kmsapi.NISTP256ECDHKWType)
+	require.NoError(t, err)
+
+	recPubKey := exportECDHPubKey(t, recPubKeyBytes, recKID)
+
+	cek := random.GetRandomBytes(32)
+	apu := []byte("sender")
+	apv := []byte("recipient")
+
+	t.Run("anoncrypt", func(t *testing.T) {
+		wk, err := c.WrapKey(cek, apu, apv, recPubKey)
+		require.NoError(t, err)
+		require.Equal(t, recKID, wk.KID)
+
+		key, err := c.UnwrapKey(wk, recKID)
+		require.NoError(t, err)
+		require.Equal(t, cek, key)
+	})
+
+	t.Run("anoncrypt XC20PKW", func(t *testing.T) {
+		wk, err := c.WrapKey(cek, apu, apv, recPubKey, cryptoapi.WithXC20PKW())
+		require.NoError(t, err)
+
+		key, err := c.UnwrapKey(wk, recKID)
+		require.NoError(t, err)
+		require.Equal(t, cek, key)
+	})
+
+	t.Run("authcrypt", func(t *testing.T) {
+		senderKID, senderPubKeyBytes, err := c.Create(kmsapi.NISTP256ECDHKWType)
+		require.NoError(t, err)
+
+		senderPubKey := exportECDHPubKey(t, senderPubKeyBytes, senderKID)
+		tag := []byte("tag")
+
+		wk, err := c.WrapKey(cek, apu, apv, recPubKey, cryptoapi.WithSender(senderKID), cryptoapi.WithTag(tag))
+		require.NoError(t, err)
+
+		key, err := c.UnwrapKey(wk, recKID, cryptoapi.WithSender(senderPubKey), cryptoapi.WithTag(tag))
+		require.NoError(t, err)
+		require.Equal(t, cek, key)
+
+		_, err = c.UnwrapKey(wk, recKID)
+		require.Error(t, err)
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		_, err := c.WrapKey(cek, apu, apv, nil)
+		require.Error(t, err)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = c.WrapKey(cek, apu, apv, recPubKey, cryptoapi.WithSender(1))
+		require.EqualError(t, err, "grpc WrapKey invalid sender key: invalid key ID 1, should be a non empty string")
+
+		_, err = c.UnwrapKey(nil, recKID)
+		require.Error(t, err)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = c.UnwrapKey(&cryptoapi.RecipientWrappedKey{}, recKID, cryptoapi.WithSender(recKID))
+		require.EqualError(t, err, "grpc UnwrapKey invalid sender key type, should be *crypto.PublicKey")
+	})
+}
+
+func TestClient_WithTimeout(t *testing.T) {
+	c := newTestClient(t, WithTimeout(time.Second), WithCallOptions(grpc.WaitForReady(true)))
+
+	keyID, _, err := c.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	sig, err := c.Sign([]byte("msg"), keyID)
+	require.NoError(t, err)
+
+	require.NoError(t, c.Verify(sig, []byte("msg"), keyID))
+}
@@ -0,0 +1,75 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpckms
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// DefaultChunkSize is the default size in bytes of a payload chunk sent over streaming calls. It is kept well below
+// gRPC's default 4MB message limit.
+const DefaultChunkSize = 64 * 1024
+
+// DefaultMaxMessageSize is the default maximum size in bytes of a message or ciphertext received by the Server over
+// streaming calls: the chunks are buffered until the end of the stream, the crypto operations are not incremental.
+const DefaultMaxMessageSize = 16 * 1024 * 1024
+
+type opts struct {
+	chunkSize      int
+	maxMessageSize int
+	timeout        time.Duration
+	callOptions    []grpc.CallOption
+}
+
+func newOpts(options ...Opt) *opts {
+	o := &opts{chunkSize: DefaultChunkSize, maxMessageSize: DefaultMaxMessageSize}
+
+	for _, opt := range options {
+		opt(o)
+	}
+
+	return o
+}
+
+// Opt are the grpckms client and server options.
+type Opt func(o *opts)
+
+// WithChunkSize sets the size in bytes of payload chunks sent over streaming calls (by the client for requests and by
+// the server for responses). Values <= 0 are ignored.
+func WithChunkSize(size int) Opt {
+	return func(o *opts) {
+		if size > 0 {
+			o.chunkSize = size
+		}
+	}
+}
+
+// WithMaxMessageSize sets the maximum size in bytes of a message or ciphertext received over streaming calls, the
+// larger ones are rejected with a ResourceExhausted status. Only used by the Server, values <= 0 are ignored.
+func WithMaxMessageSize(size int) Opt {
+	return func(o *opts) {
+		if size > 0 {
+			o.maxMessageSize = size
+		}
+	}
+}
+
+// WithTimeout sets a per call timeout on client requests. Only used by the Client, a zero value means no timeout.
+func WithTimeout(timeout time.Duration) Opt {
+	return func(o *opts) {
+		o.timeout = timeout
+	}
+}
+
+// WithCallOptions sets grpc.CallOption to add to every client request. Only used by the Client.
+func WithCallOptions(callOptions ...grpc.CallOption) Opt {
+	return func(o *opts) {
+		o.callOptions = append(o.callOptions, callOptions...)
+	}
+}
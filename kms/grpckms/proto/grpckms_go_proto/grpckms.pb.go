@@ -0,0 +1,1476 @@
+// Copyright Gen Digital Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v3.21.12
+// source: proto/grpckms.proto
+
+package grpckms_go_proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PublicKey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kid   string `protobuf:"bytes,1,opt,name=kid,proto3" json:"kid,omitempty"`
+	X     []byte `protobuf:"bytes,2,opt,name=x,proto3" json:"x,omitempty"`
+	Y     []byte `protobuf:"bytes,3,opt,name=y,proto3" json:"y,omitempty"`
+	N     []byte `protobuf:"bytes,4,opt,name=n,proto3" json:"n,omitempty"`
+	E     []byte `protobuf:"bytes,5,opt,name=e,proto3" json:"e,omitempty"`
+	Curve string `protobuf:"bytes,6,opt,name=curve,proto3" json:"curve,omitempty"`
+	Type  string `protobuf:"bytes,7,opt,name=type,proto3" json:"type,omitempty"`
+}
+
+func (x *PublicKey) Reset() {
+	*x = PublicKey{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_grpckms_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublicKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublicKey) ProtoMessage() {}
+
+func (x *PublicKey) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_grpckms_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublicKey.ProtoReflect.Descriptor instead.
+func (*PublicKey) Descriptor() ([]byte, []int) {
+	return file_proto_grpckms_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PublicKey) GetKid() string {
+	if x != nil {
+		return x.Kid
+	}
+	return ""
+}
+
+func (x *PublicKey) GetX() []byte {
+	if x != nil {
+		return x.X
+	}
+	return nil
+}
+
+func (x *PublicKey) GetY() []byte {
+	if x != nil {
+		return x.Y
+	}
+	return nil
+}
+
+func (x *PublicKey) GetN() []byte {
+	if x != nil {
+		return x.N
+	}
+	return nil
+}
+
+func (x *PublicKey) GetE() []byte {
+	if x != nil {
+		return x.E
+	}
+	return nil
+}
+
+func (x *PublicKey) GetCurve() string {
+	if x != nil {
+		return x.Curve
+	}
+	return ""
+}
+
+func (x *PublicKey) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type RecipientWrappedKey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kid          string     `protobuf:"bytes,1,opt,name=kid,proto3" json:"kid,omitempty"`
+	EncryptedCek []byte     `protobuf:"bytes,2,opt,name=encrypted_cek,json=encryptedCek,proto3" json:"encrypted_cek,omitempty"`
+	Epk          *PublicKey `protobuf:"bytes,3,opt,name=epk,proto3" json:"epk,omitempty"`
+	Alg          string     `protobuf:"bytes,4,opt,name=alg,proto3" json:"alg,omitempty"`
+	Apu          []byte     `protobuf:"bytes,5,opt,name=apu,proto3" json:"apu,omitempty"`
+	Apv          []byte     `protobuf:"bytes,6,opt,name=apv,proto3" json:"apv,omitempty"`
+	Nested       bool       `protobuf:"varint,7,opt,name=nested,proto3" json:"nested,omitempty"`
+}
+
+func (x *RecipientWrappedKey) Reset() {
+	*x = RecipientWrappedKey{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_grpckms_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RecipientWrappedKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecipientWrappedKey) ProtoMessage() {}
+
+func (x *RecipientWrappedKey) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_grpckms_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecipientWrappedKey.ProtoReflect.Descriptor instead.
+func (*RecipientWrappedKey) Descriptor() ([]byte, []int) {
+	return file_proto_grpckms_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RecipientWrappedKey) GetKid() string {
+	if x != nil {
+		return x.Kid
+	}
+	return ""
+}
+
+func (x *RecipientWrappedKey) GetEncryptedCek() []byte {
+	if x != nil {
+		return x.EncryptedCek
+	}
+	return nil
+}
+
+func (x *RecipientWrappedKey) GetEpk() *PublicKey {
+	if x != nil {
+		return x.Epk
+	}
+	return nil
+}
+
+func (x *RecipientWrappedKey) GetAlg() string {
+	if x != nil {
+		return x.Alg
+	}
+	return ""
+}
+
+func (x *RecipientWrappedKey) GetApu() []byte {
+	if x != nil {
+		return x.Apu
+	}
+	return nil
+}
+
+func (x *RecipientWrappedKey) GetApv() []byte {
+	if x != nil {
+		return x.Apv
+	}
+	return nil
+}
+
+func (x *RecipientWrappedKey) GetNested() bool {
+	if x != nil {
+		return x.Nested
+	}
+	return false
+}
+
+type CreateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	KeyType string `protobuf:"bytes,1,opt,name=key_type,json=keyType,proto3" json:"key_type,omitempty"`
+}
+
+func (x *CreateRequest) Reset() {
+	*x = CreateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_grpckms_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRequest) ProtoMessage() {}
+
+func (x *CreateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_grpckms_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRequest.ProtoReflect.Descriptor instead.
+func (*CreateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_grpckms_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateRequest) GetKeyType() string {
+	if x != nil {
+		return x.KeyType
+	}
+	return ""
+}
+
+type CreateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	KeyId     string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	PublicKey []byte `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+}
+
+func (x *CreateResponse) Reset() {
+	*x = CreateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_grpckms_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateResponse) ProtoMessage() {}
+
+func (x *CreateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_grpckms_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateResponse.ProtoReflect.Descriptor instead.
+func (*CreateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_grpckms_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateResponse) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *CreateResponse) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+type SignRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	KeyId   string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Message []byte `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *SignRequest) Reset() {
+	*x = SignRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_grpckms_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignRequest) ProtoMessage() {}
+
+func (x *SignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_grpckms_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignRequest.ProtoReflect.Descriptor instead.
+func (*SignRequest) Descriptor() ([]byte, []int) {
+	return file_proto_grpckms_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SignRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *SignRequest) GetMessage() []byte {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+type SignResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Signature []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *SignResponse) Reset() {
+	*x = SignResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_grpckms_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignResponse) ProtoMessage() {}
+
+func (x *SignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_grpckms_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignResponse.ProtoReflect.Descriptor instead.
+func (*SignResponse) Descriptor() ([]byte, []int) {
+	return file_proto_grpckms_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SignResponse) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type VerifyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	KeyId     string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Signature []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	Message   []byte `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *VerifyRequest) Reset() {
+	*x = VerifyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_grpckms_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyRequest) ProtoMessage() {}
+
+func (x *VerifyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_grpckms_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyRequest.ProtoReflect.Descriptor instead.
+func (*VerifyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_grpckms_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *VerifyRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *VerifyRequest) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *VerifyRequest) GetMessage() []byte {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+type VerifyResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *VerifyResponse) Reset() {
+	*x = VerifyResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_grpckms_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyResponse) ProtoMessage() {}
+
+func (x *VerifyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_grpckms_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyResponse.ProtoReflect.Descriptor instead.
+func (*VerifyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_grpckms_proto_rawDescGZIP(), []int{7}
+}
+
+type WrapRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cek             []byte     `protobuf:"bytes,1,opt,name=cek,proto3" json:"cek,omitempty"`
+	Apu             []byte     `protobuf:"bytes,2,opt,name=apu,proto3" json:"apu,omitempty"`
+	Apv             []byte     `protobuf:"bytes,3,opt,name=apv,proto3" json:"apv,omitempty"`
+	RecipientPubKey *PublicKey `protobuf:"bytes,4,opt,name=recipient_pub_key,json=recipientPubKey,proto3" json:"recipient_pub_key,omitempty"`
+	SenderKeyId     string     `protobuf:"bytes,5,opt,name=sender_key_id,json=senderKeyId,proto3" json:"sender_key_id,omitempty"`
+	Tag             []byte     `protobuf:"bytes,6,opt,name=tag,proto3" json:"tag,omitempty"`
+	UseXc20Pkw      bool       `protobuf:"varint,7,opt,name=use_xc20pkw,json=useXc20pkw,proto3" json:"use_xc20pkw,omitempty"`
+}
+
+func (x *WrapRequest) Reset() {
+	*x = WrapRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_grpckms_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WrapRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WrapRequest) ProtoMessage() {}
+
+func (x *WrapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_grpckms_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WrapRequest.ProtoReflect.Descriptor instead.
+func (*WrapRequest) Descriptor() ([]byte, []int) {
+	return file_proto_grpckms_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *WrapRequest) GetCek() []byte {
+	if x != nil {
+		return x.Cek
+	}
+	return nil
+}
+
+func (x *WrapRequest) GetApu() []byte {
+	if x != nil {
+		return x.Apu
+	}
+	return nil
+}
+
+func (x *WrapRequest) GetApv() []byte {
+	if x != nil {
+		return x.Apv
+	}
+	return nil
+}
+
+func (x *WrapRequest) GetRecipientPubKey() *PublicKey {
+	if x != nil {
+		return x.RecipientPubKey
+	}
+	return nil
+}
+
+func (x *WrapRequest) GetSenderKeyId() string {
+	if x != nil {
+		return x.SenderKeyId
+	}
+	return ""
+}
+
+func (x *WrapRequest) GetTag() []byte {
+	if x != nil {
+		return x.Tag
+	}
+	return nil
+}
+
+func (x *WrapRequest) GetUseXc20Pkw() bool {
+	if x != nil {
+		return x.UseXc20Pkw
+	}
+	return false
+}
+
+type WrapResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WrappedKey *RecipientWrappedKey `protobuf:"bytes,1,opt,name=wrapped_key,json=wrappedKey,proto3" json:"wrapped_key,omitempty"`
+}
+
+func (x *WrapResponse) Reset() {
+	*x = WrapResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_grpckms_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WrapResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WrapResponse) ProtoMessage() {}
+
+func (x *WrapResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_grpckms_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WrapResponse.ProtoReflect.Descriptor instead.
+func (*WrapResponse) Descriptor() ([]byte, []int) {
+	return file_proto_grpckms_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WrapResponse) GetWrappedKey() *RecipientWrappedKey {
+	if x != nil {
+		return x.WrappedKey
+	}
+	return nil
+}
+
+type UnwrapRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	KeyId        string               `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	WrappedKey   *RecipientWrappedKey `protobuf:"bytes,2,opt,name=wrapped_key,json=wrappedKey,proto3" json:"wrapped_key,omitempty"`
+	SenderPubKey *PublicKey           `protobuf:"bytes,3,opt,name=sender_pub_key,json=senderPubKey,proto3" json:"sender_pub_key,omitempty"`
+	Tag          []byte               `protobuf:"bytes,4,opt,name=tag,proto3" json:"tag,omitempty"`
+}
+
+func (x *UnwrapRequest) Reset() {
+	*x = UnwrapRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_grpckms_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnwrapRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnwrapRequest) ProtoMessage() {}
+
+func (x *UnwrapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_grpckms_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnwrapRequest.ProtoReflect.Descriptor instead.
+func (*UnwrapRequest) Descriptor() ([]byte, []int) {
+	return file_proto_grpckms_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UnwrapRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *UnwrapRequest) GetWrappedKey() *RecipientWrappedKey {
+	if x != nil {
+		return x.WrappedKey
+	}
+	return nil
+}
+
+func (x *UnwrapRequest) GetSenderPubKey() *PublicKey {
+	if x != nil {
+		return x.SenderPubKey
+	}
+	return nil
+}
+
+func (x *UnwrapRequest) GetTag() []byte {
+	if x != nil {
+		return x.Tag
+	}
+	return nil
+}
+
+type UnwrapResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (x *UnwrapResponse) Reset() {
+	*x = UnwrapResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_grpckms_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnwrapResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnwrapResponse) ProtoMessage() {}
+
+func (x *UnwrapResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_grpckms_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnwrapResponse.ProtoReflect.Descriptor instead.
+func (*UnwrapResponse) Descriptor() ([]byte, []int) {
+	return file_proto_grpckms_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UnwrapResponse) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+type EncryptRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	KeyId   string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Message []byte `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Aad     []byte `protobuf:"bytes,3,opt,name=aad,proto3" json:"aad,omitempty"`
+}
+
+func (x *EncryptRequest) Reset() {
+	*x = EncryptRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_grpckms_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EncryptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncryptRequest) ProtoMessage() {}
+
+func (x *EncryptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_grpckms_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncryptRequest.ProtoReflect.Descriptor instead.
+func (*EncryptRequest) Descriptor() ([]byte, []int) {
+	return file_proto_grpckms_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *EncryptRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *EncryptRequest) GetMessage() []byte {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *EncryptRequest) GetAad() []byte {
+	if x != nil {
+		return x.Aad
+	}
+	return nil
+}
+
+type EncryptResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ciphertext []byte `protobuf:"bytes,1,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	Nonce      []byte `protobuf:"bytes,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+}
+
+func (x *EncryptResponse) Reset() {
+	*x = EncryptResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_grpckms_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EncryptResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncryptResponse) ProtoMessage() {}
+
+func (x *EncryptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_grpckms_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncryptResponse.ProtoReflect.Descriptor instead.
+func (*EncryptResponse) Descriptor() ([]byte, []int) {
+	return file_proto_grpckms_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *EncryptResponse) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
+func (x *EncryptResponse) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+type DecryptRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	KeyId      string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Ciphertext []byte `protobuf:"bytes,2,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	Aad        []byte `protobuf:"bytes,3,opt,name=aad,proto3" json:"aad,omitempty"`
+	Nonce      []byte `protobuf:"bytes,4,opt,name=nonce,proto3" json:"nonce,omitempty"`
+}
+
+func (x *DecryptRequest) Reset() {
+	*x = DecryptRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_grpckms_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DecryptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecryptRequest) ProtoMessage() {}
+
+func (x *DecryptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_grpckms_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecryptRequest.ProtoReflect.Descriptor instead.
+func (*DecryptRequest) Descriptor() ([]byte, []int) {
+	return file_proto_grpckms_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DecryptRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *DecryptRequest) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
+func (x *DecryptRequest) GetAad() []byte {
+	if x != nil {
+		return x.Aad
+	}
+	return nil
+}
+
+func (x *DecryptRequest) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+type DecryptResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Plaintext []byte `protobuf:"bytes,1,opt,name=plaintext,proto3" json:"plaintext,omitempty"`
+}
+
+func (x *DecryptResponse) Reset() {
+	*x = DecryptResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_grpckms_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DecryptResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecryptResponse) ProtoMessage() {}
+
+func (x *DecryptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_grpckms_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecryptResponse.ProtoReflect.Descriptor instead.
+func (*DecryptResponse) Descriptor() ([]byte, []int) {
+	return file_proto_grpckms_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DecryptResponse) GetPlaintext() []byte {
+	if x != nil {
+		return x.Plaintext
+	}
+	return nil
+}
+
+var File_proto_grpckms_proto protoreflect.FileDescriptor
+
+var file_proto_grpckms_proto_rawDesc = []byte{
+	0x0a, 0x13, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x15, 0x74, 0x72, 0x75, 0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63,
+	0x2e, 0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73, 0x22, 0x7f, 0x0a, 0x09,
+	0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x69, 0x64, 0x12, 0x0c, 0x0a, 0x01, 0x78,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x01, 0x79, 0x12, 0x0c, 0x0a, 0x01, 0x6e, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x01, 0x6e, 0x12, 0x0c, 0x0a, 0x01, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x01, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x75, 0x72, 0x76, 0x65, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x63, 0x75, 0x72, 0x76, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0xce, 0x01,
+	0x0a, 0x13, 0x52, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x57, 0x72, 0x61, 0x70, 0x70,
+	0x65, 0x64, 0x4b, 0x65, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x69, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x6e, 0x63, 0x72, 0x79,
+	0x70, 0x74, 0x65, 0x64, 0x5f, 0x63, 0x65, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c,
+	0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x43, 0x65, 0x6b, 0x12, 0x32, 0x0a, 0x03,
+	0x65, 0x70, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x74, 0x72, 0x75, 0x73,
+	0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e, 0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d,
+	0x73, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x52, 0x03, 0x65, 0x70, 0x6b,
+	0x12, 0x10, 0x0a, 0x03, 0x61, 0x6c, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x61,
+	0x6c, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x70, 0x75, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x03, 0x61, 0x70, 0x75, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x70, 0x76, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x03, 0x61, 0x70, 0x76, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x65, 0x73, 0x74, 0x65, 0x64,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x6e, 0x65, 0x73, 0x74, 0x65, 0x64, 0x22, 0x2a,
+	0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x19, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6b, 0x65, 0x79, 0x54, 0x79, 0x70, 0x65, 0x22, 0x46, 0x0a, 0x0e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x15, 0x0a, 0x06,
+	0x6b, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6b, 0x65,
+	0x79, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65,
+	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b,
+	0x65, 0x79, 0x22, 0x3e, 0x0a, 0x0b, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x15, 0x0a, 0x06, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x6b, 0x65, 0x79, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x22, 0x2c, 0x0a, 0x0c, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x22, 0x5e, 0x0a, 0x0d, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x15, 0x0a, 0x06, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x6b, 0x65, 0x79, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67,
+	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x22, 0x10, 0x0a, 0x0e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0xe8, 0x01, 0x0a, 0x0b, 0x57, 0x72, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x65, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x03, 0x63, 0x65, 0x6b, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x70, 0x75, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x03, 0x61, 0x70, 0x75, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x70, 0x76, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x03, 0x61, 0x70, 0x76, 0x12, 0x4c, 0x0a, 0x11, 0x72, 0x65, 0x63, 0x69,
+	0x70, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e,
+	0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73, 0x2e, 0x50, 0x75, 0x62, 0x6c,
+	0x69, 0x63, 0x4b, 0x65, 0x79, 0x52, 0x0f, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74,
+	0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x22, 0x0a, 0x0d, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61,
+	0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x74, 0x61, 0x67, 0x12, 0x1f, 0x0a, 0x0b,
+	0x75, 0x73, 0x65, 0x5f, 0x78, 0x63, 0x32, 0x30, 0x70, 0x6b, 0x77, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0a, 0x75, 0x73, 0x65, 0x58, 0x63, 0x32, 0x30, 0x70, 0x6b, 0x77, 0x22, 0x5b, 0x0a,
+	0x0c, 0x57, 0x72, 0x61, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4b, 0x0a,
+	0x0b, 0x77, 0x72, 0x61, 0x70, 0x70, 0x65, 0x64, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e, 0x6b,
+	0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73, 0x2e, 0x52, 0x65, 0x63, 0x69, 0x70,
+	0x69, 0x65, 0x6e, 0x74, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x52, 0x0a,
+	0x77, 0x72, 0x61, 0x70, 0x70, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x22, 0xcd, 0x01, 0x0a, 0x0d, 0x55,
+	0x6e, 0x77, 0x72, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06,
+	0x6b, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6b, 0x65,
+	0x79, 0x49, 0x64, 0x12, 0x4b, 0x0a, 0x0b, 0x77, 0x72, 0x61, 0x70, 0x70, 0x65, 0x64, 0x5f, 0x6b,
+	0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74,
+	0x62, 0x6c, 0x6f, 0x63, 0x2e, 0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73,
+	0x2e, 0x52, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65,
+	0x64, 0x4b, 0x65, 0x79, 0x52, 0x0a, 0x77, 0x72, 0x61, 0x70, 0x70, 0x65, 0x64, 0x4b, 0x65, 0x79,
+	0x12, 0x46, 0x0a, 0x0e, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x70, 0x75, 0x62, 0x5f, 0x6b,
+	0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74,
+	0x62, 0x6c, 0x6f, 0x63, 0x2e, 0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73,
+	0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x52, 0x0c, 0x73, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x74, 0x61, 0x67, 0x22, 0x22, 0x0a, 0x0e, 0x55, 0x6e,
+	0x77, 0x72, 0x61, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x53,
+	0x0a, 0x0e, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x15, 0x0a, 0x06, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x6b, 0x65, 0x79, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x61, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03,
+	0x61, 0x61, 0x64, 0x22, 0x47, 0x0a, 0x0f, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72,
+	0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x63, 0x69, 0x70, 0x68,
+	0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x22, 0x6f, 0x0a, 0x0e,
+	0x44, 0x65, 0x63, 0x72, 0x79, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15,
+	0x0a, 0x06, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x6b, 0x65, 0x79, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74,
+	0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x63, 0x69, 0x70, 0x68, 0x65,
+	0x72, 0x74, 0x65, 0x78, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x61, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x03, 0x61, 0x61, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x22, 0x2f, 0x0a,
+	0x0f, 0x44, 0x65, 0x63, 0x72, 0x79, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x09, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x32, 0xf6,
+	0x07, 0x0a, 0x03, 0x4b, 0x4d, 0x53, 0x12, 0x57, 0x0a, 0x06, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x12, 0x24, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e, 0x6b, 0x6d, 0x73,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x62, 0x6c,
+	0x6f, 0x63, 0x2e, 0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73, 0x2e, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x51, 0x0a, 0x04, 0x53, 0x69, 0x67, 0x6e, 0x12, 0x22, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x62,
+	0x6c, 0x6f, 0x63, 0x2e, 0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73, 0x2e,
+	0x53, 0x69, 0x67, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x74, 0x72,
+	0x75, 0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e, 0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63,
+	0x6b, 0x6d, 0x73, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x57, 0x0a, 0x06, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x12, 0x24, 0x2e, 0x74,
+	0x72, 0x75, 0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e, 0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70,
+	0x63, 0x6b, 0x6d, 0x73, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x25, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e, 0x6b,
+	0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66,
+	0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x59, 0x0a, 0x0a, 0x53,
+	0x69, 0x67, 0x6e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x22, 0x2e, 0x74, 0x72, 0x75, 0x73,
+	0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e, 0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d,
+	0x73, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e,
+	0x74, 0x72, 0x75, 0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e, 0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x6b, 0x6d, 0x73, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x5f, 0x0a, 0x0c, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x24, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x62, 0x6c,
+	0x6f, 0x63, 0x2e, 0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73, 0x2e, 0x56,
+	0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x74,
+	0x72, 0x75, 0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e, 0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70,
+	0x63, 0x6b, 0x6d, 0x73, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x51, 0x0a, 0x04, 0x57, 0x72, 0x61, 0x70, 0x12,
+	0x22, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e, 0x6b, 0x6d, 0x73, 0x2e,
+	0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73, 0x2e, 0x57, 0x72, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e,
+	0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73, 0x2e, 0x57, 0x72, 0x61, 0x70,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x57, 0x0a, 0x06, 0x55, 0x6e,
+	0x77, 0x72, 0x61, 0x70, 0x12, 0x24, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63,
+	0x2e, 0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73, 0x2e, 0x55, 0x6e, 0x77,
+	0x72, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x74, 0x72, 0x75,
+	0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e, 0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b,
+	0x6d, 0x73, 0x2e, 0x55, 0x6e, 0x77, 0x72, 0x61, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x5a, 0x0a, 0x07, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x12, 0x25,
+	0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e, 0x6b, 0x6d, 0x73, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73, 0x2e, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x62, 0x6c, 0x6f,
+	0x63, 0x2e, 0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73, 0x2e, 0x45, 0x6e,
+	0x63, 0x72, 0x79, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x5a, 0x0a, 0x07, 0x44, 0x65, 0x63, 0x72, 0x79, 0x70, 0x74, 0x12, 0x25, 0x2e, 0x74, 0x72, 0x75,
+	0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e, 0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b,
+	0x6d, 0x73, 0x2e, 0x44, 0x65, 0x63, 0x72, 0x79, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x26, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e, 0x6b, 0x6d,
+	0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73, 0x2e, 0x44, 0x65, 0x63, 0x72, 0x79, 0x70,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x64, 0x0a, 0x0d, 0x45,
+	0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x25, 0x2e, 0x74,
+	0x72, 0x75, 0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e, 0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70,
+	0x63, 0x6b, 0x6d, 0x73, 0x2e, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e,
+	0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73, 0x2e, 0x45, 0x6e, 0x63, 0x72,
+	0x79, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30,
+	0x01, 0x12, 0x64, 0x0a, 0x0d, 0x44, 0x65, 0x63, 0x72, 0x79, 0x70, 0x74, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x12, 0x25, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e, 0x6b,
+	0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73, 0x2e, 0x44, 0x65, 0x63, 0x72, 0x79,
+	0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x74, 0x72, 0x75, 0x73,
+	0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2e, 0x6b, 0x6d, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d,
+	0x73, 0x2e, 0x44, 0x65, 0x63, 0x72, 0x79, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x42, 0x40, 0x5a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x72, 0x75, 0x73, 0x74, 0x62, 0x6c, 0x6f, 0x63, 0x2f,
+	0x6b, 0x6d, 0x73, 0x2d, 0x67, 0x6f, 0x2f, 0x6b, 0x6d, 0x73, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x6b,
+	0x6d, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x6b, 0x6d, 0x73,
+	0x5f, 0x67, 0x6f, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_proto_grpckms_proto_rawDescOnce sync.Once
+	file_proto_grpckms_proto_rawDescData = file_proto_grpckms_proto_rawDesc
+)
+
+func file_proto_grpckms_proto_rawDescGZIP() []byte {
+	file_proto_grpckms_proto_rawDescOnce.Do(func() {
+		file_proto_grpckms_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_grpckms_proto_rawDescData)
+	})
+	return file_proto_grpckms_proto_rawDescData
+}
+
+var file_proto_grpckms_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_proto_grpckms_proto_goTypes = []interface{}{
+	(*PublicKey)(nil),           // 0: trustbloc.kms.grpckms.PublicKey
+	(*RecipientWrappedKey)(nil), // 1: trustbloc.kms.grpckms.RecipientWrappedKey
+	(*CreateRequest)(nil),       // 2: trustbloc.kms.grpckms.CreateRequest
+	(*CreateResponse)(nil),      // 3: trustbloc.kms.grpckms.CreateResponse
+	(*SignRequest)(nil),         // 4: trustbloc.kms.grpckms.SignRequest
+	(*SignResponse)(nil),        // 5: trustbloc.kms.grpckms.SignResponse
+	(*VerifyRequest)(nil),       // 6: trustbloc.kms.grpckms.VerifyRequest
+	(*VerifyResponse)(nil),      // 7: trustbloc.kms.grpckms.VerifyResponse
+	(*WrapRequest)(nil),         // 8: trustbloc.kms.grpckms.WrapRequest
+	(*WrapResponse)(nil),        // 9: trustbloc.kms.grpckms.WrapResponse
+	(*UnwrapRequest)(nil),       // 10: trustbloc.kms.grpckms.UnwrapRequest
+	(*UnwrapResponse)(nil),      // 11: trustbloc.kms.grpckms.UnwrapResponse
+	(*EncryptRequest)(nil),      // 12: trustbloc.kms.grpckms.EncryptRequest
+	(*EncryptResponse)(nil),     // 13: trustbloc.kms.grpckms.EncryptResponse
+	(*DecryptRequest)(nil),      // 14: trustbloc.kms.grpckms.DecryptRequest
+	(*DecryptResponse)(nil),     // 15: trustbloc.kms.grpckms.DecryptResponse
+}
+var file_proto_grpckms_proto_depIdxs = []int32{
+	0,  // 0: trustbloc.kms.grpckms.RecipientWrappedKey.epk:type_name -> trustbloc.kms.grpckms.PublicKey
+	0,  // 1: trustbloc.kms.grpckms.WrapRequest.recipient_pub_key:type_name -> trustbloc.kms.grpckms.PublicKey
+	1,  // 2: trustbloc.kms.grpckms.WrapResponse.wrapped_key:type_name -> trustbloc.kms.grpckms.RecipientWrappedKey
+	1,  // 3: trustbloc.kms.grpckms.UnwrapRequest.wrapped_key:type_name -> trustbloc.kms.grpckms.RecipientWrappedKey
+	0,  // 4: trustbloc.kms.grpckms.UnwrapRequest.sender_pub_key:type_name -> trustbloc.kms.grpckms.PublicKey
+	2,  // 5: trustbloc.kms.grpckms.KMS.Create:input_type -> trustbloc.kms.grpckms.CreateRequest
+	4,  // 6: trustbloc.kms.grpckms.KMS.Sign:input_type -> trustbloc.kms.grpckms.SignRequest
+	6,  // 7: trustbloc.kms.grpckms.KMS.Verify:input_type -> trustbloc.kms.grpckms.VerifyRequest
+	4,  // 8: trustbloc.kms.grpckms.KMS.SignStream:input_type -> trustbloc.kms.grpckms.SignRequest
+	6,  // 9: trustbloc.kms.grpckms.KMS.VerifyStream:input_type -> trustbloc.kms.grpckms.VerifyRequest
+	8,  // 10: trustbloc.kms.grpckms.KMS.Wrap:input_type -> trustbloc.kms.grpckms.WrapRequest
+	10, // 11: trustbloc.kms.grpckms.KMS.Unwrap:input_type -> trustbloc.kms.grpckms.UnwrapRequest
+	12, // 12: trustbloc.kms.grpckms.KMS.Encrypt:input_type -> trustbloc.kms.grpckms.EncryptRequest
+	14, // 13: trustbloc.kms.grpckms.KMS.Decrypt:input_type -> trustbloc.kms.grpckms.DecryptRequest
+	12, // 14: trustbloc.kms.grpckms.KMS.EncryptStream:input_type -> trustbloc.kms.grpckms.EncryptRequest
+	14, // 15: trustbloc.kms.grpckms.KMS.DecryptStream:input_type -> trustbloc.kms.grpckms.DecryptRequest
+	3,  // 16: trustbloc.kms.grpckms.KMS.Create:output_type -> trustbloc.kms.grpckms.CreateResponse
+	5,  // 17: trustbloc.kms.grpckms.KMS.Sign:output_type -> trustbloc.kms.grpckms.SignResponse
+	7,  // 18: trustbloc.kms.grpckms.KMS.Verify:output_type -> trustbloc.kms.grpckms.VerifyResponse
+	5,  // 19: trustbloc.kms.grpckms.KMS.SignStream:output_type -> trustbloc.kms.grpckms.SignResponse
+	7,  // 20: trustbloc.kms.grpckms.KMS.VerifyStream:output_type -> trustbloc.kms.grpckms.VerifyResponse
+	9,  // 21: trustbloc.kms.grpckms.KMS.Wrap:output_type -> trustbloc.kms.grpckms.WrapResponse
+	11, // 22: trustbloc.kms.grpckms.KMS.Unwrap:output_type -> trustbloc.kms.grpckms.UnwrapResponse
+	13, // 23: trustbloc.kms.grpckms.KMS.Encrypt:output_type -> trustbloc.kms.grpckms.EncryptResponse
+	15, // 24: trustbloc.kms.grpckms.KMS.Decrypt:output_type -> trustbloc.kms.grpckms.DecryptResponse
+	13, // 25: trustbloc.kms.grpckms.KMS.EncryptStream:output_type -> trustbloc.kms.grpckms.EncryptResponse
+	15, // 26: trustbloc.kms.grpckms.KMS.DecryptStream:output_type -> trustbloc.kms.grpckms.DecryptResponse
+	16, // [16:27] is the sub-list for method output_type
+	5,  // [5:16] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_proto_grpckms_proto_init() }
+func file_proto_grpckms_proto_init() {
+	if File_proto_grpckms_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_grpckms_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublicKey); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_grpckms_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RecipientWrappedKey); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_grpckms_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_grpckms_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_grpckms_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_grpckms_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_grpckms_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_grpckms_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_grpckms_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WrapRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_grpckms_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WrapResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_grpckms_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnwrapRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_grpckms_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnwrapResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_grpckms_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EncryptRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_grpckms_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EncryptResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_grpckms_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DecryptRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_grpckms_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DecryptResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_grpckms_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_grpckms_proto_goTypes,
+		DependencyIndexes: file_proto_grpckms_proto_depIdxs,
+		MessageInfos:      file_proto_grpckms_proto_msgTypes,
+	}.Build()
+	File_proto_grpckms_proto = out.File
+	file_proto_grpckms_proto_rawDesc = nil
+	file_proto_grpckms_proto_goTypes = nil
+	file_proto_grpckms_proto_depIdxs = nil
+}
@@ -0,0 +1,644 @@
+// Copyright Gen Digital Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: proto/grpckms.proto
+
+package grpckms_go_proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	KMS_Create_FullMethodName        = "/trustbloc.kms.grpckms.KMS/Create"
+	KMS_Sign_FullMethodName          = "/trustbloc.kms.grpckms.KMS/Sign"
+	KMS_Verify_FullMethodName        = "/trustbloc.kms.grpckms.KMS/Verify"
+	KMS_SignStream_FullMethodName    = "/trustbloc.kms.grpckms.KMS/SignStream"
+	KMS_VerifyStream_FullMethodName  = "/trustbloc.kms.grpckms.KMS/VerifyStream"
+	KMS_Wrap_FullMethodName          = "/trustbloc.kms.grpckms.KMS/Wrap"
+	KMS_Unwrap_FullMethodName        = "/trustbloc.kms.grpckms.KMS/Unwrap"
+	KMS_Encrypt_FullMethodName       = "/trustbloc.kms.grpckms.KMS/Encrypt"
+	KMS_Decrypt_FullMethodName       = "/trustbloc.kms.grpckms.KMS/Decrypt"
+	KMS_EncryptStream_FullMethodName = "/trustbloc.kms.grpckms.KMS/EncryptStream"
+	KMS_DecryptStream_FullMethodName = "/trustbloc.kms.grpckms.KMS/DecryptStream"
+)
+
+// KMSClient is the client API for KMS service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type KMSClient interface {
+	// Create a new key of the given type and return its ID along with its public key bytes (if any).
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
+	// Sign a message with the private key referenced by key_id.
+	Sign(ctx context.Context, in *SignRequest, opts ...grpc.CallOption) (*SignResponse, error)
+	// Verify a signature with the public key referenced by key_id.
+	Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error)
+	// SignStream signs a message uploaded in chunks. The first request must set key_id.
+	SignStream(ctx context.Context, opts ...grpc.CallOption) (KMS_SignStreamClient, error)
+	// VerifyStream verifies a signature over a message uploaded in chunks. The first request must set key_id and
+	// signature.
+	VerifyStream(ctx context.Context, opts ...grpc.CallOption) (KMS_VerifyStreamClient, error)
+	// Wrap a CEK for a recipient public key. Setting sender_key_id uses ECDH-1PU (authcrypt), otherwise ECDH-ES.
+	Wrap(ctx context.Context, in *WrapRequest, opts ...grpc.CallOption) (*WrapResponse, error)
+	// Unwrap a recipient wrapped key with the private key referenced by key_id.
+	Unwrap(ctx context.Context, in *UnwrapRequest, opts ...grpc.CallOption) (*UnwrapResponse, error)
+	// Encrypt a message with the AEAD key referenced by key_id.
+	Encrypt(ctx context.Context, in *EncryptRequest, opts ...grpc.CallOption) (*EncryptResponse, error)
+	// Decrypt a ciphertext with the AEAD key referenced by key_id.
+	Decrypt(ctx context.Context, in *DecryptRequest, opts ...grpc.CallOption) (*DecryptResponse, error)
+	// EncryptStream encrypts a message uploaded in chunks and streams back the ciphertext in chunks. The first
+	// request must set key_id (and aad if any), the first response carries the nonce.
+	EncryptStream(ctx context.Context, opts ...grpc.CallOption) (KMS_EncryptStreamClient, error)
+	// DecryptStream decrypts a ciphertext uploaded in chunks and streams back the plaintext in chunks. The first
+	// request must set key_id, nonce (and aad if any).
+	DecryptStream(ctx context.Context, opts ...grpc.CallOption) (KMS_DecryptStreamClient, error)
+}
+
+type kMSClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewKMSClient(cc grpc.ClientConnInterface) KMSClient {
+	return &kMSClient{cc}
+}
+
+func (c *kMSClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error) {
+	out := new(CreateResponse)
+	err := c.cc.Invoke(ctx, KMS_Create_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kMSClient) Sign(ctx context.Context, in *SignRequest, opts ...grpc.CallOption) (*SignResponse, error) {
+	out := new(SignResponse)
+	err := c.cc.Invoke(ctx, KMS_Sign_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kMSClient) Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error) {
+	out := new(VerifyResponse)
+	err := c.cc.Invoke(ctx, KMS_Verify_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kMSClient) SignStream(ctx context.Context, opts ...grpc.CallOption) (KMS_SignStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &KMS_ServiceDesc.Streams[0], KMS_SignStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &kMSSignStreamClient{stream}
+	return x, nil
+}
+
+type KMS_SignStreamClient interface {
+	Send(*SignRequest) error
+	CloseAndRecv() (*SignResponse, error)
+	grpc.ClientStream
+}
+
+type kMSSignStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *kMSSignStreamClient) Send(m *SignRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *kMSSignStreamClient) CloseAndRecv() (*SignResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(SignResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *kMSClient) VerifyStream(ctx context.Context, opts ...grpc.CallOption) (KMS_VerifyStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &KMS_ServiceDesc.Streams[1], KMS_VerifyStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &kMSVerifyStreamClient{stream}
+	return x, nil
+}
+
+type KMS_VerifyStreamClient interface {
+	Send(*VerifyRequest) error
+	CloseAndRecv() (*VerifyResponse, error)
+	grpc.ClientStream
+}
+
+type kMSVerifyStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *kMSVerifyStreamClient) Send(m *VerifyRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *kMSVerifyStreamClient) CloseAndRecv() (*VerifyResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(VerifyResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *kMSClient) Wrap(ctx context.Context, in *WrapRequest, opts ...grpc.CallOption) (*WrapResponse, error) {
+	out := new(WrapResponse)
+	err := c.cc.Invoke(ctx, KMS_Wrap_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kMSClient) Unwrap(ctx context.Context, in *UnwrapRequest, opts ...grpc.CallOption) (*UnwrapResponse, error) {
+	out := new(UnwrapResponse)
+	err := c.cc.Invoke(ctx, KMS_Unwrap_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kMSClient) Encrypt(ctx context.Context, in *EncryptRequest, opts ...grpc.CallOption) (*EncryptResponse, error) {
+	out := new(EncryptResponse)
+	err := c.cc.Invoke(ctx, KMS_Encrypt_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kMSClient) Decrypt(ctx context.Context, in *DecryptRequest, opts ...grpc.CallOption) (*DecryptResponse, error) {
+	out := new(DecryptResponse)
+	err := c.cc.Invoke(ctx, KMS_Decrypt_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kMSClient) EncryptStream(ctx context.Context, opts ...grpc.CallOption) (KMS_EncryptStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &KMS_ServiceDesc.Streams[2], KMS_EncryptStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &kMSEncryptStreamClient{stream}
+	return x, nil
+}
+
+type KMS_EncryptStreamClient interface {
+	Send(*EncryptRequest) error
+	Recv() (*EncryptResponse, error)
+	grpc.ClientStream
+}
+
+type kMSEncryptStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *kMSEncryptStreamClient) Send(m *EncryptRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *kMSEncryptStreamClient) Recv() (*EncryptResponse, error) {
+	m := new(EncryptResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *kMSClient) DecryptStream(ctx context.Context, opts ...grpc.CallOption) (KMS_DecryptStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &KMS_ServiceDesc.Streams[3], KMS_DecryptStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &kMSDecryptStreamClient{stream}
+	return x, nil
+}
+
+type KMS_DecryptStreamClient interface {
+	Send(*DecryptRequest) error
+	Recv() (*DecryptResponse, error)
+	grpc.ClientStream
+}
+
+type kMSDecryptStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *kMSDecryptStreamClient) Send(m *DecryptRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *kMSDecryptStreamClient) Recv() (*DecryptResponse, error) {
+	m := new(DecryptResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// KMSServer is the server API for KMS service.
+// All implementations must embed UnimplementedKMSServer
+// for forward compatibility
+type KMSServer interface {
+	// Create a new key of the given type and return its ID along with its public key bytes (if any).
+	Create(context.Context, *CreateRequest) (*CreateResponse, error)
+	// Sign a message with the private key referenced by key_id.
+	Sign(context.Context, *SignRequest) (*SignResponse, error)
+	// Verify a signature with the public key referenced by key_id.
+	Verify(context.Context, *VerifyRequest) (*VerifyResponse, error)
+	// SignStream signs a message uploaded in chunks. The first request must set key_id.
+	SignStream(KMS_SignStreamServer) error
+	// VerifyStream verifies a signature over a message uploaded in chunks. The first request must set key_id and
+	// signature.
+	VerifyStream(KMS_VerifyStreamServer) error
+	// Wrap a CEK for a recipient public key. Setting sender_key_id uses ECDH-1PU (authcrypt), otherwise ECDH-ES.
+	Wrap(context.Context, *WrapRequest) (*WrapResponse, error)
+	// Unwrap a recipient wrapped key with the private key referenced by key_id.
+	Unwrap(context.Context, *UnwrapRequest) (*UnwrapResponse, error)
+	// Encrypt a message with the AEAD key referenced by key_id.
+	Encrypt(context.Context, *EncryptRequest) (*EncryptResponse, error)
+	// Decrypt a ciphertext with the AEAD key referenced by key_id.
+	Decrypt(context.Context, *DecryptRequest) (*DecryptResponse, error)
+	// EncryptStream encrypts a message uploaded in chunks and streams back the ciphertext in chunks. The first
+	// request must set key_id (and aad if any), the first response carries the nonce.
+	EncryptStream(KMS_EncryptStreamServer) error
+	// DecryptStream decrypts a ciphertext uploaded in chunks and streams back the plaintext in chunks. The first
+	// request must set key_id, nonce (and aad if any).
+	DecryptStream(KMS_DecryptStreamServer) error
+	mustEmbedUnimplementedKMSServer()
+}
+
+// UnimplementedKMSServer must be embedded to have forward compatible implementations.
+type UnimplementedKMSServer struct {
+}
+
+func (UnimplementedKMSServer) Create(context.Context, *CreateRequest) (*CreateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedKMSServer) Sign(context.Context, *SignRequest) (*SignResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sign not implemented")
+}
+func (UnimplementedKMSServer) Verify(context.Context, *VerifyRequest) (*VerifyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Verify not implemented")
+}
+func (UnimplementedKMSServer) SignStream(KMS_SignStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method SignStream not implemented")
+}
+func (UnimplementedKMSServer) VerifyStream(KMS_VerifyStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method VerifyStream not implemented")
+}
+func (UnimplementedKMSServer) Wrap(context.Context, *WrapRequest) (*WrapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Wrap not implemented")
+}
+func (UnimplementedKMSServer) Unwrap(context.Context, *UnwrapRequest) (*UnwrapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unwrap not implemented")
+}
+func (UnimplementedKMSServer) Encrypt(context.Context, *EncryptRequest) (*EncryptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Encrypt not implemented")
+}
+func (UnimplementedKMSServer) Decrypt(context.Context, *DecryptRequest) (*DecryptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Decrypt not implemented")
+}
+func (UnimplementedKMSServer) EncryptStream(KMS_EncryptStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method EncryptStream not implemented")
+}
+func (UnimplementedKMSServer) DecryptStream(KMS_DecryptStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method DecryptStream not implemented")
+}
+func (UnimplementedKMSServer) mustEmbedUnimplementedKMSServer() {}
+
+// UnsafeKMSServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to KMSServer will
+// result in compilation errors.
+type UnsafeKMSServer interface {
+	mustEmbedUnimplementedKMSServer()
+}
+
+func RegisterKMSServer(s grpc.ServiceRegistrar, srv KMSServer) {
+	s.RegisterService(&KMS_ServiceDesc, srv)
+}
+
+func _KMS_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KMSServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KMS_Create_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KMSServer).Create(ctx, req.(*CreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KMS_Sign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KMSServer).Sign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KMS_Sign_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KMSServer).Sign(ctx, req.(*SignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KMS_Verify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KMSServer).Verify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KMS_Verify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KMSServer).Verify(ctx, req.(*VerifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KMS_SignStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(KMSServer).SignStream(&kMSSignStreamServer{stream})
+}
+
+type KMS_SignStreamServer interface {
+	SendAndClose(*SignResponse) error
+	Recv() (*SignRequest, error)
+	grpc.ServerStream
+}
+
+type kMSSignStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *kMSSignStreamServer) SendAndClose(m *SignResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *kMSSignStreamServer) Recv() (*SignRequest, error) {
+	m := new(SignRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _KMS_VerifyStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(KMSServer).VerifyStream(&kMSVerifyStreamServer{stream})
+}
+
+type KMS_VerifyStreamServer interface {
+	SendAndClose(*VerifyResponse) error
+	Recv() (*VerifyRequest, error)
+	grpc.ServerStream
+}
+
+type kMSVerifyStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *kMSVerifyStreamServer) SendAndClose(m *VerifyResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *kMSVerifyStreamServer) Recv() (*VerifyRequest, error) {
+	m := new(VerifyRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _KMS_Wrap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WrapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KMSServer).Wrap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KMS_Wrap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KMSServer).Wrap(ctx, req.(*WrapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KMS_Unwrap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnwrapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KMSServer).Unwrap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KMS_Unwrap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KMSServer).Unwrap(ctx, req.(*UnwrapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KMS_Encrypt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EncryptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KMSServer).Encrypt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KMS_Encrypt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KMSServer).Encrypt(ctx, req.(*EncryptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KMS_Decrypt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecryptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KMSServer).Decrypt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KMS_Decrypt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KMSServer).Decrypt(ctx, req.(*DecryptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KMS_EncryptStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(KMSServer).EncryptStream(&kMSEncryptStreamServer{stream})
+}
+
+type KMS_EncryptStreamServer interface {
+	Send(*EncryptResponse) error
+	Recv() (*EncryptRequest, error)
+	grpc.ServerStream
+}
+
+type kMSEncryptStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *kMSEncryptStreamServer) Send(m *EncryptResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *kMSEncryptStreamServer) Recv() (*EncryptRequest, error) {
+	m := new(EncryptRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _KMS_DecryptStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(KMSServer).DecryptStream(&kMSDecryptStreamServer{stream})
+}
+
+type KMS_DecryptStreamServer interface {
+	Send(*DecryptResponse) error
+	Recv() (*DecryptRequest, error)
+	grpc.ServerStream
+}
+
+type kMSDecryptStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *kMSDecryptStreamServer) Send(m *DecryptResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *kMSDecryptStreamServer) Recv() (*DecryptRequest, error) {
+	m := new(DecryptRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// KMS_ServiceDesc is the grpc.ServiceDesc for KMS service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var KMS_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "trustbloc.kms.grpckms.KMS",
+	HandlerType: (*KMSServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _KMS_Create_Handler,
+		},
+		{
+			MethodName: "Sign",
+			Handler:    _KMS_Sign_Handler,
+		},
+		{
+			MethodName: "Verify",
+			Handler:    _KMS_Verify_Handler,
+		},
+		{
+			MethodName: "Wrap",
+			Handler:    _KMS_Wrap_Handler,
+		},
+		{
+			MethodName: "Unwrap",
+			Handler:    _KMS_Unwrap_Handler,
+		},
+		{
+			MethodName: "Encrypt",
+			Handler:    _KMS_Encrypt_Handler,
+		},
+		{
+			MethodName: "Decrypt",
+			Handler:    _KMS_Decrypt_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SignStream",
+			Handler:       _KMS_SignStream_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "VerifyStream",
+			Handler:       _KMS_VerifyStream_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "EncryptStream",
+			Handler:       _KMS_EncryptStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DecryptStream",
+			Handler:       _KMS_DecryptStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "proto/grpckms.proto",
+}
@@ -0,0 +1,433 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpckms
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/tink/go/keyset"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/kms"
+	pb "github.com/trustbloc/kms-go/kms/grpckms/proto/grpckms_go_proto"
+)
+
+// Server is a gRPC KMS service backed by a KeyManager and a Crypto service. Register it with a grpc.Server using
+// grpckms_go_proto.RegisterKMSServer().
+type Server struct {
+	pb.UnimplementedKMSServer
+	km             kmsapi.KeyManager
+	crypto         crypto.Crypto
+	chunkSize      int
+	maxMessageSize int
+}
+
+// NewServer creates a new gRPC KMS service using km to manage keys and c to execute crypto operations.
+func NewServer(km kmsapi.KeyManager, c crypto.Crypto, opts ...Opt) *Server {
+	o := newOpts(opts...)
+
+	return &Server{
+		km:             km,
+		crypto:         c,
+		chunkSize:      o.chunkSize,
+		maxMessageSize: o.maxMessageSize,
+	}
+}
+
+// Create a new key and return its ID with its public key bytes. Public key bytes are empty for symmetric key types.
+func (s *Server) Create(_ context.Context, req *pb.CreateRequest) (*pb.CreateResponse, error) {
+	kt := kmsapi.KeyType(req.GetKeyType())
+	if kt == "" {
+		return nil, status.Error(codes.InvalidArgument, "create: missing key type")
+	}
+
+	if isSymmetric(kt) {
+		keyID, _, err := s.km.Create(kt)
+		if err != nil {
+			return nil, toStatus("create", err)
+		}
+
+		return &pb.CreateResponse{KeyId: keyID}, nil
+	}
+
+	keyID, pubKey, err := s.km.CreateAndExportPubKeyBytes(kt)
+	if err != nil {
+		return nil, toStatus("create", err)
+	}
+
+	return &pb.CreateResponse{KeyId: keyID, PublicKey: pubKey}, nil
+}
+
+// Sign a message with the private key referenced by the request key ID.
+func (s *Server) Sign(_ context.Context, req *pb.SignRequest) (*pb.SignResponse, error) {
+	return s.sign(req.GetKeyId(), req.GetMessage())
+}
+
+// SignStream signs a message received in chunks, of up to the WithMaxMessageSize size.
+func (s *Server) SignStream(stream pb.KMS_SignStreamServer) error {
+	var (
+		keyID string
+		msg   bytes.Buffer
+	)
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if keyID == "" {
+			keyID = req.GetKeyId()
+		}
+
+		if err = s.appendChunk("sign", &msg, req.GetMessage()); err != nil {
+			return err
+		}
+	}
+
+	resp, err := s.sign(keyID, msg.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(resp)
+}
+
+func (s *Server) sign(keyID string, msg []byte) (*pb.SignResponse, error) {
+	kh, err := s.getKey("sign", keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := s.crypto.Sign(msg, kh)
+	if err != nil {
+		return nil, toStatus("sign", err)
+	}
+
+	return &pb.SignResponse{Signature: sig}, nil
+}
+
+// Verify a signature with the public key referenced by the request key ID.
+func (s *Server) Verify(_ context.Context, req *pb.VerifyRequest) (*pb.VerifyResponse, error) {
+	return s.verify(req.GetKeyId(), req.GetSignature(), req.GetMessage())
+}
+
+// VerifyStream verifies a signature over a message received in chunks, of up to the WithMaxMessageSize size.
+func (s *Server) VerifyStream(stream pb.KMS_VerifyStreamServer) error {
+	var (
+		keyID string
+		sig   []byte
+		msg   bytes.Buffer
+	)
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if keyID == "" {
+			keyID, sig = req.GetKeyId(), req.GetSignature()
+		}
+
+		if err = s.appendChunk("verify", &msg, req.GetMessage()); err != nil {
+			return err
+		}
+	}
+
+	resp, err := s.verify(keyID, sig, msg.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(resp)
+}
+
+func (s *Server) verify(keyID string, sig, msg []byte) (*pb.VerifyResponse, error) {
+	kh, err := s.getPublicKey("verify", keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.crypto.Verify(sig, msg, kh)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "verify: %v", err)
+	}
+
+	return &pb.VerifyResponse{}, nil
+}
+
+// Wrap a CEK for the request's recipient public key. If a sender key ID is set, ECDH-1PU is used instead of ECDH-ES.
+func (s *Server) Wrap(_ context.Context, req *pb.WrapRequest) (*pb.WrapResponse, error) {
+	if req.GetRecipientPubKey() == nil {
+		return nil, status.Error(codes.InvalidArgument, "wrap: missing recipient public key")
+	}
+
+	var wOpts []crypto.WrapKeyOpts
+
+	if req.GetSenderKeyId() != "" {
+		senderKH, err := s.getKey("wrap", req.GetSenderKeyId())
+		if err != nil {
+			return nil, err
+		}
+
+		wOpts = append(wOpts, crypto.WithSender(senderKH))
+	}
+
+	if len(req.GetTag()) > 0 {
+		wOpts = append(wOpts, crypto.WithTag(req.GetTag()))
+	}
+
+	if req.GetUseXc20Pkw() {
+		wOpts = append(wOpts, crypto.WithXC20PKW())
+	}
+
+	wk, err := s.crypto.WrapKey(req.GetCek(), req.GetApu(), req.GetApv(), fromPBPublicKey(req.GetRecipientPubKey()),
+		wOpts...)
+	if err != nil {
+		return nil, toStatus("wrap", err)
+	}
+
+	return &pb.WrapResponse{WrappedKey: toPBWrappedKey(wk)}, nil
+}
+
+// Unwrap a recipient wrapped key with the private key referenced by the request key ID.
+func (s *Server) Unwrap(_ context.Context, req *pb.UnwrapRequest) (*pb.UnwrapResponse, error) {
+	if req.GetWrappedKey() == nil {
+		return nil, status.Error(codes.InvalidArgument, "unwrap: missing wrapped key")
+	}
+
+	kh, err := s.getKey("unwrap", req.GetKeyId())
+	if err != nil {
+		return nil, err
+	}
+
+	var wOpts []crypto.WrapKeyOpts
+
+	if req.GetSenderPubKey() != nil {
+		wOpts = append(wOpts, crypto.WithSender(fromPBPublicKey(req.GetSenderPubKey())))
+	}
+
+	if len(req.GetTag()) > 0 {
+		wOpts = append(wOpts, crypto.WithTag(req.GetTag()))
+	}
+
+	key, err := s.crypto.UnwrapKey(fromPBWrappedKey(req.GetWrappedKey()), kh, wOpts...)
+	if err != nil {
+		return nil, toStatus("unwrap", err)
+	}
+
+	return &pb.UnwrapResponse{Key: key}, nil
+}
+
+// Encrypt a message with the AEAD key referenced by the request key ID.
+func (s *Server) Encrypt(_ context.Context, req *pb.EncryptRequest) (*pb.EncryptResponse, error) {
+	kh, err := s.getKey("encrypt", req.GetKeyId())
+	if err != nil {
+		return nil, err
+	}
+
+	ct, nonce, err := s.crypto.Encrypt(req.GetMessage(), req.GetAad(), kh)
+	if err != nil {
+		return nil, toStatus("encrypt", err)
+	}
+
+	return &pb.EncryptResponse{Ciphertext: ct, Nonce: nonce}, nil
+}
+
+// EncryptStream encrypts a message received in chunks, of up to the WithMaxMessageSize size, and sends back the
+// ciphertext in chunks. The nonce is set on the first response.
+func (s *Server) EncryptStream(stream pb.KMS_EncryptStreamServer) error {
+	req := &pb.EncryptRequest{}
+
+	var msg bytes.Buffer
+
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if req.KeyId == "" {
+			req.KeyId, req.Aad = chunk.GetKeyId(), chunk.GetAad()
+		}
+
+		if err = s.appendChunk("encrypt", &msg, chunk.GetMessage()); err != nil {
+			return err
+		}
+	}
+
+	req.Message = msg.Bytes()
+
+	resp, err := s.Encrypt(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return sendChunks(resp.Ciphertext, s.chunkSize, func(chunk []byte, first bool) error {
+		r := &pb.EncryptResponse{Ciphertext: chunk}
+		if first {
+			r.Nonce = resp.Nonce
+		}
+
+		return stream.Send(r)
+	})
+}
+
+// Decrypt a ciphertext with the AEAD key referenced by the request key ID.
+func (s *Server) Decrypt(_ context.Context, req *pb.DecryptRequest) (*pb.DecryptResponse, error) {
+	kh, err := s.getKey("decrypt", req.GetKeyId())
+	if err != nil {
+		return nil, err
+	}
+
+	pt, err := s.crypto.Decrypt(req.GetCiphertext(), req.GetAad(), req.GetNonce(), kh)
+	if err != nil {
+		return nil, toStatus("decrypt", err)
+	}
+
+	return &pb.DecryptResponse{Plaintext: pt}, nil
+}
+
+// DecryptStream decrypts a ciphertext received in chunks, of up to the WithMaxMessageSize size, and sends back the
+// plaintext in chunks.
+func (s *Server) DecryptStream(stream pb.KMS_DecryptStreamServer) error {
+	req := &pb.DecryptRequest{}
+
+	var ct bytes.Buffer
+
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if req.KeyId == "" {
+			req.KeyId, req.Aad, req.Nonce = chunk.GetKeyId(), chunk.GetAad(), chunk.GetNonce()
+		}
+
+		if err = s.appendChunk("decrypt", &ct, chunk.GetCiphertext()); err != nil {
+			return err
+		}
+	}
+
+	req.Ciphertext = ct.Bytes()
+
+	resp, err := s.Decrypt(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return sendChunks(resp.Plaintext, s.chunkSize, func(chunk []byte, _ bool) error {
+		return stream.Send(&pb.DecryptResponse{Plaintext: chunk})
+	})
+}
+
+// appendChunk appends chunk to the message buf received by the streaming call op, if the message doesn't exceed the
+// maximum message size.
+func (s *Server) appendChunk(op string, buf *bytes.Buffer, chunk []byte) error {
+	if buf.Len()+len(chunk) > s.maxMessageSize {
+		return status.Errorf(codes.ResourceExhausted, "%s: message exceeds the maximum size of %d bytes", op,
+			s.maxMessageSize)
+	}
+
+	buf.Write(chunk)
+
+	return nil
+}
+
+func (s *Server) getKey(op, keyID string) (interface{}, error) {
+	if keyID == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "%s: missing key ID", op)
+	}
+
+	kh, err := s.km.Get(keyID)
+	if err != nil {
+		return nil, toStatus(op, err)
+	}
+
+	return kh, nil
+}
+
+// getPublicKey returns the public key handle of keyID when the KeyManager returns Tink keyset handles.
+func (s *Server) getPublicKey(op, keyID string) (interface{}, error) {
+	kh, err := s.getKey(op, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	ksh, ok := kh.(*keyset.Handle)
+	if !ok {
+		return kh, nil
+	}
+
+	pubKH, err := ksh.Public()
+	if err != nil {
+		return nil, toStatus(op, fmt.Errorf("get public key handle: %w", err))
+	}
+
+	return pubKH, nil
+}
+
+// sendChunks calls send for every chunkSize part of data. send is called at least once, even with empty data.
+func sendChunks(data []byte, chunkSize int, send func(chunk []byte, first bool) error) error {
+	first := true
+
+	for first || len(data) > 0 {
+		n := chunkSize
+		if len(data) < n {
+			n = len(data)
+		}
+
+		if err := send(data[:n], first); err != nil {
+			return err
+		}
+
+		data, first = data[n:], false
+	}
+
+	return nil
+}
+
+func toStatus(op string, err error) error {
+	code := codes.Internal
+	if errors.Is(err, kms.ErrKeyNotFound) {
+		code = codes.NotFound
+	}
+
+	return status.Errorf(code, "%s: %v", op, err)
+}
+
+// isSymmetric returns true if kt is the key type of keys without public key: the symmetric keys and the CL master
+// secrets.
+func isSymmetric(kt kmsapi.KeyType) bool {
+	return kmsapi.IsSymmetric(kt) || kt == kmsapi.CLMasterSecretType
+}
@@ -0,0 +1,214 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package hd provides SLIP-0010 (and BIP-0032 for secp256k1) hierarchical deterministic key derivation from seeds
+// kept in a KMS.
+//
+// A seed is stored in the KMS as an HMAC-SHA512 key: the raw key value is the seed. Derived keys are imported in the
+// same KMS as regular ECDSA secp256k1 or Ed25519 keys: they are used with their key IDs like any other key. The key
+// ID of a derived key only depends on the seed key ID, the key type and the path, so deriving a key again returns
+// the key already imported.
+//
+// Seeds also derive pairwise Ed25519 and X25519 keys with HKDF-SHA256, one per peer identifier (see
+// Manager.DerivePairwiseKey), eg: unlinkable keys of the connections of a wallet recovered from its seed alone.
+//
+// Seed keys should be dedicated to derivation and not used to compute MACs.
+package hd
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	hmacpb "github.com/google/tink/go/proto/hmac_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+
+	"github.com/trustbloc/kms-go/internal/memguard"
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/util/cryptoutil"
+)
+
+const hmacKeyTypeURL = "type.googleapis.com/google.crypto.tink.HmacKey"
+
+// SeedKeyType is the key type of the seed keys.
+const SeedKeyType = kms.HMACSHA512Tag512Type
+
+var errSeedKey = errors.New("seed key is not an HMAC-SHA512 key")
+
+// Manager derives keys from the seeds of a KMS.
+type Manager struct {
+	km kms.KeyManager
+}
+
+// New returns a Manager deriving keys from the seeds of km, and importing the derived keys in km.
+func New(km kms.KeyManager) *Manager {
+	return &Manager{km: km}
+}
+
+// CreateSeed creates a random 64 bytes seed and returns its key ID.
+func (m *Manager) CreateSeed() (string, error) {
+	seedID, _, err := m.km.Create(SeedKeyType)
+	if err != nil {
+		return "", fmt.Errorf("create seed: %w", err)
+	}
+
+	return seedID, nil
+}
+
+// ImportSeed imports seed, which must have between 16 and 64 bytes, and returns its key ID.
+func (m *Manager) ImportSeed(seed []byte, opts ...kms.PrivateKeyOpts) (string, error) {
+	if len(seed) < minSeedSize || len(seed) > maxSeedSize {
+		return "", fmt.Errorf("import seed: seed must have between %d and %d bytes", minSeedSize, maxSeedSize)
+	}
+
+	seedID, _, err := m.km.ImportPrivateKey(seed, SeedKeyType, opts...)
+	if err != nil {
+		return "", fmt.Errorf("import seed: %w", err)
+	}
+
+	return seedID, nil
+}
+
+// Derive derives the key of type kt at path (eg: "m/44'/60'/0'/0/0") from the seed seedID, imports it and returns its
+// key ID and handle. kt is kms.ED25519Type (hardened paths only), kms.ECDSASecp256k1TypeDER,
+// kms.ECDSASecp256k1TypeIEEEP1363 or kms.BIP340Secp256k1Type.
+func (m *Manager) Derive(seedID, path string, kt kms.KeyType) (string, interface{}, error) {
+	curve, err := curveOf(kt)
+	if err != nil {
+		return "", nil, fmt.Errorf("derive: %w", err)
+	}
+
+	p, err := ParsePath(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("derive: %w", err)
+	}
+
+	keyID := DerivedKeyID(seedID, kt, p)
+
+	if kh, e := m.km.Get(keyID); e == nil {
+		return keyID, kh, nil
+	}
+
+	key, err := m.deriveKey(seedID, curve, p)
+	if err != nil {
+		return "", nil, fmt.Errorf("derive: %w", err)
+	}
+
+	defer key.Wipe()
+
+	privKey := privateKey(key)
+
+	keyID, kh, err := m.km.ImportPrivateKey(privKey, kt, kms.WithKeyID(keyID))
+
+	if edKey, ok := privKey.(ed25519.PrivateKey); ok {
+		memguard.Wipe(edKey)
+	}
+
+	if err != nil {
+		return "", nil, fmt.Errorf("derive: import derived key: %w", err)
+	}
+
+	return keyID, kh, nil
+}
+
+// DerivedKeyID returns the key ID of the key of type kt derived from the seed seedID at path p.
+func DerivedKeyID(seedID string, kt kms.KeyType, p Path) string {
+	h := sha256.New()
+	h.Write(cryptoutil.LengthPrefix([]byte("kms-go hd v1")))
+	h.Write(cryptoutil.LengthPrefix([]byte(seedID)))
+	h.Write(cryptoutil.LengthPrefix([]byte(kt)))
+	h.Write(cryptoutil.LengthPrefix([]byte(p.String())))
+
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+func (m *Manager) deriveKey(seedID string, curve Curve, p Path) (*ExtendedKey, error) {
+	kh, err := m.km.Get(seedID)
+	if err != nil {
+		return nil, fmt.Errorf("get seed: %w", err)
+	}
+
+	seed, err := seedOf(kh)
+	if err != nil {
+		return nil, err
+	}
+
+	defer memguard.Wipe(seed)
+
+	master, err := NewMasterKey(curve, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	defer master.Wipe()
+
+	return master.Derive(p)
+}
+
+// seedOf returns a copy of the raw key value of the primary HMAC-SHA512 key of kh.
+func seedOf(kh interface{}) ([]byte, error) {
+	handle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errSeedKey
+	}
+
+	ks := insecurecleartextkeyset.KeysetMaterial(handle)
+
+	for _, k := range ks.Key {
+		if k.KeyId != ks.PrimaryKeyId || k.Status != tinkpb.KeyStatusType_ENABLED {
+			continue
+		}
+
+		if k.KeyData.TypeUrl != hmacKeyTypeURL {
+			return nil, errSeedKey
+		}
+
+		key := &hmacpb.HmacKey{}
+
+		// the key data belongs to the handle, which may be cached: only the unmarshalled copy is wiped by callers.
+		if err := proto.Unmarshal(k.KeyData.Value, key); err != nil || key.Params.GetHash() != commonpb.HashType_SHA512 {
+			return nil, errSeedKey
+		}
+
+		return key.KeyValue, nil
+	}
+
+	return nil, errSeedKey
+}
+
+func curveOf(kt kms.KeyType) (Curve, error) {
+	switch kt { //nolint:exhaustive
+	case kms.ED25519Type:
+		return Ed25519, nil
+	case kms.ECDSASecp256k1TypeDER, kms.ECDSASecp256k1TypeIEEEP1363, kms.BIP340Secp256k1Type:
+		return Secp256k1, nil
+	default:
+		return 0, fmt.Errorf("key type '%s' is not supported", kt)
+	}
+}
+
+// privateKey returns the private key of key, in the format of the KMS private key import.
+func privateKey(key *ExtendedKey) interface{} {
+	if key.Curve == Ed25519 {
+		return ed25519.NewKeyFromSeed(key.Key)
+	}
+
+	priv, pub := btcec.PrivKeyFromBytes(key.Key)
+
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{Curve: btcec.S256(), X: pub.X(), Y: pub.Y()},
+		D:         new(big.Int).SetBytes(priv.Serialize()),
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package hd
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/google/tink/go/keyset"
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func TestManager_Derive(t *testing.T) {
+	km := mockkms.NewForTest(t)
+	m := New(km)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	seed, err := hex.DecodeString(testSeed)
+	require.NoError(t, err)
+
+	seedID, err := m.ImportSeed(seed)
+	require.NoError(t, err)
+
+	t.Run("secp256k1", func(t *testing.T) {
+		keyID, kh, err := m.Derive(seedID, "m/0'/1", kmsapi.ECDSASecp256k1TypeIEEEP1363)
+		require.NoError(t, err)
+		require.Equal(t, DerivedKeyID(seedID, kmsapi.ECDSASecp256k1TypeIEEEP1363, Path{HardenedOffset, 1}), keyID)
+
+		priv, err := hex.DecodeString("3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368")
+		require.NoError(t, err)
+
+		_, pub := btcec.PrivKeyFromBytes(priv)
+
+		pubKey, _, err := km.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+		require.Equal(t, pub.SerializeUncompressed(), pubKey)
+
+		sig, err := cr.Sign([]byte("message"), kh)
+		require.NoError(t, err)
+
+		pubKH, err := kh.(*keyset.Handle).Public()
+		require.NoError(t, err)
+		require.NoError(t, cr.Verify(sig, []byte("message"), pubKH))
+
+		// the BIP-340 key of the path has the x-only public key of the secp256k1 key.
+		bip340ID, _, err := m.Derive(seedID, "m/0'/1", kmsapi.BIP340Secp256k1Type)
+		require.NoError(t, err)
+		require.NotEqual(t, keyID, bip340ID)
+
+		xOnly, _, err := km.ExportPubKeyBytes(bip340ID)
+		require.NoError(t, err)
+		require.Equal(t, pub.SerializeCompressed()[1:], xOnly)
+	})
+
+	t.Run("ed25519", func(t *testing.T) {
+		keyID, _, err := m.Derive(seedID, "m/0'", kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		priv, err := hex.DecodeString("68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3")
+		require.NoError(t, err)
+
+		pubKey, _, err := km.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+		require.Equal(t, []byte(ed25519.NewKeyFromSeed(priv).Public().(ed25519.PublicKey)), pubKey)
+	})
+
+	t.Run("derived again", func(t *testing.T) {
+		keyID1, _, err := m.Derive(seedID, "m/44'/0'/0'/0/5", kmsapi.ECDSASecp256k1TypeDER)
+		require.NoError(t, err)
+
+		keyID2, _, err := m.Derive(seedID, "m/44h/0h/0h/0/5", kmsapi.ECDSASecp256k1TypeDER)
+		require.NoError(t, err)
+		require.Equal(t, keyID1, keyID2)
+
+		keyID3, _, err := m.Derive(seedID, "m/44'/0'/0'/0/5", kmsapi.ECDSASecp256k1TypeIEEEP1363)
+		require.NoError(t, err)
+		require.NotEqual(t, keyID1, keyID3)
+	})
+
+	t.Run("created seed", func(t *testing.T) {
+		createdSeedID, err := m.CreateSeed()
+		require.NoError(t, err)
+
+		keyID, _, err := m.Derive(createdSeedID, "m/0'", kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		otherKeyID, _, err := m.Derive(seedID, "m/0'", kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		pub1, _, err := km.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+
+		pub2, _, err := km.ExportPubKeyBytes(otherKeyID)
+		require.NoError(t, err)
+		require.NotEqual(t, pub1, pub2)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := m.ImportSeed(make([]byte, 65))
+		require.EqualError(t, err, "import seed: seed must have between 16 and 64 bytes")
+
+		_, _, err = m.Derive(seedID, "m/0'", kmsapi.ECDSAP256TypeDER)
+		require.EqualError(t, err, "derive: key type 'ECDSAP256DER' is not supported")
+
+		_, _, err = m.Derive(seedID, "0'", kmsapi.ED25519Type)
+		require.EqualError(t, err, "derive: parse path '0'': path must start with 'm'")
+
+		_, _, err = m.Derive(seedID, "m/0'/1", kmsapi.ED25519Type)
+		require.EqualError(t, err, "derive: derive child key 1: ed25519 only supports hardened derivation")
+
+		_, _, err = m.Derive("unknown", "m/0'", kmsapi.ED25519Type)
+		require.ErrorContains(t, err, "derive: get seed")
+
+		edKeyID, _, err := km.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		_, _, err = m.Derive(edKeyID, "m/0'", kmsapi.ED25519Type)
+		require.EqualError(t, err, "derive: seed key is not an HMAC-SHA512 key")
+
+		hmacKeyID, _, err := km.Create(kmsapi.HMACSHA256Tag256Type)
+		require.NoError(t, err)
+
+		_, _, err = m.Derive(hmacKeyID, "m/0'", kmsapi.ED25519Type)
+		require.EqualError(t, err, "derive: seed key is not an HMAC-SHA512 key")
+	})
+}
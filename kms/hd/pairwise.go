@@ -0,0 +1,117 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package hd
+
+import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/trustbloc/kms-go/internal/memguard"
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/util/cryptoutil"
+)
+
+const (
+	pairwiseLabel   = "kms-go pairwise v1"
+	pairwiseKeySize = 32
+)
+
+// DerivePairwiseKey derives the pairwise key of type kt of the peer identifier peer (eg: the DID of a connection)
+// from the seed seedID with HKDF-SHA256, imports it and returns its key ID and handle. kt is kms.ED25519Type or
+// kms.X25519ECDHKWType.
+//
+// The keys of distinct peers are unlinkable without the seed, and the key of a peer is derived again from the seed
+// instead of being backed up: its key ID only depends on the seed key ID, the key type and the peer, so deriving it
+// again returns the key already imported.
+func (m *Manager) DerivePairwiseKey(seedID, peer string, kt kms.KeyType) (string, interface{}, error) {
+	if kt != kms.ED25519Type && kt != kms.X25519ECDHKWType {
+		return "", nil, fmt.Errorf("derive pairwise key: key type '%s' is not supported", kt)
+	}
+
+	if peer == "" {
+		return "", nil, errors.New("derive pairwise key: empty peer identifier")
+	}
+
+	keyID := PairwiseKeyID(seedID, kt, peer)
+
+	if kh, e := m.km.Get(keyID); e == nil {
+		return keyID, kh, nil
+	}
+
+	key, err := m.derivePairwiseKey(seedID, peer, kt)
+	if err != nil {
+		return "", nil, fmt.Errorf("derive pairwise key: %w", err)
+	}
+
+	defer memguard.Wipe(key)
+
+	var privKey interface{}
+
+	if kt == kms.ED25519Type {
+		edKey := ed25519.NewKeyFromSeed(key)
+		defer memguard.Wipe(edKey)
+
+		privKey = edKey
+	} else {
+		privKey, err = ecdh.X25519().NewPrivateKey(key)
+		if err != nil {
+			return "", nil, fmt.Errorf("derive pairwise key: %w", err)
+		}
+	}
+
+	keyID, kh, err := m.km.ImportPrivateKey(privKey, kt, kms.WithKeyID(keyID))
+	if err != nil {
+		return "", nil, fmt.Errorf("derive pairwise key: import derived key: %w", err)
+	}
+
+	return keyID, kh, nil
+}
+
+// PairwiseKeyID returns the key ID of the pairwise key of type kt of peer derived from the seed seedID.
+func PairwiseKeyID(seedID string, kt kms.KeyType, peer string) string {
+	h := sha256.New()
+	h.Write(cryptoutil.LengthPrefix([]byte(pairwiseLabel)))
+	h.Write(cryptoutil.LengthPrefix([]byte(seedID)))
+	h.Write(cryptoutil.LengthPrefix([]byte(kt)))
+	h.Write(cryptoutil.LengthPrefix([]byte(peer)))
+
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// derivePairwiseKey returns the 32 bytes HKDF-SHA256 output of the seed seedID, with the label, key type and peer as
+// info: the Ed25519 seed or X25519 private key of the pairwise key.
+func (m *Manager) derivePairwiseKey(seedID, peer string, kt kms.KeyType) ([]byte, error) {
+	kh, err := m.km.Get(seedID)
+	if err != nil {
+		return nil, fmt.Errorf("get seed: %w", err)
+	}
+
+	seed, err := seedOf(kh)
+	if err != nil {
+		return nil, err
+	}
+
+	defer memguard.Wipe(seed)
+
+	info := append(cryptoutil.LengthPrefix([]byte(pairwiseLabel)), cryptoutil.LengthPrefix([]byte(kt))...)
+	info = append(info, cryptoutil.LengthPrefix([]byte(peer))...)
+
+	key := make([]byte, pairwiseKeySize)
+
+	if _, err = io.ReadFull(hkdf.New(sha256.New, seed, nil, info), key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package hd
+
+import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/hkdf"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+	"github.com/trustbloc/kms-go/util/cryptoutil"
+)
+
+func TestManager_DerivePairwiseKey(t *testing.T) {
+	km := mockkms.NewForTest(t)
+	m := New(km)
+
+	seed, err := hex.DecodeString(testSeed)
+	require.NoError(t, err)
+
+	seedID, err := m.ImportSeed(seed)
+	require.NoError(t, err)
+
+	hkdfKey := func(t *testing.T, kt kmsapi.KeyType, peer string) []byte {
+		t.Helper()
+
+		info := append(cryptoutil.LengthPrefix([]byte(pairwiseLabel)), cryptoutil.LengthPrefix([]byte(kt))...)
+		info = append(info, cryptoutil.LengthPrefix([]byte(peer))...)
+
+		key := make([]byte, pairwiseKeySize)
+
+		_, err := io.ReadFull(hkdf.New(sha256.New, seed, nil, info), key)
+		require.NoError(t, err)
+
+		return key
+	}
+
+	t.Run("ed25519", func(t *testing.T) {
+		keyID, _, err := m.DerivePairwiseKey(seedID, "did:example:alice", kmsapi.ED25519Type)
+		require.NoError(t, err)
+		require.Equal(t, PairwiseKeyID(seedID, kmsapi.ED25519Type, "did:example:alice"), keyID)
+
+		pubKey, _, err := km.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+
+		expected := ed25519.NewKeyFromSeed(hkdfKey(t, kmsapi.ED25519Type, "did:example:alice")).Public()
+		require.Equal(t, []byte(expected.(ed25519.PublicKey)), pubKey)
+	})
+
+	t.Run("x25519", func(t *testing.T) {
+		keyID, _, err := m.DerivePairwiseKey(seedID, "did:example:alice", kmsapi.X25519ECDHKWType)
+		require.NoError(t, err)
+
+		pubKeyBytes, _, err := km.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+
+		pubKey := &cryptoapi.PublicKey{}
+		require.NoError(t, json.Unmarshal(pubKeyBytes, pubKey))
+
+		expected, err := ecdh.X25519().NewPrivateKey(hkdfKey(t, kmsapi.X25519ECDHKWType, "did:example:alice"))
+		require.NoError(t, err)
+		require.Equal(t, expected.PublicKey().Bytes(), pubKey.X)
+	})
+
+	t.Run("derived again", func(t *testing.T) {
+		keyID1, _, err := m.DerivePairwiseKey(seedID, "did:example:bob", kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		keyID2, _, err := m.DerivePairwiseKey(seedID, "did:example:bob", kmsapi.ED25519Type)
+		require.NoError(t, err)
+		require.Equal(t, keyID1, keyID2)
+
+		// the key of the same seed in another KMS is the same key.
+		other := New(mockkms.NewForTest(t))
+
+		otherSeedID, err := other.ImportSeed(seed)
+		require.NoError(t, err)
+
+		keyID3, _, err := other.DerivePairwiseKey(otherSeedID, "did:example:bob", kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		pub1, _, err := km.ExportPubKeyBytes(keyID1)
+		require.NoError(t, err)
+
+		pub3, _, err := other.km.ExportPubKeyBytes(keyID3)
+		require.NoError(t, err)
+		require.Equal(t, pub1, pub3)
+
+		// other peers have unrelated keys.
+		keyID4, _, err := m.DerivePairwiseKey(seedID, "did:example:carol", kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		pub4, _, err := km.ExportPubKeyBytes(keyID4)
+		require.NoError(t, err)
+		require.NotEqual(t, pub1, pub4)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, _, err := m.DerivePairwiseKey(seedID, "did:example:alice", kmsapi.ECDSAP256TypeDER)
+		require.EqualError(t, err, "derive pairwise key: key type 'ECDSAP256DER' is not supported")
+
+		_, _, err = m.DerivePairwiseKey(seedID, "", kmsapi.ED25519Type)
+		require.EqualError(t, err, "derive pairwise key: empty peer identifier")
+
+		_, _, err = m.DerivePairwiseKey("unknown", "did:example:alice", kmsapi.ED25519Type)
+		require.ErrorContains(t, err, "derive pairwise key: get seed")
+
+		edKeyID, _, err := km.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		_, _, err = m.DerivePairwiseKey(edKeyID, "did:example:alice", kmsapi.ED25519Type)
+		require.EqualError(t, err, "derive pairwise key: seed key is not an HMAC-SHA512 key")
+	})
+}
@@ -0,0 +1,248 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package hd
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+
+	"github.com/trustbloc/kms-go/internal/memguard"
+)
+
+// HardenedOffset is the index of the first hardened child key.
+const HardenedOffset uint32 = 0x80000000
+
+const (
+	keySize       = 32
+	minSeedSize   = 16
+	maxSeedSize   = 64
+	indexSize     = 4
+	hardenedByte  = 0x00
+	retryByte     = 0x01
+	pathSeparator = "/"
+)
+
+// Curve is a SLIP-0010 curve.
+type Curve int
+
+const (
+	// Secp256k1 is the secp256k1 curve, its derivation is compatible with BIP-0032.
+	Secp256k1 Curve = iota + 1
+	// Ed25519 is the ed25519 curve, it only supports hardened derivation.
+	Ed25519
+)
+
+// String returns the SLIP-0010 name of c.
+func (c Curve) String() string {
+	switch c {
+	case Secp256k1:
+		return "secp256k1"
+	case Ed25519:
+		return "ed25519"
+	default:
+		return fmt.Sprintf("Curve(%d)", int(c))
+	}
+}
+
+// masterHMACKey returns the HMAC key of the master key generation of c.
+func (c Curve) masterHMACKey() ([]byte, error) {
+	switch c {
+	case Secp256k1:
+		return []byte("Bitcoin seed"), nil
+	case Ed25519:
+		return []byte("ed25519 seed"), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve '%s'", c)
+	}
+}
+
+// Path is a derivation path: the child indexes from the master key, hardened indexes are HardenedOffset or greater.
+type Path []uint32
+
+// ParsePath parses a derivation path such as "m/44'/0'/0'/0/1". Hardened indexes are suffixed with ', h or H.
+func ParsePath(s string) (Path, error) {
+	elems := strings.Split(s, pathSeparator)
+	if elems[0] != "m" {
+		return nil, fmt.Errorf("parse path '%s': path must start with 'm'", s)
+	}
+
+	p := make(Path, 0, len(elems)-1)
+
+	for _, elem := range elems[1:] {
+		var offset uint32
+
+		if trimmed := strings.TrimRight(elem, "'hH"); len(trimmed) == len(elem)-1 {
+			elem, offset = trimmed, HardenedOffset
+		}
+
+		i, err := strconv.ParseUint(elem, 10, 31) //nolint:gomnd
+		if err != nil {
+			return nil, fmt.Errorf("parse path '%s': invalid index '%s'", s, elem)
+		}
+
+		p = append(p, uint32(i)+offset)
+	}
+
+	return p, nil
+}
+
+// String returns the path in the "m/44'/0'/0'/0/1" format.
+func (p Path) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("m")
+
+	for _, i := range p {
+		sb.WriteString(pathSeparator)
+
+		if i >= HardenedOffset {
+			sb.WriteString(strconv.FormatUint(uint64(i-HardenedOffset), 10))
+			sb.WriteString("'")
+
+			continue
+		}
+
+		sb.WriteString(strconv.FormatUint(uint64(i), 10))
+	}
+
+	return sb.String()
+}
+
+// ExtendedKey is a SLIP-0010 private key with its chain code.
+type ExtendedKey struct {
+	Curve     Curve
+	Key       []byte
+	ChainCode []byte
+}
+
+// NewMasterKey derives the master key of curve c from seed, which must have between 16 and 64 bytes.
+func NewMasterKey(c Curve, seed []byte) (*ExtendedKey, error) {
+	hmacKey, err := c.masterHMACKey()
+	if err != nil {
+		return nil, fmt.Errorf("new master key: %w", err)
+	}
+
+	if len(seed) < minSeedSize || len(seed) > maxSeedSize {
+		return nil, fmt.Errorf("new master key: seed must have between %d and %d bytes", minSeedSize, maxSeedSize)
+	}
+
+	data := seed
+
+	for {
+		i := hmacSHA512(hmacKey, data)
+
+		if c == Ed25519 || validScalar(i[:keySize]) {
+			return &ExtendedKey{Curve: c, Key: i[:keySize], ChainCode: i[keySize:]}, nil
+		}
+
+		// SLIP-0010: an invalid secp256k1 key is derived again from the HMAC output.
+		data = i
+	}
+}
+
+// Derive derives the descendant key of k at path p.
+func (k *ExtendedKey) Derive(p Path) (*ExtendedKey, error) {
+	key := &ExtendedKey{
+		Curve:     k.Curve,
+		Key:       append([]byte(nil), k.Key...),
+		ChainCode: append([]byte(nil), k.ChainCode...),
+	}
+
+	for _, i := range p {
+		child, err := key.Child(i)
+		key.Wipe()
+
+		if err != nil {
+			return nil, err
+		}
+
+		key = child
+	}
+
+	return key, nil
+}
+
+// Child derives the child key of k at index i.
+func (k *ExtendedKey) Child(i uint32) (*ExtendedKey, error) {
+	data := make([]byte, 0, 1+keySize+indexSize+1)
+
+	switch {
+	case i >= HardenedOffset:
+		data = append(append(data, hardenedByte), k.Key...)
+	case k.Curve == Ed25519:
+		return nil, fmt.Errorf("derive child key %d: ed25519 only supports hardened derivation", i)
+	default:
+		_, pub := btcec.PrivKeyFromBytes(k.Key)
+		data = append(data, pub.SerializeCompressed()...)
+	}
+
+	data = binary.BigEndian.AppendUint32(data, i)
+
+	defer memguard.Wipe(data)
+
+	for {
+		h := hmacSHA512(k.ChainCode, data)
+
+		if k.Curve == Ed25519 {
+			return &ExtendedKey{Curve: k.Curve, Key: h[:keySize], ChainCode: h[keySize:]}, nil
+		}
+
+		if child, ok := addScalars(h[:keySize], k.Key); ok {
+			memguard.Wipe(h[:keySize])
+
+			return &ExtendedKey{Curve: k.Curve, Key: child, ChainCode: h[keySize:]}, nil
+		}
+
+		// SLIP-0010: an invalid secp256k1 child key is derived again from the right half of the HMAC output.
+		data = binary.BigEndian.AppendUint32(append(append(data[:0], retryByte), h[keySize:]...), i)
+	}
+}
+
+// Wipe overwrites the key and the chain code of k with zeros.
+func (k *ExtendedKey) Wipe() {
+	memguard.Wipe(k.Key, k.ChainCode)
+}
+
+func hmacSHA512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data) // nolint:errcheck // hash writes don't fail
+
+	return mac.Sum(nil)
+}
+
+// validScalar returns true if b is a valid secp256k1 private key: 0 < b < n.
+func validScalar(b []byte) bool {
+	v := new(big.Int).SetBytes(b)
+
+	return v.Sign() > 0 && v.Cmp(btcec.S256().N) < 0
+}
+
+// addScalars returns il + key mod n, or false if il is not lower than n or the sum is 0.
+func addScalars(il, key []byte) ([]byte, bool) {
+	n := btcec.S256().N
+
+	v := new(big.Int).SetBytes(il)
+	if v.Cmp(n) >= 0 {
+		return nil, false
+	}
+
+	v.Add(v, new(big.Int).SetBytes(key))
+	v.Mod(v, n)
+
+	if v.Sign() == 0 {
+		return nil, false
+	}
+
+	return v.FillBytes(make([]byte, keySize)), true
+}
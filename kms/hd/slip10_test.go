@@ -0,0 +1,120 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package hd
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test vector 1 of SLIP-0010 (https://github.com/satoshilabs/slips/blob/master/slip-0010.md), the secp256k1 keys are
+// the BIP-0032 test vector 1 keys.
+const testSeed = "000102030405060708090a0b0c0d0e0f"
+
+func TestDerive_Vectors(t *testing.T) {
+	seed, err := hex.DecodeString(testSeed)
+	require.NoError(t, err)
+
+	tests := []struct {
+		curve     Curve
+		path      string
+		chainCode string
+		key       string
+	}{
+		{
+			curve:     Secp256k1,
+			path:      "m",
+			chainCode: "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508",
+			key:       "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35",
+		},
+		{
+			curve:     Secp256k1,
+			path:      "m/0'",
+			chainCode: "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141",
+			key:       "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+		},
+		{
+			curve:     Secp256k1,
+			path:      "m/0'/1",
+			chainCode: "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19",
+			key:       "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+		},
+		{
+			curve: Secp256k1,
+			path:  "m/0H/1/2H/2/1000000000",
+			key:   "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8",
+		},
+		{
+			curve:     Ed25519,
+			path:      "m",
+			chainCode: "90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb",
+			key:       "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7",
+		},
+		{
+			curve:     Ed25519,
+			path:      "m/0'",
+			chainCode: "8b59aa11380b624e81507a27fedda59fea6d0b779a778918a2fd3590e16e9c69",
+			key:       "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3",
+		},
+		{
+			curve: Ed25519,
+			path:  "m/0h/1h/2h/2h/1000000000h",
+			key:   "8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.curve.String()+" "+tc.path, func(t *testing.T) {
+			master, err := NewMasterKey(tc.curve, seed)
+			require.NoError(t, err)
+
+			p, err := ParsePath(tc.path)
+			require.NoError(t, err)
+
+			key, err := master.Derive(p)
+			require.NoError(t, err)
+			require.Equal(t, tc.key, hex.EncodeToString(key.Key))
+
+			if tc.chainCode != "" {
+				require.Equal(t, tc.chainCode, hex.EncodeToString(key.ChainCode))
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewMasterKey(Curve(0), seed)
+		require.EqualError(t, err, "new master key: unsupported curve 'Curve(0)'")
+
+		_, err = NewMasterKey(Ed25519, seed[:15])
+		require.EqualError(t, err, "new master key: seed must have between 16 and 64 bytes")
+
+		master, err := NewMasterKey(Ed25519, seed)
+		require.NoError(t, err)
+
+		_, err = master.Derive(Path{HardenedOffset, 1})
+		require.EqualError(t, err, "derive child key 1: ed25519 only supports hardened derivation")
+	})
+}
+
+func TestParsePath(t *testing.T) {
+	p, err := ParsePath("m/44'/60h/0H/0/2147483647")
+	require.NoError(t, err)
+	require.Equal(t, Path{44 + HardenedOffset, 60 + HardenedOffset, HardenedOffset, 0, 2147483647}, p)
+	require.Equal(t, "m/44'/60'/0'/0/2147483647", p.String())
+
+	p, err = ParsePath("m")
+	require.NoError(t, err)
+	require.Empty(t, p)
+	require.Equal(t, "m", p.String())
+
+	for _, path := range []string{"", "44'/0", "m/", "m/-1", "m/2147483648", "m/1''", "m/a", "m//1"} {
+		_, err = ParsePath(path)
+		require.Error(t, err, path)
+	}
+}
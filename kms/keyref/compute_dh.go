@@ -0,0 +1,80 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyref
+
+import (
+	"errors"
+	"fmt"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+// ErrComputeDHDisabled is returned by ComputeDH when the Manager Policy doesn't allow kmsapi.OperationComputeDH for the
+// key, or when the Manager has no Policy. It is cryptoapi.ErrComputeDHDisabled, also returned by the Manager crypto
+// when it doesn't enable raw shared secrets.
+var ErrComputeDHDisabled = cryptoapi.ErrComputeDHDisabled
+
+// AllowComputeDH returns a Policy allowing all the operations of every key and kmsapi.OperationComputeDH for the keys
+// keyIDs only.
+func AllowComputeDH(keyIDs ...string) Policy {
+	allowed := make(map[string]struct{}, len(keyIDs))
+
+	for _, kid := range keyIDs {
+		allowed[kid] = struct{}{}
+	}
+
+	return PolicyFunc(func(op kmsapi.Operation, keyID string, _ kmsapi.KeyType) error {
+		if op != kmsapi.OperationComputeDH {
+			return nil
+		}
+
+		if _, ok := allowed[keyID]; !ok {
+			return ErrComputeDHDisabled
+		}
+
+		return nil
+	})
+}
+
+// ComputeECDH computes the raw ECDH shared secret of the private key privKeyID and remotePubKey, see KeyRef.ComputeDH.
+func (m *Manager) ComputeECDH(privKeyID string, remotePubKey *cryptoapi.PublicKey) ([]byte, error) {
+	ref, err := m.Get(privKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ref.ComputeDH(remotePubKey)
+}
+
+// ComputeDH computes the raw ECDH shared secret of the key, an ECDH KW key, and remotePubKey with the
+// cryptoapi.DHComputer of the Manager crypto, for protocols running their own key schedule (eg: Noise or libp2p).
+//
+// Raw shared secrets bypass the key derivation and key wrapping of WrapFor and UnwrapKey, so ComputeDH is disabled by
+// default: it is only executed when the Manager has a Policy allowing kmsapi.OperationComputeDH for the key (see
+// AllowComputeDH), a nil Policy allowing every other operation doesn't enable it.
+func (r *KeyRef) ComputeDH(remotePubKey *cryptoapi.PublicKey) ([]byte, error) {
+	if r.m.policy == nil {
+		return nil, fmt.Errorf("keyref: computeDH: %w", ErrComputeDHDisabled)
+	}
+
+	if err := r.check(kmsapi.OperationComputeDH); err != nil {
+		return nil, fmt.Errorf("keyref: computeDH: %w", err)
+	}
+
+	dh, ok := r.m.crypto.(cryptoapi.DHComputer)
+	if !ok {
+		return nil, errors.New("keyref: computeDH: crypto doesn't support computeDH")
+	}
+
+	z, err := dh.ComputeDH(r.kh, remotePubKey)
+	if err != nil {
+		return nil, fmt.Errorf("keyref: computeDH: %w", err)
+	}
+
+	return z, nil
+}
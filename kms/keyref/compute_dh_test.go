@@ -0,0 +1,87 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyref
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+func exportECDHKey(t *testing.T, m *Manager, kid string) *cryptoapi.PublicKey {
+	t.Helper()
+
+	b, _, err := m.km.ExportPubKeyBytes(kid)
+	require.NoError(t, err)
+
+	pub := &cryptoapi.PublicKey{}
+	require.NoError(t, json.Unmarshal(b, pub))
+
+	return pub
+}
+
+func TestComputeDH(t *testing.T) {
+	setup := newManager(t)
+
+	alice, err := setup.Create(kmsapi.X25519ECDHKWType)
+	require.NoError(t, err)
+
+	bob, err := setup.Create(kmsapi.X25519ECDHKWType)
+	require.NoError(t, err)
+
+	alicePub := exportECDHKey(t, setup, alice.KID())
+	bobPub := exportECDHKey(t, setup, bob.KID())
+
+	t.Run("disabled by default", func(t *testing.T) {
+		_, err := alice.ComputeDH(bobPub)
+		require.ErrorIs(t, err, ErrComputeDHDisabled)
+	})
+
+	t.Run("enabled per key", func(t *testing.T) {
+		m := New(setup.km, setup.crypto, WithPolicy(AllowComputeDH(alice.KID(), bob.KID())))
+
+		z1, err := m.ComputeECDH(alice.KID(), bobPub)
+		require.NoError(t, err)
+
+		z2, err := m.ComputeECDH(bob.KID(), alicePub)
+		require.NoError(t, err)
+		require.Equal(t, z1, z2)
+
+		m = New(setup.km, setup.crypto, WithPolicy(AllowComputeDH(alice.KID())))
+
+		_, err = m.ComputeECDH(bob.KID(), alicePub)
+		require.ErrorIs(t, err, ErrComputeDHDisabled)
+
+		ref, err := m.Get(bob.KID())
+		require.NoError(t, err)
+
+		// other operations are still allowed.
+		_, err = ref.ExportJWK()
+		require.NoError(t, err)
+
+		_, err = m.ComputeECDH("missing", bobPub)
+		require.ErrorContains(t, err, "keyref: get")
+
+		_, err = m.ComputeECDH(alice.KID(), nil)
+		require.EqualError(t, err, "keyref: computeDH: computeDH: public key is nil")
+	})
+
+	t.Run("crypto without DHComputer", func(t *testing.T) {
+		m := New(setup.km, &noDHCrypto{Crypto: setup.crypto}, WithPolicy(AllowComputeDH(alice.KID())))
+
+		_, err := m.ComputeECDH(alice.KID(), bobPub)
+		require.EqualError(t, err, "keyref: computeDH: crypto doesn't support computeDH")
+	})
+}
+
+type noDHCrypto struct {
+	cryptoapi.Crypto
+}
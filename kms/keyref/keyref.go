@@ -0,0 +1,274 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package keyref provides key reference objects binding a KMS key to its crypto operations: a KeyRef, returned by
+// Manager.Create and Manager.Get, signs, verifies, encrypts, wraps keys, exports its public key and rotates without
+// passing key IDs and key handles around. Every KeyRef operation is checked by the Manager Policy, if set, allowing
+// per key and per operation restrictions on top of the flat kmsapi.KeyManager and cryptoapi.Crypto interfaces. Raw ECDH
+// shared secrets (KeyRef.ComputeDH) are only computed for the keys the Policy explicitly enables.
+//
+// CreateSigningKey and CreateAEADKey return typed key references exposing only the operations of their key template,
+// eg: CreateSigningKey[keyref.ECDSAP256](m) has no Encrypt method, so that using a key for the wrong operation is
+// caught at compile time.
+package keyref
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/doc/util/pubkeyfmt"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+// Policy checks op can be executed with the key keyID of type kt. keyID is empty for kmsapi.OperationCreate and kt is
+// empty when the key type of a key is unknown (eg: symmetric keys returned by Manager.Get).
+type Policy interface {
+	Check(op kmsapi.Operation, keyID string, kt kmsapi.KeyType) error
+}
+
+// PolicyFunc is a function implementing Policy.
+type PolicyFunc func(op kmsapi.Operation, keyID string, kt kmsapi.KeyType) error
+
+// Check calls f.
+func (f PolicyFunc) Check(op kmsapi.Operation, keyID string, kt kmsapi.KeyType) error {
+	return f(op, keyID, kt)
+}
+
+// Opt is a Manager option.
+type Opt func(m *Manager)
+
+// WithPolicy sets the Policy checking the Manager and KeyRef operations.
+func WithPolicy(policy Policy) Opt {
+	return func(m *Manager) {
+		m.policy = policy
+	}
+}
+
+// Manager creates and gets KeyRef instances of the keys of a kmsapi.KeyManager running their operations with a
+// cryptoapi.Crypto.
+type Manager struct {
+	km     kmsapi.KeyManager
+	crypto cryptoapi.Crypto
+	policy Policy
+}
+
+// New creates a new Manager of the keys of km using crypto.
+func New(km kmsapi.KeyManager, crypto cryptoapi.Crypto, opts ...Opt) *Manager {
+	m := &Manager{km: km, crypto: crypto}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// Create creates a new key of type kt and returns its KeyRef.
+func (m *Manager) Create(kt kmsapi.KeyType, opts ...kmsapi.KeyOpts) (*KeyRef, error) {
+	if err := m.check(kmsapi.OperationCreate, "", kt); err != nil {
+		return nil, fmt.Errorf("keyref: create: %w", err)
+	}
+
+	kid, kh, err := m.km.Create(kt, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("keyref: create: %w", err)
+	}
+
+	return &KeyRef{m: m, kid: kid, kt: kt, kh: kh}, nil
+}
+
+// Get returns the KeyRef of the key keyID. The key type of asymmetric keys is read from their public key, it is empty
+// for symmetric keys.
+func (m *Manager) Get(keyID string) (*KeyRef, error) {
+	kh, err := m.km.Get(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("keyref: get: %w", err)
+	}
+
+	ref := &KeyRef{m: m, kid: keyID, kh: kh}
+
+	if _, kt, e := m.km.ExportPubKeyBytes(keyID); e == nil {
+		ref.kt = kt
+	}
+
+	return ref, nil
+}
+
+func (m *Manager) check(op kmsapi.Operation, keyID string, kt kmsapi.KeyType) error {
+	if m.policy == nil {
+		return nil
+	}
+
+	return m.policy.Check(op, keyID, kt)
+}
+
+// KeyRef is a reference to a KMS key exposing the operations bound to that key.
+type KeyRef struct {
+	m   *Manager
+	kid string
+	kt  kmsapi.KeyType
+	kh  interface{}
+}
+
+// KID returns the key ID of the key.
+func (r *KeyRef) KID() string {
+	return r.kid
+}
+
+// KeyType returns the key type of the key, it is empty if unknown.
+func (r *KeyRef) KeyType() kmsapi.KeyType {
+	return r.kt
+}
+
+// Sign signs msg with the key.
+func (r *KeyRef) Sign(msg []byte) ([]byte, error) {
+	if err := r.check(kmsapi.OperationSign); err != nil {
+		return nil, fmt.Errorf("keyref: sign: %w", err)
+	}
+
+	sig, err := r.m.crypto.Sign(msg, r.kh)
+	if err != nil {
+		return nil, fmt.Errorf("keyref: sign: %w", err)
+	}
+
+	return sig, nil
+}
+
+// Verify verifies sig is a signature of msg by the key.
+func (r *KeyRef) Verify(sig, msg []byte) error {
+	if err := r.check(kmsapi.OperationVerify); err != nil {
+		return fmt.Errorf("keyref: verify: %w", err)
+	}
+
+	pubKH, err := r.publicKeyHandle()
+	if err != nil {
+		return fmt.Errorf("keyref: verify: %w", err)
+	}
+
+	if err = r.m.crypto.Verify(sig, msg, pubKH); err != nil {
+		return fmt.Errorf("keyref: verify: %w", err)
+	}
+
+	return nil
+}
+
+// Encrypt encrypts msg with aad using the key, it returns the ciphertext and its nonce.
+func (r *KeyRef) Encrypt(msg, aad []byte) ([]byte, []byte, error) {
+	if err := r.check(kmsapi.OperationEncrypt); err != nil {
+		return nil, nil, fmt.Errorf("keyref: encrypt: %w", err)
+	}
+
+	ct, nonce, err := r.m.crypto.Encrypt(msg, aad, r.kh)
+	if err != nil {
+		return nil, nil, fmt.Errorf("keyref: encrypt: %w", err)
+	}
+
+	return ct, nonce, nil
+}
+
+// Decrypt decrypts cipher encrypted by Encrypt with aad and nonce.
+func (r *KeyRef) Decrypt(cipher, aad, nonce []byte) ([]byte, error) {
+	if err := r.check(kmsapi.OperationDecrypt); err != nil {
+		return nil, fmt.Errorf("keyref: decrypt: %w", err)
+	}
+
+	pt, err := r.m.crypto.Decrypt(cipher, aad, nonce, r.kh)
+	if err != nil {
+		return nil, fmt.Errorf("keyref: decrypt: %w", err)
+	}
+
+	return pt, nil
+}
+
+// WrapFor wraps cek for the recipient key with the key as sender (ECDH-1PU key wrapping), the key must be an ECDH KW
+// key.
+func (r *KeyRef) WrapFor(cek, apu, apv []byte, recipient *cryptoapi.PublicKey,
+	opts ...cryptoapi.WrapKeyOpts) (*cryptoapi.RecipientWrappedKey, error) {
+	if err := r.check(kmsapi.OperationWrapKey); err != nil {
+		return nil, fmt.Errorf("keyref: wrapFor: %w", err)
+	}
+
+	wrapped, err := r.m.crypto.WrapKey(cek, apu, apv, recipient, append(opts, cryptoapi.WithSender(r.kh))...)
+	if err != nil {
+		return nil, fmt.Errorf("keyref: wrapFor: %w", err)
+	}
+
+	return wrapped, nil
+}
+
+// UnwrapKey unwraps a key wrapped for the key. The sender public key must be set with cryptoapi.WithSender for keys
+// wrapped with WrapFor.
+func (r *KeyRef) UnwrapKey(recWK *cryptoapi.RecipientWrappedKey, opts ...cryptoapi.WrapKeyOpts) ([]byte, error) {
+	if err := r.check(kmsapi.OperationUnwrapKey); err != nil {
+		return nil, fmt.Errorf("keyref: unwrapKey: %w", err)
+	}
+
+	cek, err := r.m.crypto.UnwrapKey(recWK, r.kh, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("keyref: unwrapKey: %w", err)
+	}
+
+	return cek, nil
+}
+
+// ExportJWK returns the public key of the key as a JWK with the key ID as kid.
+func (r *KeyRef) ExportJWK() (*jwk.JWK, error) {
+	if err := r.check(kmsapi.OperationExportPublicKey); err != nil {
+		return nil, fmt.Errorf("keyref: exportJWK: %w", err)
+	}
+
+	pubKeyBytes, kt, err := r.m.km.ExportPubKeyBytes(r.kid)
+	if err != nil {
+		return nil, fmt.Errorf("keyref: exportJWK: %w", err)
+	}
+
+	pub, err := pubkeyfmt.ToJWK(pubKeyBytes, kt)
+	if err != nil {
+		return nil, fmt.Errorf("keyref: exportJWK: %w", err)
+	}
+
+	pub.KeyID = r.kid
+
+	return pub, nil
+}
+
+// Rotate rotates the key with a new key of type kt, or of the key type of the key if kt is empty, and returns the
+// KeyRef of the rotated keyset.
+func (r *KeyRef) Rotate(kt kmsapi.KeyType, opts ...kmsapi.KeyOpts) (*KeyRef, error) {
+	if kt == "" {
+		kt = r.kt
+	}
+
+	if kt == "" {
+		return nil, errors.New("keyref: rotate: key type of the key is unknown")
+	}
+
+	if err := r.m.check(kmsapi.OperationRotate, r.kid, kt); err != nil {
+		return nil, fmt.Errorf("keyref: rotate: %w", err)
+	}
+
+	kid, kh, err := r.m.km.Rotate(kt, r.kid, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("keyref: rotate: %w", err)
+	}
+
+	return &KeyRef{m: r.m, kid: kid, kt: kt, kh: kh}, nil
+}
+
+func (r *KeyRef) check(op kmsapi.Operation) error {
+	return r.m.check(op, r.kid, r.kt)
+}
+
+func (r *KeyRef) publicKeyHandle() (interface{}, error) {
+	pubKeyBytes, kt, err := r.m.km.ExportPubKeyBytes(r.kid)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.m.km.PubKeyBytesToHandle(pubKeyBytes, kt)
+}
@@ -0,0 +1,192 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyref
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	mockstorage "github.com/trustbloc/kms-go/internal/mock/storage"
+	"github.com/trustbloc/kms-go/kms"
+	"github.com/trustbloc/kms-go/kms/localkms"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/spi/secretlock"
+)
+
+type provider struct {
+	store kmsapi.Store
+}
+
+func (p *provider) StorageProvider() kmsapi.Store {
+	return p.store
+}
+
+func (p *provider) SecretLock() secretlock.Service {
+	return &noop.NoLock{}
+}
+
+func newManager(t *testing.T, opts ...Opt) *Manager {
+	t.Helper()
+
+	store, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+	require.NoError(t, err)
+
+	km, err := localkms.New("local-lock://custom/primary/key/", &provider{store: store})
+	require.NoError(t, err)
+
+	c, err := tinkcrypto.New(tinkcrypto.WithComputeDH())
+	require.NoError(t, err)
+
+	return New(km, c, opts...)
+}
+
+func TestKeyRef(t *testing.T) {
+	m := newManager(t)
+	msg := []byte("message")
+
+	t.Run("sign, verify, export and rotate", func(t *testing.T) {
+		ref, err := m.Create(kmsapi.ECDSAP256TypeIEEEP1363)
+		require.NoError(t, err)
+		require.NotEmpty(t, ref.KID())
+		require.Equal(t, kmsapi.ECDSAP256TypeIEEEP1363, ref.KeyType())
+
+		sig, err := ref.Sign(msg)
+		require.NoError(t, err)
+		require.NoError(t, ref.Verify(sig, msg))
+		require.Error(t, ref.Verify(sig, []byte("other message")))
+
+		pub, err := ref.ExportJWK()
+		require.NoError(t, err)
+		require.Equal(t, ref.KID(), pub.KeyID)
+		require.Equal(t, "P-256", pub.Crv)
+
+		got, err := m.Get(ref.KID())
+		require.NoError(t, err)
+		require.Equal(t, ref.KeyType(), got.KeyType())
+		require.NoError(t, got.Verify(sig, msg))
+
+		rotated, err := got.Rotate("")
+		require.NoError(t, err)
+		require.Equal(t, kmsapi.ECDSAP256TypeIEEEP1363, rotated.KeyType())
+
+		sig, err = rotated.Sign(msg)
+		require.NoError(t, err)
+		require.NoError(t, rotated.Verify(sig, msg))
+	})
+
+	t.Run("encrypt and decrypt", func(t *testing.T) {
+		ref, err := m.Create(kmsapi.AES256GCMType)
+		require.NoError(t, err)
+
+		ct, nonce, err := ref.Encrypt(msg, []byte("aad"))
+		require.NoError(t, err)
+
+		got, err := m.Get(ref.KID())
+		require.NoError(t, err)
+		require.Empty(t, got.KeyType())
+
+		pt, err := got.Decrypt(ct, []byte("aad"), nonce)
+		require.NoError(t, err)
+		require.Equal(t, msg, pt)
+
+		_, err = got.Rotate("")
+		require.EqualError(t, err, "keyref: rotate: key type of the key is unknown")
+
+		_, err = got.Rotate(kmsapi.AES256GCMType)
+		require.NoError(t, err)
+
+		_, err = got.ExportJWK()
+		require.ErrorContains(t, err, "keyref: exportJWK: ")
+
+		_, err = got.Sign(msg)
+		require.ErrorContains(t, err, "keyref: sign: ")
+	})
+
+	t.Run("wrap for a recipient", func(t *testing.T) {
+		sender, err := m.Create(kmsapi.NISTP256ECDHKWType)
+		require.NoError(t, err)
+
+		recipient, err := m.Create(kmsapi.NISTP256ECDHKWType)
+		require.NoError(t, err)
+
+		senderPub, recipientPub := exportPublicKey(t, m, sender), exportPublicKey(t, m, recipient)
+		cek := random.GetRandomBytes(64)
+
+		wrapped, err := sender.WrapFor(cek, []byte("sender"), []byte("recipient"), recipientPub)
+		require.NoError(t, err)
+
+		unwrapped, err := recipient.UnwrapKey(wrapped, cryptoapi.WithSender(senderPub))
+		require.NoError(t, err)
+		require.Equal(t, cek, unwrapped)
+
+		_, err = recipient.UnwrapKey(wrapped)
+		require.ErrorContains(t, err, "keyref: unwrapKey: ")
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := m.Get("unknown")
+		require.ErrorContains(t, err, "keyref: get: ")
+
+		_, err = m.Create("unknown")
+		require.ErrorContains(t, err, "keyref: create: ")
+	})
+}
+
+func TestPolicy(t *testing.T) {
+	errDenied := errors.New("denied")
+
+	var checked []kmsapi.Operation
+
+	m := newManager(t, WithPolicy(PolicyFunc(func(op kmsapi.Operation, keyID string, kt kmsapi.KeyType) error {
+		checked = append(checked, op)
+
+		if op == kmsapi.OperationSign || kt == kmsapi.ED25519Type {
+			return errDenied
+		}
+
+		return nil
+	})))
+
+	_, err := m.Create(kmsapi.ED25519Type)
+	require.ErrorIs(t, err, errDenied)
+
+	ref, err := m.Create(kmsapi.ECDSAP256TypeDER)
+	require.NoError(t, err)
+
+	_, err = ref.Sign([]byte("message"))
+	require.ErrorIs(t, err, errDenied)
+
+	_, err = ref.ExportJWK()
+	require.NoError(t, err)
+
+	_, err = ref.Rotate(kmsapi.ED25519Type)
+	require.ErrorIs(t, err, errDenied)
+
+	require.Equal(t, []kmsapi.Operation{
+		kmsapi.OperationCreate, kmsapi.OperationCreate, kmsapi.OperationSign, kmsapi.OperationExportPublicKey,
+		kmsapi.OperationRotate,
+	}, checked)
+}
+
+func exportPublicKey(t *testing.T, m *Manager, ref *KeyRef) *cryptoapi.PublicKey {
+	t.Helper()
+
+	pubKeyBytes, _, err := m.km.ExportPubKeyBytes(ref.KID())
+	require.NoError(t, err)
+
+	pub := &cryptoapi.PublicKey{}
+	require.NoError(t, json.Unmarshal(pubKeyBytes, pub))
+
+	return pub
+}
@@ -0,0 +1,207 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyref
+
+import (
+	"fmt"
+
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+// SigningTemplate is the type parameter of the signing key helpers, implemented by the signing key templates below
+// (eg: ECDSAP256).
+type SigningTemplate interface {
+	signingKeyType() kmsapi.KeyType
+}
+
+// AEADTemplate is the type parameter of the AEAD key helpers, implemented by the AEAD key templates below
+// (eg: AES256GCM).
+type AEADTemplate interface {
+	aeadKeyType() kmsapi.KeyType
+}
+
+// Signing key templates.
+type (
+	// ECDSAP256 is an ECDSA P-256 key with IEEE P1363 signatures.
+	ECDSAP256 struct{}
+	// ECDSAP384 is an ECDSA P-384 key with IEEE P1363 signatures.
+	ECDSAP384 struct{}
+	// ECDSAP521 is an ECDSA P-521 key with IEEE P1363 signatures.
+	ECDSAP521 struct{}
+	// ECDSAP256DER is an ECDSA P-256 key with DER signatures.
+	ECDSAP256DER struct{}
+	// ECDSAP384DER is an ECDSA P-384 key with DER signatures.
+	ECDSAP384DER struct{}
+	// ECDSAP521DER is an ECDSA P-521 key with DER signatures.
+	ECDSAP521DER struct{}
+	// ECDSASecp256k1 is an ECDSA secp256k1 key with IEEE P1363 signatures.
+	ECDSASecp256k1 struct{}
+	// BIP340Secp256k1 is a secp256k1 key with BIP-340 Schnorr signatures.
+	BIP340Secp256k1 struct{}
+	// ED25519 is an Ed25519 key.
+	ED25519 struct{}
+	// RSARS256 is an RSA key with RSASSA-PKCS1-v1_5 SHA-256 signatures.
+	RSARS256 struct{}
+	// RSAPS256 is an RSA key with RSASSA-PSS SHA-256 signatures.
+	RSAPS256 struct{}
+)
+
+func (ECDSAP256) signingKeyType() kmsapi.KeyType       { return kmsapi.ECDSAP256TypeIEEEP1363 }
+func (ECDSAP384) signingKeyType() kmsapi.KeyType       { return kmsapi.ECDSAP384TypeIEEEP1363 }
+func (ECDSAP521) signingKeyType() kmsapi.KeyType       { return kmsapi.ECDSAP521TypeIEEEP1363 }
+func (ECDSAP256DER) signingKeyType() kmsapi.KeyType    { return kmsapi.ECDSAP256TypeDER }
+func (ECDSAP384DER) signingKeyType() kmsapi.KeyType    { return kmsapi.ECDSAP384TypeDER }
+func (ECDSAP521DER) signingKeyType() kmsapi.KeyType    { return kmsapi.ECDSAP521TypeDER }
+func (ECDSASecp256k1) signingKeyType() kmsapi.KeyType  { return kmsapi.ECDSASecp256k1TypeIEEEP1363 }
+func (BIP340Secp256k1) signingKeyType() kmsapi.KeyType { return kmsapi.BIP340Secp256k1Type }
+func (ED25519) signingKeyType() kmsapi.KeyType         { return kmsapi.ED25519Type }
+func (RSARS256) signingKeyType() kmsapi.KeyType        { return kmsapi.RSARS256Type }
+func (RSAPS256) signingKeyType() kmsapi.KeyType        { return kmsapi.RSAPS256Type }
+
+// AEAD key templates.
+type (
+	// AES128GCM is an AES-128-GCM key.
+	AES128GCM struct{}
+	// AES256GCM is an AES-256-GCM key.
+	AES256GCM struct{}
+	// AES256GCMNoPrefix is an AES-256-GCM key without Tink output prefix.
+	AES256GCMNoPrefix struct{}
+	// ChaCha20Poly1305 is a ChaCha20Poly1305 key.
+	ChaCha20Poly1305 struct{}
+	// XChaCha20Poly1305 is a XChaCha20Poly1305 key.
+	XChaCha20Poly1305 struct{}
+	// AESGCMSIV256 is a nonce misuse-resistant AES-256-GCM-SIV key.
+	AESGCMSIV256 struct{}
+)
+
+func (AES128GCM) aeadKeyType() kmsapi.KeyType         { return kmsapi.AES128GCMType }
+func (AES256GCM) aeadKeyType() kmsapi.KeyType         { return kmsapi.AES256GCMType }
+func (AES256GCMNoPrefix) aeadKeyType() kmsapi.KeyType { return kmsapi.AES256GCMNoPrefixType }
+func (ChaCha20Poly1305) aeadKeyType() kmsapi.KeyType  { return kmsapi.ChaCha20Poly1305Type }
+func (XChaCha20Poly1305) aeadKeyType() kmsapi.KeyType { return kmsapi.XChaCha20Poly1305Type }
+func (AESGCMSIV256) aeadKeyType() kmsapi.KeyType      { return kmsapi.AESGCMSIV256Type }
+
+// SigningKey is a KeyRef of a signing key of template T, exposing the signing operations only.
+type SigningKey[T SigningTemplate] struct {
+	ref *KeyRef
+}
+
+// CreateSigningKey creates a new signing key of template T with m, eg: CreateSigningKey[keyref.ECDSAP256](m).
+func CreateSigningKey[T SigningTemplate](m *Manager, opts ...kmsapi.KeyOpts) (*SigningKey[T], error) {
+	var tmpl T
+
+	ref, err := m.Create(tmpl.signingKeyType(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SigningKey[T]{ref: ref}, nil
+}
+
+// GetSigningKey returns the signing key keyID of m, it fails if the key is not a key of template T.
+func GetSigningKey[T SigningTemplate](m *Manager, keyID string) (*SigningKey[T], error) {
+	var tmpl T
+
+	ref, err := m.Get(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ref.KeyType() != tmpl.signingKeyType() {
+		return nil, fmt.Errorf("keyref: get: key '%s' is a '%s' key, not a '%s' key", keyID, ref.KeyType(),
+			tmpl.signingKeyType())
+	}
+
+	return &SigningKey[T]{ref: ref}, nil
+}
+
+// KID returns the key ID of the key.
+func (k *SigningKey[T]) KID() string {
+	return k.ref.KID()
+}
+
+// KeyRef returns the untyped KeyRef of the key.
+func (k *SigningKey[T]) KeyRef() *KeyRef {
+	return k.ref
+}
+
+// Sign signs msg with the key.
+func (k *SigningKey[T]) Sign(msg []byte) ([]byte, error) {
+	return k.ref.Sign(msg)
+}
+
+// Verify verifies sig is a signature of msg by the key.
+func (k *SigningKey[T]) Verify(sig, msg []byte) error {
+	return k.ref.Verify(sig, msg)
+}
+
+// ExportJWK returns the public key of the key as a JWK.
+func (k *SigningKey[T]) ExportJWK() (*jwk.JWK, error) {
+	return k.ref.ExportJWK()
+}
+
+// Rotate rotates the key with a new key of template T.
+func (k *SigningKey[T]) Rotate(opts ...kmsapi.KeyOpts) (*SigningKey[T], error) {
+	var tmpl T
+
+	ref, err := k.ref.Rotate(tmpl.signingKeyType(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SigningKey[T]{ref: ref}, nil
+}
+
+// AEADKey is a KeyRef of an AEAD key of template T, exposing the encryption operations only.
+type AEADKey[T AEADTemplate] struct {
+	ref *KeyRef
+}
+
+// CreateAEADKey creates a new AEAD key of template T with m, eg: CreateAEADKey[keyref.AES256GCM](m).
+func CreateAEADKey[T AEADTemplate](m *Manager, opts ...kmsapi.KeyOpts) (*AEADKey[T], error) {
+	var tmpl T
+
+	ref, err := m.Create(tmpl.aeadKeyType(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AEADKey[T]{ref: ref}, nil
+}
+
+// KID returns the key ID of the key.
+func (k *AEADKey[T]) KID() string {
+	return k.ref.KID()
+}
+
+// KeyRef returns the untyped KeyRef of the key.
+func (k *AEADKey[T]) KeyRef() *KeyRef {
+	return k.ref
+}
+
+// Encrypt encrypts msg with aad using the key, it returns the ciphertext and its nonce.
+func (k *AEADKey[T]) Encrypt(msg, aad []byte) ([]byte, []byte, error) {
+	return k.ref.Encrypt(msg, aad)
+}
+
+// Decrypt decrypts cipher encrypted by Encrypt with aad and nonce.
+func (k *AEADKey[T]) Decrypt(cipher, aad, nonce []byte) ([]byte, error) {
+	return k.ref.Decrypt(cipher, aad, nonce)
+}
+
+// Rotate rotates the key with a new key of template T.
+func (k *AEADKey[T]) Rotate(opts ...kmsapi.KeyOpts) (*AEADKey[T], error) {
+	var tmpl T
+
+	ref, err := k.ref.Rotate(tmpl.aeadKeyType(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AEADKey[T]{ref: ref}, nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyref
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+func TestSigningKey(t *testing.T) {
+	m := newManager(t)
+	msg := []byte("message")
+
+	key, err := CreateSigningKey[ECDSAP384](m)
+	require.NoError(t, err)
+	require.Equal(t, kmsapi.ECDSAP384TypeIEEEP1363, key.KeyRef().KeyType())
+
+	sig, err := key.Sign(msg)
+	require.NoError(t, err)
+	require.NoError(t, key.Verify(sig, msg))
+
+	pub, err := key.ExportJWK()
+	require.NoError(t, err)
+	require.Equal(t, "P-384", pub.Crv)
+
+	got, err := GetSigningKey[ECDSAP384](m, key.KID())
+	require.NoError(t, err)
+	require.NoError(t, got.Verify(sig, msg))
+
+	_, err = GetSigningKey[ED25519](m, key.KID())
+	require.ErrorContains(t, err, "is a 'ECDSAP384IEEEP1363' key, not a 'ED25519' key")
+
+	_, err = GetSigningKey[ED25519](m, "unknown")
+	require.Error(t, err)
+
+	rotated, err := got.Rotate()
+	require.NoError(t, err)
+	require.NotEqual(t, key.KID(), rotated.KID())
+
+	sig, err = rotated.Sign(msg)
+	require.NoError(t, err)
+	require.NoError(t, rotated.Verify(sig, msg))
+
+	edKey, err := CreateSigningKey[ED25519](m)
+	require.NoError(t, err)
+	require.Equal(t, kmsapi.ED25519Type, edKey.KeyRef().KeyType())
+}
+
+func TestAEADKey(t *testing.T) {
+	m := newManager(t)
+	msg := []byte("message")
+
+	key, err := CreateAEADKey[XChaCha20Poly1305](m)
+	require.NoError(t, err)
+	require.NotEmpty(t, key.KID())
+	require.Equal(t, kmsapi.XChaCha20Poly1305Type, key.KeyRef().KeyType())
+
+	ct, nonce, err := key.Encrypt(msg, nil)
+	require.NoError(t, err)
+
+	rotated, err := key.Rotate()
+	require.NoError(t, err)
+
+	// the rotated keyset keeps the previous key to decrypt.
+	pt, err := rotated.Decrypt(ct, nil, nonce)
+	require.NoError(t, err)
+	require.Equal(t, msg, pt)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/util/fips"
+)
+
+//nolint:gochecknoglobals
+var (
+	asymmetricKeyTypes = []kmsapi.KeyType{
+		kmsapi.ECDSAP256TypeDER, kmsapi.ECDSAP384TypeDER, kmsapi.ECDSAP521TypeDER,
+		kmsapi.ECDSAP256TypeIEEEP1363, kmsapi.ECDSAP384TypeIEEEP1363, kmsapi.ECDSAP521TypeIEEEP1363,
+		kmsapi.ECDSASecp256k1TypeIEEEP1363, kmsapi.ED25519Type, kmsapi.NISTP256ECDHKWType, kmsapi.NISTP384ECDHKWType,
+		kmsapi.NISTP521ECDHKWType, kmsapi.X25519ECDHKWType, kmsapi.X448ECDHKWType, kmsapi.BLS12381G2Type,
+		kmsapi.RSARS256Type, kmsapi.RSAPS256Type, kmsapi.RSAOAEP256Type, kmsapi.BIP340Secp256k1Type,
+	}
+
+	// importableKeyTypes are the key types supported by ImportPrivateKey.
+	importableKeyTypes = map[kmsapi.KeyType]bool{
+		kmsapi.ECDSAP256TypeDER: true, kmsapi.ECDSAP384TypeDER: true, kmsapi.ECDSAP521TypeDER: true,
+		kmsapi.ECDSAP256TypeIEEEP1363: true, kmsapi.ECDSAP384TypeIEEEP1363: true, kmsapi.ECDSAP521TypeIEEEP1363: true,
+		kmsapi.ECDSASecp256k1TypeIEEEP1363: true, kmsapi.BIP340Secp256k1Type: true, kmsapi.ED25519Type: true,
+		kmsapi.NISTP256ECDHKWType: true, kmsapi.NISTP384ECDHKWType: true, kmsapi.NISTP521ECDHKWType: true,
+		kmsapi.X25519ECDHKWType: true, kmsapi.BLS12381G2Type: true,
+		kmsapi.RSARS256Type: true, kmsapi.RSAPS256Type: true, kmsapi.RSAOAEP256Type: true,
+		kmsapi.HMACSHA256Tag256Type: true, kmsapi.HMACSHA384Tag384Type: true, kmsapi.HMACSHA512Tag512Type: true,
+		kmsapi.AES128KWType: true, kmsapi.AES192KWType: true, kmsapi.AES256KWType: true,
+		kmsapi.AES128GCMType: true, kmsapi.AES256GCMType: true, kmsapi.AES256GCMNoPrefixType: true,
+		kmsapi.ChaCha20Poly1305Type: true, kmsapi.XChaCha20Poly1305Type: true, kmsapi.AESGCMSIV256Type: true,
+	}
+)
+
+// Capabilities returns the key types LocalKMS can create and the key management operations supported for each of
+// them. Crypto operations are advertised by the Crypto implementation running the keys (eg: tinkcrypto). Only the
+// approved key types are returned in FIPS mode, and only the key types of the profile set with WithProfile.
+func (l *LocalKMS) Capabilities() (*kmsapi.Capabilities, error) {
+	caps := &kmsapi.Capabilities{}
+
+	for _, kt := range kmsapi.SymmetricKeyTypes() {
+		if fips.CheckKeyType(kt) != nil || l.checkProfileKeyType(kt) != nil {
+			continue
+		}
+
+		ops := []kmsapi.Operation{kmsapi.OperationCreate, kmsapi.OperationRotate}
+
+		if importableKeyTypes[kt] {
+			ops = append(ops, kmsapi.OperationImportPrivate)
+		}
+
+		caps.KeyTypes = append(caps.KeyTypes, kmsapi.KeyCapability{KeyType: kt, Operations: ops})
+	}
+
+	for _, kt := range asymmetricKeyTypes {
+		if fips.CheckKeyType(kt) != nil || l.checkProfileKeyType(kt) != nil {
+			continue
+		}
+
+		ops := []kmsapi.Operation{kmsapi.OperationCreate, kmsapi.OperationRotate, kmsapi.OperationExportPublicKey}
+
+		if importableKeyTypes[kt] {
+			ops = append(ops, kmsapi.OperationImportPrivate)
+		}
+
+		caps.KeyTypes = append(caps.KeyTypes, kmsapi.KeyCapability{KeyType: kt, Operations: ops})
+	}
+
+	return caps, nil
+}
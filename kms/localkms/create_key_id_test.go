@@ -0,0 +1,119 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+func TestLocalKMS_CreateWithKeyID(t *testing.T) {
+	newKMS := func(t *testing.T, opts ...Opt) (*LocalKMS, *inMemoryKMSStore) {
+		t.Helper()
+
+		store := newInMemoryKMSStore()
+
+		k, err := New(testMasterKeyURI, &mockProvider{storage: store, secretLock: &noop.NoLock{}}, opts...)
+		require.NoError(t, err)
+
+		return k, store
+	}
+
+	t.Run("requested key IDs", func(t *testing.T) {
+		k, _ := newKMS(t, WithKeyIDGenerator(NewUUIDKeyIDGenerator(nil)),
+			WithKIDSchemes(kmsapi.KIDScheme{Encoding: kmsapi.KIDEncodingMultibase}))
+
+		for _, kt := range []kmsapi.KeyType{kmsapi.ED25519Type, kmsapi.AES256GCMType} {
+			keyID, _, err := k.Create(kt, kmsapi.WithCreateKeyID("key-"+string(kt)))
+			require.NoError(t, err)
+			require.Equal(t, "key-"+string(kt), keyID)
+
+			_, err = k.Get(keyID)
+			require.NoError(t, err)
+
+			_, _, err = k.Create(kt, kmsapi.WithCreateKeyID(keyID))
+			require.EqualError(t, err, "create: failed to store keyset: writeToStore: failed to write buffer to "+
+				"store: requested ID '"+keyID+"' already exists, cannot write keyset")
+		}
+
+		// the requested key IDs of asymmetric keys have KID aliases.
+		pubKey, kt, err := k.ExportPubKeyBytes("key-" + string(kmsapi.ED25519Type))
+		require.NoError(t, err)
+
+		kid, err := NewThumbprintKeyIDGenerator(
+			kmsapi.KIDScheme{Encoding: kmsapi.KIDEncodingMultibase}).NewKeyID(kt, pubKey)
+		require.NoError(t, err)
+
+		_, err = k.Get(kid)
+		require.NoError(t, err)
+
+		keyID, pubKey, err := k.CreateAndExportPubKeyBytes(kmsapi.NISTP256ECDHKWType, kmsapi.WithCreateKeyID("ecdh"))
+		require.NoError(t, err)
+		require.Equal(t, "ecdh", keyID)
+		require.Contains(t, string(pubKey), `"kid":"ecdh"`)
+	})
+
+	t.Run("idempotent creation", func(t *testing.T) {
+		k, store := newKMS(t)
+
+		for _, kt := range []kmsapi.KeyType{
+			kmsapi.ECDSAP256TypeIEEEP1363, kmsapi.NISTP384ECDHKWType, kmsapi.HMACSHA256Tag256Type,
+		} {
+			opts := []kmsapi.KeyOpts{kmsapi.WithCreateKeyID(string(kt)), kmsapi.WithReuseExistingKey()}
+
+			keyID, kh, err := k.Create(kt, opts...)
+			require.NoError(t, err)
+			require.Equal(t, string(kt), keyID)
+
+			keyID, existing, err := k.Create(kt, opts...)
+			require.NoError(t, err)
+			require.Equal(t, string(kt), keyID)
+
+			handle, ok := kh.(*keyset.Handle)
+			require.True(t, ok)
+
+			existingHandle, ok := existing.(*keyset.Handle)
+			require.True(t, ok)
+			require.Equal(t, handle.KeysetInfo().String(), existingHandle.KeysetInfo().String())
+		}
+
+		require.Len(t, store.keys, 3)
+
+		_, _, err := k.Create(kmsapi.ECDSAP256TypeDER, kmsapi.WithCreateKeyID(string(kmsapi.ECDSAP256TypeIEEEP1363)),
+			kmsapi.WithReuseExistingKey())
+		require.EqualError(t, err, "create: existing key 'ECDSAP256IEEEP1363' is not a ECDSAP256DER key: "+
+			"invalid key type")
+		require.ErrorIs(t, err, kmsapi.ErrInvalidKeyType)
+
+		_, _, err = k.Create(kmsapi.AES256GCMType, kmsapi.WithCreateKeyID(string(kmsapi.HMACSHA256Tag256Type)),
+			kmsapi.WithReuseExistingKey())
+		require.EqualError(t, err, "create: existing key 'HMACSHA256Tag256' is not a AES256GCM key: invalid key type")
+
+		// the option is ignored without a requested key ID.
+		_, _, err = k.Create(kmsapi.AES256GCMType, kmsapi.WithReuseExistingKey())
+		require.NoError(t, err)
+		require.Len(t, store.keys, 4)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		k, err := New(testMasterKeyURI, &mockProvider{
+			storage:    &mockStore{errGet: errors.New("get failed")},
+			secretLock: &noop.NoLock{},
+		})
+		require.NoError(t, err)
+
+		_, _, err = k.Create(kmsapi.ED25519Type, kmsapi.WithCreateKeyID("kid"), kmsapi.WithReuseExistingKey())
+		require.EqualError(t, err, "create: failed to get existing key 'kid': getKeySet: failed to read json "+
+			"keyset from reader: cannot read data for keysetID kid: get failed")
+	})
+}
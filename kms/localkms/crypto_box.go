@@ -21,10 +21,12 @@ import (
 	"golang.org/x/crypto/nacl/box"
 
 	"github.com/trustbloc/kms-go/doc/util/jwkkid"
+	"github.com/trustbloc/kms-go/internal/memguard"
 	"github.com/trustbloc/kms-go/kms/localkms/internal/keywrapper"
 	"github.com/trustbloc/kms-go/secretlock/noop"
 	"github.com/trustbloc/kms-go/util/cryptoutil"
 
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
 	"github.com/trustbloc/kms-go/spi/kms"
 )
 
@@ -57,58 +59,60 @@ func NewCryptoBox(w kms.KeyManager) (*CryptoBox, error) {
 	return &CryptoBox{km: lkms}, nil
 }
 
+var _ cryptoapi.CryptoBox = (*CryptoBox)(nil)
+
 // Easy seals a message with a provided nonce
 // theirPub is used as a public key, while myPub is used to identify the private key that should be used.
 func (b *CryptoBox) Easy(payload, nonce, theirPub []byte, myKID string) ([]byte, error) {
-	var recPubBytes [cryptoutil.Curve25519KeySize]byte
-
-	copy(recPubBytes[:], theirPub)
+	recPub, nonceBytes, err := boxInputs(theirPub, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("easy: %w", err)
+	}
 
-	senderPriv, err := b.km.exportEncPrivKeyBytes(myKID)
+	priv, err := b.boxPrivateKey(myKID)
 	if err != nil {
 		return nil, fmt.Errorf("easy: failed to export sender key: %w, kid: %v", err, myKID)
 	}
 
-	var (
-		priv       [cryptoutil.Curve25519KeySize]byte
-		nonceBytes [cryptoutil.NonceSize]byte
-	)
-
-	copy(priv[:], senderPriv)
-	copy(nonceBytes[:], nonce)
+	defer memguard.Wipe(priv[:])
 
-	ret := box.Seal(nil, payload, &nonceBytes, &recPubBytes, &priv)
+	if err = cryptoutil.CheckBoxKeys(priv, recPub); err != nil {
+		return nil, fmt.Errorf("easy: %w", err)
+	}
 
-	return ret, nil
+	return box.Seal(nil, payload, nonceBytes, recPub, priv), nil
 }
 
 // EasyOpen unseals a message sealed with Easy, where the nonce is provided
 // theirPub is the public key used to decrypt directly, while myPub is used to identify the private key to be used.
 func (b *CryptoBox) EasyOpen(cipherText, nonce, theirPub, myPub []byte) ([]byte, error) {
-	//	 myPub is used to get the recipient private key for decryption
-	var sendPubBytes [cryptoutil.Curve25519KeySize]byte
+	sendPub, nonceBytes, err := boxInputs(theirPub, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("easyOpen: %w", err)
+	}
 
-	copy(sendPubBytes[:], theirPub)
+	if len(cipherText) < box.Overhead {
+		return nil, errors.New("message too short")
+	}
 
+	//	 myPub is used to get the recipient private key for decryption
 	kid, err := jwkkid.CreateKID(myPub, kms.ED25519Type)
 	if err != nil {
 		return nil, err
 	}
 
-	senderPriv, err := b.km.exportEncPrivKeyBytes(kid)
+	priv, err := b.boxPrivateKey(kid)
 	if err != nil {
 		return nil, err
 	}
 
-	var (
-		priv       [cryptoutil.Curve25519KeySize]byte
-		nonceBytes [cryptoutil.NonceSize]byte
-	)
+	defer memguard.Wipe(priv[:])
 
-	copy(priv[:], senderPriv)
-	copy(nonceBytes[:], nonce)
+	if err = cryptoutil.CheckBoxKeys(priv, sendPub); err != nil {
+		return nil, fmt.Errorf("easyOpen: %w", err)
+	}
 
-	out, success := box.Open(nil, cipherText, &nonceBytes, &sendPubBytes, &priv)
+	out, success := box.Open(nil, cipherText, nonceBytes, sendPub, priv)
 	if !success {
 		return nil, errors.New("failed to unpack")
 	}
@@ -121,25 +125,7 @@ func (b *CryptoBox) EasyOpen(cipherText, nonce, theirPub, myPub []byte) ([]byte,
 // Generates an ephemeral keypair to use for the sender, and includes
 // the ephemeral sender public key in the message.
 func (b *CryptoBox) Seal(payload, theirEncPub []byte, randSource io.Reader) ([]byte, error) {
-	// generate ephemeral curve25519 asymmetric keys
-	epk, esk, err := box.GenerateKey(randSource)
-	if err != nil {
-		return nil, err
-	}
-
-	var recPubBytes [cryptoutil.Curve25519KeySize]byte
-
-	copy(recPubBytes[:], theirEncPub)
-
-	nonce, err := cryptoutil.Nonce(epk[:], theirEncPub)
-	if err != nil {
-		return nil, err
-	}
-
-	// now seal the msg with the ephemeral key, nonce and recPub (which is recipient's publicKey)
-	ret := box.Seal(epk[:], payload, nonce, &recPubBytes, esk)
-
-	return ret, nil
+	return cryptoutil.BoxSeal(payload, theirEncPub, randSource)
 }
 
 // SealOpen decrypts a payload encrypted with Seal
@@ -156,18 +142,20 @@ func (b *CryptoBox) SealOpen(cipherText, myPub []byte) ([]byte, error) {
 		return nil, fmt.Errorf("sealOpen: failed to compute ED25519 kid: %w", err)
 	}
 
-	recipientEncPriv, err := b.km.exportEncPrivKeyBytes(kid)
+	priv, err := b.boxPrivateKey(kid)
 	if err != nil {
 		return nil, fmt.Errorf("sealOpen: failed to exportPriveKeyBytes: %w", err)
 	}
 
-	var (
-		epk  [cryptoutil.Curve25519KeySize]byte
-		priv [cryptoutil.Curve25519KeySize]byte
-	)
+	defer memguard.Wipe(priv[:])
+
+	var epk [cryptoutil.Curve25519KeySize]byte
 
 	copy(epk[:], cipherText[:cryptoutil.Curve25519KeySize])
-	copy(priv[:], recipientEncPriv)
+
+	if err = cryptoutil.CheckBoxKeys(priv, &epk); err != nil {
+		return nil, fmt.Errorf("sealOpen: %w", err)
+	}
 
 	recEncPub, err := cryptoutil.PublicEd25519toCurve25519(myPub)
 	if err != nil {
@@ -179,7 +167,7 @@ func (b *CryptoBox) SealOpen(cipherText, myPub []byte) ([]byte, error) {
 		return nil, err
 	}
 
-	out, success := box.Open(nil, cipherText[cryptoutil.Curve25519KeySize:], nonce, &epk, &priv)
+	out, success := box.Open(nil, cipherText[cryptoutil.Curve25519KeySize:], nonce, &epk, priv)
 	if !success {
 		return nil, errors.New("failed to unpack")
 	}
@@ -187,6 +175,37 @@ func (b *CryptoBox) SealOpen(cipherText, myPub []byte) ([]byte, error) {
 	return out, nil
 }
 
+// boxInputs checks and returns the box public key theirPub and nonce of Easy and EasyOpen.
+func boxInputs(theirPub, nonce []byte) (*[cryptoutil.Curve25519KeySize]byte, *[cryptoutil.NonceSize]byte, error) {
+	pub, err := cryptoutil.BoxPublicKey(theirPub)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	n, err := cryptoutil.BoxNonce(nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pub, n, nil
+}
+
+// boxPrivateKey returns the X25519 private key of the Ed25519 key kid, to be wiped by the caller.
+func (b *CryptoBox) boxPrivateKey(kid string) (*[cryptoutil.Curve25519KeySize]byte, error) {
+	privBytes, err := b.km.exportEncPrivKeyBytes(kid)
+	if err != nil {
+		return nil, err
+	}
+
+	defer memguard.Wipe(privBytes)
+
+	var priv [cryptoutil.Curve25519KeySize]byte
+
+	copy(priv[:], privBytes)
+
+	return &priv, nil
+}
+
 // exportEncPrivKeyBytes temporary support function for crypto_box to be used with legacyPacker only.
 func (l *LocalKMS) exportEncPrivKeyBytes(id string) ([]byte, error) {
 	kh, err := l.getKeySet(id)
@@ -221,6 +240,8 @@ func (l *LocalKMS) exportEncPrivKeyBytes(id string) ([]byte, error) {
 		return nil, err
 	}
 
+	defer memguard.Wipe(decryptedKS)
+
 	return extractPrivKey(decryptedKS)
 }
 
@@ -245,6 +266,8 @@ func extractPrivKey(marshalledKeySet []byte) ([]byte, error) {
 		}
 
 		pkBytes := make([]byte, ed25519.PrivateKeySize)
+		defer memguard.Wipe(pkBytes)
+
 		copy(pkBytes[:ed25519.PublicKeySize], prvKey.KeyValue)
 		copy(pkBytes[ed25519.PublicKeySize:], prvKey.PublicKey.KeyValue)
 
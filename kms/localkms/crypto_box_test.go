@@ -108,6 +108,70 @@ func TestBoxSeal(t *testing.T) {
 	})
 }
 
+func TestBoxEasyWithKMSKeys(t *testing.T) {
+	k := newKMS(t)
+
+	senderKID, senderPubKey, err := k.CreateAndExportPubKeyBytes(kms.ED25519)
+	require.NoError(t, err)
+
+	senderEncPubKey, err := cryptoutil.PublicEd25519toCurve25519(senderPubKey)
+	require.NoError(t, err)
+
+	_, recPubKey, err := k.CreateAndExportPubKeyBytes(kms.ED25519)
+	require.NoError(t, err)
+
+	recEncPubKey, err := cryptoutil.PublicEd25519toCurve25519(recPubKey)
+	require.NoError(t, err)
+
+	b, err := NewCryptoBox(k)
+	require.NoError(t, err)
+
+	nonce := []byte("abcdefghijklmnopqrstuvwx")
+	msg := []byte("hjlp! my angry fez vows quit xkcd")
+
+	t.Run("success", func(t *testing.T) {
+		enc, err := b.Easy(msg, nonce, recEncPubKey, senderKID)
+		require.NoError(t, err)
+
+		dec, err := b.EasyOpen(enc, nonce, senderEncPubKey, recPubKey)
+		require.NoError(t, err)
+		require.Equal(t, msg, dec)
+
+		enc[0]++ // garbling
+
+		_, err = b.EasyOpen(enc, nonce, senderEncPubKey, recPubKey)
+		require.EqualError(t, err, "failed to unpack")
+	})
+
+	t.Run("invalid inputs", func(t *testing.T) {
+		_, err := b.Easy(msg, nonce[1:], recEncPubKey, senderKID)
+		require.EqualError(t, err, "easy: invalid box nonce size 23")
+
+		_, err = b.Easy(msg, nonce, recEncPubKey[1:], senderKID)
+		require.EqualError(t, err, "easy: invalid box public key size 31")
+
+		_, err = b.EasyOpen([]byte("short"), nonce, senderEncPubKey, recPubKey)
+		require.EqualError(t, err, "message too short")
+
+		lowOrder := make([]byte, cryptoutil.Curve25519KeySize)
+
+		_, err = b.Easy(msg, nonce, lowOrder, senderKID)
+		require.EqualError(t, err, "easy: invalid box public key: low order point")
+
+		_, err = b.EasyOpen(msg, nonce, lowOrder, recPubKey)
+		require.EqualError(t, err, "easyOpen: invalid box public key: low order point")
+
+		_, err = b.Seal(msg, lowOrder, rand.Reader)
+		require.EqualError(t, err, "invalid box public key: low order point")
+
+		_, err = b.Seal(msg, recEncPubKey[1:], rand.Reader)
+		require.EqualError(t, err, "invalid box public key size 31")
+
+		_, err = b.SealOpen(append(lowOrder, msg...), recPubKey)
+		require.EqualError(t, err, "sealOpen: invalid box public key: low order point")
+	})
+}
+
 /* Cannot convert X25519 keys to ED25519 keys, this test assumes fixed X25519 keys values. The KMS cannot store
 	encryption X25519 keys. The new KMS supports storing only ED25519 keys. For the sake of LegacyPacker,
     Crypto_Box.go converts from Ed25519 to X25519 only.
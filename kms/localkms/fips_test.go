@@ -0,0 +1,53 @@
+//go:build fips
+
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/util/fips"
+)
+
+func TestLocalKMS_FIPS(t *testing.T) {
+	k := createKMS(t)
+
+	for _, kt := range []kmsapi.KeyType{kmsapi.AES256GCMType, kmsapi.ECDSAP256TypeIEEEP1363, kmsapi.NISTP384ECDHKWType} {
+		_, _, err := k.Create(kt)
+		require.NoError(t, err, kt)
+	}
+
+	for _, kt := range []kmsapi.KeyType{kmsapi.ChaCha20Poly1305Type, kmsapi.ED25519Type, kmsapi.BLS12381G2Type} {
+		_, _, err := k.Create(kt)
+		require.ErrorIs(t, err, fips.ErrNotApproved, kt)
+	}
+
+	keyID, _, err := k.Create(kmsapi.AES256GCMType)
+	require.NoError(t, err)
+
+	_, _, err = k.Rotate(kmsapi.XChaCha20Poly1305Type, keyID)
+	require.ErrorIs(t, err, fips.ErrNotApproved)
+
+	_, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	_, _, err = k.ImportPrivateKey(privKey, kmsapi.ED25519Type)
+	require.ErrorIs(t, err, fips.ErrNotApproved)
+
+	caps, err := k.Capabilities()
+	require.NoError(t, err)
+
+	for _, c := range caps.KeyTypes {
+		require.True(t, fips.KeyTypeApproved(c.KeyType), c.KeyType)
+	}
+}
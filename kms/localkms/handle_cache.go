@@ -0,0 +1,145 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/bluele/gcache"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/util/profiles"
+)
+
+type kmsOpts struct {
+	cacheEnabled bool
+	cacheSize    int
+	cacheTTL     time.Duration
+	cacheClock   gcache.Clock
+	randomness   io.Reader
+	kmsClient    registry.KMSClient
+	useKMSClient bool
+	kidSchemes   []kmsapi.KIDScheme
+
+	keyIDGenerator KeyIDGenerator
+	keyPools       map[kmsapi.KeyType]int
+	profile        *profiles.Profile
+	logger         *slog.Logger
+}
+
+// Opt is a LocalKMS option.
+type Opt func(opts *kmsOpts)
+
+// WithKeyHandleCache enables an in-memory LRU cache of the decrypted key handles, so Get and the operations using
+// stored keys don't read and decrypt the keyset from the store on every call. The cache holds at most size handles
+// (a size of zero or less disables the size limit) for ttl (a TTL of zero or less disables expiration).
+//
+// Cached handles keep private key material in memory. Entries are invalidated by Rotate, Delete and key imports
+// through this LocalKMS, InvalidateKeyHandles must be called when the store is modified by other means (eg:
+// SyncStores or another LocalKMS sharing the store).
+func WithKeyHandleCache(size int, ttl time.Duration) Opt {
+	return func(opts *kmsOpts) {
+		opts.cacheEnabled = true
+		opts.cacheSize = size
+		opts.cacheTTL = ttl
+	}
+}
+
+func newHandleCache(opts *kmsOpts) gcache.Cache {
+	if !opts.cacheEnabled {
+		return nil
+	}
+
+	clock := opts.cacheClock
+	if clock == nil {
+		clock = gcache.NewRealClock()
+	}
+
+	builder := gcache.New(opts.cacheSize).Clock(clock)
+
+	if opts.cacheSize > 0 {
+		builder = builder.LRU()
+	}
+
+	if opts.cacheTTL > 0 {
+		builder = builder.Expiration(opts.cacheTTL)
+	}
+
+	return builder.Build()
+}
+
+// InvalidateKeyHandles removes the handles of keyIDs from the key handle cache, all the cached handles if no key ID
+// is given. It is a no-op if the cache is not enabled with WithKeyHandleCache.
+func (l *LocalKMS) InvalidateKeyHandles(keyIDs ...string) {
+	if l.handles == nil {
+		return
+	}
+
+	if len(keyIDs) == 0 {
+		l.handles.Purge()
+
+		return
+	}
+
+	for _, keyID := range keyIDs {
+		l.handles.Remove(keyID)
+	}
+}
+
+// Delete deletes the key keyID from the store and from the key handle cache. With WithKIDSchemes, keyID can be a KID
+// of the key in any configured scheme and the KID aliases of the key are deleted.
+func (l *LocalKMS) Delete(keyID string) error {
+	keyID, err := l.resolveKID(keyID)
+	if err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	if err = l.deleteKIDAliases(keyID); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	err = l.store.Delete(keyID)
+
+	l.InvalidateKeyHandles(keyID)
+
+	if err != nil {
+		return fmt.Errorf("delete: failed to delete entry for kid '%s': %w", keyID, err)
+	}
+
+	return nil
+}
+
+func (l *LocalKMS) cachedKeySet(id string) *keyset.Handle {
+	if l.handles == nil {
+		return nil
+	}
+
+	v, err := l.handles.Get(id)
+	if err != nil {
+		return nil
+	}
+
+	kh, ok := v.(*keyset.Handle)
+	if !ok {
+		return nil
+	}
+
+	return kh
+}
+
+func (l *LocalKMS) cacheKeySet(id string, kh *keyset.Handle) {
+	if l.handles == nil {
+		return
+	}
+
+	_ = l.handles.Set(id, kh) //nolint:errcheck // a cache miss only costs a store read
+}
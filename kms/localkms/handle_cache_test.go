@@ -0,0 +1,137 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bluele/gcache"
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/kms"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+)
+
+type countingStore struct {
+	*inMemoryKMSStore
+	gets int
+}
+
+func (c *countingStore) Get(keysetID string) ([]byte, error) {
+	c.gets++
+
+	return c.inMemoryKMSStore.Get(keysetID)
+}
+
+func TestLocalKMS_KeyHandleCache(t *testing.T) {
+	store := &countingStore{inMemoryKMSStore: newInMemoryKMSStore()}
+	clock := gcache.NewFakeClock()
+
+	kmsService, err := New(testMasterKeyURI, &mockProvider{storage: store, secretLock: &noop.NoLock{}},
+		WithKeyHandleCache(2, time.Minute), func(opts *kmsOpts) { opts.cacheClock = clock })
+	require.NoError(t, err)
+
+	keyID, _, err := kmsService.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	store.gets = 0
+
+	kh, err := kmsService.Get(keyID)
+	require.NoError(t, err)
+
+	cached, err := kmsService.Get(keyID)
+	require.NoError(t, err)
+	require.Same(t, kh, cached)
+	require.Equal(t, 1, store.gets)
+
+	_, _, err = kmsService.ExportPubKeyBytes(keyID)
+	require.NoError(t, err)
+	require.Equal(t, 1, store.gets)
+
+	t.Run("entries expire", func(t *testing.T) {
+		clock.Advance(2 * time.Minute)
+
+		_, err = kmsService.Get(keyID)
+		require.NoError(t, err)
+		require.Equal(t, 2, store.gets)
+	})
+
+	t.Run("least recently used entries are evicted", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			otherID, _, e := kmsService.Create(kmsapi.ED25519Type)
+			require.NoError(t, e)
+
+			_, e = kmsService.Get(otherID)
+			require.NoError(t, e)
+		}
+
+		gets := store.gets
+
+		_, err = kmsService.Get(keyID)
+		require.NoError(t, err)
+		require.Equal(t, gets+1, store.gets)
+	})
+
+	t.Run("rotate invalidates the rotated key", func(t *testing.T) {
+		_, err = kmsService.Get(keyID)
+		require.NoError(t, err)
+
+		newID, _, e := kmsService.Rotate(kmsapi.ED25519Type, keyID)
+		require.NoError(t, e)
+
+		_, err = kmsService.Get(keyID)
+		require.ErrorIs(t, err, kms.ErrKeyNotFound)
+
+		keyID = newID
+	})
+
+	t.Run("delete and explicit invalidation", func(t *testing.T) {
+		_, err = kmsService.Get(keyID)
+		require.NoError(t, err)
+
+		gets := store.gets
+
+		kmsService.InvalidateKeyHandles(keyID)
+
+		_, err = kmsService.Get(keyID)
+		require.NoError(t, err)
+		require.Equal(t, gets+1, store.gets)
+
+		kmsService.InvalidateKeyHandles()
+
+		_, err = kmsService.Get(keyID)
+		require.NoError(t, err)
+		require.Equal(t, gets+2, store.gets)
+
+		require.NoError(t, kmsService.Delete(keyID))
+
+		_, err = kmsService.Get(keyID)
+		require.ErrorIs(t, err, kms.ErrKeyNotFound)
+	})
+
+	t.Run("cache disabled", func(t *testing.T) {
+		noCache, e := New(testMasterKeyURI, &mockProvider{storage: store, secretLock: &noop.NoLock{}})
+		require.NoError(t, e)
+
+		id, _, e := noCache.Create(kmsapi.ED25519Type)
+		require.NoError(t, e)
+
+		gets := store.gets
+
+		for i := 0; i < 2; i++ {
+			_, e = noCache.Get(id)
+			require.NoError(t, e)
+		}
+
+		require.Equal(t, gets+2, store.gets)
+
+		noCache.InvalidateKeyHandles(id)
+	})
+}
@@ -0,0 +1,162 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+// WithKeyPool enables the pre-generation of keys of type kt: a background goroutine keeps up to size generated keys
+// of kt ready, and Create stores one of them instead of generating the key inline, eg: for the pairwise keys created
+// for every new connection. Create generates the key inline when the pool is empty, or when it's called with
+// kmsapi.WithAttrs. The pool is refilled as keys are taken, Close stops the pool goroutines.
+//
+// Pooled keys keep private key material in memory until they are stored. They are generated from the WithRandomness
+// source if set, which must then be safe for concurrent use: the keys generated from a deterministic source are then
+// not reproducible.
+func WithKeyPool(kt kmsapi.KeyType, size int) Opt {
+	return func(opts *kmsOpts) {
+		if opts.keyPools == nil {
+			opts.keyPools = map[kmsapi.KeyType]int{}
+		}
+
+		opts.keyPools[kt] = size
+	}
+}
+
+// keyPools are the pre-generated keys of the key types of the WithKeyPool options.
+type keyPools struct {
+	pools map[kmsapi.KeyType]chan *keyset.Handle
+	done  chan struct{}
+	once  sync.Once
+	wg    sync.WaitGroup
+}
+
+// newKeyPools starts the generation of the keys of sizes, by key type, generated from the templates of the key types.
+func newKeyPools(sizes map[kmsapi.KeyType]int, l *LocalKMS) (*keyPools, error) {
+	p := &keyPools{pools: map[kmsapi.KeyType]chan *keyset.Handle{}, done: make(chan struct{})}
+
+	templates := map[kmsapi.KeyType]*tinkpb.KeyTemplate{}
+
+	for kt, size := range sizes {
+		if size <= 0 {
+			return nil, fmt.Errorf("key pool: invalid size %d of key type %s", size, kt)
+		}
+
+		if err := l.checkProfileKeyType(kt); err != nil {
+			return nil, fmt.Errorf("key pool: %w", err)
+		}
+
+		template, err := getKeyTemplate(kt)
+		if err != nil {
+			return nil, fmt.Errorf("key pool: %w", err)
+		}
+
+		templates[kt] = template
+		p.pools[kt] = make(chan *keyset.Handle, size)
+	}
+
+	for kt, template := range templates {
+		p.wg.Add(1)
+
+		go p.fill(kt, template, l)
+	}
+
+	return p, nil
+}
+
+// fill generates keys of template into the pool of kt until the pools are closed. It stops on generation errors: the
+// keys are then generated, and the errors returned, by Create.
+func (p *keyPools) fill(kt kmsapi.KeyType, template *tinkpb.KeyTemplate, l *LocalKMS) {
+	defer p.wg.Done()
+
+	for {
+		kh, err := newKeysetHandle(template, l.randomness)
+		if err != nil {
+			l.logger.Warn("localkms: key pool stopped", "keyType", kt, "error", err)
+
+			return
+		}
+
+		select {
+		case p.pools[kt] <- kh:
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// take returns a pooled key of type kt, or nil if there is none.
+func (p *keyPools) take(kt kmsapi.KeyType) *keyset.Handle {
+	if p == nil {
+		return nil
+	}
+
+	select {
+	case kh := <-p.pools[kt]:
+		return kh
+	default:
+		return nil
+	}
+}
+
+// close stops the key generation, waits for the pool goroutines to return and drops the pooled keys.
+func (p *keyPools) close() {
+	if p == nil {
+		return
+	}
+
+	p.once.Do(func() {
+		close(p.done)
+		p.wg.Wait()
+
+		for _, pool := range p.pools {
+			close(pool)
+
+			for range pool { //nolint:revive // drains the pool.
+			}
+		}
+	})
+}
+
+// PooledKeys returns the number of keys of type kt ready in the WithKeyPool pool of kt, 0 if there is no pool of kt.
+func (l *LocalKMS) PooledKeys(kt kmsapi.KeyType) int {
+	if l.keyPools == nil {
+		return 0
+	}
+
+	return len(l.keyPools.pools[kt])
+}
+
+// Close stops the key generation of the WithKeyPool pools and drops the pooled keys, keys are then generated inline.
+// It is a no-op without key pools.
+func (l *LocalKMS) Close() error {
+	l.keyPools.close()
+
+	return nil
+}
+
+// newKey returns a pooled key of type kt created with the attributes option attrs, or a new key of template.
+func (l *LocalKMS) newKey(kt kmsapi.KeyType, template *tinkpb.KeyTemplate, attrs []string) (*keyset.Handle, error) {
+	if len(attrs) == 0 {
+		if kh := l.keyPools.take(kt); kh != nil {
+			return kh, nil
+		}
+
+		if l.keyPools != nil && l.keyPools.pools[kt] != nil {
+			l.logger.Debug("localkms: key pool empty, generating the key", "keyType", kt)
+		}
+	}
+
+	return newKeysetHandle(template, l.randomness)
+}
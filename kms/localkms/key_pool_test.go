@@ -0,0 +1,96 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+func TestLocalKMS_KeyPools(t *testing.T) {
+	newKMS := func(t *testing.T, opts ...Opt) (*LocalKMS, error) {
+		t.Helper()
+
+		return New(testMasterKeyURI, &mockProvider{storage: newInMemoryKMSStore(), secretLock: &noop.NoLock{}},
+			opts...)
+	}
+
+	t.Run("pooled keys", func(t *testing.T) {
+		k, err := newKMS(t, WithKeyPool(kmsapi.ED25519Type, 3), WithKeyPool(kmsapi.X25519ECDHKWType, 2))
+		require.NoError(t, err)
+
+		full := func() bool {
+			return k.PooledKeys(kmsapi.ED25519Type) == 3 && k.PooledKeys(kmsapi.X25519ECDHKWType) == 2
+		}
+
+		require.Eventually(t, full, time.Second, time.Millisecond)
+		require.Zero(t, k.PooledKeys(kmsapi.AES256GCMType))
+
+		keyIDs := map[string]bool{}
+
+		for i := 0; i < 5; i++ {
+			keyID, _, e := k.Create(kmsapi.ED25519Type)
+			require.NoError(t, e)
+
+			pubKey, kt, e := k.ExportPubKeyBytes(keyID)
+			require.NoError(t, e)
+			require.Equal(t, kmsapi.ED25519Type, kt)
+			require.NotEmpty(t, pubKey)
+
+			keyIDs[keyID] = true
+		}
+
+		require.Len(t, keyIDs, 5)
+
+		_, _, err = k.Create(kmsapi.X25519ECDHKWType)
+		require.NoError(t, err)
+
+		// the pools are refilled.
+		require.Eventually(t, full, time.Second, time.Millisecond)
+
+		require.NoError(t, k.Close())
+		require.NoError(t, k.Close())
+		require.Zero(t, k.PooledKeys(kmsapi.ED25519Type))
+
+		// keys are generated inline after Close.
+		_, _, err = k.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+	})
+
+	t.Run("no pools", func(t *testing.T) {
+		k, err := newKMS(t)
+		require.NoError(t, err)
+
+		require.Zero(t, k.PooledKeys(kmsapi.ED25519Type))
+		require.NoError(t, k.Close())
+	})
+
+	t.Run("key generation failure", func(t *testing.T) {
+		k, err := newKMS(t, WithKeyPool(kmsapi.ED25519Type, 1), WithRandomness(bytes.NewReader(nil)))
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, k.Close()) }()
+
+		// the pool goroutine stops, Create returns the key generation error.
+		_, _, err = k.Create(kmsapi.ED25519Type)
+		require.EqualError(t, err, "create: failed to create new keyset handle: generate key: EOF")
+	})
+
+	t.Run("invalid pools", func(t *testing.T) {
+		_, err := newKMS(t, WithKeyPool(kmsapi.ED25519Type, 0))
+		require.EqualError(t, err, "new: key pool: invalid size 0 of key type ED25519")
+
+		_, err = newKMS(t, WithKeyPool("unknown", 1))
+		require.EqualError(t, err, "new: key pool: getKeyTemplate: key type 'unknown' unrecognized: invalid key type")
+	})
+}
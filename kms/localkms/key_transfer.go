@@ -0,0 +1,322 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/trustbloc/kms-go/internal/memguard"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/util/kdfdomain"
+)
+
+// Device pairing protocol version and parameters.
+const (
+	PairingVersion = 1
+
+	pairingNonceSize       = 16
+	verificationCodeSize   = 4
+	verificationCodeModulo = 1000000
+)
+
+// keyTransferDomain is the key derivation domain of the key transfer encryption key.
+//
+//nolint:gochecknoglobals
+var keyTransferDomain = kdfdomain.MustRegister("localkms/key-transfer", PairingVersion,
+	[]byte("kms-go/localkms key transfer v1"))
+
+var (
+	// ErrPairingUsed is returned when a PairingSession accepts a second key transfer.
+	ErrPairingUsed = errors.New("pairing session already used")
+	// ErrPairingMismatch is returned when a key transfer was not made for the PairingSession offer.
+	ErrPairingMismatch = errors.New("key transfer does not match the pairing offer")
+)
+
+// PairingOffer is the bootstrap message of the receiving device, usually shown as a QR code and scanned by the sending
+// device. Scanning it out of band authenticates the receiver ephemeral X25519 public key.
+type PairingOffer struct {
+	Version   int    `json:"v"`
+	PublicKey []byte `json:"pk"`
+	Nonce     []byte `json:"n"`
+}
+
+// Encode returns the offer as a compact string fit for a QR code.
+func (o *PairingOffer) Encode() string {
+	b, _ := json.Marshal(o) //nolint:errcheck // marshalling byte slices and an int can't fail
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodePairingOffer decodes an offer encoded with PairingOffer.Encode.
+func DecodePairingOffer(s string) (*PairingOffer, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("decode pairing offer: %w", err)
+	}
+
+	o := &PairingOffer{}
+
+	if err = json.Unmarshal(b, o); err != nil {
+		return nil, fmt.Errorf("decode pairing offer: %w", err)
+	}
+
+	if o.Version != PairingVersion {
+		return nil, fmt.Errorf("decode pairing offer: unsupported version %d", o.Version)
+	}
+
+	if len(o.PublicKey) != curve25519.PointSize || len(o.Nonce) != pairingNonceSize {
+		return nil, errors.New("decode pairing offer: invalid offer")
+	}
+
+	return o, nil
+}
+
+// TransferredKey is a keyset encrypted for the receiving device.
+type TransferredKey struct {
+	KeyID      string `json:"kid"`
+	Ciphertext []byte `json:"ct"`
+}
+
+// KeyTransfer is the message sent by the sending device to the receiving one, over any (even untrusted) channel.
+type KeyTransfer struct {
+	Version   int               `json:"v"`
+	PublicKey []byte            `json:"epk"`
+	Nonce     []byte            `json:"n"`
+	Keys      []*TransferredKey `json:"keys"`
+}
+
+// PairingSession is the receiving side of a device pairing: it creates the PairingOffer then imports the keys of the
+// KeyTransfer made for it. A session accepts a single transfer.
+type PairingSession struct {
+	kms     *LocalKMS
+	offer   *PairingOffer
+	privKey []byte
+}
+
+// StartPairing starts a device pairing to receive keys from another LocalKMS.
+func (l *LocalKMS) StartPairing() (*PairingSession, error) {
+	privKey := make([]byte, curve25519.ScalarSize)
+	nonce := make([]byte, pairingNonceSize)
+
+	if _, err := io.ReadFull(rand.Reader, privKey); err != nil {
+		return nil, fmt.Errorf("start pairing: %w", err)
+	}
+
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("start pairing: %w", err)
+	}
+
+	pubKey, err := curve25519.X25519(privKey, curve25519.Basepoint)
+	if err != nil {
+		return nil, fmt.Errorf("start pairing: %w", err)
+	}
+
+	return &PairingSession{
+		kms:     l,
+		offer:   &PairingOffer{Version: PairingVersion, PublicKey: pubKey, Nonce: nonce},
+		privKey: privKey,
+	}, nil
+}
+
+// Offer returns the offer to show to the sending device.
+func (s *PairingSession) Offer() *PairingOffer {
+	return s.offer
+}
+
+// VerificationCode returns the 6 digits code of a key transfer. It must match the code displayed by the sending device
+// before accepting the transfer, to detect a man in the middle that replaced the offer.
+func (s *PairingSession) VerificationCode(t *KeyTransfer) (string, error) {
+	_, code, err := s.transferKey(t)
+
+	return code, err
+}
+
+// Accept decrypts the keys of a key transfer and stores them, encrypted with the receiving LocalKMS primary key, with
+// their original key IDs. It returns the IDs of the imported keys.
+func (s *PairingSession) Accept(t *KeyTransfer) ([]string, error) {
+	key, _, err := s.transferKey(t)
+	if err != nil {
+		return nil, err
+	}
+
+	// the session is single use.
+	memguard.Wipe(s.privKey)
+
+	s.privKey = nil
+
+	c, err := chacha20poly1305.NewX(key)
+	memguard.Wipe(key)
+
+	if err != nil {
+		return nil, fmt.Errorf("accept key transfer: %w", err)
+	}
+
+	keyIDs := make([]string, 0, len(t.Keys))
+
+	for _, k := range t.Keys {
+		ks, err := openTransferredKey(c, k)
+		if err != nil {
+			return keyIDs, fmt.Errorf("accept key transfer: key '%s': %w", k.KeyID, err)
+		}
+
+		if err = s.kms.checkProfileKeyset(ks); err == nil {
+			_, err = s.kms.writeImportedKey(ks, kmsapi.WithKeyID(k.KeyID))
+		}
+
+		wipeKeySet(ks)
+
+		if err != nil {
+			return keyIDs, fmt.Errorf("accept key transfer: key '%s': %w", k.KeyID, err)
+		}
+
+		keyIDs = append(keyIDs, k.KeyID)
+	}
+
+	return keyIDs, nil
+}
+
+func (s *PairingSession) transferKey(t *KeyTransfer) ([]byte, string, error) {
+	if s.privKey == nil {
+		return nil, "", ErrPairingUsed
+	}
+
+	if t.Version != PairingVersion || !memguard.Equal(t.Nonce, s.offer.Nonce) {
+		return nil, "", ErrPairingMismatch
+	}
+
+	shared, err := curve25519.X25519(s.privKey, t.PublicKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("key transfer: %w", err)
+	}
+
+	return deriveTransferKey(shared, s.offer.PublicKey, t.PublicKey, s.offer.Nonce)
+}
+
+// TransferKeys encrypts the keys keyIDs for the receiving device of a pairing offer. It returns the key transfer to
+// send to it and the verification code to display, which the receiving device user must confirm.
+func (l *LocalKMS) TransferKeys(offer *PairingOffer, keyIDs ...string) (*KeyTransfer, string, error) {
+	privKey := make([]byte, curve25519.ScalarSize)
+
+	if _, err := io.ReadFull(rand.Reader, privKey); err != nil {
+		return nil, "", fmt.Errorf("transfer keys: %w", err)
+	}
+
+	defer func() {
+		for i := range privKey {
+			privKey[i] = 0
+		}
+	}()
+
+	pubKey, err := curve25519.X25519(privKey, curve25519.Basepoint)
+	if err != nil {
+		return nil, "", fmt.Errorf("transfer keys: %w", err)
+	}
+
+	shared, err := curve25519.X25519(privKey, offer.PublicKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("transfer keys: %w", err)
+	}
+
+	key, code, err := deriveTransferKey(shared, offer.PublicKey, pubKey, offer.Nonce)
+	if err != nil {
+		return nil, "", fmt.Errorf("transfer keys: %w", err)
+	}
+
+	c, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, "", fmt.Errorf("transfer keys: %w", err)
+	}
+
+	t := &KeyTransfer{Version: PairingVersion, PublicKey: pubKey, Nonce: offer.Nonce}
+
+	for _, keyID := range keyIDs {
+		k, err := l.sealTransferredKey(c, keyID)
+		if err != nil {
+			return nil, "", fmt.Errorf("transfer keys: key '%s': %w", keyID, err)
+		}
+
+		t.Keys = append(t.Keys, k)
+	}
+
+	return t, code, nil
+}
+
+func (l *LocalKMS) sealTransferredKey(c cipher.AEAD, keyID string) (*TransferredKey, error) {
+	kh, err := l.getKeySet(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	serialized, err := proto.Marshal(insecurecleartextkeyset.KeysetMaterial(kh))
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, c.NonceSize())
+
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	// the key ID is authenticated so keysets can't be swapped.
+	return &TransferredKey{KeyID: keyID, Ciphertext: c.Seal(nonce, nonce, serialized, []byte(keyID))}, nil
+}
+
+func openTransferredKey(c cipher.AEAD, k *TransferredKey) (*tinkpb.Keyset, error) {
+	if len(k.Ciphertext) < c.NonceSize() {
+		return nil, errors.New("invalid ciphertext")
+	}
+
+	nonce, ct := k.Ciphertext[:c.NonceSize()], k.Ciphertext[c.NonceSize():]
+
+	serialized, err := c.Open(nil, nonce, ct, []byte(k.KeyID))
+	if err != nil {
+		return nil, err
+	}
+
+	ks := &tinkpb.Keyset{}
+
+	if err = proto.Unmarshal(serialized, ks); err != nil {
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+// deriveTransferKey derives the key transfer encryption key and verification code from the X25519 shared secret,
+// bound to both public keys and the offer nonce.
+func deriveTransferKey(shared, receiverPubKey, senderPubKey, nonce []byte) ([]byte, string, error) {
+	kdf := hkdf.New(sha256.New, shared, nonce, keyTransferDomain.Info(receiverPubKey, senderPubKey))
+
+	key := make([]byte, chacha20poly1305.KeySize)
+	code := make([]byte, verificationCodeSize)
+
+	if _, err := io.ReadFull(kdf, key); err != nil {
+		return nil, "", err
+	}
+
+	if _, err := io.ReadFull(kdf, code); err != nil {
+		return nil, "", err
+	}
+
+	return key, fmt.Sprintf("%06d", binary.BigEndian.Uint32(code)%verificationCodeModulo), nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/secretlock/noop"
+)
+
+func TestKeyTransfer(t *testing.T) {
+	sender := createKMS(t)
+
+	// the receiving device uses another secret lock.
+	receiver, err := New(testMasterKeyURI, &mockProvider{
+		storage:    newInMemoryKMSStore(),
+		secretLock: createMasterKeyAndSecretLock(t),
+	})
+	require.NoError(t, err)
+
+	signKID, signKH, err := sender.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	aeadKID, _, err := sender.Create(kmsapi.AES256GCMType)
+	require.NoError(t, err)
+
+	session, err := receiver.StartPairing()
+	require.NoError(t, err)
+
+	// the offer travels as a QR code.
+	offer, err := DecodePairingOffer(session.Offer().Encode())
+	require.NoError(t, err)
+	require.Equal(t, session.Offer(), offer)
+
+	transfer, senderCode, err := sender.TransferKeys(offer, signKID, aeadKID)
+	require.NoError(t, err)
+	require.Len(t, senderCode, 6)
+
+	receiverCode, err := session.VerificationCode(transfer)
+	require.NoError(t, err)
+	require.Equal(t, senderCode, receiverCode)
+
+	keyIDs, err := session.Accept(transfer)
+	require.NoError(t, err)
+	require.Equal(t, []string{signKID, aeadKID}, keyIDs)
+
+	senderPub, kt, err := sender.ExportPubKeyBytes(signKID)
+	require.NoError(t, err)
+
+	receiverPub, receiverKT, err := receiver.ExportPubKeyBytes(signKID)
+	require.NoError(t, err)
+	require.Equal(t, senderPub, receiverPub)
+	require.Equal(t, kt, receiverKT)
+
+	receiverKH, err := receiver.Get(signKID)
+	require.NoError(t, err)
+
+	s, err := signature.NewSigner(receiverKH.(*keyset.Handle))
+	require.NoError(t, err)
+
+	msg := []byte("message")
+
+	sig, err := s.Sign(msg)
+	require.NoError(t, err)
+
+	pubKH, err := signKH.(*keyset.Handle).Public()
+	require.NoError(t, err)
+
+	v, err := signature.NewVerifier(pubKH)
+	require.NoError(t, err)
+	require.NoError(t, v.Verify(sig, msg))
+
+	_, err = receiver.Get(aeadKID)
+	require.NoError(t, err)
+
+	// the receiving keystore can't be read with the sending device secret lock.
+	noLockKMS, err := New(testMasterKeyURI, &mockProvider{storage: receiver.store, secretLock: &noop.NoLock{}})
+	require.NoError(t, err)
+
+	_, err = noLockKMS.Get(signKID)
+	require.Error(t, err)
+
+	// sessions are single use.
+	_, err = session.Accept(transfer)
+	require.ErrorIs(t, err, ErrPairingUsed)
+}
+
+func TestKeyTransferFailures(t *testing.T) {
+	sender := createKMS(t)
+	receiver := createKMS(t)
+
+	kid, _, err := sender.Create(kmsapi.ECDSAP256TypeIEEEP1363)
+	require.NoError(t, err)
+
+	t.Run("invalid offers", func(t *testing.T) {
+		_, err := DecodePairingOffer("%")
+		require.ErrorContains(t, err, "decode pairing offer")
+
+		_, err = DecodePairingOffer("bm90IGpzb24")
+		require.ErrorContains(t, err, "decode pairing offer")
+
+		_, err = DecodePairingOffer((&PairingOffer{Version: 2}).Encode())
+		require.ErrorContains(t, err, "unsupported version 2")
+
+		_, err = DecodePairingOffer((&PairingOffer{Version: PairingVersion}).Encode())
+		require.ErrorContains(t, err, "invalid offer")
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		session, err := receiver.StartPairing()
+		require.NoError(t, err)
+
+		_, _, err = sender.TransferKeys(session.Offer(), "unknown")
+		require.ErrorContains(t, err, "transfer keys: key 'unknown'")
+	})
+
+	t.Run("transfer for another offer", func(t *testing.T) {
+		session, err := receiver.StartPairing()
+		require.NoError(t, err)
+
+		other, err := receiver.StartPairing()
+		require.NoError(t, err)
+
+		transfer, _, err := sender.TransferKeys(other.Offer(), kid)
+		require.NoError(t, err)
+
+		_, err = session.Accept(transfer)
+		require.ErrorIs(t, err, ErrPairingMismatch)
+
+		// a man in the middle replacing the offer public key can't match the verification code.
+		mitm := *other.Offer()
+		mitm.PublicKey = session.Offer().PublicKey
+
+		transfer, code, err := sender.TransferKeys(&mitm, kid)
+		require.NoError(t, err)
+
+		otherCode, err := other.VerificationCode(transfer)
+		require.NoError(t, err)
+		require.NotEqual(t, code, otherCode)
+
+		_, err = other.Accept(transfer)
+		require.ErrorContains(t, err, "accept key transfer: key")
+	})
+
+	t.Run("tampered key", func(t *testing.T) {
+		session, err := receiver.StartPairing()
+		require.NoError(t, err)
+
+		transfer, _, err := sender.TransferKeys(session.Offer(), kid)
+		require.NoError(t, err)
+
+		transfer.Keys[0].KeyID = "other"
+
+		_, err = session.Accept(transfer)
+		require.ErrorContains(t, err, "accept key transfer: key 'other'")
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		session, err := sender.StartPairing()
+		require.NoError(t, err)
+
+		transfer, _, err := sender.TransferKeys(session.Offer(), kid)
+		require.NoError(t, err)
+
+		_, err = session.Accept(transfer)
+		require.ErrorContains(t, err, "already exists")
+	})
+}
@@ -0,0 +1,464 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	gcmpb "github.com/google/tink/go/proto/aes_gcm_go_proto"
+	gcmsivpb "github.com/google/tink/go/proto/aes_gcm_siv_go_proto"
+	chachapb "github.com/google/tink/go/proto/chacha20_poly1305_go_proto"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
+	hmacpb "github.com/google/tink/go/proto/hmac_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	xchachapb "github.com/google/tink/go/proto/xchacha20_poly1305_go_proto"
+	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aeskw"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/bip340"
+	bbspb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/bbs_go_proto"
+	ecdhpb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
+	secp256k1pb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/secp256k1_go_proto"
+	"github.com/trustbloc/kms-go/internal/memguard"
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/util/entropy"
+)
+
+// Keys are generated by Tink's key managers from the system randomness source. With the WithRandomness option,
+// LocalKMS generates the key material of the key templates below itself, from the option's reader, along with the
+// keyset key IDs and the KMS key IDs of symmetric keys: a deterministic reader (see entropy.NewDeterministic)
+// produces the same keys and key IDs for the same sequence of operations.
+
+const (
+	aesGCMKeyTypeURL           = "type.googleapis.com/google.crypto.tink.AesGcmKey"
+	aesGCMSIVKeyTypeURL        = "type.googleapis.com/google.crypto.tink.AesGcmSivKey"
+	chaCha20Poly1305KeyTypeURL = "type.googleapis.com/google.crypto.tink.ChaCha20Poly1305Key"
+	xChaCha20Poly1305TypeURL   = "type.googleapis.com/google.crypto.tink.XChaCha20Poly1305Key"
+	chaCha20Poly1305KeySize    = 32
+	x25519KeySize              = 32
+	bbsSeedSize                = 32
+	keyIDSize                  = 4
+)
+
+// keyDataGenerator generates the serialized key of the serialized key format of a key template from r.
+type keyDataGenerator func(format []byte, r io.Reader) ([]byte, tinkpb.KeyData_KeyMaterialType, error)
+
+// keyDataGenerators are the key data generators of the key templates type URLs.
+//
+//nolint:gochecknoglobals
+var keyDataGenerators = map[string]keyDataGenerator{
+	aesGCMKeyTypeURL:              generateAESGCMKey,
+	aesGCMSIVKeyTypeURL:           generateAESGCMSIVKey,
+	aeskw.TypeURL:                 generateAESGCMKey,
+	chaCha20Poly1305KeyTypeURL:    generateChaCha20Poly1305Key,
+	xChaCha20Poly1305TypeURL:      generateXChaCha20Poly1305Key,
+	hmacKeyTypeURL:                generateHMACKey,
+	ed25519SignerTypeURL:          generateEd25519Key,
+	ecdsaSignerTypeURL:            generateECDSAKey,
+	secp256k1SignerTypeURL:        generateSecp256k1Key,
+	bip340.SignerTypeURL:          generateBIP340Key,
+	nistpECDHKWPrivateKeyTypeURL:  generateNISTPECDHKWKey,
+	x25519ECDHKWPrivateKeyTypeURL: generateX25519ECDHKWKey,
+	bbsSignerKeyTypeURL:           generateBBSKey,
+}
+
+// WithRandomness sets the randomness source of the keys created and rotated by LocalKMS, eg: a deterministic
+// entropy.NewDeterministic reader in tests. r is read sequentially and must be safe for concurrent use if the
+// LocalKMS is. Only the key types of symmetric, EC, Ed25519, X25519, secp256k1 (ECDSA and BIP-340) and BLS12-381 G2
+// keys can be created with a custom randomness source.
+func WithRandomness(r io.Reader) Opt {
+	return func(opts *kmsOpts) {
+		opts.randomness = r
+	}
+}
+
+// newKeysetHandle creates a new keyset handle of template, generating the key from r if it's set.
+func newKeysetHandle(template *tinkpb.KeyTemplate, r io.Reader) (*keyset.Handle, error) {
+	if r == nil {
+		return keyset.NewHandle(template)
+	}
+
+	key, err := generateKey(template, r, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return keysetHandle(&tinkpb.Keyset{PrimaryKeyId: key.KeyId, Key: []*tinkpb.Keyset_Key{key}})
+}
+
+// rotateKeysetHandle adds a new primary key of template to kh, generating the key from r if it's set.
+func rotateKeysetHandle(kh *keyset.Handle, template *tinkpb.KeyTemplate, r io.Reader) (*keyset.Handle, error) {
+	if r == nil {
+		km := keyset.NewManagerFromHandle(kh)
+
+		if err := km.Rotate(template); err != nil {
+			return nil, fmt.Errorf("failed to call Tink's keyManager rotate: %w", err)
+		}
+
+		return km.Handle()
+	}
+
+	ks, ok := proto.Clone(insecurecleartextkeyset.KeysetMaterial(kh)).(*tinkpb.Keyset)
+	if !ok {
+		return nil, fmt.Errorf("invalid keyset")
+	}
+
+	key, err := generateKey(template, r, ks.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	ks.Key = append(ks.Key, key)
+	ks.PrimaryKeyId = key.KeyId
+
+	return keysetHandle(ks)
+}
+
+func keysetHandle(ks *tinkpb.Keyset) (*keyset.Handle, error) {
+	return insecurecleartextkeyset.Read(&keyset.MemReaderWriter{Keyset: ks})
+}
+
+// generateKey generates a key of template from r, with a key ID not used by existing.
+func generateKey(template *tinkpb.KeyTemplate, r io.Reader,
+	existing []*tinkpb.Keyset_Key) (*tinkpb.Keyset_Key, error) {
+	generate, ok := keyDataGenerators[template.TypeUrl]
+	if !ok {
+		return nil, fmt.Errorf("key template '%s' is not supported with a custom randomness source", template.TypeUrl)
+	}
+
+	value, materialType, err := generate(template.Value, r)
+	if err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+
+	keyID, err := newKeyID(r, existing)
+	if err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+
+	return &tinkpb.Keyset_Key{
+		KeyData:          &tinkpb.KeyData{TypeUrl: template.TypeUrl, Value: value, KeyMaterialType: materialType},
+		Status:           tinkpb.KeyStatusType_ENABLED,
+		KeyId:            keyID,
+		OutputPrefixType: template.OutputPrefixType,
+	}, nil
+}
+
+func newKeyID(r io.Reader, existing []*tinkpb.Keyset_Key) (uint32, error) {
+	b := make([]byte, keyIDSize)
+
+	for {
+		if _, err := io.ReadFull(r, b); err != nil {
+			return 0, fmt.Errorf("read key ID: %w", err)
+		}
+
+		id := binary.BigEndian.Uint32(b)
+
+		used := id == 0
+
+		for _, k := range existing {
+			used = used || k.KeyId == id
+		}
+
+		if !used {
+			return id, nil
+		}
+	}
+}
+
+// newRandomKID returns a KMS key ID read from r, of the same size as the storeWriter generated ones.
+func newRandomKID(r io.Reader) (string, error) {
+	b := make([]byte, base64.RawURLEncoding.DecodedLen(maxKeyIDLen))
+
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", fmt.Errorf("read key ID: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func readKey(r io.Reader, size uint32) ([]byte, error) {
+	key := make([]byte, size)
+
+	if _, err := io.ReadFull(r, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func generateAESGCMKey(format []byte, r io.Reader) ([]byte, tinkpb.KeyData_KeyMaterialType, error) {
+	keyFormat := new(gcmpb.AesGcmKeyFormat)
+
+	if err := proto.Unmarshal(format, keyFormat); err != nil {
+		return nil, 0, fmt.Errorf("invalid AES key format: %w", err)
+	}
+
+	key, err := readKey(r, keyFormat.KeySize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer memguard.Wipe(key)
+
+	value, err := proto.Marshal(&gcmpb.AesGcmKey{KeyValue: key})
+
+	return value, tinkpb.KeyData_SYMMETRIC, err
+}
+
+func generateAESGCMSIVKey(format []byte, r io.Reader) ([]byte, tinkpb.KeyData_KeyMaterialType, error) {
+	keyFormat := new(gcmsivpb.AesGcmSivKeyFormat)
+
+	if err := proto.Unmarshal(format, keyFormat); err != nil {
+		return nil, 0, fmt.Errorf("invalid AES-GCM-SIV key format: %w", err)
+	}
+
+	key, err := readKey(r, keyFormat.KeySize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer memguard.Wipe(key)
+
+	value, err := proto.Marshal(&gcmsivpb.AesGcmSivKey{KeyValue: key})
+
+	return value, tinkpb.KeyData_SYMMETRIC, err
+}
+
+func generateChaCha20Poly1305Key(_ []byte, r io.Reader) ([]byte, tinkpb.KeyData_KeyMaterialType, error) {
+	key, err := readKey(r, chaCha20Poly1305KeySize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer memguard.Wipe(key)
+
+	value, err := proto.Marshal(&chachapb.ChaCha20Poly1305Key{KeyValue: key})
+
+	return value, tinkpb.KeyData_SYMMETRIC, err
+}
+
+func generateXChaCha20Poly1305Key(_ []byte, r io.Reader) ([]byte, tinkpb.KeyData_KeyMaterialType, error) {
+	key, err := readKey(r, chaCha20Poly1305KeySize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer memguard.Wipe(key)
+
+	value, err := proto.Marshal(&xchachapb.XChaCha20Poly1305Key{KeyValue: key})
+
+	return value, tinkpb.KeyData_SYMMETRIC, err
+}
+
+func generateHMACKey(format []byte, r io.Reader) ([]byte, tinkpb.KeyData_KeyMaterialType, error) {
+	keyFormat := new(hmacpb.HmacKeyFormat)
+
+	if err := proto.Unmarshal(format, keyFormat); err != nil {
+		return nil, 0, fmt.Errorf("invalid HMAC key format: %w", err)
+	}
+
+	key, err := readKey(r, keyFormat.KeySize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer memguard.Wipe(key)
+
+	value, err := proto.Marshal(&hmacpb.HmacKey{Params: keyFormat.Params, KeyValue: key})
+
+	return value, tinkpb.KeyData_SYMMETRIC, err
+}
+
+func generateEd25519Key(_ []byte, r io.Reader) ([]byte, tinkpb.KeyData_KeyMaterialType, error) {
+	seed, err := readKey(r, ed25519.SeedSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	privKey := ed25519.NewKeyFromSeed(seed)
+
+	defer memguard.Wipe(seed, privKey)
+
+	privKeyProto, err := newProtoEd25519PrivateKey(privKey)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	value, err := proto.Marshal(privKeyProto)
+
+	return value, tinkpb.KeyData_ASYMMETRIC_PRIVATE, err
+}
+
+func generateECDSAKey(format []byte, r io.Reader) ([]byte, tinkpb.KeyData_KeyMaterialType, error) {
+	keyFormat := new(ecdsapb.EcdsaKeyFormat)
+
+	if err := proto.Unmarshal(format, keyFormat); err != nil {
+		return nil, 0, fmt.Errorf("invalid ECDSA key format: %w", err)
+	}
+
+	curve, err := nistCurve(keyFormat.Params.GetCurve())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	privKey, err := entropy.ECDSAKey(curve, r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	value, err := getMarshalledECDSAPrivateKey(privKey, keyFormat.Params)
+
+	return value, tinkpb.KeyData_ASYMMETRIC_PRIVATE, err
+}
+
+func generateSecp256k1Key(format []byte, r io.Reader) ([]byte, tinkpb.KeyData_KeyMaterialType, error) {
+	keyFormat := new(secp256k1pb.Secp256K1KeyFormat)
+
+	if err := proto.Unmarshal(format, keyFormat); err != nil {
+		return nil, 0, fmt.Errorf("invalid secp256k1 key format: %w", err)
+	}
+
+	privKey, err := entropy.ECDSAKey(btcec.S256(), r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	value, err := getMarshalledECDSASecp256K1PrivateKey(privKey, keyFormat.Params)
+
+	return value, tinkpb.KeyData_ASYMMETRIC_PRIVATE, err
+}
+
+func generateBIP340Key(_ []byte, r io.Reader) ([]byte, tinkpb.KeyData_KeyMaterialType, error) {
+	privKey, err := entropy.ECDSAKey(btcec.S256(), r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	value, err := getMarshalledBIP340PrivateKey(privKey)
+
+	return value, tinkpb.KeyData_ASYMMETRIC_PRIVATE, err
+}
+
+func generateNISTPECDHKWKey(format []byte, r io.Reader) ([]byte, tinkpb.KeyData_KeyMaterialType, error) {
+	keyFormat := new(ecdhpb.EcdhAeadKeyFormat)
+
+	if err := proto.Unmarshal(format, keyFormat); err != nil {
+		return nil, 0, fmt.Errorf("invalid ECDH key format: %w", err)
+	}
+
+	curve, err := nistCurve(keyFormat.Params.GetKwParams().GetCurveType())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	privKey, err := entropy.ECDSAKey(curve, r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	value, err := proto.Marshal(&ecdhpb.EcdhAeadPrivateKey{
+		KeyValue: privKey.D.Bytes(),
+		PublicKey: &ecdhpb.EcdhAeadPublicKey{
+			Params: keyFormat.Params,
+			X:      privKey.X.Bytes(),
+			Y:      privKey.Y.Bytes(),
+		},
+	})
+
+	return value, tinkpb.KeyData_ASYMMETRIC_PRIVATE, err
+}
+
+func generateX25519ECDHKWKey(format []byte, r io.Reader) ([]byte, tinkpb.KeyData_KeyMaterialType, error) {
+	keyFormat := new(ecdhpb.EcdhAeadKeyFormat)
+
+	if err := proto.Unmarshal(format, keyFormat); err != nil {
+		return nil, 0, fmt.Errorf("invalid ECDH key format: %w", err)
+	}
+
+	key, err := readKey(r, x25519KeySize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer memguard.Wipe(key)
+
+	privKey, err := ecdh.X25519().NewPrivateKey(key)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	value, err := proto.Marshal(&ecdhpb.EcdhAeadPrivateKey{
+		KeyValue: key,
+		PublicKey: &ecdhpb.EcdhAeadPublicKey{
+			Params: keyFormat.Params,
+			X:      privKey.PublicKey().Bytes(),
+		},
+	})
+
+	return value, tinkpb.KeyData_ASYMMETRIC_PRIVATE, err
+}
+
+func generateBBSKey(format []byte, r io.Reader) ([]byte, tinkpb.KeyData_KeyMaterialType, error) {
+	keyFormat := new(bbspb.BBSKeyFormat)
+
+	if err := proto.Unmarshal(format, keyFormat); err != nil {
+		return nil, 0, fmt.Errorf("invalid BBS+ key format: %w", err)
+	}
+
+	params := keyFormat.GetParams()
+
+	if params.GetCurve() != bbspb.BBSCurveType_BLS12_381 || params.GetGroup() != bbspb.GroupField_G2 {
+		return nil, 0, fmt.Errorf("invalid BBS+ key format: only BLS12-381 G2 keys are supported")
+	}
+
+	seed, err := readKey(r, bbsSeedSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer memguard.Wipe(seed)
+
+	_, privKey, err := bbs12381g2pub.GenerateKeyPair(sha256.New, seed)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	privKeyProto, err := newProtoBBSPrivateKey(privKey, kms.BLS12381G2Type)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	value, err := proto.Marshal(privKeyProto)
+
+	return value, tinkpb.KeyData_ASYMMETRIC_PRIVATE, err
+}
+
+func nistCurve(curve commonpb.EllipticCurveType) (elliptic.Curve, error) {
+	switch curve { //nolint:exhaustive
+	case commonpb.EllipticCurveType_NIST_P256:
+		return elliptic.P256(), nil
+	case commonpb.EllipticCurveType_NIST_P384:
+		return elliptic.P384(), nil
+	case commonpb.EllipticCurveType_NIST_P521:
+		return elliptic.P521(), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve '%s'", curve)
+	}
+}
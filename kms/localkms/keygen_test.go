@@ -0,0 +1,133 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	"github.com/trustbloc/kms-go/util/entropy"
+)
+
+func newDeterministicKMS(t *testing.T, seed string) *LocalKMS {
+	t.Helper()
+
+	kmsService, err := New(testMasterKeyURI, &mockProvider{storage: newInMemoryKMSStore(), secretLock: &noop.NoLock{}},
+		WithRandomness(entropy.NewDeterministic([]byte(seed), "localkms test")))
+	require.NoError(t, err)
+
+	return kmsService
+}
+
+func TestLocalKMS_WithRandomness(t *testing.T) {
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	msg := []byte("message")
+
+	keyTypes := []kmsapi.KeyType{
+		kmsapi.AES128GCMType, kmsapi.AES256GCMType, kmsapi.AES256GCMNoPrefixType, kmsapi.ChaCha20Poly1305Type,
+		kmsapi.XChaCha20Poly1305Type, kmsapi.HMACSHA256Tag256Type, kmsapi.HMACSHA512Tag512Type, kmsapi.AES256KWType,
+		kmsapi.ED25519Type, kmsapi.ECDSAP256TypeDER, kmsapi.ECDSAP384TypeIEEEP1363, kmsapi.ECDSAP521TypeIEEEP1363,
+		kmsapi.ECDSASecp256k1TypeIEEEP1363, kmsapi.NISTP256ECDHKWType, kmsapi.X25519ECDHKWType, kmsapi.BLS12381G2Type,
+		kmsapi.AESGCMSIV256Type, kmsapi.BIP340Secp256k1Type,
+	}
+
+	kms1, kms2 := newDeterministicKMS(t, "seed"), newDeterministicKMS(t, "seed")
+	other := newDeterministicKMS(t, "other seed")
+
+	for _, kt := range keyTypes {
+		t.Run(string(kt), func(t *testing.T) {
+			kid1, kh1, err := kms1.Create(kt)
+			require.NoError(t, err)
+
+			kid2, kh2, err := kms2.Create(kt)
+			require.NoError(t, err)
+
+			kid3, _, err := other.Create(kt)
+			require.NoError(t, err)
+
+			require.Equal(t, kid1, kid2)
+			require.NotEqual(t, kid1, kid3)
+			require.Equal(t, kh1.(*keyset.Handle).KeysetInfo().String(), kh2.(*keyset.Handle).KeysetInfo().String())
+
+			switch kt { //nolint:exhaustive
+			case kmsapi.ED25519Type, kmsapi.ECDSAP256TypeDER, kmsapi.ECDSAP384TypeIEEEP1363,
+				kmsapi.ECDSAP521TypeIEEEP1363, kmsapi.ECDSASecp256k1TypeIEEEP1363, kmsapi.BIP340Secp256k1Type:
+				sig, err := cr.Sign(msg, kh1)
+				require.NoError(t, err)
+
+				pubKH, err := kh2.(*keyset.Handle).Public()
+				require.NoError(t, err)
+				require.NoError(t, cr.Verify(sig, msg, pubKH))
+			case kmsapi.BLS12381G2Type:
+				sig, err := cr.SignMulti([][]byte{msg}, kh1)
+				require.NoError(t, err)
+
+				pubKH, err := kh2.(*keyset.Handle).Public()
+				require.NoError(t, err)
+				require.NoError(t, cr.VerifyMulti([][]byte{msg}, sig, pubKH))
+			case kmsapi.HMACSHA256Tag256Type, kmsapi.HMACSHA512Tag512Type:
+				mac, err := cr.ComputeMAC(msg, kh1)
+				require.NoError(t, err)
+				require.NoError(t, cr.VerifyMAC(mac, msg, kh2))
+			case kmsapi.NISTP256ECDHKWType, kmsapi.X25519ECDHKWType:
+				pub1, _, err := kms1.ExportPubKeyBytes(kid1)
+				require.NoError(t, err)
+
+				pub2, _, err := kms2.ExportPubKeyBytes(kid2)
+				require.NoError(t, err)
+				require.Equal(t, pub1, pub2)
+			case kmsapi.AES256KWType:
+				// key encryption keys are only used for key wrapping, their keyset info is compared above.
+			default:
+				ct, nonce, err := cr.Encrypt(msg, nil, kh1)
+				require.NoError(t, err)
+
+				pt, err := cr.Decrypt(ct, nil, nonce, kh2)
+				require.NoError(t, err)
+				require.Equal(t, msg, pt)
+			}
+		})
+	}
+
+	t.Run("rotate", func(t *testing.T) {
+		kid, _, err := kms1.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		_, _, err = kms2.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		rotated1, kh1, err := kms1.Rotate(kmsapi.ED25519Type, kid)
+		require.NoError(t, err)
+
+		rotated2, kh2, err := kms2.Rotate(kmsapi.ED25519Type, kid)
+		require.NoError(t, err)
+
+		require.Equal(t, rotated1, rotated2)
+		require.NotEqual(t, kid, rotated1)
+		require.Len(t, kh1.(*keyset.Handle).KeysetInfo().KeyInfo, 2)
+		require.Equal(t, kh1.(*keyset.Handle).KeysetInfo().String(), kh2.(*keyset.Handle).KeysetInfo().String())
+	})
+
+	t.Run("unsupported key types", func(t *testing.T) {
+		_, _, err := kms1.Create(kmsapi.RSAPS256Type)
+		require.ErrorContains(t, err, "is not supported with a custom randomness source")
+
+		kid, _, err := kms1.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		_, _, err = kms1.Rotate(kmsapi.RSAPS256Type, kid)
+		require.ErrorContains(t, err, "is not supported with a custom randomness source")
+	})
+}
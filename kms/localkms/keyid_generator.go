@@ -0,0 +1,182 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/tink/go/keyset"
+
+	"github.com/trustbloc/kms-go/doc/util/jwkkid"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+const (
+	uuidSize        = 16
+	ulidEntropySize = 10
+	ulidSize        = 26
+	// Crockford's base32 alphabet of ULIDs.
+	ulidAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// KeyIDGenerator generates the key IDs of the keys created and rotated by LocalKMS, see WithKeyIDGenerator.
+type KeyIDGenerator interface {
+	// NewKeyID returns the key ID of a new key of type kt. pubKey is the marshalled public key of asymmetric keys, as
+	// returned by ExportPubKeyBytes, and nil for symmetric keys. An empty key ID is replaced by a random key ID.
+	NewKeyID(kt kmsapi.KeyType, pubKey []byte) (string, error)
+}
+
+// KeyIDGeneratorFunc is a user provided KeyIDGenerator function.
+type KeyIDGeneratorFunc func(kt kmsapi.KeyType, pubKey []byte) (string, error)
+
+// NewKeyID returns f(kt, pubKey).
+func (f KeyIDGeneratorFunc) NewKeyID(kt kmsapi.KeyType, pubKey []byte) (string, error) {
+	return f(kt, pubKey)
+}
+
+// WithKeyIDGenerator sets the generator of the key IDs of the keys created and rotated by LocalKMS, eg: sortable
+// ULIDs for store backends indexing random IDs poorly, or deterministic IDs. Without this option, asymmetric keys are
+// identified by their JWK thumbprint (see WithKIDSchemes) and symmetric keys by random base64 raw URL encoded IDs.
+//
+// The per key KID scheme of kmsapi.WithKIDScheme only applies to the default generator. Creating a key fails if its
+// generated ID is already used. Imported keys keep the ID of their import options, or a random ID.
+func WithKeyIDGenerator(g KeyIDGenerator) Opt {
+	return func(opts *kmsOpts) {
+		opts.keyIDGenerator = g
+	}
+}
+
+// NewUUIDKeyIDGenerator returns a generator of RFC 9562 version 4 (random) UUID key IDs, read from r or from
+// crypto/rand if r is nil.
+func NewUUIDKeyIDGenerator(r io.Reader) KeyIDGenerator {
+	if r == nil {
+		r = rand.Reader
+	}
+
+	return KeyIDGeneratorFunc(func(kmsapi.KeyType, []byte) (string, error) {
+		b := make([]byte, uuidSize)
+
+		if _, err := io.ReadFull(r, b); err != nil {
+			return "", fmt.Errorf("new UUID: %w", err)
+		}
+
+		b[6] = b[6]&0x0f | 0x40 //nolint:gomnd // version 4.
+		b[8] = b[8]&0x3f | 0x80 //nolint:gomnd // RFC 9562 variant.
+
+		h := hex.EncodeToString(b)
+
+		return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:], nil
+	})
+}
+
+// ulidGenerator generates monotonic ULIDs: the entropy of the ULIDs generated in the same millisecond is incremented,
+// so the key IDs generated by a ulidGenerator are sorted in generation order.
+type ulidGenerator struct {
+	mu      sync.Mutex
+	r       io.Reader
+	now     func() time.Time
+	ms      uint64
+	entropy [ulidEntropySize]byte
+}
+
+// NewULIDKeyIDGenerator returns a generator of ULID key IDs (https://github.com/ulid/spec), lexicographically
+// sortable by creation time, with the entropy read from r or from crypto/rand if r is nil.
+func NewULIDKeyIDGenerator(r io.Reader) KeyIDGenerator {
+	if r == nil {
+		r = rand.Reader
+	}
+
+	return &ulidGenerator{r: r, now: time.Now}
+}
+
+// NewKeyID returns a new ULID.
+func (g *ulidGenerator) NewKeyID(kmsapi.KeyType, []byte) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(g.now().UnixMilli())
+
+	if ms == g.ms {
+		if !increment(g.entropy[:]) {
+			return "", errors.New("new ULID: entropy overflow")
+		}
+	} else {
+		if _, err := io.ReadFull(g.r, g.entropy[:]); err != nil {
+			return "", fmt.Errorf("new ULID: %w", err)
+		}
+
+		g.ms = ms
+	}
+
+	return encodeULID(ms, g.entropy), nil
+}
+
+// increment increments the big endian integer b, it returns false on overflow.
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+
+		if b[i] != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// encodeULID base32 encodes the 128 bits ULID of the 48 bits timestamp ms and entropy.
+func encodeULID(ms uint64, entropy [ulidEntropySize]byte) string {
+	//nolint:gomnd // the 16 high bits of the entropy follow the 48 timestamp bits.
+	hi := ms<<16 | uint64(binary.BigEndian.Uint16(entropy[:2]))
+	lo := binary.BigEndian.Uint64(entropy[2:])
+
+	out := make([]byte, ulidSize)
+
+	for i := ulidSize - 1; i >= 0; i-- {
+		out[i] = ulidAlphabet[lo&31]
+		lo = lo>>5 | hi<<59 //nolint:gomnd
+		hi >>= 5
+	}
+
+	return string(out)
+}
+
+// NewThumbprintKeyIDGenerator returns a generator of the JWK thumbprint key IDs of asymmetric keys computed with
+// scheme, the default key IDs of asymmetric keys. Symmetric and CL CredDef keys get random key IDs.
+func NewThumbprintKeyIDGenerator(scheme kmsapi.KIDScheme) KeyIDGenerator {
+	return KeyIDGeneratorFunc(func(kt kmsapi.KeyType, pubKey []byte) (string, error) {
+		if pubKey == nil || kt == kmsapi.CLCredDefType {
+			return "", nil
+		}
+
+		return jwkkid.CreateKID(pubKey, kt, jwkkid.WithScheme(scheme))
+	})
+}
+
+// newKeyID returns the key ID of the new key kh of type kt generated by the configured KeyIDGenerator, and whether kh
+// is an asymmetric key.
+func (l *LocalKMS) newKeyID(kh *keyset.Handle, kt kmsapi.KeyType) (string, bool, error) {
+	pubKey, _, err := l.exportPubKeyBytes(kh)
+	if err != nil || kt == kmsapi.CLCredDefType {
+		// symmetric keys have no public key.
+		pubKey = nil
+	}
+
+	kid, err := l.opts.keyIDGenerator.NewKeyID(kt, pubKey)
+	if err != nil {
+		return "", false, fmt.Errorf("generate key ID: %w", err)
+	}
+
+	return kid, pubKey != nil, nil
+}
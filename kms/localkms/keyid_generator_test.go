@@ -0,0 +1,166 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/doc/util/jwkkid"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+func TestLocalKMS_KeyIDGenerators(t *testing.T) {
+	newKMS := func(t *testing.T, g KeyIDGenerator, opts ...Opt) *LocalKMS {
+		t.Helper()
+
+		k, err := New(testMasterKeyURI, &mockProvider{storage: newInMemoryKMSStore(), secretLock: &noop.NoLock{}},
+			append(opts, WithKeyIDGenerator(g))...)
+		require.NoError(t, err)
+
+		return k
+	}
+
+	t.Run("UUID", func(t *testing.T) {
+		k := newKMS(t, NewUUIDKeyIDGenerator(nil))
+		uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+		for _, kt := range []kmsapi.KeyType{kmsapi.ED25519Type, kmsapi.AES256GCMType} {
+			keyID, _, err := k.Create(kt)
+			require.NoError(t, err)
+			require.Regexp(t, uuid, keyID)
+
+			_, err = k.Get(keyID)
+			require.NoError(t, err)
+
+			newID, _, err := k.Rotate(kt, keyID)
+			require.NoError(t, err)
+			require.Regexp(t, uuid, newID)
+		}
+
+		id, err := NewUUIDKeyIDGenerator(bytes.NewReader(make([]byte, uuidSize))).NewKeyID(kmsapi.ED25519Type, nil)
+		require.NoError(t, err)
+		require.Equal(t, "00000000-0000-4000-8000-000000000000", id)
+	})
+
+	t.Run("ULID", func(t *testing.T) {
+		k := newKMS(t, NewULIDKeyIDGenerator(nil))
+		ulid := regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+
+		var keyIDs []string
+
+		for i := 0; i < 20; i++ {
+			keyID, _, err := k.Create(kmsapi.AES128GCMType)
+			require.NoError(t, err)
+			require.Regexp(t, ulid, keyID)
+
+			keyIDs = append(keyIDs, keyID)
+		}
+
+		require.True(t, sort.StringsAreSorted(keyIDs))
+
+		g, ok := NewULIDKeyIDGenerator(bytes.NewReader(make([]byte, 2*ulidEntropySize))).(*ulidGenerator)
+		require.True(t, ok)
+
+		ts := time.UnixMilli(1469918176385)
+		g.now = func() time.Time { return ts }
+
+		id, err := g.NewKeyID(kmsapi.ED25519Type, nil)
+		require.NoError(t, err)
+		require.Equal(t, "01ARYZ6S410000000000000000", id)
+
+		// ULIDs of the same millisecond are incremented.
+		id, err = g.NewKeyID(kmsapi.ED25519Type, nil)
+		require.NoError(t, err)
+		require.Equal(t, "01ARYZ6S410000000000000001", id)
+
+		g.entropy = [ulidEntropySize]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+
+		_, err = g.NewKeyID(kmsapi.ED25519Type, nil)
+		require.EqualError(t, err, "new ULID: entropy overflow")
+
+		ts = ts.Add(time.Millisecond)
+
+		id, err = g.NewKeyID(kmsapi.ED25519Type, nil)
+		require.NoError(t, err)
+		require.Equal(t, "01ARYZ6S420000000000000000", id)
+
+		ts = ts.Add(time.Millisecond)
+
+		_, err = g.NewKeyID(kmsapi.ED25519Type, nil)
+		require.EqualError(t, err, "new ULID: EOF")
+	})
+
+	t.Run("JWK thumbprint", func(t *testing.T) {
+		scheme := kmsapi.KIDScheme{Encoding: kmsapi.KIDEncodingMultibase}
+		k := newKMS(t, NewThumbprintKeyIDGenerator(scheme), WithKIDSchemes(kmsapi.KIDScheme{}))
+
+		keyID, _, err := k.Create(kmsapi.ECDSAP256TypeIEEEP1363)
+		require.NoError(t, err)
+
+		pubKey, kt, err := k.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+
+		kid, err := jwkkid.CreateKID(pubKey, kt, jwkkid.WithScheme(scheme))
+		require.NoError(t, err)
+		require.Equal(t, kid, keyID)
+
+		// the keys are aliased in the configured KID schemes.
+		kid, err = jwkkid.CreateKID(pubKey, kt)
+		require.NoError(t, err)
+
+		_, err = k.Get(kid)
+		require.NoError(t, err)
+
+		// symmetric keys have random key IDs.
+		keyID, _, err = k.Create(kmsapi.HMACSHA256Tag256Type)
+		require.NoError(t, err)
+		require.Len(t, keyID, maxKeyIDLen)
+	})
+
+	t.Run("user provided", func(t *testing.T) {
+		var pubKeys [][]byte
+
+		k := newKMS(t, KeyIDGeneratorFunc(func(kt kmsapi.KeyType, pubKey []byte) (string, error) {
+			pubKeys = append(pubKeys, pubKey)
+
+			return "key-" + string(kt), nil
+		}))
+
+		keyID, _, err := k.Create(kmsapi.ChaCha20Poly1305Type)
+		require.NoError(t, err)
+		require.Equal(t, "key-"+string(kmsapi.ChaCha20Poly1305Type), keyID)
+
+		keyID, _, err = k.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+		require.Equal(t, "key-"+string(kmsapi.ED25519Type), keyID)
+
+		pubKey, _, err := k.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{nil, pubKey}, pubKeys)
+
+		_, _, err = k.Create(kmsapi.ED25519Type)
+		require.EqualError(t, err, "create: failed to store keyset: writeToStore: failed to write buffer to store: "+
+			"requested ID 'key-ED25519' already exists, cannot write keyset")
+	})
+
+	t.Run("generator error", func(t *testing.T) {
+		k := newKMS(t, KeyIDGeneratorFunc(func(kmsapi.KeyType, []byte) (string, error) {
+			return "", errors.New("generator failed")
+		}))
+
+		_, _, err := k.Create(kmsapi.ED25519Type)
+		require.EqualError(t, err, "create: failed to store keyset: storeKeySet: generate key ID: generator failed")
+	})
+}
@@ -14,13 +14,20 @@ import (
 	"github.com/google/tink/go/mac"
 	commonpb "github.com/google/tink/go/proto/common_go_proto"
 	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
+	hmacpb "github.com/google/tink/go/proto/hmac_go_proto"
+	rsapb "github.com/google/tink/go/proto/rsa_ssa_pkcs1_go_proto"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 	"github.com/google/tink/go/signature"
 
 	"github.com/trustbloc/kms-go/spi/kms"
 
+	kmsaead "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aead"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aeskw"
 	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/bbs"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/bip340"
 	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/composite/ecdh"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/rsaoaep"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/rsapss"
 	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/secp256k1"
 )
 
@@ -38,6 +45,8 @@ func keyTemplate(keyType kms.KeyType, _ ...kms.KeyOpts) (*tinkpb.KeyTemplate, er
 		return aead.ChaCha20Poly1305KeyTemplate(), nil
 	case kms.XChaCha20Poly1305Type:
 		return aead.XChaCha20Poly1305KeyTemplate(), nil
+	case kms.AESGCMSIV256Type:
+		return kmsaead.AES256GCMSIVKeyTemplate(), nil
 	case kms.ECDSAP256TypeDER:
 		return signature.ECDSAP256KeyWithoutPrefixTemplate(), nil
 	case kms.ECDSAP384TypeDER:
@@ -59,6 +68,17 @@ func keyTemplate(keyType kms.KeyType, _ ...kms.KeyOpts) (*tinkpb.KeyTemplate, er
 		return signature.ED25519KeyWithoutPrefixTemplate(), nil
 	case kms.HMACSHA256Tag256Type:
 		return mac.HMACSHA256Tag256KeyTemplate(), nil
+	case kms.HMACSHA384Tag384Type:
+		// Tink has no HMAC-SHA384 key template.
+		return createHMACKeyTemplate(commonpb.HashType_SHA384, hmacSHA384Size), nil
+	case kms.HMACSHA512Tag512Type:
+		return mac.HMACSHA512Tag512KeyTemplate(), nil
+	case kms.AES128KWType:
+		return aeskw.A128KWKeyTemplate(), nil
+	case kms.AES192KWType:
+		return aeskw.A192KWKeyTemplate(), nil
+	case kms.AES256KWType:
+		return aeskw.A256KWKeyTemplate(), nil
 	case kms.NISTP256ECDHKWType:
 		return ecdh.NISTP256ECDHKWKeyTemplate(), nil
 	case kms.NISTP384ECDHKWType:
@@ -67,14 +87,24 @@ func keyTemplate(keyType kms.KeyType, _ ...kms.KeyOpts) (*tinkpb.KeyTemplate, er
 		return ecdh.NISTP521ECDHKWKeyTemplate(), nil
 	case kms.X25519ECDHKWType:
 		return ecdh.X25519ECDHKWKeyTemplate(), nil
+	case kms.X448ECDHKWType:
+		return ecdh.X448ECDHKWKeyTemplate(), nil
 	case kms.BLS12381G2Type:
 		return bbs.BLS12381G2KeyTemplate(), nil
 	case kms.ECDSASecp256k1DER:
 		return secp256k1.DERKeyTemplate()
 	case kms.ECDSASecp256k1IEEEP1363:
 		return secp256k1.IEEEP1363KeyTemplate()
+	case kms.BIP340Secp256k1Type:
+		return bip340.KeyTemplate(), nil
+	case kms.RSARS256Type:
+		return createRSASSAPKCS1KeyTemplate(commonpb.HashType_SHA256, rsaModulusSize2048), nil
+	case kms.RSAPS256Type:
+		return rsapss.PS256KeyTemplate(), nil
+	case kms.RSAOAEP256Type:
+		return rsaoaep.RSAOAEP256KeyTemplate(), nil
 	default:
-		return nil, fmt.Errorf("getKeyTemplate: key type '%s' unrecognized", keyType)
+		return nil, fmt.Errorf("getKeyTemplate: key type '%s' unrecognized: %w", keyType, kms.ErrInvalidKeyType)
 	}
 }
 
@@ -98,3 +128,35 @@ func createECDSAKeyTemplate(sigEncoding ecdsapb.EcdsaSignatureEncoding, hashType
 		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
 	}
 }
+
+// createRSASSAPKCS1KeyTemplate creates a RAW RSASSA-PKCS1-v1_5 key template with the 65537 public exponent. Tink's
+// templates create 3072-bit keys or larger.
+func createRSASSAPKCS1KeyTemplate(hashType commonpb.HashType, modulusSize uint32) *tinkpb.KeyTemplate {
+	format := &rsapb.RsaSsaPkcs1KeyFormat{
+		Params:            &rsapb.RsaSsaPkcs1Params{HashType: hashType},
+		ModulusSizeInBits: modulusSize,
+		PublicExponent:    []byte{0x01, 0x00, 0x01},
+	}
+	serializedFormat, _ := proto.Marshal(format) //nolint:errcheck
+
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          rsaSSAPKCS1SignerTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+	}
+}
+
+// createHMACKeyTemplate creates an HMAC key template with a key and a full length tag of the hash function size.
+func createHMACKeyTemplate(hashType commonpb.HashType, size uint32) *tinkpb.KeyTemplate {
+	format := &hmacpb.HmacKeyFormat{
+		Params:  &hmacpb.HmacParams{Hash: hashType, TagSize: size},
+		KeySize: size,
+	}
+	serializedFormat, _ := proto.Marshal(format) //nolint:errcheck
+
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          hmacKeyTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+	}
+}
@@ -10,9 +10,15 @@ import (
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 
 	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/util/fips"
 )
 
-// getKeyTemplate returns tink KeyTemplate associated with the provided keyType.
+// getKeyTemplate returns tink KeyTemplate associated with the provided keyType. Key types that are not approved are
+// rejected in FIPS mode.
 func getKeyTemplate(keyType kms.KeyType, opts ...kms.KeyOpts) (*tinkpb.KeyTemplate, error) {
+	if err := fips.CheckKeyType(keyType); err != nil {
+		return nil, err
+	}
+
 	return keyTemplate(keyType, opts...)
 }
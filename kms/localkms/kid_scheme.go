@@ -0,0 +1,126 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/keyset"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/doc/util/jwkkid"
+	"github.com/trustbloc/kms-go/kms"
+)
+
+// kidAliasPrefix prefixes the store entries mapping the KIDs of the other KID schemes of a key to its key ID.
+const kidAliasPrefix = "kid-alias/"
+
+// WithKIDSchemes sets the KID schemes of the asymmetric keys created by LocalKMS. A key KID is computed with the
+// first scheme, unless the key is created with kmsapi.WithKIDScheme, and aliases of the key for its KIDs in the other
+// schemes are stored: the key is then retrieved, rotated or deleted by its KID in any of the schemes. Without this
+// option, KIDs are base64 raw URL encoded thumbprints and no alias is stored.
+//
+// The aliases of keys created before the option was set, or with other schemes, are not stored.
+func WithKIDSchemes(schemes ...kmsapi.KIDScheme) Opt {
+	return func(opts *kmsOpts) {
+		opts.kidSchemes = schemes
+	}
+}
+
+// kidScheme returns the KID scheme of a key created with the KID scheme option s (nil if the option is not set).
+func (l *LocalKMS) kidScheme(s *kmsapi.KIDScheme) kmsapi.KIDScheme {
+	if s != nil {
+		return *s
+	}
+
+	if len(l.opts.kidSchemes) > 0 {
+		return l.opts.kidSchemes[0]
+	}
+
+	return kmsapi.KIDScheme{}
+}
+
+// storeKIDAliases stores the aliases of kid, the key ID of kh, for its KIDs in the configured KID schemes.
+func (l *LocalKMS) storeKIDAliases(kh *keyset.Handle, kt kmsapi.KeyType, kid string) error {
+	if len(l.opts.kidSchemes) == 0 {
+		return nil
+	}
+
+	keyBytes, _, err := l.exportPubKeyBytes(kh)
+	if err != nil {
+		return fmt.Errorf("store KID aliases: %w", err)
+	}
+
+	for _, scheme := range l.opts.kidSchemes {
+		alias, e := jwkkid.CreateKID(keyBytes, kt, jwkkid.WithScheme(scheme))
+		if e != nil {
+			return fmt.Errorf("store KID aliases: %w", e)
+		}
+
+		if alias == kid {
+			continue
+		}
+
+		if e = l.store.Put(kidAliasPrefix+alias, []byte(kid)); e != nil {
+			return fmt.Errorf("store KID aliases: %w", e)
+		}
+	}
+
+	return nil
+}
+
+// deleteKIDAliases deletes the KID aliases of the key kid, if any.
+func (l *LocalKMS) deleteKIDAliases(kid string) error {
+	if len(l.opts.kidSchemes) == 0 {
+		return nil
+	}
+
+	kh, err := l.getKeySet(kid)
+	if err != nil {
+		// the key doesn't exist, or can't be read: it has no aliases to delete.
+		return nil //nolint:nilerr
+	}
+
+	keyBytes, kt, err := l.exportPubKeyBytes(kh)
+	if err != nil {
+		// symmetric keys have no KID aliases.
+		return nil //nolint:nilerr
+	}
+
+	for _, scheme := range l.opts.kidSchemes {
+		alias, e := jwkkid.CreateKID(keyBytes, kt, jwkkid.WithScheme(scheme))
+		if e != nil || alias == kid {
+			continue
+		}
+
+		if e = l.store.Delete(kidAliasPrefix + alias); e != nil {
+			return fmt.Errorf("delete KID aliases: %w", e)
+		}
+	}
+
+	return nil
+}
+
+// resolveKID returns the key ID of the KID alias id, or id if it's not an alias.
+func (l *LocalKMS) resolveKID(id string) (string, error) {
+	if len(l.opts.kidSchemes) == 0 {
+		return id, nil
+	}
+
+	kid, err := l.store.Get(kidAliasPrefix + id)
+
+	switch {
+	case err == nil:
+		return string(kid), nil
+	case errors.Is(err, kms.ErrKeyNotFound):
+		return id, nil
+	default:
+		return "", fmt.Errorf("resolve KID '%s': %w", id, err)
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/doc/util/jwkkid"
+	"github.com/trustbloc/kms-go/kms"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+func TestLocalKMS_KIDSchemes(t *testing.T) {
+	multibase := kmsapi.KIDScheme{Encoding: kmsapi.KIDEncodingMultibase}
+	truncated := kmsapi.KIDScheme{Size: 16}
+
+	t.Run("default scheme", func(t *testing.T) {
+		store := newInMemoryKMSStore()
+
+		k, err := New(testMasterKeyURI, &mockProvider{storage: store, secretLock: &noop.NoLock{}})
+		require.NoError(t, err)
+
+		keyID, _, err := k.Create(kmsapi.ED25519Type, kmsapi.WithKIDScheme(truncated))
+		require.NoError(t, err)
+		require.Len(t, keyID, 22)
+		require.Len(t, store.keys, 1)
+
+		pubKey, _, err := k.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+
+		expected, err := jwkkid.CreateKID(pubKey, kmsapi.ED25519Type, jwkkid.WithScheme(truncated))
+		require.NoError(t, err)
+		require.Equal(t, expected, keyID)
+	})
+
+	t.Run("keys retrieved by any configured scheme", func(t *testing.T) {
+		store := newInMemoryKMSStore()
+
+		k, err := New(testMasterKeyURI, &mockProvider{storage: store, secretLock: &noop.NoLock{}},
+			WithKIDSchemes(multibase, kmsapi.KIDScheme{}, truncated))
+		require.NoError(t, err)
+
+		keyID, _, err := k.Create(kmsapi.NISTP256ECDHKWType)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(keyID, "zQm"))
+
+		pubKey, kt, err := k.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+
+		for _, scheme := range []kmsapi.KIDScheme{multibase, {}, truncated} {
+			kid, e := jwkkid.CreateKID(pubKey, kt, jwkkid.WithScheme(scheme))
+			require.NoError(t, e)
+
+			_, e = k.Get(kid)
+			require.NoError(t, e)
+
+			_, _, e = k.GetWithOpts(kid)
+			require.NoError(t, e)
+		}
+
+		// the key and its aliases in the other two schemes.
+		require.Len(t, store.keys, 3)
+
+		// per key schemes take precedence over the default scheme.
+		edID, _, err := k.Create(kmsapi.ED25519Type, kmsapi.WithKIDScheme(truncated))
+		require.NoError(t, err)
+		require.Len(t, edID, 22)
+		require.Len(t, store.keys, 6)
+
+		// symmetric keys have random key IDs and no aliases.
+		_, _, err = k.Create(kmsapi.AES256GCMType)
+		require.NoError(t, err)
+		require.Len(t, store.keys, 7)
+
+		pubKey, kt, err = k.ExportPubKeyBytes(edID)
+		require.NoError(t, err)
+
+		defaultKID, err := jwkkid.CreateKID(pubKey, kt)
+		require.NoError(t, err)
+
+		newID, _, err := k.Rotate(kmsapi.ED25519Type, defaultKID)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(newID, "zQm"))
+		require.Len(t, store.keys, 7)
+
+		_, err = k.Get(edID)
+		require.ErrorIs(t, err, kms.ErrKeyNotFound)
+
+		require.NoError(t, k.Delete(defaultKID))
+		require.Len(t, store.keys, 7)
+
+		// the KID of the rotated key is the KID of its new primary key.
+		require.NoError(t, k.Delete(newID))
+		require.Len(t, store.keys, 4)
+
+		_, err = k.Get(newID)
+		require.ErrorIs(t, err, kms.ErrKeyNotFound)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		k, err := New(testMasterKeyURI, &mockProvider{
+			storage:    &mockStore{errGet: errors.New("get failed")},
+			secretLock: &noop.NoLock{},
+		}, WithKIDSchemes(multibase))
+		require.NoError(t, err)
+
+		_, err = k.Get("kid")
+		require.EqualError(t, err, "getKeySet: resolve KID 'kid': get failed")
+
+		_, _, err = k.GetWithOpts("kid")
+		require.EqualError(t, err, "getKeySet: resolve KID 'kid': get failed")
+
+		_, _, err = k.Rotate(kmsapi.ED25519Type, "kid")
+		require.EqualError(t, err, "rotate: resolve KID 'kid': get failed")
+
+		err = k.Delete("kid")
+		require.EqualError(t, err, "delete: resolve KID 'kid': get failed")
+
+		k, err = New(testMasterKeyURI, &mockProvider{storage: newInMemoryKMSStore(), secretLock: &noop.NoLock{}},
+			WithKIDSchemes(kmsapi.KIDScheme{Size: 4}))
+		require.NoError(t, err)
+
+		_, _, err = k.Create(kmsapi.ED25519Type)
+		require.EqualError(t, err, "create: failed to store keyset: storeKeySet: failed to generate kid: "+
+			"createKID: invalid KID size 4, want 8 to 32")
+	})
+}
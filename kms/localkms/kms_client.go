@@ -0,0 +1,57 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/tink"
+
+	"github.com/trustbloc/kms-go/spi/secretlock"
+
+	"github.com/trustbloc/kms-go/kms/localkms/internal/keywrapper"
+)
+
+// WithKMSClient wraps the stored keysets with the remote KMS AEAD of client for the primary key URI (eg: a Tink
+// aws-kms:// or gcp-kms:// client, such as the ones of github.com/google/tink/go/integration) instead of the secret
+// lock of the provider, which is not used. As with the local secret lock, keysets are encrypted with Tink KMS envelope
+// encryption: each keyset is encrypted with a fresh data encryption key, and only this key is sent to the remote KMS
+// to be wrapped.
+//
+// If client is nil, the KMS client supporting the primary key URI is looked up in the clients registered with Tink's
+// registry.RegisterKMSClient. Keysets stored with another key wrapper can't be read.
+func WithKMSClient(client registry.KMSClient) Opt {
+	return func(opts *kmsOpts) {
+		opts.kmsClient = client
+		opts.useKMSClient = true
+	}
+}
+
+// newKeyWrapper returns the AEAD wrapping the data encryption keys of the stored keysets with the primary key.
+func newKeyWrapper(secretLock secretlock.Service, primaryKeyURI string, opts *kmsOpts) (tink.AEAD, error) {
+	if !opts.useKMSClient {
+		return keywrapper.New(secretLock, primaryKeyURI)
+	}
+
+	client := opts.kmsClient
+
+	if client == nil {
+		var err error
+
+		client, err = registry.GetKMSClient(primaryKeyURI)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !client.Supported(primaryKeyURI) {
+		return nil, fmt.Errorf("KMS client doesn't support key URI '%s'", primaryKeyURI)
+	}
+
+	return client.GetAEAD(primaryKeyURI)
+}
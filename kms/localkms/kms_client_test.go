@@ -0,0 +1,112 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/tink"
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/secretlock/noop"
+)
+
+const testRemoteKeyURI = "aws-kms://arn:aws:kms:us-east-1:123456789012:key/test"
+
+// mockKMSClient is a Tink KMS client with in-memory remote keys.
+type mockKMSClient struct {
+	a      tink.AEAD
+	getErr error
+	calls  int
+}
+
+func newMockKMSClient(t *testing.T) *mockKMSClient {
+	t.Helper()
+
+	kh, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+	require.NoError(t, err)
+
+	a, err := aead.New(kh)
+	require.NoError(t, err)
+
+	return &mockKMSClient{a: a}
+}
+
+func (c *mockKMSClient) Supported(keyURI string) bool {
+	return strings.HasPrefix(keyURI, "aws-kms://")
+}
+
+func (c *mockKMSClient) GetAEAD(string) (tink.AEAD, error) {
+	c.calls++
+
+	return c.a, c.getErr
+}
+
+func TestLocalKMS_WithKMSClient(t *testing.T) {
+	client := newMockKMSClient(t)
+	store := newInMemoryKMSStore()
+
+	// no secret lock is needed with a KMS client.
+	kmsService, err := New(testRemoteKeyURI, &mockProvider{storage: store}, WithKMSClient(client))
+	require.NoError(t, err)
+	require.Equal(t, 1, client.calls)
+
+	keyID, kh, err := kmsService.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	reopened, err := New(testRemoteKeyURI, &mockProvider{storage: store}, WithKMSClient(client))
+	require.NoError(t, err)
+
+	stored, err := reopened.Get(keyID)
+	require.NoError(t, err)
+	require.Equal(t, kh.(*keyset.Handle).KeysetInfo().String(), stored.(*keyset.Handle).KeysetInfo().String())
+
+	t.Run("keysets are not wrapped by the secret lock", func(t *testing.T) {
+		local, err := New(testMasterKeyURI, &mockProvider{storage: store, secretLock: &noop.NoLock{}})
+		require.NoError(t, err)
+
+		_, err = local.Get(keyID)
+		require.Error(t, err)
+
+		other, err := New(testRemoteKeyURI, &mockProvider{storage: store}, WithKMSClient(newMockKMSClient(t)))
+		require.NoError(t, err)
+
+		_, err = other.Get(keyID)
+		require.Error(t, err)
+	})
+
+	t.Run("registered KMS client", func(t *testing.T) {
+		_, err := New(testRemoteKeyURI, &mockProvider{storage: store}, WithKMSClient(nil))
+		require.ErrorContains(t, err, "KMS client supporting "+testRemoteKeyURI+" not found")
+
+		registry.RegisterKMSClient(client)
+		defer registry.ClearKMSClients()
+
+		registered, err := New(testRemoteKeyURI, &mockProvider{storage: store}, WithKMSClient(nil))
+		require.NoError(t, err)
+
+		_, err = registered.Get(keyID)
+		require.NoError(t, err)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := New("gcp-kms://projects/p/locations/l/keyRings/r/cryptoKeys/k", &mockProvider{storage: store},
+			WithKMSClient(client))
+		require.ErrorContains(t, err, "KMS client doesn't support key URI 'gcp-kms://")
+
+		_, err = New(testRemoteKeyURI, &mockProvider{storage: store},
+			WithKMSClient(&mockKMSClient{getErr: errors.New("remote KMS unavailable")}))
+		require.ErrorContains(t, err, "remote KMS unavailable")
+	})
+}
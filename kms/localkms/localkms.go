@@ -8,25 +8,38 @@ package localkms
 
 import (
 	"bytes"
+	"crypto/ecdh"
 	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/rsa"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"time"
 
+	"github.com/bluele/gcache"
 	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/insecurecleartextkeyset"
 	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+	"google.golang.org/protobuf/proto"
 
 	kmsapi "github.com/trustbloc/kms-go/spi/kms"
 
 	"github.com/trustbloc/kms-go/spi/secretlock"
+	"github.com/trustbloc/kms-go/util/fips"
 
 	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
 
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
 	"github.com/trustbloc/kms-go/doc/util/jwkkid"
+	"github.com/trustbloc/kms-go/doc/util/pubkeyfmt"
+	"github.com/trustbloc/kms-go/internal/logutil"
 	"github.com/trustbloc/kms-go/kms"
-	"github.com/trustbloc/kms-go/kms/localkms/internal/keywrapper"
+	"github.com/trustbloc/kms-go/kms/audit"
 )
 
 const (
@@ -47,20 +60,33 @@ var errInvalidKeyType = errors.New("key type is not supported")
 // uses a secretLock service to protect private key material in the storage.
 
 // LocalKMS implements kms.KeyManager to provide key management capabilities using a local db.
-// It uses an underlying secret lock service (default local secretLock) to wrap (encrypt) keys
-// prior to storing them.
+// It uses an underlying secret lock service (default local secretLock), or a remote KMS set with WithKMSClient, to
+// wrap (encrypt) keys prior to storing them.
 type LocalKMS struct {
 	secretLock        secretlock.Service
 	primaryKeyURI     string
 	store             kmsapi.Store
 	primaryKeyEnvAEAD *aead.KMSEnvelopeAEAD
+	auditLogger       kmsapi.AuditLogger
+	handles           gcache.Cache
+	randomness        io.Reader
+	opts              *kmsOpts
+	keyPools          *keyPools
+	logger            *slog.Logger
 }
 
-// New will create a new (local) KMS service.
-func New(primaryKeyURI string, p kmsapi.Provider) (*LocalKMS, error) {
+// New will create a new (local) KMS service. If p is a kms.AuditLoggerProvider, its AuditLogger records the key
+// management operations.
+func New(primaryKeyURI string, p kmsapi.Provider, opts ...Opt) (*LocalKMS, error) {
 	secretLock := p.SecretLock()
 
-	kw, err := keywrapper.New(secretLock, primaryKeyURI)
+	options := &kmsOpts{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	kw, err := newKeyWrapper(secretLock, primaryKeyURI, options)
 	if err != nil {
 		return nil, fmt.Errorf("new: failed to create new keywrapper: %w", err)
 	}
@@ -68,13 +94,32 @@ func New(primaryKeyURI string, p kmsapi.Provider) (*LocalKMS, error) {
 	// create a KMSEnvelopeAEAD instance to wrap/unwrap keys managed by LocalKMS
 	keyEnvelopeAEAD := aead.NewKMSEnvelopeAEAD2(aead.AES256GCMKeyTemplate(), kw)
 
-	return &LocalKMS{
-			store:             p.StorageProvider(),
-			secretLock:        secretLock,
-			primaryKeyURI:     primaryKeyURI,
-			primaryKeyEnvAEAD: keyEnvelopeAEAD,
-		},
-		nil
+	var auditLogger kmsapi.AuditLogger
+
+	if ap, ok := p.(kmsapi.AuditLoggerProvider); ok {
+		auditLogger = ap.AuditLogger()
+	}
+
+	l := &LocalKMS{
+		store:             p.StorageProvider(),
+		secretLock:        secretLock,
+		primaryKeyURI:     primaryKeyURI,
+		primaryKeyEnvAEAD: keyEnvelopeAEAD,
+		auditLogger:       auditLogger,
+		handles:           newHandleCache(options),
+		randomness:        options.randomness,
+		opts:              options,
+		logger:            logutil.Logger(options.logger),
+	}
+
+	if len(options.keyPools) > 0 {
+		l.keyPools, err = newKeyPools(options.keyPools, l)
+		if err != nil {
+			return nil, fmt.Errorf("new: %w", err)
+		}
+	}
+
+	return l, nil
 }
 
 // HealthCheck check kms.
@@ -88,12 +133,26 @@ func (l *LocalKMS) HealthCheck() error {
 //   - handle instance (to private key)
 //   - error if failure
 func (l *LocalKMS) Create(kt kmsapi.KeyType, opts ...kmsapi.KeyOpts) (string, interface{}, error) {
+	start := time.Now()
+	keyID, kh, err := l.create(kt, opts...)
+
+	audit.Log(l.auditLogger, kmsapi.OperationCreate, keyID, nil, start, err)
+
+	return keyID, kh, err
+}
+
+func (l *LocalKMS) create(kt kmsapi.KeyType, opts ...kmsapi.KeyOpts) (string, interface{}, error) {
 	if kt == "" {
-		return "", nil, fmt.Errorf("failed to create new key, missing key type")
+		return "", nil, fmt.Errorf("failed to create new key, missing key type: %w", kmsapi.ErrInvalidKeyType)
 	}
 
 	if kt == kmsapi.ECDSASecp256k1DER {
-		return "", nil, fmt.Errorf("create: Unable to create kms key: Secp256K1 is not supported by DER format")
+		return "", nil, fmt.Errorf("create: Unable to create kms key: Secp256K1 is not supported by DER format: %w",
+			kmsapi.ErrInvalidKeyType)
+	}
+
+	if err := l.checkProfileKeyType(kt); err != nil {
+		return "", nil, fmt.Errorf("create: %w", err)
 	}
 
 	keyTemplate, err := getKeyTemplate(kt, opts...)
@@ -101,12 +160,33 @@ func (l *LocalKMS) Create(kt kmsapi.KeyType, opts ...kmsapi.KeyOpts) (string, in
 		return "", nil, fmt.Errorf("create: failed to getKeyTemplate: %w", err)
 	}
 
-	kh, err := keyset.NewHandle(keyTemplate)
+	keyOpts := kmsapi.NewKeyOpt()
+
+	for _, opt := range opts {
+		opt(keyOpts)
+	}
+
+	reuse := keyOpts.KeyID() != "" && keyOpts.ReuseExistingKey()
+
+	if reuse {
+		if kh, found, e := l.existingKey(keyOpts.KeyID(), kt, keyTemplate); found || e != nil {
+			return keyOpts.KeyID(), kh, e
+		}
+	}
+
+	kh, err := l.newKey(kt, keyTemplate, keyOpts.Attrs())
 	if err != nil {
 		return "", nil, fmt.Errorf("create: failed to create new keyset handle: %w", err)
 	}
 
 	keyID, err := l.storeKeySet(kh, kt, opts...)
+	if err != nil && reuse {
+		// the key may have been created concurrently.
+		if existing, found, e := l.existingKey(keyOpts.KeyID(), kt, keyTemplate); found || e != nil {
+			return keyOpts.KeyID(), existing, e
+		}
+	}
+
 	if err != nil {
 		return "", nil, fmt.Errorf("create: failed to store keyset: %w", err)
 	}
@@ -114,6 +194,45 @@ func (l *LocalKMS) Create(kt kmsapi.KeyType, opts ...kmsapi.KeyOpts) (string, in
 	return keyID, kh, nil
 }
 
+// existingKey returns the handle of the key keyID to reuse for the creation of a key of type kt with template, and
+// false if there is no key keyID.
+func (l *LocalKMS) existingKey(keyID string, kt kmsapi.KeyType,
+	template *tinkpb.KeyTemplate) (*keyset.Handle, bool, error) {
+	kh, err := l.getKeySet(keyID)
+	if errors.Is(err, kms.ErrKeyNotFound) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, fmt.Errorf("create: failed to get existing key '%s': %w", keyID, err)
+	}
+
+	if !l.hasKeyType(kh, kt, template) {
+		return nil, false, fmt.Errorf("create: existing key '%s' is not a %s key: %w", keyID, kt, kmsapi.ErrInvalidKeyType)
+	}
+
+	return kh, true, nil
+}
+
+// hasKeyType returns whether kh is a key of type kt: the key type of its public key for asymmetric keys, or the type
+// URL and output prefix of template of its primary key for symmetric keys (eg: the key sizes of AES keys are not
+// compared).
+func (l *LocalKMS) hasKeyType(kh *keyset.Handle, kt kmsapi.KeyType, template *tinkpb.KeyTemplate) bool {
+	if _, pubKT, err := l.exportPubKeyBytes(kh); err == nil {
+		return pubKT == kt
+	}
+
+	info := kh.KeysetInfo()
+
+	for _, ki := range info.GetKeyInfo() {
+		if ki.GetKeyId() == info.GetPrimaryKeyId() {
+			return ki.GetTypeUrl() == template.GetTypeUrl() && ki.GetOutputPrefixType() == template.GetOutputPrefixType()
+		}
+	}
+
+	return false
+}
+
 // Get key handle for the given keyID
 // Returns:
 //   - handle instance (to private key)
@@ -138,6 +257,29 @@ func (l *LocalKMS) GetWithOpts(keyID string, opts ...kmsapi.ExportKeyOpts) (any,
 //   - handle instance (to private key)
 //   - error if failure
 func (l *LocalKMS) Rotate(kt kmsapi.KeyType, keyID string, opts ...kmsapi.KeyOpts) (string, interface{}, error) {
+	start := time.Now()
+	newID, kh, err := l.rotate(kt, keyID, opts...)
+
+	audit.Log(l.auditLogger, kmsapi.OperationRotate, keyID, nil, start, err)
+
+	return newID, kh, err
+}
+
+func (l *LocalKMS) rotate(kt kmsapi.KeyType, keyID string, opts ...kmsapi.KeyOpts) (string, interface{}, error) {
+	if err := l.checkProfileKeyType(kt); err != nil {
+		return "", nil, fmt.Errorf("rotate: %w", err)
+	}
+
+	keyID, err := l.resolveKID(keyID)
+	if err != nil {
+		return "", nil, fmt.Errorf("rotate: %w", err)
+	}
+
+	claim, err := l.keySetClaim(keyID)
+	if err != nil {
+		return "", nil, fmt.Errorf("rotate: %w", err)
+	}
+
 	kh, err := l.getKeySet(keyID)
 	if err != nil {
 		return "", nil, fmt.Errorf("rotate: failed to getKeySet: %w", err)
@@ -148,19 +290,25 @@ func (l *LocalKMS) Rotate(kt kmsapi.KeyType, keyID string, opts ...kmsapi.KeyOpt
 		return "", nil, fmt.Errorf("rotate: failed to get GetKeyTemplate: %w", err)
 	}
 
-	km := keyset.NewManagerFromHandle(kh)
-
-	err = km.Rotate(keyTemplate)
+	updatedKH, err := rotateKeysetHandle(kh, keyTemplate, l.randomness)
 	if err != nil {
-		return "", nil, fmt.Errorf("rotate: failed to call Tink's keyManager rotate: %w", err)
+		return "", nil, fmt.Errorf("rotate: %w", err)
 	}
 
-	updatedKH, err := km.Handle()
-	if err != nil {
-		return "", nil, fmt.Errorf("rotate: failed to get kms keyest handle: %w", err)
+	if err = claim(); err != nil {
+		l.InvalidateKeyHandles(keyID)
+
+		return "", nil, fmt.Errorf("rotate: keyset '%s' was updated concurrently: %w", keyID, err)
+	}
+
+	if err = l.deleteKIDAliases(keyID); err != nil {
+		return "", nil, fmt.Errorf("rotate: %w", err)
 	}
 
 	err = l.store.Delete(keyID)
+
+	l.InvalidateKeyHandles(keyID)
+
 	if err != nil {
 		return "", nil, fmt.Errorf("rotate: failed to delete entry for kid '%s': %w", keyID, err)
 	}
@@ -173,24 +321,35 @@ func (l *LocalKMS) Rotate(kt kmsapi.KeyType, keyID string, opts ...kmsapi.KeyOpt
 	return newID, updatedKH, nil
 }
 
+// keySetClaim returns the function claiming the keyset stored under keyID before it is replaced, if the store is a
+// kmsapi.StoreVersioner: the keyset is stored again under the version read by keySetClaim, so only one of concurrent
+// rotations of keyID claims it and the others fail with a *kms.VersionConflictError. Otherwise, it claims nothing.
+func (l *LocalKMS) keySetClaim(keyID string) (func() error, error) {
+	versioner, ok := storeVersioner(l.store)
+	if !ok {
+		return func() error { return nil }, nil
+	}
+
+	key, metadata, version, err := versioner.GetWithVersion(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keyset version: %w", err)
+	}
+
+	return func() error {
+		return versioner.PutWithVersion(keyID, key, metadata, version)
+	}, nil
+}
+
 func (l *LocalKMS) storeKeySet(kh *keyset.Handle, kt kmsapi.KeyType, opts ...kmsapi.KeyOpts) (string, error) {
-	var (
-		kid string
-		err error
-	)
-
-	switch kt {
-	case kmsapi.AES128GCMType, kmsapi.AES256GCMType, kmsapi.AES256GCMNoPrefixType, kmsapi.ChaCha20Poly1305Type,
-		kmsapi.XChaCha20Poly1305Type, kmsapi.HMACSHA256Tag256Type, kmsapi.CLMasterSecretType:
-		// symmetric keys will have random kid value (generated in the local storeWriter)
-	case kmsapi.CLCredDefType:
-		// ignoring custom KID generation for the asymmetric CL CredDef
-	default:
-		// asymmetric keys will use the public key's JWK thumbprint base64URL encoded as kid value
-		kid, err = l.generateKID(kh, kt)
-		if err != nil && !errors.Is(err, errInvalidKeyType) {
-			return "", fmt.Errorf("storeKeySet: failed to generate kid: %w", err)
-		}
+	keyOpts := kmsapi.NewKeyOpt()
+
+	for _, opt := range opts {
+		opt(keyOpts)
+	}
+
+	kid, asymmetric, err := l.storeKeyID(kh, kt, keyOpts.KeyID(), keyOpts.KIDScheme())
+	if err != nil {
+		return "", fmt.Errorf("storeKeySet: %w", err)
 	}
 
 	buf := new(bytes.Buffer)
@@ -201,19 +360,78 @@ func (l *LocalKMS) storeKeySet(kh *keyset.Handle, kt kmsapi.KeyType, opts ...kms
 		return "", fmt.Errorf("storeKeySet: failed to write json key to buffer: %w", err)
 	}
 
-	keyOpts := kmsapi.NewKeyOpt()
+	// asymmetric keys are JWK thumbprints of the public key, base64URL encoded stored in kid, unless a KeyIDGenerator
+	// is set. symmetric keys will have a randomly generated key ID (where kid is empty), read from the custom
+	// randomness source if set.
+	if kid == "" && l.randomness != nil {
+		kid, err = newRandomKID(l.randomness)
+		if err != nil {
+			return "", fmt.Errorf("storeKeySet: %w", err)
+		}
+	}
 
-	for _, opt := range opts {
-		opt(keyOpts)
+	if kid == "" {
+		return l.writeToStore(buf, kmsapi.ImportWithMetadata(keyOpts.Metadata()))
 	}
 
-	// asymmetric keys are JWK thumbprints of the public key, base64URL encoded stored in kid.
-	// symmetric keys will have a randomly generated key ID (where kid is empty)
-	if kid != "" {
-		return writeToStore(l.store, buf, kmsapi.WithKeyID(kid), kmsapi.ImportWithMetadata(keyOpts.Metadata()))
+	id, err := l.writeToStore(buf, kmsapi.WithKeyID(kid), kmsapi.ImportWithMetadata(keyOpts.Metadata()))
+	if err != nil {
+		return "", err
 	}
 
-	return writeToStore(l.store, buf, kmsapi.ImportWithMetadata(keyOpts.Metadata()))
+	if asymmetric {
+		if err = l.storeKIDAliases(kh, kt, id); err != nil {
+			return "", fmt.Errorf("storeKeySet: %w", err)
+		}
+	}
+
+	return id, nil
+}
+
+// storeKeyID returns the key ID of the new key kh of type kt created with the requested key ID keyID and the KID
+// scheme option scheme (empty and nil if not set), empty for a random key ID, and whether it's the key ID of an
+// asymmetric key.
+func (l *LocalKMS) storeKeyID(kh *keyset.Handle, kt kmsapi.KeyType, keyID string,
+	scheme *kmsapi.KIDScheme) (string, bool, error) {
+	if keyID != "" {
+		_, _, err := l.exportPubKeyBytes(kh)
+
+		return keyID, err == nil && kt != kmsapi.CLCredDefType, nil
+	}
+
+	if l.opts.keyIDGenerator != nil {
+		return l.newKeyID(kh, kt)
+	}
+
+	switch {
+	case kmsapi.IsSymmetric(kt), kt == kmsapi.CLMasterSecretType:
+		// symmetric keys will have random kid value (generated in the local storeWriter)
+		return "", false, nil
+	case kt == kmsapi.CLCredDefType:
+		// ignoring custom KID generation for the asymmetric CL CredDef
+		return "", false, nil
+	default:
+		// asymmetric keys will use the public key's JWK thumbprint base64URL encoded (or encoded with the KID scheme
+		// of the key) as kid value
+		kid, err := l.generateKID(kh, kt, l.kidScheme(scheme))
+		if err != nil && !errors.Is(err, errInvalidKeyType) {
+			return "", false, fmt.Errorf("failed to generate kid: %w", err)
+		}
+
+		return kid, kid != "", nil
+	}
+}
+
+// writeToStore writes buf to the store and invalidates the cached handle of the written key ID, if any.
+func (l *LocalKMS) writeToStore(buf *bytes.Buffer, opts ...kmsapi.PrivateKeyOpts) (string, error) {
+	id, err := writeToStore(l.store, buf, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	l.InvalidateKeyHandles(id)
+
+	return id, nil
 }
 
 func writeToStore(store kmsapi.Store, buf *bytes.Buffer, opts ...kmsapi.PrivateKeyOpts) (string, error) {
@@ -229,41 +447,111 @@ func writeToStore(store kmsapi.Store, buf *bytes.Buffer, opts ...kmsapi.PrivateK
 }
 
 func (l *LocalKMS) getKeySet(id string) (*keyset.Handle, error) {
+	id, err := l.resolveKID(id)
+	if err != nil {
+		return nil, fmt.Errorf("getKeySet: %w", err)
+	}
+
+	if kh := l.cachedKeySet(id); kh != nil {
+		return kh, nil
+	}
+
+	if l.handles != nil {
+		l.logger.Debug("localkms: key handle cache miss", "keyID", id)
+	}
+
 	localDBReader := newReader(l.store, id)
 
 	jsonKeysetReader := keyset.NewJSONReader(localDBReader)
 
 	// Read reads the encrypted keyset handle back from the io.reader implementation
 	// and decrypts it using primaryKeyEnvAEAD.
-	kh, err := keyset.Read(jsonKeysetReader, l.primaryKeyEnvAEAD)
+	kh, err := l.readKeyset(jsonKeysetReader)
 	if err != nil {
+		l.logLoadError(id, err)
+
 		return nil, fmt.Errorf("getKeySet: failed to read json keyset from reader: %w", err)
 	}
 
+	l.logger.Debug("localkms: key loaded", "keyID", id)
+	l.cacheKeySet(id, kh)
+
 	return kh, nil
 }
 
 func (l *LocalKMS) getKeySetWithOpts(id string, opts ...kmsapi.ExportKeyOpts) (*keyset.Handle, map[string]any, error) {
+	id, err := l.resolveKID(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getKeySet: %w", err)
+	}
+
 	localDBReader := newReader(l.store, id, opts...)
 
 	jsonKeysetReader := keyset.NewJSONReader(localDBReader)
 
 	// Read reads the encrypted keyset handle back from the io.reader implementation
 	// and decrypts it using primaryKeyEnvAEAD.
-	kh, err := keyset.Read(jsonKeysetReader, l.primaryKeyEnvAEAD)
+	kh, err := l.readKeyset(jsonKeysetReader)
 	if err != nil {
+		l.logLoadError(id, err)
+
 		return nil, nil, fmt.Errorf("getKeySet: failed to read json keyset from reader: %w", err)
 	}
 
+	l.logger.Debug("localkms: key loaded", "keyID", id)
+
 	return kh, localDBReader.metadata, nil
 }
 
+// logLoadError logs the error of the load of the key id: keys that are not stored are logged at the debug level, the
+// other failures (eg: locked keysets) at the warn level.
+func (l *LocalKMS) logLoadError(id string, err error) {
+	if errors.Is(err, kmsapi.ErrKeyNotFound) {
+		l.logger.Debug("localkms: key not found", "keyID", id)
+
+		return
+	}
+
+	l.logger.Warn("localkms: key load failed", "keyID", id, "error", err)
+}
+
+// readKeyset reads the keyset encrypted with primaryKeyEnvAEAD from r. Unlike keyset.Read, it keeps the decryption
+// error chain: keysets that can't be unlocked with the master key return errors wrapping secretlock.ErrLocked.
+func (l *LocalKMS) readKeyset(r keyset.Reader) (*keyset.Handle, error) {
+	encryptedKS, err := r.ReadEncrypted()
+	if err != nil {
+		return nil, err
+	}
+
+	serializedKS, err := l.primaryKeyEnvAEAD.Decrypt(encryptedKS.GetEncryptedKeyset(), []byte{})
+	if err != nil {
+		return nil, fmt.Errorf("keyset decryption failed: %w", err)
+	}
+
+	ks := &tinkpb.Keyset{}
+
+	if err = proto.Unmarshal(serializedKS, ks); err != nil {
+		return nil, fmt.Errorf("invalid keyset: %w", err)
+	}
+
+	return insecurecleartextkeyset.Read(&keyset.MemReaderWriter{Keyset: ks})
+}
+
 // ExportPubKeyBytes will fetch a key referenced by id then gets its public key in raw bytes and returns it.
 // The key must be an asymmetric key.
 // Returns:
 //   - marshalled public key []byte
 //   - error if it fails to export the public key bytes
 func (l *LocalKMS) ExportPubKeyBytes(id string) ([]byte, kmsapi.KeyType, error) {
+	start := time.Now()
+	pubKeyBytes, kt, err := l.exportPubKeyBytesByID(id)
+
+	audit.Log(l.auditLogger, kmsapi.OperationExportPublicKey, id, nil, start, err)
+
+	return pubKeyBytes, kt, err
+}
+
+func (l *LocalKMS) exportPubKeyBytesByID(id string) ([]byte, kmsapi.KeyType, error) {
 	kh, err := l.getKeySet(id)
 	if err != nil {
 		return nil, "", fmt.Errorf("exportPubKeyBytes: failed to get keyset handle: %w", err)
@@ -316,6 +604,21 @@ func (l *LocalKMS) exportPubKeyBytes(kh *keyset.Handle) ([]byte, kmsapi.KeyType,
 	return buf.Bytes(), pubKeyWriter.KeyType, nil
 }
 
+// ExportPubKey returns the public key of the key id encoded in format, see kmsapi.PubKeyFormat.
+func (l *LocalKMS) ExportPubKey(id string, format kmsapi.PubKeyFormat) ([]byte, error) {
+	pubKeyBytes, kt, err := l.ExportPubKeyBytes(id)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := pubkeyfmt.Encode(id, pubKeyBytes, kt, format)
+	if err != nil {
+		return nil, fmt.Errorf("exportPubKey: %w", err)
+	}
+
+	return encoded, nil
+}
+
 // CreateAndExportPubKeyBytes will create a key of type kt and export its public key in raw bytes and returns it.
 // The key must be an asymmetric key.
 // Returns:
@@ -341,13 +644,22 @@ func (l *LocalKMS) CreateAndExportPubKeyBytes(kt kmsapi.KeyType, opts ...kmsapi.
 // Note: The key handle created is not stored in the KMS, it's only useful to execute the crypto primitive
 // associated with it.
 func (l *LocalKMS) PubKeyBytesToHandle(pubKey []byte, kt kmsapi.KeyType, opts ...kmsapi.KeyOpts) (interface{}, error) {
+	if err := l.checkProfileKeyType(kt); err != nil {
+		return nil, fmt.Errorf("pubKeyBytesToHandle: %w", err)
+	}
+
 	return PublicKeyBytesToHandle(pubKey, kt, opts...)
 }
 
 // ImportPrivateKey will import privKey into the KMS storage for the given keyType then returns the new key id and
 // the newly persisted Handle.
-// 'privKey' possible types are: *ecdsa.PrivateKey and ed25519.PrivateKey
-// 'keyType' possible types are signing key types only (ECDSA keys or Ed25519)
+// 'privKey' possible types are: *ecdsa.PrivateKey, ed25519.PrivateKey, *bbs12381g2pub.PrivateKey, *rsa.PrivateKey,
+// *ecdh.PrivateKey (X25519), *jwk.JWK and []byte. []byte keys are either raw HMAC or AES-KW keys, or encoded private
+// keys: PEM (PKCS#8, SEC1 or PKCS#1), DER (PKCS#8, SEC1 or PKCS#1) or JSON JWK
+// 'keyType' possible types are signing key types (ECDSA, Ed25519, BBS+ or RSA keys), NIST P and X25519 ECDH KW,
+// RSA-OAEP, HMAC and AES-KW keys. It can be empty for encoded and JWK keys, the key type is then detected from the
+// key: DER ECDSA types for PEM and DER EC keys, IEEE P1363 ECDSA types for JWK EC keys, ED25519Type, RSAPS256Type,
+// BLS12381G2Type or X25519ECDHKWType
 // 'opts' allows setting the keysetID of the imported key using WithKeyID() option. If the ID is already used,
 // then an error is returned.
 // Returns:
@@ -356,6 +668,27 @@ func (l *LocalKMS) PubKeyBytesToHandle(pubKey []byte, kt kmsapi.KeyType, opts ..
 //   - error if import failure (key empty, invalid, doesn't match keyType, unsupported keyType or storing key failed)
 func (l *LocalKMS) ImportPrivateKey(privKey interface{}, kt kmsapi.KeyType,
 	opts ...kmsapi.PrivateKeyOpts) (string, interface{}, error) {
+	start := time.Now()
+	keyID, kh, err := l.importPrivateKey(privKey, kt, opts...)
+
+	audit.Log(l.auditLogger, kmsapi.OperationImportPrivate, keyID, nil, start, err)
+
+	return keyID, kh, err
+}
+
+func (l *LocalKMS) importPrivateKey(privKey interface{}, kt kmsapi.KeyType,
+	opts ...kmsapi.PrivateKeyOpts) (string, interface{}, error) {
+	if err := fips.CheckKeyType(kt); err != nil {
+		return "", nil, fmt.Errorf("import private key: %w", err)
+	}
+
+	// an empty kt is resolved from encoded and JWK keys, which are imported again with the resolved key type.
+	if kt != "" {
+		if err := l.checkProfileKeyType(kt); err != nil {
+			return "", nil, fmt.Errorf("import private key: %w", err)
+		}
+	}
+
 	switch pk := privKey.(type) {
 	case *ecdsa.PrivateKey:
 		return l.importECDSAKey(pk, kt, opts...)
@@ -363,16 +696,29 @@ func (l *LocalKMS) ImportPrivateKey(privKey interface{}, kt kmsapi.KeyType,
 		return l.importEd25519Key(pk, kt, opts...)
 	case *bbs12381g2pub.PrivateKey:
 		return l.importBBSKey(pk, kt, opts...)
+	case *rsa.PrivateKey:
+		return l.importRSAKey(pk, kt, opts...)
+	case *ecdh.PrivateKey:
+		return l.importX25519Key(pk, kt, opts...)
+	case *jwk.JWK:
+		return l.importJWK(pk, kt, opts...)
+	case []byte:
+		if !isSecretKeyType(kt) && isEncodedPrivateKey(pk) {
+			return l.importEncodedKey(pk, kt, opts...)
+		}
+
+		return l.importSecretKey(pk, kt, opts...)
 	default:
-		return "", nil, fmt.Errorf("import private key does not support this key type or key is public")
+		return "", nil, fmt.Errorf("import private key does not support this key type or key is public: %w",
+			kmsapi.ErrInvalidKeyType)
 	}
 }
 
-func (l *LocalKMS) generateKID(kh *keyset.Handle, kt kmsapi.KeyType) (string, error) {
+func (l *LocalKMS) generateKID(kh *keyset.Handle, kt kmsapi.KeyType, scheme kmsapi.KIDScheme) (string, error) {
 	keyBytes, _, err := l.exportPubKeyBytes(kh)
 	if err != nil {
 		return "", fmt.Errorf("generateKID: failed to export public key: %w", err)
 	}
 
-	return jwkkid.CreateKID(keyBytes, kt)
+	return jwkkid.CreateKID(keyBytes, kt, jwkkid.WithScheme(scheme))
 }
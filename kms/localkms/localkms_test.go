@@ -7,6 +7,7 @@
 package localkms
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/elliptic"
@@ -34,6 +35,7 @@ import (
 
 	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
 	"github.com/trustbloc/kms-go/kms"
+	"github.com/trustbloc/kms-go/kms/audit"
 	"github.com/trustbloc/kms-go/kms/localkms/internal/keywrapper"
 	mocksecretlock "github.com/trustbloc/kms-go/mock/secretlock"
 	"github.com/trustbloc/kms-go/secretlock/local"
@@ -72,6 +74,16 @@ func (i *inMemoryKMSStore) Delete(keysetID string) error {
 	return nil
 }
 
+func (i *inMemoryKMSStore) KeyIDs() ([]string, error) {
+	keyIDs := make([]string, 0, len(i.keys))
+
+	for keyID := range i.keys {
+		keyIDs = append(keyIDs, keyID)
+	}
+
+	return keyIDs, nil
+}
+
 type mockStore struct {
 	errPut error
 	errGet error
@@ -244,7 +256,8 @@ func TestCreateGetRotateKey_Failure(t *testing.T) {
 		// try to create and export an unsupported key type.
 		_, _, err = kmsStorage.CreateAndExportPubKeyBytes("unsupported")
 		require.EqualError(t, err, "createAndExportPubKeyBytes: failed to create new key: create: failed to "+
-			"getKeyTemplate: getKeyTemplate: key type 'unsupported' unrecognized")
+			"getKeyTemplate: getKeyTemplate: key type 'unsupported' unrecognized: invalid key type")
+		require.ErrorIs(t, err, kmsapi.ErrInvalidKeyType)
 
 		// try to create and export a supported key type, but does not support export.
 		_, _, err = kmsStorage.CreateAndExportPubKeyBytes(kmsapi.HMACSHA256Tag256)
@@ -299,6 +312,57 @@ func TestEncryptRotateDecrypt_Success(t *testing.T) {
 	}
 }
 
+func TestRotate_ConcurrentRotation(t *testing.T) {
+	store := newVersionedStore()
+
+	newKMS := func(tenantID string) *LocalKMS {
+		k, err := New(testMasterKeyURI, &mockProvider{storage: store, secretLock: &noop.NoLock{}})
+		require.NoError(t, err)
+
+		if tenantID == "" {
+			return k
+		}
+
+		tk, err := k.NewTenantKMS(tenantID)
+		require.NoError(t, err)
+
+		return tk
+	}
+
+	for _, tenantID := range []string{"", "tenant1"} {
+		t.Run("tenant '"+tenantID+"'", func(t *testing.T) {
+			kms1, kms2 := newKMS(tenantID), newKMS(tenantID)
+
+			keyID, _, err := kms1.Create(kmsapi.AES256GCMType)
+			require.NoError(t, err)
+
+			// kms2 rotates the key while kms1 is rotating it.
+			var rotatedID string
+
+			store.beforePut = func() {
+				var e error
+
+				rotatedID, _, e = kms2.Rotate(kmsapi.AES256GCMType, keyID)
+				require.NoError(t, e)
+			}
+
+			_, _, err = kms1.Rotate(kmsapi.AES256GCMType, keyID)
+			require.ErrorContains(t, err, "rotate: keyset '"+keyID+"' was updated concurrently")
+
+			var conflict *kms.VersionConflictError
+
+			require.ErrorAs(t, err, &conflict)
+
+			// only the rotation of kms2 is stored.
+			_, err = kms1.Get(keyID)
+			require.ErrorIs(t, err, kms.ErrKeyNotFound)
+
+			_, err = kms1.Get(rotatedID)
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestLocalKMS_Success(t *testing.T) {
 	// create a real (not mocked) master key and secret lock to test the KMS end to end
 	sl := createMasterKeyAndSecretLock(t)
@@ -323,6 +387,7 @@ func TestLocalKMS_Success(t *testing.T) {
 		kmsapi.AES256GCMType,
 		kmsapi.ChaCha20Poly1305Type,
 		kmsapi.XChaCha20Poly1305Type,
+		kmsapi.AESGCMSIV256Type,
 		kmsapi.ECDSAP256TypeDER,
 		kmsapi.ECDSAP384TypeDER,
 		kmsapi.ECDSAP521TypeDER,
@@ -337,13 +402,16 @@ func TestLocalKMS_Success(t *testing.T) {
 		kmsapi.BLS12381G2Type,
 		kmsapi.ECDSASecp256k1DER,
 		kmsapi.ECDSASecp256k1IEEEP1363,
+		kmsapi.BIP340Secp256k1Type,
 	}
 
 	for _, v := range keyTemplates {
 		if v == kmsapi.ECDSASecp256k1DER {
 			t.Logf("testing create for %s", v)
 			_, _, e := kmsService.Create(v)
-			require.EqualError(t, e, "create: Unable to create kms key: Secp256K1 is not supported by DER format")
+			require.EqualError(t, e, "create: Unable to create kms key: Secp256K1 is not supported by DER format: "+
+				"invalid key type")
+			require.ErrorIs(t, e, kmsapi.ErrInvalidKeyType)
 
 			continue
 		}
@@ -429,7 +497,7 @@ func TestLocalKMS_ImportPrivateKey(t *testing.T) { // nolint:gocyclo
 
 	// test import with nil key
 	_, _, err := kmsService.ImportPrivateKey(nil, kmsapi.ECDSAP256TypeDER)
-	require.EqualError(t, err, "import private key does not support this key type or key is public")
+	require.EqualError(t, err, "import private key does not support this key type or key is public: invalid key type")
 
 	flagTests := []struct {
 		tcName  string
@@ -637,6 +705,35 @@ func TestLocalKMS_getKeyTemplate(t *testing.T) {
 	require.Equal(t, "type.googleapis.com/google.crypto.tink.HmacKey", keyTemplate.TypeUrl)
 }
 
+func TestLocalKMS_Errors(t *testing.T) {
+	store := newInMemoryKMSStore()
+
+	kmsService, err := New(testMasterKeyURI, &mockProvider{
+		storage:    store,
+		secretLock: createMasterKeyAndSecretLock(t),
+	})
+	require.NoError(t, err)
+
+	keyID, _, err := kmsService.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	_, err = kmsService.Get("unknown")
+	require.ErrorIs(t, err, kmsapi.ErrKeyNotFound)
+
+	_, _, err = kmsService.Create("unknown")
+	require.ErrorIs(t, err, kmsapi.ErrInvalidKeyType)
+
+	// the keys can't be read with another master key.
+	otherKMS, err := New(testMasterKeyURI, &mockProvider{
+		storage:    store,
+		secretLock: createMasterKeyAndSecretLock(t),
+	})
+	require.NoError(t, err)
+
+	_, err = otherKMS.Get(keyID)
+	require.ErrorIs(t, err, secretlock.ErrLocked)
+}
+
 func createMasterKeyAndSecretLock(t *testing.T) secretlock.Service {
 	t.Helper()
 
@@ -705,3 +802,83 @@ func (m *mockProvider) StorageProvider() kmsapi.Store {
 func (m *mockProvider) SecretLock() secretlock.Service {
 	return m.secretLock
 }
+
+func TestLocalKMS_Capabilities(t *testing.T) {
+	kmsService, err := New(testMasterKeyURI, &mockProvider{
+		storage:    newInMemoryKMSStore(),
+		secretLock: &noop.NoLock{},
+	})
+	require.NoError(t, err)
+
+	caps, err := kmsService.Capabilities()
+	require.NoError(t, err)
+	require.NotEmpty(t, caps.KeyTypes)
+
+	for _, kt := range caps.KeyTypesFor(kmsapi.OperationCreate) {
+		keyID, _, err := kmsService.Create(kt)
+		require.NoError(t, err, "key type %s", kt)
+
+		if caps.Supports(kt, kmsapi.OperationExportPublicKey) {
+			_, _, err = kmsService.ExportPubKeyBytes(keyID)
+			require.NoError(t, err, "key type %s", kt)
+		}
+	}
+
+	require.True(t, caps.Supports(kmsapi.ED25519Type, kmsapi.OperationImportPrivate))
+	require.False(t, caps.Supports(kmsapi.AES256GCMType, kmsapi.OperationExportPublicKey))
+}
+
+type auditProvider struct {
+	mockProvider
+	logger kmsapi.AuditLogger
+}
+
+func (a *auditProvider) AuditLogger() kmsapi.AuditLogger {
+	return a.logger
+}
+
+func TestLocalKMS_AuditLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := audit.NewJSONLogger(buf)
+
+	kmsService, err := New(testMasterKeyURI, &auditProvider{
+		mockProvider: mockProvider{storage: newInMemoryKMSStore(), secretLock: &noop.NoLock{}},
+		logger:       logger,
+	})
+	require.NoError(t, err)
+
+	keyID, kh, err := kmsService.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	_, _, err = kmsService.ExportPubKeyBytes("unknown")
+	require.Error(t, err)
+
+	c, err := tinkcrypto.New(tinkcrypto.WithAuditLogger(logger))
+	require.NoError(t, err)
+
+	_, err = c.Sign([]byte("msg"), kh)
+	require.NoError(t, err)
+	require.NoError(t, logger.Err())
+
+	_, err = audit.Verify(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+
+	var records []audit.Record
+
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		rec := audit.Record{}
+		require.NoError(t, json.Unmarshal(line, &rec))
+
+		records = append(records, rec)
+	}
+
+	require.Len(t, records, 3)
+	require.Equal(t, kmsapi.OperationCreate, records[0].Op)
+	require.Equal(t, keyID, records[0].KeyID)
+	require.True(t, records[0].Success)
+	require.Equal(t, kmsapi.OperationExportPublicKey, records[1].Op)
+	require.Equal(t, "unknown", records[1].KeyID)
+	require.False(t, records[1].Success)
+	require.Equal(t, kmsapi.OperationSign, records[2].Op)
+	require.True(t, records[2].Success)
+}
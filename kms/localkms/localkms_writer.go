@@ -63,7 +63,21 @@ func (l *storeWriter) Write(p []byte) (int, error) {
 		}
 	}
 
-	if len(l.metadata) != 0 {
+	// stores shared by concurrent KeyManagers create the keyset atomically, in case the ID was used concurrently: a
+	// versioned store only creates it if no key is stored (version 0).
+	var conflict *kms.VersionConflictError
+
+	if versioner, ok := storeVersioner(l.storage); ok {
+		err = versioner.PutWithVersion(ksID, p, l.metadata, 0)
+		if errors.As(err, &conflict) {
+			return 0, fmt.Errorf("requested ID '%s' already exists, cannot write keyset", ksID)
+		}
+	} else if creator, ok := l.storage.(kmsapi.StoreCreator); ok {
+		err = creator.Create(ksID, p, l.metadata)
+		if errors.Is(err, kms.ErrKeyExists) {
+			return 0, fmt.Errorf("requested ID '%s' already exists, cannot write keyset", ksID)
+		}
+	} else if len(l.metadata) != 0 {
 		metadataStorage, ok := l.storage.(kmsapi.StoreWithMetadata)
 		if !ok {
 			return 0, fmt.Errorf("requested to save 'metadata', but storage doesn't support it")
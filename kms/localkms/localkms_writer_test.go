@@ -111,4 +111,113 @@ func TestLocalKMSWriter(t *testing.T) {
 		require.EqualError(t, err, fmt.Sprintf("requested ID '%s' already exists, cannot write keyset",
 			l.KeysetID))
 	})
+
+	t.Run("store creator - concurrently created keysetID", func(t *testing.T) {
+		mockStore := &creatorStore{inMemoryKMSStore: newInMemoryKMSStore()}
+
+		l := newWriter(mockStore, kmsapi.WithKeyID("kid"), kmsapi.ImportWithMetadata(map[string]any{"k": "v"}))
+		_, err := l.Write([]byte("someKeyData"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("someKeyData"), mockStore.keys["kid"])
+		require.Equal(t, map[string]any{"k": "v"}, mockStore.metadata["kid"])
+
+		// the keysetID is created by another writer after verifyRequestedID.
+		mockStore.createErr = fmt.Errorf("create: %w", kms.ErrKeyExists)
+
+		_, err = newWriter(mockStore, kmsapi.WithKeyID("other")).Write([]byte("someKeyData"))
+		require.EqualError(t, err, "requested ID 'other' already exists, cannot write keyset")
+	})
+}
+
+// creatorStore is an inMemoryKMSStore implementing kms.StoreCreator.
+type creatorStore struct {
+	*inMemoryKMSStore
+	metadata  map[string]map[string]any
+	createErr error
+}
+
+func (c *creatorStore) Create(keysetID string, key []byte, metadata map[string]any) error {
+	if c.createErr != nil {
+		return c.createErr
+	}
+
+	if _, ok := c.keys[keysetID]; ok {
+		return kms.ErrKeyExists
+	}
+
+	if c.metadata == nil {
+		c.metadata = map[string]map[string]any{}
+	}
+
+	c.keys[keysetID], c.metadata[keysetID] = key, metadata
+
+	return nil
+}
+
+// versionedStore is an inMemoryKMSStore implementing kms.StoreVersioner, beforePut is called once by the next
+// PutWithVersion call before the version is checked.
+type versionedStore struct {
+	*inMemoryKMSStore
+	metadata  map[string]map[string]any
+	versions  map[string]int64
+	beforePut func()
+}
+
+func newVersionedStore() *versionedStore {
+	return &versionedStore{
+		inMemoryKMSStore: newInMemoryKMSStore(),
+		metadata:         map[string]map[string]any{},
+		versions:         map[string]int64{},
+	}
+}
+
+func (v *versionedStore) Delete(keysetID string) error {
+	delete(v.versions, keysetID)
+	delete(v.metadata, keysetID)
+
+	return v.inMemoryKMSStore.Delete(keysetID)
+}
+
+func (v *versionedStore) GetWithVersion(keysetID string) ([]byte, map[string]any, int64, error) {
+	key, err := v.Get(keysetID)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return key, v.metadata[keysetID], v.versions[keysetID], nil
+}
+
+func (v *versionedStore) PutWithVersion(keysetID string, key []byte, metadata map[string]any,
+	expectedVersion int64) error {
+	if f := v.beforePut; f != nil {
+		v.beforePut = nil
+
+		f()
+	}
+
+	if v.versions[keysetID] != expectedVersion {
+		return fmt.Errorf("put: %w", &kms.VersionConflictError{Expected: expectedVersion, Actual: v.versions[keysetID]})
+	}
+
+	v.keys[keysetID], v.metadata[keysetID] = key, metadata
+	v.versions[keysetID]++
+
+	return nil
+}
+
+func TestLocalKMSWriter_StoreVersioner(t *testing.T) {
+	mockStore := newVersionedStore()
+
+	l := newWriter(mockStore, kmsapi.WithKeyID("kid"), kmsapi.ImportWithMetadata(map[string]any{"k": "v"}))
+	_, err := l.Write([]byte("someKeyData"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("someKeyData"), mockStore.keys["kid"])
+	require.Equal(t, map[string]any{"k": "v"}, mockStore.metadata["kid"])
+	require.Equal(t, int64(1), mockStore.versions["kid"])
+
+	// the keysetID is created by another writer after verifyRequestedID.
+	mockStore.beforePut = func() { mockStore.versions["other"] = 1 }
+
+	_, err = newWriter(mockStore, kmsapi.WithKeyID("other")).Write([]byte("someKeyData"))
+	require.EqualError(t, err, "requested ID 'other' already exists, cannot write keyset")
 }
@@ -0,0 +1,20 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"log/slog"
+)
+
+// WithLogger sets the logger of the LocalKMS debug events (key loads, key handle cache misses and key pool misses)
+// and warn events (keys that can't be loaded and stopped key pools). The key material is never logged, the events
+// carry the key IDs and key types only. Without logger the events are discarded.
+func WithLogger(logger *slog.Logger) Opt {
+	return func(opts *kmsOpts) {
+		opts.logger = logger
+	}
+}
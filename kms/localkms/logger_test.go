@@ -0,0 +1,74 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+func TestLocalKMS_WithLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	store := newInMemoryKMSStore()
+
+	k, err := New(testMasterKeyURI, &mockProvider{storage: store, secretLock: &noop.NoLock{}},
+		WithLogger(logger), WithKeyHandleCache(10, time.Minute))
+	require.NoError(t, err)
+
+	keyID, _, err := k.Create(kmsapi.AES256GCMType)
+	require.NoError(t, err)
+
+	_, err = k.Get(keyID)
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), `level=DEBUG msg="localkms: key handle cache miss" keyID=`+keyID)
+	require.Contains(t, buf.String(), `level=DEBUG msg="localkms: key loaded" keyID=`+keyID)
+
+	buf.Reset()
+
+	// cached handle.
+	_, err = k.Get(keyID)
+	require.NoError(t, err)
+	require.Empty(t, buf.String())
+
+	_, err = k.Get("unknown")
+	require.ErrorIs(t, err, kmsapi.ErrKeyNotFound)
+	require.Contains(t, buf.String(), `level=DEBUG msg="localkms: key not found" keyID=unknown`)
+
+	buf.Reset()
+
+	// the keys can't be read with another master key.
+	other, err := New(testMasterKeyURI, &mockProvider{storage: store, secretLock: createMasterKeyAndSecretLock(t)},
+		WithLogger(logger))
+	require.NoError(t, err)
+
+	_, err = other.Get(keyID)
+	require.Error(t, err)
+	require.Contains(t, buf.String(), `level=WARN msg="localkms: key load failed" keyID=`+keyID)
+
+	buf.Reset()
+
+	t.Run("key pools", func(t *testing.T) {
+		pooled, e := New(testMasterKeyURI, &mockProvider{storage: newInMemoryKMSStore(), secretLock: &noop.NoLock{}},
+			WithLogger(logger), WithKeyPool(kmsapi.ED25519Type, 1))
+		require.NoError(t, e)
+
+		require.NoError(t, pooled.Close())
+
+		_, _, e = pooled.Create(kmsapi.ED25519Type)
+		require.NoError(t, e)
+		require.Contains(t, buf.String(), `level=DEBUG msg="localkms: key pool empty, generating the key" `+
+			`keyType=ED25519`)
+	})
+}
@@ -0,0 +1,243 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/spi/kms"
+
+	ecdhpb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
+)
+
+const (
+	x25519ECDHKWPrivateKeyTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.X25519EcdhKwPrivateKey"
+	asn1Sequence                  = 0x30
+)
+
+// isEncodedPrivateKey returns true if data looks like a PEM block, a JSON object (JWK) or a DER (PKCS#8, SEC1 or
+// PKCS#1) private key rather than raw secret key bytes.
+func isEncodedPrivateKey(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+
+	return bytes.HasPrefix(trimmed, []byte("-----BEGIN ")) || bytes.HasPrefix(trimmed, []byte("{")) ||
+		(len(data) > 0 && data[0] == asn1Sequence)
+}
+
+// importEncodedKey imports a PEM, DER or JWK encoded private key. An empty kt is replaced by the key type detected
+// from the key.
+func (l *LocalKMS) importEncodedKey(data []byte, kt kms.KeyType,
+	opts ...kms.PrivateKeyOpts) (string, interface{}, error) {
+	privKey, detected, err := decodePrivateKey(data)
+	if err != nil {
+		return "", nil, fmt.Errorf("import encoded private key failed: %w", err)
+	}
+
+	if kt == "" {
+		kt = detected
+	}
+
+	return l.ImportPrivateKey(privKey, kt, opts...)
+}
+
+// importJWK imports the private key of j. An empty kt is replaced by the key type of the JWK.
+func (l *LocalKMS) importJWK(j *jwk.JWK, kt kms.KeyType, opts ...kms.PrivateKeyOpts) (string, interface{}, error) {
+	if j == nil || !isPrivateKey(j.Key) {
+		return "", nil, fmt.Errorf("import private key does not support this key type or key is public: %w",
+			kms.ErrInvalidKeyType)
+	}
+
+	if kt == "" {
+		detected, err := j.KeyType()
+		if err != nil {
+			return "", nil, fmt.Errorf("import JWK private key failed: %w", err)
+		}
+
+		kt = detected
+	}
+
+	return l.ImportPrivateKey(j.Key, kt, opts...)
+}
+
+func (l *LocalKMS) importX25519Key(privKey *ecdh.PrivateKey, kt kms.KeyType,
+	opts ...kms.PrivateKeyOpts) (string, *keyset.Handle, error) {
+	if privKey == nil || privKey.Curve() != ecdh.X25519() {
+		return "", nil, fmt.Errorf("import private X25519 key failed: private key is not a X25519 key")
+	}
+
+	if kt != kms.X25519ECDHKWType {
+		return "", nil, fmt.Errorf("import private X25519 key failed: %w", kms.ErrInvalidKeyType)
+	}
+
+	template, err := keyTemplate(kt)
+	if err != nil {
+		return "", nil, fmt.Errorf("import private X25519 key failed: %w", err)
+	}
+
+	keyFormat := new(ecdhpb.EcdhAeadKeyFormat)
+
+	err = proto.Unmarshal(template.Value, keyFormat)
+	if err != nil {
+		return "", nil, fmt.Errorf("import private X25519 key failed: invalid key format")
+	}
+
+	privBytes, err := proto.Marshal(&ecdhpb.EcdhAeadPrivateKey{
+		Version:  0,
+		KeyValue: privKey.Bytes(),
+		PublicKey: &ecdhpb.EcdhAeadPublicKey{
+			Version: 0,
+			Params:  keyFormat.Params,
+			X:       privKey.PublicKey().Bytes(),
+		},
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("import private X25519 key failed: %w", err)
+	}
+
+	ks := newKeySet(x25519ECDHKWPrivateKeyTypeURL, privBytes, tinkpb.KeyData_ASYMMETRIC_PRIVATE)
+
+	return l.importKeySet(ks, opts...)
+}
+
+// decodePrivateKey parses a PEM (PKCS#8, SEC1 or PKCS#1), DER (PKCS#8, SEC1 or PKCS#1) or JWK private key and
+// returns it with its detected key type.
+func decodePrivateKey(data []byte) (interface{}, kms.KeyType, error) {
+	trimmed := bytes.TrimSpace(data)
+
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		return decodeJWKPrivateKey(trimmed)
+	}
+
+	if block, _ := pem.Decode(trimmed); block != nil {
+		if _, encrypted := block.Headers["Proc-Type"]; encrypted || block.Type == "ENCRYPTED PRIVATE KEY" {
+			return nil, "", errors.New("encrypted PEM private keys are not supported")
+		}
+
+		return decodeDERPrivateKey(block.Bytes)
+	}
+
+	return decodeDERPrivateKey(data)
+}
+
+func decodeDERPrivateKey(der []byte) (interface{}, kms.KeyType, error) {
+	var (
+		key interface{}
+		err error
+	)
+
+	if key, err = x509.ParsePKCS8PrivateKey(der); err != nil {
+		if key, err = x509.ParseECPrivateKey(der); err != nil {
+			if key, err = x509.ParsePKCS1PrivateKey(der); err != nil {
+				return nil, "", errors.New("unrecognized PKCS#8, SEC1 or PKCS#1 private key")
+			}
+		}
+	}
+
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		kt, err := ecdsaDERKeyType(k.Curve)
+
+		return k, kt, err
+	case ed25519.PrivateKey:
+		return k, kms.ED25519Type, nil
+	case *rsa.PrivateKey:
+		return k, kms.RSAPS256Type, nil
+	case *ecdh.PrivateKey:
+		if k.Curve() != ecdh.X25519() {
+			return nil, "", errors.New("unsupported ECDH curve")
+		}
+
+		return k, kms.X25519ECDHKWType, nil
+	default:
+		return nil, "", fmt.Errorf("unsupported private key type %T: %w", key, kms.ErrInvalidKeyType)
+	}
+}
+
+func ecdsaDERKeyType(curve elliptic.Curve) (kms.KeyType, error) {
+	switch curve {
+	case elliptic.P256():
+		return kms.ECDSAP256TypeDER, nil
+	case elliptic.P384():
+		return kms.ECDSAP384TypeDER, nil
+	case elliptic.P521():
+		return kms.ECDSAP521TypeDER, nil
+	default:
+		return "", errors.New("unsupported EC curve")
+	}
+}
+
+// okpJWK is the part of an OKP JWK needed to read X25519 private keys, which jwk.JWK only reads as public keys.
+type okpJWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	D   string `json:"d"`
+}
+
+func decodeJWKPrivateKey(data []byte) (interface{}, kms.KeyType, error) {
+	okp := &okpJWK{}
+
+	if err := json.Unmarshal(data, okp); err != nil {
+		return nil, "", fmt.Errorf("unable to read JWK: %w", err)
+	}
+
+	if okp.Kty == "OKP" && okp.Crv == "X25519" {
+		d, err := base64.RawURLEncoding.DecodeString(okp.D)
+		if err != nil || len(d) == 0 {
+			return nil, "", errors.New("X25519 JWK is missing a valid private key")
+		}
+
+		key, err := ecdh.X25519().NewPrivateKey(d)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid X25519 JWK private key: %w", err)
+		}
+
+		return key, kms.X25519ECDHKWType, nil
+	}
+
+	j := &jwk.JWK{}
+
+	if err := j.UnmarshalJSON(data); err != nil {
+		return nil, "", err
+	}
+
+	if !isPrivateKey(j.Key) {
+		return nil, "", errors.New("JWK is not a private key")
+	}
+
+	kt, err := j.KeyType()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return j.Key, kt, nil
+}
+
+func isPrivateKey(key interface{}) bool {
+	switch key.(type) {
+	case *ecdsa.PrivateKey, ed25519.PrivateKey, *rsa.PrivateKey, *bbs12381g2pub.PrivateKey, *ecdh.PrivateKey:
+		return true
+	default:
+		return false
+	}
+}
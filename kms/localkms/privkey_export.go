@@ -0,0 +1,349 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/golang/protobuf/proto"
+	hybrid "github.com/google/tink/go/hybrid/subtle"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/kwp/subtle"
+	gcmpb "github.com/google/tink/go/proto/aes_gcm_go_proto"
+	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
+	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+	rsapb "github.com/google/tink/go/proto/rsa_ssa_pkcs1_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aeskw"
+	ecdhpb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/rsaoaep"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/rsapss"
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/internal/memguard"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/kms/audit"
+)
+
+// wrappedKeyContentType is the JWE content type of the wrapped private keys.
+const wrappedKeyContentType = "pkcs8"
+
+// ExportPrivateKey exports the private key keyID as a PKCS#8 DER blob protected by wrappingKey, for key escrow or to
+// transfer a single key to another KMS:
+//   - a string wrappingKey is the key ID of an AES128KWType or AES256KWType key of l (eg: a KEK imported on both KMS
+//     instances): the blob is the PKCS#8 key wrapped with AES-KWP (RFC 5649).
+//   - a *jwk.JWK wrappingKey is an EC (NIST P curves) or RSA public key of the recipient: the blob is a compact JWE
+//     (ECDH-ES+A256KW or RSA-OAEP-256, A256GCM) of the PKCS#8 key, with the "pkcs8" content type.
+//
+// Dual control of the escrowed keys is obtained by protecting the KEK or the recipient private key (eg: split with a
+// key ceremony), the blob itself being safe to store or transfer. Only the primary key of the keyset is exported: the
+// Ed25519, ECDSA, RSA and ECDH-KW (NIST P curves and X25519) keys can be exported.
+func (l *LocalKMS) ExportPrivateKey(keyID string, wrappingKey interface{}) ([]byte, error) {
+	start := time.Now()
+	blob, err := l.exportPrivateKey(keyID, wrappingKey)
+
+	audit.Log(l.auditLogger, kmsapi.OperationExportPrivate, keyID, nil, start, err)
+
+	return blob, err
+}
+
+func (l *LocalKMS) exportPrivateKey(keyID string, wrappingKey interface{}) ([]byte, error) {
+	privKey, err := l.primaryPrivateKey(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("export private key: %w", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privKey)
+	if err != nil {
+		return nil, fmt.Errorf("export private key: %w", err)
+	}
+
+	defer memguard.Wipe(der)
+
+	var blob []byte
+
+	switch wk := wrappingKey.(type) {
+	case string:
+		blob, err = l.wrapWithKEK(wk, der)
+	case *jwk.JWK:
+		blob, err = wrapWithJWE(wk, der)
+	default:
+		err = fmt.Errorf("unsupported wrapping key type %T", wrappingKey)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("export private key: %w", err)
+	}
+
+	return blob, nil
+}
+
+// ImportWrappedKey imports a private key blob exported by ExportPrivateKey. unwrappingKeyID is the key ID of the key
+// of l unwrapping the blob: the AES-KW KEK, or the NIST P ECDH-KW or RSA-OAEP-256 private key of the JWE recipient.
+//
+// An empty kt is replaced by the key type detected from the PKCS#8 key, NIST P ECDH-KW keys being detected as ECDSA
+// keys. 'opts' allows setting the keysetID of the imported key using WithKeyID() option.
+// Returns:
+//   - keyID of the handle
+//   - handle instance (to private key)
+//   - error if the blob can't be unwrapped or the import failed
+func (l *LocalKMS) ImportWrappedKey(blob []byte, unwrappingKeyID string, kt kmsapi.KeyType,
+	opts ...kmsapi.PrivateKeyOpts) (string, interface{}, error) {
+	start := time.Now()
+	keyID, kh, err := l.importWrappedKey(blob, unwrappingKeyID, kt, opts...)
+
+	audit.Log(l.auditLogger, kmsapi.OperationImportPrivate, keyID, nil, start, err)
+
+	return keyID, kh, err
+}
+
+func (l *LocalKMS) importWrappedKey(blob []byte, unwrappingKeyID string, kt kmsapi.KeyType,
+	opts ...kmsapi.PrivateKeyOpts) (string, interface{}, error) {
+	der, err := l.unwrapPrivateKey(blob, unwrappingKeyID)
+	if err != nil {
+		return "", nil, fmt.Errorf("import wrapped key: %w", err)
+	}
+
+	defer memguard.Wipe(der)
+
+	privKey, detected, err := decodeDERPrivateKey(der)
+	if err != nil {
+		return "", nil, fmt.Errorf("import wrapped key: %w", err)
+	}
+
+	if kt == "" {
+		kt = detected
+	}
+
+	return l.importPrivateKey(privKey, kt, opts...)
+}
+
+func (l *LocalKMS) unwrapPrivateKey(blob []byte, unwrappingKeyID string) ([]byte, error) {
+	key, err := l.primaryKey(unwrappingKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.KeyData.TypeUrl == aeskw.TypeURL {
+		kek, e := aesKWKey(key.KeyData)
+		if e != nil {
+			return nil, e
+		}
+
+		defer memguard.Wipe(kek)
+
+		kwp, e := subtle.NewKWP(kek)
+		if e != nil {
+			return nil, e
+		}
+
+		return kwp.Unwrap(blob)
+	}
+
+	privKey, err := privateKeyFromKeyData(key.KeyData)
+	if err != nil {
+		return nil, err
+	}
+
+	jwe, err := jose.ParseEncrypted(string(blob))
+	if err != nil {
+		return nil, fmt.Errorf("parse JWE: %w", err)
+	}
+
+	if cty, _ := jwe.Header.ExtraHeaders[jose.HeaderContentType].(string); cty != wrappedKeyContentType {
+		return nil, fmt.Errorf("JWE content type is not '%s'", wrappedKeyContentType)
+	}
+
+	return jwe.Decrypt(privKey)
+}
+
+func (l *LocalKMS) wrapWithKEK(kekID string, der []byte) ([]byte, error) {
+	key, err := l.primaryKey(kekID)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.KeyData.TypeUrl != aeskw.TypeURL {
+		return nil, fmt.Errorf("wrapping key '%s' is not an AES-KW key", kekID)
+	}
+
+	kek, err := aesKWKey(key.KeyData)
+	if err != nil {
+		return nil, err
+	}
+
+	defer memguard.Wipe(kek)
+
+	kwp, err := subtle.NewKWP(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	return kwp.Wrap(der)
+}
+
+func wrapWithJWE(recipient *jwk.JWK, der []byte) ([]byte, error) {
+	var alg jose.KeyAlgorithm
+
+	switch recipient.Key.(type) {
+	case *ecdsa.PublicKey:
+		alg = jose.ECDH_ES_A256KW
+	case *rsa.PublicKey:
+		alg = jose.RSA_OAEP_256
+	default:
+		return nil, fmt.Errorf("unsupported JWK wrapping key type %T", recipient.Key)
+	}
+
+	rcpt := jose.Recipient{Algorithm: alg, Key: recipient.Key, KeyID: recipient.KeyID}
+
+	enc, err := jose.NewEncrypter(jose.A256GCM, rcpt, (&jose.EncrypterOptions{}).WithContentType(wrappedKeyContentType))
+	if err != nil {
+		return nil, err
+	}
+
+	jwe, err := enc.Encrypt(der)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := jwe.CompactSerialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(s), nil
+}
+
+// primaryKey returns the primary key of the keyset keyID.
+func (l *LocalKMS) primaryKey(keyID string) (*tinkpb.Keyset_Key, error) {
+	kh, err := l.getKeySet(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the keyset is owned by kh, it is read and not wiped.
+	ks := insecurecleartextkeyset.KeysetMaterial(kh)
+
+	for _, key := range ks.Key {
+		if key.KeyId == ks.PrimaryKeyId && key.Status == tinkpb.KeyStatusType_ENABLED {
+			return key, nil
+		}
+	}
+
+	return nil, errors.New("primary key not found")
+}
+
+// primaryPrivateKey returns the primary key of the keyset keyID as a crypto/x509 PKCS#8 private key.
+func (l *LocalKMS) primaryPrivateKey(keyID string) (interface{}, error) {
+	key, err := l.primaryKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return privateKeyFromKeyData(key.KeyData)
+}
+
+func aesKWKey(kd *tinkpb.KeyData) ([]byte, error) {
+	key := &gcmpb.AesGcmKey{}
+
+	if err := proto.Unmarshal(kd.Value, key); err != nil {
+		return nil, fmt.Errorf("invalid AES-KW key: %w", err)
+	}
+
+	return key.KeyValue, nil
+}
+
+//nolint:gocyclo
+func privateKeyFromKeyData(kd *tinkpb.KeyData) (interface{}, error) {
+	switch kd.TypeUrl {
+	case ed25519SignerTypeURL:
+		key := &ed25519pb.Ed25519PrivateKey{}
+
+		if err := proto.Unmarshal(kd.Value, key); err != nil || len(key.KeyValue) != ed25519.SeedSize {
+			return nil, errors.New("invalid Ed25519 private key")
+		}
+
+		defer memguard.Wipe(key.KeyValue)
+
+		return ed25519.NewKeyFromSeed(key.KeyValue), nil
+	case ecdsaSignerTypeURL:
+		key := &ecdsapb.EcdsaPrivateKey{}
+
+		if err := proto.Unmarshal(kd.Value, key); err != nil || key.PublicKey == nil {
+			return nil, errors.New("invalid ECDSA private key")
+		}
+
+		return newECDSAPrivateKey(key.PublicKey.Params.GetCurve().String(), key.KeyValue, key.PublicKey.X,
+			key.PublicKey.Y)
+	case nistpECDHKWPrivateKeyTypeURL, x25519ECDHKWPrivateKeyTypeURL:
+		key := &ecdhpb.EcdhAeadPrivateKey{}
+
+		if err := proto.Unmarshal(kd.Value, key); err != nil || key.PublicKey == nil {
+			return nil, errors.New("invalid ECDH-KW private key")
+		}
+
+		if kd.TypeUrl == x25519ECDHKWPrivateKeyTypeURL {
+			defer memguard.Wipe(key.KeyValue)
+
+			return ecdh.X25519().NewPrivateKey(key.KeyValue)
+		}
+
+		return newECDSAPrivateKey(key.PublicKey.Params.GetKwParams().GetCurveType().String(), key.KeyValue,
+			key.PublicKey.X, key.PublicKey.Y)
+	case rsaSSAPKCS1SignerTypeURL, rsapss.SignerTypeURL, rsaoaep.DecrypterTypeURL:
+		key := &rsapb.RsaSsaPkcs1PrivateKey{}
+
+		if err := proto.Unmarshal(kd.Value, key); err != nil || key.PublicKey == nil {
+			return nil, errors.New("invalid RSA private key")
+		}
+
+		return newRSAPrivateKey(key)
+	default:
+		return nil, fmt.Errorf("private key type '%s' can't be exported", kd.TypeUrl)
+	}
+}
+
+func newECDSAPrivateKey(curveName string, d, x, y []byte) (*ecdsa.PrivateKey, error) {
+	curve, err := hybrid.GetCurve(curveName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)},
+		D:         new(big.Int).SetBytes(d),
+	}, nil
+}
+
+func newRSAPrivateKey(key *rsapb.RsaSsaPkcs1PrivateKey) (*rsa.PrivateKey, error) {
+	privKey := &rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{
+			N: new(big.Int).SetBytes(key.PublicKey.N),
+			E: int(new(big.Int).SetBytes(key.PublicKey.E).Int64()),
+		},
+		D:      new(big.Int).SetBytes(key.D),
+		Primes: []*big.Int{new(big.Int).SetBytes(key.P), new(big.Int).SetBytes(key.Q)},
+	}
+
+	if err := privKey.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid RSA private key: %w", err)
+	}
+
+	privKey.Precompute()
+
+	return privKey, nil
+}
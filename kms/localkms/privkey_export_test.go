@@ -0,0 +1,155 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+type privateKey interface {
+	Equal(x crypto.PrivateKey) bool
+}
+
+func TestLocalKMS_ExportImportWrappedKey(t *testing.T) {
+	source, err := New(testMasterKeyURI, &mockProvider{storage: newInMemoryKMSStore(), secretLock: &noop.NoLock{}})
+	require.NoError(t, err)
+
+	target, err := New(testMasterKeyURI, &mockProvider{storage: newInMemoryKMSStore(), secretLock: &noop.NoLock{}})
+	require.NoError(t, err)
+
+	kek := make([]byte, 32)
+	_, err = rand.Read(kek)
+	require.NoError(t, err)
+
+	for _, k := range []*LocalKMS{source, target} {
+		_, _, err = k.ImportPrivateKey(kek, kmsapi.AES256KWType, kmsapi.WithKeyID("escrow-kek"))
+		require.NoError(t, err)
+	}
+
+	recipientID, _, err := target.Create(kmsapi.NISTP256ECDHKWType)
+	require.NoError(t, err)
+
+	recipientKey, err := target.primaryPrivateKey(recipientID)
+	require.NoError(t, err)
+
+	recipient := &jwk.JWK{JSONWebKey: jose.JSONWebKey{
+		Key:   &recipientKey.(*ecdsa.PrivateKey).PublicKey,
+		KeyID: recipientID,
+	}}
+
+	tests := []struct {
+		name string
+		kt   kmsapi.KeyType
+	}{
+		{name: "Ed25519", kt: kmsapi.ED25519Type},
+		{name: "ECDSA P-384", kt: kmsapi.ECDSAP384TypeIEEEP1363},
+		{name: "RSA-PSS", kt: kmsapi.RSAPS256Type},
+		{name: "NIST P-384 ECDH-KW", kt: kmsapi.NISTP384ECDHKWType},
+		{name: "X25519 ECDH-KW", kt: kmsapi.X25519ECDHKWType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyID, _, err := source.Create(tt.kt)
+			require.NoError(t, err)
+
+			privKey, err := source.primaryPrivateKey(keyID)
+			require.NoError(t, err)
+
+			blob, err := source.ExportPrivateKey(keyID, "escrow-kek")
+			require.NoError(t, err)
+
+			_, _, err = target.ImportWrappedKey(blob, "escrow-kek", tt.kt, kmsapi.WithKeyID(keyID+"-kek"))
+			require.NoError(t, err)
+
+			imported, err := target.primaryPrivateKey(keyID + "-kek")
+			require.NoError(t, err)
+			require.True(t, privKey.(privateKey).Equal(imported))
+
+			blob, err = source.ExportPrivateKey(keyID, recipient)
+			require.NoError(t, err)
+			require.Equal(t, 4, bytes.Count(blob, []byte(".")))
+
+			_, _, err = target.ImportWrappedKey(blob, recipientID, tt.kt, kmsapi.WithKeyID(keyID+"-jwe"))
+			require.NoError(t, err)
+
+			imported, err = target.primaryPrivateKey(keyID + "-jwe")
+			require.NoError(t, err)
+			require.True(t, privKey.(privateKey).Equal(imported))
+		})
+	}
+
+	t.Run("detects the key type", func(t *testing.T) {
+		keyID, _, err := source.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		blob, err := source.ExportPrivateKey(keyID, "escrow-kek")
+		require.NoError(t, err)
+
+		_, kh, err := target.ImportWrappedKey(blob, "escrow-kek", "")
+		require.NoError(t, err)
+		require.NotNil(t, kh)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		keyID, _, err := source.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		_, err = source.ExportPrivateKey("missing", "escrow-kek")
+		require.ErrorContains(t, err, "export private key")
+
+		_, err = source.ExportPrivateKey(keyID, []byte("escrow-kek"))
+		require.EqualError(t, err, "export private key: unsupported wrapping key type []uint8")
+
+		_, err = source.ExportPrivateKey(keyID, keyID)
+		require.EqualError(t, err, "export private key: wrapping key '"+keyID+"' is not an AES-KW key")
+
+		_, err = source.ExportPrivateKey(keyID, &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: []byte("secret")}})
+		require.EqualError(t, err, "export private key: unsupported JWK wrapping key type []uint8")
+
+		hmacID, _, err := source.Create(kmsapi.HMACSHA256Tag256Type)
+		require.NoError(t, err)
+
+		_, err = source.ExportPrivateKey(hmacID, "escrow-kek")
+		require.ErrorContains(t, err, "can't be exported")
+
+		blob, err := source.ExportPrivateKey(keyID, "escrow-kek")
+		require.NoError(t, err)
+
+		blob[len(blob)-1] ^= 1
+
+		_, _, err = target.ImportWrappedKey(blob, "escrow-kek", "")
+		require.ErrorContains(t, err, "import wrapped key")
+
+		_, _, err = target.ImportWrappedKey([]byte("not a JWE"), recipientID, "")
+		require.ErrorContains(t, err, "import wrapped key: parse JWE")
+
+		enc, err := jose.NewEncrypter(jose.A256GCM, jose.Recipient{Algorithm: jose.ECDH_ES_A256KW,
+			Key: recipient.Key}, nil)
+		require.NoError(t, err)
+
+		jwe, err := enc.Encrypt([]byte("key"))
+		require.NoError(t, err)
+
+		s, err := jwe.CompactSerialize()
+		require.NoError(t, err)
+
+		_, _, err = target.ImportWrappedKey([]byte(s), recipientID, "")
+		require.EqualError(t, err, "import wrapped key: JWE content type is not 'pkcs8'")
+	})
+}
@@ -10,23 +10,37 @@ import (
 	"bytes"
 	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/rsa"
 	"fmt"
+	"math/big"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/keyset"
+	gcmpb "github.com/google/tink/go/proto/aes_gcm_go_proto"
+	gcmsivpb "github.com/google/tink/go/proto/aes_gcm_siv_go_proto"
+	chachapb "github.com/google/tink/go/proto/chacha20_poly1305_go_proto"
 	commonpb "github.com/google/tink/go/proto/common_go_proto"
 	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
 	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+	hmacpb "github.com/google/tink/go/proto/hmac_go_proto"
+	rsapb "github.com/google/tink/go/proto/rsa_ssa_pkcs1_go_proto"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	xchachapb "github.com/google/tink/go/proto/xchacha20_poly1305_go_proto"
 	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
 
+	"github.com/trustbloc/kms-go/internal/memguard"
 	"github.com/trustbloc/kms-go/spi/kms"
 
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aeskw"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/bip340"
 	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/composite/ecdh"
 	bbspb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/bbs_go_proto"
 	clpb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/cl_go_proto"
 	ecdhpb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
 	secp256k1pb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/secp256k1_go_proto"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/rsaoaep"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/rsapss"
 )
 
 const (
@@ -35,8 +49,66 @@ const (
 	bbsSignerKeyTypeURL          = "type.hyperledger.org/hyperledger.aries.crypto.tink.BBSPrivateKey"
 	secp256k1SignerTypeURL       = "type.googleapis.com/google.crypto.tink.secp256k1PrivateKey"
 	nistpECDHKWPrivateKeyTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.NistPEcdhKwPrivateKey"
+	rsaSSAPKCS1SignerTypeURL     = "type.googleapis.com/google.crypto.tink.RsaSsaPkcs1PrivateKey"
+	rsaModulusSize2048           = 2048
+	rsaPublicExponent            = 65537
+	hmacKeyTypeURL               = "type.googleapis.com/google.crypto.tink.HmacKey"
+	hmacSHA256Size               = 32
+	hmacSHA384Size               = 48
+	hmacSHA512Size               = 64
+	hmacMinKeySize               = 16
+	aes128KeySize                = 16
+	aes192KeySize                = 24
+	aes256KeySize                = 32
 )
 
+type hmacKeyParams struct {
+	hash commonpb.HashType
+	size uint32
+}
+
+// aesKWKeySizes are the KEK sizes of the AES-KW key types.
+//
+//nolint:gochecknoglobals
+var aesKWKeySizes = map[kms.KeyType]int{
+	kms.AES128KWType: aes128KeySize,
+	kms.AES192KWType: aes192KeySize,
+	kms.AES256KWType: aes256KeySize,
+}
+
+// hmacKeyTypes are the hash function and tag size of the HMAC key types.
+//
+//nolint:gochecknoglobals
+var hmacKeyTypes = map[kms.KeyType]hmacKeyParams{
+	kms.HMACSHA256Tag256Type: {hash: commonpb.HashType_SHA256, size: hmacSHA256Size},
+	kms.HMACSHA384Tag384Type: {hash: commonpb.HashType_SHA384, size: hmacSHA384Size},
+	kms.HMACSHA512Tag512Type: {hash: commonpb.HashType_SHA512, size: hmacSHA512Size},
+}
+
+type aeadKeyParams struct {
+	typeURL string
+	size    int
+}
+
+// aeadKeyTypes are the key type URL and key size of the AEAD key types.
+//
+//nolint:gochecknoglobals
+var aeadKeyTypes = map[kms.KeyType]aeadKeyParams{
+	kms.AES128GCMType:         {typeURL: aesGCMKeyTypeURL, size: aes128KeySize},
+	kms.AES256GCMType:         {typeURL: aesGCMKeyTypeURL, size: aes256KeySize},
+	kms.AES256GCMNoPrefixType: {typeURL: aesGCMKeyTypeURL, size: aes256KeySize},
+	kms.ChaCha20Poly1305Type:  {typeURL: chaCha20Poly1305KeyTypeURL, size: chaCha20Poly1305KeySize},
+	kms.XChaCha20Poly1305Type: {typeURL: xChaCha20Poly1305TypeURL, size: chaCha20Poly1305KeySize},
+	kms.AESGCMSIV256Type:      {typeURL: aesGCMSIVKeyTypeURL, size: aes256KeySize},
+}
+
+//nolint:gochecknoglobals
+var rsaPrivateKeyTypeURLs = map[kms.KeyType]string{
+	kms.RSARS256Type:   rsaSSAPKCS1SignerTypeURL,
+	kms.RSAPS256Type:   rsapss.SignerTypeURL,
+	kms.RSAOAEP256Type: rsaoaep.DecrypterTypeURL,
+}
+
 //nolint:funlen,gocyclo
 func (l *LocalKMS) importECDSAKey(privKey *ecdsa.PrivateKey, kt kms.KeyType,
 	opts ...kms.PrivateKeyOpts) (string, *keyset.Handle, error) {
@@ -91,15 +163,17 @@ func (l *LocalKMS) importECDSAKey(privKey *ecdsa.PrivateKey, kt kms.KeyType,
 			HashType: commonpb.HashType_SHA256,
 			Curve:    secp256k1pb.BitcoinCurveType_SECP256K1,
 			Encoding: secp256k1pb.Secp256K1SignatureEncoding_Bitcoin_DER,
-		})
+		}, opts...)
 	case kms.ECDSASecp256k1IEEEP1363:
 		return l.importSecp256K1Key(privKey, &secp256k1pb.Secp256K1Params{
 			HashType: commonpb.HashType_SHA256,
 			Curve:    secp256k1pb.BitcoinCurveType_SECP256K1,
 			Encoding: secp256k1pb.Secp256K1SignatureEncoding_Bitcoin_IEEE_P1363,
-		})
+		}, opts...)
+	case kms.BIP340Secp256k1Type:
+		return l.importBIP340Key(privKey, opts...)
 	default:
-		return "", nil, fmt.Errorf("import private EC key failed: invalid ECDSA key type")
+		return "", nil, fmt.Errorf("import private EC key failed: invalid ECDSA key type: %w", kms.ErrInvalidKeyType)
 	}
 
 	mKeyValue, err := getMarshalledECDSAPrivateKey(privKey, params)
@@ -124,6 +198,21 @@ func (l *LocalKMS) importSecp256K1Key(privKey *ecdsa.PrivateKey, params *secp256
 	return l.importKeySet(ks, opts...)
 }
 
+func (l *LocalKMS) importBIP340Key(privKey *ecdsa.PrivateKey, opts ...kms.PrivateKeyOpts) (string, *keyset.Handle, error) {
+	if privKey.Curve == nil || privKey.Curve.Params().Name != btcec.S256().Params().Name {
+		return "", nil, fmt.Errorf("import private BIP-340 key failed: not a secp256k1 key")
+	}
+
+	mKeyValue, err := getMarshalledBIP340PrivateKey(privKey)
+	if err != nil {
+		return "", nil, fmt.Errorf("import private BIP-340 key failed: %w", err)
+	}
+
+	ks := newKeySet(bip340.SignerTypeURL, mKeyValue, tinkpb.KeyData_ASYMMETRIC_PRIVATE)
+
+	return l.importKeySet(ks, opts...)
+}
+
 func (l *LocalKMS) buildAndImportECDSAPrivateKeyAsECDHKW(privKey *ecdsa.PrivateKey, kt kms.KeyType,
 	opts ...kms.PrivateKeyOpts) (string, *keyset.Handle, error) {
 	var keyTemplate *tinkpb.KeyTemplate
@@ -156,6 +245,8 @@ func (l *LocalKMS) buildAndImportECDSAPrivateKeyAsECDHKW(privKey *ecdsa.PrivateK
 	}
 
 	privBytes, err := proto.Marshal(priv)
+	memguard.Wipe(priv.KeyValue)
+
 	if err != nil {
 		return "", nil, fmt.Errorf("marshal protobuf: %w", err)
 	}
@@ -166,6 +257,9 @@ func (l *LocalKMS) buildAndImportECDSAPrivateKeyAsECDHKW(privKey *ecdsa.PrivateK
 }
 
 func (l *LocalKMS) importKeySet(ks *tinkpb.Keyset, opts ...kms.PrivateKeyOpts) (string, *keyset.Handle, error) {
+	// the returned handle is read back from the store, the plaintext key material of ks is not needed afterwards.
+	defer wipeKeySet(ks)
+
 	ksID, err := l.writeImportedKey(ks, opts...)
 	if err != nil {
 		return "", nil, fmt.Errorf("import private EC key failed: %w", err)
@@ -190,6 +284,167 @@ func getMarshalledECDSASecp256K1PrivateKey(privKey *ecdsa.PrivateKey,
 	return proto.Marshal(newProtoECDSASecp256K1PrivateKey(pubKeyProto, privKey.D.Bytes()))
 }
 
+// getMarshalledBIP340PrivateKey returns the BIP-340 private key proto of privKey, its public key is the x-only key of
+// privKey.
+func getMarshalledBIP340PrivateKey(privKey *ecdsa.PrivateKey) ([]byte, error) {
+	if privKey.D.Sign() <= 0 || privKey.D.BitLen() > bip340.PrivateKeySize*8 {
+		return nil, fmt.Errorf("invalid private key")
+	}
+
+	keyValue := privKey.D.FillBytes(make([]byte, bip340.PrivateKeySize))
+
+	key, err := bip340.NewPrivateKey(keyValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(key)
+}
+
+func (l *LocalKMS) importRSAKey(privKey *rsa.PrivateKey, kt kms.KeyType,
+	opts ...kms.PrivateKeyOpts) (string, *keyset.Handle, error) {
+	tURL, ok := rsaPrivateKeyTypeURLs[kt]
+	if !ok {
+		return "", nil, fmt.Errorf("import private RSA key failed: invalid RSA key type: %w", kms.ErrInvalidKeyType)
+	}
+
+	if err := privKey.Validate(); err != nil {
+		return "", nil, fmt.Errorf("import private RSA key failed: %w", err)
+	}
+
+	if len(privKey.Primes) != 2 || privKey.N.BitLen() < rsaModulusSize2048 || privKey.E != rsaPublicExponent {
+		return "", nil, fmt.Errorf("import private RSA key failed: key must be a two primes key with a modulus "+
+			"of %d bits or more and the %d public exponent", rsaModulusSize2048, rsaPublicExponent)
+	}
+
+	privKey.Precompute()
+
+	mKeyValue, err := proto.Marshal(&rsapb.RsaSsaPkcs1PrivateKey{
+		PublicKey: newProtoRSAPublicKey(&privKey.PublicKey),
+		D:         privKey.D.Bytes(),
+		P:         privKey.Primes[0].Bytes(),
+		Q:         privKey.Primes[1].Bytes(),
+		Dp:        privKey.Precomputed.Dp.Bytes(),
+		Dq:        privKey.Precomputed.Dq.Bytes(),
+		Crt:       privKey.Precomputed.Qinv.Bytes(),
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("import private RSA key failed: %w", err)
+	}
+
+	ks := newKeySet(tURL, mKeyValue, tinkpb.KeyData_ASYMMETRIC_PRIVATE)
+
+	return l.importKeySet(ks, opts...)
+}
+
+// newProtoRSAPublicKey returns the proto of an RSA public key. All the RSA key types use SHA-256.
+func newProtoRSAPublicKey(pubKey *rsa.PublicKey) *rsapb.RsaSsaPkcs1PublicKey {
+	return &rsapb.RsaSsaPkcs1PublicKey{
+		Params: &rsapb.RsaSsaPkcs1Params{HashType: commonpb.HashType_SHA256},
+		N:      pubKey.N.Bytes(),
+		E:      big.NewInt(int64(pubKey.E)).Bytes(),
+	}
+}
+
+// isSecretKeyType returns true if kt is an HMAC, AES-KW or AEAD key type, imported from raw key bytes.
+func isSecretKeyType(kt kms.KeyType) bool {
+	_, isAESKW := aesKWKeySizes[kt]
+	_, isHMAC := hmacKeyTypes[kt]
+	_, isAEAD := aeadKeyTypes[kt]
+
+	return isAESKW || isHMAC || isAEAD
+}
+
+// importSecretKey imports raw symmetric key bytes of kt.
+func (l *LocalKMS) importSecretKey(key []byte, kt kms.KeyType,
+	opts ...kms.PrivateKeyOpts) (string, *keyset.Handle, error) {
+	if _, ok := aesKWKeySizes[kt]; ok {
+		return l.importAESKWKey(key, kt, opts...)
+	}
+
+	if _, ok := aeadKeyTypes[kt]; ok {
+		return l.importAEADKey(key, kt, opts...)
+	}
+
+	return l.importHMACKey(key, kt, opts...)
+}
+
+// importAEADKey imports a raw AES-GCM, AES-GCM-SIV, ChaCha20-Poly1305 or XChaCha20-Poly1305 key. Imported keys have no output
+// prefix: their ciphertexts are the nonce followed by the plain AEAD ciphertext.
+func (l *LocalKMS) importAEADKey(key []byte, kt kms.KeyType,
+	opts ...kms.PrivateKeyOpts) (string, *keyset.Handle, error) {
+	params := aeadKeyTypes[kt]
+
+	if len(key) != params.size {
+		return "", nil, fmt.Errorf("import AEAD key failed: %s keys must have %d bytes", kt, params.size)
+	}
+
+	var aeadKey proto.Message
+
+	switch params.typeURL {
+	case chaCha20Poly1305KeyTypeURL:
+		aeadKey = &chachapb.ChaCha20Poly1305Key{KeyValue: key}
+	case xChaCha20Poly1305TypeURL:
+		aeadKey = &xchachapb.XChaCha20Poly1305Key{KeyValue: key}
+	case aesGCMSIVKeyTypeURL:
+		aeadKey = &gcmsivpb.AesGcmSivKey{KeyValue: key}
+	default:
+		aeadKey = &gcmpb.AesGcmKey{KeyValue: key}
+	}
+
+	mKeyValue, err := proto.Marshal(aeadKey)
+	if err != nil {
+		return "", nil, fmt.Errorf("import AEAD key failed: %w", err)
+	}
+
+	ks := newKeySet(params.typeURL, mKeyValue, tinkpb.KeyData_SYMMETRIC)
+
+	return l.importKeySet(ks, opts...)
+}
+
+// importAESKWKey imports a raw AES-KW key encryption key.
+func (l *LocalKMS) importAESKWKey(key []byte, kt kms.KeyType,
+	opts ...kms.PrivateKeyOpts) (string, *keyset.Handle, error) {
+	if len(key) != aesKWKeySizes[kt] {
+		return "", nil, fmt.Errorf("import AES-KW key failed: %s keys must have %d bytes", kt, aesKWKeySizes[kt])
+	}
+
+	mKeyValue, err := proto.Marshal(&gcmpb.AesGcmKey{KeyValue: key})
+	if err != nil {
+		return "", nil, fmt.Errorf("import AES-KW key failed: %w", err)
+	}
+
+	ks := newKeySet(aeskw.TypeURL, mKeyValue, tinkpb.KeyData_SYMMETRIC)
+
+	return l.importKeySet(ks, opts...)
+}
+
+// importHMACKey imports raw HMAC key bytes. Imported keys have no output prefix: their MACs are plain HMAC tags,
+// compatible with the ones computed outside of the KMS.
+func (l *LocalKMS) importHMACKey(key []byte, kt kms.KeyType,
+	opts ...kms.PrivateKeyOpts) (string, *keyset.Handle, error) {
+	params, ok := hmacKeyTypes[kt]
+	if !ok {
+		return "", nil, fmt.Errorf("import HMAC key failed: invalid HMAC key type: %w", kms.ErrInvalidKeyType)
+	}
+
+	if len(key) < hmacMinKeySize {
+		return "", nil, fmt.Errorf("import HMAC key failed: key must have %d bytes or more", hmacMinKeySize)
+	}
+
+	mKeyValue, err := proto.Marshal(&hmacpb.HmacKey{
+		Params:   &hmacpb.HmacParams{Hash: params.hash, TagSize: params.size},
+		KeyValue: key,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("import HMAC key failed: %w", err)
+	}
+
+	ks := newKeySet(hmacKeyTypeURL, mKeyValue, tinkpb.KeyData_SYMMETRIC)
+
+	return l.importKeySet(ks, opts...)
+}
+
 func (l *LocalKMS) importEd25519Key(privKey ed25519.PrivateKey, kt kms.KeyType,
 	opts ...kms.PrivateKeyOpts) (string, *keyset.Handle, error) {
 	if privKey == nil {
@@ -197,7 +452,7 @@ func (l *LocalKMS) importEd25519Key(privKey ed25519.PrivateKey, kt kms.KeyType,
 	}
 
 	if kt != kms.ED25519Type {
-		return "", nil, fmt.Errorf("import private ED25519 key failed: invalid key type")
+		return "", nil, fmt.Errorf("import private ED25519 key failed: %w", kms.ErrInvalidKeyType)
 	}
 
 	privKeyProto, err := newProtoEd25519PrivateKey(privKey)
@@ -222,7 +477,7 @@ func (l *LocalKMS) importBBSKey(privKey *bbs12381g2pub.PrivateKey, kt kms.KeyTyp
 	}
 
 	if kt != kms.BLS12381G2Type {
-		return "", nil, fmt.Errorf("import private BBS+ key failed: invalid key type")
+		return "", nil, fmt.Errorf("import private BBS+ key failed: %w", kms.ErrInvalidKeyType)
 	}
 
 	privKeyProto, err := newProtoBBSPrivateKey(privKey, kt)
@@ -358,6 +613,8 @@ func (l *LocalKMS) writeImportedKey(ks *tinkpb.Keyset, opts ...kms.PrivateKeyOpt
 	}
 
 	encrypted, err := l.primaryKeyEnvAEAD.Encrypt(serializedKeyset, []byte{})
+	memguard.Wipe(serializedKeyset)
+
 	if err != nil {
 		return "", fmt.Errorf("encrypted failed: %w", err)
 	}
@@ -380,7 +637,14 @@ func (l *LocalKMS) writeImportedKey(ks *tinkpb.Keyset, opts ...kms.PrivateKeyOpt
 		return "", fmt.Errorf("failed to write keyset as json: %w", err)
 	}
 
-	return writeToStore(l.store, buf, opts...)
+	return l.writeToStore(buf, opts...)
+}
+
+// wipeKeySet overwrites the serialized key material of ks with zeros.
+func wipeKeySet(ks *tinkpb.Keyset) {
+	for _, k := range ks.GetKey() {
+		memguard.Wipe(k.GetKeyData().GetValue())
+	}
 }
 
 func getKeysetInfo(ks *tinkpb.Keyset) (*tinkpb.KeysetInfo, error) {
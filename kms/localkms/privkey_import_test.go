@@ -7,22 +7,40 @@ SPDX-License-Identifier: Apache-2.0
 package localkms
 
 import (
+	"crypto"
+	"crypto/ecdh"
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"math/big"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/go-jose/go-jose/v3"
 	"github.com/golang/mock/gomock"
+	"github.com/google/tink/go/hybrid"
+	"github.com/google/tink/go/keyset"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/signature"
 	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
 
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
 	"github.com/trustbloc/kms-go/spi/kms"
 
 	"github.com/trustbloc/kms-go/spi/secretlock"
 
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/bip340"
 	mocksecretlock "github.com/trustbloc/kms-go/mock/secretlock"
 	"github.com/trustbloc/kms-go/secretlock/noop"
 )
@@ -38,8 +56,9 @@ func TestImportECDSAKeyWithInvalidKey(t *testing.T) {
 	require.NoError(t, err)
 
 	_, _, err = k.importECDSAKey(privKey, kms.AES128GCM)
-	require.EqualError(t, err, errPrefix+"invalid ECDSA key type", "importECDSAKey should fail with "+
-		"unsupported key type")
+	require.EqualError(t, err, errPrefix+"invalid ECDSA key type: invalid key type", "importECDSAKey should fail "+
+		"with unsupported key type")
+	require.ErrorIs(t, err, kms.ErrInvalidKeyType)
 }
 
 func TestImportEd25519KeyWitnInvalidKey(t *testing.T) {
@@ -197,3 +216,325 @@ func (m *goMockProvider) StorageProvider() kms.Store {
 func (m *goMockProvider) SecretLock() secretlock.Service {
 	return m.secretLock
 }
+
+func TestImportRSAKey(t *testing.T) {
+	k := createKMS(t)
+	errPrefix := "import private RSA key failed: "
+
+	privKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	_, _, err = k.importRSAKey(privKey, kms.ECDSAP256TypeDER)
+	require.EqualError(t, err, errPrefix+"invalid RSA key type: invalid key type")
+	require.ErrorIs(t, err, kms.ErrInvalidKeyType)
+
+	smallKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	_, _, err = k.ImportPrivateKey(smallKey, kms.RSARS256Type)
+	require.ErrorContains(t, err, errPrefix+"key must be a two primes key")
+
+	msg := []byte("message")
+	digest := sha256.Sum256(msg)
+
+	for _, kt := range []kms.KeyType{kms.RSARS256Type, kms.RSAPS256Type, kms.RSAOAEP256Type} {
+		t.Run(string(kt), func(t *testing.T) {
+			kid, kh, err := k.ImportPrivateKey(privKey, kt)
+			require.NoError(t, err)
+			require.NotEmpty(t, kid)
+
+			pubKeyBytes, _, err := k.ExportPubKeyBytes(kid)
+			require.NoError(t, err)
+
+			expected, err := x509.MarshalPKIXPublicKey(&privKey.PublicKey)
+			require.NoError(t, err)
+			require.Equal(t, expected, pubKeyBytes)
+
+			pubKH, err := k.PubKeyBytesToHandle(pubKeyBytes, kt)
+			require.NoError(t, err)
+			require.NotNil(t, pubKH)
+
+			pkcs1KH, err := k.PubKeyBytesToHandle(x509.MarshalPKCS1PublicKey(&privKey.PublicKey), kt)
+			require.NoError(t, err)
+			require.Equal(t, pubKH.(*keyset.Handle).KeysetInfo(), pkcs1KH.(*keyset.Handle).KeysetInfo())
+
+			handle, ok := kh.(*keyset.Handle)
+			require.True(t, ok)
+
+			switch kt { //nolint:exhaustive
+			case kms.RSARS256Type:
+				s, err := signature.NewSigner(handle)
+				require.NoError(t, err)
+
+				sig, err := s.Sign(msg)
+				require.NoError(t, err)
+				require.NoError(t, rsa.VerifyPKCS1v15(&privKey.PublicKey, crypto.SHA256, digest[:], sig))
+			case kms.RSAPS256Type:
+				s, err := signature.NewSigner(handle)
+				require.NoError(t, err)
+
+				sig, err := s.Sign(msg)
+				require.NoError(t, err)
+				require.NoError(t, rsa.VerifyPSS(&privKey.PublicKey, crypto.SHA256, digest[:], sig, nil))
+			case kms.RSAOAEP256Type:
+				ct, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, &privKey.PublicKey, msg, nil)
+				require.NoError(t, err)
+
+				d, err := hybrid.NewHybridDecrypt(handle)
+				require.NoError(t, err)
+
+				pt, err := d.Decrypt(ct, nil)
+				require.NoError(t, err)
+				require.Equal(t, msg, pt)
+			}
+		})
+	}
+}
+
+func TestImportHMACKey(t *testing.T) {
+	k := createKMS(t)
+	errPrefix := "import HMAC key failed: "
+
+	_, _, err := k.ImportPrivateKey(make([]byte, 32), kms.BLS12381G2Type)
+	require.EqualError(t, err, errPrefix+"invalid HMAC key type: invalid key type")
+	require.ErrorIs(t, err, kms.ErrInvalidKeyType)
+
+	_, _, err = k.ImportPrivateKey(make([]byte, 8), kms.HMACSHA256Tag256Type)
+	require.EqualError(t, err, errPrefix+"key must have 16 bytes or more")
+
+	kid, kh, err := k.ImportPrivateKey(make([]byte, 32), kms.HMACSHA512Tag512Type, kms.WithKeyID("hmac"))
+	require.NoError(t, err)
+	require.Equal(t, "hmac", kid)
+	require.NotNil(t, kh)
+
+	caps, err := k.Capabilities()
+	require.NoError(t, err)
+	require.True(t, caps.Supports(kms.HMACSHA384Tag384Type, kms.OperationImportPrivate))
+	require.False(t, caps.Supports(kms.CLCredDefType, kms.OperationImportPrivate))
+}
+
+func TestImportAEADKey(t *testing.T) {
+	k := createKMS(t)
+
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	_, _, err = k.ImportPrivateKey(make([]byte, 16), kms.AES256GCMType)
+	require.EqualError(t, err, "import AEAD key failed: AES256GCM keys must have 32 bytes")
+
+	for kt, size := range map[kms.KeyType]int{
+		kms.AES128GCMType: 16, kms.AES256GCMType: 32, kms.AES256GCMNoPrefixType: 32, kms.ChaCha20Poly1305Type: 32,
+		kms.XChaCha20Poly1305Type: 32, kms.AESGCMSIV256Type: 32,
+	} {
+		key := make([]byte, size)
+		_, err = rand.Read(key)
+		require.NoError(t, err)
+
+		kid, kh, err := k.ImportPrivateKey(key, kt, kms.WithKeyID(string(kt)))
+		require.NoError(t, err)
+		require.Equal(t, string(kt), kid)
+
+		ct, nonce, err := c.Encrypt([]byte("message"), []byte("aad"), kh)
+		require.NoError(t, err)
+
+		pt, err := c.Decrypt(ct, []byte("aad"), nonce, kh)
+		require.NoError(t, err)
+		require.Equal(t, []byte("message"), pt)
+	}
+
+	caps, err := k.Capabilities()
+	require.NoError(t, err)
+	require.True(t, caps.Supports(kms.AES256GCMType, kms.OperationImportPrivate))
+}
+
+func TestImportSecp256K1KeyWithKeyID(t *testing.T) {
+	k := createKMS(t)
+
+	for _, kt := range []kms.KeyType{kms.ECDSASecp256k1TypeDER, kms.ECDSASecp256k1TypeIEEEP1363} {
+		privKey, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+		require.NoError(t, err)
+
+		kid, kh, err := k.ImportPrivateKey(privKey, kt, kms.WithKeyID(string(kt)))
+		require.NoError(t, err)
+		require.Equal(t, string(kt), kid)
+		require.NotNil(t, kh)
+	}
+}
+
+func TestImportBIP340Key(t *testing.T) {
+	k := createKMS(t)
+
+	privKey, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+	require.NoError(t, err)
+
+	kid, kh, err := k.ImportPrivateKey(privKey, kms.BIP340Secp256k1Type)
+	require.NoError(t, err)
+
+	// the public key is the x-only key of privKey.
+	pubKeyBytes, kt, err := k.ExportPubKeyBytes(kid)
+	require.NoError(t, err)
+	require.Equal(t, kms.BIP340Secp256k1Type, kt)
+	require.Equal(t, privKey.X.FillBytes(make([]byte, bip340.PublicKeySize)), pubKeyBytes)
+
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	msg := []byte("message")
+
+	sig, err := c.Sign(msg, kh)
+	require.NoError(t, err)
+	require.NoError(t, bip340.Verify(pubKeyBytes, msg, sig))
+
+	pubKH, err := k.PubKeyBytesToHandle(pubKeyBytes, kms.BIP340Secp256k1Type)
+	require.NoError(t, err)
+	require.NoError(t, c.Verify(sig, msg, pubKH))
+
+	_, err = k.PubKeyBytesToHandle(pubKeyBytes[1:], kms.BIP340Secp256k1Type)
+	require.ErrorContains(t, err, "invalid BIP-340 public key size")
+
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	_, _, err = k.ImportPrivateKey(p256Key, kms.BIP340Secp256k1Type)
+	require.EqualError(t, err, "import private BIP-340 key failed: not a secp256k1 key")
+}
+
+func TestImportAESKWKey(t *testing.T) {
+	k := createKMS(t)
+
+	_, _, err := k.ImportPrivateKey(make([]byte, 16), kms.AES256KWType)
+	require.EqualError(t, err, "import AES-KW key failed: AES256KW keys must have 32 bytes")
+
+	kid, kh, err := k.ImportPrivateKey(make([]byte, 24), kms.AES192KWType)
+	require.NoError(t, err)
+	require.NotEmpty(t, kid)
+	require.NotNil(t, kh)
+}
+
+func TestImportEncodedPrivateKey(t *testing.T) {
+	k := createKMS(t)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	xKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	pkcs8 := func(key interface{}) []byte {
+		der, e := x509.MarshalPKCS8PrivateKey(key)
+		require.NoError(t, e)
+
+		return der
+	}
+
+	sec1, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+
+	ecJWK, err := (&jose.JSONWebKey{Key: ecKey}).MarshalJSON()
+	require.NoError(t, err)
+
+	edJWK, err := (&jose.JSONWebKey{Key: edKey}).MarshalJSON()
+	require.NoError(t, err)
+
+	xJWK := fmt.Sprintf(`{"kty":"OKP","crv":"X25519","x":"%s","d":"%s"}`,
+		base64.RawURLEncoding.EncodeToString(xKey.PublicKey().Bytes()),
+		base64.RawURLEncoding.EncodeToString(xKey.Bytes()))
+
+	tests := []struct {
+		name string
+		key  []byte
+		kt   kms.KeyType
+	}{
+		{"PKCS#8 DER EC", pkcs8(ecKey), kms.ECDSAP384TypeDER},
+		{"PKCS#8 PEM EC", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8(ecKey)}), kms.ECDSAP384TypeDER},
+		{"SEC1 PEM EC", pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1}), kms.ECDSAP384TypeDER},
+		{"JWK EC", ecJWK, kms.ECDSAP384TypeIEEEP1363},
+		{"PKCS#8 PEM Ed25519", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8(edKey)}),
+			kms.ED25519Type},
+		{"JWK Ed25519", edJWK, kms.ED25519Type},
+		{"PKCS#1 PEM RSA", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}), kms.RSAPS256Type},
+		{"PKCS#8 DER X25519", pkcs8(xKey), kms.X25519ECDHKWType},
+		{"JWK X25519", []byte(xJWK), kms.X25519ECDHKWType},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			kid, kh, err := k.ImportPrivateKey(tc.key, "")
+			require.NoError(t, err)
+			require.NotNil(t, kh)
+
+			_, kt, err := k.ExportPubKeyBytes(kid)
+			require.NoError(t, err)
+			require.Equal(t, tc.kt, kt)
+		})
+	}
+
+	t.Run("explicit key type overrides the detected one", func(t *testing.T) {
+		kid, _, err := k.ImportPrivateKey(pkcs8(ecKey), kms.ECDSAP384TypeIEEEP1363)
+		require.NoError(t, err)
+
+		_, kt, err := k.ExportPubKeyBytes(kid)
+		require.NoError(t, err)
+		require.Equal(t, kms.ECDSAP384TypeIEEEP1363, kt)
+
+		_, _, err = k.ImportPrivateKey(pkcs8(rsaKey), kms.ED25519Type)
+		require.EqualError(t, err, "import private RSA key failed: invalid RSA key type: invalid key type")
+	})
+
+	t.Run("imported X25519 key keeps its public key", func(t *testing.T) {
+		kid, _, err := k.ImportPrivateKey(xKey, kms.X25519ECDHKWType)
+		require.NoError(t, err)
+
+		pubKeyBytes, _, err := k.ExportPubKeyBytes(kid)
+		require.NoError(t, err)
+
+		pubKey := &cryptoapi.PublicKey{}
+		require.NoError(t, json.Unmarshal(pubKeyBytes, pubKey))
+		require.Equal(t, xKey.PublicKey().Bytes(), pubKey.X)
+
+		_, _, err = k.ImportPrivateKey(xKey, kms.ECDSAP256TypeDER)
+		require.EqualError(t, err, "import private X25519 key failed: invalid key type")
+	})
+
+	t.Run("BLS12-381 G2 JWK", func(t *testing.T) {
+		_, blsKey, err := bbs12381g2pub.GenerateKeyPair(sha256.New, nil)
+		require.NoError(t, err)
+
+		kid, _, err := k.ImportPrivateKey(&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: blsKey}}, "")
+		require.NoError(t, err)
+
+		_, kt, err := k.ExportPubKeyBytes(kid)
+		require.NoError(t, err)
+		require.Equal(t, kms.BLS12381G2Type, kt)
+	})
+
+	t.Run("invalid encoded keys", func(t *testing.T) {
+		_, _, err := k.ImportPrivateKey([]byte(`{"kty":"EC"`), "")
+		require.ErrorContains(t, err, "import encoded private key failed: unable to read JWK")
+
+		pubJWK, err := (&jose.JSONWebKey{Key: &ecKey.PublicKey}).MarshalJSON()
+		require.NoError(t, err)
+
+		_, _, err = k.ImportPrivateKey(pubJWK, "")
+		require.EqualError(t, err, "import encoded private key failed: JWK is not a private key")
+
+		_, _, err = k.ImportPrivateKey([]byte(`{"kty":"OKP","crv":"X25519","x":"AA"}`), "")
+		require.ErrorContains(t, err, "X25519 JWK is missing a valid private key")
+
+		_, _, err = k.ImportPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: sec1}), "")
+		require.ErrorContains(t, err, "encrypted PEM private keys are not supported")
+
+		_, _, err = k.ImportPrivateKey([]byte{0x30, 0x01, 0x02}, "")
+		require.ErrorContains(t, err, "unrecognized PKCS#8, SEC1 or PKCS#1 private key")
+
+		_, _, err = k.ImportPrivateKey(&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: &ecKey.PublicKey}}, "")
+		require.EqualError(t, err, "import private key does not support this key type or key is public: invalid key type")
+	})
+}
@@ -0,0 +1,49 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/util/profiles"
+)
+
+// WithProfile restricts LocalKMS to the key types of the crypto suite profile p: creating, rotating to, pooling,
+// importing (including keysets and key transfers) or converting public keys of other key types fails with
+// profiles.ErrNotAllowed, and Capabilities only returns the key types of p. The keys stored before the profile was set
+// can still be read with Get, the Crypto running them is restricted by its own profile (eg: tinkcrypto.WithProfile).
+func WithProfile(p *profiles.Profile) Opt {
+	return func(opts *kmsOpts) {
+		opts.profile = p
+	}
+}
+
+// checkProfileKeyType returns profiles.ErrNotAllowed if LocalKMS has a profile and kt is not one of its key types.
+func (l *LocalKMS) checkProfileKeyType(kt kmsapi.KeyType) error {
+	if l.opts.profile == nil {
+		return nil
+	}
+
+	return l.opts.profile.CheckKeyType(kt)
+}
+
+// checkProfileKeyset returns profiles.ErrNotAllowed if LocalKMS has a profile and ks holds a key that is not of one
+// of its key types.
+func (l *LocalKMS) checkProfileKeyset(ks *tinkpb.Keyset) error {
+	if l.opts.profile == nil {
+		return nil
+	}
+
+	for _, k := range ks.GetKey() {
+		if err := l.opts.profile.CheckTypeURL(k.GetKeyData().GetTypeUrl()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/util/profiles"
+)
+
+func TestLocalKMS_WithProfile(t *testing.T) {
+	didcomm, err := profiles.Get(profiles.DIDCommV2)
+	require.NoError(t, err)
+
+	newKMS := func(t *testing.T, opts ...Opt) *LocalKMS {
+		t.Helper()
+
+		k, e := New(testMasterKeyURI, &mockProvider{storage: newInMemoryKMSStore(), secretLock: &noop.NoLock{}},
+			opts...)
+		require.NoError(t, e)
+
+		return k
+	}
+
+	k := newKMS(t, WithProfile(didcomm))
+
+	t.Run("create and rotate", func(t *testing.T) {
+		keyID, _, e := k.Create(kmsapi.ED25519Type)
+		require.NoError(t, e)
+
+		_, _, e = k.Create(kmsapi.RSARS256Type)
+		require.ErrorIs(t, e, profiles.ErrNotAllowed)
+		require.EqualError(t, e, "create: profiles: not allowed by the profile: profile 'didcomm-v2': key type "+
+			"'RSARS256'")
+
+		_, _, e = k.Rotate(kmsapi.ECDSAP384TypeDER, keyID)
+		require.ErrorIs(t, e, profiles.ErrNotAllowed)
+
+		_, _, e = k.Rotate(kmsapi.ECDSAP256TypeIEEEP1363, keyID)
+		require.NoError(t, e)
+	})
+
+	t.Run("import", func(t *testing.T) {
+		_, privKey, e := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, e)
+
+		_, _, e = k.ImportPrivateKey(privKey, kmsapi.ED25519Type)
+		require.NoError(t, e)
+
+		_, _, e = k.ImportPrivateKey([]byte("0123456789abcdef0123456789abcdef"), kmsapi.HMACSHA256Tag256Type)
+		require.ErrorIs(t, e, profiles.ErrNotAllowed)
+
+		pubKey, _ := privKey.Public().(ed25519.PublicKey)
+
+		_, e = k.PubKeyBytesToHandle(pubKey, kmsapi.ED25519Type)
+		require.NoError(t, e)
+
+		_, e = k.PubKeyBytesToHandle(pubKey, kmsapi.BLS12381G2Type)
+		require.ErrorIs(t, e, profiles.ErrNotAllowed)
+
+		// keysets are checked by key type URL.
+		unrestricted := newKMS(t)
+
+		aeadKID, _, e := unrestricted.Create(kmsapi.AES256GCMType)
+		require.NoError(t, e)
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, unrestricted.ExportKeyset(aeadKID, keyset.NewBinaryWriter(buf), nil))
+
+		_, _, e = k.ImportKeyset(keyset.NewBinaryReader(buf), nil)
+		require.ErrorIs(t, e, profiles.ErrNotAllowed)
+	})
+
+	t.Run("capabilities", func(t *testing.T) {
+		caps, e := k.Capabilities()
+		require.NoError(t, e)
+		require.Len(t, caps.KeyTypes, len(didcomm.KeyTypes))
+
+		for _, c := range caps.KeyTypes {
+			require.NoError(t, didcomm.CheckKeyType(c.KeyType))
+		}
+	})
+
+	t.Run("key pools", func(t *testing.T) {
+		_, e := New(testMasterKeyURI, &mockProvider{storage: newInMemoryKMSStore(), secretLock: &noop.NoLock{}},
+			WithProfile(didcomm), WithKeyPool(kmsapi.AES256GCMType, 1))
+		require.ErrorIs(t, e, profiles.ErrNotAllowed)
+	})
+}
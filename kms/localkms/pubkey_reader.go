@@ -9,7 +9,9 @@ package localkms
 import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"crypto/rsa"
 	"crypto/x509"
+	"errors"
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
@@ -23,6 +25,7 @@ import (
 
 	"github.com/trustbloc/kms-go/spi/kms"
 
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/bip340"
 	bbspb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/bbs_go_proto"
 	clpb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/cl_go_proto"
 	secp256k1pb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/secp256k1_go_proto"
@@ -198,6 +201,22 @@ func getMarshalledProtoKeyAndKeyURL(pubKey []byte, kt kms.KeyType,
 		if err != nil {
 			return nil, "", err
 		}
+	case kms.BIP340Secp256k1Type:
+		if len(pubKey) != bip340.PublicKeySize {
+			return nil, "", fmt.Errorf("invalid BIP-340 public key size")
+		}
+
+		tURL = bip340.VerifierTypeURL
+
+		keyValue, err = proto.Marshal(&secp256k1pb.Secp256K1PublicKey{Params: bip340.Params(), X: pubKey})
+		if err != nil {
+			return nil, "", err
+		}
+	case kms.RSARS256Type, kms.RSAPS256Type, kms.RSAOAEP256Type:
+		tURL, keyValue, err = getMarshalledRSAKey(pubKey, kt)
+		if err != nil {
+			return nil, "", err
+		}
 	case kms.ECDSASecp256k1IEEEP1363:
 		tURL = secp256k1VerifierTypeURL
 
@@ -210,7 +229,7 @@ func getMarshalledProtoKeyAndKeyURL(pubKey []byte, kt kms.KeyType,
 			return nil, "", err
 		}
 	default:
-		return nil, "", fmt.Errorf("invalid key type")
+		return nil, "", kms.ErrInvalidKeyType
 	}
 
 	return keyValue, tURL, nil
@@ -338,3 +357,35 @@ func newProtoSecp256K1PublicKey(ecPubKey *ecdsa.PublicKey,
 		Params:  params,
 	}
 }
+
+// getMarshalledRSAKey reads a PKIX (as exported by LocalKMS) or PKCS #1 (as returned by jwk.JWK.PublicKeyBytes) RSA
+// public key.
+func getMarshalledRSAKey(marshaledPubKey []byte, kt kms.KeyType) (string, []byte, error) {
+	rsaPubKey, err := x509.ParsePKCS1PublicKey(marshaledPubKey)
+	if err != nil {
+		pubKey, e := x509.ParsePKIXPublicKey(marshaledPubKey)
+		if e != nil {
+			return "", nil, fmt.Errorf("failed to parse RSA public key: %w", e)
+		}
+
+		var ok bool
+
+		rsaPubKey, ok = pubKey.(*rsa.PublicKey)
+		if !ok {
+			return "", nil, errors.New("public key is not an RSA key")
+		}
+	}
+
+	keyValue, err := proto.Marshal(newProtoRSAPublicKey(rsaPubKey))
+	if err != nil {
+		return "", nil, err
+	}
+
+	for tURL, t := range rsaKMSKeyTypes {
+		if t == kt {
+			return tURL, keyValue, nil
+		}
+	}
+
+	return "", nil, fmt.Errorf("%w: %s is not an RSA key type", kms.ErrInvalidKeyType, kt)
+}
@@ -9,6 +9,7 @@ package localkms
 import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"crypto/rsa"
 	"crypto/x509"
 	"fmt"
 	"io"
@@ -18,15 +19,19 @@ import (
 	commonpb "github.com/google/tink/go/proto/common_go_proto"
 	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
 	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+	rsapb "github.com/google/tink/go/proto/rsa_ssa_pkcs1_go_proto"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 	"github.com/google/tink/go/subtle"
 
 	"github.com/trustbloc/kms-go/spi/kms"
 
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/bip340"
 	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/composite/keyio"
 	bbspb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/bbs_go_proto"
 	clpb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/cl_go_proto"
 	secp256k1pb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/secp256k1_go_proto"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/rsaoaep"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/rsapss"
 	secp256k1subtle "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/secp256k1/subtle"
 )
 
@@ -35,9 +40,11 @@ const (
 	ed25519VerifierTypeURL       = "type.googleapis.com/google.crypto.tink.Ed25519PublicKey"
 	nistPECDHKWPublicKeyTypeURL  = "type.hyperledger.org/hyperledger.aries.crypto.tink.NistPEcdhKwPublicKey"
 	x25519ECDHKWPublicKeyTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.X25519EcdhKwPublicKey"
+	x448ECDHKWPublicKeyTypeURL   = "type.hyperledger.org/hyperledger.aries.crypto.tink.X448EcdhKwPublicKey"
 	bbsVerifierKeyTypeURL        = "type.hyperledger.org/hyperledger.aries.crypto.tink.BBSPublicKey"
 	clCredDefKeyTypeURL          = "type.hyperledger.org/hyperledger.aries.crypto.tink.CLCredDefKey"
 	secp256k1VerifierTypeURL     = "type.googleapis.com/google.crypto.tink.secp256k1PublicKey"
+	rsaSSAPKCS1VerifierTypeURL   = "type.googleapis.com/google.crypto.tink.RsaSsaPkcs1PublicKey"
 	derPrefix                    = "der-"
 	p13163Prefix                 = "p1363-"
 )
@@ -54,6 +61,13 @@ var ecdsaKMSKeyTypes = map[string]kms.KeyType{
 	p13163Prefix + "SECP256K1": kms.ECDSASecp256k1IEEEP1363,
 }
 
+//nolint:gochecknoglobals
+var rsaKMSKeyTypes = map[string]kms.KeyType{
+	rsaSSAPKCS1VerifierTypeURL: kms.RSARS256Type,
+	rsapss.VerifierTypeURL:     kms.RSAPS256Type,
+	rsaoaep.EncrypterTypeURL:   kms.RSAOAEP256Type,
+}
+
 // PubKeyWriter will write the raw bytes of a Tink KeySet's primary public key
 // The keyset must be one of the keyURLs defined above
 // Note: Only signing public keys and ecdh key types created in tinkcrypto can be exported through this PubKeyWriter.
@@ -102,12 +116,13 @@ func write(w io.Writer, msg *tinkpb.Keyset) (kms.KeyType, error) {
 		if key.KeyId == primaryKID && key.Status == tinkpb.KeyStatusType_ENABLED {
 			switch key.KeyData.TypeUrl {
 			case ecdsaVerifierTypeURL, ed25519VerifierTypeURL, bbsVerifierKeyTypeURL, clCredDefKeyTypeURL,
-				secp256k1VerifierTypeURL:
+				secp256k1VerifierTypeURL, rsaSSAPKCS1VerifierTypeURL, rsapss.VerifierTypeURL, rsaoaep.EncrypterTypeURL,
+				bip340.VerifierTypeURL:
 				created, kt, err = writePubKey(w, key)
 				if err != nil {
 					return "", err
 				}
-			case nistPECDHKWPublicKeyTypeURL, x25519ECDHKWPublicKeyTypeURL:
+			case nistPECDHKWPublicKeyTypeURL, x25519ECDHKWPublicKeyTypeURL, x448ECDHKWPublicKeyTypeURL:
 				pkW := keyio.NewWriter(w)
 
 				err = pkW.Write(msg)
@@ -202,6 +217,37 @@ func writePubKey(w io.Writer, key *tinkpb.Keyset_Key) (bool, kms.KeyType, error)
 		if err != nil {
 			return false, "", err
 		}
+	case bip340.VerifierTypeURL:
+		pubKeyProto := new(secp256k1pb.Secp256K1PublicKey)
+
+		err := proto.Unmarshal(key.KeyData.Value, pubKeyProto)
+		if err != nil {
+			return false, "", err
+		}
+
+		// BIP-340 public keys are exported as 32 bytes x-only keys.
+		marshaledRawPubKey = make([]byte, len(pubKeyProto.X))
+		copy(marshaledRawPubKey, pubKeyProto.X)
+
+		kt = kms.BIP340Secp256k1Type
+	case rsaSSAPKCS1VerifierTypeURL, rsapss.VerifierTypeURL, rsaoaep.EncrypterTypeURL:
+		pubKeyProto := new(rsapb.RsaSsaPkcs1PublicKey)
+
+		err := proto.Unmarshal(key.KeyData.Value, pubKeyProto)
+		if err != nil {
+			return false, "", err
+		}
+
+		// RSA public keys are exported as PKIX (SubjectPublicKeyInfo) DER.
+		marshaledRawPubKey, err = x509.MarshalPKIXPublicKey(&rsa.PublicKey{
+			N: new(big.Int).SetBytes(pubKeyProto.N),
+			E: int(new(big.Int).SetBytes(pubKeyProto.E).Int64()),
+		})
+		if err != nil {
+			return false, "", err
+		}
+
+		kt = rsaKMSKeyTypes[key.KeyData.TypeUrl]
 	default:
 		return false, "", fmt.Errorf("can't export key with keyURL:%s", key.KeyData.TypeUrl)
 	}
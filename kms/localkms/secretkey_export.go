@@ -0,0 +1,100 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/golang/protobuf/proto"
+	gcmpb "github.com/google/tink/go/proto/aes_gcm_go_proto"
+	chachapb "github.com/google/tink/go/proto/chacha20_poly1305_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	xchachapb "github.com/google/tink/go/proto/xchacha20_poly1305_go_proto"
+
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/kms/audit"
+)
+
+const octKty = "oct"
+
+// ExportSecretKeyJWK exports the primary key of the AEAD keyset keyID as an oct JWK (RFC 7518 section 6.4) with the
+// JOSE content encryption algorithm of the key: C20P for ChaCha20Poly1305Type keys, XC20P for XChaCha20Poly1305Type
+// keys and A128GCM or A256GCM for AES-GCM keys. The JWK kid is keyID.
+//
+// It is intended for interop with consumers holding the key themselves, eg: libsodium's
+// crypto_aead_chacha20poly1305_ietf with ChaCha20Poly1305Type keys, for which the ciphertext and nonce returned by
+// Crypto.Encrypt are the combined mode ciphertext and its 12 bytes nonce. The returned JWK contains the secret key in
+// clear: it must be protected by the caller, ExportPrivateKey exports asymmetric keys wrapped instead.
+func (l *LocalKMS) ExportSecretKeyJWK(keyID string) (*jwk.JWK, error) {
+	start := time.Now()
+	j, err := l.exportSecretKeyJWK(keyID)
+
+	audit.Log(l.auditLogger, kmsapi.OperationExportPrivate, keyID, nil, start, err)
+
+	return j, err
+}
+
+func (l *LocalKMS) exportSecretKeyJWK(keyID string) (*jwk.JWK, error) {
+	key, err := l.primaryKey(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("export secret key: %w", err)
+	}
+
+	secret, alg, err := aeadSecretKey(key.KeyData)
+	if err != nil {
+		return nil, fmt.Errorf("export secret key: %w", err)
+	}
+
+	return &jwk.JWK{
+		JSONWebKey: jose.JSONWebKey{Key: secret, KeyID: keyID, Algorithm: alg, Use: "enc"},
+		Kty:        octKty,
+	}, nil
+}
+
+// aeadSecretKey returns the raw key of the AEAD key data kd and its JOSE content encryption algorithm.
+func aeadSecretKey(kd *tinkpb.KeyData) ([]byte, string, error) {
+	switch kd.TypeUrl {
+	case chaCha20Poly1305KeyTypeURL:
+		key := &chachapb.ChaCha20Poly1305Key{}
+
+		if err := proto.Unmarshal(kd.Value, key); err != nil || len(key.KeyValue) != chaCha20Poly1305KeySize {
+			return nil, "", errors.New("invalid ChaCha20Poly1305 key")
+		}
+
+		return key.KeyValue, "C20P", nil
+	case xChaCha20Poly1305TypeURL:
+		key := &xchachapb.XChaCha20Poly1305Key{}
+
+		if err := proto.Unmarshal(kd.Value, key); err != nil || len(key.KeyValue) != chaCha20Poly1305KeySize {
+			return nil, "", errors.New("invalid XChaCha20Poly1305 key")
+		}
+
+		return key.KeyValue, "XC20P", nil
+	case aesGCMKeyTypeURL:
+		key := &gcmpb.AesGcmKey{}
+
+		if err := proto.Unmarshal(kd.Value, key); err != nil {
+			return nil, "", errors.New("invalid AES-GCM key")
+		}
+
+		switch len(key.KeyValue) {
+		case aes128KeySize:
+			return key.KeyValue, "A128GCM", nil
+		case aes256KeySize:
+			return key.KeyValue, "A256GCM", nil
+		default:
+			return nil, "", fmt.Errorf("unsupported AES-GCM key size %d", len(key.KeyValue))
+		}
+	default:
+		return nil, "", fmt.Errorf("key type '%s' can't be exported as a JWK", kd.TypeUrl)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+func TestLocalKMS_ExportSecretKeyJWK(t *testing.T) {
+	k, err := New(testMasterKeyURI, &mockProvider{storage: newInMemoryKMSStore(), secretLock: &noop.NoLock{}})
+	require.NoError(t, err)
+
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	msg, aad := []byte("message"), []byte("aad")
+
+	t.Run("ChaCha20Poly1305 IETF interop", func(t *testing.T) {
+		keyID, kh, err := k.Create(kmsapi.ChaCha20Poly1305Type)
+		require.NoError(t, err)
+
+		ct, nonce, err := c.Encrypt(msg, aad, kh)
+		require.NoError(t, err)
+		require.Len(t, nonce, chacha20poly1305.NonceSize)
+
+		j, err := k.ExportSecretKeyJWK(keyID)
+		require.NoError(t, err)
+
+		mJWK, err := json.Marshal(j)
+		require.NoError(t, err)
+
+		parsed := &jwk.JWK{}
+		require.NoError(t, json.Unmarshal(mJWK, parsed))
+		require.Equal(t, "oct", parsed.Kty)
+		require.Equal(t, "C20P", parsed.Algorithm)
+		require.Equal(t, keyID, parsed.KeyID)
+
+		kt, err := parsed.KeyType()
+		require.NoError(t, err)
+		require.Equal(t, kmsapi.ChaCha20Poly1305Type, kt)
+
+		// the key decrypts the combined mode ciphertext of a libsodium style IETF ChaCha20-Poly1305 consumer.
+		a, err := chacha20poly1305.New(parsed.Key.([]byte))
+		require.NoError(t, err)
+
+		pt, err := a.Open(nil, nonce, ct, aad)
+		require.NoError(t, err)
+		require.Equal(t, msg, pt)
+
+		// and the KMS decrypts the consumer's ciphertexts.
+		ct = a.Seal(nil, nonce, []byte("reply"), aad)
+
+		pt, err = c.Decrypt(ct, aad, nonce, kh)
+		require.NoError(t, err)
+		require.Equal(t, []byte("reply"), pt)
+
+		// raw keys of consumers are imported.
+		rawKey := make([]byte, chacha20poly1305.KeySize)
+		_, err = rand.Read(rawKey)
+		require.NoError(t, err)
+
+		importedID, importedKH, err := k.ImportPrivateKey(rawKey, kmsapi.ChaCha20Poly1305Type)
+		require.NoError(t, err)
+
+		a, err = chacha20poly1305.New(rawKey)
+		require.NoError(t, err)
+
+		pt, err = c.Decrypt(a.Seal(nil, nonce, msg, aad), aad, nonce, importedKH)
+		require.NoError(t, err)
+		require.Equal(t, msg, pt)
+
+		j, err = k.ExportSecretKeyJWK(importedID)
+		require.NoError(t, err)
+		require.Equal(t, rawKey, j.Key)
+	})
+
+	t.Run("AEAD key types", func(t *testing.T) {
+		for kt, alg := range map[kmsapi.KeyType]string{
+			kmsapi.XChaCha20Poly1305Type: "XC20P", kmsapi.AES128GCMType: "A128GCM", kmsapi.AES256GCMType: "A256GCM",
+		} {
+			keyID, _, err := k.Create(kt)
+			require.NoError(t, err)
+
+			j, err := k.ExportSecretKeyJWK(keyID)
+			require.NoError(t, err)
+			require.Equal(t, alg, j.Algorithm)
+
+			detected, err := j.KeyType()
+			require.NoError(t, err)
+			require.Equal(t, kt, detected)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := k.ExportSecretKeyJWK("missing")
+		require.ErrorContains(t, err, "export secret key")
+
+		keyID, _, err := k.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		_, err = k.ExportSecretKeyJWK(keyID)
+		require.EqualError(t, err, "export secret key: key type "+
+			"'type.googleapis.com/google.crypto.tink.Ed25519PrivateKey' can't be exported as a JWK")
+	})
+}
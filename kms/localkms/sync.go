@@ -0,0 +1,251 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/tink/go/keyset"
+
+	"github.com/trustbloc/kms-go/internal/memguard"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/kms"
+)
+
+// SyncPolicy is the conflict resolution policy of SyncStores.
+type SyncPolicy int
+
+const (
+	// SyncNewerWins copies the source keys that are missing or have a newer version in the target store, and keeps the
+	// target keys of conflicts.
+	SyncNewerWins SyncPolicy = iota
+	// SyncSourceWins copies the missing and updated source keys and overwrites the target keys of conflicts.
+	SyncSourceWins
+	// SyncTargetWins only copies the source keys that are missing in the target store.
+	SyncTargetWins
+	// SyncFailOnConflict copies the missing and updated source keys, unless there are conflicts, in which case nothing
+	// is copied and ErrSyncConflict is returned.
+	SyncFailOnConflict
+)
+
+// ErrSyncConflict is returned by SyncStores when the SyncFailOnConflict policy finds conflicts.
+var ErrSyncConflict = errors.New("key stores have conflicting keys")
+
+// KeyVersion is the version of a stored key: the number of keys of its keyset, incremented by each rotation, and the
+// ID of the keyset primary key. It is read from the cleartext keyset info of the encrypted envelope.
+type KeyVersion struct {
+	Version      int
+	PrimaryKeyID uint32
+}
+
+// SyncDiff is the difference between a source and a target key store, by key ID.
+type SyncDiff struct {
+	// Missing are the IDs of the source keys not found in the target store.
+	Missing []string
+	// Updated are the IDs of the keys with a newer version in the source store.
+	Updated []string
+	// Conflicts are the IDs of the keys which envelopes differ although the target version is the same or newer.
+	Conflicts []string
+}
+
+// SyncResult is the outcome of SyncStores.
+type SyncResult struct {
+	SyncDiff
+	// Copied are the IDs of the source keys written to the target store.
+	Copied []string
+}
+
+// SyncOpts are the options of DiffStores and SyncStores.
+type SyncOpts func(opts *syncOpts)
+
+type syncOpts struct {
+	policy SyncPolicy
+	keyIDs []string
+}
+
+// WithSyncPolicy sets the conflict resolution policy of SyncStores. The default is SyncNewerWins.
+func WithSyncPolicy(policy SyncPolicy) SyncOpts {
+	return func(opts *syncOpts) {
+		opts.policy = policy
+	}
+}
+
+// WithSyncKeyIDs restricts the sync to the given key IDs. It is required when the source store isn't a
+// kms.StoreLister.
+func WithSyncKeyIDs(keyIDs ...string) SyncOpts {
+	return func(opts *syncOpts) {
+		opts.keyIDs = keyIDs
+	}
+}
+
+// DiffStores computes the difference between the LocalKMS key stores source and target. Key envelopes are compared
+// without being decrypted.
+func DiffStores(source, target kmsapi.Store, opts ...SyncOpts) (*SyncDiff, error) {
+	sOpts := newSyncOpts(opts...)
+
+	diff, err := diffStores(source, target, sOpts.keyIDs)
+	if err != nil {
+		return nil, fmt.Errorf("diff stores: %w", err)
+	}
+
+	return diff, nil
+}
+
+// SyncStores copies the encrypted envelopes of the source keys missing or updated in target, resolving conflicts
+// with the sync policy, for active-passive replicas (eg: wallet backups). Envelopes are copied as is: both stores
+// must be used by LocalKMS instances sharing the same primary key. Keys deleted in source are not deleted in target.
+func SyncStores(source, target kmsapi.Store, opts ...SyncOpts) (*SyncResult, error) {
+	sOpts := newSyncOpts(opts...)
+
+	diff, err := diffStores(source, target, sOpts.keyIDs)
+	if err != nil {
+		return nil, fmt.Errorf("sync stores: %w", err)
+	}
+
+	result := &SyncResult{SyncDiff: *diff}
+
+	keyIDs := append([]string{}, diff.Missing...)
+
+	switch sOpts.policy {
+	case SyncNewerWins:
+		keyIDs = append(keyIDs, diff.Updated...)
+	case SyncSourceWins:
+		keyIDs = append(append(keyIDs, diff.Updated...), diff.Conflicts...)
+	case SyncTargetWins:
+	case SyncFailOnConflict:
+		if len(diff.Conflicts) > 0 {
+			return result, fmt.Errorf("sync stores: %w: %v", ErrSyncConflict, diff.Conflicts)
+		}
+
+		keyIDs = append(keyIDs, diff.Updated...)
+	default:
+		return nil, fmt.Errorf("sync stores: invalid sync policy %d", sOpts.policy)
+	}
+
+	for _, keyID := range keyIDs {
+		if err = copyEnvelope(source, target, keyID); err != nil {
+			return result, fmt.Errorf("sync stores: key '%s': %w", keyID, err)
+		}
+
+		result.Copied = append(result.Copied, keyID)
+	}
+
+	return result, nil
+}
+
+func newSyncOpts(opts ...SyncOpts) *syncOpts {
+	sOpts := &syncOpts{}
+
+	for _, opt := range opts {
+		opt(sOpts)
+	}
+
+	return sOpts
+}
+
+func diffStores(source, target kmsapi.Store, keyIDs []string) (*SyncDiff, error) {
+	if keyIDs == nil {
+		lister, ok := source.(kmsapi.StoreLister)
+		if !ok {
+			return nil, errors.New("source store can't list its keys, key IDs must be set")
+		}
+
+		var err error
+
+		keyIDs, err = lister.KeyIDs()
+		if err != nil {
+			return nil, fmt.Errorf("list source keys: %w", err)
+		}
+
+		// stores may list keys in any order.
+		sort.Strings(keyIDs)
+	}
+
+	diff := &SyncDiff{}
+
+	for _, keyID := range keyIDs {
+		srcEnvelope, err := source.Get(keyID)
+		if err != nil {
+			return nil, fmt.Errorf("key '%s': %w", keyID, err)
+		}
+
+		tgtEnvelope, err := target.Get(keyID)
+		if errors.Is(err, kms.ErrKeyNotFound) {
+			diff.Missing = append(diff.Missing, keyID)
+
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("key '%s': %w", keyID, err)
+		}
+
+		if memguard.Equal(srcEnvelope, tgtEnvelope) {
+			continue
+		}
+
+		srcVersion, err := EnvelopeVersion(srcEnvelope)
+		if err != nil {
+			return nil, fmt.Errorf("source key '%s': %w", keyID, err)
+		}
+
+		tgtVersion, err := EnvelopeVersion(tgtEnvelope)
+		if err != nil {
+			return nil, fmt.Errorf("target key '%s': %w", keyID, err)
+		}
+
+		if srcVersion.Version > tgtVersion.Version {
+			diff.Updated = append(diff.Updated, keyID)
+		} else {
+			diff.Conflicts = append(diff.Conflicts, keyID)
+		}
+	}
+
+	return diff, nil
+}
+
+// EnvelopeVersion returns the version of a key envelope stored by LocalKMS.
+func EnvelopeVersion(envelope []byte) (*KeyVersion, error) {
+	eks, err := keyset.NewJSONReader(bytes.NewReader(envelope)).ReadEncrypted()
+	if err != nil {
+		return nil, fmt.Errorf("read key envelope: %w", err)
+	}
+
+	info := eks.GetKeysetInfo()
+	if info == nil {
+		return nil, errors.New("read key envelope: missing keyset info")
+	}
+
+	return &KeyVersion{Version: len(info.GetKeyInfo()), PrimaryKeyID: info.GetPrimaryKeyId()}, nil
+}
+
+func copyEnvelope(source, target kmsapi.Store, keyID string) error {
+	srcMetadata, ok := source.(kmsapi.StoreWithMetadata)
+	if !ok {
+		envelope, err := source.Get(keyID)
+		if err != nil {
+			return err
+		}
+
+		return target.Put(keyID, envelope)
+	}
+
+	envelope, metadata, err := srcMetadata.GetWithMetadata(keyID)
+	if err != nil {
+		return err
+	}
+
+	if tgtMetadata, ok := target.(kmsapi.StoreWithMetadata); ok && metadata != nil {
+		return tgtMetadata.PutWithMetadata(keyID, envelope, metadata)
+	}
+
+	return target.Put(keyID, envelope)
+}
@@ -0,0 +1,215 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/kms"
+)
+
+func TestSyncStores(t *testing.T) {
+	secretLock := createMasterKeyAndSecretLock(t)
+
+	newReplica := func(t *testing.T) (*LocalKMS, *inMemoryKMSStore) {
+		t.Helper()
+
+		store := newInMemoryKMSStore()
+
+		k, err := New(testMasterKeyURI, &mockProvider{storage: store, secretLock: secretLock})
+		require.NoError(t, err)
+
+		return k, store
+	}
+
+	t.Run("sync missing and updated keys", func(t *testing.T) {
+		active, activeStore := newReplica(t)
+		passive, passiveStore := newReplica(t)
+
+		kid1, _, err := active.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		kid2, _, err := active.Create(kmsapi.AES256GCMType)
+		require.NoError(t, err)
+
+		result, err := SyncStores(activeStore, passiveStore)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{kid1, kid2}, result.Missing)
+		require.ElementsMatch(t, []string{kid1, kid2}, result.Copied)
+
+		_, err = passive.Get(kid1)
+		require.NoError(t, err)
+
+		// a new version of a key on the active replica is an update for the passive one.
+		rotateInPlace(t, active, kid2)
+
+		diff, err := DiffStores(activeStore, passiveStore)
+		require.NoError(t, err)
+		require.Empty(t, diff.Missing)
+		require.Equal(t, []string{kid2}, diff.Updated)
+		require.Empty(t, diff.Conflicts)
+
+		result, err = SyncStores(activeStore, passiveStore)
+		require.NoError(t, err)
+		require.Equal(t, []string{kid2}, result.Copied)
+
+		version, err := EnvelopeVersion(passiveStore.keys[kid2])
+		require.NoError(t, err)
+		require.Equal(t, 2, version.Version)
+
+		// nothing left to sync.
+		result, err = SyncStores(activeStore, passiveStore)
+		require.NoError(t, err)
+		require.Empty(t, result.Copied)
+	})
+
+	t.Run("conflict resolution policies", func(t *testing.T) {
+		active, activeStore := newReplica(t)
+		passive, passiveStore := newReplica(t)
+
+		kid, _, err := active.Create(kmsapi.AES256GCMType)
+		require.NoError(t, err)
+
+		_, err = SyncStores(activeStore, passiveStore)
+		require.NoError(t, err)
+
+		// both replicas rotate the same key: same version, different keys.
+		rotateInPlace(t, active, kid)
+
+		rotateInPlace(t, passive, kid)
+
+		passiveEnvelope := passiveStore.keys[kid]
+
+		result, err := SyncStores(activeStore, passiveStore, WithSyncPolicy(SyncFailOnConflict))
+		require.True(t, errors.Is(err, ErrSyncConflict))
+		require.Equal(t, []string{kid}, result.Conflicts)
+		require.Equal(t, passiveEnvelope, passiveStore.keys[kid])
+
+		for _, policy := range []SyncPolicy{SyncNewerWins, SyncTargetWins} {
+			result, err = SyncStores(activeStore, passiveStore, WithSyncPolicy(policy))
+			require.NoError(t, err)
+			require.Empty(t, result.Copied)
+			require.Equal(t, passiveEnvelope, passiveStore.keys[kid])
+		}
+
+		result, err = SyncStores(activeStore, passiveStore, WithSyncPolicy(SyncSourceWins))
+		require.NoError(t, err)
+		require.Equal(t, []string{kid}, result.Copied)
+		require.Equal(t, activeStore.keys[kid], passiveStore.keys[kid])
+	})
+
+	t.Run("target wins keeps updated keys", func(t *testing.T) {
+		active, activeStore := newReplica(t)
+		_, passiveStore := newReplica(t)
+
+		kid1, _, err := active.Create(kmsapi.AES256GCMType)
+		require.NoError(t, err)
+
+		_, err = SyncStores(activeStore, passiveStore)
+		require.NoError(t, err)
+
+		rotateInPlace(t, active, kid1)
+
+		kid2, _, err := active.Create(kmsapi.AES256GCMType)
+		require.NoError(t, err)
+
+		result, err := SyncStores(activeStore, passiveStore, WithSyncPolicy(SyncTargetWins))
+		require.NoError(t, err)
+		require.Equal(t, []string{kid1}, result.Updated)
+		require.Equal(t, []string{kid2}, result.Copied)
+	})
+
+	t.Run("sync selected keys of a store without listing", func(t *testing.T) {
+		active, activeStore := newReplica(t)
+		_, passiveStore := newReplica(t)
+
+		kid1, _, err := active.Create(kmsapi.AES256GCMType)
+		require.NoError(t, err)
+
+		_, _, err = active.Create(kmsapi.AES256GCMType)
+		require.NoError(t, err)
+
+		source := &struct{ kmsapi.Store }{activeStore}
+
+		_, err = SyncStores(source, passiveStore)
+		require.EqualError(t, err, "sync stores: source store can't list its keys, key IDs must be set")
+
+		result, err := SyncStores(source, passiveStore, WithSyncKeyIDs(kid1))
+		require.NoError(t, err)
+		require.Equal(t, []string{kid1}, result.Copied)
+		require.Len(t, passiveStore.keys, 1)
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		active, activeStore := newReplica(t)
+		_, passiveStore := newReplica(t)
+
+		kid, _, err := active.Create(kmsapi.AES256GCMType)
+		require.NoError(t, err)
+
+		_, err = DiffStores(activeStore, passiveStore, WithSyncKeyIDs("unknown"))
+		require.ErrorIs(t, err, kms.ErrKeyNotFound)
+
+		_, err = SyncStores(activeStore, &mockStore{errGet: errors.New("get failed")})
+		require.EqualError(t, err, "sync stores: key '"+kid+"': get failed")
+
+		_, err = SyncStores(activeStore, &mockStore{errGet: kms.ErrKeyNotFound, errPut: errors.New("put failed")})
+		require.EqualError(t, err, "sync stores: key '"+kid+"': put failed")
+
+		_, err = SyncStores(activeStore, passiveStore, WithSyncPolicy(SyncPolicy(-1)))
+		require.EqualError(t, err, "sync stores: invalid sync policy -1")
+
+		passiveStore.keys[kid] = []byte("bad envelope")
+
+		_, err = DiffStores(activeStore, passiveStore)
+		require.ErrorContains(t, err, "target key '"+kid+"': read key envelope")
+
+		_, err = EnvelopeVersion([]byte("{}"))
+		require.EqualError(t, err, "read key envelope: missing keyset info")
+	})
+
+	t.Run("keys are listed in order", func(t *testing.T) {
+		active, activeStore := newReplica(t)
+		_, passiveStore := newReplica(t)
+
+		for i := 0; i < 5; i++ {
+			_, _, err := active.Create(kmsapi.AES256GCMType)
+			require.NoError(t, err)
+		}
+
+		result, err := SyncStores(activeStore, passiveStore)
+		require.NoError(t, err)
+		require.True(t, sort.StringsAreSorted(result.Copied))
+	})
+}
+
+// rotateInPlace rotates the keyset keyID, keeping its ID (LocalKMS.Rotate gives rotated keys a new ID).
+func rotateInPlace(t *testing.T, k *LocalKMS, keyID string) {
+	t.Helper()
+
+	kh, err := k.getKeySet(keyID)
+	require.NoError(t, err)
+
+	km := keyset.NewManagerFromHandle(kh)
+	require.NoError(t, km.Rotate(aead.AES256GCMKeyTemplate()))
+
+	kh, err = km.Handle()
+	require.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, kh.Write(keyset.NewJSONWriter(buf), k.primaryKeyEnvAEAD))
+	require.NoError(t, k.store.Put(keyID, buf.Bytes()))
+}
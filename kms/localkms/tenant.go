@@ -0,0 +1,218 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/kms"
+)
+
+const (
+	tenantKeyPrefix       = "tenant/"
+	tenantMasterKeyPrefix = "tenant-master/"
+	tenantSeparator       = "/"
+)
+
+// NewTenantKMS returns a LocalKMS for the keys of the tenant tenantID, sharing the store of l. The keys of a tenant
+// are stored under a prefix of the tenant ID, so the key IDs of a tenant are only resolved in its namespace: a tenant
+// LocalKMS can't read, rotate or delete the keys of another tenant, nor the keys of l.
+//
+// Each tenant has its own AES-256-GCM master key, created on first use and stored by l (wrapped by the primary key of
+// l) under the "tenant-master/<tenantID>" key ID: the keysets of a tenant are wrapped by its master key, so deleting
+// the master key of a tenant makes all its keys unreadable. tenantID can't be empty or contain a '/'.
+//
+// The tenant LocalKMS has the options of l, with a handle cache of its own.
+func (l *LocalKMS) NewTenantKMS(tenantID string) (*LocalKMS, error) {
+	if tenantID == "" || strings.Contains(tenantID, tenantSeparator) {
+		return nil, fmt.Errorf("new tenant KMS: invalid tenant ID '%s'", tenantID)
+	}
+
+	masterKey, err := l.tenantMasterKey(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("new tenant KMS: tenant '%s' master key: %w", tenantID, err)
+	}
+
+	kw, err := aead.New(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("new tenant KMS: tenant '%s' master key: %w", tenantID, err)
+	}
+
+	return &LocalKMS{
+		store:             &tenantStore{store: l.store, prefix: tenantKeyPrefix + tenantID + tenantSeparator},
+		secretLock:        l.secretLock,
+		primaryKeyURI:     l.primaryKeyURI,
+		primaryKeyEnvAEAD: aead.NewKMSEnvelopeAEAD2(aead.AES256GCMKeyTemplate(), kw),
+		auditLogger:       l.auditLogger,
+		handles:           newHandleCache(l.opts),
+		randomness:        l.randomness,
+		opts:              l.opts,
+		logger:            l.logger.With("tenant", tenantID),
+	}, nil
+}
+
+// tenantMasterKey returns the master key of tenantID, creating it if it doesn't exist.
+func (l *LocalKMS) tenantMasterKey(tenantID string) (*keyset.Handle, error) {
+	keyID := tenantMasterKeyPrefix + tenantID
+
+	kh, err := l.getKeySet(keyID)
+	if err == nil {
+		return kh, nil
+	}
+
+	if !errors.Is(err, kms.ErrKeyNotFound) {
+		return nil, err
+	}
+
+	kh, err = newKeysetHandle(aead.AES256GCMKeyTemplate(), l.randomness)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+
+	if err = kh.Write(keyset.NewJSONWriter(buf), l.primaryKeyEnvAEAD); err != nil {
+		return nil, err
+	}
+
+	if _, err = l.writeToStore(buf, kmsapi.WithKeyID(keyID)); err != nil {
+		// the master key may have been created concurrently by another LocalKMS sharing the store.
+		if existing, e := l.getKeySet(keyID); e == nil {
+			return existing, nil
+		}
+
+		return nil, err
+	}
+
+	return kh, nil
+}
+
+// tenantStore is the namespace of a tenant in a store: the key IDs are stored with the tenant prefix.
+type tenantStore struct {
+	store  kmsapi.Store
+	prefix string
+}
+
+func (s *tenantStore) Put(keysetID string, key []byte) error {
+	return s.store.Put(s.prefix+keysetID, key)
+}
+
+func (s *tenantStore) Get(keysetID string) ([]byte, error) {
+	return s.store.Get(s.prefix + keysetID)
+}
+
+func (s *tenantStore) Delete(keysetID string) error {
+	return s.store.Delete(s.prefix + keysetID)
+}
+
+func (s *tenantStore) PutWithMetadata(keysetID string, key []byte, metadata map[string]any) error {
+	ms, ok := s.store.(kmsapi.StoreWithMetadata)
+	if !ok {
+		return errors.New("storage doesn't support metadata")
+	}
+
+	return ms.PutWithMetadata(s.prefix+keysetID, key, metadata)
+}
+
+func (s *tenantStore) GetWithMetadata(keysetID string) ([]byte, map[string]any, error) {
+	ms, ok := s.store.(kmsapi.StoreWithMetadata)
+	if !ok {
+		return nil, nil, errors.New("storage doesn't support metadata")
+	}
+
+	return ms.GetWithMetadata(s.prefix + keysetID)
+}
+
+func (s *tenantStore) Create(keysetID string, key []byte, metadata map[string]any) error {
+	if _, ok := storeVersioner(s.store); ok {
+		err := s.PutWithVersion(keysetID, key, metadata, 0)
+
+		var conflict *kms.VersionConflictError
+		if errors.As(err, &conflict) {
+			return fmt.Errorf("%w: %w", kms.ErrKeyExists, err)
+		}
+
+		return err
+	}
+
+	creator, ok := s.store.(kmsapi.StoreCreator)
+	if ok {
+		return creator.Create(s.prefix+keysetID, key, metadata)
+	}
+
+	if len(metadata) != 0 {
+		return s.PutWithMetadata(keysetID, key, metadata)
+	}
+
+	return s.Put(keysetID, key)
+}
+
+func (s *tenantStore) GetWithVersion(keysetID string) ([]byte, map[string]any, int64, error) {
+	versioner, ok := storeVersioner(s.store)
+	if !ok {
+		return nil, nil, 0, errors.New("storage doesn't support versions")
+	}
+
+	return versioner.GetWithVersion(s.prefix + keysetID)
+}
+
+func (s *tenantStore) PutWithVersion(keysetID string, key []byte, metadata map[string]any,
+	expectedVersion int64) error {
+	versioner, ok := storeVersioner(s.store)
+	if !ok {
+		return errors.New("storage doesn't support versions")
+	}
+
+	return versioner.PutWithVersion(s.prefix+keysetID, key, metadata, expectedVersion)
+}
+
+// storeVersioner returns store as a kmsapi.StoreVersioner if it supports versions, a tenantStore does if the store it
+// prefixes does.
+func storeVersioner(store kmsapi.Store) (kmsapi.StoreVersioner, bool) {
+	if ts, ok := store.(*tenantStore); ok {
+		if _, ok = storeVersioner(ts.store); !ok {
+			return nil, false
+		}
+
+		return ts, true
+	}
+
+	versioner, ok := store.(kmsapi.StoreVersioner)
+
+	return versioner, ok
+}
+
+// KeyIDs returns the IDs of the keys of the tenant.
+func (s *tenantStore) KeyIDs() ([]string, error) {
+	lister, ok := s.store.(kmsapi.StoreLister)
+	if !ok {
+		return nil, errors.New("storage doesn't support listing key IDs")
+	}
+
+	ids, err := lister.KeyIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	var keyIDs []string
+
+	for _, id := range ids {
+		if keyID, found := strings.CutPrefix(id, s.prefix); found {
+			keyIDs = append(keyIDs, keyID)
+		}
+	}
+
+	return keyIDs, nil
+}
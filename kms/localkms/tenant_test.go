@@ -0,0 +1,134 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+)
+
+func TestLocalKMS_NewTenantKMS(t *testing.T) {
+	store := newInMemoryKMSStore()
+
+	kmsService, err := New(testMasterKeyURI, &mockProvider{storage: store, secretLock: &noop.NoLock{}},
+		WithKeyHandleCache(10, time.Minute))
+	require.NoError(t, err)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	msg := []byte("message")
+
+	tenantA, err := kmsService.NewTenantKMS("a")
+	require.NoError(t, err)
+
+	tenantB, err := kmsService.NewTenantKMS("b")
+	require.NoError(t, err)
+
+	keyID, kh, err := tenantA.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	sig, err := cr.Sign(msg, kh)
+	require.NoError(t, err)
+
+	require.Contains(t, store.keys, tenantKeyPrefix+"a/"+keyID)
+	require.Contains(t, store.keys, tenantMasterKeyPrefix+"a")
+	require.Contains(t, store.keys, tenantMasterKeyPrefix+"b")
+
+	t.Run("tenant keys are isolated", func(t *testing.T) {
+		_, err = tenantB.Get(keyID)
+		require.Error(t, err)
+
+		_, err = kmsService.Get(keyID)
+		require.Error(t, err)
+
+		_, _, err = tenantB.Rotate(kmsapi.ED25519Type, keyID)
+		require.Error(t, err)
+
+		_, err = tenantA.Get(tenantMasterKeyPrefix + "a")
+		require.Error(t, err)
+
+		// the keyset of tenant a is wrapped by its own master key, tenant b can't decrypt it.
+		store.keys[tenantKeyPrefix+"b/"+keyID] = store.keys[tenantKeyPrefix+"a/"+keyID]
+
+		_, err = tenantB.Get(keyID)
+		require.Error(t, err)
+
+		ids, err := tenantA.store.(*tenantStore).KeyIDs()
+		require.NoError(t, err)
+		require.Equal(t, []string{keyID}, ids)
+	})
+
+	t.Run("tenant KMS reopened on the same store", func(t *testing.T) {
+		reopened, err := New(testMasterKeyURI, &mockProvider{storage: store, secretLock: &noop.NoLock{}})
+		require.NoError(t, err)
+
+		tenant, err := reopened.NewTenantKMS("a")
+		require.NoError(t, err)
+
+		stored, err := tenant.Get(keyID)
+		require.NoError(t, err)
+
+		pubKey, _, err := tenant.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+		require.NotEmpty(t, pubKey)
+
+		pubKH, err := stored.(*keyset.Handle).Public()
+		require.NoError(t, err)
+		require.NoError(t, cr.Verify(sig, msg, pubKH))
+	})
+
+	t.Run("deleting the tenant master key shreds its keys", func(t *testing.T) {
+		tenant, err := kmsService.NewTenantKMS("c")
+		require.NoError(t, err)
+
+		kid, _, err := tenant.Create(kmsapi.AES256GCMType)
+		require.NoError(t, err)
+
+		require.NoError(t, store.Delete(tenantMasterKeyPrefix+"c"))
+		kmsService.InvalidateKeyHandles()
+
+		tenant, err = kmsService.NewTenantKMS("c")
+		require.NoError(t, err)
+
+		_, err = tenant.Get(kid)
+		require.Error(t, err)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := kmsService.NewTenantKMS("")
+		require.EqualError(t, err, "new tenant KMS: invalid tenant ID ''")
+
+		_, err = kmsService.NewTenantKMS("a/b")
+		require.EqualError(t, err, "new tenant KMS: invalid tenant ID 'a/b'")
+
+		failing, err := New(testMasterKeyURI, &mockProvider{
+			storage:    &mockStore{errGet: errors.New("store unavailable")},
+			secretLock: &noop.NoLock{},
+		})
+		require.NoError(t, err)
+
+		_, err = failing.NewTenantKMS("a")
+		require.ErrorContains(t, err, "new tenant KMS: tenant 'a' master key")
+		require.ErrorContains(t, err, "store unavailable")
+
+		_, _, err = tenantA.store.(*tenantStore).GetWithMetadata(keyID)
+		require.EqualError(t, err, "storage doesn't support metadata")
+
+		_, _, err = tenantA.Create(kmsapi.AES256GCMType, kmsapi.WithMetadata(map[string]any{"k": "v"}))
+		require.ErrorContains(t, err, "storage doesn't support")
+	})
+}
@@ -0,0 +1,120 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/tink"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/util/fips"
+
+	"github.com/trustbloc/kms-go/kms/audit"
+)
+
+// ImportKeyset imports a Tink keyset read from r, in the binary (keyset.NewBinaryReader) or JSON
+// (keyset.NewJSONReader) keyset format of all the Tink implementations (eg: Tink Java or Python). If keysetAEAD is
+// nil, the keyset is in cleartext, otherwise it is an encrypted keyset and keysetAEAD decrypts it (without
+// associated data, as Tink does).
+//
+// The Tink key IDs, statuses and primary key of the keyset are preserved. 'opts' allows setting the keysetID of the
+// imported keyset using WithKeyID() option, otherwise a random keysetID is used.
+// Returns:
+//   - keyID of the handle
+//   - handle instance (to private key)
+//   - error if import failure (invalid or undecryptable keyset, key type not approved in FIPS mode or storing failed)
+func (l *LocalKMS) ImportKeyset(r keyset.Reader, keysetAEAD tink.AEAD,
+	opts ...kmsapi.PrivateKeyOpts) (string, interface{}, error) {
+	start := time.Now()
+	keyID, kh, err := l.importKeyset(r, keysetAEAD, opts...)
+
+	audit.Log(l.auditLogger, kmsapi.OperationImportPrivate, keyID, nil, start, err)
+
+	return keyID, kh, err
+}
+
+func (l *LocalKMS) importKeyset(r keyset.Reader, keysetAEAD tink.AEAD,
+	opts ...kmsapi.PrivateKeyOpts) (string, *keyset.Handle, error) {
+	var (
+		kh  *keyset.Handle
+		err error
+	)
+
+	if keysetAEAD == nil {
+		kh, err = insecurecleartextkeyset.Read(r)
+	} else {
+		kh, err = keyset.Read(r, keysetAEAD)
+	}
+
+	if err != nil {
+		return "", nil, fmt.Errorf("import keyset: %w", err)
+	}
+
+	// Tink reads keysets with keys without key data, KeysetInfo panics on them.
+	if err = keyset.Validate(insecurecleartextkeyset.KeysetMaterial(kh)); err != nil {
+		return "", nil, fmt.Errorf("import keyset: %w", err)
+	}
+
+	for _, ki := range kh.KeysetInfo().GetKeyInfo() {
+		if err = fips.CheckTypeURL(ki.GetTypeUrl()); err != nil {
+			return "", nil, fmt.Errorf("import keyset: %w", err)
+		}
+	}
+
+	if err = l.checkProfileKeyset(insecurecleartextkeyset.KeysetMaterial(kh)); err != nil {
+		return "", nil, fmt.Errorf("import keyset: %w", err)
+	}
+
+	// the keyset is stored as is, it is owned by kh so it is not wiped.
+	keyID, err := l.writeImportedKey(insecurecleartextkeyset.KeysetMaterial(kh), opts...)
+	if err != nil {
+		return "", nil, fmt.Errorf("import keyset: %w", err)
+	}
+
+	return keyID, kh, nil
+}
+
+// ExportKeyset writes the keyset keyID to w, in the binary (keyset.NewBinaryWriter) or JSON (keyset.NewJSONWriter)
+// keyset format of all the Tink implementations, with its Tink key IDs and primary key. If keysetAEAD is not nil,
+// the keyset is written as an encrypted keyset, encrypted with keysetAEAD, otherwise the private key material is
+// written in cleartext: w must then only be shared with trusted parties.
+func (l *LocalKMS) ExportKeyset(keyID string, w keyset.Writer, keysetAEAD tink.AEAD) error {
+	start := time.Now()
+	err := l.exportKeyset(keyID, w, keysetAEAD)
+
+	audit.Log(l.auditLogger, kmsapi.OperationExportPrivate, keyID, nil, start, err)
+
+	return err
+}
+
+func (l *LocalKMS) exportKeyset(keyID string, w keyset.Writer, keysetAEAD tink.AEAD) error {
+	if w == nil {
+		return errors.New("export keyset: writer is nil")
+	}
+
+	kh, err := l.getKeySet(keyID)
+	if err != nil {
+		return fmt.Errorf("export keyset: %w", err)
+	}
+
+	if keysetAEAD == nil {
+		err = insecurecleartextkeyset.Write(kh, w)
+	} else {
+		err = kh.Write(w, keysetAEAD)
+	}
+
+	if err != nil {
+		return fmt.Errorf("export keyset: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+)
+
+// tinkJSONKeyset is an AES128-GCM keyset in the JSON keyset format written by Tink Java.
+const tinkJSONKeyset = `{
+  "primaryKeyId": 1931667682,
+  "key": [{
+    "keyData": {
+      "typeUrl": "type.googleapis.com/google.crypto.tink.AesGcmKey",
+      "value": "GhAAAQIDBAUGBwgJCgsMDQ4P",
+      "keyMaterialType": "SYMMETRIC"
+    },
+    "status": "ENABLED",
+    "keyId": 1931667682,
+    "outputPrefixType": "TINK"
+  }]
+}`
+
+func TestLocalKMS_ImportExportKeyset(t *testing.T) {
+	kmsService, err := New(testMasterKeyURI, &mockProvider{storage: newInMemoryKMSStore(), secretLock: &noop.NoLock{}})
+	require.NoError(t, err)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	msg := []byte("message")
+
+	t.Run("import Tink JSON keyset", func(t *testing.T) {
+		keyID, kh, err := kmsService.ImportKeyset(keyset.NewJSONReader(strings.NewReader(tinkJSONKeyset)), nil)
+		require.NoError(t, err)
+		require.NotEmpty(t, keyID)
+		require.EqualValues(t, 1931667682, kh.(*keyset.Handle).KeysetInfo().PrimaryKeyId)
+
+		stored, err := kmsService.Get(keyID)
+		require.NoError(t, err)
+
+		tinkKH, err := insecurecleartextkeyset.Read(keyset.NewJSONReader(strings.NewReader(tinkJSONKeyset)))
+		require.NoError(t, err)
+
+		a, err := aead.New(tinkKH)
+		require.NoError(t, err)
+
+		ct, err := a.Encrypt(msg, nil)
+		require.NoError(t, err)
+
+		storedAEAD, err := aead.New(stored.(*keyset.Handle))
+		require.NoError(t, err)
+
+		pt, err := storedAEAD.Decrypt(ct, nil)
+		require.NoError(t, err)
+		require.Equal(t, msg, pt)
+	})
+
+	t.Run("export and import keeps key IDs and primary key", func(t *testing.T) {
+		manager := keyset.NewManager()
+
+		first, err := manager.Add(signature.ED25519KeyTemplate())
+		require.NoError(t, err)
+
+		_, err = manager.Add(signature.ED25519KeyTemplate())
+		require.NoError(t, err)
+
+		require.NoError(t, manager.SetPrimary(first))
+
+		original, err := manager.Handle()
+		require.NoError(t, err)
+
+		buf := new(bytes.Buffer)
+		require.NoError(t, insecurecleartextkeyset.Write(original, keyset.NewBinaryWriter(buf)))
+
+		keyID, _, err := kmsService.ImportKeyset(keyset.NewBinaryReader(buf), nil, kmsapi.WithKeyID("tink-keyset"))
+		require.NoError(t, err)
+		require.Equal(t, "tink-keyset", keyID)
+
+		kekHandle, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+		require.NoError(t, err)
+
+		kek, err := aead.New(kekHandle)
+		require.NoError(t, err)
+
+		buf.Reset()
+		require.NoError(t, kmsService.ExportKeyset(keyID, keyset.NewJSONWriter(buf), kek))
+		require.NotContains(t, buf.String(), "keyData")
+
+		exported, err := keyset.Read(keyset.NewJSONReader(bytes.NewReader(buf.Bytes())), kek)
+		require.NoError(t, err)
+		require.Equal(t, original.KeysetInfo().String(), exported.KeysetInfo().String())
+		require.EqualValues(t, first, exported.KeysetInfo().PrimaryKeyId)
+
+		sig, err := cr.Sign(msg, exported)
+		require.NoError(t, err)
+
+		pubKH, err := original.Public()
+		require.NoError(t, err)
+		require.NoError(t, cr.Verify(sig, msg, pubKH))
+
+		reimportedID, kh, err := kmsService.ImportKeyset(keyset.NewJSONReader(bytes.NewReader(buf.Bytes())), kek)
+		require.NoError(t, err)
+		require.NotEqual(t, keyID, reimportedID)
+		require.Equal(t, original.KeysetInfo().String(), kh.(*keyset.Handle).KeysetInfo().String())
+
+		buf.Reset()
+		require.NoError(t, kmsService.ExportKeyset(keyID, keyset.NewBinaryWriter(buf), nil))
+
+		cleartext, err := insecurecleartextkeyset.Read(keyset.NewBinaryReader(buf))
+		require.NoError(t, err)
+		require.Equal(t, original.KeysetInfo().String(), cleartext.KeysetInfo().String())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, _, err := kmsService.ImportKeyset(keyset.NewJSONReader(strings.NewReader("{")), nil)
+		require.ErrorContains(t, err, "import keyset")
+
+		kekHandle, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+		require.NoError(t, err)
+
+		kek, err := aead.New(kekHandle)
+		require.NoError(t, err)
+
+		_, _, err = kmsService.ImportKeyset(keyset.NewJSONReader(strings.NewReader(tinkJSONKeyset)), kek)
+		require.ErrorContains(t, err, "import keyset")
+
+		_, _, err = kmsService.ImportKeyset(keyset.NewJSONReader(strings.NewReader(tinkJSONKeyset)), nil,
+			kmsapi.WithKeyID("tink-keyset"))
+		require.ErrorContains(t, err, "import keyset")
+
+		err = kmsService.ExportKeyset("unknown", keyset.NewBinaryWriter(new(bytes.Buffer)), nil)
+		require.ErrorContains(t, err, "export keyset")
+
+		err = kmsService.ExportKeyset("tink-keyset", nil, nil)
+		require.EqualError(t, err, "export keyset: writer is nil")
+	})
+}
+
+// FuzzLocalKMS_ImportKeyset checks that ImportKeyset rejects malformed binary and JSON keysets without panicking and
+// that the imported keysets can be read back from the store.
+func FuzzLocalKMS_ImportKeyset(f *testing.F) {
+	kmsService, err := New(testMasterKeyURI, &mockProvider{storage: newInMemoryKMSStore(), secretLock: &noop.NoLock{}})
+	require.NoError(f, err)
+
+	for _, kt := range []kmsapi.KeyType{kmsapi.AES256GCMType, kmsapi.ED25519Type, kmsapi.NISTP256ECDHKWType} {
+		keyID, _, e := kmsService.Create(kt)
+		require.NoError(f, e)
+
+		buf := new(bytes.Buffer)
+		require.NoError(f, kmsService.ExportKeyset(keyID, keyset.NewBinaryWriter(buf), nil))
+		f.Add(false, buf.Bytes())
+
+		buf = new(bytes.Buffer)
+		require.NoError(f, kmsService.ExportKeyset(keyID, keyset.NewJSONWriter(buf), nil))
+		f.Add(true, buf.Bytes())
+	}
+
+	f.Add(true, []byte(tinkJSONKeyset))
+	f.Add(true, []byte(`{"primaryKeyId":1,"key":[]}`))
+	f.Add(false, []byte{})
+
+	f.Fuzz(func(t *testing.T, jsonFormat bool, data []byte) {
+		var r keyset.Reader = keyset.NewBinaryReader(bytes.NewReader(data))
+		if jsonFormat {
+			r = keyset.NewJSONReader(bytes.NewReader(data))
+		}
+
+		keyID, kh, e := kmsService.ImportKeyset(r, nil)
+		if e != nil {
+			return
+		}
+
+		stored, e := kmsService.getKeySet(keyID)
+		require.NoError(t, e)
+		require.Equal(t, kh.(*keyset.Handle).KeysetInfo().String(), stored.KeysetInfo().String())
+	})
+}
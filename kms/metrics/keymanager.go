@@ -0,0 +1,107 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"time"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+const (
+	// OperationGet is the operation label of KeyManager.Get.
+	OperationGet = kmsapi.Operation("get")
+	// OperationPubKeyBytesToHandle is the operation label of KeyManager.PubKeyBytesToHandle.
+	OperationPubKeyBytesToHandle = kmsapi.Operation("pubKeyBytesToHandle")
+)
+
+// KeyManager is a kms.KeyManager decorator reporting the operations of the wrapped KeyManager to a Recorder. The
+// operations are labeled with the kms.Operation values advertised in kms.Capabilities, CreateAndExportPubKeyBytes is
+// reported as OperationCreate.
+type KeyManager struct {
+	km kmsapi.KeyManager
+	r  Recorder
+}
+
+// NewKeyManager creates a KeyManager reporting the operations of km to r.
+func NewKeyManager(km kmsapi.KeyManager, r Recorder) *KeyManager {
+	return &KeyManager{km: km, r: r}
+}
+
+// Create a new key of type kt.
+func (k *KeyManager) Create(kt kmsapi.KeyType, opts ...kmsapi.KeyOpts) (string, interface{}, error) {
+	start := time.Now()
+	kid, kh, err := k.km.Create(kt, opts...)
+
+	observe(k.r, kmsapi.OperationCreate, kt, start, err)
+
+	return kid, kh, err
+}
+
+// Get the key handle of keyID, the key type is not known.
+func (k *KeyManager) Get(keyID string) (interface{}, error) {
+	start := time.Now()
+	kh, err := k.km.Get(keyID)
+
+	observe(k.r, OperationGet, "", start, err)
+
+	return kh, err
+}
+
+// Rotate the key keyID to a new key of type kt.
+func (k *KeyManager) Rotate(kt kmsapi.KeyType, keyID string, opts ...kmsapi.KeyOpts) (string, interface{}, error) {
+	start := time.Now()
+	kid, kh, err := k.km.Rotate(kt, keyID, opts...)
+
+	observe(k.r, kmsapi.OperationRotate, kt, start, err)
+
+	return kid, kh, err
+}
+
+// ExportPubKeyBytes exports the public key of keyID.
+func (k *KeyManager) ExportPubKeyBytes(keyID string) ([]byte, kmsapi.KeyType, error) {
+	start := time.Now()
+	pubKey, kt, err := k.km.ExportPubKeyBytes(keyID)
+
+	observe(k.r, kmsapi.OperationExportPublicKey, kt, start, err)
+
+	return pubKey, kt, err
+}
+
+// CreateAndExportPubKeyBytes creates a new key of type kt and exports its public key.
+func (k *KeyManager) CreateAndExportPubKeyBytes(kt kmsapi.KeyType, opts ...kmsapi.KeyOpts) (string, []byte, error) {
+	start := time.Now()
+	kid, pubKey, err := k.km.CreateAndExportPubKeyBytes(kt, opts...)
+
+	observe(k.r, kmsapi.OperationCreate, kt, start, err)
+
+	return kid, pubKey, err
+}
+
+// PubKeyBytesToHandle converts the public key pubKey of type kt to a key handle.
+func (k *KeyManager) PubKeyBytesToHandle(pubKey []byte, kt kmsapi.KeyType,
+	opts ...kmsapi.KeyOpts) (interface{}, error) {
+	start := time.Now()
+	kh, err := k.km.PubKeyBytesToHandle(pubKey, kt, opts...)
+
+	observe(k.r, OperationPubKeyBytesToHandle, kt, start, err)
+
+	return kh, err
+}
+
+// ImportPrivateKey imports the private key privKey of type kt.
+func (k *KeyManager) ImportPrivateKey(privKey interface{}, kt kmsapi.KeyType,
+	opts ...kmsapi.PrivateKeyOpts) (string, interface{}, error) {
+	start := time.Now()
+	kid, kh, err := k.km.ImportPrivateKey(privKey, kt, opts...)
+
+	observe(k.r, kmsapi.OperationImportPrivate, kt, start, err)
+
+	return kid, kh, err
+}
+
+var _ kmsapi.KeyManager = &KeyManager{}
@@ -0,0 +1,119 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package metrics provides an opt-in metrics decorator for kms.KeyManager implementations (see crypto/metrics for
+// crypto.Crypto implementations). The decorators report the operations to a Recorder: the default Collector is a
+// prometheus.Collector of operation counters, error counters and latency histograms labeled by operation and key type,
+// registered on the application prometheus.Registerer (serve its registry with promhttp, eg: on the '/metrics' path).
+// Applications using other metrics libraries can implement Recorder instead.
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+// Recorder records the operations executed by the decorators. keyType is the key type of the operation key, empty
+// when it is not known.
+type Recorder interface {
+	Observe(op kmsapi.Operation, keyType string, latency time.Duration, err error)
+}
+
+// DefBuckets are the default latency histogram buckets, in seconds.
+//
+//nolint:gochecknoglobals
+var DefBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5}
+
+//nolint:gochecknoglobals
+var labelNames = []string{"operation", "key_type"}
+
+var (
+	_ Recorder             = (*Collector)(nil)
+	_ prometheus.Collector = (*Collector)(nil)
+)
+
+// Collector is the default Recorder, a prometheus.Collector of the metrics:
+//
+//	<namespace>_operations_total{operation, key_type}          counter
+//	<namespace>_operation_errors_total{operation, key_type}    counter
+//	<namespace>_operation_duration_seconds{operation, key_type} histogram
+type Collector struct {
+	operations *prometheus.CounterVec
+	errors     *prometheus.CounterVec
+	duration   *prometheus.HistogramVec
+}
+
+// NewCollector creates a Collector naming its metrics with namespace (eg: 'kms') and using the latency histogram
+// buckets, in seconds, and registers it on reg. DefBuckets are used if buckets is empty.
+func NewCollector(reg prometheus.Registerer, namespace string, buckets ...float64) (*Collector, error) {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+
+	b := append([]float64{}, buckets...)
+	sort.Float64s(b)
+
+	c := &Collector{
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "operations_total",
+			Help:      "Number of operations.",
+		}, labelNames),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "operation_errors_total",
+			Help:      "Number of failed operations.",
+		}, labelNames),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "operation_duration_seconds",
+			Help:      "Latency of the operations.",
+			Buckets:   b,
+		}, labelNames),
+	}
+
+	if err := reg.Register(c); err != nil {
+		return nil, fmt.Errorf("register metrics collector: %w", err)
+	}
+
+	return c, nil
+}
+
+// Observe records an operation.
+func (c *Collector) Observe(op kmsapi.Operation, keyType string, latency time.Duration, err error) {
+	c.operations.WithLabelValues(string(op), keyType).Inc()
+
+	errs := c.errors.WithLabelValues(string(op), keyType)
+	if err != nil {
+		errs.Inc()
+	}
+
+	c.duration.WithLabelValues(string(op), keyType).Observe(latency.Seconds())
+}
+
+// Describe sends the descriptors of the metrics to ch.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.operations.Describe(ch)
+	c.errors.Describe(ch)
+	c.duration.Describe(ch)
+}
+
+// Collect sends the metrics to ch.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.operations.Collect(ch)
+	c.errors.Collect(ch)
+	c.duration.Collect(ch)
+}
+
+// observe reports the operation op started at start and ending with err to r.
+func observe(r Recorder, op kmsapi.Operation, kt kmsapi.KeyType, start time.Time, err error) {
+	r.Observe(op, string(kt), time.Since(start), err)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/kms/metrics"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func TestCollector(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+
+	c, err := metrics.NewCollector(reg, "kms", 0.01, 0.001)
+	require.NoError(t, err)
+
+	c.Observe(kmsapi.OperationSign, "ED25519", 500*time.Microsecond, nil)
+	c.Observe(kmsapi.OperationSign, "ED25519", 5*time.Millisecond, errors.New("sign failed"))
+	c.Observe(kmsapi.OperationCreate, `quote"d`, time.Second, nil)
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`# HELP kms_operations_total Number of operations.
+# TYPE kms_operations_total counter
+kms_operations_total{key_type="quote\"d",operation="create"} 1
+kms_operations_total{key_type="ED25519",operation="sign"} 2
+# HELP kms_operation_errors_total Number of failed operations.
+# TYPE kms_operation_errors_total counter
+kms_operation_errors_total{key_type="quote\"d",operation="create"} 0
+kms_operation_errors_total{key_type="ED25519",operation="sign"} 1
+# HELP kms_operation_duration_seconds Latency of the operations.
+# TYPE kms_operation_duration_seconds histogram
+kms_operation_duration_seconds_bucket{key_type="quote\"d",operation="create",le="0.001"} 0
+kms_operation_duration_seconds_bucket{key_type="quote\"d",operation="create",le="0.01"} 0
+kms_operation_duration_seconds_bucket{key_type="quote\"d",operation="create",le="+Inf"} 1
+kms_operation_duration_seconds_sum{key_type="quote\"d",operation="create"} 1
+kms_operation_duration_seconds_count{key_type="quote\"d",operation="create"} 1
+kms_operation_duration_seconds_bucket{key_type="ED25519",operation="sign",le="0.001"} 1
+kms_operation_duration_seconds_bucket{key_type="ED25519",operation="sign",le="0.01"} 2
+kms_operation_duration_seconds_bucket{key_type="ED25519",operation="sign",le="+Inf"} 2
+kms_operation_duration_seconds_sum{key_type="ED25519",operation="sign"} 0.0055
+kms_operation_duration_seconds_count{key_type="ED25519",operation="sign"} 2
+`)))
+
+	// the metrics of a namespace are registered once.
+	_, err = metrics.NewCollector(reg, "kms")
+	require.ErrorContains(t, err, "register metrics collector: ")
+}
+
+func TestKeyManager(t *testing.T) {
+	localKMS := mockkms.NewForTest(t)
+
+	reg := prometheus.NewPedanticRegistry()
+
+	c, err := metrics.NewCollector(reg, "kms")
+	require.NoError(t, err)
+
+	km := metrics.NewKeyManager(localKMS, c)
+
+	kid, _, err := km.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	_, err = km.Get(kid)
+	require.NoError(t, err)
+
+	_, err = km.Get("unknown")
+	require.Error(t, err)
+
+	_, pubKey, err := km.CreateAndExportPubKeyBytes(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	_, err = km.PubKeyBytesToHandle(pubKey, kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	_, _, err = km.ExportPubKeyBytes(kid)
+	require.NoError(t, err)
+
+	_, _, err = km.Rotate(kmsapi.ED25519Type, kid)
+	require.NoError(t, err)
+
+	_, _, err = km.ImportPrivateKey("not a key", kmsapi.ED25519Type)
+	require.Error(t, err)
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`# HELP kms_operations_total Number of operations.
+# TYPE kms_operations_total counter
+kms_operations_total{key_type="ED25519",operation="create"} 2
+kms_operations_total{key_type="ED25519",operation="exportPublicKey"} 1
+kms_operations_total{key_type="ED25519",operation="importPrivateKey"} 1
+kms_operations_total{key_type="ED25519",operation="pubKeyBytesToHandle"} 1
+kms_operations_total{key_type="ED25519",operation="rotate"} 1
+kms_operations_total{key_type="",operation="get"} 2
+# HELP kms_operation_errors_total Number of failed operations.
+# TYPE kms_operation_errors_total counter
+kms_operation_errors_total{key_type="ED25519",operation="create"} 0
+kms_operation_errors_total{key_type="ED25519",operation="exportPublicKey"} 0
+kms_operation_errors_total{key_type="ED25519",operation="importPrivateKey"} 1
+kms_operation_errors_total{key_type="ED25519",operation="pubKeyBytesToHandle"} 0
+kms_operation_errors_total{key_type="ED25519",operation="rotate"} 0
+kms_operation_errors_total{key_type="",operation="get"} 1
+`), "kms_operations_total", "kms_operation_errors_total"))
+
+	require.Equal(t, 6, testutil.CollectAndCount(c, "kms_operation_duration_seconds"))
+}
@@ -0,0 +1,38 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+const bearerPrefix = "Bearer "
+
+// BearerTokenAuth returns a Middleware rejecting requests without a valid "Authorization: Bearer <token>" header.
+// verify is called with the request and the token, it must return an error if the token is not accepted for the
+// request. Clients can set the header with webkms.WithHeaders().
+func BearerTokenAuth(verify func(r *http.Request, token string) error) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth := r.Header.Get("Authorization")
+			if !strings.HasPrefix(auth, bearerPrefix) {
+				writeError(w, http.StatusUnauthorized, "missing bearer token")
+
+				return
+			}
+
+			if err := verify(r, strings.TrimPrefix(auth, bearerPrefix)); err != nil {
+				writeError(w, http.StatusForbidden, err.Error())
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
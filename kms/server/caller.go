@@ -0,0 +1,156 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+// Authorizer checks the caller of a request can execute the server operation op (eg: "sign") with the key keyID.
+// keyID is empty for keystore level operations (eg: "create"). The caller is read from ctx with
+// kmsapi.CallerInfoFromContext. Unauthorized requests get a 403 status code.
+type Authorizer interface {
+	Authorize(ctx context.Context, op, keyID string) error
+}
+
+// RateLimiter limits the operations executed per caller. The caller is read from ctx with
+// kmsapi.CallerInfoFromContext. Rejected requests get a 429 status code.
+type RateLimiter interface {
+	Allow(ctx context.Context, op string) error
+}
+
+// AuditEvent describes a server operation request.
+type AuditEvent struct {
+	Op     string
+	KeyID  string
+	Caller *kmsapi.CallerInfo
+	// Status is the HTTP status code of the response.
+	Status int
+	// Latency is the time taken to serve the request.
+	Latency time.Duration
+}
+
+// AuditSink records the operations requested to the server, including the rejected ones.
+type AuditSink interface {
+	Audit(ctx context.Context, event *AuditEvent)
+}
+
+//nolint:gochecknoglobals
+var auditOperations = map[string]kmsapi.Operation{
+	"create":      kmsapi.OperationCreate,
+	"import":      kmsapi.OperationImportPrivate,
+	"export":      kmsapi.OperationExportPublicKey,
+	"wrap":        kmsapi.OperationWrapKey,
+	"unwrap":      kmsapi.OperationUnwrapKey,
+	"sign":        kmsapi.OperationSign,
+	"verify":      kmsapi.OperationVerify,
+	"encrypt":     kmsapi.OperationEncrypt,
+	"decrypt":     kmsapi.OperationDecrypt,
+	"computemac":  kmsapi.OperationComputeMAC,
+	"verifymac":   kmsapi.OperationVerifyMAC,
+	"signmulti":   kmsapi.OperationSignMulti,
+	"verifymulti": kmsapi.OperationVerifyMulti,
+	"deriveproof": kmsapi.OperationDeriveProof,
+	"verifyproof": kmsapi.OperationVerifyProof,
+}
+
+// AuditLoggerSink returns an AuditSink forwarding the events to the kms.AuditLogger l (eg: an audit.JSONLogger).
+// Requests with a status code below 400 are recorded as successful, server operations without an Operation
+// counterpart (eg: "mpc/sign") are recorded with their server operation name.
+func AuditLoggerSink(l kmsapi.AuditLogger) AuditSink {
+	return &auditLoggerSink{l: l}
+}
+
+type auditLoggerSink struct {
+	l kmsapi.AuditLogger
+}
+
+func (a *auditLoggerSink) Audit(_ context.Context, event *AuditEvent) {
+	op, ok := auditOperations[event.Op]
+	if !ok {
+		op = kmsapi.Operation(event.Op)
+	}
+
+	a.l.OnOperation(op, event.KeyID, event.Status < http.StatusBadRequest, event.Latency, event.Caller)
+}
+
+// CallerInfo returns a Middleware attaching the CallerInfo returned by extract to the context of the requests (read
+// back with kmsapi.CallerInfoFromContext). Requests for which extract fails are rejected with a 401 status code.
+// Authentication middlewares (eg: BearerTokenAuth) must be set before it.
+func CallerInfo(extract func(r *http.Request) (*kmsapi.CallerInfo, error)) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			info, err := extract(r)
+			if err != nil {
+				writeError(w, http.StatusUnauthorized, err.Error())
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(kmsapi.WithCallerInfo(r.Context(), info)))
+		})
+	}
+}
+
+// guard applies the rate limiter, the authorizer and the audit sink to the op requests.
+func (s *Server) guard(op string, next http.HandlerFunc) http.HandlerFunc {
+	if s.opts.authorizer == nil && s.opts.rateLimiter == nil && s.opts.auditSink == nil {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		keyID := r.PathValue("keyID")
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+
+		if s.opts.auditSink != nil {
+			defer func() {
+				s.opts.auditSink.Audit(ctx, &AuditEvent{
+					Op:      op,
+					KeyID:   keyID,
+					Caller:  kmsapi.CallerInfoFromContext(ctx),
+					Status:  rec.status,
+					Latency: time.Since(start),
+				})
+			}()
+		}
+
+		if s.opts.rateLimiter != nil {
+			if err := s.opts.rateLimiter.Allow(ctx, op); err != nil {
+				writeError(rec, http.StatusTooManyRequests, err.Error())
+
+				return
+			}
+		}
+
+		if s.opts.authorizer != nil {
+			if err := s.opts.authorizer.Authorize(ctx, op, keyID); err != nil {
+				writeError(rec, http.StatusForbidden, err.Error())
+
+				return
+			}
+		}
+
+		next(rec, r)
+	}
+}
+
+// statusRecorder records the status code of a response for auditing.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,461 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/http"
+
+	"github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+func (s *Server) createKeystore(w http.ResponseWriter, r *http.Request) {
+	if !s.readRequest(w, r, &createKeystoreReq{}) {
+		return
+	}
+
+	writeResponse(w, http.StatusCreated, &createKeyStoreResp{KeyStoreURL: s.keystoreURL(r)})
+}
+
+func (s *Server) createKey(w http.ResponseWriter, r *http.Request) {
+	req := &createKeyReq{}
+	if !s.readRequest(w, r, req) {
+		return
+	}
+
+	var keyOpts []kmsapi.KeyOpts
+
+	if len(req.Attrs) > 0 {
+		keyOpts = append(keyOpts, kmsapi.WithAttrs(req.Attrs))
+	}
+
+	keyID, _, err := s.km.Create(req.KeyType, keyOpts...)
+	if err != nil {
+		writeKMSError(w, "create key", err)
+
+		return
+	}
+
+	// symmetric keys don't have a public key to export.
+	pubKey, _, err := s.km.ExportPubKeyBytes(keyID)
+	if err != nil {
+		pubKey = nil
+	}
+
+	keyURL := s.keyURL(r, keyID)
+
+	w.Header().Set("Location", keyURL)
+	writeResponse(w, http.StatusCreated, &createKeyResp{KeyURL: keyURL, PublicKey: pubKey})
+}
+
+func (s *Server) importKey(w http.ResponseWriter, r *http.Request) {
+	req := &importKeyReq{}
+	if !s.readRequest(w, r, req) {
+		return
+	}
+
+	privKey, err := x509.ParsePKCS8PrivateKey(req.Key)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("import key: parse PKCS#8 key: %s", err))
+
+		return
+	}
+
+	var keyOpts []kmsapi.PrivateKeyOpts
+
+	if req.KeyID != "" {
+		keyOpts = append(keyOpts, kmsapi.WithKeyID(req.KeyID))
+	}
+
+	keyID, _, err := s.km.ImportPrivateKey(privKey, req.KeyType, keyOpts...)
+	if err != nil {
+		writeKMSError(w, "import key", err)
+
+		return
+	}
+
+	writeResponse(w, http.StatusCreated, &importKeyResp{KeyURL: s.keyURL(r, keyID)})
+}
+
+func (s *Server) exportKey(w http.ResponseWriter, r *http.Request) {
+	pubKey, kt, err := s.km.ExportPubKeyBytes(r.PathValue("keyID"))
+	if err != nil {
+		writeKMSError(w, "export key", err)
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, &exportKeyResp{PublicKey: pubKey, KeyType: string(kt)})
+}
+
+func (s *Server) sign(w http.ResponseWriter, r *http.Request) {
+	req := &signReq{}
+	if !s.readRequest(w, r, req) {
+		return
+	}
+
+	kh := s.getKey(w, r)
+	if kh == nil {
+		return
+	}
+
+	sig, err := s.crypto.Sign(req.Message, kh)
+	if err != nil {
+		writeKMSError(w, "sign", err)
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, &signResp{Signature: sig})
+}
+
+func (s *Server) verify(w http.ResponseWriter, r *http.Request) {
+	req := &verifyReq{}
+	if !s.readRequest(w, r, req) {
+		return
+	}
+
+	kh := s.getPublicKey(w, r)
+	if kh == nil {
+		return
+	}
+
+	if err := s.crypto.Verify(req.Signature, req.Message, kh); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("verify: %s", err))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, nil)
+}
+
+func (s *Server) encrypt(w http.ResponseWriter, r *http.Request) {
+	req := &encryptReq{}
+	if !s.readRequest(w, r, req) {
+		return
+	}
+
+	kh := s.getKey(w, r)
+	if kh == nil {
+		return
+	}
+
+	ct, nonce, err := s.crypto.Encrypt(req.Message, req.AssociatedData, kh)
+	if err != nil {
+		writeKMSError(w, "encrypt", err)
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, &encryptResp{Ciphertext: ct, Nonce: nonce})
+}
+
+func (s *Server) decrypt(w http.ResponseWriter, r *http.Request) {
+	req := &decryptReq{}
+	if !s.readRequest(w, r, req) {
+		return
+	}
+
+	kh := s.getKey(w, r)
+	if kh == nil {
+		return
+	}
+
+	pt, err := s.crypto.Decrypt(req.Ciphertext, req.AssociatedData, req.Nonce, kh)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("decrypt: %s", err))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, &decryptResp{Plaintext: pt})
+}
+
+func (s *Server) computeMAC(w http.ResponseWriter, r *http.Request) {
+	req := &computeMACReq{}
+	if !s.readRequest(w, r, req) {
+		return
+	}
+
+	kh := s.getKey(w, r)
+	if kh == nil {
+		return
+	}
+
+	mac, err := s.crypto.ComputeMAC(req.Data, kh)
+	if err != nil {
+		writeKMSError(w, "compute MAC", err)
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, &computeMACResp{MAC: mac})
+}
+
+func (s *Server) verifyMAC(w http.ResponseWriter, r *http.Request) {
+	req := &verifyMACReq{}
+	if !s.readRequest(w, r, req) {
+		return
+	}
+
+	kh := s.getKey(w, r)
+	if kh == nil {
+		return
+	}
+
+	if err := s.crypto.VerifyMAC(req.MAC, req.Data, kh); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("verify MAC: %s", err))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, nil)
+}
+
+func (s *Server) signMulti(w http.ResponseWriter, r *http.Request) {
+	req := &signMultiReq{}
+	if !s.readRequest(w, r, req) {
+		return
+	}
+
+	kh := s.getKey(w, r)
+	if kh == nil {
+		return
+	}
+
+	sig, err := s.crypto.SignMulti(req.Messages, kh)
+	if err != nil {
+		writeKMSError(w, "sign multi", err)
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, &signResp{Signature: sig})
+}
+
+func (s *Server) verifyMulti(w http.ResponseWriter, r *http.Request) {
+	req := &verifyMultiReq{}
+	if !s.readRequest(w, r, req) {
+		return
+	}
+
+	kh := s.getPublicKey(w, r)
+	if kh == nil {
+		return
+	}
+
+	if err := s.crypto.VerifyMulti(req.Messages, req.Signature, kh); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("verify multi: %s", err))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, nil)
+}
+
+func (s *Server) deriveProof(w http.ResponseWriter, r *http.Request) {
+	req := &deriveProofReq{}
+	if !s.readRequest(w, r, req) {
+		return
+	}
+
+	kh := s.getPublicKey(w, r)
+	if kh == nil {
+		return
+	}
+
+	proof, err := s.crypto.DeriveProof(req.Messages, req.Signature, req.Nonce, req.RevealedIndexes, kh)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("derive proof: %s", err))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, &deriveProofResp{Proof: proof})
+}
+
+func (s *Server) verifyProof(w http.ResponseWriter, r *http.Request) {
+	req := &verifyProofReq{}
+	if !s.readRequest(w, r, req) {
+		return
+	}
+
+	kh := s.getPublicKey(w, r)
+	if kh == nil {
+		return
+	}
+
+	if err := s.crypto.VerifyProof(req.Messages, req.Proof, req.Nonce, kh); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("verify proof: %s", err))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, nil)
+}
+
+// wrap serves WrapKey requests (keystore level for anoncrypt, key level for authcrypt with the key as sender) and
+// CryptoBox.Easy requests (key level, identified by their payload).
+func (s *Server) wrap(w http.ResponseWriter, r *http.Request) {
+	req := &wrapReq{}
+	if !s.readRequest(w, r, req) {
+		return
+	}
+
+	if r.PathValue("keyID") != "" && req.Payload != nil {
+		s.easy(w, r, req)
+
+		return
+	}
+
+	if req.RecipientPubKey == nil {
+		writeError(w, http.StatusBadRequest, "wrap: missing recipient public key")
+
+		return
+	}
+
+	var wOpts []crypto.WrapKeyOpts
+
+	if r.PathValue("keyID") != "" {
+		senderKH := s.getKey(w, r)
+		if senderKH == nil {
+			return
+		}
+
+		wOpts = append(wOpts, crypto.WithSender(senderKH))
+	}
+
+	if len(req.Tag) > 0 {
+		wOpts = append(wOpts, crypto.WithTag(req.Tag))
+	}
+
+	wk, err := s.crypto.WrapKey(req.CEK, req.APU, req.APV, req.RecipientPubKey, wOpts...)
+	if err != nil {
+		writeKMSError(w, "wrap", err)
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, &wrapKeyResp{RecipientWrappedKey: *wk})
+}
+
+func (s *Server) easy(w http.ResponseWriter, r *http.Request, req *wrapReq) {
+	if s.opts.cryptoBox == nil {
+		writeError(w, http.StatusNotImplemented, "easy: crypto box is not supported")
+
+		return
+	}
+
+	ct, err := s.opts.cryptoBox.Easy(req.Payload, req.Nonce, req.TheirPub, r.PathValue("keyID"))
+	if err != nil {
+		writeKMSError(w, "easy", err)
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, &easyResp{Ciphertext: ct})
+}
+
+// unwrap serves UnwrapKey requests and CryptoBox EasyOpen and SealOpen requests (identified by the lack of a wrapped
+// key).
+func (s *Server) unwrap(w http.ResponseWriter, r *http.Request) {
+	req := &unwrapReq{}
+	if !s.readRequest(w, r, req) {
+		return
+	}
+
+	if req.WrappedKey == nil {
+		s.open(w, req)
+
+		return
+	}
+
+	kh := s.getKey(w, r)
+	if kh == nil {
+		return
+	}
+
+	var wOpts []crypto.WrapKeyOpts
+
+	if req.SenderPubKey != nil {
+		wOpts = append(wOpts, crypto.WithSender(req.SenderPubKey))
+	}
+
+	if len(req.Tag) > 0 {
+		wOpts = append(wOpts, crypto.WithTag(req.Tag))
+	}
+
+	key, err := s.crypto.UnwrapKey(req.WrappedKey, kh, wOpts...)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unwrap: %s", err))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, &unwrapKeyResp{Key: key})
+}
+
+func (s *Server) open(w http.ResponseWriter, req *unwrapReq) {
+	if s.opts.cryptoBox == nil {
+		writeError(w, http.StatusNotImplemented, "open: crypto box is not supported")
+
+		return
+	}
+
+	var (
+		pt  []byte
+		err error
+		op  = "seal open"
+	)
+
+	if req.Nonce != nil {
+		op = "easy open"
+		pt, err = s.opts.cryptoBox.EasyOpen(req.Ciphertext, req.Nonce, req.TheirPub, req.MyPub)
+	} else {
+		pt, err = s.opts.cryptoBox.SealOpen(req.Ciphertext, req.MyPub)
+	}
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("%s: %s", op, err))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, &openResp{Plaintext: pt})
+}
+
+// capabilities returns the merged capabilities of the KeyManager and Crypto, for those implementing
+// kms.CapabilitiesProvider.
+func (s *Server) capabilities(w http.ResponseWriter, _ *http.Request) {
+	var caps []*kmsapi.Capabilities
+
+	for _, c := range []interface{}{s.km, s.crypto} {
+		p, ok := c.(kmsapi.CapabilitiesProvider)
+		if !ok {
+			continue
+		}
+
+		kc, err := p.Capabilities()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("capabilities: %s", err))
+
+			return
+		}
+
+		caps = append(caps, kc)
+	}
+
+	if len(caps) == 0 {
+		writeError(w, http.StatusNotImplemented, "capabilities: not supported")
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, kmsapi.MergeCapabilities(caps...))
+}
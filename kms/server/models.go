@@ -0,0 +1,171 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server
+
+import (
+	"github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+// The request and response models below are the wire format of the REST API consumed by the webkms clients
+// (kms/webkms and crypto/webkms). They must be kept in sync with those packages.
+
+type errMessage struct {
+	Error string `json:"errMessage"`
+}
+
+type createKeystoreReq struct {
+	Controller string `json:"controller,omitempty"`
+}
+
+type createKeyStoreResp struct {
+	KeyStoreURL string `json:"key_store_url"`
+	Capability  []byte `json:"capability,omitempty"`
+}
+
+type createKeyReq struct {
+	KeyType kms.KeyType `json:"key_type"`
+	Attrs   []string    `json:"attrs,omitempty"`
+}
+
+type createKeyResp struct {
+	KeyURL    string `json:"key_url"`
+	PublicKey []byte `json:"public_key"`
+}
+
+type exportKeyResp struct {
+	PublicKey []byte `json:"public_key"`
+	KeyType   string `json:"key_type"`
+}
+
+type importKeyReq struct {
+	Key     []byte      `json:"key"`
+	KeyType kms.KeyType `json:"key_type"`
+	KeyID   string      `json:"key_id,omitempty"`
+}
+
+type importKeyResp struct {
+	KeyURL string `json:"key_url"`
+}
+
+type signReq struct {
+	Message []byte `json:"message"`
+}
+
+type signResp struct {
+	Signature []byte `json:"signature"`
+}
+
+type verifyReq struct {
+	Signature []byte `json:"signature"`
+	Message   []byte `json:"message"`
+}
+
+type encryptReq struct {
+	Message        []byte `json:"message"`
+	AssociatedData []byte `json:"associated_data,omitempty"`
+}
+
+type encryptResp struct {
+	Ciphertext []byte `json:"ciphertext"`
+	Nonce      []byte `json:"nonce"`
+}
+
+type decryptReq struct {
+	Ciphertext     []byte `json:"ciphertext"`
+	AssociatedData []byte `json:"associated_data,omitempty"`
+	Nonce          []byte `json:"nonce"`
+}
+
+type decryptResp struct {
+	Plaintext []byte `json:"plaintext"`
+}
+
+type computeMACReq struct {
+	Data []byte `json:"data"`
+}
+
+type computeMACResp struct {
+	MAC []byte `json:"mac"`
+}
+
+type verifyMACReq struct {
+	MAC  []byte `json:"mac"`
+	Data []byte `json:"data"`
+}
+
+type signMultiReq struct {
+	Messages [][]byte `json:"messages"`
+}
+
+type verifyMultiReq struct {
+	Signature []byte   `json:"signature"`
+	Messages  [][]byte `json:"messages"`
+}
+
+type deriveProofReq struct {
+	Messages        [][]byte `json:"messages"`
+	Signature       []byte   `json:"signature"`
+	Nonce           []byte   `json:"nonce"`
+	RevealedIndexes []int    `json:"revealed_indexes"`
+}
+
+type deriveProofResp struct {
+	Proof []byte `json:"proof"`
+}
+
+type verifyProofReq struct {
+	Proof    []byte   `json:"proof"`
+	Messages [][]byte `json:"messages"`
+	Nonce    []byte   `json:"nonce"`
+}
+
+// wrapReq is the union of the WrapKey and CryptoBox.Easy requests, both are posted to a /wrap endpoint.
+type wrapReq struct {
+	// WrapKey fields.
+	CEK             []byte            `json:"cek"`
+	APU             []byte            `json:"apu"`
+	APV             []byte            `json:"apv"`
+	RecipientPubKey *crypto.PublicKey `json:"recipient_pub_key"`
+	Tag             []byte            `json:"tag,omitempty"`
+
+	// CryptoBox.Easy fields.
+	Payload  []byte `json:"payload"`
+	Nonce    []byte `json:"nonce"`
+	TheirPub []byte `json:"their_pub"`
+}
+
+type wrapKeyResp struct {
+	crypto.RecipientWrappedKey
+}
+
+type easyResp struct {
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// unwrapReq is the union of the UnwrapKey, CryptoBox.EasyOpen and CryptoBox.SealOpen requests, all are posted to a
+// key's /unwrap endpoint.
+type unwrapReq struct {
+	// UnwrapKey fields.
+	WrappedKey   *crypto.RecipientWrappedKey `json:"wrapped_key"`
+	SenderPubKey *crypto.PublicKey           `json:"sender_pub_key,omitempty"`
+	Tag          []byte                      `json:"tag,omitempty"`
+
+	// CryptoBox.EasyOpen and SealOpen fields (Nonce and TheirPub are only set for EasyOpen).
+	Ciphertext []byte `json:"ciphertext"`
+	Nonce      []byte `json:"nonce"`
+	TheirPub   []byte `json:"their_pub"`
+	MyPub      []byte `json:"my_pub"`
+}
+
+type unwrapKeyResp struct {
+	Key []byte `json:"key"`
+}
+
+type openResp struct {
+	Plaintext []byte `json:"plaintext"`
+}
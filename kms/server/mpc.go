@@ -0,0 +1,90 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/trustbloc/kms-go/kms"
+	"github.com/trustbloc/kms-go/x/crypto/ecdsa2p"
+)
+
+func (s *Server) mpcKeyGen(w http.ResponseWriter, r *http.Request) {
+	req := &ecdsa2p.KeyGenRequest{}
+	if !s.readRequest(w, r, req) {
+		return
+	}
+
+	resp, err := s.opts.mpc.KeyGen(req)
+	if err != nil {
+		writeMPCError(w, err)
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, resp)
+}
+
+func (s *Server) mpcKeyGenFinish(w http.ResponseWriter, r *http.Request) {
+	req := &ecdsa2p.KeyGenFinishRequest{}
+	if !s.readRequest(w, r, req) {
+		return
+	}
+
+	resp, err := s.opts.mpc.KeyGenFinish(req)
+	if err != nil {
+		writeMPCError(w, err)
+
+		return
+	}
+
+	writeResponse(w, http.StatusCreated, resp)
+}
+
+func (s *Server) mpcSign(w http.ResponseWriter, r *http.Request) {
+	req := &ecdsa2p.SignRequest{}
+	if !s.readRequest(w, r, req) {
+		return
+	}
+
+	resp, err := s.opts.mpc.Sign(req)
+	if err != nil {
+		writeMPCError(w, err)
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, resp)
+}
+
+func (s *Server) mpcSignFinish(w http.ResponseWriter, r *http.Request) {
+	req := &ecdsa2p.SignFinishRequest{}
+	if !s.readRequest(w, r, req) {
+		return
+	}
+
+	resp, err := s.opts.mpc.SignFinish(req)
+	if err != nil {
+		writeMPCError(w, err)
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, resp)
+}
+
+// writeMPCError reports protocol failures as bad requests, they are caused by invalid or replayed messages.
+func writeMPCError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, kms.ErrKeyNotFound) {
+		status = http.StatusNotFound
+	}
+
+	writeError(w, status, fmt.Sprintf("mpc: %s", err))
+}
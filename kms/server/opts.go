@@ -0,0 +1,108 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/trustbloc/kms-go/kms"
+	"github.com/trustbloc/kms-go/x/crypto/ecdsa2p"
+)
+
+// DefaultKeystoreID is the ID of the keystore served when none is set with WithKeystoreID.
+const DefaultKeystoreID = "default"
+
+// DefaultMaxRequestSize is the maximum size of the request bodies, in bytes, when none is set with WithMaxRequestSize.
+const DefaultMaxRequestSize = 4 * 1024 * 1024
+
+// Middleware wraps the server's http.Handler, eg to authenticate requests.
+type Middleware func(http.Handler) http.Handler
+
+type options struct {
+	keystoreID     string
+	baseURL        string
+	maxRequestSize int64
+	cryptoBox      kms.CryptoBox
+	mpc            *ecdsa2p.Server
+	middlewares    []Middleware
+	authorizer     Authorizer
+	rateLimiter    RateLimiter
+	auditSink      AuditSink
+}
+
+// Opt is a Server option.
+type Opt func(o *options)
+
+// WithKeystoreID sets the ID of the keystore served. Requests for any other keystore ID are rejected.
+func WithKeystoreID(id string) Opt {
+	return func(o *options) {
+		o.keystoreID = id
+	}
+}
+
+// WithBaseURL sets the server base URL (eg: https://kms.example.com) used to build keystore and key URLs returned to
+// clients. By default, the URL is built from the incoming request host.
+func WithBaseURL(baseURL string) Opt {
+	return func(o *options) {
+		o.baseURL = baseURL
+	}
+}
+
+// WithMaxRequestSize sets the maximum size of the request bodies, in bytes. Larger requests are rejected with the
+// 413 status. Values lower than 1 are ignored.
+func WithMaxRequestSize(size int64) Opt {
+	return func(o *options) {
+		if size > 0 {
+			o.maxRequestSize = size
+		}
+	}
+}
+
+// WithCryptoBox enables the legacy CryptoBox (Easy, EasyOpen and SealOpen) endpoints using box.
+func WithCryptoBox(box kms.CryptoBox) Opt {
+	return func(o *options) {
+		o.cryptoBox = box
+	}
+}
+
+// WithMPC enables the two-party ECDSA endpoints, the server taking the remote party role with mpc. The ecdsa2p
+// package is experimental: ecdsa2p.NewServer fails until the 'crypto/ecdsa2p' feature is enabled (see the x package).
+func WithMPC(mpc *ecdsa2p.Server) Opt {
+	return func(o *options) {
+		o.mpc = mpc
+	}
+}
+
+// WithMiddleware adds middlewares (eg: authentication) applied to all endpoints but /healthcheck. Middlewares are
+// applied in the given order, the first one being the outermost.
+func WithMiddleware(middlewares ...Middleware) Opt {
+	return func(o *options) {
+		o.middlewares = append(o.middlewares, middlewares...)
+	}
+}
+
+// WithAuthorizer sets the authorization check of the keystore operations requests, eg using the CallerInfo attached by
+// the CallerInfo middleware.
+func WithAuthorizer(authorizer Authorizer) Opt {
+	return func(o *options) {
+		o.authorizer = authorizer
+	}
+}
+
+// WithRateLimiter sets the rate limiter of the keystore operations requests.
+func WithRateLimiter(limiter RateLimiter) Opt {
+	return func(o *options) {
+		o.rateLimiter = limiter
+	}
+}
+
+// WithAuditSink sets the sink recording the keystore operations requests.
+func WithAuditSink(sink AuditSink) Opt {
+	return func(o *options) {
+		o.auditSink = sink
+	}
+}
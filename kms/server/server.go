@@ -0,0 +1,256 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package server is a reference implementation of the remote KMS REST API consumed by the webkms clients
+// (kms/webkms.RemoteKMS, kms/webkms.CryptoBox and crypto/webkms.RemoteCrypto). It exposes a kms.KeyManager and
+// crypto.Crypto pair as a single keystore.
+//
+// Endpoints (relative to the server base URL):
+//
+//	GET  /healthcheck
+//	POST /v1/keystores                               create keystore (returns the served keystore URL)
+//	GET  /v1/keystores/{keystoreID}/capabilities     supported key types, algorithms, operations and limits
+//	POST /v1/keystores/{keystoreID}/keys             create key
+//	PUT  /v1/keystores/{keystoreID}/keys             import private key (PKCS#8)
+//	GET  /v1/keystores/{keystoreID}/keys/{keyID}/export
+//	POST /v1/keystores/{keystoreID}/wrap             anoncrypt key wrapping
+//	POST /v1/keystores/{keystoreID}/keys/{keyID}/{op}
+//
+// where op is one of sign, verify, encrypt, decrypt, computemac, verifymac, signmulti, verifymulti, deriveproof,
+// verifyproof, wrap (authcrypt key wrapping or CryptoBox Easy) and unwrap (key unwrapping or CryptoBox EasyOpen and
+// SealOpen).
+//
+// With WithMPC, the two-party ECDSA (x/crypto/ecdsa2p) remote party endpoints are also served:
+//
+//	POST /v1/keystores/{keystoreID}/mpc/keygen
+//	POST /v1/keystores/{keystoreID}/mpc/keygen/finish
+//	POST /v1/keystores/{keystoreID}/mpc/sign
+//	POST /v1/keystores/{keystoreID}/mpc/sign/finish
+//
+// Multi-user deployments attach the caller identity to the requests with the CallerInfo middleware, it is then
+// passed to the Authorizer, RateLimiter and AuditSink set with WithAuthorizer, WithRateLimiter and WithAuditSink.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/google/tink/go/keyset"
+
+	"github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/kms"
+)
+
+const (
+	keystoresPath = "/v1/keystores"
+	keysPath      = keystoresPath + "/{keystoreID}/keys"
+	keyPath       = keysPath + "/{keyID}"
+	mpcPath       = keystoresPath + "/{keystoreID}/mpc"
+
+	contentType = "application/json"
+
+	logPrefix = " [kms-go/kms/server] "
+)
+
+var errorLogger = log.New(os.Stderr, logPrefix, log.Ldate|log.Ltime|log.LUTC)
+
+// Server serves a KeyManager and Crypto pair over the webkms REST API. It implements http.Handler.
+type Server struct {
+	km      kmsapi.KeyManager
+	crypto  crypto.Crypto
+	opts    *options
+	handler http.Handler
+}
+
+// New creates a new REST Server exposing km and c.
+func New(km kmsapi.KeyManager, c crypto.Crypto, opts ...Opt) *Server {
+	o := &options{keystoreID: DefaultKeystoreID, maxRequestSize: DefaultMaxRequestSize}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	s := &Server{
+		km:     km,
+		crypto: c,
+		opts:   o,
+	}
+
+	api := http.NewServeMux()
+
+	api.HandleFunc("POST "+keystoresPath, s.createKeystore)
+	api.HandleFunc("GET "+keystoresPath+"/{keystoreID}/capabilities", s.keystore(s.guard("capabilities", s.capabilities)))
+	api.HandleFunc("POST "+keysPath, s.keystore(s.guard("create", s.createKey)))
+	api.HandleFunc("PUT "+keysPath, s.keystore(s.guard("import", s.importKey)))
+	api.HandleFunc("GET "+keyPath+"/export", s.keystore(s.guard("export", s.exportKey)))
+	api.HandleFunc("POST "+keystoresPath+"/{keystoreID}/wrap", s.keystore(s.guard("wrap", s.wrap)))
+	api.HandleFunc("POST "+keyPath+"/wrap", s.keystore(s.guard("wrap", s.wrap)))
+	api.HandleFunc("POST "+keyPath+"/unwrap", s.keystore(s.guard("unwrap", s.unwrap)))
+	api.HandleFunc("POST "+keyPath+"/sign", s.keystore(s.guard("sign", s.sign)))
+	api.HandleFunc("POST "+keyPath+"/verify", s.keystore(s.guard("verify", s.verify)))
+	api.HandleFunc("POST "+keyPath+"/encrypt", s.keystore(s.guard("encrypt", s.encrypt)))
+	api.HandleFunc("POST "+keyPath+"/decrypt", s.keystore(s.guard("decrypt", s.decrypt)))
+	api.HandleFunc("POST "+keyPath+"/computemac", s.keystore(s.guard("computemac", s.computeMAC)))
+	api.HandleFunc("POST "+keyPath+"/verifymac", s.keystore(s.guard("verifymac", s.verifyMAC)))
+	api.HandleFunc("POST "+keyPath+"/signmulti", s.keystore(s.guard("signmulti", s.signMulti)))
+	api.HandleFunc("POST "+keyPath+"/verifymulti", s.keystore(s.guard("verifymulti", s.verifyMulti)))
+	api.HandleFunc("POST "+keyPath+"/deriveproof", s.keystore(s.guard("deriveproof", s.deriveProof)))
+	api.HandleFunc("POST "+keyPath+"/verifyproof", s.keystore(s.guard("verifyproof", s.verifyProof)))
+
+	if o.mpc != nil {
+		api.HandleFunc("POST "+mpcPath+"/keygen", s.keystore(s.guard("mpc/keygen", s.mpcKeyGen)))
+		api.HandleFunc("POST "+mpcPath+"/keygen/finish", s.keystore(s.guard("mpc/keygen/finish", s.mpcKeyGenFinish)))
+		api.HandleFunc("POST "+mpcPath+"/sign", s.keystore(s.guard("mpc/sign", s.mpcSign)))
+		api.HandleFunc("POST "+mpcPath+"/sign/finish", s.keystore(s.guard("mpc/sign/finish", s.mpcSignFinish)))
+	}
+
+	var apiHandler http.Handler = api
+
+	for i := len(o.middlewares) - 1; i >= 0; i-- {
+		apiHandler = o.middlewares[i](apiHandler)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthcheck", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.Handle("/", apiHandler)
+
+	s.handler = mux
+
+	return s
+}
+
+// ServeHTTP serves the REST API.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+// keystore rejects requests for a keystore that is not served.
+func (s *Server) keystore(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("keystoreID") != s.opts.keystoreID {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("keystore %q not found", r.PathValue("keystoreID")))
+
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (s *Server) keystoreURL(r *http.Request) string {
+	baseURL := s.opts.baseURL
+
+	if baseURL == "" {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+
+		baseURL = scheme + "://" + r.Host
+	}
+
+	return baseURL + keystoresPath + "/" + s.opts.keystoreID
+}
+
+func (s *Server) keyURL(r *http.Request, keyID string) string {
+	return s.keystoreURL(r) + "/keys/" + keyID
+}
+
+// getKey returns the key handle of the keyID path value. It writes the error response and returns nil on failure.
+func (s *Server) getKey(w http.ResponseWriter, r *http.Request) interface{} {
+	kh, err := s.km.Get(r.PathValue("keyID"))
+	if err != nil {
+		writeKMSError(w, "get key", err)
+
+		return nil
+	}
+
+	return kh
+}
+
+// getPublicKey returns the public key handle of the keyID path value when the KeyManager returns Tink keyset
+// handles, verification primitives require public keyset handles.
+func (s *Server) getPublicKey(w http.ResponseWriter, r *http.Request) interface{} {
+	kh := s.getKey(w, r)
+	if kh == nil {
+		return nil
+	}
+
+	ksh, ok := kh.(*keyset.Handle)
+	if !ok {
+		return kh
+	}
+
+	pubKH, err := ksh.Public()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("get public key: %s", err))
+
+		return nil
+	}
+
+	return pubKH
+}
+
+// readRequest decodes the JSON body of r into req. It writes the error response and returns false on failure, with
+// the 413 status if the body exceeds the maximum request size.
+func (s *Server) readRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.opts.maxRequestSize))
+	if err != nil {
+		status := http.StatusBadRequest
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+
+		writeError(w, status, fmt.Sprintf("read request: %s", err))
+
+		return false
+	}
+
+	if err = json.Unmarshal(body, req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %s", err))
+
+		return false
+	}
+
+	return true
+}
+
+func writeResponse(w http.ResponseWriter, status int, resp interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+
+	if resp == nil {
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		errorLogger.Printf("failed to write response: %s", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeResponse(w, status, &errMessage{Error: msg})
+}
+
+func writeKMSError(w http.ResponseWriter, op string, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, kms.ErrKeyNotFound) {
+		status = http.StatusNotFound
+	}
+
+	writeError(w, status, fmt.Sprintf("%s: %s", op, err))
+}
@@ -0,0 +1,589 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	webcrypto "github.com/trustbloc/kms-go/crypto/webkms"
+	mockstorage "github.com/trustbloc/kms-go/internal/mock/storage"
+	"github.com/trustbloc/kms-go/kms"
+	"github.com/trustbloc/kms-go/kms/localkms"
+	"github.com/trustbloc/kms-go/kms/server"
+	"github.com/trustbloc/kms-go/kms/webkms"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+	"github.com/trustbloc/kms-go/util/cryptoutil"
+	"github.com/trustbloc/kms-go/x"
+	"github.com/trustbloc/kms-go/x/crypto/ecdsa2p"
+)
+
+func newTestServer(t *testing.T, opts ...server.Opt) (*httptest.Server, *mockkms.KeyManager) {
+	t.Helper()
+
+	km := mockkms.NewForTest(t)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	box, err := localkms.NewCryptoBox(km.LocalKMS)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(server.New(km, cr, append([]server.Opt{server.WithCryptoBox(box)}, opts...)...))
+	t.Cleanup(srv.Close)
+
+	return srv, km
+}
+
+func createKeystore(t *testing.T, srv *httptest.Server, opts ...webkms.Opt) string {
+	t.Helper()
+
+	keystoreURL, _, err := webkms.CreateKeyStore(srv.Client(), srv.URL, "controller", "", nil, opts...)
+	require.NoError(t, err)
+	require.Equal(t, srv.URL+"/v1/keystores/"+server.DefaultKeystoreID, keystoreURL)
+
+	return keystoreURL
+}
+
+func TestServer_RemoteKMS(t *testing.T) {
+	srv, _ := newTestServer(t)
+	keystoreURL := createKeystore(t, srv)
+
+	rKMS := webkms.New(keystoreURL, srv.Client())
+	require.NoError(t, rKMS.HealthCheck())
+
+	kid, pubKey, err := rKMS.CreateAndExportPubKeyBytes(kmsapi.ED25519Type)
+	require.NoError(t, err)
+	require.NotEmpty(t, pubKey)
+
+	exported, kt, err := rKMS.ExportPubKeyBytes(kid)
+	require.NoError(t, err)
+	require.Equal(t, pubKey, exported)
+	require.Equal(t, kmsapi.ED25519Type, kt)
+
+	_, _, err = rKMS.ExportPubKeyBytes("unknown")
+	require.Error(t, err)
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	importedKID, keyURL, err := rKMS.ImportPrivateKey(privKey, kmsapi.ECDSAP256TypeDER, kmsapi.WithKeyID("imported"))
+	require.NoError(t, err)
+	require.Equal(t, "imported", importedKID)
+	require.Equal(t, keystoreURL+"/keys/imported", keyURL)
+
+	_, _, err = rKMS.Create("unknown-type")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "create key")
+}
+
+func TestServer_RemoteCrypto(t *testing.T) {
+	srv, _ := newTestServer(t)
+	keystoreURL := createKeystore(t, srv)
+
+	rKMS := webkms.New(keystoreURL, srv.Client())
+	rCrypto := webcrypto.New(keystoreURL, srv.Client())
+
+	t.Run("sign and verify", func(t *testing.T) {
+		_, keyURL, err := rKMS.Create(kmsapi.ECDSAP256TypeIEEEP1363)
+		require.NoError(t, err)
+
+		sig, err := rCrypto.Sign([]byte("msg"), keyURL)
+		require.NoError(t, err)
+
+		require.NoError(t, rCrypto.Verify(sig, []byte("msg"), keyURL))
+		require.Error(t, rCrypto.Verify(sig, []byte("other msg"), keyURL))
+	})
+
+	t.Run("encrypt and decrypt", func(t *testing.T) {
+		_, keyURL, err := rKMS.Create(kmsapi.AES256GCMType)
+		require.NoError(t, err)
+
+		ct, nonce, err := rCrypto.Encrypt([]byte("msg"), []byte("aad"), keyURL)
+		require.NoError(t, err)
+
+		pt, err := rCrypto.Decrypt(ct, []byte("aad"), nonce, keyURL)
+		require.NoError(t, err)
+		require.Equal(t, []byte("msg"), pt)
+	})
+
+	t.Run("compute and verify MAC", func(t *testing.T) {
+		_, keyURL, err := rKMS.Create(kmsapi.HMACSHA256Tag256Type)
+		require.NoError(t, err)
+
+		mac, err := rCrypto.ComputeMAC([]byte("data"), keyURL)
+		require.NoError(t, err)
+
+		require.NoError(t, rCrypto.VerifyMAC(mac, []byte("data"), keyURL))
+		require.Error(t, rCrypto.VerifyMAC(mac, []byte("other data"), keyURL))
+	})
+
+	t.Run("BBS+ sign, verify and derive proof", func(t *testing.T) {
+		_, keyURL, err := rKMS.Create(kmsapi.BLS12381G2Type)
+		require.NoError(t, err)
+
+		msgs := [][]byte{[]byte("msg1"), []byte("msg2")}
+
+		sig, err := rCrypto.SignMulti(msgs, keyURL)
+		require.NoError(t, err)
+		require.NoError(t, rCrypto.VerifyMulti(msgs, sig, keyURL))
+
+		nonce := []byte("nonce")
+
+		proof, err := rCrypto.DeriveProof(msgs, sig, nonce, []int{0}, keyURL)
+		require.NoError(t, err)
+		require.NoError(t, rCrypto.VerifyProof([][]byte{msgs[0]}, proof, nonce, keyURL))
+	})
+
+	t.Run("wrap and unwrap", func(t *testing.T) {
+		recKID, recPubKeyBytes, err := rKMS.CreateAndExportPubKeyBytes(kmsapi.NISTP256ECDHKWType)
+		require.NoError(t, err)
+
+		recPubKey := &cryptoapi.PublicKey{}
+		require.NoError(t, json.Unmarshal(recPubKeyBytes, recPubKey))
+
+		recPubKey.KID = recKID
+		recKeyURL := keystoreURL + "/keys/" + recKID
+		cek := []byte("0123456789abcdef0123456789abcdef")
+
+		wk, err := rCrypto.WrapKey(cek, []byte("apu"), []byte("apv"), recPubKey)
+		require.NoError(t, err)
+
+		key, err := rCrypto.UnwrapKey(wk, recKeyURL)
+		require.NoError(t, err)
+		require.Equal(t, cek, key)
+
+		senderKID, senderPubKeyBytes, err := rKMS.CreateAndExportPubKeyBytes(kmsapi.NISTP256ECDHKWType)
+		require.NoError(t, err)
+
+		senderPubKey := &cryptoapi.PublicKey{}
+		require.NoError(t, json.Unmarshal(senderPubKeyBytes, senderPubKey))
+
+		wk, err = rCrypto.WrapKey(cek, []byte("apu"), []byte("apv"), recPubKey,
+			cryptoapi.WithSender(keystoreURL+"/keys/"+senderKID), cryptoapi.WithTag([]byte("tag")))
+		require.NoError(t, err)
+
+		key, err = rCrypto.UnwrapKey(wk, recKeyURL, cryptoapi.WithSender(senderPubKey),
+			cryptoapi.WithTag([]byte("tag")))
+		require.NoError(t, err)
+		require.Equal(t, cek, key)
+	})
+
+	t.Run("key not found", func(t *testing.T) {
+		_, err := rCrypto.Sign([]byte("msg"), keystoreURL+"/keys/unknown")
+		require.EqualError(t, err, "posting Sign returned http error: 404 Not Found")
+	})
+}
+
+func TestServer_CryptoBox(t *testing.T) {
+	srv, km := newTestServer(t)
+	keystoreURL := createKeystore(t, srv)
+
+	rKMS := webkms.New(keystoreURL, srv.Client())
+
+	rBox, err := webkms.NewCryptoBox(rKMS)
+	require.NoError(t, err)
+
+	senderKID, senderPub, err := km.CreateAndExportPubKeyBytes(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	_, recPub, err := km.CreateAndExportPubKeyBytes(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	recEncPub := ed25519PubToCurve25519(t, recPub)
+	senderEncPub := ed25519PubToCurve25519(t, senderPub)
+	nonce := []byte("0123456789abcdef01234567")
+
+	ct, err := rBox.Easy([]byte("payload"), nonce, recEncPub, senderKID)
+	require.NoError(t, err)
+
+	pt, err := rBox.EasyOpen(ct, nonce, senderEncPub, recPub)
+	require.NoError(t, err)
+	require.Equal(t, []byte("payload"), pt)
+
+	sealed, err := rBox.Seal([]byte("sealed payload"), recEncPub, rand.Reader)
+	require.NoError(t, err)
+
+	pt, err = rBox.SealOpen(sealed, recPub)
+	require.NoError(t, err)
+	require.Equal(t, []byte("sealed payload"), pt)
+
+	t.Run("crypto box not enabled", func(t *testing.T) {
+		cr, err := tinkcrypto.New()
+		require.NoError(t, err)
+
+		noBoxSrv := httptest.NewServer(server.New(km, cr))
+		defer noBoxSrv.Close()
+
+		noBox, err := webkms.NewCryptoBox(webkms.New(createKeystore(t, noBoxSrv), noBoxSrv.Client()))
+		require.NoError(t, err)
+
+		_, err = noBox.Easy([]byte("payload"), nonce, recEncPub, senderKID)
+		require.EqualError(t, err, "easy: crypto box is not supported")
+
+		_, err = noBox.SealOpen(sealed, recPub)
+		require.EqualError(t, err, "open: crypto box is not supported")
+	})
+}
+
+func TestServer_MPC(t *testing.T) {
+	x.Enable("crypto/ecdsa2p")
+
+	serverStore, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+	require.NoError(t, err)
+
+	mpc, err := ecdsa2p.NewServer(serverStore, ecdsa2p.WithSecretLock(&noop.NoLock{}, ""))
+	require.NoError(t, err)
+
+	srv, _ := newTestServer(t, server.WithMPC(mpc))
+	keystoreURL := createKeystore(t, srv)
+
+	remote, err := webkms.NewMPCRemote(webkms.New(keystoreURL, srv.Client()))
+	require.NoError(t, err)
+
+	deviceStore, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+	require.NoError(t, err)
+
+	party, err := ecdsa2p.NewParty(deviceStore, remote, ecdsa2p.WithSecretLock(&noop.NoLock{}, ""))
+	require.NoError(t, err)
+
+	keyID, pub, err := party.CreateKey()
+	require.NoError(t, err)
+
+	sig, err := party.SignMPC(keyID, []byte("firmware image"))
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte("firmware image"))
+	require.True(t, ecdsa.VerifyASN1(pub, digest[:], sig))
+
+	_, err = remote.SignFinish(&ecdsa2p.SignFinishRequest{KeyID: keyID, SessionID: "unknown"})
+	require.ErrorContains(t, err, "unknown session")
+
+	_, err = remote.Sign(&ecdsa2p.SignRequest{KeyID: "unknown", Digest: digest[:], Commitment: digest[:]})
+	require.ErrorContains(t, err, "key not found")
+
+	t.Run("mpc not enabled", func(t *testing.T) {
+		noMPCSrv, _ := newTestServer(t)
+
+		noMPC, err := webkms.NewMPCRemote(webkms.New(createKeystore(t, noMPCSrv), noMPCSrv.Client()))
+		require.NoError(t, err)
+
+		noMPCParty, err := ecdsa2p.NewParty(deviceStore, noMPC, ecdsa2p.WithSecretLock(&noop.NoLock{}, ""))
+		require.NoError(t, err)
+
+		_, err = noMPCParty.SignMPC(keyID, []byte("msg"))
+		require.Error(t, err)
+	})
+}
+
+func TestServer_Capabilities(t *testing.T) {
+	srv, _ := newTestServer(t)
+	keystoreURL := createKeystore(t, srv)
+
+	caps, err := webkms.New(keystoreURL, srv.Client()).Capabilities()
+	require.NoError(t, err)
+
+	// key management operations from localkms and crypto operations from tinkcrypto are merged.
+	require.True(t, caps.Supports(kmsapi.ED25519Type, kmsapi.OperationCreate))
+	require.True(t, caps.Supports(kmsapi.ED25519Type, kmsapi.OperationSign))
+	require.True(t, caps.Supports(kmsapi.NISTP256ECDHKWType, kmsapi.OperationWrapKey))
+	require.False(t, caps.Supports(kmsapi.AES256GCMType, kmsapi.OperationSign))
+	require.Contains(t, caps.ContentEncryption, "A256GCM")
+
+	cryptoCaps, err := webcrypto.New(keystoreURL, srv.Client()).Capabilities()
+	require.NoError(t, err)
+	require.Equal(t, caps, cryptoCaps)
+
+	t.Run("not supported", func(t *testing.T) {
+		cr, err := tinkcrypto.New()
+		require.NoError(t, err)
+
+		// embedding hides the Capabilities methods.
+		noCapsSrv := httptest.NewServer(server.New(
+			struct{ kmsapi.KeyManager }{mockkms.NewForTest(t)}, struct{ cryptoapi.Crypto }{cr}))
+		defer noCapsSrv.Close()
+
+		_, err = webkms.New(createKeystore(t, noCapsSrv), noCapsSrv.Client()).Capabilities()
+		require.ErrorContains(t, err, "not supported")
+	})
+}
+
+func TestServer_Auth(t *testing.T) {
+	srv, _ := newTestServer(t, server.WithMiddleware(server.BearerTokenAuth(func(_ *http.Request, token string) error {
+		if token != "secret" {
+			return errors.New("invalid token")
+		}
+
+		return nil
+	})))
+
+	withToken := func(token string) webkms.Opt {
+		return webkms.WithHeaders(func(req *http.Request) (*http.Header, error) {
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			return &req.Header, nil
+		})
+	}
+
+	keystoreURL := createKeystore(t, srv, withToken("secret"))
+
+	_, _, err := webkms.New(keystoreURL, srv.Client(), withToken("secret")).Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	_, _, err = webkms.New(keystoreURL, srv.Client(), withToken("wrong")).Create(kmsapi.ED25519Type)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid token")
+
+	_, _, err = webkms.New(keystoreURL, srv.Client()).Create(kmsapi.ED25519Type)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing bearer token")
+
+	// healthcheck is not authenticated.
+	require.NoError(t, webkms.New(keystoreURL, srv.Client()).HealthCheck())
+}
+
+type authorizerFunc func(ctx context.Context, op, keyID string) error
+
+func (f authorizerFunc) Authorize(ctx context.Context, op, keyID string) error {
+	return f(ctx, op, keyID)
+}
+
+type rateLimiterFunc func(ctx context.Context, op string) error
+
+func (f rateLimiterFunc) Allow(ctx context.Context, op string) error {
+	return f(ctx, op)
+}
+
+type auditSinkFunc func(ctx context.Context, event *server.AuditEvent)
+
+func (f auditSinkFunc) Audit(ctx context.Context, event *server.AuditEvent) {
+	f(ctx, event)
+}
+
+func TestServer_CallerInfo(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []*server.AuditEvent
+		signs  = map[string]int{}
+	)
+
+	srv, _ := newTestServer(t,
+		server.WithMiddleware(server.CallerInfo(func(r *http.Request) (*kmsapi.CallerInfo, error) {
+			subject := r.Header.Get("X-Subject")
+			if subject == "" {
+				return nil, errors.New("unknown caller")
+			}
+
+			return &kmsapi.CallerInfo{Subject: subject, Origin: r.RemoteAddr, Purpose: r.Header.Get("X-Purpose")}, nil
+		})),
+		server.WithAuthorizer(authorizerFunc(func(ctx context.Context, op, _ string) error {
+			if op == "sign" && kmsapi.CallerInfoFromContext(ctx).Subject == "auditor" {
+				return errors.New("auditors can't sign")
+			}
+
+			return nil
+		})),
+		server.WithRateLimiter(rateLimiterFunc(func(ctx context.Context, op string) error {
+			if op != "sign" {
+				return nil
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			subject := kmsapi.CallerInfoFromContext(ctx).Subject
+			if signs[subject] == 2 {
+				return errors.New("sign rate exceeded")
+			}
+
+			signs[subject]++
+
+			return nil
+		})),
+		server.WithAuditSink(auditSinkFunc(func(_ context.Context, event *server.AuditEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			events = append(events, event)
+		})),
+	)
+
+	as := func(subject string) webkms.Opt {
+		return webkms.WithHeaders(func(req *http.Request) (*http.Header, error) {
+			req.Header.Set("X-Subject", subject)
+			req.Header.Set("X-Purpose", "test")
+
+			return &req.Header, nil
+		})
+	}
+
+	keystoreURL := createKeystore(t, srv, as("alice"))
+
+	_, keyURL, err := webkms.New(keystoreURL, srv.Client(), as("alice")).Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	aliceCrypto := webcrypto.New(keystoreURL, srv.Client(), as("alice"))
+
+	for i := 0; i < 2; i++ {
+		_, err = aliceCrypto.Sign([]byte("msg"), keyURL)
+		require.NoError(t, err)
+	}
+
+	_, err = aliceCrypto.Sign([]byte("msg"), keyURL)
+	require.ErrorContains(t, err, "429")
+
+	auditorCrypto := webcrypto.New(keystoreURL, srv.Client(), as("auditor"))
+
+	_, err = auditorCrypto.Sign([]byte("msg"), keyURL)
+	require.ErrorContains(t, err, "403")
+
+	_, _, err = webkms.New(keystoreURL, srv.Client()).Create(kmsapi.ED25519Type)
+	require.ErrorContains(t, err, "unknown caller")
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Len(t, events, 5)
+	require.Equal(t, "create", events[0].Op)
+	require.Equal(t, &kmsapi.CallerInfo{Subject: "alice", Origin: events[0].Caller.Origin, Purpose: "test"},
+		events[0].Caller)
+	require.Equal(t, http.StatusCreated, events[0].Status)
+
+	for _, e := range events[1:] {
+		require.Equal(t, "sign", e.Op)
+		require.NotEmpty(t, e.KeyID)
+	}
+
+	require.Equal(t, http.StatusOK, events[2].Status)
+	require.Equal(t, http.StatusTooManyRequests, events[3].Status)
+	require.Equal(t, "auditor", events[4].Caller.Subject)
+	require.Equal(t, http.StatusForbidden, events[4].Status)
+}
+
+type auditRecord struct {
+	op      kmsapi.Operation
+	keyID   string
+	success bool
+	caller  *kmsapi.CallerInfo
+}
+
+type auditLogger struct {
+	mu      sync.Mutex
+	records []auditRecord
+}
+
+func (l *auditLogger) OnOperation(op kmsapi.Operation, keyID string, success bool, _ time.Duration,
+	caller *kmsapi.CallerInfo) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.records = append(l.records, auditRecord{op: op, keyID: keyID, success: success, caller: caller})
+}
+
+func TestServer_AuditLogger(t *testing.T) {
+	serverLogger, clientLogger := &auditLogger{}, &auditLogger{}
+
+	srv, _ := newTestServer(t, server.WithAuditSink(server.AuditLoggerSink(serverLogger)))
+
+	keystoreURL := createKeystore(t, srv)
+
+	kid, keyURL, err := webkms.New(keystoreURL, srv.Client(), webkms.WithAuditLogger(clientLogger)).
+		Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	remoteCrypto := webcrypto.New(keystoreURL, srv.Client(), webkms.WithAuditLogger(clientLogger))
+
+	_, err = remoteCrypto.Sign([]byte("msg"), keyURL)
+	require.NoError(t, err)
+
+	err = remoteCrypto.Verify([]byte("bad signature"), []byte("msg"), keyURL)
+	require.Error(t, err)
+
+	expected := []auditRecord{
+		{op: kmsapi.OperationCreate, keyID: kid, success: true},
+		{op: kmsapi.OperationSign, keyID: kid, success: true},
+		{op: kmsapi.OperationVerify, keyID: kid, success: false},
+	}
+
+	require.Equal(t, expected, clientLogger.records)
+
+	expected[0].keyID = ""
+
+	require.Equal(t, expected, serverLogger.records)
+}
+
+func TestServer_Errors(t *testing.T) {
+	srv, _ := newTestServer(t, server.WithKeystoreID("ks1"), server.WithBaseURL("https://kms.example.com"))
+
+	keystoreURL, _, err := webkms.CreateKeyStore(srv.Client(), srv.URL, "controller", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, "https://kms.example.com/v1/keystores/ks1", keystoreURL)
+
+	_, _, err = webkms.New(srv.URL+"/v1/keystores/other", srv.Client()).Create(kmsapi.ED25519Type)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `keystore "other" not found`)
+
+	resp, err := srv.Client().Post(srv.URL+"/v1/keystores/ks1/keys", "application/json", strings.NewReader("{"))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = srv.Client().Post(srv.URL+"/v1/keystores/ks1/wrap", "application/json", strings.NewReader("{}"))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = srv.Client().Post(srv.URL+"/v1/keystores/ks1/keys", "application/json",
+		strings.NewReader(`{"key_type":"ED25519"}`))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.True(t, strings.HasPrefix(resp.Header.Get("Location"), "https://kms.example.com/v1/keystores/ks1/keys/"))
+}
+
+func TestServer_MaxRequestSize(t *testing.T) {
+	srv, _ := newTestServer(t, server.WithMaxRequestSize(64))
+
+	resp, err := srv.Client().Post(srv.URL+"/v1/keystores/default/keys", "application/json",
+		strings.NewReader(`{"key_type":"ED25519"}`))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp, err = srv.Client().Post(srv.URL+"/v1/keystores/default/keys", "application/json",
+		strings.NewReader(`{"key_type":"ED25519","attrs":["`+strings.Repeat("a", 64)+`"]}`))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
+
+func ed25519PubToCurve25519(t *testing.T, pub []byte) []byte {
+	t.Helper()
+
+	encPub, err := cryptoutil.PublicEd25519toCurve25519(pub)
+	require.NoError(t, err)
+
+	return encPub
+}
@@ -0,0 +1,394 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package sigstore is a sigstore KMS provider of the signing keys of a KeyManager, so cosign and the other sigstore
+// tools can sign container images and blobs with keys held by kms-go. The keys are referenced by kms-go://<key ID>
+// URIs, eg: cosign sign --key kms-go://release-signing-key.
+//
+// SignerVerifier implements the kms.SignerVerifier interface of github.com/sigstore/sigstore/pkg/signature/kms, it is
+// registered for ReferenceScheme with kms.AddProvider by RegisterProvider. The precomputed digests of the sigstore
+// options.WithDigest option are signed and verified instead of the messages.
+//
+// The messages are signed and verified with a Crypto service, the ECDSA signatures are ASN.1 DER encoded as sigstore
+// expects them. The precomputed digests are signed with the crypto.Signer of the signer package.
+package sigstore
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/kms"
+
+	"github.com/trustbloc/kms-go/crypto/signer"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/util/sigencoding"
+)
+
+// ReferenceScheme is the scheme of the key references of the provider.
+const ReferenceScheme = "kms-go://"
+
+// Signing algorithms of CreateKey.
+const (
+	AlgorithmECDSAP256 = "ecdsa-p256-sha256"
+	AlgorithmECDSAP384 = "ecdsa-p384-sha384"
+	AlgorithmECDSAP521 = "ecdsa-p521-sha512"
+	AlgorithmRSAPKCS1  = "rsa-pkcs1-2048-sha256"
+	AlgorithmRSAPSS    = "rsa-pss-2048-sha256"
+	AlgorithmED25519   = "ed25519"
+)
+
+type algorithm struct {
+	name    string
+	keyType kmsapi.KeyType
+}
+
+// algorithms are the signing algorithms of the provider, the first algorithm is the default algorithm.
+//
+//nolint:gochecknoglobals
+var algorithms = []algorithm{
+	{AlgorithmECDSAP256, kmsapi.ECDSAP256TypeDER},
+	{AlgorithmECDSAP384, kmsapi.ECDSAP384TypeDER},
+	{AlgorithmECDSAP521, kmsapi.ECDSAP521TypeDER},
+	{AlgorithmRSAPKCS1, kmsapi.RSARS256Type},
+	{AlgorithmRSAPSS, kmsapi.RSAPS256Type},
+	{AlgorithmED25519, kmsapi.ED25519Type},
+}
+
+// keyTypeHashes are the hash functions of the signing key types, including the IEEE P1363 ECDSA key types of the
+// existing keys.
+//
+//nolint:gochecknoglobals
+var keyTypeHashes = map[kmsapi.KeyType]crypto.Hash{
+	kmsapi.ECDSAP256TypeDER:       crypto.SHA256,
+	kmsapi.ECDSAP384TypeDER:       crypto.SHA384,
+	kmsapi.ECDSAP521TypeDER:       crypto.SHA512,
+	kmsapi.ECDSAP256TypeIEEEP1363: crypto.SHA256,
+	kmsapi.ECDSAP384TypeIEEEP1363: crypto.SHA384,
+	kmsapi.ECDSAP521TypeIEEEP1363: crypto.SHA512,
+	kmsapi.RSARS256Type:           crypto.SHA256,
+	kmsapi.RSAPS256Type:           crypto.SHA256,
+	kmsapi.ED25519Type:            crypto.Hash(0),
+}
+
+// ValidReference checks ref is a kms-go://<key ID> key reference.
+func ValidReference(ref string) error {
+	_, err := parseReference(ref)
+
+	return err
+}
+
+func parseReference(ref string) (string, error) {
+	keyID, ok := strings.CutPrefix(ref, ReferenceScheme)
+	if !ok || keyID == "" {
+		return "", fmt.Errorf("invalid kms-go key reference '%s', expected %s<key ID>", ref, ReferenceScheme)
+	}
+
+	return keyID, nil
+}
+
+type opts struct {
+	digest []byte
+}
+
+// newOpts returns the opts of the sigstore options: the digest of options.WithDigest, computed with the hash function
+// of the key. Ed25519 keys sign the messages only.
+func newOpts[T signature.MessageOption](options []T) *opts {
+	o := &opts{}
+
+	for _, opt := range options {
+		opt.ApplyDigest(&o.digest)
+	}
+
+	return o
+}
+
+// RegisterProvider registers the provider of the kms-go://<key ID> key references with kms.AddProvider, signing with
+// km and c: kms.Get then returns the SignerVerifier of LoadSignerVerifier for these references.
+func RegisterProvider(km kmsapi.KeyManager, c cryptoapi.Crypto) {
+	kms.AddProvider(ReferenceScheme, func(_ context.Context, ref string, hashFunc crypto.Hash,
+		_ ...signature.RPCOption) (kms.SignerVerifier, error) {
+		sv, err := LoadSignerVerifier(km, c, ref, hashFunc)
+		if err != nil {
+			return nil, err
+		}
+
+		return sv, nil
+	})
+}
+
+// SignerVerifier signs and verifies with the key of a kms-go key reference.
+type SignerVerifier struct {
+	km       kmsapi.KeyManager
+	crypto   cryptoapi.Crypto
+	keyID    string
+	hashFunc crypto.Hash
+}
+
+var _ kms.SignerVerifier = (*SignerVerifier)(nil)
+
+// LoadSignerVerifier returns the SignerVerifier of the key referenced by ref, signing with km and c. The key is not
+// loaded: it can be created with CreateKey. hashFunc is the hash function requested by the sigstore tool, the
+// signatures fail if the key uses another hash function, 0 for the hash function of the key.
+func LoadSignerVerifier(km kmsapi.KeyManager, c cryptoapi.Crypto, ref string, hashFunc crypto.Hash) (*SignerVerifier,
+	error) {
+	keyID, err := parseReference(ref)
+	if err != nil {
+		return nil, fmt.Errorf("sigstore load signer verifier: %w", err)
+	}
+
+	return &SignerVerifier{km: km, crypto: c, keyID: keyID, hashFunc: hashFunc}, nil
+}
+
+// SignMessage signs the message read from message, or the digest of the options.WithDigest option.
+func (s *SignerVerifier) SignMessage(message io.Reader, options ...signature.SignOption) ([]byte, error) {
+	sig, err := s.sign(message, newOpts(options))
+	if err != nil {
+		return nil, fmt.Errorf("sigstore sign message: %w", err)
+	}
+
+	return sig, nil
+}
+
+func (s *SignerVerifier) sign(message io.Reader, o *opts) ([]byte, error) {
+	_, kt, err := s.publicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.checkKeyType(kt); err != nil {
+		return nil, err
+	}
+
+	if o.digest != nil {
+		if kt == kmsapi.ED25519Type {
+			return nil, fmt.Errorf("key type '%s' doesn't sign digests", kt)
+		}
+
+		sig, e := signer.New(s.km, s.keyID)
+		if e != nil {
+			return nil, e
+		}
+
+		return sig.Sign(rand.Reader, o.digest, signerOpts(kt))
+	}
+
+	msg, err := io.ReadAll(message)
+	if err != nil {
+		return nil, fmt.Errorf("read message: %w", err)
+	}
+
+	kh, err := s.km.Get(s.keyID)
+	if err != nil {
+		return nil, fmt.Errorf("get key: %w", err)
+	}
+
+	sig, err := s.crypto.Sign(msg, kh)
+	if err != nil {
+		return nil, err
+	}
+
+	return sigencoding.Convert(sig, kt, sigencoding.DER)
+}
+
+// VerifySignature verifies the signature read from signature of the message read from message, or of the digest of
+// the options.WithDigest option.
+func (s *SignerVerifier) VerifySignature(sig, message io.Reader, options ...signature.VerifyOption) error {
+	if err := s.verify(sig, message, newOpts(options)); err != nil {
+		return fmt.Errorf("sigstore verify signature: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SignerVerifier) verify(signatureReader, message io.Reader, o *opts) error {
+	pubBytes, kt, err := s.publicKey()
+	if err != nil {
+		return err
+	}
+
+	if err = s.checkKeyType(kt); err != nil {
+		return err
+	}
+
+	sig, err := io.ReadAll(signatureReader)
+	if err != nil {
+		return fmt.Errorf("read signature: %w", err)
+	}
+
+	if o.digest != nil {
+		return verifyDigest(pubBytes, kt, sig, o.digest)
+	}
+
+	msg, err := io.ReadAll(message)
+	if err != nil {
+		return fmt.Errorf("read message: %w", err)
+	}
+
+	kh, err := s.km.PubKeyBytesToHandle(pubBytes, kt)
+	if err != nil {
+		return fmt.Errorf("public key handle: %w", err)
+	}
+
+	if sig, err = sigencoding.ConvertToKeyType(sig, kt, sigencoding.DER); err != nil {
+		return err
+	}
+
+	return s.crypto.Verify(sig, msg, kh)
+}
+
+func verifyDigest(pubBytes []byte, kt kmsapi.KeyType, sig, digest []byte) error {
+	pub, err := jwksupport.PubKeyBytesToKey(pubBytes, kt)
+	if err != nil {
+		return fmt.Errorf("public key: %w", err)
+	}
+
+	switch pub := pub.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(pub, digest, sig) {
+			return errors.New("invalid signature")
+		}
+
+		return nil
+	case *rsa.PublicKey:
+		if pss, ok := signerOpts(kt).(*rsa.PSSOptions); ok {
+			return rsa.VerifyPSS(pub, pss.Hash, digest, sig, pss)
+		}
+
+		return rsa.VerifyPKCS1v15(pub, keyTypeHashes[kt], digest, sig)
+	default:
+		return fmt.Errorf("key type '%s' doesn't sign digests", kt)
+	}
+}
+
+// PublicKey returns the public key of the key: an *ecdsa.PublicKey, an *rsa.PublicKey or an ed25519.PublicKey.
+func (s *SignerVerifier) PublicKey(_ ...signature.PublicKeyOption) (crypto.PublicKey, error) {
+	pub, err := s.cryptoPublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("sigstore public key: %w", err)
+	}
+
+	return pub, nil
+}
+
+func (s *SignerVerifier) cryptoPublicKey() (crypto.PublicKey, error) {
+	pubBytes, kt, err := s.publicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := keyTypeHashes[kt]; !ok {
+		return nil, fmt.Errorf("key type '%s' is not supported", kt)
+	}
+
+	return jwksupport.PubKeyBytesToKey(pubBytes, kt)
+}
+
+// CreateKey creates the key of the key reference with the signing algorithm algorithm, unless it exists with the key
+// type of algorithm, and returns its public key.
+func (s *SignerVerifier) CreateKey(_ context.Context, algorithm string) (crypto.PublicKey, error) {
+	kt, err := algorithmKeyType(algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("sigstore create key: %w", err)
+	}
+
+	if _, _, err = s.km.Create(kt, kmsapi.WithCreateKeyID(s.keyID), kmsapi.WithReuseExistingKey()); err != nil {
+		return nil, fmt.Errorf("sigstore create key: %w", err)
+	}
+
+	pub, err := s.cryptoPublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("sigstore create key: %w", err)
+	}
+
+	return pub, nil
+}
+
+// CryptoSigner returns the crypto.Signer of the key and its SignerOpts, eg: to sign the certificate requests of
+// Fulcio. errFunc is never called, the Signer returns its errors.
+func (s *SignerVerifier) CryptoSigner(_ context.Context, _ func(error)) (crypto.Signer, crypto.SignerOpts, error) {
+	_, kt, err := s.publicKey()
+	if err != nil {
+		return nil, nil, fmt.Errorf("sigstore crypto signer: %w", err)
+	}
+
+	if err = s.checkKeyType(kt); err != nil {
+		return nil, nil, fmt.Errorf("sigstore crypto signer: %w", err)
+	}
+
+	sig, err := signer.New(s.km, s.keyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sigstore crypto signer: %w", err)
+	}
+
+	return sig, signerOpts(kt), nil
+}
+
+// SupportedAlgorithms returns the signing algorithms of CreateKey.
+func (s *SignerVerifier) SupportedAlgorithms() []string {
+	names := make([]string, 0, len(algorithms))
+
+	for _, a := range algorithms {
+		names = append(names, a.name)
+	}
+
+	return names
+}
+
+// DefaultAlgorithm returns the default signing algorithm of CreateKey, AlgorithmECDSAP256.
+func (s *SignerVerifier) DefaultAlgorithm() string {
+	return algorithms[0].name
+}
+
+func algorithmKeyType(name string) (kmsapi.KeyType, error) {
+	for _, a := range algorithms {
+		if a.name == name {
+			return a.keyType, nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported algorithm '%s'", name)
+}
+
+func (s *SignerVerifier) publicKey() ([]byte, kmsapi.KeyType, error) {
+	pubBytes, kt, err := s.km.ExportPubKeyBytes(s.keyID)
+	if err != nil {
+		return nil, "", fmt.Errorf("export public key: %w", err)
+	}
+
+	return pubBytes, kt, nil
+}
+
+// checkKeyType checks kt is a signing key type of the provider, using the requested hash function.
+func (s *SignerVerifier) checkKeyType(kt kmsapi.KeyType) error {
+	h, ok := keyTypeHashes[kt]
+	if !ok {
+		return fmt.Errorf("key type '%s' is not supported", kt)
+	}
+
+	if s.hashFunc != 0 && s.hashFunc != h {
+		return fmt.Errorf("hash function %s doesn't match the hash function %s of the key", s.hashFunc, h)
+	}
+
+	return nil
+}
+
+func signerOpts(kt kmsapi.KeyType) crypto.SignerOpts {
+	if kt == kmsapi.RSAPS256Type {
+		return &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA256}
+	}
+
+	return keyTypeHashes[kt]
+}
@@ -0,0 +1,227 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sigstore_test
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+	"testing"
+
+	sigstorekms "github.com/sigstore/sigstore/pkg/signature/kms"
+	"github.com/sigstore/sigstore/pkg/signature/options"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/kms/sigstore"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+const payload = `{"critical":{"identity":{"docker-reference":"registry.example.com/app"},` +
+	`"type":"cosign container image signature"}}`
+
+func TestSignVerify(t *testing.T) {
+	km := mockkms.NewForTest(t)
+	c := newCrypto(t)
+
+	for _, alg := range []string{
+		sigstore.AlgorithmECDSAP256, sigstore.AlgorithmECDSAP384, sigstore.AlgorithmECDSAP521,
+		sigstore.AlgorithmRSAPKCS1, sigstore.AlgorithmRSAPSS, sigstore.AlgorithmED25519,
+	} {
+		t.Run(alg, func(t *testing.T) {
+			sv, err := sigstore.LoadSignerVerifier(km, c, "kms-go://"+alg, 0)
+			require.NoError(t, err)
+
+			pub, err := sv.CreateKey(context.Background(), alg)
+			require.NoError(t, err)
+
+			again, err := sv.CreateKey(context.Background(), alg)
+			require.NoError(t, err)
+			require.Equal(t, pub, again)
+
+			exported, err := sv.PublicKey()
+			require.NoError(t, err)
+			require.Equal(t, pub, exported)
+
+			sig, err := sv.SignMessage(strings.NewReader(payload))
+			require.NoError(t, err)
+			require.NoError(t, sv.VerifySignature(bytes.NewReader(sig), strings.NewReader(payload)))
+			require.Error(t, sv.VerifySignature(bytes.NewReader(sig), strings.NewReader(payload+" ")))
+
+			cs, opts, err := sv.CryptoSigner(context.Background(), nil)
+			require.NoError(t, err)
+			require.Equal(t, pub, cs.Public())
+
+			digest := []byte(payload)
+
+			if h := opts.HashFunc(); h != 0 {
+				hh := h.New()
+				hh.Write([]byte(payload))
+				digest = hh.Sum(nil)
+			}
+
+			verifyStdlib(t, pub, opts, digest, sig)
+
+			sig, err = cs.Sign(rand.Reader, digest, opts)
+			require.NoError(t, err)
+			require.NoError(t, sv.VerifySignature(bytes.NewReader(sig), strings.NewReader(payload)))
+
+			if alg == sigstore.AlgorithmED25519 {
+				_, err = sv.SignMessage(nil, options.WithDigest(digest))
+				require.EqualError(t, err, "sigstore sign message: key type 'ED25519' doesn't sign digests")
+
+				err = sv.VerifySignature(bytes.NewReader(sig), nil, options.WithDigest(digest))
+				require.EqualError(t, err, "sigstore verify signature: key type 'ED25519' doesn't sign digests")
+
+				return
+			}
+
+			sig, err = sv.SignMessage(nil, options.WithDigest(digest))
+			require.NoError(t, err)
+			require.NoError(t, sv.VerifySignature(bytes.NewReader(sig), nil, options.WithDigest(digest)))
+			require.NoError(t, sv.VerifySignature(bytes.NewReader(sig), strings.NewReader(payload)))
+			verifyStdlib(t, pub, opts, digest, sig)
+
+			digest[0] ^= 1
+			require.Error(t, sv.VerifySignature(bytes.NewReader(sig), nil, options.WithDigest(digest)))
+		})
+	}
+}
+
+func TestRegisterProvider(t *testing.T) {
+	km := mockkms.NewForTest(t)
+
+	sigstore.RegisterProvider(km, newCrypto(t))
+	require.Contains(t, sigstorekms.SupportedProviders(), sigstore.ReferenceScheme)
+
+	sv, err := sigstorekms.Get(context.Background(), "kms-go://registered", crypto.SHA256)
+	require.NoError(t, err)
+
+	_, err = sv.CreateKey(context.Background(), sv.DefaultAlgorithm())
+	require.NoError(t, err)
+
+	sig, err := sv.SignMessage(strings.NewReader(payload))
+	require.NoError(t, err)
+	require.NoError(t, sv.VerifySignature(bytes.NewReader(sig), strings.NewReader(payload)))
+
+	_, err = sigstorekms.Get(context.Background(), "kms-go://", crypto.SHA256)
+	require.EqualError(t, err,
+		"sigstore load signer verifier: invalid kms-go key reference 'kms-go://', expected kms-go://<key ID>")
+}
+
+func TestIEEEP1363Key(t *testing.T) {
+	km := mockkms.NewForTest(t)
+
+	_, _, err := km.Create(kmsapi.ECDSAP256TypeIEEEP1363, kmsapi.WithCreateKeyID("p1363"))
+	require.NoError(t, err)
+
+	sv, err := sigstore.LoadSignerVerifier(km, newCrypto(t), "kms-go://p1363", crypto.SHA256)
+	require.NoError(t, err)
+
+	sig, err := sv.SignMessage(strings.NewReader(payload))
+	require.NoError(t, err)
+	require.NoError(t, sv.VerifySignature(bytes.NewReader(sig), strings.NewReader(payload)))
+
+	pub, err := sv.PublicKey()
+	require.NoError(t, err)
+
+	digest := crypto.SHA256.New()
+	digest.Write([]byte(payload))
+	require.True(t, ecdsa.VerifyASN1(pub.(*ecdsa.PublicKey), digest.Sum(nil), sig))
+}
+
+func TestErrors(t *testing.T) {
+	km := mockkms.NewForTest(t)
+	c := newCrypto(t)
+
+	for _, ref := range []string{"kms-go://", "awskms:///key", "key"} {
+		require.ErrorContains(t, sigstore.ValidReference(ref), "invalid kms-go key reference")
+
+		_, err := sigstore.LoadSignerVerifier(km, c, ref, 0)
+		require.ErrorContains(t, err, "sigstore load signer verifier: invalid kms-go key reference")
+	}
+
+	require.NoError(t, sigstore.ValidReference("kms-go://key"))
+
+	sv, err := sigstore.LoadSignerVerifier(km, c, "kms-go://key", crypto.SHA512)
+	require.NoError(t, err)
+
+	require.Equal(t, sigstore.AlgorithmECDSAP256, sv.DefaultAlgorithm())
+	require.Contains(t, sv.SupportedAlgorithms(), sigstore.AlgorithmRSAPSS)
+
+	_, err = sv.SignMessage(strings.NewReader(payload))
+	require.ErrorContains(t, err, "sigstore sign message: export public key:")
+
+	_, err = sv.PublicKey()
+	require.ErrorContains(t, err, "sigstore public key: export public key:")
+
+	_, _, err = sv.CryptoSigner(context.Background(), nil)
+	require.ErrorContains(t, err, "sigstore crypto signer: export public key:")
+
+	_, err = sv.CreateKey(context.Background(), "hmac-sha256")
+	require.EqualError(t, err, "sigstore create key: unsupported algorithm 'hmac-sha256'")
+
+	_, err = sv.CreateKey(context.Background(), sigstore.AlgorithmECDSAP256)
+	require.NoError(t, err)
+
+	_, err = sv.CreateKey(context.Background(), sigstore.AlgorithmED25519)
+	require.ErrorContains(t, err, "sigstore create key:")
+
+	const mismatch = "hash function SHA-512 doesn't match the hash function SHA-256 of the key"
+
+	_, err = sv.SignMessage(strings.NewReader(payload))
+	require.EqualError(t, err, "sigstore sign message: "+mismatch)
+
+	err = sv.VerifySignature(strings.NewReader("sig"), strings.NewReader(payload))
+	require.EqualError(t, err, "sigstore verify signature: "+mismatch)
+
+	_, _, err = sv.CryptoSigner(context.Background(), nil)
+	require.EqualError(t, err, "sigstore crypto signer: "+mismatch)
+
+	_, _, err = km.Create(kmsapi.BLS12381G2Type, kmsapi.WithCreateKeyID("bbs"))
+	require.NoError(t, err)
+
+	sv, err = sigstore.LoadSignerVerifier(km, c, "kms-go://bbs", 0)
+	require.NoError(t, err)
+
+	_, err = sv.PublicKey()
+	require.EqualError(t, err, "sigstore public key: key type 'BLS12381G2' is not supported")
+}
+
+func verifyStdlib(t *testing.T, pub crypto.PublicKey, opts crypto.SignerOpts, digest, sig []byte) {
+	t.Helper()
+
+	switch pub := pub.(type) {
+	case *ecdsa.PublicKey:
+		require.True(t, ecdsa.VerifyASN1(pub, digest, sig))
+	case *rsa.PublicKey:
+		if pss, ok := opts.(*rsa.PSSOptions); ok {
+			require.NoError(t, rsa.VerifyPSS(pub, pss.Hash, digest, sig, pss))
+		} else {
+			require.NoError(t, rsa.VerifyPKCS1v15(pub, opts.HashFunc(), digest, sig))
+		}
+	case ed25519.PublicKey:
+		require.True(t, ed25519.Verify(pub, digest, sig))
+	default:
+		t.Fatalf("unexpected public key %T", pub)
+	}
+}
+
+func newCrypto(t *testing.T) *tinkcrypto.Crypto {
+	t.Helper()
+
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	return c
+}
@@ -0,0 +1,213 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package file provides a KMS store in a local directory, for the keystores of command line tools (see cmd/kmscli)
+// or of single host LocalKMS deployments.
+//
+// Each keyset is stored, with its metadata, in its own file of the directory, named after the base64url encoded key
+// ID so any key ID is a valid file name. The keysets are written to a temporary file first: a keyset file is replaced
+// by a rename, and new keysets are created with a hard link, so two LocalKMS instances sharing the directory can't
+// create a keyset with the same key ID.
+package file
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/kms"
+)
+
+const (
+	fileSuffix = ".json"
+	tmpPattern = ".tmp-*"
+	dirPerm    = 0o700
+)
+
+var (
+	_ kmsapi.Store             = (*Store)(nil)
+	_ kmsapi.StoreWithMetadata = (*Store)(nil)
+	_ kmsapi.StoreCreator      = (*Store)(nil)
+	_ kmsapi.StoreLister       = (*Store)(nil)
+)
+
+// record is the stored value of a keyset.
+type record struct {
+	Key      []byte         `json:"k"`
+	Metadata map[string]any `json:"m,omitempty"`
+}
+
+// Store is a KMS store in a local directory.
+type Store struct {
+	dir string
+}
+
+// New returns a Store of the keysets of the directory dir, dir is created if it doesn't exist.
+func New(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return nil, fmt.Errorf("new file store: %w", err)
+	}
+
+	return &Store{dir: dir}, nil
+}
+
+// Put stores key under keysetID, replacing the stored key if any.
+func (s *Store) Put(keysetID string, key []byte) error {
+	return s.PutWithMetadata(keysetID, key, nil)
+}
+
+// PutWithMetadata stores key and metadata under keysetID, replacing the stored key if any.
+func (s *Store) PutWithMetadata(keysetID string, key []byte, metadata map[string]any) error {
+	tmp, err := s.writeTemp(key, metadata)
+	if err != nil {
+		return fmt.Errorf("put key '%s': %w", keysetID, err)
+	}
+
+	if err = os.Rename(tmp, s.path(keysetID)); err != nil {
+		_ = os.Remove(tmp) //nolint:errcheck // the rename error is returned
+
+		return fmt.Errorf("put key '%s': %w", keysetID, err)
+	}
+
+	return nil
+}
+
+// Create stores key and metadata under keysetID if no key is stored under it, otherwise it returns an error wrapping
+// kms.ErrKeyExists.
+func (s *Store) Create(keysetID string, key []byte, metadata map[string]any) error {
+	tmp, err := s.writeTemp(key, metadata)
+	if err != nil {
+		return fmt.Errorf("create key '%s': %w", keysetID, err)
+	}
+
+	defer os.Remove(tmp) //nolint:errcheck // the keyset file is linked to the temporary file
+
+	err = os.Link(tmp, s.path(keysetID))
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("create key '%s': %w", keysetID, kms.ErrKeyExists)
+	}
+
+	if err != nil {
+		return fmt.Errorf("create key '%s': %w", keysetID, err)
+	}
+
+	return nil
+}
+
+// Get returns the key stored under keysetID, or an error wrapping kms.ErrKeyNotFound.
+func (s *Store) Get(keysetID string) ([]byte, error) {
+	r, err := s.get(keysetID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Key, nil
+}
+
+// GetWithMetadata returns the key and metadata stored under keysetID, or an error wrapping kms.ErrKeyNotFound.
+func (s *Store) GetWithMetadata(keysetID string) ([]byte, map[string]any, error) {
+	r, err := s.get(keysetID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return r.Key, r.Metadata, nil
+}
+
+func (s *Store) get(keysetID string) (*record, error) {
+	v, err := os.ReadFile(s.path(keysetID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("get key '%s': %w", keysetID, kms.ErrKeyNotFound)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get key '%s': %w", keysetID, err)
+	}
+
+	r := &record{}
+
+	if err = json.Unmarshal(v, r); err != nil {
+		return nil, fmt.Errorf("get key '%s': %w", keysetID, err)
+	}
+
+	return r, nil
+}
+
+// Delete deletes the key stored under keysetID, if any.
+func (s *Store) Delete(keysetID string) error {
+	if err := os.Remove(s.path(keysetID)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("delete key '%s': %w", keysetID, err)
+	}
+
+	return nil
+}
+
+// KeyIDs returns the IDs of all the keys in the store.
+func (s *Store) KeyIDs() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("list key IDs: %w", err)
+	}
+
+	var keyIDs []string
+
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), fileSuffix)
+		if !ok || !e.Type().IsRegular() {
+			continue
+		}
+
+		keyID, e := base64.RawURLEncoding.DecodeString(name)
+		if e != nil {
+			// not a keyset file.
+			continue
+		}
+
+		keyIDs = append(keyIDs, string(keyID))
+	}
+
+	return keyIDs, nil
+}
+
+func (s *Store) path(keysetID string) string {
+	return filepath.Join(s.dir, base64.RawURLEncoding.EncodeToString([]byte(keysetID))+fileSuffix)
+}
+
+// writeTemp writes the record of key and metadata to a new temporary file of the directory, and returns its path.
+func (s *Store) writeTemp(key []byte, metadata map[string]any) (string, error) {
+	v, err := json.Marshal(&record{Key: key, Metadata: metadata})
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.CreateTemp(s.dir, tmpPattern)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = f.Write(v); err == nil {
+		err = f.Sync()
+	}
+
+	if e := f.Close(); err == nil {
+		err = e
+	}
+
+	if err != nil {
+		_ = os.Remove(f.Name()) //nolint:errcheck // the write error is returned
+
+		return "", err
+	}
+
+	return f.Name(), nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package file
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/kms"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func TestStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "keys")
+
+	s, err := New(dir)
+	require.NoError(t, err)
+
+	t.Run("put, get and delete", func(t *testing.T) {
+		require.NoError(t, s.Put("did:example:123#key-1", []byte("keyset")))
+
+		key, err := s.Get("did:example:123#key-1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("keyset"), key)
+
+		require.NoError(t, s.PutWithMetadata("did:example:123#key-1", []byte("rotated"), map[string]any{"k": "v"}))
+
+		key, metadata, err := s.GetWithMetadata("did:example:123#key-1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("rotated"), key)
+		require.Equal(t, map[string]any{"k": "v"}, metadata)
+
+		require.NoError(t, s.Delete("did:example:123#key-1"))
+		require.NoError(t, s.Delete("did:example:123#key-1"))
+
+		_, err = s.Get("did:example:123#key-1")
+		require.ErrorIs(t, err, kms.ErrKeyNotFound)
+
+		_, _, err = s.GetWithMetadata("did:example:123#key-1")
+		require.ErrorIs(t, err, kms.ErrKeyNotFound)
+	})
+
+	t.Run("create", func(t *testing.T) {
+		require.NoError(t, s.Create("new", []byte("keyset"), nil))
+
+		err := s.Create("new", []byte("other keyset"), nil)
+		require.ErrorIs(t, err, kms.ErrKeyExists)
+
+		key, err := s.Get("new")
+		require.NoError(t, err)
+		require.Equal(t, []byte("keyset"), key)
+	})
+
+	t.Run("concurrent create", func(t *testing.T) {
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			created int
+		)
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				err := s.Create("race", []byte("keyset"), nil)
+				if err == nil {
+					mu.Lock()
+					created++
+					mu.Unlock()
+
+					return
+				}
+
+				if !errors.Is(err, kms.ErrKeyExists) {
+					t.Error(err)
+				}
+			}()
+		}
+
+		wg.Wait()
+		require.Equal(t, 1, created)
+	})
+
+	t.Run("key IDs", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a keyset"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "!.json"), []byte("not a keyset"), 0o600))
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "a2lk.json"), 0o700))
+
+		ids, err := s.KeyIDs()
+		require.NoError(t, err)
+
+		sort.Strings(ids)
+		require.Equal(t, []string{"new", "race"}, ids)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 5, "temporary files must be removed")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(s.path("corrupted"), []byte("{"), 0o600))
+
+		_, err := s.Get("corrupted")
+		require.ErrorContains(t, err, "get key 'corrupted':")
+
+		_, err = New(filepath.Join(dir, "notes.txt"))
+		require.ErrorContains(t, err, "new file store:")
+
+		missing := &Store{dir: filepath.Join(dir, "missing")}
+
+		require.ErrorContains(t, missing.Put("kid", nil), "put key 'kid':")
+		require.ErrorContains(t, missing.Create("kid", nil, nil), "create key 'kid':")
+
+		_, err = missing.KeyIDs()
+		require.ErrorContains(t, err, "list key IDs:")
+	})
+}
+
+func TestLocalKMS(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := New(dir)
+	require.NoError(t, err)
+
+	keyID, _, err := mockkms.NewForTest(t, mockkms.WithStore(s)).Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	s, err = New(dir)
+	require.NoError(t, err)
+
+	pub, kt, err := mockkms.NewForTest(t, mockkms.WithStore(s)).ExportPubKeyBytes(keyID)
+	require.NoError(t, err)
+	require.Equal(t, kmsapi.ED25519Type, kt)
+	require.Len(t, pub, 32)
+
+	ids, err := s.KeyIDs()
+	require.NoError(t, err)
+	require.Equal(t, []string{keyID}, ids)
+}
@@ -0,0 +1,337 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package postgres provides a PostgreSQL KMS store, for LocalKMS deployments sharing their keys between server
+// instances.
+//
+// The store uses a database/sql connection pool: any PostgreSQL driver (eg: github.com/jackc/pgx/v5/stdlib or
+// github.com/lib/pq) can be used, and the pool is configured with the sql.DB settings (SetMaxOpenConns,
+// SetConnMaxLifetime...). Keysets are stored in a single table, created by Migrate:
+//
+//	CREATE TABLE IF NOT EXISTS kmsdb (
+//		key TEXT PRIMARY KEY,
+//		value BYTEA NOT NULL,
+//		metadata BYTEA,
+//		version BIGINT NOT NULL DEFAULT 1
+//	)
+//
+// New keysets are created with kms.StoreCreator, atomically: two LocalKMS instances can't create a keyset with the
+// same key ID. The version column is incremented by every update of a keyset: with kms.StoreVersioner, a keyset is
+// only updated if its version is the one it was read with, otherwise PutWithVersion fails with a
+// *kms.VersionConflictError.
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/kms"
+)
+
+var (
+	_ kmsapi.Store             = (*Store)(nil)
+	_ kmsapi.StoreWithMetadata = (*Store)(nil)
+	_ kmsapi.StoreCreator      = (*Store)(nil)
+	_ kmsapi.StoreLister       = (*Store)(nil)
+	_ kmsapi.StoreVersioner    = (*Store)(nil)
+)
+
+var tableName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Store is a KMS store in a PostgreSQL table.
+type Store struct {
+	db     *sql.DB
+	table  string
+	prefix string
+}
+
+// Opt is a Store option.
+type Opt func(s *Store)
+
+// WithTable sets the name of the table of the keysets, kms.AriesWrapperStoreName by default.
+func WithTable(name string) Opt {
+	return func(s *Store) {
+		s.table = name
+	}
+}
+
+// WithKeyPrefix sets the prefix of the key IDs in the table, eg: to share a table between KMS instances.
+func WithKeyPrefix(prefix string) Opt {
+	return func(s *Store) {
+		s.prefix = prefix
+	}
+}
+
+// New returns a Store using the connection pool db.
+func New(db *sql.DB, opts ...Opt) (*Store, error) {
+	s := &Store{db: db, table: kms.AriesWrapperStoreName}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if !tableName.MatchString(s.table) {
+		return nil, fmt.Errorf("new postgres store: invalid table name '%s'", s.table)
+	}
+
+	return s, nil
+}
+
+// Migrate creates the table of the keysets if it doesn't exist.
+func (s *Store) Migrate() error {
+	_, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS ` + s.table + ` (
+	key TEXT PRIMARY KEY,
+	value BYTEA NOT NULL,
+	metadata BYTEA,
+	version BIGINT NOT NULL DEFAULT 1
+)`)
+	if err != nil {
+		return fmt.Errorf("migrate postgres store: %w", err)
+	}
+
+	return nil
+}
+
+// MigrateKeyPrefix moves the keysets stored with the key ID prefix oldPrefix (eg: "" for a table shared before
+// WithKeyPrefix was set) to the prefix of s. It returns the number of moved keysets.
+func (s *Store) MigrateKeyPrefix(oldPrefix string) (int64, error) {
+	res, err := s.db.Exec(`UPDATE `+s.table+` SET key = $2 || substr(key, length($1) + 1), version = version + 1
+WHERE left(key, length($1)) = $1 AND ($2 = '' OR left(key, length($2)) <> $2)`, oldPrefix, s.prefix)
+	if err != nil {
+		return 0, fmt.Errorf("migrate key prefix: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("migrate key prefix: %w", err)
+	}
+
+	return n, nil
+}
+
+// Put stores key under keysetID, replacing the stored key if any.
+func (s *Store) Put(keysetID string, key []byte) error {
+	return s.PutWithMetadata(keysetID, key, nil)
+}
+
+// PutWithMetadata stores key and metadata under keysetID, replacing the stored key if any.
+func (s *Store) PutWithMetadata(keysetID string, key []byte, metadata map[string]any) error {
+	m, err := marshalMetadata(metadata)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`INSERT INTO `+s.table+` (key, value, metadata) VALUES ($1, $2, $3)
+ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, metadata = EXCLUDED.metadata, version = `+s.table+
+		`.version + 1`, s.prefix+keysetID, key, m)
+	if err != nil {
+		return fmt.Errorf("put key '%s': %w", keysetID, err)
+	}
+
+	return nil
+}
+
+// PutWithVersion stores key and metadata under keysetID if the version of the stored key is expectedVersion, or if no
+// key is stored and expectedVersion is 0. Otherwise, the returned error wraps a *kms.VersionConflictError.
+func (s *Store) PutWithVersion(keysetID string, key []byte, metadata map[string]any, expectedVersion int64) error {
+	m, err := marshalMetadata(metadata)
+	if err != nil {
+		return err
+	}
+
+	var res sql.Result
+
+	if expectedVersion == 0 {
+		res, err = s.db.Exec(`INSERT INTO `+s.table+` (key, value, metadata) VALUES ($1, $2, $3)
+ON CONFLICT (key) DO NOTHING`, s.prefix+keysetID, key, m)
+	} else {
+		res, err = s.db.Exec(`UPDATE `+s.table+` SET value = $2, metadata = $3, version = version + 1
+WHERE key = $1 AND version = $4`, s.prefix+keysetID, key, m, expectedVersion)
+	}
+
+	if err != nil {
+		return fmt.Errorf("put key '%s': %w", keysetID, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("put key '%s': %w", keysetID, err)
+	}
+
+	if n == 0 {
+		return s.versionConflict(keysetID, expectedVersion)
+	}
+
+	return nil
+}
+
+// versionConflict returns the error of a PutWithVersion call that updated no keyset, with the stored version.
+func (s *Store) versionConflict(keysetID string, expectedVersion int64) error {
+	var version int64
+
+	err := s.db.QueryRow(`SELECT version FROM `+s.table+` WHERE key = $1`, s.prefix+keysetID).Scan(&version)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("put key '%s': %w", keysetID, err)
+	}
+
+	return fmt.Errorf("put key '%s': %w", keysetID,
+		&kms.VersionConflictError{Expected: expectedVersion, Actual: version})
+}
+
+// Create stores key and metadata under keysetID if no key is stored under it, otherwise it returns an error wrapping
+// kms.ErrKeyExists.
+func (s *Store) Create(keysetID string, key []byte, metadata map[string]any) error {
+	m, err := marshalMetadata(metadata)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.db.Exec(`INSERT INTO `+s.table+` (key, value, metadata) VALUES ($1, $2, $3)
+ON CONFLICT (key) DO NOTHING`, s.prefix+keysetID, key, m)
+	if err != nil {
+		return fmt.Errorf("create key '%s': %w", keysetID, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("create key '%s': %w", keysetID, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("create key '%s': %w", keysetID, kms.ErrKeyExists)
+	}
+
+	return nil
+}
+
+// Get returns the key stored under keysetID, or an error wrapping kms.ErrKeyNotFound.
+func (s *Store) Get(keysetID string) ([]byte, error) {
+	var key []byte
+
+	err := s.db.QueryRow(`SELECT value FROM `+s.table+` WHERE key = $1`, s.prefix+keysetID).Scan(&key)
+	if err != nil {
+		return nil, getError(keysetID, err)
+	}
+
+	return key, nil
+}
+
+// GetWithMetadata returns the key and metadata stored under keysetID, or an error wrapping kms.ErrKeyNotFound.
+func (s *Store) GetWithMetadata(keysetID string) ([]byte, map[string]any, error) {
+	var key, m []byte
+
+	err := s.db.QueryRow(`SELECT value, metadata FROM `+s.table+` WHERE key = $1`, s.prefix+keysetID).Scan(&key, &m)
+	if err != nil {
+		return nil, nil, getError(keysetID, err)
+	}
+
+	metadata, err := unmarshalMetadata(keysetID, m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, metadata, nil
+}
+
+// GetWithVersion returns the key, metadata and version stored under keysetID, or an error wrapping
+// kms.ErrKeyNotFound.
+func (s *Store) GetWithVersion(keysetID string) ([]byte, map[string]any, int64, error) {
+	var (
+		key, m  []byte
+		version int64
+	)
+
+	err := s.db.QueryRow(`SELECT value, metadata, version FROM `+s.table+` WHERE key = $1`, s.prefix+keysetID).
+		Scan(&key, &m, &version)
+	if err != nil {
+		return nil, nil, 0, getError(keysetID, err)
+	}
+
+	metadata, err := unmarshalMetadata(keysetID, m)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return key, metadata, version, nil
+}
+
+// Delete deletes the key stored under keysetID, if any.
+func (s *Store) Delete(keysetID string) error {
+	if _, err := s.db.Exec(`DELETE FROM `+s.table+` WHERE key = $1`, s.prefix+keysetID); err != nil {
+		return fmt.Errorf("delete key '%s': %w", keysetID, err)
+	}
+
+	return nil
+}
+
+// KeyIDs returns the IDs of all the keys in the store.
+func (s *Store) KeyIDs() ([]string, error) {
+	rows, err := s.db.Query(`SELECT substr(key, length($1) + 1) FROM `+s.table+` WHERE left(key, length($1)) = $1`,
+		s.prefix)
+	if err != nil {
+		return nil, fmt.Errorf("list key IDs: %w", err)
+	}
+
+	defer rows.Close() //nolint:errcheck // the rows error is checked below
+
+	var keyIDs []string
+
+	for rows.Next() {
+		var keyID string
+
+		if err = rows.Scan(&keyID); err != nil {
+			return nil, fmt.Errorf("list key IDs: %w", err)
+		}
+
+		keyIDs = append(keyIDs, keyID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("list key IDs: %w", err)
+	}
+
+	return keyIDs, nil
+}
+
+func marshalMetadata(metadata map[string]any) ([]byte, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+
+	m, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("marshal metadata: %w", err)
+	}
+
+	return m, nil
+}
+
+func unmarshalMetadata(keysetID string, m []byte) (map[string]any, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+
+	var metadata map[string]any
+
+	if err := json.Unmarshal(m, &metadata); err != nil {
+		return nil, fmt.Errorf("get key '%s': metadata: %w", keysetID, err)
+	}
+
+	return metadata, nil
+}
+
+func getError(keysetID string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("get key '%s': %w", keysetID, kms.ErrKeyNotFound)
+	}
+
+	return fmt.Errorf("get key '%s': %w", keysetID, err)
+}
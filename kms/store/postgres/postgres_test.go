@@ -0,0 +1,460 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/kms"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func TestStore(t *testing.T) {
+	db := &fakeDB{rows: map[string]*fakeRow{}}
+
+	s, err := New(sql.OpenDB(db), WithTable("keys"), WithKeyPrefix("issuer/"))
+	require.NoError(t, err)
+	require.NoError(t, s.Migrate())
+	require.Contains(t, db.queries[0], "CREATE TABLE IF NOT EXISTS keys (")
+
+	t.Run("put, get and delete", func(t *testing.T) {
+		require.NoError(t, s.Put("kid", []byte("keyset")))
+		require.Equal(t, int64(1), db.rows["issuer/kid"].version)
+
+		key, err := s.Get("kid")
+		require.NoError(t, err)
+		require.Equal(t, []byte("keyset"), key)
+
+		require.NoError(t, s.PutWithMetadata("kid", []byte("rotated"), map[string]any{"k": "v"}))
+		require.Equal(t, int64(2), db.rows["issuer/kid"].version)
+
+		key, metadata, err := s.GetWithMetadata("kid")
+		require.NoError(t, err)
+		require.Equal(t, []byte("rotated"), key)
+		require.Equal(t, map[string]any{"k": "v"}, metadata)
+
+		require.NoError(t, s.Delete("kid"))
+
+		_, err = s.Get("kid")
+		require.ErrorIs(t, err, kms.ErrKeyNotFound)
+
+		_, _, err = s.GetWithMetadata("kid")
+		require.ErrorIs(t, err, kms.ErrKeyNotFound)
+	})
+
+	t.Run("create", func(t *testing.T) {
+		require.NoError(t, s.Create("new", []byte("keyset"), nil))
+
+		err := s.Create("new", []byte("other keyset"), nil)
+		require.ErrorIs(t, err, kms.ErrKeyExists)
+
+		key, metadata, err := s.GetWithMetadata("new")
+		require.NoError(t, err)
+		require.Equal(t, []byte("keyset"), key)
+		require.Nil(t, metadata)
+	})
+
+	t.Run("put with version", func(t *testing.T) {
+		err := s.PutWithVersion("versioned", []byte("keyset"), nil, 1)
+		require.ErrorAs(t, err, new(*kms.VersionConflictError))
+		require.EqualError(t, err, "put key 'versioned': version conflict: expected version 1, stored version 0")
+
+		require.NoError(t, s.PutWithVersion("versioned", []byte("keyset"), nil, 0))
+
+		key, metadata, version, err := s.GetWithVersion("versioned")
+		require.NoError(t, err)
+		require.Equal(t, []byte("keyset"), key)
+		require.Nil(t, metadata)
+		require.Equal(t, int64(1), version)
+
+		require.NoError(t, s.PutWithVersion("versioned", []byte("rotated"), map[string]any{"k": "v"}, version))
+
+		err = s.PutWithVersion("versioned", []byte("stale"), nil, version)
+		require.EqualError(t, err, "put key 'versioned': version conflict: expected version 1, stored version 2")
+
+		err = s.PutWithVersion("versioned", []byte("stale"), nil, 0)
+		require.ErrorAs(t, err, new(*kms.VersionConflictError))
+
+		key, metadata, version, err = s.GetWithVersion("versioned")
+		require.NoError(t, err)
+		require.Equal(t, []byte("rotated"), key)
+		require.Equal(t, map[string]any{"k": "v"}, metadata)
+		require.Equal(t, int64(2), version)
+
+		require.NoError(t, s.Delete("versioned"))
+
+		_, _, _, err = s.GetWithVersion("versioned")
+		require.ErrorIs(t, err, kms.ErrKeyNotFound)
+	})
+
+	t.Run("concurrent writers", func(t *testing.T) {
+		require.NoError(t, s.Put("counter", []byte("0")))
+
+		incrementConcurrently(t, s, "counter", 8, 10)
+
+		key, err := s.Get("counter")
+		require.NoError(t, err)
+		require.Equal(t, []byte("80"), key)
+
+		require.NoError(t, s.Delete("counter"))
+	})
+
+	t.Run("key IDs and prefix migration", func(t *testing.T) {
+		db.rows["legacy1"] = &fakeRow{value: []byte("keyset1"), version: 1}
+		db.rows["legacy2"] = &fakeRow{value: []byte("keyset2"), version: 1}
+
+		ids, err := s.KeyIDs()
+		require.NoError(t, err)
+		require.Equal(t, []string{"new"}, ids)
+
+		n, err := s.MigrateKeyPrefix("")
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+
+		ids, err = s.KeyIDs()
+		require.NoError(t, err)
+		require.Equal(t, []string{"legacy1", "legacy2", "new"}, ids)
+
+		key, err := s.Get("legacy1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("keyset1"), key)
+	})
+
+	t.Run("LocalKMS store", func(t *testing.T) {
+		k := mockkms.NewForTest(t, mockkms.WithStore(s))
+
+		keyID, _, err := k.Create(kmsapi.ED25519Type, kmsapi.WithMetadata(map[string]any{"owner": "issuer"}))
+		require.NoError(t, err)
+
+		_, metadata, err := k.GetWithOpts(keyID, kmsapi.ExportWithMetadata(true))
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"owner": "issuer"}, metadata)
+
+		_, _, err = k.ImportPrivateKey(make([]byte, 32), kmsapi.AES256GCMType, kmsapi.WithKeyID(keyID))
+		require.ErrorContains(t, err, "already exists")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := New(sql.OpenDB(db), WithTable("keys; DROP TABLE keys"))
+		require.EqualError(t, err, "new postgres store: invalid table name 'keys; DROP TABLE keys'")
+
+		db.err = errors.New("connection refused")
+		defer func() { db.err = nil }()
+
+		require.ErrorContains(t, s.Migrate(), "migrate postgres store: connection refused")
+		require.ErrorContains(t, s.Put("kid", nil), "put key 'kid': connection refused")
+		require.ErrorContains(t, s.Create("kid", nil, nil), "create key 'kid': connection refused")
+		require.ErrorContains(t, s.Delete("kid"), "delete key 'kid': connection refused")
+
+		_, err = s.Get("kid")
+		require.ErrorContains(t, err, "get key 'kid': connection refused")
+
+		_, err = s.KeyIDs()
+		require.ErrorContains(t, err, "list key IDs: connection refused")
+
+		_, err = s.MigrateKeyPrefix("")
+		require.ErrorContains(t, err, "migrate key prefix: connection refused")
+
+		err = s.PutWithMetadata("kid", nil, map[string]any{"invalid": make(chan int)})
+		require.ErrorContains(t, err, "marshal metadata")
+
+		_, _, _, err = s.GetWithVersion("kid")
+		require.ErrorContains(t, err, "get key 'kid': connection refused")
+
+		require.ErrorContains(t, s.PutWithVersion("kid", nil, nil, 1), "put key 'kid': connection refused")
+
+		err = s.PutWithVersion("kid", nil, map[string]any{"invalid": make(chan int)}, 1)
+		require.ErrorContains(t, err, "marshal metadata")
+	})
+}
+
+// incrementConcurrently increments the counter stored under keysetID n times in each of the writers goroutines, with
+// the read, increment and PutWithVersion retries of optimistic concurrency.
+func incrementConcurrently(t *testing.T, s kmsapi.StoreVersioner, keysetID string, writers, n int) {
+	t.Helper()
+
+	var (
+		wg        sync.WaitGroup
+		conflicts atomic.Int64
+	)
+
+	errs := make(chan error, writers)
+
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < n; {
+				key, _, version, err := s.GetWithVersion(keysetID)
+				if err != nil {
+					errs <- err
+
+					return
+				}
+
+				counter, err := strconv.Atoi(string(key))
+				if err != nil {
+					errs <- err
+
+					return
+				}
+
+				// let the other writers update the counter between the read and the write.
+				runtime.Gosched()
+
+				err = s.PutWithVersion(keysetID, []byte(strconv.Itoa(counter+1)), nil, version)
+				if errors.As(err, new(*kms.VersionConflictError)) {
+					conflicts.Add(1)
+
+					continue
+				}
+
+				if err != nil {
+					errs <- err
+
+					return
+				}
+
+				j++
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	t.Logf("%d version conflicts", conflicts.Load())
+}
+
+type fakeRow struct {
+	value, metadata []byte
+	version         int64
+}
+
+// fakeDB is an in-memory database/sql connector interpreting the queries of Store, one query at a time.
+type fakeDB struct {
+	mu      sync.Mutex
+	rows    map[string]*fakeRow
+	queries []string
+	err     error
+}
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{db: db}, nil
+}
+
+func (db *fakeDB) Driver() driver.Driver {
+	return nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) { //nolint:gocyclo
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	s.db.queries = append(s.db.queries, s.query)
+
+	if s.db.err != nil {
+		return nil, s.db.err
+	}
+
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE"):
+		return driver.RowsAffected(0), nil
+	case strings.HasPrefix(s.query, "INSERT"):
+		key := args[0].(string)
+		value, _ := args[1].([]byte)
+		metadata, _ := args[2].([]byte)
+
+		row, exists := s.db.rows[key]
+
+		switch {
+		case !exists:
+			s.db.rows[key] = &fakeRow{value: value, metadata: metadata, version: 1}
+		case strings.Contains(s.query, "DO NOTHING"):
+			return driver.RowsAffected(0), nil
+		default:
+			row.value, row.metadata, row.version = value, metadata, row.version+1
+		}
+
+		return driver.RowsAffected(1), nil
+	case strings.HasPrefix(s.query, "UPDATE") && strings.Contains(s.query, "AND version = $4"):
+		row, exists := s.db.rows[args[0].(string)]
+		if !exists || row.version != args[3].(int64) {
+			return driver.RowsAffected(0), nil
+		}
+
+		row.value, _ = args[1].([]byte)
+		row.metadata, _ = args[2].([]byte)
+		row.version++
+
+		return driver.RowsAffected(1), nil
+	case strings.HasPrefix(s.query, "UPDATE"):
+		oldPrefix, newPrefix := args[0].(string), args[1].(string)
+
+		moved := map[string]*fakeRow{}
+
+		for key, row := range s.db.rows {
+			if strings.HasPrefix(key, oldPrefix) && (newPrefix == "" || !strings.HasPrefix(key, newPrefix)) {
+				delete(s.db.rows, key)
+				row.version++
+				moved[newPrefix+key[len(oldPrefix):]] = row
+			}
+		}
+
+		for key, row := range moved {
+			s.db.rows[key] = row
+		}
+
+		return driver.RowsAffected(len(moved)), nil
+	case strings.HasPrefix(s.query, "DELETE"):
+		delete(s.db.rows, args[0].(string))
+
+		return driver.RowsAffected(1), nil
+	default:
+		return nil, errors.New("unexpected query: " + s.query)
+	}
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) { //nolint:gocyclo
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	s.db.queries = append(s.db.queries, s.query)
+
+	if s.db.err != nil {
+		return nil, s.db.err
+	}
+
+	switch {
+	case strings.HasPrefix(s.query, "SELECT value, metadata, version"):
+		row, ok := s.db.rows[args[0].(string)]
+		if !ok {
+			return &fakeRows{}, nil
+		}
+
+		return &fakeRows{values: [][]driver.Value{{row.value, row.metadata, row.version}}}, nil
+	case strings.HasPrefix(s.query, "SELECT version"):
+		row, ok := s.db.rows[args[0].(string)]
+		if !ok {
+			return &fakeRows{}, nil
+		}
+
+		return &fakeRows{values: [][]driver.Value{{row.version}}}, nil
+	case strings.HasPrefix(s.query, "SELECT value, metadata"):
+		row, ok := s.db.rows[args[0].(string)]
+		if !ok {
+			return &fakeRows{}, nil
+		}
+
+		return &fakeRows{values: [][]driver.Value{{row.value, row.metadata}}}, nil
+	case strings.HasPrefix(s.query, "SELECT value"):
+		row, ok := s.db.rows[args[0].(string)]
+		if !ok {
+			return &fakeRows{}, nil
+		}
+
+		return &fakeRows{values: [][]driver.Value{{row.value}}}, nil
+	case strings.HasPrefix(s.query, "SELECT substr"):
+		prefix := args[0].(string)
+
+		var keys []string
+
+		for key := range s.db.rows {
+			if strings.HasPrefix(key, prefix) {
+				keys = append(keys, key[len(prefix):])
+			}
+		}
+
+		sort.Strings(keys)
+
+		rows := &fakeRows{}
+
+		for _, key := range keys {
+			rows.values = append(rows.values, []driver.Value{key})
+		}
+
+		return rows, nil
+	default:
+		return nil, errors.New("unexpected query: " + s.query)
+	}
+}
+
+type fakeRows struct {
+	values [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	if len(r.values) == 0 {
+		return []string{"value"}
+	}
+
+	return make([]string, len(r.values[0]))
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+
+	return nil
+}
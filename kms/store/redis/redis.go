@@ -0,0 +1,366 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package redis provides a Redis KMS store, for LocalKMS deployments sharing their keys between server instances.
+//
+// The store sends its commands with a Client, so it works with any Redis client library and its connection pool. For
+// example, with github.com/redis/go-redis:
+//
+//	type goRedisClient struct {
+//		*redis.Client
+//	}
+//
+//	func (c goRedisClient) Do(ctx context.Context, args ...any) (any, error) {
+//		v, err := c.Client.Do(ctx, args...).Result()
+//		if errors.Is(err, redis.Nil) {
+//			return nil, nil
+//		}
+//
+//		return v, err
+//	}
+//
+// Each keyset is stored, with its metadata and version, in a single string value: new keysets are created with SET NX,
+// so two LocalKMS instances can't create a keyset with the same key ID. The keysets are updated by a Lua script
+// incrementing their version: with kms.StoreVersioner, a keyset is only updated if its version is the one it was read
+// with, otherwise PutWithVersion fails with a *kms.VersionConflictError.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/kms"
+)
+
+// DefaultKeyPrefix is the default prefix of the Redis keys of the keysets.
+const DefaultKeyPrefix = kms.AriesWrapperStoreName + ":"
+
+const scanCount = 100
+
+// putScript stores the record ARGV[1], JSON without version, with the incremented version of the stored record if its
+// version is ARGV[2], or whatever its version if ARGV[2] is -1. It returns the version of the stored record, 0 if none.
+const putScript = `local v = redis.call('GET', KEYS[1])
+local version = 0
+if v then version = cjson.decode(v)['v'] or 1 end
+local expected = tonumber(ARGV[2])
+if expected >= 0 and version ~= expected then return version end
+redis.call('SET', KEYS[1], string.sub(ARGV[1], 1, -2) .. ',"v":' .. (version + 1) .. '}')
+return version`
+
+// anyVersion is the expected version of the unconditional updates of putScript.
+const anyVersion = -1
+
+var (
+	_ kmsapi.Store             = (*Store)(nil)
+	_ kmsapi.StoreWithMetadata = (*Store)(nil)
+	_ kmsapi.StoreCreator      = (*Store)(nil)
+	_ kmsapi.StoreLister       = (*Store)(nil)
+	_ kmsapi.StoreVersioner    = (*Store)(nil)
+)
+
+// Client sends a Redis command, eg: Do(ctx, "GET", key), and returns its reply. A nil reply must be returned as a
+// nil value with a nil error.
+type Client interface {
+	Do(ctx context.Context, args ...any) (any, error)
+}
+
+// record is the stored value of a keyset. The records stored without version, before the keyset versions, have the
+// version 1.
+type record struct {
+	Key      []byte         `json:"k"`
+	Metadata map[string]any `json:"m,omitempty"`
+	Version  int64          `json:"v,omitempty"`
+}
+
+// Store is a KMS store in a Redis database.
+type Store struct {
+	client Client
+	prefix string
+}
+
+// Opt is a Store option.
+type Opt func(s *Store)
+
+// WithKeyPrefix sets the prefix of the Redis keys of the keysets, DefaultKeyPrefix by default.
+func WithKeyPrefix(prefix string) Opt {
+	return func(s *Store) {
+		s.prefix = prefix
+	}
+}
+
+// New returns a Store sending its commands with client.
+func New(client Client, opts ...Opt) *Store {
+	s := &Store{client: client, prefix: DefaultKeyPrefix}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Put stores key under keysetID, replacing the stored key if any.
+func (s *Store) Put(keysetID string, key []byte) error {
+	return s.PutWithMetadata(keysetID, key, nil)
+}
+
+// PutWithMetadata stores key and metadata under keysetID, replacing the stored key if any.
+func (s *Store) PutWithMetadata(keysetID string, key []byte, metadata map[string]any) error {
+	if _, err := s.put(keysetID, key, metadata, anyVersion); err != nil {
+		return fmt.Errorf("put key '%s': %w", keysetID, err)
+	}
+
+	return nil
+}
+
+// PutWithVersion stores key and metadata under keysetID if the version of the stored key is expectedVersion, or if no
+// key is stored and expectedVersion is 0. Otherwise, the returned error wraps a *kms.VersionConflictError.
+func (s *Store) PutWithVersion(keysetID string, key []byte, metadata map[string]any, expectedVersion int64) error {
+	if expectedVersion < 0 {
+		return fmt.Errorf("put key '%s': invalid version %d", keysetID, expectedVersion)
+	}
+
+	version, err := s.put(keysetID, key, metadata, expectedVersion)
+	if err != nil {
+		return fmt.Errorf("put key '%s': %w", keysetID, err)
+	}
+
+	if version != expectedVersion {
+		return fmt.Errorf("put key '%s': %w", keysetID,
+			&kms.VersionConflictError{Expected: expectedVersion, Actual: version})
+	}
+
+	return nil
+}
+
+// put runs putScript and returns the version of the record stored before.
+func (s *Store) put(keysetID string, key []byte, metadata map[string]any, expectedVersion int64) (int64, error) {
+	v, err := json.Marshal(&record{Key: key, Metadata: metadata})
+	if err != nil {
+		return 0, err
+	}
+
+	reply, err := s.client.Do(context.Background(), "EVAL", putScript, 1, s.prefix+keysetID, v, expectedVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	version, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected reply type %T", reply)
+	}
+
+	return version, nil
+}
+
+// Create stores key and metadata under keysetID if no key is stored under it, otherwise it returns an error wrapping
+// kms.ErrKeyExists.
+func (s *Store) Create(keysetID string, key []byte, metadata map[string]any) error {
+	v, err := json.Marshal(&record{Key: key, Metadata: metadata, Version: 1})
+	if err != nil {
+		return fmt.Errorf("create key '%s': %w", keysetID, err)
+	}
+
+	reply, err := s.client.Do(context.Background(), "SET", s.prefix+keysetID, v, "NX")
+	if err != nil {
+		return fmt.Errorf("create key '%s': %w", keysetID, err)
+	}
+
+	if reply == nil {
+		return fmt.Errorf("create key '%s': %w", keysetID, kms.ErrKeyExists)
+	}
+
+	return nil
+}
+
+// Get returns the key stored under keysetID, or an error wrapping kms.ErrKeyNotFound.
+func (s *Store) Get(keysetID string) ([]byte, error) {
+	r, err := s.get(keysetID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Key, nil
+}
+
+// GetWithMetadata returns the key and metadata stored under keysetID, or an error wrapping kms.ErrKeyNotFound.
+func (s *Store) GetWithMetadata(keysetID string) ([]byte, map[string]any, error) {
+	r, err := s.get(keysetID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return r.Key, r.Metadata, nil
+}
+
+// GetWithVersion returns the key, metadata and version stored under keysetID, or an error wrapping
+// kms.ErrKeyNotFound.
+func (s *Store) GetWithVersion(keysetID string) ([]byte, map[string]any, int64, error) {
+	r, err := s.get(keysetID)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return r.Key, r.Metadata, r.Version, nil
+}
+
+func (s *Store) get(keysetID string) (*record, error) {
+	reply, err := s.client.Do(context.Background(), "GET", s.prefix+keysetID)
+	if err != nil {
+		return nil, fmt.Errorf("get key '%s': %w", keysetID, err)
+	}
+
+	if reply == nil {
+		return nil, fmt.Errorf("get key '%s': %w", keysetID, kms.ErrKeyNotFound)
+	}
+
+	v, err := replyBytes(reply)
+	if err != nil {
+		return nil, fmt.Errorf("get key '%s': %w", keysetID, err)
+	}
+
+	r := &record{Version: 1}
+
+	if err = json.Unmarshal(v, r); err != nil {
+		return nil, fmt.Errorf("get key '%s': %w", keysetID, err)
+	}
+
+	return r, nil
+}
+
+// Delete deletes the key stored under keysetID, if any.
+func (s *Store) Delete(keysetID string) error {
+	if _, err := s.client.Do(context.Background(), "DEL", s.prefix+keysetID); err != nil {
+		return fmt.Errorf("delete key '%s': %w", keysetID, err)
+	}
+
+	return nil
+}
+
+// KeyIDs returns the IDs of all the keys in the store.
+func (s *Store) KeyIDs() ([]string, error) {
+	keys, err := s.scan(s.prefix)
+	if err != nil {
+		return nil, fmt.Errorf("list key IDs: %w", err)
+	}
+
+	keyIDs := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		keyIDs = append(keyIDs, key[len(s.prefix):])
+	}
+
+	return keyIDs, nil
+}
+
+// MigrateKeyPrefix renames the Redis keys of the keysets stored with the prefix oldPrefix (eg: the prefix of an
+// existing deployment) to the prefix of s. It returns the number of renamed keysets, and fails on the first keyset
+// already stored with the prefix of s.
+func (s *Store) MigrateKeyPrefix(oldPrefix string) (int, error) {
+	keys, err := s.scan(oldPrefix)
+	if err != nil {
+		return 0, fmt.Errorf("migrate key prefix: %w", err)
+	}
+
+	n := 0
+
+	for _, key := range keys {
+		if s.prefix != "" && strings.HasPrefix(key, s.prefix) {
+			continue
+		}
+
+		newKey := s.prefix + key[len(oldPrefix):]
+
+		reply, err := s.client.Do(context.Background(), "RENAMENX", key, newKey)
+		if err != nil {
+			return n, fmt.Errorf("migrate key prefix: %w", err)
+		}
+
+		if renamed, ok := reply.(int64); !ok || renamed != 1 {
+			return n, fmt.Errorf("migrate key prefix: key '%s': %w", newKey, kms.ErrKeyExists)
+		}
+
+		n++
+	}
+
+	return n, nil
+}
+
+// scan returns the Redis keys starting with prefix.
+func (s *Store) scan(prefix string) ([]string, error) {
+	var (
+		keys   []string
+		cursor = "0"
+	)
+
+	for {
+		reply, err := s.client.Do(context.Background(), "SCAN", cursor, "MATCH", escapeGlob(prefix)+"*",
+			"COUNT", scanCount)
+		if err != nil {
+			return nil, err
+		}
+
+		page, ok := reply.([]any)
+		if !ok || len(page) != 2 { //nolint:gomnd // SCAN replies are [cursor, keys]
+			return nil, errors.New("invalid SCAN reply")
+		}
+
+		next, err := replyBytes(page[0])
+		if err != nil {
+			return nil, err
+		}
+
+		pageKeys, ok := page[1].([]any)
+		if !ok {
+			return nil, errors.New("invalid SCAN reply")
+		}
+
+		for _, k := range pageKeys {
+			key, err := replyBytes(k)
+			if err != nil {
+				return nil, err
+			}
+
+			keys = append(keys, string(key))
+		}
+
+		cursor = string(next)
+		if cursor == "0" {
+			return keys, nil
+		}
+	}
+}
+
+func replyBytes(reply any) ([]byte, error) {
+	switch v := reply.(type) {
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unexpected reply type %T", reply)
+	}
+}
+
+// escapeGlob escapes the glob-style pattern characters of a SCAN MATCH pattern.
+func escapeGlob(s string) string {
+	var sb strings.Builder
+
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\`, r) {
+			sb.WriteRune('\\')
+		}
+
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
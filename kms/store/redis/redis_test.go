@@ -0,0 +1,393 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/kms"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func TestStore(t *testing.T) {
+	client := &fakeClient{data: map[string]string{}}
+	s := New(client)
+
+	t.Run("put, get and delete", func(t *testing.T) {
+		require.NoError(t, s.Put("kid", []byte("keyset")))
+		require.Contains(t, client.data, "kmsdb:kid")
+
+		key, err := s.Get("kid")
+		require.NoError(t, err)
+		require.Equal(t, []byte("keyset"), key)
+
+		require.NoError(t, s.PutWithMetadata("kid", []byte("rotated"), map[string]any{"k": "v"}))
+
+		key, metadata, err := s.GetWithMetadata("kid")
+		require.NoError(t, err)
+		require.Equal(t, []byte("rotated"), key)
+		require.Equal(t, map[string]any{"k": "v"}, metadata)
+
+		require.NoError(t, s.Delete("kid"))
+
+		_, err = s.Get("kid")
+		require.ErrorIs(t, err, kms.ErrKeyNotFound)
+
+		_, _, err = s.GetWithMetadata("kid")
+		require.ErrorIs(t, err, kms.ErrKeyNotFound)
+	})
+
+	t.Run("create", func(t *testing.T) {
+		require.NoError(t, s.Create("new", []byte("keyset"), nil))
+
+		err := s.Create("new", []byte("other keyset"), nil)
+		require.ErrorIs(t, err, kms.ErrKeyExists)
+
+		key, err := s.Get("new")
+		require.NoError(t, err)
+		require.Equal(t, []byte("keyset"), key)
+	})
+
+	t.Run("put with version", func(t *testing.T) {
+		err := s.PutWithVersion("versioned", []byte("keyset"), nil, 1)
+		require.ErrorAs(t, err, new(*kms.VersionConflictError))
+		require.EqualError(t, err, "put key 'versioned': version conflict: expected version 1, stored version 0")
+
+		require.NoError(t, s.PutWithVersion("versioned", []byte("keyset"), nil, 0))
+
+		key, metadata, version, err := s.GetWithVersion("versioned")
+		require.NoError(t, err)
+		require.Equal(t, []byte("keyset"), key)
+		require.Nil(t, metadata)
+		require.Equal(t, int64(1), version)
+
+		require.NoError(t, s.PutWithVersion("versioned", []byte("rotated"), map[string]any{"k": "v"}, version))
+
+		err = s.PutWithVersion("versioned", []byte("stale"), nil, version)
+		require.EqualError(t, err, "put key 'versioned': version conflict: expected version 1, stored version 2")
+
+		// the unconditional updates increment the version too.
+		require.NoError(t, s.PutWithMetadata("versioned", []byte("rotated"), map[string]any{"k": "v"}))
+
+		key, metadata, version, err = s.GetWithVersion("versioned")
+		require.NoError(t, err)
+		require.Equal(t, []byte("rotated"), key)
+		require.Equal(t, map[string]any{"k": "v"}, metadata)
+		require.Equal(t, int64(3), version)
+
+		// the keysets stored without version have the version 1.
+		client.data["kmsdb:legacy"] = `{"k":"a2V5c2V0"}`
+
+		_, _, version, err = s.GetWithVersion("legacy")
+		require.NoError(t, err)
+		require.Equal(t, int64(1), version)
+
+		require.NoError(t, s.PutWithVersion("legacy", []byte("rotated"), nil, 1))
+
+		_, _, version, err = s.GetWithVersion("legacy")
+		require.NoError(t, err)
+		require.Equal(t, int64(2), version)
+
+		require.NoError(t, s.Delete("versioned"))
+		require.NoError(t, s.Delete("legacy"))
+
+		_, _, _, err = s.GetWithVersion("versioned")
+		require.ErrorIs(t, err, kms.ErrKeyNotFound)
+
+		require.EqualError(t, s.PutWithVersion("versioned", nil, nil, -1), "put key 'versioned': invalid version -1")
+	})
+
+	t.Run("concurrent writers", func(t *testing.T) {
+		require.NoError(t, s.Put("counter", []byte("0")))
+
+		incrementConcurrently(t, s, "counter", 8, 10)
+
+		key, err := s.Get("counter")
+		require.NoError(t, err)
+		require.Equal(t, []byte("80"), key)
+
+		require.NoError(t, s.Delete("counter"))
+	})
+
+	t.Run("key IDs and prefix migration", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			require.NoError(t, New(client, WithKeyPrefix("legacy*:")).Put(fmt.Sprintf("kid%d", i), []byte("keyset")))
+		}
+
+		client.data["other"] = "not a keyset"
+
+		ids, err := s.KeyIDs()
+		require.NoError(t, err)
+		require.Equal(t, []string{"new"}, ids)
+
+		n, err := s.MigrateKeyPrefix("legacy*:")
+		require.NoError(t, err)
+		require.Equal(t, 3, n)
+
+		ids, err = s.KeyIDs()
+		require.NoError(t, err)
+		sort.Strings(ids)
+		require.Equal(t, []string{"kid0", "kid1", "kid2", "new"}, ids)
+
+		client.data["legacy*:new"] = client.data["kmsdb:new"]
+
+		_, err = s.MigrateKeyPrefix("legacy*:")
+		require.ErrorIs(t, err, kms.ErrKeyExists)
+	})
+
+	t.Run("LocalKMS store", func(t *testing.T) {
+		k := mockkms.NewForTest(t, mockkms.WithStore(New(client, WithKeyPrefix("issuer:"))))
+
+		keyID, _, err := k.Create(kmsapi.ED25519Type, kmsapi.WithMetadata(map[string]any{"owner": "issuer"}))
+		require.NoError(t, err)
+		require.Contains(t, client.data, "issuer:"+keyID)
+
+		_, metadata, err := k.GetWithOpts(keyID, kmsapi.ExportWithMetadata(true))
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"owner": "issuer"}, metadata)
+
+		_, _, err = k.ImportPrivateKey(make([]byte, 32), kmsapi.AES256GCMType, kmsapi.WithKeyID(keyID))
+		require.ErrorContains(t, err, "already exists")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		client.err = errors.New("connection refused")
+		defer func() { client.err = nil }()
+
+		require.ErrorContains(t, s.Put("kid", nil), "put key 'kid': connection refused")
+		require.ErrorContains(t, s.PutWithVersion("kid", nil, nil, 1), "put key 'kid': connection refused")
+		require.ErrorContains(t, s.Create("kid", nil, nil), "create key 'kid': connection refused")
+		require.ErrorContains(t, s.Delete("kid"), "delete key 'kid': connection refused")
+
+		_, err := s.Get("kid")
+		require.ErrorContains(t, err, "get key 'kid': connection refused")
+
+		_, err = s.KeyIDs()
+		require.ErrorContains(t, err, "list key IDs: connection refused")
+
+		_, err = s.MigrateKeyPrefix("")
+		require.ErrorContains(t, err, "migrate key prefix: connection refused")
+
+		client.err = nil
+		client.data["kmsdb:invalid"] = "{"
+
+		_, err = s.Get("invalid")
+		require.ErrorContains(t, err, "get key 'invalid'")
+
+		err = s.PutWithMetadata("kid", nil, map[string]any{"invalid": make(chan int)})
+		require.ErrorContains(t, err, "put key 'kid'")
+
+		_, _, _, err = s.GetWithVersion("invalid")
+		require.ErrorContains(t, err, "get key 'invalid'")
+
+		err = s.PutWithVersion("kid", nil, map[string]any{"invalid": make(chan int)}, 1)
+		require.ErrorContains(t, err, "put key 'kid'")
+
+		client.badReplies = true
+
+		_, err = s.KeyIDs()
+		require.EqualError(t, err, "list key IDs: invalid SCAN reply")
+
+		require.EqualError(t, s.Put("kid", nil), "put key 'kid': unexpected reply type string")
+	})
+}
+
+// incrementConcurrently increments the counter stored under keysetID n times in each of the writers goroutines, with
+// the read, increment and PutWithVersion retries of optimistic concurrency.
+func incrementConcurrently(t *testing.T, s kmsapi.StoreVersioner, keysetID string, writers, n int) {
+	t.Helper()
+
+	var (
+		wg        sync.WaitGroup
+		conflicts atomic.Int64
+	)
+
+	errs := make(chan error, writers)
+
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < n; {
+				key, _, version, err := s.GetWithVersion(keysetID)
+				if err != nil {
+					errs <- err
+
+					return
+				}
+
+				counter, err := strconv.Atoi(string(key))
+				if err != nil {
+					errs <- err
+
+					return
+				}
+
+				// let the other writers update the counter between the read and the write.
+				runtime.Gosched()
+
+				err = s.PutWithVersion(keysetID, []byte(strconv.Itoa(counter+1)), nil, version)
+				if errors.As(err, new(*kms.VersionConflictError)) {
+					conflicts.Add(1)
+
+					continue
+				}
+
+				if err != nil {
+					errs <- err
+
+					return
+				}
+
+				j++
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	t.Logf("%d version conflicts", conflicts.Load())
+}
+
+// fakeClient is an in-memory Redis running one command at a time, SCAN returns a single key per page.
+type fakeClient struct {
+	mu         sync.Mutex
+	data       map[string]string
+	err        error
+	badReplies bool
+}
+
+func (c *fakeClient) Do(_ context.Context, args ...any) (any, error) { //nolint:gocyclo
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	cmd, key := args[0].(string), args[1].(string)
+
+	switch cmd {
+	case "GET":
+		v, ok := c.data[key]
+		if !ok {
+			return nil, nil
+		}
+
+		return v, nil
+	case "SET":
+		if _, exists := c.data[key]; exists && len(args) == 4 && args[3] == "NX" {
+			return nil, nil
+		}
+
+		c.data[key] = string(args[2].([]byte))
+
+		return "OK", nil
+	case "DEL":
+		delete(c.data, key)
+
+		return int64(1), nil
+	case "RENAMENX":
+		newKey := args[2].(string)
+		if _, exists := c.data[newKey]; exists {
+			return int64(0), nil
+		}
+
+		c.data[newKey] = c.data[key]
+		delete(c.data, key)
+
+		return int64(1), nil
+	case "EVAL":
+		if key != putScript {
+			return nil, errors.New("unknown script")
+		}
+
+		return c.put(args[3].(string), args[4].([]byte), args[5].(int64))
+	case "SCAN":
+		return c.scan(key, args[3].(string))
+	default:
+		return nil, fmt.Errorf("unknown command %s", cmd)
+	}
+}
+
+// put runs putScript.
+func (c *fakeClient) put(key string, v []byte, expected int64) (any, error) {
+	if c.badReplies {
+		return "OK", nil
+	}
+
+	var version int64
+
+	if stored, ok := c.data[key]; ok {
+		r := &record{Version: 1}
+
+		if err := json.Unmarshal([]byte(stored), r); err != nil {
+			return nil, err
+		}
+
+		version = r.Version
+	}
+
+	if expected >= 0 && version != expected {
+		return version, nil
+	}
+
+	c.data[key] = fmt.Sprintf(`%s,"v":%d}`, v[:len(v)-1], version+1)
+
+	return version, nil
+}
+
+func (c *fakeClient) scan(cursor, pattern string) (any, error) {
+	if c.badReplies {
+		return "OK", nil
+	}
+
+	var keys []string
+
+	for key := range c.data {
+		if ok, _ := path.Match(pattern, key); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	var i int
+
+	fmt.Sscan(cursor, &i) //nolint:errcheck
+
+	if i >= len(keys) {
+		return []any{"0", []any{}}, nil
+	}
+
+	next := fmt.Sprint(i + 1)
+	if i+1 == len(keys) {
+		next = "0"
+	}
+
+	return []any{next, []any{keys[i]}}, nil
+}
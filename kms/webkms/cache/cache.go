@@ -0,0 +1,235 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package cache provides an opt-in caching decorator for a kms.KeyManager (typically a webkms.RemoteKMS). It memoizes
+// public key exports and key metadata responses so that verification-heavy services do not call the key server for
+// the same public keys over and over again.
+package cache
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bluele/gcache"
+
+	"github.com/trustbloc/kms-go/doc/util/pubkeyfmt"
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+type exportedKey struct {
+	pubKey []byte
+	kt     kms.KeyType
+}
+
+// metadataKey is the cache key of the key metadata of keyID, read with the resolved export options.
+type metadataKey struct {
+	keyID       string
+	getMetadata bool
+}
+
+func newMetadataKey(keyID string, opts []kms.ExportKeyOpts) metadataKey {
+	exportOpts := kms.NewExportOpt()
+
+	for _, opt := range opts {
+		opt(exportOpts)
+	}
+
+	return metadataKey{keyID: keyID, getMetadata: exportOpts.GetMetadata()}
+}
+
+type keyMetadata struct {
+	handle   interface{}
+	metadata map[string]any
+}
+
+// metadataGetter is implemented by key managers supporting key metadata (eg: localkms.LocalKMS).
+type metadataGetter interface {
+	GetWithOpts(keyID string, opts ...kms.ExportKeyOpts) (any, map[string]any, error)
+}
+
+// KeyManager is a kms.KeyManager decorator caching exported public keys and key metadata of the wrapped KeyManager.
+// All other operations are delegated to the wrapped KeyManager as is.
+type KeyManager struct {
+	kms.KeyManager
+	exports  gcache.Cache
+	metadata gcache.Cache
+}
+
+// New creates a new caching KeyManager wrapping km.
+func New(km kms.KeyManager, opts ...Opt) *KeyManager {
+	cOpts := newCacheOpts()
+
+	for _, opt := range opts {
+		opt(cOpts)
+	}
+
+	return &KeyManager{
+		KeyManager: km,
+		exports:    buildCache(cOpts),
+		metadata:   buildCache(cOpts),
+	}
+}
+
+func buildCache(opts *cacheOpts) gcache.Cache {
+	builder := gcache.New(opts.size).Clock(opts.clock)
+
+	if opts.size > 0 {
+		builder = builder.LRU()
+	}
+
+	if opts.ttl > 0 {
+		builder = builder.Expiration(opts.ttl)
+	}
+
+	return builder.Build()
+}
+
+// ExportPubKeyBytes returns the public key referenced by keyID from the cache if found, otherwise it fetches it from
+// the wrapped KeyManager and caches the result.
+// Returns:
+//   - marshalled public key []byte
+//   - error if it fails to export the public key bytes
+func (k *KeyManager) ExportPubKeyBytes(keyID string) ([]byte, kms.KeyType, error) {
+	v, err := k.exports.Get(keyID)
+	if err == nil {
+		ek := v.(*exportedKey) //nolint:errcheck,forcetypeassert // only exportedKey values are stored.
+
+		return copyBytes(ek.pubKey), ek.kt, nil
+	}
+
+	if !errors.Is(err, gcache.KeyNotFoundError) {
+		return nil, "", fmt.Errorf("cache: failed to read public key from cache: %w", err)
+	}
+
+	pubKey, kt, err := k.KeyManager.ExportPubKeyBytes(keyID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err = k.setExport(keyID, pubKey, kt); err != nil {
+		return nil, "", err
+	}
+
+	return pubKey, kt, nil
+}
+
+// ExportPubKey returns the public key referenced by keyID, read with ExportPubKeyBytes, encoded in format.
+func (k *KeyManager) ExportPubKey(keyID string, format kms.PubKeyFormat) ([]byte, error) {
+	pubKey, kt, err := k.ExportPubKeyBytes(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pubkeyfmt.Encode(keyID, pubKey, kt, format)
+}
+
+// CreateAndExportPubKeyBytes creates a key of type kt with the wrapped KeyManager and caches its exported public key.
+// Returns:
+//   - keyID of the new handle created.
+//   - marshalled public key []byte
+//   - error if it fails to export the public key bytes
+func (k *KeyManager) CreateAndExportPubKeyBytes(kt kms.KeyType, opts ...kms.KeyOpts) (string, []byte, error) {
+	keyID, pubKey, err := k.KeyManager.CreateAndExportPubKeyBytes(kt, opts...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err = k.setExport(keyID, pubKey, kt); err != nil {
+		return "", nil, err
+	}
+
+	return keyID, pubKey, nil
+}
+
+// Rotate rotates the key referenced by keyID with the wrapped KeyManager and evicts the old key from the cache.
+// Returns:
+//   - new KeyID
+//   - handle instance (to private key)
+//   - error if failure
+func (k *KeyManager) Rotate(kt kms.KeyType, keyID string, opts ...kms.KeyOpts) (string, interface{}, error) {
+	newKeyID, kh, err := k.KeyManager.Rotate(kt, keyID, opts...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	k.Invalidate(keyID)
+
+	return newKeyID, kh, nil
+}
+
+// GetWithOpts returns the key handle and metadata referenced by keyID from the cache if found, otherwise it fetches
+// them from the wrapped KeyManager and caches the result. The results are cached per keyID and export options. The
+// wrapped KeyManager must support key metadata.
+// Returns:
+//   - handle instance
+//   - metadata if any saved
+//   - error if failure
+func (k *KeyManager) GetWithOpts(keyID string, opts ...kms.ExportKeyOpts) (any, map[string]any, error) {
+	mg, ok := k.KeyManager.(metadataGetter)
+	if !ok {
+		return nil, nil, errors.New("cache: wrapped key manager does not support key metadata")
+	}
+
+	cacheKey := newMetadataKey(keyID, opts)
+
+	v, err := k.metadata.Get(cacheKey)
+	if err == nil {
+		km := v.(*keyMetadata) //nolint:errcheck,forcetypeassert // only keyMetadata values are stored.
+
+		return km.handle, km.metadata, nil
+	}
+
+	if !errors.Is(err, gcache.KeyNotFoundError) {
+		return nil, nil, fmt.Errorf("cache: failed to read key metadata from cache: %w", err)
+	}
+
+	kh, metadata, err := mg.GetWithOpts(keyID, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = k.metadata.Set(cacheKey, &keyMetadata{handle: kh, metadata: metadata})
+	if err != nil {
+		return nil, nil, fmt.Errorf("cache: failed to store key metadata in cache: %w", err)
+	}
+
+	return kh, metadata, nil
+}
+
+// Invalidate evicts any cached entry for keyID.
+func (k *KeyManager) Invalidate(keyID string) {
+	k.exports.Remove(keyID)
+
+	for _, getMetadata := range []bool{false, true} {
+		k.metadata.Remove(metadataKey{keyID: keyID, getMetadata: getMetadata})
+	}
+}
+
+// Purge evicts all cached entries.
+func (k *KeyManager) Purge() {
+	k.exports.Purge()
+	k.metadata.Purge()
+}
+
+func (k *KeyManager) setExport(keyID string, pubKey []byte, kt kms.KeyType) error {
+	err := k.exports.Set(keyID, &exportedKey{pubKey: copyBytes(pubKey), kt: kt})
+	if err != nil {
+		return fmt.Errorf("cache: failed to store public key in cache: %w", err)
+	}
+
+	return nil
+}
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+
+	c := make([]byte, len(b))
+	copy(c, b)
+
+	return c
+}
@@ -0,0 +1,306 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bluele/gcache"
+	"github.com/stretchr/testify/require"
+
+	mockkms "github.com/trustbloc/kms-go/mock/kms"
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+type countingKMS struct {
+	mockkms.KeyManager
+	exportCalls   int
+	metadataCalls int
+	metadata      map[string]any
+}
+
+func (c *countingKMS) ExportPubKeyBytes(keyID string) ([]byte, kms.KeyType, error) {
+	c.exportCalls++
+
+	return c.KeyManager.ExportPubKeyBytes(keyID)
+}
+
+func (c *countingKMS) GetWithOpts(keyID string, opts ...kms.ExportKeyOpts) (any, map[string]any, error) {
+	c.metadataCalls++
+
+	if c.GetKeyErr != nil {
+		return nil, nil, c.GetKeyErr
+	}
+
+	exportOpts := kms.NewExportOpt()
+
+	for _, opt := range opts {
+		opt(exportOpts)
+	}
+
+	if !exportOpts.GetMetadata() {
+		return keyID, nil, nil
+	}
+
+	return keyID, c.metadata, nil
+}
+
+func TestKeyManager_ExportPubKeyBytes(t *testing.T) {
+	t.Run("success - second call served from cache", func(t *testing.T) {
+		km := &countingKMS{KeyManager: mockkms.KeyManager{
+			ExportPubKeyBytesValue: []byte("pubkey"),
+			ExportPubKeyTypeValue:  kms.ED25519Type,
+		}}
+
+		c := New(km)
+
+		for i := 0; i < 3; i++ {
+			pub, kt, err := c.ExportPubKeyBytes("kid")
+			require.NoError(t, err)
+			require.Equal(t, []byte("pubkey"), pub)
+			require.Equal(t, kms.ED25519Type, kt)
+		}
+
+		require.Equal(t, 1, km.exportCalls)
+	})
+
+	t.Run("success - ExportPubKey encodes the cached public key", func(t *testing.T) {
+		pubKey := make([]byte, 32)
+		km := &countingKMS{KeyManager: mockkms.KeyManager{
+			ExportPubKeyBytesValue: pubKey,
+			ExportPubKeyTypeValue:  kms.ED25519Type,
+		}}
+
+		c := New(km)
+
+		for _, format := range []kms.PubKeyFormat{kms.PubKeyFormatRaw, kms.PubKeyFormatJWK} {
+			_, err := c.ExportPubKey("kid", format)
+			require.NoError(t, err)
+		}
+
+		raw, err := c.ExportPubKey("kid", kms.PubKeyFormatRaw)
+		require.NoError(t, err)
+		require.Equal(t, pubKey, raw)
+		require.Equal(t, 1, km.exportCalls)
+	})
+
+	t.Run("cached value is not affected by caller modifications", func(t *testing.T) {
+		km := &countingKMS{KeyManager: mockkms.KeyManager{ExportPubKeyBytesValue: []byte("pubkey")}}
+
+		c := New(km)
+
+		pub, _, err := c.ExportPubKeyBytes("kid")
+		require.NoError(t, err)
+
+		pub[0] = 'x'
+
+		pub, _, err = c.ExportPubKeyBytes("kid")
+		require.NoError(t, err)
+		require.Equal(t, []byte("pubkey"), pub)
+	})
+
+	t.Run("entries expire after TTL", func(t *testing.T) {
+		km := &countingKMS{KeyManager: mockkms.KeyManager{ExportPubKeyBytesValue: []byte("pubkey")}}
+		clock := gcache.NewFakeClock()
+
+		c := New(km, WithTTL(time.Minute), WithClock(clock))
+
+		_, _, err := c.ExportPubKeyBytes("kid")
+		require.NoError(t, err)
+
+		clock.Advance(2 * time.Minute)
+
+		_, _, err = c.ExportPubKeyBytes("kid")
+		require.NoError(t, err)
+		require.Equal(t, 2, km.exportCalls)
+	})
+
+	t.Run("least recently used entries are evicted when size is reached", func(t *testing.T) {
+		km := &countingKMS{KeyManager: mockkms.KeyManager{ExportPubKeyBytesValue: []byte("pubkey")}}
+
+		c := New(km, WithSize(1), WithTTL(0))
+
+		_, _, err := c.ExportPubKeyBytes("kid1")
+		require.NoError(t, err)
+
+		_, _, err = c.ExportPubKeyBytes("kid2")
+		require.NoError(t, err)
+
+		_, _, err = c.ExportPubKeyBytes("kid1")
+		require.NoError(t, err)
+		require.Equal(t, 3, km.exportCalls)
+	})
+
+	t.Run("unlimited size", func(t *testing.T) {
+		km := &countingKMS{KeyManager: mockkms.KeyManager{ExportPubKeyBytesValue: []byte("pubkey")}}
+
+		c := New(km, WithSize(0))
+
+		for _, kid := range []string{"kid1", "kid2", "kid1", "kid2"} {
+			_, _, err := c.ExportPubKeyBytes(kid)
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, 2, km.exportCalls)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		errExpected := errors.New("export failed")
+		km := &countingKMS{KeyManager: mockkms.KeyManager{ExportPubKeyBytesErr: errExpected}}
+
+		c := New(km)
+
+		_, _, err := c.ExportPubKeyBytes("kid")
+		require.ErrorIs(t, err, errExpected)
+
+		_, _, err = c.ExportPubKeyBytes("kid")
+		require.ErrorIs(t, err, errExpected)
+		require.Equal(t, 2, km.exportCalls)
+	})
+}
+
+func TestKeyManager_CreateAndExportPubKeyBytes(t *testing.T) {
+	t.Run("success - created key is cached", func(t *testing.T) {
+		km := &countingKMS{KeyManager: mockkms.KeyManager{
+			CrAndExportPubKeyID:    "kid",
+			CrAndExportPubKeyValue: []byte("pubkey"),
+		}}
+
+		c := New(km)
+
+		kid, pub, err := c.CreateAndExportPubKeyBytes(kms.ECDSAP256TypeIEEEP1363)
+		require.NoError(t, err)
+		require.Equal(t, "kid", kid)
+		require.Equal(t, []byte("pubkey"), pub)
+
+		pub, kt, err := c.ExportPubKeyBytes(kid)
+		require.NoError(t, err)
+		require.Equal(t, []byte("pubkey"), pub)
+		require.Equal(t, kms.ECDSAP256TypeIEEEP1363, kt)
+		require.Zero(t, km.exportCalls)
+	})
+
+	t.Run("create error", func(t *testing.T) {
+		errExpected := errors.New("create failed")
+		c := New(&countingKMS{KeyManager: mockkms.KeyManager{CrAndExportPubKeyErr: errExpected}})
+
+		_, _, err := c.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.ErrorIs(t, err, errExpected)
+	})
+}
+
+func TestKeyManager_Invalidation(t *testing.T) {
+	km := &countingKMS{KeyManager: mockkms.KeyManager{
+		ExportPubKeyBytesValue: []byte("pubkey"),
+		RotateKeyID:            "newKID",
+	}}
+
+	c := New(km)
+
+	_, _, err := c.ExportPubKeyBytes("kid")
+	require.NoError(t, err)
+
+	newKID, _, err := c.Rotate(kms.ED25519Type, "kid")
+	require.NoError(t, err)
+	require.Equal(t, "newKID", newKID)
+
+	_, _, err = c.ExportPubKeyBytes("kid")
+	require.NoError(t, err)
+	require.Equal(t, 2, km.exportCalls)
+
+	c.Invalidate("kid")
+
+	_, _, err = c.ExportPubKeyBytes("kid")
+	require.NoError(t, err)
+	require.Equal(t, 3, km.exportCalls)
+
+	c.Purge()
+
+	_, _, err = c.ExportPubKeyBytes("kid")
+	require.NoError(t, err)
+	require.Equal(t, 4, km.exportCalls)
+
+	t.Run("rotate error", func(t *testing.T) {
+		errExpected := errors.New("rotate failed")
+		rc := New(&countingKMS{KeyManager: mockkms.KeyManager{RotateKeyErr: errExpected}})
+
+		_, _, err = rc.Rotate(kms.ED25519Type, "kid")
+		require.ErrorIs(t, err, errExpected)
+	})
+}
+
+func TestKeyManager_GetWithOpts(t *testing.T) {
+	t.Run("success - metadata cached", func(t *testing.T) {
+		km := &countingKMS{metadata: map[string]any{"k": "v"}}
+
+		c := New(km)
+
+		for i := 0; i < 2; i++ {
+			kh, metadata, err := c.GetWithOpts("kid", kms.ExportWithMetadata(true))
+			require.NoError(t, err)
+			require.Equal(t, "kid", kh)
+			require.Equal(t, map[string]any{"k": "v"}, metadata)
+		}
+
+		require.Equal(t, 1, km.metadataCalls)
+
+		c.Invalidate("kid")
+
+		_, _, err := c.GetWithOpts("kid")
+		require.NoError(t, err)
+		require.Equal(t, 2, km.metadataCalls)
+	})
+
+	t.Run("success - cached per export options", func(t *testing.T) {
+		km := &countingKMS{metadata: map[string]any{"k": "v"}}
+
+		c := New(km)
+
+		for i := 0; i < 2; i++ {
+			_, metadata, err := c.GetWithOpts("kid", kms.ExportWithMetadata(true))
+			require.NoError(t, err)
+			require.Equal(t, map[string]any{"k": "v"}, metadata)
+
+			_, metadata, err = c.GetWithOpts("kid", kms.ExportWithMetadata(false))
+			require.NoError(t, err)
+			require.Nil(t, metadata)
+
+			_, metadata, err = c.GetWithOpts("kid")
+			require.NoError(t, err)
+			require.Nil(t, metadata)
+		}
+
+		require.Equal(t, 2, km.metadataCalls)
+
+		c.Invalidate("kid")
+
+		_, metadata, err := c.GetWithOpts("kid", kms.ExportWithMetadata(true))
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"k": "v"}, metadata)
+
+		_, _, err = c.GetWithOpts("kid")
+		require.NoError(t, err)
+		require.Equal(t, 4, km.metadataCalls)
+	})
+
+	t.Run("error from wrapped key manager", func(t *testing.T) {
+		errExpected := errors.New("get failed")
+		c := New(&countingKMS{KeyManager: mockkms.KeyManager{GetKeyErr: errExpected}})
+
+		_, _, err := c.GetWithOpts("kid")
+		require.ErrorIs(t, err, errExpected)
+	})
+
+	t.Run("wrapped key manager does not support metadata", func(t *testing.T) {
+		c := New(&mockkms.KeyManager{})
+
+		_, _, err := c.GetWithOpts("kid")
+		require.EqualError(t, err, "cache: wrapped key manager does not support key metadata")
+	})
+}
@@ -0,0 +1,59 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cache
+
+import (
+	"time"
+
+	"github.com/bluele/gcache"
+)
+
+const (
+	// DefaultSize is the default maximum number of cached public keys.
+	DefaultSize = 1000
+	// DefaultTTL is the default duration a cached public key remains valid.
+	DefaultTTL = 5 * time.Minute
+)
+
+type cacheOpts struct {
+	size  int
+	ttl   time.Duration
+	clock gcache.Clock
+}
+
+func newCacheOpts() *cacheOpts {
+	return &cacheOpts{
+		size:  DefaultSize,
+		ttl:   DefaultTTL,
+		clock: gcache.NewRealClock(),
+	}
+}
+
+// Opt is a cache KeyManager option.
+type Opt func(opts *cacheOpts)
+
+// WithSize sets the maximum number of entries held by the cache. Least recently used entries are evicted first.
+// A size of zero or less disables the size limit.
+func WithSize(size int) Opt {
+	return func(opts *cacheOpts) {
+		opts.size = size
+	}
+}
+
+// WithTTL sets the duration after which a cached entry expires. A TTL of zero or less disables expiration.
+func WithTTL(ttl time.Duration) Opt {
+	return func(opts *cacheOpts) {
+		opts.ttl = ttl
+	}
+}
+
+// WithClock sets the clock used to compute entries expiration (mainly useful for tests).
+func WithClock(clock gcache.Clock) Opt {
+	return func(opts *cacheOpts) {
+		opts.clock = clock
+	}
+}
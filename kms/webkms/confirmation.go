@@ -0,0 +1,210 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webkms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/trustbloc/kms-go/internal/logutil"
+)
+
+const (
+	defaultConfirmationPollInterval = time.Second
+	defaultConfirmationTimeout      = 5 * time.Minute
+	maxConfirmationBodySize         = 1 << 16
+)
+
+var (
+	// ErrConfirmationRequired is returned when the server requires a user confirmation of the operation (202
+	// Accepted response) and neither WithConfirmationPolling nor WithConfirmationWaiter is set.
+	ErrConfirmationRequired = errors.New("operation requires a confirmation")
+	// ErrConfirmationTimeout is returned when the operation is still pending confirmation after the timeout set by
+	// WithConfirmationPolling.
+	ErrConfirmationTimeout = errors.New("operation confirmation timed out")
+	// ErrConfirmationOrigin is returned when the confirmation URI of a 202 Accepted response isn't on the scheme and
+	// host of the request: the result of the operation is only fetched from the server the request was sent to.
+	ErrConfirmationOrigin = errors.New("confirmation URI is not on the origin of the request")
+)
+
+// ConfirmationWaiter blocks until the operation pending at confirmationURL is confirmed or rejected by the user, eg:
+// until the webhook callback of the server for confirmationURL is received by the application. remoteCrypto fetches
+// the result of the operation at confirmationURL once it returns nil.
+type ConfirmationWaiter func(confirmationURL string) error
+
+// confirmationResp is the optional body of a 202 Accepted response, the Location header takes precedence.
+type confirmationResp struct {
+	ConfirmationURI string `json:"confirmation_uri"`
+}
+
+// WithConfirmationPolling option polls the confirmation URI of the operations the server requires a user
+// confirmation for (202 Accepted response) every interval, until the result of the operation is returned or timeout
+// expires. A zero interval polls every second and a zero timeout stops polling after 5 minutes.
+func WithConfirmationPolling(interval, timeout time.Duration) Opt {
+	return func(opts *Opts) {
+		if interval <= 0 {
+			interval = defaultConfirmationPollInterval
+		}
+
+		if timeout <= 0 {
+			timeout = defaultConfirmationTimeout
+		}
+
+		opts.ConfirmationPollInterval = interval
+		opts.ConfirmationTimeout = timeout
+	}
+}
+
+// WithConfirmationWaiter option calls waiter for the operations the server requires a user confirmation for (202
+// Accepted response), the result of the operation is fetched at the confirmation URI once waiter returns. It can be
+// combined with WithConfirmationPolling, the confirmation URI is then polled if the operation is still pending.
+func WithConfirmationWaiter(waiter ConfirmationWaiter) Opt {
+	return func(opts *Opts) {
+		opts.ConfirmationWaiter = waiter
+	}
+}
+
+// AwaitConfirmation returns resp, the response of the request to reqURL, if it's not a 202 Accepted response.
+// Otherwise, it closes resp, waits for the confirmation of the operation as set in opts and returns the response of
+// its confirmation URI fetched with get. Polling stops when ctx is done. The caller closes the returned response body.
+// Not to be used directly. It's intended for implementations of remoteKMS.
+func AwaitConfirmation(ctx context.Context, opts *Opts, reqURL string, resp *http.Response,
+	get func(context.Context, string) (*http.Response, error)) (*http.Response, error) {
+	if resp.StatusCode != http.StatusAccepted {
+		return resp, nil
+	}
+
+	confirmationURL, err := parseConfirmationURI(reqURL, resp)
+
+	_ = resp.Body.Close() //nolint:errcheck // the 202 response is replaced by the confirmation result
+
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.ConfirmationWaiter == nil && opts.ConfirmationPollInterval == 0 {
+		return nil, fmt.Errorf("%w at %s", ErrConfirmationRequired, confirmationURL)
+	}
+
+	if opts.ConfirmationWaiter != nil {
+		if err = opts.ConfirmationWaiter(confirmationURL); err != nil {
+			return nil, fmt.Errorf("wait for confirmation at %s: %w", confirmationURL, err)
+		}
+	}
+
+	return pollConfirmation(ctx, opts, confirmationURL, get)
+}
+
+func pollConfirmation(ctx context.Context, opts *Opts, confirmationURL string,
+	get func(context.Context, string) (*http.Response, error)) (*http.Response, error) {
+	// with a waiter only, the operation is confirmed: the confirmation URI is fetched once.
+	if opts.ConfirmationPollInterval == 0 {
+		return getConfirmation(ctx, confirmationURL, get)
+	}
+
+	timeout := opts.ConfirmationTimeout
+	if timeout <= 0 {
+		timeout = defaultConfirmationTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		resp, err := getConfirmation(ctx, confirmationURL, get)
+		if err != nil && ctx.Err() != nil {
+			// the poll was interrupted by ctx.
+			return nil, confirmationDone(ctx, opts, confirmationURL)
+		}
+
+		if err != nil || resp.StatusCode != http.StatusAccepted {
+			return resp, err
+		}
+
+		_ = resp.Body.Close() //nolint:errcheck // still pending, polled again
+
+		logutil.Logger(opts.Logger).Debug("webkms: confirmation pending, polling again", "url", confirmationURL,
+			"interval", opts.ConfirmationPollInterval)
+
+		timer := time.NewTimer(opts.ConfirmationPollInterval)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil, confirmationDone(ctx, opts, confirmationURL)
+		case <-timer.C:
+		}
+	}
+}
+
+// confirmationDone returns the error of a confirmation polling stopped by ctx, ErrConfirmationTimeout if its deadline
+// was exceeded.
+func confirmationDone(ctx context.Context, opts *Opts, confirmationURL string) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		logutil.Logger(opts.Logger).Warn("webkms: confirmation timeout", "url", confirmationURL)
+
+		return fmt.Errorf("%w at %s", ErrConfirmationTimeout, confirmationURL)
+	}
+
+	return fmt.Errorf("wait for confirmation at %s: %w", confirmationURL, ctx.Err())
+}
+
+func getConfirmation(ctx context.Context, confirmationURL string,
+	get func(context.Context, string) (*http.Response, error)) (*http.Response, error) {
+	resp, err := get(ctx, confirmationURL)
+	if err != nil {
+		return nil, fmt.Errorf("get confirmation result at %s: %w", confirmationURL, err)
+	}
+
+	return resp, nil
+}
+
+// parseConfirmationURI returns the confirmation URI of the 202 Accepted response resp, resolved against reqURL. It
+// fails with ErrConfirmationOrigin if the resolved URI has another scheme or host than reqURL.
+func parseConfirmationURI(reqURL string, resp *http.Response) (string, error) {
+	uri := resp.Header.Get("Location")
+
+	if uri == "" {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxConfirmationBodySize))
+		if err != nil {
+			return "", fmt.Errorf("read confirmation response: %w", err)
+		}
+
+		cResp := &confirmationResp{}
+
+		if err = json.Unmarshal(body, cResp); err != nil || cResp.ConfirmationURI == "" {
+			return "", errors.New("202 Accepted response without a confirmation URI")
+		}
+
+		uri = cResp.ConfirmationURI
+	}
+
+	base, err := url.Parse(reqURL)
+	if err != nil {
+		return "", fmt.Errorf("parse request URL: %w", err)
+	}
+
+	ref, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("parse confirmation URI: %w", err)
+	}
+
+	resolved := base.ResolveReference(ref)
+
+	if resolved.Scheme != base.Scheme || resolved.Host != base.Host {
+		return "", fmt.Errorf("%w: %s", ErrConfirmationOrigin, resolved.Redacted())
+	}
+
+	return resolved.String(), nil
+}
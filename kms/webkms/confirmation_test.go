@@ -0,0 +1,157 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webkms
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func response(status int, location, body string) *http.Response {
+	resp := &http.Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))}
+
+	if location != "" {
+		resp.Header.Set("Location", location)
+	}
+
+	return resp
+}
+
+func TestAwaitConfirmation(t *testing.T) {
+	const reqURL = "https://kms.example.com/v1/keystores/ks1/keys/k1/sign"
+
+	ok := func(context.Context, string) (*http.Response, error) { return response(http.StatusOK, "", "{}"), nil }
+
+	t.Run("not a 202 Accepted response", func(t *testing.T) {
+		resp := response(http.StatusOK, "", "")
+
+		got, err := AwaitConfirmation(context.Background(), NewOpt(), reqURL, resp, nil)
+		require.NoError(t, err)
+		require.Same(t, resp, got)
+	})
+
+	t.Run("resolves the confirmation URI", func(t *testing.T) {
+		opts := NewOpt()
+		WithConfirmationPolling(0, 0)(opts)
+		require.Equal(t, defaultConfirmationPollInterval, opts.ConfirmationPollInterval)
+		require.Equal(t, defaultConfirmationTimeout, opts.ConfirmationTimeout)
+
+		var fetched []string
+
+		get := func(ctx context.Context, u string) (*http.Response, error) {
+			fetched = append(fetched, u)
+
+			return ok(ctx, u)
+		}
+
+		ctx := context.Background()
+
+		_, err := AwaitConfirmation(ctx, opts, reqURL, response(http.StatusAccepted, "../confirmations/1", ""), get)
+		require.NoError(t, err)
+
+		_, err = AwaitConfirmation(ctx, opts, reqURL,
+			response(http.StatusAccepted, "", `{"confirmation_uri":"https://kms.example.com/c/2"}`), get)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{
+			"https://kms.example.com/v1/keystores/ks1/keys/confirmations/1",
+			"https://kms.example.com/c/2",
+		}, fetched)
+	})
+
+	t.Run("rejects a confirmation URI on another origin", func(t *testing.T) {
+		opts := NewOpt()
+		WithConfirmationPolling(time.Millisecond, 0)(opts)
+
+		for _, uri := range []string{
+			"https://confirm.example.com/c/2",
+			"http://kms.example.com/c/2",
+			"https://kms.example.com:8443/c/2",
+			"//attacker.example.com/c/2",
+		} {
+			_, err := AwaitConfirmation(context.Background(), opts, reqURL, response(http.StatusAccepted, uri, ""),
+				func(context.Context, string) (*http.Response, error) {
+					require.FailNow(t, "the confirmation URI must not be fetched")
+
+					return nil, nil
+				})
+			require.ErrorIs(t, err, ErrConfirmationOrigin, uri)
+		}
+	})
+
+	t.Run("stops polling when the context is done", func(t *testing.T) {
+		opts := NewOpt()
+		WithConfirmationPolling(time.Millisecond, 0)(opts)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		polls := 0
+		pending := func(context.Context, string) (*http.Response, error) {
+			polls++
+			if polls == 3 {
+				cancel()
+			}
+
+			return response(http.StatusAccepted, "", ""), nil
+		}
+
+		_, err := AwaitConfirmation(ctx, opts, reqURL, response(http.StatusAccepted, "/c/1", ""), pending)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, 3, polls)
+
+		WithConfirmationPolling(time.Millisecond, 20*time.Millisecond)(opts)
+
+		alwaysPending := func(context.Context, string) (*http.Response, error) {
+			return response(http.StatusAccepted, "", ""), nil
+		}
+
+		_, err = AwaitConfirmation(context.Background(), opts, reqURL, response(http.StatusAccepted, "/c/1", ""),
+			alwaysPending)
+		require.ErrorIs(t, err, ErrConfirmationTimeout)
+
+		// the timeout expires during a poll.
+		blocked := func(ctx context.Context, _ string) (*http.Response, error) {
+			<-ctx.Done()
+
+			return nil, ctx.Err()
+		}
+
+		_, err = AwaitConfirmation(context.Background(), opts, reqURL, response(http.StatusAccepted, "/c/1", ""),
+			blocked)
+		require.ErrorIs(t, err, ErrConfirmationTimeout)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		opts := NewOpt()
+		WithConfirmationPolling(time.Millisecond, 0)(opts)
+
+		ctx := context.Background()
+
+		_, err := AwaitConfirmation(ctx, opts, reqURL, response(http.StatusAccepted, "", "{}"), ok)
+		require.EqualError(t, err, "202 Accepted response without a confirmation URI")
+
+		_, err = AwaitConfirmation(ctx, opts, reqURL, response(http.StatusAccepted, "%zz", ""), ok)
+		require.ErrorContains(t, err, "parse confirmation URI")
+
+		_, err = AwaitConfirmation(ctx, opts, reqURL, response(http.StatusAccepted, "/c/1", ""),
+			func(context.Context, string) (*http.Response, error) { return nil, errors.New("connection refused") })
+		require.EqualError(t, err,
+			"get confirmation result at https://kms.example.com/c/1: connection refused")
+
+		WithConfirmationWaiter(func(string) error { return errors.New("rejected") })(opts)
+
+		_, err = AwaitConfirmation(ctx, opts, reqURL, response(http.StatusAccepted, "/c/1", ""), ok)
+		require.EqualError(t, err, "wait for confirmation at https://kms.example.com/c/1: rejected")
+	})
+}
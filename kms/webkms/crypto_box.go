@@ -12,10 +12,9 @@ import (
 	"io/ioutil"
 	"time"
 
-	"golang.org/x/crypto/nacl/box"
-
 	"github.com/trustbloc/kms-go/util/cryptoutil"
 
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
 	"github.com/trustbloc/kms-go/spi/kms"
 
 	"github.com/trustbloc/kms-go/doc/util/jwkkid"
@@ -55,6 +54,8 @@ type sealOpenResp struct {
 	Plaintext []byte `json:"plaintext"`
 }
 
+var _ cryptoapi.CryptoBox = (*CryptoBox)(nil)
+
 const (
 	wrapURL   = "/wrap"
 	unwrapURL = "/unwrap"
@@ -186,31 +187,19 @@ func (b *CryptoBox) EasyOpen(cipherText, nonce, theirPub, myPub []byte) ([]byte,
 	return httpResp.Plaintext, nil
 }
 
-// Seal seals a payload using the equivalent of libsodium box_seal. This is an exact copy of localkms's CryptoBox.Seal()
+// Seal seals a payload using the equivalent of libsodium box_seal. This is the same as localkms's CryptoBox.Seal()
 // as no private key is involved and therefore it is not necessary to call the key server.
 //
 // Generates an ephemeral keypair to use for the sender, and includes
 // the ephemeral sender public key in the message.
 func (b *CryptoBox) Seal(payload, theirEncPub []byte, randSource io.Reader) ([]byte, error) {
 	sealStart := time.Now()
-	// generate ephemeral curve25519 asymmetric keys
-	epk, esk, err := box.GenerateKey(randSource)
-	if err != nil {
-		return nil, err
-	}
-
-	var recPubBytes [cryptoutil.Curve25519KeySize]byte
 
-	copy(recPubBytes[:], theirEncPub)
-
-	nonce, err := cryptoutil.Nonce(epk[:], theirEncPub)
+	ret, err := cryptoutil.BoxSeal(payload, theirEncPub, randSource)
 	if err != nil {
 		return nil, err
 	}
 
-	// now seal the msg with the ephemeral key, nonce and recPub (which is recipient's publicKey)
-	ret := box.Seal(epk[:], payload, nonce, &recPubBytes, esk)
-
 	debugLogger.Printf("overall Seal (non remote call) duration: %s", time.Since(sealStart))
 
 	return ret, nil
@@ -8,9 +8,15 @@ package webkms
 
 import (
 	"encoding/json"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/bluele/gcache"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/trustbloc/kms-go/spi/kms"
 )
 
 // AddHeaders function supports adding custom http headers.
@@ -20,7 +26,20 @@ type AddHeaders func(req *http.Request) (*http.Header, error)
 type Opts struct {
 	HeadersFunc     AddHeaders
 	ComputeMACCache gcache.Cache
-	marshal         MarshalFunc
+	AuditLogger     kms.AuditLogger
+	// Tracer and Propagator are set by WithTracer and WithPropagator.
+	Tracer     trace.Tracer
+	Propagator propagation.TextMapPropagator
+	// CapabilityInvoker is set by WithCapabilityInvoker.
+	CapabilityInvoker *CapabilityInvoker
+	// ConfirmationPollInterval, ConfirmationTimeout and ConfirmationWaiter are set by WithConfirmationPolling and
+	// WithConfirmationWaiter.
+	ConfirmationPollInterval time.Duration
+	ConfirmationTimeout      time.Duration
+	ConfirmationWaiter       ConfirmationWaiter
+	// Logger is set by WithLogger.
+	Logger  *slog.Logger
+	marshal MarshalFunc
 }
 
 // NewOpt creates a new empty option.
@@ -54,3 +73,10 @@ func WithMarshalFn(fn MarshalFunc) Opt {
 		opts.marshal = fn
 	}
 }
+
+// WithAuditLogger sets the audit logger recording the operations of remoteKMS and remoteCrypto.
+func WithAuditLogger(l kms.AuditLogger) Opt {
+	return func(opts *Opts) {
+		opts.AuditLogger = l
+	}
+}
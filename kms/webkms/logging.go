@@ -0,0 +1,46 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webkms
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/trustbloc/kms-go/internal/logutil"
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+// WithLogger sets the logger of the remote KMS and Crypto debug events (requests and confirmation polls) and warn
+// events (unavailable servers and confirmation timeouts). As in the spans of WithTracer, the key IDs of the logged
+// URLs are replaced by their hash. Without logger the events are discarded.
+func WithLogger(logger *slog.Logger) Opt {
+	return func(opts *Opts) {
+		opts.Logger = logger
+	}
+}
+
+// LogRequest logs the request req sent at start with the logger of opts, at the warn level if the server is
+// unavailable (err wraps kms.ErrRemoteUnavailable or resp is a server error response).
+// Not to be used directly. It's intended for implementations of remoteKMS.
+func LogRequest(opts *Opts, req *http.Request, resp *http.Response, err error, start time.Time) {
+	logger := logutil.Logger(opts.Logger)
+	url, _ := redactKeyID(req.URL.String())
+	attrs := []any{"method", req.Method, "url", url, "duration", time.Since(start)}
+
+	switch {
+	case errors.Is(err, kms.ErrRemoteUnavailable):
+		logger.Warn("webkms: remote server unavailable", append(attrs, "error", err)...)
+	case err != nil:
+		logger.Debug("webkms: request failed", append(attrs, "error", err)...)
+	case errors.Is(&kms.RemoteError{StatusCode: resp.StatusCode}, kms.ErrRemoteUnavailable):
+		logger.Warn("webkms: remote server unavailable", append(attrs, "status", resp.StatusCode)...)
+	default:
+		logger.Debug("webkms: request", append(attrs, "status", resp.StatusCode)...)
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webkms
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+func TestWithLogger(t *testing.T) {
+	status := http.StatusCreated
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"key_url":"` + r.URL.Path + `/secret-key-id"}`))
+	}))
+	defer srv.Close()
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	keystoreURL := srv.URL + "/v1/keystores/ks1"
+
+	_, _, err := New(keystoreURL, srv.Client(), WithLogger(logger)).Create(kms.ED25519Type)
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), `level=DEBUG msg="webkms: request" method=POST url=`+keystoreURL+"/keys")
+	require.Contains(t, buf.String(), "status=201")
+
+	buf.Reset()
+
+	_, _, err = New(keystoreURL, failingClient{}, WithLogger(logger)).ExportPubKeyBytes("secret-key-id")
+	require.ErrorIs(t, err, kms.ErrRemoteUnavailable)
+	require.Contains(t, buf.String(), `level=WARN msg="webkms: remote server unavailable"`)
+	require.Contains(t, buf.String(), "error=\"remote server unavailable: connection refused\"")
+	require.NotContains(t, buf.String(), "secret-key-id")
+
+	buf.Reset()
+
+	status = http.StatusServiceUnavailable
+
+	_, _, err = New(keystoreURL, srv.Client(), WithLogger(logger)).Create(kms.ED25519Type)
+	require.ErrorIs(t, err, kms.ErrRemoteUnavailable)
+	require.Contains(t, buf.String(), `level=WARN msg="webkms: remote server unavailable"`)
+	require.Contains(t, buf.String(), "status=503")
+
+	// without logger, the events are discarded.
+	_, _, err = New(keystoreURL, srv.Client()).Create(kms.ED25519Type)
+	require.Error(t, err)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webkms
+
+import (
+	"fmt"
+
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/x/crypto/ecdsa2p"
+)
+
+const (
+	mpcKeyGenURL       = "/mpc/keygen"
+	mpcKeyGenFinishURL = "/mpc/keygen/finish"
+	mpcSignURL         = "/mpc/sign"
+	mpcSignFinishURL   = "/mpc/sign/finish"
+)
+
+// MPCRemote is the remote party of two-party ECDSA (x/crypto/ecdsa2p) executed by a key server. It is used as the
+// ecdsa2p.Remote of an ecdsa2p.Party holding the device key share, the experimental 'crypto/ecdsa2p' feature must be
+// enabled to create the party.
+type MPCRemote struct {
+	km *RemoteKMS
+}
+
+// NewMPCRemote creates an MPCRemote on the keystore of the given remote KMS.
+func NewMPCRemote(w kms.KeyManager) (*MPCRemote, error) {
+	rkms, ok := w.(*RemoteKMS)
+	if !ok {
+		return nil, fmt.Errorf("cannot use parameter argument as KMS")
+	}
+
+	return &MPCRemote{km: rkms}, nil
+}
+
+// KeyGen starts a two-party key generation on the key server.
+func (m *MPCRemote) KeyGen(req *ecdsa2p.KeyGenRequest) (*ecdsa2p.KeyGenResponse, error) {
+	resp := &ecdsa2p.KeyGenResponse{}
+
+	return resp, m.post(mpcKeyGenURL, "KeyGen", req, resp)
+}
+
+// KeyGenFinish completes a two-party key generation on the key server.
+func (m *MPCRemote) KeyGenFinish(req *ecdsa2p.KeyGenFinishRequest) (*ecdsa2p.KeyGenFinishResponse, error) {
+	resp := &ecdsa2p.KeyGenFinishResponse{}
+
+	return resp, m.post(mpcKeyGenFinishURL, "KeyGenFinish", req, resp)
+}
+
+// Sign starts a two-party signing session on the key server.
+func (m *MPCRemote) Sign(req *ecdsa2p.SignRequest) (*ecdsa2p.SignResponse, error) {
+	resp := &ecdsa2p.SignResponse{}
+
+	return resp, m.post(mpcSignURL, "Sign", req, resp)
+}
+
+// SignFinish completes a two-party signing session on the key server.
+func (m *MPCRemote) SignFinish(req *ecdsa2p.SignFinishRequest) (*ecdsa2p.SignFinishResponse, error) {
+	resp := &ecdsa2p.SignFinishResponse{}
+
+	return resp, m.post(mpcSignFinishURL, "SignFinish", req, resp)
+}
+
+func (m *MPCRemote) post(path, action string, req, resp interface{}) error {
+	destination := m.km.keystoreURL + path
+
+	mReq, err := m.km.marshalFunc(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request [%s, %w]", action, destination, err)
+	}
+
+	httpResp, err := m.km.postHTTPRequest(destination, mReq)
+	if err != nil {
+		return fmt.Errorf("posting %s request failed [%s, %w]", action, destination, err)
+	}
+
+	defer closeResponseBody(httpResp.Body, action)
+
+	err = readResponse(httpResp, resp, m.km.unmarshalFunc)
+	if err != nil {
+		return fmt.Errorf("%s failed [%s, %w]", action, destination, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webkms
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	mockkms "github.com/trustbloc/kms-go/mock/kms"
+	"github.com/trustbloc/kms-go/x/crypto/ecdsa2p"
+)
+
+func TestMPCRemote(t *testing.T) {
+	_, err := NewMPCRemote(&mockkms.KeyManager{})
+	require.EqualError(t, err, "cannot use parameter argument as KMS")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/keystores/ks/mpc/sign":
+			require.NoError(t, json.NewEncoder(w).Encode(&ecdsa2p.SignResponse{SessionID: "session"}))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			require.NoError(t, json.NewEncoder(w).Encode(&errMessage{Error: "mpc: bad request"}))
+		}
+	}))
+	defer srv.Close()
+
+	remote, err := NewMPCRemote(New(srv.URL+"/v1/keystores/ks", srv.Client()))
+	require.NoError(t, err)
+
+	resp, err := remote.Sign(&ecdsa2p.SignRequest{KeyID: "kid"})
+	require.NoError(t, err)
+	require.Equal(t, "session", resp.SessionID)
+
+	_, err = remote.KeyGen(&ecdsa2p.KeyGenRequest{})
+	require.ErrorContains(t, err, "mpc: bad request")
+
+	_, err = remote.KeyGenFinish(&ecdsa2p.KeyGenFinishRequest{})
+	require.ErrorContains(t, err, "KeyGenFinish failed")
+
+	_, err = remote.SignFinish(&ecdsa2p.SignFinishRequest{})
+	require.ErrorContains(t, err, "SignFinish failed")
+}
@@ -21,6 +21,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/trustbloc/kms-go/doc/util/pubkeyfmt"
+	"github.com/trustbloc/kms-go/kms/audit"
 	"github.com/trustbloc/kms-go/spi/kms"
 )
 
@@ -32,6 +34,8 @@ const (
 	ContentType = "application/json"
 
 	logPrefix = " [kms-go/kms/webkms] "
+
+	capabilitiesURI = "/capabilities"
 )
 
 var errorLogger = log.New(os.Stderr, logPrefix, log.Ldate|log.Ltime|log.LUTC)
@@ -105,6 +109,7 @@ type RemoteKMS struct {
 	opts          *Opts
 }
 
+// checkError returns the *kms.RemoteError of the non 2xx response resp.
 func checkError(resp *http.Response) error {
 	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
 		return nil
@@ -113,10 +118,10 @@ func checkError(resp *http.Response) error {
 	var errAPI errMessage
 
 	if err := json.NewDecoder(resp.Body).Decode(&errAPI); err != nil {
-		return err
+		errAPI.Error = err.Error()
 	}
 
-	return errors.New(errAPI.Error)
+	return &kms.RemoteError{StatusCode: resp.StatusCode, Message: errAPI.Error}
 }
 
 // CreateKeyStore calls the key server's create keystore REST function and returns the resulting keystoreURL value.
@@ -162,20 +167,13 @@ func CreateKeyStore(httpClient HTTPClient, keyserverURL, controller, vaultURL st
 
 	httpReq.Header.Set("Content-Type", ContentType)
 
-	if kmsOpts.HeadersFunc != nil {
-		httpHeaders, e := kmsOpts.HeadersFunc(httpReq)
-		if e != nil {
-			return "", nil, fmt.Errorf("add optional request headers error: %w", e)
-		}
-
-		if httpHeaders != nil {
-			httpReq.Header = httpHeaders.Clone()
-		}
+	if err = SetHeaders(kmsOpts, httpReq); err != nil {
+		return "", nil, err
 	}
 
 	start := time.Now()
 
-	resp, err := httpClient.Do(httpReq)
+	resp, err := DoTraced(kmsOpts, httpClient, httpReq)
 	if err != nil {
 		return "", nil, fmt.Errorf("posting Create keystore failed [%s, %w]", destination, err)
 	}
@@ -249,20 +247,14 @@ func (r *RemoteKMS) doHTTPRequest(method, destination string, mReq []byte) (*htt
 		httpReq.Header.Set("Content-Type", ContentType)
 	}
 
-	if r.opts.HeadersFunc != nil {
-		httpHeaders, e := r.opts.HeadersFunc(httpReq)
-		if e != nil {
-			return nil, fmt.Errorf("add optional request headers error: %w", e)
-		}
-
-		if httpHeaders != nil {
-			httpReq.Header = httpHeaders.Clone()
-		}
+	if err = SetHeaders(r.opts, httpReq); err != nil {
+		return nil, err
 	}
 
-	resp, err := r.httpClient.Do(httpReq)
+	resp, err := DoTraced(r.opts, r.httpClient, httpReq)
 
 	debugLogger.Printf("  HTTP %s %s call duration: %s", method, destination, time.Since(start))
+	LogRequest(r.opts, httpReq, resp, err, start)
 
 	return resp, err
 }
@@ -288,6 +280,15 @@ func (r *RemoteKMS) Create(kt kms.KeyType, opts ...kms.KeyOpts) (string, interfa
 }
 
 func (r *RemoteKMS) createKey(kt kms.KeyType, opts ...kms.KeyOpts) (string, []byte, error) {
+	start := time.Now()
+	keyURL, pubKey, err := r.postCreateKey(kt, opts...)
+
+	audit.Log(r.opts.AuditLogger, kms.OperationCreate, keyURL[strings.LastIndex(keyURL, "/")+1:], nil, start, err)
+
+	return keyURL, pubKey, err
+}
+
+func (r *RemoteKMS) postCreateKey(kt kms.KeyType, opts ...kms.KeyOpts) (string, []byte, error) {
 	destination := r.keystoreURL + "/keys"
 
 	keyOpts := kms.NewKeyOpt()
@@ -340,7 +341,10 @@ func (r *RemoteKMS) HealthCheck() error {
 	defer closeResponseBody(resp.Body, "HealthCheck")
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("kms health check return %d status code", resp.StatusCode)
+		return &kms.RemoteError{
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("kms health check return %d status code", resp.StatusCode),
+		}
 	}
 
 	return nil
@@ -374,6 +378,15 @@ func (r *RemoteKMS) Rotate(kt kms.KeyType, keyID string, opts ...kms.KeyOpts) (s
 //   - marshalled public key []byte
 //   - error if it fails to export the public key bytes
 func (r *RemoteKMS) ExportPubKeyBytes(keyID string) ([]byte, kms.KeyType, error) {
+	start := time.Now()
+	pubKey, kt, err := r.exportPubKeyBytes(keyID)
+
+	audit.Log(r.opts.AuditLogger, kms.OperationExportPublicKey, keyID, nil, start, err)
+
+	return pubKey, kt, err
+}
+
+func (r *RemoteKMS) exportPubKeyBytes(keyID string) ([]byte, kms.KeyType, error) {
 	startExport := time.Now()
 	keyURL := r.buildKIDURL(keyID)
 
@@ -399,6 +412,43 @@ func (r *RemoteKMS) ExportPubKeyBytes(keyID string) ([]byte, kms.KeyType, error)
 	return httpResp.PublicKey, kms.KeyType(httpResp.KeyType), nil
 }
 
+// ExportPubKey remotely fetches the public key of the key keyID and returns it encoded in format, see kms.PubKeyFormat.
+func (r *RemoteKMS) ExportPubKey(keyID string, format kms.PubKeyFormat) ([]byte, error) {
+	pubKeyBytes, kt, err := r.ExportPubKeyBytes(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := pubkeyfmt.Encode(keyID, pubKeyBytes, kt, format)
+	if err != nil {
+		return nil, fmt.Errorf("export pub key failed: %w", err)
+	}
+
+	return encoded, nil
+}
+
+// Capabilities fetches the key types, algorithms, operations and limits supported by the remote keystore.
+func (r *RemoteKMS) Capabilities() (*kms.Capabilities, error) {
+	destination := r.keystoreURL + capabilitiesURI
+
+	resp, err := r.getHTTPRequest(destination)
+	if err != nil {
+		return nil, fmt.Errorf("posting GET Capabilities failed [%s, %w]", destination, err)
+	}
+
+	// handle response
+	defer closeResponseBody(resp.Body, "Capabilities")
+
+	caps := &kms.Capabilities{}
+
+	err = readResponse(resp, caps, r.unmarshalFunc)
+	if err != nil {
+		return nil, fmt.Errorf("get capabilities failed [%s, %w]", destination, err)
+	}
+
+	return caps, nil
+}
+
 // CreateAndExportPubKeyBytes will remotely create a key of type kt and export its public key in raw bytes and returns
 // it. The key must be an asymmetric key.
 // Returns:
@@ -436,6 +486,16 @@ func (r *RemoteKMS) PubKeyBytesToHandle(pubKey []byte, kt kms.KeyType, opts ...k
 //   - handle instance (to private key)
 //   - error if import failure (key empty, invalid, doesn't match KeyType, unsupported KeyType or storing key failed)
 func (r *RemoteKMS) ImportPrivateKey(privKey interface{}, kt kms.KeyType,
+	opts ...kms.PrivateKeyOpts) (string, interface{}, error) {
+	start := time.Now()
+	keyID, keyURL, err := r.importPrivateKey(privKey, kt, opts...)
+
+	audit.Log(r.opts.AuditLogger, kms.OperationImportPrivate, keyID, nil, start, err)
+
+	return keyID, keyURL, err
+}
+
+func (r *RemoteKMS) importPrivateKey(privKey interface{}, kt kms.KeyType,
 	opts ...kms.PrivateKeyOpts) (string, interface{}, error) {
 	pOpts := kms.NewOpt()
 
@@ -0,0 +1,152 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webkms
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+// Span attribute names, following the OpenTelemetry semantic conventions for HTTP clients.
+const (
+	AttrHTTPMethod     = "http.request.method"
+	AttrURL            = "url.full"
+	AttrHTTPStatusCode = "http.response.status_code"
+	// AttrKeyIDHash is the hex encoded truncated SHA-256 hash of the key ID, key IDs are never recorded in clear.
+	AttrKeyIDHash = "kms.key_id.hash"
+)
+
+const (
+	keysPathSegment = "/keys/"
+	keyIDHashSize   = 8
+)
+
+// WithTracer option creates a span with tracer for every HTTP call of remoteKMS and remoteCrypto and propagates its
+// trace context in the request headers, with the propagator set by WithPropagator. The key IDs in the request URL
+// are hashed in the span attributes.
+func WithTracer(tracer trace.Tracer) Opt {
+	return func(opts *Opts) {
+		opts.Tracer = tracer
+	}
+}
+
+// WithPropagator option sets the propagator injecting the trace context of the WithTracer spans in the request
+// headers, eg: propagation.TraceContext for the W3C traceparent and tracestate headers. By default, the global
+// propagator of otel.GetTextMapPropagator is used.
+func WithPropagator(propagator propagation.TextMapPropagator) Opt {
+	return func(opts *Opts) {
+		opts.Propagator = propagator
+	}
+}
+
+// DoTraced executes req with client in a span of the tracer of opts. It is client.Do(req) if opts has no tracer.
+// Failed requests errors wrap kms.ErrRemoteUnavailable.
+// Not to be used directly. It's intended for implementations of remoteKMS.
+func DoTraced(opts *Opts, client HTTPClient, req *http.Request) (*http.Response, error) {
+	if opts.Tracer == nil {
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", kms.ErrRemoteUnavailable, err)
+		}
+
+		return resp, nil
+	}
+
+	url, keyIDHash := redactKeyID(req.URL.String())
+
+	attrs := []attribute.KeyValue{
+		attribute.String(AttrHTTPMethod, req.Method),
+		attribute.String(AttrURL, url),
+	}
+
+	if keyIDHash != "" {
+		attrs = append(attrs, attribute.String(AttrKeyIDHash, keyIDHash))
+	}
+
+	ctx, span := opts.Tracer.Start(req.Context(), "webkms "+req.Method+" "+operationName(req.URL.Path),
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	defer span.End()
+
+	propagator := opts.Propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+
+	req = req.WithContext(ctx)
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, fmt.Errorf("%w: %w", kms.ErrRemoteUnavailable, err)
+	}
+
+	span.SetAttributes(attribute.Int(AttrHTTPStatusCode, resp.StatusCode))
+
+	// as per the semantic conventions, the client spans of responses with 4xx status codes are not failed.
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+
+	return resp, nil
+}
+
+// redactKeyID replaces the key ID of the key URL url with its hash, which is also returned.
+func redactKeyID(url string) (string, string) {
+	i := strings.LastIndex(url, keysPathSegment)
+	if i < 0 {
+		return url, ""
+	}
+
+	start := i + len(keysPathSegment)
+	end := strings.IndexAny(url[start:], "/?")
+
+	if end < 0 {
+		end = len(url) - start
+	}
+
+	keyID := url[start : start+end]
+	if keyID == "" {
+		return url, ""
+	}
+
+	h := sha256.Sum256([]byte(keyID))
+	keyIDHash := hex.EncodeToString(h[:keyIDHashSize])
+
+	return url[:start] + "sha256-" + keyIDHash + url[start+end:], keyIDHash
+}
+
+// operationName returns the operation of the request path: its last segment for key operations (eg: 'sign'), 'key'
+// for key requests and 'keys' for key creation requests.
+func operationName(path string) string {
+	path = strings.TrimSuffix(path, "/")
+
+	i := strings.LastIndex(path, keysPathSegment)
+	if i < 0 {
+		return path[strings.LastIndex(path, "/")+1:]
+	}
+
+	rest := strings.Split(path[i+len(keysPathSegment):], "/")
+	if len(rest) > 1 {
+		return rest[len(rest)-1]
+	}
+
+	return "key"
+}
@@ -0,0 +1,113 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webkms
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+type failingClient struct{}
+
+func (failingClient) Do(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestWithTracer(t *testing.T) {
+	var traceparent string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"key_url":"` + r.URL.Path + `/secret-key-id"}`))
+	}))
+	defer srv.Close()
+
+	rec := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)).Tracer("webkms")
+	keystoreURL := srv.URL + "/v1/keystores/ks1"
+
+	_, keyURL, err := New(keystoreURL, srv.Client(), WithTracer(tracer),
+		WithPropagator(propagation.TraceContext{})).Create(kms.ED25519Type)
+	require.NoError(t, err)
+	require.Contains(t, keyURL, "secret-key-id")
+
+	_, _, err = New(keystoreURL, failingClient{}, WithTracer(tracer)).ExportPubKeyBytes("secret-key-id")
+	require.ErrorIs(t, err, kms.ErrRemoteUnavailable)
+
+	spans := rec.Ended()
+	require.Len(t, spans, 2)
+
+	create := spans[0]
+	require.Equal(t, "webkms POST keys", create.Name())
+	require.Equal(t, "00-"+create.SpanContext().TraceID().String()+"-"+create.SpanContext().SpanID().String()+"-01",
+		traceparent)
+
+	attrs := spanAttributes(create)
+	require.Equal(t, http.MethodPost, attrs[AttrHTTPMethod].AsString())
+	require.Equal(t, keystoreURL+"/keys", attrs[AttrURL].AsString())
+	require.Equal(t, int64(http.StatusCreated), attrs[AttrHTTPStatusCode].AsInt64())
+	require.Equal(t, codes.Unset, create.Status().Code)
+
+	export := spans[1]
+	require.Equal(t, "webkms GET export", export.Name())
+
+	attrs = spanAttributes(export)
+	require.NotEmpty(t, attrs[AttrKeyIDHash].AsString())
+	require.Equal(t, keystoreURL+"/keys/sha256-"+attrs[AttrKeyIDHash].AsString()+"/export", attrs[AttrURL].AsString())
+	require.Equal(t, codes.Error, export.Status().Code)
+	require.Equal(t, "connection refused", export.Status().Description)
+	require.Len(t, export.Events(), 1)
+	require.Equal(t, "exception", export.Events()[0].Name)
+
+	for _, s := range spans {
+		for _, kv := range s.Attributes() {
+			require.False(t, strings.Contains(kv.Value.Emit(), "secret-key-id"))
+		}
+	}
+}
+
+func spanAttributes(s sdktrace.ReadOnlySpan) map[string]attribute.Value {
+	attrs := map[string]attribute.Value{}
+
+	for _, kv := range s.Attributes() {
+		attrs[string(kv.Key)] = kv.Value
+	}
+
+	return attrs
+}
+
+func TestRedactKeyID(t *testing.T) {
+	url, hash := redactKeyID("https://kms.example.com/v1/keystores/ks1/keys/kid1")
+	require.Len(t, hash, 16)
+	require.Equal(t, "https://kms.example.com/v1/keystores/ks1/keys/sha256-"+hash, url)
+
+	url2, hash2 := redactKeyID("https://kms.example.com/v1/keystores/ks1/keys/kid1?format=jwk")
+	require.Equal(t, hash, hash2)
+	require.Equal(t, "https://kms.example.com/v1/keystores/ks1/keys/sha256-"+hash+"?format=jwk", url2)
+
+	url, hash = redactKeyID("https://kms.example.com/v1/keystores/ks1/capabilities")
+	require.Empty(t, hash)
+	require.Equal(t, "https://kms.example.com/v1/keystores/ks1/capabilities", url)
+
+	require.Equal(t, "sign", operationName("/v1/keystores/ks1/keys/kid1/sign"))
+	require.Equal(t, "key", operationName("/v1/keystores/ks1/keys/kid1"))
+	require.Equal(t, "wrap", operationName("/v1/keystores/ks1/wrap"))
+}
@@ -0,0 +1,406 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webkms
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// CapabilityInvocationHeader is the HTTP header of the capability invoked by a request, signed with the request.
+	CapabilityInvocationHeader = "Capability-Invocation"
+	// SignatureHeader is the HTTP header of the HTTP signature of a capability invocation.
+	SignatureHeader = "Signature"
+
+	digestHeader       = "Digest"
+	signatureAlgorithm = "hs2019"
+)
+
+// ErrCapabilityNotFound is returned by a CapabilityStore when it has no capability for an invocation target.
+var ErrCapabilityNotFound = errors.New("capability not found")
+
+// Capability is a ZCAP-LD authorization capability (https://w3c-ccg.github.io/zcap-spec/) issued by a key server, eg:
+// the capability returned by CreateKeyStore, or delegated from such a capability. Its proofs, delegation chain
+// included, are kept as issued and verified by the key server only.
+type Capability struct {
+	ID               string           `json:"id"`
+	InvocationTarget InvocationTarget `json:"invocationTarget"`
+	Controller       string           `json:"controller,omitempty"`
+	Invoker          string           `json:"invoker,omitempty"`
+	ParentCapability string           `json:"parentCapability,omitempty"`
+	AllowedAction    []string         `json:"allowedAction,omitempty"`
+	Expires          string           `json:"expires,omitempty"`
+
+	raw []byte
+}
+
+// InvocationTarget is the resource a Capability grants access to: a keystore URL or a key URL.
+type InvocationTarget struct {
+	ID   string `json:"id"`
+	Type string `json:"type,omitempty"`
+}
+
+// UnmarshalJSON unmarshals an invocation target object, or the URL of the target as the latest ZCAP-LD drafts do.
+func (t *InvocationTarget) UnmarshalJSON(b []byte) error {
+	var id string
+
+	if err := json.Unmarshal(b, &id); err == nil {
+		*t = InvocationTarget{ID: id}
+
+		return nil
+	}
+
+	type target InvocationTarget
+
+	return json.Unmarshal(b, (*target)(t))
+}
+
+// ParseCapability parses the JSON capability b.
+func ParseCapability(b []byte) (*Capability, error) {
+	c := &Capability{}
+
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("parse capability: %w", err)
+	}
+
+	if c.ID == "" || c.InvocationTarget.ID == "" {
+		return nil, errors.New("parse capability: missing id or invocation target")
+	}
+
+	c.raw = append([]byte{}, b...)
+
+	return c, nil
+}
+
+// Bytes returns the JSON capability, as issued if it was parsed with ParseCapability.
+func (c *Capability) Bytes() ([]byte, error) {
+	if c.raw != nil {
+		return c.raw, nil
+	}
+
+	return json.Marshal(c)
+}
+
+// allows reports whether the capability allows action at time now.
+func (c *Capability) allows(action string, now time.Time) error {
+	if c.Expires != "" {
+		expires, err := time.Parse(time.RFC3339, c.Expires)
+		if err != nil {
+			return fmt.Errorf("capability %s: invalid expiration: %w", c.ID, err)
+		}
+
+		if !now.Before(expires) {
+			return fmt.Errorf("capability %s expired", c.ID)
+		}
+	}
+
+	if len(c.AllowedAction) == 0 {
+		return nil
+	}
+
+	for _, a := range c.AllowedAction {
+		if a == action {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("capability %s does not allow action '%s'", c.ID, action)
+}
+
+// CapabilityStore stores the capabilities invoked by a CapabilityInvoker.
+type CapabilityStore interface {
+	// Get returns the capability of the invocation target URL target, or ErrCapabilityNotFound.
+	Get(target string) (*Capability, error)
+}
+
+// MemCapabilityStore is an in-memory CapabilityStore.
+type MemCapabilityStore struct {
+	mu           sync.RWMutex
+	capabilities map[string]*Capability
+}
+
+// NewMemCapabilityStore creates a new empty MemCapabilityStore.
+func NewMemCapabilityStore() *MemCapabilityStore {
+	return &MemCapabilityStore{capabilities: map[string]*Capability{}}
+}
+
+// Put stores c for its invocation target, replacing the capability already stored for the target.
+func (s *MemCapabilityStore) Put(c *Capability) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.capabilities[c.InvocationTarget.ID] = c
+}
+
+// Get returns the capability of target, or ErrCapabilityNotFound.
+func (s *MemCapabilityStore) Get(target string) (*Capability, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.capabilities[target]
+	if !ok {
+		return nil, ErrCapabilityNotFound
+	}
+
+	return c, nil
+}
+
+// InvocationSigner signs capability invocations with the key of the invoker of the capabilities, eg: a
+// kmssigner.KMSSigner of an Ed25519 key.
+type InvocationSigner interface {
+	Sign(data []byte) ([]byte, error)
+}
+
+// ActionFunc returns the capability action of a request.
+type ActionFunc func(req *http.Request) string
+
+// CapabilityInvoker invokes the capabilities of a CapabilityStore in the requests of remoteKMS and remoteCrypto: it
+// attaches the capability of the request URL and signs the request with an HTTP signature
+// (https://datatracker.ietf.org/doc/html/draft-cavage-http-signatures-12) of the invoker key.
+type CapabilityInvoker struct {
+	store              CapabilityStore
+	signer             InvocationSigner
+	verificationMethod string
+	action             ActionFunc
+	now                func() time.Time
+}
+
+// InvokerOpt is a CapabilityInvoker option.
+type InvokerOpt func(i *CapabilityInvoker)
+
+// WithActionFunc sets the function returning the capability action of a request, DefaultAction by default.
+func WithActionFunc(f ActionFunc) InvokerOpt {
+	return func(i *CapabilityInvoker) {
+		i.action = f
+	}
+}
+
+// WithInvocationClock sets the clock of the invocations, time.Now by default.
+func WithInvocationClock(now func() time.Time) InvokerOpt {
+	return func(i *CapabilityInvoker) {
+		i.now = now
+	}
+}
+
+// NewCapabilityInvoker creates a new CapabilityInvoker of the capabilities of store, signing the invocations with
+// signer. verificationMethod is the key ID of the signatures, the verification method of the invoker of the
+// capabilities, eg: a did:key URL.
+func NewCapabilityInvoker(store CapabilityStore, signer InvocationSigner, verificationMethod string,
+	opts ...InvokerOpt) *CapabilityInvoker {
+	i := &CapabilityInvoker{
+		store:              store,
+		signer:             signer,
+		verificationMethod: verificationMethod,
+		action:             DefaultAction,
+		now:                time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return i
+}
+
+// WithCapabilityInvoker option invokes the capabilities of invoker in the HTTP calls of remoteKMS and remoteCrypto,
+// after setting the headers of WithHeaders. The requests to URLs without capability are sent as is.
+func WithCapabilityInvoker(invoker *CapabilityInvoker) Opt {
+	return func(opts *Opts) {
+		opts.CapabilityInvoker = invoker
+	}
+}
+
+// Invoke sets the capability invocation headers of req: the capability of the longest prefix of the request URL in
+// the store and the signature of the invocation. It leaves req unchanged if no prefix has a capability.
+func (i *CapabilityInvoker) Invoke(req *http.Request) error {
+	c, err := i.capability(req.URL.Scheme + "://" + req.URL.Host + req.URL.Path)
+	if err != nil || c == nil {
+		return err
+	}
+
+	action := i.action(req)
+	now := i.now()
+
+	if err = c.allows(action, now); err != nil {
+		return err
+	}
+
+	invocation, err := compressCapability(c)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set(CapabilityInvocationHeader, fmt.Sprintf(`zcap capability="%s",action="%s"`, invocation, action))
+
+	return i.sign(req, now)
+}
+
+// capability returns the capability of the longest prefix of target, or nil if there is none.
+func (i *CapabilityInvoker) capability(target string) (*Capability, error) {
+	target = strings.TrimSuffix(target, "/")
+
+	for {
+		c, err := i.store.Get(target)
+		if err == nil {
+			return c, nil
+		}
+
+		if !errors.Is(err, ErrCapabilityNotFound) {
+			return nil, fmt.Errorf("get capability: %w", err)
+		}
+
+		idx := strings.LastIndex(target, "/")
+		if idx <= strings.Index(target, "://")+len("://") {
+			return nil, nil
+		}
+
+		target = target[:idx]
+	}
+}
+
+func (i *CapabilityInvoker) sign(req *http.Request, now time.Time) error {
+	headers := []string{"(request-target)", "(created)", "host", strings.ToLower(CapabilityInvocationHeader)}
+
+	if req.GetBody != nil && req.ContentLength != 0 {
+		body, err := req.GetBody()
+		if err != nil {
+			return fmt.Errorf("read request body: %w", err)
+		}
+
+		h := sha256.New()
+
+		if _, err = io.Copy(h, body); err != nil {
+			return fmt.Errorf("read request body: %w", err)
+		}
+
+		req.Header.Set(digestHeader, "SHA-256="+base64.StdEncoding.EncodeToString(h.Sum(nil)))
+		headers = append(headers, strings.ToLower(digestHeader))
+	}
+
+	created := strconv.FormatInt(now.Unix(), 10)
+	lines := make([]string, len(headers))
+
+	for n, h := range headers {
+		switch h {
+		case "(request-target)":
+			lines[n] = h + ": " + strings.ToLower(req.Method) + " " + req.URL.RequestURI()
+		case "(created)":
+			lines[n] = h + ": " + created
+		case "host":
+			lines[n] = h + ": " + req.URL.Host
+		default:
+			lines[n] = h + ": " + req.Header.Get(h)
+		}
+	}
+
+	sig, err := i.signer.Sign([]byte(strings.Join(lines, "\n")))
+	if err != nil {
+		return fmt.Errorf("sign capability invocation: %w", err)
+	}
+
+	req.Header.Set(SignatureHeader, fmt.Sprintf(`keyId="%s",algorithm="%s",created=%s,headers="%s",signature="%s"`,
+		i.verificationMethod, signatureAlgorithm, created, strings.Join(headers, " "),
+		base64.StdEncoding.EncodeToString(sig)))
+
+	return nil
+}
+
+// compressCapability returns the base64url encoded gzip compression of the capability c, as the capability
+// invocation header holds it.
+func compressCapability(c *Capability) (string, error) {
+	b, err := c.Bytes()
+	if err != nil {
+		return "", fmt.Errorf("marshal capability: %w", err)
+	}
+
+	var buf bytes.Buffer
+
+	w := gzip.NewWriter(&buf)
+
+	if _, err = w.Write(b); err != nil {
+		return "", fmt.Errorf("compress capability: %w", err)
+	}
+
+	if err = w.Close(); err != nil {
+		return "", fmt.Errorf("compress capability: %w", err)
+	}
+
+	return base64.URLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+//nolint:gochecknoglobals
+var actions = map[string]string{
+	"computemac":  "computeMAC",
+	"verifymac":   "verifyMAC",
+	"signmulti":   "signMulti",
+	"verifymulti": "verifyMulti",
+	"deriveproof": "deriveProof",
+	"verifyproof": "verifyProof",
+	"easyopen":    "easyOpen",
+	"sealopen":    "sealOpen",
+}
+
+// DefaultAction returns the capability action of the requests of remoteKMS and remoteCrypto: createKey, importKey
+// and exportKey for the key creations, imports and exports, read for the other GET requests and the name of the
+// operation of the URL path otherwise, eg: sign, unwrap or computeMAC.
+func DefaultAction(req *http.Request) string {
+	path := strings.TrimSuffix(req.URL.Path, "/")
+	op := path[strings.LastIndex(path, "/")+1:]
+
+	switch {
+	case op == "keys" && req.Method == http.MethodPost:
+		return "createKey"
+	case op == "keys" && req.Method == http.MethodPut:
+		return "importKey"
+	case op == "export":
+		return "exportKey"
+	case req.Method == http.MethodGet:
+		return "read"
+	}
+
+	if a, ok := actions[op]; ok {
+		return a
+	}
+
+	return op
+}
+
+// SetHeaders sets the headers of opts in req: the headers of the HeadersFunc then the capability invocation headers
+// of the CapabilityInvoker.
+// Not to be used directly. It's intended for implementations of remoteKMS.
+func SetHeaders(opts *Opts, req *http.Request) error {
+	if opts.HeadersFunc != nil {
+		httpHeaders, err := opts.HeadersFunc(req)
+		if err != nil {
+			return fmt.Errorf("add optional request headers error: %w", err)
+		}
+
+		if httpHeaders != nil {
+			req.Header = httpHeaders.Clone()
+		}
+	}
+
+	if opts.CapabilityInvoker != nil {
+		if err := opts.CapabilityInvoker.Invoke(req); err != nil {
+			return fmt.Errorf("capability invocation error: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,263 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webkms
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testKeystoreURL        = "https://kms.example.com/v1/keystores/12345"
+	testVerificationMethod = "did:example:invoker#key-1"
+)
+
+type ed25519Signer ed25519.PrivateKey
+
+func (s ed25519Signer) Sign(data []byte) ([]byte, error) {
+	return ed25519.Sign(ed25519.PrivateKey(s), data), nil
+}
+
+func TestCapabilityInvoker_Invoke(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	capability := []byte(`{"@context":"https://w3id.org/security/v2","id":"urn:zcap:root","invocationTarget":{` +
+		`"id":"` + testKeystoreURL + `","type":"urn:kms:keystore"},"controller":"did:example:controller",` +
+		`"invoker":"did:example:invoker","allowedAction":["createKey","sign","read"],` +
+		`"expires":"2030-01-01T00:00:00Z","proof":[{"capabilityChain":["urn:zcap:parent"]}]}`)
+
+	c, err := ParseCapability(capability)
+	require.NoError(t, err)
+	require.Equal(t, testKeystoreURL, c.InvocationTarget.ID)
+
+	store := NewMemCapabilityStore()
+	store.Put(c)
+
+	invoker := NewCapabilityInvoker(store, ed25519Signer(privKey), testVerificationMethod,
+		WithInvocationClock(func() time.Time { return now }))
+
+	t.Run("signed request with body", func(t *testing.T) {
+		body := []byte(`{"message":"dGVzdA=="}`)
+
+		req, err := http.NewRequest(http.MethodPost, testKeystoreURL+"/keys/abc/sign?x=1", bytes.NewReader(body))
+		require.NoError(t, err)
+
+		require.NoError(t, invoker.Invoke(req))
+
+		digest := sha256.Sum256(body)
+		require.Equal(t, "SHA-256="+base64.StdEncoding.EncodeToString(digest[:]), req.Header.Get("Digest"))
+
+		invocation := req.Header.Get(CapabilityInvocationHeader)
+		m := regexp.MustCompile(`^zcap capability="([^"]+)",action="sign"$`).FindStringSubmatch(invocation)
+		require.Len(t, m, 2)
+		require.Equal(t, capability, decompress(t, m[1]))
+
+		signingString := strings.Join([]string{
+			"(request-target): post /v1/keystores/12345/keys/abc/sign?x=1",
+			"(created): 1700000000",
+			"host: kms.example.com",
+			"capability-invocation: " + invocation,
+			"digest: " + req.Header.Get("Digest"),
+		}, "\n")
+
+		verifySignature(t, pubKey, req.Header.Get(SignatureHeader), signingString,
+			"(request-target) (created) host capability-invocation digest")
+	})
+
+	t.Run("signed request without body", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, testKeystoreURL+"/keys/abc", nil)
+		require.NoError(t, err)
+
+		require.NoError(t, invoker.Invoke(req))
+		require.Empty(t, req.Header.Get("Digest"))
+
+		invocation := req.Header.Get(CapabilityInvocationHeader)
+		require.True(t, strings.HasSuffix(invocation, `,action="read"`))
+
+		signingString := strings.Join([]string{
+			"(request-target): get /v1/keystores/12345/keys/abc",
+			"(created): 1700000000",
+			"host: kms.example.com",
+			"capability-invocation: " + invocation,
+		}, "\n")
+
+		verifySignature(t, pubKey, req.Header.Get(SignatureHeader), signingString,
+			"(request-target) (created) host capability-invocation")
+	})
+
+	t.Run("URL without capability", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "https://kms.example.com/healthcheck", nil)
+		require.NoError(t, err)
+
+		require.NoError(t, invoker.Invoke(req))
+		require.Empty(t, req.Header)
+	})
+
+	t.Run("action not allowed", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, testKeystoreURL+"/keys/abc/unwrap", nil)
+		require.NoError(t, err)
+
+		require.EqualError(t, invoker.Invoke(req), "capability urn:zcap:root does not allow action 'unwrap'")
+	})
+
+	t.Run("expired capability", func(t *testing.T) {
+		expired := NewCapabilityInvoker(store, ed25519Signer(privKey), testVerificationMethod,
+			WithInvocationClock(func() time.Time { return time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC) }))
+
+		req, err := http.NewRequest(http.MethodPost, testKeystoreURL+"/keys", nil)
+		require.NoError(t, err)
+
+		require.EqualError(t, expired.Invoke(req), "capability urn:zcap:root expired")
+	})
+
+	t.Run("custom action", func(t *testing.T) {
+		custom := NewCapabilityInvoker(store, ed25519Signer(privKey), testVerificationMethod,
+			WithActionFunc(func(*http.Request) string { return "createKey" }))
+
+		req, err := http.NewRequest(http.MethodGet, testKeystoreURL, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, custom.Invoke(req))
+		require.True(t, strings.HasSuffix(req.Header.Get(CapabilityInvocationHeader), `,action="createKey"`))
+	})
+
+	t.Run("store and signer errors", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, testKeystoreURL, nil)
+		require.NoError(t, err)
+
+		failing := NewCapabilityInvoker(failingCapabilityStore{}, ed25519Signer(privKey), testVerificationMethod)
+		require.EqualError(t, failing.Invoke(req), "get capability: store error")
+
+		failing = NewCapabilityInvoker(store, failingSigner{}, testVerificationMethod,
+			WithInvocationClock(func() time.Time { return now }))
+		require.EqualError(t, failing.Invoke(req), "sign capability invocation: sign error")
+	})
+}
+
+func TestParseCapability(t *testing.T) {
+	c, err := ParseCapability([]byte(`{"id":"urn:zcap:1","invocationTarget":"` + testKeystoreURL + `"}`))
+	require.NoError(t, err)
+	require.Equal(t, InvocationTarget{ID: testKeystoreURL}, c.InvocationTarget)
+
+	_, err = ParseCapability([]byte(`{"id":"urn:zcap:1"}`))
+	require.EqualError(t, err, "parse capability: missing id or invocation target")
+
+	_, err = ParseCapability([]byte(`{"id":1}`))
+	require.ErrorContains(t, err, "parse capability:")
+
+	b, err := (&Capability{ID: "urn:zcap:2", InvocationTarget: InvocationTarget{ID: testKeystoreURL}}).Bytes()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"urn:zcap:2","invocationTarget":{"id":"`+testKeystoreURL+`"}}`, string(b))
+}
+
+func TestDefaultAction(t *testing.T) {
+	for _, tc := range []struct {
+		method, path, action string
+	}{
+		{http.MethodPost, "/v1/keystores/1/keys", "createKey"},
+		{http.MethodPut, "/v1/keystores/1/keys", "importKey"},
+		{http.MethodGet, "/v1/keystores/1/keys/2/export", "exportKey"},
+		{http.MethodGet, "/v1/keystores/1/keys/2", "read"},
+		{http.MethodPost, "/v1/keystores/1/keys/2/sign", "sign"},
+		{http.MethodPost, "/v1/keystores/1/keys/2/computemac", "computeMAC"},
+		{http.MethodPost, "/v1/keystores/1/keys/2/deriveproof/", "deriveProof"},
+	} {
+		req, err := http.NewRequest(tc.method, "https://kms.example.com"+tc.path, nil)
+		require.NoError(t, err)
+		require.Equal(t, tc.action, DefaultAction(req), tc.path)
+	}
+}
+
+func TestSetHeaders(t *testing.T) {
+	_, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	store := NewMemCapabilityStore()
+	store.Put(&Capability{ID: "urn:zcap:root", InvocationTarget: InvocationTarget{ID: testKeystoreURL}})
+
+	opts := NewOpt()
+
+	WithHeaders(func(req *http.Request) (*http.Header, error) {
+		h := req.Header.Clone()
+		h.Set("Authorization", "Bearer token")
+
+		return &h, nil
+	})(opts)
+	WithCapabilityInvoker(NewCapabilityInvoker(store, ed25519Signer(privKey), testVerificationMethod))(opts)
+
+	req, err := http.NewRequest(http.MethodPost, testKeystoreURL+"/keys", bytes.NewReader([]byte("{}")))
+	require.NoError(t, err)
+
+	require.NoError(t, SetHeaders(opts, req))
+	require.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+	require.NotEmpty(t, req.Header.Get(CapabilityInvocationHeader))
+	require.NotEmpty(t, req.Header.Get(SignatureHeader))
+
+	WithCapabilityInvoker(NewCapabilityInvoker(store, failingSigner{}, testVerificationMethod))(opts)
+	require.EqualError(t, SetHeaders(opts, req), "capability invocation error: sign capability invocation: sign error")
+
+	WithHeaders(func(*http.Request) (*http.Header, error) {
+		return nil, errors.New("headers error")
+	})(opts)
+	require.EqualError(t, SetHeaders(opts, req), "add optional request headers error: headers error")
+}
+
+func verifySignature(t *testing.T, pubKey ed25519.PublicKey, header, signingString, headers string) {
+	t.Helper()
+
+	m := regexp.MustCompile(`^keyId="([^"]+)",algorithm="hs2019",created=1700000000,headers="([^"]+)",` +
+		`signature="([^"]+)"$`).FindStringSubmatch(header)
+	require.Len(t, m, 4)
+	require.Equal(t, testVerificationMethod, m[1])
+	require.Equal(t, headers, m[2])
+
+	sig, err := base64.StdEncoding.DecodeString(m[3])
+	require.NoError(t, err)
+	require.True(t, ed25519.Verify(pubKey, []byte(signingString), sig))
+}
+
+func decompress(t *testing.T, invocation string) []byte {
+	t.Helper()
+
+	b, err := base64.URLEncoding.DecodeString(invocation)
+	require.NoError(t, err)
+
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	require.NoError(t, err)
+
+	b, err = io.ReadAll(r)
+	require.NoError(t, err)
+
+	return b
+}
+
+type failingCapabilityStore struct{}
+
+func (failingCapabilityStore) Get(string) (*Capability, error) {
+	return nil, errors.New("store error")
+}
+
+type failingSigner struct{}
+
+func (failingSigner) Sign([]byte) ([]byte, error) {
+	return nil, errors.New("sign error")
+}
@@ -45,6 +45,11 @@ func (m *MockSuite) KMSCryptoMultiSigner() (api.KMSCryptoMultiSigner, error) {
 	return (*wrapper.MockKMSCrypto)(m), nil
 }
 
+// KMSCryptoBatchSigner mock.
+func (m *MockSuite) KMSCryptoBatchSigner() (api.KMSCryptoBatchSigner, error) {
+	return (*wrapper.MockKMSCrypto)(m), nil
+}
+
 // EncrypterDecrypter mock.
 func (m *MockSuite) EncrypterDecrypter() (api.EncrypterDecrypter, error) {
 	return (*wrapper.MockKMSCrypto)(m), nil
@@ -65,4 +70,23 @@ func (m *MockSuite) FixedKeyMultiSigner(kid string) (api.FixedKeyMultiSigner, er
 	return (*wrapper.MockKMSCrypto)(m).FixedKeyMultiSigner(nil)
 }
 
+// KeyRef mock.
+func (m *MockSuite) KeyRef(kid string) (api.KeyRef, error) {
+	return &wrapper.MockKeyRef{
+		MockFixedKeyCrypto: wrapper.MockFixedKeyCrypto{SignVal: m.SignVal, SignErr: m.SignErr, VerifyErr: m.VerifyErr},
+		KIDVal:             kid,
+		PublicKeyVal:       m.CreateVal,
+		EncryptVal:         m.EncryptVal,
+		EncryptNonce:       m.EncryptNonce,
+		EncryptErr:         m.EncryptErr,
+		DecryptVal:         m.DecryptVal,
+		DecryptErr:         m.DecryptErr,
+	}, nil
+}
+
+// KeyDeriver mock.
+func (m *MockSuite) KeyDeriver() (api.KeyDeriver, error) {
+	return (*wrapper.MockKMSCrypto)(m), nil
+}
+
 var _ api.Suite = &MockSuite{}
@@ -8,6 +8,7 @@ package wrapper
 
 import (
 	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/spi/crypto"
 	"github.com/trustbloc/kms-go/spi/kms"
 	wrapperapi "github.com/trustbloc/kms-go/wrapper/api"
 )
@@ -28,6 +29,9 @@ type MockKMSCrypto struct {
 	EncryptErr        error
 	DecryptVal        []byte
 	DecryptErr        error
+	DeriveKeyID       string
+	DeriveKeyVal      []byte
+	DeriveKeyErr      error
 }
 
 // Create mock.
@@ -45,6 +49,21 @@ func (m *MockKMSCrypto) Sign(msg []byte, pub *jwk.JWK) ([]byte, error) {
 	return m.SignVal, m.SignErr
 }
 
+// SignBatch mock.
+func (m *MockKMSCrypto) SignBatch(msgs [][]byte, kid string, opts ...crypto.SignBatchOpts) ([][]byte, error) {
+	if m.SignErr != nil {
+		return nil, m.SignErr
+	}
+
+	sigs := make([][]byte, len(msgs))
+
+	for i := range msgs {
+		sigs[i] = m.SignVal
+	}
+
+	return sigs, nil
+}
+
 // SignMulti mock.
 func (m *MockKMSCrypto) SignMulti(msgs [][]byte, pub *jwk.JWK) ([]byte, error) {
 	return m.SignVal, m.SignErr
@@ -60,6 +79,12 @@ func (m *MockKMSCrypto) Encrypt(msg, aad []byte, kid string) (cipher, nonce []by
 	return m.EncryptVal, m.EncryptNonce, m.EncryptErr
 }
 
+// DeriveKey mock.
+func (m *MockKMSCrypto) DeriveKey(kid string, salt, info []byte, length int,
+	targetKeyType kms.KeyType) (string, []byte, error) {
+	return m.DeriveKeyID, m.DeriveKeyVal, m.DeriveKeyErr
+}
+
 // Decrypt mock.
 func (m *MockKMSCrypto) Decrypt(cipher, aad, nonce []byte, kid string) (msg []byte, err error) {
 	return m.DecryptVal, m.DecryptErr
@@ -120,6 +145,88 @@ func (m *MockFixedKeyCrypto) Verify(sig, msg []byte) error {
 	return m.VerifyErr
 }
 
+// MockKeyRef mocks wrapperapi.KeyRef.
+type MockKeyRef struct {
+	MockFixedKeyCrypto
+	KIDVal         string
+	PublicKeyVal   *jwk.JWK
+	PublicKeyErr   error
+	ProofVal       []byte
+	ProofErr       error
+	VerifyProofErr error
+	EncryptVal     []byte
+	EncryptNonce   []byte
+	EncryptErr     error
+	DecryptVal     []byte
+	DecryptErr     error
+	MACVal         []byte
+	MACErr         error
+	VerifyMACErr   error
+	WrapVal        *crypto.RecipientWrappedKey
+	WrapErr        error
+	UnwrapVal      []byte
+	UnwrapErr      error
+}
+
+// KID mock.
+func (m *MockKeyRef) KID() string {
+	return m.KIDVal
+}
+
+// PublicKey mock.
+func (m *MockKeyRef) PublicKey() (*jwk.JWK, error) {
+	return m.PublicKeyVal, m.PublicKeyErr
+}
+
+// VerifyMulti mock.
+func (m *MockKeyRef) VerifyMulti(msgs [][]byte, sig []byte) error {
+	return m.VerifyErr
+}
+
+// DeriveProof mock.
+func (m *MockKeyRef) DeriveProof(msgs [][]byte, bbsSig, nonce []byte, revealedIndexes []int) ([]byte, error) {
+	return m.ProofVal, m.ProofErr
+}
+
+// VerifyProof mock.
+func (m *MockKeyRef) VerifyProof(revealedMsgs [][]byte, proof, nonce []byte) error {
+	return m.VerifyProofErr
+}
+
+// Encrypt mock.
+func (m *MockKeyRef) Encrypt(msg, aad []byte) (cipher, nonce []byte, err error) {
+	return m.EncryptVal, m.EncryptNonce, m.EncryptErr
+}
+
+// Decrypt mock.
+func (m *MockKeyRef) Decrypt(cipher, aad, nonce []byte) (msg []byte, err error) {
+	return m.DecryptVal, m.DecryptErr
+}
+
+// ComputeMAC mock.
+func (m *MockKeyRef) ComputeMAC(data []byte) ([]byte, error) {
+	return m.MACVal, m.MACErr
+}
+
+// VerifyMAC mock.
+func (m *MockKeyRef) VerifyMAC(mac, data []byte) error {
+	return m.VerifyMACErr
+}
+
+// WrapKeyWithKEK mock.
+func (m *MockKeyRef) WrapKeyWithKEK(key []byte) (*crypto.RecipientWrappedKey, error) {
+	return m.WrapVal, m.WrapErr
+}
+
+// UnwrapKey mock.
+func (m *MockKeyRef) UnwrapKey(recWK *crypto.RecipientWrappedKey, opts ...crypto.WrapKeyOpts) ([]byte, error) {
+	return m.UnwrapVal, m.UnwrapErr
+}
+
+var _ wrapperapi.KeyRef = &MockKeyRef{}
+
 var _ wrapperapi.KMSCryptoMultiSigner = &MockKMSCrypto{}
 
 var _ wrapperapi.KMSCrypto = &MockKMSCrypto{}
+
+var _ wrapperapi.KMSCryptoBatchSigner = &MockKMSCrypto{}
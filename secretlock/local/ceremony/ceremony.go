@@ -0,0 +1,381 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package ceremony provides the key ceremony generating the master key of a local secret lock (see
+// secretlock/local) for production deployments, where no single operator may know or hold the master key:
+//
+//	c, err := ceremony.New(3)
+//	fingerprint, err := c.Contribute("alice", aliceEntropy) // for each operator, read back to the operator
+//	mk, err := c.Generate()                                 // mk.Fingerprint() is recorded in the ceremony minutes
+//	sealed, err := mk.SealShare("alice", alicePassphrase)  // for each operator, given to the operator
+//
+// The master key is derived with HKDF-SHA256 from the system randomness and the entropy contributed by each
+// operator, so it is unpredictable as long as one of them is. It is then split with Shamir's secret sharing in one
+// share per operator, threshold of them being required to recover it, and each share is sealed with a key derived
+// from its operator's passphrase with PBKDF2-SHA256.
+//
+// The master key is recovered from the unsealed shares of threshold operators:
+//
+//	share, err := sealed.Unseal(alicePassphrase)           // for each operator present
+//	masterKeyReader, err := ceremony.Recover(shares...)
+//	lock, err := local.NewService(masterKeyReader, nil)
+//
+// The kmscli command drives the ceremony and the recovery interactively, see its ceremony and recover commands.
+package ceremony
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/trustbloc/kms-go/secretlock/local/masterlock/pbkdf2"
+	"github.com/trustbloc/kms-go/spi/secretlock"
+)
+
+const (
+	// MasterKeySize is the size of the generated master keys, an AES-256 key.
+	MasterKeySize = 32
+	// MinEntropySize is the minimum size of the entropy contributed by an operator.
+	MinEntropySize = 16
+	// DefaultKDFIterations is the default PBKDF2 iterations count of the share sealing keys.
+	DefaultKDFIterations = 600000
+
+	maxShares      = 255
+	minThreshold   = 2
+	idSize         = 16
+	saltSize       = 16
+	systemEntropy  = 32
+	shareVersion   = 1
+	masterKeyInfo  = "kms-go key ceremony master key"
+	fingerprintLen = 16
+)
+
+// Ceremony is a key ceremony generating a master key from the entropy contributed by its operators.
+type Ceremony struct {
+	id            []byte
+	threshold     int
+	iterations    int
+	random        io.Reader
+	contributions []contribution
+}
+
+type contribution struct {
+	operator string
+	entropy  []byte
+}
+
+// Opt is a Ceremony option.
+type Opt func(c *Ceremony)
+
+// WithKDFIterations sets the PBKDF2 iterations count of the share sealing keys, DefaultKDFIterations by default.
+func WithKDFIterations(iterations int) Opt {
+	return func(c *Ceremony) {
+		c.iterations = iterations
+	}
+}
+
+// WithRandom sets the system randomness source, crypto/rand.Reader by default.
+func WithRandom(random io.Reader) Opt {
+	return func(c *Ceremony) {
+		c.random = random
+	}
+}
+
+// New returns a key ceremony generating a master key recoverable from the shares of threshold operators.
+func New(threshold int, opts ...Opt) (*Ceremony, error) {
+	c := &Ceremony{threshold: threshold, iterations: DefaultKDFIterations, random: rand.Reader}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if threshold < minThreshold || threshold > maxShares {
+		return nil, fmt.Errorf("new ceremony: threshold must be between %d and %d", minThreshold, maxShares)
+	}
+
+	if c.iterations < 1 {
+		return nil, errors.New("new ceremony: invalid KDF iterations count")
+	}
+
+	c.id = make([]byte, idSize)
+
+	if _, err := io.ReadFull(c.random, c.id); err != nil {
+		return nil, fmt.Errorf("new ceremony: %w", err)
+	}
+
+	return c, nil
+}
+
+// ID returns the ID of the ceremony, set in its shares.
+func (c *Ceremony) ID() string {
+	return hex.EncodeToString(c.id)
+}
+
+// Contribute adds the entropy contributed by operator, eg: typed random characters or dice rolls, and returns its
+// fingerprint for the operator to check it was recorded as entered. Each operator gets one share of the master key.
+func (c *Ceremony) Contribute(operator string, entropy []byte) (string, error) {
+	if strings.TrimSpace(operator) == "" {
+		return "", errors.New("contribute: operator is empty")
+	}
+
+	if len(entropy) < MinEntropySize {
+		return "", fmt.Errorf("contribute: entropy of operator '%s' must be at least %d bytes", operator,
+			MinEntropySize)
+	}
+
+	if len(c.contributions) == maxShares {
+		return "", fmt.Errorf("contribute: at most %d operators are supported", maxShares)
+	}
+
+	for _, contrib := range c.contributions {
+		if contrib.operator == operator {
+			return "", fmt.Errorf("contribute: operator '%s' already contributed", operator)
+		}
+	}
+
+	c.contributions = append(c.contributions, contribution{operator: operator, entropy: bytes.Clone(entropy)})
+
+	return Fingerprint(entropy), nil
+}
+
+// Generate generates the master key, once the operators contributed their entropy.
+func (c *Ceremony) Generate() (*MasterKey, error) {
+	if len(c.contributions) < c.threshold {
+		return nil, fmt.Errorf("generate master key: %d contributions for a threshold of %d", len(c.contributions),
+			c.threshold)
+	}
+
+	key, err := c.deriveMasterKey()
+	if err != nil {
+		return nil, fmt.Errorf("generate master key: %w", err)
+	}
+
+	values, err := split(key, len(c.contributions), c.threshold, c.random)
+	if err != nil {
+		return nil, fmt.Errorf("generate master key: %w", err)
+	}
+
+	mk := &MasterKey{key: key, ceremony: c, shares: make(map[string]*Share, len(values))}
+
+	for i, contrib := range c.contributions {
+		mk.shares[contrib.operator] = &Share{
+			Header: Header{
+				Version:              shareVersion,
+				CeremonyID:           c.ID(),
+				Operator:             contrib.operator,
+				Index:                i + 1,
+				Threshold:            c.threshold,
+				Shares:               len(values),
+				MasterKeyFingerprint: mk.Fingerprint(),
+			},
+			value: values[i],
+		}
+	}
+
+	return mk, nil
+}
+
+// deriveMasterKey derives the master key from the system randomness and the digests of the contributions.
+func (c *Ceremony) deriveMasterKey() ([]byte, error) {
+	ikm := make([]byte, systemEntropy, systemEntropy+len(c.contributions)*sha256.Size)
+
+	if _, err := io.ReadFull(c.random, ikm); err != nil {
+		return nil, err
+	}
+
+	for _, contrib := range c.contributions {
+		h := sha256.New()
+		h.Write([]byte(contrib.operator))
+		h.Write([]byte{0})
+		h.Write(contrib.entropy)
+
+		ikm = h.Sum(ikm)
+	}
+
+	key := make([]byte, MasterKeySize)
+
+	if _, err := io.ReadFull(hkdf.New(sha256.New, ikm, c.id, []byte(masterKeyInfo)), key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// MasterKey is the master key generated by a ceremony, and its shares.
+type MasterKey struct {
+	key      []byte
+	ceremony *Ceremony
+	shares   map[string]*Share
+}
+
+// Fingerprint returns the fingerprint of the master key, checked on recovery.
+func (m *MasterKey) Fingerprint() string {
+	return Fingerprint(m.key)
+}
+
+// Reader returns a reader of the base64URL encoded master key, for local.NewService.
+func (m *MasterKey) Reader() io.Reader {
+	return strings.NewReader(base64.URLEncoding.EncodeToString(m.key))
+}
+
+// SealShare returns the share of operator sealed with passphrase.
+func (m *MasterKey) SealShare(operator, passphrase string) (*SealedShare, error) {
+	share, ok := m.shares[operator]
+	if !ok {
+		return nil, fmt.Errorf("seal share: unknown operator '%s'", operator)
+	}
+
+	s := &SealedShare{Header: share.Header, Salt: make([]byte, saltSize), Iterations: m.ceremony.iterations}
+
+	if _, err := io.ReadFull(m.ceremony.random, s.Salt); err != nil {
+		return nil, fmt.Errorf("seal share: %w", err)
+	}
+
+	lock, err := s.lock(passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("seal share: %w", err)
+	}
+
+	resp, err := lock.Encrypt("", &secretlock.EncryptRequest{
+		Plaintext:                   string(share.value),
+		AdditionalAuthenticatedData: s.Header.aad(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("seal share: %w", err)
+	}
+
+	s.Ciphertext = resp.Ciphertext
+
+	return s, nil
+}
+
+// Header is the public part of a master key share.
+type Header struct {
+	Version              int    `json:"version"`
+	CeremonyID           string `json:"ceremonyID"`
+	Operator             string `json:"operator"`
+	Index                int    `json:"index"`
+	Threshold            int    `json:"threshold"`
+	Shares               int    `json:"shares"`
+	MasterKeyFingerprint string `json:"masterKeyFingerprint"`
+}
+
+// aad binds the header to the sealed share.
+func (h *Header) aad() string {
+	return strings.Join([]string{
+		strconv.Itoa(h.Version), h.CeremonyID, h.Operator, strconv.Itoa(h.Index), strconv.Itoa(h.Threshold),
+		strconv.Itoa(h.Shares), h.MasterKeyFingerprint,
+	}, "\n")
+}
+
+// Share is an unsealed master key share.
+type Share struct {
+	Header
+	value []byte
+}
+
+// SealedShare is a master key share sealed with the passphrase of its operator, stored as JSON by the operator.
+type SealedShare struct {
+	Header
+	Salt       []byte `json:"salt"`
+	Iterations int    `json:"iterations"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// Unseal returns the share sealed with passphrase.
+func (s *SealedShare) Unseal(passphrase string) (*Share, error) {
+	if s.Version != shareVersion {
+		return nil, fmt.Errorf("unseal share: unsupported version %d", s.Version)
+	}
+
+	lock, err := s.lock(passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("unseal share: %w", err)
+	}
+
+	resp, err := lock.Decrypt("", &secretlock.DecryptRequest{
+		Ciphertext:                  s.Ciphertext,
+		AdditionalAuthenticatedData: s.Header.aad(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unseal share of operator '%s': wrong passphrase or corrupted share", s.Operator)
+	}
+
+	return &Share{Header: s.Header, value: []byte(resp.Plaintext)}, nil
+}
+
+func (s *SealedShare) lock(passphrase string) (secretlock.Service, error) {
+	if s.Iterations < 1 {
+		return nil, errors.New("invalid KDF iterations count")
+	}
+
+	return pbkdf2.NewMasterLock(passphrase, sha256.New, s.Iterations, s.Salt)
+}
+
+// Recover recovers the master key from the shares of at least threshold operators, and returns a reader of the
+// base64URL encoded master key for local.NewService.
+func Recover(shares ...*Share) (io.Reader, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("recover master key: no shares")
+	}
+
+	first := shares[0].Header
+
+	if len(shares) < first.Threshold {
+		return nil, fmt.Errorf("recover master key: %d shares for a threshold of %d", len(shares), first.Threshold)
+	}
+
+	xs := make([]byte, len(shares))
+	ys := make([][]byte, len(shares))
+
+	for i, share := range shares {
+		if share.CeremonyID != first.CeremonyID || share.MasterKeyFingerprint != first.MasterKeyFingerprint ||
+			share.Threshold != first.Threshold {
+			return nil, errors.New("recover master key: shares of different ceremonies")
+		}
+
+		if share.Index < 1 || share.Index > maxShares {
+			return nil, fmt.Errorf("recover master key: invalid share index %d", share.Index)
+		}
+
+		xs[i], ys[i] = byte(share.Index), share.value
+	}
+
+	key, err := combine(xs, ys)
+	if err != nil {
+		return nil, fmt.Errorf("recover master key: %w", err)
+	}
+
+	if Fingerprint(key) != first.MasterKeyFingerprint {
+		return nil, errors.New("recover master key: the recovered master key doesn't match its fingerprint")
+	}
+
+	return strings.NewReader(base64.URLEncoding.EncodeToString(key)), nil
+}
+
+// Fingerprint returns the fingerprint of b read back to the operators: the first 16 bytes of its SHA-256 digest, as
+// groups of 4 hex digits.
+func Fingerprint(b []byte) string {
+	digest := sha256.Sum256(b)
+	h := strings.ToUpper(hex.EncodeToString(digest[:fingerprintLen]))
+
+	groups := make([]string, 0, len(h)/4) //nolint:gomnd // 4 hex digits groups
+
+	for i := 0; i < len(h); i += 4 {
+		groups = append(groups, h[i:i+4])
+	}
+
+	return strings.Join(groups, " ")
+}
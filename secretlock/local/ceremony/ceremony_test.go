@@ -0,0 +1,274 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ceremony_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/secretlock/local"
+	"github.com/trustbloc/kms-go/secretlock/local/ceremony"
+	"github.com/trustbloc/kms-go/spi/secretlock"
+)
+
+var operators = []string{"alice", "bob", "carol", "dave", "erin"}
+
+func TestCeremony(t *testing.T) {
+	c, err := ceremony.New(3, ceremony.WithKDFIterations(1000))
+	require.NoError(t, err)
+	require.Len(t, c.ID(), 32)
+
+	for _, operator := range operators {
+		entropy := []byte(strings.Repeat(operator, 8))
+
+		fingerprint, e := c.Contribute(operator, entropy)
+		require.NoError(t, e)
+		require.Equal(t, ceremony.Fingerprint(entropy), fingerprint)
+		require.Len(t, fingerprint, 39)
+	}
+
+	mk, err := c.Generate()
+	require.NoError(t, err)
+
+	lock, err := local.NewService(mk.Reader(), nil)
+	require.NoError(t, err)
+
+	enc, err := lock.Encrypt("", &secretlock.EncryptRequest{Plaintext: "keyset"})
+	require.NoError(t, err)
+
+	shares := make([]*ceremony.Share, len(operators))
+
+	for i, operator := range operators {
+		sealed, e := mk.SealShare(operator, operator+" passphrase")
+		require.NoError(t, e)
+		require.Equal(t, i+1, sealed.Index)
+		require.Equal(t, 3, sealed.Threshold)
+		require.Equal(t, 5, sealed.Shares)
+		require.Equal(t, c.ID(), sealed.CeremonyID)
+		require.Equal(t, mk.Fingerprint(), sealed.MasterKeyFingerprint)
+
+		// the sealed shares are stored as JSON files by the operators.
+		v, e := json.Marshal(sealed)
+		require.NoError(t, e)
+
+		stored := &ceremony.SealedShare{}
+		require.NoError(t, json.Unmarshal(v, stored))
+
+		_, e = stored.Unseal("wrong passphrase")
+		require.EqualError(t, e, "unseal share of operator '"+operator+"': wrong passphrase or corrupted share")
+
+		shares[i], e = stored.Unseal(operator + " passphrase")
+		require.NoError(t, e)
+		require.Equal(t, sealed.Header, shares[i].Header)
+	}
+
+	t.Run("recover from any threshold shares", func(t *testing.T) {
+		for i := 0; i < len(shares); i++ {
+			for j := i + 1; j < len(shares); j++ {
+				for k := j + 1; k < len(shares); k++ {
+					requireRecovered(t, enc, shares[k], shares[i], shares[j])
+				}
+			}
+		}
+
+		requireRecovered(t, enc, shares[4], shares[0], shares[2], shares[1])
+		requireRecovered(t, enc, shares...)
+	})
+
+	t.Run("recover errors", func(t *testing.T) {
+		_, err = ceremony.Recover()
+		require.EqualError(t, err, "recover master key: no shares")
+
+		_, err = ceremony.Recover(shares[0], shares[1])
+		require.EqualError(t, err, "recover master key: 2 shares for a threshold of 3")
+
+		_, err = ceremony.Recover(shares[0], shares[1], shares[1])
+		require.EqualError(t, err, "recover master key: duplicate shares")
+
+		other := newShares(t)
+
+		_, err = ceremony.Recover(shares[0], shares[1], other[2])
+		require.EqualError(t, err, "recover master key: shares of different ceremonies")
+
+		forged := *shares[2]
+		forged.Index = 256
+
+		_, err = ceremony.Recover(shares[0], shares[1], &forged)
+		require.EqualError(t, err, "recover master key: invalid share index 256")
+
+		forged.Index = 4
+
+		_, err = ceremony.Recover(shares[0], shares[1], &forged)
+		require.EqualError(t, err, "recover master key: the recovered master key doesn't match its fingerprint")
+	})
+}
+
+func TestSealedShareTampering(t *testing.T) {
+	c, err := ceremony.New(2, ceremony.WithKDFIterations(1000))
+	require.NoError(t, err)
+
+	for _, operator := range operators[:2] {
+		_, err = c.Contribute(operator, bytes.Repeat([]byte(operator), 8))
+		require.NoError(t, err)
+	}
+
+	mk, err := c.Generate()
+	require.NoError(t, err)
+
+	_, err = mk.SealShare("mallory", "passphrase")
+	require.EqualError(t, err, "seal share: unknown operator 'mallory'")
+
+	sealed, err := mk.SealShare("alice", "passphrase")
+	require.NoError(t, err)
+
+	// the header is authenticated.
+	tampered := *sealed
+	tampered.Threshold = 1
+
+	_, err = tampered.Unseal("passphrase")
+	require.ErrorContains(t, err, "wrong passphrase or corrupted share")
+
+	tampered = *sealed
+	tampered.Version = 2
+
+	_, err = tampered.Unseal("passphrase")
+	require.EqualError(t, err, "unseal share: unsupported version 2")
+
+	tampered = *sealed
+	tampered.Iterations = 0
+
+	_, err = tampered.Unseal("passphrase")
+	require.EqualError(t, err, "unseal share: invalid KDF iterations count")
+
+	_, err = sealed.Unseal("")
+	require.EqualError(t, err, "unseal share: passphrase is empty")
+}
+
+func TestErrors(t *testing.T) {
+	_, err := ceremony.New(1)
+	require.EqualError(t, err, "new ceremony: threshold must be between 2 and 255")
+
+	_, err = ceremony.New(2, ceremony.WithKDFIterations(0))
+	require.EqualError(t, err, "new ceremony: invalid KDF iterations count")
+
+	_, err = ceremony.New(2, ceremony.WithRandom(failingReader{}))
+	require.EqualError(t, err, "new ceremony: random failure")
+
+	c, err := ceremony.New(2)
+	require.NoError(t, err)
+
+	_, err = c.Contribute(" ", bytes.Repeat([]byte{1}, 16))
+	require.EqualError(t, err, "contribute: operator is empty")
+
+	_, err = c.Contribute("alice", []byte("short"))
+	require.EqualError(t, err, "contribute: entropy of operator 'alice' must be at least 16 bytes")
+
+	_, err = c.Contribute("alice", bytes.Repeat([]byte{1}, 16))
+	require.NoError(t, err)
+
+	_, err = c.Contribute("alice", bytes.Repeat([]byte{2}, 16))
+	require.EqualError(t, err, "contribute: operator 'alice' already contributed")
+
+	_, err = c.Generate()
+	require.EqualError(t, err, "generate master key: 1 contributions for a threshold of 2")
+
+	random := &limitedReader{n: 16}
+
+	c, err = ceremony.New(2, ceremony.WithRandom(random))
+	require.NoError(t, err)
+
+	for _, operator := range operators[:2] {
+		_, err = c.Contribute(operator, bytes.Repeat([]byte(operator), 8))
+		require.NoError(t, err)
+	}
+
+	_, err = c.Generate()
+	require.EqualError(t, err, "generate master key: random failure")
+
+	random.n = 32
+
+	_, err = c.Generate()
+	require.EqualError(t, err, "generate master key: read polynomial coefficients: random failure")
+
+	random.n = 64
+
+	mk, err := c.Generate()
+	require.NoError(t, err)
+
+	_, err = mk.SealShare("alice", "passphrase")
+	require.EqualError(t, err, "seal share: random failure")
+}
+
+func requireRecovered(t *testing.T, enc *secretlock.EncryptResponse, shares ...*ceremony.Share) {
+	t.Helper()
+
+	r, err := ceremony.Recover(shares...)
+	require.NoError(t, err)
+
+	lock, err := local.NewService(r, nil)
+	require.NoError(t, err)
+
+	dec, err := lock.Decrypt("", &secretlock.DecryptRequest{Ciphertext: enc.Ciphertext})
+	require.NoError(t, err)
+	require.Equal(t, "keyset", dec.Plaintext)
+}
+
+func newShares(t *testing.T) []*ceremony.Share {
+	t.Helper()
+
+	c, err := ceremony.New(3, ceremony.WithKDFIterations(1000))
+	require.NoError(t, err)
+
+	for _, operator := range operators {
+		_, err = c.Contribute(operator, bytes.Repeat([]byte(operator), 8))
+		require.NoError(t, err)
+	}
+
+	mk, err := c.Generate()
+	require.NoError(t, err)
+
+	shares := make([]*ceremony.Share, len(operators))
+
+	for i, operator := range operators {
+		sealed, e := mk.SealShare(operator, "passphrase")
+		require.NoError(t, e)
+
+		shares[i], e = sealed.Unseal("passphrase")
+		require.NoError(t, e)
+	}
+
+	return shares
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("random failure")
+}
+
+// limitedReader reads n random bytes, then fails.
+type limitedReader struct {
+	n int
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.n < len(p) {
+		r.n = 0
+
+		return 0, errors.New("random failure")
+	}
+
+	r.n -= len(p)
+
+	return io.ReadFull(strings.NewReader(strings.Repeat("r", len(p))), p)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ceremony
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// split splits secret in n shares, threshold of them being required to recover secret with combine, using Shamir's
+// secret sharing over GF(2^8): each byte of secret is the constant term of a random polynomial of degree threshold-1,
+// and the share i holds the values at x = i+1 of the polynomials.
+func split(secret []byte, n, threshold int, random io.Reader) ([][]byte, error) {
+	coefficients := make([]byte, len(secret)*(threshold-1))
+
+	if _, err := io.ReadFull(random, coefficients); err != nil {
+		return nil, fmt.Errorf("read polynomial coefficients: %w", err)
+	}
+
+	shares := make([][]byte, n)
+
+	for i := range shares {
+		x := byte(i + 1)
+		shares[i] = make([]byte, len(secret))
+
+		for j, s := range secret {
+			// Horner's method, from the highest degree coefficient.
+			var y byte
+
+			for _, c := range reversed(coefficients[j*(threshold-1) : (j+1)*(threshold-1)]) {
+				y = gfMul(y, x) ^ c
+			}
+
+			shares[i][j] = gfMul(y, x) ^ s
+		}
+	}
+
+	return shares, nil
+}
+
+// combine recovers the secret of the shares ys at the points xs, with the Lagrange interpolation at x = 0.
+func combine(xs []byte, ys [][]byte) ([]byte, error) {
+	if len(xs) == 0 || len(xs) != len(ys) {
+		return nil, errors.New("invalid shares")
+	}
+
+	secret := make([]byte, len(ys[0]))
+
+	for i, xi := range xs {
+		if xi == 0 || len(ys[i]) != len(secret) {
+			return nil, errors.New("invalid shares")
+		}
+
+		// basis is the Lagrange basis polynomial of xi at 0: the product of xj / (xj - xi) for j != i.
+		basis := byte(1)
+
+		for j, xj := range xs {
+			if j == i {
+				continue
+			}
+
+			if xj == xi {
+				return nil, errors.New("duplicate shares")
+			}
+
+			basis = gfMul(basis, gfMul(xj, gfInv(xj^xi)))
+		}
+
+		for k, y := range ys[i] {
+			secret[k] ^= gfMul(y, basis)
+		}
+	}
+
+	return secret, nil
+}
+
+func reversed(b []byte) []byte {
+	r := make([]byte, len(b))
+
+	for i, v := range b {
+		r[len(b)-1-i] = v
+	}
+
+	return r
+}
+
+// gfMul multiplies a and b in GF(2^8) with the AES polynomial x^8 + x^4 + x^3 + x + 1, without branches on the
+// values.
+func gfMul(a, b byte) byte {
+	var p byte
+
+	for i := 0; i < 8; i++ {
+		p ^= a & -(b & 1)
+		a = a<<1 ^ 0x1b&-(a>>7) //nolint:gomnd // reduction by the AES polynomial
+		b >>= 1
+	}
+
+	return p
+}
+
+// gfInv returns the multiplicative inverse of a != 0 in GF(2^8), a^254.
+func gfInv(a byte) byte {
+	r := a
+
+	for i := 0; i < 6; i++ {
+		r = gfMul(gfMul(r, r), a)
+	}
+
+	return gfMul(r, r)
+}
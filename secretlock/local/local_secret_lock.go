@@ -32,8 +32,10 @@ import (
 //
 // The user can then call either:
 //		MasterKeyFromPath(path) or
-//		MasterKeyFromEnv(envPrefix, keyURI)
-// to get an io.Reader instance needed to read the master key and create a keys Lock service.
+//		MasterKeyFromEnv(envPrefix, keyURI) or
+//		MasterKeyFromStore(store, keyURI)
+// to get an io.Reader instance needed to read the master key and create a keys Lock service. MasterKeyFromStore reads
+// the master key from a MasterKeyStore, eg: webstorage.Store for browsers in js/wasm builds.
 //
 // The content of the master key reader may be either raw bytes or base64URL encoded (by masterlock if protected or
 // manually if not). Base64URL encoding is useful when setting a master key in an environment variable as some OSs may
@@ -143,7 +145,7 @@ func (s *Lock) Decrypt(keyURI string, req *secretlock.DecryptRequest) (*secretlo
 
 	pt, err := s.aead.Open(nil, nonce, ct, []byte(req.AdditionalAuthenticatedData))
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", secretlock.ErrLocked, err)
 	}
 
 	return &secretlock.DecryptResponse{Plaintext: string(pt)}, nil
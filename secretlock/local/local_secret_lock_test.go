@@ -293,7 +293,7 @@ func checkCreateServiceUsingMasterLock(t *testing.T, masterLocker secretlock.Ser
 	badCipher := base64.URLEncoding.EncodeToString([]byte("BadCipherTextInAction"))
 
 	someKeyDec, err = s.Decrypt("", &secretlock.DecryptRequest{Ciphertext: badCipher})
-	require.Error(t, err)
+	require.ErrorIs(t, err, secretlock.ErrLocked)
 	require.Empty(t, someKeyDec)
 
 	// try with a short cipher (shorter than nonce+ciphertext)
@@ -419,3 +419,46 @@ func TestCreateServiceFromEnvWithMasterLock(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, someKey, []byte(someKeyDec.Plaintext))
 }
+
+type mapMasterKeyStore map[string][]byte
+
+func (m mapMasterKeyStore) Get(keyURI string) ([]byte, error) {
+	mk, ok := m[keyURI]
+	if !ok {
+		return nil, ErrMasterKeyNotFound
+	}
+
+	return mk, nil
+}
+
+func TestCreateServiceFromStore(t *testing.T) {
+	store := mapMasterKeyStore{
+		testKeyURI: []byte(base64.URLEncoding.EncodeToString(random.GetRandomBytes(uint32(32)))),
+		"empty":    {},
+	}
+
+	r, err := MasterKeyFromStore(store, testKeyURI)
+	require.NoError(t, err)
+
+	s, err := NewService(r, nil)
+	require.NoError(t, err)
+
+	someKeyEnc, err := s.Encrypt("", &secretlock.EncryptRequest{Plaintext: "secret"})
+	require.NoError(t, err)
+
+	someKeyDec, err := s.Decrypt("", &secretlock.DecryptRequest{Ciphertext: someKeyEnc.Ciphertext})
+	require.NoError(t, err)
+	require.Equal(t, "secret", someKeyDec.Plaintext)
+
+	_, err = MasterKeyFromStore(store, "bad/mk/test/key")
+	require.ErrorIs(t, err, ErrMasterKeyNotFound)
+
+	_, err = MasterKeyFromStore(store, "empty")
+	require.ErrorIs(t, err, ErrMasterKeyNotFound)
+
+	_, err = MasterKeyFromStore(nil, testKeyURI)
+	require.EqualError(t, err, "master key store is nil")
+
+	_, err = MasterKeyFromStore(EnvMasterKeyStore(envPrefix), testKeyURI)
+	require.ErrorIs(t, err, ErrMasterKeyNotFound)
+}
@@ -8,7 +8,6 @@ package local
 import (
 	"bytes"
 	"errors"
-	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -46,10 +45,48 @@ func MasterKeyFromPath(path string) (io.Reader, error) {
 // MasterKeyFromEnv creates a new instance of a local secret lock Reader
 // to read a master key found in a env variable with key: `envPrefix` + `keyURI`.
 func MasterKeyFromEnv(envPrefix, keyURI string) (io.Reader, error) {
-	mk := os.Getenv(envPrefix + strings.ReplaceAll(keyURI, "/", "_"))
+	return MasterKeyFromStore(EnvMasterKeyStore(envPrefix), keyURI)
+}
+
+// ErrMasterKeyNotFound is returned by a MasterKeyStore that doesn't hold the master key of a key URI.
+var ErrMasterKeyNotFound = errors.New("masterKey not set")
+
+// MasterKeyStore holds the master keys of local secret locks by key URI, for platforms where the master key can't be
+// read from a file or an environment variable (eg: the browser storage of js/wasm builds, see the webstorage package).
+type MasterKeyStore interface {
+	// Get returns the master key content of keyURI, raw or base64URL encoded, or an error wrapping
+	// ErrMasterKeyNotFound if it's not in the store.
+	Get(keyURI string) ([]byte, error)
+}
+
+// MasterKeyFromStore creates a new instance of a local secret lock Reader to read the master key of keyURI in store.
+func MasterKeyFromStore(store MasterKeyStore, keyURI string) (io.Reader, error) {
+	if store == nil {
+		return nil, errors.New("master key store is nil")
+	}
+
+	mk, err := store.Get(keyURI)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(mk) == 0 {
+		return nil, ErrMasterKeyNotFound
+	}
+
+	return bytes.NewReader(mk), nil
+}
+
+// EnvMasterKeyStore is a MasterKeyStore reading the master keys in environment variables named with the prefix
+// followed by the key URI, '/' characters replaced by '_'.
+type EnvMasterKeyStore string
+
+// Get returns the master key content of keyURI.
+func (e EnvMasterKeyStore) Get(keyURI string) ([]byte, error) {
+	mk := os.Getenv(string(e) + strings.ReplaceAll(keyURI, "/", "_"))
 	if mk == "" {
-		return nil, fmt.Errorf("masterKey not set")
+		return nil, ErrMasterKeyNotFound
 	}
 
-	return bytes.NewReader([]byte(mk)), nil
+	return []byte(mk), nil
 }
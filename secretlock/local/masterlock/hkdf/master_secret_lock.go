@@ -116,7 +116,7 @@ func (m *masterLockHKDF) Decrypt(keyURI string, req *secretlock.DecryptRequest)
 
 	pt, err := m.aead.Open(nil, nonce, ct, []byte(req.AdditionalAuthenticatedData))
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", secretlock.ErrLocked, err)
 	}
 
 	return &secretlock.DecryptResponse{Plaintext: string(pt)}, nil
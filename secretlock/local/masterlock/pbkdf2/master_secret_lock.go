@@ -99,7 +99,7 @@ func (m *masterLockPBKDF2) Decrypt(keyURI string, req *secretlock.DecryptRequest
 
 	pt, err := m.aead.Open(nil, nonce, ct, []byte(req.AdditionalAuthenticatedData))
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", secretlock.ErrLocked, err)
 	}
 
 	return &secretlock.DecryptResponse{Plaintext: string(pt)}, nil
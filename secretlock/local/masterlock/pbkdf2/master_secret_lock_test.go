@@ -83,7 +83,7 @@ func TestMasterLock(t *testing.T) {
 	require.NoError(t, err)
 
 	decryptedMk2, err = mkLock2.Decrypt("", &secretlock.DecryptRequest{Ciphertext: encryptedMk.Ciphertext})
-	require.Error(t, err)
+	require.ErrorIs(t, err, secretlock.ErrLocked)
 	require.Empty(t, decryptedMk2)
 
 	// try creating a lock with a nil hash
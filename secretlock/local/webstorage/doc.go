@@ -0,0 +1,22 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package webstorage provides a local.MasterKeyStore backed by the Web Storage API (window.localStorage or
+// window.sessionStorage), so browser wallets built with GOOS=js GOARCH=wasm can keep the master key of the local
+// secret lock in the browser. The package is empty in the other builds.
+//
+//	store, err := webstorage.NewLocalStorage("kms-go/")
+//	// once, when the wallet is created, the base64URL encoded master key:
+//	err = store.Put(keyURI, base64.URLEncoding.EncodeToString(random.GetRandomBytes(32)))
+//	// or the master key encrypted by a master lock:
+//	err = store.Put(keyURI, encryptResponse.Ciphertext)
+//
+//	r, err := local.MasterKeyFromStore(store, keyURI)
+//	secretLock, err := local.NewService(r, masterLock)
+//
+// Web Storage is readable by any script of the page's origin, the master key should be protected with a master lock
+// (see the masterlock sub packages of secretlock/local) derived from a user passphrase.
+package webstorage
@@ -0,0 +1,98 @@
+//go:build js && wasm
+
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webstorage
+
+import (
+	"errors"
+	"fmt"
+	"syscall/js"
+
+	"github.com/trustbloc/kms-go/secretlock/local"
+)
+
+// Store is a local.MasterKeyStore saving master keys in a Web Storage object, with item names made of a prefix
+// followed by the key URI.
+type Store struct {
+	storage js.Value
+	prefix  string
+}
+
+var _ local.MasterKeyStore = &Store{}
+
+// New returns a Store saving items in storage, a Web Storage object (or any object with the getItem, setItem and
+// removeItem methods).
+func New(storage js.Value, prefix string) (*Store, error) {
+	if storage.Type() != js.TypeObject {
+		return nil, errors.New("webstorage: storage is not an object")
+	}
+
+	return &Store{storage: storage, prefix: prefix}, nil
+}
+
+// NewLocalStorage returns a Store saving items in window.localStorage.
+func NewLocalStorage(prefix string) (*Store, error) {
+	return New(js.Global().Get("localStorage"), prefix)
+}
+
+// NewSessionStorage returns a Store saving items in window.sessionStorage.
+func NewSessionStorage(prefix string) (*Store, error) {
+	return New(js.Global().Get("sessionStorage"), prefix)
+}
+
+// Get returns the master key content of keyURI.
+func (s *Store) Get(keyURI string) (mk []byte, err error) {
+	defer recoverJSError("get", &err)
+
+	v := s.storage.Call("getItem", s.prefix+keyURI)
+	if v.IsNull() || v.IsUndefined() {
+		return nil, fmt.Errorf("webstorage: key URI '%s': %w", keyURI, local.ErrMasterKeyNotFound)
+	}
+
+	return []byte(v.String()), nil
+}
+
+// Put saves the master key content of keyURI: the base64URL encoded master key, or the master key encrypted by a
+// master lock. Web Storage items are strings, raw master keys can't be saved.
+func (s *Store) Put(keyURI, masterKey string) (err error) {
+	defer recoverJSError("put", &err)
+
+	if masterKey == "" {
+		return errors.New("webstorage: master key is empty")
+	}
+
+	s.storage.Call("setItem", s.prefix+keyURI, masterKey)
+
+	return nil
+}
+
+// Delete removes the master key of keyURI.
+func (s *Store) Delete(keyURI string) (err error) {
+	defer recoverJSError("delete", &err)
+
+	s.storage.Call("removeItem", s.prefix+keyURI)
+
+	return nil
+}
+
+// recoverJSError converts the JavaScript exceptions (eg: QuotaExceededError, SecurityError), raised by syscall/js as
+// panics, into errors.
+func recoverJSError(op string, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	if jsErr, ok := r.(js.Error); ok {
+		*err = fmt.Errorf("webstorage: %s: %w", op, jsErr)
+
+		return
+	}
+
+	panic(r)
+}
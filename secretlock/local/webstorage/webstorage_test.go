@@ -0,0 +1,107 @@
+//go:build js && wasm
+
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webstorage
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"syscall/js"
+	"testing"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/secretlock/local"
+	"github.com/trustbloc/kms-go/secretlock/local/masterlock/hkdf"
+	"github.com/trustbloc/kms-go/spi/secretlock"
+)
+
+const testKeyURI = "local-lock://test/key"
+
+// newStorage returns an in-memory object with the Web Storage methods, Node.js has no localStorage. Setting the
+// "full" item throws an exception.
+func newStorage() js.Value {
+	return js.Global().Get("Function").New(`
+		const items = {};
+
+		return {
+			getItem: (k) => (k in items ? items[k] : null),
+			setItem: (k, v) => {
+				if (k === "full") {
+					throw new Error("QuotaExceededError");
+				}
+
+				items[k] = String(v);
+			},
+			removeItem: (k) => { delete items[k]; },
+		};`).Invoke()
+}
+
+func TestStore(t *testing.T) {
+	store, err := New(newStorage(), "kms-go/")
+	require.NoError(t, err)
+
+	_, err = store.Get(testKeyURI)
+	require.ErrorIs(t, err, local.ErrMasterKeyNotFound)
+
+	require.NoError(t, store.Put(testKeyURI, base64.URLEncoding.EncodeToString(random.GetRandomBytes(32))))
+
+	r, err := local.MasterKeyFromStore(store, testKeyURI)
+	require.NoError(t, err)
+
+	secretLock, err := local.NewService(r, nil)
+	require.NoError(t, err)
+
+	encResp, err := secretLock.Encrypt(testKeyURI, &secretlock.EncryptRequest{Plaintext: "secret"})
+	require.NoError(t, err)
+
+	decResp, err := secretLock.Decrypt(testKeyURI, &secretlock.DecryptRequest{Ciphertext: encResp.Ciphertext})
+	require.NoError(t, err)
+	require.Equal(t, "secret", decResp.Plaintext)
+
+	require.NoError(t, store.Delete(testKeyURI))
+
+	_, err = local.MasterKeyFromStore(store, testKeyURI)
+	require.ErrorIs(t, err, local.ErrMasterKeyNotFound)
+
+	t.Run("master key protected by a master lock", func(t *testing.T) {
+		masterLock, err := hkdf.NewMasterLock("passphrase", sha256.New, nil)
+		require.NoError(t, err)
+
+		protected, err := masterLock.Encrypt("", &secretlock.EncryptRequest{
+			Plaintext: string(random.GetRandomBytes(32)),
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, store.Put(testKeyURI, protected.Ciphertext))
+
+		r, err := local.MasterKeyFromStore(store, testKeyURI)
+		require.NoError(t, err)
+
+		_, err = local.NewService(r, masterLock)
+		require.NoError(t, err)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := New(js.Undefined(), "")
+		require.EqualError(t, err, "webstorage: storage is not an object")
+
+		require.EqualError(t, store.Put(testKeyURI, ""), "webstorage: master key is empty")
+
+		store.prefix = ""
+
+		err = store.Put("full", "key")
+
+		var jsErr js.Error
+
+		require.True(t, errors.As(err, &jsErr))
+		require.ErrorContains(t, err, "webstorage: put: ")
+	})
+}
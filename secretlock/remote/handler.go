@@ -0,0 +1,172 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remote
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/trustbloc/kms-go/spi/secretlock"
+)
+
+// DefaultMaxRequestSize is the maximum size of the request bodies, in bytes, when none is set with
+// WithMaxRequestSize.
+const DefaultMaxRequestSize = 1024 * 1024
+
+// ErrNoAuthorize is returned to the requests of a Handler created without an Authorize function.
+var ErrNoAuthorize = errors.New("no authorization check is set on the secret lock service")
+
+// Authorize checks a request to the remote secret lock service is allowed to use the master key in keyURI.
+type Authorize func(req *http.Request, keyURI string) error
+
+// HandlerOpt are the Handler options.
+type HandlerOpt func(h *Handler)
+
+// WithAuthorize sets the authorization check of the requests. Unauthorized requests get a 403 status code.
+func WithAuthorize(authorize Authorize) HandlerOpt {
+	return func(h *Handler) {
+		h.authorize = authorize
+	}
+}
+
+// WithMaxRequestSize sets the maximum size of the request bodies, in bytes. Larger requests are rejected with the
+// 413 status. Values lower than 1 are ignored.
+func WithMaxRequestSize(size int64) HandlerOpt {
+	return func(h *Handler) {
+		if size > 0 {
+			h.maxRequestSize = size
+		}
+	}
+}
+
+// Handler serves a secret lock service (eg: a local.Lock holding the master key) to remote Lock clients. It
+// implements http.Handler.
+type Handler struct {
+	lock           secretlock.Service
+	authorize      Authorize
+	maxRequestSize int64
+	mux            *http.ServeMux
+}
+
+// NewHandler creates a new Handler serving lock. The requests are denied unless an Authorize function is set with
+// WithAuthorize: the master keys of lock must not be usable by any client reaching the service.
+func NewHandler(lock secretlock.Service, opts ...HandlerOpt) *Handler {
+	h := &Handler{lock: lock, authorize: denyAll, maxRequestSize: DefaultMaxRequestSize, mux: http.NewServeMux()}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if h.authorize == nil {
+		h.authorize = denyAll
+	}
+
+	h.mux.HandleFunc(EncryptPath, h.encrypt)
+	h.mux.HandleFunc(DecryptPath, h.decrypt)
+
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) encrypt(w http.ResponseWriter, r *http.Request) {
+	req := &encryptReq{}
+
+	if !h.readRequest(w, r, req, &req.KeyURI) {
+		return
+	}
+
+	resp, err := h.lock.Encrypt(req.KeyURI, &secretlock.EncryptRequest{
+		Plaintext:                   req.Plaintext,
+		AdditionalAuthenticatedData: req.AAD,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	writeResponse(w, &encryptResp{Ciphertext: resp.Ciphertext})
+}
+
+func (h *Handler) decrypt(w http.ResponseWriter, r *http.Request) {
+	req := &decryptReq{}
+
+	if !h.readRequest(w, r, req, &req.KeyURI) {
+		return
+	}
+
+	resp, err := h.lock.Decrypt(req.KeyURI, &secretlock.DecryptRequest{
+		Ciphertext:                  req.Ciphertext,
+		AdditionalAuthenticatedData: req.AAD,
+	})
+	if errors.Is(err, secretlock.ErrLocked) {
+		writeError(w, http.StatusUnprocessableEntity, err)
+
+		return
+	}
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	writeResponse(w, &decryptResp{Plaintext: resp.Plaintext})
+}
+
+// readRequest decodes the request body into req and authorizes the use of keyURI (a field of req). Bodies exceeding
+// the maximum request size get a 413 status code.
+func (h *Handler) readRequest(w http.ResponseWriter, r *http.Request, req interface{}, keyURI *string) bool {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+
+		return false
+	}
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxRequestSize)).Decode(req); err != nil {
+		status := http.StatusBadRequest
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+
+		writeError(w, status, err)
+
+		return false
+	}
+
+	if err := h.authorize(r, *keyURI); err != nil {
+		writeError(w, http.StatusForbidden, err)
+
+		return false
+	}
+
+	return true
+}
+
+func denyAll(*http.Request, string) error {
+	return ErrNoAuthorize
+}
+
+func writeResponse(w http.ResponseWriter, resp interface{}) {
+	w.Header().Set("Content-Type", contentType)
+
+	_ = json.NewEncoder(w).Encode(resp) //nolint:errcheck
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(&errMessage{Error: err.Error()}) //nolint:errcheck
+}
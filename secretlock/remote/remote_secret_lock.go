@@ -0,0 +1,201 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package remote provides a secret lock service delegating the master key operations to a remote (hardened) secret
+// lock service, so the KMS host never holds the master secret. Lock is the client and Handler serves any
+// secretlock.Service over the protocol below.
+//
+// Endpoints (relative to the service base URL), JSON encoded:
+//
+//	POST /encrypt   {"keyURI", "plaintext", "aad"} -> {"ciphertext"}
+//	POST /decrypt   {"keyURI", "ciphertext", "aad"} -> {"plaintext"}
+//
+// Values are passed as is from and to the secretlock requests and responses (base64URL encoded by the KMS). Failed
+// requests return a non 200 status code with an {"errMessage"} body, returned by Lock as a *kms.RemoteError. The errors
+// of unreachable services wrap kms.ErrRemoteUnavailable. Decrypt requests that fail with secretlock.ErrLocked get a 422
+// status code, their errors wrap secretlock.ErrLocked. Handler denies the requests (403) unless it has an Authorize
+// function, and rejects bodies over its maximum request size (413).
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/spi/secretlock"
+)
+
+const (
+	// EncryptPath is the path of the encrypt endpoint.
+	EncryptPath = "/encrypt"
+	// DecryptPath is the path of the decrypt endpoint.
+	DecryptPath = "/decrypt"
+
+	contentType = "application/json"
+)
+
+type encryptReq struct {
+	KeyURI    string `json:"keyURI"`
+	Plaintext string `json:"plaintext"`
+	AAD       string `json:"aad,omitempty"`
+}
+
+type encryptResp struct {
+	Ciphertext string `json:"ciphertext"`
+}
+
+type decryptReq struct {
+	KeyURI     string `json:"keyURI"`
+	Ciphertext string `json:"ciphertext"`
+	AAD        string `json:"aad,omitempty"`
+}
+
+type decryptResp struct {
+	Plaintext string `json:"plaintext"`
+}
+
+type errMessage struct {
+	Error string `json:"errMessage"`
+}
+
+// HTTPClient interface for the http client.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// AddHeaders function supports adding custom http headers (eg: authorization headers for the remote service).
+type AddHeaders func(req *http.Request) (*http.Header, error)
+
+// Opt are the remote Lock options.
+type Opt func(l *Lock)
+
+// WithHeaders option is for setting additional http request headers.
+func WithHeaders(addHeadersFunc AddHeaders) Opt {
+	return func(l *Lock) {
+		l.headersFunc = addHeadersFunc
+	}
+}
+
+// Lock is a secret lock service calling a remote secret lock service.
+type Lock struct {
+	serviceURL  string
+	httpClient  HTTPClient
+	headersFunc AddHeaders
+}
+
+// New creates a new remote secret lock service client for the service at serviceURL.
+func New(serviceURL string, client HTTPClient, opts ...Opt) *Lock {
+	l := &Lock{
+		serviceURL: strings.TrimSuffix(serviceURL, "/"),
+		httpClient: client,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// Encrypt req for master key in keyURI with the remote secret lock service.
+func (l *Lock) Encrypt(keyURI string, req *secretlock.EncryptRequest) (*secretlock.EncryptResponse, error) {
+	resp := &encryptResp{}
+
+	err := l.post(EncryptPath, &encryptReq{
+		KeyURI:    keyURI,
+		Plaintext: req.Plaintext,
+		AAD:       req.AdditionalAuthenticatedData,
+	}, resp)
+	if err != nil {
+		return nil, fmt.Errorf("remote encrypt: %w", err)
+	}
+
+	return &secretlock.EncryptResponse{Ciphertext: resp.Ciphertext}, nil
+}
+
+// Decrypt req for master key in keyURI with the remote secret lock service.
+func (l *Lock) Decrypt(keyURI string, req *secretlock.DecryptRequest) (*secretlock.DecryptResponse, error) {
+	resp := &decryptResp{}
+
+	err := l.post(DecryptPath, &decryptReq{
+		KeyURI:     keyURI,
+		Ciphertext: req.Ciphertext,
+		AAD:        req.AdditionalAuthenticatedData,
+	}, resp)
+
+	var remoteErr *kmsapi.RemoteError
+
+	if errors.As(err, &remoteErr) && remoteErr.StatusCode == http.StatusUnprocessableEntity {
+		return nil, fmt.Errorf("remote decrypt: %w: %w", secretlock.ErrLocked, err)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("remote decrypt: %w", err)
+	}
+
+	return &secretlock.DecryptResponse{Plaintext: resp.Plaintext}, nil
+}
+
+func (l *Lock) post(path string, req, resp interface{}) error {
+	mReq, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, l.serviceURL+path, bytes.NewBuffer(mReq))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", contentType)
+
+	if l.headersFunc != nil {
+		httpHeaders, e := l.headersFunc(httpReq)
+		if e != nil {
+			return fmt.Errorf("add optional request headers: %w", e)
+		}
+
+		if httpHeaders != nil {
+			httpReq.Header = httpHeaders.Clone()
+		}
+	}
+
+	httpResp, err := l.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("posting request failed: %w: %w", kmsapi.ErrRemoteUnavailable, err)
+	}
+
+	defer httpResp.Body.Close() //nolint:errcheck
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("read response body: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		errMsg := &errMessage{}
+
+		if e := json.Unmarshal(body, errMsg); e != nil || errMsg.Error == "" {
+			errMsg.Error = string(body)
+		}
+
+		return &kmsapi.RemoteError{
+			StatusCode: httpResp.StatusCode,
+			Message:    fmt.Sprintf("http status %d: %s", httpResp.StatusCode, errMsg.Error),
+		}
+	}
+
+	if err = json.Unmarshal(body, resp); err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	return nil
+}
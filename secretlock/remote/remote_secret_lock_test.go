@@ -0,0 +1,184 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remote_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/spi/secretlock"
+
+	"github.com/trustbloc/kms-go/secretlock/local"
+	"github.com/trustbloc/kms-go/secretlock/remote"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func newLocalLock(t *testing.T) secretlock.Service {
+	t.Helper()
+
+	masterKey := base64.URLEncoding.EncodeToString(random.GetRandomBytes(32))
+
+	lock, err := local.NewService(bytes.NewReader([]byte(masterKey)), nil)
+	require.NoError(t, err)
+
+	return lock
+}
+
+func allowAll(*http.Request, string) error {
+	return nil
+}
+
+func TestRemoteLock(t *testing.T) {
+	srv := httptest.NewServer(remote.NewHandler(newLocalLock(t), remote.WithAuthorize(allowAll),
+		remote.WithMaxRequestSize(1024)))
+	defer srv.Close()
+
+	lock := remote.New(srv.URL, srv.Client())
+
+	t.Run("encrypt and decrypt", func(t *testing.T) {
+		plaintext := base64.URLEncoding.EncodeToString([]byte("secret"))
+		aad := base64.URLEncoding.EncodeToString([]byte("aad"))
+
+		encResp, err := lock.Encrypt("test/master/key", &secretlock.EncryptRequest{
+			Plaintext:                   plaintext,
+			AdditionalAuthenticatedData: aad,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, encResp.Ciphertext)
+
+		decResp, err := lock.Decrypt("test/master/key", &secretlock.DecryptRequest{
+			Ciphertext:                  encResp.Ciphertext,
+			AdditionalAuthenticatedData: aad,
+		})
+		require.NoError(t, err)
+		require.Equal(t, plaintext, decResp.Plaintext)
+
+		_, err = lock.Decrypt("test/master/key", &secretlock.DecryptRequest{Ciphertext: encResp.Ciphertext})
+		require.ErrorContains(t, err, "http status 422")
+		require.ErrorIs(t, err, secretlock.ErrLocked)
+		require.NotErrorIs(t, err, kms.ErrRemoteUnavailable)
+	})
+
+	t.Run("LocalKMS with a remote lock", func(t *testing.T) {
+		k := mockkms.NewForTest(t, mockkms.WithSecretLock(lock))
+
+		kid, _, err := k.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		_, err = k.Get(kid)
+		require.NoError(t, err)
+
+		// the keys can't be read without the remote service master key.
+		_, err = mockkms.NewForTest(t, mockkms.WithStore(k.Store()), mockkms.WithSecretLock(newLocalLock(t))).Get(kid)
+		require.Error(t, err)
+	})
+
+	t.Run("headers", func(t *testing.T) {
+		var authorization string
+
+		authSrv := httptest.NewServer(remote.NewHandler(newLocalLock(t),
+			remote.WithAuthorize(func(req *http.Request, keyURI string) error {
+				authorization = req.Header.Get("Authorization")
+
+				if authorization != "Bearer token" || keyURI != "allowed/key" {
+					return errors.New("not allowed")
+				}
+
+				return nil
+			})))
+		defer authSrv.Close()
+
+		authLock := remote.New(authSrv.URL+"/", authSrv.Client(), remote.WithHeaders(
+			func(req *http.Request) (*http.Header, error) {
+				req.Header.Set("Authorization", "Bearer token")
+
+				return &req.Header, nil
+			}))
+
+		_, err := authLock.Encrypt("allowed/key", &secretlock.EncryptRequest{Plaintext: "c2VjcmV0"})
+		require.NoError(t, err)
+		require.Equal(t, "Bearer token", authorization)
+
+		_, err = authLock.Encrypt("other/key", &secretlock.EncryptRequest{Plaintext: "c2VjcmV0"})
+		require.EqualError(t, err, "remote encrypt: http status 403: not allowed")
+
+		var remoteErr *kms.RemoteError
+
+		require.ErrorAs(t, err, &remoteErr)
+		require.Equal(t, http.StatusForbidden, remoteErr.StatusCode)
+
+		_, err = remote.New(authSrv.URL, authSrv.Client()).Decrypt("allowed/key", &secretlock.DecryptRequest{})
+		require.EqualError(t, err, "remote decrypt: http status 403: not allowed")
+
+		_, err = remote.New(authSrv.URL, authSrv.Client(), remote.WithHeaders(
+			func(req *http.Request) (*http.Header, error) {
+				return nil, errors.New("no token")
+			})).Encrypt("allowed/key", &secretlock.EncryptRequest{})
+		require.EqualError(t, err, "remote encrypt: add optional request headers: no token")
+	})
+
+	t.Run("deny by default", func(t *testing.T) {
+		for _, opts := range [][]remote.HandlerOpt{nil, {remote.WithAuthorize(nil)}} {
+			denySrv := httptest.NewServer(remote.NewHandler(newLocalLock(t), opts...))
+
+			_, err := remote.New(denySrv.URL, denySrv.Client()).Encrypt("test/master/key",
+				&secretlock.EncryptRequest{Plaintext: "c2VjcmV0"})
+			require.EqualError(t, err, "remote encrypt: http status 403: "+remote.ErrNoAuthorize.Error())
+
+			denySrv.Close()
+		}
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		_, err := remote.New("http://localhost:1", http.DefaultClient).Encrypt("key", &secretlock.EncryptRequest{})
+		require.ErrorContains(t, err, "remote encrypt: posting request failed")
+		require.ErrorIs(t, err, kms.ErrRemoteUnavailable)
+
+		_, err = remote.New(":bad url", http.DefaultClient).Encrypt("key", &secretlock.EncryptRequest{})
+		require.ErrorContains(t, err, "remote encrypt: build request")
+
+		resp, err := srv.Client().Get(srv.URL + remote.EncryptPath)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+		resp, err = srv.Client().Post(srv.URL+remote.DecryptPath, "application/json", bytes.NewBufferString("{"))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		_, err = lock.Encrypt("key", &secretlock.EncryptRequest{Plaintext: strings.Repeat("a", 1024)})
+		require.ErrorContains(t, err, "remote encrypt: http status 413")
+
+		badSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == remote.EncryptPath {
+				w.WriteHeader(http.StatusBadGateway)
+			}
+
+			_, _ = w.Write([]byte("not json")) //nolint:errcheck
+		}))
+		defer badSrv.Close()
+
+		badLock := remote.New(badSrv.URL, badSrv.Client())
+
+		_, err = badLock.Encrypt("key", &secretlock.EncryptRequest{})
+		require.EqualError(t, err, "remote encrypt: http status 502: not json")
+		require.ErrorIs(t, err, kms.ErrRemoteUnavailable)
+
+		_, err = badLock.Decrypt("key", &secretlock.DecryptRequest{})
+		require.ErrorContains(t, err, "remote decrypt: unmarshal response")
+	})
+}
@@ -0,0 +1,46 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package crypto
+
+// BatchSigner is implemented by Crypto implementations signing batches of messages with a single key resolution and
+// signer primitive. It is an optional interface: callers should type-assert for it, or use crypto.SignBatch which
+// falls back to Crypto.Sign.
+type BatchSigner interface {
+	// SignBatch signs every message of messages using the signing key of kh.
+	// returns:
+	// 		the signatures, in the order of messages
+	// 		error in case of errors, no signature is returned if any message fails to be signed
+	SignBatch(messages [][]byte, kh interface{}, opts ...SignBatchOpts) ([][]byte, error)
+}
+
+type signBatchOpts struct {
+	parallelism int
+}
+
+// NewSignBatchOpt creates a new empty sign batch option.
+// Not to be used directly. It's intended for implementations of the BatchSigner interface.
+// Use WithParallelism() option function below instead.
+func NewSignBatchOpt() *signBatchOpts { // nolint // unexported type doesn't need to be used outside of crypto package
+	return &signBatchOpts{}
+}
+
+// Parallelism gets the maximum number of messages signed concurrently.
+// Not to be used directly. It's intended for implementations of the BatchSigner interface.
+func (o *signBatchOpts) Parallelism() int {
+	return o.parallelism
+}
+
+// SignBatchOpts are the BatchSigner.SignBatch options.
+type SignBatchOpts func(opts *signBatchOpts)
+
+// WithParallelism option signs at most n messages concurrently. Without it, or with n <= 1, messages are signed
+// sequentially.
+func WithParallelism(n int) SignBatchOpts {
+	return func(opts *signBatchOpts) {
+		opts.parallelism = n
+	}
+}
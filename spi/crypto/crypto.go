@@ -13,6 +13,8 @@ SPDX-License-Identifier: Apache-2.0
 // primitives or via webkms for remote KMS BBS+ signing.
 package crypto
 
+import "io"
+
 // Crypto interface provides all crypto operations needed in the Aries framework.
 type Crypto interface {
 	// Encrypt will encrypt msg and aad using a matching AEAD primitive in kh key handle of a public key
@@ -86,6 +88,124 @@ type Crypto interface {
 	DeriveProof(messages [][]byte, bbsSignature, nonce []byte, revealedIndexes []int, kh interface{}) ([]byte, error)
 }
 
+// KEKWrapper is implemented by Crypto implementations supporting direct AES key wrapping (RFC 3394) with symmetric key
+// encryption keys, for envelope encryption without the ECDH key agreement of WrapKey. It is an optional interface:
+// callers should type-assert for it. Wrapped keys are unwrapped with Crypto.UnwrapKey and the same kek.
+type KEKWrapper interface {
+	// WrapKeyWithKEK wraps key material with the key encryption key handle kek.
+	// returns:
+	// 		RecipientWrappedKey containing the wrapped key and the A128KW, A192KW or A256KW alg
+	// 		error in case of errors
+	WrapKeyWithKEK(key []byte, kek interface{}) (*RecipientWrappedKey, error)
+}
+
+// DirectKeyAgreement is implemented by Crypto implementations supporting JWE ECDH-ES direct key agreement, where the
+// key derived from the ECDH exchange is used as the CEK instead of wrapping one. It is an optional interface: callers
+// should type-assert for it. The recipient derives the same CEK by calling Crypto.UnwrapKey with the returned
+// RecipientWrappedKey and its private key.
+type DirectKeyAgreement interface {
+	// DeriveDirectKey derives a CEK for the content encryption algorithm enc (eg: A256GCM) using apu, apv and an
+	// ephemeral key agreement with the recipient public key recPubKey.
+	// returns:
+	// 		the derived CEK
+	// 		RecipientWrappedKey with the ECDH-ES alg, enc, EPK, APU and APV and no EncryptedCEK
+	// 		error in case of errors
+	DeriveDirectKey(enc string, apu, apv []byte, recPubKey *PublicKey) ([]byte, *RecipientWrappedKey, error)
+}
+
+// DetachedNonceAEAD is implemented by Crypto implementations supporting AEAD encryption with a nonce chosen by the
+// caller and carried separately from the ciphertext, for protocols carrying the IV in headers (eg: JWE or COSE). It is
+// an optional interface: callers should type-assert for it.
+type DetachedNonceAEAD interface {
+	// EncryptWithNonce encrypts msg with aad and nonce using the primary AEAD key of kh. nonce must have the nonce
+	// size of the key content encryption algorithm, it is checked with the WithNoncePolicy option when set.
+	// returns:
+	// 		the ciphertext followed by the tag, without nonce
+	// 		error in case of errors
+	EncryptWithNonce(msg, aad, nonce []byte, kh interface{}, opts ...NonceOpts) ([]byte, error)
+	// DecryptDetached decrypts cipher, created by EncryptWithNonce or Crypto.Encrypt, with aad and its nonce using a
+	// matching AEAD key in kh.
+	// returns:
+	// 		plaintext in []byte
+	// 		error in case of errors
+	DecryptDetached(cipher, aad, nonce []byte, kh interface{}) ([]byte, error)
+}
+
+// KeyDeriver is implemented by Crypto implementations supporting HKDF (RFC 5869) key derivation from symmetric keys,
+// so that new key material is derived without exporting the key it is derived from. It is an optional interface:
+// callers should type-assert for it.
+type KeyDeriver interface {
+	// DeriveKey derives length bytes of key material from the primary key of kh with HKDF, using salt and info.
+	// returns:
+	// 		the derived key material
+	// 		error in case of errors
+	DeriveKey(kh interface{}, salt, info []byte, length int) ([]byte, error)
+}
+
+// PublicKeyVerifier is implemented by Crypto implementations verifying signatures with raw public keys, for stateless
+// verification services: the signer public key is not imported in the KMS. It is an optional interface: callers
+// should type-assert for it.
+type PublicKeyVerifier interface {
+	// VerifyWithPublicKey verifies signature of msg with pubKey: an EC public key on a NIST P curve (ECDSA signature
+	// in the IEEE P1363 or DER format), an OKP Ed25519 public key or an EC BLS12381_G2 BBS+ public key (signature of
+	// the single message msg) whose X is the public key bytes.
+	// returns:
+	// 		error in case of errors or nil if signature verification was successful
+	VerifyWithPublicKey(signature, msg []byte, pubKey *PublicKey) error
+	// VerifyMultiWithPublicKey verifies the BBS+ signature of messages with the BLS12381_G2 public key pubKey.
+	// returns:
+	// 		error in case of errors or nil if signature verification was successful
+	VerifyMultiWithPublicKey(messages [][]byte, signature []byte, pubKey *PublicKey) error
+}
+
+// DHComputer is implemented by Crypto implementations computing raw Diffie-Hellman shared secrets with the private
+// keys they hold, for protocols running their own key schedule on top of DH (eg: Noise): the private key never leaves
+// the Crypto implementation. It is an optional interface: callers should type-assert for it. Raw shared secrets bypass
+// the key derivation of key wrapping, so implementations disable it by default and fail with ErrComputeDHDisabled
+// until enabled (eg: tinkcrypto.WithComputeDH), key ID based callers also enable it per key (see kms/keyref
+// KeyRef.ComputeDH).
+type DHComputer interface {
+	// ComputeDH computes the shared secret of the ECDH private key in kh (an X25519, X448 or NIST P curve ECDH key) and
+	// the public key pubKey, of the same curve. The shared secret is the raw X coordinate of the DH output, it must be
+	// hashed or run through a KDF before being used as a key.
+	// returns:
+	// 		the shared secret in []byte
+	// 		error in case of errors
+	ComputeDH(kh interface{}, pubKey *PublicKey) ([]byte, error)
+}
+
+// CryptoBox is the libsodium compatible X25519 box service of the legacy (DIDComm v1) authcrypt and anoncrypt
+// packers: Easy and EasyOpen are the authenticated crypto_box operations and Seal and SealOpen the anonymous
+// crypto_box_seal ones. Private keys are read by the implementation from its KMS (eg: localkms or a remote key server
+// with webkms), callers only see public keys. Recipient keys are identified by their Ed25519 public keys, the box keys
+// being their X25519 conversion.
+type CryptoBox interface {
+	// Easy seals payload with the 24 bytes nonce for the X25519 public key theirPub, using the X25519 conversion of the
+	// Ed25519 private key of myKID.
+	// returns:
+	// 		cipherText in []byte
+	// 		error in case of errors
+	Easy(payload, nonce, theirPub []byte, myKID string) ([]byte, error)
+	// EasyOpen opens cipherText sealed with Easy by the X25519 public key theirPub, using nonce and the X25519
+	// conversion of the Ed25519 private key of the public key myPub.
+	// returns:
+	// 		plainText in []byte
+	// 		error in case of errors
+	EasyOpen(cipherText, nonce, theirPub, myPub []byte) ([]byte, error)
+	// Seal anonymously seals payload for the X25519 public key theirEncPub with an ephemeral key pair generated with
+	// randSource, the equivalent of libsodium crypto_box_seal.
+	// returns:
+	// 		cipherText in []byte, prefixed with the ephemeral public key
+	// 		error in case of errors
+	Seal(payload, theirEncPub []byte, randSource io.Reader) ([]byte, error)
+	// SealOpen opens cipherText sealed with Seal, using the X25519 conversion of the Ed25519 private key of the public
+	// key myPub.
+	// returns:
+	// 		plainText in []byte
+	// 		error in case of errors
+	SealOpen(cipherText, myPub []byte) ([]byte, error)
+}
+
 // RecipientWrappedKey contains recipient key material required to unwrap CEK.
 type RecipientWrappedKey struct {
 	KID          string    `json:"kid,omitempty"`
@@ -94,6 +214,11 @@ type RecipientWrappedKey struct {
 	Alg          string    `json:"alg,omitempty"`
 	APU          []byte    `json:"apu,omitempty"`
 	APV          []byte    `json:"apv,omitempty"`
+	// Enc is the content encryption alg the CEK is derived for. It is only set for ECDH-ES direct key agreement.
+	Enc string `json:"enc,omitempty"`
+	// Nested is set when EncryptedCEK wraps another (serialized) RecipientWrappedKey instead of a raw CEK. Such
+	// layered keys are built for onward routing (eg: mediators) and are peeled one layer per recipient key.
+	Nested bool `json:"nested,omitempty"`
 }
 
 // PublicKey mainly to exchange EPK in RecipientWrappedKey.
@@ -0,0 +1,17 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package crypto
+
+import "errors"
+
+// ErrAuthTagMismatch is wrapped by the errors returned when the authentication of a ciphertext, a wrapped key or a
+// MAC fails: the data was tampered with, or the key, nonce or associated data don't match.
+var ErrAuthTagMismatch = errors.New("authentication tag mismatch")
+
+// ErrComputeDHDisabled is wrapped by the errors of the DHComputer implementations when raw Diffie-Hellman shared
+// secrets are not enabled for the key.
+var ErrComputeDHDisabled = errors.New("computeDH is not enabled for the key")
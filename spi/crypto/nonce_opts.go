@@ -0,0 +1,42 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package crypto
+
+// NoncePolicy checks nonces chosen by callers of DetachedNonceAEAD.EncryptWithNonce before they are used, eg: to
+// reject a nonce already used with the same key.
+type NoncePolicy interface {
+	// CheckNonce returns an error if nonce must not be used to encrypt with the key identified by kid.
+	CheckNonce(kid string, nonce []byte) error
+}
+
+type nonceOpts struct {
+	policy NoncePolicy
+}
+
+// NewNonceOpt creates a new empty nonce option.
+// Not to be used directly. It's intended for implementations of the DetachedNonceAEAD interface.
+// Use WithNoncePolicy() option function below instead.
+func NewNonceOpt() *nonceOpts { // nolint // unexported type doesn't need to be used outside of crypto package
+	return &nonceOpts{}
+}
+
+// Policy gets the nonce policy to check the nonce with.
+// Not to be used directly. It's intended for implementations of the DetachedNonceAEAD interface.
+func (o *nonceOpts) Policy() NoncePolicy {
+	return o.policy
+}
+
+// NonceOpts are the DetachedNonceAEAD.EncryptWithNonce options.
+type NonceOpts func(opts *nonceOpts)
+
+// WithNoncePolicy option checks the nonce given to EncryptWithNonce with policy. Without it, only the nonce size is
+// validated and the caller is responsible of never reusing a nonce with the same key.
+func WithNoncePolicy(policy NoncePolicy) NonceOpts {
+	return func(opts *nonceOpts) {
+		opts.policy = policy
+	}
+}
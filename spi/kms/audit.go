@@ -0,0 +1,21 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import "time"
+
+// AuditLogger records the KMS and Crypto operations executed by an implementation, for key usage trails. It is called
+// once per operation, after it completes, with the key it applies to (if known), whether it succeeded, how long it
+// took and its caller (nil for operations executed without a caller context).
+type AuditLogger interface {
+	OnOperation(op Operation, keyID string, success bool, latency time.Duration, caller *CallerInfo)
+}
+
+// AuditLoggerProvider is implemented by Providers setting the AuditLogger of the KeyManager created with them.
+type AuditLoggerProvider interface {
+	AuditLogger() AuditLogger
+}
@@ -0,0 +1,34 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import "context"
+
+// CallerInfo identifies the caller of a KMS or Crypto operation. Multi-user services attach it to the context of each
+// operation with WithCallerInfo so that authorization, audit and rate limiting layers act per caller.
+type CallerInfo struct {
+	// Subject is the authenticated identity of the caller (eg: a user or service account ID).
+	Subject string `json:"subject,omitempty"`
+	// Origin is where the request comes from (eg: a client address or application ID).
+	Origin string `json:"origin,omitempty"`
+	// Purpose is the reason given by the caller for the operation.
+	Purpose string `json:"purpose,omitempty"`
+}
+
+type callerInfoKey struct{}
+
+// WithCallerInfo returns a copy of ctx carrying info.
+func WithCallerInfo(ctx context.Context, info *CallerInfo) context.Context {
+	return context.WithValue(ctx, callerInfoKey{}, info)
+}
+
+// CallerInfoFromContext returns the CallerInfo attached to ctx by WithCallerInfo, or nil if there is none.
+func CallerInfoFromContext(ctx context.Context) *CallerInfo {
+	info, _ := ctx.Value(callerInfoKey{}).(*CallerInfo) //nolint:errcheck
+
+	return info
+}
@@ -0,0 +1,189 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import "sort"
+
+// Operation is a key management or crypto operation advertised in Capabilities.
+type Operation string
+
+// Key management operations.
+const (
+	OperationCreate          = Operation("create")
+	OperationRotate          = Operation("rotate")
+	OperationExportPublicKey = Operation("exportPublicKey")
+	OperationImportPrivate   = Operation("importPrivateKey")
+	OperationExportPrivate   = Operation("exportPrivateKey")
+)
+
+// Crypto operations.
+const (
+	OperationEncrypt     = Operation("encrypt")
+	OperationDecrypt     = Operation("decrypt")
+	OperationSign        = Operation("sign")
+	OperationVerify      = Operation("verify")
+	OperationComputeMAC  = Operation("computeMAC")
+	OperationVerifyMAC   = Operation("verifyMAC")
+	OperationComputePRF  = Operation("computePRF")
+	OperationDeriveKey   = Operation("deriveKey")
+	OperationComputeDH   = Operation("computeDH")
+	OperationWrapKey     = Operation("wrapKey")
+	OperationUnwrapKey   = Operation("unwrapKey")
+	OperationSignMulti   = Operation("signMulti")
+	OperationVerifyMulti = Operation("verifyMulti")
+	OperationDeriveProof = Operation("deriveProof")
+	OperationVerifyProof = Operation("verifyProof")
+)
+
+// KeyCapability describes what an implementation supports for one key type.
+type KeyCapability struct {
+	KeyType KeyType `json:"keyType"`
+	// Algorithms are the JOSE algorithm identifiers (eg: ES256, ECDH-ES+A256KW) keys of this type can be used with.
+	Algorithms []string    `json:"algorithms,omitempty"`
+	Operations []Operation `json:"operations"`
+}
+
+// Limits are the implementation limits, a zero value means no limit.
+type Limits struct {
+	// MaxMessageSize is the maximum size in bytes of a message (or plaintext) accepted by a single operation.
+	MaxMessageSize int `json:"maxMessageSize,omitempty"`
+	// MaxMessages is the maximum number of messages accepted by the multi-message operations (eg: SignMulti).
+	MaxMessages int `json:"maxMessages,omitempty"`
+}
+
+// Capabilities is a machine-readable description of the key types, algorithms, operations and limits supported by
+// a KeyManager or Crypto implementation.
+type Capabilities struct {
+	KeyTypes []KeyCapability `json:"keyTypes"`
+	// ContentEncryption are the JWE content encryption algorithms ("enc" values) supported.
+	ContentEncryption []string `json:"contentEncryption,omitempty"`
+	Limits            Limits   `json:"limits"`
+}
+
+// CapabilitiesProvider is implemented by KeyManager and Crypto implementations that can describe what they support.
+// It is an optional interface: callers should type-assert for it and degrade gracefully when it is not implemented.
+type CapabilitiesProvider interface {
+	Capabilities() (*Capabilities, error)
+}
+
+// KeyType returns the capability of kt, or nil if kt is not supported.
+func (c *Capabilities) KeyType(kt KeyType) *KeyCapability {
+	for i := range c.KeyTypes {
+		if c.KeyTypes[i].KeyType == kt {
+			return &c.KeyTypes[i]
+		}
+	}
+
+	return nil
+}
+
+// Supports returns true if op is supported for keys of type kt.
+func (c *Capabilities) Supports(kt KeyType, op Operation) bool {
+	kc := c.KeyType(kt)
+	if kc == nil {
+		return false
+	}
+
+	for _, o := range kc.Operations {
+		if o == op {
+			return true
+		}
+	}
+
+	return false
+}
+
+// KeyTypesFor returns the key types supporting all ops.
+func (c *Capabilities) KeyTypesFor(ops ...Operation) []KeyType {
+	var kts []KeyType
+
+	for _, kc := range c.KeyTypes {
+		supported := true
+
+		for _, op := range ops {
+			if !c.Supports(kc.KeyType, op) {
+				supported = false
+
+				break
+			}
+		}
+
+		if supported {
+			kts = append(kts, kc.KeyType)
+		}
+	}
+
+	return kts
+}
+
+// MergeCapabilities combines the capabilities of components used together, typically a KeyManager and the Crypto
+// running its keys. Key types, algorithms and operations are merged, limits are the most restrictive ones.
+func MergeCapabilities(caps ...*Capabilities) *Capabilities {
+	merged := &Capabilities{}
+	keyTypes := map[KeyType]*KeyCapability{}
+
+	var order []KeyType
+
+	for _, c := range caps {
+		if c == nil {
+			continue
+		}
+
+		for _, kc := range c.KeyTypes {
+			m, ok := keyTypes[kc.KeyType]
+			if !ok {
+				m = &KeyCapability{KeyType: kc.KeyType}
+				keyTypes[kc.KeyType] = m
+				order = append(order, kc.KeyType)
+			}
+
+			m.Algorithms = union(m.Algorithms, kc.Algorithms)
+			m.Operations = union(m.Operations, kc.Operations)
+		}
+
+		merged.ContentEncryption = union(merged.ContentEncryption, c.ContentEncryption)
+		merged.Limits.MaxMessageSize = minLimit(merged.Limits.MaxMessageSize, c.Limits.MaxMessageSize)
+		merged.Limits.MaxMessages = minLimit(merged.Limits.MaxMessages, c.Limits.MaxMessages)
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return order[i] < order[j] })
+
+	for _, kt := range order {
+		merged.KeyTypes = append(merged.KeyTypes, *keyTypes[kt])
+	}
+
+	return merged
+}
+
+func union[T comparable](a, b []T) []T {
+	for _, v := range b {
+		found := false
+
+		for _, e := range a {
+			if e == v {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			a = append(a, v)
+		}
+	}
+
+	return a
+}
+
+// minLimit returns the most restrictive of two limits, 0 meaning no limit.
+func minLimit(a, b int) int {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+
+	return a
+}
@@ -0,0 +1,70 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// The KMS errors below are wrapped by the errors of the KMS and Crypto implementations (localkms, webkms,
+// tinkcrypto and the secret locks), callers branch on them with errors.Is. The errors of the remote servers are
+// returned as *RemoteError, matched with errors.As.
+var (
+	// ErrKeyNotFound is wrapped by the errors returned for key IDs that are not stored. It is also the error that a KMS
+	// expects from the Store.Get method if no key stored under the given key ID could be found.
+	ErrKeyNotFound = errors.New("key not found")
+
+	// ErrInvalidKeyType is wrapped by the errors returned for unsupported key types, key handles that are not of the
+	// expected type and keys that don't match the requested key type.
+	ErrInvalidKeyType = errors.New("invalid key type")
+
+	// ErrRemoteUnavailable is wrapped by the errors returned when a remote KMS, crypto or secret lock server can't be
+	// reached or fails with a server error: the operation may succeed if it is retried later.
+	ErrRemoteUnavailable = errors.New("remote server unavailable")
+)
+
+// RemoteError is the error response of a remote KMS, crypto or secret lock server. It matches ErrRemoteUnavailable
+// for server errors (5xx) and too many requests (429) responses, and ErrKeyNotFound for not found (404) responses.
+type RemoteError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Message is the error message of the response.
+	Message string
+}
+
+// Error returns the message of the response.
+func (e *RemoteError) Error() string {
+	return e.Message
+}
+
+// Is reports whether e matches target, ErrRemoteUnavailable or ErrKeyNotFound depending on its status code.
+func (e *RemoteError) Is(target error) bool {
+	switch target { //nolint:errorlint // the sentinel errors are compared
+	case ErrRemoteUnavailable:
+		return e.StatusCode >= http.StatusInternalServerError || e.StatusCode == http.StatusTooManyRequests
+	case ErrKeyNotFound:
+		return e.StatusCode == http.StatusNotFound
+	default:
+		return false
+	}
+}
+
+// VersionConflictError is the error of the StoreVersioner.PutWithVersion method when the version of the stored key
+// isn't the expected one: the key was created, updated or deleted concurrently. It is matched with errors.As.
+type VersionConflictError struct {
+	// Expected is the expected version of the key, 0 if no key was expected.
+	Expected int64
+	// Actual is the version of the stored key, 0 if no key is stored.
+	Actual int64
+}
+
+// Error returns the expected and actual versions of the key.
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("version conflict: expected version %d, stored version %d", e.Expected, e.Actual)
+}
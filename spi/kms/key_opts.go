@@ -8,8 +8,11 @@ package kms
 
 // keyOpts holds options for Create, Rotate and CreateAndExportPubKeyBytes.
 type keyOpts struct {
-	attrs    []string
-	metadata map[string]any
+	attrs     []string
+	metadata  map[string]any
+	kidScheme *KIDScheme
+	keyID     string
+	reuseKey  bool
 }
 
 // NewKeyOpt creates a new empty key option.
@@ -31,6 +34,21 @@ func (pk *keyOpts) Metadata() map[string]any {
 	return pk.metadata
 }
 
+// KIDScheme gets the KID scheme of an asymmetric key creation, nil if not set.
+func (pk *keyOpts) KIDScheme() *KIDScheme {
+	return pk.kidScheme
+}
+
+// KeyID gets the key ID requested for a key creation, empty if not set.
+func (pk *keyOpts) KeyID() string {
+	return pk.keyID
+}
+
+// ReuseExistingKey gets whether a key creation with a requested key ID returns the existing key of that ID.
+func (pk *keyOpts) ReuseExistingKey() bool {
+	return pk.reuseKey
+}
+
 // KeyOpts are the create key option.
 type KeyOpts func(opts *keyOpts)
 
@@ -47,3 +65,28 @@ func WithMetadata(metadata map[string]any) KeyOpts {
 		opts.metadata = metadata
 	}
 }
+
+// WithKIDScheme option is for creating an asymmetric key with a KID computed with scheme instead of the default
+// scheme of the KeyManager.
+func WithKIDScheme(scheme KIDScheme) KeyOpts {
+	return func(opts *keyOpts) {
+		opts.kidScheme = &scheme
+	}
+}
+
+// WithCreateKeyID option is for creating a key with the key ID keyID instead of a key ID generated by the KeyManager,
+// eg: to provision keys with deterministic key IDs. The creation fails if a key of that ID already exists, unless
+// the WithReuseExistingKey option is set. It is ignored by Rotate.
+func WithCreateKeyID(keyID string) KeyOpts {
+	return func(opts *keyOpts) {
+		opts.keyID = keyID
+	}
+}
+
+// WithReuseExistingKey option makes a key creation with the WithCreateKeyID option idempotent: if a key of the
+// requested key ID already exists and has the requested key type, it is returned instead of creating a new key.
+func WithReuseExistingKey() KeyOpts {
+	return func(opts *keyOpts) {
+		opts.reuseKey = true
+	}
+}
@@ -0,0 +1,27 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+// KIDEncoding is an encoding of the JWK thumbprint KIDs of asymmetric keys.
+type KIDEncoding string
+
+// KID encodings.
+const (
+	// KIDEncodingBase64URL is the base64 raw URL encoding of the thumbprint, the default.
+	KIDEncodingBase64URL = KIDEncoding("base64url")
+	// KIDEncodingMultibase is the base58btc multibase encoding of the sha2-256 multihash of the thumbprint
+	// (eg: "zQm...").
+	KIDEncodingMultibase = KIDEncoding("multibase")
+)
+
+// KIDScheme is a computation mode of the KIDs of asymmetric keys: the RFC 7638 SHA-256 JWK thumbprint of the public
+// key, truncated to its first Size bytes if Size is set, and encoded with Encoding. The zero value is the default
+// scheme: the full thumbprint, base64 raw URL encoded.
+type KIDScheme struct {
+	Encoding KIDEncoding
+	Size     int
+}
@@ -89,6 +89,34 @@ type StoreWithMetadata interface {
 	GetWithMetadata(keysetID string) (key []byte, metadata map[string]any, err error)
 }
 
+// StoreCreator defines the optional storage capability to store a new key atomically, for stores shared by concurrent
+// KeyManagers: a KeyManager creating a key with a given keysetID can't overwrite the key stored concurrently by another
+// one under the same keysetID.
+type StoreCreator interface {
+	// Create stores the given key and metadata (which may be nil) under the given keysetID, only if no key is already
+	// stored under it. Otherwise, the returned error is expected to wrap ErrKeyExists.
+	Create(keysetID string, key []byte, metadata map[string]any) error
+}
+
+// StoreVersioner defines the optional storage capability of optimistic concurrency on key updates, for stores shared by
+// concurrent KeyManagers: a key read with its version is only replaced if no other KeyManager updated it since.
+type StoreVersioner interface {
+	// GetWithVersion retrieves the key, its metadata and version stored under the given keysetID. The version is
+	// incremented by every update of the key. If no key is found, the returned error is expected to wrap
+	// ErrKeyNotFound.
+	GetWithVersion(keysetID string) (key []byte, metadata map[string]any, version int64, err error)
+	// PutWithVersion stores the given key and metadata (which may be nil) under the given keysetID, only if the version
+	// of the stored key is expectedVersion, or if no key is stored and expectedVersion is 0. Otherwise, the returned
+	// error is expected to wrap a *VersionConflictError.
+	PutWithVersion(keysetID string, key []byte, metadata map[string]any, expectedVersion int64) error
+}
+
+// StoreLister defines the optional storage capability to list the IDs of the stored keys.
+type StoreLister interface {
+	// KeyIDs returns the IDs of all the keys in the store.
+	KeyIDs() ([]string, error)
+}
+
 // Provider for KeyManager builder/constructor.
 type Provider interface {
 	StorageProvider() Store
@@ -109,6 +137,8 @@ const (
 	ChaCha20Poly1305 = "ChaCha20Poly1305"
 	// XChaCha20Poly1305 key type value.
 	XChaCha20Poly1305 = "XChaCha20Poly1305"
+	// AESGCMSIV256 key type value.
+	AESGCMSIV256 = "AESGCMSIV256"
 	// ECDSAP256DER key type value.
 	ECDSAP256DER = "ECDSAP256DER"
 	// ECDSAP384DER key type value.
@@ -125,14 +155,28 @@ const (
 	ECDSAP521IEEEP1363 = "ECDSAP521IEEEP1363"
 	// ECDSASecp256k1IEEEP1363 key type value.
 	ECDSASecp256k1IEEEP1363 = "ECDSASecp256k1IEEEP1363"
+	// BIP340Secp256k1 key type value.
+	BIP340Secp256k1 = "BIP340Secp256k1"
 	// ED25519 key type value.
 	ED25519 = "ED25519"
 	// RSARS256 key type value.
 	RSARS256 = "RSARS256"
 	// RSAPS256 key type value.
 	RSAPS256 = "RSAPS256"
+	// RSAOAEP256 key type value.
+	RSAOAEP256 = "RSAOAEP256"
 	// HMACSHA256Tag256 key type value.
 	HMACSHA256Tag256 = "HMACSHA256Tag256"
+	// HMACSHA384Tag384 key type value.
+	HMACSHA384Tag384 = "HMACSHA384Tag384"
+	// HMACSHA512Tag512 key type value.
+	HMACSHA512Tag512 = "HMACSHA512Tag512"
+	// AES128KW key type value.
+	AES128KW = "AES128KW"
+	// AES192KW key type value.
+	AES192KW = "AES192KW"
+	// AES256KW key type value.
+	AES256KW = "AES256KW"
 	// NISTP256ECDHKW key type value.
 	NISTP256ECDHKW = "NISTP256ECDHKW"
 	// NISTP384ECDHKW key type value.
@@ -141,6 +185,8 @@ const (
 	NISTP521ECDHKW = "NISTP521ECDHKW"
 	// X25519ECDHKW key type value.
 	X25519ECDHKW = "X25519ECDHKW"
+	// X448ECDHKW key type value.
+	X448ECDHKW = "X448ECDHKW"
 	// BLS12381G2 BBS+ key type value.
 	BLS12381G2 = "BLS12381G2"
 	// CLCredDef key type value.
@@ -163,6 +209,8 @@ const (
 	ChaCha20Poly1305Type = KeyType(ChaCha20Poly1305)
 	// XChaCha20Poly1305Type key type value.
 	XChaCha20Poly1305Type = KeyType(XChaCha20Poly1305)
+	// AESGCMSIV256Type key type value, a nonce misuse-resistant AES-256-GCM-SIV (RFC 8452) key.
+	AESGCMSIV256Type = KeyType(AESGCMSIV256)
 	// ECDSAP256TypeDER key type value.
 	ECDSAP256TypeDER = KeyType(ECDSAP256DER)
 	// ECDSASecp256k1TypeDER key type value.
@@ -179,14 +227,28 @@ const (
 	ECDSAP521TypeIEEEP1363 = KeyType(ECDSAP521IEEEP1363)
 	// ECDSASecp256k1TypeIEEEP1363 key type value.
 	ECDSASecp256k1TypeIEEEP1363 = KeyType(ECDSASecp256k1IEEEP1363)
+	// BIP340Secp256k1Type key type value, a secp256k1 key of BIP-340 Schnorr signatures with x-only public keys.
+	BIP340Secp256k1Type = KeyType(BIP340Secp256k1)
 	// ED25519Type key type value.
 	ED25519Type = KeyType(ED25519)
 	// RSARS256Type key type value.
 	RSARS256Type = KeyType(RSARS256)
 	// RSAPS256Type key type value.
 	RSAPS256Type = KeyType(RSAPS256)
+	// RSAOAEP256Type key type value.
+	RSAOAEP256Type = KeyType(RSAOAEP256)
 	// HMACSHA256Tag256Type key type value.
 	HMACSHA256Tag256Type = KeyType(HMACSHA256Tag256)
+	// HMACSHA384Tag384Type key type value.
+	HMACSHA384Tag384Type = KeyType(HMACSHA384Tag384)
+	// HMACSHA512Tag512Type key type value.
+	HMACSHA512Tag512Type = KeyType(HMACSHA512Tag512)
+	// AES128KWType key type value.
+	AES128KWType = KeyType(AES128KW)
+	// AES192KWType key type value.
+	AES192KWType = KeyType(AES192KW)
+	// AES256KWType key type value.
+	AES256KWType = KeyType(AES256KW)
 	// NISTP256ECDHKWType key type value.
 	NISTP256ECDHKWType = KeyType(NISTP256ECDHKW)
 	// NISTP384ECDHKWType key type value.
@@ -195,10 +257,39 @@ const (
 	NISTP521ECDHKWType = KeyType(NISTP521ECDHKW)
 	// X25519ECDHKWType key type value.
 	X25519ECDHKWType = KeyType(X25519ECDHKW)
+	// X448ECDHKWType key type value.
+	X448ECDHKWType = KeyType(X448ECDHKW)
 	// BLS12381G2Type BBS+ key type value.
 	BLS12381G2Type = KeyType(BLS12381G2)
-	// CLCredDefType type value.
+	// CLCredDefType type value. The module has no CL (Camenisch-Lysyanskaya) signature primitive: the CL key types
+	// are only read and written as key material, without CL signatures, predicate proofs or revocation.
 	CLCredDefType = KeyType(CLCredDef)
 	// CLMasterSecretType key type value.
 	CLMasterSecretType = KeyType(CLMasterSecret)
 )
+
+// symmetricKeyTypes are the key types of the AEAD, MAC and AES key wrapping keys.
+//
+//nolint:gochecknoglobals
+var symmetricKeyTypes = []KeyType{
+	AES128GCMType, AES256GCMType, AES256GCMNoPrefixType, ChaCha20Poly1305Type, XChaCha20Poly1305Type,
+	AESGCMSIV256Type, HMACSHA256Tag256Type, HMACSHA384Tag384Type, HMACSHA512Tag512Type,
+	AES128KWType, AES192KWType, AES256KWType,
+}
+
+// SymmetricKeyTypes returns the symmetric key types: the key types of the AEAD, MAC and AES key wrapping keys, keys
+// without public key.
+func SymmetricKeyTypes() []KeyType {
+	return append([]KeyType(nil), symmetricKeyTypes...)
+}
+
+// IsSymmetric returns true if kt is one of the SymmetricKeyTypes.
+func IsSymmetric(kt KeyType) bool {
+	for _, t := range symmetricKeyTypes {
+		if t == kt {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,34 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+// PubKeyFormat is an encoding of the public keys exported with PubKeyExporter.
+type PubKeyFormat string
+
+// Public key formats.
+const (
+	// PubKeyFormatJWK is a JSON Web Key (RFC 7517).
+	PubKeyFormatJWK = PubKeyFormat("jwk")
+	// PubKeyFormatSPKIDER is a DER X.509 SubjectPublicKeyInfo, as returned by x509.MarshalPKIXPublicKey.
+	PubKeyFormatSPKIDER = PubKeyFormat("spki-der")
+	// PubKeyFormatSPKIPEM is a PEM "PUBLIC KEY" block of the SubjectPublicKeyInfo.
+	PubKeyFormatSPKIPEM = PubKeyFormat("spki-pem")
+	// PubKeyFormatRaw is the raw public key: the uncompressed point of EC keys, the 32 bytes of Ed25519 and X25519
+	// keys, the 96 bytes of BLS12-381 G2 keys and the PKCS#1 DER of RSA keys.
+	PubKeyFormatRaw = PubKeyFormat("raw")
+	// PubKeyFormatMultibase is the base58btc multibase encoding of the multicodec prefixed public key, as used in
+	// did:key identifiers (eg: "z6Mk..." for Ed25519 keys). EC keys are compressed.
+	PubKeyFormatMultibase = PubKeyFormat("multibase")
+)
+
+// PubKeyExporter is implemented by KeyManager implementations exporting public keys in well known formats, unlike
+// KeyManager.ExportPubKeyBytes returning a key type specific encoding. It is an optional interface: callers should
+// type-assert for it and fall back to ExportPubKeyBytes when it is not implemented.
+type PubKeyExporter interface {
+	// ExportPubKey returns the public key of the key keyID encoded in format.
+	ExportPubKey(keyID string, format PubKeyFormat) ([]byte, error)
+}
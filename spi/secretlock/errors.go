@@ -0,0 +1,13 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package secretlock
+
+import "errors"
+
+// ErrLocked is wrapped by the errors returned by Service.Decrypt when the secret can't be unlocked: the master key or
+// passphrase is wrong, or the ciphertext is corrupted.
+var ErrLocked = errors.New("secret is locked")
@@ -0,0 +1,87 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package fault records the method calls of the testutil mocks and returns the errors injected in them.
+package fault
+
+import "sync"
+
+// Injector records the calls of methods, by method name, and returns their injected errors. The zero Injector
+// injects no errors, it is safe for concurrent use.
+type Injector struct {
+	mu     sync.Mutex
+	always map[string]error
+	next   map[string][]error
+	calls  map[string]int
+}
+
+// FailOn makes the calls of method fail with err, or no longer fail if err is nil. The errors queued by FailNext
+// are returned first.
+func (i *Injector) FailOn(method string, err error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.always == nil {
+		i.always = map[string]error{}
+	}
+
+	if err == nil {
+		delete(i.always, method)
+
+		return
+	}
+
+	i.always[method] = err
+}
+
+// FailNext queues errs as the errors of the next calls of method, one call per error. A nil error lets its call
+// through.
+func (i *Injector) FailNext(method string, errs ...error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.next == nil {
+		i.next = map[string][]error{}
+	}
+
+	i.next[method] = append(i.next[method], errs...)
+}
+
+// Call records a call of method and returns the error injected in it, nil if the call goes through.
+func (i *Injector) Call(method string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.calls == nil {
+		i.calls = map[string]int{}
+	}
+
+	i.calls[method]++
+
+	if errs := i.next[method]; len(errs) > 0 {
+		i.next[method] = errs[1:]
+
+		return errs[0]
+	}
+
+	return i.always[method]
+}
+
+// Calls returns the number of calls of method, failed calls included.
+func (i *Injector) Calls(method string) int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.calls[method]
+}
+
+// Reset clears the injected errors and the recorded calls.
+func (i *Injector) Reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.always, i.next, i.calls = nil, nil, nil
+}
@@ -0,0 +1,43 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fault
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjector(t *testing.T) {
+	errAlways, errNext := errors.New("always"), errors.New("next")
+
+	var i Injector
+
+	require.NoError(t, i.Call("m"))
+
+	i.FailOn("m", errAlways)
+	i.FailNext("m", errNext, nil)
+
+	require.Equal(t, errNext, i.Call("m"))
+	require.NoError(t, i.Call("m"))
+	require.Equal(t, errAlways, i.Call("m"))
+	require.NoError(t, i.Call("other"))
+
+	i.FailOn("m", nil)
+
+	require.NoError(t, i.Call("m"))
+	require.Equal(t, 5, i.Calls("m"))
+	require.Equal(t, 1, i.Calls("other"))
+
+	i.FailOn("m", errAlways)
+	i.FailNext("m", errNext)
+	i.Reset()
+
+	require.Zero(t, i.Calls("m"))
+	require.NoError(t, i.Call("m"))
+}
@@ -0,0 +1,333 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package mockcrypto provides a scriptable Crypto for tests. Unlike the mock/crypto Crypto, which returns the values
+// set in its fields, it forwards the calls to a real Crypto (tinkcrypto by default) so it works with the key handles
+// of a KeyManager (see testutil/mockkms), and fails the calls of the methods the errors are injected in:
+//
+//	c, err := mockcrypto.New()
+//	// the next Sign call fails, the following ones sign.
+//	c.FailNext(mockcrypto.MethodSign, errTimeout)
+//	// every Verify call fails.
+//	c.FailOn(mockcrypto.MethodVerify, errInvalidSignature)
+//
+// The Crypto also implements the optional interfaces of spi/crypto (PublicKeyVerifier, DHComputer, etc.), their calls
+// fail with errors.ErrUnsupported if the forwarded Crypto doesn't implement them. The Crypto is safe for concurrent
+// use if the forwarded Crypto is.
+package mockcrypto
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/testutil/internal/fault"
+)
+
+// Method is a method of the Crypto, to inject errors in its calls.
+type Method string
+
+// The methods of the Crypto.
+const (
+	MethodEncrypt                  Method = "Encrypt"
+	MethodDecrypt                  Method = "Decrypt"
+	MethodSign                     Method = "Sign"
+	MethodVerify                   Method = "Verify"
+	MethodComputeMAC               Method = "ComputeMAC"
+	MethodVerifyMAC                Method = "VerifyMAC"
+	MethodWrapKey                  Method = "WrapKey"
+	MethodUnwrapKey                Method = "UnwrapKey"
+	MethodSignMulti                Method = "SignMulti"
+	MethodVerifyMulti              Method = "VerifyMulti"
+	MethodVerifyProof              Method = "VerifyProof"
+	MethodDeriveProof              Method = "DeriveProof"
+	MethodWrapKeyWithKEK           Method = "WrapKeyWithKEK"
+	MethodDeriveDirectKey          Method = "DeriveDirectKey"
+	MethodEncryptWithNonce         Method = "EncryptWithNonce"
+	MethodDecryptDetached          Method = "DecryptDetached"
+	MethodDeriveKey                Method = "DeriveKey"
+	MethodVerifyWithPublicKey      Method = "VerifyWithPublicKey"
+	MethodVerifyMultiWithPublicKey Method = "VerifyMultiWithPublicKey"
+	MethodComputeDH                Method = "ComputeDH"
+)
+
+var (
+	_ cryptoapi.Crypto             = (*Crypto)(nil)
+	_ cryptoapi.KEKWrapper         = (*Crypto)(nil)
+	_ cryptoapi.DirectKeyAgreement = (*Crypto)(nil)
+	_ cryptoapi.DetachedNonceAEAD  = (*Crypto)(nil)
+	_ cryptoapi.KeyDeriver         = (*Crypto)(nil)
+	_ cryptoapi.PublicKeyVerifier  = (*Crypto)(nil)
+	_ cryptoapi.DHComputer         = (*Crypto)(nil)
+)
+
+// Crypto is a Crypto forwarding its calls to another Crypto, unless an error is injected in them.
+type Crypto struct {
+	crypto cryptoapi.Crypto
+	faults fault.Injector
+}
+
+// Opt is an option of the Crypto.
+type Opt func(*Crypto)
+
+// WithCrypto sets the Crypto the calls are forwarded to, a tinkcrypto Crypto WithComputeDH by default.
+func WithCrypto(c cryptoapi.Crypto) Opt {
+	return func(m *Crypto) {
+		m.crypto = c
+	}
+}
+
+// New returns a Crypto without injected errors.
+func New(opts ...Opt) (*Crypto, error) {
+	c := &Crypto{}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.crypto == nil {
+		tc, err := tinkcrypto.New(tinkcrypto.WithComputeDH())
+		if err != nil {
+			return nil, fmt.Errorf("new mock crypto: %w", err)
+		}
+
+		c.crypto = tc
+	}
+
+	return c, nil
+}
+
+// FailOn makes the calls of m fail with err, or no longer fail if err is nil. The errors queued by FailNext are
+// returned first.
+func (c *Crypto) FailOn(m Method, err error) {
+	c.faults.FailOn(string(m), err)
+}
+
+// FailNext queues errs as the errors of the next calls of m, one call per error. A nil error lets its call through.
+func (c *Crypto) FailNext(m Method, errs ...error) {
+	c.faults.FailNext(string(m), errs...)
+}
+
+// Calls returns the number of calls of m, failed calls included.
+func (c *Crypto) Calls(m Method) int {
+	return c.faults.Calls(string(m))
+}
+
+// Reset clears the injected errors and the recorded calls.
+func (c *Crypto) Reset() {
+	c.faults.Reset()
+}
+
+// Encrypt encrypts msg with the forwarded Crypto.
+func (c *Crypto) Encrypt(msg, aad []byte, kh interface{}) ([]byte, []byte, error) {
+	if err := c.faults.Call(string(MethodEncrypt)); err != nil {
+		return nil, nil, err
+	}
+
+	return c.crypto.Encrypt(msg, aad, kh)
+}
+
+// Decrypt decrypts cipher with the forwarded Crypto.
+func (c *Crypto) Decrypt(cipher, aad, nonce []byte, kh interface{}) ([]byte, error) {
+	if err := c.faults.Call(string(MethodDecrypt)); err != nil {
+		return nil, err
+	}
+
+	return c.crypto.Decrypt(cipher, aad, nonce, kh)
+}
+
+// Sign signs msg with the forwarded Crypto.
+func (c *Crypto) Sign(msg []byte, kh interface{}) ([]byte, error) {
+	if err := c.faults.Call(string(MethodSign)); err != nil {
+		return nil, err
+	}
+
+	return c.crypto.Sign(msg, kh)
+}
+
+// Verify verifies signature with the forwarded Crypto.
+func (c *Crypto) Verify(signature, msg []byte, kh interface{}) error {
+	if err := c.faults.Call(string(MethodVerify)); err != nil {
+		return err
+	}
+
+	return c.crypto.Verify(signature, msg, kh)
+}
+
+// ComputeMAC computes the MAC of data with the forwarded Crypto.
+func (c *Crypto) ComputeMAC(data []byte, kh interface{}) ([]byte, error) {
+	if err := c.faults.Call(string(MethodComputeMAC)); err != nil {
+		return nil, err
+	}
+
+	return c.crypto.ComputeMAC(data, kh)
+}
+
+// VerifyMAC verifies mac with the forwarded Crypto.
+func (c *Crypto) VerifyMAC(mac, data []byte, kh interface{}) error {
+	if err := c.faults.Call(string(MethodVerifyMAC)); err != nil {
+		return err
+	}
+
+	return c.crypto.VerifyMAC(mac, data, kh)
+}
+
+// WrapKey wraps cek with the forwarded Crypto.
+func (c *Crypto) WrapKey(cek, apu, apv []byte, recPubKey *cryptoapi.PublicKey,
+	opts ...cryptoapi.WrapKeyOpts) (*cryptoapi.RecipientWrappedKey, error) {
+	if err := c.faults.Call(string(MethodWrapKey)); err != nil {
+		return nil, err
+	}
+
+	return c.crypto.WrapKey(cek, apu, apv, recPubKey, opts...)
+}
+
+// UnwrapKey unwraps recWK with the forwarded Crypto.
+func (c *Crypto) UnwrapKey(recWK *cryptoapi.RecipientWrappedKey, kh interface{},
+	opts ...cryptoapi.WrapKeyOpts) ([]byte, error) {
+	if err := c.faults.Call(string(MethodUnwrapKey)); err != nil {
+		return nil, err
+	}
+
+	return c.crypto.UnwrapKey(recWK, kh, opts...)
+}
+
+// SignMulti signs messages with the forwarded Crypto.
+func (c *Crypto) SignMulti(messages [][]byte, kh interface{}) ([]byte, error) {
+	if err := c.faults.Call(string(MethodSignMulti)); err != nil {
+		return nil, err
+	}
+
+	return c.crypto.SignMulti(messages, kh)
+}
+
+// VerifyMulti verifies signature with the forwarded Crypto.
+func (c *Crypto) VerifyMulti(messages [][]byte, signature []byte, kh interface{}) error {
+	if err := c.faults.Call(string(MethodVerifyMulti)); err != nil {
+		return err
+	}
+
+	return c.crypto.VerifyMulti(messages, signature, kh)
+}
+
+// VerifyProof verifies proof with the forwarded Crypto.
+func (c *Crypto) VerifyProof(revealedMessages [][]byte, proof, nonce []byte, kh interface{}) error {
+	if err := c.faults.Call(string(MethodVerifyProof)); err != nil {
+		return err
+	}
+
+	return c.crypto.VerifyProof(revealedMessages, proof, nonce, kh)
+}
+
+// DeriveProof derives a signature proof with the forwarded Crypto.
+func (c *Crypto) DeriveProof(messages [][]byte, bbsSignature, nonce []byte, revealedIndexes []int,
+	kh interface{}) ([]byte, error) {
+	if err := c.faults.Call(string(MethodDeriveProof)); err != nil {
+		return nil, err
+	}
+
+	return c.crypto.DeriveProof(messages, bbsSignature, nonce, revealedIndexes, kh)
+}
+
+// forwarded returns the forwarded Crypto as the optional interface T of the method m, or the error injected in the
+// call of m.
+func forwarded[T any](c *Crypto, m Method) (T, error) {
+	var zero T
+
+	if err := c.faults.Call(string(m)); err != nil {
+		return zero, err
+	}
+
+	t, ok := c.crypto.(T)
+	if !ok {
+		return zero, fmt.Errorf("%s: %w", m, errors.ErrUnsupported)
+	}
+
+	return t, nil
+}
+
+// WrapKeyWithKEK wraps key with the forwarded Crypto.
+func (c *Crypto) WrapKeyWithKEK(key []byte, kek interface{}) (*cryptoapi.RecipientWrappedKey, error) {
+	w, err := forwarded[cryptoapi.KEKWrapper](c, MethodWrapKeyWithKEK)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.WrapKeyWithKEK(key, kek)
+}
+
+// DeriveDirectKey derives a CEK with the forwarded Crypto.
+func (c *Crypto) DeriveDirectKey(enc string, apu, apv []byte,
+	recPubKey *cryptoapi.PublicKey) ([]byte, *cryptoapi.RecipientWrappedKey, error) {
+	a, err := forwarded[cryptoapi.DirectKeyAgreement](c, MethodDeriveDirectKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return a.DeriveDirectKey(enc, apu, apv, recPubKey)
+}
+
+// EncryptWithNonce encrypts msg with nonce with the forwarded Crypto.
+func (c *Crypto) EncryptWithNonce(msg, aad, nonce []byte, kh interface{},
+	opts ...cryptoapi.NonceOpts) ([]byte, error) {
+	a, err := forwarded[cryptoapi.DetachedNonceAEAD](c, MethodEncryptWithNonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.EncryptWithNonce(msg, aad, nonce, kh, opts...)
+}
+
+// DecryptDetached decrypts cipher with nonce with the forwarded Crypto.
+func (c *Crypto) DecryptDetached(cipher, aad, nonce []byte, kh interface{}) ([]byte, error) {
+	a, err := forwarded[cryptoapi.DetachedNonceAEAD](c, MethodDecryptDetached)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.DecryptDetached(cipher, aad, nonce, kh)
+}
+
+// DeriveKey derives key material with the forwarded Crypto.
+func (c *Crypto) DeriveKey(kh interface{}, salt, info []byte, length int) ([]byte, error) {
+	d, err := forwarded[cryptoapi.KeyDeriver](c, MethodDeriveKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.DeriveKey(kh, salt, info, length)
+}
+
+// VerifyWithPublicKey verifies signature with pubKey with the forwarded Crypto.
+func (c *Crypto) VerifyWithPublicKey(signature, msg []byte, pubKey *cryptoapi.PublicKey) error {
+	v, err := forwarded[cryptoapi.PublicKeyVerifier](c, MethodVerifyWithPublicKey)
+	if err != nil {
+		return err
+	}
+
+	return v.VerifyWithPublicKey(signature, msg, pubKey)
+}
+
+// VerifyMultiWithPublicKey verifies the BBS+ signature with pubKey with the forwarded Crypto.
+func (c *Crypto) VerifyMultiWithPublicKey(messages [][]byte, signature []byte, pubKey *cryptoapi.PublicKey) error {
+	v, err := forwarded[cryptoapi.PublicKeyVerifier](c, MethodVerifyMultiWithPublicKey)
+	if err != nil {
+		return err
+	}
+
+	return v.VerifyMultiWithPublicKey(messages, signature, pubKey)
+}
+
+// ComputeDH computes a shared secret with pubKey with the forwarded Crypto.
+func (c *Crypto) ComputeDH(kh interface{}, pubKey *cryptoapi.PublicKey) ([]byte, error) {
+	dh, err := forwarded[cryptoapi.DHComputer](c, MethodComputeDH)
+	if err != nil {
+		return nil, err
+	}
+
+	return dh.ComputeDH(kh, pubKey)
+}
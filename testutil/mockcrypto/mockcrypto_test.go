@@ -0,0 +1,160 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mockcrypto_test
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	mockcryptoapi "github.com/trustbloc/kms-go/mock/crypto"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockcrypto"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func TestCrypto(t *testing.T) {
+	km, err := mockkms.New()
+	require.NoError(t, err)
+
+	c, err := mockcrypto.New()
+	require.NoError(t, err)
+
+	msg, aad := []byte("msg"), []byte("aad")
+
+	keyID, kh, err := km.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	sig, err := c.Sign(msg, kh)
+	require.NoError(t, err)
+
+	pubKey, _, err := km.ExportPubKeyBytes(keyID)
+	require.NoError(t, err)
+
+	require.NoError(t, c.VerifyWithPublicKey(sig, msg, &cryptoapi.PublicKey{Type: "OKP", Curve: "Ed25519", X: pubKey}))
+	require.True(t, ed25519.Verify(pubKey, msg, sig))
+
+	_, aeadKH, err := km.Create(kmsapi.AES256GCMType)
+	require.NoError(t, err)
+
+	ct, nonce, err := c.Encrypt(msg, aad, aeadKH)
+	require.NoError(t, err)
+
+	pt, err := c.Decrypt(ct, aad, nonce, aeadKH)
+	require.NoError(t, err)
+	require.Equal(t, msg, pt)
+
+	ct, err = c.EncryptWithNonce(msg, aad, nonce, aeadKH)
+	require.NoError(t, err)
+
+	pt, err = c.DecryptDetached(ct, aad, nonce, aeadKH)
+	require.NoError(t, err)
+	require.Equal(t, msg, pt)
+
+	derived, err := c.DeriveKey(aeadKH, []byte("salt"), []byte("info"), 32)
+	require.NoError(t, err)
+	require.Len(t, derived, 32)
+
+	_, macKH, err := km.Create(kmsapi.HMACSHA256Tag256Type)
+	require.NoError(t, err)
+
+	mac, err := c.ComputeMAC(msg, macKH)
+	require.NoError(t, err)
+	require.NoError(t, c.VerifyMAC(mac, msg, macKH))
+
+	require.Equal(t, 1, c.Calls(mockcrypto.MethodSign))
+	require.Equal(t, 1, c.Calls(mockcrypto.MethodVerifyWithPublicKey))
+	require.Zero(t, c.Calls(mockcrypto.MethodVerify))
+}
+
+func TestCrypto_FailOn(t *testing.T) {
+	km, err := mockkms.New()
+	require.NoError(t, err)
+
+	c, err := mockcrypto.New()
+	require.NoError(t, err)
+
+	_, kh, err := km.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	errInjected := errors.New("injected")
+
+	c.FailNext(mockcrypto.MethodSign, errInjected, nil)
+
+	_, err = c.Sign([]byte("msg"), kh)
+	require.ErrorIs(t, err, errInjected)
+
+	_, err = c.Sign([]byte("msg"), kh)
+	require.NoError(t, err)
+
+	for m, call := range map[mockcrypto.Method]func() error{
+		mockcrypto.MethodEncrypt:                  func() error { _, _, e := c.Encrypt(nil, nil, nil); return e },
+		mockcrypto.MethodDecrypt:                  func() error { _, e := c.Decrypt(nil, nil, nil, nil); return e },
+		mockcrypto.MethodSign:                     func() error { _, e := c.Sign(nil, nil); return e },
+		mockcrypto.MethodVerify:                   func() error { return c.Verify(nil, nil, nil) },
+		mockcrypto.MethodComputeMAC:               func() error { _, e := c.ComputeMAC(nil, nil); return e },
+		mockcrypto.MethodVerifyMAC:                func() error { return c.VerifyMAC(nil, nil, nil) },
+		mockcrypto.MethodWrapKey:                  func() error { _, e := c.WrapKey(nil, nil, nil, nil); return e },
+		mockcrypto.MethodUnwrapKey:                func() error { _, e := c.UnwrapKey(nil, nil); return e },
+		mockcrypto.MethodSignMulti:                func() error { _, e := c.SignMulti(nil, nil); return e },
+		mockcrypto.MethodVerifyMulti:              func() error { return c.VerifyMulti(nil, nil, nil) },
+		mockcrypto.MethodVerifyProof:              func() error { return c.VerifyProof(nil, nil, nil, nil) },
+		mockcrypto.MethodDeriveProof:              func() error { _, e := c.DeriveProof(nil, nil, nil, nil, nil); return e },
+		mockcrypto.MethodWrapKeyWithKEK:           func() error { _, e := c.WrapKeyWithKEK(nil, nil); return e },
+		mockcrypto.MethodDeriveDirectKey:          func() error { _, _, e := c.DeriveDirectKey("", nil, nil, nil); return e },
+		mockcrypto.MethodEncryptWithNonce:         func() error { _, e := c.EncryptWithNonce(nil, nil, nil, nil); return e },
+		mockcrypto.MethodDecryptDetached:          func() error { _, e := c.DecryptDetached(nil, nil, nil, nil); return e },
+		mockcrypto.MethodDeriveKey:                func() error { _, e := c.DeriveKey(nil, nil, nil, 0); return e },
+		mockcrypto.MethodVerifyWithPublicKey:      func() error { return c.VerifyWithPublicKey(nil, nil, nil) },
+		mockcrypto.MethodVerifyMultiWithPublicKey: func() error { return c.VerifyMultiWithPublicKey(nil, nil, nil) },
+		mockcrypto.MethodComputeDH:                func() error { _, e := c.ComputeDH(nil, nil); return e },
+	} {
+		c.FailOn(m, errInjected)
+		require.ErrorIs(t, call(), errInjected, m)
+
+		c.FailOn(m, nil)
+		require.NotErrorIs(t, call(), errInjected, m)
+	}
+
+	c.Reset()
+
+	require.Zero(t, c.Calls(mockcrypto.MethodSign))
+}
+
+func TestCrypto_WithCrypto(t *testing.T) {
+	c, err := mockcrypto.New(mockcrypto.WithCrypto(&mockcryptoapi.Crypto{SignValue: []byte("sig")}))
+	require.NoError(t, err)
+
+	sig, err := c.Sign([]byte("msg"), nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("sig"), sig)
+
+	_, err = c.ComputeDH(nil, nil)
+	require.ErrorIs(t, err, errors.ErrUnsupported)
+	require.EqualError(t, err, "ComputeDH: unsupported operation")
+
+	_, err = c.WrapKeyWithKEK(nil, nil)
+	require.ErrorIs(t, err, errors.ErrUnsupported)
+
+	_, _, err = c.DeriveDirectKey("", nil, nil, nil)
+	require.ErrorIs(t, err, errors.ErrUnsupported)
+
+	_, err = c.EncryptWithNonce(nil, nil, nil, nil)
+	require.ErrorIs(t, err, errors.ErrUnsupported)
+
+	_, err = c.DecryptDetached(nil, nil, nil, nil)
+	require.ErrorIs(t, err, errors.ErrUnsupported)
+
+	_, err = c.DeriveKey(nil, nil, nil, 0)
+	require.ErrorIs(t, err, errors.ErrUnsupported)
+
+	require.ErrorIs(t, c.VerifyWithPublicKey(nil, nil, nil), errors.ErrUnsupported)
+	require.ErrorIs(t, c.VerifyMultiWithPublicKey(nil, nil, nil), errors.ErrUnsupported)
+}
@@ -0,0 +1,228 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package mockkms provides an in-memory KeyManager for tests. Unlike the mock/kms KeyManager, which returns the values
+// set in its fields, it is a LocalKMS storing its keys, unencrypted, in an in-memory Store: its key handles work with
+// the Crypto implementations (see testutil/mockcrypto). Errors can be injected in the calls of its KeyManager methods:
+//
+//	km, err := mockkms.New()
+//	// the next Create call fails, the following ones create keys.
+//	km.FailNext(mockkms.MethodCreate, errTimeout)
+//	// every Get call fails.
+//	km.FailOn(mockkms.MethodGet, kms.ErrKeyNotFound)
+//
+// NewForTest creates a KeyManager failing the test if it can't be created, in place of per-package KMS provider
+// helpers:
+//
+//	km := mockkms.NewForTest(t)
+package mockkms
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/trustbloc/kms-go/kms/localkms"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/spi/secretlock"
+	"github.com/trustbloc/kms-go/testutil/internal/fault"
+)
+
+// primaryKeyURI is the primary key URI of the LocalKMS, the noop secret lock ignores it.
+const primaryKeyURI = "local-lock://mockkms/primary/key/"
+
+// Method is a KeyManager method of the KeyManager, to inject errors in its calls.
+type Method string
+
+// The KeyManager methods of the KeyManager.
+const (
+	MethodCreate                     Method = "Create"
+	MethodGet                        Method = "Get"
+	MethodRotate                     Method = "Rotate"
+	MethodExportPubKeyBytes          Method = "ExportPubKeyBytes"
+	MethodCreateAndExportPubKeyBytes Method = "CreateAndExportPubKeyBytes"
+	MethodPubKeyBytesToHandle        Method = "PubKeyBytesToHandle"
+	MethodImportPrivateKey           Method = "ImportPrivateKey"
+)
+
+var _ kmsapi.KeyManager = (*KeyManager)(nil)
+
+// KeyManager is an in-memory LocalKMS failing the calls of its KeyManager methods the errors are injected in. The
+// other LocalKMS methods are called without injected errors.
+type KeyManager struct {
+	*localkms.LocalKMS
+	store  *Store
+	faults fault.Injector
+}
+
+type options struct {
+	store      kmsapi.Store
+	secretLock secretlock.Service
+	kmsOpts    []localkms.Opt
+}
+
+// Opt is an option of the KeyManager.
+type Opt func(*options)
+
+// WithSecretLock sets the secret lock encrypting the stored keys, a noop secret lock by default.
+func WithSecretLock(s secretlock.Service) Opt {
+	return func(o *options) {
+		o.secretLock = s
+	}
+}
+
+// WithStore sets the Store of the keys, an empty in-memory Store by default. It runs the KeyManager on a Store under
+// test, or on the Store of another KeyManager to read its keys with another secret lock.
+func WithStore(store kmsapi.Store) Opt {
+	return func(o *options) {
+		o.store = store
+	}
+}
+
+// WithLocalKMSOptions sets the options of the LocalKMS, eg: localkms.WithKeyIDGenerator for deterministic key IDs.
+func WithLocalKMSOptions(opts ...localkms.Opt) Opt {
+	return func(o *options) {
+		o.kmsOpts = append(o.kmsOpts, opts...)
+	}
+}
+
+// New returns a KeyManager with an empty Store and without injected errors.
+func New(opts ...Opt) (*KeyManager, error) {
+	o := &options{store: NewStore(), secretLock: &noop.NoLock{}}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	l, err := localkms.New(primaryKeyURI, &provider{store: o.store, secretLock: o.secretLock}, o.kmsOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("new mock kms: %w", err)
+	}
+
+	k := &KeyManager{LocalKMS: l}
+
+	if s, ok := o.store.(*Store); ok {
+		k.store = s
+	}
+
+	return k, nil
+}
+
+// NewForTest returns a KeyManager created with opts, it fails t if the KeyManager can't be created.
+func NewForTest(t testing.TB, opts ...Opt) *KeyManager {
+	t.Helper()
+
+	k, err := New(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return k
+}
+
+// Store returns the Store of the keys of k, nil if k was created WithStore with another Store implementation.
+func (k *KeyManager) Store() *Store {
+	return k.store
+}
+
+// FailOn makes the calls of m fail with err, or no longer fail if err is nil. The errors queued by FailNext are
+// returned first.
+func (k *KeyManager) FailOn(m Method, err error) {
+	k.faults.FailOn(string(m), err)
+}
+
+// FailNext queues errs as the errors of the next calls of m, one call per error. A nil error lets its call through.
+func (k *KeyManager) FailNext(m Method, errs ...error) {
+	k.faults.FailNext(string(m), errs...)
+}
+
+// Calls returns the number of calls of m, failed calls included.
+func (k *KeyManager) Calls(m Method) int {
+	return k.faults.Calls(string(m))
+}
+
+// Reset clears the injected errors and the recorded calls, the stored keys are kept.
+func (k *KeyManager) Reset() {
+	k.faults.Reset()
+}
+
+// Create creates a key of type kt.
+func (k *KeyManager) Create(kt kmsapi.KeyType, opts ...kmsapi.KeyOpts) (string, interface{}, error) {
+	if err := k.faults.Call(string(MethodCreate)); err != nil {
+		return "", nil, err
+	}
+
+	return k.LocalKMS.Create(kt, opts...)
+}
+
+// Get returns the key handle of keyID.
+func (k *KeyManager) Get(keyID string) (interface{}, error) {
+	if err := k.faults.Call(string(MethodGet)); err != nil {
+		return nil, err
+	}
+
+	return k.LocalKMS.Get(keyID)
+}
+
+// Rotate rotates the key of keyID to a new key of type kt.
+func (k *KeyManager) Rotate(kt kmsapi.KeyType, keyID string, opts ...kmsapi.KeyOpts) (string, interface{}, error) {
+	if err := k.faults.Call(string(MethodRotate)); err != nil {
+		return "", nil, err
+	}
+
+	return k.LocalKMS.Rotate(kt, keyID, opts...)
+}
+
+// ExportPubKeyBytes exports the public key of keyID.
+func (k *KeyManager) ExportPubKeyBytes(keyID string) ([]byte, kmsapi.KeyType, error) {
+	if err := k.faults.Call(string(MethodExportPubKeyBytes)); err != nil {
+		return nil, "", err
+	}
+
+	return k.LocalKMS.ExportPubKeyBytes(keyID)
+}
+
+// CreateAndExportPubKeyBytes creates a key of type kt and exports its public key.
+func (k *KeyManager) CreateAndExportPubKeyBytes(kt kmsapi.KeyType, opts ...kmsapi.KeyOpts) (string, []byte, error) {
+	if err := k.faults.Call(string(MethodCreateAndExportPubKeyBytes)); err != nil {
+		return "", nil, err
+	}
+
+	return k.LocalKMS.CreateAndExportPubKeyBytes(kt, opts...)
+}
+
+// PubKeyBytesToHandle returns the key handle of the public key pubKey of type kt.
+func (k *KeyManager) PubKeyBytesToHandle(pubKey []byte, kt kmsapi.KeyType,
+	opts ...kmsapi.KeyOpts) (interface{}, error) {
+	if err := k.faults.Call(string(MethodPubKeyBytesToHandle)); err != nil {
+		return nil, err
+	}
+
+	return k.LocalKMS.PubKeyBytesToHandle(pubKey, kt, opts...)
+}
+
+// ImportPrivateKey imports the private key privKey as a key of type kt.
+func (k *KeyManager) ImportPrivateKey(privKey interface{}, kt kmsapi.KeyType,
+	opts ...kmsapi.PrivateKeyOpts) (string, interface{}, error) {
+	if err := k.faults.Call(string(MethodImportPrivateKey)); err != nil {
+		return "", nil, err
+	}
+
+	return k.LocalKMS.ImportPrivateKey(privKey, kt, opts...)
+}
+
+type provider struct {
+	store      kmsapi.Store
+	secretLock secretlock.Service
+}
+
+func (p *provider) StorageProvider() kmsapi.Store {
+	return p.store
+}
+
+func (p *provider) SecretLock() secretlock.Service {
+	return p.secretLock
+}
@@ -0,0 +1,143 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mockkms_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/kms/localkms"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func TestKeyManager(t *testing.T) {
+	km, err := mockkms.New(mockkms.WithSecretLock(&noop.NoLock{}),
+		mockkms.WithLocalKMSOptions(localkms.WithKeyHandleCache(0, 0)))
+	require.NoError(t, err)
+
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	keyID, kh, err := km.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	sig, err := c.Sign([]byte("msg"), kh)
+	require.NoError(t, err)
+
+	pubKey, kt, err := km.ExportPubKeyBytes(keyID)
+	require.NoError(t, err)
+	require.Equal(t, kmsapi.ED25519Type, kt)
+
+	pubKH, err := km.PubKeyBytesToHandle(pubKey, kt)
+	require.NoError(t, err)
+	require.NoError(t, c.Verify(sig, []byte("msg"), pubKH))
+
+	_, err = km.Get(keyID)
+	require.NoError(t, err)
+
+	keyIDs, err := km.Store().KeyIDs()
+	require.NoError(t, err)
+	require.Equal(t, []string{keyID}, keyIDs)
+
+	rotatedKeyID, _, err := km.Rotate(kmsapi.ED25519Type, keyID)
+	require.NoError(t, err)
+
+	_, _, err = km.CreateAndExportPubKeyBytes(kmsapi.ECDSAP256TypeIEEEP1363)
+	require.NoError(t, err)
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	_, _, err = km.ImportPrivateKey(privKey, kmsapi.ECDSAP256TypeIEEEP1363)
+	require.NoError(t, err)
+
+	require.NoError(t, km.Delete(rotatedKeyID))
+
+	for _, m := range []mockkms.Method{
+		mockkms.MethodCreate, mockkms.MethodGet, mockkms.MethodRotate, mockkms.MethodExportPubKeyBytes,
+		mockkms.MethodCreateAndExportPubKeyBytes, mockkms.MethodPubKeyBytesToHandle, mockkms.MethodImportPrivateKey,
+	} {
+		require.Equal(t, 1, km.Calls(m), m)
+	}
+}
+
+func TestKeyManager_FailOn(t *testing.T) {
+	km, err := mockkms.New()
+	require.NoError(t, err)
+
+	errInjected := errors.New("injected")
+
+	keyID, _, err := km.Create(kmsapi.AES256GCMType)
+	require.NoError(t, err)
+
+	km.FailNext(mockkms.MethodCreate, errInjected, nil)
+	km.FailOn(mockkms.MethodGet, errInjected)
+
+	_, _, err = km.Create(kmsapi.AES256GCMType)
+	require.ErrorIs(t, err, errInjected)
+
+	_, _, err = km.Create(kmsapi.AES256GCMType)
+	require.NoError(t, err)
+
+	_, err = km.Get(keyID)
+	require.ErrorIs(t, err, errInjected)
+
+	for _, m := range []mockkms.Method{
+		mockkms.MethodRotate, mockkms.MethodExportPubKeyBytes, mockkms.MethodCreateAndExportPubKeyBytes,
+		mockkms.MethodPubKeyBytesToHandle, mockkms.MethodImportPrivateKey,
+	} {
+		km.FailOn(m, errInjected)
+	}
+
+	_, _, err = km.Rotate(kmsapi.AES256GCMType, keyID)
+	require.ErrorIs(t, err, errInjected)
+
+	_, _, err = km.ExportPubKeyBytes(keyID)
+	require.ErrorIs(t, err, errInjected)
+
+	_, _, err = km.CreateAndExportPubKeyBytes(kmsapi.ED25519Type)
+	require.ErrorIs(t, err, errInjected)
+
+	_, err = km.PubKeyBytesToHandle([]byte("key"), kmsapi.ED25519Type)
+	require.ErrorIs(t, err, errInjected)
+
+	_, _, err = km.ImportPrivateKey(nil, kmsapi.ED25519Type)
+	require.ErrorIs(t, err, errInjected)
+
+	require.Equal(t, 3, km.Calls(mockkms.MethodCreate))
+
+	km.Reset()
+
+	require.Zero(t, km.Calls(mockkms.MethodCreate))
+
+	_, err = km.Get(keyID)
+	require.NoError(t, err)
+}
+
+func TestNewForTest(t *testing.T) {
+	km := mockkms.NewForTest(t)
+
+	keyID, _, err := km.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	_, err = mockkms.NewForTest(t, mockkms.WithStore(km.Store())).Get(keyID)
+	require.NoError(t, err)
+
+	wrapped := mockkms.NewForTest(t, mockkms.WithStore(struct{ *mockkms.Store }{km.Store()}))
+	require.Nil(t, wrapped.Store())
+
+	_, err = wrapped.Get(keyID)
+	require.NoError(t, err)
+}
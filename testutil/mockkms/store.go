@@ -0,0 +1,117 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mockkms
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/kms-go/kms"
+)
+
+var (
+	_ kmsapi.Store             = (*Store)(nil)
+	_ kmsapi.StoreWithMetadata = (*Store)(nil)
+	_ kmsapi.StoreCreator      = (*Store)(nil)
+	_ kmsapi.StoreLister       = (*Store)(nil)
+)
+
+type record struct {
+	key      []byte
+	metadata map[string]any
+}
+
+// Store is an in-memory KMS store, safe for concurrent use. It stores copies of the keys and metadata.
+type Store struct {
+	mu      sync.RWMutex
+	records map[string]record
+}
+
+// NewStore returns an empty Store.
+func NewStore() *Store {
+	return &Store{records: map[string]record{}}
+}
+
+// Put stores key under keysetID, replacing the stored key if any.
+func (s *Store) Put(keysetID string, key []byte) error {
+	return s.PutWithMetadata(keysetID, key, nil)
+}
+
+// PutWithMetadata stores key and metadata under keysetID, replacing the stored key if any.
+func (s *Store) PutWithMetadata(keysetID string, key []byte, metadata map[string]any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[keysetID] = record{key: slices.Clone(key), metadata: maps.Clone(metadata)}
+
+	return nil
+}
+
+// Create stores key and metadata under keysetID, only if no key is stored under it. Otherwise, the returned error
+// wraps kms.ErrKeyExists.
+func (s *Store) Create(keysetID string, key []byte, metadata map[string]any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.records[keysetID]; ok {
+		return fmt.Errorf("create key '%s': %w", keysetID, kms.ErrKeyExists)
+	}
+
+	s.records[keysetID] = record{key: slices.Clone(key), metadata: maps.Clone(metadata)}
+
+	return nil
+}
+
+// Get returns the key stored under keysetID, or an error wrapping kms.ErrKeyNotFound.
+func (s *Store) Get(keysetID string) ([]byte, error) {
+	key, _, err := s.GetWithMetadata(keysetID)
+
+	return key, err
+}
+
+// GetWithMetadata returns the key and metadata stored under keysetID, or an error wrapping kms.ErrKeyNotFound.
+func (s *Store) GetWithMetadata(keysetID string) ([]byte, map[string]any, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.records[keysetID]
+	if !ok {
+		return nil, nil, fmt.Errorf("get key '%s': %w", keysetID, kms.ErrKeyNotFound)
+	}
+
+	return slices.Clone(r.key), maps.Clone(r.metadata), nil
+}
+
+// Delete deletes the key stored under keysetID, if any.
+func (s *Store) Delete(keysetID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, keysetID)
+
+	return nil
+}
+
+// KeyIDs returns the sorted IDs of all the keys in the store.
+func (s *Store) KeyIDs() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keyIDs := make([]string, 0, len(s.records))
+
+	for keyID := range s.records {
+		keyIDs = append(keyIDs, keyID)
+	}
+
+	slices.Sort(keyIDs)
+
+	return keyIDs, nil
+}
@@ -0,0 +1,50 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mockkms_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+)
+
+func TestStore(t *testing.T) {
+	s := mockkms.NewStore()
+
+	_, err := s.Get("k1")
+	require.ErrorIs(t, err, kms.ErrKeyNotFound)
+
+	key := []byte("key")
+
+	require.NoError(t, s.Put("k2", key))
+	require.NoError(t, s.Create("k1", []byte("key1"), map[string]any{"a": "b"}))
+	require.ErrorIs(t, s.Create("k1", key, nil), kms.ErrKeyExists)
+
+	key[0] = 'K'
+
+	stored, err := s.Get("k2")
+	require.NoError(t, err)
+	require.Equal(t, []byte("key"), stored)
+
+	stored, metadata, err := s.GetWithMetadata("k1")
+	require.NoError(t, err)
+	require.Equal(t, []byte("key1"), stored)
+	require.Equal(t, map[string]any{"a": "b"}, metadata)
+
+	keyIDs, err := s.KeyIDs()
+	require.NoError(t, err)
+	require.Equal(t, []string{"k1", "k2"}, keyIDs)
+
+	require.NoError(t, s.Delete("k1"))
+	require.NoError(t, s.Delete("k1"))
+
+	_, _, err = s.GetWithMetadata("k1")
+	require.ErrorIs(t, err, kms.ErrKeyNotFound)
+}
@@ -6,7 +6,17 @@ SPDX-License-Identifier: Apache-2.0
 
 package cryptoutil
 
-import "golang.org/x/crypto/blake2b"
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+
+	"github.com/trustbloc/kms-go/internal/memguard"
+)
 
 // Nonce makes a nonce using blake2b, to match the format expected by libsodium.
 func Nonce(pub1, pub2 []byte) (*[NonceSize]byte, error) {
@@ -32,3 +42,71 @@ func Nonce(pub1, pub2 []byte) (*[NonceSize]byte, error) {
 
 	return &nonce, nil
 }
+
+// BoxPublicKey returns pub as a box (Curve25519) public key, pub must be Curve25519KeySize bytes long.
+func BoxPublicKey(pub []byte) (*[Curve25519KeySize]byte, error) {
+	if len(pub) != Curve25519KeySize {
+		return nil, fmt.Errorf("invalid box public key size %d", len(pub))
+	}
+
+	var key [Curve25519KeySize]byte
+
+	copy(key[:], pub)
+
+	return &key, nil
+}
+
+// BoxNonce returns nonce as a box nonce, nonce must be NonceSize bytes long.
+func BoxNonce(nonce []byte) (*[NonceSize]byte, error) {
+	if len(nonce) != NonceSize {
+		return nil, fmt.Errorf("invalid box nonce size %d", len(nonce))
+	}
+
+	var n [NonceSize]byte
+
+	copy(n[:], nonce)
+
+	return &n, nil
+}
+
+// CheckBoxKeys checks the box shared secret of priv and pub is not zero. The shared secret of a low-order public key
+// is zero whatever the private key, a box sealed or opened with such a key is not authenticated nor confidential.
+func CheckBoxKeys(priv, pub *[Curve25519KeySize]byte) error {
+	shared, err := curve25519.X25519(priv[:], pub[:])
+	if err != nil {
+		return errors.New("invalid box public key: low order point")
+	}
+
+	memguard.Wipe(shared)
+
+	return nil
+}
+
+// BoxSeal seals payload for the box public key theirEncPub using the equivalent of libsodium box_seal: it generates an
+// ephemeral key pair with randSource and prepends the ephemeral public key to the sealed payload.
+func BoxSeal(payload, theirEncPub []byte, randSource io.Reader) ([]byte, error) {
+	recPub, err := BoxPublicKey(theirEncPub)
+	if err != nil {
+		return nil, err
+	}
+
+	// generate ephemeral curve25519 asymmetric keys
+	epk, esk, err := box.GenerateKey(randSource)
+	if err != nil {
+		return nil, err
+	}
+
+	defer memguard.Wipe(esk[:])
+
+	if err = CheckBoxKeys(esk, recPub); err != nil {
+		return nil, err
+	}
+
+	nonce, err := Nonce(epk[:], theirEncPub)
+	if err != nil {
+		return nil, err
+	}
+
+	// now seal the msg with the ephemeral key, nonce and recPub (which is recipient's publicKey)
+	return box.Seal(epk[:], payload, nonce, recPub, esk), nil
+}
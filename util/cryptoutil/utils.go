@@ -46,6 +46,9 @@ func LengthPrefix(array []byte) []byte {
 // Curve25519KeySize number of bytes in a Curve25519 public or private key.
 const Curve25519KeySize = 32
 
+// Curve448KeySize number of bytes in a Curve448 (X448) public or private key.
+const Curve448KeySize = 56
+
 // NonceSize size of a nonce used by Box encryption (Xchacha20Poly1305).
 const NonceSize = 24
 
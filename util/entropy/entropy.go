@@ -0,0 +1,126 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package entropy provides the randomness sources of key generation: the system one by default, or a deterministic
+// one seeded from a test vector so integration tests written in other languages can reproduce the same keys.
+//
+// The deterministic stream for a seed and a label is the concatenation of the blocks
+//
+//	HKDF-SHA256(ikm = seed, salt = empty, info = "kms-go entropy/deterministic v1" || len(label) || label || i)
+//
+// of 8160 bytes (the HKDF-SHA256 maximum output size) for i = 0, 1, 2..., where len(label) and i are big endian
+// uint32 values. Keys generated from a known seed are not secret: deterministic readers must only be used in tests.
+package entropy
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/trustbloc/kms-go/util/cryptoutil"
+	"github.com/trustbloc/kms-go/util/kdfdomain"
+)
+
+const (
+	blockSize    = 255 * sha256.Size
+	counterSize  = 4
+	scalarMargin = 8
+)
+
+// nolint:gochecknoglobals
+var deterministicDomain = kdfdomain.MustRegister("entropy/deterministic", 1, nil)
+
+// Reader returns r, or the system randomness source (crypto/rand.Reader) if r is nil.
+func Reader(r io.Reader) io.Reader {
+	if r == nil {
+		return rand.Reader
+	}
+
+	return r
+}
+
+// NewDeterministic returns the deterministic stream of seed and label (see the package documentation). The reader
+// is not safe for concurrent use.
+func NewDeterministic(seed []byte, label string) io.Reader {
+	return &deterministicReader{
+		seed:  append([]byte(nil), seed...),
+		label: cryptoutil.LengthPrefix([]byte(label)),
+	}
+}
+
+type deterministicReader struct {
+	seed    []byte
+	label   []byte
+	counter uint32
+	block   []byte
+}
+
+func (d *deterministicReader) Read(p []byte) (int, error) {
+	n := 0
+
+	for n < len(p) {
+		if len(d.block) == 0 {
+			if err := d.nextBlock(); err != nil {
+				return n, err
+			}
+		}
+
+		c := copy(p[n:], d.block)
+		d.block = d.block[c:]
+		n += c
+	}
+
+	return n, nil
+}
+
+func (d *deterministicReader) nextBlock() error {
+	counter := make([]byte, counterSize)
+	binary.BigEndian.PutUint32(counter, d.counter)
+
+	block := make([]byte, blockSize)
+
+	if _, err := io.ReadFull(hkdf.New(sha256.New, d.seed, nil, deterministicDomain.Info(d.label, counter)),
+		block); err != nil {
+		return fmt.Errorf("deterministic entropy: %w", err)
+	}
+
+	d.counter++
+	d.block = block
+
+	return nil
+}
+
+// ECDSAKey generates an ECDSA private key of curve from r: the private scalar is the big endian integer of the
+// curve order size plus 8 bytes read from r, reduced modulo n-1, plus 1 (FIPS 186-5 A.2.1). Unlike
+// ecdsa.GenerateKey, the key is deterministic when r is.
+func ECDSAKey(curve elliptic.Curve, r io.Reader) (*ecdsa.PrivateKey, error) {
+	params := curve.Params()
+
+	b := make([]byte, (params.N.BitLen()+7)/8+scalarMargin) //nolint:gomnd
+
+	if _, err := io.ReadFull(Reader(r), b); err != nil {
+		return nil, fmt.Errorf("generate ECDSA key: %w", err)
+	}
+
+	nMinus1 := new(big.Int).Sub(params.N, big.NewInt(1))
+
+	d := new(big.Int).SetBytes(b)
+	d.Mod(d, nMinus1)
+	d.Add(d, big.NewInt(1))
+
+	priv := &ecdsa.PrivateKey{D: d}
+	priv.Curve = curve
+	priv.X, priv.Y = curve.ScalarBaseMult(d.FillBytes(make([]byte, (params.N.BitLen()+7)/8))) //nolint:gomnd
+
+	return priv, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package entropy
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/hkdf"
+)
+
+func TestReader(t *testing.T) {
+	require.Equal(t, rand.Reader, Reader(nil))
+
+	r := bytes.NewReader(nil)
+	require.Equal(t, r, Reader(r))
+}
+
+func TestNewDeterministic(t *testing.T) {
+	seed := []byte("seed")
+
+	read := func(r io.Reader, n int) []byte {
+		b := make([]byte, n)
+
+		_, err := io.ReadFull(r, b)
+		require.NoError(t, err)
+
+		return b
+	}
+
+	stream := read(NewDeterministic(seed, "label"), 3*blockSize)
+
+	t.Run("matches the documented construction", func(t *testing.T) {
+		for i, counter := range [][]byte{{0, 0, 0, 0}, {0, 0, 0, 1}, {0, 0, 0, 2}} {
+			info := append([]byte("kms-go entropy/deterministic v1\x00\x00\x00\x05label"), counter...)
+
+			block := read(hkdf.New(sha256.New, seed, nil, info), blockSize)
+			require.Equal(t, block, stream[i*blockSize:(i+1)*blockSize])
+		}
+	})
+
+	t.Run("reads are split across blocks", func(t *testing.T) {
+		r := NewDeterministic(seed, "label")
+
+		var chunks []byte
+
+		for len(chunks) < len(stream) {
+			chunks = append(chunks, read(r, 1000)...)
+		}
+
+		require.Equal(t, stream, chunks[:len(stream)])
+	})
+
+	require.NotEqual(t, stream[:32], read(NewDeterministic(seed, "other label"), 32))
+	require.NotEqual(t, stream[:32], read(NewDeterministic([]byte("other seed"), "label"), 32))
+}
+
+func TestECDSAKey(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), elliptic.P521(), btcec.S256()} {
+		t.Run(curve.Params().Name, func(t *testing.T) {
+			key1, err := ECDSAKey(curve, NewDeterministic([]byte("seed"), "ecdsa"))
+			require.NoError(t, err)
+
+			key2, err := ECDSAKey(curve, NewDeterministic([]byte("seed"), "ecdsa"))
+			require.NoError(t, err)
+
+			require.True(t, key1.Equal(key2))
+			require.True(t, curve.IsOnCurve(key1.X, key1.Y))
+			require.Positive(t, key1.D.Sign())
+			require.Negative(t, key1.D.Cmp(curve.Params().N))
+
+			key3, err := ECDSAKey(curve, nil)
+			require.NoError(t, err)
+			require.False(t, key1.Equal(key3))
+		})
+	}
+
+	t.Run("known answer", func(t *testing.T) {
+		key, err := ECDSAKey(elliptic.P256(), bytes.NewReader(make([]byte, 40)))
+		require.NoError(t, err)
+		require.Equal(t, "0000000000000000000000000000000000000000000000000000000000000001",
+			hex.EncodeToString(key.D.FillBytes(make([]byte, 32))))
+		require.Equal(t, elliptic.P256().Params().Gx, key.X)
+	})
+
+	_, err := ECDSAKey(elliptic.P256(), bytes.NewReader(make([]byte, 8)))
+	require.ErrorContains(t, err, "generate ECDSA key")
+}
@@ -0,0 +1,15 @@
+//go:build boringcrypto
+
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fips
+
+import "crypto/boring"
+
+func boringCrypto() bool {
+	return boring.Enabled()
+}
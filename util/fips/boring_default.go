@@ -0,0 +1,13 @@
+//go:build !boringcrypto
+
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fips
+
+func boringCrypto() bool {
+	return false
+}
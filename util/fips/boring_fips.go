@@ -0,0 +1,12 @@
+//go:build fips && boringcrypto
+
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fips
+
+// restrict crypto/tls (webkms clients) to the FIPS approved TLS settings.
+import _ "crypto/tls/fipsonly"
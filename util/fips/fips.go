@@ -0,0 +1,124 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package fips gates the algorithms of localkms and tinkcrypto when the module is built with the fips build tag:
+//
+//	go build -tags fips ./...
+//
+// In this mode, only FIPS 140-3 approved algorithms can be used: AES-GCM, AES key wrap, HMAC-SHA2, ECDSA and ECDH on
+// the NIST P-256, P-384 and P-521 curves, and RSA with SHA-256. Creating, importing or using ChaCha20-Poly1305,
+// XChaCha20-Poly1305, Ed25519, X25519, X448, secp256k1, BBS+ or CL keys fails with ErrNotApproved.
+//
+// The gating doesn't make the module a validated cryptographic module. AES, SHA-2 and the NIST curves are run by the
+// Go standard library: built with GOEXPERIMENT=boringcrypto, it delegates them to the BoringCrypto module (see
+// BoringCrypto) and the fips build additionally restricts crypto/tls to FIPS settings.
+package fips
+
+import (
+	"errors"
+	"fmt"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+// ErrNotApproved is returned for algorithms that are not approved in FIPS mode.
+var ErrNotApproved = errors.New("fips: algorithm not approved in FIPS mode")
+
+//nolint:gochecknoglobals
+var (
+	approvedKeyTypes = map[kmsapi.KeyType]bool{
+		kmsapi.AES128GCMType: true, kmsapi.AES256GCMType: true, kmsapi.AES256GCMNoPrefixType: true,
+		kmsapi.AES128KWType: true, kmsapi.AES192KWType: true, kmsapi.AES256KWType: true,
+		kmsapi.HMACSHA256Tag256Type: true, kmsapi.HMACSHA384Tag384Type: true, kmsapi.HMACSHA512Tag512Type: true,
+		kmsapi.ECDSAP256TypeDER: true, kmsapi.ECDSAP384TypeDER: true, kmsapi.ECDSAP521TypeDER: true,
+		kmsapi.ECDSAP256TypeIEEEP1363: true, kmsapi.ECDSAP384TypeIEEEP1363: true, kmsapi.ECDSAP521TypeIEEEP1363: true,
+		kmsapi.NISTP256ECDHKWType: true, kmsapi.NISTP384ECDHKWType: true, kmsapi.NISTP521ECDHKWType: true,
+		kmsapi.RSARS256Type: true, kmsapi.RSAPS256Type: true, kmsapi.RSAOAEP256Type: true,
+	}
+
+	// approvedTypeURLs are the Tink key type URLs of the approved key types.
+	approvedTypeURLs = map[string]bool{
+		"type.googleapis.com/google.crypto.tink.AesGcmKey":                         true,
+		"type.hyperledger.org/hyperledger.aries.crypto.tink.AesKwKey":              true,
+		"type.googleapis.com/google.crypto.tink.HmacKey":                           true,
+		"type.googleapis.com/google.crypto.tink.EcdsaPrivateKey":                   true,
+		"type.googleapis.com/google.crypto.tink.EcdsaPublicKey":                    true,
+		"type.hyperledger.org/hyperledger.aries.crypto.tink.NistPEcdhKwPrivateKey": true,
+		"type.hyperledger.org/hyperledger.aries.crypto.tink.NistPEcdhKwPublicKey":  true,
+		"type.googleapis.com/google.crypto.tink.RsaSsaPkcs1PrivateKey":             true,
+		"type.googleapis.com/google.crypto.tink.RsaSsaPkcs1PublicKey":              true,
+		"type.hyperledger.org/hyperledger.aries.crypto.tink.RsaSsaPssPrivateKey":   true,
+		"type.hyperledger.org/hyperledger.aries.crypto.tink.RsaSsaPssPublicKey":    true,
+		"type.hyperledger.org/hyperledger.aries.crypto.tink.RsaOaepPrivateKey":     true,
+		"type.hyperledger.org/hyperledger.aries.crypto.tink.RsaOaepPublicKey":      true,
+	}
+
+	// approvedCurves are the names of the NIST P-256, P-384 and P-521 curves accepted by tinkcrypto.
+	approvedCurves = map[string]bool{
+		"P-256": true, "NIST_P256": true, "secp256r1": true, "EllipticCurveType_NIST_P256": true,
+		"P-384": true, "NIST_P384": true, "secp384r1": true, "EllipticCurveType_NIST_P384": true,
+		"P-521": true, "NIST_P521": true, "secp521r1": true, "EllipticCurveType_NIST_P521": true,
+	}
+
+	// approvedAlgorithms are the approved JOSE key management algorithms.
+	approvedAlgorithms = map[string]bool{
+		"ECDH-ES": true, "ECDH-ES+A256KW": true, "ECDH-1PU+A128KW": true, "ECDH-1PU+A192KW": true,
+		"ECDH-1PU+A256KW": true, "A128KW": true, "A192KW": true, "A256KW": true, "RSA-OAEP-256": true,
+	}
+)
+
+// Enabled reports whether the module is built in FIPS mode.
+func Enabled() bool {
+	return enabled
+}
+
+// BoringCrypto reports whether the Go standard library crypto is delegated to the BoringCrypto module.
+func BoringCrypto() bool {
+	return boringCrypto()
+}
+
+// KeyTypeApproved reports whether kt is a key type of an approved algorithm, regardless of the build mode.
+func KeyTypeApproved(kt kmsapi.KeyType) bool {
+	return approvedKeyTypes[kt]
+}
+
+// CheckKeyType returns ErrNotApproved if the module is built in FIPS mode and kt is not an approved key type.
+func CheckKeyType(kt kmsapi.KeyType) error {
+	if enabled && !approvedKeyTypes[kt] {
+		return fmt.Errorf("%w: key type '%s'", ErrNotApproved, kt)
+	}
+
+	return nil
+}
+
+// CheckCurve returns ErrNotApproved if the module is built in FIPS mode and curve is not an approved elliptic curve.
+func CheckCurve(curve string) error {
+	if enabled && !approvedCurves[curve] {
+		return fmt.Errorf("%w: curve '%s'", ErrNotApproved, curve)
+	}
+
+	return nil
+}
+
+// CheckAlgorithm returns ErrNotApproved if the module is built in FIPS mode and alg is not an approved JOSE key
+// management algorithm.
+func CheckAlgorithm(alg string) error {
+	if enabled && !approvedAlgorithms[alg] {
+		return fmt.Errorf("%w: algorithm '%s'", ErrNotApproved, alg)
+	}
+
+	return nil
+}
+
+// CheckTypeURL returns ErrNotApproved if the module is built in FIPS mode and typeURL is not the Tink key type URL
+// of an approved key type.
+func CheckTypeURL(typeURL string) error {
+	if enabled && !approvedTypeURLs[typeURL] {
+		return fmt.Errorf("%w: key type URL '%s'", ErrNotApproved, typeURL)
+	}
+
+	return nil
+}
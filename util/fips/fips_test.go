@@ -0,0 +1,62 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fips
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+func TestKeyTypeApproved(t *testing.T) {
+	for _, kt := range []kmsapi.KeyType{
+		kmsapi.AES256GCMType, kmsapi.HMACSHA256Tag256Type, kmsapi.AES256KWType, kmsapi.ECDSAP384TypeIEEEP1363,
+		kmsapi.NISTP256ECDHKWType, kmsapi.RSAPS256Type,
+	} {
+		require.True(t, KeyTypeApproved(kt), kt)
+		require.NoError(t, CheckKeyType(kt))
+	}
+
+	for _, kt := range []kmsapi.KeyType{
+		kmsapi.ChaCha20Poly1305Type, kmsapi.XChaCha20Poly1305Type, kmsapi.ED25519Type, kmsapi.X25519ECDHKWType,
+		kmsapi.ECDSASecp256k1TypeIEEEP1363, kmsapi.BLS12381G2Type, kmsapi.CLCredDefType, kmsapi.AESGCMSIV256Type,
+		kmsapi.BIP340Secp256k1Type,
+	} {
+		require.False(t, KeyTypeApproved(kt), kt)
+
+		requireApproved(t, CheckKeyType(kt), "fips: algorithm not approved in FIPS mode: key type '"+string(kt)+"'")
+	}
+}
+
+func TestChecks(t *testing.T) {
+	require.NoError(t, CheckCurve("P-256"))
+	require.NoError(t, CheckCurve("NIST_P521"))
+	require.NoError(t, CheckAlgorithm("ECDH-ES+A256KW"))
+	require.NoError(t, CheckTypeURL("type.googleapis.com/google.crypto.tink.AesGcmKey"))
+
+	requireApproved(t, CheckCurve("X25519"), "fips: algorithm not approved in FIPS mode: curve 'X25519'")
+	requireApproved(t, CheckAlgorithm("ECDH-ES+XC20PKW"),
+		"fips: algorithm not approved in FIPS mode: algorithm 'ECDH-ES+XC20PKW'")
+	requireApproved(t, CheckTypeURL("type.googleapis.com/google.crypto.tink.Ed25519PrivateKey"),
+		"fips: algorithm not approved in FIPS mode: key type URL 'type.googleapis.com/google.crypto.tink.Ed25519PrivateKey'")
+}
+
+// requireApproved checks err is the ErrNotApproved error msg in FIPS mode, and nil otherwise.
+func requireApproved(t *testing.T, err error, msg string) {
+	t.Helper()
+
+	if !Enabled() {
+		require.NoError(t, err)
+
+		return
+	}
+
+	require.ErrorIs(t, err, ErrNotApproved)
+	require.EqualError(t, err, msg)
+}
@@ -0,0 +1,11 @@
+//go:build fips
+
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fips
+
+const enabled = true
@@ -0,0 +1,140 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package kdfdomain is the registry of the key derivation domains of the module. A domain is a protocol label and
+// version mapped to the info (or AlgorithmID) bytes fed to the KDF, so keys derived for one protocol can never be
+// derived by another one: registering a domain fails if its label and version, or its info bytes, are already
+// registered. All the derivations of the module register their domain at init time, Domains lists them for auditing.
+package kdfdomain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Domain is a registered key derivation domain.
+type Domain struct {
+	label   string
+	version uint32
+	info    []byte
+}
+
+// Label returns the protocol label of the domain.
+func (d *Domain) Label() string {
+	return d.label
+}
+
+// Version returns the protocol version of the domain.
+func (d *Domain) Version() uint32 {
+	return d.version
+}
+
+// Info returns the info bytes of the domain followed by context, eg: the public keys the derived key is bound to.
+// Variable length context values should be length prefixed (see cryptoutil.LengthPrefix) to keep the info unambiguous.
+func (d *Domain) Info(context ...[]byte) []byte {
+	info := append([]byte{}, d.info...)
+
+	for _, c := range context {
+		info = append(info, c...)
+	}
+
+	return info
+}
+
+// String returns the domain as 'label/v<version>'.
+func (d *Domain) String() string {
+	return fmt.Sprintf("%s/v%d", d.label, d.version)
+}
+
+type domainID struct {
+	label   string
+	version uint32
+}
+
+//nolint:gochecknoglobals
+var (
+	mu       sync.RWMutex
+	registry = map[domainID]*Domain{}
+)
+
+// Register registers the domain label and version with info bytes. If info is empty, the info bytes are
+// 'kms-go <label> v<version>'. It fails if the domain or its info bytes are already registered.
+func Register(label string, version uint32, info []byte) (*Domain, error) {
+	if label == "" || version == 0 {
+		return nil, errors.New("kdfdomain: label and version are required")
+	}
+
+	if len(info) == 0 {
+		info = []byte(fmt.Sprintf("kms-go %s v%d", label, version))
+	}
+
+	d := &Domain{label: label, version: version, info: append([]byte{}, info...)}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := registry[domainID{label, version}]; ok {
+		return nil, fmt.Errorf("kdfdomain: domain '%s' is already registered", d)
+	}
+
+	for _, r := range registry {
+		if bytes.Equal(r.info, d.info) {
+			return nil, fmt.Errorf("kdfdomain: info of domain '%s' is already used by domain '%s'", d, r)
+		}
+	}
+
+	registry[domainID{label, version}] = d
+
+	return d, nil
+}
+
+// MustRegister is Register panicking on error, for package level domains.
+func MustRegister(label string, version uint32, info []byte) *Domain {
+	d, err := Register(label, version, info)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// Lookup returns the domain registered with label and version.
+func Lookup(label string, version uint32) (*Domain, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	d, ok := registry[domainID{label, version}]
+	if !ok {
+		return nil, fmt.Errorf("kdfdomain: domain '%s/v%d' is not registered", label, version)
+	}
+
+	return d, nil
+}
+
+// Domains returns the registered domains sorted by label and version.
+func Domains() []*Domain {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	domains := make([]*Domain, 0, len(registry))
+
+	for _, d := range registry {
+		domains = append(domains, d)
+	}
+
+	sort.Slice(domains, func(i, j int) bool {
+		if domains[i].label != domains[j].label {
+			return domains[i].label < domains[j].label
+		}
+
+		return domains[i].version < domains[j].version
+	})
+
+	return domains
+}
@@ -0,0 +1,49 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kdfdomain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister(t *testing.T) {
+	d, err := Register("test/protocol", 1, nil)
+	require.NoError(t, err)
+	require.Equal(t, "test/protocol", d.Label())
+	require.EqualValues(t, 1, d.Version())
+	require.Equal(t, "test/protocol/v1", d.String())
+	require.Equal(t, []byte("kms-go test/protocol v1"), d.Info())
+	require.Equal(t, []byte("kms-go test/protocol v1ab"), d.Info([]byte("a"), []byte("b")))
+
+	got, err := Lookup("test/protocol", 1)
+	require.NoError(t, err)
+	require.Same(t, d, got)
+
+	v2, err := Register("test/protocol", 2, []byte("custom info"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("custom info"), v2.Info())
+
+	_, err = Register("test/protocol", 1, []byte("other info"))
+	require.EqualError(t, err, "kdfdomain: domain 'test/protocol/v1' is already registered")
+
+	_, err = Register("test/other", 1, []byte("custom info"))
+	require.EqualError(t, err, "kdfdomain: info of domain 'test/other/v1' is already used by domain 'test/protocol/v2'")
+
+	_, err = Register("", 1, nil)
+	require.Error(t, err)
+
+	_, err = Lookup("test/protocol", 3)
+	require.EqualError(t, err, "kdfdomain: domain 'test/protocol/v3' is not registered")
+
+	require.Panics(t, func() { MustRegister("test/protocol", 1, nil) })
+
+	domains := Domains()
+	require.Len(t, domains, 2)
+	require.Equal(t, []*Domain{d, v2}, domains)
+}
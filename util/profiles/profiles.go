@@ -0,0 +1,304 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package profiles defines crypto suite profiles: a named Profile pins the key types, the JWE key management and
+// content encryption algorithm combinations and the JWS signature algorithms an application allows. localkms (see
+// localkms.WithProfile), tinkcrypto (see tinkcrypto.WithProfile) and doc/jose (see jose.WithEncryptProfile and
+// jose.WithDecryptProfile) constructed with a profile reject the operations outside of it with ErrNotAllowed.
+//
+// Unlike the fips build mode (see util/fips), profiles are selected at runtime and can be combined with it: an
+// operation must then be both approved and allowed by the profile.
+//
+// The built-in profiles are:
+//
+//   - DIDCommV2: the DIDComm Messaging v2 suites, X25519 and NIST P curves ECDH-ES/1PU key agreement with A256GCM,
+//     XC20P or A256CBC-HS512 content encryption, and EdDSA, ES256 and ES256K signatures.
+//   - FIPS: the FIPS 140-3 approved algorithms of util/fips, as a runtime profile.
+//
+// There is no post-quantum hybrid profile: the module doesn't implement a post-quantum KEM or signature scheme yet.
+// Applications can Register their own profiles.
+package profiles
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+const (
+	// DIDCommV2 is the name of the DIDComm Messaging v2 profile.
+	DIDCommV2 = "didcomm-v2"
+	// FIPS is the name of the FIPS 140-3 approved algorithms profile.
+	FIPS = "fips"
+)
+
+// ErrNotAllowed is returned for the key types and algorithms outside of a profile.
+var ErrNotAllowed = errors.New("profiles: not allowed by the profile")
+
+// JWEAlgorithm is an allowed combination of a JWE key management algorithm (alg header, eg: ECDH-ES+A256KW) and
+// content encryption algorithm (enc header, eg: A256GCM).
+type JWEAlgorithm struct {
+	Alg string
+	Enc string
+}
+
+// Profile is a named crypto suite profile. A Profile must not be modified once in use.
+type Profile struct {
+	// Name of the profile.
+	Name string
+	// KeyTypes are the allowed key types.
+	KeyTypes []kmsapi.KeyType
+	// JWE are the allowed JWE alg and enc combinations.
+	JWE []JWEAlgorithm
+	// SignatureAlgorithms are the allowed JWS signature algorithms (eg: EdDSA, ES256).
+	SignatureAlgorithms []string
+}
+
+//nolint:gochecknoglobals
+var (
+	// keyTypeURLs are the Tink key type URLs of the private and public keys of the key types.
+	keyTypeURLs = map[kmsapi.KeyType][]string{
+		kmsapi.AES128GCMType:         {tinkURL + "AesGcmKey"},
+		kmsapi.AES256GCMType:         {tinkURL + "AesGcmKey"},
+		kmsapi.AES256GCMNoPrefixType: {tinkURL + "AesGcmKey"},
+		kmsapi.ChaCha20Poly1305Type:  {tinkURL + "ChaCha20Poly1305Key"},
+		kmsapi.XChaCha20Poly1305Type: {tinkURL + "XChaCha20Poly1305Key"},
+		kmsapi.AESGCMSIV256Type:      {tinkURL + "AesGcmSivKey"},
+		kmsapi.HMACSHA256Tag256Type:  {tinkURL + "HmacKey"},
+		kmsapi.HMACSHA384Tag384Type:  {tinkURL + "HmacKey"},
+		kmsapi.HMACSHA512Tag512Type:  {tinkURL + "HmacKey"},
+		kmsapi.AES128KWType:          {ariesURL + "AesKwKey"},
+		kmsapi.AES192KWType:          {ariesURL + "AesKwKey"},
+		kmsapi.AES256KWType:          {ariesURL + "AesKwKey"},
+
+		kmsapi.ECDSAP256TypeDER:            {tinkURL + "EcdsaPrivateKey", tinkURL + "EcdsaPublicKey"},
+		kmsapi.ECDSAP384TypeDER:            {tinkURL + "EcdsaPrivateKey", tinkURL + "EcdsaPublicKey"},
+		kmsapi.ECDSAP521TypeDER:            {tinkURL + "EcdsaPrivateKey", tinkURL + "EcdsaPublicKey"},
+		kmsapi.ECDSAP256TypeIEEEP1363:      {tinkURL + "EcdsaPrivateKey", tinkURL + "EcdsaPublicKey"},
+		kmsapi.ECDSAP384TypeIEEEP1363:      {tinkURL + "EcdsaPrivateKey", tinkURL + "EcdsaPublicKey"},
+		kmsapi.ECDSAP521TypeIEEEP1363:      {tinkURL + "EcdsaPrivateKey", tinkURL + "EcdsaPublicKey"},
+		kmsapi.ECDSASecp256k1TypeIEEEP1363: {tinkURL + "secp256k1PrivateKey", tinkURL + "secp256k1PublicKey"},
+		kmsapi.ED25519Type:                 {tinkURL + "Ed25519PrivateKey", tinkURL + "Ed25519PublicKey"},
+		kmsapi.NISTP256ECDHKWType:          {ariesURL + "NistPEcdhKwPrivateKey", ariesURL + "NistPEcdhKwPublicKey"},
+		kmsapi.NISTP384ECDHKWType:          {ariesURL + "NistPEcdhKwPrivateKey", ariesURL + "NistPEcdhKwPublicKey"},
+		kmsapi.NISTP521ECDHKWType:          {ariesURL + "NistPEcdhKwPrivateKey", ariesURL + "NistPEcdhKwPublicKey"},
+		kmsapi.X25519ECDHKWType:            {ariesURL + "X25519EcdhKwPrivateKey", ariesURL + "X25519EcdhKwPublicKey"},
+		kmsapi.X448ECDHKWType:              {ariesURL + "X448EcdhKwPrivateKey", ariesURL + "X448EcdhKwPublicKey"},
+		kmsapi.BLS12381G2Type:              {ariesURL + "BBSPrivateKey", ariesURL + "BBSPublicKey"},
+		kmsapi.RSARS256Type:                {tinkURL + "RsaSsaPkcs1PrivateKey", tinkURL + "RsaSsaPkcs1PublicKey"},
+		kmsapi.RSAPS256Type:                {ariesURL + "RsaSsaPssPrivateKey", ariesURL + "RsaSsaPssPublicKey"},
+		kmsapi.RSAOAEP256Type:              {ariesURL + "RsaOaepPrivateKey", ariesURL + "RsaOaepPublicKey"},
+		kmsapi.BIP340Secp256k1Type: {
+			ariesURL + "BIP340Secp256k1PrivateKey", ariesURL + "BIP340Secp256k1PublicKey",
+		},
+	}
+
+	// keyTypeCurves are the names of the elliptic curves of the key types accepted by tinkcrypto.
+	keyTypeCurves = map[kmsapi.KeyType][]string{
+		kmsapi.ECDSAP256TypeDER:            p256,
+		kmsapi.ECDSAP384TypeDER:            p384,
+		kmsapi.ECDSAP521TypeDER:            p521,
+		kmsapi.ECDSAP256TypeIEEEP1363:      p256,
+		kmsapi.ECDSAP384TypeIEEEP1363:      p384,
+		kmsapi.ECDSAP521TypeIEEEP1363:      p521,
+		kmsapi.NISTP256ECDHKWType:          p256,
+		kmsapi.NISTP384ECDHKWType:          p384,
+		kmsapi.NISTP521ECDHKWType:          p521,
+		kmsapi.ECDSASecp256k1TypeIEEEP1363: {"secp256k1", "SECP256K1"},
+		kmsapi.X25519ECDHKWType:            {"X25519"},
+		kmsapi.X448ECDHKWType:              {"X448"},
+	}
+
+	p256 = []string{"P-256", "NIST_P256", "secp256r1", "EllipticCurveType_NIST_P256"}
+	p384 = []string{"P-384", "NIST_P384", "secp384r1", "EllipticCurveType_NIST_P384"}
+	p521 = []string{"P-521", "NIST_P521", "secp521r1", "EllipticCurveType_NIST_P521"}
+
+	registry = map[string]*Profile{
+		DIDCommV2: didCommV2(),
+		FIPS:      fipsProfile(),
+	}
+
+	registryMutex sync.RWMutex
+)
+
+const (
+	tinkURL  = "type.googleapis.com/google.crypto.tink."
+	ariesURL = "type.hyperledger.org/hyperledger.aries.crypto.tink."
+)
+
+func didCommV2() *Profile {
+	return &Profile{
+		Name: DIDCommV2,
+		KeyTypes: []kmsapi.KeyType{
+			kmsapi.X25519ECDHKWType, kmsapi.NISTP256ECDHKWType, kmsapi.NISTP384ECDHKWType, kmsapi.NISTP521ECDHKWType,
+			kmsapi.ED25519Type, kmsapi.ECDSAP256TypeDER, kmsapi.ECDSAP256TypeIEEEP1363,
+			kmsapi.ECDSASecp256k1TypeIEEEP1363,
+		},
+		JWE: []JWEAlgorithm{
+			{Alg: "ECDH-ES+A256KW", Enc: "A256CBC-HS512"},
+			{Alg: "ECDH-ES+A256KW", Enc: "A256GCM"},
+			{Alg: "ECDH-ES+A256KW", Enc: "XC20P"},
+			{Alg: "ECDH-ES+XC20PKW", Enc: "XC20P"},
+			{Alg: "ECDH-1PU+A256KW", Enc: "A256CBC-HS512"},
+		},
+		SignatureAlgorithms: []string{"EdDSA", "ES256", "ES256K"},
+	}
+}
+
+func fipsProfile() *Profile {
+	return &Profile{
+		Name: FIPS,
+		KeyTypes: []kmsapi.KeyType{
+			kmsapi.AES128GCMType, kmsapi.AES256GCMType, kmsapi.AES256GCMNoPrefixType,
+			kmsapi.AES128KWType, kmsapi.AES192KWType, kmsapi.AES256KWType,
+			kmsapi.HMACSHA256Tag256Type, kmsapi.HMACSHA384Tag384Type, kmsapi.HMACSHA512Tag512Type,
+			kmsapi.ECDSAP256TypeDER, kmsapi.ECDSAP384TypeDER, kmsapi.ECDSAP521TypeDER,
+			kmsapi.ECDSAP256TypeIEEEP1363, kmsapi.ECDSAP384TypeIEEEP1363, kmsapi.ECDSAP521TypeIEEEP1363,
+			kmsapi.NISTP256ECDHKWType, kmsapi.NISTP384ECDHKWType, kmsapi.NISTP521ECDHKWType,
+			kmsapi.RSARS256Type, kmsapi.RSAPS256Type, kmsapi.RSAOAEP256Type,
+		},
+		JWE: combinations(
+			[]string{
+				"ECDH-ES", "ECDH-ES+A256KW", "ECDH-1PU+A128KW", "ECDH-1PU+A192KW", "ECDH-1PU+A256KW",
+				"A128KW", "A192KW", "A256KW", "RSA-OAEP-256",
+			},
+			[]string{"A128GCM", "A192GCM", "A256GCM", "A128CBC-HS256", "A192CBC-HS384", "A256CBC-HS512"},
+		),
+		SignatureAlgorithms: []string{"ES256", "ES384", "ES512", "RS256", "PS256"},
+	}
+}
+
+// combinations returns the JWE combinations of every alg with every enc.
+func combinations(algs, encs []string) []JWEAlgorithm {
+	jwe := make([]JWEAlgorithm, 0, len(algs)*len(encs))
+
+	for _, alg := range algs {
+		for _, enc := range encs {
+			jwe = append(jwe, JWEAlgorithm{Alg: alg, Enc: enc})
+		}
+	}
+
+	return jwe
+}
+
+// Get returns the profile registered with name.
+func Get(name string) (*Profile, error) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	p, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("get profile: unknown profile '%s'", name)
+	}
+
+	return p, nil
+}
+
+// Register registers p with its name, replacing the profile registered with the same name, if any.
+func Register(p *Profile) error {
+	if p == nil || p.Name == "" {
+		return errors.New("register profile: the profile must have a name")
+	}
+
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	registry[p.Name] = p
+
+	return nil
+}
+
+// Names returns the sorted names of the registered profiles.
+func Names() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	names := make([]string, 0, len(registry))
+
+	for name := range registry {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// CheckKeyType returns ErrNotAllowed if kt is not a key type of p.
+func (p *Profile) CheckKeyType(kt kmsapi.KeyType) error {
+	for _, allowed := range p.KeyTypes {
+		if allowed == kt {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: profile '%s': key type '%s'", ErrNotAllowed, p.Name, kt)
+}
+
+// CheckTypeURL returns ErrNotAllowed if typeURL is not the Tink key type URL of a private or public key of a key type
+// of p. Key types sharing a type URL (eg: ECDSA keys of all the curves) can't be told apart by their type URL.
+func (p *Profile) CheckTypeURL(typeURL string) error {
+	for _, kt := range p.KeyTypes {
+		for _, url := range keyTypeURLs[kt] {
+			if url == typeURL {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("%w: profile '%s': key type URL '%s'", ErrNotAllowed, p.Name, typeURL)
+}
+
+// CheckCurve returns ErrNotAllowed if curve is not the name of the elliptic curve of an ECDSA or ECDH key type of p.
+func (p *Profile) CheckCurve(curve string) error {
+	for _, kt := range p.KeyTypes {
+		for _, c := range keyTypeCurves[kt] {
+			if c == curve {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("%w: profile '%s': curve '%s'", ErrNotAllowed, p.Name, curve)
+}
+
+// CheckKeyWrapAlgorithm returns ErrNotAllowed if alg is not a JWE key management algorithm of p, combined with any
+// content encryption algorithm.
+func (p *Profile) CheckKeyWrapAlgorithm(alg string) error {
+	for _, jwe := range p.JWE {
+		if jwe.Alg == alg {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: profile '%s': key wrapping algorithm '%s'", ErrNotAllowed, p.Name, alg)
+}
+
+// CheckJWE returns ErrNotAllowed if the combination of the JWE key management algorithm alg and the content
+// encryption algorithm enc is not allowed by p.
+func (p *Profile) CheckJWE(alg, enc string) error {
+	for _, jwe := range p.JWE {
+		if jwe.Alg == alg && jwe.Enc == enc {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: profile '%s': JWE algorithm '%s' with encryption '%s'", ErrNotAllowed, p.Name, alg, enc)
+}
+
+// CheckSignatureAlgorithm returns ErrNotAllowed if alg is not a JWS signature algorithm of p.
+func (p *Profile) CheckSignatureAlgorithm(alg string) error {
+	for _, allowed := range p.SignatureAlgorithms {
+		if allowed == alg {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: profile '%s': signature algorithm '%s'", ErrNotAllowed, p.Name, alg)
+}
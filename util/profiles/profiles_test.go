@@ -0,0 +1,110 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package profiles_test
+
+import (
+	"testing"
+
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+	"github.com/trustbloc/kms-go/util/profiles"
+)
+
+func TestBuiltInProfiles(t *testing.T) {
+	require.Subset(t, profiles.Names(), []string{profiles.DIDCommV2, profiles.FIPS})
+
+	didcomm, err := profiles.Get(profiles.DIDCommV2)
+	require.NoError(t, err)
+	require.Equal(t, profiles.DIDCommV2, didcomm.Name)
+
+	require.NoError(t, didcomm.CheckKeyType(kmsapi.X25519ECDHKWType))
+	require.NoError(t, didcomm.CheckKeyType(kmsapi.ED25519Type))
+	require.NoError(t, didcomm.CheckJWE("ECDH-1PU+A256KW", "A256CBC-HS512"))
+	require.NoError(t, didcomm.CheckJWE("ECDH-ES+XC20PKW", "XC20P"))
+	require.NoError(t, didcomm.CheckSignatureAlgorithm("EdDSA"))
+	require.NoError(t, didcomm.CheckCurve("X25519"))
+	require.NoError(t, didcomm.CheckCurve("NIST_P256"))
+
+	err = didcomm.CheckKeyType(kmsapi.RSAOAEP256Type)
+	require.ErrorIs(t, err, profiles.ErrNotAllowed)
+	require.EqualError(t, err, "profiles: not allowed by the profile: profile 'didcomm-v2': key type 'RSAOAEP256'")
+
+	require.ErrorIs(t, didcomm.CheckJWE("ECDH-1PU+A256KW", "A256GCM"), profiles.ErrNotAllowed)
+	require.ErrorIs(t, didcomm.CheckSignatureAlgorithm("RS256"), profiles.ErrNotAllowed)
+	require.ErrorIs(t, didcomm.CheckCurve("X448"), profiles.ErrNotAllowed)
+	require.ErrorIs(t, didcomm.CheckKeyWrapAlgorithm("RSA-OAEP-256"), profiles.ErrNotAllowed)
+
+	fips, err := profiles.Get(profiles.FIPS)
+	require.NoError(t, err)
+
+	require.NoError(t, fips.CheckKeyType(kmsapi.ECDSAP384TypeDER))
+	require.NoError(t, fips.CheckJWE("RSA-OAEP-256", "A256GCM"))
+	require.NoError(t, fips.CheckKeyWrapAlgorithm("A256KW"))
+	require.NoError(t, fips.CheckCurve("P-521"))
+	require.ErrorIs(t, fips.CheckKeyType(kmsapi.ED25519Type), profiles.ErrNotAllowed)
+	require.ErrorIs(t, fips.CheckJWE("ECDH-ES+A256KW", "XC20P"), profiles.ErrNotAllowed)
+	require.ErrorIs(t, fips.CheckSignatureAlgorithm("ES256K"), profiles.ErrNotAllowed)
+	require.ErrorIs(t, fips.CheckCurve("X25519"), profiles.ErrNotAllowed)
+	require.ErrorIs(t, fips.CheckTypeURL("type.googleapis.com/google.crypto.tink.Ed25519PrivateKey"),
+		profiles.ErrNotAllowed)
+
+	_, err = profiles.Get("pq-hybrid")
+	require.EqualError(t, err, "get profile: unknown profile 'pq-hybrid'")
+}
+
+func TestRegister(t *testing.T) {
+	require.EqualError(t, profiles.Register(&profiles.Profile{}), "register profile: the profile must have a name")
+
+	p := &profiles.Profile{
+		Name:                "test-ed25519",
+		KeyTypes:            []kmsapi.KeyType{kmsapi.ED25519Type},
+		SignatureAlgorithms: []string{"EdDSA"},
+	}
+
+	require.NoError(t, profiles.Register(p))
+	require.Contains(t, profiles.Names(), "test-ed25519")
+
+	registered, err := profiles.Get("test-ed25519")
+	require.NoError(t, err)
+	require.Same(t, p, registered)
+
+	err = registered.CheckJWE("ECDH-ES+A256KW", "A256GCM")
+	require.EqualError(t, err, "profiles: not allowed by the profile: profile 'test-ed25519': JWE algorithm "+
+		"'ECDH-ES+A256KW' with encryption 'A256GCM'")
+}
+
+// TestCheckTypeURL checks the type URLs of the profile key types against the keys created by localkms.
+func TestCheckTypeURL(t *testing.T) {
+	km := mockkms.NewForTest(t)
+
+	caps, err := km.Capabilities()
+	require.NoError(t, err)
+
+	for _, c := range caps.KeyTypes {
+		_, kh, e := km.Create(c.KeyType)
+		require.NoError(t, e, c.KeyType)
+
+		p := &profiles.Profile{Name: "test", KeyTypes: []kmsapi.KeyType{c.KeyType}}
+
+		h, ok := kh.(*keyset.Handle)
+		require.True(t, ok)
+
+		for _, k := range insecurecleartextkeyset.KeysetMaterial(h).GetKey() {
+			require.NoError(t, p.CheckTypeURL(k.GetKeyData().GetTypeUrl()), c.KeyType)
+		}
+
+		if pubKH, e := h.Public(); e == nil {
+			for _, ki := range pubKH.KeysetInfo().GetKeyInfo() {
+				require.NoError(t, p.CheckTypeURL(ki.GetTypeUrl()), c.KeyType)
+			}
+		}
+	}
+}
@@ -0,0 +1,259 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package sigencoding converts ECDSA signatures between the ASN.1 DER encoding (used by X.509, CMS or TLS) and the
+// IEEE-P1363 encoding (R || S, used by JOSE and COSE). A KMS key signs in the encoding of its key type (eg:
+// ECDSAP256TypeDER or ECDSAP256TypeIEEEP1363), Sign and Verify produce and accept the other encoding per call.
+package sigencoding
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/google/tink/go/keyset"
+
+	"github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/spi/kms"
+)
+
+// Encoding is an ECDSA signature encoding.
+type Encoding string
+
+const (
+	// DER is the ASN.1 DER encoding of the ECDSA-Sig-Value sequence (RFC 3279).
+	DER = Encoding("DER")
+	// IEEEP1363 is the fixed size R || S encoding, each value left padded to the curve size.
+	IEEEP1363 = Encoding("IEEE_P1363")
+)
+
+type ecdsaDERSignature struct {
+	R, S *big.Int
+}
+
+// KeyTypeEncoding returns the signature encoding of the ECDSA key type kt and its curve size in bytes. ok is false
+// for non ECDSA key types.
+func KeyTypeEncoding(kt kms.KeyType) (Encoding, int, bool) {
+	switch kt { //nolint:exhaustive
+	case kms.ECDSAP256TypeDER, kms.ECDSASecp256k1TypeDER:
+		return DER, 32, true //nolint:gomnd
+	case kms.ECDSAP384TypeDER:
+		return DER, 48, true //nolint:gomnd
+	case kms.ECDSAP521TypeDER:
+		return DER, 66, true //nolint:gomnd
+	case kms.ECDSAP256TypeIEEEP1363, kms.ECDSASecp256k1TypeIEEEP1363:
+		return IEEEP1363, 32, true //nolint:gomnd
+	case kms.ECDSAP384TypeIEEEP1363:
+		return IEEEP1363, 48, true //nolint:gomnd
+	case kms.ECDSAP521TypeIEEEP1363:
+		return IEEEP1363, 66, true //nolint:gomnd
+	default:
+		return "", 0, false
+	}
+}
+
+// DERToP1363 converts the DER encoded ECDSA signature sig to the IEEE-P1363 encoding for a curve of size bytes.
+func DERToP1363(sig []byte, size int) ([]byte, error) {
+	derSig := &ecdsaDERSignature{}
+
+	rest, err := asn1.Unmarshal(sig, derSig)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal DER signature: %w", err)
+	}
+
+	if len(rest) > 0 || !validScalar(derSig.R, size) || !validScalar(derSig.S, size) {
+		return nil, errors.New("invalid DER signature")
+	}
+
+	p1363 := make([]byte, 2*size)
+	derSig.R.FillBytes(p1363[:size])
+	derSig.S.FillBytes(p1363[size:])
+
+	return p1363, nil
+}
+
+// P1363ToDER converts the IEEE-P1363 encoded ECDSA signature sig for a curve of size bytes to the DER encoding.
+func P1363ToDER(sig []byte, size int) ([]byte, error) {
+	if size <= 0 || len(sig) != 2*size {
+		return nil, errors.New("invalid ECDSA signature size")
+	}
+
+	derSig := ecdsaDERSignature{
+		R: new(big.Int).SetBytes(sig[:size]),
+		S: new(big.Int).SetBytes(sig[size:]),
+	}
+
+	if derSig.R.Sign() == 0 || derSig.S.Sign() == 0 {
+		return nil, errors.New("invalid IEEE-P1363 signature")
+	}
+
+	return asn1.Marshal(derSig)
+}
+
+// Detect returns the encoding of the ECDSA signature sig for a curve of size bytes. Signatures of exactly 2*size bytes
+// are IEEE-P1363 signatures: a DER signature of this size would need both R and S to be several bytes shorter than
+// the curve size, which is negligibly likely.
+func Detect(sig []byte, size int) (Encoding, error) {
+	if size > 0 && len(sig) == 2*size {
+		return IEEEP1363, nil
+	}
+
+	if _, err := DERToP1363(sig, size); err != nil {
+		return "", fmt.Errorf("unrecognized ECDSA signature encoding: %w", err)
+	}
+
+	return DER, nil
+}
+
+func validScalar(v *big.Int, size int) bool {
+	return v.Sign() > 0 && v.BitLen() <= 8*size
+}
+
+// Convert converts sig, created by a key of type kt, to the encoding to. Signatures of non ECDSA key types and
+// signatures already in the to encoding are returned as is.
+func Convert(sig []byte, kt kms.KeyType, to Encoding) ([]byte, error) {
+	return convert(sig, kt, to, false)
+}
+
+// ConvertToKeyType converts sig, in the encoding from, to the encoding of the key type kt, eg: to verify a JWS
+// signature with a DER key. Signatures of non ECDSA key types are returned as is.
+func ConvertToKeyType(sig []byte, kt kms.KeyType, from Encoding) ([]byte, error) {
+	return convert(sig, kt, from, true)
+}
+
+func convert(sig []byte, kt kms.KeyType, other Encoding, toKeyType bool) ([]byte, error) {
+	if other != DER && other != IEEEP1363 {
+		return nil, fmt.Errorf("unsupported signature encoding '%s'", other)
+	}
+
+	enc, size, ok := KeyTypeEncoding(kt)
+	if !ok || enc == other {
+		return sig, nil
+	}
+
+	from := enc
+
+	if toKeyType {
+		from = other
+	}
+
+	if from == DER {
+		return DERToP1363(sig, size)
+	}
+
+	return P1363ToDER(sig, size)
+}
+
+type opts struct {
+	encoding   Encoding
+	reverify   bool
+	autoDetect bool
+}
+
+// Opt is an option of Sign and Verify.
+type Opt func(o *opts)
+
+// WithEncoding sets the encoding of the signature returned by Sign or given to Verify. Without it, the encoding of
+// the key type is used.
+func WithEncoding(enc Encoding) Opt {
+	return func(o *opts) {
+		o.encoding = enc
+	}
+}
+
+// WithReverify makes Sign convert the signature back to the encoding of the key type and verify it with the signing
+// key handle (its public key handle for Tink keyset handles) before returning it, catching faulty conversions or
+// signers.
+func WithReverify() Opt {
+	return func(o *opts) {
+		o.reverify = true
+	}
+}
+
+// WithAutoDetect makes Verify detect the encoding of the signature with Detect, instead of using the encoding set with
+// WithEncoding or the encoding of the key type. It has no effect on Sign and on non ECDSA key types.
+func WithAutoDetect() Opt {
+	return func(o *opts) {
+		o.autoDetect = true
+	}
+}
+
+// Sign signs msg with the key handle kh of key type kt using c and returns the signature in the encoding set with
+// WithEncoding.
+func Sign(c crypto.Crypto, msg []byte, kh interface{}, kt kms.KeyType, options ...Opt) ([]byte, error) {
+	o := applyOpts(options)
+
+	sig, err := c.Sign(msg, kh)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.encoding == "" {
+		return sig, nil
+	}
+
+	converted, err := Convert(sig, kt, o.encoding)
+	if err != nil {
+		return nil, fmt.Errorf("sigencoding: %w", err)
+	}
+
+	if o.reverify {
+		if err = Verify(c, converted, msg, verificationHandle(kh), kt, WithEncoding(o.encoding)); err != nil {
+			return nil, fmt.Errorf("sigencoding: reverify converted signature: %w", err)
+		}
+	}
+
+	return converted, nil
+}
+
+// Verify verifies sig, in the encoding set with WithEncoding (or detected with WithAutoDetect), for msg with the key
+// handle kh of key type kt using c.
+func Verify(c crypto.Crypto, sig, msg []byte, kh interface{}, kt kms.KeyType, options ...Opt) error {
+	o := applyOpts(options)
+
+	if _, size, ok := KeyTypeEncoding(kt); ok && o.autoDetect {
+		enc, err := Detect(sig, size)
+		if err != nil {
+			return fmt.Errorf("sigencoding: %w", err)
+		}
+
+		o.encoding = enc
+	}
+
+	if o.encoding != "" {
+		var err error
+
+		sig, err = ConvertToKeyType(sig, kt, o.encoding)
+		if err != nil {
+			return fmt.Errorf("sigencoding: %w", err)
+		}
+	}
+
+	return c.Verify(sig, msg, kh)
+}
+
+// verificationHandle returns the public key handle of Tink keyset handles, the key handle as is otherwise (eg: a
+// webkms key URL).
+func verificationHandle(kh interface{}) interface{} {
+	if h, ok := kh.(*keyset.Handle); ok {
+		if pub, err := h.Public(); err == nil {
+			return pub
+		}
+	}
+
+	return kh
+}
+
+func applyOpts(options []Opt) *opts {
+	o := &opts{}
+
+	for _, opt := range options {
+		opt(o)
+	}
+
+	return o
+}
@@ -0,0 +1,182 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sigencoding_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+	"github.com/trustbloc/kms-go/util/sigencoding"
+)
+
+func TestDERToP1363(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte("msg"))
+
+	der, err := ecdsa.SignASN1(rand.Reader, priv, digest[:])
+	require.NoError(t, err)
+
+	p1363, err := sigencoding.DERToP1363(der, 48)
+	require.NoError(t, err)
+	require.Len(t, p1363, 96)
+
+	r, s := new(big.Int).SetBytes(p1363[:48]), new(big.Int).SetBytes(p1363[48:])
+	require.True(t, ecdsa.Verify(&priv.PublicKey, digest[:], r, s))
+
+	back, err := sigencoding.P1363ToDER(p1363, 48)
+	require.NoError(t, err)
+	require.Equal(t, der, back)
+
+	enc, err := sigencoding.Detect(der, 48)
+	require.NoError(t, err)
+	require.Equal(t, sigencoding.DER, enc)
+
+	enc, err = sigencoding.Detect(p1363, 48)
+	require.NoError(t, err)
+	require.Equal(t, sigencoding.IEEEP1363, enc)
+
+	t.Run("errors", func(t *testing.T) {
+		_, err = sigencoding.DERToP1363([]byte("not DER"), 32)
+		require.ErrorContains(t, err, "unmarshal DER signature")
+
+		_, err = sigencoding.DERToP1363(append(der, 0), 48)
+		require.EqualError(t, err, "invalid DER signature")
+
+		_, err = sigencoding.DERToP1363(der, 32)
+		require.EqualError(t, err, "invalid DER signature")
+
+		negative, err := asn1.Marshal(struct{ R, S *big.Int }{big.NewInt(-1), big.NewInt(1)})
+		require.NoError(t, err)
+
+		_, err = sigencoding.DERToP1363(negative, 32)
+		require.EqualError(t, err, "invalid DER signature")
+
+		_, err = sigencoding.P1363ToDER(p1363[1:], 48)
+		require.EqualError(t, err, "invalid ECDSA signature size")
+
+		_, err = sigencoding.P1363ToDER(make([]byte, 64), 32)
+		require.EqualError(t, err, "invalid IEEE-P1363 signature")
+
+		_, err = sigencoding.Detect([]byte("not DER"), 48)
+		require.ErrorContains(t, err, "unrecognized ECDSA signature encoding")
+
+		_, err = sigencoding.Convert(der, kmsapi.ECDSAP384TypeDER, "raw")
+		require.EqualError(t, err, "unsupported signature encoding 'raw'")
+	})
+}
+
+func TestSignVerify(t *testing.T) {
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	tests := []struct {
+		kt    kmsapi.KeyType
+		other sigencoding.Encoding
+	}{
+		{kmsapi.ECDSAP256TypeDER, sigencoding.IEEEP1363},
+		{kmsapi.ECDSAP384TypeIEEEP1363, sigencoding.DER},
+		{kmsapi.ECDSAP521TypeDER, sigencoding.IEEEP1363},
+		{kmsapi.ED25519Type, sigencoding.DER},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.kt), func(t *testing.T) {
+			kh := newKeyHandle(t, tc.kt)
+			msg := []byte("msg")
+
+			enc, size, ok := sigencoding.KeyTypeEncoding(tc.kt)
+
+			sig, err := sigencoding.Sign(c, msg, kh, tc.kt, sigencoding.WithEncoding(tc.other),
+				sigencoding.WithReverify())
+			require.NoError(t, err)
+
+			if ok && tc.other == sigencoding.IEEEP1363 {
+				require.Len(t, sig, 2*size)
+			}
+
+			pub, err := kh.Public()
+			require.NoError(t, err)
+
+			require.NoError(t, sigencoding.Verify(c, sig, msg, pub, tc.kt, sigencoding.WithEncoding(tc.other)))
+
+			native, err := sigencoding.ConvertToKeyType(sig, tc.kt, tc.other)
+			require.NoError(t, err)
+			require.NoError(t, sigencoding.Verify(c, native, msg, pub, tc.kt))
+
+			require.NoError(t, sigencoding.Verify(c, sig, msg, pub, tc.kt, sigencoding.WithAutoDetect()))
+			require.NoError(t, sigencoding.Verify(c, native, msg, pub, tc.kt, sigencoding.WithAutoDetect()))
+
+			if ok {
+				require.NotEqual(t, sig, native)
+
+				same, err := sigencoding.Convert(native, tc.kt, enc)
+				require.NoError(t, err)
+				require.Equal(t, native, same)
+
+				require.Error(t, sigencoding.Verify(c, sig, msg, pub, tc.kt))
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		kh := newKeyHandle(t, kmsapi.ECDSAP256TypeDER)
+
+		_, err = sigencoding.Sign(c, []byte("msg"), kh, kmsapi.ECDSAP256TypeDER, sigencoding.WithEncoding("raw"))
+		require.EqualError(t, err, "sigencoding: unsupported signature encoding 'raw'")
+
+		_, err = sigencoding.Sign(c, []byte("msg"), "not a key handle", kmsapi.ECDSAP256TypeDER)
+		require.Error(t, err)
+
+		err = sigencoding.Verify(c, []byte("short"), []byte("msg"), nil, kmsapi.ECDSAP256TypeDER,
+			sigencoding.WithEncoding(sigencoding.IEEEP1363))
+		require.EqualError(t, err, "sigencoding: invalid ECDSA signature size")
+
+		err = sigencoding.Verify(c, []byte("short"), []byte("msg"), nil, kmsapi.ECDSAP256TypeDER,
+			sigencoding.WithAutoDetect())
+		require.ErrorContains(t, err, "sigencoding: unrecognized ECDSA signature encoding")
+
+		_, err = sigencoding.Sign(&failingVerifier{Crypto: c}, []byte("msg"), kh, kmsapi.ECDSAP256TypeDER,
+			sigencoding.WithEncoding(sigencoding.IEEEP1363), sigencoding.WithReverify())
+		require.EqualError(t, err, "sigencoding: reverify converted signature: verify failed")
+	})
+}
+
+type failingVerifier struct {
+	*tinkcrypto.Crypto
+}
+
+func (f *failingVerifier) Verify([]byte, []byte, interface{}) error {
+	return errors.New("verify failed")
+}
+
+func newKeyHandle(t *testing.T, kt kmsapi.KeyType) *keyset.Handle {
+	t.Helper()
+
+	k := mockkms.NewForTest(t)
+
+	_, kh, err := k.Create(kt)
+	require.NoError(t, err)
+
+	h, ok := kh.(*keyset.Handle)
+	require.True(t, ok)
+
+	return h
+}
@@ -0,0 +1,134 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wycheproof
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aead/subtle"
+)
+
+const bitsPerByte = 8
+
+type aeadGroup struct {
+	IVSize  int          `json:"ivSize"`
+	TagSize int          `json:"tagSize"`
+	Tests   []aeadVector `json:"tests"`
+}
+
+type aeadVector struct {
+	testVector
+	Key hexBytes `json:"key"`
+	IV  hexBytes `json:"iv"`
+	AAD hexBytes `json:"aad"`
+	Msg hexBytes `json:"msg"`
+	CT  hexBytes `json:"ct"`
+	Tag hexBytes `json:"tag"`
+}
+
+// detachedAEAD is an AEAD with explicit nonces, as subtle.DetachedAEAD and subtle.AESCBCHMAC.
+type detachedAEAD interface {
+	EncryptWithNonce(plaintext, additionalData, nonce []byte) ([]byte, error)
+	DecryptDetached(ciphertext, additionalData, nonce []byte) ([]byte, error)
+}
+
+type aeadAlgorithm struct {
+	newAEAD   func(key []byte) (detachedAEAD, error)
+	nonceSize int
+	// tagSize is the tag size of the algorithm, or 0 for the algorithms with a tag size depending on the key size.
+	tagSize int
+}
+
+//nolint:gochecknoglobals
+var aeadAlgorithms = map[string]aeadAlgorithm{
+	"AES-GCM": {
+		newAEAD:   func(key []byte) (detachedAEAD, error) { return subtle.NewAESGCMDetached(key) },
+		nonceSize: subtle.AESGCMIVSize,
+		tagSize:   subtle.AESGCMTagSize,
+	},
+	"CHACHA20-POLY1305": {
+		newAEAD:   func(key []byte) (detachedAEAD, error) { return subtle.NewChaCha20Poly1305Detached(key) },
+		nonceSize: chacha20poly1305.NonceSize,
+		tagSize:   chacha20poly1305.Overhead,
+	},
+	"XCHACHA20-POLY1305": {
+		newAEAD:   func(key []byte) (detachedAEAD, error) { return subtle.NewXChaCha20Poly1305Detached(key) },
+		nonceSize: chacha20poly1305.NonceSizeX,
+		tagSize:   chacha20poly1305.Overhead,
+	},
+	"A128CBC-HS256": {newAEAD: newAESCBCHMAC, nonceSize: subtle.AESCBCIVSize},
+	"A192CBC-HS384": {newAEAD: newAESCBCHMAC, nonceSize: subtle.AESCBCIVSize},
+	"A256CBC-HS512": {newAEAD: newAESCBCHMAC, nonceSize: subtle.AESCBCIVSize},
+}
+
+func newAESCBCHMAC(key []byte) (detachedAEAD, error) {
+	return subtle.NewAESCBCHMAC(key)
+}
+
+// validateAEAD decrypts the ciphertexts of the vectors, and encrypts the messages of the valid vectors with their IV.
+// The groups of IV or tag sizes the algorithm doesn't support are skipped.
+func validateAEAD(_ *options, algorithm string, groups json.RawMessage, res *Result) error {
+	alg, ok := aeadAlgorithms[algorithm]
+	if !ok {
+		return fmt.Errorf("%w: aead algorithm '%s'", ErrUnsupported, algorithm)
+	}
+
+	var aeadGroups []aeadGroup
+
+	if err := json.Unmarshal(groups, &aeadGroups); err != nil {
+		return fmt.Errorf("aead groups: %w", err)
+	}
+
+	for _, g := range aeadGroups {
+		if g.IVSize != alg.nonceSize*bitsPerByte || (alg.tagSize != 0 && g.TagSize != alg.tagSize*bitsPerByte) {
+			res.Skipped += len(g.Tests)
+
+			continue
+		}
+
+		for i := range g.Tests {
+			v := &g.Tests[i]
+
+			res.check(&v.testVector, checkAEAD(alg, v))
+		}
+	}
+
+	return nil
+}
+
+func checkAEAD(alg aeadAlgorithm, v *aeadVector) error {
+	a, err := alg.newAEAD(v.Key)
+	if err != nil {
+		return err
+	}
+
+	ct := append(append([]byte{}, v.CT...), v.Tag...)
+
+	pt, err := a.DecryptDetached(ct, v.AAD, v.IV)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(pt, v.Msg) {
+		return fmt.Errorf("decrypt: %w", errMismatch)
+	}
+
+	encrypted, err := a.EncryptWithNonce(v.Msg, v.AAD, v.IV)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(encrypted, ct) {
+		return fmt.Errorf("encrypt: %w", errMismatch)
+	}
+
+	return nil
+}
@@ -0,0 +1,208 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wycheproof
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+const uncompressedPointPrefix = 4
+
+// ecdhCurves are the curves and the ECDH-KW key types of the NIST P curves, by Wycheproof curve name.
+//
+//nolint:gochecknoglobals
+var ecdhCurves = map[string]struct {
+	curve elliptic.Curve
+	kt    kmsapi.KeyType
+}{
+	"secp256r1": {curve: elliptic.P256(), kt: kmsapi.NISTP256ECDHKWType},
+	"secp384r1": {curve: elliptic.P384(), kt: kmsapi.NISTP384ECDHKWType},
+	"secp521r1": {curve: elliptic.P521(), kt: kmsapi.NISTP521ECDHKWType},
+}
+
+type ecdhGroup struct {
+	Curve string `json:"curve"`
+	// Encoding is the encoding of the public keys, asn (ecdh_test_schema.json) or ecpoint
+	// (ecdh_ecpoint_test_schema.json).
+	Encoding string       `json:"encoding"`
+	Tests    []ecdhVector `json:"tests"`
+}
+
+type ecdhVector struct {
+	testVector
+	Public  hexBytes `json:"public"`
+	Private hexBytes `json:"private"`
+	Shared  hexBytes `json:"shared"`
+}
+
+func dhComputer(opts *options) (cryptoapi.DHComputer, error) {
+	c, ok := opts.crypto.(cryptoapi.DHComputer)
+	if !ok {
+		return nil, errors.New("crypto doesn't compute ECDH shared secrets")
+	}
+
+	return c, nil
+}
+
+// validateECDH imports the private key of each NIST P curve vector in the KeyManager and computes its shared secret
+// with the ASN.1 or uncompressed point public key of the vector. The groups of other curves are skipped.
+func validateECDH(opts *options, algorithm string, groups json.RawMessage, res *Result) error {
+	dh, err := dhComputer(opts)
+	if err != nil {
+		return err
+	}
+
+	var ecdhGroups []ecdhGroup
+
+	if err = json.Unmarshal(groups, &ecdhGroups); err != nil {
+		return fmt.Errorf("%s groups: %w", algorithm, err)
+	}
+
+	for _, g := range ecdhGroups {
+		c, ok := ecdhCurves[g.Curve]
+		if !ok {
+			res.Skipped += len(g.Tests)
+
+			continue
+		}
+
+		for i := range g.Tests {
+			v := &g.Tests[i]
+
+			res.check(&v.testVector, checkECDH(opts.km, dh, c.curve, c.kt, g.Encoding, v))
+		}
+	}
+
+	return nil
+}
+
+func checkECDH(km kmsapi.KeyManager, dh cryptoapi.DHComputer, curve elliptic.Curve, kt kmsapi.KeyType,
+	encoding string, v *ecdhVector) error {
+	pubKey, err := ecdhPublicKey(curve, encoding, v.Public)
+	if err != nil {
+		return err
+	}
+
+	d := new(big.Int).SetBytes(v.Private)
+	privKey := &ecdsa.PrivateKey{PublicKey: ecdsa.PublicKey{Curve: curve}, D: d}
+	privKey.X, privKey.Y = curve.ScalarBaseMult(d.Bytes())
+
+	_, kh, err := km.ImportPrivateKey(privKey, kt)
+	if err != nil {
+		return fmt.Errorf("import private key: %w", err)
+	}
+
+	return checkShared(dh, kh, pubKey, v.Shared)
+}
+
+// ecdhPublicKey returns the public key of the ASN.1 or uncompressed point encoded pub. The public key is not checked
+// to be on curve: the ECDH of the Crypto must reject the invalid points.
+func ecdhPublicKey(curve elliptic.Curve, encoding string, pub []byte) (*cryptoapi.PublicKey, error) {
+	switch encoding {
+	case "asn":
+		key, err := x509.ParsePKIXPublicKey(pub)
+		if err != nil {
+			return nil, err
+		}
+
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, errors.New("public key is not an EC key")
+		}
+
+		return &cryptoapi.PublicKey{
+			Type:  ecKeyType,
+			Curve: ecKey.Curve.Params().Name,
+			X:     ecKey.X.Bytes(),
+			Y:     ecKey.Y.Bytes(),
+		}, nil
+	case "ecpoint":
+		size := (curve.Params().BitSize + bitsPerByte - 1) / bitsPerByte
+
+		if len(pub) != 1+2*size || pub[0] != uncompressedPointPrefix {
+			return nil, errors.New("public key is not an uncompressed point")
+		}
+
+		return &cryptoapi.PublicKey{
+			Type:  ecKeyType,
+			Curve: curve.Params().Name,
+			X:     pub[1 : 1+size],
+			Y:     pub[1+size:],
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: public key encoding '%s'", ErrUnsupported, encoding)
+	}
+}
+
+// validateXDH imports the private key of each X25519 vector in the KeyManager and computes its shared secret with the
+// public key of the vector. The X448 groups are skipped: X448 private keys can't be imported.
+func validateXDH(opts *options, algorithm string, groups json.RawMessage, res *Result) error {
+	dh, err := dhComputer(opts)
+	if err != nil {
+		return err
+	}
+
+	var xdhGroups []ecdhGroup
+
+	if err = json.Unmarshal(groups, &xdhGroups); err != nil {
+		return fmt.Errorf("%s groups: %w", algorithm, err)
+	}
+
+	for _, g := range xdhGroups {
+		if g.Curve != "curve25519" {
+			res.Skipped += len(g.Tests)
+
+			continue
+		}
+
+		for i := range g.Tests {
+			v := &g.Tests[i]
+
+			res.check(&v.testVector, checkX25519(opts.km, dh, v))
+		}
+	}
+
+	return nil
+}
+
+func checkX25519(km kmsapi.KeyManager, dh cryptoapi.DHComputer, v *ecdhVector) error {
+	privKey, err := ecdh.X25519().NewPrivateKey(v.Private)
+	if err != nil {
+		return err
+	}
+
+	_, kh, err := km.ImportPrivateKey(privKey, kmsapi.X25519ECDHKWType)
+	if err != nil {
+		return fmt.Errorf("import private key: %w", err)
+	}
+
+	return checkShared(dh, kh, &cryptoapi.PublicKey{Type: okpKeyType, Curve: "X25519", X: v.Public}, v.Shared)
+}
+
+func checkShared(dh cryptoapi.DHComputer, kh interface{}, pubKey *cryptoapi.PublicKey, shared []byte) error {
+	z, err := dh.ComputeDH(kh, pubKey)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(z, shared) {
+		return fmt.Errorf("shared secret: %w", errMismatch)
+	}
+
+	return nil
+}
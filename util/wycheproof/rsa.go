@@ -0,0 +1,185 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wycheproof
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+const (
+	// the RSA key types use 2048 bits or more moduli, the 65537 public exponent and SHA-256.
+	rsaMinKeySize = 2048
+	rsaExponent   = 65537
+	rsaSHA        = "SHA-256"
+	rsaMGF        = "MGF1"
+	// PS256 uses salts as long as the SHA-256 digest.
+	rsaPSSSaltLength = 32
+)
+
+type rsaGroup struct {
+	KeySize int      `json:"keySize"`
+	E       hexBytes `json:"e"`
+	// KeyDer and PublicKeyDer are the DER public key of the signature groups, before and in testvectors_v1.
+	KeyDer          hexBytes    `json:"keyDer"`
+	PublicKeyDer    hexBytes    `json:"publicKeyDer"`
+	PrivateKeyPkcs8 hexBytes    `json:"privateKeyPkcs8"`
+	SHA             string      `json:"sha"`
+	MGF             string      `json:"mgf"`
+	MGFSHA          string      `json:"mgfSha"`
+	SLen            int         `json:"sLen"`
+	Tests           []rsaVector `json:"tests"`
+}
+
+type rsaVector struct {
+	signatureVector
+	CT    hexBytes `json:"ct"`
+	Label hexBytes `json:"label"`
+}
+
+// supported returns true if the key and hash functions of g are the ones of the RSA key types.
+func (g *rsaGroup) supported() bool {
+	return g.KeySize >= rsaMinKeySize && new(big.Int).SetBytes(g.E).Int64() == rsaExponent && g.SHA == rsaSHA &&
+		(g.MGF == "" || (g.MGF == rsaMGF && g.MGFSHA == rsaSHA))
+}
+
+func (g *rsaGroup) publicKeyDer() []byte {
+	if len(g.PublicKeyDer) != 0 {
+		return g.PublicKeyDer
+	}
+
+	return g.KeyDer
+}
+
+func unmarshalRSAGroups(algorithm string, groups json.RawMessage) ([]rsaGroup, error) {
+	var rsaGroups []rsaGroup
+
+	if err := json.Unmarshal(groups, &rsaGroups); err != nil {
+		return nil, fmt.Errorf("%s groups: %w", algorithm, err)
+	}
+
+	return rsaGroups, nil
+}
+
+// validateRSAPKCS1 verifies the signatures of the RSA PKCS #1 v1.5 vectors with the RS256 public keys of their groups.
+// The groups of keys or hash functions not supported by RS256 are skipped.
+func validateRSAPKCS1(opts *options, algorithm string, groups json.RawMessage, res *Result) error {
+	return validateRSASignatures(opts, algorithm, groups, kmsapi.RSARS256Type, res)
+}
+
+// validateRSAPSS verifies the signatures of the RSA-PSS vectors with the PS256 public keys of their groups. The groups
+// of keys, hash functions or salt lengths not supported by PS256 are skipped.
+func validateRSAPSS(opts *options, algorithm string, groups json.RawMessage, res *Result) error {
+	return validateRSASignatures(opts, algorithm, groups, kmsapi.RSAPS256Type, res)
+}
+
+func validateRSASignatures(opts *options, algorithm string, groups json.RawMessage, kt kmsapi.KeyType,
+	res *Result) error {
+	rsaGroups, err := unmarshalRSAGroups(algorithm, groups)
+	if err != nil {
+		return err
+	}
+
+	for _, g := range rsaGroups {
+		if !g.supported() || (kt == kmsapi.RSAPS256Type && g.SLen != rsaPSSSaltLength) {
+			res.Skipped += len(g.Tests)
+
+			continue
+		}
+
+		kh, e := opts.km.PubKeyBytesToHandle(g.publicKeyDer(), kt)
+		if e != nil {
+			return fmt.Errorf("%s public key: %w", algorithm, e)
+		}
+
+		for i := range g.Tests {
+			v := &g.Tests[i]
+
+			res.check(&v.testVector, opts.crypto.Verify(v.Sig, v.Msg, kh))
+		}
+	}
+
+	return nil
+}
+
+// validateRSAOAEP imports the private keys of the RSA-OAEP groups in the KeyManager and unwraps the ciphertexts of
+// their vectors with the RSA-OAEP-256 key wrapping algorithm. The groups of keys or hash functions not supported by
+// RSA-OAEP-256, and the vectors with a label, are skipped.
+func validateRSAOAEP(opts *options, algorithm string, groups json.RawMessage, res *Result) error {
+	rsaGroups, err := unmarshalRSAGroups(algorithm, groups)
+	if err != nil {
+		return err
+	}
+
+	for _, g := range rsaGroups {
+		if !g.supported() {
+			res.Skipped += len(g.Tests)
+
+			continue
+		}
+
+		kh, e := importRSAPrivateKey(opts.km, g.PrivateKeyPkcs8)
+		if e != nil {
+			return fmt.Errorf("%s private key: %w", algorithm, e)
+		}
+
+		for i := range g.Tests {
+			v := &g.Tests[i]
+
+			if len(v.Label) != 0 {
+				res.Skipped++
+
+				continue
+			}
+
+			res.check(&v.testVector, checkRSAOAEP(opts.crypto, kh, v.CT, v.Msg))
+		}
+	}
+
+	return nil
+}
+
+func importRSAPrivateKey(km kmsapi.KeyManager, der []byte) (interface{}, error) {
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+
+	_, kh, err := km.ImportPrivateKey(rsaKey, kmsapi.RSAOAEP256Type)
+	if err != nil {
+		return nil, err
+	}
+
+	return kh, nil
+}
+
+func checkRSAOAEP(c cryptoapi.Crypto, kh interface{}, ct, msg []byte) error {
+	pt, err := c.UnwrapKey(&cryptoapi.RecipientWrappedKey{Alg: tinkcrypto.RSAOAEP256Alg, EncryptedCEK: ct}, kh)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(pt, msg) {
+		return fmt.Errorf("decrypt: %w", errMismatch)
+	}
+
+	return nil
+}
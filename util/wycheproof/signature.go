@@ -0,0 +1,145 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wycheproof
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+)
+
+const (
+	ecKeyType  = "EC"
+	okpKeyType = "OKP"
+)
+
+// ecdsaCurves are the JWK curve names and the hash functions of the JWS algorithms of the NIST P curves, by Wycheproof
+// curve name.
+//
+//nolint:gochecknoglobals
+var ecdsaCurves = map[string]struct{ crv, sha string }{
+	"secp256r1": {crv: "P-256", sha: "SHA-256"},
+	"secp384r1": {crv: "P-384", sha: "SHA-384"},
+	"secp521r1": {crv: "P-521", sha: "SHA-512"},
+}
+
+type signatureVector struct {
+	testVector
+	Msg hexBytes `json:"msg"`
+	Sig hexBytes `json:"sig"`
+}
+
+// publicKey is the public key of the signature groups, the key field of the Wycheproof vectors before the
+// testvectors_v1 publicKey field.
+type publicKey struct {
+	Curve string   `json:"curve"`
+	PK    hexBytes `json:"pk"`
+	WX    hexBytes `json:"wx"`
+	WY    hexBytes `json:"wy"`
+}
+
+type signatureGroup struct {
+	Key       *publicKey        `json:"key"`
+	PublicKey *publicKey        `json:"publicKey"`
+	SHA       string            `json:"sha"`
+	Tests     []signatureVector `json:"tests"`
+}
+
+func (g *signatureGroup) publicKey() *publicKey {
+	if g.PublicKey != nil {
+		return g.PublicKey
+	}
+
+	if g.Key != nil {
+		return g.Key
+	}
+
+	return &publicKey{}
+}
+
+func publicKeyVerifier(opts *options) (cryptoapi.PublicKeyVerifier, error) {
+	v, ok := opts.crypto.(cryptoapi.PublicKeyVerifier)
+	if !ok {
+		return nil, errors.New("crypto doesn't verify signatures with public keys")
+	}
+
+	return v, nil
+}
+
+// validateEdDSA verifies the signatures of the Ed25519 vectors with the public keys of their groups. The Ed448 groups
+// are skipped.
+func validateEdDSA(opts *options, algorithm string, groups json.RawMessage, res *Result) error {
+	verifier, err := publicKeyVerifier(opts)
+	if err != nil {
+		return err
+	}
+
+	var sigGroups []signatureGroup
+
+	if err = json.Unmarshal(groups, &sigGroups); err != nil {
+		return fmt.Errorf("%s groups: %w", algorithm, err)
+	}
+
+	for _, g := range sigGroups {
+		key := g.publicKey()
+
+		if key.Curve != "edwards25519" {
+			res.Skipped += len(g.Tests)
+
+			continue
+		}
+
+		pubKey := &cryptoapi.PublicKey{Type: okpKeyType, Curve: "Ed25519", X: key.PK}
+
+		for i := range g.Tests {
+			v := &g.Tests[i]
+
+			res.check(&v.testVector, verifier.VerifyWithPublicKey(v.Sig, v.Msg, pubKey))
+		}
+	}
+
+	return nil
+}
+
+// validateECDSA verifies the DER or IEEE P1363 signatures of the ECDSA vectors with the public keys of their groups.
+// The groups of other curves than the NIST P curves, or other hash functions than the one of the JWS algorithm of their
+// curve, are skipped.
+func validateECDSA(opts *options, algorithm string, groups json.RawMessage, res *Result) error {
+	verifier, err := publicKeyVerifier(opts)
+	if err != nil {
+		return err
+	}
+
+	var sigGroups []signatureGroup
+
+	if err = json.Unmarshal(groups, &sigGroups); err != nil {
+		return fmt.Errorf("%s groups: %w", algorithm, err)
+	}
+
+	for _, g := range sigGroups {
+		key := g.publicKey()
+
+		curve, ok := ecdsaCurves[key.Curve]
+		if !ok || curve.sha != g.SHA {
+			res.Skipped += len(g.Tests)
+
+			continue
+		}
+
+		pubKey := &cryptoapi.PublicKey{Type: ecKeyType, Curve: curve.crv, X: key.WX, Y: key.WY}
+
+		for i := range g.Tests {
+			v := &g.Tests[i]
+
+			res.check(&v.testVector, verifier.VerifyWithPublicKey(v.Sig, v.Msg, pubKey))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,287 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package wycheproof validates the primitives of the module with the Google Wycheproof test vectors
+// (https://github.com/C2SP/wycheproof). ValidateVectors reads a Wycheproof vectors file and runs its vectors against:
+//
+//   - the AES-GCM, ChaCha20Poly1305, XChaCha20Poly1305 and AES-CBC-HMAC AEADs of
+//     crypto/tinkcrypto/primitive/aead/subtle (aead_test_schema.json),
+//   - the Ed25519 and NIST P curves ECDSA verification of a Crypto (eddsa_verify_schema.json,
+//     ecdsa_verify_schema.json and ecdsa_p1363_verify_schema.json),
+//   - the NIST P curves and X25519 ECDH of a Crypto, with private keys imported in a KeyManager
+//     (ecdh_test_schema.json, ecdh_ecpoint_test_schema.json and xdh_comp_schema.json),
+//   - the RSA PKCS #1 v1.5 and PSS verification and the RSA-OAEP decryption of a Crypto
+//     (rsassa_pkcs1_verify_schema.json, rsassa_pss_verify_schema.json and rsaes_oaep_decrypt_schema.json).
+//
+// The Crypto and KeyManager are tinkcrypto and localkms by default. Forks changing the primitives of the module, or
+// providing their own Crypto and KeyManager implementations (see WithCrypto and WithKeyManager), can re-verify them
+// with the vector files of a Wycheproof checkout.
+//
+// Wycheproof has no AES-CBC-HMAC (RFC 7518 A128CBC-HS256, A192CBC-HS384 and A256CBC-HS512) vectors: the aead vectors
+// of these algorithms are read with the same schema. The vectors of parameters not supported by the module, as AES-GCM
+// IVs of other sizes than 12 bytes or ECDSA signatures with another hash function than the one of the JWS algorithm of
+// the curve, are skipped.
+package wycheproof
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/kms/localkms"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/spi/secretlock"
+)
+
+const (
+	resultValid      = "valid"
+	resultAcceptable = "acceptable"
+
+	masterKeyURI = "local-lock://wycheproof/master/key/"
+)
+
+var (
+	// ErrUnsupported is returned by ValidateVectors for the vectors files of schemas or algorithms that are not
+	// validated.
+	ErrUnsupported = errors.New("unsupported vectors")
+	// ErrVectorsFailed is returned by Result.Err if vectors failed.
+	ErrVectorsFailed = errors.New("wycheproof vectors failed")
+
+	errMismatch = errors.New("output mismatch")
+)
+
+// Result is the result of the validation of a vectors file.
+type Result struct {
+	Algorithm string
+	// Passed is the number of valid vectors that passed, invalid vectors that were rejected and acceptable vectors.
+	Passed int
+	// Skipped is the number of vectors of parameters not supported by the module.
+	Skipped  int
+	Failures []Failure
+}
+
+// Failure is a vector that failed: a valid vector that was rejected or an invalid vector that passed.
+type Failure struct {
+	TcID    int
+	Comment string
+	// Result is the expected result of the vector, valid or invalid.
+	Result string
+	// Err is the error of the rejected valid vector, nil for an invalid vector.
+	Err error
+}
+
+// Err returns ErrVectorsFailed with the failed vectors if r has failures, or nil.
+func (r *Result) Err() error {
+	if len(r.Failures) == 0 {
+		return nil
+	}
+
+	failures := make([]string, len(r.Failures))
+
+	for i, f := range r.Failures {
+		failures[i] = fmt.Sprintf("tcId %d (%s, expected %s): %v", f.TcID, f.Comment, f.Result, f.Err)
+	}
+
+	return fmt.Errorf("%w: %s: %d of %d vectors: %s", ErrVectorsFailed, r.Algorithm, len(r.Failures),
+		r.Passed+len(r.Failures), strings.Join(failures, "; "))
+}
+
+// check records the outcome err of v: valid vectors must pass, invalid vectors must be rejected and acceptable vectors
+// may do either.
+func (r *Result) check(v *testVector, err error) {
+	if v.Result == resultAcceptable || (err == nil) == (v.Result == resultValid) {
+		r.Passed++
+
+		return
+	}
+
+	r.Failures = append(r.Failures, Failure{TcID: v.TcID, Comment: v.Comment, Result: v.Result, Err: err})
+}
+
+// Opt is an option of ValidateVectors.
+type Opt func(opts *options)
+
+type options struct {
+	crypto cryptoapi.Crypto
+	km     kmsapi.KeyManager
+}
+
+// WithCrypto validates the signature verification, ECDH and RSA-OAEP decryption of c instead of tinkcrypto (created
+// WithComputeDH). c must be a cryptoapi.PublicKeyVerifier to validate the EdDSA and ECDSA vectors and a
+// cryptoapi.DHComputer with ComputeDH enabled to validate the ECDH vectors.
+func WithCrypto(c cryptoapi.Crypto) Opt {
+	return func(opts *options) {
+		opts.crypto = c
+	}
+}
+
+// WithKeyManager imports the keys of the ECDH and RSA vectors in km instead of an in-memory localkms.
+func WithKeyManager(km kmsapi.KeyManager) Opt {
+	return func(opts *options) {
+		opts.km = km
+	}
+}
+
+type vectorsFile struct {
+	Algorithm  string          `json:"algorithm"`
+	Schema     string          `json:"schema"`
+	TestGroups json.RawMessage `json:"testGroups"`
+}
+
+type testVector struct {
+	TcID    int    `json:"tcId"`
+	Comment string `json:"comment"`
+	Result  string `json:"result"`
+}
+
+// hexBytes are the hex encoded bytes of the vectors.
+type hexBytes []byte
+
+func (h *hexBytes) UnmarshalJSON(data []byte) error {
+	var s string
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+
+	*h = b
+
+	return nil
+}
+
+type validateFunc func(opts *options, algorithm string, groups json.RawMessage, res *Result) error
+
+// validators are the vectors validations by schema name, without the .json extension and the _v1 suffix of the
+// testvectors_v1 files.
+//
+//nolint:gochecknoglobals
+var validators = map[string]validateFunc{
+	"aead_test_schema":           validateAEAD,
+	"eddsa_verify_schema":        validateEdDSA,
+	"ecdsa_verify_schema":        validateECDSA,
+	"ecdsa_p1363_verify_schema":  validateECDSA,
+	"ecdh_test_schema":           validateECDH,
+	"ecdh_ecpoint_test_schema":   validateECDH,
+	"xdh_comp_schema":            validateXDH,
+	"rsassa_pkcs1_verify_schema": validateRSAPKCS1,
+	"rsassa_pss_verify_schema":   validateRSAPSS,
+	"rsaes_oaep_decrypt_schema":  validateRSAOAEP,
+}
+
+// ValidateVectors runs the vectors of the Wycheproof vectors file r. It returns ErrUnsupported for the files of other
+// schemas or algorithms, and the Result of the vectors otherwise: Result.Err returns an error if vectors failed.
+func ValidateVectors(r io.Reader, opts ...Opt) (*Result, error) {
+	var f vectorsFile
+
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return nil, fmt.Errorf("validate vectors: %w", err)
+	}
+
+	validate, ok := validators[strings.TrimSuffix(strings.TrimSuffix(f.Schema, ".json"), "_v1")]
+	if !ok {
+		return nil, fmt.Errorf("validate vectors: %w: schema '%s'", ErrUnsupported, f.Schema)
+	}
+
+	o := &options{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if err := o.setDefaults(); err != nil {
+		return nil, fmt.Errorf("validate vectors: %w", err)
+	}
+
+	res := &Result{Algorithm: f.Algorithm}
+
+	if err := validate(o, f.Algorithm, f.TestGroups, res); err != nil {
+		return nil, fmt.Errorf("validate vectors: %w", err)
+	}
+
+	return res, nil
+}
+
+func (o *options) setDefaults() error {
+	if o.crypto == nil {
+		c, err := tinkcrypto.New(tinkcrypto.WithComputeDH())
+		if err != nil {
+			return err
+		}
+
+		o.crypto = c
+	}
+
+	if o.km == nil {
+		km, err := localkms.New(masterKeyURI, &kmsProvider{store: &memStore{keys: map[string][]byte{}}})
+		if err != nil {
+			return err
+		}
+
+		o.km = km
+	}
+
+	return nil
+}
+
+type kmsProvider struct {
+	store kmsapi.Store
+}
+
+func (p *kmsProvider) StorageProvider() kmsapi.Store {
+	return p.store
+}
+
+func (p *kmsProvider) SecretLock() secretlock.Service {
+	return &noop.NoLock{}
+}
+
+// memStore is the in-memory store of the keys of the vectors.
+type memStore struct {
+	mu   sync.RWMutex
+	keys map[string][]byte
+}
+
+func (s *memStore) Put(keysetID string, key []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[keysetID] = key
+
+	return nil
+}
+
+func (s *memStore) Get(keysetID string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.keys[keysetID]
+	if !ok {
+		return nil, kmsapi.ErrKeyNotFound
+	}
+
+	return key, nil
+}
+
+func (s *memStore) Delete(keysetID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.keys, keysetID)
+
+	return nil
+}
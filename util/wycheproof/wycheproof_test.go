@@ -0,0 +1,368 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wycheproof_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/aead/subtle"
+	"github.com/trustbloc/kms-go/util/wycheproof"
+)
+
+type vector map[string]interface{}
+
+// TestValidateVectors_Testdata validates the Wycheproof vectors files vendored in testdata, with the number of vectors
+// passed and skipped for the parameters not supported by the module.
+func TestValidateVectors_Testdata(t *testing.T) {
+	for file, want := range map[string]struct{ passed, skipped int }{
+		"ed25519_test.json":                      {passed: 145},
+		"x25519_test.json":                       {passed: 87},
+		"a128cbc_hs256_test.json":                {passed: 7},
+		"a192cbc_hs384_test.json":                {passed: 7},
+		"a256cbc_hs512_test.json":                {passed: 7},
+		"aes_gcm_test.json":                      {passed: 143, skipped: 113},
+		"ecdsa_secp256r1_sha256_test.json":       {passed: 387},
+		"ecdsa_secp256r1_sha256_p1363_test.json": {passed: 219},
+		"ecdh_secp256r1_test.json":               {passed: 460},
+		"rsa_signature_2048_sha256_test.json":    {passed: 238, skipped: 2},
+		"rsa_pss_2048_sha256_mgf1_32_test.json":  {passed: 103},
+	} {
+		t.Run(file, func(t *testing.T) {
+			f, err := os.Open(filepath.Join("testdata", file))
+			require.NoError(t, err)
+
+			defer func() { require.NoError(t, f.Close()) }()
+
+			res, err := wycheproof.ValidateVectors(f)
+			require.NoError(t, err)
+			require.NoError(t, res.Err())
+			require.Equal(t, want.passed, res.Passed)
+			require.Equal(t, want.skipped, res.Skipped)
+		})
+	}
+}
+
+// TestValidateVectors_Wycheproof validates the vectors files of the Wycheproof checkout in the WYCHEPROOF_DIR
+// environment variable, in addition to the vectors vendored in testdata.
+func TestValidateVectors_Wycheproof(t *testing.T) {
+	dir := os.Getenv("WYCHEPROOF_DIR")
+	if dir == "" {
+		t.Skip("WYCHEPROOF_DIR is not set")
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".json" {
+			return err
+		}
+
+		t.Run(path, func(t *testing.T) {
+			f, e := os.Open(path) //nolint:gosec // vector files of the Wycheproof checkout.
+			require.NoError(t, e)
+
+			defer func() { require.NoError(t, f.Close()) }()
+
+			res, e := wycheproof.ValidateVectors(f)
+			if errors.Is(e, wycheproof.ErrUnsupported) {
+				t.Skip(e)
+			}
+
+			require.NoError(t, e)
+			require.NoError(t, res.Err())
+		})
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestValidateVectors_AEAD(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	iv := bytes.Repeat([]byte{2}, subtle.AESGCMIVSize)
+	msg, aad := []byte("message"), []byte("aad")
+
+	gcm, err := subtle.NewAESGCMDetached(key)
+	require.NoError(t, err)
+
+	ct, err := gcm.EncryptWithNonce(msg, aad, iv)
+	require.NoError(t, err)
+
+	tagOffset := len(ct) - subtle.AESGCMTagSize
+	valid := vector{"key": key, "iv": iv, "aad": aad, "msg": msg, "ct": ct[:tagOffset], "tag": ct[tagOffset:]}
+
+	res := validate(t, "AES-GCM", "aead_test_schema.json",
+		group{"ivSize": 96, "tagSize": 128}, valid, modified(valid, "tag"), modified(valid, "aad"))
+	require.NoError(t, res.Err())
+	require.Equal(t, 3, res.Passed)
+
+	res = validate(t, "AES-GCM", "aead_test_schema.json", group{"ivSize": 128, "tagSize": 128}, valid)
+	require.Equal(t, 1, res.Skipped)
+
+	res = validate(t, "AES-GCM", "aead_test_schema.json", group{"ivSize": 96, "tagSize": 128},
+		withResult(modified(valid, "ct"), "valid"))
+	require.ErrorIs(t, res.Err(), wycheproof.ErrVectorsFailed)
+	require.Len(t, res.Failures, 1)
+	require.Equal(t, 1, res.Failures[0].TcID)
+	require.Error(t, res.Failures[0].Err)
+
+	_, err = wycheproof.ValidateVectors(vectorsFile(t, "AES-SIV-CMAC", "aead_test_schema.json", group{}))
+	require.ErrorIs(t, err, wycheproof.ErrUnsupported)
+}
+
+func TestValidateVectors_ECDSA(t *testing.T) {
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	msg := []byte("message")
+	digest := sha256.Sum256(msg)
+
+	derSig, err := ecdsa.SignASN1(rand.Reader, privKey, digest[:])
+	require.NoError(t, err)
+
+	r, s, err := ecdsa.Sign(rand.Reader, privKey, digest[:])
+	require.NoError(t, err)
+
+	p1363Sig := append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+
+	key := map[string]interface{}{
+		"curve": "secp256r1",
+		"wx":    hex.EncodeToString(privKey.X.Bytes()),
+		"wy":    hex.EncodeToString(privKey.Y.Bytes()),
+	}
+
+	for schema, sig := range map[string][]byte{
+		"ecdsa_verify_schema.json":          derSig,
+		"ecdsa_p1363_verify_schema_v1.json": p1363Sig,
+	} {
+		valid := vector{"msg": msg, "sig": sig}
+
+		res := validate(t, "ECDSA", schema, group{"publicKey": key, "sha": "SHA-256"}, valid, modified(valid, "sig"),
+			modified(valid, "msg"))
+		require.NoError(t, res.Err(), schema)
+		require.Equal(t, 3, res.Passed, schema)
+
+		res = validate(t, "ECDSA", schema, group{"key": key, "sha": "SHA-512"}, valid)
+		require.Equal(t, 1, res.Skipped, schema)
+	}
+}
+
+func TestValidateVectors_ECDH(t *testing.T) {
+	privKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	peer, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	asnPub, err := x509.MarshalPKIXPublicKey(&peer.PublicKey)
+	require.NoError(t, err)
+
+	ecdhPeer, err := peer.PublicKey.ECDH()
+	require.NoError(t, err)
+
+	ecdhPrivKey, err := privKey.ECDH()
+	require.NoError(t, err)
+
+	shared, err := ecdhPrivKey.ECDH(ecdhPeer)
+	require.NoError(t, err)
+
+	offCurve := append([]byte{}, ecdhPeer.Bytes()...)
+	offCurve[len(offCurve)-1] ^= 1
+
+	for encoding, pub := range map[string][]byte{"asn": asnPub, "ecpoint": ecdhPeer.Bytes()} {
+		valid := vector{"public": pub, "private": privKey.D.Bytes(), "shared": shared}
+		invalid := vector{"public": offCurve, "private": privKey.D.Bytes(), "shared": shared}
+
+		res := validate(t, "ECDH", "ecdh_test_schema.json", group{"curve": "secp384r1", "encoding": encoding},
+			valid, withResult(invalid, "invalid"), modified(valid, "shared"))
+		require.NoError(t, res.Err(), encoding)
+		require.Equal(t, 3, res.Passed, encoding)
+	}
+
+	res := validate(t, "ECDH", "ecdh_test_schema.json", group{"curve": "secp256k1", "encoding": "asn"}, vector{})
+	require.Equal(t, 1, res.Skipped)
+
+	x25519Key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	x25519Peer, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	shared, err = x25519Key.ECDH(x25519Peer.PublicKey())
+	require.NoError(t, err)
+
+	valid := vector{"public": x25519Peer.PublicKey().Bytes(), "private": x25519Key.Bytes(), "shared": shared}
+
+	res = validate(t, "XDH", "xdh_comp_schema.json", group{"curve": "curve25519"}, valid, modified(valid, "shared"),
+		withResult(vector{"public": make([]byte, 32), "private": x25519Key.Bytes(), "shared": make([]byte, 32)},
+			"acceptable"))
+	require.NoError(t, res.Err())
+	require.Equal(t, 3, res.Passed)
+
+	res = validate(t, "XDH", "xdh_comp_schema.json", group{"curve": "curve448"}, valid)
+	require.Equal(t, 1, res.Skipped)
+}
+
+func TestValidateVectors_RSA(t *testing.T) {
+	privKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	pubKeyDer, err := x509.MarshalPKIXPublicKey(&privKey.PublicKey)
+	require.NoError(t, err)
+
+	msg := []byte("message")
+	digest := sha256.Sum256(msg)
+
+	rsaGroup := func(g group) group {
+		g["keySize"] = 2048
+		g["e"] = hex.EncodeToString(big.NewInt(int64(privKey.E)).Bytes())
+		g["sha"] = "SHA-256"
+
+		return g
+	}
+
+	t.Run("PKCS #1 v1.5 signatures", func(t *testing.T) {
+		sig, e := rsa.SignPKCS1v15(rand.Reader, privKey, crypto.SHA256, digest[:])
+		require.NoError(t, e)
+
+		valid := vector{"msg": msg, "sig": sig}
+
+		res := validate(t, "RSASSA-PKCS1-v1_5", "rsassa_pkcs1_verify_schema.json",
+			rsaGroup(group{"keyDer": hex.EncodeToString(pubKeyDer)}), valid, modified(valid, "sig"))
+		require.NoError(t, res.Err())
+		require.Equal(t, 2, res.Passed)
+	})
+
+	t.Run("PSS signatures", func(t *testing.T) {
+		sig, e := rsa.SignPSS(rand.Reader, privKey, crypto.SHA256, digest[:],
+			&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		require.NoError(t, e)
+
+		valid := vector{"msg": msg, "sig": sig}
+		pssGroup := func(sLen int) group {
+			return rsaGroup(group{
+				"publicKeyDer": hex.EncodeToString(pubKeyDer), "mgf": "MGF1", "mgfSha": "SHA-256", "sLen": sLen,
+			})
+		}
+
+		res := validate(t, "RSASSA-PSS", "rsassa_pss_verify_schema.json", pssGroup(32), valid, modified(valid, "msg"))
+		require.NoError(t, res.Err())
+		require.Equal(t, 2, res.Passed)
+
+		res = validate(t, "RSASSA-PSS", "rsassa_pss_verify_schema.json", pssGroup(0), valid)
+		require.Equal(t, 1, res.Skipped)
+	})
+
+	t.Run("OAEP decryption", func(t *testing.T) {
+		ct, e := rsa.EncryptOAEP(sha256.New(), rand.Reader, &privKey.PublicKey, msg, nil)
+		require.NoError(t, e)
+
+		pkcs8, e := x509.MarshalPKCS8PrivateKey(privKey)
+		require.NoError(t, e)
+
+		valid := vector{"msg": msg, "ct": ct}
+
+		res := validate(t, "RSAES-OAEP", "rsaes_oaep_decrypt_schema.json",
+			rsaGroup(group{"privateKeyPkcs8": hex.EncodeToString(pkcs8), "mgf": "MGF1", "mgfSha": "SHA-256"}),
+			valid, modified(valid, "ct"), vector{"msg": msg, "ct": ct, "label": []byte("label")})
+		require.NoError(t, res.Err())
+		require.Equal(t, 2, res.Passed)
+		require.Equal(t, 1, res.Skipped)
+	})
+}
+
+func TestValidateVectors_Errors(t *testing.T) {
+	_, err := wycheproof.ValidateVectors(bytes.NewReader([]byte("{")))
+	require.ErrorContains(t, err, "validate vectors: ")
+
+	_, err = wycheproof.ValidateVectors(vectorsFile(t, "HKDF-SHA-256", "hkdf_test_schema.json", group{}))
+	require.ErrorIs(t, err, wycheproof.ErrUnsupported)
+	require.EqualError(t, err, "validate vectors: unsupported vectors: schema 'hkdf_test_schema.json'")
+}
+
+type group map[string]interface{}
+
+// validate validates the vectors of a vectors file with the group g, hex encoding their byte slices. The vectors are
+// valid vectors unless they have a result.
+func validate(t *testing.T, algorithm, schema string, g group, vectors ...vector) *wycheproof.Result {
+	t.Helper()
+
+	res, err := wycheproof.ValidateVectors(vectorsFile(t, algorithm, schema, g, vectors...))
+	require.NoError(t, err)
+
+	return res
+}
+
+func vectorsFile(t *testing.T, algorithm, schema string, g group, vectors ...vector) *bytes.Reader {
+	t.Helper()
+
+	tests := make([]map[string]interface{}, len(vectors))
+
+	for i, v := range vectors {
+		test := map[string]interface{}{"tcId": i + 1, "comment": "", "result": "valid", "flags": []string{}}
+
+		for k, value := range v {
+			if b, ok := value.([]byte); ok {
+				value = hex.EncodeToString(b)
+			}
+
+			test[k] = value
+		}
+
+		tests[i] = test
+	}
+
+	g["tests"] = tests
+
+	data, err := json.Marshal(map[string]interface{}{
+		"algorithm":  algorithm,
+		"schema":     schema,
+		"testGroups": []group{g},
+	})
+	require.NoError(t, err)
+
+	return bytes.NewReader(data)
+}
+
+// modified returns an invalid copy of v with the last byte of its field modified.
+func modified(v vector, field string) vector {
+	m := withResult(v, "invalid")
+
+	b := append([]byte{}, v[field].([]byte)...)
+	b[len(b)-1] ^= 1
+	m[field] = b
+
+	return m
+}
+
+func withResult(v vector, result string) vector {
+	m := vector{"result": result}
+
+	for k, value := range v {
+		if k != "result" {
+			m[k] = value
+		}
+	}
+
+	return m
+}
@@ -9,6 +9,7 @@ import (
 	"errors"
 
 	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
 	kmsapi "github.com/trustbloc/kms-go/spi/kms"
 )
 
@@ -22,11 +23,14 @@ type Suite interface {
 	KMSCrypto() (KMSCrypto, error)
 	KMSCryptoSigner() (KMSCryptoSigner, error)
 	KMSCryptoMultiSigner() (KMSCryptoMultiSigner, error)
+	KMSCryptoBatchSigner() (KMSCryptoBatchSigner, error)
 	KMSCryptoVerifier() (KMSCryptoVerifier, error)
 	EncrypterDecrypter() (EncrypterDecrypter, error)
 	FixedKeyCrypto(pub *jwk.JWK) (FixedKeyCrypto, error)
 	FixedKeySigner(kid string) (FixedKeySigner, error)
 	FixedKeyMultiSigner(kid string) (FixedKeyMultiSigner, error)
+	KeyRef(kid string) (KeyRef, error)
+	KeyDeriver() (KeyDeriver, error)
 }
 
 // ErrNotSupported is returned by a Suite method when said Suite does not
@@ -86,6 +90,13 @@ type KMSCryptoMultiSigner interface {
 	FixedMultiSignerGivenKID(kid string) (FixedKeyMultiSigner, error)
 }
 
+// KMSCryptoBatchSigner provides a batch signing interface, resolving the signing key once per batch.
+type KMSCryptoBatchSigner interface {
+	// SignBatch signs msgs with the key kid and returns the signatures in the order of msgs. Messages are signed
+	// concurrently with the crypto.WithParallelism option.
+	SignBatch(msgs [][]byte, kid string, opts ...cryptoapi.SignBatchOpts) ([][]byte, error)
+}
+
 // FixedKeyMultiSigner provides a signing interface for regular and
 // multi-signatures using a fixed key for each signer instance.
 type FixedKeyMultiSigner interface {
@@ -93,8 +104,45 @@ type FixedKeyMultiSigner interface {
 	FixedKeySigner
 }
 
+// KeyDeriver derives new key material from the symmetric keys of the wrapped KMS with HKDF, without exporting them.
+type KeyDeriver interface {
+	// DeriveKey derives length bytes from the key kid with HKDF using salt and info. If targetKeyType is set (eg:
+	// kmsapi.AES256GCMType), the derived bytes are imported in the KMS as a new key of this type and its key ID is
+	// returned without the derived bytes, otherwise the derived bytes are returned with an empty key ID.
+	DeriveKey(kid string, salt, info []byte, length int, targetKeyType kmsapi.KeyType) (string, []byte, error)
+}
+
 // EncrypterDecrypter provides encryption and decryption services.
 type EncrypterDecrypter interface {
 	Encrypt(msg, aad []byte, kid string) (cipher, nonce []byte, err error)
 	Decrypt(cipher, aad, nonce []byte, kid string) (msg []byte, err error)
 }
+
+// KeyRef is a handle on a single KMS key, executing all the crypto operations supported by the key without passing
+// its key handle or public key around, eg: suite.KeyRef(kid).Sign(msg).
+//
+// Operations not supported by the key type fail with the error of the underlying crypto implementation, operations
+// not supported by the Suite return ErrNotSupported.
+type KeyRef interface {
+	// KID returns the key ID.
+	KID() string
+	// PublicKey returns the public key as a JWK, with KeyID set to KID().
+	PublicKey() (*jwk.JWK, error)
+
+	FixedKeyCrypto
+	SignMulti(msgs [][]byte) ([]byte, error)
+	VerifyMulti(msgs [][]byte, sig []byte) error
+	DeriveProof(msgs [][]byte, bbsSig, nonce []byte, revealedIndexes []int) ([]byte, error)
+	VerifyProof(revealedMsgs [][]byte, proof, nonce []byte) error
+
+	Encrypt(msg, aad []byte) (cipher, nonce []byte, err error)
+	Decrypt(cipher, aad, nonce []byte) (msg []byte, err error)
+
+	ComputeMAC(data []byte) ([]byte, error)
+	VerifyMAC(mac, data []byte) error
+
+	// WrapKeyWithKEK wraps key with this symmetric key encryption key.
+	WrapKeyWithKEK(key []byte) (*cryptoapi.RecipientWrappedKey, error)
+	// UnwrapKey unwraps recWK with this key, either the recipient private key or the key encryption key.
+	UnwrapKey(recWK *cryptoapi.RecipientWrappedKey, opts ...cryptoapi.WrapKeyOpts) ([]byte, error)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import (
+	"sync"
+
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+// ExternalSigner signs and verifies with keys held outside of the Suite KMS, eg: a smartcard-backed P-256 key or a
+// hardware token. The key is identified by its public key, pub.KeyID being the key ID set by the application.
+type ExternalSigner interface {
+	Sign(msg []byte, pub *jwk.JWK) ([]byte, error)
+	Verify(sig, msg []byte, pub *jwk.JWK) error
+}
+
+// SignerRegistry holds the ExternalSigner of key types. A Suite using a SignerRegistry signs and verifies with the
+// ExternalSigner registered for the key type of the public key (see jwk.JWK.KeyType, eg: kmsapi.ED25519Type or
+// kmsapi.ECDSAP256TypeIEEEP1363 for P-256 keys), instead of its KMS and crypto. It is safe for concurrent use, signers
+// can be registered while the Suite is used.
+type SignerRegistry struct {
+	mu      sync.RWMutex
+	signers map[kmsapi.KeyType]ExternalSigner
+}
+
+// NewSignerRegistry returns an empty SignerRegistry.
+func NewSignerRegistry() *SignerRegistry {
+	return &SignerRegistry{signers: map[kmsapi.KeyType]ExternalSigner{}}
+}
+
+// Register registers s as the signer of the keys of type kt, replacing the signer of kt if any.
+func (r *SignerRegistry) Register(kt kmsapi.KeyType, s ExternalSigner) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.signers[kt] = s
+}
+
+// Unregister removes the signer of kt, its keys are then signed by the Suite KMS.
+func (r *SignerRegistry) Unregister(kt kmsapi.KeyType) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.signers, kt)
+}
+
+// Lookup returns the signer registered for the key type of pub, if any.
+func (r *SignerRegistry) Lookup(pub *jwk.JWK) (ExternalSigner, bool) {
+	if r == nil || pub == nil {
+		return nil, false
+	}
+
+	kt, err := pub.KeyType()
+	if err != nil {
+		return nil, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.signers[kt]
+
+	return s, ok
+}
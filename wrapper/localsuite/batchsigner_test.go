@@ -0,0 +1,76 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localsuite
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	mockstorage "github.com/trustbloc/kms-go/internal/mock/storage"
+	"github.com/trustbloc/kms-go/kms"
+	mockcrypto "github.com/trustbloc/kms-go/mock/crypto"
+	mockkms "github.com/trustbloc/kms-go/mock/kms"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+)
+
+func TestBatchSigner(t *testing.T) {
+	msgs := [][]byte{[]byte("foo"), []byte("bar"), []byte("baz"), []byte("qux")}
+
+	t.Run("sign success", func(t *testing.T) {
+		store, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		suite, err := NewLocalCryptoSuite("local-lock://custom/primary/key/", store, &noop.NoLock{})
+		require.NoError(t, err)
+
+		creator, err := suite.KeyCreator()
+		require.NoError(t, err)
+
+		pub, err := creator.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		bs, err := suite.KMSCryptoBatchSigner()
+		require.NoError(t, err)
+
+		verifier, err := suite.KMSCryptoVerifier()
+		require.NoError(t, err)
+
+		for _, opts := range [][]cryptoapi.SignBatchOpts{nil, {cryptoapi.WithParallelism(3)}} {
+			sigs, err := bs.SignBatch(msgs, pub.KeyID, opts...)
+			require.NoError(t, err)
+			require.Len(t, sigs, len(msgs))
+
+			for i, sig := range sigs {
+				require.NoError(t, verifier.Verify(sig, msgs[i], pub))
+			}
+		}
+
+		_, err = bs.SignBatch(msgs, "unknown")
+		require.Error(t, err)
+	})
+
+	errExpected := errors.New("expected error")
+
+	t.Run("kms get error", func(t *testing.T) {
+		bs := newKMSCryptoBatchSigner(&mockkms.KeyManager{GetKeyErr: errExpected}, &mockcrypto.Crypto{})
+
+		sigs, err := bs.SignBatch(msgs, "foo")
+		require.ErrorIs(t, err, errExpected)
+		require.Nil(t, sigs)
+	})
+
+	t.Run("sign error", func(t *testing.T) {
+		bs := newKMSCryptoBatchSigner(&mockkms.KeyManager{}, &mockcrypto.Crypto{SignErr: errExpected})
+
+		sigs, err := bs.SignBatch(msgs, "foo", cryptoapi.WithParallelism(2))
+		require.ErrorIs(t, err, errExpected)
+		require.Contains(t, err.Error(), "sign message 0")
+		require.Nil(t, sigs)
+	})
+}
@@ -0,0 +1,67 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localsuite
+
+import (
+	"fmt"
+
+	"github.com/trustbloc/kms-go/internal/memguard"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/wrapper/api"
+)
+
+type keyImporter interface {
+	ImportPrivateKey(privKey interface{}, kt kmsapi.KeyType, opts ...kmsapi.PrivateKeyOpts) (string, interface{}, error)
+}
+
+func makeKeyDeriver(kms keyGetter, crypto interface{}) (api.KeyDeriver, error) {
+	importer, ok := kms.(keyImporter)
+	if !ok {
+		return nil, api.ErrNotSupported
+	}
+
+	deriver, ok := crypto.(cryptoapi.KeyDeriver)
+	if !ok {
+		return nil, api.ErrNotSupported
+	}
+
+	return &keyDeriverImpl{kms: kms, importer: importer, crypto: deriver}, nil
+}
+
+type keyDeriverImpl struct {
+	kms      keyGetter
+	importer keyImporter
+	crypto   cryptoapi.KeyDeriver
+}
+
+func (k *keyDeriverImpl) DeriveKey(kid string, salt, info []byte, length int,
+	targetKeyType kmsapi.KeyType) (string, []byte, error) {
+	kh, err := k.kms.Get(kid)
+	if err != nil {
+		return "", nil, err
+	}
+
+	key, err := k.crypto.DeriveKey(kh, salt, info, length)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if targetKeyType == "" {
+		return "", key, nil
+	}
+
+	defer memguard.Wipe(key)
+
+	derivedKID, _, err := k.importer.ImportPrivateKey(key, targetKeyType)
+	if err != nil {
+		return "", nil, fmt.Errorf("import derived key: %w", err)
+	}
+
+	return derivedKID, nil, nil
+}
+
+var _ api.KeyDeriver = &keyDeriverImpl{}
@@ -0,0 +1,85 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localsuite
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	mockstorage "github.com/trustbloc/kms-go/internal/mock/storage"
+	"github.com/trustbloc/kms-go/kms"
+	"github.com/trustbloc/kms-go/kms/localkms"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/wrapper/api"
+)
+
+func TestKeyDeriver(t *testing.T) {
+	store, e := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+	require.NoError(t, e)
+
+	suite, e := NewLocalCryptoSuite("local-lock://custom/primary/key/", store, &noop.NoLock{})
+	require.NoError(t, e)
+
+	localKMS, ok := suite.(*suiteImpl).kms.(*localkms.LocalKMS)
+	require.True(t, ok)
+
+	kid, _, e := localKMS.Create(kmsapi.HMACSHA256Tag256Type)
+	require.NoError(t, e)
+
+	deriver, e := suite.KeyDeriver()
+	require.NoError(t, e)
+
+	encDec, e := suite.EncrypterDecrypter()
+	require.NoError(t, e)
+
+	salt, info := []byte("salt"), []byte("encryption key")
+
+	t.Run("derive and import", func(t *testing.T) {
+		rawKID, key, err := deriver.DeriveKey(kid, salt, info, 32, "")
+		require.NoError(t, err)
+		require.Empty(t, rawKID)
+		require.Len(t, key, 32)
+
+		derivedKID, derived, err := deriver.DeriveKey(kid, salt, info, 32, kmsapi.AES256GCMType)
+		require.NoError(t, err)
+		require.NotEmpty(t, derivedKID)
+		require.Nil(t, derived)
+
+		ct, nonce, err := encDec.Encrypt([]byte("message"), []byte("aad"), derivedKID)
+		require.NoError(t, err)
+
+		// the imported key is the derived key.
+		block, err := aes.NewCipher(key)
+		require.NoError(t, err)
+
+		gcm, err := cipher.NewGCM(block)
+		require.NoError(t, err)
+
+		pt, err := gcm.Open(nil, nonce, ct, []byte("aad"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("message"), pt)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, _, err := deriver.DeriveKey("unknown", salt, info, 32, "")
+		require.Error(t, err)
+
+		_, _, err = deriver.DeriveKey(kid, salt, info, 0, "")
+		require.EqualError(t, err, "deriveKey: invalid key length")
+
+		_, _, err = deriver.DeriveKey(kid, salt, info, 16, kmsapi.AES256GCMType)
+		require.EqualError(t, err, "import derived key: import AEAD key failed: AES256GCM keys must have 32 bytes")
+
+		_, err = makeKeyDeriver(localKMS, nil)
+		require.ErrorIs(t, err, api.ErrNotSupported)
+
+		_, err = makeKeyDeriver(nil, suite.(*suiteImpl).crypto)
+		require.ErrorIs(t, err, api.ErrNotSupported)
+	})
+}
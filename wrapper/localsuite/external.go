@@ -0,0 +1,102 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localsuite
+
+import (
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/wrapper/api"
+)
+
+// dispatchKMSCrypto returns kc dispatching the keys of the key types registered in reg to their external signer.
+func dispatchKMSCrypto(kc api.KMSCrypto, reg *api.SignerRegistry) api.KMSCrypto {
+	if reg == nil {
+		return kc
+	}
+
+	return &externalKMSCrypto{KMSCrypto: kc, reg: reg}
+}
+
+// dispatchKMSCryptoSigner returns ks dispatching the keys of the key types registered in reg to their external
+// signer.
+func dispatchKMSCryptoSigner(ks api.KMSCryptoSigner, reg *api.SignerRegistry) api.KMSCryptoSigner {
+	if reg == nil {
+		return ks
+	}
+
+	return &externalKMSCryptoSigner{KMSCryptoSigner: ks, reg: reg}
+}
+
+type externalKMSCrypto struct {
+	api.KMSCrypto
+	reg *api.SignerRegistry
+}
+
+func (k *externalKMSCrypto) Sign(msg []byte, pub *jwk.JWK) ([]byte, error) {
+	if es, ok := k.reg.Lookup(pub); ok {
+		return es.Sign(msg, pub)
+	}
+
+	return k.KMSCrypto.Sign(msg, pub)
+}
+
+func (k *externalKMSCrypto) Verify(sig, msg []byte, pub *jwk.JWK) error {
+	if es, ok := k.reg.Lookup(pub); ok {
+		return es.Verify(sig, msg, pub)
+	}
+
+	return k.KMSCrypto.Verify(sig, msg, pub)
+}
+
+func (k *externalKMSCrypto) FixedKeyCrypto(pub *jwk.JWK) (api.FixedKeyCrypto, error) {
+	if es, ok := k.reg.Lookup(pub); ok {
+		return &externalFixedKey{signer: es, pub: pub}, nil
+	}
+
+	return k.KMSCrypto.FixedKeyCrypto(pub)
+}
+
+func (k *externalKMSCrypto) FixedKeySigner(pub *jwk.JWK) (api.FixedKeySigner, error) {
+	if es, ok := k.reg.Lookup(pub); ok {
+		return &externalFixedKey{signer: es, pub: pub}, nil
+	}
+
+	return k.KMSCrypto.FixedKeySigner(pub)
+}
+
+type externalKMSCryptoSigner struct {
+	api.KMSCryptoSigner
+	reg *api.SignerRegistry
+}
+
+func (k *externalKMSCryptoSigner) Sign(msg []byte, pub *jwk.JWK) ([]byte, error) {
+	if es, ok := k.reg.Lookup(pub); ok {
+		return es.Sign(msg, pub)
+	}
+
+	return k.KMSCryptoSigner.Sign(msg, pub)
+}
+
+func (k *externalKMSCryptoSigner) FixedKeySigner(pub *jwk.JWK) (api.FixedKeySigner, error) {
+	if es, ok := k.reg.Lookup(pub); ok {
+		return &externalFixedKey{signer: es, pub: pub}, nil
+	}
+
+	return k.KMSCryptoSigner.FixedKeySigner(pub)
+}
+
+// externalFixedKey signs and verifies with the external key pub.
+type externalFixedKey struct {
+	signer api.ExternalSigner
+	pub    *jwk.JWK
+}
+
+func (f *externalFixedKey) Sign(msg []byte) ([]byte, error) {
+	return f.signer.Sign(msg, f.pub)
+}
+
+func (f *externalFixedKey) Verify(sig, msg []byte) error {
+	return f.signer.Verify(sig, msg, f.pub)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localsuite
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	mockstorage "github.com/trustbloc/kms-go/internal/mock/storage"
+	"github.com/trustbloc/kms-go/kms"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/wrapper/api"
+)
+
+// tokenSigner is a P-256 signer standing for a smartcard.
+type tokenSigner struct {
+	keys map[string]*ecdsa.PrivateKey
+}
+
+func (s *tokenSigner) Sign(msg []byte, pub *jwk.JWK) ([]byte, error) {
+	key, ok := s.keys[pub.KeyID]
+	if !ok {
+		return nil, errors.New("key not on token")
+	}
+
+	digest := sha256.Sum256(msg)
+
+	return ecdsa.SignASN1(rand.Reader, key, digest[:])
+}
+
+func (s *tokenSigner) Verify(sig, msg []byte, pub *jwk.JWK) error {
+	digest := sha256.Sum256(msg)
+
+	if !ecdsa.VerifyASN1(pub.Key.(*ecdsa.PublicKey), digest[:], sig) {
+		return errors.New("invalid signature")
+	}
+
+	return nil
+}
+
+func TestSuite_ExternalSigners(t *testing.T) {
+	store, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+	require.NoError(t, err)
+
+	reg := api.NewSignerRegistry()
+
+	suite, err := NewLocalCryptoSuite("local-lock://custom/primary/key/", store, &noop.NoLock{},
+		WithSignerRegistry(reg))
+	require.NoError(t, err)
+
+	tokenKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	token := &tokenSigner{keys: map[string]*ecdsa.PrivateKey{"card-1": tokenKey}}
+	reg.Register(kmsapi.ECDSAP256TypeIEEEP1363, token)
+
+	pub := &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: &tokenKey.PublicKey, KeyID: "card-1"}}
+	msg := []byte("message")
+
+	kc, err := suite.KMSCrypto()
+	require.NoError(t, err)
+
+	t.Run("KMSCrypto", func(t *testing.T) {
+		sig, err := kc.Sign(msg, pub)
+		require.NoError(t, err)
+		require.NoError(t, kc.Verify(sig, msg, pub))
+
+		fkc, err := kc.FixedKeyCrypto(pub)
+		require.NoError(t, err)
+
+		sig, err = fkc.Sign(msg)
+		require.NoError(t, err)
+		require.NoError(t, fkc.Verify(sig, msg))
+
+		fks, err := kc.FixedKeySigner(pub)
+		require.NoError(t, err)
+
+		sig, err = fks.Sign(msg)
+		require.NoError(t, err)
+
+		verifier, err := suite.KMSCryptoVerifier()
+		require.NoError(t, err)
+		require.NoError(t, verifier.Verify(sig, msg, pub))
+
+		fkc, err = suite.FixedKeyCrypto(pub)
+		require.NoError(t, err)
+		require.NoError(t, fkc.Verify(sig, msg))
+	})
+
+	t.Run("KMSCryptoSigner", func(t *testing.T) {
+		ks, err := suite.KMSCryptoSigner()
+		require.NoError(t, err)
+
+		sig, err := ks.Sign(msg, pub)
+		require.NoError(t, err)
+
+		fks, err := ks.FixedKeySigner(pub)
+		require.NoError(t, err)
+
+		sig2, err := fks.Sign(msg)
+		require.NoError(t, err)
+
+		require.NoError(t, token.Verify(sig, msg, pub))
+		require.NoError(t, token.Verify(sig2, msg, pub))
+	})
+
+	t.Run("other key types use the KMS", func(t *testing.T) {
+		kmsPub, err := kc.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		sig, err := kc.Sign(msg, kmsPub)
+		require.NoError(t, err)
+		require.NoError(t, kc.Verify(sig, msg, kmsPub))
+	})
+
+	t.Run("unregistered key type", func(t *testing.T) {
+		reg.Unregister(kmsapi.ECDSAP256TypeIEEEP1363)
+		defer reg.Register(kmsapi.ECDSAP256TypeIEEEP1363, token)
+
+		_, err := kc.Sign(msg, pub)
+		require.Error(t, err)
+	})
+}
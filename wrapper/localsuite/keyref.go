@@ -0,0 +1,139 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localsuite
+
+import (
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/wrapper/api"
+)
+
+func makeKeyRef(kms keyManager, crypto allCrypto, kid string) (api.KeyRef, error) {
+	kh, err := kms.Get(kid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyRefImpl{
+		kid:    kid,
+		kms:    kms,
+		crypto: crypto,
+		kh:     kh,
+	}, nil
+}
+
+type keyRefImpl struct {
+	kid    string
+	kms    keyManager
+	crypto allCrypto
+	kh     interface{}
+}
+
+func (k *keyRefImpl) KID() string {
+	return k.kid
+}
+
+func (k *keyRefImpl) PublicKey() (*jwk.JWK, error) {
+	pkb, kt, err := k.kms.ExportPubKeyBytes(k.kid)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := jwksupport.PubKeyBytesToJWK(pkb, kt)
+	if err != nil {
+		return nil, err
+	}
+
+	pub.KeyID = k.kid
+
+	return pub, nil
+}
+
+// pubKH returns the public key handle used for verifications.
+func (k *keyRefImpl) pubKH() (interface{}, error) {
+	pkb, kt, err := k.kms.ExportPubKeyBytes(k.kid)
+	if err != nil {
+		return nil, err
+	}
+
+	return k.kms.PubKeyBytesToHandle(pkb, kt)
+}
+
+func (k *keyRefImpl) Sign(msg []byte) ([]byte, error) {
+	return k.crypto.Sign(msg, k.kh)
+}
+
+func (k *keyRefImpl) Verify(sig, msg []byte) error {
+	kh, err := k.pubKH()
+	if err != nil {
+		return err
+	}
+
+	return k.crypto.Verify(sig, msg, kh)
+}
+
+func (k *keyRefImpl) SignMulti(msgs [][]byte) ([]byte, error) {
+	return k.crypto.SignMulti(msgs, k.kh)
+}
+
+func (k *keyRefImpl) VerifyMulti(msgs [][]byte, sig []byte) error {
+	kh, err := k.pubKH()
+	if err != nil {
+		return err
+	}
+
+	return k.crypto.VerifyMulti(msgs, sig, kh)
+}
+
+func (k *keyRefImpl) DeriveProof(msgs [][]byte, bbsSig, nonce []byte, revealedIndexes []int) ([]byte, error) {
+	kh, err := k.pubKH()
+	if err != nil {
+		return nil, err
+	}
+
+	return k.crypto.DeriveProof(msgs, bbsSig, nonce, revealedIndexes, kh)
+}
+
+func (k *keyRefImpl) VerifyProof(revealedMsgs [][]byte, proof, nonce []byte) error {
+	kh, err := k.pubKH()
+	if err != nil {
+		return err
+	}
+
+	return k.crypto.VerifyProof(revealedMsgs, proof, nonce, kh)
+}
+
+func (k *keyRefImpl) Encrypt(msg, aad []byte) (cipher, nonce []byte, err error) {
+	return k.crypto.Encrypt(msg, aad, k.kh)
+}
+
+func (k *keyRefImpl) Decrypt(cipher, aad, nonce []byte) (msg []byte, err error) {
+	return k.crypto.Decrypt(cipher, aad, nonce, k.kh)
+}
+
+func (k *keyRefImpl) ComputeMAC(data []byte) ([]byte, error) {
+	return k.crypto.ComputeMAC(data, k.kh)
+}
+
+func (k *keyRefImpl) VerifyMAC(mac, data []byte) error {
+	return k.crypto.VerifyMAC(mac, data, k.kh)
+}
+
+func (k *keyRefImpl) WrapKeyWithKEK(key []byte) (*cryptoapi.RecipientWrappedKey, error) {
+	kw, ok := k.crypto.(cryptoapi.KEKWrapper)
+	if !ok {
+		return nil, api.ErrNotSupported
+	}
+
+	return kw.WrapKeyWithKEK(key, k.kh)
+}
+
+func (k *keyRefImpl) UnwrapKey(recWK *cryptoapi.RecipientWrappedKey, opts ...cryptoapi.WrapKeyOpts) ([]byte, error) {
+	return k.crypto.UnwrapKey(recWK, k.kh, opts...)
+}
+
+var _ api.KeyRef = &keyRefImpl{}
@@ -0,0 +1,126 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localsuite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	mockstorage "github.com/trustbloc/kms-go/internal/mock/storage"
+	"github.com/trustbloc/kms-go/kms"
+	"github.com/trustbloc/kms-go/kms/localkms"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/wrapper/api"
+)
+
+func TestKeyRef(t *testing.T) {
+	store, e := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+	require.NoError(t, e)
+
+	suite, e := NewLocalCryptoSuite("local-lock://custom/primary/key/", store, &noop.NoLock{})
+	require.NoError(t, e)
+
+	creator, e := suite.KeyCreator()
+	require.NoError(t, e)
+
+	// the suite only creates asymmetric keys.
+	createSymmetric := func(t *testing.T, kt kmsapi.KeyType) api.KeyRef {
+		t.Helper()
+
+		localKMS, ok := suite.(*suiteImpl).kms.(*localkms.LocalKMS)
+		require.True(t, ok)
+
+		kid, _, err := localKMS.Create(kt)
+		require.NoError(t, err)
+
+		ref, err := suite.KeyRef(kid)
+		require.NoError(t, err)
+
+		return ref
+	}
+
+	msg := []byte("message")
+
+	t.Run("sign and verify", func(t *testing.T) {
+		pub, err := creator.Create(kmsapi.ECDSAP256TypeIEEEP1363)
+		require.NoError(t, err)
+
+		ref, err := suite.KeyRef(pub.KeyID)
+		require.NoError(t, err)
+		require.Equal(t, pub.KeyID, ref.KID())
+
+		refPub, err := ref.PublicKey()
+		require.NoError(t, err)
+		require.Equal(t, pub.KeyID, refPub.KeyID)
+		require.Equal(t, pub.Crv, refPub.Crv)
+
+		sig, err := ref.Sign(msg)
+		require.NoError(t, err)
+		require.NoError(t, ref.Verify(sig, msg))
+		require.Error(t, ref.Verify(sig, []byte("other message")))
+	})
+
+	t.Run("BBS+", func(t *testing.T) {
+		pub, err := creator.Create(kmsapi.BLS12381G2Type)
+		require.NoError(t, err)
+
+		ref, err := suite.KeyRef(pub.KeyID)
+		require.NoError(t, err)
+
+		msgs := [][]byte{[]byte("msg 1"), []byte("msg 2")}
+
+		sig, err := ref.SignMulti(msgs)
+		require.NoError(t, err)
+		require.NoError(t, ref.VerifyMulti(msgs, sig))
+
+		nonce := []byte("nonce")
+
+		proof, err := ref.DeriveProof(msgs, sig, nonce, []int{1})
+		require.NoError(t, err)
+		require.NoError(t, ref.VerifyProof(msgs[1:], proof, nonce))
+	})
+
+	t.Run("encrypt and decrypt", func(t *testing.T) {
+		ref := createSymmetric(t, kmsapi.AES256GCMType)
+
+		ct, nonce, err := ref.Encrypt(msg, []byte("aad"))
+		require.NoError(t, err)
+
+		pt, err := ref.Decrypt(ct, []byte("aad"), nonce)
+		require.NoError(t, err)
+		require.Equal(t, msg, pt)
+
+		_, err = ref.PublicKey()
+		require.Error(t, err)
+	})
+
+	t.Run("MAC", func(t *testing.T) {
+		ref := createSymmetric(t, kmsapi.HMACSHA256Tag256Type)
+
+		mac, err := ref.ComputeMAC(msg)
+		require.NoError(t, err)
+		require.NoError(t, ref.VerifyMAC(mac, msg))
+		require.Error(t, ref.VerifyMAC(mac, []byte("other message")))
+	})
+
+	t.Run("key wrapping", func(t *testing.T) {
+		ref := createSymmetric(t, kmsapi.AES256KWType)
+		cek := make([]byte, 32)
+
+		wk, err := ref.WrapKeyWithKEK(cek)
+		require.NoError(t, err)
+
+		unwrapped, err := ref.UnwrapKey(wk)
+		require.NoError(t, err)
+		require.Equal(t, cek, unwrapped)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := suite.KeyRef("unknown")
+		require.Error(t, err)
+	})
+}
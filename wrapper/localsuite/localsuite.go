@@ -16,12 +16,26 @@ import (
 	"github.com/trustbloc/kms-go/wrapper/api"
 )
 
+// Opt is a NewLocalCryptoSuite option.
+type Opt func(s *suiteImpl)
+
+// WithSignerRegistry sets the registry of the external signers of the suite: the keys of the registered key types are
+// signed and verified by their api.ExternalSigner, in the signing and verification APIs taking a public key (Sign,
+// Verify, FixedKeyCrypto and FixedKeySigner of KMSCrypto, KMSCryptoSigner and KMSCryptoVerifier, and
+// Suite.FixedKeyCrypto). The APIs taking a key ID only use the local kms keys.
+func WithSignerRegistry(reg *api.SignerRegistry) Opt {
+	return func(s *suiteImpl) {
+		s.reg = reg
+	}
+}
+
 // NewLocalCryptoSuite initializes a wrapper.Suite using local kms and crypto
 // implementations, supporting all Suite APIs.
 func NewLocalCryptoSuite(
 	primaryKeyURI string,
 	keyStore kmsapi.Store,
 	secretLock secretlock.Service,
+	opts ...Opt,
 ) (api.Suite, error) {
 	kms, err := localkms.New(primaryKeyURI, &kmsProv{
 		store: keyStore,
@@ -36,10 +50,16 @@ func NewLocalCryptoSuite(
 		return nil, err
 	}
 
-	return &suiteImpl{
+	s := &suiteImpl{
 		kms:    kms,
 		crypto: crypto,
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
 }
 
 type kmsProv struct {
@@ -6,6 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package localsuite
 
 import (
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
 	kmsapi "github.com/trustbloc/kms-go/spi/kms"
 )
 
@@ -51,8 +52,26 @@ type encDecrypter interface {
 	Decrypt(cipher, aad, nonce []byte, kh interface{}) ([]byte, error)
 }
 
+type bbsCrypto interface {
+	VerifyMulti(messages [][]byte, signature []byte, kh interface{}) error
+	VerifyProof(revealedMessages [][]byte, proof, nonce []byte, kh interface{}) error
+	DeriveProof(messages [][]byte, bbsSignature, nonce []byte, revealedIndexes []int, kh interface{}) ([]byte, error)
+}
+
+type macCrypto interface {
+	ComputeMAC(data []byte, kh interface{}) ([]byte, error)
+	VerifyMAC(mac, data []byte, kh interface{}) error
+}
+
+type keyUnwrapper interface {
+	UnwrapKey(recWK *cryptoapi.RecipientWrappedKey, kh interface{}, opts ...cryptoapi.WrapKeyOpts) ([]byte, error)
+}
+
 type allCrypto interface {
 	multiSigner
 	verifier
 	encDecrypter
+	bbsCrypto
+	macCrypto
+	keyUnwrapper
 }
@@ -6,7 +6,9 @@ SPDX-License-Identifier: Apache-2.0
 package localsuite
 
 import (
+	cryptopkg "github.com/trustbloc/kms-go/crypto"
 	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
 	"github.com/trustbloc/kms-go/wrapper/api"
 )
 
@@ -52,3 +54,26 @@ type fixedKeySignerImpl struct {
 func (f *fixedKeySignerImpl) Sign(msg []byte) ([]byte, error) {
 	return f.cr.Sign(msg, f.kh)
 }
+
+// newKMSCryptoBatchSigner creates a KMSCryptoBatchSigner using the given kms and crypto implementations.
+func newKMSCryptoBatchSigner(kms keyGetter, crypto signer) api.KMSCryptoBatchSigner {
+	return &kmsCryptoBatchSignerImpl{
+		kms:    kms,
+		crypto: crypto,
+	}
+}
+
+type kmsCryptoBatchSignerImpl struct {
+	kms    keyGetter
+	crypto signer
+}
+
+func (k *kmsCryptoBatchSignerImpl) SignBatch(msgs [][]byte, kid string,
+	opts ...cryptoapi.SignBatchOpts) ([][]byte, error) {
+	kh, err := k.kms.Get(kid)
+	if err != nil {
+		return nil, err
+	}
+
+	return cryptopkg.SignBatch(k.crypto, msgs, kh, opts...)
+}
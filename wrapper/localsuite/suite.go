@@ -13,6 +13,7 @@ import (
 type suiteImpl struct {
 	kms    keyManager
 	crypto allCrypto
+	reg    *wrapperapi.SignerRegistry
 }
 
 func (s *suiteImpl) KeyCreator() (wrapperapi.KeyCreator, error) {
@@ -24,19 +25,23 @@ func (s *suiteImpl) RawKeyCreator() (wrapperapi.RawKeyCreator, error) {
 }
 
 func (s *suiteImpl) KMSCrypto() (wrapperapi.KMSCrypto, error) {
-	return newKMSCrypto(s.kms, s.crypto), nil
+	return dispatchKMSCrypto(newKMSCrypto(s.kms, s.crypto), s.reg), nil
 }
 
 func (s *suiteImpl) KMSCryptoSigner() (wrapperapi.KMSCryptoSigner, error) {
-	return newKMSCryptoSigner(s.kms, s.crypto), nil
+	return dispatchKMSCryptoSigner(newKMSCryptoSigner(s.kms, s.crypto), s.reg), nil
 }
 
 func (s *suiteImpl) KMSCryptoMultiSigner() (wrapperapi.KMSCryptoMultiSigner, error) {
 	return newKMSCryptoMultiSigner(s.kms, s.crypto), nil
 }
 
+func (s *suiteImpl) KMSCryptoBatchSigner() (wrapperapi.KMSCryptoBatchSigner, error) {
+	return newKMSCryptoBatchSigner(s.kms, s.crypto), nil
+}
+
 func (s *suiteImpl) KMSCryptoVerifier() (wrapperapi.KMSCryptoVerifier, error) {
-	return newKMSCrypto(s.kms, s.crypto), nil
+	return dispatchKMSCrypto(newKMSCrypto(s.kms, s.crypto), s.reg), nil
 }
 
 func (s *suiteImpl) EncrypterDecrypter() (wrapperapi.EncrypterDecrypter, error) {
@@ -44,6 +49,10 @@ func (s *suiteImpl) EncrypterDecrypter() (wrapperapi.EncrypterDecrypter, error)
 }
 
 func (s *suiteImpl) FixedKeyCrypto(pub *jwk.JWK) (wrapperapi.FixedKeyCrypto, error) {
+	if es, ok := s.reg.Lookup(pub); ok {
+		return &externalFixedKey{signer: es, pub: pub}, nil
+	}
+
 	return makeFixedKeyCrypto(s.kms, s.crypto, pub)
 }
 
@@ -54,3 +63,11 @@ func (s *suiteImpl) FixedKeySigner(kid string) (wrapperapi.FixedKeySigner, error
 func (s *suiteImpl) FixedKeyMultiSigner(kid string) (wrapperapi.FixedKeyMultiSigner, error) {
 	return getFixedMultiSigner(s.kms, s.crypto, kid)
 }
+
+func (s *suiteImpl) KeyRef(kid string) (wrapperapi.KeyRef, error) {
+	return makeKeyRef(s.kms, s.crypto, kid)
+}
+
+func (s *suiteImpl) KeyDeriver() (wrapperapi.KeyDeriver, error) {
+	return makeKeyDeriver(s.kms, s.crypto)
+}
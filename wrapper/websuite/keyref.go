@@ -0,0 +1,95 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package websuite
+
+import (
+	webcrypto "github.com/trustbloc/kms-go/crypto/webkms"
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+	"github.com/trustbloc/kms-go/kms/webkms"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	wrapperapi "github.com/trustbloc/kms-go/wrapper/api"
+)
+
+func makeKeyRef(kid string, km *webkms.RemoteKMS, cr *webcrypto.RemoteCrypto) (*keyRef, error) {
+	fk, err := makeFixedKey(kid, km, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyRef{fixedKeyCrypto: fk, kid: kid, km: km}, nil
+}
+
+// keyRef runs all the operations remotely with the key URL, the web crypto server uses the public key of the key
+// for verifications.
+type keyRef struct {
+	*fixedKeyCrypto
+	kid string
+	km  *webkms.RemoteKMS
+}
+
+func (k *keyRef) KID() string {
+	return k.kid
+}
+
+func (k *keyRef) PublicKey() (*jwk.JWK, error) {
+	pkb, kt, err := k.km.ExportPubKeyBytes(k.kid)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := jwksupport.PubKeyBytesToJWK(pkb, kt)
+	if err != nil {
+		return nil, err
+	}
+
+	pub.KeyID = k.kid
+
+	return pub, nil
+}
+
+func (k *keyRef) VerifyMulti(msgs [][]byte, sig []byte) error {
+	return k.cr.VerifyMulti(msgs, sig, k.keyURL)
+}
+
+func (k *keyRef) DeriveProof(msgs [][]byte, bbsSig, nonce []byte, revealedIndexes []int) ([]byte, error) {
+	return k.cr.DeriveProof(msgs, bbsSig, nonce, revealedIndexes, k.keyURL)
+}
+
+func (k *keyRef) VerifyProof(revealedMsgs [][]byte, proof, nonce []byte) error {
+	return k.cr.VerifyProof(revealedMsgs, proof, nonce, k.keyURL)
+}
+
+func (k *keyRef) Encrypt(msg, aad []byte) (cipher, nonce []byte, err error) {
+	return k.cr.Encrypt(msg, aad, k.keyURL)
+}
+
+func (k *keyRef) Decrypt(cipher, aad, nonce []byte) (msg []byte, err error) {
+	return k.cr.Decrypt(cipher, aad, nonce, k.keyURL)
+}
+
+func (k *keyRef) ComputeMAC(data []byte) ([]byte, error) {
+	return k.cr.ComputeMAC(data, k.keyURL)
+}
+
+func (k *keyRef) VerifyMAC(mac, data []byte) error {
+	return k.cr.VerifyMAC(mac, data, k.keyURL)
+}
+
+func (k *keyRef) WrapKeyWithKEK(key []byte) (*cryptoapi.RecipientWrappedKey, error) {
+	kw, ok := interface{}(k.cr).(cryptoapi.KEKWrapper)
+	if !ok {
+		return nil, wrapperapi.ErrNotSupported
+	}
+
+	return kw.WrapKeyWithKEK(key, k.keyURL)
+}
+
+func (k *keyRef) UnwrapKey(recWK *cryptoapi.RecipientWrappedKey, opts ...cryptoapi.WrapKeyOpts) ([]byte, error) {
+	return k.cr.UnwrapKey(recWK, k.keyURL, opts...)
+}
+
+var _ wrapperapi.KeyRef = &keyRef{}
@@ -6,10 +6,12 @@ SPDX-License-Identifier: Apache-2.0
 package websuite
 
 import (
+	cryptopkg "github.com/trustbloc/kms-go/crypto"
 	webcrypto "github.com/trustbloc/kms-go/crypto/webkms"
 	"github.com/trustbloc/kms-go/doc/jose/jwk"
 	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
 	"github.com/trustbloc/kms-go/kms/webkms"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
 	"github.com/trustbloc/kms-go/spi/kms"
 	wrapperapi "github.com/trustbloc/kms-go/wrapper/api"
 )
@@ -58,6 +60,15 @@ func (k *kmsCrypto) Sign(msg []byte, pub *jwk.JWK) ([]byte, error) {
 	return k.cr.Sign(msg, kh)
 }
 
+func (k *kmsCrypto) SignBatch(msgs [][]byte, kid string, opts ...cryptoapi.SignBatchOpts) ([][]byte, error) {
+	kh, err := k.km.Get(kid)
+	if err != nil {
+		return nil, err
+	}
+
+	return cryptopkg.SignBatch(k.cr, msgs, kh, opts...)
+}
+
 func (k *kmsCrypto) SignMulti(msgs [][]byte, pub *jwk.JWK) ([]byte, error) {
 	kh, err := k.km.Get(pub.KeyID)
 	if err != nil {
@@ -82,6 +82,13 @@ func (s *suite) KMSCryptoMultiSigner() (wrapperapi.KMSCryptoMultiSigner, error)
 	}, nil
 }
 
+func (s *suite) KMSCryptoBatchSigner() (wrapperapi.KMSCryptoBatchSigner, error) {
+	return &kmsCrypto{
+		km: s.km,
+		cr: s.cr,
+	}, nil
+}
+
 func (s *suite) FixedKeyMultiSigner(kid string) (wrapperapi.FixedKeyMultiSigner, error) {
 	return makeFixedKey(kid, s.km, s.cr)
 }
@@ -92,3 +99,11 @@ func (s *suite) EncrypterDecrypter() (wrapperapi.EncrypterDecrypter, error) {
 		cr: s.cr,
 	}, nil
 }
+
+func (s *suite) KeyRef(kid string) (wrapperapi.KeyRef, error) {
+	return makeKeyRef(kid, s.km, s.cr)
+}
+
+func (s *suite) KeyDeriver() (wrapperapi.KeyDeriver, error) {
+	return nil, wrapperapi.ErrNotSupported
+}
@@ -0,0 +1,209 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package blindrsa provides the RSA blind signatures of RFC 9474 (RSABSSA), used by Privacy Pass style token
+// issuance: a client gets the RSA-PSS signature of a message from a signer that sees neither the message nor the
+// signature.
+//
+// The client prepares and blinds the message with a Client, the Signer signs the blinded message with an RSA-PSS key
+// of a KMS (kms.RSAPS256Type) and the client finalizes the blind signature into the RSA-PSS signature of the
+// message, verified by anyone with the public key.
+//
+// The signer computes the raw RSA signature of any blinded message, the clients of a Signer get the RSA-PSS or RSA
+// PKCS#1 v1.5 signature of any message with its keys: as RFC 9474 requires, a key of the Signer must be used for
+// blind signatures only.
+package blindrsa
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	// register the SHA-384 hash function.
+	_ "crypto/sha512"
+
+	"github.com/trustbloc/kms-go/x"
+)
+
+// Variant is an RSABSSA variant of RFC 9474 section 5.
+type Variant int
+
+const (
+	// SHA384PSSRandomized is RSABSSA-SHA384-PSS-Randomized, the recommended variant.
+	SHA384PSSRandomized Variant = iota
+	// SHA384PSSZeroRandomized is RSABSSA-SHA384-PSSZERO-Randomized.
+	SHA384PSSZeroRandomized
+	// SHA384PSSDeterministic is RSABSSA-SHA384-PSS-Deterministic.
+	SHA384PSSDeterministic
+	// SHA384PSSZeroDeterministic is RSABSSA-SHA384-PSSZERO-Deterministic.
+	SHA384PSSZeroDeterministic
+)
+
+const msgPrefixSize = 32
+
+// ErrInvalidSignature is returned when a signature is not valid.
+var ErrInvalidSignature = errors.New("blindrsa: invalid signature")
+
+// Client blinds messages and finalizes their blind signatures for an RSA public key.
+type Client struct {
+	pubKey     *rsa.PublicKey
+	hash       crypto.Hash
+	saltLen    int
+	randomized bool
+	random     io.Reader
+}
+
+// ClientOpt is an option of a Client.
+type ClientOpt func(c *Client)
+
+// WithRandomness sets the randomness source of the message prefixes, PSS salts and blinds, crypto/rand.Reader by
+// default, eg: a fixed reader to reproduce test vectors.
+func WithRandomness(r io.Reader) ClientOpt {
+	return func(c *Client) {
+		c.random = r
+	}
+}
+
+// NewClient creates a new Client of the RSABSSA variant v with the RSA public key pubKey of the signer.
+func NewClient(pubKey *rsa.PublicKey, v Variant, opts ...ClientOpt) (*Client, error) {
+	if err := x.Require("crypto/blindrsa"); err != nil {
+		return nil, fmt.Errorf("blindrsa: new client: %w", err)
+	}
+
+	c := &Client{pubKey: pubKey, hash: crypto.SHA384, random: rand.Reader}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	switch v {
+	case SHA384PSSRandomized:
+		c.saltLen, c.randomized = c.hash.Size(), true
+	case SHA384PSSZeroRandomized:
+		c.randomized = true
+	case SHA384PSSDeterministic:
+		c.saltLen = c.hash.Size()
+	case SHA384PSSZeroDeterministic:
+	default:
+		return nil, fmt.Errorf("blindrsa: new client: unsupported variant %d", v)
+	}
+
+	return c, nil
+}
+
+// Prepare returns the input message of msg, the message that is blinded and signed: msg prefixed with 32 random bytes
+// for the randomized variants, msg itself for the deterministic ones.
+func (c *Client) Prepare(msg []byte) ([]byte, error) {
+	if !c.randomized {
+		return append([]byte{}, msg...), nil
+	}
+
+	prefix := make([]byte, msgPrefixSize)
+
+	if _, err := io.ReadFull(c.random, prefix); err != nil {
+		return nil, fmt.Errorf("blindrsa: prepare: %w", err)
+	}
+
+	return append(prefix, msg...), nil
+}
+
+// Blind blinds the input message inputMsg. It returns the blinded message, sent to the signer, and the inverse of
+// the blind, kept secret by the client to finalize the blind signature.
+func (c *Client) Blind(inputMsg []byte) ([]byte, []byte, error) {
+	n := c.pubKey.N
+
+	encodedMsg, err := emsaPSSEncode(inputMsg, n.BitLen()-1, c.hash, c.saltLen, c.random)
+	if err != nil {
+		return nil, nil, fmt.Errorf("blindrsa: blind: %w", err)
+	}
+
+	m := new(big.Int).SetBytes(encodedMsg)
+
+	if new(big.Int).GCD(nil, nil, m, n).Cmp(big.NewInt(1)) != 0 {
+		return nil, nil, errors.New("blindrsa: blind: invalid input")
+	}
+
+	r, inv, err := blind(n, c.random)
+	if err != nil {
+		return nil, nil, fmt.Errorf("blindrsa: blind: %w", err)
+	}
+
+	// z = m * r^e mod n
+	z := m.Mul(m, r.Exp(r, big.NewInt(int64(c.pubKey.E)), n))
+	z.Mod(z, n)
+
+	return z.FillBytes(make([]byte, c.pubKey.Size())), inv.FillBytes(make([]byte, c.pubKey.Size())), nil
+}
+
+// Finalize returns the RSA-PSS signature of the input message inputMsg from the blind signature blindSig of its
+// blinded message, with the inverse inv of its blind. It fails if the signature is not valid.
+func (c *Client) Finalize(inputMsg, blindSig, inv []byte) ([]byte, error) {
+	if len(blindSig) != c.pubKey.Size() || len(inv) != c.pubKey.Size() {
+		return nil, errors.New("blindrsa: finalize: unexpected input size")
+	}
+
+	// s = z * inv mod n
+	s := new(big.Int).SetBytes(blindSig)
+	s.Mul(s, new(big.Int).SetBytes(inv)).Mod(s, c.pubKey.N)
+
+	sig := s.FillBytes(make([]byte, c.pubKey.Size()))
+
+	if err := c.Verify(inputMsg, sig); err != nil {
+		return nil, fmt.Errorf("blindrsa: finalize: %w", err)
+	}
+
+	return sig, nil
+}
+
+// Verify verifies the RSA-PSS signature sig of the input message inputMsg. It returns ErrInvalidSignature if the
+// signature is not valid.
+func (c *Client) Verify(inputMsg, sig []byte) error {
+	n := c.pubKey.N
+
+	if len(sig) != c.pubKey.Size() {
+		return ErrInvalidSignature
+	}
+
+	s := new(big.Int).SetBytes(sig)
+	if s.Cmp(n) >= 0 {
+		return ErrInvalidSignature
+	}
+
+	emBits := n.BitLen() - 1
+	m := s.Exp(s, big.NewInt(int64(c.pubKey.E)), n)
+
+	if m.BitLen() > emBits {
+		return ErrInvalidSignature
+	}
+
+	if emsaPSSVerify(inputMsg, m.FillBytes(make([]byte, (emBits+7)/8)), emBits, c.hash, c.saltLen) != nil { //nolint:gomnd
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// blind returns a random blind r of the modulus n and its inverse mod n.
+func blind(n *big.Int, random io.Reader) (*big.Int, *big.Int, error) {
+	for {
+		r, err := rand.Int(random, n)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if r.Sign() == 0 {
+			continue
+		}
+
+		if inv := new(big.Int).ModInverse(r, n); inv != nil {
+			return r, inv, nil
+		}
+	}
+}
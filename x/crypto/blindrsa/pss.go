@@ -0,0 +1,119 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blindrsa
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+const pssTrailer = 0xbc
+
+var errEncoding = errors.New("encoding error")
+
+// emsaPSSEncode is the EMSA-PSS-ENCODE operation of RFC 8017 section 9.1.1, with MGF1 and the hash function h, for
+// an encoded message of emBits bits.
+func emsaPSSEncode(msg []byte, emBits int, h crypto.Hash, saltLen int, random io.Reader) ([]byte, error) {
+	hLen := h.Size()
+	emLen := (emBits + 7) / 8 //nolint:gomnd // bits to bytes.
+
+	if emLen < hLen+saltLen+2 {
+		return nil, errEncoding
+	}
+
+	salt := make([]byte, saltLen)
+
+	if _, err := io.ReadFull(random, salt); err != nil {
+		return nil, err
+	}
+
+	mHash := digest(h, msg)
+	hPrime := digest(h, make([]byte, 8), mHash, salt) //nolint:gomnd // M' starts with 8 zero bytes.
+
+	// DB = PS || 0x01 || salt, maskedDB = DB xor MGF1(H, emLen - hLen - 1)
+	em := make([]byte, emLen)
+	db := em[:emLen-hLen-1]
+	db[emLen-saltLen-hLen-2] = 0x01
+	copy(db[emLen-saltLen-hLen-1:], salt)
+
+	mgf1XOR(db, h, hPrime)
+
+	db[0] &= 0xff >> (8*emLen - emBits)
+
+	copy(em[emLen-hLen-1:], hPrime)
+	em[emLen-1] = pssTrailer
+
+	return em, nil
+}
+
+// emsaPSSVerify is the EMSA-PSS-VERIFY operation of RFC 8017 section 9.1.2, with MGF1 and the hash function h, for
+// the encoded message em of emBits bits.
+func emsaPSSVerify(msg, em []byte, emBits int, h crypto.Hash, saltLen int) error {
+	hLen := h.Size()
+	emLen := (emBits + 7) / 8 //nolint:gomnd // bits to bytes.
+
+	if len(em) != emLen || emLen < hLen+saltLen+2 || em[emLen-1] != pssTrailer {
+		return errEncoding
+	}
+
+	db := append([]byte{}, em[:emLen-hLen-1]...)
+	hPrime := em[emLen-hLen-1 : emLen-1]
+
+	if db[0]&^(0xff>>(8*emLen-emBits)) != 0 {
+		return errEncoding
+	}
+
+	mgf1XOR(db, h, hPrime)
+
+	db[0] &= 0xff >> (8*emLen - emBits)
+
+	psLen := emLen - hLen - saltLen - 2
+
+	for _, b := range db[:psLen] {
+		if b != 0 {
+			return errEncoding
+		}
+	}
+
+	if db[psLen] != 0x01 {
+		return errEncoding
+	}
+
+	salt := db[len(db)-saltLen:]
+
+	if subtle.ConstantTimeCompare(digest(h, make([]byte, 8), digest(h, msg), salt), hPrime) != 1 { //nolint:gomnd
+		return errEncoding
+	}
+
+	return nil
+}
+
+// mgf1XOR xors out with the MGF1 mask of seed, with the hash function h.
+func mgf1XOR(out []byte, h crypto.Hash, seed []byte) {
+	var counter [4]byte
+
+	for done := 0; done < len(out); {
+		mask := digest(h, seed, counter[:])
+
+		done += subtle.XORBytes(out[done:], out[done:], mask)
+
+		binary.BigEndian.PutUint32(counter[:], binary.BigEndian.Uint32(counter[:])+1)
+	}
+}
+
+func digest(h crypto.Hash, data ...[]byte) []byte {
+	d := h.New()
+
+	for _, b := range data {
+		d.Write(b)
+	}
+
+	return d.Sum(nil)
+}
@@ -0,0 +1,130 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blindrsa
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	rsapb "github.com/google/tink/go/proto/rsa_ssa_pkcs1_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/rsapss"
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/x"
+)
+
+// Signer computes the blind signatures of the RSA-PSS keys of a KeyManager.
+type Signer struct {
+	km kms.KeyManager
+}
+
+// New creates a new Signer of the keys of km.
+func New(km kms.KeyManager) (*Signer, error) {
+	if err := x.Require("crypto/blindrsa"); err != nil {
+		return nil, fmt.Errorf("blindrsa: new: %w", err)
+	}
+
+	return &Signer{km: km}, nil
+}
+
+// BlindSign returns the blind signature of the blinded message blindedMsg with the RSA-PSS key keyID.
+func (s *Signer) BlindSign(keyID string, blindedMsg []byte) ([]byte, error) {
+	kh, err := s.km.Get(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("blindrsa: blind sign: get key: %w", err)
+	}
+
+	key, err := rsaPSSPrivateKey(kh)
+	if err != nil {
+		return nil, fmt.Errorf("blindrsa: blind sign: %w", err)
+	}
+
+	if len(blindedMsg) != key.Size() {
+		return nil, errors.New("blindrsa: blind sign: unexpected input size")
+	}
+
+	m := new(big.Int).SetBytes(blindedMsg)
+	if m.Cmp(key.N) >= 0 {
+		return nil, errors.New("blindrsa: blind sign: message representative out of range")
+	}
+
+	sig, err := rsasp1(key, m)
+	if err != nil {
+		return nil, fmt.Errorf("blindrsa: blind sign: %w", err)
+	}
+
+	// the signature is verified to protect the key against faults of the computation.
+	if new(big.Int).Exp(sig, big.NewInt(int64(key.E)), key.N).Cmp(m) != 0 {
+		return nil, errors.New("blindrsa: blind sign: signing failure")
+	}
+
+	return sig.FillBytes(make([]byte, key.Size())), nil
+}
+
+// rsasp1 returns m^d mod n, with the message blinded to mitigate the timing of the big.Int exponentiation.
+func rsasp1(key *rsa.PrivateKey, m *big.Int) (*big.Int, error) {
+	r, inv, err := blind(key.N, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	// s = (m * r^e)^d * r^-1 mod n
+	c := new(big.Int).Exp(r, big.NewInt(int64(key.E)), key.N)
+	c.Mul(c, m).Mod(c, key.N)
+
+	s := c.Exp(c, key.D, key.N)
+	s.Mul(s, inv).Mod(s, key.N)
+
+	return s, nil
+}
+
+// rsaPSSPrivateKey returns the RSA private key of the primary key of the RSA-PSS keyset kh.
+func rsaPSSPrivateKey(kh interface{}) (*rsa.PrivateKey, error) {
+	handle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errors.New("key is not a keyset handle")
+	}
+
+	ks := insecurecleartextkeyset.KeysetMaterial(handle)
+
+	for _, k := range ks.Key {
+		if k.KeyId != ks.PrimaryKeyId || k.Status != tinkpb.KeyStatusType_ENABLED {
+			continue
+		}
+
+		key := &rsapb.RsaSsaPkcs1PrivateKey{}
+
+		if k.KeyData.TypeUrl != rsapss.SignerTypeURL || proto.Unmarshal(k.KeyData.Value, key) != nil ||
+			key.PublicKey == nil {
+			return nil, errors.New("key is not an RSA-PSS private key")
+		}
+
+		priv := &rsa.PrivateKey{
+			PublicKey: rsa.PublicKey{
+				N: new(big.Int).SetBytes(key.PublicKey.N),
+				E: int(new(big.Int).SetBytes(key.PublicKey.E).Int64()),
+			},
+			D:      new(big.Int).SetBytes(key.D),
+			Primes: []*big.Int{new(big.Int).SetBytes(key.P), new(big.Int).SetBytes(key.Q)},
+		}
+
+		if err := priv.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid RSA-PSS private key: %w", err)
+		}
+
+		return priv, nil
+	}
+
+	return nil, errors.New("primary key not found")
+}
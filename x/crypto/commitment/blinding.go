@@ -0,0 +1,54 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package commitment
+
+import (
+	"fmt"
+
+	"github.com/trustbloc/kms-go/spi/crypto"
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/util/cryptoutil"
+	"github.com/trustbloc/kms-go/x"
+)
+
+const blindingDST = "kms-go commitment v1 blinding factor"
+
+// BlindingFactors derives blinding factors from a MAC key managed by a KMS (eg: an HMACSHA256Tag256 key): the
+// blinding factor of a label is a hash to a scalar of the MAC of the label, so the holder of the key derives it again
+// to open a commitment or prove its range instead of storing it. The labels of the commitments of a key must be
+// distinct, eg: the ID of a credential and the name of the committed attribute.
+type BlindingFactors struct {
+	km     kms.KeyManager
+	crypto crypto.Crypto
+	keyID  string
+}
+
+// NewBlindingFactors returns the blinding factors of the MAC key keyID of km, computed by c.
+func NewBlindingFactors(km kms.KeyManager, c crypto.Crypto, keyID string) (*BlindingFactors, error) {
+	if err := x.Require("crypto/commitment"); err != nil {
+		return nil, fmt.Errorf("commitment: new blinding factors: %w", err)
+	}
+
+	return &BlindingFactors{km: km, crypto: c, keyID: keyID}, nil
+}
+
+// BlindingFactor returns the blinding factor of label for the commitments of p.
+func (b *BlindingFactors) BlindingFactor(p *Params, label []byte) ([]byte, error) {
+	kh, err := b.km.Get(b.keyID)
+	if err != nil {
+		return nil, fmt.Errorf("commitment: blinding factor: get key: %w", err)
+	}
+
+	data := append(cryptoutil.LengthPrefix([]byte(p.curve.String())), cryptoutil.LengthPrefix(label)...)
+
+	mac, err := b.crypto.ComputeMAC(data, kh)
+	if err != nil {
+		return nil, fmt.Errorf("commitment: blinding factor: compute MAC: %w", err)
+	}
+
+	return p.g.hashToScalar(mac, []byte(blindingDST)).bytes(), nil
+}
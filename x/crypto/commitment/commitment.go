@@ -0,0 +1,197 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package commitment provides Pedersen commitments to integer values over BLS12-381 G1 or ristretto255, with
+// Bulletproofs range proofs of the committed values.
+//
+// The commitment V = v*G + r*H of a value v with the blinding factor r hides v, and is binding: it can't be opened to
+// another value. A range proof proves that the value of a commitment is in [0, 2^bits) without revealing it, and
+// ProveGreaterOrEqual proves that it is above a minimum, eg: that the birth year of a holder is old enough for an
+// age-over-18 predicate. Commitments over BLS12-381 G1 live in the group of the BBS+ signatures messages generators,
+// so a holder presenting a BBS+ proof of a credential can present the range proof of an undisclosed attribute with
+// it.
+//
+// Blinding factors are either random (RandomBlinding) or derived from a KMS MAC key with BlindingFactors: the holder
+// derives the blinding factor of a commitment again from the key ID and a label instead of storing it.
+package commitment
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/kms-go/x"
+)
+
+// Curve is the group of commitments.
+type Curve int
+
+const (
+	// BLS12381G1 is the G1 group of BLS12-381.
+	BLS12381G1 Curve = iota + 1
+	// Ristretto255 is the ristretto255 group.
+	Ristretto255
+)
+
+const (
+	blindingGeneratorDST = "kms-go commitment v1 blinding generator"
+	vectorGeneratorsDST  = "kms-go commitment v1 vector generators"
+	randomScalarDST      = "kms-go commitment v1 random scalar"
+)
+
+// ErrInvalidOpening is returned by Open when a commitment is not the commitment of a value with a blinding factor.
+var ErrInvalidOpening = errors.New("commitment: invalid opening")
+
+// String returns the name of c.
+func (c Curve) String() string {
+	switch c {
+	case BLS12381G1:
+		return "BLS12381G1"
+	case Ristretto255:
+		return "Ristretto255"
+	default:
+		return fmt.Sprintf("Curve(%d)", int(c))
+	}
+}
+
+// Params are the generators of the commitments and range proofs of a curve.
+type Params struct {
+	curve Curve
+	g     group
+	// gen and h are the value and blinding factor generators: h is a hash to the curve, its discrete logarithm to
+	// the base gen is unknown.
+	gen point
+	h   point
+	// gs, hs and u are the vector generators of range proofs.
+	gs []point
+	hs []point
+	u  point
+}
+
+// New returns the commitment parameters of curve.
+func New(curve Curve) (*Params, error) {
+	if err := x.Require("crypto/commitment"); err != nil {
+		return nil, fmt.Errorf("commitment: new: %w", err)
+	}
+
+	var g group
+
+	switch curve {
+	case BLS12381G1:
+		g = bls12381G1{}
+	case Ristretto255:
+		g = ristretto255{}
+	default:
+		return nil, fmt.Errorf("commitment: unsupported curve %s", curve)
+	}
+
+	p := &Params{
+		curve: curve,
+		g:     g,
+		gen:   g.generator(),
+		h:     g.hashToPoint([]byte(curve.String()), []byte(blindingGeneratorDST)),
+		gs:    make([]point, MaxRangeBits),
+		hs:    make([]point, MaxRangeBits),
+	}
+
+	for i := range p.gs {
+		p.gs[i] = g.hashToPoint([]byte(fmt.Sprintf("%s G %d", curve, i)), []byte(vectorGeneratorsDST))
+		p.hs[i] = g.hashToPoint([]byte(fmt.Sprintf("%s H %d", curve, i)), []byte(vectorGeneratorsDST))
+	}
+
+	p.u = g.hashToPoint([]byte(fmt.Sprintf("%s U", curve)), []byte(vectorGeneratorsDST))
+
+	return p, nil
+}
+
+// Curve returns the curve of p.
+func (p *Params) Curve() Curve {
+	return p.curve
+}
+
+// BlindingSize returns the size in bytes of blinding factors.
+func (p *Params) BlindingSize() int {
+	return p.g.scalarSize()
+}
+
+// CommitmentSize returns the size in bytes of commitments.
+func (p *Params) CommitmentSize() int {
+	return p.g.pointSize()
+}
+
+// RandomBlinding returns a random blinding factor.
+func (p *Params) RandomBlinding() ([]byte, error) {
+	r, err := p.g.randomScalar(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("commitment: random blinding: %w", err)
+	}
+
+	return r.bytes(), nil
+}
+
+// Commit returns the commitment of value with blinding.
+func (p *Params) Commit(value uint64, blinding []byte) ([]byte, error) {
+	r, err := p.g.decodeScalar(blinding)
+	if err != nil {
+		return nil, fmt.Errorf("commitment: commit: blinding: %w", err)
+	}
+
+	return p.commit(p.g.newScalar(value), r).bytes(), nil
+}
+
+// Open verifies that commitment is the commitment of value with blinding. It returns ErrInvalidOpening if it is not.
+func (p *Params) Open(commitment []byte, value uint64, blinding []byte) error {
+	v, err := p.g.decodePoint(commitment)
+	if err != nil {
+		return fmt.Errorf("commitment: open: %w", err)
+	}
+
+	r, err := p.g.decodeScalar(blinding)
+	if err != nil {
+		return fmt.Errorf("commitment: open: blinding: %w", err)
+	}
+
+	if !p.commit(p.g.newScalar(value), r).equal(v) {
+		return ErrInvalidOpening
+	}
+
+	return nil
+}
+
+// Add returns the commitment of the sum of the values of commitments c1 and c2, with the sum of their blinding
+// factors (see AddBlindings).
+func (p *Params) Add(c1, c2 []byte) ([]byte, error) {
+	v1, err := p.g.decodePoint(c1)
+	if err != nil {
+		return nil, fmt.Errorf("commitment: add: %w", err)
+	}
+
+	v2, err := p.g.decodePoint(c2)
+	if err != nil {
+		return nil, fmt.Errorf("commitment: add: %w", err)
+	}
+
+	return v1.add(v2).bytes(), nil
+}
+
+// AddBlindings returns the sum of blinding factors r1 and r2, the blinding factor of the sum of their commitments.
+func (p *Params) AddBlindings(r1, r2 []byte) ([]byte, error) {
+	s1, err := p.g.decodeScalar(r1)
+	if err != nil {
+		return nil, fmt.Errorf("commitment: add blindings: %w", err)
+	}
+
+	s2, err := p.g.decodeScalar(r2)
+	if err != nil {
+		return nil, fmt.Errorf("commitment: add blindings: %w", err)
+	}
+
+	return s1.add(s2).bytes(), nil
+}
+
+func (p *Params) commit(v, r scalar) point {
+	return p.gen.mul(v).add(p.h.mul(r))
+}
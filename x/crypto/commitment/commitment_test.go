@@ -0,0 +1,171 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package commitment_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	cryptoapi "github.com/trustbloc/kms-go/spi/crypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+	"github.com/trustbloc/kms-go/x"
+	"github.com/trustbloc/kms-go/x/crypto/commitment"
+)
+
+var curves = []commitment.Curve{commitment.BLS12381G1, commitment.Ristretto255}
+
+func TestParams_Commit(t *testing.T) {
+	x.Enable("crypto/commitment")
+
+	for _, curve := range curves {
+		t.Run(curve.String(), func(t *testing.T) {
+			p, err := commitment.New(curve)
+			require.NoError(t, err)
+			require.Equal(t, curve, p.Curve())
+
+			r1, err := p.RandomBlinding()
+			require.NoError(t, err)
+			require.Len(t, r1, p.BlindingSize())
+
+			r2, err := p.RandomBlinding()
+			require.NoError(t, err)
+			require.NotEqual(t, r1, r2)
+
+			c1, err := p.Commit(42, r1)
+			require.NoError(t, err)
+			require.Len(t, c1, p.CommitmentSize())
+			require.NoError(t, p.Open(c1, 42, r1))
+
+			// the commitment is hiding: the same value has unrelated commitments with other blinding factors.
+			other, err := p.Commit(42, r2)
+			require.NoError(t, err)
+			require.NotEqual(t, c1, other)
+
+			require.ErrorIs(t, p.Open(c1, 43, r1), commitment.ErrInvalidOpening)
+			require.ErrorIs(t, p.Open(c1, 42, r2), commitment.ErrInvalidOpening)
+
+			// commitments are homomorphic.
+			c2, err := p.Commit(8, r2)
+			require.NoError(t, err)
+
+			sum, err := p.Add(c1, c2)
+			require.NoError(t, err)
+
+			r, err := p.AddBlindings(r1, r2)
+			require.NoError(t, err)
+			require.NoError(t, p.Open(sum, 50, r))
+
+			t.Run("errors", func(t *testing.T) {
+				_, err = p.Commit(42, []byte("invalid"))
+				require.EqualError(t, err, "commitment: commit: blinding: invalid scalar")
+
+				err = p.Open([]byte("invalid"), 42, r1)
+				require.EqualError(t, err, "commitment: open: invalid point")
+
+				err = p.Open(c1, 42, []byte("invalid"))
+				require.EqualError(t, err, "commitment: open: blinding: invalid scalar")
+
+				_, err = p.Add(c1, make([]byte, len(c1)+1))
+				require.EqualError(t, err, "commitment: add: invalid point")
+
+				_, err = p.Add([]byte("invalid"), c2)
+				require.EqualError(t, err, "commitment: add: invalid point")
+
+				_, err = p.AddBlindings(r1, nil)
+				require.EqualError(t, err, "commitment: add blindings: invalid scalar")
+
+				_, err = p.AddBlindings(nil, r2)
+				require.EqualError(t, err, "commitment: add blindings: invalid scalar")
+			})
+		})
+	}
+
+	_, err := commitment.New(commitment.Curve(0))
+	require.EqualError(t, err, "commitment: unsupported curve Curve(0)")
+
+	x.Disable()
+
+	_, err = commitment.New(commitment.Ristretto255)
+	require.ErrorIs(t, err, x.ErrNotEnabled)
+
+	_, err = commitment.NewBlindingFactors(nil, nil, "")
+	require.ErrorIs(t, err, x.ErrNotEnabled)
+}
+
+func TestBlindingFactors(t *testing.T) {
+	km := mockkms.NewForTest(t)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	macKeyID, _, err := km.Create(kmsapi.HMACSHA256Tag256Type)
+	require.NoError(t, err)
+
+	otherKeyID, _, err := km.Create(kmsapi.HMACSHA256Tag256Type)
+	require.NoError(t, err)
+
+	factors := newBlindingFactors(t, km, cr, macKeyID)
+
+	for _, curve := range curves {
+		t.Run(curve.String(), func(t *testing.T) {
+			p, err := commitment.New(curve)
+			require.NoError(t, err)
+
+			label := []byte("urn:uuid:credential#age")
+
+			r, err := factors.BlindingFactor(p, label)
+			require.NoError(t, err)
+			require.Len(t, r, p.BlindingSize())
+
+			c, err := p.Commit(30, r)
+			require.NoError(t, err)
+
+			// the blinding factor is derived again to open the commitment.
+			again, err := newBlindingFactors(t, km, cr, macKeyID).BlindingFactor(p, label)
+			require.NoError(t, err)
+			require.Equal(t, r, again)
+			require.NoError(t, p.Open(c, 30, again))
+
+			otherLabel, err := factors.BlindingFactor(p, []byte("urn:uuid:credential#height"))
+			require.NoError(t, err)
+			require.NotEqual(t, r, otherLabel)
+
+			otherKey, err := newBlindingFactors(t, km, cr, otherKeyID).BlindingFactor(p, label)
+			require.NoError(t, err)
+			require.NotEqual(t, r, otherKey)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		p, err := commitment.New(commitment.Ristretto255)
+		require.NoError(t, err)
+
+		_, err = newBlindingFactors(t, km, cr, "unknown").BlindingFactor(p, nil)
+		require.ErrorContains(t, err, "commitment: blinding factor: get key")
+
+		aeadID, _, err := km.Create(kmsapi.AES256GCMType)
+		require.NoError(t, err)
+
+		_, err = newBlindingFactors(t, km, cr, aeadID).BlindingFactor(p, nil)
+		require.ErrorContains(t, err, "commitment: blinding factor: compute MAC")
+	})
+}
+
+func newBlindingFactors(t *testing.T, km kmsapi.KeyManager, cr cryptoapi.Crypto,
+	keyID string) *commitment.BlindingFactors {
+	t.Helper()
+
+	x.Enable("crypto/commitment")
+
+	b, err := commitment.NewBlindingFactors(km, cr, keyID)
+	require.NoError(t, err)
+
+	return b
+}
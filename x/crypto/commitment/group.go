@@ -0,0 +1,320 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package commitment
+
+import (
+	"crypto"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/cloudflare/circl/expander"
+	circlgroup "github.com/cloudflare/circl/group"
+)
+
+var (
+	errInvalidScalar = errors.New("invalid scalar")
+	errInvalidPoint  = errors.New("invalid point")
+)
+
+// group is a prime order group: the curve points of commitments and their scalars, with immutable operations.
+type group interface {
+	generator() point
+	identity() point
+	hashToPoint(msg, dst []byte) point
+	decodePoint(b []byte) (point, error)
+	pointSize() int
+
+	newScalar(v uint64) scalar
+	randomScalar(r io.Reader) (scalar, error)
+	hashToScalar(msg, dst []byte) scalar
+	decodeScalar(b []byte) (scalar, error)
+	scalarSize() int
+}
+
+type point interface {
+	add(q point) point
+	sub(q point) point
+	mul(s scalar) point
+	equal(q point) bool
+	bytes() []byte
+}
+
+type scalar interface {
+	add(t scalar) scalar
+	sub(t scalar) scalar
+	mul(t scalar) scalar
+	inv() scalar
+	isZero() bool
+	bytes() []byte
+}
+
+// ristretto255 is the ristretto255 group of circl.
+type ristretto255 struct{}
+
+type ristrettoPoint struct{ e circlgroup.Element }
+
+type ristrettoScalar struct{ s circlgroup.Scalar }
+
+func (ristretto255) generator() point {
+	return ristrettoPoint{circlgroup.Ristretto255.Generator()}
+}
+
+func (ristretto255) identity() point {
+	return ristrettoPoint{circlgroup.Ristretto255.Identity()}
+}
+
+func (ristretto255) hashToPoint(msg, dst []byte) point {
+	return ristrettoPoint{circlgroup.Ristretto255.HashToElement(msg, dst)}
+}
+
+func (g ristretto255) decodePoint(b []byte) (point, error) {
+	e := circlgroup.Ristretto255.NewElement()
+
+	if len(b) != g.pointSize() || e.UnmarshalBinary(b) != nil {
+		return nil, errInvalidPoint
+	}
+
+	return ristrettoPoint{e}, nil
+}
+
+func (ristretto255) pointSize() int {
+	return int(circlgroup.Ristretto255.Params().CompressedElementLength)
+}
+
+func (ristretto255) newScalar(v uint64) scalar {
+	return ristrettoScalar{circlgroup.Ristretto255.NewScalar().SetUint64(v)}
+}
+
+func (ristretto255) randomScalar(r io.Reader) (scalar, error) {
+	// circl ignores the reader of RandomScalar: the random bytes are read from r and reduced by HashToScalar.
+	b := make([]byte, 64) //nolint:gomnd // a wide reduction of 512 bits.
+
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+
+	return ristrettoScalar{circlgroup.Ristretto255.HashToScalar(b, []byte(randomScalarDST))}, nil
+}
+
+func (ristretto255) hashToScalar(msg, dst []byte) scalar {
+	return ristrettoScalar{circlgroup.Ristretto255.HashToScalar(msg, dst)}
+}
+
+func (g ristretto255) decodeScalar(b []byte) (scalar, error) {
+	s := circlgroup.Ristretto255.NewScalar()
+
+	if len(b) != g.scalarSize() || s.UnmarshalBinary(b) != nil {
+		return nil, errInvalidScalar
+	}
+
+	return ristrettoScalar{s}, nil
+}
+
+func (ristretto255) scalarSize() int {
+	return int(circlgroup.Ristretto255.Params().ScalarLength)
+}
+
+func (p ristrettoPoint) add(q point) point {
+	return ristrettoPoint{circlgroup.Ristretto255.NewElement().Add(p.e, q.(ristrettoPoint).e)}
+}
+
+func (p ristrettoPoint) sub(q point) point {
+	neg := circlgroup.Ristretto255.NewElement().Neg(q.(ristrettoPoint).e)
+
+	return ristrettoPoint{circlgroup.Ristretto255.NewElement().Add(p.e, neg)}
+}
+
+func (p ristrettoPoint) mul(s scalar) point {
+	return ristrettoPoint{circlgroup.Ristretto255.NewElement().Mul(p.e, s.(ristrettoScalar).s)}
+}
+
+func (p ristrettoPoint) equal(q point) bool {
+	return p.e.IsEqual(q.(ristrettoPoint).e)
+}
+
+func (p ristrettoPoint) bytes() []byte {
+	b, _ := p.e.MarshalBinaryCompress() //nolint:errcheck // ristretto255 elements always marshal.
+
+	return b
+}
+
+func (s ristrettoScalar) add(t scalar) scalar {
+	return ristrettoScalar{circlgroup.Ristretto255.NewScalar().Add(s.s, t.(ristrettoScalar).s)}
+}
+
+func (s ristrettoScalar) sub(t scalar) scalar {
+	return ristrettoScalar{circlgroup.Ristretto255.NewScalar().Sub(s.s, t.(ristrettoScalar).s)}
+}
+
+func (s ristrettoScalar) mul(t scalar) scalar {
+	return ristrettoScalar{circlgroup.Ristretto255.NewScalar().Mul(s.s, t.(ristrettoScalar).s)}
+}
+
+func (s ristrettoScalar) inv() scalar {
+	return ristrettoScalar{circlgroup.Ristretto255.NewScalar().Inv(s.s)}
+}
+
+func (s ristrettoScalar) isZero() bool {
+	return s.s.IsZero()
+}
+
+func (s ristrettoScalar) bytes() []byte {
+	b, _ := s.s.MarshalBinary() //nolint:errcheck // ristretto255 scalars always marshal.
+
+	return b
+}
+
+// bls12381G1 is the G1 group of BLS12-381, the group of the BBS+ signatures messages generators.
+type bls12381G1 struct{}
+
+type g1Point struct{ p *bls12381.G1 }
+
+type g1Scalar struct{ s *bls12381.Scalar }
+
+func (bls12381G1) generator() point {
+	return g1Point{bls12381.G1Generator()}
+}
+
+func (bls12381G1) identity() point {
+	p := new(bls12381.G1)
+	p.SetIdentity()
+
+	return g1Point{p}
+}
+
+func (bls12381G1) hashToPoint(msg, dst []byte) point {
+	p := new(bls12381.G1)
+	p.Hash(msg, dst)
+
+	return g1Point{p}
+}
+
+func (bls12381G1) decodePoint(b []byte) (point, error) {
+	p := new(bls12381.G1)
+
+	if len(b) != bls12381.G1SizeCompressed || p.SetBytes(b) != nil || !p.IsOnG1() {
+		return nil, errInvalidPoint
+	}
+
+	return g1Point{p}, nil
+}
+
+func (bls12381G1) pointSize() int {
+	return bls12381.G1SizeCompressed
+}
+
+func (bls12381G1) newScalar(v uint64) scalar {
+	s := new(bls12381.Scalar)
+	s.SetUint64(v)
+
+	return g1Scalar{s}
+}
+
+func (g bls12381G1) randomScalar(r io.Reader) (scalar, error) {
+	s := new(bls12381.Scalar)
+
+	if err := s.Random(r); err != nil {
+		return nil, err
+	}
+
+	return g1Scalar{s}, nil
+}
+
+func (bls12381G1) hashToScalar(msg, dst []byte) scalar {
+	// RFC 9380 hash_to_field with L = 48 bytes.
+	const l = 48
+
+	s := new(bls12381.Scalar)
+	s.SetBytes(expander.NewExpanderMD(crypto.SHA256, dst).Expand(msg, l))
+
+	return g1Scalar{s}
+}
+
+func (g bls12381G1) decodeScalar(b []byte) (scalar, error) {
+	s := new(bls12381.Scalar)
+
+	if len(b) != g.scalarSize() || s.UnmarshalBinary(b) != nil {
+		return nil, errInvalidScalar
+	}
+
+	return g1Scalar{s}, nil
+}
+
+func (bls12381G1) scalarSize() int {
+	return bls12381.ScalarSize
+}
+
+func (p g1Point) add(q point) point {
+	r := new(bls12381.G1)
+	r.Add(p.p, q.(g1Point).p)
+
+	return g1Point{r}
+}
+
+func (p g1Point) sub(q point) point {
+	neg := *q.(g1Point).p
+	neg.Neg()
+
+	r := new(bls12381.G1)
+	r.Add(p.p, &neg)
+
+	return g1Point{r}
+}
+
+func (p g1Point) mul(s scalar) point {
+	r := new(bls12381.G1)
+	r.ScalarMult(s.(g1Scalar).s, p.p)
+
+	return g1Point{r}
+}
+
+func (p g1Point) equal(q point) bool {
+	return p.p.IsEqual(q.(g1Point).p)
+}
+
+func (p g1Point) bytes() []byte {
+	return p.p.BytesCompressed()
+}
+
+func (s g1Scalar) add(t scalar) scalar {
+	r := new(bls12381.Scalar)
+	r.Add(s.s, t.(g1Scalar).s)
+
+	return g1Scalar{r}
+}
+
+func (s g1Scalar) sub(t scalar) scalar {
+	r := new(bls12381.Scalar)
+	r.Sub(s.s, t.(g1Scalar).s)
+
+	return g1Scalar{r}
+}
+
+func (s g1Scalar) mul(t scalar) scalar {
+	r := new(bls12381.Scalar)
+	r.Mul(s.s, t.(g1Scalar).s)
+
+	return g1Scalar{r}
+}
+
+func (s g1Scalar) inv() scalar {
+	r := new(bls12381.Scalar)
+	r.Inv(s.s)
+
+	return g1Scalar{r}
+}
+
+func (s g1Scalar) isZero() bool {
+	return s.s.IsZero() == 1
+}
+
+func (s g1Scalar) bytes() []byte {
+	b, _ := s.s.MarshalBinary() //nolint:errcheck // BLS12-381 scalars always marshal.
+
+	return b
+}
@@ -0,0 +1,495 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package commitment
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/trustbloc/kms-go/util/cryptoutil"
+)
+
+// MaxRangeBits is the maximum bit size of the ranges of range proofs.
+const MaxRangeBits = 64
+
+const (
+	rangeProofLabel   = "kms-go range proof v1"
+	rangeChallengeDST = "kms-go commitment v1 range proof challenge"
+	rangeProofPoints  = 4
+	rangeProofScalars = 5
+	roundPoints       = 2
+)
+
+// ErrInvalidRangeProof is returned when a range proof fails verification.
+var ErrInvalidRangeProof = errors.New("commitment: invalid range proof")
+
+// A range proof is a Bulletproofs (https://eprint.iacr.org/2017/1066) proof that the value v of a commitment
+// V = v*G + gamma*H is in [0, 2^n): the bits aL of v, with aR = aL - 1^n, verify <aL, 2^n> = v, aL o aR = 0^n and
+// aL - aR = 1^n. The prover commits to aL and aR with A, to blinding vectors sL and sR with S, and the challenges y
+// and z of the verifier reduce the constraints to the inner product t(x) = <l(x), r(x)> of the polynomials
+//
+//	l(X) = aL - z*1^n + sL*X
+//	r(X) = y^n o (aR + z*1^n + sR*X) + z^2*2^n
+//
+// whose coefficients t1 and t2 are committed with T1 and T2. Given the challenge x, the proof opens the blinding
+// factor taux of t(x) and mu of A + x*S, and proves that <l, r> = t(x) with a logarithmic size inner product argument
+// of l and r. The proof is made non-interactive with a Fiat-Shamir transcript.
+//
+// Proofs are serialized as A, S, T1, T2, taux, mu, t(x), the L and R points of the log2(n) rounds of the inner
+// product argument and its final scalars a and b.
+
+// ProveRange returns the proof that the value of the commitment of value with blinding is in [0, 2^bits). bits is a
+// power of 2 up to MaxRangeBits.
+func (p *Params) ProveRange(value uint64, blinding []byte, bits int) ([]byte, error) {
+	proof, err := p.proveRange(value, blinding, 0, bits)
+	if err != nil {
+		return nil, fmt.Errorf("commitment: prove range: %w", err)
+	}
+
+	return proof, nil
+}
+
+// VerifyRange verifies the proof of ProveRange that the value of commitment is in [0, 2^bits). It returns
+// ErrInvalidRangeProof if it is not valid.
+func (p *Params) VerifyRange(commitment, proof []byte, bits int) error {
+	return p.verifyRange(commitment, proof, 0, bits)
+}
+
+// ProveGreaterOrEqual returns the proof that the value of the commitment of value with blinding is in
+// [minimum, minimum + 2^bits), eg: that the birth date of a credential subject is more than 18 years old, without
+// revealing the value.
+func (p *Params) ProveGreaterOrEqual(value uint64, blinding []byte, minimum uint64, bits int) ([]byte, error) {
+	if value < minimum {
+		return nil, errors.New("commitment: prove greater or equal: value is less than minimum")
+	}
+
+	proof, err := p.proveRange(value, blinding, minimum, bits)
+	if err != nil {
+		return nil, fmt.Errorf("commitment: prove greater or equal: %w", err)
+	}
+
+	return proof, nil
+}
+
+// VerifyGreaterOrEqual verifies the proof of ProveGreaterOrEqual that the value of commitment is in
+// [minimum, minimum + 2^bits). It returns ErrInvalidRangeProof if it is not valid.
+func (p *Params) VerifyGreaterOrEqual(commitment, proof []byte, minimum uint64, bits int) error {
+	return p.verifyRange(commitment, proof, minimum, bits)
+}
+
+// proveRange proves that value - minimum is in [0, 2^bits): the value of the commitment V - minimum*G.
+func (p *Params) proveRange(value uint64, blinding []byte, minimum uint64, n int) ([]byte, error) {
+	if err := checkRangeBits(n); err != nil {
+		return nil, err
+	}
+
+	value -= minimum
+
+	if n < MaxRangeBits && value>>n != 0 {
+		return nil, errors.New("value out of range")
+	}
+
+	gamma, err := p.g.decodeScalar(blinding)
+	if err != nil {
+		return nil, fmt.Errorf("blinding: %w", err)
+	}
+
+	randoms, err := p.randomScalars(2*n + 4) //nolint:gomnd // sL, sR, alpha, rho, tau1 and tau2.
+	if err != nil {
+		return nil, err
+	}
+
+	sL, sR := randoms[:n], randoms[n:2*n]
+	alpha, rho, tau1, tau2 := randoms[2*n], randoms[2*n+1], randoms[2*n+2], randoms[2*n+3]
+
+	zero := p.g.newScalar(0)
+	aL, aR := p.bitVectors(value, n)
+	gs, hs := p.gs[:n], p.hs[:n]
+	a := p.h.mul(alpha).add(multiExp(p.g, gs, aL)).add(multiExp(p.g, hs, aR))
+	s := p.h.mul(rho).add(multiExp(p.g, gs, sL)).add(multiExp(p.g, hs, sR))
+
+	t := p.newTranscript(p.commit(p.g.newScalar(value), gamma), n)
+	t.appendPoints(a, s)
+	y, z := t.challenge(), t.challenge()
+
+	l0, r0, r1 := p.polynomials(aL, aR, sR, y, z)
+
+	t1 := innerProduct(zero, l0, r1).add(innerProduct(zero, sL, r0))
+	t2 := innerProduct(zero, sL, r1)
+	bigT1, bigT2 := p.commit(t1, tau1), p.commit(t2, tau2)
+
+	t.appendPoints(bigT1, bigT2)
+	x := t.challenge()
+
+	taux := tau2.mul(x).mul(x).add(tau1.mul(x)).add(z.mul(z).mul(gamma))
+	mu := alpha.add(rho.mul(x))
+	l, r := make([]scalar, n), make([]scalar, n)
+
+	for i := 0; i < n; i++ {
+		l[i] = l0[i].add(sL[i].mul(x))
+		r[i] = r0[i].add(r1[i].mul(x))
+	}
+
+	tx := innerProduct(zero, l, r)
+
+	t.appendScalars(taux, mu, tx)
+	q := p.u.mul(t.challenge())
+
+	proof := make([]byte, 0, rangeProofSize(p.g, n))
+	proof = append(append(append(append(proof, a.bytes()...), s.bytes()...), bigT1.bytes()...), bigT2.bytes()...)
+	proof = append(append(append(proof, taux.bytes()...), mu.bytes()...), tx.bytes()...)
+
+	return append(proof, proveInnerProduct(t, gs, p.primeHs(y, n), q, l, r)...), nil
+}
+
+// bitVectors returns the n bits aL of value and aR = aL - 1^n.
+func (p *Params) bitVectors(value uint64, n int) ([]scalar, []scalar) {
+	one := p.g.newScalar(1)
+	aL, aR := make([]scalar, n), make([]scalar, n)
+
+	for i := 0; i < n; i++ {
+		aL[i] = p.g.newScalar(value >> i & 1)
+		aR[i] = aL[i].sub(one)
+	}
+
+	return aL, aR
+}
+
+// polynomials returns the coefficients l0 of l(X), and r0 and r1 of r(X); the coefficient of X of l(X) is sL.
+func (p *Params) polynomials(aL, aR, sR []scalar, y, z scalar) ([]scalar, []scalar, []scalar) {
+	n := len(aL)
+	yn, twon := p.powers(y, n), p.powers(p.g.newScalar(2), n) //nolint:gomnd // 2^n.
+	z2 := z.mul(z)
+
+	l0, r0, r1 := make([]scalar, n), make([]scalar, n), make([]scalar, n)
+
+	for i := 0; i < n; i++ {
+		l0[i] = aL[i].sub(z)
+		r0[i] = yn[i].mul(aR[i].add(z)).add(z2.mul(twon[i]))
+		r1[i] = yn[i].mul(sR[i])
+	}
+
+	return l0, r0, r1
+}
+
+func (p *Params) verifyRange(commitment, proof []byte, minimum uint64, n int) error {
+	if err := checkRangeBits(n); err != nil {
+		return fmt.Errorf("commitment: verify range: %w", err)
+	}
+
+	v, err := p.g.decodePoint(commitment)
+	if err != nil {
+		return fmt.Errorf("commitment: verify range: %w", err)
+	}
+
+	v = v.sub(p.gen.mul(p.g.newScalar(minimum)))
+
+	pr, err := p.parseRangeProof(proof, n)
+	if err != nil {
+		return ErrInvalidRangeProof
+	}
+
+	t := p.newTranscript(v, n)
+	t.appendPoints(pr.a, pr.s)
+	y, z := t.challenge(), t.challenge()
+	t.appendPoints(pr.t1, pr.t2)
+	x := t.challenge()
+	t.appendScalars(pr.taux, pr.mu, pr.tx)
+	q := p.u.mul(t.challenge())
+
+	zero := p.g.newScalar(0)
+	yn, twon := p.powers(y, n), p.powers(p.g.newScalar(2), n) //nolint:gomnd // 2^n.
+	z2 := z.mul(z)
+
+	// delta(y, z) = (z - z^2) * <1^n, y^n> - z^3 * <1^n, 2^n>
+	delta := z.sub(z2).mul(sum(zero, yn)).sub(z2.mul(z).mul(p.g.newScalar(^uint64(0) >> (MaxRangeBits - n))))
+
+	// t(x)*G + taux*H = z^2*V + delta(y, z)*G + x*T1 + x^2*T2
+	lhs := p.commit(pr.tx, pr.taux)
+	rhs := v.mul(z2).add(p.gen.mul(delta)).add(pr.t1.mul(x)).add(pr.t2.mul(x.mul(x)))
+
+	if !lhs.equal(rhs) {
+		return ErrInvalidRangeProof
+	}
+
+	// P = A + x*S - z*<1^n, G> + <z*y^n + z^2*2^n, H'> - mu*H + t(x)*Q = <l, G> + <r, H'> + <l, r>*Q
+	hs := p.primeHs(y, n)
+	gScalars, hScalars := make([]scalar, n), make([]scalar, n)
+
+	for i := 0; i < n; i++ {
+		gScalars[i] = zero.sub(z)
+		hScalars[i] = z.mul(yn[i]).add(z2.mul(twon[i]))
+	}
+
+	pt := pr.a.add(pr.s.mul(x)).add(multiExp(p.g, p.gs[:n], gScalars)).add(multiExp(p.g, hs, hScalars))
+	pt = pt.sub(p.h.mul(pr.mu)).add(q.mul(pr.tx))
+
+	if !verifyInnerProduct(t, p.gs[:n], hs, q, pt, pr) {
+		return ErrInvalidRangeProof
+	}
+
+	return nil
+}
+
+type rangeProof struct {
+	a, s, t1, t2 point
+	taux, mu, tx scalar
+	ls, rs       []point
+	ipaA, ipaB   scalar
+}
+
+func (p *Params) parseRangeProof(proof []byte, n int) (*rangeProof, error) {
+	if len(proof) != rangeProofSize(p.g, n) {
+		return nil, ErrInvalidRangeProof
+	}
+
+	d := &decoder{g: p.g, b: proof}
+	pr := &rangeProof{
+		a: d.point(), s: d.point(), t1: d.point(), t2: d.point(),
+		taux: d.scalar(), mu: d.scalar(), tx: d.scalar(),
+	}
+
+	for i := 0; i < bits.TrailingZeros(uint(n)); i++ {
+		pr.ls = append(pr.ls, d.point())
+		pr.rs = append(pr.rs, d.point())
+	}
+
+	pr.ipaA, pr.ipaB = d.scalar(), d.scalar()
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	return pr, nil
+}
+
+// decoder decodes the points and scalars of a proof in sequence, it keeps the first error.
+type decoder struct {
+	g   group
+	b   []byte
+	err error
+}
+
+func (d *decoder) point() point {
+	size := d.g.pointSize()
+
+	e, err := d.g.decodePoint(d.b[:size])
+	if err != nil && d.err == nil {
+		d.err = err
+	}
+
+	d.b = d.b[size:]
+
+	return e
+}
+
+func (d *decoder) scalar() scalar {
+	size := d.g.scalarSize()
+
+	s, err := d.g.decodeScalar(d.b[:size])
+	if err != nil && d.err == nil {
+		d.err = err
+	}
+
+	d.b = d.b[size:]
+
+	return s
+}
+
+// proveInnerProduct returns the L and R points of the rounds, and the final scalars a and b, of the inner product
+// argument of P = <a, gs> + <b, hs> + <a, b>*q.
+func proveInnerProduct(t *transcript, gs, hs []point, q point, a, b []scalar) []byte {
+	var proof []byte
+
+	zero := t.g.newScalar(0)
+
+	for n := len(a); n > 1; n /= 2 {
+		m := n / 2
+		cL, cR := innerProduct(zero, a[:m], b[m:]), innerProduct(zero, a[m:], b[:m])
+		l := multiExp(t.g, gs[m:], a[:m]).add(multiExp(t.g, hs[:m], b[m:])).add(q.mul(cL))
+		r := multiExp(t.g, gs[:m], a[m:]).add(multiExp(t.g, hs[m:], b[:m])).add(q.mul(cR))
+
+		proof = append(append(proof, l.bytes()...), r.bytes()...)
+
+		t.appendPoints(l, r)
+		x := t.challenge()
+		xInv := x.inv()
+
+		gs, hs = foldPoints(gs, xInv, x), foldPoints(hs, x, xInv)
+		a, b = foldScalars(a, x, xInv), foldScalars(b, xInv, x)
+	}
+
+	return append(append(proof, a[0].bytes()...), b[0].bytes()...)
+}
+
+// verifyInnerProduct verifies the inner product argument of pr for P = pt.
+func verifyInnerProduct(t *transcript, gs, hs []point, q, pt point, pr *rangeProof) bool {
+	for i := range pr.ls {
+		t.appendPoints(pr.ls[i], pr.rs[i])
+		x := t.challenge()
+
+		if x.isZero() {
+			return false
+		}
+
+		xInv := x.inv()
+
+		// P' = x^2*L + P + x^-2*R
+		pt = pr.ls[i].mul(x.mul(x)).add(pt).add(pr.rs[i].mul(xInv.mul(xInv)))
+		gs, hs = foldPoints(gs, xInv, x), foldPoints(hs, x, xInv)
+	}
+
+	return pt.equal(gs[0].mul(pr.ipaA).add(hs[0].mul(pr.ipaB)).add(q.mul(pr.ipaA.mul(pr.ipaB))))
+}
+
+// foldPoints returns lo*x1 + hi*x2 of the halves lo and hi of v.
+func foldPoints(v []point, x1, x2 scalar) []point {
+	m := len(v) / 2
+	res := make([]point, m)
+
+	for i := range res {
+		res[i] = v[i].mul(x1).add(v[m+i].mul(x2))
+	}
+
+	return res
+}
+
+// foldScalars returns lo*x1 + hi*x2 of the halves lo and hi of v.
+func foldScalars(v []scalar, x1, x2 scalar) []scalar {
+	m := len(v) / 2
+	res := make([]scalar, m)
+
+	for i := range res {
+		res[i] = v[i].mul(x1).add(v[m+i].mul(x2))
+	}
+
+	return res
+}
+
+// primeHs returns the generators H'[i] = y^-i * H[i].
+func (p *Params) primeHs(y scalar, n int) []point {
+	yInv := p.powers(y.inv(), n)
+	hs := make([]point, n)
+
+	for i := range hs {
+		hs[i] = p.hs[i].mul(yInv[i])
+	}
+
+	return hs
+}
+
+// powers returns [1, x, x^2, ..., x^(n-1)].
+func (p *Params) powers(x scalar, n int) []scalar {
+	res := make([]scalar, n)
+	res[0] = p.g.newScalar(1)
+
+	for i := 1; i < n; i++ {
+		res[i] = res[i-1].mul(x)
+	}
+
+	return res
+}
+
+func (p *Params) randomScalars(n int) ([]scalar, error) {
+	res := make([]scalar, n)
+
+	for i := range res {
+		s, err := p.g.randomScalar(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+
+		res[i] = s
+	}
+
+	return res, nil
+}
+
+func innerProduct(zero scalar, a, b []scalar) scalar {
+	res := zero
+
+	for i := range a {
+		res = res.add(a[i].mul(b[i]))
+	}
+
+	return res
+}
+
+func sum(zero scalar, v []scalar) scalar {
+	res := zero
+
+	for _, s := range v {
+		res = res.add(s)
+	}
+
+	return res
+}
+
+func multiExp(g group, bases []point, scalars []scalar) point {
+	res := g.identity()
+
+	for i := range bases {
+		res = res.add(bases[i].mul(scalars[i]))
+	}
+
+	return res
+}
+
+func checkRangeBits(n int) error {
+	if n <= 0 || n > MaxRangeBits || n&(n-1) != 0 {
+		return fmt.Errorf("invalid range bits %d", n)
+	}
+
+	return nil
+}
+
+func rangeProofSize(g group, n int) int {
+	rounds := bits.TrailingZeros(uint(n))
+
+	return (rangeProofPoints+roundPoints*rounds)*g.pointSize() + rangeProofScalars*g.scalarSize()
+}
+
+// transcript is the Fiat-Shamir transcript of a range proof: the challenges are hashes of the curve, range, commitment
+// and proof messages so far.
+type transcript struct {
+	g    group
+	data []byte
+}
+
+func (p *Params) newTranscript(v point, n int) *transcript {
+	data := cryptoutil.LengthPrefix([]byte(rangeProofLabel))
+	data = append(data, cryptoutil.LengthPrefix([]byte(p.curve.String()))...)
+	data = binary.BigEndian.AppendUint32(data, uint32(n))
+
+	t := &transcript{g: p.g, data: data}
+	t.appendPoints(v)
+
+	return t
+}
+
+func (t *transcript) appendPoints(points ...point) {
+	for _, e := range points {
+		t.data = append(t.data, cryptoutil.LengthPrefix(e.bytes())...)
+	}
+}
+
+func (t *transcript) appendScalars(scalars ...scalar) {
+	for _, s := range scalars {
+		t.data = append(t.data, cryptoutil.LengthPrefix(s.bytes())...)
+	}
+}
+
+// challenge returns the next challenge of the transcript, and appends it to the transcript.
+func (t *transcript) challenge() scalar {
+	c := t.g.hashToScalar(t.data, []byte(rangeChallengeDST))
+	t.appendScalars(c)
+
+	return c
+}
@@ -0,0 +1,147 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package commitment_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+	"github.com/trustbloc/kms-go/x"
+	"github.com/trustbloc/kms-go/x/crypto/commitment"
+)
+
+func TestParams_ProveRange(t *testing.T) {
+	x.Enable("crypto/commitment")
+
+	for _, curve := range curves {
+		t.Run(curve.String(), func(t *testing.T) {
+			p, err := commitment.New(curve)
+			require.NoError(t, err)
+
+			r, err := p.RandomBlinding()
+			require.NoError(t, err)
+
+			for _, tc := range []struct {
+				value uint64
+				bits  int
+			}{
+				{0, 1}, {1, 1}, {5, 4}, {255, 8}, {1000, 16}, {math.MaxUint32, 32}, {math.MaxUint64, 64},
+			} {
+				c, err := p.Commit(tc.value, r)
+				require.NoError(t, err)
+
+				proof, err := p.ProveRange(tc.value, r, tc.bits)
+				require.NoError(t, err)
+				require.NoError(t, p.VerifyRange(c, proof, tc.bits))
+
+				// the proof is bound to the range and the commitment.
+				if tc.bits < commitment.MaxRangeBits {
+					require.Error(t, p.VerifyRange(c, proof, 2*tc.bits))
+				}
+
+				other, err := p.Commit(tc.value^1, r)
+				require.NoError(t, err)
+				require.ErrorIs(t, p.VerifyRange(other, proof, tc.bits), commitment.ErrInvalidRangeProof)
+			}
+
+			t.Run("tampered proof", func(t *testing.T) {
+				c, err := p.Commit(1000, r)
+				require.NoError(t, err)
+
+				proof, err := p.ProveRange(1000, r, 16)
+				require.NoError(t, err)
+
+				for _, i := range []int{0, len(proof) / 2, len(proof) - 1} {
+					tampered := append([]byte{}, proof...)
+					tampered[i] ^= 1
+
+					require.ErrorIs(t, p.VerifyRange(c, tampered, 16), commitment.ErrInvalidRangeProof)
+				}
+
+				require.ErrorIs(t, p.VerifyRange(c, proof[1:], 16), commitment.ErrInvalidRangeProof)
+			})
+
+			t.Run("errors", func(t *testing.T) {
+				_, err = p.ProveRange(256, r, 8)
+				require.EqualError(t, err, "commitment: prove range: value out of range")
+
+				_, err = p.ProveRange(1, r, 12)
+				require.EqualError(t, err, "commitment: prove range: invalid range bits 12")
+
+				_, err = p.ProveRange(1, r, 128)
+				require.EqualError(t, err, "commitment: prove range: invalid range bits 128")
+
+				_, err = p.ProveRange(1, []byte("invalid"), 8)
+				require.EqualError(t, err, "commitment: prove range: blinding: invalid scalar")
+
+				c, err := p.Commit(1, r)
+				require.NoError(t, err)
+
+				proof, err := p.ProveRange(1, r, 8)
+				require.NoError(t, err)
+
+				err = p.VerifyRange(c, proof, 0)
+				require.EqualError(t, err, "commitment: verify range: invalid range bits 0")
+
+				err = p.VerifyRange([]byte("invalid"), proof, 8)
+				require.EqualError(t, err, "commitment: verify range: invalid point")
+			})
+		})
+	}
+}
+
+func TestParams_ProveGreaterOrEqual(t *testing.T) {
+	const (
+		// the holder proves that they are 18 years old or more, ages in days.
+		birthDay = 19000
+		today    = 26000
+		minAge   = 18 * 365
+		bits     = 16
+	)
+
+	km := mockkms.NewForTest(t)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	macKeyID, _, err := km.Create(kmsapi.HMACSHA256Tag256Type)
+	require.NoError(t, err)
+
+	factors := newBlindingFactors(t, km, cr, macKeyID)
+
+	for _, curve := range curves {
+		t.Run(curve.String(), func(t *testing.T) {
+			p, err := commitment.New(curve)
+			require.NoError(t, err)
+
+			r, err := factors.BlindingFactor(p, []byte("urn:uuid:credential#age"))
+			require.NoError(t, err)
+
+			// the issuer signs the commitment of the age, the holder proves the predicate on it.
+			c, err := p.Commit(today-birthDay, r)
+			require.NoError(t, err)
+
+			proof, err := p.ProveGreaterOrEqual(today-birthDay, r, minAge, bits)
+			require.NoError(t, err)
+			require.NoError(t, p.VerifyGreaterOrEqual(c, proof, minAge, bits))
+
+			require.ErrorIs(t, p.VerifyGreaterOrEqual(c, proof, minAge+1, bits), commitment.ErrInvalidRangeProof)
+			require.ErrorIs(t, p.VerifyRange(c, proof, bits), commitment.ErrInvalidRangeProof)
+
+			_, err = p.ProveGreaterOrEqual(minAge-1, r, minAge, bits)
+			require.EqualError(t, err, "commitment: prove greater or equal: value is less than minimum")
+
+			_, err = p.ProveGreaterOrEqual(minAge+1<<bits, r, minAge, bits)
+			require.EqualError(t, err, "commitment: prove greater or equal: value out of range")
+		})
+	}
+}
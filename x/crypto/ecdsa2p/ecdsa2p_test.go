@@ -0,0 +1,257 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ecdsa2p
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/trustbloc/kms-go/kms"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	"github.com/trustbloc/kms-go/x"
+	"github.com/trustbloc/kms-go/x/crypto/internal/mpc"
+)
+
+type inMemoryKMSStore struct {
+	keys map[string][]byte
+}
+
+func newInMemoryKMSStore() *inMemoryKMSStore {
+	return &inMemoryKMSStore{keys: make(map[string][]byte)}
+}
+
+func (i *inMemoryKMSStore) Put(keysetID string, key []byte) error {
+	i.keys[keysetID] = key
+
+	return nil
+}
+
+func (i *inMemoryKMSStore) Get(keysetID string) ([]byte, error) {
+	key, found := i.keys[keysetID]
+	if !found {
+		return nil, kmsapi.ErrKeyNotFound
+	}
+
+	return key, nil
+}
+
+func (i *inMemoryKMSStore) Delete(keysetID string) error {
+	delete(i.keys, keysetID)
+
+	return nil
+}
+
+// recordingRemote records the key generation requests of the device.
+type recordingRemote struct {
+	*Server
+	keyGen       *KeyGenRequest
+	keyGenFinish *KeyGenFinishRequest
+}
+
+func (r *recordingRemote) KeyGen(req *KeyGenRequest) (*KeyGenResponse, error) {
+	r.keyGen = req
+
+	return r.Server.KeyGen(req)
+}
+
+func (r *recordingRemote) KeyGenFinish(req *KeyGenFinishRequest) (*KeyGenFinishResponse, error) {
+	r.keyGenFinish = req
+
+	return r.Server.KeyGenFinish(req)
+}
+
+func TestCreateKeyAndSign(t *testing.T) {
+	server := newServer(t, newInMemoryKMSStore())
+	deviceStore := newInMemoryKMSStore()
+	party := newParty(t, deviceStore, server)
+
+	keyID, pub, err := party.CreateKey()
+	require.NoError(t, err)
+	require.NotEmpty(t, keyID)
+
+	stored, err := party.PublicKey(keyID)
+	require.NoError(t, err)
+	require.True(t, pub.Equal(stored))
+
+	for _, msg := range []string{"firmware", "another message", ""} {
+		sig, err := party.SignMPC(keyID, []byte(msg))
+		require.NoError(t, err)
+
+		digest := sha256.Sum256([]byte(msg))
+		require.True(t, ecdsa.VerifyASN1(pub, digest[:], sig))
+	}
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err = party.SignMPC("unknown", []byte("msg"))
+		require.ErrorIs(t, err, kmsapi.ErrKeyNotFound)
+
+		// the device holds the key but not the server.
+		deviceStore.keys["orphan"] = deviceStore.keys[keyID]
+
+		_, err = party.SignMPC("orphan", []byte("msg"))
+		require.ErrorContains(t, err, "remote sign")
+	})
+}
+
+func TestServerChecks(t *testing.T) {
+	server := newServer(t, newInMemoryKMSStore())
+	party := newParty(t, newInMemoryKMSStore(), server)
+
+	keyID, _, err := party.CreateKey()
+	require.NoError(t, err)
+
+	k1, err := mpc.RandomScalar()
+	require.NoError(t, err)
+
+	r1 := mpc.MarshalPoint(mpc.Curve().ScalarBaseMult(k1.Bytes()))
+
+	proof, err := mpc.Prove(domainNonce, k1)
+	require.NoError(t, err)
+
+	com, salt, err := mpc.Commit(domainNonce, r1, proof)
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte("msg"))
+
+	resp, err := server.Sign(&SignRequest{KeyID: keyID, Digest: digest[:], Commitment: com})
+	require.NoError(t, err)
+
+	t.Run("nonce point must match the commitment", func(t *testing.T) {
+		other := mpc.MarshalPoint(mpc.Curve().ScalarBaseMult([]byte{2}))
+
+		_, err = server.SignFinish(&SignFinishRequest{
+			KeyID: keyID, SessionID: resp.SessionID, R1: other, Proof: proof, Salt: salt,
+		})
+		require.ErrorContains(t, err, "commitment mismatch")
+	})
+
+	t.Run("sessions are single use", func(t *testing.T) {
+		_, err = server.SignFinish(&SignFinishRequest{
+			KeyID: keyID, SessionID: resp.SessionID, R1: r1, Proof: proof, Salt: salt,
+		})
+		require.ErrorContains(t, err, "unknown session")
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		_, err = server.Sign(&SignRequest{KeyID: keyID, Digest: []byte("short"), Commitment: com})
+		require.Error(t, err)
+
+		_, err = server.KeyGen(&KeyGenRequest{})
+		require.Error(t, err)
+
+		_, err = server.KeyGenFinish(&KeyGenFinishRequest{KeyID: "unknown"})
+		require.ErrorContains(t, err, "unknown session")
+	})
+	t.Run("not enabled", func(t *testing.T) {
+		x.Disable()
+		defer x.Enable("crypto/ecdsa2p")
+
+		_, err = NewServer(newInMemoryKMSStore(), WithSecretLock(&noop.NoLock{}, ""))
+		require.ErrorIs(t, err, x.ErrNotEnabled)
+
+		_, err = NewParty(newInMemoryKMSStore(), server, WithSecretLock(&noop.NoLock{}, ""))
+		require.ErrorIs(t, err, x.ErrNotEnabled)
+		require.ErrorContains(t, err, "ecdsa2p: new party: ")
+	})
+	t.Run("secret lock is required", func(t *testing.T) {
+		_, err = NewServer(newInMemoryKMSStore())
+		require.ErrorIs(t, err, ErrSecretLockRequired)
+		require.EqualError(t, err, "ecdsa2p: new server: a secret lock is required to store key shares")
+
+		_, err = NewParty(newInMemoryKMSStore(), server)
+		require.ErrorIs(t, err, ErrSecretLockRequired)
+		require.EqualError(t, err, "ecdsa2p: new party: a secret lock is required to store key shares")
+	})
+}
+
+func newServer(t *testing.T, store *inMemoryKMSStore) *Server {
+	t.Helper()
+
+	x.Enable("crypto/ecdsa2p")
+
+	server, err := NewServer(store, WithSecretLock(&noop.NoLock{}, ""))
+	require.NoError(t, err)
+
+	return server
+}
+
+func newParty(t *testing.T, store *inMemoryKMSStore, remote Remote) *Party {
+	t.Helper()
+
+	x.Enable("crypto/ecdsa2p")
+
+	party, err := NewParty(store, remote, WithSecretLock(&noop.NoLock{}, ""))
+	require.NoError(t, err)
+
+	return party
+}
+
+func TestKeyGenProofs(t *testing.T) {
+	serverStore := newInMemoryKMSStore()
+	server := newServer(t, serverStore)
+	remote := &recordingRemote{Server: server}
+
+	keyID, _, err := newParty(t, newInMemoryKMSStore(), remote).CreateKey()
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name   string
+		tamper func(req *KeyGenFinishRequest)
+		err    string
+	}{
+		{
+			name:   "missing paillier modulus proof",
+			tamper: func(req *KeyGenFinishRequest) { req.PaillierProof = nil },
+			err:    "ecdsa2p: keygen finish: paillier modulus: missing paillier modulus proof",
+		},
+		{
+			name: "paillier modulus with a small factor",
+			tamper: func(req *KeyGenFinishRequest) {
+				req.PaillierN = new(big.Int).Mul(new(big.Int).SetBytes(req.PaillierN), big.NewInt(3)).Bytes()
+			},
+			err: "ecdsa2p: keygen finish: paillier modulus: paillier modulus has a small factor 3",
+		},
+		{
+			name:   "missing range proof",
+			tamper: func(req *KeyGenFinishRequest) { req.RangeProof = nil },
+			err:    "ecdsa2p: keygen finish: encrypted share: missing range proof",
+		},
+		{
+			name: "encrypted share is not the device share",
+			tamper: func(req *KeyGenFinishRequest) {
+				// the square of the ciphertext encrypts twice the device share.
+				n := new(big.Int).SetBytes(req.PaillierN)
+				encX := new(big.Int).SetBytes(req.EncryptedX)
+				req.EncryptedX = encX.Exp(encX, big.NewInt(2), n.Mul(n, n)).Bytes()
+			},
+			err: "ecdsa2p: keygen finish: encrypted share: invalid range proof",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// a new session for the recorded device share.
+			resp, e := server.KeyGen(remote.keyGen)
+			require.NoError(t, e)
+
+			req := *remote.keyGenFinish
+			req.KeyID = resp.KeyID
+			tc.tamper(&req)
+
+			_, e = server.KeyGenFinish(&req)
+			require.EqualError(t, e, tc.err)
+
+			_, e = serverStore.Get(resp.KeyID)
+			require.ErrorIs(t, e, kmsapi.ErrKeyNotFound)
+		})
+	}
+
+	_, err = serverStore.Get(keyID)
+	require.NoError(t, err)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ecdsa2p
+
+import "github.com/trustbloc/kms-go/x/crypto/internal/mpc"
+
+// Proof is a non-interactive Schnorr proof of knowledge of the discrete logarithm of a curve point.
+type Proof = mpc.Proof
+
+// PaillierProof is a non-interactive proof that a Paillier modulus is well formed.
+type PaillierProof = mpc.PaillierProof
+
+// RangeProof is a non-interactive proof that a Paillier ciphertext encrypts the discrete logarithm of a curve point,
+// in range.
+type RangeProof = mpc.RangeProof
+
+// Remote is the remote (server) party of the protocol. Server implements it in-process, webkms implements it over
+// HTTP.
+type Remote interface {
+	// KeyGen starts a key generation: the server creates its key share and a key ID.
+	KeyGen(req *KeyGenRequest) (*KeyGenResponse, error)
+	// KeyGenFinish completes the key generation: the server stores its key share and returns the joint public key.
+	KeyGenFinish(req *KeyGenFinishRequest) (*KeyGenFinishResponse, error)
+	// Sign starts a signing session for a digest.
+	Sign(req *SignRequest) (*SignResponse, error)
+	// SignFinish completes a signing session: the server returns its encrypted partial signature.
+	SignFinish(req *SignFinishRequest) (*SignFinishResponse, error)
+}
+
+// KeyGenRequest holds the device commitment to its public key share.
+type KeyGenRequest struct {
+	Commitment []byte `json:"commitment"`
+}
+
+// KeyGenResponse holds the server public key share and its proof.
+type KeyGenResponse struct {
+	KeyID string `json:"keyID"`
+	Q2    []byte `json:"q2"`
+	Proof *Proof `json:"proof"`
+}
+
+// KeyGenFinishRequest opens the device commitment and holds the Paillier encryption of the device share, with the
+// proofs that the Paillier modulus is well formed and that the encrypted share is the discrete logarithm of Q1.
+type KeyGenFinishRequest struct {
+	KeyID         string         `json:"keyID"`
+	Q1            []byte         `json:"q1"`
+	Proof         *Proof         `json:"proof"`
+	Salt          []byte         `json:"salt"`
+	PaillierN     []byte         `json:"paillierN"`
+	PaillierProof *PaillierProof `json:"paillierProof"`
+	EncryptedX    []byte         `json:"encryptedX"`
+	RangeProof    *RangeProof    `json:"rangeProof"`
+}
+
+// KeyGenFinishResponse holds the joint public key (uncompressed P-256 point).
+type KeyGenFinishResponse struct {
+	PublicKey []byte `json:"publicKey"`
+}
+
+// SignRequest holds the digest to sign and the device commitment to its nonce point.
+type SignRequest struct {
+	KeyID      string `json:"keyID"`
+	Digest     []byte `json:"digest"`
+	Commitment []byte `json:"commitment"`
+}
+
+// SignResponse holds the server nonce point and its proof.
+type SignResponse struct {
+	SessionID string `json:"sessionID"`
+	R2        []byte `json:"r2"`
+	Proof     *Proof `json:"proof"`
+}
+
+// SignFinishRequest opens the device nonce point commitment.
+type SignFinishRequest struct {
+	KeyID     string `json:"keyID"`
+	SessionID string `json:"sessionID"`
+	R1        []byte `json:"r1"`
+	Proof     *Proof `json:"proof"`
+	Salt      []byte `json:"salt"`
+}
+
+// SignFinishResponse holds the Paillier encrypted partial signature.
+type SignFinishResponse struct {
+	EncryptedS []byte `json:"encryptedS"`
+}
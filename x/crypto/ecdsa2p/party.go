@@ -0,0 +1,234 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package ecdsa2p implements two-party (2-of-2) ECDSA on P-256 following Lindell's protocol ("Fast Secure Two-Party
+// ECDSA Signing", 2017): the private key is never assembled, the device party (Party) and the remote party (Server)
+// each hold a multiplicative share of it and both must take part to produce a signature.
+//
+// The device share holds the Paillier private key and receives the final signature, which it verifies before
+// returning it. Public key shares and nonce points are exchanged with commitments and Schnorr proofs of knowledge. With
+// the Paillier encryption of its share, the device sends zero-knowledge proofs that the Paillier modulus is well
+// formed and that the ciphertext encrypts the discrete logarithm of its public key share in range, which the server
+// verifies before it stores its share: a malicious device can't learn the server share from the partial signatures.
+package ecdsa2p
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/x"
+	"github.com/trustbloc/kms-go/x/crypto/internal/mpc"
+)
+
+const (
+	domainKeyGen = "ecdsa2p-keygen-p1"
+	domainRange  = "ecdsa2p-range-p1"
+	domainNonce  = "ecdsa2p-nonce-p1"
+
+	domainServerKeyGen = "ecdsa2p-keygen-p2"
+	domainServerNonce  = "ecdsa2p-nonce-p2"
+)
+
+// Party is the device party, its key shares are stored in a kms.Store (usually the LocalKMS store).
+type Party struct {
+	store  kms.Store
+	remote Remote
+	opts   *opts
+}
+
+// NewParty creates a device party storing its key shares in store, encrypted with the secret lock set WithSecretLock,
+// and running the protocol with remote.
+func NewParty(store kms.Store, remote Remote, options ...Opt) (*Party, error) {
+	if err := x.Require("crypto/ecdsa2p"); err != nil {
+		return nil, fmt.Errorf("ecdsa2p: new party: %w", err)
+	}
+
+	o := newOpts(options)
+	if o.secretLock == nil {
+		return nil, fmt.Errorf("ecdsa2p: new party: %w", ErrSecretLockRequired)
+	}
+
+	return &Party{store: store, remote: remote, opts: o}, nil
+}
+
+// CreateKey runs the key generation protocol with the remote party and stores the device share under the returned key
+// ID, which is the same on both parties.
+func (p *Party) CreateKey() (string, *ecdsa.PublicKey, error) { //nolint:funlen
+	x1, err := mpc.RandomScalar()
+	if err != nil {
+		return "", nil, fmt.Errorf("ecdsa2p: create key: %w", err)
+	}
+
+	c := mpc.Curve()
+	q1 := mpc.MarshalPoint(c.ScalarBaseMult(x1.Bytes()))
+
+	proof, err := mpc.Prove(domainKeyGen, x1)
+	if err != nil {
+		return "", nil, fmt.Errorf("ecdsa2p: create key: %w", err)
+	}
+
+	com, salt, err := mpc.Commit(domainKeyGen, q1, proof)
+	if err != nil {
+		return "", nil, fmt.Errorf("ecdsa2p: create key: %w", err)
+	}
+
+	resp, err := p.remote.KeyGen(&KeyGenRequest{Commitment: com})
+	if err != nil {
+		return "", nil, fmt.Errorf("ecdsa2p: create key: remote keygen: %w", err)
+	}
+
+	if err = resp.Proof.Verify(domainServerKeyGen, resp.Q2); err != nil {
+		return "", nil, fmt.Errorf("ecdsa2p: create key: server share: %w", err)
+	}
+
+	q2X, q2Y, _ := mpc.UnmarshalPoint(resp.Q2) //nolint:errcheck // checked by verify
+	pub := mpc.MarshalPoint(c.ScalarMult(q2X, q2Y, x1.Bytes()))
+
+	sk, err := mpc.GeneratePaillierKey()
+	if err != nil {
+		return "", nil, fmt.Errorf("ecdsa2p: create key: %w", err)
+	}
+
+	paillierProof, err := mpc.ProvePaillier(sk)
+	if err != nil {
+		return "", nil, fmt.Errorf("ecdsa2p: create key: %w", err)
+	}
+
+	encX, rangeProof, err := mpc.EncryptInRange(domainRange, sk, x1)
+	if err != nil {
+		return "", nil, fmt.Errorf("ecdsa2p: create key: %w", err)
+	}
+
+	finish, err := p.remote.KeyGenFinish(&KeyGenFinishRequest{
+		KeyID: resp.KeyID, Q1: q1, Proof: proof, Salt: salt, PaillierN: sk.N.Bytes(), PaillierProof: paillierProof,
+		EncryptedX: encX.Bytes(), RangeProof: rangeProof,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("ecdsa2p: create key: remote keygen finish: %w", err)
+	}
+
+	if string(finish.PublicKey) != string(pub) {
+		return "", nil, errors.New("ecdsa2p: create key: public key mismatch")
+	}
+
+	err = putShare(p.store, p.opts, resp.KeyID, &deviceShare{
+		X: x1, PublicKey: pub, N: sk.N, Lambda: sk.Lambda, Mu: sk.Mu,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("ecdsa2p: create key: %w", err)
+	}
+
+	pk, err := toECDSA(pub)
+	if err != nil {
+		return "", nil, fmt.Errorf("ecdsa2p: create key: %w", err)
+	}
+
+	return resp.KeyID, pk, nil
+}
+
+// PublicKey returns the joint public key of keyID.
+func (p *Party) PublicKey(keyID string) (*ecdsa.PublicKey, error) {
+	share := &deviceShare{}
+
+	if err := getShare(p.store, p.opts, keyID, share); err != nil {
+		return nil, fmt.Errorf("ecdsa2p: %w", err)
+	}
+
+	return toECDSA(share.PublicKey)
+}
+
+// SignMPC signs the SHA-256 digest of msg with keyID jointly with the remote party and returns an ASN.1 DER ECDSA
+// signature, as created by kms.ECDSAP256TypeDER keys.
+func (p *Party) SignMPC(keyID string, msg []byte) ([]byte, error) { //nolint:funlen
+	share := &deviceShare{}
+
+	if err := getShare(p.store, p.opts, keyID, share); err != nil {
+		return nil, fmt.Errorf("ecdsa2p: sign: %w", err)
+	}
+
+	c := mpc.Curve()
+	q := c.Params().N
+	digest := sha256.Sum256(msg)
+
+	k1, err := mpc.RandomScalar()
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa2p: sign: %w", err)
+	}
+
+	r1 := mpc.MarshalPoint(c.ScalarBaseMult(k1.Bytes()))
+
+	proof, err := mpc.Prove(domainNonce, k1)
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa2p: sign: %w", err)
+	}
+
+	com, salt, err := mpc.Commit(domainNonce, r1, proof)
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa2p: sign: %w", err)
+	}
+
+	resp, err := p.remote.Sign(&SignRequest{KeyID: keyID, Digest: digest[:], Commitment: com})
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa2p: sign: remote sign: %w", err)
+	}
+
+	if err = resp.Proof.Verify(domainServerNonce, resp.R2); err != nil {
+		return nil, fmt.Errorf("ecdsa2p: sign: server nonce: %w", err)
+	}
+
+	finish, err := p.remote.SignFinish(&SignFinishRequest{
+		KeyID: keyID, SessionID: resp.SessionID, R1: r1, Proof: proof, Salt: salt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa2p: sign: remote sign finish: %w", err)
+	}
+
+	r2X, r2Y, _ := mpc.UnmarshalPoint(resp.R2) //nolint:errcheck // checked by verify
+	rX, _ := c.ScalarMult(r2X, r2Y, k1.Bytes())
+	r := new(big.Int).Mod(rX, q)
+
+	sk := &mpc.PaillierPrivateKey{
+		PaillierPublicKey: *mpc.NewPaillierPublicKey(share.N), Lambda: share.Lambda, Mu: share.Mu,
+	}
+
+	sPrime, err := sk.Decrypt(new(big.Int).SetBytes(finish.EncryptedS))
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa2p: sign: %w", err)
+	}
+
+	s := new(big.Int).Mul(sPrime, new(big.Int).ModInverse(k1, q))
+	s.Mod(s, q)
+
+	// use the low-s form of the signature.
+	if s.Cmp(new(big.Int).Rsh(q, 1)) > 0 {
+		s.Sub(q, s)
+	}
+
+	pub, err := toECDSA(share.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa2p: sign: %w", err)
+	}
+
+	if r.Sign() == 0 || s.Sign() == 0 || !ecdsa.Verify(pub, digest[:], r, s) {
+		return nil, errors.New("ecdsa2p: sign: invalid joint signature")
+	}
+
+	return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+}
+
+func toECDSA(pub []byte) (*ecdsa.PublicKey, error) {
+	x, y, err := mpc.UnmarshalPoint(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ecdsa.PublicKey{Curve: mpc.Curve(), X: x, Y: y}, nil
+}
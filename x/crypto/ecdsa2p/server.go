@@ -0,0 +1,271 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ecdsa2p
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/x"
+	"github.com/trustbloc/kms-go/x/crypto/internal/mpc"
+)
+
+// SessionTimeout is how long a pending key generation or signing session is kept by the Server.
+const SessionTimeout = 5 * time.Minute
+
+type keyGenSession struct {
+	x          *big.Int
+	q2         []byte
+	commitment []byte
+	expires    time.Time
+}
+
+type signSession struct {
+	keyID      string
+	k          *big.Int
+	r2         []byte
+	digest     []byte
+	commitment []byte
+	expires    time.Time
+}
+
+// Server is the remote party of the protocol. Its key shares are stored in a kms.Store, pending sessions are held in
+// memory until they complete or expire.
+type Server struct {
+	store kms.Store
+	opts  *opts
+
+	mu      sync.Mutex
+	keyGens map[string]*keyGenSession
+	signs   map[string]*signSession
+}
+
+// NewServer creates a remote party storing its key shares in store, encrypted with the secret lock set WithSecretLock.
+func NewServer(store kms.Store, options ...Opt) (*Server, error) {
+	if err := x.Require("crypto/ecdsa2p"); err != nil {
+		return nil, fmt.Errorf("ecdsa2p: new server: %w", err)
+	}
+
+	o := newOpts(options)
+	if o.secretLock == nil {
+		return nil, fmt.Errorf("ecdsa2p: new server: %w", ErrSecretLockRequired)
+	}
+
+	return &Server{
+		store:   store,
+		opts:    o,
+		keyGens: map[string]*keyGenSession{},
+		signs:   map[string]*signSession{},
+	}, nil
+}
+
+// expire drops the expired sessions, it must be called with s.mu held.
+func (s *Server) expire(now time.Time) {
+	for id, sess := range s.keyGens {
+		if now.After(sess.expires) {
+			delete(s.keyGens, id)
+		}
+	}
+
+	for id, sess := range s.signs {
+		if now.After(sess.expires) {
+			delete(s.signs, id)
+		}
+	}
+}
+
+// KeyGen creates the server key share and a new key ID.
+func (s *Server) KeyGen(req *KeyGenRequest) (*KeyGenResponse, error) {
+	if len(req.Commitment) != sha256.Size {
+		return nil, errors.New("ecdsa2p: keygen: invalid commitment")
+	}
+
+	x2, err := mpc.RandomScalar()
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa2p: keygen: %w", err)
+	}
+
+	q2 := mpc.MarshalPoint(mpc.Curve().ScalarBaseMult(x2.Bytes()))
+
+	proof, err := mpc.Prove(domainServerKeyGen, x2)
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa2p: keygen: %w", err)
+	}
+
+	keyID, err := mpc.RandomID()
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa2p: keygen: %w", err)
+	}
+
+	now := time.Now()
+
+	s.mu.Lock()
+	s.expire(now)
+	s.keyGens[keyID] = &keyGenSession{x: x2, q2: q2, commitment: req.Commitment, expires: now.Add(SessionTimeout)}
+	s.mu.Unlock()
+
+	return &KeyGenResponse{KeyID: keyID, Q2: q2, Proof: proof}, nil
+}
+
+// KeyGenFinish checks the device opened its commitment and the proofs of its Paillier modulus and encrypted share,
+// and stores the server key share.
+func (s *Server) KeyGenFinish(req *KeyGenFinishRequest) (*KeyGenFinishResponse, error) {
+	s.mu.Lock()
+	sess, ok := s.keyGens[req.KeyID]
+	delete(s.keyGens, req.KeyID)
+	s.mu.Unlock()
+
+	if !ok || time.Now().After(sess.expires) {
+		return nil, fmt.Errorf("ecdsa2p: keygen finish: unknown session '%s'", req.KeyID)
+	}
+
+	if err := mpc.CheckCommitment(domainKeyGen, sess.commitment, req.Q1, req.Proof, req.Salt); err != nil {
+		return nil, fmt.Errorf("ecdsa2p: keygen finish: %w", err)
+	}
+
+	if err := req.Proof.Verify(domainKeyGen, req.Q1); err != nil {
+		return nil, fmt.Errorf("ecdsa2p: keygen finish: device share: %w", err)
+	}
+
+	n := new(big.Int).SetBytes(req.PaillierN)
+	if err := req.PaillierProof.Verify(n); err != nil {
+		return nil, fmt.Errorf("ecdsa2p: keygen finish: paillier modulus: %w", err)
+	}
+
+	encX := new(big.Int).SetBytes(req.EncryptedX)
+	if err := req.RangeProof.Verify(domainRange, n, encX, req.Q1); err != nil {
+		return nil, fmt.Errorf("ecdsa2p: keygen finish: encrypted share: %w", err)
+	}
+
+	q1X, q1Y, _ := mpc.UnmarshalPoint(req.Q1) //nolint:errcheck // checked by verify
+	pub := mpc.MarshalPoint(mpc.Curve().ScalarMult(q1X, q1Y, sess.x.Bytes()))
+
+	err := putShare(s.store, s.opts, req.KeyID, &serverShare{X: sess.x, PublicKey: pub, N: n, EncryptedX: encX})
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa2p: keygen finish: %w", err)
+	}
+
+	return &KeyGenFinishResponse{PublicKey: pub}, nil
+}
+
+// Sign starts a signing session for req.Digest with the key req.KeyID.
+func (s *Server) Sign(req *SignRequest) (*SignResponse, error) {
+	if len(req.Digest) != sha256.Size || len(req.Commitment) != sha256.Size {
+		return nil, errors.New("ecdsa2p: sign: invalid request")
+	}
+
+	if _, err := s.store.Get(req.KeyID); err != nil {
+		return nil, fmt.Errorf("ecdsa2p: sign: get key share '%s': %w", req.KeyID, err)
+	}
+
+	k2, err := mpc.RandomScalar()
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa2p: sign: %w", err)
+	}
+
+	r2 := mpc.MarshalPoint(mpc.Curve().ScalarBaseMult(k2.Bytes()))
+
+	proof, err := mpc.Prove(domainServerNonce, k2)
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa2p: sign: %w", err)
+	}
+
+	sessionID, err := mpc.RandomID()
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa2p: sign: %w", err)
+	}
+
+	now := time.Now()
+
+	s.mu.Lock()
+	s.expire(now)
+	s.signs[sessionID] = &signSession{
+		keyID: req.KeyID, k: k2, r2: r2, digest: req.Digest, commitment: req.Commitment,
+		expires: now.Add(SessionTimeout),
+	}
+	s.mu.Unlock()
+
+	return &SignResponse{SessionID: sessionID, R2: r2, Proof: proof}, nil
+}
+
+// SignFinish checks the device opened its nonce commitment and returns the encrypted partial signature
+// Enc(rho*q + k2^-1*m) + Enc(x1)*(k2^-1*r*x2). A session can only be finished once.
+func (s *Server) SignFinish(req *SignFinishRequest) (*SignFinishResponse, error) {
+	s.mu.Lock()
+	sess, ok := s.signs[req.SessionID]
+	delete(s.signs, req.SessionID)
+	s.mu.Unlock()
+
+	if !ok || sess.keyID != req.KeyID || time.Now().After(sess.expires) {
+		return nil, fmt.Errorf("ecdsa2p: sign finish: unknown session '%s'", req.SessionID)
+	}
+
+	if err := mpc.CheckCommitment(domainNonce, sess.commitment, req.R1, req.Proof, req.Salt); err != nil {
+		return nil, fmt.Errorf("ecdsa2p: sign finish: %w", err)
+	}
+
+	if err := req.Proof.Verify(domainNonce, req.R1); err != nil {
+		return nil, fmt.Errorf("ecdsa2p: sign finish: device nonce: %w", err)
+	}
+
+	share := &serverShare{}
+
+	if err := getShare(s.store, s.opts, req.KeyID, share); err != nil {
+		return nil, fmt.Errorf("ecdsa2p: sign finish: %w", err)
+	}
+
+	encS, err := partialSignature(share, sess, req.R1)
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa2p: sign finish: %w", err)
+	}
+
+	return &SignFinishResponse{EncryptedS: encS.Bytes()}, nil
+}
+
+func partialSignature(share *serverShare, sess *signSession, r1 []byte) (*big.Int, error) {
+	c := mpc.Curve()
+	q := c.Params().N
+
+	r1X, r1Y, err := mpc.UnmarshalPoint(r1)
+	if err != nil {
+		return nil, err
+	}
+
+	rX, _ := c.ScalarMult(r1X, r1Y, sess.k.Bytes())
+	r := new(big.Int).Mod(rX, q)
+	m := new(big.Int).SetBytes(sess.digest)
+	kInv := new(big.Int).ModInverse(sess.k, q)
+
+	rho, err := rand.Int(rand.Reader, new(big.Int).Mul(q, q))
+	if err != nil {
+		return nil, err
+	}
+
+	// plaintext of c1: rho*q + k2^-1*m mod q
+	pt := new(big.Int).Mul(kInv, m)
+	pt.Mod(pt, q)
+	pt.Add(pt, new(big.Int).Mul(rho, q))
+
+	pk := mpc.NewPaillierPublicKey(share.N)
+
+	c1, err := pk.Encrypt(pt)
+	if err != nil {
+		return nil, err
+	}
+
+	v := new(big.Int).Mul(kInv, r)
+	v.Mul(v, share.X)
+	v.Mod(v, q)
+
+	return pk.Add(c1, pk.Mul(share.EncryptedX, v)), nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ecdsa2p
+
+import (
+	"math/big"
+
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/spi/secretlock"
+	"github.com/trustbloc/kms-go/x/crypto/internal/mpc"
+)
+
+// ErrSecretLockRequired is returned by NewParty and NewServer without WithSecretLock, key shares are never stored in
+// plaintext.
+var ErrSecretLockRequired = mpc.ErrSecretLockRequired
+
+// Opt is a Party or Server option.
+type Opt func(o *opts)
+
+type opts struct {
+	secretLock secretlock.Service
+	keyURI     string
+}
+
+// WithSecretLock encrypts the key shares stored in the kms.Store with the secret lock master key keyURI. It is
+// required: NewParty and NewServer fail with ErrSecretLockRequired without it.
+func WithSecretLock(sl secretlock.Service, keyURI string) Opt {
+	return func(o *opts) {
+		o.secretLock = sl
+		o.keyURI = keyURI
+	}
+}
+
+func newOpts(options []Opt) *opts {
+	o := &opts{}
+
+	for _, opt := range options {
+		opt(o)
+	}
+
+	return o
+}
+
+// deviceShare is the key share of the device party.
+type deviceShare struct {
+	X         *big.Int `json:"x"`
+	PublicKey []byte   `json:"publicKey"`
+	N         *big.Int `json:"n"`
+	Lambda    *big.Int `json:"lambda"`
+	Mu        *big.Int `json:"mu"`
+}
+
+// serverShare is the key share of the server party.
+type serverShare struct {
+	X          *big.Int `json:"x"`
+	PublicKey  []byte   `json:"publicKey"`
+	N          *big.Int `json:"n"`
+	EncryptedX *big.Int `json:"encryptedX"`
+}
+
+func putShare(store kms.Store, o *opts, keyID string, share interface{}) error {
+	return mpc.PutShare(store, o.secretLock, o.keyURI, keyID, share)
+}
+
+func getShare(store kms.Store, o *opts, keyID string, share interface{}) error {
+	return mpc.GetShare(store, o.secretLock, o.keyURI, keyID, share)
+}
@@ -0,0 +1,10 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package mpc provides the building blocks shared by the multi-party ECDSA packages (x/crypto/ecdsa2p and
+// x/crypto/threshold): Paillier homomorphic encryption with the zero-knowledge proofs of well formed moduli and in
+// range ciphertexts, Schnorr proofs of knowledge of discrete logarithms and hash commitments on P-256.
+package mpc
@@ -0,0 +1,118 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mpc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaillier(t *testing.T) {
+	sk, err := GeneratePaillierKey()
+	require.NoError(t, err)
+
+	a, b := big.NewInt(1234), big.NewInt(5678)
+
+	ca, err := sk.Encrypt(a)
+	require.NoError(t, err)
+
+	cb, err := sk.Encrypt(b)
+	require.NoError(t, err)
+
+	sum, err := sk.Decrypt(sk.Add(ca, cb))
+	require.NoError(t, err)
+	require.Equal(t, int64(1234+5678), sum.Int64())
+
+	prod, err := sk.Decrypt(sk.Mul(ca, big.NewInt(3)))
+	require.NoError(t, err)
+	require.Equal(t, int64(1234*3), prod.Int64())
+
+	_, err = sk.Encrypt(sk.N)
+	require.Error(t, err)
+
+	_, err = sk.Decrypt(big.NewInt(0))
+	require.Error(t, err)
+}
+
+func TestProof(t *testing.T) {
+	x, err := RandomScalar()
+	require.NoError(t, err)
+
+	point := MarshalPoint(Curve().ScalarBaseMult(x.Bytes()))
+
+	p, err := Prove("domain", x)
+	require.NoError(t, err)
+	require.NoError(t, p.Verify("domain", point))
+	require.Error(t, p.Verify("other domain", point))
+
+	other := MarshalPoint(Curve().ScalarBaseMult([]byte{1}))
+	require.Error(t, p.Verify("domain", other))
+
+	var nilProof *Proof
+	require.Error(t, nilProof.Verify("domain", point))
+}
+
+func TestPaillierProof(t *testing.T) {
+	sk, err := GeneratePaillierKey()
+	require.NoError(t, err)
+
+	p, err := ProvePaillier(sk)
+	require.NoError(t, err)
+	require.NoError(t, p.Verify(sk.N))
+
+	other, err := GeneratePaillierKey()
+	require.NoError(t, err)
+	require.EqualError(t, p.Verify(other.N), "invalid paillier modulus proof")
+
+	// a modulus with a square factor is not coprime with phi(N).
+	squared := new(big.Int).Mul(sk.p, sk.p)
+	require.EqualError(t, p.Verify(squared.Mul(squared, big.NewInt(65537*65539))), "invalid paillier modulus proof")
+
+	withSmallFactor := new(big.Int).Mul(sk.N, big.NewInt(3))
+	require.EqualError(t, p.Verify(withSmallFactor), "paillier modulus has a small factor 3")
+
+	require.EqualError(t, p.Verify(big.NewInt(15)), "invalid paillier modulus size")
+	require.EqualError(t, (&PaillierProof{}).Verify(sk.N), "missing paillier modulus proof")
+}
+
+func TestRangeProof(t *testing.T) {
+	sk, err := GeneratePaillierKey()
+	require.NoError(t, err)
+
+	x, err := RandomScalar()
+	require.NoError(t, err)
+
+	point := MarshalPoint(Curve().ScalarBaseMult(x.Bytes()))
+
+	c, p, err := EncryptInRange("domain", sk, x)
+	require.NoError(t, err)
+	require.NoError(t, p.Verify("domain", sk.N, c, point))
+
+	pt, err := sk.Decrypt(c)
+	require.NoError(t, err)
+	require.Zero(t, pt.Cmp(x))
+
+	require.EqualError(t, p.Verify("other domain", sk.N, c, point), "invalid range proof")
+
+	other := MarshalPoint(Curve().ScalarBaseMult([]byte{1}))
+	require.EqualError(t, p.Verify("domain", sk.N, c, other), "invalid range proof")
+
+	// another ciphertext of x changes the challenges.
+	reencrypted := sk.Add(c, sk.encrypt(big.NewInt(0), sk.nthPower(big.NewInt(2))))
+	require.EqualError(t, p.Verify("domain", sk.N, reencrypted, point), "invalid range proof")
+
+	// the points of the rounds still match, the batch equation of the ciphertexts does not.
+	p.Z2[0] = big.NewInt(2).Bytes()
+	require.EqualError(t, p.Verify("domain", sk.N, c, point), "invalid range proof")
+
+	_, _, err = EncryptInRange("domain", sk, Curve().Params().N)
+	require.EqualError(t, err, "encrypt in range: plaintext is not a scalar")
+
+	require.EqualError(t, (&RangeProof{}).Verify("domain", sk.N, c, point), "missing range proof")
+}
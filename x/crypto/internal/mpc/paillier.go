@@ -0,0 +1,164 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mpc
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// PaillierBits is the size of the Paillier modulus, large enough for the homomorphic signing operations on P-256.
+const PaillierBits = 2048
+
+var one = big.NewInt(1) //nolint:gochecknoglobals
+
+// PaillierPublicKey is a Paillier public key with generator n+1.
+type PaillierPublicKey struct {
+	N  *big.Int
+	n2 *big.Int
+}
+
+// PaillierPrivateKey is a Paillier private key. The primes of the modulus are only set for generated keys, they speed
+// up the proofs of ProvePaillier and EncryptInRange; they are not stored with the key shares.
+type PaillierPrivateKey struct {
+	PaillierPublicKey
+	Lambda *big.Int
+	Mu     *big.Int
+	p, q   *big.Int
+}
+
+// NewPaillierPublicKey returns the Paillier public key with modulus n.
+func NewPaillierPublicKey(n *big.Int) *PaillierPublicKey {
+	return &PaillierPublicKey{N: n, n2: new(big.Int).Mul(n, n)}
+}
+
+// GeneratePaillierKey generates a Paillier key with a 2048 bit modulus.
+func GeneratePaillierKey() (*PaillierPrivateKey, error) {
+	for {
+		p, err := rand.Prime(rand.Reader, PaillierBits/2)
+		if err != nil {
+			return nil, fmt.Errorf("generate paillier prime: %w", err)
+		}
+
+		q, err := rand.Prime(rand.Reader, PaillierBits/2)
+		if err != nil {
+			return nil, fmt.Errorf("generate paillier prime: %w", err)
+		}
+
+		if p.Cmp(q) == 0 {
+			continue
+		}
+
+		n := new(big.Int).Mul(p, q)
+		if n.BitLen() != PaillierBits {
+			continue
+		}
+
+		pm1 := new(big.Int).Sub(p, one)
+		qm1 := new(big.Int).Sub(q, one)
+		phi := new(big.Int).Mul(pm1, qm1)
+
+		// lambda = lcm(p-1, q-1)
+		lambda := new(big.Int).Div(phi, new(big.Int).GCD(nil, nil, pm1, qm1))
+
+		mu := new(big.Int).ModInverse(lambda, n)
+		if mu == nil {
+			continue
+		}
+
+		return &PaillierPrivateKey{PaillierPublicKey: *NewPaillierPublicKey(n), Lambda: lambda, Mu: mu, p: p, q: q}, nil
+	}
+}
+
+func (pk *PaillierPublicKey) randomUnit() (*big.Int, error) {
+	for {
+		r, err := rand.Int(rand.Reader, pk.N)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.Sign() > 0 && new(big.Int).GCD(nil, nil, r, pk.N).Cmp(one) == 0 {
+			return r, nil
+		}
+	}
+}
+
+// Encrypt returns (1+n)^m * r^n mod n^2 = (1 + m*n) * r^n mod n^2.
+func (pk *PaillierPublicKey) Encrypt(m *big.Int) (*big.Int, error) {
+	if m.Sign() < 0 || m.Cmp(pk.N) >= 0 {
+		return nil, errors.New("paillier plaintext out of range")
+	}
+
+	r, err := pk.randomUnit()
+	if err != nil {
+		return nil, fmt.Errorf("paillier encrypt: %w", err)
+	}
+
+	return pk.encrypt(m, new(big.Int).Exp(r, pk.N, pk.n2)), nil
+}
+
+// encrypt returns (1 + m*n) * rN mod n^2, rN is the n-th power of the encryption randomness.
+func (pk *PaillierPublicKey) encrypt(m, rN *big.Int) *big.Int {
+	c := new(big.Int).Mul(m, pk.N)
+	c.Add(c, one)
+	c.Mul(c, rN)
+
+	return c.Mod(c, pk.n2)
+}
+
+// nthPower returns r^n mod n^2, computed modulo p^2 and q^2 when the primes of the modulus are known.
+func (sk *PaillierPrivateKey) nthPower(r *big.Int) *big.Int {
+	if sk.p == nil || sk.q == nil {
+		return new(big.Int).Exp(r, sk.N, sk.n2)
+	}
+
+	p2 := new(big.Int).Mul(sk.p, sk.p)
+	q2 := new(big.Int).Mul(sk.q, sk.q)
+
+	// r^n = a mod p^2 and b mod q^2, r^n = b + q^2 * ((a - b) * (q^2)^-1 mod p^2) mod n^2.
+	a := new(big.Int).Exp(r, sk.N, p2)
+	b := new(big.Int).Exp(r, sk.N, q2)
+
+	h := new(big.Int).Sub(a, b)
+	h.Mul(h, new(big.Int).ModInverse(q2, p2))
+	h.Mod(h, p2)
+
+	return h.Mul(h, q2).Add(h, b)
+}
+
+// Add returns an encryption of the sum of the plaintexts of c1 and c2.
+func (pk *PaillierPublicKey) Add(c1, c2 *big.Int) *big.Int {
+	c := new(big.Int).Mul(c1, c2)
+
+	return c.Mod(c, pk.n2)
+}
+
+// Mul returns an encryption of the plaintext of c multiplied by k.
+func (pk *PaillierPublicKey) Mul(c, k *big.Int) *big.Int {
+	return new(big.Int).Exp(c, k, pk.n2)
+}
+
+// ValidCiphertext checks c is in the Paillier ciphertext space of pk.
+func (pk *PaillierPublicKey) ValidCiphertext(c *big.Int) bool {
+	return c.Sign() > 0 && c.Cmp(pk.n2) < 0 && new(big.Int).GCD(nil, nil, c, pk.N).Cmp(one) == 0
+}
+
+// Decrypt returns L(c^lambda mod n^2) * mu mod n with L(x) = (x-1)/n.
+func (sk *PaillierPrivateKey) Decrypt(c *big.Int) (*big.Int, error) {
+	if !sk.ValidCiphertext(c) {
+		return nil, errors.New("invalid paillier ciphertext")
+	}
+
+	m := new(big.Int).Exp(c, sk.Lambda, sk.n2)
+	m.Sub(m, one)
+	m.Div(m, sk.N)
+	m.Mul(m, sk.Mu)
+
+	return m.Mod(m, sk.N), nil
+}
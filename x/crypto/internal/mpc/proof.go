@@ -0,0 +1,152 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mpc
+
+import (
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/big"
+)
+
+const saltSize = 32
+
+// Proof is a non-interactive Schnorr proof of knowledge of the discrete logarithm of a curve point.
+type Proof struct {
+	T []byte `json:"t"`
+	Z []byte `json:"z"`
+}
+
+// Curve returns the curve of the MPC protocols, P-256.
+func Curve() elliptic.Curve {
+	return elliptic.P256()
+}
+
+// RandomScalar returns a random non zero scalar of Curve().
+func RandomScalar() (*big.Int, error) {
+	q := Curve().Params().N
+
+	for {
+		k, err := rand.Int(rand.Reader, q)
+		if err != nil {
+			return nil, err
+		}
+
+		if k.Sign() > 0 {
+			return k, nil
+		}
+	}
+}
+
+// MarshalPoint returns the uncompressed encoding of a Curve() point.
+func MarshalPoint(x, y *big.Int) []byte {
+	return elliptic.Marshal(Curve(), x, y) //nolint:staticcheck // uncompressed point encoding
+}
+
+// UnmarshalPoint parses a point encoded with MarshalPoint, checking it is on Curve().
+func UnmarshalPoint(b []byte) (*big.Int, *big.Int, error) {
+	x, y := elliptic.Unmarshal(Curve(), b) //nolint:staticcheck // checks the point is on the curve
+	if x == nil {
+		return nil, nil, errors.New("invalid curve point")
+	}
+
+	return x, y, nil
+}
+
+func proofChallenge(domain string, point, t []byte) *big.Int {
+	h := sha256.New()
+	h.Write([]byte(domain))
+	h.Write(point)
+	h.Write(t)
+
+	e := new(big.Int).SetBytes(h.Sum(nil))
+
+	return e.Mod(e, Curve().Params().N)
+}
+
+// Prove creates a proof of knowledge of x for the point x*G, bound to domain.
+func Prove(domain string, x *big.Int) (*Proof, error) {
+	k, err := RandomScalar()
+	if err != nil {
+		return nil, err
+	}
+
+	c := Curve()
+	tX, tY := c.ScalarBaseMult(k.Bytes())
+	t := MarshalPoint(tX, tY)
+	e := proofChallenge(domain, MarshalPoint(c.ScalarBaseMult(x.Bytes())), t)
+
+	z := new(big.Int).Mul(e, x)
+	z.Add(z, k)
+	z.Mod(z, c.Params().N)
+
+	return &Proof{T: t, Z: z.Bytes()}, nil
+}
+
+// Verify checks p proves knowledge of the discrete logarithm of point: z*G == T + e*point.
+func (p *Proof) Verify(domain string, point []byte) error {
+	if p == nil {
+		return errors.New("missing proof")
+	}
+
+	c := Curve()
+
+	pX, pY, err := UnmarshalPoint(point)
+	if err != nil {
+		return err
+	}
+
+	tX, tY, err := UnmarshalPoint(p.T)
+	if err != nil {
+		return err
+	}
+
+	e := proofChallenge(domain, point, p.T)
+	lX, lY := c.ScalarBaseMult(p.Z)
+	eX, eY := c.ScalarMult(pX, pY, e.Bytes())
+	rX, rY := c.Add(tX, tY, eX, eY)
+
+	if lX.Cmp(rX) != 0 || lY.Cmp(rY) != 0 {
+		return errors.New("invalid proof of knowledge")
+	}
+
+	return nil
+}
+
+// Commit returns a hash commitment to point and its proof, with a random salt to open it.
+func Commit(domain string, point []byte, p *Proof) ([]byte, []byte, error) {
+	salt := make([]byte, saltSize)
+
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, nil, err
+	}
+
+	return commitment(domain, point, p, salt), salt, nil
+}
+
+func commitment(domain string, point []byte, p *Proof, salt []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte(domain))
+	h.Write(salt)
+	h.Write(point)
+	h.Write(p.T)
+	h.Write(p.Z)
+
+	return h.Sum(nil)
+}
+
+// CheckCommitment checks c opens to point and p with salt.
+func CheckCommitment(domain string, c, point []byte, p *Proof, salt []byte) error {
+	if p == nil || !hmac.Equal(c, commitment(domain, point, p, salt)) {
+		return errors.New("commitment mismatch")
+	}
+
+	return nil
+}
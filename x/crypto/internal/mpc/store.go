@@ -0,0 +1,92 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mpc
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/spi/secretlock"
+)
+
+// ErrSecretLockRequired is returned when key shares would be stored or read without a secret lock: like the LocalKMS
+// keysets, key shares are never stored in plaintext.
+var ErrSecretLockRequired = errors.New("a secret lock is required to store key shares")
+
+// RandomID returns a random key or session ID.
+func RandomID() (string, error) {
+	b := make([]byte, 16) //nolint:gomnd
+
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// PutShare stores the JSON encoding of share under keyID, encrypted with the secret lock master key keyURI (keyID is
+// the additional authenticated data). It fails with ErrSecretLockRequired if sl is nil.
+func PutShare(store kms.Store, sl secretlock.Service, keyURI, keyID string, share interface{}) error {
+	if sl == nil {
+		return ErrSecretLockRequired
+	}
+
+	b, err := json.Marshal(share)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sl.Encrypt(keyURI, &secretlock.EncryptRequest{
+		Plaintext:                   base64.URLEncoding.EncodeToString(b),
+		AdditionalAuthenticatedData: base64.URLEncoding.EncodeToString([]byte(keyID)),
+	})
+	if err != nil {
+		return fmt.Errorf("encrypt key share: %w", err)
+	}
+
+	if err = store.Put(keyID, []byte(resp.Ciphertext)); err != nil {
+		return fmt.Errorf("store key share: %w", err)
+	}
+
+	return nil
+}
+
+// GetShare reads the share stored by PutShare under keyID. It fails with ErrSecretLockRequired if sl is nil.
+func GetShare(store kms.Store, sl secretlock.Service, keyURI, keyID string, share interface{}) error {
+	if sl == nil {
+		return ErrSecretLockRequired
+	}
+
+	b, err := store.Get(keyID)
+	if err != nil {
+		return fmt.Errorf("get key share '%s': %w", keyID, err)
+	}
+
+	resp, err := sl.Decrypt(keyURI, &secretlock.DecryptRequest{
+		Ciphertext:                  string(b),
+		AdditionalAuthenticatedData: base64.URLEncoding.EncodeToString([]byte(keyID)),
+	})
+	if err != nil {
+		return fmt.Errorf("decrypt key share: %w", err)
+	}
+
+	b, err = base64.URLEncoding.DecodeString(resp.Plaintext)
+	if err != nil {
+		return fmt.Errorf("decode key share: %w", err)
+	}
+
+	if err = json.Unmarshal(b, share); err != nil {
+		return fmt.Errorf("unmarshal key share: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,341 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mpc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+const (
+	// paillierProofRounds N-th roots prove the modulus has no prime factor dividing phi(N) larger than
+	// smallPrimeBound with a soundness error of 2^-128, the smaller factors are checked by trial division.
+	paillierProofRounds = 8
+	smallPrimeBound     = 1 << 16
+
+	// rangeProofRounds binary challenge rounds prove a ciphertext plaintext is in range with a soundness error of
+	// 2^-128. The masks of the plaintext are rangeSlack bits larger than the curve order.
+	rangeProofRounds = 128
+	rangeSlack       = 128
+	batchBits        = 128
+
+	domainPaillierProof = "mpc-paillier-modulus"
+)
+
+// PaillierProof is a non-interactive proof that a Paillier modulus N is well formed, gcd(N, phi(N)) = 1: it holds
+// N-th roots modulo N of values derived from N (Goldberg et al., "Efficient Noninteractive Certification of RSA
+// Moduli and Beyond", 2019).
+type PaillierProof struct {
+	Sigma [][]byte `json:"sigma"`
+}
+
+// ProvePaillier creates the proof that the modulus of sk is well formed.
+func ProvePaillier(sk *PaillierPrivateKey) (*PaillierProof, error) {
+	nInv := new(big.Int).ModInverse(sk.N, sk.Lambda)
+	if nInv == nil {
+		return nil, errors.New("prove paillier modulus: modulus is not coprime with lambda")
+	}
+
+	proof := &PaillierProof{Sigma: make([][]byte, paillierProofRounds)}
+
+	for i := range proof.Sigma {
+		proof.Sigma[i] = new(big.Int).Exp(paillierChallenge(sk.N, i), nInv, sk.N).Bytes()
+	}
+
+	return proof, nil
+}
+
+// Verify checks p proves the Paillier modulus n is well formed and at least PaillierBits long. It must be verified
+// before the RangeProofs of ciphertexts of n.
+func (p *PaillierProof) Verify(n *big.Int) error {
+	if n.BitLen() < PaillierBits || n.Bit(0) == 0 {
+		return errors.New("invalid paillier modulus size")
+	}
+
+	if p == nil || len(p.Sigma) != paillierProofRounds {
+		return errors.New("missing paillier modulus proof")
+	}
+
+	if f := smallFactor(n); f != 0 {
+		return fmt.Errorf("paillier modulus has a small factor %d", f)
+	}
+
+	for i, b := range p.Sigma {
+		sigma := new(big.Int).SetBytes(b)
+
+		if sigma.Sign() == 0 || sigma.Cmp(n) >= 0 ||
+			new(big.Int).Exp(sigma, n, n).Cmp(paillierChallenge(n, i)) != 0 {
+			return errors.New("invalid paillier modulus proof")
+		}
+	}
+
+	return nil
+}
+
+// paillierChallenge returns the i-th value of Z_n the modulus proof of n holds the n-th root of.
+func paillierChallenge(n *big.Int, i int) *big.Int {
+	// hash to twice the size of n, to make the challenge statistically uniform modulo n.
+	out := make([]byte, 0, 2*len(n.Bytes())+sha256.Size)
+
+	for ctr := uint32(0); len(out) < cap(out)-sha256.Size; ctr++ {
+		h := sha256.New()
+		h.Write([]byte(domainPaillierProof))
+		h.Write(n.Bytes())
+		_ = binary.Write(h, binary.BigEndian, uint32(i))
+		_ = binary.Write(h, binary.BigEndian, ctr)
+		out = h.Sum(out)
+	}
+
+	e := new(big.Int).SetBytes(out)
+
+	return e.Mod(e, n)
+}
+
+// smallFactor returns the smallest prime factor of n below smallPrimeBound, or 0.
+func smallFactor(n *big.Int) int64 {
+	composite := make([]bool, smallPrimeBound)
+	d, m := new(big.Int), new(big.Int)
+
+	for i := int64(2); i < smallPrimeBound; i++ {
+		if composite[i] {
+			continue
+		}
+
+		for j := i * i; j < smallPrimeBound; j += i {
+			composite[j] = true
+		}
+
+		if m.Mod(n, d.SetInt64(i)).Sign() == 0 {
+			return i
+		}
+	}
+
+	return 0
+}
+
+// RangeProof is a non-interactive zero-knowledge proof that a Paillier ciphertext c encrypts the discrete logarithm
+// x of a curve point Q, with |x| smaller than the curve order times 2^129. It repeats a binary challenge sigma
+// protocol: the prover sends A = Enc(alpha; beta) and R = alpha*G, and returns z1 = alpha + e*x and
+// z2 = beta * r^e mod N for the challenge bit e.
+type RangeProof struct {
+	A  [][]byte `json:"a"`
+	R  [][]byte `json:"r"`
+	Z1 [][]byte `json:"z1"`
+	Z2 [][]byte `json:"z2"`
+}
+
+// EncryptInRange encrypts x, a scalar of Curve(), with sk and returns the ciphertext with the proof, bound to domain,
+// that it encrypts the discrete logarithm of x*G in range.
+func EncryptInRange(domain string, sk *PaillierPrivateKey, x *big.Int) (*big.Int, *RangeProof, error) {
+	g := Curve()
+
+	if x.Sign() < 0 || x.Cmp(g.Params().N) >= 0 {
+		return nil, nil, errors.New("encrypt in range: plaintext is not a scalar")
+	}
+
+	r, err := sk.randomUnit()
+	if err != nil {
+		return nil, nil, fmt.Errorf("encrypt in range: %w", err)
+	}
+
+	c := sk.encrypt(x, sk.nthPower(r))
+	bound := rangeBound()
+	proof := &RangeProof{}
+	alphas := make([]*big.Int, rangeProofRounds)
+	betas := make([]*big.Int, rangeProofRounds)
+
+	for i := range alphas {
+		if alphas[i], err = rand.Int(rand.Reader, bound); err != nil {
+			return nil, nil, fmt.Errorf("encrypt in range: %w", err)
+		}
+
+		if betas[i], err = sk.randomUnit(); err != nil {
+			return nil, nil, fmt.Errorf("encrypt in range: %w", err)
+		}
+
+		proof.A = append(proof.A, sk.encrypt(alphas[i], sk.nthPower(betas[i])).Bytes())
+		proof.R = append(proof.R, MarshalPoint(g.ScalarBaseMult(alphas[i].Bytes())))
+	}
+
+	e := rangeChallenge(domain, sk.N, c, MarshalPoint(g.ScalarBaseMult(x.Bytes())), proof)
+
+	for i := range alphas {
+		z1, z2 := alphas[i], betas[i]
+
+		if challengeBit(e, i) {
+			z1 = new(big.Int).Add(z1, x)
+			z2 = new(big.Int).Mul(z2, r)
+			z2.Mod(z2, sk.N)
+		}
+
+		proof.Z1 = append(proof.Z1, z1.Bytes())
+		proof.Z2 = append(proof.Z2, z2.Bytes())
+	}
+
+	return c, proof, nil
+}
+
+// rangeBound is the bound of the masks of the plaintext, the curve order times 2^rangeSlack.
+func rangeBound() *big.Int {
+	return new(big.Int).Lsh(Curve().Params().N, rangeSlack)
+}
+
+// rangeChallenge returns the challenge bits of the proof of the ciphertext c of point, hash of its first messages.
+func rangeChallenge(domain string, n, c *big.Int, point []byte, p *RangeProof) []byte {
+	h := sha256.New()
+	h.Write([]byte(domain))
+	h.Write(n.Bytes())
+	h.Write(c.Bytes())
+	h.Write(point)
+
+	for i := range p.A {
+		h.Write(p.A[i])
+		h.Write(p.R[i])
+	}
+
+	// sha256.Size bytes hold 2 * rangeProofRounds bits, only the first rangeProofRounds are used.
+	return h.Sum(nil)
+}
+
+func challengeBit(e []byte, i int) bool {
+	return e[i/8]>>(i%8)&1 == 1
+}
+
+// Verify checks p proves the ciphertext c of the Paillier modulus n encrypts the discrete logarithm of point, in
+// range. The modulus must be checked with its PaillierProof first.
+func (p *RangeProof) Verify(domain string, n, c *big.Int, point []byte) error {
+	if p == nil || len(p.A) != rangeProofRounds || len(p.R) != rangeProofRounds ||
+		len(p.Z1) != rangeProofRounds || len(p.Z2) != rangeProofRounds {
+		return errors.New("missing range proof")
+	}
+
+	pk := NewPaillierPublicKey(n)
+
+	if !pk.ValidCiphertext(c) {
+		return errors.New("invalid ciphertext")
+	}
+
+	qX, qY, err := UnmarshalPoint(point)
+	if err != nil {
+		return err
+	}
+
+	e := rangeChallenge(domain, n, c, point, p)
+
+	if err = p.verifyPoints(e, qX, qY); err != nil {
+		return err
+	}
+
+	return p.verifyCiphertexts(pk, e, c)
+}
+
+// verifyPoints checks z1*G = R + e*Q for every round, with z1 in range.
+func (p *RangeProof) verifyPoints(e []byte, qX, qY *big.Int) error {
+	g := Curve()
+	bound := new(big.Int).Lsh(rangeBound(), 1)
+
+	for i := range p.Z1 {
+		z1 := new(big.Int).SetBytes(p.Z1[i])
+		if z1.Cmp(bound) >= 0 {
+			return errors.New("range proof response out of range")
+		}
+
+		rX, rY, err := UnmarshalPoint(p.R[i])
+		if err != nil {
+			return err
+		}
+
+		if challengeBit(e, i) {
+			rX, rY = g.Add(rX, rY, qX, qY)
+		}
+
+		// z1 is reduced modulo the curve order as ScalarBaseMult expects a scalar.
+		lX, lY := g.ScalarBaseMult(new(big.Int).Mod(z1, g.Params().N).Bytes())
+
+		if lX.Cmp(rX) != 0 || lY.Cmp(rY) != 0 {
+			return errors.New("invalid range proof")
+		}
+	}
+
+	return nil
+}
+
+// verifyCiphertexts checks Enc(z1; z2) = A * c^e for every round, as a single batch equation with random weights w:
+// (prod z2^w)^N * (1+N)^(sum w*z1) = prod A^w * c^(sum of the w of the rounds with e = 1). As the modulus is well
+// formed, it fails for a wrong round with a probability of 1 - 2^-batchBits.
+func (p *RangeProof) verifyCiphertexts(pk *PaillierPublicKey, e []byte, c *big.Int) error {
+	weights := make([]*big.Int, rangeProofRounds)
+	as := make([]*big.Int, rangeProofRounds)
+	z2s := make([]*big.Int, rangeProofRounds)
+	sumZ1, sumE := new(big.Int), new(big.Int)
+	weightBound := new(big.Int).Lsh(one, batchBits)
+
+	for i := range weights {
+		w, err := rand.Int(rand.Reader, weightBound)
+		if err != nil {
+			return fmt.Errorf("verify range proof: %w", err)
+		}
+
+		as[i] = new(big.Int).SetBytes(p.A[i])
+		z2s[i] = new(big.Int).SetBytes(p.Z2[i])
+
+		if !pk.ValidCiphertext(as[i]) || z2s[i].Cmp(pk.N) >= 0 ||
+			new(big.Int).GCD(nil, nil, z2s[i], pk.N).Cmp(one) != 0 {
+			return errors.New("invalid range proof")
+		}
+
+		weights[i] = w
+		sumZ1.Add(sumZ1, new(big.Int).Mul(w, new(big.Int).SetBytes(p.Z1[i])))
+
+		if challengeBit(e, i) {
+			sumE.Add(sumE, w)
+		}
+	}
+
+	// z2^N mod N^2 only depends on z2 mod N.
+	left := new(big.Int).Exp(multiExp(z2s, weights, pk.N), pk.N, pk.n2)
+	left = pk.encrypt(sumZ1.Mod(sumZ1, pk.N), left)
+
+	right := multiExp(as, weights, pk.n2)
+	right.Mul(right, new(big.Int).Exp(c, sumE, pk.n2))
+	right.Mod(right, pk.n2)
+
+	if left.Cmp(right) != 0 {
+		return errors.New("invalid range proof")
+	}
+
+	return nil
+}
+
+// multiExp returns the product of bases[i]^exps[i] mod m, sharing the squarings of the exponentiations.
+func multiExp(bases, exps []*big.Int, m *big.Int) *big.Int {
+	bits := 0
+
+	for _, e := range exps {
+		if e.BitLen() > bits {
+			bits = e.BitLen()
+		}
+	}
+
+	acc := big.NewInt(1)
+
+	for b := bits - 1; b >= 0; b-- {
+		acc.Mul(acc, acc).Mod(acc, m)
+
+		for i, e := range exps {
+			if e.Bit(b) == 1 {
+				acc.Mul(acc, bases[i]).Mod(acc, m)
+			}
+		}
+	}
+
+	return acc
+}
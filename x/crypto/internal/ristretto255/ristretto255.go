@@ -0,0 +1,147 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package ristretto255 provides the ristretto255 group functions shared by the OPRF and OPAQUE packages (x/crypto/oprf
+// and x/crypto/opaque): hashing to the group and to scalars as RFC 9380 and RFC 9497 do, and the strict
+// deserializations of elements and scalars.
+package ristretto255
+
+import (
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+
+	"github.com/bwesterb/go-ristretto"
+)
+
+const (
+	// ElementSize is the size in bytes of a serialized ristretto255 element.
+	ElementSize = 32
+	// ScalarSize is the size in bytes of a serialized ristretto255 scalar.
+	ScalarSize = 32
+
+	uniformSize = 64
+)
+
+var (
+	// ErrInvalidElement is returned when an element can't be deserialized.
+	ErrInvalidElement = errors.New("invalid element")
+	// ErrInvalidScalar is returned when a scalar can't be deserialized.
+	ErrInvalidScalar = errors.New("invalid scalar")
+)
+
+// HashToGroup is the hash_to_ristretto255 function of RFC 9380, with expand_message_xmd and SHA-512.
+func HashToGroup(msg, dst []byte) *ristretto.Point {
+	uniform := expandMessageXMD(msg, dst, uniformSize)
+
+	var b1, b2 [32]byte
+
+	copy(b1[:], uniform[:32])
+	copy(b2[:], uniform[32:])
+
+	var p1, p2 ristretto.Point
+
+	p1.SetElligator(&b1)
+	p2.SetElligator(&b2)
+
+	return new(ristretto.Point).Add(&p1, &p2)
+}
+
+// HashToScalar is the HashToScalar function of RFC 9497 for ristretto255: the 64 bytes of expand_message_xmd with
+// SHA-512, little endian, mod the group order.
+func HashToScalar(msg, dst []byte) *ristretto.Scalar {
+	var uniform [uniformSize]byte
+
+	copy(uniform[:], expandMessageXMD(msg, dst, uniformSize))
+
+	return new(ristretto.Scalar).SetReduced(&uniform)
+}
+
+// expandMessageXMD is the expand_message_xmd function of RFC 9380 section 5.3.1 with SHA-512, for DSTs of 255 bytes
+// at most.
+func expandMessageXMD(msg, dst []byte, size int) []byte {
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+	ell := (size + sha512.Size - 1) / sha512.Size
+
+	// b_0 = H(Z_pad || msg || I2OSP(size, 2) || I2OSP(0, 1) || DST_prime)
+	h := sha512.New()
+	h.Write(make([]byte, h.BlockSize()))
+	h.Write(msg)
+	h.Write(binary.BigEndian.AppendUint16(nil, uint16(size)))
+	h.Write([]byte{0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	// b_i = H(strxor(b_0, b_(i - 1)) || I2OSP(i, 1) || DST_prime), with b_1 = H(b_0 || I2OSP(1, 1) || DST_prime)
+	out := make([]byte, 0, ell*sha512.Size)
+	bi := make([]byte, sha512.Size)
+
+	for i := 1; i <= ell; i++ {
+		subtle.XORBytes(bi, b0, bi)
+
+		h.Reset()
+		h.Write(bi)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+
+		out = append(out, bi...)
+	}
+
+	return out[:size]
+}
+
+// DeserializeElement returns the element of b, it fails if b isn't the canonical encoding of an element or if the
+// element is the identity.
+func DeserializeElement(b []byte) (*ristretto.Point, error) {
+	var buf [ElementSize]byte
+
+	if len(b) != ElementSize {
+		return nil, ErrInvalidElement
+	}
+
+	copy(buf[:], b)
+
+	p := new(ristretto.Point)
+
+	if !p.SetBytes(&buf) || p.Equals(new(ristretto.Point).SetZero()) {
+		return nil, ErrInvalidElement
+	}
+
+	return p, nil
+}
+
+// DeserializeScalar returns the scalar of b, it fails if b isn't the canonical encoding of a scalar.
+func DeserializeScalar(b []byte) (*ristretto.Scalar, error) {
+	var buf [uniformSize]byte
+
+	if len(b) != ScalarSize {
+		return nil, ErrInvalidScalar
+	}
+
+	copy(buf[:], b)
+
+	s := new(ristretto.Scalar).SetReduced(&buf)
+
+	if subtle.ConstantTimeCompare(s.Bytes(), b) != 1 {
+		return nil, ErrInvalidScalar
+	}
+
+	return s, nil
+}
+
+// LengthPrefixed returns the concatenation of each of values prefixed with its 2 bytes length.
+func LengthPrefixed(values ...[]byte) []byte {
+	var out []byte
+
+	for _, v := range values {
+		out = binary.BigEndian.AppendUint16(out, uint16(len(v)))
+		out = append(out, v...)
+	}
+
+	return out
+}
@@ -0,0 +1,142 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package musig2
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+const (
+	keyAggListTag        = "KeyAgg list"
+	keyAggCoefficientTag = "KeyAgg coefficient"
+	auxTag               = "MuSig/aux"
+	nonceTag             = "MuSig/nonce"
+	nonceCoefficientTag  = "MuSig/noncecoef"
+	challengeTag         = "BIP0340/challenge"
+)
+
+var errInvalidPoint = errors.New("invalid point")
+
+// parsePoint returns the point of the 33 bytes compressed encoding b.
+func parsePoint(b []byte) (*btcec.JacobianPoint, error) {
+	if len(b) != PublicKeySize {
+		return nil, errInvalidPoint
+	}
+
+	pub, err := btcec.ParsePubKey(b)
+	if err != nil {
+		return nil, errInvalidPoint
+	}
+
+	p := new(btcec.JacobianPoint)
+	pub.AsJacobian(p)
+
+	return p, nil
+}
+
+// parsePointExt is parsePoint with the point at infinity encoded as 33 zero bytes.
+func parsePointExt(b []byte) (*btcec.JacobianPoint, error) {
+	if len(b) == PublicKeySize && isZero(b) {
+		return new(btcec.JacobianPoint), nil
+	}
+
+	return parsePoint(b)
+}
+
+// pointBytes returns the 33 bytes compressed encoding of the affine point p.
+func pointBytes(p *btcec.JacobianPoint) []byte {
+	return btcec.NewPublicKey(&p.X, &p.Y).SerializeCompressed()
+}
+
+// pointBytesExt is pointBytes with the point at infinity encoded as 33 zero bytes.
+func pointBytesExt(p *btcec.JacobianPoint) []byte {
+	if isInfinity(p) {
+		return make([]byte, PublicKeySize)
+	}
+
+	return pointBytes(p)
+}
+
+// xBytes returns the 32 bytes x coordinate of the affine point p.
+func xBytes(p *btcec.JacobianPoint) []byte {
+	b := p.X.Bytes()
+
+	return b[:]
+}
+
+// add returns the affine point p1 + p2.
+func add(p1, p2 *btcec.JacobianPoint) *btcec.JacobianPoint {
+	r := new(btcec.JacobianPoint)
+	btcec.AddNonConst(p1, p2, r)
+	r.ToAffine()
+
+	return r
+}
+
+// mul returns the affine point k*p.
+func mul(k *btcec.ModNScalar, p *btcec.JacobianPoint) *btcec.JacobianPoint {
+	r := new(btcec.JacobianPoint)
+	btcec.ScalarMultNonConst(k, p, r)
+	r.ToAffine()
+
+	return r
+}
+
+// baseMul returns the affine point k*G.
+func baseMul(k *btcec.ModNScalar) *btcec.JacobianPoint {
+	r := new(btcec.JacobianPoint)
+	btcec.ScalarBaseMultNonConst(k, r)
+	r.ToAffine()
+
+	return r
+}
+
+func isInfinity(p *btcec.JacobianPoint) bool {
+	return (p.X.IsZero() && p.Y.IsZero()) || p.Z.IsZero()
+}
+
+// hashToScalar returns int(hash_tag(x)) mod n.
+func hashToScalar(tag string, x ...[]byte) *btcec.ModNScalar {
+	h := taggedHash(tag, x...)
+
+	s := new(btcec.ModNScalar)
+	s.SetBytes(&h)
+
+	return s
+}
+
+// taggedHash is the BIP-340 tagged hash SHA256(SHA256(tag) || SHA256(tag) || x).
+func taggedHash(tag string, x ...[]byte) [32]byte {
+	tagHash := sha256.Sum256([]byte(tag))
+
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+
+	for _, b := range x {
+		h.Write(b)
+	}
+
+	var out [32]byte
+
+	copy(out[:], h.Sum(nil))
+
+	return out
+}
+
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,132 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package musig2
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// KeyAggContext is the aggregation of the public keys of the signers of a MuSig2 session.
+type KeyAggContext struct {
+	pubKeys   [][]byte
+	q         *btcec.JacobianPoint
+	listHash  []byte
+	secondKey []byte
+}
+
+// SortKeys returns the public keys pubKeys sorted in lexicographical order, the KeySort order of BIP-327: the signers
+// agree on the order of the keys they aggregate, sorting them makes the aggregate key independent of their order.
+func SortKeys(pubKeys [][]byte) [][]byte {
+	sorted := append([][]byte{}, pubKeys...)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+
+	return sorted
+}
+
+// AggregateKeys aggregates the 33 bytes compressed public keys pubKeys of the signers, in the given order.
+func AggregateKeys(pubKeys [][]byte) (*KeyAggContext, error) {
+	if len(pubKeys) == 0 {
+		return nil, errors.New("musig2: aggregate keys: no public keys")
+	}
+
+	ctx := &KeyAggContext{
+		pubKeys:   make([][]byte, len(pubKeys)),
+		secondKey: make([]byte, PublicKeySize),
+	}
+
+	points := make([]*btcec.JacobianPoint, len(pubKeys))
+
+	for i, pk := range pubKeys {
+		p, err := parsePoint(pk)
+		if err != nil {
+			return nil, fmt.Errorf("musig2: aggregate keys: public key %d: %w", i, err)
+		}
+
+		points[i] = p
+		ctx.pubKeys[i] = append([]byte{}, pk...)
+	}
+
+	// the second key is the first key that differs from the first one, its coefficient is 1.
+	for _, pk := range ctx.pubKeys[1:] {
+		if !bytes.Equal(pk, ctx.pubKeys[0]) {
+			ctx.secondKey = pk
+
+			break
+		}
+	}
+
+	l := taggedHash(keyAggListTag, ctx.pubKeys...)
+	ctx.listHash = l[:]
+
+	q := new(btcec.JacobianPoint)
+
+	for i, p := range points {
+		q = add(q, mul(ctx.coefficient(ctx.pubKeys[i]), p))
+	}
+
+	if isInfinity(q) {
+		return nil, errors.New("musig2: aggregate keys: aggregate key is the point at infinity")
+	}
+
+	ctx.q = q
+
+	return ctx, nil
+}
+
+// PublicKey returns the 32 bytes x-only aggregate public key, the BIP-340 public key of the aggregate signatures.
+func (c *KeyAggContext) PublicKey() []byte {
+	return xBytes(c.q)
+}
+
+// PublicKeys returns the public keys of the signers, in their aggregation order.
+func (c *KeyAggContext) PublicKeys() [][]byte {
+	pubKeys := make([][]byte, len(c.pubKeys))
+
+	for i, pk := range c.pubKeys {
+		pubKeys[i] = append([]byte{}, pk...)
+	}
+
+	return pubKeys
+}
+
+func (c *KeyAggContext) contains(pubKey []byte) bool {
+	for _, pk := range c.pubKeys {
+		if bytes.Equal(pk, pubKey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// coefficient returns the KeyAgg coefficient of the public key pubKey.
+func (c *KeyAggContext) coefficient(pubKey []byte) *btcec.ModNScalar {
+	if bytes.Equal(pubKey, c.secondKey) {
+		return new(btcec.ModNScalar).SetInt(1)
+	}
+
+	return hashToScalar(keyAggCoefficientTag, c.listHash, pubKey)
+}
+
+// parity returns g = 1 if the aggregate point Q has an even Y, n - 1 otherwise.
+func (c *KeyAggContext) parity() *btcec.ModNScalar {
+	g := new(btcec.ModNScalar).SetInt(1)
+
+	if c.q.Y.IsOdd() {
+		g.Negate()
+	}
+
+	return g
+}
@@ -0,0 +1,26 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package musig2
+
+// NonceMessage holds the public nonce of a signer, sent to the other signers or to the aggregator in the first
+// round.
+type NonceMessage struct {
+	PublicKey []byte `json:"publicKey"`
+	PubNonce  []byte `json:"pubNonce"`
+}
+
+// AggNonceMessage holds the aggregate nonce of a session, sent by the aggregator to the signers.
+type AggNonceMessage struct {
+	AggNonce []byte `json:"aggNonce"`
+	Message  []byte `json:"message"`
+}
+
+// PartialSignatureMessage holds the partial signature of a signer, sent to the aggregator in the second round.
+type PartialSignatureMessage struct {
+	PublicKey        []byte `json:"publicKey"`
+	PartialSignature []byte `json:"partialSignature"`
+}
@@ -0,0 +1,270 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package musig2 provides the MuSig2 multi-signatures of BIP-327, see
+// https://github.com/bitcoin/bips/blob/master/bip-0327.mediawiki: the signers of a message jointly produce a single
+// BIP-340 Schnorr signature that verifies with the aggregate of their public keys.
+//
+// The signers keys are the BIP-340 secp256k1 keys of a KMS (kms.BIP340Secp256k1Type), the public key of a signer in
+// the protocol is the 33 bytes compressed public key returned by Signer.PublicKey rather than the x-only key of the
+// KMS. A signing session has two rounds:
+//
+//  1. the signers aggregate their public keys with AggregateKeys, each signer generates a nonce with
+//     Signer.GenerateNonce and sends its public nonce to the others, or to an aggregator, which aggregates them with
+//     AggregateNonces.
+//  2. each signer creates the session of the message with NewSession and the aggregate nonce, and sends the partial
+//     signature of Signer.Sign. The partial signatures are verified with Session.VerifyPartialSignature and aggregated
+//     with Session.AggregatePartialSignatures into the BIP-340 signature of the message with the aggregate key.
+//
+// The public keys, nonces and partial signatures are the byte strings of BIP-327, NonceMessage and
+// PartialSignatureMessage carry them between the signers. A SecretNonce is never serialized: it is used for one
+// partial signature, the private key is revealed by two partial signatures with the same nonce.
+package musig2
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/bip340"
+	secp256k1pb "github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/proto/secp256k1_go_proto"
+	"github.com/trustbloc/kms-go/internal/memguard"
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/x"
+)
+
+const (
+	// PublicKeySize is the size in bytes of the compressed public key of a signer.
+	PublicKeySize = 33
+	// PubNonceSize is the size in bytes of a public nonce and of an aggregate nonce.
+	PubNonceSize = 2 * PublicKeySize
+	// PartialSignatureSize is the size in bytes of a partial signature.
+	PartialSignatureSize = 32
+	// SignatureSize is the size in bytes of an aggregate signature, a BIP-340 signature.
+	SignatureSize = bip340.SignatureSize
+)
+
+// SecretNonce is the secret nonce of a signer in a session. It is wiped by the partial signature it is used for.
+type SecretNonce struct {
+	k1, k2   btcec.ModNScalar
+	pubKey   []byte
+	pubNonce []byte
+}
+
+// PubNonce returns the 66 bytes public nonce of the secret nonce.
+func (n *SecretNonce) PubNonce() []byte {
+	return append([]byte{}, n.pubNonce...)
+}
+
+func (n *SecretNonce) wipe() {
+	n.k1.Zero()
+	n.k2.Zero()
+}
+
+// Signer computes the MuSig2 partial signatures of the BIP-340 secp256k1 keys of a KeyManager.
+type Signer struct {
+	km kms.KeyManager
+}
+
+// New creates a new Signer of the keys of km.
+func New(km kms.KeyManager) (*Signer, error) {
+	if err := x.Require("crypto/musig2"); err != nil {
+		return nil, fmt.Errorf("musig2: new: %w", err)
+	}
+
+	return &Signer{km: km}, nil
+}
+
+// PublicKey returns the 33 bytes compressed public key of the key keyID, its public key in the MuSig2 protocol.
+func (s *Signer) PublicKey(keyID string) ([]byte, error) {
+	d, err := s.privateKey(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("musig2: public key: %w", err)
+	}
+
+	defer d.Zero()
+
+	return pointBytes(baseMul(d)), nil
+}
+
+// GenerateNonce generates the secret nonce of the key keyID for a session of the signers of keyAgg, and returns it
+// with its public nonce. The message msg of the session is optional, nil if it isn't known yet.
+func (s *Signer) GenerateNonce(keyID string, keyAgg *KeyAggContext, msg []byte) (*SecretNonce, []byte, error) {
+	d, err := s.privateKey(keyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("musig2: generate nonce: %w", err)
+	}
+
+	defer d.Zero()
+
+	n, err := generateNonce(d, keyAgg.PublicKey(), msg, rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("musig2: generate nonce: %w", err)
+	}
+
+	return n, n.PubNonce(), nil
+}
+
+// generateNonce is the NonceGen algorithm of BIP-327, without extra input.
+func generateNonce(d *btcec.ModNScalar, aggPubKey, msg []byte, random io.Reader) (*SecretNonce, error) {
+	seed := make([]byte, 32) //nolint:gomnd // BIP-327 nonce randomness size.
+
+	if _, err := io.ReadFull(random, seed); err != nil {
+		return nil, fmt.Errorf("randomness: %w", err)
+	}
+
+	// rand = bytes(sk) xor hash_aux(rand')
+	r := taggedHash(auxTag, seed)
+	defer memguard.Wipe(r[:])
+
+	sk := d.Bytes()
+	defer memguard.Wipe(sk[:])
+
+	for i := range r {
+		r[i] ^= sk[i]
+	}
+
+	pk := pointBytes(baseMul(d))
+
+	msgPrefixed := []byte{0}
+
+	if msg != nil {
+		msgPrefixed = binary.BigEndian.AppendUint64([]byte{1}, uint64(len(msg)))
+		msgPrefixed = append(msgPrefixed, msg...)
+	}
+
+	n := &SecretNonce{pubKey: pk}
+
+	// k_i = int(hash_nonce(rand || len(pk) || pk || len(aggpk) || aggpk || m_prefixed || len(in) || in || i - 1)) mod n
+	for i, k := range []*btcec.ModNScalar{&n.k1, &n.k2} {
+		h := taggedHash(nonceTag, r[:], []byte{byte(len(pk))}, pk, []byte{byte(len(aggPubKey))}, aggPubKey,
+			msgPrefixed, make([]byte, 4), []byte{byte(i)}) //nolint:gomnd // 4 bytes size of the empty extra input.
+
+		k.SetBytes(&h)
+		memguard.Wipe(h[:])
+
+		if k.IsZero() {
+			n.wipe()
+
+			return nil, errors.New("zero nonce")
+		}
+	}
+
+	n.pubNonce = append(pointBytes(baseMul(&n.k1)), pointBytes(baseMul(&n.k2))...)
+
+	return n, nil
+}
+
+// Sign returns the partial signature of the session message with the key keyID and its secret nonce secNonce. The
+// secret nonce is wiped: it can't be used again, even if Sign fails.
+func (s *Signer) Sign(keyID string, secNonce *SecretNonce, session *Session) ([]byte, error) {
+	var k1, k2 btcec.ModNScalar
+
+	k1.Set(&secNonce.k1)
+	k2.Set(&secNonce.k2)
+	secNonce.wipe()
+
+	defer k1.Zero()
+	defer k2.Zero()
+
+	if k1.IsZero() || k2.IsZero() {
+		return nil, errors.New("musig2: sign: secret nonce already used")
+	}
+
+	d, err := s.privateKey(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("musig2: sign: %w", err)
+	}
+
+	defer d.Zero()
+
+	pk := pointBytes(baseMul(d))
+
+	if !bytes.Equal(pk, secNonce.pubKey) {
+		return nil, errors.New("musig2: sign: secret nonce is not a nonce of the key")
+	}
+
+	if !session.keyAgg.contains(pk) {
+		return nil, errors.New("musig2: sign: key is not a key of the session")
+	}
+
+	if session.r.Y.IsOdd() {
+		k1.Negate()
+		k2.Negate()
+	}
+
+	// s = (k1 + b*k2 + e*a*g*d) mod n
+	d.Mul(session.keyAgg.coefficient(pk)).Mul(session.keyAgg.parity())
+
+	partialSig := new(btcec.ModNScalar).Mul2(session.e, d).Add(new(btcec.ModNScalar).Mul2(session.b, &k2)).Add(&k1)
+	sBytes := partialSig.Bytes()
+
+	// the partial signature is verified to protect the key against faults of the computation.
+	if err = session.VerifyPartialSignature(sBytes[:], secNonce.pubNonce, pk); err != nil {
+		return nil, fmt.Errorf("musig2: sign: %w", err)
+	}
+
+	return sBytes[:], nil
+}
+
+// privateKey returns the secret scalar of the BIP-340 key keyID.
+func (s *Signer) privateKey(keyID string) (*btcec.ModNScalar, error) {
+	kh, err := s.km.Get(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("get key: %w", err)
+	}
+
+	keyValue, err := bip340KeyValue(kh)
+	if err != nil {
+		return nil, err
+	}
+
+	defer memguard.Wipe(keyValue)
+
+	d := new(btcec.ModNScalar)
+
+	if d.SetByteSlice(keyValue) || d.IsZero() {
+		return nil, errors.New("invalid private key")
+	}
+
+	return d, nil
+}
+
+// bip340KeyValue returns the private key of the primary key of the BIP-340 keyset kh.
+func bip340KeyValue(kh interface{}) ([]byte, error) {
+	handle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errors.New("key is not a keyset handle")
+	}
+
+	ks := insecurecleartextkeyset.KeysetMaterial(handle)
+
+	for _, k := range ks.Key {
+		if k.KeyId != ks.PrimaryKeyId || k.Status != tinkpb.KeyStatusType_ENABLED {
+			continue
+		}
+
+		key := &secp256k1pb.Secp256K1PrivateKey{}
+
+		if k.KeyData.TypeUrl != bip340.SignerTypeURL || proto.Unmarshal(k.KeyData.Value, key) != nil ||
+			len(key.KeyValue) != bip340.PrivateKeySize {
+			return nil, errors.New("key is not a BIP-340 secp256k1 private key")
+		}
+
+		return key.KeyValue, nil
+	}
+
+	return nil, errors.New("primary key not found")
+}
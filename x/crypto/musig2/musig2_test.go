@@ -0,0 +1,346 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package musig2_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto/primitive/bip340"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+	"github.com/trustbloc/kms-go/x"
+	"github.com/trustbloc/kms-go/x/crypto/musig2"
+)
+
+// BIP-327 key aggregation test vectors, https://github.com/bitcoin/bips/blob/master/bip-0327/vectors.
+var testPubKeys = []string{
+	"02F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+	"03DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+	"023590A94E768F8E1815C2F24B4D80A8E3149316C3518CE7B7AD338368D038CA66",
+}
+
+func TestAggregateKeys(t *testing.T) {
+	vectors := []struct {
+		keys     []int
+		expected string
+	}{
+		{keys: []int{0, 1, 2}, expected: "90539EEDE565F5D054F32CC0C220126889ED1E5D193BAF15AEF344FE59D4610C"},
+		{keys: []int{2, 1, 0}, expected: "6204DE8B083426DC6EAF9502D27024D53FC826BF7D2012148A0575435DF54B2B"},
+		{keys: []int{0, 0, 0}, expected: "B436E3BAD62B8CD409969A224731C193D051162D8C5AE8B109306127DA3AA935"},
+		{keys: []int{0, 0, 1, 1}, expected: "69BC22BFA5D106306E48A20679DE1D7389386124D07571D0D872686028C26A3E"},
+	}
+
+	for _, v := range vectors {
+		var pubKeys [][]byte
+
+		for _, i := range v.keys {
+			pubKeys = append(pubKeys, decode(t, testPubKeys[i]))
+		}
+
+		keyAgg, err := musig2.AggregateKeys(pubKeys)
+		require.NoError(t, err)
+		require.Equal(t, decode(t, v.expected), keyAgg.PublicKey())
+		require.Equal(t, pubKeys, keyAgg.PublicKeys())
+	}
+
+	t.Run("sorted keys", func(t *testing.T) {
+		pubKeys := [][]byte{decode(t, testPubKeys[1]), decode(t, testPubKeys[2]), decode(t, testPubKeys[0])}
+
+		sorted := musig2.SortKeys(pubKeys)
+		require.Equal(t, [][]byte{pubKeys[1], pubKeys[2], pubKeys[0]}, sorted)
+		require.Equal(t, decode(t, testPubKeys[1]), pubKeys[0])
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := musig2.AggregateKeys(nil)
+		require.EqualError(t, err, "musig2: aggregate keys: no public keys")
+
+		// BIP-327 invalid key vectors: x not on the curve, invalid prefix and x exceeding the field size.
+		for _, invalid := range []string{
+			"020000000000000000000000000000000000000000000000000000000000000005",
+			"04F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+			"03FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC30",
+		} {
+			_, err = musig2.AggregateKeys([][]byte{decode(t, testPubKeys[0]), decode(t, invalid)})
+			require.EqualError(t, err, "musig2: aggregate keys: public key 1: invalid point")
+		}
+	})
+}
+
+type signer struct {
+	keyID  string
+	pubKey []byte
+}
+
+func TestSigningSession(t *testing.T) {
+	km := mockkms.NewForTest(t)
+	s := newSigner(t, km)
+
+	signers := make([]*signer, 3)
+
+	var pubKeys [][]byte
+
+	for i := range signers {
+		keyID, xOnly, err := km.CreateAndExportPubKeyBytes(kmsapi.BIP340Secp256k1Type)
+		require.NoError(t, err)
+
+		pubKey, err := s.PublicKey(keyID)
+		require.NoError(t, err)
+		require.Len(t, pubKey, musig2.PublicKeySize)
+		require.Equal(t, xOnly, pubKey[1:])
+
+		signers[i] = &signer{keyID: keyID, pubKey: pubKey}
+		pubKeys = append(pubKeys, pubKey)
+	}
+
+	keyAgg, err := musig2.AggregateKeys(musig2.SortKeys(pubKeys))
+	require.NoError(t, err)
+
+	for _, msg := range [][]byte{[]byte("message"), {}, make([]byte, 100)} {
+		sig := runSession(t, s, keyAgg, signers, msg)
+		require.Len(t, sig, musig2.SignatureSize)
+		require.NoError(t, bip340.Verify(keyAgg.PublicKey(), msg, sig))
+		require.ErrorIs(t, bip340.Verify(keyAgg.PublicKey(), []byte("other"), sig), bip340.ErrInvalidSignature)
+	}
+
+	t.Run("single signer", func(t *testing.T) {
+		single, err := musig2.AggregateKeys(pubKeys[:1])
+		require.NoError(t, err)
+
+		msg := []byte("message")
+		require.NoError(t, bip340.Verify(single.PublicKey(), msg, runSession(t, s, single, signers[:1], msg)))
+	})
+}
+
+// runSession runs a signing session of msg by the signers, with the messages of the protocol serialized as JSON.
+func runSession(t *testing.T, s *musig2.Signer, keyAgg *musig2.KeyAggContext, signers []*signer,
+	msg []byte) []byte {
+	t.Helper()
+
+	secNonces := make([]*musig2.SecretNonce, len(signers))
+	pubNonces := map[string][]byte{}
+
+	var nonces [][]byte
+
+	for i, sig := range signers {
+		secNonce, pubNonce, err := s.GenerateNonce(sig.keyID, keyAgg, msg)
+		require.NoError(t, err)
+		require.Len(t, pubNonce, musig2.PubNonceSize)
+
+		secNonces[i] = secNonce
+
+		nonceMsg := roundTrip(t, &musig2.NonceMessage{PublicKey: sig.pubKey, PubNonce: pubNonce})
+		pubNonces[string(nonceMsg.PublicKey)] = nonceMsg.PubNonce
+		nonces = append(nonces, nonceMsg.PubNonce)
+	}
+
+	aggNonce, err := musig2.AggregateNonces(nonces)
+	require.NoError(t, err)
+	require.Len(t, aggNonce, musig2.PubNonceSize)
+
+	aggNonceMsg := roundTrip(t, &musig2.AggNonceMessage{AggNonce: aggNonce, Message: msg})
+
+	var partialSigs [][]byte
+
+	for i, sig := range signers {
+		session, err := musig2.NewSession(keyAgg, aggNonceMsg.AggNonce, aggNonceMsg.Message)
+		require.NoError(t, err)
+
+		partialSig, err := s.Sign(sig.keyID, secNonces[i], session)
+		require.NoError(t, err)
+		require.Len(t, partialSig, musig2.PartialSignatureSize)
+
+		sigMsg := roundTrip(t, &musig2.PartialSignatureMessage{PublicKey: sig.pubKey, PartialSignature: partialSig})
+		partialSigs = append(partialSigs, sigMsg.PartialSignature)
+	}
+
+	session, err := musig2.NewSession(keyAgg, aggNonce, msg)
+	require.NoError(t, err)
+
+	for i, sig := range signers {
+		require.NoError(t, session.VerifyPartialSignature(partialSigs[i], pubNonces[string(sig.pubKey)], sig.pubKey))
+	}
+
+	aggSig, err := session.AggregatePartialSignatures(partialSigs)
+	require.NoError(t, err)
+
+	return aggSig
+}
+
+func TestSigner_Sign(t *testing.T) {
+	km := mockkms.NewForTest(t)
+	s := newSigner(t, km)
+
+	keyID1, _, err := km.Create(kmsapi.BIP340Secp256k1Type)
+	require.NoError(t, err)
+
+	keyID2, _, err := km.Create(kmsapi.BIP340Secp256k1Type)
+	require.NoError(t, err)
+
+	pubKey1, err := s.PublicKey(keyID1)
+	require.NoError(t, err)
+
+	pubKey2, err := s.PublicKey(keyID2)
+	require.NoError(t, err)
+
+	keyAgg, err := musig2.AggregateKeys([][]byte{pubKey1, pubKey2})
+	require.NoError(t, err)
+
+	msg := []byte("message")
+
+	secNonce1, pubNonce1, err := s.GenerateNonce(keyID1, keyAgg, msg)
+	require.NoError(t, err)
+	require.Equal(t, pubNonce1, secNonce1.PubNonce())
+
+	secNonce2, pubNonce2, err := s.GenerateNonce(keyID2, keyAgg, nil)
+	require.NoError(t, err)
+
+	aggNonce, err := musig2.AggregateNonces([][]byte{pubNonce1, pubNonce2})
+	require.NoError(t, err)
+
+	session, err := musig2.NewSession(keyAgg, aggNonce, msg)
+	require.NoError(t, err)
+
+	t.Run("secret nonce of another key", func(t *testing.T) {
+		_, err = s.Sign(keyID2, secNonce1, session)
+		require.EqualError(t, err, "musig2: sign: secret nonce is not a nonce of the key")
+
+		// the secret nonce is wiped.
+		_, err = s.Sign(keyID1, secNonce1, session)
+		require.EqualError(t, err, "musig2: sign: secret nonce already used")
+	})
+
+	t.Run("invalid partial signatures", func(t *testing.T) {
+		partialSig, err := s.Sign(keyID2, secNonce2, session)
+		require.NoError(t, err)
+		require.NoError(t, session.VerifyPartialSignature(partialSig, pubNonce2, pubKey2))
+
+		_, err = s.Sign(keyID2, secNonce2, session)
+		require.EqualError(t, err, "musig2: sign: secret nonce already used")
+
+		err = session.VerifyPartialSignature(partialSig, pubNonce1, pubKey2)
+		require.ErrorIs(t, err, musig2.ErrInvalidPartialSignature)
+
+		err = session.VerifyPartialSignature(partialSig, pubNonce2, pubKey1)
+		require.ErrorIs(t, err, musig2.ErrInvalidPartialSignature)
+
+		tampered := append([]byte{}, partialSig...)
+		tampered[31] ^= 1
+		require.ErrorIs(t, session.VerifyPartialSignature(tampered, pubNonce2, pubKey2), musig2.ErrInvalidPartialSignature)
+
+		// partial signatures must be reduced scalars.
+		overflow := decode(t, "FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141")
+		require.ErrorIs(t, session.VerifyPartialSignature(overflow, pubNonce2, pubKey2), musig2.ErrInvalidPartialSignature)
+
+		_, err = session.AggregatePartialSignatures([][]byte{partialSig, overflow})
+		require.ErrorIs(t, err, musig2.ErrInvalidPartialSignature)
+
+		_, err = session.AggregatePartialSignatures([][]byte{partialSig})
+		require.EqualError(t, err,
+			"musig2: aggregate partial signatures: a partial signature of each signer is required")
+
+		err = session.VerifyPartialSignature(partialSig, pubNonce2[1:], pubKey2)
+		require.EqualError(t, err, "musig2: verify partial signature: public nonce: invalid nonce size")
+
+		err = session.VerifyPartialSignature(partialSig, pubNonce2, decode(t, testPubKeys[0]))
+		require.EqualError(t, err, "musig2: verify partial signature: public key is not a key of the session")
+	})
+
+	t.Run("key of another session", func(t *testing.T) {
+		keyID3, _, err := km.Create(kmsapi.BIP340Secp256k1Type)
+		require.NoError(t, err)
+
+		secNonce, _, err := s.GenerateNonce(keyID3, keyAgg, msg)
+		require.NoError(t, err)
+
+		_, err = s.Sign(keyID3, secNonce, session)
+		require.EqualError(t, err, "musig2: sign: key is not a key of the session")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		ecdsaKeyID, _, err := km.Create(kmsapi.ECDSASecp256k1TypeIEEEP1363)
+		require.NoError(t, err)
+
+		_, err = s.PublicKey(ecdsaKeyID)
+		require.EqualError(t, err, "musig2: public key: key is not a BIP-340 secp256k1 private key")
+
+		_, _, err = s.GenerateNonce(ecdsaKeyID, keyAgg, msg)
+		require.EqualError(t, err, "musig2: generate nonce: key is not a BIP-340 secp256k1 private key")
+
+		_, err = s.PublicKey("unknown")
+		require.ErrorContains(t, err, "musig2: public key: get key")
+
+		_, err = musig2.AggregateNonces(nil)
+		require.EqualError(t, err, "musig2: aggregate nonces: no public nonces")
+
+		_, err = musig2.AggregateNonces([][]byte{pubNonce1, make([]byte, musig2.PubNonceSize)})
+		require.EqualError(t, err, "musig2: aggregate nonces: public nonce 1: invalid point")
+
+		_, err = musig2.NewSession(keyAgg, aggNonce[:1], msg)
+		require.EqualError(t, err, "musig2: new session: aggregate nonce: invalid nonce size")
+	})
+
+	t.Run("aggregate nonce at infinity", func(t *testing.T) {
+		// the aggregate nonce of opposite nonces is the point at infinity, the session nonce is then the generator.
+		opposite := append([]byte{}, pubNonce1...)
+		opposite[0] ^= 1
+		opposite[musig2.PublicKeySize] ^= 1
+
+		infinity, err := musig2.AggregateNonces([][]byte{pubNonce1, opposite})
+		require.NoError(t, err)
+		require.Equal(t, make([]byte, musig2.PubNonceSize), infinity)
+
+		_, err = musig2.NewSession(keyAgg, infinity, msg)
+		require.NoError(t, err)
+	})
+	t.Run("not enabled", func(t *testing.T) {
+		x.Disable()
+
+		_, err = musig2.New(km)
+		require.ErrorIs(t, err, x.ErrNotEnabled)
+
+		_, err = musig2.NewSession(keyAgg, aggNonce, msg)
+		require.ErrorIs(t, err, x.ErrNotEnabled)
+		require.ErrorContains(t, err, "musig2: new session: ")
+	})
+}
+
+func newSigner(t *testing.T, km kmsapi.KeyManager) *musig2.Signer {
+	t.Helper()
+
+	x.Enable("crypto/musig2")
+
+	s, err := musig2.New(km)
+	require.NoError(t, err)
+
+	return s
+}
+
+func roundTrip[T any](t *testing.T, msg *T) *T {
+	t.Helper()
+
+	b, err := json.Marshal(msg)
+	require.NoError(t, err)
+
+	out := new(T)
+	require.NoError(t, json.Unmarshal(b, out))
+
+	return out
+}
+
+func decode(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+
+	return b
+}
@@ -0,0 +1,164 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package musig2
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+
+	"github.com/trustbloc/kms-go/x"
+)
+
+// ErrInvalidPartialSignature is returned by VerifyPartialSignature when a partial signature is not valid.
+var ErrInvalidPartialSignature = errors.New("musig2: invalid partial signature")
+
+// AggregateNonces aggregates the public nonces pubNonces of all the signers of a session into the 66 bytes aggregate
+// nonce of the session.
+func AggregateNonces(pubNonces [][]byte) ([]byte, error) {
+	if len(pubNonces) == 0 {
+		return nil, errors.New("musig2: aggregate nonces: no public nonces")
+	}
+
+	r1, r2 := new(btcec.JacobianPoint), new(btcec.JacobianPoint)
+
+	for i, pubNonce := range pubNonces {
+		p1, p2, err := parseNonce(pubNonce, parsePoint)
+		if err != nil {
+			return nil, fmt.Errorf("musig2: aggregate nonces: public nonce %d: %w", i, err)
+		}
+
+		r1, r2 = add(r1, p1), add(r2, p2)
+	}
+
+	return append(pointBytesExt(r1), pointBytesExt(r2)...), nil
+}
+
+// Session is the signing session of a message by the signers of a KeyAggContext, once their nonces are aggregated.
+type Session struct {
+	keyAgg *KeyAggContext
+	b      *btcec.ModNScalar
+	r      *btcec.JacobianPoint
+	e      *btcec.ModNScalar
+}
+
+// NewSession creates the signing session of msg by the signers of keyAgg with the aggregate nonce aggNonce.
+func NewSession(keyAgg *KeyAggContext, aggNonce, msg []byte) (*Session, error) {
+	if err := x.Require("crypto/musig2"); err != nil {
+		return nil, fmt.Errorf("musig2: new session: %w", err)
+	}
+
+	r1, r2, err := parseNonce(aggNonce, parsePointExt)
+	if err != nil {
+		return nil, fmt.Errorf("musig2: new session: aggregate nonce: %w", err)
+	}
+
+	// b = int(hash_noncecoef(aggnonce || xbytes(Q) || m)) mod n, R = R1 + b*R2 or G if it is the point at infinity.
+	b := hashToScalar(nonceCoefficientTag, aggNonce, keyAgg.PublicKey(), msg)
+
+	r := add(r1, mul(b, r2))
+	if isInfinity(r) {
+		r = baseMul(new(btcec.ModNScalar).SetInt(1))
+	}
+
+	return &Session{
+		keyAgg: keyAgg,
+		b:      b,
+		r:      r,
+		e:      hashToScalar(challengeTag, xBytes(r), keyAgg.PublicKey(), msg),
+	}, nil
+}
+
+// VerifyPartialSignature verifies the partial signature partialSig of the signer of public key pubKey and public
+// nonce pubNonce in the session. It returns ErrInvalidPartialSignature if the partial signature is not valid.
+func (s *Session) VerifyPartialSignature(partialSig, pubNonce, pubKey []byte) error {
+	sig, err := parsePartialSignature(partialSig)
+	if err != nil {
+		return err
+	}
+
+	p, err := parsePoint(pubKey)
+	if err != nil || !s.keyAgg.contains(pubKey) {
+		return errors.New("musig2: verify partial signature: public key is not a key of the session")
+	}
+
+	r1, r2, err := parseNonce(pubNonce, parsePoint)
+	if err != nil {
+		return fmt.Errorf("musig2: verify partial signature: public nonce: %w", err)
+	}
+
+	// s*G = Re + e*a*g*P, with Re = R1 + b*R2 negated if R has an odd Y.
+	re := add(r1, mul(s.b, r2))
+	if s.r.Y.IsOdd() {
+		re.Y.Negate(1).Normalize()
+	}
+
+	k := new(btcec.ModNScalar).Mul2(s.e, s.keyAgg.coefficient(pubKey)).Mul(s.keyAgg.parity())
+
+	expected := add(re, mul(k, p))
+	actual := baseMul(sig)
+
+	if !actual.X.Equals(&expected.X) || !actual.Y.Equals(&expected.Y) {
+		return ErrInvalidPartialSignature
+	}
+
+	return nil
+}
+
+// AggregatePartialSignatures aggregates the partial signatures partialSigs of all the signers of the session into
+// the 64 bytes BIP-340 signature of the session message with the aggregate public key.
+func (s *Session) AggregatePartialSignatures(partialSigs [][]byte) ([]byte, error) {
+	if len(partialSigs) != len(s.keyAgg.pubKeys) {
+		return nil, errors.New("musig2: aggregate partial signatures: a partial signature of each signer is required")
+	}
+
+	sum := new(btcec.ModNScalar)
+
+	for _, partialSig := range partialSigs {
+		sig, err := parsePartialSignature(partialSig)
+		if err != nil {
+			return nil, err
+		}
+
+		sum.Add(sig)
+	}
+
+	sBytes := sum.Bytes()
+
+	return append(xBytes(s.r), sBytes[:]...), nil
+}
+
+// parseNonce returns the two points of the 66 bytes nonce b, parsed with parse.
+func parseNonce(b []byte, parse func([]byte) (*btcec.JacobianPoint, error)) (*btcec.JacobianPoint,
+	*btcec.JacobianPoint, error) {
+	if len(b) != PubNonceSize {
+		return nil, nil, errors.New("invalid nonce size")
+	}
+
+	r1, err := parse(b[:PublicKeySize])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r2, err := parse(b[PublicKeySize:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return r1, r2, nil
+}
+
+func parsePartialSignature(partialSig []byte) (*btcec.ModNScalar, error) {
+	s := new(btcec.ModNScalar)
+
+	if len(partialSig) != PartialSignatureSize || s.SetByteSlice(partialSig) {
+		return nil, ErrInvalidPartialSignature
+	}
+
+	return s, nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opaque
+
+import (
+	"encoding/binary"
+
+	"github.com/bwesterb/go-ristretto"
+
+	"github.com/trustbloc/kms-go/x/crypto/internal/ristretto255"
+)
+
+// keys are the keys of a login derived from the 3DH shared secrets, as DeriveKeys of RFC 9807 section 6.4.2 does.
+type keys struct {
+	serverMACKey []byte
+	clientMACKey []byte
+	sessionKey   []byte
+}
+
+// preamble returns the transcript of a login, as Preamble of RFC 9807 section 6.4.1.
+func (c *config) preamble(clientIdentity, ke1, serverIdentity, ke2Prefix []byte) []byte {
+	p := []byte("OPAQUEv1-")
+	p = append(p, ristretto255.LengthPrefixed(c.context, clientIdentity)...)
+	p = append(p, ke1...)
+	p = append(p, ristretto255.LengthPrefixed(serverIdentity)...)
+
+	// ke2Prefix is credential_response || server_nonce || server_public_keyshare.
+	return append(p, ke2Prefix...)
+}
+
+func deriveKeys(ikm, preamble []byte) *keys {
+	prk := extract(ikm)
+	preambleHash := hash(preamble)
+	handshakeSecret := deriveSecret(prk, "HandshakeSecret", preambleHash)
+
+	return &keys{
+		serverMACKey: deriveSecret(handshakeSecret, "ServerMAC", nil),
+		clientMACKey: deriveSecret(handshakeSecret, "ClientMAC", nil),
+		sessionKey:   deriveSecret(prk, "SessionKey", preambleHash),
+	}
+}
+
+// deriveSecret is Derive-Secret of RFC 9807 section 6.4.2: Expand-Label with the size of the KDF output.
+func deriveSecret(secret []byte, label string, transcriptHash []byte) []byte {
+	label = "OPAQUE-" + label
+
+	customLabel := binary.BigEndian.AppendUint16(nil, nx)
+	customLabel = append(customLabel, byte(len(label)))
+	customLabel = append(customLabel, label...)
+	customLabel = append(customLabel, byte(len(transcriptHash)))
+	customLabel = append(customLabel, transcriptHash...)
+
+	return expand(secret, customLabel, nx)
+}
+
+// tripleDiffieHellman returns the input keying material of a login, the concatenation of the 3DH shared secrets
+// sk1*pk1, sk2*pk2 and sk3*pk3.
+func tripleDiffieHellman(sk1 *ristretto.Scalar, pk1 []byte, sk2 *ristretto.Scalar, pk2 []byte,
+	sk3 *ristretto.Scalar, pk3 []byte) ([]byte, error) {
+	ikm := make([]byte, 0, 3*npk) //nolint:gomnd // three shared secrets
+
+	for _, dh := range []struct {
+		sk *ristretto.Scalar
+		pk []byte
+	}{{sk1, pk1}, {sk2, pk2}, {sk3, pk3}} {
+		secret, err := diffieHellman(dh.sk, dh.pk)
+		if err != nil {
+			return nil, err
+		}
+
+		ikm = append(ikm, secret...)
+	}
+
+	return ikm, nil
+}
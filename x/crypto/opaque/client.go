@@ -0,0 +1,221 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opaque
+
+import (
+	"crypto/hmac"
+	"fmt"
+	"io"
+
+	"github.com/bwesterb/go-ristretto"
+
+	"github.com/trustbloc/kms-go/x"
+	"github.com/trustbloc/kms-go/x/crypto/oprf"
+)
+
+// Client registers passwords with a Server and logs in with them.
+type Client struct {
+	config *config
+	oprf   *oprf.Client
+}
+
+// RegistrationState is the state of a registration kept by the client between StartRegistration and
+// FinishRegistration.
+type RegistrationState struct {
+	blind *oprf.Blind
+}
+
+// LoginState is the state of a login kept by the client between StartLogin and FinishLogin.
+type LoginState struct {
+	blind        *oprf.Blind
+	clientSecret *ristretto.Scalar
+	ke1          []byte
+}
+
+// NewClient creates a new Client.
+func NewClient(opts ...Opt) (*Client, error) {
+	if err := x.Require("crypto/opaque"); err != nil {
+		return nil, fmt.Errorf("opaque: new client: %w", err)
+	}
+
+	c := newConfig(opts)
+
+	o, err := oprf.NewClient(oprf.ModeOPRF, nil, oprf.WithRandom(c.random))
+	if err != nil {
+		return nil, fmt.Errorf("opaque: new client: %w", err)
+	}
+
+	return &Client{config: c, oprf: o}, nil
+}
+
+// StartRegistration starts the registration of password. It returns the registration state and the registration
+// request sent to the server.
+func (c *Client) StartRegistration(password []byte) (*RegistrationState, []byte, error) {
+	blind, err := c.oprf.Blind(password)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opaque: start registration: %w", err)
+	}
+
+	return &RegistrationState{blind: blind}, blind.BlindedElement(), nil
+}
+
+// FinishRegistration finishes the registration of state with the registration response of the server. It returns the
+// record of the password, uploaded to the server, and the export key of the password.
+func (c *Client) FinishRegistration(state *RegistrationState, response []byte) ([]byte, []byte, error) {
+	if err := checkSize("registration response", response, RegistrationResponseSize); err != nil {
+		return nil, nil, fmt.Errorf("opaque: finish registration: %w", err)
+	}
+
+	evaluatedElement, serverPublicKey := response[:noe], response[noe:]
+
+	randomizedPassword, err := c.randomizedPassword(state.blind, evaluatedElement)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opaque: finish registration: %w", err)
+	}
+
+	nonce := make([]byte, nn)
+
+	if _, err = io.ReadFull(c.config.random, nonce); err != nil {
+		return nil, nil, fmt.Errorf("opaque: finish registration: %w", err)
+	}
+
+	env, err := openEnvelope(randomizedPassword, nonce)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opaque: finish registration: %w", err)
+	}
+
+	env.sk.SetZero()
+
+	credentials, _, _ := c.config.cleartextCredentials(serverPublicKey, env.pk)
+
+	record := append([]byte{}, env.pk...)
+	record = append(record, expand(randomizedPassword, []byte("MaskingKey"), nh)...)
+	record = append(record, nonce...)
+	record = append(record, env.authTag(credentials)...)
+
+	return record, env.exportKey, nil
+}
+
+// StartLogin starts a login with password. It returns the login state and the KE1 message sent to the server.
+func (c *Client) StartLogin(password []byte) (*LoginState, []byte, error) {
+	blind, err := c.oprf.Blind(password)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opaque: start login: %w", err)
+	}
+
+	nonce := make([]byte, nn)
+	keyshareSeed := make([]byte, nseed)
+
+	if _, err = io.ReadFull(c.config.random, nonce); err != nil {
+		return nil, nil, fmt.Errorf("opaque: start login: %w", err)
+	}
+
+	if _, err = io.ReadFull(c.config.random, keyshareSeed); err != nil {
+		return nil, nil, fmt.Errorf("opaque: start login: %w", err)
+	}
+
+	clientSecret, clientKeyshare, err := deriveDiffieHellmanKeyPair(keyshareSeed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opaque: start login: %w", err)
+	}
+
+	ke1 := append(blind.BlindedElement(), nonce...)
+	ke1 = append(ke1, clientKeyshare...)
+
+	return &LoginState{blind: blind, clientSecret: clientSecret, ke1: ke1}, ke1, nil
+}
+
+// FinishLogin finishes the login of state with the KE2 message of the server. It returns the KE3 message sent to the
+// server, the session key and the export key of the password. It returns ErrAuthentication if the password is wrong
+// or the server is not the one the password was registered with.
+func (c *Client) FinishLogin(state *LoginState, ke2 []byte) ([]byte, []byte, []byte, error) {
+	if err := checkSize("KE2", ke2, KE2Size); err != nil {
+		return nil, nil, nil, fmt.Errorf("opaque: finish login: %w", err)
+	}
+
+	defer state.clientSecret.SetZero()
+
+	credentialResponse := ke2[:credentialResponseSize]
+	serverKeyshare := ke2[credentialResponseSize+nn : credentialResponseSize+nn+npk]
+	serverMAC := ke2[credentialResponseSize+nn+npk:]
+
+	env, serverPublicKey, err := c.recoverCredentials(state.blind, credentialResponse)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	defer env.sk.SetZero()
+
+	_, serverIdentity, clientIdentity := c.config.cleartextCredentials(serverPublicKey, env.pk)
+	preamble := c.config.preamble(clientIdentity, state.ke1, serverIdentity, ke2[:len(ke2)-nm])
+
+	ikm, err := tripleDiffieHellman(
+		state.clientSecret, serverKeyshare,
+		state.clientSecret, serverPublicKey,
+		env.sk, serverKeyshare)
+	if err != nil {
+		return nil, nil, nil, ErrAuthentication
+	}
+
+	k := deriveKeys(ikm, preamble)
+
+	expectedServerMAC := mac(k.serverMACKey, hash(preamble))
+	if !hmac.Equal(serverMAC, expectedServerMAC) {
+		return nil, nil, nil, ErrAuthentication
+	}
+
+	return mac(k.clientMACKey, hash(preamble, expectedServerMAC)), k.sessionKey, env.exportKey, nil
+}
+
+// recoverCredentials returns the envelope of the client and the server public key of a credential response, as
+// RecoverCredentials of RFC 9807 section 6.3.2.2 does.
+func (c *Client) recoverCredentials(blind *oprf.Blind, credentialResponse []byte) (*envelope, []byte, error) {
+	evaluatedElement := credentialResponse[:noe]
+	maskingNonce := credentialResponse[noe : noe+nn]
+	maskedResponse := credentialResponse[noe+nn:]
+
+	randomizedPassword, err := c.randomizedPassword(blind, evaluatedElement)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opaque: finish login: %w", err)
+	}
+
+	maskingKey := expand(randomizedPassword, []byte("MaskingKey"), nh)
+	pad := expand(maskingKey, concat(maskingNonce, "CredentialResponsePad"), npk+envelopeSize)
+	response := xor(pad, maskedResponse)
+
+	serverPublicKey, nonce, authTag := response[:npk], response[npk:npk+nn], response[npk+nn:]
+
+	env, err := openEnvelope(randomizedPassword, nonce)
+	if err != nil {
+		return nil, nil, ErrAuthentication
+	}
+
+	credentials, _, _ := c.config.cleartextCredentials(serverPublicKey, env.pk)
+
+	if !hmac.Equal(authTag, env.authTag(credentials)) {
+		env.sk.SetZero()
+
+		return nil, nil, ErrAuthentication
+	}
+
+	return env, serverPublicKey, nil
+}
+
+// randomizedPassword returns the randomized password of the OPRF evaluation of a password, hardened by the KSF.
+func (c *Client) randomizedPassword(blind *oprf.Blind, evaluatedElement []byte) ([]byte, error) {
+	outputs, err := c.oprf.Finalize([]*oprf.Blind{blind}, [][]byte{evaluatedElement}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	stretched, err := c.config.ksf(outputs[0])
+	if err != nil {
+		return nil, fmt.Errorf("ksf: %w", err)
+	}
+
+	return extract(append(outputs[0], stretched...)), nil
+}
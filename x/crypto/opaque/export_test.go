@@ -0,0 +1,34 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opaque
+
+import (
+	"github.com/bwesterb/go-ristretto"
+
+	"github.com/trustbloc/kms-go/x/crypto/internal/ristretto255"
+)
+
+// RegistrationResponseWithKey is RegistrationResponse with the AKE private key sk instead of the key of the KMS: the
+// test vectors of RFC 9807 give the private key of the server, not its seed.
+func (s *Server) RegistrationResponseWithKey(sk, request, credentialID []byte) ([]byte, error) {
+	k, err := ristretto255.DeserializeScalar(sk)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.registrationResponse(new(ristretto.Point).ScalarMultBase(k).Bytes(), request, credentialID)
+}
+
+// StartLoginWithKey is StartLogin with the AKE private key sk instead of the key of the KMS.
+func (s *Server) StartLoginWithKey(sk, record, credentialID, ke1 []byte) (*ServerLoginState, []byte, error) {
+	k, err := ristretto255.DeserializeScalar(sk)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.startLogin(k, new(ristretto.Point).ScalarMultBase(k).Bytes(), record, credentialID, ke1)
+}
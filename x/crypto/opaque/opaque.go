@@ -0,0 +1,264 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package opaque provides the OPAQUE asymmetric password-authenticated key exchange of RFC 9807, with the
+// OPAQUE-3DH ristretto255-SHA512 configuration: the ristretto255-SHA512 OPRF of x/crypto/oprf, HKDF-SHA512,
+// HMAC-SHA512 and scrypt as key stretching function.
+//
+// A client registers a password with a server and later logs in with it: the server stores a record of the password
+// that it can't derive the password from, and never sees the password, even during the login. Both parties of a
+// login agree on a session key, the client also gets an export key only known to it, eg: to encrypt its data stored
+// by the server.
+//
+// The server secrets are keys of a KMS: the OPRF seed of the server is an HMAC-SHA512 key (OPRFSeedKeyType) and its
+// AKE private key is derived from an HMAC-SHA256 key (PrivateKeyType) as DeriveDiffieHellmanKeyPair of RFC 9807 does.
+// Both keys should be dedicated to OPAQUE and not used to compute MACs.
+//
+// The client identity of the protocol is the client public key and the server identity is the server public key,
+// unless set with WithServerIdentity.
+//
+// The package is experimental: its clients also run the OPRF of x/crypto/oprf, so NewClient requires both the
+// 'crypto/opaque' and 'crypto/oprf' features to be enabled (see the x package).
+package opaque
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bwesterb/go-ristretto"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/x/crypto/internal/ristretto255"
+	"github.com/trustbloc/kms-go/x/crypto/oprf"
+)
+
+const (
+	// RegistrationRequestSize is the size in bytes of a registration request.
+	RegistrationRequestSize = noe
+	// RegistrationResponseSize is the size in bytes of a registration response.
+	RegistrationResponseSize = noe + npk
+	// RecordSize is the size in bytes of a registration record.
+	RecordSize = npk + nh + envelopeSize
+	// KE1Size is the size in bytes of the first message of a login, sent by the client.
+	KE1Size = noe + nn + npk
+	// KE2Size is the size in bytes of the second message of a login, sent by the server.
+	KE2Size = credentialResponseSize + nn + npk + nm
+	// KE3Size is the size in bytes of the last message of a login, sent by the client.
+	KE3Size = nm
+
+	// OPRFSeedKeyType is the key type of the OPRF seed of a server.
+	OPRFSeedKeyType = kms.HMACSHA512Tag512Type
+	// PrivateKeyType is the key type of the AKE private key of a server.
+	PrivateKeyType = kms.HMACSHA256Tag256Type
+
+	nn    = 32
+	nseed = 32
+	npk   = ristretto255.ElementSize
+	nh    = sha512.Size
+	nm    = sha512.Size
+	nx    = sha512.Size
+	nok   = ristretto255.ScalarSize
+	noe   = oprf.ElementSize
+
+	envelopeSize           = nn + nm
+	credentialResponseSize = noe + nn + npk + envelopeSize
+
+	scryptN = 32768
+	scryptR = 8
+	scryptP = 1
+)
+
+// ErrAuthentication is returned when a login fails: wrong password, record or server keys, or a tampered message.
+var ErrAuthentication = errors.New("opaque: authentication failed")
+
+// KSF is a key stretching function, it hardens the OPRF output of passwords against offline dictionary attacks.
+type KSF func(msg []byte) ([]byte, error)
+
+type config struct {
+	context        []byte
+	serverIdentity []byte
+	ksf            KSF
+	random         io.Reader
+}
+
+// Opt is a Client or Server option.
+type Opt func(c *config)
+
+// WithContext sets the application context of the logins, empty by default. The client and the server must use the
+// same context.
+func WithContext(context []byte) Opt {
+	return func(c *config) {
+		c.context = context
+	}
+}
+
+// WithServerIdentity sets the server identity, the server public key by default. The client and the server must use
+// the same server identity.
+func WithServerIdentity(identity []byte) Opt {
+	return func(c *config) {
+		c.serverIdentity = identity
+	}
+}
+
+// WithKSF sets the key stretching function of a Client, scrypt with N = 32768, r = 8 and p = 1 by default. A client
+// must use the same function for the registration and the logins of a password.
+func WithKSF(ksf KSF) Opt {
+	return func(c *config) {
+		c.ksf = ksf
+	}
+}
+
+// WithRandom sets the source of the nonces, key share seeds and OPRF blinds (see oprf.WithRandom) of a Client or
+// Server, crypto/rand by default.
+func WithRandom(random io.Reader) Opt {
+	return func(c *config) {
+		c.random = random
+	}
+}
+
+func newConfig(opts []Opt) *config {
+	c := &config{ksf: scryptKSF, random: rand.Reader}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func scryptKSF(msg []byte) ([]byte, error) {
+	return scrypt.Key(msg, nil, scryptN, scryptR, scryptP, nh)
+}
+
+// cleartextCredentials returns the cleartext credentials of RFC 9807 section 4, with the identities of the config.
+func (c *config) cleartextCredentials(serverPublicKey, clientPublicKey []byte) ([]byte, []byte, []byte) {
+	serverIdentity := c.serverIdentity
+	if serverIdentity == nil {
+		serverIdentity = serverPublicKey
+	}
+
+	clientIdentity := clientPublicKey
+
+	credentials := append(append([]byte{}, serverPublicKey...),
+		ristretto255.LengthPrefixed(serverIdentity, clientIdentity)...)
+
+	return credentials, serverIdentity, clientIdentity
+}
+
+// envelope holds the keys derived from a randomized password and an envelope nonce, as Store of RFC 9807 does.
+type envelope struct {
+	nonce     []byte
+	authKey   []byte
+	exportKey []byte
+	sk        *ristretto.Scalar
+	pk        []byte
+}
+
+func openEnvelope(randomizedPassword, nonce []byte) (*envelope, error) {
+	sk, pk, err := deriveDiffieHellmanKeyPair(expand(randomizedPassword, concat(nonce, "PrivateKey"), nseed))
+	if err != nil {
+		return nil, err
+	}
+
+	return &envelope{
+		nonce:     nonce,
+		authKey:   expand(randomizedPassword, concat(nonce, "AuthKey"), nh),
+		exportKey: expand(randomizedPassword, concat(nonce, "ExportKey"), nh),
+		sk:        sk,
+		pk:        pk,
+	}, nil
+}
+
+// authTag returns the authentication tag of the envelope for the cleartext credentials.
+func (e *envelope) authTag(cleartextCredentials []byte) []byte {
+	return mac(e.authKey, e.nonce, cleartextCredentials)
+}
+
+// deriveDiffieHellmanKeyPair is DeriveDiffieHellmanKeyPair of RFC 9807 for ristretto255.
+func deriveDiffieHellmanKeyPair(seed []byte) (*ristretto.Scalar, []byte, error) {
+	sk, pk, err := oprf.DeriveKeyPair(oprf.ModeOPRF, seed, []byte("OPAQUE-DeriveDiffieHellmanKeyPair"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s, err := ristretto255.DeserializeScalar(sk)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, pk, nil
+}
+
+// diffieHellman returns the serialized element sk*pk.
+func diffieHellman(sk *ristretto.Scalar, pk []byte) ([]byte, error) {
+	p, err := ristretto255.DeserializeElement(pk)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(ristretto.Point).ScalarMult(p, sk).Bytes(), nil
+}
+
+func expand(prk, info []byte, size int) []byte {
+	out := make([]byte, size)
+
+	// HKDF-Expand only fails beyond 255 hash sizes of output.
+	_, _ = io.ReadFull(hkdf.Expand(sha512.New, prk, info), out) //nolint:errcheck
+
+	return out
+}
+
+func extract(ikm []byte) []byte {
+	return hkdf.Extract(sha512.New, ikm, nil)
+}
+
+func mac(key []byte, data ...[]byte) []byte {
+	h := hmac.New(sha512.New, key)
+
+	for _, d := range data {
+		h.Write(d)
+	}
+
+	return h.Sum(nil)
+}
+
+func hash(data ...[]byte) []byte {
+	h := sha512.New()
+
+	for _, d := range data {
+		h.Write(d)
+	}
+
+	return h.Sum(nil)
+}
+
+func xor(a, b []byte) []byte {
+	out := make([]byte, len(a))
+
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+
+	return out
+}
+
+func concat(b []byte, label string) []byte {
+	return append(append([]byte{}, b...), label...)
+}
+
+func checkSize(name string, b []byte, size int) error {
+	if len(b) != size {
+		return fmt.Errorf("invalid %s size", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,416 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opaque_test
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+	"github.com/trustbloc/kms-go/x"
+	"github.com/trustbloc/kms-go/x/crypto/opaque"
+)
+
+var (
+	password     = []byte("CorrectHorseBatteryStaple")
+	credentialID = []byte("alice@example.com")
+)
+
+// identityKSF is the Identity KSF of RFC 9807, fast for the tests.
+func identityKSF(msg []byte) ([]byte, error) {
+	return msg, nil
+}
+
+func TestRegistrationAndLogin(t *testing.T) {
+	x.Enable("crypto/opaque", "crypto/oprf")
+
+	server := newServer(t, opaque.WithContext([]byte("test")))
+
+	client, err := opaque.NewClient(opaque.WithContext([]byte("test")), opaque.WithKSF(identityKSF))
+	require.NoError(t, err)
+
+	record, exportKey := register(t, client, server, password)
+	require.Len(t, record, opaque.RecordSize)
+	require.Len(t, exportKey, sha512.Size)
+
+	for i := 0; i < 2; i++ {
+		clientSessionKey, serverSessionKey, loginExportKey, err := login(t, client, server, record, password)
+		require.NoError(t, err)
+		require.Equal(t, clientSessionKey, serverSessionKey)
+		require.Len(t, clientSessionKey, sha512.Size)
+		require.Equal(t, exportKey, loginExportKey)
+	}
+
+	t.Run("wrong password", func(t *testing.T) {
+		_, _, _, err = login(t, client, server, record, []byte("wrong password"))
+		require.ErrorIs(t, err, opaque.ErrAuthentication)
+	})
+
+	t.Run("unregistered client", func(t *testing.T) {
+		_, _, _, err = login(t, client, server, nil, password)
+		require.ErrorIs(t, err, opaque.ErrAuthentication)
+	})
+
+	t.Run("other credential identifier", func(t *testing.T) {
+		state, ke1, err := client.StartLogin(password)
+		require.NoError(t, err)
+
+		_, ke2, err := server.StartLogin(record, []byte("bob@example.com"), ke1)
+		require.NoError(t, err)
+
+		_, _, _, err = client.FinishLogin(state, ke2)
+		require.ErrorIs(t, err, opaque.ErrAuthentication)
+	})
+
+	t.Run("mismatched context", func(t *testing.T) {
+		other, err := opaque.NewClient(opaque.WithKSF(identityKSF))
+		require.NoError(t, err)
+
+		_, _, _, err = login(t, other, server, record, password)
+		require.ErrorIs(t, err, opaque.ErrAuthentication)
+	})
+
+	t.Run("tampered KE2", func(t *testing.T) {
+		state, ke1, err := client.StartLogin(password)
+		require.NoError(t, err)
+
+		_, ke2, err := server.StartLogin(record, credentialID, ke1)
+		require.NoError(t, err)
+
+		for _, i := range []int{opaque.KE2Size - 1, opaque.KE2Size - sha512.Size - 1, 40} {
+			tampered := append([]byte{}, ke2...)
+			tampered[i] ^= 1
+
+			_, _, _, err = client.FinishLogin(state, tampered)
+			require.Error(t, err)
+		}
+	})
+
+	t.Run("tampered KE3", func(t *testing.T) {
+		state, ke1, err := client.StartLogin(password)
+		require.NoError(t, err)
+
+		serverState, ke2, err := server.StartLogin(record, credentialID, ke1)
+		require.NoError(t, err)
+
+		ke3, _, _, err := client.FinishLogin(state, ke2)
+		require.NoError(t, err)
+
+		ke3[0] ^= 1
+
+		_, err = server.FinishLogin(serverState, ke3)
+		require.ErrorIs(t, err, opaque.ErrAuthentication)
+	})
+}
+
+func TestServerIdentity(t *testing.T) {
+	x.Enable("crypto/opaque", "crypto/oprf")
+
+	server := newServer(t, opaque.WithServerIdentity([]byte("example.com")))
+
+	client, err := opaque.NewClient(opaque.WithServerIdentity([]byte("example.com")), opaque.WithKSF(identityKSF))
+	require.NoError(t, err)
+
+	record, _ := register(t, client, server, password)
+
+	_, _, _, err = login(t, client, server, record, password)
+	require.NoError(t, err)
+
+	other, err := opaque.NewClient(opaque.WithServerIdentity([]byte("example.org")), opaque.WithKSF(identityKSF))
+	require.NoError(t, err)
+
+	_, _, _, err = login(t, other, server, record, password)
+	require.ErrorIs(t, err, opaque.ErrAuthentication)
+}
+
+func TestDefaultKSF(t *testing.T) {
+	x.Enable("crypto/opaque", "crypto/oprf")
+
+	server := newServer(t)
+
+	client, err := opaque.NewClient()
+	require.NoError(t, err)
+
+	record, exportKey := register(t, client, server, password)
+
+	_, _, loginExportKey, err := login(t, client, server, record, password)
+	require.NoError(t, err)
+	require.Equal(t, exportKey, loginExportKey)
+
+	fast, err := opaque.NewClient(opaque.WithKSF(identityKSF))
+	require.NoError(t, err)
+
+	_, _, _, err = login(t, fast, server, record, password)
+	require.ErrorIs(t, err, opaque.ErrAuthentication)
+
+	failing, err := opaque.NewClient(opaque.WithKSF(func([]byte) ([]byte, error) {
+		return nil, errors.New("ksf error")
+	}))
+	require.NoError(t, err)
+
+	state, request, err := failing.StartRegistration(password)
+	require.NoError(t, err)
+
+	response, err := server.RegistrationResponse(request, credentialID)
+	require.NoError(t, err)
+
+	_, _, err = failing.FinishRegistration(state, response)
+	require.EqualError(t, err, "opaque: finish registration: ksf: ksf error")
+}
+
+func TestServerErrors(t *testing.T) {
+	x.Enable("crypto/opaque", "crypto/oprf")
+
+	km := mockkms.NewForTest(t)
+
+	oprfSeedID, _, err := km.Create(opaque.OPRFSeedKeyType)
+	require.NoError(t, err)
+
+	privateKeyID, _, err := km.Create(opaque.PrivateKeyType)
+	require.NoError(t, err)
+
+	server := serverOf(t, km, oprfSeedID, privateKeyID)
+
+	t.Run("wrong key types", func(t *testing.T) {
+		_, err = serverOf(t, km, oprfSeedID, oprfSeedID).PublicKey()
+		require.EqualError(t, err, "opaque: public key: private key: key is not an HMAC-SHA256 key of 32 bytes")
+
+		_, err = serverOf(t, km, privateKeyID, privateKeyID).RegistrationResponse(make([]byte, 32), credentialID)
+		require.EqualError(t, err,
+			"opaque: registration response: oprf seed: key is not an HMAC-SHA512 key of 64 bytes")
+
+		_, err = serverOf(t, km, "unknown", privateKeyID).RegistrationResponse(make([]byte, 32), credentialID)
+		require.ErrorContains(t, err, "opaque: registration response: oprf seed: get key:")
+	})
+
+	t.Run("invalid messages", func(t *testing.T) {
+		_, err = server.RegistrationResponse(make([]byte, 31), credentialID)
+		require.EqualError(t, err, "opaque: registration response: invalid registration request size")
+
+		_, err = server.RegistrationResponse(make([]byte, 32), credentialID)
+		require.EqualError(t, err, "opaque: registration response: blinded element: invalid element")
+
+		_, _, err = server.StartLogin(nil, credentialID, make([]byte, 95))
+		require.EqualError(t, err, "opaque: start login: invalid KE1 size")
+
+		_, _, err = server.StartLogin(make([]byte, 191), credentialID, make([]byte, opaque.KE1Size))
+		require.EqualError(t, err, "opaque: start login: invalid record size")
+	})
+}
+
+func TestClientErrors(t *testing.T) {
+	x.Enable("crypto/opaque", "crypto/oprf")
+
+	client, err := opaque.NewClient(opaque.WithKSF(identityKSF))
+	require.NoError(t, err)
+
+	state, _, err := client.StartRegistration(password)
+	require.NoError(t, err)
+
+	_, _, err = client.FinishRegistration(state, make([]byte, 63))
+	require.EqualError(t, err, "opaque: finish registration: invalid registration response size")
+
+	_, _, err = client.FinishRegistration(state, make([]byte, opaque.RegistrationResponseSize))
+	require.ErrorContains(t, err, "opaque: finish registration: oprf: finalize: evaluated element 0:")
+
+	loginState, _, err := client.StartLogin(password)
+	require.NoError(t, err)
+
+	_, _, _, err = client.FinishLogin(loginState, make([]byte, 319))
+	require.EqualError(t, err, "opaque: finish login: invalid KE2 size")
+
+	x.Disable()
+
+	_, err = opaque.NewClient()
+	require.ErrorIs(t, err, x.ErrNotEnabled)
+
+	_, err = opaque.NewServer(nil, "", "")
+	require.ErrorIs(t, err, x.ErrNotEnabled)
+
+	// the OPRF of the client is also experimental.
+	x.Enable("crypto/opaque")
+
+	_, err = opaque.NewClient()
+	require.ErrorIs(t, err, x.ErrNotEnabled)
+	require.ErrorContains(t, err, "'crypto/oprf'")
+}
+
+// TestRFC9807Vectors checks the test vector C.1.1 of RFC 9807 (OPAQUE-3DH, ristretto255, Identity KSF), without the
+// client and server identities.
+func TestRFC9807Vectors(t *testing.T) {
+	x.Enable("crypto/opaque", "crypto/oprf")
+
+	h := func(s string) []byte {
+		b, err := hex.DecodeString(s)
+		require.NoError(t, err)
+
+		return b
+	}
+
+	var (
+		serverPrivateKey   = h("47451a85372f8b3537e249d7b54188091fb18edde78094b43e2ba42b5eb89f0d")
+		vectorCredentialID = h("31323334")
+		vectorPassword     = h("436f7272656374486f72736542617474657279537461706c65")
+		blindRegistration  = h("76cfbfe758db884bebb33582331ba9f159720ca8784a2a070a265d9c2d6abe01")
+		blindLogin         = h("6ecc102d2e7a7cf49617aad7bbe188556792d4acd60a1a8a8d2b65d4b0790308")
+		envelopeNonce      = h("ac13171b2f17bc2c74997f0fce1e1f35bec6b91fe2e12dbd323d23ba7a38dfec")
+		clientNonce        = h("da7e07376d6d6f034cfa9bb537d11b8c6b4238c334333d1f0aebb380cae6a6cc")
+		clientKeyshareSeed = h("82850a697b42a505f5b68fcdafce8c31f0af2b581f063cf1091933541936304b")
+		// the masking nonce, the server nonce and the server keyshare seed.
+		serverRandom = h("38fe59af0df2c79f57b8780278f5ae47355fe1f817119041951c80f612fdfc6d" +
+			"71cd9960ecef2fe0d0f7494986fa3d8b2bb01963537e60efb13981e138e3d4a1" +
+			"05a4f54206eef1ba2f615bc0aa285cb22f26d1153b5b40a1e85ff80da12f982f")
+		oprfSeed = h("f433d0227b0b9dd54f7c4422b600e764e47fb503f1f9a0f0a47c6606b054a7fdc65347f1a08f277e22358bbabe26f823" +
+			"fca82c7848e9a75661f4ec5d5c1989ef")
+		zeros = make([]byte, 32)
+	)
+
+	km := mockkms.NewForTest(t)
+
+	oprfSeedID, _, err := km.ImportPrivateKey(oprfSeed, opaque.OPRFSeedKeyType)
+	require.NoError(t, err)
+
+	server := serverOf(t, km, oprfSeedID, "", opaque.WithContext([]byte("OPAQUE-POC")),
+		opaque.WithRandom(bytes.NewReader(serverRandom)))
+
+	// the blinds are read as 64 bytes reduced modulo the group order.
+	clientRandom := bytes.Join([][]byte{
+		blindRegistration, zeros, envelopeNonce, blindLogin, zeros, clientNonce, clientKeyshareSeed,
+	}, nil)
+
+	client, err := opaque.NewClient(opaque.WithContext([]byte("OPAQUE-POC")), opaque.WithKSF(identityKSF),
+		opaque.WithRandom(bytes.NewReader(clientRandom)))
+	require.NoError(t, err)
+
+	state, request, err := client.StartRegistration(vectorPassword)
+	require.NoError(t, err)
+	require.Equal(t, h("5059ff249eb1551b7ce4991f3336205bde44a105a032e747d21bf382e75f7a71"), request)
+
+	response, err := server.RegistrationResponseWithKey(serverPrivateKey, request, vectorCredentialID)
+	require.NoError(t, err)
+	require.Equal(t, h("7408a268083e03abc7097fc05b587834539065e86fb0c7b6342fcf5e01e5b019b2fe7af9f48cc502d016729d2fe25cdd"+
+		"433f2c4bc904660b2a382c9b79df1a78"), response)
+
+	record, exportKey, err := client.FinishRegistration(state, response)
+	require.NoError(t, err)
+	require.Equal(t, h("76a845464c68a5d2f7e442436bb1424953b17d3e2e289ccbaccafb57ac5c36751ac5844383c7708077dea41cbefe2fa1"+
+		"5724f449e535dd7dd562e66f5ecfb95864eadddec9db5874959905117dad40a4524111849799281fefe3c51fa82785c5"+
+		"ac13171b2f17bc2c74997f0fce1e1f35bec6b91fe2e12dbd323d23ba7a38dfec634b0f5b96109c198a8027da51854c35"+
+		"bee90d1e1c781806d07d49b76de6a28b8d9e9b6c93b9f8b64d16dddd9c5bfb5fea48ee8fd2f75012a8b308605cdd8ba5"), record)
+	require.Equal(t, h("1ef15b4fa99e8a852412450ab78713aad30d21fa6966c9b8c9fb3262a970dc62950d4dd4ed62598229b1b72794fc0335"+
+		"199d9f7fcc6eaedde92cc04870e63f16"), exportKey)
+
+	loginState, ke1, err := client.StartLogin(vectorPassword)
+	require.NoError(t, err)
+	require.Equal(t, h("c4dedb0ba6ed5d965d6f250fbe554cd45cba5dfcce3ce836e4aee778aa3cd44dda7e07376d6d6f034cfa9bb537d11b8c"+
+		"6b4238c334333d1f0aebb380cae6a6cc6e29bee50701498605b2c085d7b241ca15ba5c32027dd21ba420b94ce60da326"), ke1)
+
+	serverState, ke2, err := server.StartLoginWithKey(serverPrivateKey, record, vectorCredentialID, ke1)
+	require.NoError(t, err)
+	require.Equal(t, h("7e308140890bcde30cbcea28b01ea1ecfbd077cff62c4def8efa075aabcbb47138fe59af0df2c79f57b8780278f5ae47"+
+		"355fe1f817119041951c80f612fdfc6dd6ec60bcdb26dc455ddf3e718f1020490c192d70dfc7e403981179d8073d1146"+
+		"a4f9aa1ced4e4cd984c657eb3b54ced3848326f70331953d91b02535af44d9fedc80188ca46743c52786e0382f95ad85"+
+		"c08f6afcd1ccfbff95e2bdeb015b166c6b20b92f832cc6df01e0b86a7efd92c1c804ff865781fa93f2f20b446c8371b6"+
+		"71cd9960ecef2fe0d0f7494986fa3d8b2bb01963537e60efb13981e138e3d4a1c4f62198a9d6fa9170c42c3c71f1971b"+
+		"29eb1d5d0bd733e40816c91f7912cc4a660c48dae03e57aaa38f3d0cffcfc21852ebc8b405d15bd6744945ba1a93438a"+
+		"162b6111699d98a16bb55b7bdddfe0fc5608b23da246e7bd73b47369169c5c90"), ke2)
+
+	ke3, clientSessionKey, loginExportKey, err := client.FinishLogin(loginState, ke2)
+	require.NoError(t, err)
+	require.Equal(t, h("4455df4f810ac31a6748835888564b536e6da5d9944dfea9e34defb9575fe5e2661ef61d2ae3929bcf57e53d464113d3"+
+		"64365eb7d1a57b629707ca48da18e442"), ke3)
+	require.Equal(t, exportKey, loginExportKey)
+
+	sessionKey := h("42afde6f5aca0cfa5c163763fbad55e73a41db6b41bc87b8e7b62214a8eedc6731fa3cb857d657ab9b3764b89a84e91e" +
+		"bcb4785166fbb02cedfcbdfda215b96f")
+	require.Equal(t, sessionKey, clientSessionKey)
+
+	serverSessionKey, err := server.FinishLogin(serverState, ke3)
+	require.NoError(t, err)
+	require.Equal(t, sessionKey, serverSessionKey)
+}
+
+func register(t *testing.T, client *opaque.Client, server *opaque.Server, pwd []byte) ([]byte, []byte) {
+	t.Helper()
+
+	state, request, err := client.StartRegistration(pwd)
+	require.NoError(t, err)
+	require.Len(t, request, opaque.RegistrationRequestSize)
+
+	response, err := server.RegistrationResponse(request, credentialID)
+	require.NoError(t, err)
+	require.Len(t, response, opaque.RegistrationResponseSize)
+
+	record, exportKey, err := client.FinishRegistration(state, response)
+	require.NoError(t, err)
+
+	return record, exportKey
+}
+
+// login logs in with password pwd, it returns the client and server session keys and the export key.
+func login(t *testing.T, client *opaque.Client, server *opaque.Server, record, pwd []byte) (
+	[]byte, []byte, []byte, error) {
+	t.Helper()
+
+	state, ke1, err := client.StartLogin(pwd)
+	require.NoError(t, err)
+	require.Len(t, ke1, opaque.KE1Size)
+
+	serverState, ke2, err := server.StartLogin(record, credentialID, ke1)
+	require.NoError(t, err)
+	require.Len(t, ke2, opaque.KE2Size)
+
+	ke3, clientSessionKey, exportKey, err := client.FinishLogin(state, ke2)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	require.Len(t, ke3, opaque.KE3Size)
+
+	serverSessionKey, err := server.FinishLogin(serverState, ke3)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return clientSessionKey, serverSessionKey, exportKey, nil
+}
+
+func serverOf(t *testing.T, km kmsapi.KeyManager, oprfSeedID, privateKeyID string,
+	opts ...opaque.Opt) *opaque.Server {
+	t.Helper()
+
+	server, err := opaque.NewServer(km, oprfSeedID, privateKeyID, opts...)
+	require.NoError(t, err)
+
+	return server
+}
+
+func newServer(t *testing.T, opts ...opaque.Opt) *opaque.Server {
+	t.Helper()
+
+	km := mockkms.NewForTest(t)
+
+	oprfSeedID, _, err := km.Create(opaque.OPRFSeedKeyType)
+	require.NoError(t, err)
+
+	privateKeyID, _, err := km.Create(opaque.PrivateKeyType)
+	require.NoError(t, err)
+
+	server := serverOf(t, km, oprfSeedID, privateKeyID, opts...)
+
+	pubKey, err := server.PublicKey()
+	require.NoError(t, err)
+	require.Len(t, pubKey, 32)
+
+	return server
+}
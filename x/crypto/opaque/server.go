@@ -0,0 +1,336 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opaque
+
+import (
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bwesterb/go-ristretto"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	hmacpb "github.com/google/tink/go/proto/hmac_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+
+	"github.com/trustbloc/kms-go/internal/memguard"
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/x"
+	"github.com/trustbloc/kms-go/x/crypto/internal/ristretto255"
+	"github.com/trustbloc/kms-go/x/crypto/oprf"
+)
+
+const (
+	hmacKeyTypeURL = "type.googleapis.com/google.crypto.tink.HmacKey"
+	oprfSeedSize   = 64
+)
+
+// Server registers the passwords of clients and authenticates their logins with the keys of a KeyManager.
+type Server struct {
+	km           kms.KeyManager
+	oprfSeedID   string
+	privateKeyID string
+	config       *config
+}
+
+// ServerLoginState is the state of a login kept by the server between StartLogin and FinishLogin.
+type ServerLoginState struct {
+	expectedClientMAC []byte
+	sessionKey        []byte
+}
+
+// NewServer creates a new Server with the OPRF seed oprfSeedID (OPRFSeedKeyType) and the AKE private key
+// privateKeyID (PrivateKeyType) of km.
+func NewServer(km kms.KeyManager, oprfSeedID, privateKeyID string, opts ...Opt) (*Server, error) {
+	if err := x.Require("crypto/opaque"); err != nil {
+		return nil, fmt.Errorf("opaque: new server: %w", err)
+	}
+
+	return &Server{
+		km:           km,
+		oprfSeedID:   oprfSeedID,
+		privateKeyID: privateKeyID,
+		config:       newConfig(opts),
+	}, nil
+}
+
+// PublicKey returns the 32 bytes AKE public key of the server.
+func (s *Server) PublicKey() ([]byte, error) {
+	sk, pk, err := s.keyPair()
+	if err != nil {
+		return nil, fmt.Errorf("opaque: public key: %w", err)
+	}
+
+	sk.SetZero()
+
+	return pk, nil
+}
+
+// RegistrationResponse returns the response to the registration request of the client of the credential identifier
+// credentialID, unique to the client, eg: a hash of its user name. The server stores the record of the client, sent
+// after the response, with its credential identifier.
+func (s *Server) RegistrationResponse(request, credentialID []byte) ([]byte, error) {
+	if err := checkSize("registration request", request, RegistrationRequestSize); err != nil {
+		return nil, fmt.Errorf("opaque: registration response: %w", err)
+	}
+
+	sk, pk, err := s.keyPair()
+	if err != nil {
+		return nil, fmt.Errorf("opaque: registration response: %w", err)
+	}
+
+	sk.SetZero()
+
+	response, err := s.registrationResponse(pk, request, credentialID)
+	if err != nil {
+		return nil, fmt.Errorf("opaque: registration response: %w", err)
+	}
+
+	return response, nil
+}
+
+// StartLogin starts the login of the client of the credential identifier credentialID and the record record with its
+// KE1 message ke1. It returns the login state and the KE2 message sent to the client. If the client is not
+// registered, record is nil: the server then responds with a fake record to not reveal the client is unknown, and the
+// login fails.
+func (s *Server) StartLogin(record, credentialID, ke1 []byte) (*ServerLoginState, []byte, error) {
+	if err := checkSize("KE1", ke1, KE1Size); err != nil {
+		return nil, nil, fmt.Errorf("opaque: start login: %w", err)
+	}
+
+	var err error
+
+	if record == nil {
+		record, err = s.fakeRecord()
+	} else {
+		err = checkSize("record", record, RecordSize)
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("opaque: start login: %w", err)
+	}
+
+	sk, pk, err := s.keyPair()
+	if err != nil {
+		return nil, nil, fmt.Errorf("opaque: start login: %w", err)
+	}
+
+	defer sk.SetZero()
+
+	state, ke2, err := s.startLogin(sk, pk, record, credentialID, ke1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opaque: start login: %w", err)
+	}
+
+	return state, ke2, nil
+}
+
+// FinishLogin finishes the login of state with the KE3 message of the client. It returns the session key of the
+// login, or ErrAuthentication if the client failed to authenticate.
+func (s *Server) FinishLogin(state *ServerLoginState, ke3 []byte) ([]byte, error) {
+	if !hmac.Equal(ke3, state.expectedClientMAC) {
+		return nil, ErrAuthentication
+	}
+
+	return state.sessionKey, nil
+}
+
+// registrationResponse returns the registration response of the server of public key pk, as
+// CreateRegistrationResponse of RFC 9807 does.
+func (s *Server) registrationResponse(pk, request, credentialID []byte) ([]byte, error) {
+	evaluatedElement, err := s.evaluate(request, credentialID)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(evaluatedElement, pk...), nil
+}
+
+// startLogin returns the login state and the KE2 message of the server of key pair sk and pk, as GenerateKE2 of
+// RFC 9807 does.
+func (s *Server) startLogin(sk *ristretto.Scalar, pk, record, credentialID, ke1 []byte) (*ServerLoginState, []byte,
+	error) {
+	credentialResponse, err := s.credentialResponse(record, credentialID, ke1[:noe], pk)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.respond(sk, pk, record[:npk], ke1, credentialResponse)
+}
+
+// credentialResponse returns the credential response of the record of a client, as CreateCredentialResponse of
+// RFC 9807 section 6.3.2.2.
+func (s *Server) credentialResponse(record, credentialID, blindedElement, serverPublicKey []byte) ([]byte, error) {
+	evaluatedElement, err := s.evaluate(blindedElement, credentialID)
+	if err != nil {
+		return nil, err
+	}
+
+	maskingNonce := make([]byte, nn)
+
+	if _, err = io.ReadFull(s.config.random, maskingNonce); err != nil {
+		return nil, err
+	}
+
+	maskingKey, env := record[npk:npk+nh], record[npk+nh:]
+	pad := expand(maskingKey, concat(maskingNonce, "CredentialResponsePad"), npk+envelopeSize)
+
+	response := append(evaluatedElement, maskingNonce...)
+
+	return append(response, xor(pad, append(append([]byte{}, serverPublicKey...), env...))...), nil
+}
+
+// respond returns the login state and the KE2 message of the credential response, as AuthServerRespond of
+// RFC 9807 section 6.4.3.
+func (s *Server) respond(sk *ristretto.Scalar, pk, clientPublicKey, ke1, credentialResponse []byte) (
+	*ServerLoginState, []byte, error) {
+	nonce := make([]byte, nn)
+	keyshareSeed := make([]byte, nseed)
+
+	if _, err := io.ReadFull(s.config.random, nonce); err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := io.ReadFull(s.config.random, keyshareSeed); err != nil {
+		return nil, nil, err
+	}
+
+	keyshareSK, keyshare, err := deriveDiffieHellmanKeyPair(keyshareSeed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer keyshareSK.SetZero()
+
+	clientKeyshare := ke1[noe+nn:]
+
+	ikm, err := tripleDiffieHellman(
+		keyshareSK, clientKeyshare,
+		sk, clientKeyshare,
+		keyshareSK, clientPublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ke2 := append(append([]byte{}, credentialResponse...), nonce...)
+	ke2 = append(ke2, keyshare...)
+
+	_, serverIdentity, clientIdentity := s.config.cleartextCredentials(pk, clientPublicKey)
+	preamble := s.config.preamble(clientIdentity, ke1, serverIdentity, ke2)
+
+	k := deriveKeys(ikm, preamble)
+	serverMAC := mac(k.serverMACKey, hash(preamble))
+
+	return &ServerLoginState{
+		expectedClientMAC: mac(k.clientMACKey, hash(preamble, serverMAC)),
+		sessionKey:        k.sessionKey,
+	}, append(ke2, serverMAC...), nil
+}
+
+// evaluate evaluates the blinded element of a password with the OPRF key of the credential identifier.
+func (s *Server) evaluate(blindedElement, credentialID []byte) ([]byte, error) {
+	oprfSeed, err := s.keyValue(s.oprfSeedID, commonpb.HashType_SHA512, oprfSeedSize)
+	if err != nil {
+		return nil, fmt.Errorf("oprf seed: %w", err)
+	}
+
+	seed := expand(oprfSeed, concat(credentialID, "OprfKey"), nok)
+	defer memguard.Wipe(seed)
+
+	skBytes, _, err := oprf.DeriveKeyPair(oprf.ModeOPRF, seed, []byte("OPAQUE-DeriveKeyPair"))
+	if err != nil {
+		return nil, err
+	}
+
+	defer memguard.Wipe(skBytes)
+
+	k, err := ristretto255.DeserializeScalar(skBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	defer k.SetZero()
+
+	evaluatedElement, err := diffieHellman(k, blindedElement)
+	if err != nil {
+		return nil, fmt.Errorf("blinded element: %w", err)
+	}
+
+	return evaluatedElement, nil
+}
+
+// fakeRecord returns a random record, the record of RFC 9807 section 10.9 used for the clients that are not
+// registered.
+func (s *Server) fakeRecord() ([]byte, error) {
+	seed := make([]byte, nseed)
+	maskingKey := make([]byte, nh)
+
+	if _, err := io.ReadFull(s.config.random, seed); err != nil {
+		return nil, err
+	}
+
+	if _, err := io.ReadFull(s.config.random, maskingKey); err != nil {
+		return nil, err
+	}
+
+	sk, pk, err := deriveDiffieHellmanKeyPair(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	sk.SetZero()
+
+	record := append(pk, maskingKey...)
+
+	return append(record, make([]byte, envelopeSize)...), nil
+}
+
+// keyPair returns the AKE key pair of the server, derived from the seed of its private key.
+func (s *Server) keyPair() (*ristretto.Scalar, []byte, error) {
+	seed, err := s.keyValue(s.privateKeyID, commonpb.HashType_SHA256, nseed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("private key: %w", err)
+	}
+
+	return deriveDiffieHellmanKeyPair(seed)
+}
+
+// keyValue returns the raw key value of the primary HMAC key of the key keyID, of the hash function hash and of
+// size bytes.
+func (s *Server) keyValue(keyID string, hash commonpb.HashType, size int) ([]byte, error) {
+	kh, err := s.km.Get(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("get key: %w", err)
+	}
+
+	handle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errors.New("key is not a keyset handle")
+	}
+
+	ks := insecurecleartextkeyset.KeysetMaterial(handle)
+
+	for _, k := range ks.Key {
+		if k.KeyId != ks.PrimaryKeyId || k.Status != tinkpb.KeyStatusType_ENABLED {
+			continue
+		}
+
+		key := &hmacpb.HmacKey{}
+
+		if k.KeyData.TypeUrl != hmacKeyTypeURL || proto.Unmarshal(k.KeyData.Value, key) != nil ||
+			key.Params.GetHash() != hash || len(key.KeyValue) != size {
+			return nil, fmt.Errorf("key is not an HMAC-%s key of %d bytes", hash, size)
+		}
+
+		return key.KeyValue, nil
+	}
+
+	return nil, errors.New("primary key not found")
+}
@@ -0,0 +1,434 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package oprf provides the ristretto255-SHA512 oblivious pseudorandom functions of RFC 9497, in the OPRF and
+// verifiable OPRF (VOPRF) modes.
+//
+// A client blinds its input, the server evaluates the blinded input with its secret key and the client finalizes the
+// evaluation into the PRF output of its input: the server learns neither the input nor the output, the client learns
+// nothing of the key. In the VOPRF mode, the evaluations come with a proof that they used the key of the server
+// public key. OPRFs are the building block of password-authenticated key exchanges, private set intersections or
+// rate limited token issuance.
+//
+// The server secret is a key of a KMS: an HMAC-SHA256 key (kms.HMACSHA256Tag256Type) whose 32 bytes key value is the
+// seed of the DeriveKeyPair function of RFC 9497, with the key info of the Server. The key should be dedicated to the
+// OPRF and not used to compute MACs.
+package oprf
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bwesterb/go-ristretto"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	hmacpb "github.com/google/tink/go/proto/hmac_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+
+	"github.com/trustbloc/kms-go/internal/memguard"
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/x"
+	"github.com/trustbloc/kms-go/x/crypto/internal/ristretto255"
+)
+
+// Mode is a protocol mode of RFC 9497.
+type Mode byte
+
+const (
+	// ModeOPRF is the OPRF mode.
+	ModeOPRF Mode = 0x00
+	// ModeVOPRF is the verifiable OPRF mode: the client verifies the proofs of the server evaluations.
+	ModeVOPRF Mode = 0x01
+)
+
+const (
+	// ElementSize is the size in bytes of a serialized element: blinded and evaluated elements and public keys.
+	ElementSize = ristretto255.ElementSize
+	// ScalarSize is the size in bytes of a serialized scalar, a private key.
+	ScalarSize = ristretto255.ScalarSize
+	// OutputSize is the size in bytes of an OPRF output.
+	OutputSize = sha512.Size
+
+	suiteIdentifier = "ristretto255-SHA512"
+	seedSize        = 32
+	maxInputSize    = 1<<16 - 1
+	maxCounter      = 255
+
+	hmacKeyTypeURL = "type.googleapis.com/google.crypto.tink.HmacKey"
+)
+
+// ErrInvalidProof is returned by Client.Finalize when the proof of VOPRF evaluations is not valid.
+var ErrInvalidProof = errors.New("oprf: invalid proof")
+
+var errInputTooLong = errors.New("input too long")
+
+// suite is the ristretto255-SHA512 suite of a mode.
+type suite struct {
+	mode             Mode
+	contextString    string
+	hashToGroupDST   []byte
+	hashToScalarDST  []byte
+	deriveKeyPairDST []byte
+}
+
+func newSuite(mode Mode) (*suite, error) {
+	if mode != ModeOPRF && mode != ModeVOPRF {
+		return nil, fmt.Errorf("unsupported mode %d", mode)
+	}
+
+	contextString := "OPRFV1-" + string([]byte{byte(mode)}) + "-" + suiteIdentifier
+
+	return &suite{
+		mode:             mode,
+		contextString:    contextString,
+		hashToGroupDST:   []byte("HashToGroup-" + contextString),
+		hashToScalarDST:  []byte("HashToScalar-" + contextString),
+		deriveKeyPairDST: []byte("DeriveKeyPair" + contextString),
+	}, nil
+}
+
+// DeriveKeyPair is the DeriveKeyPair function of RFC 9497 section 3.2.1 in the mode mode: it returns the serialized
+// private and public keys of seed and info. Protocols built on the OPRF, as OPAQUE, derive their keys with it.
+func DeriveKeyPair(mode Mode, seed, info []byte) ([]byte, []byte, error) {
+	st, err := newSuite(mode)
+	if err != nil {
+		return nil, nil, fmt.Errorf("oprf: derive key pair: %w", err)
+	}
+
+	sk, err := st.deriveKeyPair(seed, info)
+	if err != nil {
+		return nil, nil, fmt.Errorf("oprf: derive key pair: %w", err)
+	}
+
+	defer sk.SetZero()
+
+	return sk.Bytes(), new(ristretto.Point).ScalarMultBase(sk).Bytes(), nil
+}
+
+// deriveKeyPair is the DeriveKeyPair function of RFC 9497 section 3.2.1, it returns the private key of seed and info.
+func (s *suite) deriveKeyPair(seed, info []byte) (*ristretto.Scalar, error) {
+	if len(info) > maxInputSize {
+		return nil, errInputTooLong
+	}
+
+	deriveInput := append(append([]byte{}, seed...), ristretto255.LengthPrefixed(info)...)
+	defer memguard.Wipe(deriveInput)
+
+	for counter := 0; counter <= maxCounter; counter++ {
+		sk := ristretto255.HashToScalar(append(deriveInput, byte(counter)), s.deriveKeyPairDST)
+
+		if sk.IsNonZeroI() == 1 {
+			return sk, nil
+		}
+	}
+
+	return nil, errors.New("derive key pair error")
+}
+
+// hashInput returns the element of input, as the HashToGroup function of the suite.
+func (s *suite) hashInput(input []byte) (*ristretto.Point, error) {
+	if len(input) > maxInputSize {
+		return nil, errInputTooLong
+	}
+
+	p := ristretto255.HashToGroup(input, s.hashToGroupDST)
+
+	if p.Equals(new(ristretto.Point).SetZero()) {
+		return nil, errors.New("invalid input")
+	}
+
+	return p, nil
+}
+
+// finalize returns the OPRF output of input and of its unblinded element.
+func finalize(input []byte, unblinded *ristretto.Point) []byte {
+	h := sha512.Sum512(append(ristretto255.LengthPrefixed(input, unblinded.Bytes()), "Finalize"...))
+
+	return h[:]
+}
+
+// seedOf returns the raw key value of the primary HMAC-SHA256 key of kh, the seed of the OPRF private key.
+func seedOf(kh interface{}) ([]byte, error) {
+	handle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errors.New("key is not a keyset handle")
+	}
+
+	ks := insecurecleartextkeyset.KeysetMaterial(handle)
+
+	for _, k := range ks.Key {
+		if k.KeyId != ks.PrimaryKeyId || k.Status != tinkpb.KeyStatusType_ENABLED {
+			continue
+		}
+
+		key := &hmacpb.HmacKey{}
+
+		if k.KeyData.TypeUrl != hmacKeyTypeURL || proto.Unmarshal(k.KeyData.Value, key) != nil ||
+			key.Params.GetHash() != commonpb.HashType_SHA256 || len(key.KeyValue) != seedSize {
+			return nil, errors.New("key is not an HMAC-SHA256 key")
+		}
+
+		return key.KeyValue, nil
+	}
+
+	return nil, errors.New("primary key not found")
+}
+
+// Server evaluates the OPRF with the keys of a KeyManager.
+type Server struct {
+	km      kms.KeyManager
+	suite   *suite
+	keyInfo []byte
+}
+
+// Opt is a Server option.
+type Opt func(s *Server)
+
+// WithKeyInfo sets the key info the private keys of the Server are derived with, empty by default: a key derives a
+// different private key for each key info.
+func WithKeyInfo(info []byte) Opt {
+	return func(s *Server) {
+		s.keyInfo = info
+	}
+}
+
+// NewServer creates a new Server of the keys of km, in the mode mode.
+func NewServer(km kms.KeyManager, mode Mode, opts ...Opt) (*Server, error) {
+	if err := x.Require("crypto/oprf"); err != nil {
+		return nil, fmt.Errorf("oprf: new server: %w", err)
+	}
+
+	st, err := newSuite(mode)
+	if err != nil {
+		return nil, fmt.Errorf("oprf: new server: %w", err)
+	}
+
+	s := &Server{km: km, suite: st}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
+}
+
+// PublicKey returns the 32 bytes public key of the key keyID, used by the VOPRF clients to verify the evaluations.
+func (s *Server) PublicKey(keyID string) ([]byte, error) {
+	sk, err := s.privateKey(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("oprf: public key: %w", err)
+	}
+
+	defer sk.SetZero()
+
+	return new(ristretto.Point).ScalarMultBase(sk).Bytes(), nil
+}
+
+// BlindEvaluate evaluates the blinded elements blindedElements of the clients with the key keyID. It returns the
+// evaluated elements and, in the VOPRF mode, the proof of the batch of evaluations, nil in the OPRF mode.
+func (s *Server) BlindEvaluate(keyID string, blindedElements [][]byte) ([][]byte, []byte, error) {
+	if len(blindedElements) == 0 {
+		return nil, nil, errors.New("oprf: blind evaluate: no blinded elements")
+	}
+
+	sk, err := s.privateKey(keyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("oprf: blind evaluate: %w", err)
+	}
+
+	defer sk.SetZero()
+
+	blinded := make([]*ristretto.Point, len(blindedElements))
+	evaluated := make([]*ristretto.Point, len(blindedElements))
+	evaluatedElements := make([][]byte, len(blindedElements))
+
+	for i, b := range blindedElements {
+		blinded[i], err = ristretto255.DeserializeElement(b)
+		if err != nil {
+			return nil, nil, fmt.Errorf("oprf: blind evaluate: blinded element %d: %w", i, err)
+		}
+
+		evaluated[i] = new(ristretto.Point).ScalarMult(blinded[i], sk)
+		evaluatedElements[i] = evaluated[i].Bytes()
+	}
+
+	if s.suite.mode == ModeOPRF {
+		return evaluatedElements, nil, nil
+	}
+
+	var r ristretto.Scalar
+
+	r.Rand()
+	defer r.SetZero()
+
+	pkS := new(ristretto.Point).ScalarMultBase(sk)
+
+	return evaluatedElements, s.suite.generateProof(sk, pkS, blinded, evaluated, &r), nil
+}
+
+// Evaluate returns the OPRF output of input with the key keyID, the output a client gets for input with
+// BlindEvaluate, without the blinding.
+func (s *Server) Evaluate(keyID string, input []byte) ([]byte, error) {
+	p, err := s.suite.hashInput(input)
+	if err != nil {
+		return nil, fmt.Errorf("oprf: evaluate: %w", err)
+	}
+
+	sk, err := s.privateKey(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("oprf: evaluate: %w", err)
+	}
+
+	defer sk.SetZero()
+
+	return finalize(input, new(ristretto.Point).ScalarMult(p, sk)), nil
+}
+
+func (s *Server) privateKey(keyID string) (*ristretto.Scalar, error) {
+	kh, err := s.km.Get(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("get key: %w", err)
+	}
+
+	seed, err := seedOf(kh)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.suite.deriveKeyPair(seed, s.keyInfo)
+}
+
+// Blind is the blinding of an input by a Client, kept by the client to finalize the evaluation of its blinded
+// element.
+type Blind struct {
+	input          []byte
+	r              ristretto.Scalar
+	blindedElement *ristretto.Point
+}
+
+// BlindedElement returns the 32 bytes blinded element, sent to the server.
+func (b *Blind) BlindedElement() []byte {
+	return b.blindedElement.Bytes()
+}
+
+// Client blinds inputs and finalizes their evaluations by a server.
+type Client struct {
+	suite  *suite
+	pubKey *ristretto.Point
+	random io.Reader
+}
+
+// ClientOpt is a Client option.
+type ClientOpt func(c *Client)
+
+// WithRandom sets the source of the blinds of the Client, crypto/rand by default. A blind is the scalar of 64 bytes
+// read from random, little-endian, reduced modulo the group order.
+func WithRandom(random io.Reader) ClientOpt {
+	return func(c *Client) {
+		c.random = random
+	}
+}
+
+// NewClient creates a new Client in the mode mode. The public key pubKey of the server is required in the VOPRF mode
+// to verify the evaluations, it is ignored in the OPRF mode.
+func NewClient(mode Mode, pubKey []byte, opts ...ClientOpt) (*Client, error) {
+	if err := x.Require("crypto/oprf"); err != nil {
+		return nil, fmt.Errorf("oprf: new client: %w", err)
+	}
+
+	st, err := newSuite(mode)
+	if err != nil {
+		return nil, fmt.Errorf("oprf: new client: %w", err)
+	}
+
+	c := &Client{suite: st, random: rand.Reader}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if mode == ModeVOPRF {
+		c.pubKey, err = ristretto255.DeserializeElement(pubKey)
+		if err != nil {
+			return nil, fmt.Errorf("oprf: new client: public key: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+// Blind blinds input with a random blind.
+func (c *Client) Blind(input []byte) (*Blind, error) {
+	var buf [64]byte
+
+	if _, err := io.ReadFull(c.random, buf[:]); err != nil {
+		return nil, fmt.Errorf("oprf: blind: %w", err)
+	}
+
+	var r ristretto.Scalar
+
+	r.SetReduced(&buf)
+	memguard.Wipe(buf[:])
+
+	b, err := c.blind(input, &r)
+	if err != nil {
+		return nil, fmt.Errorf("oprf: blind: %w", err)
+	}
+
+	return b, nil
+}
+
+func (c *Client) blind(input []byte, r *ristretto.Scalar) (*Blind, error) {
+	p, err := c.suite.hashInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Blind{input: append([]byte{}, input...)}
+	b.r.Set(r)
+	b.blindedElement = new(ristretto.Point).ScalarMult(p, r)
+
+	return b, nil
+}
+
+// Finalize returns the OPRF outputs of the inputs of blinds from the evaluated elements of their blinded elements,
+// in the same order. In the VOPRF mode, proof is the proof of the batch of evaluations: Finalize fails with
+// ErrInvalidProof if it is not valid.
+func (c *Client) Finalize(blinds []*Blind, evaluatedElements [][]byte, proof []byte) ([][]byte, error) {
+	if len(blinds) == 0 || len(blinds) != len(evaluatedElements) {
+		return nil, errors.New("oprf: finalize: mismatched number of blinds and evaluated elements")
+	}
+
+	evaluated := make([]*ristretto.Point, len(blinds))
+	blinded := make([]*ristretto.Point, len(blinds))
+
+	for i, e := range evaluatedElements {
+		p, err := ristretto255.DeserializeElement(e)
+		if err != nil {
+			return nil, fmt.Errorf("oprf: finalize: evaluated element %d: %w", i, err)
+		}
+
+		evaluated[i], blinded[i] = p, blinds[i].blindedElement
+	}
+
+	if c.suite.mode == ModeVOPRF && !c.suite.verifyProof(c.pubKey, blinded, evaluated, proof) {
+		return nil, ErrInvalidProof
+	}
+
+	outputs := make([][]byte, len(blinds))
+
+	for i, b := range blinds {
+		inv := new(ristretto.Scalar).Inverse(&b.r)
+		outputs[i] = finalize(b.input, new(ristretto.Point).ScalarMult(evaluated[i], inv))
+	}
+
+	return outputs, nil
+}
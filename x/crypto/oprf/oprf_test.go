@@ -0,0 +1,312 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oprf
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+	"github.com/trustbloc/kms-go/x"
+)
+
+// RFC 9497 appendix A.1 test vectors.
+var (
+	testSeed    = bytes.Repeat([]byte{0xa3}, seedSize)
+	testKeyInfo = []byte("test key")
+)
+
+func TestDeriveKeyPair(t *testing.T) {
+	for mode, skSm := range map[Mode]string{
+		ModeOPRF:  "5ebcea5ee37023ccb9fc2d2019f9d7737be85591ae8652ffa9ef0f4d37063b0e",
+		ModeVOPRF: "e6f73f344b79b379f1a0dd37e07ff62e38d9f71345ce62ae3a9bc60b04ccd909",
+	} {
+		s, err := newSuite(mode)
+		require.NoError(t, err)
+
+		sk, err := s.deriveKeyPair(testSeed, testKeyInfo)
+		require.NoError(t, err)
+		require.Equal(t, decode(t, skSm), sk.Bytes())
+	}
+}
+
+func TestServer_Evaluate(t *testing.T) {
+	x.Enable("crypto/oprf")
+
+	km := mockkms.NewForTest(t)
+
+	keyID, _, err := km.ImportPrivateKey(testSeed, kmsapi.HMACSHA256Tag256Type)
+	require.NoError(t, err)
+
+	server, err := NewServer(km, ModeOPRF, WithKeyInfo(testKeyInfo))
+	require.NoError(t, err)
+
+	output, err := server.Evaluate(keyID, decode(t, "00"))
+	require.NoError(t, err)
+	require.Equal(t, decode(t, "527759c3d9366f277d8c6020418d96bb393ba2afb20ff90df23fb7708264e2f3"+
+		"ab9135e3bd69955851de4b1f9fe8a0973396719b7912ba9ee8aa7d0b5e24bcf6"), output)
+}
+
+// TestClient_WithRandom checks the RFC 9497 appendix A.1.1 test vector 1, with the blind read from the random source
+// of the client: the blind, little-endian and smaller than the group order, followed by zeros.
+func TestClient_WithRandom(t *testing.T) {
+	x.Enable("crypto/oprf")
+
+	km := mockkms.NewForTest(t)
+
+	keyID, _, err := km.ImportPrivateKey(testSeed, kmsapi.HMACSHA256Tag256Type)
+	require.NoError(t, err)
+
+	server, err := NewServer(km, ModeOPRF, WithKeyInfo(testKeyInfo))
+	require.NoError(t, err)
+
+	random := append(decode(t, "64d37aed22a27f5191de1c1d69fadb899d8862b58eb4220029e036ec4c1f6706"), make([]byte, 32)...)
+
+	client, err := NewClient(ModeOPRF, nil, WithRandom(bytes.NewReader(random)))
+	require.NoError(t, err)
+
+	blind, err := client.Blind(decode(t, "00"))
+	require.NoError(t, err)
+	require.Equal(t, decode(t, "609a0ae68c15a3cf6903766461307e5c8bb2f95e7e6550e1ffa2dc99e412803c"),
+		blind.BlindedElement())
+
+	evaluatedElements, _, err := server.BlindEvaluate(keyID, [][]byte{blind.BlindedElement()})
+	require.NoError(t, err)
+	require.Equal(t, decode(t, "7ec6578ae5120958eb2db1745758ff379e77cb64fe77b0b2d8cc917ea0869c7e"),
+		evaluatedElements[0])
+
+	outputs, err := client.Finalize([]*Blind{blind}, evaluatedElements, nil)
+	require.NoError(t, err)
+	require.Equal(t, decode(t, "527759c3d9366f277d8c6020418d96bb393ba2afb20ff90df23fb7708264e2f3"+
+		"ab9135e3bd69955851de4b1f9fe8a0973396719b7912ba9ee8aa7d0b5e24bcf6"), outputs[0])
+
+	// the random source is exhausted.
+	_, err = client.Blind(decode(t, "00"))
+	require.EqualError(t, err, "oprf: blind: EOF")
+}
+
+func TestBlindEvaluate(t *testing.T) {
+	x.Enable("crypto/oprf")
+
+	km := mockkms.NewForTest(t)
+
+	keyID, _, err := km.Create(kmsapi.HMACSHA256Tag256Type)
+	require.NoError(t, err)
+
+	inputs := [][]byte{[]byte("alice@example.com"), {}, bytes.Repeat([]byte{0x5a}, 17)}
+
+	for _, mode := range []Mode{ModeOPRF, ModeVOPRF} {
+		server, err := NewServer(km, mode)
+		require.NoError(t, err)
+
+		pubKey, err := server.PublicKey(keyID)
+		require.NoError(t, err)
+		require.Len(t, pubKey, ElementSize)
+
+		client, err := NewClient(mode, pubKey)
+		require.NoError(t, err)
+
+		blinds := make([]*Blind, len(inputs))
+		blindedElements := make([][]byte, len(inputs))
+
+		for i, input := range inputs {
+			blinds[i], err = client.Blind(input)
+			require.NoError(t, err)
+
+			blindedElements[i] = blinds[i].BlindedElement()
+		}
+
+		evaluatedElements, proof, err := server.BlindEvaluate(keyID, blindedElements)
+		require.NoError(t, err)
+		require.Len(t, evaluatedElements, len(inputs))
+
+		if mode == ModeOPRF {
+			require.Nil(t, proof)
+		} else {
+			require.Len(t, proof, ProofSize)
+		}
+
+		outputs, err := client.Finalize(blinds, evaluatedElements, proof)
+		require.NoError(t, err)
+
+		for i, input := range inputs {
+			output, err := server.Evaluate(keyID, input)
+			require.NoError(t, err)
+			require.Len(t, output, OutputSize)
+			require.Equal(t, output, outputs[i])
+		}
+
+		// a single evaluation is a batch of one.
+		single, proof, err := server.BlindEvaluate(keyID, blindedElements[:1])
+		require.NoError(t, err)
+
+		singleOutputs, err := client.Finalize(blinds[:1], single, proof)
+		require.NoError(t, err)
+		require.Equal(t, outputs[:1], singleOutputs)
+		require.Equal(t, evaluatedElements[0], single[0])
+
+		// the blinds are random.
+		other, err := client.Blind(inputs[0])
+		require.NoError(t, err)
+		require.NotEqual(t, blindedElements[0], other.BlindedElement())
+	}
+
+	t.Run("key info", func(t *testing.T) {
+		server, err := NewServer(km, ModeOPRF)
+		require.NoError(t, err)
+
+		withInfo, err := NewServer(km, ModeOPRF, WithKeyInfo(testKeyInfo))
+		require.NoError(t, err)
+
+		output1, err := server.Evaluate(keyID, inputs[0])
+		require.NoError(t, err)
+
+		output2, err := withInfo.Evaluate(keyID, inputs[0])
+		require.NoError(t, err)
+		require.NotEqual(t, output1, output2)
+	})
+}
+
+func TestVerifiableMode(t *testing.T) {
+	x.Enable("crypto/oprf")
+
+	km := mockkms.NewForTest(t)
+
+	keyID, _, err := km.Create(kmsapi.HMACSHA256Tag256Type)
+	require.NoError(t, err)
+
+	otherKeyID, _, err := km.Create(kmsapi.HMACSHA256Tag256Type)
+	require.NoError(t, err)
+
+	server, err := NewServer(km, ModeVOPRF)
+	require.NoError(t, err)
+
+	pubKey, err := server.PublicKey(keyID)
+	require.NoError(t, err)
+
+	client, err := NewClient(ModeVOPRF, pubKey)
+	require.NoError(t, err)
+
+	blind1, err := client.Blind([]byte("input 1"))
+	require.NoError(t, err)
+
+	blind2, err := client.Blind([]byte("input 2"))
+	require.NoError(t, err)
+
+	blinds := []*Blind{blind1, blind2}
+	blindedElements := [][]byte{blind1.BlindedElement(), blind2.BlindedElement()}
+
+	evaluatedElements, proof, err := server.BlindEvaluate(keyID, blindedElements)
+	require.NoError(t, err)
+
+	// the evaluations of another key don't verify with the public key.
+	otherElements, otherProof, err := server.BlindEvaluate(otherKeyID, blindedElements)
+	require.NoError(t, err)
+
+	_, err = client.Finalize(blinds, otherElements, otherProof)
+	require.ErrorIs(t, err, ErrInvalidProof)
+
+	_, err = client.Finalize(blinds, [][]byte{evaluatedElements[0], otherElements[1]}, proof)
+	require.ErrorIs(t, err, ErrInvalidProof)
+
+	// the proof covers the whole batch, in order.
+	_, err = client.Finalize([]*Blind{blind2, blind1}, [][]byte{evaluatedElements[1], evaluatedElements[0]}, proof)
+	require.ErrorIs(t, err, ErrInvalidProof)
+
+	tampered := append([]byte{}, proof...)
+	tampered[ScalarSize] ^= 1
+	_, err = client.Finalize(blinds, evaluatedElements, tampered)
+	require.ErrorIs(t, err, ErrInvalidProof)
+
+	_, err = client.Finalize(blinds, evaluatedElements, proof[1:])
+	require.ErrorIs(t, err, ErrInvalidProof)
+
+	_, err = client.Finalize(blinds, evaluatedElements, bytes.Repeat([]byte{0xff}, ProofSize))
+	require.ErrorIs(t, err, ErrInvalidProof)
+
+	_, err = client.Finalize(blinds, evaluatedElements, proof)
+	require.NoError(t, err)
+}
+
+func TestErrors(t *testing.T) {
+	x.Enable("crypto/oprf")
+
+	km := mockkms.NewForTest(t)
+
+	keyID, _, err := km.Create(kmsapi.HMACSHA256Tag256Type)
+	require.NoError(t, err)
+
+	server, err := NewServer(km, ModeOPRF)
+	require.NoError(t, err)
+
+	client, err := NewClient(ModeOPRF, nil)
+	require.NoError(t, err)
+
+	blind, err := client.Blind([]byte("input"))
+	require.NoError(t, err)
+
+	_, err = NewServer(km, Mode(2))
+	require.EqualError(t, err, "oprf: new server: unsupported mode 2")
+
+	_, err = NewClient(Mode(2), nil)
+	require.EqualError(t, err, "oprf: new client: unsupported mode 2")
+
+	_, err = NewClient(ModeVOPRF, nil)
+	require.EqualError(t, err, "oprf: new client: public key: invalid element")
+
+	_, err = NewClient(ModeVOPRF, make([]byte, ElementSize))
+	require.EqualError(t, err, "oprf: new client: public key: invalid element")
+
+	_, err = client.Blind(make([]byte, maxInputSize+1))
+	require.EqualError(t, err, "oprf: blind: input too long")
+
+	_, err = server.Evaluate(keyID, make([]byte, maxInputSize+1))
+	require.EqualError(t, err, "oprf: evaluate: input too long")
+
+	_, _, err = server.BlindEvaluate(keyID, nil)
+	require.EqualError(t, err, "oprf: blind evaluate: no blinded elements")
+
+	_, _, err = server.BlindEvaluate(keyID, [][]byte{blind.BlindedElement(), bytes.Repeat([]byte{0xff}, ElementSize)})
+	require.EqualError(t, err, "oprf: blind evaluate: blinded element 1: invalid element")
+
+	_, err = client.Finalize([]*Blind{blind}, nil, nil)
+	require.EqualError(t, err, "oprf: finalize: mismatched number of blinds and evaluated elements")
+
+	_, err = client.Finalize([]*Blind{blind}, [][]byte{make([]byte, ElementSize)}, nil)
+	require.EqualError(t, err, "oprf: finalize: evaluated element 0: invalid element")
+
+	hmacKeyID, _, err := km.Create(kmsapi.HMACSHA512Tag512Type)
+	require.NoError(t, err)
+
+	_, err = server.PublicKey(hmacKeyID)
+	require.EqualError(t, err, "oprf: public key: key is not an HMAC-SHA256 key")
+
+	_, err = server.Evaluate("unknown", nil)
+	require.ErrorContains(t, err, "oprf: evaluate: get key")
+
+	x.Disable()
+
+	_, err = NewServer(km, ModeOPRF)
+	require.ErrorIs(t, err, x.ErrNotEnabled)
+
+	_, err = NewClient(ModeOPRF, nil)
+	require.ErrorIs(t, err, x.ErrNotEnabled)
+	require.ErrorContains(t, err, "oprf: new client: ")
+}
+
+func decode(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+
+	return b
+}
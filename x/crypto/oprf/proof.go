@@ -0,0 +1,107 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oprf
+
+import (
+	"crypto/sha512"
+	"crypto/subtle"
+
+	"github.com/bwesterb/go-ristretto"
+
+	"github.com/trustbloc/kms-go/x/crypto/internal/ristretto255"
+)
+
+// ProofSize is the size in bytes of a VOPRF proof.
+const ProofSize = 2 * ScalarSize
+
+// generateProof returns the proof that the discrete logarithms of pkS = k*G and of the evaluated elements d[i] =
+// k*c[i] are equal, as GenerateProof of RFC 9497 section 2.2.1.
+func (s *suite) generateProof(k *ristretto.Scalar, pkS *ristretto.Point, c, d []*ristretto.Point,
+	r *ristretto.Scalar) []byte {
+	m := s.composite(pkS, c, d, nil)
+	z := new(ristretto.Point).ScalarMult(m, k)
+
+	t2 := new(ristretto.Point).ScalarMultBase(r)
+	t3 := new(ristretto.Point).ScalarMult(m, r)
+
+	// c = HashToScalar(challenge transcript), s = r - c*k
+	challenge := s.challenge(pkS, m, z, t2, t3)
+	proofS := new(ristretto.Scalar).MulSub(challenge, k, r)
+	proofS.Neg(proofS)
+
+	return append(challenge.Bytes(), proofS.Bytes()...)
+}
+
+// verifyProof verifies the proof that the discrete logarithms of pkS and of the evaluated elements d[i] in base c[i]
+// are equal, as VerifyProof of RFC 9497 section 2.2.2.
+func (s *suite) verifyProof(pkS *ristretto.Point, c, d []*ristretto.Point, proof []byte) bool {
+	if len(proof) != ProofSize {
+		return false
+	}
+
+	challenge, err := ristretto255.DeserializeScalar(proof[:ScalarSize])
+	if err != nil {
+		return false
+	}
+
+	proofS, err := ristretto255.DeserializeScalar(proof[ScalarSize:])
+	if err != nil {
+		return false
+	}
+
+	z := new(ristretto.Point)
+	m := s.composite(pkS, c, d, z)
+
+	var sA, cB, sM, cZ ristretto.Point
+
+	t2 := new(ristretto.Point).Add(sA.PublicScalarMultBase(proofS), cB.PublicScalarMult(pkS, challenge))
+	t3 := new(ristretto.Point).Add(sM.PublicScalarMult(m, proofS), cZ.PublicScalarMult(z, challenge))
+
+	return subtle.ConstantTimeCompare(s.challenge(pkS, m, z, t2, t3).Bytes(), challenge.Bytes()) == 1
+}
+
+// composite returns the composite element M = sum(d_i*c[i]) of ComputeComposites of RFC 9497, and sets z to the
+// composite of the evaluated elements sum(d_i*d[i]) if z isn't nil: the server computes Z as k*M instead.
+func (s *suite) composite(pkS *ristretto.Point, c, d []*ristretto.Point, z *ristretto.Point) *ristretto.Point {
+	seedDST := []byte("Seed-" + s.contextString)
+	seed := sha512.Sum512(ristretto255.LengthPrefixed(pkS.Bytes(), seedDST))
+
+	m := new(ristretto.Point).SetZero()
+
+	if z != nil {
+		z.SetZero()
+	}
+
+	for i := range c {
+		ci, di := c[i].Bytes(), d[i].Bytes()
+
+		transcript := ristretto255.LengthPrefixed(seed[:])
+		transcript = append(transcript, byte(i>>8), byte(i))
+		transcript = append(transcript, ristretto255.LengthPrefixed(ci, di)...)
+		transcript = append(transcript, "Composite"...)
+
+		w := ristretto255.HashToScalar(transcript, s.hashToScalarDST)
+
+		var wc ristretto.Point
+
+		m.Add(m, wc.PublicScalarMult(c[i], w))
+
+		if z != nil {
+			var wd ristretto.Point
+
+			z.Add(z, wd.PublicScalarMult(d[i], w))
+		}
+	}
+
+	return m
+}
+
+func (s *suite) challenge(pkS, m, z, t2, t3 *ristretto.Point) *ristretto.Scalar {
+	transcript := ristretto255.LengthPrefixed(pkS.Bytes(), m.Bytes(), z.Bytes(), t2.Bytes(), t3.Bytes())
+
+	return ristretto255.HashToScalar(append(transcript, "Challenge"...), s.hashToScalarDST)
+}
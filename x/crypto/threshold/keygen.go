@@ -0,0 +1,416 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package threshold
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/trustbloc/kms-go/x/crypto/internal/mpc"
+)
+
+const (
+	domainKeyGen = "threshold-keygen"
+	domainRange  = "threshold-range"
+
+	// pointSize is the size of an uncompressed P-256 point.
+	pointSize = 65
+)
+
+// KeyShare is the share of a threshold key held by one party.
+type KeyShare struct {
+	KeyID string `json:"keyID"`
+	// Index is the party index, from 1 to len(PublicShares).
+	Index int `json:"index"`
+	// X is the secret share of the party: f(Index) for the degree 1 polynomial f with f(0) the private key.
+	X *big.Int `json:"x"`
+	// PublicKey is the joint public key f(0)*G.
+	PublicKey []byte `json:"publicKey"`
+	// PublicShares are the public shares f(i)*G of all the parties, PublicShares[i-1] is the share of party i.
+	PublicShares [][]byte `json:"publicShares"`
+}
+
+// KeyGenParty is a party of a distributed key generation. Party implements it in-process, a remote party implements
+// it over its own transport.
+type KeyGenParty interface {
+	// KeyGenRound1 starts the generation of keyID as the party of index index out of n parties.
+	KeyGenRound1(keyID string, index, n int) (*KeyGenRound1Message, error)
+	// KeyGenRound2 checks the round 1 messages of all the parties and returns the shares of the other parties.
+	KeyGenRound2(msgs []*KeyGenRound1Message) (*KeyGenRound2Message, error)
+	// KeyGenFinish checks the round 2 messages of all the parties, stores the key share of the party and returns the
+	// joint public key.
+	KeyGenFinish(msgs []*KeyGenRound2Message) (*ecdsa.PublicKey, error)
+}
+
+// keyGenSession is the state of a key generation on a party.
+type keyGenSession struct {
+	index         int
+	n             int
+	coefficients  [2]*big.Int
+	points        [][]byte
+	proof         *Proof
+	salt          []byte
+	sk            *mpc.PaillierPrivateKey
+	paillierProof *PaillierProof
+	commitments   [][]byte
+	expires       time.Time
+}
+
+// KeyGen generates a new P-256 key shared by parties without a dealer: parties[i] is the party of index i+1 and any 2
+// of them can sign. It returns the key ID and the joint public key.
+func KeyGen(parties ...KeyGenParty) (string, *ecdsa.PublicKey, error) {
+	if len(parties) < 2 {
+		return "", nil, errors.New("threshold: keygen: at least 2 parties are required")
+	}
+
+	keyID, err := mpc.RandomID()
+	if err != nil {
+		return "", nil, fmt.Errorf("threshold: keygen: %w", err)
+	}
+
+	msgs1 := make([]*KeyGenRound1Message, len(parties))
+
+	for i, p := range parties {
+		if msgs1[i], err = p.KeyGenRound1(keyID, i+1, len(parties)); err != nil {
+			return "", nil, fmt.Errorf("threshold: keygen: party %d round 1: %w", i+1, err)
+		}
+	}
+
+	msgs2 := make([]*KeyGenRound2Message, len(parties))
+
+	for i, p := range parties {
+		if msgs2[i], err = p.KeyGenRound2(msgs1); err != nil {
+			return "", nil, fmt.Errorf("threshold: keygen: party %d round 2: %w", i+1, err)
+		}
+	}
+
+	var pub *ecdsa.PublicKey
+
+	for i, p := range parties {
+		pk, e := p.KeyGenFinish(msgs2)
+		if e != nil {
+			return "", nil, fmt.Errorf("threshold: keygen: party %d finish: %w", i+1, e)
+		}
+
+		if pub != nil && !pub.Equal(pk) {
+			return "", nil, fmt.Errorf("threshold: keygen: party %d public key mismatch", i+1)
+		}
+
+		pub = pk
+	}
+
+	return keyID, pub, nil
+}
+
+// KeyGenRound1 starts the generation of keyID as the party of index index out of n parties: it creates the degree 1
+// polynomial of its contribution to the key and a Paillier key, and commits to the polynomial.
+func (p *Party) KeyGenRound1(keyID string, index, n int) (*KeyGenRound1Message, error) {
+	if keyID == "" || n < 2 || index < 1 || index > n {
+		return nil, errors.New("threshold: keygen round 1: invalid request")
+	}
+
+	if _, err := p.store.Get(keyID); err == nil {
+		return nil, fmt.Errorf("threshold: keygen round 1: key '%s' already exists", keyID)
+	}
+
+	sess := &keyGenSession{index: index, n: n}
+
+	if err := sess.init(); err != nil {
+		return nil, fmt.Errorf("threshold: keygen round 1: %w", err)
+	}
+
+	com, salt, err := mpc.Commit(domainKeyGen, concat(sess.points), sess.proof)
+	if err != nil {
+		return nil, fmt.Errorf("threshold: keygen round 1: %w", err)
+	}
+
+	sess.salt = salt
+	now := time.Now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.expire(now)
+
+	if _, ok := p.keyGens[keyID]; ok {
+		return nil, fmt.Errorf("threshold: keygen round 1: session '%s' already exists", keyID)
+	}
+
+	sess.expires = now.Add(SessionTimeout)
+	p.keyGens[keyID] = sess
+
+	return &KeyGenRound1Message{
+		KeyID: keyID, Index: index, Commitment: com, PaillierN: sess.sk.N.Bytes(), PaillierProof: sess.paillierProof,
+	}, nil
+}
+
+// init creates the polynomial f(i) = u + a*i of the session with the points u*G and a*G, the proof of knowledge of u
+// and the Paillier key of the party.
+func (s *keyGenSession) init() error {
+	c := mpc.Curve()
+
+	for i := range s.coefficients {
+		k, err := mpc.RandomScalar()
+		if err != nil {
+			return err
+		}
+
+		s.coefficients[i] = k
+		s.points = append(s.points, mpc.MarshalPoint(c.ScalarBaseMult(k.Bytes())))
+	}
+
+	proof, err := mpc.Prove(domainKeyGen, s.coefficients[0])
+	if err != nil {
+		return err
+	}
+
+	sk, err := mpc.GeneratePaillierKey()
+	if err != nil {
+		return err
+	}
+
+	paillierProof, err := mpc.ProvePaillier(sk)
+	if err != nil {
+		return err
+	}
+
+	s.proof, s.sk, s.paillierProof = proof, sk, paillierProof
+
+	return nil
+}
+
+// eval returns f(i).
+func (s *keyGenSession) eval(i int) *big.Int {
+	v := new(big.Int).Mul(s.coefficients[1], big.NewInt(int64(i)))
+	v.Add(v, s.coefficients[0])
+
+	return v.Mod(v, mpc.Curve().Params().N)
+}
+
+// KeyGenRound2 checks the Paillier moduli of the other parties and opens the commitment of the party, with the
+// shares f(j) of the other parties encrypted with their Paillier keys.
+func (p *Party) KeyGenRound2(msgs []*KeyGenRound1Message) (*KeyGenRound2Message, error) {
+	if len(msgs) == 0 || msgs[0] == nil {
+		return nil, errors.New("threshold: keygen round 2: invalid request")
+	}
+
+	keyID := msgs[0].KeyID
+
+	// a pending session is put back once the messages are checked, it is dropped if they are invalid.
+	p.mu.Lock()
+	sess, ok := p.keyGens[keyID]
+	ok = ok && sess.commitments == nil && !time.Now().After(sess.expires)
+
+	if ok {
+		delete(p.keyGens, keyID)
+	}
+	p.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("threshold: keygen round 2: unknown session '%s'", keyID)
+	}
+
+	if len(msgs) != sess.n {
+		return nil, fmt.Errorf("threshold: keygen round 2: %d messages for %d parties", len(msgs), sess.n)
+	}
+
+	commitments := make([][]byte, sess.n)
+	shares := make([][]byte, sess.n)
+
+	for i, msg := range msgs {
+		if msg == nil || msg.KeyID != keyID || msg.Index != i+1 || len(msg.Commitment) == 0 {
+			return nil, fmt.Errorf("threshold: keygen round 2: invalid message of party %d", i+1)
+		}
+
+		commitments[i] = msg.Commitment
+
+		if msg.Index == sess.index {
+			continue
+		}
+
+		n := new(big.Int).SetBytes(msg.PaillierN)
+		if err := msg.PaillierProof.Verify(n); err != nil {
+			return nil, fmt.Errorf("threshold: keygen round 2: party %d paillier modulus: %w", msg.Index, err)
+		}
+
+		encShare, err := mpc.NewPaillierPublicKey(n).Encrypt(sess.eval(msg.Index))
+		if err != nil {
+			return nil, fmt.Errorf("threshold: keygen round 2: %w", err)
+		}
+
+		shares[i] = encShare.Bytes()
+	}
+
+	sess.commitments = commitments
+
+	p.mu.Lock()
+	p.keyGens[keyID] = sess
+	p.mu.Unlock()
+
+	return &KeyGenRound2Message{
+		KeyID: keyID, Index: sess.index, Points: sess.points, Proof: sess.proof, Salt: sess.salt,
+		EncryptedShares: shares,
+	}, nil
+}
+
+// KeyGenFinish checks the other parties opened their commitments and sent shares consistent with them, and stores
+// the key share of the party: the sum of the shares f(index) of all the parties, with its encryption and the proofs
+// the cosigners verify when the party signs.
+func (p *Party) KeyGenFinish(msgs []*KeyGenRound2Message) (*ecdsa.PublicKey, error) {
+	if len(msgs) == 0 || msgs[0] == nil {
+		return nil, errors.New("threshold: keygen finish: invalid request")
+	}
+
+	keyID := msgs[0].KeyID
+
+	p.mu.Lock()
+	sess, ok := p.keyGens[keyID]
+	delete(p.keyGens, keyID)
+	p.mu.Unlock()
+
+	if !ok || sess.commitments == nil || time.Now().After(sess.expires) {
+		return nil, fmt.Errorf("threshold: keygen finish: unknown session '%s'", keyID)
+	}
+
+	if len(msgs) != sess.n {
+		return nil, fmt.Errorf("threshold: keygen finish: %d messages for %d parties", len(msgs), sess.n)
+	}
+
+	share, err := sess.combine(keyID, msgs)
+	if err != nil {
+		return nil, fmt.Errorf("threshold: keygen finish: %w", err)
+	}
+
+	if err = putShare(p.store, p.opts, share); err != nil {
+		return nil, fmt.Errorf("threshold: keygen finish: %w", err)
+	}
+
+	return toECDSA(share.PublicKey)
+}
+
+// combine checks the round 2 messages and returns the key share of the party.
+func (s *keyGenSession) combine(keyID string, msgs []*KeyGenRound2Message) (*partyShare, error) {
+	c := mpc.Curve()
+	x := s.eval(s.index)
+	// sums of the points of the polynomials of all the parties, they are the points of the joint polynomial.
+	c0X, c0Y, _ := mpc.UnmarshalPoint(s.points[0]) //nolint:errcheck // own point
+	c1X, c1Y, _ := mpc.UnmarshalPoint(s.points[1]) //nolint:errcheck // own point
+
+	for i, msg := range msgs {
+		if msg == nil || msg.KeyID != keyID || msg.Index != i+1 {
+			return nil, fmt.Errorf("invalid message of party %d", i+1)
+		}
+
+		if msg.Index == s.index {
+			continue
+		}
+
+		f, err := s.checkShare(msg)
+		if err != nil {
+			return nil, fmt.Errorf("party %d: %w", msg.Index, err)
+		}
+
+		x.Add(x, f)
+
+		pX, pY, _ := mpc.UnmarshalPoint(msg.Points[0]) //nolint:errcheck // checked by checkShare
+		c0X, c0Y = c.Add(c0X, c0Y, pX, pY)
+
+		pX, pY, _ = mpc.UnmarshalPoint(msg.Points[1]) //nolint:errcheck // checked by checkShare
+		c1X, c1Y = c.Add(c1X, c1Y, pX, pY)
+	}
+
+	x.Mod(x, c.Params().N)
+
+	publicShares := make([][]byte, s.n)
+
+	for i := range publicShares {
+		kX, kY := c.ScalarMult(c1X, c1Y, big.NewInt(int64(i+1)).Bytes())
+		publicShares[i] = mpc.MarshalPoint(c.Add(c0X, c0Y, kX, kY))
+	}
+
+	encX, rangeProof, err := mpc.EncryptInRange(domainRange, s.sk, x)
+	if err != nil {
+		return nil, err
+	}
+
+	return &partyShare{
+		KeyShare: KeyShare{
+			KeyID: keyID, Index: s.index, X: x, PublicKey: mpc.MarshalPoint(c0X, c0Y), PublicShares: publicShares,
+		},
+		N: s.sk.N, Lambda: s.sk.Lambda, Mu: s.sk.Mu, PaillierProof: s.paillierProof, EncryptedX: encX,
+		RangeProof: rangeProof,
+	}, nil
+}
+
+// checkShare checks msg opens the round 1 commitment of its party and returns the share f(index) it sent, checked
+// against the points of the polynomial f: f(index)*G = u*G + index*(a*G).
+func (s *keyGenSession) checkShare(msg *KeyGenRound2Message) (*big.Int, error) {
+	if len(msg.Points) != len(s.points) || len(msg.EncryptedShares) != s.n {
+		return nil, errors.New("invalid round 2 message")
+	}
+
+	err := mpc.CheckCommitment(domainKeyGen, s.commitments[msg.Index-1], concat(msg.Points), msg.Proof, msg.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = msg.Proof.Verify(domainKeyGen, msg.Points[0]); err != nil {
+		return nil, err
+	}
+
+	c := mpc.Curve()
+
+	c1X, c1Y, err := mpc.UnmarshalPoint(msg.Points[1])
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := s.sk.Decrypt(new(big.Int).SetBytes(msg.EncryptedShares[s.index-1]))
+	if err != nil {
+		return nil, err
+	}
+
+	if f.Cmp(c.Params().N) >= 0 {
+		return nil, errors.New("share is not a scalar")
+	}
+
+	c0X, c0Y, _ := mpc.UnmarshalPoint(msg.Points[0]) //nolint:errcheck // checked by verify
+	kX, kY := c.ScalarMult(c1X, c1Y, big.NewInt(int64(s.index)).Bytes())
+	eX, eY := c.Add(c0X, c0Y, kX, kY)
+	fX, fY := c.ScalarBaseMult(f.Bytes())
+
+	if fX.Cmp(eX) != 0 || fY.Cmp(eY) != 0 {
+		return nil, errors.New("share does not match its polynomial")
+	}
+
+	return f, nil
+}
+
+// concat returns the concatenation of the points, each of pointSize bytes.
+func concat(points [][]byte) []byte {
+	b := make([]byte, 0, len(points)*pointSize)
+
+	for _, p := range points {
+		b = append(b, p...)
+	}
+
+	return b
+}
+
+// lagrange returns the Lagrange coefficient at 0 of the party i for the pair of parties i and j: j/(j-i) mod q.
+func lagrange(i, j int) *big.Int {
+	q := mpc.Curve().Params().N
+
+	d := new(big.Int).Mod(big.NewInt(int64(j-i)), q)
+	l := new(big.Int).ModInverse(d, q)
+	l.Mul(l, big.NewInt(int64(j)))
+
+	return l.Mod(l, q)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package threshold
+
+import "github.com/trustbloc/kms-go/x/crypto/internal/mpc"
+
+// Proof is a non-interactive Schnorr proof of knowledge of the discrete logarithm of a curve point.
+type Proof = mpc.Proof
+
+// PaillierProof is a non-interactive proof that a Paillier modulus is well formed.
+type PaillierProof = mpc.PaillierProof
+
+// RangeProof is a non-interactive proof that a Paillier ciphertext encrypts the discrete logarithm of a curve point,
+// in range.
+type RangeProof = mpc.RangeProof
+
+// KeyGenRound1Message holds the commitment of a party to the points of its polynomial and its Paillier modulus.
+type KeyGenRound1Message struct {
+	KeyID         string         `json:"keyID"`
+	Index         int            `json:"index"`
+	Commitment    []byte         `json:"commitment"`
+	PaillierN     []byte         `json:"paillierN"`
+	PaillierProof *PaillierProof `json:"paillierProof"`
+}
+
+// KeyGenRound2Message opens the commitment of a party to the points u*G and a*G of its polynomial f(i) = u + a*i,
+// with the proof of knowledge of u, and holds the shares f(j) of the parties. EncryptedShares[j-1] is encrypted with
+// the Paillier key of the party j, the entry of the party itself is empty.
+type KeyGenRound2Message struct {
+	KeyID           string   `json:"keyID"`
+	Index           int      `json:"index"`
+	Points          [][]byte `json:"points"`
+	Proof           *Proof   `json:"proof"`
+	Salt            []byte   `json:"salt"`
+	EncryptedShares [][]byte `json:"encryptedShares"`
+}
+
+// Cosigner is the party a signer runs the signing rounds with. Party implements it in-process, a remote cosigner
+// implements it over its own transport.
+type Cosigner interface {
+	// SignRound2 starts the cosigner side of a signing session and returns its nonce point.
+	SignRound2(msg *SignRound1Message) (*SignRound2Message, error)
+	// SignRound4 completes the cosigner side of a signing session and returns its encrypted partial signature.
+	SignRound4(msg *SignRound3Message) (*SignRound4Message, error)
+}
+
+// SignRound1Message holds the signer commitment to its nonce point and the Paillier encryption of its key share,
+// with the proofs that the Paillier modulus is well formed and that the encrypted share is the discrete logarithm of
+// the signer public share.
+type SignRound1Message struct {
+	KeyID          string         `json:"keyID"`
+	SessionID      string         `json:"sessionID"`
+	Signer         int            `json:"signer"`
+	Cosigner       int            `json:"cosigner"`
+	Digest         []byte         `json:"digest"`
+	Commitment     []byte         `json:"commitment"`
+	PaillierN      []byte         `json:"paillierN"`
+	PaillierProof  *PaillierProof `json:"paillierProof"`
+	EncryptedShare []byte         `json:"encryptedShare"`
+	RangeProof     *RangeProof    `json:"rangeProof"`
+}
+
+// SignRound2Message holds the cosigner nonce point and its proof.
+type SignRound2Message struct {
+	SessionID string `json:"sessionID"`
+	R2        []byte `json:"r2"`
+	Proof     *Proof `json:"proof"`
+}
+
+// SignRound3Message opens the signer nonce commitment.
+type SignRound3Message struct {
+	SessionID string `json:"sessionID"`
+	R1        []byte `json:"r1"`
+	Proof     *Proof `json:"proof"`
+	Salt      []byte `json:"salt"`
+}
+
+// SignRound4Message holds the cosigner encrypted partial signature.
+type SignRound4Message struct {
+	SessionID  string `json:"sessionID"`
+	EncryptedS []byte `json:"encryptedS"`
+}
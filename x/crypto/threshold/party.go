@@ -0,0 +1,458 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package threshold implements 2-of-n threshold ECDSA signing on P-256: n parties generate a key together with KeyGen,
+// each holding a share of it, and any two of them sign together, without the private key ever being assembled.
+// Typically the LocalKMS store holds one share and a remote cosigner another one.
+//
+// The key generation is a Feldman verifiable secret sharing run by every party, in the style of GG20: each party
+// commits to the points of a random degree 1 polynomial and publishes its Paillier modulus with the proof that it is
+// well formed (KeyGenRound1), then opens its commitment and sends the share of every other party encrypted with its
+// Paillier key (KeyGenRound2). Each party checks the shares it receives against the points of their polynomials and
+// stores the sum of its shares (KeyGenFinish). KeyGen runs all the rounds with the KeyGenParty of every party.
+//
+// A signature is created in rounds between a signer and a cosigner, following the two-party protocol of
+// x/crypto/ecdsa2p with the Lagrange-weighted shares of the pair as additive shares of the key: the signer sends the
+// Paillier encryption of its share with the proofs that its Paillier modulus is well formed and that the ciphertext
+// encrypts its share in range, and a commitment to its nonce point (SignRound1), the cosigner verifies the proofs and
+// returns its nonce point (SignRound2), the signer opens its commitment (SignRound3), the cosigner returns its
+// encrypted partial signature (SignRound4) and the signer completes and verifies the signature (SignFinish).
+// Party.Sign runs all the rounds with a Cosigner.
+package threshold
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/x"
+	"github.com/trustbloc/kms-go/x/crypto/internal/mpc"
+)
+
+const (
+	domainSignerNonce   = "threshold-nonce-signer"
+	domainCosignerNonce = "threshold-nonce-cosigner"
+)
+
+// SessionTimeout is how long a pending key generation or signing session is kept by a Party.
+const SessionTimeout = 5 * time.Minute
+
+// signerSession is the state of a signing session on the signer side.
+type signerSession struct {
+	keyID   string
+	k       *big.Int
+	r1      []byte
+	proof   *Proof
+	salt    []byte
+	r2      []byte
+	digest  []byte
+	expires time.Time
+}
+
+// cosignerSession is the state of a signing session on the cosigner side.
+type cosignerSession struct {
+	keyID          string
+	signer         int
+	k              *big.Int
+	digest         []byte
+	commitment     []byte
+	n              *big.Int
+	encryptedShare *big.Int
+	expires        time.Time
+}
+
+// Party holds threshold key shares in a kms.Store (usually the LocalKMS store). It takes part in key generations and is
+// the signer or the cosigner of signing sessions, pending sessions are held in memory until they complete or expire.
+type Party struct {
+	store kms.Store
+	opts  *opts
+
+	mu        sync.Mutex
+	keyGens   map[string]*keyGenSession
+	signers   map[string]*signerSession
+	cosigners map[string]*cosignerSession
+}
+
+var (
+	_ KeyGenParty = (*Party)(nil)
+	_ Cosigner    = (*Party)(nil)
+)
+
+// NewParty creates a party storing its key shares in store, encrypted with the secret lock set WithSecretLock.
+func NewParty(store kms.Store, options ...Opt) (*Party, error) {
+	if err := x.Require("crypto/threshold"); err != nil {
+		return nil, fmt.Errorf("threshold: new party: %w", err)
+	}
+
+	o := newOpts(options)
+	if o.secretLock == nil {
+		return nil, fmt.Errorf("threshold: new party: %w", ErrSecretLockRequired)
+	}
+
+	return &Party{
+		store:     store,
+		opts:      o,
+		keyGens:   map[string]*keyGenSession{},
+		signers:   map[string]*signerSession{},
+		cosigners: map[string]*cosignerSession{},
+	}, nil
+}
+
+// PublicKey returns the joint public key of keyID.
+func (p *Party) PublicKey(keyID string) (*ecdsa.PublicKey, error) {
+	share, err := getShare(p.store, p.opts, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("threshold: %w", err)
+	}
+
+	return toECDSA(share.PublicKey)
+}
+
+// Sign signs the SHA-256 digest of msg with keyID jointly with cosigner, the party of index cosignerIndex, and
+// returns an ASN.1 DER ECDSA signature, as created by kms.ECDSAP256TypeDER keys.
+func (p *Party) Sign(keyID string, cosigner Cosigner, cosignerIndex int, msg []byte) ([]byte, error) {
+	digest := sha256.Sum256(msg)
+
+	msg1, err := p.SignRound1(keyID, cosignerIndex, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	msg2, err := cosigner.SignRound2(msg1)
+	if err != nil {
+		return nil, fmt.Errorf("threshold: sign: cosigner round 2: %w", err)
+	}
+
+	msg3, err := p.SignRound3(msg2)
+	if err != nil {
+		return nil, err
+	}
+
+	msg4, err := cosigner.SignRound4(msg3)
+	if err != nil {
+		return nil, fmt.Errorf("threshold: sign: cosigner round 4: %w", err)
+	}
+
+	return p.SignFinish(msg4)
+}
+
+// SignRound1 starts a signing session of digest with keyID as the signer, with the party of index cosigner.
+func (p *Party) SignRound1(keyID string, cosigner int, digest []byte) (*SignRound1Message, error) { //nolint:funlen
+	if len(digest) != sha256.Size {
+		return nil, errors.New("threshold: sign round 1: invalid digest")
+	}
+
+	share, err := getShare(p.store, p.opts, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("threshold: sign round 1: %w", err)
+	}
+
+	if cosigner == share.Index || cosigner < 1 || cosigner > len(share.PublicShares) {
+		return nil, fmt.Errorf("threshold: sign round 1: invalid cosigner %d", cosigner)
+	}
+
+	k1, err := mpc.RandomScalar()
+	if err != nil {
+		return nil, fmt.Errorf("threshold: sign round 1: %w", err)
+	}
+
+	r1 := mpc.MarshalPoint(mpc.Curve().ScalarBaseMult(k1.Bytes()))
+
+	proof, err := mpc.Prove(domainSignerNonce, k1)
+	if err != nil {
+		return nil, fmt.Errorf("threshold: sign round 1: %w", err)
+	}
+
+	com, salt, err := mpc.Commit(domainSignerNonce, r1, proof)
+	if err != nil {
+		return nil, fmt.Errorf("threshold: sign round 1: %w", err)
+	}
+
+	sessionID, err := mpc.RandomID()
+	if err != nil {
+		return nil, fmt.Errorf("threshold: sign round 1: %w", err)
+	}
+
+	now := time.Now()
+
+	p.mu.Lock()
+	p.expire(now)
+	p.signers[sessionID] = &signerSession{
+		keyID: keyID, k: k1, r1: r1, proof: proof, salt: salt, digest: digest, expires: now.Add(SessionTimeout),
+	}
+	p.mu.Unlock()
+
+	return &SignRound1Message{
+		KeyID: keyID, SessionID: sessionID, Signer: share.Index, Cosigner: cosigner, Digest: digest,
+		Commitment: com, PaillierN: share.N.Bytes(), PaillierProof: share.PaillierProof,
+		EncryptedShare: share.EncryptedX.Bytes(), RangeProof: share.RangeProof,
+	}, nil
+}
+
+// SignRound2 checks the proofs of the signer Paillier modulus and encrypted share, starts the cosigner side of the
+// signing session of msg and returns the cosigner nonce point.
+func (p *Party) SignRound2(msg *SignRound1Message) (*SignRound2Message, error) { //nolint:funlen
+	if len(msg.Digest) != sha256.Size || len(msg.Commitment) != sha256.Size || msg.SessionID == "" {
+		return nil, errors.New("threshold: sign round 2: invalid request")
+	}
+
+	share, err := getShare(p.store, p.opts, msg.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("threshold: sign round 2: %w", err)
+	}
+
+	if msg.Cosigner != share.Index || msg.Signer == share.Index || msg.Signer < 1 ||
+		msg.Signer > len(share.PublicShares) {
+		return nil, errors.New("threshold: sign round 2: invalid signer or cosigner")
+	}
+
+	n := new(big.Int).SetBytes(msg.PaillierN)
+	if err = msg.PaillierProof.Verify(n); err != nil {
+		return nil, fmt.Errorf("threshold: sign round 2: signer paillier modulus: %w", err)
+	}
+
+	encX := new(big.Int).SetBytes(msg.EncryptedShare)
+
+	err = msg.RangeProof.Verify(domainRange, n, encX, share.PublicShares[msg.Signer-1])
+	if err != nil {
+		return nil, fmt.Errorf("threshold: sign round 2: signer encrypted share: %w", err)
+	}
+
+	k2, err := mpc.RandomScalar()
+	if err != nil {
+		return nil, fmt.Errorf("threshold: sign round 2: %w", err)
+	}
+
+	r2 := mpc.MarshalPoint(mpc.Curve().ScalarBaseMult(k2.Bytes()))
+
+	proof, err := mpc.Prove(domainCosignerNonce, k2)
+	if err != nil {
+		return nil, fmt.Errorf("threshold: sign round 2: %w", err)
+	}
+
+	now := time.Now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.expire(now)
+
+	if _, ok := p.cosigners[msg.SessionID]; ok {
+		return nil, fmt.Errorf("threshold: sign round 2: session '%s' already exists", msg.SessionID)
+	}
+
+	p.cosigners[msg.SessionID] = &cosignerSession{
+		keyID: msg.KeyID, signer: msg.Signer, k: k2, digest: msg.Digest, commitment: msg.Commitment, n: n,
+		encryptedShare: encX, expires: now.Add(SessionTimeout),
+	}
+
+	return &SignRound2Message{SessionID: msg.SessionID, R2: r2, Proof: proof}, nil
+}
+
+// SignRound3 checks the cosigner nonce point and opens the signer nonce commitment.
+func (p *Party) SignRound3(msg *SignRound2Message) (*SignRound3Message, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sess, ok := p.signers[msg.SessionID]
+	if !ok || sess.r2 != nil || time.Now().After(sess.expires) {
+		return nil, fmt.Errorf("threshold: sign round 3: unknown session '%s'", msg.SessionID)
+	}
+
+	if err := msg.Proof.Verify(domainCosignerNonce, msg.R2); err != nil {
+		delete(p.signers, msg.SessionID)
+
+		return nil, fmt.Errorf("threshold: sign round 3: cosigner nonce: %w", err)
+	}
+
+	sess.r2 = msg.R2
+
+	return &SignRound3Message{SessionID: msg.SessionID, R1: sess.r1, Proof: sess.proof, Salt: sess.salt}, nil
+}
+
+// SignRound4 checks the signer opened its nonce commitment and returns the encrypted partial signature
+// Enc(rho*q + k2^-1*(m + r*a2)) + Enc(x1)*(k2^-1*r*l1), with a2 the additive share of the cosigner and l1 the
+// Lagrange coefficient of the signer share x1, a1 = l1*x1. A session can only be finished once.
+func (p *Party) SignRound4(msg *SignRound3Message) (*SignRound4Message, error) {
+	p.mu.Lock()
+	sess, ok := p.cosigners[msg.SessionID]
+	delete(p.cosigners, msg.SessionID)
+	p.mu.Unlock()
+
+	if !ok || time.Now().After(sess.expires) {
+		return nil, fmt.Errorf("threshold: sign round 4: unknown session '%s'", msg.SessionID)
+	}
+
+	if err := mpc.CheckCommitment(domainSignerNonce, sess.commitment, msg.R1, msg.Proof, msg.Salt); err != nil {
+		return nil, fmt.Errorf("threshold: sign round 4: %w", err)
+	}
+
+	if err := msg.Proof.Verify(domainSignerNonce, msg.R1); err != nil {
+		return nil, fmt.Errorf("threshold: sign round 4: signer nonce: %w", err)
+	}
+
+	share, err := getShare(p.store, p.opts, sess.keyID)
+	if err != nil {
+		return nil, fmt.Errorf("threshold: sign round 4: %w", err)
+	}
+
+	encS, err := partialSignature(share, sess, msg.R1)
+	if err != nil {
+		return nil, fmt.Errorf("threshold: sign round 4: %w", err)
+	}
+
+	return &SignRound4Message{SessionID: msg.SessionID, EncryptedS: encS.Bytes()}, nil
+}
+
+// SignFinish decrypts the cosigner partial signature, completes the signature and verifies it with the joint public
+// key. It returns an ASN.1 DER ECDSA signature.
+func (p *Party) SignFinish(msg *SignRound4Message) ([]byte, error) {
+	p.mu.Lock()
+	sess, ok := p.signers[msg.SessionID]
+	delete(p.signers, msg.SessionID)
+	p.mu.Unlock()
+
+	if !ok || sess.r2 == nil || time.Now().After(sess.expires) {
+		return nil, fmt.Errorf("threshold: sign finish: unknown session '%s'", msg.SessionID)
+	}
+
+	share, err := getShare(p.store, p.opts, sess.keyID)
+	if err != nil {
+		return nil, fmt.Errorf("threshold: sign finish: %w", err)
+	}
+
+	c := mpc.Curve()
+	q := c.Params().N
+
+	r2X, r2Y, _ := mpc.UnmarshalPoint(sess.r2) //nolint:errcheck // checked by verify
+	rX, _ := c.ScalarMult(r2X, r2Y, sess.k.Bytes())
+	r := new(big.Int).Mod(rX, q)
+
+	sk := &mpc.PaillierPrivateKey{
+		PaillierPublicKey: *mpc.NewPaillierPublicKey(share.N), Lambda: share.Lambda, Mu: share.Mu,
+	}
+
+	sPrime, err := sk.Decrypt(new(big.Int).SetBytes(msg.EncryptedS))
+	if err != nil {
+		return nil, fmt.Errorf("threshold: sign finish: %w", err)
+	}
+
+	s := new(big.Int).Mul(sPrime, new(big.Int).ModInverse(sess.k, q))
+	s.Mod(s, q)
+
+	// use the low-s form of the signature.
+	if s.Cmp(new(big.Int).Rsh(q, 1)) > 0 {
+		s.Sub(q, s)
+	}
+
+	pub, err := toECDSA(share.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("threshold: sign finish: %w", err)
+	}
+
+	if r.Sign() == 0 || s.Sign() == 0 || !ecdsa.Verify(pub, sess.digest, r, s) {
+		return nil, errors.New("threshold: sign finish: invalid joint signature")
+	}
+
+	return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+}
+
+// Verify verifies the ASN.1 DER ECDSA signature sig of the SHA-256 digest of msg with the joint public key pub, as
+// returned by Party.PublicKey.
+func Verify(pub *ecdsa.PublicKey, msg, sig []byte) error {
+	if pub == nil {
+		return errors.New("threshold: verify: public key is required")
+	}
+
+	digest := sha256.Sum256(msg)
+
+	if !ecdsa.VerifyASN1(pub, digest[:], sig) {
+		return errors.New("threshold: verify: invalid signature")
+	}
+
+	return nil
+}
+
+// expire drops the expired sessions, it must be called with p.mu held.
+func (p *Party) expire(now time.Time) {
+	for id, sess := range p.keyGens {
+		if now.After(sess.expires) {
+			delete(p.keyGens, id)
+		}
+	}
+
+	for id, sess := range p.signers {
+		if now.After(sess.expires) {
+			delete(p.signers, id)
+		}
+	}
+
+	for id, sess := range p.cosigners {
+		if now.After(sess.expires) {
+			delete(p.cosigners, id)
+		}
+	}
+}
+
+func partialSignature(share *partyShare, sess *cosignerSession, r1 []byte) (*big.Int, error) {
+	c := mpc.Curve()
+	q := c.Params().N
+
+	r1X, r1Y, err := mpc.UnmarshalPoint(r1)
+	if err != nil {
+		return nil, err
+	}
+
+	rX, _ := c.ScalarMult(r1X, r1Y, sess.k.Bytes())
+	r := new(big.Int).Mod(rX, q)
+	m := new(big.Int).SetBytes(sess.digest)
+	kInv := new(big.Int).ModInverse(sess.k, q)
+
+	rho, err := rand.Int(rand.Reader, new(big.Int).Mul(q, q))
+	if err != nil {
+		return nil, err
+	}
+
+	// the cosigner additive share of the key for this pair of parties.
+	a2 := new(big.Int).Mul(lagrange(share.Index, sess.signer), share.X)
+
+	// plaintext of c1: rho*q + k2^-1*(m + r*a2) mod q
+	pt := new(big.Int).Mul(r, a2)
+	pt.Add(pt, m)
+	pt.Mul(pt, kInv)
+	pt.Mod(pt, q)
+	pt.Add(pt, new(big.Int).Mul(rho, q))
+
+	pk := mpc.NewPaillierPublicKey(sess.n)
+
+	c1, err := pk.Encrypt(pt)
+	if err != nil {
+		return nil, err
+	}
+
+	// the encrypted share of the signer is weighted by its Lagrange coefficient.
+	v := new(big.Int).Mul(kInv, r)
+	v.Mul(v, lagrange(sess.signer, share.Index))
+	v.Mod(v, q)
+
+	return pk.Add(c1, pk.Mul(sess.encryptedShare, v)), nil
+}
+
+func toECDSA(pub []byte) (*ecdsa.PublicKey, error) {
+	x, y, err := mpc.UnmarshalPoint(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ecdsa.PublicKey{Curve: mpc.Curve(), X: x, Y: y}, nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package threshold
+
+import (
+	"math/big"
+
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/spi/secretlock"
+	"github.com/trustbloc/kms-go/x/crypto/internal/mpc"
+)
+
+// ErrSecretLockRequired is returned by NewParty without WithSecretLock, key shares are never stored in plaintext.
+var ErrSecretLockRequired = mpc.ErrSecretLockRequired
+
+// Opt is a Party option.
+type Opt func(o *opts)
+
+type opts struct {
+	secretLock secretlock.Service
+	keyURI     string
+}
+
+// WithSecretLock encrypts the key shares stored in the kms.Store with the secret lock master key keyURI. It is
+// required: NewParty fails with ErrSecretLockRequired without it.
+func WithSecretLock(sl secretlock.Service, keyURI string) Opt {
+	return func(o *opts) {
+		o.secretLock = sl
+		o.keyURI = keyURI
+	}
+}
+
+func newOpts(options []Opt) *opts {
+	o := &opts{}
+
+	for _, opt := range options {
+		opt(o)
+	}
+
+	return o
+}
+
+// partyShare is the stored key share of a party with the Paillier key it uses when it is the signer, the encryption
+// of its share and the proofs sent to the cosigners.
+type partyShare struct {
+	KeyShare
+	N             *big.Int           `json:"n"`
+	Lambda        *big.Int           `json:"lambda"`
+	Mu            *big.Int           `json:"mu"`
+	PaillierProof *mpc.PaillierProof `json:"paillierProof"`
+	EncryptedX    *big.Int           `json:"encryptedX"`
+	RangeProof    *mpc.RangeProof    `json:"rangeProof"`
+}
+
+func putShare(store kms.Store, o *opts, share *partyShare) error {
+	return mpc.PutShare(store, o.secretLock, o.keyURI, share.KeyID, share)
+}
+
+func getShare(store kms.Store, o *opts, keyID string) (*partyShare, error) {
+	share := &partyShare{}
+
+	if err := mpc.GetShare(store, o.secretLock, o.keyURI, keyID, share); err != nil {
+		return nil, err
+	}
+
+	return share, nil
+}
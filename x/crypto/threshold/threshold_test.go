@@ -0,0 +1,314 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package threshold
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/trustbloc/kms-go/kms"
+	"github.com/trustbloc/kms-go/secretlock/noop"
+	"github.com/trustbloc/kms-go/x"
+	"github.com/trustbloc/kms-go/x/crypto/internal/mpc"
+)
+
+type inMemoryKMSStore struct {
+	keys map[string][]byte
+}
+
+func newInMemoryKMSStore() *inMemoryKMSStore {
+	return &inMemoryKMSStore{keys: make(map[string][]byte)}
+}
+
+func (i *inMemoryKMSStore) Put(keysetID string, key []byte) error {
+	i.keys[keysetID] = key
+
+	return nil
+}
+
+func (i *inMemoryKMSStore) Get(keysetID string) ([]byte, error) {
+	key, found := i.keys[keysetID]
+	if !found {
+		return nil, kmsapi.ErrKeyNotFound
+	}
+
+	return key, nil
+}
+
+func (i *inMemoryKMSStore) Delete(keysetID string) error {
+	delete(i.keys, keysetID)
+
+	return nil
+}
+
+func newParty(t *testing.T) *Party {
+	t.Helper()
+
+	x.Enable("crypto/threshold")
+
+	p, err := NewParty(newInMemoryKMSStore(), WithSecretLock(&noop.NoLock{}, ""))
+	require.NoError(t, err)
+
+	return p
+}
+
+func newParties(t *testing.T, n int) (string, []*Party) {
+	t.Helper()
+
+	parties := make([]*Party, n)
+	keyGenParties := make([]KeyGenParty, n)
+
+	for i := range parties {
+		parties[i] = newParty(t)
+		keyGenParties[i] = parties[i]
+	}
+
+	keyID, pub, err := KeyGen(keyGenParties...)
+	require.NoError(t, err)
+	require.NotEmpty(t, keyID)
+
+	stored, err := parties[0].PublicKey(keyID)
+	require.NoError(t, err)
+	require.True(t, pub.Equal(stored))
+
+	return keyID, parties
+}
+
+func TestKeyGenAndSign(t *testing.T) {
+	keyID, parties := newParties(t, 3)
+
+	pub, err := parties[0].PublicKey(keyID)
+	require.NoError(t, err)
+
+	for i := range parties {
+		for j := range parties {
+			if i == j {
+				continue
+			}
+
+			sig, err := parties[i].Sign(keyID, parties[j], j+1, []byte("firmware"))
+			require.NoError(t, err)
+			require.NoError(t, Verify(pub, []byte("firmware"), sig))
+			require.Error(t, Verify(pub, []byte("other message"), sig))
+		}
+	}
+
+	t.Run("every party has the joint public key", func(t *testing.T) {
+		for _, p := range parties {
+			other, err := p.PublicKey(keyID)
+			require.NoError(t, err)
+			require.True(t, pub.Equal(other))
+		}
+	})
+
+	t.Run("invalid signer and cosigner", func(t *testing.T) {
+		_, err = parties[0].Sign(keyID, parties[0], 1, []byte("msg"))
+		require.ErrorContains(t, err, "invalid cosigner")
+
+		_, err = parties[0].Sign(keyID, parties[1], 4, []byte("msg"))
+		require.ErrorContains(t, err, "invalid cosigner")
+
+		// party 3 is not the cosigner 2.
+		_, err = parties[0].Sign(keyID, parties[2], 2, []byte("msg"))
+		require.ErrorContains(t, err, "invalid signer or cosigner")
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err = parties[0].Sign("unknown", parties[1], 2, []byte("msg"))
+		require.ErrorIs(t, err, kmsapi.ErrKeyNotFound)
+
+		require.Error(t, Verify(nil, []byte("msg"), nil))
+	})
+}
+
+func TestSignRounds(t *testing.T) {
+	keyID, parties := newParties(t, 2)
+	signer, cosigner := parties[0], parties[1]
+	digest := sha256.Sum256([]byte("msg"))
+
+	msg1, err := signer.SignRound1(keyID, 2, digest[:])
+	require.NoError(t, err)
+
+	msg2, err := cosigner.SignRound2(msg1)
+	require.NoError(t, err)
+
+	t.Run("sessions are unique", func(t *testing.T) {
+		_, err = cosigner.SignRound2(msg1)
+		require.ErrorContains(t, err, "already exists")
+	})
+
+	msg3, err := signer.SignRound3(msg2)
+	require.NoError(t, err)
+
+	t.Run("signer rounds are run once", func(t *testing.T) {
+		_, err = signer.SignRound3(msg2)
+		require.ErrorContains(t, err, "unknown session")
+	})
+
+	t.Run("nonce point must match the commitment", func(t *testing.T) {
+		other := *msg3
+		other.R1 = mpc.MarshalPoint(mpc.Curve().ScalarBaseMult([]byte{2}))
+
+		_, err = cosigner.SignRound4(&other)
+		require.ErrorContains(t, err, "commitment mismatch")
+
+		// the cosigner session was dropped.
+		_, err = cosigner.SignRound4(msg3)
+		require.ErrorContains(t, err, "unknown session")
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		_, err = signer.SignRound1(keyID, 2, []byte("short"))
+		require.Error(t, err)
+
+		bad := *msg1
+		bad.SessionID = "other"
+		bad.PaillierN = big.NewInt(15).Bytes()
+
+		_, err = cosigner.SignRound2(&bad)
+		require.ErrorContains(t, err, "signer paillier modulus: invalid paillier modulus size")
+
+		bad = *msg1
+		bad.SessionID = "other"
+		bad.RangeProof = nil
+
+		_, err = cosigner.SignRound2(&bad)
+		require.ErrorContains(t, err, "signer encrypted share: missing range proof")
+
+		// the square of the ciphertext encrypts twice the signer share.
+		n := new(big.Int).SetBytes(msg1.PaillierN)
+		encX := new(big.Int).SetBytes(msg1.EncryptedShare)
+		bad.RangeProof = msg1.RangeProof
+		bad.EncryptedShare = encX.Exp(encX, big.NewInt(2), n.Mul(n, n)).Bytes()
+
+		_, err = cosigner.SignRound2(&bad)
+		require.ErrorContains(t, err, "signer encrypted share: invalid range proof")
+
+		_, err = signer.SignFinish(&SignRound4Message{SessionID: "unknown"})
+		require.ErrorContains(t, err, "unknown session")
+	})
+}
+
+// keyGenRound1 runs the first key generation round of new parties for a new key.
+func keyGenRound1(t *testing.T, n int) ([]*Party, []*KeyGenRound1Message) {
+	t.Helper()
+
+	keyID, err := mpc.RandomID()
+	require.NoError(t, err)
+
+	parties := make([]*Party, n)
+	msgs := make([]*KeyGenRound1Message, n)
+
+	for i := range parties {
+		parties[i] = newParty(t)
+
+		msgs[i], err = parties[i].KeyGenRound1(keyID, i+1, n)
+		require.NoError(t, err)
+	}
+
+	return parties, msgs
+}
+
+func TestKeyGen(t *testing.T) {
+	t.Run("invalid requests", func(t *testing.T) {
+		p := newParty(t)
+
+		_, err := p.KeyGenRound1("key", 0, 2)
+		require.ErrorContains(t, err, "invalid request")
+
+		_, err = p.KeyGenRound1("key", 2, 1)
+		require.ErrorContains(t, err, "invalid request")
+
+		_, _, err = KeyGen(p)
+		require.ErrorContains(t, err, "at least 2 parties are required")
+
+		_, err = p.KeyGenRound2(nil)
+		require.ErrorContains(t, err, "invalid request")
+
+		_, err = p.KeyGenFinish([]*KeyGenRound2Message{{KeyID: "unknown"}})
+		require.ErrorContains(t, err, "unknown session")
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		x.Disable()
+		defer x.Enable("crypto/threshold")
+
+		_, err := NewParty(newInMemoryKMSStore(), WithSecretLock(&noop.NoLock{}, ""))
+		require.ErrorIs(t, err, x.ErrNotEnabled)
+		require.ErrorContains(t, err, "threshold: new party: ")
+	})
+
+	t.Run("secret lock is required", func(t *testing.T) {
+		x.Enable("crypto/threshold")
+
+		_, err := NewParty(newInMemoryKMSStore())
+		require.ErrorIs(t, err, ErrSecretLockRequired)
+		require.EqualError(t, err, "threshold: new party: a secret lock is required to store key shares")
+	})
+
+	t.Run("paillier modulus must be well formed", func(t *testing.T) {
+		parties, msgs1 := keyGenRound1(t, 2)
+
+		_, err := parties[1].KeyGenRound1(msgs1[0].KeyID, 2, 2)
+		require.ErrorContains(t, err, "already exists")
+
+		msgs1[0].PaillierProof = nil
+
+		_, err = parties[1].KeyGenRound2(msgs1)
+		require.EqualError(t, err,
+			"threshold: keygen round 2: party 1 paillier modulus: missing paillier modulus proof")
+
+		// the session was dropped.
+		_, err = parties[1].KeyGenRound2(msgs1)
+		require.ErrorContains(t, err, "unknown session")
+	})
+
+	t.Run("rounds 2 messages are checked", func(t *testing.T) {
+		parties, msgs1 := keyGenRound1(t, 2)
+		msgs2 := make([]*KeyGenRound2Message, 2)
+
+		for i, p := range parties {
+			var err error
+
+			msgs2[i], err = p.KeyGenRound2(msgs1)
+			require.NoError(t, err)
+		}
+
+		_, err := parties[0].KeyGenRound2(msgs1)
+		require.ErrorContains(t, err, "unknown session")
+
+		// the points must open the commitment of their party.
+		tampered := *msgs2[1]
+		tampered.Points = [][]byte{msgs2[1].Points[0], msgs2[0].Points[1]}
+
+		_, err = parties[0].KeyGenFinish([]*KeyGenRound2Message{msgs2[0], &tampered})
+		require.EqualError(t, err, "threshold: keygen finish: party 2: commitment mismatch")
+
+		// the share must match the points of its polynomial: the ciphertext encrypts the share plus one.
+		pk := mpc.NewPaillierPublicKey(new(big.Int).SetBytes(msgs1[1].PaillierN))
+
+		encOne, err := pk.Encrypt(big.NewInt(1))
+		require.NoError(t, err)
+
+		encShare := pk.Add(new(big.Int).SetBytes(msgs2[0].EncryptedShares[1]), encOne)
+		tampered = *msgs2[0]
+		tampered.EncryptedShares = [][]byte{nil, encShare.Bytes()}
+
+		_, err = parties[1].KeyGenFinish([]*KeyGenRound2Message{&tampered, msgs2[1]})
+		require.EqualError(t, err, "threshold: keygen finish: party 1: share does not match its polynomial")
+
+		// sessions are single use and no share was stored.
+		_, err = parties[1].KeyGenFinish(msgs2)
+		require.ErrorContains(t, err, "unknown session")
+
+		_, err = parties[1].PublicKey(msgs1[0].KeyID)
+		require.ErrorIs(t, err, kmsapi.ErrKeyNotFound)
+	})
+}
@@ -0,0 +1,157 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vrf
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/bwesterb/go-ristretto"
+	"github.com/bwesterb/go-ristretto/edwards25519"
+)
+
+const (
+	pointSize  = 32
+	scalarSize = 32
+)
+
+var errInvalidPoint = errors.New("invalid point")
+
+//nolint:gochecknoglobals
+var (
+	feOne = new(edwards25519.FieldElement).SetOne()
+	// feD is the d parameter of edwards25519, -121665/121666.
+	feD = func() *edwards25519.FieldElement {
+		var num, den edwards25519.FieldElement
+
+		num.Neg(feSmall(121665))     //nolint:gomnd // RFC 8032 curve constant.
+		den.Inverse(feSmall(121666)) //nolint:gomnd // RFC 8032 curve constant.
+
+		return num.Mul(&num, &den)
+	}()
+	// basePoint is the RFC 8032 base point B. The base point of go-ristretto is another point of the ristretto
+	// equivalence class of B.
+	basePoint = func() *edwards25519.ExtendedPoint {
+		enc := bytes.Repeat([]byte{0x66}, pointSize)
+		enc[0] = 0x58
+
+		p, _ := decodePoint(enc) //nolint:errcheck // B is a valid point.
+
+		return p
+	}()
+)
+
+func feSmall(v uint32) *edwards25519.FieldElement {
+	var b [32]byte
+
+	b[0], b[1], b[2], b[3] = byte(v), byte(v>>8), byte(v>>16), byte(v>>24)
+
+	return new(edwards25519.FieldElement).SetBytes(&b)
+}
+
+// decodePoint decodes the RFC 8032 encoding of a point of edwards25519, it rejects non canonical encodings.
+func decodePoint(b []byte) (*edwards25519.ExtendedPoint, error) {
+	if len(b) != pointSize {
+		return nil, errInvalidPoint
+	}
+
+	var buf [32]byte
+
+	copy(buf[:], b)
+
+	sign := int32(buf[31] >> 7) //nolint:gomnd // the sign bit of x.
+	buf[31] &= 0x7f
+
+	var y edwards25519.FieldElement
+
+	if y.SetBytes(&buf); y.Bytes() != buf {
+		return nil, errInvalidPoint
+	}
+
+	// x^2 = (y^2 - 1) / (d*y^2 + 1)
+	var yy, u, v, xx, x, chk edwards25519.FieldElement
+
+	yy.Square(&y)
+	u.Sub(&yy, feOne)
+	v.Mul(&yy, feD)
+	v.Add(&v, feOne)
+	xx.Mul(&u, v.Inverse(&v))
+	x.Sqrt(&xx)
+
+	if !chk.Square(&x).Equals(&xx) || (x.IsNonZeroI() == 0 && sign == 1) {
+		return nil, errInvalidPoint
+	}
+
+	if x.IsNegativeI() != sign {
+		x.Neg(&x)
+	}
+
+	p := &edwards25519.ExtendedPoint{X: x, Y: y}
+	p.Z.SetOne()
+	p.T.Mul(&x, &y)
+
+	return p, nil
+}
+
+// encodePoint returns the RFC 8032 encoding of p.
+func encodePoint(p *edwards25519.ExtendedPoint) []byte {
+	var zInv, x, y edwards25519.FieldElement
+
+	zInv.Inverse(&p.Z)
+	x.Mul(&p.X, &zInv)
+	y.Mul(&p.Y, &zInv)
+
+	b := y.Bytes()
+	b[31] |= byte(x.IsNegativeI()) << 7 //nolint:gomnd // the sign bit of x.
+
+	return b[:]
+}
+
+// mulByCofactor returns 8*p.
+func mulByCofactor(p *edwards25519.ExtendedPoint) *edwards25519.ExtendedPoint {
+	r := new(edwards25519.ExtendedPoint).Double(p)
+
+	return r.Double(r).Double(r)
+}
+
+// scalarMult returns s*p in constant time.
+func scalarMult(p *edwards25519.ExtendedPoint, s *ristretto.Scalar) *edwards25519.ExtendedPoint {
+	var b [32]byte
+
+	s.BytesInto(&b)
+
+	return new(edwards25519.ExtendedPoint).ScalarMult(p, &b)
+}
+
+// scalarBaseMult returns s*B in constant time.
+func scalarBaseMult(s *ristretto.Scalar) *edwards25519.ExtendedPoint {
+	return scalarMult(basePoint, s)
+}
+
+// reduceScalar returns b mod L, the little endian integer b is at most 64 bytes.
+func reduceScalar(b []byte) *ristretto.Scalar {
+	var wide [64]byte
+
+	copy(wide[:], b)
+
+	return new(ristretto.Scalar).SetReduced(&wide)
+}
+
+// decodeScalar decodes a canonical little endian scalar.
+func decodeScalar(b []byte) (*ristretto.Scalar, bool) {
+	if len(b) != scalarSize {
+		return nil, false
+	}
+
+	s := reduceScalar(b)
+
+	var enc [32]byte
+
+	s.BytesInto(&enc)
+
+	return s, string(enc[:]) == string(b)
+}
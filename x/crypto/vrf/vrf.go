@@ -0,0 +1,293 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package vrf provides the ECVRF-EDWARDS25519-SHA512-TAI verifiable random function of RFC 9381.
+//
+// The VRF proof of an input alpha with a private key proves that the VRF output beta of alpha is the one of the public
+// key: the output is unique and pseudorandom, only the holder of the private key computes it, and anyone verifies it,
+// eg: for fair ordering or lottery protocols where the outcome can't be ground by the prover.
+//
+// VRF keys are the Ed25519 keys of a KMS (kms.ED25519Type), the VRF public key is the Ed25519 public key. An Ed25519
+// key used for both signatures and VRF proofs must not derive the nonces of its proofs as RFC 9381 does, with the same
+// hash as its Ed25519 signature nonces: the signature of a message that is the hash to the curve of a VRF input would
+// use the nonce of its proof and reveal the private key. The Prover derives domain separated nonces instead, the
+// proofs are valid RFC 9381 proofs with the same outputs. WithDedicatedKeys selects the RFC 9381 nonces for keys that
+// are used for VRF proofs only.
+package vrf
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+
+	"github.com/bwesterb/go-ristretto"
+	"github.com/bwesterb/go-ristretto/edwards25519"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+
+	"github.com/trustbloc/kms-go/internal/memguard"
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/x"
+)
+
+const (
+	// ProofSize is the size in bytes of a VRF proof.
+	ProofSize = pointSize + challengeSize + scalarSize
+	// OutputSize is the size in bytes of a VRF output.
+	OutputSize = sha512.Size
+
+	challengeSize = 16
+
+	suiteString byte = 0x03
+
+	encodeToCurveFront  byte = 0x01
+	challengeFront      byte = 0x02
+	proofToHashFront    byte = 0x03
+	domainSeparatorBack byte = 0x00
+
+	ed25519PrivateKeyTypeURL = "type.googleapis.com/google.crypto.tink.Ed25519PrivateKey"
+	nonceDST                 = "kms-go ECVRF-EDWARDS25519-SHA512-TAI nonce"
+)
+
+// ErrInvalidProof is returned by Verify when a proof is not valid.
+var ErrInvalidProof = errors.New("vrf: invalid proof")
+
+// Prover computes the VRF proofs of the Ed25519 keys of a KeyManager.
+type Prover struct {
+	km        kms.KeyManager
+	dedicated bool
+}
+
+// Opt is a Prover option.
+type Opt func(p *Prover)
+
+// WithDedicatedKeys derives the nonces of the proofs as RFC 9381 does: the keys of the Prover must not be used for
+// Ed25519 signatures.
+func WithDedicatedKeys() Opt {
+	return func(p *Prover) {
+		p.dedicated = true
+	}
+}
+
+// New creates a new Prover of the keys of km.
+func New(km kms.KeyManager, opts ...Opt) (*Prover, error) {
+	if err := x.Require("crypto/vrf"); err != nil {
+		return nil, fmt.Errorf("vrf: new: %w", err)
+	}
+
+	p := &Prover{km: km}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p, nil
+}
+
+// Prove returns the VRF proof of alpha with the Ed25519 key keyID. The VRF output of the proof is given by
+// ProofToHash.
+func (p *Prover) Prove(keyID string, alpha []byte) ([]byte, error) {
+	kh, err := p.km.Get(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("vrf: prove: get key: %w", err)
+	}
+
+	sk, err := ed25519Seed(kh)
+	if err != nil {
+		return nil, fmt.Errorf("vrf: prove: %w", err)
+	}
+
+	defer memguard.Wipe(sk)
+
+	return p.prove(sk, alpha)
+}
+
+func (p *Prover) prove(sk, alpha []byte) ([]byte, error) {
+	h := sha512.Sum512(sk)
+	defer memguard.Wipe(h[:])
+
+	// the secret scalar x of the Ed25519 key.
+	h[0] &= 248
+	h[31] &= 127
+	h[31] |= 64
+
+	x := reduceScalar(h[:32])
+	defer x.SetZero()
+
+	pk := encodePoint(scalarBaseMult(x))
+
+	hPoint, err := encodeToCurve(pk, alpha)
+	if err != nil {
+		return nil, fmt.Errorf("vrf: prove: %w", err)
+	}
+
+	hString := encodePoint(hPoint)
+	gamma := scalarMult(hPoint, x)
+
+	nonce := sha512.New()
+
+	if !p.dedicated {
+		// the nonce hash input doesn't start with the Ed25519 secret prefix, unlike the ones of the signatures.
+		nonce.Write([]byte(nonceDST))
+	}
+
+	nonce.Write(h[32:])
+	nonce.Write(hString)
+
+	kString := nonce.Sum(nil)
+	defer memguard.Wipe(kString)
+
+	k := reduceScalar(kString)
+	defer k.SetZero()
+
+	u, v := encodePoint(scalarBaseMult(k)), encodePoint(scalarMult(hPoint, k))
+	c := challenge(pk, hString, encodePoint(gamma), u, v)
+
+	// s = k + c*x mod L
+	s := new(ristretto.Scalar).MulAdd(reduceScalar(c), x, k)
+
+	proof := append(encodePoint(gamma), c...)
+
+	return append(proof, s.Bytes()...), nil
+}
+
+// ProofToHash returns the VRF output of proof. It doesn't verify the proof: the output of an unverified proof must not
+// be trusted, see Verify.
+func ProofToHash(proof []byte) ([]byte, error) {
+	if len(proof) != ProofSize {
+		return nil, ErrInvalidProof
+	}
+
+	gamma, err := decodePoint(proof[:pointSize])
+	if err != nil {
+		return nil, ErrInvalidProof
+	}
+
+	return proofToHash(gamma), nil
+}
+
+// Verify verifies the VRF proof of alpha with the Ed25519 public key pubKey and returns its VRF output. It returns
+// ErrInvalidProof if the proof is not valid, and fails for the public keys of small order.
+func Verify(pubKey, alpha, proof []byte) ([]byte, error) {
+	y, err := decodePoint(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("vrf: verify: public key: %w", err)
+	}
+
+	if isIdentity(mulByCofactor(y)) {
+		return nil, errors.New("vrf: verify: public key: small order point")
+	}
+
+	if len(proof) != ProofSize {
+		return nil, ErrInvalidProof
+	}
+
+	gamma, err := decodePoint(proof[:pointSize])
+	if err != nil {
+		return nil, ErrInvalidProof
+	}
+
+	c := proof[pointSize : pointSize+challengeSize]
+
+	s, ok := decodeScalar(proof[pointSize+challengeSize:])
+	if !ok {
+		return nil, ErrInvalidProof
+	}
+
+	hPoint, err := encodeToCurve(pubKey, alpha)
+	if err != nil {
+		return nil, fmt.Errorf("vrf: verify: %w", err)
+	}
+
+	// U = s*B - c*Y, V = s*H - c*Gamma
+	cScalar := reduceScalar(c)
+	u := new(edwards25519.ExtendedPoint).Sub(scalarBaseMult(s), scalarMult(y, cScalar))
+	v := new(edwards25519.ExtendedPoint).Sub(scalarMult(hPoint, s), scalarMult(gamma, cScalar))
+
+	expected := challenge(pubKey, encodePoint(hPoint), proof[:pointSize], encodePoint(u), encodePoint(v))
+
+	if string(expected) != string(c) {
+		return nil, ErrInvalidProof
+	}
+
+	return proofToHash(gamma), nil
+}
+
+// encodeToCurve is the try and increment encoding of RFC 9381 of alpha to the curve, with the public key pk as salt.
+func encodeToCurve(pk, alpha []byte) (*edwards25519.ExtendedPoint, error) {
+	data := append([]byte{suiteString, encodeToCurveFront}, pk...)
+	data = append(data, alpha...)
+
+	for ctr := 0; ctr < 256; ctr++ {
+		hash := sha512.Sum512(append(data, byte(ctr), domainSeparatorBack))
+
+		if p, err := decodePoint(hash[:pointSize]); err == nil {
+			return mulByCofactor(p), nil
+		}
+	}
+
+	return nil, errors.New("encode to curve: no valid point found")
+}
+
+// challenge returns the RFC 9381 challenge of the encoded points.
+func challenge(points ...[]byte) []byte {
+	h := sha512.New()
+	h.Write([]byte{suiteString, challengeFront})
+
+	for _, p := range points {
+		h.Write(p)
+	}
+
+	h.Write([]byte{domainSeparatorBack})
+
+	return h.Sum(nil)[:challengeSize]
+}
+
+func proofToHash(gamma *edwards25519.ExtendedPoint) []byte {
+	h := sha512.New()
+	h.Write([]byte{suiteString, proofToHashFront})
+	h.Write(encodePoint(mulByCofactor(gamma)))
+	h.Write([]byte{domainSeparatorBack})
+
+	return h.Sum(nil)
+}
+
+func isIdentity(p *edwards25519.ExtendedPoint) bool {
+	b := encodePoint(p)
+
+	return b[0] == 1 && string(b[1:]) == string(make([]byte, pointSize-1))
+}
+
+// ed25519Seed returns the private key (RFC 8032 seed) of the primary key of the Ed25519 keyset kh.
+func ed25519Seed(kh interface{}) ([]byte, error) {
+	handle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errors.New("key is not a keyset handle")
+	}
+
+	ks := insecurecleartextkeyset.KeysetMaterial(handle)
+
+	for _, k := range ks.Key {
+		if k.KeyId != ks.PrimaryKeyId || k.Status != tinkpb.KeyStatusType_ENABLED {
+			continue
+		}
+
+		key := &ed25519pb.Ed25519PrivateKey{}
+
+		if k.KeyData.TypeUrl != ed25519PrivateKeyTypeURL || proto.Unmarshal(k.KeyData.Value, key) != nil ||
+			len(key.KeyValue) != ed25519.SeedSize {
+			return nil, errors.New("key is not an Ed25519 private key")
+		}
+
+		return key.KeyValue, nil
+	}
+
+	return nil, errors.New("primary key not found")
+}
@@ -0,0 +1,197 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vrf_test
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	kmsapi "github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/kms-go/testutil/mockkms"
+	"github.com/trustbloc/kms-go/x"
+	"github.com/trustbloc/kms-go/x/crypto/vrf"
+)
+
+// RFC 9381 appendix B.3 example 16.
+const (
+	testSK    = "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60"
+	testPK    = "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"
+	testProof = "8657106690b5526245a92b003bb079ccd1a92130477671f6fc01ad16f26f723f" +
+		"26f8a57ccaed74ee1b190bed1f479d97" +
+		"27d2d0f9b005a6e456a35d4fb0daab1268a1b0db10836d9826a528ca76567805"
+	testBeta = "90cf1df3b703cce59e2a35b925d411164068269d7b2d29f3301c03dd757876ff" +
+		"66b71dda49d2de59d03450451af026798e8f81cd2e333de5cdf4f3e140fdd8ae"
+)
+
+func TestProver_Prove(t *testing.T) {
+	km := mockkms.NewForTest(t)
+
+	keyID, _, err := km.ImportPrivateKey(ed25519.NewKeyFromSeed(decode(t, testSK)), kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	pubKey, _, err := km.ExportPubKeyBytes(keyID)
+	require.NoError(t, err)
+	require.Equal(t, decode(t, testPK), pubKey)
+
+	t.Run("RFC 9381 test vector", func(t *testing.T) {
+		proof, err := newProver(t, km, vrf.WithDedicatedKeys()).Prove(keyID, nil)
+		require.NoError(t, err)
+		require.Equal(t, decode(t, testProof), proof)
+
+		beta, err := vrf.ProofToHash(proof)
+		require.NoError(t, err)
+		require.Equal(t, decode(t, testBeta), beta)
+
+		beta, err = vrf.Verify(pubKey, nil, proof)
+		require.NoError(t, err)
+		require.Equal(t, decode(t, testBeta), beta)
+	})
+
+	t.Run("domain separated nonces", func(t *testing.T) {
+		proof, err := newProver(t, km).Prove(keyID, nil)
+		require.NoError(t, err)
+		require.Len(t, proof, vrf.ProofSize)
+		require.NotEqual(t, decode(t, testProof), proof)
+
+		// the output is unique: it doesn't depend on the nonce.
+		beta, err := vrf.Verify(pubKey, nil, proof)
+		require.NoError(t, err)
+		require.Len(t, beta, vrf.OutputSize)
+		require.Equal(t, decode(t, testBeta), beta)
+	})
+
+	t.Run("created key", func(t *testing.T) {
+		kid, pub, err := km.CreateAndExportPubKeyBytes(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		p := newProver(t, km)
+
+		proof, err := p.Prove(kid, []byte("round 1"))
+		require.NoError(t, err)
+
+		beta, err := vrf.Verify(pub, []byte("round 1"), proof)
+		require.NoError(t, err)
+
+		// the key still signs: the Ed25519 signatures and the VRF proofs of a key are compatible.
+		cr, err := tinkcrypto.New()
+		require.NoError(t, err)
+
+		kh, err := km.Get(kid)
+		require.NoError(t, err)
+
+		sig, err := cr.Sign([]byte("round 1"), kh)
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(pub, []byte("round 1"), sig))
+
+		other, err := p.Prove(kid, []byte("round 2"))
+		require.NoError(t, err)
+
+		otherBeta, err := vrf.Verify(pub, []byte("round 2"), other)
+		require.NoError(t, err)
+		require.NotEqual(t, beta, otherBeta)
+
+		_, err = vrf.Verify(pub, []byte("round 2"), proof)
+		require.ErrorIs(t, err, vrf.ErrInvalidProof)
+
+		_, err = vrf.Verify(pubKey, []byte("round 1"), proof)
+		require.ErrorIs(t, err, vrf.ErrInvalidProof)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := newProver(t, km).Prove("unknown", nil)
+		require.ErrorContains(t, err, "vrf: prove: get key")
+
+		p256ID, _, err := km.Create(kmsapi.ECDSAP256TypeIEEEP1363)
+		require.NoError(t, err)
+
+		_, err = newProver(t, km).Prove(p256ID, nil)
+		require.EqualError(t, err, "vrf: prove: key is not an Ed25519 private key")
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		x.Disable()
+
+		_, err := vrf.New(km)
+		require.ErrorIs(t, err, x.ErrNotEnabled)
+		require.ErrorContains(t, err, "vrf: new: ")
+	})
+}
+
+func TestVerify(t *testing.T) {
+	pubKey, proof := decode(t, testPK), decode(t, testProof)
+
+	for _, i := range []int{0, 32, 47, 48, 79} {
+		tampered := append([]byte{}, proof...)
+		tampered[i] ^= 1
+
+		_, err := vrf.Verify(pubKey, nil, tampered)
+		require.ErrorIs(t, err, vrf.ErrInvalidProof)
+	}
+
+	// s must be reduced.
+	tampered := append([]byte{}, proof...)
+	tampered[79] |= 0xf0
+
+	_, err := vrf.Verify(pubKey, nil, tampered)
+	require.ErrorIs(t, err, vrf.ErrInvalidProof)
+
+	_, err = vrf.Verify(pubKey, nil, proof[1:])
+	require.ErrorIs(t, err, vrf.ErrInvalidProof)
+
+	_, err = vrf.Verify(pubKey, []byte("other"), proof)
+	require.ErrorIs(t, err, vrf.ErrInvalidProof)
+
+	_, err = vrf.Verify(pubKey[1:], nil, proof)
+	require.EqualError(t, err, "vrf: verify: public key: invalid point")
+
+	// the identity and the points of small order are not valid public keys.
+	identity := make([]byte, 32)
+	identity[0] = 1
+
+	_, err = vrf.Verify(identity, nil, proof)
+	require.EqualError(t, err, "vrf: verify: public key: small order point")
+
+	_, err = vrf.ProofToHash(proof[1:])
+	require.ErrorIs(t, err, vrf.ErrInvalidProof)
+
+	_, err = vrf.ProofToHash(make([]byte, vrf.ProofSize))
+	require.NoError(t, err)
+
+	invalid := append([]byte{}, proof...)
+	invalid[31] = 0xff
+
+	for i := 0; i < 31; i++ {
+		invalid[i] = 0xff
+	}
+
+	_, err = vrf.ProofToHash(invalid)
+	require.ErrorIs(t, err, vrf.ErrInvalidProof)
+}
+
+func decode(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+
+	return b
+}
+
+func newProver(t *testing.T, km kmsapi.KeyManager, opts ...vrf.Opt) *vrf.Prover {
+	t.Helper()
+
+	x.Enable("crypto/vrf")
+
+	p, err := vrf.New(km, opts...)
+	require.NoError(t, err)
+
+	return p
+}
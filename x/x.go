@@ -0,0 +1,145 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package x is the root of the experimental namespace of the module. New primitives (eg: post-quantum, MPC or
+// attribute based encryption schemes) are first added as sub packages of x (eg: x/crypto/<scheme>).
+//
+// Experimental packages are not covered by the API stability guarantees of the module: their API, their wire formats
+// and their key storage formats can change in any release. They must not be exposed through the spi interfaces, and
+// the stable packages only use them behind opt-in options or types (eg: server.WithMPC or webkms.MPCRemote for
+// x/crypto/ecdsa2p), so they can't change the behavior of applications not using them.
+//
+// The experimental packages are:
+//
+//   - x/crypto/blindrsa: RSA blind signatures (RFC 9474).
+//   - x/crypto/commitment: Pedersen commitments with Bulletproofs range proofs.
+//   - x/crypto/ecdsa2p: two-party ECDSA signatures.
+//   - x/crypto/musig2: MuSig2 multi-signatures (BIP-327).
+//   - x/crypto/opaque: the OPAQUE password-authenticated key exchange (RFC 9807).
+//   - x/crypto/oprf: oblivious pseudorandom functions (RFC 9497).
+//   - x/crypto/threshold: 2-of-n threshold ECDSA signatures.
+//   - x/crypto/vrf: verifiable random functions (RFC 9381).
+//
+// They are disabled at runtime until enabled by the application, either by calling Enable or by setting the
+// KMS_GO_EXPERIMENTAL environment variable to 'all' or to a comma separated list of features. Their constructors call
+// Require with their feature name (the package path relative to x, eg: 'crypto/vrf') and fail with ErrNotEnabled until
+// then.
+//
+// An experimental package is promoted to the stable namespace (with the same package name) once its API has been
+// unchanged for a release, its formats are specified (standard or documented) and it has test vectors. The x package
+// is then kept for one release as deprecated aliases to the stable package.
+package x
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// EnvVar is the environment variable enabling experimental features: 'all' or a comma separated list of features.
+const EnvVar = "KMS_GO_EXPERIMENTAL"
+
+const allFeatures = "all"
+
+// ErrNotEnabled is returned by Require for experimental features that are not enabled.
+var ErrNotEnabled = errors.New("experimental feature is not enabled")
+
+//nolint:gochecknoglobals
+var (
+	mu       sync.RWMutex
+	envOnce  sync.Once
+	enabled  = map[string]bool{}
+	features = map[string]bool{}
+)
+
+// Enable enables the experimental features, all the experimental features if none is given.
+func Enable(feature ...string) {
+	loadEnv()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(feature) == 0 {
+		enabled[allFeatures] = true
+
+		return
+	}
+
+	for _, f := range feature {
+		enabled[f] = true
+	}
+}
+
+// Disable disables all the experimental features, including the ones enabled with KMS_GO_EXPERIMENTAL.
+func Disable() {
+	loadEnv()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	enabled = map[string]bool{}
+}
+
+// Enabled reports whether the experimental feature is enabled.
+func Enabled(feature string) bool {
+	loadEnv()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return enabled[allFeatures] || enabled[feature]
+}
+
+// Require returns ErrNotEnabled if the experimental feature is not enabled. Experimental packages call it in their
+// constructors, it also records feature for Features.
+func Require(feature string) error {
+	mu.Lock()
+	features[feature] = true
+	mu.Unlock()
+
+	if !Enabled(feature) {
+		return fmt.Errorf("%w: '%s' (see x.Enable or the %s environment variable)", ErrNotEnabled, feature, EnvVar)
+	}
+
+	return nil
+}
+
+// Features returns the sorted experimental features required so far by the application.
+func Features() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	list := make([]string, 0, len(features))
+
+	for f := range features {
+		list = append(list, f)
+	}
+
+	sort.Strings(list)
+
+	return list
+}
+
+func loadEnv() {
+	envOnce.Do(func() {
+		value := os.Getenv(EnvVar)
+		if value == "" {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		for _, f := range strings.Split(value, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				enabled[f] = true
+			}
+		}
+	})
+}
@@ -0,0 +1,58 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package x
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func reset() {
+	envOnce = sync.Once{}
+	enabled = map[string]bool{}
+	features = map[string]bool{}
+}
+
+func TestRequire(t *testing.T) {
+	t.Setenv(EnvVar, "")
+	reset()
+
+	err := Require("crypto/vrf")
+	require.ErrorIs(t, err, ErrNotEnabled)
+	require.EqualError(t, err, "experimental feature is not enabled: 'crypto/vrf' "+
+		"(see x.Enable or the KMS_GO_EXPERIMENTAL environment variable)")
+
+	Enable("crypto/vrf")
+	require.NoError(t, Require("crypto/vrf"))
+	require.ErrorIs(t, Require("crypto/oprf"), ErrNotEnabled)
+
+	Enable()
+	require.NoError(t, Require("crypto/oprf"))
+	require.Equal(t, []string{"crypto/oprf", "crypto/vrf"}, Features())
+
+	Disable()
+	require.False(t, Enabled("crypto/vrf"))
+}
+
+func TestEnvVar(t *testing.T) {
+	t.Setenv(EnvVar, "crypto/vrf, crypto/oprf")
+	reset()
+
+	require.True(t, Enabled("crypto/vrf"))
+	require.True(t, Enabled("crypto/oprf"))
+	require.False(t, Enabled("crypto/musig2"))
+
+	t.Setenv(EnvVar, "all")
+	reset()
+
+	require.True(t, Enabled("crypto/musig2"))
+
+	Disable()
+	require.False(t, Enabled("crypto/musig2"))
+}